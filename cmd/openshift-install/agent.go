@@ -7,6 +7,7 @@ import (
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/agent/agentconfig"
 	"github.com/openshift/installer/pkg/asset/agent/image"
+	"github.com/openshift/installer/pkg/asset/agent/joiner"
 	"github.com/openshift/installer/pkg/asset/agent/manifests"
 	"github.com/openshift/installer/pkg/asset/agent/mirror"
 	"github.com/openshift/installer/pkg/asset/kubeconfig"
@@ -83,7 +84,56 @@ var (
 		},
 	}
 
-	agentTargets = []target{agentConfigTarget, agentManifestsTarget, agentImageTarget, agentPXEFilesTarget}
+	agentGenericImageTarget = target{
+		name: "Agent Generic ISO Image",
+		command: &cobra.Command{
+			Use:   "generic-image",
+			Short: "Generates a bootable image, not specific to a cluster, which is configured by a config image at boot",
+			Long: `Generates a bootable image which is not specific to a cluster, so that
+hardware vendors can write it to the hosts at the factory. The hosts booted
+with the image wait for the config image generated by 'agent create
+config-image' to be attached before installing the cluster.`,
+			Args: cobra.ExactArgs(0),
+		},
+		assets: []asset.WritableAsset{
+			&image.GenericImage{},
+		},
+	}
+
+	agentConfigImageTarget = target{
+		name: "Agent Config Image",
+		command: &cobra.Command{
+			Use:   "config-image",
+			Short: "Generates a small image containing the cluster configuration of the hosts booted with the generic image",
+			Args:  cobra.ExactArgs(0),
+		},
+		assets: []asset.WritableAsset{
+			&image.ConfigImage{},
+			&kubeconfig.AgentAdminClient{},
+			&password.KubeadminPassword{},
+		},
+	}
+
+	agentAddNodesISOTarget = target{
+		name: "Add Nodes ISO",
+		command: &cobra.Command{
+			Use:   "add-nodes-iso",
+			Short: "Generates a bootable image adding the hosts of nodes-config.yaml as workers to the cluster of auth/kubeconfig",
+			Long: `Generates a bootable image adding the hosts of nodes-config.yaml as workers
+to the existing cluster of the kubeconfig in the auth directory of the
+assets directory. Each host booted with the image is identified by the MAC
+addresses of its interfaces, installed with the worker Ignition config of the
+cluster and rebooted. The add-nodes/approve-csrs.sh script approves the
+certificate signing requests of the hosts as they join the cluster.`,
+			Args: cobra.ExactArgs(0),
+		},
+		assets: []asset.WritableAsset{
+			&image.AddNodesImage{},
+			&joiner.ApproveCSRsScript{},
+		},
+	}
+
+	agentTargets = []target{agentConfigTarget, agentManifestsTarget, agentImageTarget, agentPXEFilesTarget, agentGenericImageTarget, agentConfigImageTarget, agentAddNodesISOTarget}
 )
 
 func newAgentCreateCmd() *cobra.Command {
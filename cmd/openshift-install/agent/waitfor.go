@@ -2,6 +2,8 @@ package agent
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -72,11 +74,16 @@ func newWaitForBootstrapCompleteCmd() *cobra.Command {
 }
 
 func newWaitForInstallCompleteCmd() *cobra.Command {
-	return &cobra.Command{
+	var output string
+	cmd := &cobra.Command{
 		Use:   "install-complete",
 		Short: "Wait until the cluster installation is complete",
 		Args:  cobra.ExactArgs(0),
 		Run: func(cmd *cobra.Command, args []string) {
+			if output != "text" && output != "json" {
+				logrus.Fatalf("invalid --output %q, must be text or json", output)
+			}
+
 			assetDir := cmd.Flags().Lookup("dir").Value.String()
 			logrus.Debugf("asset directory: %s", assetDir)
 			if len(assetDir) == 0 {
@@ -88,12 +95,21 @@ func newWaitForInstallCompleteCmd() *cobra.Command {
 			if err != nil {
 				logrus.Exit(exitCodeBootstrapFailed)
 			}
+			if output == "json" {
+				cluster.OnProgress = printProgress
+			}
 
 			if err := agentpkg.WaitForBootstrapComplete(cluster); err != nil {
+				if output == "json" {
+					printFinalProgress(cluster, err)
+				}
 				handleBootstrapError(cluster, err)
 			}
 
 			if err = agentpkg.WaitForInstallComplete(cluster); err != nil {
+				if output == "json" {
+					printFinalProgress(cluster, err)
+				}
 				logrus.Error(err)
 				err2 := cluster.API.OpenShift.LogClusterOperatorConditions()
 				if err2 != nil {
@@ -104,7 +120,35 @@ func newWaitForInstallCompleteCmd() *cobra.Command {
 				https://docs.openshift.com/container-platform/latest/support/troubleshooting/troubleshooting-installations.html`)
 				logrus.Exit(exitCodeInstallFailed)
 			}
+			if output == "json" {
+				printFinalProgress(cluster, nil)
+			}
 			cluster.PrintInstallationComplete()
 		},
 	}
+	cmd.Flags().StringVar(&output, "output", "text", "format of the installation progress: text, or json to print a JSON object on stdout every time the progress of the cluster or of a host changes")
+	return cmd
+}
+
+// printProgress prints the progress as a single line of JSON on stdout.
+func printProgress(progress *agentpkg.ClusterProgress) {
+	data, err := json.Marshal(progress)
+	if err != nil {
+		logrus.Debug(errors.Wrap(err, "failed to marshal the installation progress"))
+		return
+	}
+	fmt.Println(string(data))
+}
+
+// printFinalProgress prints the last progress of the cluster, in the
+// complete phase or, when the wait failed, in the failed phase with the
+// error.
+func printFinalProgress(cluster *agentpkg.Cluster, err error) {
+	progress := cluster.Progress()
+	progress.Phase = agentpkg.ProgressPhaseComplete
+	if err != nil {
+		progress.Phase = agentpkg.ProgressPhaseFailed
+		progress.Error = err.Error()
+	}
+	printProgress(progress)
 }
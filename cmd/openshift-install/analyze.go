@@ -1,50 +1,79 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/installer/pkg/gather/service"
+	"github.com/openshift/installer/pkg/gather/diagnosis"
 )
 
 var (
 	analyzeOpts struct {
 		gatherBundle string
+		output       string
 	}
 )
 
 func newAnalyzeCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "analyze",
+		Use:   "analyze [log-bundle.tar.gz]",
 		Short: "Analyze debugging data for a given installation failure",
 		Long: `Analyze debugging data for a given installation failure.
 
-This command helps users to analyze the reasons for an installation that failed while bootstrapping.`,
-		Args: cobra.ExactArgs(0),
-		Run: func(_ *cobra.Command, _ []string) {
+This command helps users to analyze the reasons for an installation that failed.
+It inspects a previously gathered log bundle offline, correlating the services of
+the bootstrap machine, the kubelet logs of the hosts and the status of the cluster
+operators into a diagnosis.`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
 			gatherBundle := analyzeOpts.gatherBundle
-			if gatherBundle == "" {
+			if len(args) == 1 {
+				gatherBundle = args[0]
+			} else if gatherBundle == "" {
 				var err error
 				gatherBundle, err = getGatherBundleFromAssetsDirectory()
 				if err != nil {
 					logrus.Fatal(err)
 				}
-			}
-			if !filepath.IsAbs(gatherBundle) {
+			} else if !filepath.IsAbs(gatherBundle) {
 				gatherBundle = filepath.Join(rootOpts.dir, gatherBundle)
 			}
-			if err := service.AnalyzeGatherBundle(gatherBundle); err != nil {
+			if err := runAnalyzeCmd(gatherBundle, analyzeOpts.output); err != nil {
 				logrus.Fatal(err)
 			}
 		},
 	}
 	cmd.PersistentFlags().StringVar(&analyzeOpts.gatherBundle, "file", "", "Filename of the bootstrap gather bundle; either absolute or relative to the assets directory")
+	cmd.PersistentFlags().StringVar(&analyzeOpts.output, "output", "text", "format of the diagnosis report: text or json")
 	return cmd
 }
 
+func runAnalyzeCmd(gatherBundle string, output string) error {
+	report, err := diagnosis.AnalyzeBundle(gatherBundle)
+	if err != nil {
+		return err
+	}
+	switch output {
+	case "text":
+		return report.Print(os.Stdout)
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(os.Stdout, string(data))
+		return err
+	default:
+		return errors.Errorf("unsupported output %q, must be text or json", output)
+	}
+}
+
 func getGatherBundleFromAssetsDirectory() (string, error) {
 	matches, err := filepath.Glob(filepath.Join(rootOpts.dir, "log-bundle-*.tar.gz"))
 	if err != nil {
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/bootstrap"
+)
+
+const (
+	externalBootstrapIgnitionFile = "bootstrap-external.ign"
+	externalBootstrapPointerFile  = "bootstrap-external-pointer.ign"
+)
+
+var bootstrapServeOpts struct {
+	bootstrapHost   string
+	listenHost      string
+	ignitionAddress string
+	advertiseHost   string
+}
+
+func newBootstrapCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bootstrap",
+		Short: "Run the bootstrap machine outside of the platform of the cluster",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newBootstrapServeCmd())
+	return cmd
+}
+
+func newBootstrapServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the endpoints of a bootstrap machine run on another platform",
+		Long: `Serves the endpoints of a bootstrap machine run on another platform than the
+cluster, e.g. a local libvirt virtual machine or an existing host, booted
+from the Ignition config of create external-bootstrap-ignition-config.
+
+The command serves that Ignition config to the bootstrap machine, and writes
+bootstrap-external-pointer.ign to the assets directory for the bootstrap
+machine to boot from: it fetches the config with a token generated for this
+run. It relays the Kubernetes API (6443) and the machine config server
+(22623) to the bootstrap machine, so the load balancers of the cluster
+point to the host running the command in place of the bootstrap machine.
+Stop it once bootstrapping completes.`,
+		Args: cobra.ExactArgs(0),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runBootstrapServeCmd(rootOpts.dir)
+		},
+	}
+	cmd.Flags().StringVar(&bootstrapServeOpts.bootstrapHost, "bootstrap-host", "", "hostname or IP of the bootstrap machine to relay the endpoints to")
+	cmd.Flags().StringVar(&bootstrapServeOpts.listenHost, "listen-host", "", "host to listen on for the relays of the endpoints, all the interfaces if empty")
+	cmd.Flags().StringVar(&bootstrapServeOpts.ignitionAddress, "ignition-address", ":22624", "address to serve the Ignition config of the bootstrap machine on, or empty to not serve it")
+	cmd.Flags().StringVar(&bootstrapServeOpts.advertiseHost, "advertise-host", "", "hostname or IP the bootstrap machine reaches this host at, for the pointer Ignition config; the address of the interface routing to --bootstrap-host if empty")
+	return cmd
+}
+
+func runBootstrapServeCmd(directory string) error {
+	if bootstrapServeOpts.bootstrapHost == "" {
+		return errors.New("--bootstrap-host is required")
+	}
+
+	s := &bootstrap.Server{
+		ListenHost:      bootstrapServeOpts.listenHost,
+		BootstrapHost:   bootstrapServeOpts.bootstrapHost,
+		Ports:           []int{bootstrap.APIPort, bootstrap.MachineConfigServerPort},
+		IgnitionAddress: bootstrapServeOpts.ignitionAddress,
+	}
+	if s.IgnitionAddress != "" {
+		ignitionPath := filepath.Join(directory, externalBootstrapIgnitionFile)
+		data, err := os.ReadFile(ignitionPath)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read the Ignition config of the bootstrap machine, run create external-bootstrap-ignition-config first")
+		}
+		s.Ignition = data
+
+		token := make([]byte, 32)
+		if _, err := rand.Read(token); err != nil {
+			return errors.Wrap(err, "failed to generate the token")
+		}
+		s.Token = hex.EncodeToString(token)
+
+		url, err := ignitionURL(s.IgnitionAddress, bootstrapServeOpts.advertiseHost, s.BootstrapHost)
+		if err != nil {
+			return err
+		}
+		pointer, err := bootstrap.PointerIgnition(url, s.Token)
+		if err != nil {
+			return err
+		}
+		pointerPath := filepath.Join(directory, externalBootstrapPointerFile)
+		if err := os.WriteFile(pointerPath, pointer, 0600); err != nil {
+			return errors.Wrap(err, "failed to write the pointer Ignition config")
+		}
+		logrus.Infof("Boot the bootstrap machine from %s", pointerPath)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return s.Serve(ctx)
+}
+
+// ignitionURL returns the URL the bootstrap machine fetches its Ignition
+// config at.
+func ignitionURL(address, advertiseHost, bootstrapHost string) (string, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid --ignition-address %q", address)
+	}
+	if advertiseHost != "" {
+		host = advertiseHost
+	} else if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+		// the address of the interface routing to the bootstrap
+		// machine; dialing UDP sends nothing
+		conn, err := net.Dial("udp", net.JoinHostPort(bootstrapHost, strconv.Itoa(bootstrap.APIPort)))
+		if err != nil {
+			return "", errors.Wrap(err, "failed to find the address the bootstrap machine reaches this host at, set --advertise-host")
+		}
+		host = conn.LocalAddr().(*net.UDPAddr).IP.String()
+		conn.Close()
+	}
+	return fmt.Sprintf("http://%s%s", net.JoinHostPort(host, port), bootstrap.IgnitionPath), nil
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/clusterset"
+	"github.com/openshift/installer/pkg/rhcos/cache"
+)
+
+var (
+	clusterSetOpts struct {
+		config string
+	}
+)
+
+func newCreateClustersCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clusters",
+		Short: "Create a set of OpenShift clusters concurrently",
+		Long: `Create the clusters of a cluster set concurrently, each with create cluster in
+its own assets directory. The installs share the cache of the images they
+download, and their progress is reported together.
+
+The cluster set is a YAML file such as:
+
+  parallelism: 2
+  imageCacheDir: cache
+  clusters:
+  - name: lab-1
+    installConfig: lab-1.yaml
+  - name: lab-2
+    dir: /srv/lab-2
+    installConfig: lab-2.yaml
+    credentialsFile: lab-2-credentials.yaml
+
+The assets directory of a cluster defaults to its name, and its
+install-config is copied to it unless it has one already. Relative paths are
+relative to the cluster set. The installs use the credentials file of their
+cluster, or else the one of the command. Re-running the command resumes the interrupted
+installs.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			cleanup := setupFileHook(rootOpts.dir)
+			defer cleanup()
+
+			set, err := clusterset.Load(clusterSetOpts.config)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			ctx, stop := interruptibleContext(context.Background())
+			defer stop()
+
+			statuses := clusterset.Create(ctx, set, runClusterSetInstall(set), reportClusterStatus)
+			if err := printClusterStatuses(os.Stdout, statuses); err != nil {
+				logrus.Error(err)
+			}
+			failed := 0
+			for _, status := range statuses {
+				if status.State != clusterset.StateSucceeded {
+					failed++
+				}
+			}
+			if failed > 0 {
+				logrus.Fatalf("%d of the %d clusters were not created", failed, len(statuses))
+			}
+		},
+	}
+	cmd.Flags().StringVar(&clusterSetOpts.config, "config", "", "YAML file of the cluster set to create")
+	if err := cmd.MarkFlagRequired("config"); err != nil {
+		logrus.Fatal(err)
+	}
+	return cmd
+}
+
+// runClusterSetInstall returns the function running the install of a
+// cluster of the set, with create cluster of this installer in the assets
+// directory of the cluster. The install sends its progress events to the
+// events of the cluster through a unix socket, and writes its logs to the
+// log file of its assets directory.
+func runClusterSetInstall(set *clusterset.ClusterSet) clusterset.RunFunc {
+	return func(ctx context.Context, c clusterset.Cluster, events io.Writer) error {
+		executable, err := os.Executable()
+		if err != nil {
+			return errors.Wrap(err, "failed to find the installer executable")
+		}
+		socketDir, err := os.MkdirTemp("", "openshift-install-events-")
+		if err != nil {
+			return errors.Wrap(err, "failed to create the directory of the events socket")
+		}
+		defer os.RemoveAll(socketDir)
+		socket := filepath.Join(socketDir, "events.sock")
+		listener, err := net.Listen("unix", socket)
+		if err != nil {
+			return errors.Wrap(err, "failed to listen for the events of the install")
+		}
+		defer listener.Close()
+
+		args := []string{"create", "cluster", "--dir", c.Dir, "--log-level", rootOpts.logLevel, "--progress-events", "unix:" + socket}
+		if createOpts.rollbackOnFailure {
+			args = append(args, "--rollback-on-failure")
+		}
+		if c.CredentialsFile != "" {
+			args = append(args, "--credentials-file", c.CredentialsFile)
+		} else if rootOpts.credentialsFile != "" {
+			args = append(args, "--credentials-file", rootOpts.credentialsFile)
+		}
+		// the install is not started with the context, so that it is not
+		// killed when interrupted: it receives the signal itself, and stops
+		// once its running steps complete
+		install := exec.Command(executable, args...)
+		install.Env = os.Environ()
+		if set.ImageCacheDir != "" {
+			install.Env = append(install.Env, fmt.Sprintf("%s=%s", cache.CacheDirEnv, set.ImageCacheDir))
+		}
+		if err := install.Start(); err != nil {
+			return errors.Wrapf(err, "failed to start the install of %s", c.Name)
+		}
+		// the install connects to the socket when it starts, and the events
+		// end when it exits and closes its connection
+		copied := make(chan struct{})
+		go func() {
+			defer close(copied)
+			conn, err := listener.Accept()
+			if err != nil {
+				// the install exited without connecting
+				return
+			}
+			defer conn.Close()
+			io.Copy(events, conn)
+		}()
+		err = install.Wait()
+		listener.Close()
+		<-copied
+		if err != nil {
+			return errors.Wrapf(err, "the install failed, see %s", c.Dir)
+		}
+		return nil
+	}
+}
+
+// reportClusterStatus logs the change of the status of a cluster of the set.
+func reportClusterStatus(status clusterset.Status) {
+	switch status.State {
+	case clusterset.StateRunning:
+		switch {
+		case status.Phase == "":
+			logrus.Infof("%s: started", status.Name)
+		case status.Percent != nil:
+			logrus.Infof("%s: %s (%d%%) %s", status.Name, status.Phase, *status.Percent, status.Message)
+		default:
+			logrus.Infof("%s: %s %s", status.Name, status.Phase, status.Message)
+		}
+	case clusterset.StateSucceeded:
+		logrus.Infof("%s: created in %v", status.Name, status.Finished.Sub(status.Started).Round(time.Second))
+	case clusterset.StateFailed:
+		logrus.Errorf("%s: failed during %s: %v", status.Name, status.Phase, status.Err)
+	}
+}
+
+// printClusterStatuses prints a table of the statuses of the clusters of the
+// set.
+func printClusterStatuses(w io.Writer, statuses []clusterset.Status) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSTATE\tPHASE\tDURATION\tDIR")
+	for _, status := range statuses {
+		duration := "-"
+		if !status.Finished.IsZero() {
+			duration = status.Finished.Sub(status.Started).Round(time.Second).String()
+		}
+		phase := status.Phase
+		if phase == "" {
+			phase = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", status.Name, status.State, phase, duration, status.Dir)
+	}
+	return tw.Flush()
+}
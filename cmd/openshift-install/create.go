@@ -3,11 +3,15 @@ package main
 import (
 	"context"
 	"crypto/x509"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -28,14 +32,21 @@ import (
 	routeclient "github.com/openshift/client-go/route/clientset/versioned"
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/agent/agentconfig"
-	"github.com/openshift/installer/pkg/asset/cluster"
 	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 	"github.com/openshift/installer/pkg/asset/logging"
 	assetstore "github.com/openshift/installer/pkg/asset/store"
 	targetassets "github.com/openshift/installer/pkg/asset/targets"
-	destroybootstrap "github.com/openshift/installer/pkg/destroy/bootstrap"
+	"github.com/openshift/installer/pkg/checkpoint"
+	"github.com/openshift/installer/pkg/client"
+	"github.com/openshift/installer/pkg/deterministic"
 	"github.com/openshift/installer/pkg/gather/service"
+	"github.com/openshift/installer/pkg/hooks"
+	"github.com/openshift/installer/pkg/metrics/progress"
 	timer "github.com/openshift/installer/pkg/metrics/timer"
+	"github.com/openshift/installer/pkg/provenance"
+	"github.com/openshift/installer/pkg/tracing"
+	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/baremetal"
 	cov1helpers "github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
 	"github.com/openshift/library-go/pkg/route/routeapihelpers"
@@ -47,6 +58,38 @@ type target struct {
 	assets  []asset.WritableAsset
 }
 
+// clusterVersionProgressRegexp captures the percentage from the progressing
+// message of the ClusterVersion, e.g. "Working towards 4.12.0: 567 of 771
+// done (73% complete)".
+var clusterVersionProgressRegexp = regexp.MustCompile(`\((\d+)% complete`)
+
+var (
+	createOpts struct {
+		deterministic  bool
+		clusterID      string
+		nonInteractive bool
+		answers        map[string]*string
+		pullSecretFile string
+
+		preserveBootstrap        bool
+		bootstrapTeardownDelay   time.Duration
+		rollbackOnFailure        bool
+		retainBootstrapArtifacts string
+
+		releaseImage         string
+		verificationKeyFiles []string
+		signatureStores      []string
+
+		provenanceKeyFile   string
+		policyDir           string
+		checkRegistryAccess bool
+		allowUnknownFields  bool
+		validity            []string
+
+		outputDir string
+	}
+)
+
 const (
 	exitCodeInstallConfigError = iota + 3
 	exitCodeInfrastructureFailed
@@ -100,6 +143,70 @@ var (
 		assets: targetassets.SingleNodeIgnitionConfig,
 	}
 
+	externalBootstrapIgnitionConfigTarget = target{
+		name: "External Bootstrap Ignition Config",
+		command: &cobra.Command{
+			Use:   "external-bootstrap-ignition-config",
+			Short: "Generates the Ignition Config assets with a bootstrap machine on another platform",
+			Long: `Generates the Ignition configs of the cluster, with the config of a bootstrap
+machine run on another platform than the cluster, e.g. a local libvirt
+virtual machine or an existing host, in bootstrap-external.ign. The config
+has none of the files and services of the platform of the cluster. Serve
+it, and relay the endpoints the control plane machines reach the bootstrap
+machine at, with bootstrap serve.`,
+		},
+		assets: targetassets.ExternalBootstrapIgnitionConfig,
+	}
+
+	rootCASigningRequestTarget = target{
+		name: "Root CA Signing Request",
+		command: &cobra.Command{
+			Use:   "root-ca-signing-request",
+			Short: "Generates the key of the root CA and a request for an external CA to sign it",
+			Long: `Generates the private key of the root CA of the cluster in tls/root-ca.key
+and a request to sign its certificate in tls/root-ca.csr, for the root CA
+to be issued by an external CA instead of being self-signed. Supply the
+certificate the external CA issues, followed by the certificates of its
+chain, in tls/root-ca.crt before generating the other assets.`,
+		},
+		assets: targetassets.RootCASigningRequest,
+	}
+
+	infraPlanTarget = target{
+		name: "Infrastructure Plan",
+		command: &cobra.Command{
+			Use:   "infra-plan",
+			Short: "Plans the infrastructure of an OpenShift cluster without creating it",
+			Long: `Plans the infrastructure create cluster would create, and writes the plan
+to infra-plan.json for review, or for policy engines to gate on. Terraform
+stages which need the outputs of other stages are deferred until those
+are created.`,
+			PostRun: func(_ *cobra.Command, _ []string) {
+				if err := printInfraPlan(os.Stdout, rootOpts.dir, infraPlanOpts.output); err != nil {
+					logrus.Fatal(err)
+				}
+			},
+		},
+		assets: targetassets.InfraPlan,
+	}
+
+	upiTemplatesTarget = target{
+		name: "UPI Templates",
+		command: &cobra.Command{
+			Use:   "upi-templates",
+			Short: "Generates the templates of the infrastructure to provision it yourself",
+			Long: `Generates the templates of the network, the firewall rules and the machines
+of the cluster in the upi directory, filled in with the zones, the CIDRs and
+the instance types of the install-config: a CloudFormation template on AWS,
+an ARM template on Azure, a Deployment Manager configuration on GCP and a
+terraform configuration on vSphere. The machines boot with the Ignition
+configs of the cluster, and the bootstrap machine fetches bootstrap.ign from
+a location given as a parameter of the template. The load balancers and the
+DNS records of the API and of the ingress are left to the user.`,
+		},
+		assets: targetassets.UPITemplates,
+	}
+
 	clusterTarget = target{
 		name: "Cluster",
 		command: &cobra.Command{
@@ -115,53 +222,94 @@ var (
 
 				// FIXME: pulling the kubeconfig and metadata out of the root
 				// directory is a bit cludgy when we already have them in memory.
-				config, err := clientcmd.BuildConfigFromFlags("", filepath.Join(rootOpts.dir, "auth", "kubeconfig"))
+				config, err := loadKubeconfig(rootOpts.dir)
 				if err != nil {
 					logrus.Fatal(errors.Wrap(err, "loading kubeconfig"))
 				}
 
-				timer.StartTimer("Bootstrap Complete")
-				if err := waitForBootstrapComplete(ctx, config); err != nil {
-					bundlePath, gatherErr := runGatherBootstrapCmd(rootOpts.dir)
-					if gatherErr != nil {
-						logrus.Error("Attempted to gather debug logs after installation failure: ", gatherErr)
-					}
-					if err := logClusterOperatorConditions(ctx, config); err != nil {
-						logrus.Error("Attempted to gather ClusterOperator status after installation failure: ", err)
-					}
-					logrus.Error("Bootstrap failed to complete: ", err.Unwrap())
-					logrus.Error(err.Error())
-					if gatherErr == nil {
-						if err := service.AnalyzeGatherBundle(bundlePath); err != nil {
-							logrus.Error("Attempted to analyze the debug logs after installation failure: ", err)
+				checkpoints, err := checkpoint.Load(rootOpts.dir)
+				if err != nil {
+					logrus.Fatal(err)
+				}
+
+				if checkpoints.Completed(bootstrapCompleteCheckpoint) {
+					logrus.Info("Bootstrap already completed")
+				} else {
+					timer.StartTimer("Bootstrap Complete")
+					if err := waitForBootstrapComplete(ctx, config); err != nil {
+						bundlePath, gatherErr := runGatherBootstrapCmd(rootOpts.dir)
+						if gatherErr != nil {
+							logrus.Error("Attempted to gather debug logs after installation failure: ", gatherErr)
 						}
-						logrus.Infof("Bootstrap gather logs captured here %q", bundlePath)
+						if err := logClusterOperatorConditions(ctx, config); err != nil {
+							logrus.Error("Attempted to gather ClusterOperator status after installation failure: ", err)
+						}
+						logrus.Error("Bootstrap failed to complete: ", err.Unwrap())
+						logrus.Error(err.Error())
+						if gatherErr == nil {
+							if err := service.AnalyzeGatherBundle(bundlePath); err != nil {
+								logrus.Error("Attempted to analyze the debug logs after installation failure: ", err)
+							}
+							analyzeFindings(bundlePath)
+							logrus.Infof("Bootstrap gather logs captured here %q", bundlePath)
+						}
+						exitWithCode(exitCodeBootstrapFailed)
+					}
+					timer.StopTimer("Bootstrap Complete")
+					if err := checkpoints.Complete(bootstrapCompleteCheckpoint); err != nil {
+						logrus.Fatal(err)
 					}
-					logrus.Exit(exitCodeBootstrapFailed)
 				}
-				timer.StopTimer("Bootstrap Complete")
-				timer.StartTimer("Bootstrap Destroy")
 
-				if oi, ok := os.LookupEnv("OPENSHIFT_INSTALL_PRESERVE_BOOTSTRAP"); ok && oi != "" {
-					logrus.Warn("OPENSHIFT_INSTALL_PRESERVE_BOOTSTRAP is set, not destroying bootstrap resources. " +
-						"Warning: this should only be used for debugging purposes, and poses a risk to cluster stability.")
-				} else {
-					logrus.Info("Destroying the bootstrap resources...")
-					err = destroybootstrap.Destroy(rootOpts.dir)
-					if err != nil {
+				teardown, err := destroyBootstrap(ctx, checkpoints, func(ctx context.Context) error {
+					return destroyBootstrapResources(ctx, rootOpts.dir)
+				})
+				if err != nil {
+					logrus.Fatal(err)
+				}
+				// the failures below exit through logrus, and still wait for
+				// the delayed teardown
+				logrus.DeferExitHandler(func() {
+					if err := teardown.wait(); err != nil {
+						logrus.Error(err)
+					}
+				})
+
+				if !checkpoints.Completed(postBootstrapHooksCheckpoint) {
+					if err := runHooks(ctx, hooks.PostBootstrap); err != nil {
+						logrus.Fatal(err)
+					}
+					if err := checkpoints.Complete(postBootstrapHooksCheckpoint); err != nil {
 						logrus.Fatal(err)
 					}
 				}
-				timer.StopTimer("Bootstrap Destroy")
 
 				err = waitForInstallComplete(ctx, config, rootOpts.dir)
 				if err != nil {
 					if err2 := logClusterOperatorConditions(ctx, config); err2 != nil {
 						logrus.Error("Attempted to gather ClusterOperator status after installation failure: ", err2)
 					}
+					if bundlePath, gatherErr := gatherNodeJournals(config, rootOpts.dir); gatherErr != nil {
+						logrus.Error("Attempted to gather node journals after installation failure: ", gatherErr)
+					} else {
+						analyzeFindings(bundlePath)
+						logrus.Infof("Node journals captured here %q", bundlePath)
+					}
 					logTroubleshootingLink()
 					logrus.Error(err)
-					logrus.Exit(exitCodeInstallFailed)
+					if err := teardown.wait(); err != nil {
+						logrus.Error(err)
+					}
+					exitWithCode(exitCodeInstallFailed)
+				}
+				if err := teardown.wait(); err != nil {
+					logrus.Fatal(err)
+				}
+				if err := runHooks(ctx, hooks.PostInstall); err != nil {
+					logrus.Fatal(err)
+				}
+				if err := checkpoint.Remove(rootOpts.dir); err != nil {
+					logrus.Warn(err)
 				}
 				timer.StopTimer(timer.TotalTimeElapsed)
 				timer.LogSummary()
@@ -170,7 +318,7 @@ var (
 		assets: targetassets.Cluster,
 	}
 
-	targets = []target{installConfigTarget, manifestsTarget, ignitionConfigsTarget, clusterTarget, singleNodeIgnitionConfigTarget}
+	targets = []target{installConfigTarget, rootCASigningRequestTarget, manifestsTarget, ignitionConfigsTarget, infraPlanTarget, upiTemplatesTarget, clusterTarget, singleNodeIgnitionConfigTarget, externalBootstrapIgnitionConfigTarget}
 )
 
 // clusterCreateError defines a custom error type that would help identify where the error occurs
@@ -236,76 +384,383 @@ func newCreateCmd() *cobra.Command {
 		t.command.Run = runTargetCmd(t.assets...)
 		cmd.AddCommand(t.command)
 	}
+	cmd.AddCommand(newCreateClustersCmd())
+	cmd.AddCommand(newCreateAssetCmd())
+
+	infraPlanTarget.command.Flags().StringVar(&infraPlanOpts.output, "output", "text", "format of the plan printed: text or json")
+	for _, t := range []target{manifestsTarget, ignitionConfigsTarget} {
+		t.command.Flags().StringVar(&createOpts.outputDir, "output-dir", "", "directory to write the generated files to instead of the assets directory, or - to write them to stdout as a tar archive; the assets directory and its state file are left as they were")
+	}
 
+	cmd.PersistentFlags().BoolVar(&createOpts.deterministic, "deterministic", false, "generate the same assets from the same install-config, deriving the IDs, passwords and certificate serial numbers from the secret seed in OPENSHIFT_INSTALL_DETERMINISTIC_SEED and the timestamps from SOURCE_DATE_EPOCH; private keys and password hashes are still random unless supplied in the tls directory")
+	cmd.PersistentFlags().StringVar(&createOpts.clusterID, "cluster-id", "", "cluster ID (a UUID) to use with --deterministic instead of deriving one from the seed")
+	addWaitTimeoutFlag(cmd)
+
+	createOpts.answers = map[string]*string{}
+	for _, key := range []string{answers.Platform, answers.Region, answers.BaseDomain, answers.ClusterName, answers.SSHKeyFile} {
+		createOpts.answers[key] = cmd.PersistentFlags().String(key, "", fmt.Sprintf("answer to the %s prompt of the install-config survey (or set %s)", key, answers.EnvVar(key)))
+	}
+	cmd.PersistentFlags().StringVar(&createOpts.pullSecretFile, "pull-secret-file", "", fmt.Sprintf("file with the answer to the pull-secret prompt of the install-config survey (or set %s)", answers.EnvVar(answers.PullSecret)))
+	cmd.PersistentFlags().BoolVar(&createOpts.nonInteractive, "non-interactive", false, "fail instead of prompting when the install-config survey has a question without an answer")
+	cmd.PersistentFlags().BoolVar(&createOpts.preserveBootstrap, "preserve-bootstrap", false, "keep the bootstrap resources after bootstrapping completes, for debugging (or set OPENSHIFT_INSTALL_PRESERVE_BOOTSTRAP); destroy them later with destroy bootstrap")
+	cmd.PersistentFlags().BoolVar(&createOpts.rollbackOnFailure, "rollback-on-failure", false, "destroy the partially created infrastructure when provisioning it fails or is interrupted, instead of leaving it to resume the install")
+	cmd.PersistentFlags().DurationVar(&createOpts.bootstrapTeardownDelay, "bootstrap-teardown-delay", 0, "how long to keep the bootstrap resources after bootstrapping completes before destroying them automatically; the install waits for the teardown before it exits")
+	cmd.PersistentFlags().StringVar(&createOpts.retainBootstrapArtifacts, "retain-bootstrap-artifacts", "", "gather the logs, the rendered assets and the journals of the bootstrap machine before destroying it, and store the bundle in Secrets of the cluster (secret) or in an object store (s3://<bucket>[/<prefix>], gs://<bucket>[/<prefix>] or azblob://<account>/<container>[/<prefix>])")
+	cmd.PersistentFlags().StringVar(&createOpts.releaseImage, "release-image", "", "pull spec of the release image to install instead of the one the installer was built for; overrides releaseImage in the install-config")
+	cmd.PersistentFlags().StringArrayVar(&createOpts.verificationKeyFiles, "release-image-verification-key", nil, "file with an ASCII-armored GPG public key the release image must be signed with (may be repeated)")
+	cmd.PersistentFlags().StringArrayVar(&createOpts.signatureStores, "release-image-signature-store", nil, "base URL of a store to look up the signatures of the release image in (may be repeated)")
+	cmd.PersistentFlags().StringVar(&createOpts.policyDir, "policy-dir", "", "directory of Rego (.rego) and CEL (.cel) policies the install-config and the manifests must comply with before the Ignition configs are generated. The deny rules of the openshift.install package of the Rego policies report the violations, and the opa command, which evaluates them, must be installed. Each CEL policy is an expression over input which evaluates to the list of its violations or to whether the install complies with it")
+	cmd.PersistentFlags().BoolVar(&createOpts.checkRegistryAccess, "check-registry-access", false, "check that the registry of the release image can be reached from the installer host through the proxy of the install-config, and accepts the pull secret, before the cluster is provisioned")
+	cmd.PersistentFlags().BoolVar(&createOpts.allowUnknownFields, "allow-unknown-fields", false, "warn about the unknown fields of the install-config and ignore them instead of rejecting them; deprecated, for install-configs written for installers which accepted them")
+	cmd.PersistentFlags().StringArrayVar(&createOpts.validity, "certificate-validity", nil, "how long a certificate the installer generates is valid, as the base name of its files in the tls directory and a duration, e.g. root-ca=43800h (may be repeated); a certificate cannot be valid for longer than its CA")
+	cmd.PersistentFlags().StringVar(&createOpts.provenanceKeyFile, "provenance-key", "", "file with a PEM-encoded, unencrypted ECDSA, RSA or Ed25519 private key to sign provenance.json, the checksums of the generated manifests and Ignition configs, with; verify it with cosign verify-blob")
 	return cmd
 }
 
-func asFileWriter(a asset.WritableAsset) asset.FileWriter {
-	switch v := a.(type) {
-	case asset.FileWriter:
-		return v
+// bootstrapTeardown is the destruction of the bootstrap resources once the
+// teardown delay elapsed.
+type bootstrapTeardown struct {
+	done chan struct{}
+	err  error
+}
+
+// wait waits for the teardown and returns its error. The error is returned
+// to the first caller only, so that it is reported once.
+func (t *bootstrapTeardown) wait() error {
+	if t == nil {
+		return nil
+	}
+	select {
+	case <-t.done:
 	default:
-		return asset.NewDefaultFileWriter(a)
+		logrus.Info("Waiting for the delayed teardown of the bootstrap resources...")
+		<-t.done
 	}
+	err := t.err
+	t.err = nil
+	return err
 }
 
-func runTargetCmd(targets ...asset.WritableAsset) func(cmd *cobra.Command, args []string) {
-	runner := func(directory string) error {
-		assetStore, err := assetstore.NewStore(directory)
+// destroyBootstrap destroys the bootstrap resources with destroy after
+// bootstrapping completes, unless they are preserved. With a teardown delay
+// it destroys them in the background once the delay elapsed, or returns the
+// error of the context if it is done first, and the returned teardown is
+// waited for.
+func destroyBootstrap(ctx context.Context, checkpoints *checkpoint.Checkpoints, destroy func(context.Context) error) (*bootstrapTeardown, error) {
+	if checkpoints.Completed(bootstrapDestroyCheckpoint) {
+		logrus.Info("The bootstrap resources are already destroyed")
+		return nil, nil
+	}
+	preserve := createOpts.preserveBootstrap
+	if oi, ok := os.LookupEnv("OPENSHIFT_INSTALL_PRESERVE_BOOTSTRAP"); ok && oi != "" {
+		preserve = true
+	}
+	if preserve && createOpts.bootstrapTeardownDelay > 0 {
+		return nil, errors.New("the bootstrap resources cannot be both preserved and destroyed after a delay")
+	}
+	if preserve {
+		logrus.Warn("Preserving the bootstrap resources, not destroying them. " +
+			"Warning: this should only be used for debugging purposes, and poses a risk to cluster stability.")
+		return nil, nil
+	}
+
+	run := func() error {
+		timer.StartTimer("Bootstrap Destroy")
+		defer timer.StopTimer("Bootstrap Destroy")
+		if err := destroy(ctx); err != nil {
+			return err
+		}
+		return checkpoints.Complete(bootstrapDestroyCheckpoint)
+	}
+	if createOpts.bootstrapTeardownDelay <= 0 {
+		return nil, run()
+	}
+
+	logrus.Infof("Destroying the bootstrap resources in %v (at %v)", createOpts.bootstrapTeardownDelay, time.Now().Add(createOpts.bootstrapTeardownDelay).Format(time.RFC3339))
+	teardown := &bootstrapTeardown{done: make(chan struct{})}
+	go func() {
+		defer close(teardown.done)
+		delay := time.NewTimer(createOpts.bootstrapTeardownDelay)
+		defer delay.Stop()
+		select {
+		case <-ctx.Done():
+			teardown.err = errors.Wrap(ctx.Err(), "the bootstrap resources were not destroyed")
+		case <-delay.C:
+			teardown.err = run()
+		}
+	}()
+	return teardown, nil
+}
+
+// destroyBootstrapResources destroys the bootstrap resources of the cluster
+// of the directory, after retaining the artifacts of the bootstrap machine
+// if requested.
+func destroyBootstrapResources(ctx context.Context, dir string) error {
+	if createOpts.retainBootstrapArtifacts != "" {
+		// the install goes on without the artifacts
+		if err := retainBootstrapArtifacts(ctx, dir, createOpts.retainBootstrapArtifacts); err != nil {
+			logrus.Warnf("Failed to retain the artifacts of the bootstrap machine: %v", err)
+		}
+	}
+	logrus.Info("Destroying the bootstrap resources...")
+	installer, err := client.New(dir)
+	if err != nil {
+		return err
+	}
+	return installer.DestroyBootstrap(ctx)
+}
+
+// setAnswers passes the answers to the install-config survey given as flags
+// to the survey.
+func setAnswers() error {
+	for key, value := range createOpts.answers {
+		if *value != "" {
+			answers.Set(key, *value)
+		}
+	}
+	if createOpts.pullSecretFile != "" {
+		pullSecret, err := os.ReadFile(createOpts.pullSecretFile)
 		if err != nil {
-			return errors.Wrap(err, "failed to create asset store")
+			return errors.Wrap(err, "failed to read the pull secret")
 		}
+		answers.Set(answers.PullSecret, strings.TrimSpace(string(pullSecret)))
+	}
+	answers.SetNonInteractive(createOpts.nonInteractive)
+	return nil
+}
 
-		for _, a := range targets {
-			err := assetStore.Fetch(a, targets...)
-			if err != nil {
-				err = errors.Wrapf(err, "failed to fetch %s", a.Name())
-			}
+// releaseImageOverride returns the release image given by --release-image,
+// if any.
+func releaseImageOverride() (*types.ReleaseImage, error) {
+	if createOpts.releaseImage == "" {
+		if len(createOpts.verificationKeyFiles) > 0 || len(createOpts.signatureStores) > 0 {
+			return nil, errors.New("--release-image-verification-key and --release-image-signature-store require --release-image")
+		}
+		return nil, nil
+	}
 
-			err2 := asFileWriter(a).PersistToFile(directory)
-			if err2 != nil {
-				err2 = errors.Wrapf(err2, "failed to write asset (%s) to disk", a.Name())
-				if err != nil {
-					logrus.Error(err2)
-					return err
-				}
-				return err2
-			}
+	releaseImage := &types.ReleaseImage{
+		PullSpec:        createOpts.releaseImage,
+		SignatureStores: createOpts.signatureStores,
+	}
+	for _, file := range createOpts.verificationKeyFiles {
+		key, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read the release image verification key")
+		}
+		releaseImage.VerificationKeys = append(releaseImage.VerificationKeys, string(key))
+	}
+	return releaseImage, nil
+}
+
+// deterministicSource returns the source to generate assets with
+// deterministically when --deterministic is set, or nil otherwise.
+func deterministicSource() (*deterministic.Source, error) {
+	if !createOpts.deterministic {
+		if createOpts.clusterID != "" {
+			return nil, errors.New("--cluster-id requires --deterministic")
+		}
+		return nil, nil
+	}
+	seed := os.Getenv("OPENSHIFT_INSTALL_DETERMINISTIC_SEED")
+	if seed == "" {
+		return nil, errors.New("--deterministic requires OPENSHIFT_INSTALL_DETERMINISTIC_SEED to be set to a secret seed")
+	}
+	epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64)
+	if err != nil {
+		return nil, errors.New("--deterministic requires SOURCE_DATE_EPOCH to be set to a Unix timestamp")
+	}
+	if createOpts.clusterID != "" {
+		if _, err := uuid.Parse(createOpts.clusterID); err != nil {
+			return nil, errors.Wrapf(err, "invalid --cluster-id %q", createOpts.clusterID)
+		}
+	}
+	source, err := deterministic.New(deterministic.Config{
+		Seed:      []byte(seed),
+		Time:      time.Unix(epoch, 0).UTC(),
+		ClusterID: createOpts.clusterID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid OPENSHIFT_INSTALL_DETERMINISTIC_SEED")
+	}
+	logrus.Warn("Generating assets deterministically: anyone who knows the seed can derive the passwords of the cluster")
+	logrus.Warn("Private keys and the kubeadmin password hash are still generated randomly, supply them in the tls directory of the assets directory (with OPENSHIFT_INSTALL_LOAD_CLUSTER_CERTS=true for the keys) to reproduce them")
+	return source, nil
+}
+
+// certificateValidity returns the validity of the certificates set with
+// --certificate-validity.
+func certificateValidity() (map[string]time.Duration, error) {
+	if len(createOpts.validity) == 0 {
+		return nil, nil
+	}
+	validity := make(map[string]time.Duration, len(createOpts.validity))
+	for _, v := range createOpts.validity {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok || name == "" {
+			return nil, errors.Errorf("invalid --certificate-validity %q: must be of the form name=duration", v)
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid --certificate-validity %q", v)
+		}
+		if d <= 0 {
+			return nil, errors.Errorf("invalid --certificate-validity %q: the duration must be positive", v)
+		}
+		validity[name] = d
+	}
+	return validity, nil
+}
 
+func runTargetCmd(targets ...asset.WritableAsset) func(cmd *cobra.Command, args []string) {
+	runner := func(ctx context.Context, directory string) error {
+		releaseImage, err := releaseImageOverride()
+		if err != nil {
+			return err
+		}
+		source, err := deterministicSource()
+		if err != nil {
+			return err
+		}
+		validity, err := certificateValidity()
+		if err != nil {
+			return err
+		}
+		options := []client.Option{client.WithReleaseImage(releaseImage), client.WithSource(source), client.WithPolicyDir(createOpts.policyDir), client.WithCertificateValidity(validity)}
+		if createOpts.checkRegistryAccess {
+			options = append(options, client.WithRegistryAccessChecks())
+		}
+		if createOpts.allowUnknownFields {
+			options = append(options, client.WithUnknownFields())
+		}
+		if createOpts.provenanceKeyFile != "" {
+			key, err := provenance.LoadPrivateKey(createOpts.provenanceKeyFile)
 			if err != nil {
-				return err
+				return errors.Wrap(err, "invalid --provenance-key")
 			}
+			options = append(options, client.WithProvenanceKey(key))
 		}
-		return nil
+		switch createOpts.outputDir {
+		case "":
+		case "-":
+			options = append(options, client.WithOutputArchive(os.Stdout))
+		default:
+			if err := os.MkdirAll(createOpts.outputDir, 0750); err != nil {
+				return errors.Wrap(err, "failed to create the output directory")
+			}
+			options = append(options, client.WithOutputDir(createOpts.outputDir))
+		}
+		installer, err := client.New(directory, options...)
+		if err != nil {
+			return err
+		}
+		return installer.Generate(ctx, targets...)
 	}
 
 	return func(cmd *cobra.Command, args []string) {
 		timer.StartTimer(timer.TotalTimeElapsed)
 
-		cleanup := setupFileHook(rootOpts.dir)
-		defer cleanup()
+		// the files are written to the output directory, if any, along with
+		// the log, and the archive streamed to stdout is not logged to a
+		// file
+		outputDir := rootOpts.dir
+		if createOpts.outputDir != "" {
+			outputDir = createOpts.outputDir
+		}
+		if outputDir != "-" {
+			cleanup := setupFileHook(outputDir)
+			defer cleanup()
+		}
+
+		if err := setAnswers(); err != nil {
+			logrus.Fatal(err)
+		}
 
-		cluster.InstallDir = rootOpts.dir
+		ctx, stop := interruptibleContext(tracing.RootContext())
+		defer stop()
+		// provisioning is whether the command provisions the infrastructure
+		provisioning := cmd.Name() == "cluster" || cmd.Name() == "resume"
 
-		err := runner(rootOpts.dir)
+		err := runner(ctx, rootOpts.dir)
+		if rollbackRequired(ctx, provisioning, err) {
+			rollbackProvisioning(rootOpts.dir, destroyCluster(rootOpts.dir))
+		}
 		if err != nil {
-			if strings.Contains(err.Error(), asset.InstallConfigError) {
+			if client.IsInstallConfigError(err) {
 				logrus.Error(err)
-				logrus.Exit(exitCodeInstallConfigError)
+				exitWithCode(exitCodeInstallConfigError)
 			}
-			if strings.Contains(err.Error(), asset.ClusterCreationError) {
+			if client.IsInfrastructureError(err) {
 				logrus.Error(err)
-				logrus.Exit(exitCodeInfrastructureFailed)
+				exitWithCode(exitCodeInfrastructureFailed)
 			}
 			logrus.Fatal(err)
 		}
-		switch cmd.Name() {
-		case "cluster", "image", "pxe-files":
+		if provisioning && ctx.Err() != nil {
+			logrus.Fatal("Interrupted after the infrastructure was provisioned, resume the install with openshift-install resume")
+		}
+		switch {
+		case cmd.Name() == "cluster", cmd.Name() == "resume", cmd.Name() == "infra-plan", cmd.Name() == "image", cmd.Name() == "pxe-files":
+		case outputDir == "-":
 		default:
-			logrus.Infof(logging.LogCreatedFiles(cmd.Name(), rootOpts.dir, targets))
+			logrus.Infof(logging.LogCreatedFiles(cmd.Name(), outputDir, targets))
+		}
+
+	}
+}
+
+// runHooks runs the hooks of the install-config for the phase.
+func runHooks(ctx context.Context, phase string) error {
+	ic := loadInstallConfig(rootOpts.dir)
+	if ic == nil || len(hooks.ForPhase(ic.Hooks, phase)) == 0 {
+		return nil
+	}
+	kubeconfig, cleanup, err := hookKubeconfig(rootOpts.dir)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	event := hooks.Event{
+		Phase:       phase,
+		ClusterName: ic.ObjectMeta.Name,
+		AssetDir:    rootOpts.dir,
+		Kubeconfig:  kubeconfig,
+	}
+	if assetStore, err := assetstore.NewStore(rootOpts.dir); err == nil {
+		if clusterID, err := assetStore.Load(&installconfig.ClusterID{}); err == nil && clusterID != nil {
+			event.InfraID = clusterID.(*installconfig.ClusterID).InfraID
 		}
+	}
+	return hooks.Run(ctx, ic.Hooks, event)
+}
 
+// hookKubeconfig returns the path of the admin kubeconfig for the hooks. When
+// the kubeconfig is encrypted with the state, the hooks are given a plaintext
+// copy, which the returned function removes.
+func hookKubeconfig(directory string) (string, func(), error) {
+	kubeconfig := filepath.Join(directory, "auth", "kubeconfig")
+	data, err := os.ReadFile(kubeconfig)
+	if err != nil || !assetstore.Encrypted(data) {
+		return kubeconfig, func() {}, nil
+	}
+	data, err = assetstore.DecryptFile(kubeconfig, data)
+	if err != nil {
+		return "", nil, err
+	}
+	f, err := os.CreateTemp("", "kubeconfig-")
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to create the kubeconfig of the hooks")
+	}
+	cleanup := func() { os.Remove(f.Name()) }
+	_, err = f.Write(data)
+	if err2 := f.Close(); err == nil {
+		err = err2
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, errors.Wrap(err, "failed to write the kubeconfig of the hooks")
 	}
+	return f.Name(), cleanup, nil
 }
 
 // addRouterCAToClusterCA adds router CA to cluster CA in kubeconfig
@@ -323,7 +778,11 @@ func addRouterCAToClusterCA(ctx context.Context, config *rest.Config, directory
 
 	routerCrtBytes := []byte(caConfigMap.Data["ca-bundle.crt"])
 	kubeconfig := filepath.Join(directory, "auth", "kubeconfig")
-	kconfig, err := clientcmd.LoadFromFile(kubeconfig)
+	data, err := assetstore.ReadFile(kubeconfig)
+	if err != nil {
+		return errors.Wrap(err, "loading kubeconfig")
+	}
+	kconfig, err := clientcmd.Load(data)
 	if err != nil {
 		return errors.Wrap(err, "loading kubeconfig")
 	}
@@ -348,7 +807,15 @@ func addRouterCAToClusterCA(ctx context.Context, config *rest.Config, directory
 		newCA := append(routerCrtBytes, clusterCABytes...)
 		c.CertificateAuthorityData = newCA
 	}
-	if err := clientcmd.WriteToFile(*kconfig, kubeconfig); err != nil {
+	data, err = clientcmd.Write(*kconfig)
+	if err != nil {
+		return errors.Wrap(err, "writing kubeconfig")
+	}
+	data, err = assetstore.EncryptFile(filepath.Join("auth", "kubeconfig"), data)
+	if err != nil {
+		return errors.Wrap(err, "writing kubeconfig")
+	}
+	if err := os.WriteFile(kubeconfig, data, 0o600); err != nil {
 		return errors.Wrap(err, "writing kubeconfig")
 	}
 	return nil
@@ -415,6 +882,36 @@ func waitForBootstrapComplete(ctx context.Context, config *rest.Config) *cluster
 	return waitForBootstrapConfigMap(ctx, client)
 }
 
+// streamBootstrapEvents logs the events the bootstrap machine records on the
+// bootstrap configmap in kube-system as it progresses, e.g. pulling the
+// release image and rendering the control plane, until the context is done.
+func streamBootstrapEvents(ctx context.Context, client *kubernetes.Clientset) {
+	_, err := clientwatch.UntilWithSync(
+		ctx,
+		cache.NewListWatchFromClient(client.CoreV1().RESTClient(), "events", "kube-system", fields.OneTermEqualSelector("involvedObject.name", "bootstrap")),
+		&corev1.Event{},
+		nil,
+		func(event watch.Event) (bool, error) {
+			if event.Type != watch.Added {
+				return false, nil
+			}
+			if e, ok := event.Object.(*corev1.Event); ok {
+				logBootstrapProgress(e.Message)
+			}
+			return false, nil
+		},
+	)
+	if err != nil && ctx.Err() == nil {
+		logrus.Debugf("Stopped streaming bootstrap events: %v", err)
+	}
+}
+
+// logBootstrapProgress reports what the bootstrap machine is doing.
+func logBootstrapProgress(message string) {
+	logrus.Infof("Bootstrap: %s", message)
+	progress.Status("Bootstrap Complete", message)
+}
+
 // waitForBootstrapConfigMap watches the configmaps in the kube-system namespace
 // and waits for the bootstrap configmap to report that bootstrapping has
 // completed.
@@ -422,13 +919,11 @@ func waitForBootstrapConfigMap(ctx context.Context, client *kubernetes.Clientset
 	timeout := 30 * time.Minute
 
 	// Wait longer for baremetal, due to length of time it takes to boot
-	if assetStore, err := assetstore.NewStore(rootOpts.dir); err == nil {
-		if installConfig, err := assetStore.Load(&installconfig.InstallConfig{}); err == nil && installConfig != nil {
-			if installConfig.(*installconfig.InstallConfig).Config.Platform.Name() == baremetal.Name {
-				timeout = 60 * time.Minute
-			}
-		}
+	ic := loadInstallConfig(rootOpts.dir)
+	if ic != nil && ic.Platform.Name() == baremetal.Name {
+		timeout = 60 * time.Minute
 	}
+	timeout = waitTimeout(bootstrapCompletePhase, ic, timeout)
 
 	untilTime := time.Now().Add(timeout)
 	logrus.Infof("Waiting up to %v (until %v) for bootstrapping to complete...",
@@ -437,6 +932,9 @@ func waitForBootstrapConfigMap(ctx context.Context, client *kubernetes.Clientset
 	waitCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	go streamBootstrapEvents(waitCtx, client)
+
+	lastProgress := ""
 	_, err := clientwatch.UntilWithSync(
 		waitCtx,
 		cache.NewListWatchFromClient(client.CoreV1().RESTClient(), "configmaps", "kube-system", fields.OneTermEqualSelector("metadata.name", "bootstrap")),
@@ -453,6 +951,10 @@ func waitForBootstrapConfigMap(ctx context.Context, client *kubernetes.Clientset
 				logrus.Warnf("Expected a core/v1.ConfigMap object but got a %q object instead", event.Object.GetObjectKind().GroupVersionKind())
 				return false, nil
 			}
+			if p := cm.Data["progress"]; p != "" && p != lastProgress {
+				logBootstrapProgress(p)
+				lastProgress = p
+			}
 			status, ok := cm.Data["status"]
 			if !ok {
 				logrus.Debugf("No status found in bootstrap configmap")
@@ -475,13 +977,13 @@ func waitForInitializedCluster(ctx context.Context, config *rest.Config) error {
 	timeout := 40 * time.Minute
 
 	// Wait longer for baremetal, due to length of time it takes to boot
-	if assetStore, err := assetstore.NewStore(rootOpts.dir); err == nil {
-		if installConfig, err := assetStore.Load(&installconfig.InstallConfig{}); err == nil && installConfig != nil {
-			if installConfig.(*installconfig.InstallConfig).Config.Platform.Name() == baremetal.Name {
-				timeout = 60 * time.Minute
-			}
-		}
+	ic := loadInstallConfig(rootOpts.dir)
+	if ic != nil && ic.Platform.Name() == baremetal.Name {
+		timeout = 60 * time.Minute
+	}
+	timeout = waitTimeout(clusterOperatorsStablePhase, ic, timeout)
 
+	if assetStore, err := assetstore.NewStore(rootOpts.dir); err == nil {
 		checkIfAgentCommand(assetStore)
 	}
 
@@ -523,6 +1025,11 @@ func waitForInitializedCluster(ctx context.Context, config *rest.Config) error {
 					lastError = cov1helpers.FindStatusCondition(cv.Status.Conditions, configv1.OperatorProgressing).Message
 				}
 				logrus.Debugf("Still waiting for the cluster to initialize: %s", lastError)
+				if m := clusterVersionProgressRegexp.FindStringSubmatch(lastError); m != nil {
+					if percent, err := strconv.Atoi(m[1]); err == nil {
+						progress.Progress("Cluster Operators", percent, lastError)
+					}
+				}
 				return false, nil
 			}
 			logrus.Debug("Still waiting for the cluster to initialize...")
@@ -557,7 +1064,7 @@ func getConsole(ctx context.Context, config *rest.Config) (string, error) {
 		return "", errors.Wrap(err, "creating a route client")
 	}
 
-	consoleRouteTimeout := 2 * time.Minute
+	consoleRouteTimeout := waitTimeout(consoleRoutePhase, loadInstallConfig(rootOpts.dir), 2*time.Minute)
 	logrus.Infof("Checking to see if there is a route at %s/%s...", consoleNamespace, consoleRouteName)
 	consoleRouteContext, cancel := context.WithTimeout(ctx, consoleRouteTimeout)
 	defer cancel()
@@ -611,14 +1118,25 @@ func logComplete(directory, consoleURL string) error {
 	kubeconfig := filepath.Join(absDir, "auth", "kubeconfig")
 	pwFile := filepath.Join(absDir, "auth", "kubeadmin-password")
 	pw, err := os.ReadFile(pwFile)
-	if err != nil {
+	// The password file is not written when the kubeadmin user is disabled.
+	if err != nil && !os.IsNotExist(err) {
 		return err
 	}
 	logrus.Info("Install complete!")
-	logrus.Infof("To access the cluster as the system:admin user when using 'oc', run 'export KUBECONFIG=%s'", kubeconfig)
+	// The credentials encrypted with the state are not printed in plaintext.
+	if data, err := os.ReadFile(kubeconfig); err == nil && assetstore.Encrypted(data) {
+		logrus.Infof("To access the cluster as the system:admin user when using 'oc', decrypt the kubeconfig with 'openshift-install --dir %s state decrypt auth/kubeconfig'", absDir)
+	} else {
+		logrus.Infof("To access the cluster as the system:admin user when using 'oc', run 'export KUBECONFIG=%s'", kubeconfig)
+	}
 	if consoleURL != "" {
 		logrus.Infof("Access the OpenShift web-console here: %s", consoleURL)
-		logrus.Infof("Login to the console with user: %q, and password: %q", "kubeadmin", pw)
+		switch {
+		case assetstore.Encrypted(pw):
+			logrus.Infof("Login to the console with user: %q, and the password of 'openshift-install --dir %s state decrypt auth/kubeadmin-password'", "kubeadmin", absDir)
+		case pw != nil:
+			logrus.Infof("Login to the console with user: %q, and password: %q", "kubeadmin", pw)
+		}
 	}
 	return nil
 }
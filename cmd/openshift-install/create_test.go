@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/checkpoint"
+)
+
+// setBootstrapOpts sets the options of the bootstrap teardown for the test.
+func setBootstrapOpts(t *testing.T, preserve bool, delay time.Duration) {
+	t.Setenv("OPENSHIFT_INSTALL_PRESERVE_BOOTSTRAP", "")
+	previousPreserve, previousDelay := createOpts.preserveBootstrap, createOpts.bootstrapTeardownDelay
+	createOpts.preserveBootstrap, createOpts.bootstrapTeardownDelay = preserve, delay
+	t.Cleanup(func() {
+		createOpts.preserveBootstrap, createOpts.bootstrapTeardownDelay = previousPreserve, previousDelay
+	})
+}
+
+func TestDestroyBootstrap(t *testing.T) {
+	cases := []struct {
+		name      string
+		preserve  bool
+		delay     time.Duration
+		destroyed bool
+		destroy   error
+		err       string
+	}{
+		{
+			name:      "immediate",
+			destroyed: true,
+		},
+		{
+			name:      "immediate failure",
+			destroyed: true,
+			destroy:   errors.New("failed to destroy"),
+			err:       "failed to destroy",
+		},
+		{
+			name:     "preserved",
+			preserve: true,
+		},
+		{
+			name:     "preserved with delay",
+			preserve: true,
+			delay:    time.Minute,
+			err:      "the bootstrap resources cannot be both preserved and destroyed after a delay",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			setBootstrapOpts(t, tc.preserve, tc.delay)
+			dir := t.TempDir()
+			checkpoints, err := checkpoint.Load(dir)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			destroyed := false
+			teardown, err := destroyBootstrap(context.Background(), checkpoints, func(context.Context) error {
+				destroyed = true
+				return tc.destroy
+			})
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Nil(t, teardown)
+			assert.Equal(t, tc.destroyed, destroyed)
+			assert.Equal(t, tc.destroyed && tc.destroy == nil, checkpoints.Completed(bootstrapDestroyCheckpoint))
+		})
+	}
+}
+
+func TestDestroyBootstrapAlreadyDestroyed(t *testing.T) {
+	setBootstrapOpts(t, false, 0)
+	checkpoints, err := checkpoint.Load(t.TempDir())
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, checkpoints.Complete(bootstrapDestroyCheckpoint))
+
+	teardown, err := destroyBootstrap(context.Background(), checkpoints, func(context.Context) error {
+		t.Fatal("the bootstrap resources were destroyed again")
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, teardown.wait())
+}
+
+func TestDestroyBootstrapDelay(t *testing.T) {
+	setBootstrapOpts(t, false, 100*time.Millisecond)
+	checkpoints, err := checkpoint.Load(t.TempDir())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var destroyed int32
+	start := time.Now()
+	teardown, err := destroyBootstrap(context.Background(), checkpoints, func(context.Context) error {
+		atomic.AddInt32(&destroyed, 1)
+		return nil
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, int32(0), atomic.LoadInt32(&destroyed), "the bootstrap resources were destroyed before the delay")
+
+	assert.NoError(t, teardown.wait())
+	assert.True(t, time.Since(start) >= 100*time.Millisecond, "the bootstrap resources were destroyed before the delay")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&destroyed))
+	assert.True(t, checkpoints.Completed(bootstrapDestroyCheckpoint))
+	assert.NoError(t, teardown.wait())
+}
+
+func TestDestroyBootstrapDelayFailure(t *testing.T) {
+	setBootstrapOpts(t, false, time.Millisecond)
+	checkpoints, err := checkpoint.Load(t.TempDir())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	teardown, err := destroyBootstrap(context.Background(), checkpoints, func(context.Context) error {
+		return errors.New("failed to destroy")
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.EqualError(t, teardown.wait(), "failed to destroy")
+	assert.False(t, checkpoints.Completed(bootstrapDestroyCheckpoint))
+	// the error is reported once, e.g. not again by the exit handler
+	assert.NoError(t, teardown.wait())
+}
+
+func TestDestroyBootstrapDelayCanceled(t *testing.T) {
+	setBootstrapOpts(t, false, time.Hour)
+	checkpoints, err := checkpoint.Load(t.TempDir())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	teardown, err := destroyBootstrap(ctx, checkpoints, func(context.Context) error {
+		t.Fatal("the bootstrap resources were destroyed after the cancellation")
+		return nil
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	cancel()
+	err = teardown.wait()
+	assert.EqualError(t, err, "the bootstrap resources were not destroyed: context canceled")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, checkpoints.Completed(bootstrapDestroyCheckpoint))
+}
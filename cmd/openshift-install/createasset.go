@@ -0,0 +1,80 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+var createAssetOpts struct {
+	name string
+}
+
+func newCreateAssetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "asset",
+		Short: "Generates a single asset and the assets it depends on",
+		Long: `Generates the named asset, e.g. bootstrap.Bootstrap for the bootstrap
+Ignition config or manifests.CloudProviderConfig for the cloud-provider
+config, and only the assets it depends on, and writes its files to the assets
+directory, for the user-provisioned infrastructure workflows which consume
+individual files. The asset is named by its type, as the graph command lists
+them, or by its name, e.g. "Bootstrap Ignition Config".`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, args []string) {
+			a, err := writableAsset(createAssetOpts.name)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			runTargetCmd(a)(cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&createAssetOpts.name, "name", "", "type of the asset to generate, e.g. bootstrap.Bootstrap, or its name")
+	cmd.Flags().StringVar(&createOpts.outputDir, "output-dir", "", "directory to write the generated files to instead of the assets directory, or - to write them to stdout as a tar archive; the assets directory and its state file are left as they were")
+	return cmd
+}
+
+// writableAsset returns the asset of the targets, or of their dependencies,
+// with the type or the name, which must have files.
+func writableAsset(name string) (asset.WritableAsset, error) {
+	if name == "" {
+		return nil, errors.New("the name of the asset is required")
+	}
+	assets := allAssets()
+	a, ok := assets[name]
+	if !ok {
+		var matches []string
+		for typ, candidate := range assets {
+			if strings.EqualFold(candidate.Name(), name) {
+				matches = append(matches, typ)
+			}
+		}
+		sort.Strings(matches)
+		if len(matches) > 1 {
+			return nil, errors.Errorf("several assets are named %q, use the type of one of them: %s", name, strings.Join(matches, ", "))
+		}
+		if len(matches) == 1 {
+			a, ok = assets[matches[0]], true
+		}
+	}
+	if ok {
+		if wa, ok := a.(asset.WritableAsset); ok {
+			return wa, nil
+		}
+		return nil, errors.Errorf("the asset %q has no files", name)
+	}
+
+	known := make([]string, 0, len(assets))
+	for typ, a := range assets {
+		if _, ok := a.(asset.WritableAsset); ok {
+			known = append(known, typ)
+		}
+	}
+	sort.Strings(known)
+	return nil, errors.Errorf("unknown asset %q, must be one of %s", name, strings.Join(known, ", "))
+}
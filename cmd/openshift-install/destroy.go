@@ -1,31 +1,22 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"os"
-	"path/filepath"
+	"strings"
 
+	"github.com/AlecAivazis/survey/v2"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
-	assetstore "github.com/openshift/installer/pkg/asset/store"
+	"github.com/openshift/installer/pkg/client"
 	"github.com/openshift/installer/pkg/destroy"
-	"github.com/openshift/installer/pkg/destroy/bootstrap"
-	quotaasset "github.com/openshift/installer/pkg/destroy/quota"
+	"github.com/openshift/installer/pkg/destroy/providers"
 	"github.com/openshift/installer/pkg/metrics/timer"
-
-	_ "github.com/openshift/installer/pkg/destroy/alibabacloud"
-	_ "github.com/openshift/installer/pkg/destroy/aws"
-	_ "github.com/openshift/installer/pkg/destroy/azure"
-	_ "github.com/openshift/installer/pkg/destroy/baremetal"
-	_ "github.com/openshift/installer/pkg/destroy/gcp"
-	_ "github.com/openshift/installer/pkg/destroy/ibmcloud"
-	_ "github.com/openshift/installer/pkg/destroy/libvirt"
-	_ "github.com/openshift/installer/pkg/destroy/nutanix"
-	_ "github.com/openshift/installer/pkg/destroy/openstack"
-	_ "github.com/openshift/installer/pkg/destroy/ovirt"
-	_ "github.com/openshift/installer/pkg/destroy/powervs"
-	_ "github.com/openshift/installer/pkg/destroy/vsphere"
+	awstypes "github.com/openshift/installer/pkg/types/aws"
 )
 
 func newDestroyCmd() *cobra.Command {
@@ -42,78 +33,174 @@ func newDestroyCmd() *cobra.Command {
 	return cmd
 }
 
+var (
+	destroyClusterOpts struct {
+		options providers.Options
+		report  string
+		orphan  destroy.Orphan
+		yes     bool
+	}
+)
+
 func newDestroyClusterCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "cluster",
 		Short: "Destroy an OpenShift cluster",
-		Args:  cobra.ExactArgs(0),
+		Long: fmt.Sprintf(`Destroy the OpenShift cluster of the assets directory.
+
+With --infra-id, destroy the resources tagged with the infra ID of a cluster
+instead, e.g. when its assets directory was lost. This is only supported on
+the %s platforms; the clusters of the other platforms can only be destroyed
+from their assets directory.`, strings.Join(destroy.OrphanPlatforms, ", ")),
+		Args: cobra.ExactArgs(0),
 		Run: func(_ *cobra.Command, _ []string) {
 			cleanup := setupFileHook(rootOpts.dir)
 			defer cleanup()
 
-			err := runDestroyCmd(rootOpts.dir, os.Getenv("OPENSHIFT_INSTALL_REPORT_QUOTA_FOOTPRINT") == "true")
+			var err error
+			if destroyClusterOpts.orphan.InfraID != "" {
+				err = runDestroyOrphanCmd(destroyClusterOpts.orphan)
+			} else {
+				err = runDestroyCmd(rootOpts.dir, os.Getenv("OPENSHIFT_INSTALL_REPORT_QUOTA_FOOTPRINT") == "true")
+			}
 			if err != nil {
 				logrus.Fatal(err)
 			}
+			if destroyClusterOpts.options.DryRun {
+				logrus.Infof("Dry run complete, no resources were deleted")
+				return
+			}
 			logrus.Infof("Uninstallation complete!")
 		},
 	}
+	cmd.Flags().BoolVar(&destroyClusterOpts.options.DryRun, "dry-run", false, "list the resources of the cluster without deleting them or the assets")
+	cmd.Flags().StringSliceVar(&destroyClusterOpts.options.Keep, "keep", nil, "resource types to leave in place, e.g. route53 or dns:records; a type also keeps its subtypes (e.g. ec2 keeps ec2:instance)")
+	cmd.Flags().StringVar(&destroyClusterOpts.report, "report", "", "file to write a JSON report of the resources found, kept, deleted or failed to delete to")
+	cmd.Flags().StringVar(&destroyClusterOpts.orphan.InfraID, "infra-id", "", "instead of the cluster of the assets directory, destroy the resources tagged with this infra ID, e.g. when the assets directory was lost")
+	cmd.Flags().StringVar(&destroyClusterOpts.orphan.Platform, "platform", awstypes.Name, fmt.Sprintf("platform of the resources of --infra-id, one of %s", strings.Join(destroy.OrphanPlatforms, ", ")))
+	cmd.Flags().StringVar(&destroyClusterOpts.orphan.Region, "region", "", "region of the resources of --infra-id")
+	cmd.Flags().StringVar(&destroyClusterOpts.orphan.ProjectID, "project", "", "GCP project of the resources of --infra-id")
+	cmd.Flags().BoolVar(&destroyClusterOpts.yes, "yes", false, "destroy the resources of --infra-id without asking for confirmation")
+	return cmd
 }
 
 func runDestroyCmd(directory string, reportQuota bool) error {
 	timer.StartTimer(timer.TotalTimeElapsed)
-	destroyer, err := destroy.New(logrus.StandardLogger(), directory)
+	installer, err := client.New(directory)
 	if err != nil {
-		return errors.Wrap(err, "Failed while preparing to destroy cluster")
+		return err
+	}
+	report, err := installer.DestroyCluster(context.Background(), client.DestroyOptions{
+		Options:     destroyClusterOpts.options,
+		ReportQuota: reportQuota,
+	})
+	if report != nil {
+		if reportErr := writeDestroyReport(destroyClusterOpts.report, report); reportErr != nil {
+			logrus.Error(reportErr)
+		}
 	}
-	quota, err := destroyer.Run()
 	if err != nil {
-		return errors.Wrap(err, "Failed to destroy cluster")
+		return err
 	}
-
-	if reportQuota {
-		if err := quotaasset.WriteQuota(directory, quota); err != nil {
-			return errors.Wrap(err, "failed to record quota")
-		}
+	if destroyClusterOpts.options.DryRun {
+		logDryRun(report)
+		return nil
 	}
 
-	store, err := assetstore.NewStore(directory)
+	timer.StopTimer(timer.TotalTimeElapsed)
+	timer.LogSummary()
+
+	return nil
+}
+
+// runDestroyOrphanCmd destroys the resources found by the infra ID of the
+// orphan after the user confirms the list of resources matched by a dry run.
+// It leaves the assets directory alone.
+func runDestroyOrphanCmd(orphan destroy.Orphan) error {
+	timer.StartTimer(timer.TotalTimeElapsed)
+	options := destroyClusterOpts.options
+	dryRun := options
+	dryRun.DryRun = true
+	destroyer, report, err := destroy.NewForOrphan(logrus.StandardLogger(), orphan, dryRun)
 	if err != nil {
-		return errors.Wrap(err, "failed to create asset store")
+		return errors.Wrap(err, "Failed while preparing to destroy cluster")
 	}
-	for _, asset := range clusterTarget.assets {
-		if err := store.Destroy(asset); err != nil {
-			return errors.Wrapf(err, "failed to destroy asset %q", asset.Name())
-		}
+	if _, err := destroyer.Run(); err != nil {
+		return errors.Wrap(err, "Failed to find the resources of the cluster")
+	}
+	if options.DryRun {
+		logDryRun(report)
+		return writeDestroyReport(destroyClusterOpts.report, report)
 	}
 
-	// delete the state file as well
-	err = store.DestroyState()
-	if err != nil {
-		return errors.Wrap(err, "failed to remove state file")
+	found := 0
+	for _, resource := range report.Resources {
+		if resource.Status == providers.ResourceFound {
+			fmt.Fprintf(os.Stderr, "  %s %s\n", resource.Type, resource.ID)
+			found++
+		}
+	}
+	if found == 0 {
+		logrus.Infof("No resources found for infra ID %s", orphan.InfraID)
+		return writeDestroyReport(destroyClusterOpts.report, report)
+	}
+	if !destroyClusterOpts.yes {
+		confirmed := false
+		if err := survey.AskOne(&survey.Confirm{
+			Message: fmt.Sprintf("Delete the %d resources above matching infra ID %s?", found, orphan.InfraID),
+		}, &confirmed); err != nil {
+			return errors.Wrap(err, "failed to confirm the deletion")
+		}
+		if !confirmed {
+			return errors.New("deletion not confirmed")
+		}
 	}
 
-	// delete terraform files
-	tfstateFiles, err := filepath.Glob(filepath.Join(directory, "*.tfstate"))
+	destroyer, report, err = destroy.NewForOrphan(logrus.StandardLogger(), orphan, options)
 	if err != nil {
-		return errors.Wrap(err, "failed to glob for tfstate files")
+		return errors.Wrap(err, "Failed while preparing to destroy cluster")
 	}
-	tfvarsFiles, err := filepath.Glob(filepath.Join(directory, "*.tfvars.json"))
+	_, err = destroyer.Run()
 	if err != nil {
-		return errors.Wrap(err, "failed to glob for tfvars files")
+		report.Error = err.Error()
 	}
-	for _, f := range append(tfstateFiles, tfvarsFiles...) {
-		if err := os.Remove(f); err != nil {
-			return errors.Wrapf(err, "failed to remove terraform file %q", f)
-		}
+	if reportErr := writeDestroyReport(destroyClusterOpts.report, report); reportErr != nil {
+		logrus.Error(reportErr)
+	}
+	if err != nil {
+		return errors.Wrap(err, "Failed to destroy cluster")
 	}
 
 	timer.StopTimer(timer.TotalTimeElapsed)
 	timer.LogSummary()
-
 	return nil
 }
 
+// logDryRun logs what a destroy would do with the resources of the report of
+// a dry run.
+func logDryRun(report *providers.Report) {
+	for _, resource := range report.Resources {
+		action := "delete"
+		if resource.Status == providers.ResourceKept {
+			action = "keep"
+		}
+		logrus.Infof("Would %s %s %s", action, resource.Type, resource.ID)
+	}
+}
+
+// writeDestroyReport writes the report of the resources of the cluster to the
+// file as JSON, unless the file is empty.
+func writeDestroyReport(file string, report *providers.Report) error {
+	if file == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal destroy report")
+	}
+	return errors.Wrap(os.WriteFile(file, append(data, '\n'), 0o640), "failed to write destroy report")
+}
+
 func newDestroyBootstrapCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "bootstrap",
@@ -124,7 +211,10 @@ func newDestroyBootstrapCmd() *cobra.Command {
 			defer cleanup()
 
 			timer.StartTimer(timer.TotalTimeElapsed)
-			err := bootstrap.Destroy(rootOpts.dir)
+			installer, err := client.New(rootOpts.dir)
+			if err == nil {
+				err = installer.DestroyBootstrap(context.Background())
+			}
 			if err != nil {
 				logrus.Fatal(err)
 			}
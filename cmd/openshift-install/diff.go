@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+	targetassets "github.com/openshift/installer/pkg/asset/targets"
+	"github.com/openshift/installer/pkg/manifestdiff"
+)
+
+func newDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare generated assets with the asset directory",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "manifests",
+		Short: "Show how the manifests in the asset directory differ from the generated manifests",
+		Long: `Regenerates the manifests from the install-config and the state of the
+asset directory, without changing either, and shows how the manifests
+in the asset directory differ from them field by field.`,
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cleanup := setupFileHook(rootOpts.dir)
+			defer cleanup()
+			return runDiffManifestsCmd(rootOpts.dir, os.Stdout)
+		},
+	})
+	return cmd
+}
+
+func runDiffManifestsCmd(directory string, out io.Writer) error {
+	generated, err := generateManifests(directory)
+	if err != nil {
+		return err
+	}
+
+	// Compare every file in the directories the manifests are written to,
+	// so that manifests added by hand are reported too.
+	onDisk := map[string][]byte{}
+	dirs := map[string]bool{}
+	for name := range generated {
+		dirs[strings.SplitN(name, string(filepath.Separator), 2)[0]] = true
+	}
+	for dir := range dirs {
+		err := filepath.WalkDir(filepath.Join(directory, dir), func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			name, err := filepath.Rel(directory, path)
+			if err != nil {
+				return err
+			}
+			onDisk[name] = data
+			return nil
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "failed to read %s", dir)
+		}
+	}
+
+	diffs := manifestdiff.Compare(generated, onDisk)
+	if len(diffs) == 0 {
+		logrus.Info("The manifests in the asset directory match the generated manifests")
+		return nil
+	}
+	for _, d := range diffs {
+		fmt.Fprintf(out, "%s: %s\n", d.Filename, d.Status)
+		for _, c := range d.Changes {
+			switch {
+			case c.OnDisk == nil:
+				fmt.Fprintf(out, "  - %s: %s\n", c.Path, *c.Generated)
+			case c.Generated == nil:
+				fmt.Fprintf(out, "  + %s: %s\n", c.Path, *c.OnDisk)
+			default:
+				fmt.Fprintf(out, "  ~ %s: %s -> %s\n", c.Path, *c.Generated, *c.OnDisk)
+			}
+		}
+	}
+	return nil
+}
+
+// generateManifests generates the manifests from the state of the asset
+// directory and its install-config.yaml, if there is one, and returns their
+// contents by filename. The asset directory and its state are not changed.
+func generateManifests(directory string) (map[string][]byte, error) {
+	backend, err := assetstore.NewStateBackend(directory)
+	if err != nil {
+		return nil, err
+	}
+	state, err := backend.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read state")
+	}
+	if state == nil {
+		return nil, errors.Errorf("no state found for %s, the manifests must be created first", directory)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "openshift-install-diff-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Only the install-config is taken from the asset directory, so that
+	// edited manifests are regenerated instead of loaded.
+	installConfig, err := os.ReadFile(filepath.Join(directory, "install-config.yaml"))
+	switch {
+	case err == nil:
+		if err := os.WriteFile(filepath.Join(tmpDir, "install-config.yaml"), installConfig, 0o600); err != nil {
+			return nil, err
+		}
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	store, err := assetstore.NewStoreWithBackend(tmpDir, assetstore.NewMemoryBackend(state))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create asset store")
+	}
+	generated := map[string][]byte{}
+	for _, a := range targetassets.Manifests {
+		if err := store.Fetch(context.Background(), a, targetassets.Manifests...); err != nil {
+			return nil, errors.Wrapf(err, "failed to generate %s", a.Name())
+		}
+		for _, f := range a.Files() {
+			generated[f.Filename] = f.Data
+		}
+	}
+	return generated, nil
+}
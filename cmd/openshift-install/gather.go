@@ -19,10 +19,12 @@ import (
 
 	configv1 "github.com/openshift/api/config/v1"
 	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/installconfig"
 	assetstore "github.com/openshift/installer/pkg/asset/store"
 	"github.com/openshift/installer/pkg/asset/tls"
 	serialgather "github.com/openshift/installer/pkg/gather"
+	"github.com/openshift/installer/pkg/gather/findings"
 	"github.com/openshift/installer/pkg/gather/service"
 	"github.com/openshift/installer/pkg/gather/ssh"
 	platformstages "github.com/openshift/installer/pkg/terraform/stages/platform"
@@ -30,6 +32,7 @@ import (
 	_ "github.com/openshift/installer/pkg/gather/aws"
 	_ "github.com/openshift/installer/pkg/gather/azure"
 	_ "github.com/openshift/installer/pkg/gather/gcp"
+	_ "github.com/openshift/installer/pkg/gather/openstack"
 )
 
 func newGatherCmd() *cobra.Command {
@@ -54,6 +57,7 @@ var gatherBootstrapOpts struct {
 	masters      []string
 	sshKeys      []string
 	skipAnalysis bool
+	proxy        ssh.Proxy
 }
 
 func newGatherBootstrapCmd() *cobra.Command {
@@ -73,6 +77,7 @@ func newGatherBootstrapCmd() *cobra.Command {
 				if err := service.AnalyzeGatherBundle(bundlePath); err != nil {
 					logrus.Fatal(err)
 				}
+				analyzeFindings(bundlePath)
 			}
 
 			logrus.Infof("Bootstrap gather logs captured here %q", bundlePath)
@@ -82,6 +87,8 @@ func newGatherBootstrapCmd() *cobra.Command {
 	cmd.PersistentFlags().StringArrayVar(&gatherBootstrapOpts.masters, "master", []string{}, "Hostnames or IPs of all control plane hosts")
 	cmd.PersistentFlags().StringArrayVar(&gatherBootstrapOpts.sshKeys, "key", []string{}, "Path to SSH private keys that should be used for authentication. If no key was provided, SSH private keys from user's environment will be used")
 	cmd.PersistentFlags().BoolVar(&gatherBootstrapOpts.skipAnalysis, "skipAnalysis", false, "Skip analysis of the gathered data")
+	cmd.PersistentFlags().StringVar(&gatherBootstrapOpts.proxy.Bastion, "bastion", "", "[user@]host[:port] of an SSH jump host to connect to the bootstrap host through; overrides gatherProxy in the install-config")
+	cmd.PersistentFlags().StringVar(&gatherBootstrapOpts.proxy.SOCKS, "socks-proxy", "", "host:port of a SOCKS5 proxy to connect to the bootstrap host through; overrides gatherProxy in the install-config")
 	return cmd
 }
 
@@ -90,30 +97,40 @@ func runGatherBootstrapCmd(directory string) (string, error) {
 	if err != nil {
 		return "", errors.Wrap(err, "failed to create asset store")
 	}
-	// add the default bootstrap key pair to the sshKeys list
-	bootstrapSSHKeyPair := &tls.BootstrapSSHKeyPair{}
-	if err := assetStore.Fetch(bootstrapSSHKeyPair); err != nil {
-		return "", errors.Wrapf(err, "failed to fetch %s", bootstrapSSHKeyPair.Name())
-	}
-	tmpfile, err := os.CreateTemp("", "bootstrap-ssh")
+	// add the default bootstrap key pair to the sshKeys list, unless the
+	// state has none, e.g. it was exported with its secrets redacted
+	bootstrapSSHKeyPair, err := assetStore.Load(&tls.BootstrapSSHKeyPair{})
 	if err != nil {
-		return "", err
+		return "", errors.Wrap(err, "failed to load the bootstrap SSH key pair")
 	}
-	defer os.Remove(tmpfile.Name())
-	if _, err := tmpfile.Write(bootstrapSSHKeyPair.Private()); err != nil {
-		return "", err
+	if bootstrapSSHKeyPair == nil {
+		logrus.Warn("The state has no bootstrap SSH key pair, only the SSH keys of the user are used")
+	} else {
+		tmpfile, err := os.CreateTemp("", "bootstrap-ssh")
+		if err != nil {
+			return "", err
+		}
+		defer os.Remove(tmpfile.Name())
+		if _, err := tmpfile.Write(bootstrapSSHKeyPair.(*tls.BootstrapSSHKeyPair).Private()); err != nil {
+			return "", err
+		}
+		if err := tmpfile.Close(); err != nil {
+			return "", err
+		}
+		gatherBootstrapOpts.sshKeys = append(gatherBootstrapOpts.sshKeys, tmpfile.Name())
 	}
-	if err := tmpfile.Close(); err != nil {
+
+	proxy, err := gatherProxy(assetStore)
+	if err != nil {
 		return "", err
 	}
-	gatherBootstrapOpts.sshKeys = append(gatherBootstrapOpts.sshKeys, tmpfile.Name())
 
 	bootstrap := gatherBootstrapOpts.bootstrap
 	port := 22
 	masters := gatherBootstrapOpts.masters
 	if bootstrap == "" && len(masters) == 0 {
 		config := &installconfig.InstallConfig{}
-		if err := assetStore.Fetch(config); err != nil {
+		if err := assetStore.Fetch(context.Background(), config); err != nil {
 			return "", errors.Wrapf(err, "failed to fetch %s", config.Name())
 		}
 
@@ -139,10 +156,34 @@ func runGatherBootstrapCmd(directory string) (string, error) {
 		return "", errors.New("must provide bootstrap host address")
 	}
 
-	return gatherBootstrap(bootstrap, port, masters, directory)
+	return gatherBootstrap(bootstrap, port, masters, proxy, directory)
+}
+
+// gatherProxy returns the proxy to connect to the bootstrap host through
+// from the flags, or else from the install-config of the asset directory.
+func gatherProxy(assetStore asset.Store) (ssh.Proxy, error) {
+	proxy := gatherBootstrapOpts.proxy
+	if proxy.Bastion != "" && proxy.SOCKS != "" {
+		return proxy, errors.New("only one of --bastion and --socks-proxy may be set")
+	}
+	if proxy != (ssh.Proxy{}) {
+		return proxy, nil
+	}
+	config, err := assetStore.Load(&installconfig.InstallConfig{})
+	if err != nil {
+		logrus.Debugf("Failed to load the install-config for its gather proxy: %v", err)
+		return proxy, nil
+	}
+	if config == nil || config.(*installconfig.InstallConfig).Config == nil {
+		return proxy, nil
+	}
+	if p := config.(*installconfig.InstallConfig).Config.GatherProxy; p != nil {
+		proxy = ssh.Proxy{Bastion: p.Bastion, SOCKS: p.SOCKS}
+	}
+	return proxy, nil
 }
 
-func gatherBootstrap(bootstrap string, port int, masters []string, directory string) (string, error) {
+func gatherBootstrap(bootstrap string, port int, masters []string, proxy ssh.Proxy, directory string) (string, error) {
 	gatherID := time.Now().Format("20060102150405")
 
 	serialLogBundle := filepath.Join(directory, fmt.Sprintf("serial-log-bundle-%s.tar.gz", gatherID))
@@ -161,8 +202,29 @@ func gatherBootstrap(bootstrap string, port int, masters []string, directory str
 		}
 	}
 
+	journalBundlePath, err := filepath.Abs(filepath.Join(directory, fmt.Sprintf("journal-log-bundle-%s.tar.gz", gatherID)))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to stat log file")
+	}
+	if config, err := loadKubeconfig(directory); err != nil {
+		logrus.Infof("Skipping node journal gather: %s", err.Error())
+	} else {
+		logrus.Info("Pulling node journals through the API")
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		if err := serialgather.GatherNodeJournals(ctx, logrus.StandardLogger(), config, journalBundlePath, serialgather.JournalUnits); err != nil {
+			logrus.Infof("Failed to gather node journals: %s", err.Error())
+		}
+		cancel()
+	}
+
 	logrus.Info("Pulling debug logs from the bootstrap machine")
-	client, err := ssh.NewClient("core", net.JoinHostPort(bootstrap, strconv.Itoa(port)), gatherBootstrapOpts.sshKeys)
+	switch {
+	case proxy.Bastion != "":
+		logrus.Infof("Connecting to the bootstrap machine through the bastion host %s", proxy.Bastion)
+	case proxy.SOCKS != "":
+		logrus.Infof("Connecting to the bootstrap machine through the SOCKS proxy %s", proxy.SOCKS)
+	}
+	client, err := ssh.NewClientWithProxy("core", net.JoinHostPort(bootstrap, strconv.Itoa(port)), gatherBootstrapOpts.sshKeys, proxy)
 	if err != nil {
 		if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ETIMEDOUT) {
 			return "", errors.Wrap(err, "failed to connect to the bootstrap machine")
@@ -185,7 +247,7 @@ func gatherBootstrap(bootstrap string, port int, masters []string, directory str
 	}
 
 	logBundlePath := filepath.Join(filepath.Dir(clusterLogBundlePath), fmt.Sprintf("log-bundle-%s.tar.gz", gatherID))
-	archives := map[string]string{serialLogBundlePath: "serial", clusterLogBundlePath: ""}
+	archives := map[string]string{serialLogBundlePath: "serial", journalBundlePath: "journals", clusterLogBundlePath: ""}
 	err = serialgather.CombineArchives(logBundlePath, archives)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to combine archives")
@@ -194,6 +256,50 @@ func gatherBootstrap(bootstrap string, port int, masters []string, directory str
 	return logBundlePath, nil
 }
 
+// gatherNodeJournals gathers the node journals of the cluster through the API
+// into a log bundle in the directory, for failures after bootstrapping when
+// there is no bootstrap machine to gather from.
+func gatherNodeJournals(config *rest.Config, directory string) (string, error) {
+	gatherID := time.Now().Format("20060102150405")
+	journalBundlePath, err := filepath.Abs(filepath.Join(directory, fmt.Sprintf("journal-log-bundle-%s.tar.gz", gatherID)))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to stat log file")
+	}
+
+	logrus.Info("Pulling node journals through the API")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	if err := serialgather.GatherNodeJournals(ctx, logrus.StandardLogger(), config, journalBundlePath, serialgather.JournalUnits); err != nil {
+		logrus.Infof("Failed to gather some node journals: %s", err.Error())
+	}
+	if _, err := os.Stat(journalBundlePath); err != nil {
+		return "", errors.Wrap(err, "no node journals gathered")
+	}
+
+	logBundlePath := filepath.Join(filepath.Dir(journalBundlePath), fmt.Sprintf("log-bundle-%s.tar.gz", gatherID))
+	if err := serialgather.CombineArchives(logBundlePath, map[string]string{journalBundlePath: "journals"}); err != nil {
+		return "", errors.Wrap(err, "failed to combine archives")
+	}
+	return logBundlePath, nil
+}
+
+// analyzeFindings logs the likely root causes of the failure found in the
+// log bundle and writes them as JSON next to it.
+func analyzeFindings(bundlePath string) {
+	report, err := findings.AnalyzeBundle(bundlePath)
+	if err != nil {
+		logrus.Error("Attempted to find the likely root causes in the debug logs: ", err)
+		return
+	}
+	report.Log(logrus.StandardLogger())
+	findingsPath := findings.FindingsFile(bundlePath)
+	if err := report.WriteFile(findingsPath); err != nil {
+		logrus.Error(err)
+		return
+	}
+	logrus.Infof("Findings written to %q", findingsPath)
+}
+
 func logClusterOperatorConditions(ctx context.Context, config *rest.Config) error {
 	client, err := configclient.NewForConfig(config)
 	if err != nil {
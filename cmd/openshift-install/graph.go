@@ -1,25 +1,51 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"reflect"
 	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/awalterschulze/gographviz"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/installer/pkg/asset"
+	assetstore "github.com/openshift/installer/pkg/asset/store"
 )
 
 var (
 	graphOpts struct {
 		outputFile string
+		format     string
+		regenerate string
 	}
 )
 
+// graphJSON is the JSON form of the dependency graph.
+type graphJSON struct {
+	Targets []graphTarget `json:"targets"`
+	Assets  []graphAsset  `json:"assets"`
+}
+
+type graphTarget struct {
+	Name   string   `json:"name"`
+	Assets []string `json:"assets"`
+}
+
+type graphAsset struct {
+	// Type is the Go type of the asset, e.g. manifests.Manifests, which is
+	// also the name the asset is given to --regenerate.
+	Type         string   `json:"type"`
+	Name         string   `json:"name"`
+	Dependencies []string `json:"dependencies"`
+}
+
 func newGraphCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "graph",
@@ -29,10 +55,47 @@ func newGraphCmd() *cobra.Command {
 		RunE:  runGraphCmd,
 	}
 	cmd.PersistentFlags().StringVar(&graphOpts.outputFile, "output-file", "", "file where the graph is written, if empty prints the graph to Stdout.")
+	cmd.PersistentFlags().StringVar(&graphOpts.format, "format", "dot", "format of the graph, one of dot or json.")
+	cmd.PersistentFlags().StringVar(&graphOpts.regenerate, "regenerate", "", "instead of printing the graph, invalidate this asset (e.g. manifests.Manifests) and every asset depending on it in the state of the asset directory, so that they are regenerated by the next create.")
 	return cmd
 }
 
 func runGraphCmd(cmd *cobra.Command, args []string) error {
+	if graphOpts.regenerate != "" {
+		return runRegenerate(rootOpts.dir, graphOpts.regenerate)
+	}
+
+	var graph string
+	switch graphOpts.format {
+	case "dot":
+		graph = dotGraph()
+	case "json":
+		data, err := json.MarshalIndent(jsonGraph(), "", "  ")
+		if err != nil {
+			return err
+		}
+		graph = string(data) + "\n"
+	default:
+		return errors.Errorf("unsupported format %q, must be dot or json", graphOpts.format)
+	}
+
+	out := os.Stdout
+	if graphOpts.outputFile != "" {
+		f, err := os.Create(graphOpts.outputFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if _, err := io.WriteString(out, graph); err != nil {
+		return err
+	}
+	return nil
+}
+
+func dotGraph() string {
 	g := gographviz.NewGraph()
 	g.SetName("G")
 	g.SetDir(true)
@@ -61,21 +124,7 @@ func runGraphCmd(cmd *cobra.Command, args []string) error {
 		}
 		g.AddNode(subgraphName, node.Name, nil)
 	}
-
-	out := os.Stdout
-	if graphOpts.outputFile != "" {
-		f, err := os.Create(graphOpts.outputFile)
-		if err != nil {
-			return err
-		}
-		defer f.Close()
-		out = f
-	}
-
-	if _, err := io.WriteString(out, g.String()); err != nil {
-		return err
-	}
-	return nil
+	return g.String()
 }
 
 func addEdge(g *gographviz.Graph, parent string, asset asset.Asset) {
@@ -104,3 +153,103 @@ func isEdge(g *gographviz.Graph, src, dst string) bool {
 	}
 	return false
 }
+
+// assetType returns the name of the Go type of the asset, without the
+// pointer.
+func assetType(a asset.Asset) string {
+	return reflect.TypeOf(a).Elem().String()
+}
+
+// allAssets returns every asset reachable from the targets, by type.
+func allAssets() map[string]asset.Asset {
+	assets := map[string]asset.Asset{}
+	var walk func(a asset.Asset)
+	walk = func(a asset.Asset) {
+		if _, ok := assets[assetType(a)]; ok {
+			return
+		}
+		assets[assetType(a)] = a
+		for _, dep := range a.Dependencies() {
+			walk(dep)
+		}
+	}
+	for _, t := range targets {
+		for _, a := range t.assets {
+			walk(a)
+		}
+	}
+	return assets
+}
+
+func jsonGraph() *graphJSON {
+	graph := &graphJSON{}
+	for _, t := range targets {
+		target := graphTarget{Name: t.name}
+		for _, a := range t.assets {
+			target.Assets = append(target.Assets, assetType(a))
+		}
+		graph.Targets = append(graph.Targets, target)
+	}
+	for typ, a := range allAssets() {
+		node := graphAsset{Type: typ, Name: a.Name(), Dependencies: []string{}}
+		for _, dep := range a.Dependencies() {
+			node.Dependencies = append(node.Dependencies, assetType(dep))
+		}
+		sort.Strings(node.Dependencies)
+		graph.Assets = append(graph.Assets, node)
+	}
+	sort.Slice(graph.Assets, func(i, j int) bool { return graph.Assets[i].Type < graph.Assets[j].Type })
+	return graph
+}
+
+// runRegenerate invalidates the named asset and its dependents in the store
+// of the asset directory.
+func runRegenerate(directory string, name string) error {
+	assets := allAssets()
+	if _, ok := assets[name]; !ok {
+		known := make([]string, 0, len(assets))
+		for typ := range assets {
+			known = append(known, typ)
+		}
+		sort.Strings(known)
+		return errors.Errorf("unknown asset %q, must be one of %s", name, strings.Join(known, ", "))
+	}
+
+	// dependsOn memoizes whether an asset depends, directly or not, on the
+	// asset to regenerate.
+	dependsOn := map[string]bool{name: true}
+	var check func(a asset.Asset) bool
+	check = func(a asset.Asset) bool {
+		if v, ok := dependsOn[assetType(a)]; ok {
+			return v
+		}
+		dependsOn[assetType(a)] = false
+		for _, dep := range a.Dependencies() {
+			if check(dep) {
+				dependsOn[assetType(a)] = true
+				break
+			}
+		}
+		return dependsOn[assetType(a)]
+	}
+
+	store, err := assetstore.NewStore(directory)
+	if err != nil {
+		return errors.Wrap(err, "failed to create asset store")
+	}
+	dependents := 0
+	for typ, a := range assets {
+		if !check(a) {
+			continue
+		}
+		logrus.Debugf("Invalidating %s", typ)
+		if err := store.Invalidate(a); err != nil {
+			return errors.Wrapf(err, "failed to invalidate %q", a.Name())
+		}
+		if typ != name {
+			dependents++
+		}
+	}
+	logrus.Infof("Invalidated %s and %d assets depending on it, they were removed from the asset directory and will be regenerated by the next create", name, dependents)
+	return nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset/cluster"
+	"github.com/openshift/installer/pkg/infrastructure"
+)
+
+var infraPlanOpts struct {
+	output string
+}
+
+// printInfraPlan prints the infrastructure plan written by create
+// infra-plan, either as a summary or as the JSON of the plan.
+func printInfraPlan(w io.Writer, dir string, output string) error {
+	data, err := os.ReadFile(filepath.Join(dir, cluster.InfraPlanFileName))
+	if err != nil {
+		return errors.Wrap(err, "failed to read the infrastructure plan")
+	}
+
+	switch output {
+	case "json":
+		_, err := fmt.Fprintln(w, string(data))
+		return err
+	case "text":
+		plan := &infrastructure.Plan{}
+		if err := json.Unmarshal(data, plan); err != nil {
+			return errors.Wrap(err, "failed to parse the infrastructure plan")
+		}
+		_, err := io.WriteString(w, summarizeInfraPlan(plan))
+		return err
+	default:
+		return errors.Errorf("unsupported output format %q, must be text or json", output)
+	}
+}
+
+// summarizeInfraPlan returns the changes of each stage of the plan, marked
+// like terraform plan marks them, followed by their totals.
+func summarizeInfraPlan(plan *infrastructure.Plan) string {
+	var b strings.Builder
+	var create, update, remove int
+	for _, stage := range plan.Stages {
+		if len(stage.DeferredBy) > 0 {
+			fmt.Fprintf(&b, "Stage %s: deferred until %s is created\n", stage.Name, strings.Join(stage.DeferredBy, ", "))
+			continue
+		}
+		fmt.Fprintf(&b, "Stage %s (%s):\n", stage.Name, plan.Backend)
+		if len(stage.Changes) == 0 {
+			fmt.Fprintf(&b, "  no changes\n")
+		}
+		for _, change := range stage.Changes {
+			fmt.Fprintf(&b, "  %s %s\n", actionSymbol(change.Actions), change.Address)
+			for _, action := range change.Actions {
+				switch action {
+				case "create":
+					create++
+				case "update":
+					update++
+				case "delete":
+					remove++
+				}
+			}
+		}
+	}
+	fmt.Fprintf(&b, "Plan: %d to create, %d to change, %d to destroy.\n", create, update, remove)
+	return b.String()
+}
+
+// actionSymbol returns the symbol terraform plan marks the actions with.
+func actionSymbol(actions []string) string {
+	switch strings.Join(actions, ",") {
+	case "create":
+		return "+"
+	case "update":
+		return "~"
+	case "delete":
+		return "-"
+	case "delete,create":
+		return "-/+"
+	case "create,delete":
+		return "+/-"
+	case "read":
+		return "<="
+	default:
+		return "?"
+	}
+}
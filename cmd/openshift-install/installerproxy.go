@@ -0,0 +1,99 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+	"github.com/openshift/installer/pkg/installerproxy"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// installerProxyCluster is the value of --installer-proxy which selects the
+// proxy of the install-config.
+const installerProxyCluster = "cluster"
+
+// configureInstallerProxy routes the requests of the installer through the
+// proxy of --installer-proxy, and records the endpoints they reach with
+// --report-endpoints.
+func configureInstallerProxy(dir string) error {
+	var config *installerproxy.Config
+	switch rootOpts.installerProxy {
+	case "":
+		if rootOpts.installerNoProxy != "" {
+			return errors.New("--installer-no-proxy requires --installer-proxy")
+		}
+	case installerProxyCluster:
+		proxy, err := installConfigProxy(dir)
+		if err != nil {
+			return err
+		}
+		if proxy == nil {
+			return errors.New("--installer-proxy=cluster requires the proxy of the install-config, which has none")
+		}
+		config = &installerproxy.Config{HTTPProxy: proxy.HTTPProxy, HTTPSProxy: proxy.HTTPSProxy, NoProxy: proxy.NoProxy}
+		if rootOpts.installerNoProxy != "" {
+			config.NoProxy = rootOpts.installerNoProxy
+		}
+	default:
+		config = &installerproxy.Config{HTTPProxy: rootOpts.installerProxy, HTTPSProxy: rootOpts.installerProxy, NoProxy: rootOpts.installerNoProxy}
+	}
+	if err := installerproxy.Configure(config, rootOpts.reportEndpoints); err != nil {
+		return errors.Wrap(err, "invalid --installer-proxy")
+	}
+	return nil
+}
+
+// installConfigProxy returns the proxy of the install-config of the assets
+// directory, or of its state once the install-config is consumed. The file is
+// not validated, which could reach the platform before the proxy is set.
+func installConfigProxy(dir string) (*types.Proxy, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "install-config.yaml"))
+	if err == nil {
+		var config struct {
+			Proxy *types.Proxy `json:"proxy,omitempty"`
+		}
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return nil, errors.Wrap(err, "failed to read the proxy of the install-config")
+		}
+		return config.Proxy, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	store, err := assetstore.NewStore(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create asset store")
+	}
+	config, err := store.Load(&installconfig.InstallConfig{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the install-config")
+	}
+	if config == nil || config.(*installconfig.InstallConfig).Config == nil {
+		return nil, errors.New("--installer-proxy=cluster requires an install-config")
+	}
+	return config.(*installconfig.InstallConfig).Config.Proxy, nil
+}
+
+// reportEndpoints logs the endpoints the requests of the installer reached,
+// with --report-endpoints.
+func reportEndpoints() {
+	if !rootOpts.reportEndpoints {
+		return
+	}
+	endpoints := installerproxy.Endpoints()
+	if len(endpoints) == 0 {
+		logrus.Info("The installer reached no endpoint over HTTP(S)")
+		return
+	}
+	logrus.Info("The installer reached these endpoints over HTTP(S):")
+	for _, endpoint := range endpoints {
+		logrus.Infof("  %s through %s", endpoint.Host, endpoint.Proxy)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/openshift/installer/pkg/asset/kubeconfig"
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+)
+
+var kubeconfigMergeOpts struct {
+	kubeconfig string
+	useContext bool
+}
+
+func newKubeconfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "kubeconfig",
+		Short: "Manage the admin kubeconfig of the cluster",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	merge := &cobra.Command{
+		Use:   "merge",
+		Short: "Merge the admin kubeconfig into another kubeconfig",
+		Long: `Merges the cluster, user and context of the admin kubeconfig in the
+asset directory into another kubeconfig, creating it when it does not
+exist. Entries with the same names, e.g. from an earlier cluster of the
+same name, are replaced.`,
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			source, err := assetstore.ReadFile(filepath.Join(rootOpts.dir, "auth", "kubeconfig"))
+			if err != nil {
+				return err
+			}
+			return kubeconfig.Merge(source, kubeconfigMergeOpts.kubeconfig, kubeconfigMergeOpts.useContext)
+		},
+	}
+	merge.Flags().StringVar(&kubeconfigMergeOpts.kubeconfig, "kubeconfig", clientcmd.RecommendedHomeFile, "the kubeconfig to merge the admin kubeconfig into")
+	merge.Flags().BoolVar(&kubeconfigMergeOpts.useContext, "use-context", false, "switch the current context to the cluster even when the kubeconfig has one")
+	cmd.AddCommand(merge)
+	return cmd
+}
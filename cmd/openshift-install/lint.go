@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/manifestlint"
+)
+
+func newLintCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lint",
+		Short: "Check the manifests of the asset directory for mistakes",
+		Long: `Checks the manifests and openshift directories of the asset directory, e.g.
+after they were modified by hand, before the Ignition configs are generated
+from them: the documents must be valid YAML matching the schema of their
+kind, without unknown fields, the user data secrets of the machines and the
+machine config pools of the machine configs must exist, and the installer
+must not ignore the files. Warnings are reported for likely mistakes, such
+as a namespace on a cluster-scoped object.
+
+The command fails when errors are found.`,
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cleanup := setupFileHook(rootOpts.dir)
+			defer cleanup()
+			return runLintCmd(rootOpts.dir, os.Stdout)
+		},
+	}
+}
+
+func runLintCmd(directory string, out io.Writer) error {
+	problems, err := manifestlint.LintDir(directory)
+	if err != nil {
+		return err
+	}
+	errs := 0
+	for _, p := range problems {
+		fmt.Fprintln(out, p)
+		if p.Severity == manifestlint.Error {
+			errs++
+		}
+	}
+	if errs > 0 {
+		return errors.Errorf("found %d errors and %d warnings in the manifests", errs, len(problems)-errs)
+	}
+	logrus.Infof("Found no errors and %d warnings in the manifests", len(problems))
+	return nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"io"
 	"os"
@@ -12,12 +13,28 @@ import (
 	terminal "golang.org/x/term"
 	"k8s.io/klog"
 	klogv2 "k8s.io/klog/v2"
+
+	"github.com/openshift/installer/pkg/credentialsfile"
+	"github.com/openshift/installer/pkg/metrics/progress"
+	"github.com/openshift/installer/pkg/metrics/telemetry"
+	"github.com/openshift/installer/pkg/offline"
+	"github.com/openshift/installer/pkg/tracing"
+	"github.com/openshift/installer/pkg/version"
 )
 
 var (
 	rootOpts struct {
-		dir      string
-		logLevel string
+		dir               string
+		logLevel          string
+		logFormat         string
+		progressEvents    string
+		telemetry         bool
+		telemetryEndpoint string
+		credentialsFile   string
+		installerProxy    string
+		installerNoProxy  string
+		reportEndpoints   bool
+		offline           bool
 	}
 )
 
@@ -49,11 +66,20 @@ func installerMain() {
 		newAnalyzeCmd(),
 		newVersionCmd(),
 		newGraphCmd(),
+		newDiffCmd(),
+		newLintCmd(),
 		newCoreOSCmd(),
 		newCompletionCmd(),
 		newMigrateCmd(),
 		newExplainCmd(),
 		newAgentCmd(),
+		newServeCmd(),
+		newBootstrapCmd(),
+		newPreflightCmd(),
+		newKubeconfigCmd(),
+		newStatsCmd(),
+		newResumeCmd(),
+		newStateCmd(),
 	} {
 		rootCmd.AddCommand(subCmd)
 	}
@@ -69,11 +95,27 @@ func newRootCmd() *cobra.Command {
 		Short:            "Creates OpenShift clusters",
 		Long:             "",
 		PersistentPreRun: runRootCmd,
-		SilenceErrors:    true,
-		SilenceUsage:     true,
+		PersistentPostRun: func(cmd *cobra.Command, _ []string) {
+			reportEndpoints()
+			if rootOpts.telemetry {
+				recordTelemetry(cmd, telemetry.ResultSucceeded)
+			}
+			shutdownTracing(false)
+		},
+		SilenceErrors: true,
+		SilenceUsage:  true,
 	}
 	cmd.PersistentFlags().StringVar(&rootOpts.dir, "dir", ".", "assets directory")
 	cmd.PersistentFlags().StringVar(&rootOpts.logLevel, "log-level", "info", "log level (e.g. \"debug | info | warn | error\")")
+	cmd.PersistentFlags().StringVar(&rootOpts.logFormat, "log-format", "text", "log format (e.g. \"text | json\")")
+	cmd.PersistentFlags().StringVar(&rootOpts.progressEvents, "progress-events", "", "file to append JSON progress events to, or unix:<path> for a unix socket to send them to")
+	cmd.PersistentFlags().BoolVar(&rootOpts.telemetry, "telemetry", false, "record the anonymized timings of the phases of the installer in the assets directory, see the stats command")
+	cmd.PersistentFlags().StringVar(&rootOpts.credentialsFile, "credentials-file", "", "YAML file of the cloud credentials to use in place of the ones of the environment and of the user")
+	cmd.PersistentFlags().StringVar(&rootOpts.telemetryEndpoint, "telemetry-endpoint", "", "URL to also POST the anonymized timings of the phases to as JSON, requires --telemetry")
+	cmd.PersistentFlags().StringVar(&rootOpts.installerProxy, "installer-proxy", "", "URL of the proxy the requests of the installer itself go through, e.g. of the cloud SDKs and of the release image and RHCOS downloads, or cluster for the proxy of the install-config; the proxy environment variables are used otherwise")
+	cmd.PersistentFlags().StringVar(&rootOpts.installerNoProxy, "installer-no-proxy", "", "comma-separated domains, IPs and CIDRs the requests of the installer reach without --installer-proxy")
+	cmd.PersistentFlags().BoolVar(&rootOpts.reportEndpoints, "report-endpoints", false, "report the endpoints the requests of the installer reached, and through which proxy, when the command ends")
+	cmd.PersistentFlags().BoolVar(&rootOpts.offline, "offline", false, "skip the validations which need the network, and fail the operations which cannot be done without it, e.g. the RHCOS image import")
 	return cmd
 }
 
@@ -86,19 +128,87 @@ func runRootCmd(cmd *cobra.Command, args []string) {
 		level = logrus.InfoLevel
 	}
 
-	logrus.AddHook(newFileHookWithNewlineTruncate(os.Stderr, level, &logrus.TextFormatter{
-		// Setting ForceColors is necessary because logrus.TextFormatter determines
-		// whether or not to enable colors by looking at the output of the logger.
-		// In this case, the output is io.Discard, which is not a terminal.
-		// Overriding it here allows the same check to be done, but against the
-		// hook's output instead of the logger's output.
-		ForceColors:            terminal.IsTerminal(int(os.Stderr.Fd())),
-		DisableTimestamp:       true,
-		DisableLevelTruncation: true,
-		DisableQuote:           true,
-	}))
+	switch rootOpts.logFormat {
+	case "json":
+		// JSON entries are not split at newlines, so that each line of the
+		// output stays a JSON object.
+		logrus.AddHook(newFileHook(os.Stderr, level, &logrus.JSONFormatter{}))
+	default:
+		logrus.AddHook(newFileHookWithNewlineTruncate(os.Stderr, level, &logrus.TextFormatter{
+			// Setting ForceColors is necessary because logrus.TextFormatter determines
+			// whether or not to enable colors by looking at the output of the logger.
+			// In this case, the output is io.Discard, which is not a terminal.
+			// Overriding it here allows the same check to be done, but against the
+			// hook's output instead of the logger's output.
+			ForceColors:            terminal.IsTerminal(int(os.Stderr.Fd())),
+			DisableTimestamp:       true,
+			DisableLevelTruncation: true,
+			DisableQuote:           true,
+		}))
+	}
 
 	if err != nil {
 		logrus.Fatal(errors.Wrap(err, "invalid log-level"))
 	}
+	if rootOpts.logFormat != "text" && rootOpts.logFormat != "json" {
+		logrus.Fatalf("invalid log-format %q, must be text or json", rootOpts.logFormat)
+	}
+
+	if rootOpts.progressEvents != "" {
+		// The stream is closed when the process exits.
+		if _, err := progress.Open(rootOpts.progressEvents); err != nil {
+			logrus.Fatal(err)
+		}
+	}
+
+	if rootOpts.credentialsFile != "" {
+		file, err := credentialsfile.Load(rootOpts.credentialsFile)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+		credentialsfile.Set(file)
+	}
+
+	if rootOpts.offline {
+		if rootOpts.installerProxy != "" || rootOpts.telemetryEndpoint != "" {
+			logrus.Fatal("--offline conflicts with --installer-proxy and --telemetry-endpoint")
+		}
+		offline.SetEnabled(true)
+	}
+
+	if err := configureInstallerProxy(rootOpts.dir); err != nil {
+		logrus.Fatal(err)
+	}
+	// failed commands exit through logrus, so their endpoints are reported
+	// by an exit handler
+	logrus.RegisterExitHandler(reportEndpoints)
+
+	if rootOpts.telemetryEndpoint != "" && !rootOpts.telemetry {
+		logrus.Fatal("--telemetry-endpoint requires --telemetry")
+	}
+	if rootOpts.telemetry {
+		// failed commands exit through logrus, so their telemetry is
+		// recorded by an exit handler
+		logrus.RegisterExitHandler(func() {
+			recordTelemetry(cmd, telemetry.ResultFailed)
+		})
+	}
+
+	installerVersion, _ := version.Version()
+	if _, err := tracing.Init(context.Background(), cmd.CommandPath(), installerVersion); err != nil {
+		logrus.Warnf("Tracing is disabled: %v", err)
+	}
+	// failed commands exit through logrus, so their spans which were not
+	// exported as they ended are exported by an exit handler
+	logrus.RegisterExitHandler(func() {
+		shutdownTracing(true)
+	})
+}
+
+// shutdownTracing exports the spans of the command, when tracing is
+// configured. Tracing never fails the command, so errors are only logged.
+func shutdownTracing(failed bool) {
+	if err := tracing.Shutdown(context.Background(), failed); err != nil {
+		logrus.Warnf("Failed to export the tracing spans: %v", err)
+	}
 }
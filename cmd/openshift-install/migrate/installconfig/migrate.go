@@ -0,0 +1,75 @@
+package installconfig
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/strictyaml"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/conversion"
+)
+
+var (
+	migrateInstallConfigOpts struct {
+		inPlace bool
+	}
+)
+
+func runMigrateInstallConfigCmd(cmd *cobra.Command, args []string) error {
+	filename := "install-config.yaml"
+	if len(args) > 0 {
+		filename = args[0]
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	config := &types.InstallConfig{}
+	if err := strictyaml.Unmarshal(data, config); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal %s", filename)
+	}
+	if err := conversion.MigrateInstallConfig(config); err != nil {
+		return errors.Wrapf(err, "failed to migrate %s", filename)
+	}
+	data, err = yaml.Marshal(config)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the install-config")
+	}
+
+	if !migrateInstallConfigOpts.inPlace {
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filename, data, info.Mode()); err != nil {
+		return err
+	}
+	logrus.Infof("Migrated %s to the install-config version %s", filename, types.InstallConfigVersion)
+	return nil
+}
+
+// NewMigrateInstallConfigCmd adds the install-config command to openshift-install
+func NewMigrateInstallConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "install-config [FILE]",
+		Short: "Migrate an install-config to the current version",
+		Long: fmt.Sprintf(`This will convert an install-config of an earlier version, install-config.yaml
+by default, to the version %s, replacing its deprecated fields, and print it.
+The comments of the install-config are not kept.`, types.InstallConfigVersion),
+		Args: cobra.MaximumNArgs(1),
+		RunE: runMigrateInstallConfigCmd,
+	}
+
+	cmd.PersistentFlags().BoolVar(&migrateInstallConfigOpts.inPlace, "in-place", false, "Write the migrated install-config to the file instead of printing it")
+
+	return cmd
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/installconfig/connectivity"
+	"github.com/openshift/installer/pkg/asset/releaseimage"
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+	"github.com/openshift/installer/pkg/offline"
+)
+
+var (
+	preflightConnectivityOpts struct {
+		output string
+	}
+)
+
+func newPreflightCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "preflight",
+		Short: "Run checks of the environment before the cluster is provisioned",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	connectivityCmd := &cobra.Command{
+		Use:   "connectivity",
+		Short: "Check that the services the cluster depends on can be reached",
+		Long: `Check that the services the cluster depends on can be reached from the
+installer host, to catch the gaps of the firewalls before provisioning: the
+registries of the release image and of its mirrors, the APIs of the platform,
+the Tang and NTP servers of the machine pools and the proxies of the
+install-config. The HTTPS endpoints are reached through the proxy of the
+install-config, unless noProxy excludes them, and must present certificates
+trusted by the system roots and the additional trust bundle.
+
+The matrix of the endpoints, the proxy each one was reached through and the
+result is printed. The command fails when an endpoint cannot be reached.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			cleanup := setupFileHook(rootOpts.dir)
+			defer cleanup()
+			if err := runPreflightConnectivityCmd(context.Background(), os.Stdout, rootOpts.dir, preflightConnectivityOpts.output); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+	connectivityCmd.Flags().StringVar(&preflightConnectivityOpts.output, "output", "text", "format of the matrix: text or json")
+	cmd.AddCommand(connectivityCmd)
+	return cmd
+}
+
+func runPreflightConnectivityCmd(ctx context.Context, w io.Writer, directory string, output string) error {
+	if output != "text" && output != "json" {
+		return errors.Errorf("unsupported output %q, must be text or json", output)
+	}
+	if err := offline.Require("Checking the connectivity"); err != nil {
+		return err
+	}
+
+	assetStore, err := assetstore.NewStore(directory)
+	if err != nil {
+		return errors.Wrap(err, "failed to create asset store")
+	}
+	config := &installconfig.InstallConfig{}
+	releaseImage := &releaseimage.Image{}
+	if err := assetStore.Fetch(ctx, config); err != nil {
+		return errors.Wrapf(err, "failed to fetch %s", config.Name())
+	}
+	if err := assetStore.Fetch(ctx, releaseImage); err != nil {
+		return errors.Wrapf(err, "failed to fetch %s", releaseImage.Name())
+	}
+
+	results, err := connectivity.CheckMatrix(ctx, config.Config, releaseImage.PullSpec)
+	if err != nil {
+		return err
+	}
+	if output == "json" {
+		err = printConnectivityMatrixJSON(w, results)
+	} else {
+		err = printConnectivityMatrix(w, results)
+	}
+	if err != nil {
+		return err
+	}
+
+	unreachable := 0
+	for _, r := range results {
+		if r.Err != nil {
+			unreachable++
+		}
+	}
+	if unreachable > 0 {
+		return errors.Errorf("%d of the %d endpoints cannot be reached from the installer host", unreachable, len(results))
+	}
+	logrus.Infof("All the %d endpoints can be reached from the installer host", len(results))
+	return nil
+}
+
+// printConnectivityMatrix prints a line for each endpoint: its kind, its
+// address, the proxy it was reached through and the result.
+func printConnectivityMatrix(w io.Writer, results []connectivity.Result) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tENDPOINT\tVIA\tRESULT")
+	for _, r := range results {
+		result := "ok"
+		if r.Err != nil {
+			result = fmt.Sprintf("unreachable: %v", r.Err)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Kind, r.Address(), r.Via, result)
+	}
+	return tw.Flush()
+}
+
+// printConnectivityMatrixJSON prints the matrix as a JSON array.
+func printConnectivityMatrixJSON(w io.Writer, results []connectivity.Result) error {
+	type entry struct {
+		Kind      string `json:"kind"`
+		Endpoint  string `json:"endpoint"`
+		Via       string `json:"via"`
+		Reachable bool   `json:"reachable"`
+		Error     string `json:"error,omitempty"`
+	}
+	entries := make([]entry, 0, len(results))
+	for _, r := range results {
+		e := entry{Kind: r.Kind, Endpoint: r.Address(), Via: r.Via, Reachable: r.Err == nil}
+		if r.Err != nil {
+			e.Error = r.Err.Error()
+		}
+		entries = append(entries, e)
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
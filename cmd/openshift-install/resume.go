@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	targetassets "github.com/openshift/installer/pkg/asset/targets"
+	"github.com/openshift/installer/pkg/checkpoint"
+)
+
+// The checkpoints of the steps of create cluster which follow the
+// provisioning of the infrastructure.
+const (
+	bootstrapCompleteCheckpoint  = "bootstrap-complete"
+	bootstrapDestroyCheckpoint   = "bootstrap-destroy"
+	postBootstrapHooksCheckpoint = "post-bootstrap-hooks"
+)
+
+func newResumeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resume",
+		Short: "Resume an interrupted install of an OpenShift cluster",
+		Long: `Resume an interrupted install of an OpenShift cluster.
+
+The steps of create cluster which completed are recorded in the assets
+directory, so that an install interrupted by a failure or a signal continues
+from the last completed step instead of requiring the cluster to be
+destroyed and installed again. This command resumes the install, and fails
+when there is no interrupted install in the assets directory. Re-running
+create cluster resumes it as well.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, args []string) {
+			interrupted, err := checkpoint.Exists(rootOpts.dir)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			if !interrupted {
+				logrus.Fatalf("There is no interrupted install to resume in %s", rootOpts.dir)
+			}
+			runTargetCmd(targetassets.Cluster...)(cmd, args)
+			clusterTarget.command.PostRun(cmd, args)
+		},
+	}
+	addWaitTimeoutFlag(cmd)
+	cmd.PersistentFlags().BoolVar(&createOpts.preserveBootstrap, "preserve-bootstrap", false, "keep the bootstrap resources after bootstrapping completes, for debugging (or set OPENSHIFT_INSTALL_PRESERVE_BOOTSTRAP); destroy them later with destroy bootstrap")
+	cmd.PersistentFlags().BoolVar(&createOpts.rollbackOnFailure, "rollback-on-failure", false, "destroy the partially created infrastructure when provisioning it fails or is interrupted, instead of leaving it to resume the install")
+	cmd.PersistentFlags().DurationVar(&createOpts.bootstrapTeardownDelay, "bootstrap-teardown-delay", 0, "how long to keep the bootstrap resources after bootstrapping completes before destroying them automatically; the install waits for the teardown before it exits")
+	cmd.PersistentFlags().StringVar(&createOpts.retainBootstrapArtifacts, "retain-bootstrap-artifacts", "", "gather the logs, the rendered assets and the journals of the bootstrap machine before destroying it, and store the bundle in Secrets of the cluster (secret) or in an object store (s3://<bucket>[/<prefix>], gs://<bucket>[/<prefix>] or azblob://<account>/<container>[/<prefix>])")
+	return cmd
+}
+
+// interruptibleContext returns a context which is canceled on the first
+// SIGINT or SIGTERM, so that the running steps complete and are checkpointed
+// before the installer exits. A second signal exits immediately.
+func interruptibleContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-signals:
+			signal.Stop(signals)
+			logrus.Warnf("Received %v, waiting for the running steps to complete; interrupt again to exit immediately", sig)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel()
+	}
+}
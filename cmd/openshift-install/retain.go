@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset/store"
+	"github.com/openshift/installer/pkg/gather"
+)
+
+// retainInSecrets is the destination of --retain-bootstrap-artifacts which
+// stores the bundle in Secrets of the cluster.
+const retainInSecrets = "secret"
+
+// retainBootstrapArtifacts gathers the logs, the rendered assets and the
+// journals of the bootstrap machine, and stores the bundle at the destination
+// of --retain-bootstrap-artifacts, so that they outlive the bootstrap machine.
+func retainBootstrapArtifacts(ctx context.Context, dir, destination string) error {
+	logrus.Info("Retaining the artifacts of the bootstrap machine...")
+	bundlePath, err := runGatherBootstrapCmd(dir)
+	if err != nil {
+		return errors.Wrap(err, "failed to gather the artifacts of the bootstrap machine")
+	}
+	data, err := os.ReadFile(bundlePath)
+	if err != nil {
+		return err
+	}
+	name := filepath.Base(bundlePath)
+
+	if destination == retainInSecrets {
+		config, err := loadKubeconfig(dir)
+		if err != nil {
+			return errors.Wrap(err, "loading kubeconfig")
+		}
+		if err := gather.RetainInSecrets(ctx, config, name, data); err != nil {
+			return err
+		}
+		logrus.Infof("Retained the artifacts of the bootstrap machine in the Secrets of %s labeled %s=%s", gather.RetainedArtifactsNamespace, gather.RetainedArtifactsLabel, name)
+		return nil
+	}
+
+	if err := store.WriteObject(destination, name, data); err != nil {
+		return err
+	}
+	logrus.Infof("Retained the artifacts of the bootstrap machine in %s as %s", destination, name)
+	return nil
+}
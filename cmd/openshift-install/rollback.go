@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset/cluster"
+	"github.com/openshift/installer/pkg/client"
+)
+
+// rollbackRequired returns whether the provisioning, which returned the
+// error, left the infrastructure of the cluster partially created: it failed
+// to provision the infrastructure or it was interrupted. A provisioning
+// interrupted after it succeeded left the infrastructure complete, and is
+// resumed instead.
+func rollbackRequired(ctx context.Context, provisioning bool, err error) bool {
+	return provisioning && err != nil && (client.IsInfrastructureError(err) || ctx.Err() != nil)
+}
+
+// rollbackProvisioning destroys the partially created infrastructure of a
+// provisioning which failed or was interrupted with destroy, when
+// --rollback-on-failure is set. Otherwise it tells how to resume the install
+// or destroy the infrastructure.
+func rollbackProvisioning(dir string, destroy func(context.Context) error) {
+	// without the metadata of the cluster, nothing was provisioned
+	if _, err := cluster.LoadMetadata(dir); err != nil {
+		return
+	}
+	if !createOpts.rollbackOnFailure {
+		logrus.Info("The infrastructure of the cluster is partially created. Resume the install with openshift-install resume, " +
+			"or destroy the infrastructure with openshift-install destroy cluster (or automatically with --rollback-on-failure)")
+		return
+	}
+
+	logrus.Info("Rolling back the partially created infrastructure of the cluster...")
+	// the context of the provisioning is canceled when it was interrupted
+	if err := destroy(context.Background()); err != nil {
+		logrus.Errorf("Failed to roll back the infrastructure of the cluster, destroy it with openshift-install destroy cluster: %v", err)
+		return
+	}
+	logrus.Info("Rolled back the infrastructure of the cluster")
+}
+
+// destroyCluster returns a function which destroys the cluster of the assets
+// directory.
+func destroyCluster(dir string) func(context.Context) error {
+	return func(ctx context.Context) error {
+		installer, err := client.New(dir)
+		if err != nil {
+			return err
+		}
+		_, err = installer.DestroyCluster(ctx, client.DestroyOptions{})
+		return err
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+func TestRollbackRequired(t *testing.T) {
+	interrupted, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cases := []struct {
+		name         string
+		ctx          context.Context
+		provisioning bool
+		err          error
+		expected     bool
+	}{
+		{
+			name:         "succeeded",
+			ctx:          context.Background(),
+			provisioning: true,
+		},
+		{
+			name:         "infrastructure failure",
+			ctx:          context.Background(),
+			provisioning: true,
+			err:          errors.Wrap(errors.New("quota exceeded"), asset.ClusterCreationError),
+			expected:     true,
+		},
+		{
+			name:         "interrupted while provisioning",
+			ctx:          interrupted,
+			provisioning: true,
+			err:          context.Canceled,
+			expected:     true,
+		},
+		{
+			// the infrastructure is complete and the install is resumed
+			name:         "interrupted after the infrastructure was provisioned",
+			ctx:          interrupted,
+			provisioning: true,
+		},
+		{
+			name:         "other failure",
+			ctx:          context.Background(),
+			provisioning: true,
+			err:          errors.New(asset.InstallConfigError),
+		},
+		{
+			name: "infrastructure failure without provisioning",
+			ctx:  context.Background(),
+			err:  errors.Wrap(errors.New("quota exceeded"), asset.ClusterCreationError),
+		},
+		{
+			name: "interrupted without provisioning",
+			ctx:  interrupted,
+			err:  context.Canceled,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, rollbackRequired(tc.ctx, tc.provisioning, tc.err))
+		})
+	}
+}
+
+// setRollbackOnFailure sets --rollback-on-failure for the test.
+func setRollbackOnFailure(t *testing.T, rollback bool) {
+	previous := createOpts.rollbackOnFailure
+	createOpts.rollbackOnFailure = rollback
+	t.Cleanup(func() {
+		createOpts.rollbackOnFailure = previous
+	})
+}
+
+func TestRollbackProvisioning(t *testing.T) {
+	cases := []struct {
+		name      string
+		metadata  bool
+		rollback  bool
+		destroy   error
+		destroyed bool
+		level     logrus.Level
+		message   string
+	}{
+		{
+			name:     "nothing provisioned",
+			rollback: true,
+		},
+		{
+			name:     "without rollback",
+			metadata: true,
+			level:    logrus.InfoLevel,
+			message:  "Resume the install with openshift-install resume",
+		},
+		{
+			name:      "rollback",
+			metadata:  true,
+			rollback:  true,
+			destroyed: true,
+			level:     logrus.InfoLevel,
+			message:   "Rolled back the infrastructure of the cluster",
+		},
+		{
+			name:      "rollback failure",
+			metadata:  true,
+			rollback:  true,
+			destroy:   errors.New("failed to destroy"),
+			destroyed: true,
+			level:     logrus.ErrorLevel,
+			message:   "destroy it with openshift-install destroy cluster: failed to destroy",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			setRollbackOnFailure(t, tc.rollback)
+			hook := logrustest.NewGlobal()
+			defer hook.Reset()
+
+			dir := t.TempDir()
+			if tc.metadata {
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, "metadata.json"), []byte(`{"clusterName":"test","infraID":"test-abcde"}`), 0o640))
+			}
+
+			destroyed := false
+			rollbackProvisioning(dir, func(ctx context.Context) error {
+				destroyed = true
+				assert.NoError(t, ctx.Err())
+				return tc.destroy
+			})
+			assert.Equal(t, tc.destroyed, destroyed)
+			if tc.message == "" {
+				assert.Empty(t, hook.AllEntries())
+				return
+			}
+			if assert.NotNil(t, hook.LastEntry()) {
+				assert.Equal(t, tc.level, hook.LastEntry().Level)
+				assert.Contains(t, hook.LastEntry().Message, tc.message)
+			}
+		})
+	}
+}
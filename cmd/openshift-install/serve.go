@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
+	"github.com/openshift/installer/pkg/server"
+)
+
+var serveOpts struct {
+	listen      string
+	tokenFile   string
+	tlsCertFile string
+	tlsKeyFile  string
+}
+
+func newServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve an API to create and destroy clusters remotely",
+		Long: `Serve an HTTP API to create and destroy clusters, keeping the assets
+of each install in a subdirectory of --dir named after it:
+
+  PUT    /v1/installs/{name}             create the cluster of the install-config in the body
+  GET    /v1/installs/{name}             the state of the last operation of the install
+  GET    /v1/installs/{name}/events      the progress events of the last operation
+  GET    /v1/installs/{name}/kubeconfig  the admin kubeconfig of the cluster
+  DELETE /v1/installs/{name}             destroy the cluster`,
+		Args: cobra.ExactArgs(0),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runServeCmd(rootOpts.dir)
+		},
+	}
+	cmd.Flags().StringVar(&serveOpts.listen, "listen", "127.0.0.1:8080", "address to listen on")
+	cmd.Flags().StringVar(&serveOpts.tokenFile, "token-file", "", "file holding the bearer token requests must carry")
+	cmd.Flags().StringVar(&serveOpts.tlsCertFile, "tls-cert-file", "", "certificate to serve TLS with")
+	cmd.Flags().StringVar(&serveOpts.tlsKeyFile, "tls-key-file", "", "key of --tls-cert-file")
+	return cmd
+}
+
+func runServeCmd(directory string) error {
+	if (serveOpts.tlsCertFile == "") != (serveOpts.tlsKeyFile == "") {
+		return errors.New("--tls-cert-file and --tls-key-file must be given together")
+	}
+	var token string
+	if serveOpts.tokenFile != "" {
+		data, err := os.ReadFile(serveOpts.tokenFile)
+		if err != nil {
+			return errors.Wrap(err, "failed to read the token")
+		}
+		token = strings.TrimSpace(string(data))
+		if token == "" {
+			return errors.Errorf("%s is empty", serveOpts.tokenFile)
+		}
+	} else {
+		logrus.Warn("Serving without --token-file: anyone who can reach the API can create and destroy clusters")
+	}
+
+	// there is no one to answer the questions of the install-config
+	answers.SetNonInteractive(true)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	s := server.New(directory, token)
+	operationsDone := make(chan struct{})
+	go func() {
+		defer close(operationsDone)
+		s.Run(ctx)
+	}()
+
+	httpServer := &http.Server{
+		Addr:              serveOpts.listen,
+		Handler:           s,
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			logrus.WithError(err).Warn("Failed to shut down the server gracefully")
+		}
+	}()
+
+	logrus.Infof("Serving the installs of %s on %s", directory, serveOpts.listen)
+	var err error
+	if serveOpts.tlsCertFile != "" {
+		err = httpServer.ListenAndServeTLS(serveOpts.tlsCertFile, serveOpts.tlsKeyFile)
+	} else {
+		err = httpServer.ListenAndServe()
+	}
+
+	// the operations are cancelled with the context, and their assets must
+	// be saved before exiting
+	stop()
+	logrus.Debug("Waiting for the running operation to stop")
+	<-operationsDone
+	<-shutdownDone
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
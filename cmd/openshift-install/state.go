@@ -0,0 +1,156 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+)
+
+var (
+	stateExportOpts struct {
+		output        string
+		redactSecrets bool
+	}
+)
+
+func newStateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Export, import and decrypt the state of the assets directory",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	export := &cobra.Command{
+		Use:   "export",
+		Short: "Export the assets directory and its state to an archive",
+		Long: `Export the assets directory and its state, wherever it is kept, to a gzipped
+tarball, to hand the cluster over to another team member or to archive it.
+A state and files encrypted with OPENSHIFT_INSTALL_STATE_PASSPHRASE or
+OPENSHIFT_INSTALL_STATE_KMS_KEY_ID stay encrypted in the archive.
+
+With --redact-secrets, the kubeadmin password, the kubeconfigs, the private
+keys, the ignition configs and the other secrets are left out or replaced.
+Such an export keeps what destroy cluster and gather bootstrap use, the
+metadata, the terraform state and the install-config, but the install cannot
+be resumed from it, and gather bootstrap needs the SSH keys of the user
+(--key). Credentials in the metadata, e.g. of vSphere, are redacted too.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := runStateExportCmd(rootOpts.dir, stateExportOpts.output, stateExportOpts.redactSecrets); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+	export.Flags().StringVar(&stateExportOpts.output, "output", "", "file to write the archive to, or - for stdout")
+	export.Flags().BoolVar(&stateExportOpts.redactSecrets, "redact-secrets", false, "leave the secrets out of the archive")
+	if err := export.MarkFlagRequired("output"); err != nil {
+		panic(err)
+	}
+	cmd.AddCommand(export)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "import ARCHIVE",
+		Short: "Import an exported assets directory and its state",
+		Long: `Import an archive of the export command into the assets directory, which must
+not have a state. The state is written wherever the state of the directory is
+kept, e.g. the remote backend of OPENSHIFT_INSTALL_STATE_URL.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			if err := runStateImportCmd(rootOpts.dir, args[0]); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "decrypt FILE",
+		Short: "Print the plaintext of an encrypted file of the assets directory",
+		Long: `Print the plaintext of a file of the assets directory, e.g. auth/kubeconfig or
+auth/kubeadmin-password, to stdout. With OPENSHIFT_INSTALL_STATE_PASSPHRASE or
+OPENSHIFT_INSTALL_STATE_KMS_KEY_ID set, the files under auth/ are encrypted
+along with the state, and the tools which read them, e.g. oc, need their
+plaintext:
+
+  openshift-install state decrypt auth/kubeconfig > kubeconfig
+
+The path is relative to the assets directory. Files which are not encrypted
+are printed as they are.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			if err := runStateDecryptCmd(rootOpts.dir, args[0], os.Stdout); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	})
+	return cmd
+}
+
+func runStateExportCmd(directory string, output string, redactSecrets bool) error {
+	var w io.Writer = os.Stdout
+	if output != "-" {
+		dir, err := filepath.Abs(directory)
+		if err != nil {
+			return err
+		}
+		path, err := filepath.Abs(output)
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return errors.Errorf("the archive %s must be outside of the assets directory", output)
+		}
+		f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			return errors.Wrap(err, "failed to create the archive")
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := assetstore.Export(directory, w, redactSecrets); err != nil {
+		return err
+	}
+	if output != "-" {
+		logrus.Infof("Exported the assets directory to %s", output)
+	}
+	return nil
+}
+
+func runStateImportCmd(directory string, archive string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return errors.Wrap(err, "failed to open the archive")
+	}
+	defer f.Close()
+	manifest, err := assetstore.Import(directory, f)
+	if err != nil {
+		return err
+	}
+	logrus.Infof("Imported the assets directory exported by %s at %s", manifest.Version, manifest.Created.Format("2006-01-02 15:04:05 MST"))
+	if manifest.Redacted {
+		logrus.Warn("The export has its secrets redacted: the install cannot be resumed, and gather bootstrap needs the SSH keys of the user (--key)")
+	}
+	return nil
+}
+
+func runStateDecryptCmd(directory string, file string, w io.Writer) error {
+	path := file
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(directory, path)
+	}
+	data, err := assetstore.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/metrics/telemetry"
+)
+
+var (
+	statsOpts struct {
+		output string
+	}
+)
+
+func newStatsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Print the timings of the phases of the installer runs",
+		Long: `Print the timings of the phases of the installer runs.
+
+This command prints the anonymized timings of the phases of the runs of the
+installer in the assets directory, which are recorded when the installer runs
+with --telemetry.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := runStatsCmd(os.Stdout, rootOpts.dir, statsOpts.output); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+	cmd.PersistentFlags().StringVar(&statsOpts.output, "output", "text", "format of the timings: text or json")
+	return cmd
+}
+
+func runStatsCmd(w io.Writer, directory string, output string) error {
+	records, err := telemetry.Read(directory)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return errors.Errorf("no timings are recorded in %s, run the installer with --telemetry to record them", directory)
+	}
+
+	switch output {
+	case "text":
+		return printStats(w, records)
+	case "json":
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	default:
+		return errors.Errorf("unsupported output %q, must be text or json", output)
+	}
+}
+
+// printStats prints the records, each followed by the durations of its
+// phases.
+func printStats(w io.Writer, records []telemetry.Record) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for i, record := range records {
+		if i > 0 {
+			fmt.Fprintln(tw)
+		}
+		result := string(record.Result)
+		if record.Result == telemetry.ResultFailed {
+			result = fmt.Sprintf("%s (%s)", result, record.FailureCategory)
+			if record.FailedPhase != "" {
+				result = fmt.Sprintf("%s in %s", result, record.FailedPhase)
+			}
+		}
+		platform := record.Platform
+		if platform == "" {
+			platform = "unknown platform"
+		}
+		fmt.Fprintf(tw, "%s\t%s on %s %s: %s\n", record.Time.Format(time.RFC3339), record.Command, platform, record.Version, result)
+		for _, phase := range record.Phases {
+			fmt.Fprintf(tw, "  %s\t%s\n", phase.Name, time.Duration(phase.DurationSeconds*float64(time.Second)))
+		}
+	}
+	return tw.Flush()
+}
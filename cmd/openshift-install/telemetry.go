@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/asset/cluster"
+	"github.com/openshift/installer/pkg/metrics/telemetry"
+	"github.com/openshift/installer/pkg/metrics/timer"
+	"github.com/openshift/installer/pkg/version"
+)
+
+// exitCodeFailureCategories are the telemetry failure categories of the exit
+// codes of the installer.
+var exitCodeFailureCategories = map[int]telemetry.FailureCategory{
+	exitCodeInstallConfigError:   telemetry.FailureInstallConfig,
+	exitCodeInfrastructureFailed: telemetry.FailureInfrastructure,
+	exitCodeBootstrapFailed:      telemetry.FailureBootstrap,
+	exitCodeInstallFailed:        telemetry.FailureInstall,
+}
+
+// failureCategory is the telemetry failure category of the exit of the
+// installer. Fatal errors have no exit code of their own.
+var failureCategory = telemetry.FailureOther
+
+// exitWithCode exits the installer with the exit code, recording its failure
+// category for telemetry.
+func exitWithCode(code int) {
+	if category, ok := exitCodeFailureCategories[code]; ok {
+		failureCategory = category
+	}
+	logrus.Exit(code)
+}
+
+// recordTelemetry records the timings of the phases of the command, when the
+// command is timed. The record is appended to the telemetry file of the
+// assets directory, and sent to the telemetry endpoint when one is set.
+// Telemetry never fails the command, so errors are only logged.
+func recordTelemetry(cmd *cobra.Command, result telemetry.Result) {
+	if !timer.Started(timer.TotalTimeElapsed) {
+		return
+	}
+
+	installerVersion, _ := version.Version()
+	record := &telemetry.Record{
+		Time:     time.Now().UTC(),
+		Command:  strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" "),
+		Version:  installerVersion,
+		Platform: telemetryPlatform(rootOpts.dir),
+		Result:   result,
+		Phases:   []telemetry.Phase{},
+	}
+	for _, stage := range timer.CompletedStages() {
+		record.Phases = append(record.Phases, telemetry.Phase{Name: stage.Name, DurationSeconds: stage.Duration.Seconds()})
+	}
+	if result == telemetry.ResultFailed {
+		record.FailureCategory = failureCategory
+		record.FailedPhase = timer.RunningStage()
+	}
+
+	if err := telemetry.Write(rootOpts.dir, record); err != nil {
+		logrus.Warnf("Failed to record telemetry: %v", err)
+	}
+	if rootOpts.telemetryEndpoint != "" {
+		if err := telemetry.Send(context.Background(), rootOpts.telemetryEndpoint, record); err != nil {
+			logrus.Warnf("Failed to send telemetry: %v", err)
+		}
+	}
+}
+
+// telemetryPlatform returns the platform of the cluster of the assets
+// directory, from the install-config or, once it is consumed, from the
+// metadata of the cluster.
+func telemetryPlatform(directory string) string {
+	if ic := loadInstallConfig(directory); ic != nil {
+		return ic.Platform.Name()
+	}
+	if metadata, err := cluster.LoadMetadata(directory); err == nil {
+		return metadata.Platform()
+	}
+	return ""
+}
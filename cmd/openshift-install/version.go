@@ -1,38 +1,132 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/connectivity"
 	"github.com/openshift/installer/pkg/asset/releaseimage"
+	"github.com/openshift/installer/pkg/rhcos"
+	"github.com/openshift/installer/pkg/terraform/providers"
 	"github.com/openshift/installer/pkg/version"
 )
 
+var versionOpts struct {
+	output  string
+	resolve bool
+}
+
+// versionReport is the version information printed with --output json.
+type versionReport struct {
+	Version             string              `json:"version"`
+	Commit              string              `json:"commit,omitempty"`
+	ReleaseImage        *releaseImageReport `json:"releaseImage,omitempty"`
+	ReleaseArchitecture string              `json:"releaseArchitecture"`
+	RHCOS               *rhcosReport        `json:"rhcos,omitempty"`
+	Terraform           *providers.Versions `json:"terraform,omitempty"`
+}
+
+// releaseImageReport is the release image the installer is pinned to.
+type releaseImageReport struct {
+	PullSpec string `json:"pullSpec"`
+	Digest   string `json:"digest,omitempty"`
+}
+
+// rhcosReport is the RHCOS stream the installer is pinned to, with the
+// build of the boot images by architecture and platform.
+type rhcosReport struct {
+	Stream string                       `json:"stream"`
+	Builds map[string]map[string]string `json:"builds"`
+}
+
 func newVersionCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print version information",
 		Long:  "",
 		Args:  cobra.ExactArgs(0),
 		RunE:  runVersionCmd,
 	}
+	cmd.Flags().StringVar(&versionOpts.output, "output", "text", "format of the version information: text or json; json adds the RHCOS builds and the embedded terraform versions")
+	cmd.Flags().BoolVar(&versionOpts.resolve, "resolve", false, "look up the digest of the release image in its registry when it is pinned by tag")
+	return cmd
 }
 
 func runVersionCmd(cmd *cobra.Command, args []string) error {
+	if versionOpts.output != "text" && versionOpts.output != "json" {
+		return errors.Errorf("unsupported output format %q, must be text or json", versionOpts.output)
+	}
+
 	versionString, err := version.Version()
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("%s %s\n", os.Args[0], versionString)
-	if version.Commit != "" {
-		fmt.Printf("built from commit %s\n", version.Commit)
+	report := &versionReport{
+		Version:             versionString,
+		Commit:              version.Commit,
+		ReleaseArchitecture: string(version.DefaultArch()),
 	}
 	if image, err := releaseimage.Default(); err == nil {
-		fmt.Printf("release image %s\n", image)
+		report.ReleaseImage = &releaseImageReport{PullSpec: image}
+		if versionOpts.resolve {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			report.ReleaseImage.Digest, err = connectivity.ResolveDigest(ctx, image)
+			if err != nil {
+				return errors.Wrapf(err, "failed to resolve the digest of %s", image)
+			}
+		}
+	}
+
+	if versionOpts.output == "text" {
+		fmt.Printf("%s %s\n", os.Args[0], versionString)
+		if version.Commit != "" {
+			fmt.Printf("built from commit %s\n", version.Commit)
+		}
+		if report.ReleaseImage != nil {
+			fmt.Printf("release image %s\n", report.ReleaseImage.PullSpec)
+			if report.ReleaseImage.Digest != "" {
+				fmt.Printf("release image digest %s\n", report.ReleaseImage.Digest)
+			}
+		}
+		fmt.Printf("release architecture %s\n", version.DefaultArch())
+		return nil
+	}
+
+	report.RHCOS, err = rhcosBuilds()
+	if err != nil {
+		return err
+	}
+	report.Terraform, err = providers.EmbeddedVersions()
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// rhcosBuilds returns the builds of the boot images in the embedded RHCOS
+// stream metadata.
+func rhcosBuilds() (*rhcosReport, error) {
+	st, err := rhcos.FetchCoreOSBuild(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	report := &rhcosReport{Stream: st.Stream, Builds: map[string]map[string]string{}}
+	for archName, streamArch := range st.Architectures {
+		builds := map[string]string{}
+		for platform, artifacts := range streamArch.Artifacts {
+			builds[platform] = artifacts.Release
+		}
+		report.Builds[archName] = builds
 	}
-	fmt.Printf("release architecture %s\n", version.DefaultArch())
-	return nil
+	return report, nil
 }
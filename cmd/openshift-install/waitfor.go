@@ -2,16 +2,139 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
+	configv1 "github.com/openshift/api/config/v1"
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	assetstore "github.com/openshift/installer/pkg/asset/store"
 	timer "github.com/openshift/installer/pkg/metrics/timer"
+	"github.com/openshift/installer/pkg/types"
+	cov1helpers "github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
 )
 
+// The phases whose wait timeout can be configured.
+const (
+	bootstrapCompletePhase      = "bootstrap-complete"
+	clusterOperatorsStablePhase = "cluster-operators-stable"
+	consoleRoutePhase           = "console-route"
+	operatorsStablePhase        = "operators-stable"
+	nodesReadyPhase             = "nodes-ready"
+)
+
+// waitTimeoutPhases lists the phases accepted by --wait-timeout.
+var waitTimeoutPhases = []string{bootstrapCompletePhase, clusterOperatorsStablePhase, consoleRoutePhase, operatorsStablePhase, nodesReadyPhase}
+
+// waitTimeouts are the timeouts set with --wait-timeout, by phase.
+var waitTimeouts = waitTimeoutsFlag{}
+
+// waitTimeoutsFlag parses the phase=duration pairs of --wait-timeout.
+type waitTimeoutsFlag map[string]time.Duration
+
+func (f waitTimeoutsFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for phase, d := range f {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", phase, d))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (f waitTimeoutsFlag) Set(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		phase, duration, ok := strings.Cut(pair, "=")
+		if !ok {
+			return errors.Errorf("%q is not of the form phase=duration", pair)
+		}
+		if !sets.NewString(waitTimeoutPhases...).Has(phase) {
+			return errors.Errorf("unknown phase %q, must be one of %s", phase, strings.Join(waitTimeoutPhases, ", "))
+		}
+		d, err := time.ParseDuration(duration)
+		if err != nil {
+			return err
+		}
+		if d <= 0 {
+			return errors.Errorf("the timeout of %s must be positive", phase)
+		}
+		f[phase] = d
+	}
+	return nil
+}
+
+func (f waitTimeoutsFlag) Type() string {
+	return "phase=duration"
+}
+
+// addWaitTimeoutFlag adds the --wait-timeout flag to the command.
+func addWaitTimeoutFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().Var(waitTimeouts, "wait-timeout", fmt.Sprintf("how long to wait for a phase of the install, e.g. %s=90m; the phases are %s. Overrides waitTimeouts in the install-config.", bootstrapCompletePhase, strings.Join(waitTimeoutPhases, ", ")))
+}
+
+// loadInstallConfig returns the install-config from the asset directory,
+// or nil if it cannot be loaded.
+func loadInstallConfig(directory string) *types.InstallConfig {
+	assetStore, err := assetstore.NewStore(directory)
+	if err != nil {
+		return nil
+	}
+	ic, err := assetStore.Load(&installconfig.InstallConfig{})
+	if err != nil || ic == nil {
+		return nil
+	}
+	return ic.(*installconfig.InstallConfig).Config
+}
+
+// loadKubeconfig returns the client config of the admin kubeconfig in the
+// asset directory, which is decrypted when it was encrypted with the state.
+func loadKubeconfig(directory string) (*rest.Config, error) {
+	data, err := assetstore.ReadFile(filepath.Join(directory, "auth", "kubeconfig"))
+	if err != nil {
+		return nil, err
+	}
+	return clientcmd.RESTConfigFromKubeConfig(data)
+}
+
+// waitTimeout returns how long to wait for the phase: the --wait-timeout
+// flag, else the waitTimeouts of the install-config, else defaultTimeout.
+func waitTimeout(phase string, ic *types.InstallConfig, defaultTimeout time.Duration) time.Duration {
+	if d, ok := waitTimeouts[phase]; ok {
+		return d
+	}
+	if ic != nil && ic.WaitTimeouts != nil {
+		var d *metav1.Duration
+		switch phase {
+		case bootstrapCompletePhase:
+			d = ic.WaitTimeouts.BootstrapComplete
+		case clusterOperatorsStablePhase:
+			d = ic.WaitTimeouts.ClusterOperatorsStable
+		case consoleRoutePhase:
+			d = ic.WaitTimeouts.ConsoleRoute
+		}
+		if d != nil {
+			return d.Duration
+		}
+	}
+	return defaultTimeout
+}
+
 func newWaitForCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "wait-for",
@@ -27,6 +150,9 @@ them directly.`,
 	}
 	cmd.AddCommand(newWaitForBootstrapCompleteCmd())
 	cmd.AddCommand(newWaitForInstallCompleteCmd())
+	cmd.AddCommand(newWaitForOperatorsStableCmd())
+	cmd.AddCommand(newWaitForNodesReadyCmd())
+	addWaitTimeoutFlag(cmd)
 	return cmd
 }
 
@@ -42,7 +168,7 @@ func newWaitForBootstrapCompleteCmd() *cobra.Command {
 			cleanup := setupFileHook(rootOpts.dir)
 			defer cleanup()
 
-			config, err := clientcmd.BuildConfigFromFlags("", filepath.Join(rootOpts.dir, "auth", "kubeconfig"))
+			config, err := loadKubeconfig(rootOpts.dir)
 			if err != nil {
 				logrus.Fatal(errors.Wrap(err, "loading kubeconfig"))
 			}
@@ -56,7 +182,7 @@ func newWaitForBootstrapCompleteCmd() *cobra.Command {
 				logrus.Info("openshift-install gather bootstrap --help")
 				logrus.Error("Bootstrap failed to complete: ", err.Unwrap())
 				logrus.Error(err.Error())
-				logrus.Exit(exitCodeBootstrapFailed)
+				exitWithCode(exitCodeBootstrapFailed)
 			}
 
 			logrus.Info("It is now safe to remove the bootstrap resources")
@@ -79,7 +205,7 @@ func newWaitForInstallCompleteCmd() *cobra.Command {
 			cleanup := setupFileHook(rootOpts.dir)
 			defer cleanup()
 
-			config, err := clientcmd.BuildConfigFromFlags("", filepath.Join(rootOpts.dir, "auth", "kubeconfig"))
+			config, err := loadKubeconfig(rootOpts.dir)
 			if err != nil {
 				logrus.Fatal(errors.Wrap(err, "loading kubeconfig"))
 			}
@@ -91,10 +217,249 @@ func newWaitForInstallCompleteCmd() *cobra.Command {
 				}
 				logTroubleshootingLink()
 				logrus.Error(err)
-				logrus.Exit(exitCodeInstallFailed)
+				exitWithCode(exitCodeInstallFailed)
 			}
 			timer.StopTimer(timer.TotalTimeElapsed)
 			timer.LogSummary()
 		},
 	}
 }
+
+// settleOpts are the flags of the wait-for targets that wait for a set of
+// components to settle.
+type settleOpts struct {
+	settlePeriod time.Duration
+	output       string
+}
+
+func (o *settleOpts) addFlags(cmd *cobra.Command) {
+	cmd.Flags().DurationVar(&o.settlePeriod, "settle-period", 2*time.Minute, "how long every component must stay ready before the wait succeeds")
+	cmd.Flags().StringVar(&o.output, "output", "text", "format of the final status: text or json")
+}
+
+func newWaitForOperatorsStableCmd() *cobra.Command {
+	opts := &settleOpts{}
+	cmd := &cobra.Command{
+		Use:   "operators-stable",
+		Short: "Wait until all cluster operators are available and no longer progressing",
+		Args:  cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			runWaitForSettled(opts, operatorsStablePhase, "cluster operators", listClusterOperatorStatuses)
+		},
+	}
+	opts.addFlags(cmd)
+	return cmd
+}
+
+func newWaitForNodesReadyCmd() *cobra.Command {
+	opts := &settleOpts{}
+	cmd := &cobra.Command{
+		Use:   "nodes-ready",
+		Short: "Wait until all nodes are ready",
+		Args:  cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			runWaitForSettled(opts, nodesReadyPhase, "nodes", listNodeStatuses)
+		},
+	}
+	opts.addFlags(cmd)
+	return cmd
+}
+
+// componentStatus is the readiness of a cluster operator or a node.
+type componentStatus struct {
+	Name    string `json:"name"`
+	Ready   bool   `json:"ready"`
+	Message string `json:"message,omitempty"`
+}
+
+// settleStatus is the outcome of waiting for a set of components to settle.
+type settleStatus struct {
+	Target     string            `json:"target"`
+	Settled    bool              `json:"settled"`
+	ReadySince *metav1.Time      `json:"readySince,omitempty"`
+	Components []componentStatus `json:"components"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// statusFunc returns the readiness of the components to wait for.
+type statusFunc func(ctx context.Context, config *rest.Config) ([]componentStatus, error)
+
+func runWaitForSettled(opts *settleOpts, phase, components string, statuses statusFunc) {
+	if opts.output != "text" && opts.output != "json" {
+		logrus.Fatalf("invalid --output %q, must be text or json", opts.output)
+	}
+
+	timer.StartTimer(timer.TotalTimeElapsed)
+	ctx := context.Background()
+
+	cleanup := setupFileHook(rootOpts.dir)
+	defer cleanup()
+
+	config, err := loadKubeconfig(rootOpts.dir)
+	if err != nil {
+		logrus.Fatal(errors.Wrap(err, "loading kubeconfig"))
+	}
+
+	timeout := waitTimeout(phase, nil, 30*time.Minute)
+	logrus.Infof("Waiting up to %v for all %s to be ready for %v...", timeout, components, opts.settlePeriod)
+	status := waitForSettled(ctx, config, timeout, opts.settlePeriod, statuses)
+	status.Target = phase
+
+	if opts.output == "json" {
+		if err := writeSettleStatus(os.Stdout, status); err != nil {
+			logrus.Fatal(err)
+		}
+	} else {
+		for _, c := range status.Components {
+			if !c.Ready {
+				logrus.Infof("%s is not ready: %s", c.Name, c.Message)
+			}
+		}
+	}
+	if !status.Settled {
+		logrus.Errorf("The %s did not settle: %s", components, status.Error)
+		exitWithCode(exitCodeInstallFailed)
+	}
+	logrus.Infof("All %d %s are ready", len(status.Components), components)
+	timer.StopTimer(timer.TotalTimeElapsed)
+	timer.LogSummary()
+}
+
+// writeSettleStatus writes the status as indented JSON.
+func writeSettleStatus(w io.Writer, status *settleStatus) error {
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshaling the status")
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// settlePollInterval is how often waitForSettled polls the components.
+var settlePollInterval = 10 * time.Second
+
+// waitForSettled polls the components until all of them have been ready for
+// the settle period, or the timeout expires. A component which is not ready
+// restarts the settle period, and so does an empty list of components.
+func waitForSettled(ctx context.Context, config *rest.Config, timeout, settlePeriod time.Duration, statuses statusFunc) *settleStatus {
+	status := &settleStatus{}
+	err := wait.PollImmediate(settlePollInterval, timeout, func() (bool, error) {
+		components, err := statuses(ctx, config)
+		if err != nil {
+			logrus.Debugf("Still waiting: %v", err)
+			status.Error = err.Error()
+			return false, nil
+		}
+		status.Components = components
+		status.Error = ""
+		if len(components) == 0 {
+			status.Error = "none were found"
+		}
+		if !allReady(components) {
+			status.ReadySince = nil
+			return false, nil
+		}
+		if status.ReadySince == nil {
+			now := metav1.Now()
+			status.ReadySince = &now
+			logrus.Debugf("All %d components are ready, waiting %v for them to settle", len(components), settlePeriod)
+		}
+		return time.Since(status.ReadySince.Time) >= settlePeriod, nil
+	})
+	if err != nil {
+		if status.Error == "" {
+			status.Error = err.Error()
+		}
+		return status
+	}
+	status.Settled = true
+	return status
+}
+
+// allReady returns whether there are components and all of them are ready.
+func allReady(components []componentStatus) bool {
+	if len(components) == 0 {
+		return false
+	}
+	for _, c := range components {
+		if !c.Ready {
+			return false
+		}
+	}
+	return true
+}
+
+// listClusterOperatorStatuses returns the readiness of the cluster operators.
+func listClusterOperatorStatuses(ctx context.Context, config *rest.Config) ([]componentStatus, error) {
+	client, err := configclient.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating a config client")
+	}
+	operators, err := client.ConfigV1().ClusterOperators().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing ClusterOperator objects")
+	}
+	return clusterOperatorStatuses(operators.Items), nil
+}
+
+// clusterOperatorStatuses reports a cluster operator as ready when it is
+// available, not progressing and not degraded.
+func clusterOperatorStatuses(operators []configv1.ClusterOperator) []componentStatus {
+	statuses := make([]componentStatus, 0, len(operators))
+	for _, operator := range operators {
+		status := componentStatus{Name: operator.Name, Ready: true}
+		conditions := operator.Status.Conditions
+		switch {
+		case !cov1helpers.IsStatusConditionTrue(conditions, configv1.OperatorAvailable):
+			status.Ready = false
+			status.Message = conditionMessage(cov1helpers.FindStatusCondition(conditions, configv1.OperatorAvailable), "not available")
+		case cov1helpers.IsStatusConditionTrue(conditions, configv1.OperatorProgressing):
+			status.Ready = false
+			status.Message = conditionMessage(cov1helpers.FindStatusCondition(conditions, configv1.OperatorProgressing), "progressing")
+		case cov1helpers.IsStatusConditionTrue(conditions, configv1.OperatorDegraded):
+			status.Ready = false
+			status.Message = conditionMessage(cov1helpers.FindStatusCondition(conditions, configv1.OperatorDegraded), "degraded")
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func conditionMessage(condition *configv1.ClusterOperatorStatusCondition, fallback string) string {
+	if condition == nil || condition.Message == "" {
+		return fallback
+	}
+	return fmt.Sprintf("%s: %s", fallback, condition.Message)
+}
+
+// listNodeStatuses returns the readiness of the nodes.
+func listNodeStatuses(ctx context.Context, config *rest.Config) ([]componentStatus, error) {
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating a Kubernetes client")
+	}
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing Node objects")
+	}
+	return nodeStatuses(nodes.Items), nil
+}
+
+// nodeStatuses reports a node as ready when its Ready condition is true.
+func nodeStatuses(nodes []corev1.Node) []componentStatus {
+	statuses := make([]componentStatus, 0, len(nodes))
+	for _, node := range nodes {
+		status := componentStatus{Name: node.Name, Message: "no Ready condition"}
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady {
+				status.Ready = condition.Status == corev1.ConditionTrue
+				status.Message = condition.Message
+				if status.Ready {
+					status.Message = ""
+				}
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+func operatorCondition(conditionType configv1.ClusterStatusConditionType, status configv1.ConditionStatus, message string) configv1.ClusterOperatorStatusCondition {
+	return configv1.ClusterOperatorStatusCondition{Type: conditionType, Status: status, Message: message}
+}
+
+func TestClusterOperatorStatuses(t *testing.T) {
+	cases := []struct {
+		name       string
+		conditions []configv1.ClusterOperatorStatusCondition
+		expected   componentStatus
+	}{
+		{
+			name: "available",
+			conditions: []configv1.ClusterOperatorStatusCondition{
+				operatorCondition(configv1.OperatorAvailable, configv1.ConditionTrue, ""),
+				operatorCondition(configv1.OperatorProgressing, configv1.ConditionFalse, ""),
+				operatorCondition(configv1.OperatorDegraded, configv1.ConditionFalse, ""),
+			},
+			expected: componentStatus{Name: "test", Ready: true},
+		},
+		{
+			name: "available without progressing and degraded",
+			conditions: []configv1.ClusterOperatorStatusCondition{
+				operatorCondition(configv1.OperatorAvailable, configv1.ConditionTrue, ""),
+			},
+			expected: componentStatus{Name: "test", Ready: true},
+		},
+		{
+			name:     "no conditions",
+			expected: componentStatus{Name: "test", Message: "not available"},
+		},
+		{
+			name: "unavailable",
+			conditions: []configv1.ClusterOperatorStatusCondition{
+				operatorCondition(configv1.OperatorAvailable, configv1.ConditionFalse, "no pods"),
+				operatorCondition(configv1.OperatorProgressing, configv1.ConditionTrue, "rolling out"),
+				operatorCondition(configv1.OperatorDegraded, configv1.ConditionTrue, "crash looping"),
+			},
+			expected: componentStatus{Name: "test", Message: "not available: no pods"},
+		},
+		{
+			name: "unknown availability",
+			conditions: []configv1.ClusterOperatorStatusCondition{
+				operatorCondition(configv1.OperatorAvailable, configv1.ConditionUnknown, ""),
+			},
+			expected: componentStatus{Name: "test", Message: "not available"},
+		},
+		{
+			name: "progressing",
+			conditions: []configv1.ClusterOperatorStatusCondition{
+				operatorCondition(configv1.OperatorAvailable, configv1.ConditionTrue, ""),
+				operatorCondition(configv1.OperatorProgressing, configv1.ConditionTrue, "rolling out"),
+				operatorCondition(configv1.OperatorDegraded, configv1.ConditionTrue, "crash looping"),
+			},
+			expected: componentStatus{Name: "test", Message: "progressing: rolling out"},
+		},
+		{
+			name: "degraded",
+			conditions: []configv1.ClusterOperatorStatusCondition{
+				operatorCondition(configv1.OperatorAvailable, configv1.ConditionTrue, ""),
+				operatorCondition(configv1.OperatorProgressing, configv1.ConditionFalse, ""),
+				operatorCondition(configv1.OperatorDegraded, configv1.ConditionTrue, "crash looping"),
+			},
+			expected: componentStatus{Name: "test", Message: "degraded: crash looping"},
+		},
+		{
+			name: "degraded without message",
+			conditions: []configv1.ClusterOperatorStatusCondition{
+				operatorCondition(configv1.OperatorAvailable, configv1.ConditionTrue, ""),
+				operatorCondition(configv1.OperatorDegraded, configv1.ConditionTrue, ""),
+			},
+			expected: componentStatus{Name: "test", Message: "degraded"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			operator := configv1.ClusterOperator{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Status:     configv1.ClusterOperatorStatus{Conditions: tc.conditions},
+			}
+			assert.Equal(t, []componentStatus{tc.expected}, clusterOperatorStatuses([]configv1.ClusterOperator{operator}))
+		})
+	}
+}
+
+func TestNodeStatuses(t *testing.T) {
+	cases := []struct {
+		name       string
+		conditions []corev1.NodeCondition
+		expected   componentStatus
+	}{
+		{
+			name: "ready",
+			conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse},
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue, Message: "kubelet is posting ready status"},
+			},
+			expected: componentStatus{Name: "test", Ready: true},
+		},
+		{
+			name: "not ready",
+			conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionFalse, Message: "container runtime network not ready"},
+			},
+			expected: componentStatus{Name: "test", Message: "container runtime network not ready"},
+		},
+		{
+			name: "unknown",
+			conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionUnknown, Message: "kubelet stopped posting node status"},
+			},
+			expected: componentStatus{Name: "test", Message: "kubelet stopped posting node status"},
+		},
+		{
+			name: "no ready condition",
+			conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse},
+			},
+			expected: componentStatus{Name: "test", Message: "no Ready condition"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			node := corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Status:     corev1.NodeStatus{Conditions: tc.conditions},
+			}
+			assert.Equal(t, []componentStatus{tc.expected}, nodeStatuses([]corev1.Node{node}))
+		})
+	}
+}
+
+// fakeStatuses returns the statuses of its polls in turn, and the last one
+// once they are exhausted.
+type fakeStatuses struct {
+	mu    sync.Mutex
+	polls []func() ([]componentStatus, error)
+	times []time.Time
+}
+
+func (f *fakeStatuses) statuses(context.Context, *rest.Config) ([]componentStatus, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.times = append(f.times, time.Now())
+	poll := f.polls[0]
+	if len(f.polls) > 1 {
+		f.polls = f.polls[1:]
+	}
+	return poll()
+}
+
+func components(ready ...bool) func() ([]componentStatus, error) {
+	return func() ([]componentStatus, error) {
+		statuses := make([]componentStatus, 0, len(ready))
+		for i, r := range ready {
+			status := componentStatus{Name: string(rune('a' + i)), Ready: r}
+			if !r {
+				status.Message = "not available"
+			}
+			statuses = append(statuses, status)
+		}
+		return statuses, nil
+	}
+}
+
+func failure(message string) func() ([]componentStatus, error) {
+	return func() ([]componentStatus, error) {
+		return nil, errors.New(message)
+	}
+}
+
+func TestWaitForSettled(t *testing.T) {
+	previous := settlePollInterval
+	settlePollInterval = 10 * time.Millisecond
+	defer func() { settlePollInterval = previous }()
+
+	cases := []struct {
+		name       string
+		polls      []func() ([]componentStatus, error)
+		settled    bool
+		components []componentStatus
+		readySince bool
+		err        string
+	}{
+		{
+			name:       "ready",
+			polls:      []func() ([]componentStatus, error){components(true, true)},
+			settled:    true,
+			components: []componentStatus{{Name: "a", Ready: true}, {Name: "b", Ready: true}},
+			readySince: true,
+		},
+		{
+			name:       "becomes ready",
+			polls:      []func() ([]componentStatus, error){components(true, false), failure("connection refused"), components(true, true)},
+			settled:    true,
+			components: []componentStatus{{Name: "a", Ready: true}, {Name: "b", Ready: true}},
+			readySince: true,
+		},
+		{
+			name:       "never ready",
+			polls:      []func() ([]componentStatus, error){components(true, false)},
+			components: []componentStatus{{Name: "a", Ready: true}, {Name: "b", Message: "not available"}},
+			err:        "timed out waiting for the condition",
+		},
+		{
+			name:       "no components",
+			polls:      []func() ([]componentStatus, error){components()},
+			components: []componentStatus{},
+			err:        "none were found",
+		},
+		{
+			// the failures to poll the components do not restart the
+			// settle period, but the components do not settle meanwhile
+			name:       "failing",
+			polls:      []func() ([]componentStatus, error){components(true), failure("connection refused")},
+			components: []componentStatus{{Name: "a", Ready: true}},
+			readySince: true,
+			err:        "connection refused",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := &fakeStatuses{polls: tc.polls}
+			status := waitForSettled(context.Background(), nil, 500*time.Millisecond, 100*time.Millisecond, fake.statuses)
+			assert.Equal(t, tc.settled, status.Settled)
+			assert.Equal(t, tc.components, status.Components)
+			assert.Equal(t, tc.err, status.Error)
+			assert.Equal(t, tc.readySince, status.ReadySince != nil)
+		})
+	}
+}
+
+func TestWaitForSettledRestartsSettlePeriod(t *testing.T) {
+	previous := settlePollInterval
+	settlePollInterval = 10 * time.Millisecond
+	defer func() { settlePollInterval = previous }()
+
+	// the components are ready, then one of them is not, then none is
+	// found, and then they are ready for good
+	fake := &fakeStatuses{polls: []func() ([]componentStatus, error){
+		components(true, true),
+		components(true, true),
+		components(true, false),
+		components(true, true),
+		components(),
+		components(true, true),
+	}}
+	settlePeriod := 200 * time.Millisecond
+	status := waitForSettled(context.Background(), nil, 5*time.Second, settlePeriod, fake.statuses)
+	if !assert.True(t, status.Settled) {
+		return
+	}
+
+	// the settle period started over after the last poll without ready
+	// components
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	assert.True(t, status.ReadySince.Time.After(fake.times[4]), "the settle period did not restart")
+	assert.True(t, time.Since(status.ReadySince.Time) >= settlePeriod, "the components did not settle for the settle period")
+}
+
+func TestWriteSettleStatus(t *testing.T) {
+	readySince := metav1.NewTime(time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC))
+	cases := []struct {
+		name     string
+		status   *settleStatus
+		expected string
+	}{
+		{
+			name: "settled",
+			status: &settleStatus{
+				Target:     operatorsStablePhase,
+				Settled:    true,
+				ReadySince: &readySince,
+				Components: []componentStatus{{Name: "dns", Ready: true}},
+			},
+			expected: `{
+  "target": "operators-stable",
+  "settled": true,
+  "readySince": "2022-01-02T03:04:05Z",
+  "components": [
+    {
+      "name": "dns",
+      "ready": true
+    }
+  ]
+}
+`,
+		},
+		{
+			name: "not settled",
+			status: &settleStatus{
+				Target:     nodesReadyPhase,
+				Components: []componentStatus{{Name: "master-0", Message: "container runtime network not ready"}},
+				Error:      "timed out waiting for the condition",
+			},
+			expected: `{
+  "target": "nodes-ready",
+  "settled": false,
+  "components": [
+    {
+      "name": "master-0",
+      "ready": false,
+      "message": "container runtime network not ready"
+    }
+  ],
+  "error": "timed out waiting for the condition"
+}
+`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, writeSettleStatus(&buf, tc.status))
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
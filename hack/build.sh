@@ -24,6 +24,19 @@ copy_terraform_to_mirror() {
 
   mkdir -p "${PWD}/pkg/terraform/providers/mirror/terraform/"
   cp "${PWD}/terraform/bin/${TARGET_OS_ARCH}/terraform" "${PWD}/pkg/terraform/providers/mirror/terraform/"
+
+  # Record the upstream versions of terraform and the providers for `openshift-install version`.
+  {
+    printf '{"terraform":"%s","providers":{' "$(awk '/^require github.com\/hashicorp\/terraform / {print $3}' terraform/terraform/go.mod)"
+    separator=""
+    for goMod in terraform/providers/*/go.mod; do
+      providerName="$(basename "$(dirname "${goMod}")")"
+      providerVersion="$(awk '/^require [^(]/ {print $3; exit}' "${goMod}")"
+      printf '%s"%s":"%s"' "${separator}" "${providerName}" "${providerVersion:-local}"
+      separator=","
+    done
+    printf '}}\n'
+  } > "${PWD}/pkg/terraform/providers/mirror/versions.json"
 }
 
 minimum_go_version=1.18
@@ -67,7 +80,7 @@ dev)
 	exit 1
 esac
 
-if (echo "${TAGS}" | grep -q 'libvirt')
+if (echo "${TAGS}" | grep -q 'libvirt\|fipscapable')
 then
 	export CGO_ENABLED=1
 fi
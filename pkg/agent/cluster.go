@@ -12,6 +12,7 @@ import (
 
 	"github.com/openshift/assisted-service/client/installer"
 	"github.com/openshift/assisted-service/models"
+	assetstore "github.com/openshift/installer/pkg/asset/store"
 )
 
 // Cluster is a struct designed to help interact with the cluster that is
@@ -24,6 +25,11 @@ type Cluster struct {
 	clusterID              *strfmt.UUID
 	clusterInfraEnvID      *strfmt.UUID
 	installHistory         *clusterInstallStatusHistory
+	progress               *ClusterProgress
+
+	// OnProgress, when set, is called with the progress of the installation
+	// every time it changes.
+	OnProgress func(progress *ClusterProgress)
 }
 
 type clientSet struct {
@@ -109,6 +115,7 @@ func NewCluster(ctx context.Context, assetDir string) (*Cluster, error) {
 	czero.clusterConsoleRouteURL = ""
 	czero.installHistory = cinstallstatushistory
 	czero.installHistory.ValidationResults = cvalidationresults
+	czero.progress = &ClusterProgress{Phase: ProgressPhaseBootstrap}
 	return czero, nil
 }
 
@@ -206,6 +213,7 @@ func (czero *Cluster) IsBootstrapComplete() (bool, bool, error) {
 		}
 
 		czero.PrintInstallStatus(clusterMetadata)
+		czero.updateProgress(clusterMetadata)
 
 		if *clusterMetadata.Status == models.ClusterStatusReady {
 			stuck, err := czero.IsClusterStuckInReady()
@@ -405,6 +413,13 @@ func (czero *Cluster) PrintInstallationComplete() error {
 		return err
 	}
 	logrus.Info("Install complete!")
+	// The credentials encrypted with the state are not printed in plaintext.
+	if assetstore.Encrypted(pw) {
+		logrus.Infof("To access the cluster as the system:admin user when using 'oc', decrypt the kubeconfig with\n    openshift-install --dir %s state decrypt auth/kubeconfig", absDir)
+		logrus.Infof("Access the OpenShift web-console here: %s", czero.clusterConsoleRouteURL)
+		logrus.Infof("Login to the console with user: %q, and the password of 'openshift-install --dir %s state decrypt auth/kubeadmin-password'", "kubeadmin", absDir)
+		return nil
+	}
 	logrus.Infof("To access the cluster as the system:admin user when using 'oc', run\n    export KUBECONFIG=%s", kubeconfig)
 	logrus.Infof("Access the OpenShift web-console here: %s", czero.clusterConsoleRouteURL)
 	logrus.Infof("Login to the console with user: %q, and password: %q", "kubeadmin", pw)
@@ -10,6 +10,8 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+
+	assetstore "github.com/openshift/installer/pkg/asset/store"
 )
 
 // ClusterKubeAPIClient is a kube client to interact with the cluster that agent installer is installing.
@@ -26,7 +28,7 @@ func NewClusterKubeAPIClient(ctx context.Context, assetDir string) (*ClusterKube
 	kubeClient := &ClusterKubeAPIClient{}
 
 	kubeconfigpath := filepath.Join(assetDir, "auth", "kubeconfig")
-	kubeconfig, err := clientcmd.BuildConfigFromFlags("", kubeconfigpath)
+	kubeconfig, err := loadKubeconfig(kubeconfigpath)
 	if err != nil {
 		return nil, errors.Wrap(err, "error loading kubeconfig from assets")
 	}
@@ -44,6 +46,16 @@ func NewClusterKubeAPIClient(ctx context.Context, assetDir string) (*ClusterKube
 	return kubeClient, nil
 }
 
+// loadKubeconfig returns the client config of the kubeconfig at path, which
+// is decrypted when it was encrypted with the state.
+func loadKubeconfig(path string) (*rest.Config, error) {
+	data, err := assetstore.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return clientcmd.RESTConfigFromKubeConfig(data)
+}
+
 // IsKubeAPILive Determine if the cluster under install has initailized the kubenertes API.
 func (kube *ClusterKubeAPIClient) IsKubeAPILive() (bool, error) {
 
@@ -59,7 +71,7 @@ func (kube *ClusterKubeAPIClient) IsKubeAPILive() (bool, error) {
 // DoesKubeConfigExist Determine if the kubeconfig for the cluster can be used without errors.
 func (kube *ClusterKubeAPIClient) DoesKubeConfigExist() (bool, error) {
 
-	_, err := clientcmd.LoadFromFile(kube.configPath)
+	_, err := loadKubeconfig(kube.configPath)
 	if err != nil {
 		return false, errors.Wrap(err, "error loading kubeconfig from file")
 	}
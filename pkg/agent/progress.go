@@ -0,0 +1,194 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/assisted-service/api/common"
+	"github.com/openshift/assisted-service/models"
+)
+
+// Phases of the installation reported in the cluster progress.
+const (
+	ProgressPhaseBootstrap = "bootstrap"
+	ProgressPhaseInstall   = "install"
+	ProgressPhaseComplete  = "complete"
+	ProgressPhaseFailed    = "failed"
+)
+
+// ClusterProgress is the progress of the cluster installation, as last
+// reported by the Agent Rest API.
+type ClusterProgress struct {
+	Phase             string              `json:"phase"`
+	Status            string              `json:"status,omitempty"`
+	StatusInfo        string              `json:"statusInfo,omitempty"`
+	FailedValidations []ValidationFailure `json:"failedValidations,omitempty"`
+	Hosts             []HostProgress      `json:"hosts,omitempty"`
+	Error             string              `json:"error,omitempty"`
+}
+
+// HostProgress is the progress of the installation of a host.
+type HostProgress struct {
+	Hostname          string              `json:"hostname"`
+	Role              string              `json:"role,omitempty"`
+	Status            string              `json:"status,omitempty"`
+	StatusInfo        string              `json:"statusInfo,omitempty"`
+	Stage             string              `json:"stage,omitempty"`
+	Percentage        int64               `json:"percentage,omitempty"`
+	FailedValidations []ValidationFailure `json:"failedValidations,omitempty"`
+}
+
+// ValidationFailure is a validation of the cluster or of a host which
+// failed.
+type ValidationFailure struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// newClusterProgress returns the progress of the cluster described by the
+// metadata of the Agent Rest API, in the given phase. The hosts are sorted by
+// hostname.
+func newClusterProgress(phase string, cluster *models.Cluster) (*ClusterProgress, error) {
+	progress := &ClusterProgress{Phase: phase}
+	if cluster.Status != nil {
+		progress.Status = *cluster.Status
+	}
+	if cluster.StatusInfo != nil {
+		progress.StatusInfo = *cluster.StatusInfo
+	}
+	failures, err := failedValidations(cluster.ValidationsInfo)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid cluster validations")
+	}
+	progress.FailedValidations = failures
+
+	for _, h := range cluster.Hosts {
+		host := HostProgress{
+			Hostname: h.RequestedHostname,
+			Role:     string(h.Role),
+		}
+		if h.Status != nil {
+			host.Status = *h.Status
+		}
+		if h.StatusInfo != nil {
+			host.StatusInfo = *h.StatusInfo
+		}
+		if h.Progress != nil {
+			host.Stage = string(h.Progress.CurrentStage)
+			host.Percentage = h.Progress.InstallationPercentage
+		}
+		failures, err := failedValidations(h.ValidationsInfo)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid validations of host %s", h.RequestedHostname)
+		}
+		host.FailedValidations = failures
+		progress.Hosts = append(progress.Hosts, host)
+	}
+	sort.Slice(progress.Hosts, func(i, j int) bool {
+		return progress.Hosts[i].Hostname < progress.Hosts[j].Hostname
+	})
+	return progress, nil
+}
+
+// failedValidations returns the failed validations of the validations info
+// of the cluster or of a host, sorted by ID.
+func failedValidations(validationsInfo string) ([]ValidationFailure, error) {
+	if validationsInfo == "" {
+		return nil, nil
+	}
+	validationsStatus := common.ValidationsStatus{}
+	if err := json.Unmarshal([]byte(validationsInfo), &validationsStatus); err != nil {
+		return nil, err
+	}
+	var failures []ValidationFailure
+	for _, results := range validationsStatus {
+		for _, r := range results {
+			switch r.Status {
+			case validationFailure, validationError:
+				failures = append(failures, ValidationFailure{ID: r.ID, Status: r.Status, Message: r.Message})
+			}
+		}
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].ID < failures[j].ID
+	})
+	return failures, nil
+}
+
+// logHostProgress logs the hosts whose status or installation stage changed
+// since the previous progress, and the failure of the hosts in error.
+func logHostProgress(previous, current *ClusterProgress, log logrus.FieldLogger) {
+	seen := map[string]HostProgress{}
+	if previous != nil {
+		for _, h := range previous.Hosts {
+			seen[h.Hostname] = h
+		}
+	}
+	for _, h := range current.Hosts {
+		if p, ok := seen[h.Hostname]; ok && p.Status == h.Status && p.Stage == h.Stage && p.Percentage == h.Percentage {
+			continue
+		}
+		switch {
+		case h.Status == models.HostStatusError:
+			log.Errorf("Host %s failed: %s", h.Hostname, h.StatusInfo)
+		case h.Stage != "":
+			log.Infof("Host %s: %s", h.Hostname, hostStageMessage(h))
+		default:
+			log.Debugf("Host %s: %s", h.Hostname, h.Status)
+		}
+	}
+}
+
+// hostStageMessage returns the installation stage of the host, with its
+// percentage when known.
+func hostStageMessage(h HostProgress) string {
+	if h.Percentage > 0 {
+		return fmt.Sprintf("%s (%d%%)", h.Stage, h.Percentage)
+	}
+	return h.Stage
+}
+
+// Progress returns a copy of the last progress of the installation.
+func (czero *Cluster) Progress() *ClusterProgress {
+	progress := *czero.progress
+	return &progress
+}
+
+// updateProgress updates the progress of the installation from the metadata
+// of the Agent Rest API, logs the hosts which progressed and reports the
+// progress when it changed.
+func (czero *Cluster) updateProgress(cluster *models.Cluster) {
+	progress, err := newClusterProgress(czero.progress.Phase, cluster)
+	if err != nil {
+		logrus.Debug(errors.Wrap(err, "unable to determine the installation progress"))
+		return
+	}
+	logHostProgress(czero.progress, progress, logrus.StandardLogger())
+	if !reflect.DeepEqual(progress, czero.progress) {
+		czero.progress = progress
+		czero.reportProgress()
+	}
+}
+
+// setProgressPhase moves the installation progress to the phase and reports
+// it.
+func (czero *Cluster) setProgressPhase(phase string) {
+	if czero.progress.Phase == phase {
+		return
+	}
+	czero.progress = czero.Progress()
+	czero.progress.Phase = phase
+	czero.reportProgress()
+}
+
+func (czero *Cluster) reportProgress() {
+	if czero.OnProgress != nil {
+		czero.OnProgress(czero.Progress())
+	}
+}
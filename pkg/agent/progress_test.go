@@ -0,0 +1,100 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/assisted-service/models"
+)
+
+func TestNewClusterProgress(t *testing.T) {
+	clusterStatus := models.ClusterStatusInstalling
+	installing := models.HostStatusInstallingInProgress
+	failed := models.HostStatusError
+	failedInfo := "Failed to write image to disk"
+	cluster := &models.Cluster{
+		Status:          &clusterStatus,
+		ValidationsInfo: validationsInfoSuccess,
+		Hosts: []*models.Host{
+			{
+				RequestedHostname: "master-1",
+				Role:              models.HostRoleMaster,
+				Status:            &failed,
+				StatusInfo:        &failedInfo,
+				ValidationsInfo:   validationsInfoFailure,
+			},
+			{
+				RequestedHostname: "master-0",
+				Role:              models.HostRoleMaster,
+				Status:            &installing,
+				Progress: &models.HostProgressInfo{
+					CurrentStage:           models.HostStageWritingImageToDisk,
+					InstallationPercentage: 45,
+				},
+			},
+		},
+	}
+
+	progress, err := newClusterProgress(ProgressPhaseBootstrap, cluster)
+	assert.NoError(t, err)
+	assert.Equal(t, &ClusterProgress{
+		Phase:  ProgressPhaseBootstrap,
+		Status: models.ClusterStatusInstalling,
+		Hosts: []HostProgress{
+			{
+				Hostname:   "master-0",
+				Role:       "master",
+				Status:     models.HostStatusInstallingInProgress,
+				Stage:      string(models.HostStageWritingImageToDisk),
+				Percentage: 45,
+			},
+			{
+				Hostname:   "master-1",
+				Role:       "master",
+				Status:     models.HostStatusError,
+				StatusInfo: "Failed to write image to disk",
+				FailedValidations: []ValidationFailure{{
+					ID:      "test",
+					Status:  "failure",
+					Message: "The validation failed",
+				}},
+			},
+		},
+	}, progress)
+}
+
+func TestNewClusterProgressInvalidValidations(t *testing.T) {
+	_, err := newClusterProgress(ProgressPhaseBootstrap, &models.Cluster{ValidationsInfo: "{"})
+	assert.EqualError(t, err, "invalid cluster validations: unexpected end of JSON input")
+}
+
+func TestLogHostProgress(t *testing.T) {
+	previous := &ClusterProgress{
+		Hosts: []HostProgress{
+			{Hostname: "master-0", Status: models.HostStatusInstallingInProgress, Stage: "Writing image to disk", Percentage: 45},
+			{Hostname: "master-1", Status: models.HostStatusInstallingInProgress, Stage: "Rebooting"},
+		},
+	}
+	current := &ClusterProgress{
+		Hosts: []HostProgress{
+			{Hostname: "master-0", Status: models.HostStatusInstallingInProgress, Stage: "Writing image to disk", Percentage: 45},
+			{Hostname: "master-1", Status: models.HostStatusError, Stage: "Rebooting", StatusInfo: "Host failed to reboot"},
+			{Hostname: "master-2", Status: models.HostStatusInstallingInProgress, Stage: "Writing image to disk", Percentage: 10},
+		},
+	}
+
+	logger, hook := test.NewNullLogger()
+	logHostProgress(previous, current, logger)
+
+	var messages []string
+	for _, entry := range hook.AllEntries() {
+		messages = append(messages, entry.Level.String()+": "+entry.Message)
+	}
+	assert.Equal(t, []string{
+		logrus.ErrorLevel.String() + ": Host master-1 failed: Host failed to reboot",
+		logrus.InfoLevel.String() + ": Host master-2: Writing image to disk (10%)",
+	}, messages)
+}
@@ -61,6 +61,8 @@ func WaitForBootstrapComplete(cluster *Cluster) error {
 // WaitForInstallComplete Waits for the cluster installation triggered by the
 // agent installer to be complete.
 func WaitForInstallComplete(cluster *Cluster) error {
+	cluster.setProgressPhase(ProgressPhaseInstall)
+
 	timeout := 90 * time.Minute
 	waitContext, cancel := context.WithTimeout(cluster.Ctx, timeout)
 	defer cancel()
@@ -1,9 +1,12 @@
 package agentconfig
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -11,8 +14,10 @@ import (
 	"sigs.k8s.io/yaml"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/strictyaml"
 	"github.com/openshift/installer/pkg/types/agent"
 	"github.com/openshift/installer/pkg/types/agent/conversion"
+	"github.com/openshift/installer/pkg/types/baremetal"
 	"github.com/openshift/installer/pkg/validate"
 )
 
@@ -66,6 +71,11 @@ hosts:
   # https://docs.openshift.com/container-platform/4.10/installing/installing_bare_metal_ipi/ipi-install-installation-workflow.html#root-device-hints_ipi-install-installation-workflow
   rootDeviceHints:
     deviceName: /dev/sda
+  # rootDevice is needed when the root device is attached over iSCSI or
+  # Fibre Channel (transport: iSCSI or FC), or through multiple paths.
+  # rootDevice:
+  #   transport: FC
+  #   multipath: true
   # interfaces are used to identify the host to apply this configuration to
   interfaces:
     - macAddress: 00:00:00:00:00:00
@@ -125,7 +135,7 @@ func (a *AgentConfig) Load(f asset.FileFetcher) (bool, error) {
 	}
 
 	config := &agent.Config{}
-	if err := yaml.UnmarshalStrict(file.Data, config); err != nil {
+	if err := strictyaml.Unmarshal(file.Data, config); err != nil {
 		return false, errors.Wrapf(err, "failed to unmarshal %s", agentConfigFilename)
 	}
 
@@ -169,6 +179,10 @@ func (a *AgentConfig) validateAgent() field.ErrorList {
 		allErrs = append(allErrs, err...)
 	}
 
+	if a.Config.PXE != nil {
+		allErrs = append(allErrs, a.validatePXE(field.NewPath("pxe"), a.Config.PXE)...)
+	}
+
 	return allErrs
 }
 
@@ -234,10 +248,10 @@ func (a *AgentConfig) validateHostInterfaces(hostPath *field.Path, host agent.Ho
 			allErrs = append(allErrs, field.Invalid(macAddressPath, mac, err.Error()))
 		}
 
-		if _, ok := macs[mac]; ok {
+		if _, ok := macs[strings.ToLower(mac)]; ok {
 			allErrs = append(allErrs, field.Invalid(macAddressPath, mac, "duplicate MAC address found"))
 		}
-		macs[mac] = true
+		macs[strings.ToLower(mac)] = true
 	}
 
 	return allErrs
@@ -255,6 +269,21 @@ func (a *AgentConfig) validateHostRootDeviceHints(hostPath *field.Path, host age
 		allErrs = append(allErrs, field.Forbidden(hostPath.Child("RootDeviceHints", "WWNVendorExtension"), "WWN vendor extensions are not supported in root device hints"))
 	}
 
+	if host.RootDevice != nil {
+		switch host.RootDevice.Transport {
+		case "", agent.RootDeviceTransportISCSI, agent.RootDeviceTransportFC:
+		default:
+			allErrs = append(allErrs, field.NotSupported(hostPath.Child("RootDevice", "Transport"), host.RootDevice.Transport,
+				[]string{string(agent.RootDeviceTransportISCSI), string(agent.RootDeviceTransportFC)}))
+		}
+
+		// without hints, a local disk of the host could be selected
+		if (host.RootDevice.Transport != "" || host.RootDevice.Multipath) && reflect.DeepEqual(host.RootDeviceHints, baremetal.RootDeviceHints{}) {
+			allErrs = append(allErrs, field.Required(hostPath.Child("RootDeviceHints"),
+				"root device hints must select the root device attached over the storage network"))
+		}
+	}
+
 	return allErrs
 }
 
@@ -302,6 +331,24 @@ func (a *AgentConfig) validateRendevousIPNotWorker(rendezvousIP string, hosts []
 	return allErrs
 }
 
+func (a *AgentConfig) validatePXE(pxePath *field.Path, pxe *agent.PXE) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if pxe.BaseURL != "" {
+		if u, err := url.Parse(pxe.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(pxePath.Child("baseURL"), pxe.BaseURL, "must be an http or https URL"))
+		}
+	}
+
+	switch pxe.ConfigImage {
+	case "", agent.PXEConfigImageEmbedded, agent.PXEConfigImageServed:
+	default:
+		allErrs = append(allErrs, field.NotSupported(pxePath.Child("configImage"), pxe.ConfigImage, []string{string(agent.PXEConfigImageEmbedded), string(agent.PXEConfigImageServed)}))
+	}
+
+	return allErrs
+}
+
 // HostConfigFileMap is a map from a filepath ("<host>/<file>") to file content
 // for hostconfig files.
 type HostConfigFileMap map[string][]byte
@@ -337,6 +384,14 @@ func (a *AgentConfig) HostConfigFiles() (HostConfigFileMap, error) {
 			files[filepath.Join(name, "root-device-hints.yaml")] = rdh
 		}
 
+		if args := rootDeviceInstallerArgs(host.RootDevice); len(args) > 0 {
+			data, err := json.Marshal(args)
+			if err != nil {
+				return nil, err
+			}
+			files[filepath.Join(name, "installer-args.json")] = data
+		}
+
 		if len(host.Role) > 0 {
 			files[filepath.Join(name, "role")] = []byte(host.Role)
 		}
@@ -344,6 +399,28 @@ func (a *AgentConfig) HostConfigFiles() (HostConfigFileMap, error) {
 	return files, nil
 }
 
+// rootDeviceInstallerArgs returns the coreos-installer arguments adding the
+// kernel arguments the installed host needs to mount its root device.
+func rootDeviceInstallerArgs(rootDevice *agent.RootDevice) []string {
+	if rootDevice == nil {
+		return nil
+	}
+
+	var kargs []string
+	if rootDevice.Transport == agent.RootDeviceTransportISCSI {
+		kargs = append(kargs, "rd.iscsi.firmware=1")
+	}
+	if rootDevice.Multipath {
+		kargs = append(kargs, "rd.multipath=default", "root=/dev/disk/by-label/dm-mpath-root", "rw")
+	}
+
+	var args []string
+	for _, karg := range kargs {
+		args = append(args, "--append-karg", karg)
+	}
+	return args
+}
+
 func unmarshalJSON(b []byte) []byte {
 	output, _ := yaml.JSONToYAML(b)
 	return output
@@ -133,7 +133,7 @@ metadata:
 wrongField: wrongValue`,
 
 			expectedFound: false,
-			expectedError: "failed to unmarshal agent-config.yaml: error unmarshaling JSON: while decoding JSON: json: unknown field \"wrongField\"",
+			expectedError: "failed to unmarshal agent-config.yaml: line 5, column 1: unknown field \"wrongField\"",
 		},
 		{
 			name: "interface-missing-mac-address-error",
@@ -280,6 +280,24 @@ hosts:
 			expectedFound: false,
 			expectedError: "invalid Agent Config configuration: Hosts[1].Interfaces[0].macAddress: Invalid value: \"28:d2:44:d2:b2:1a\": duplicate MAC address found",
 		},
+		{
+			name: "mac-addresses-are-compared-case-insensitively",
+			data: `
+apiVersion: v1alpha1
+metadata:
+  name: agent-config-cluster0
+rendezvousIP: 192.168.111.80
+hosts:
+  - interfaces:
+      - name: enp3s1
+        macAddress: 28:d2:44:d2:b2:1a
+  - interfaces:
+      - name: enp3s1
+        macAddress: 28:D2:44:D2:B2:1A`,
+
+			expectedFound: false,
+			expectedError: "invalid Agent Config configuration: Hosts[1].Interfaces[0].macAddress: Invalid value: \"28:D2:44:D2:B2:1A\": duplicate MAC address found",
+		},
 		{
 			name: "invalid-mac",
 			data: `
@@ -316,6 +334,19 @@ rendezvousIP: not-a-valid-ip`,
 			expectedFound: false,
 			expectedError: "invalid Agent Config configuration: rendezvousIP: Invalid value: \"not-a-valid-ip\": \"not-a-valid-ip\" is not a valid IP",
 		},
+		{
+			name: "invalid-pxe",
+			data: `
+apiVersion: v1alpha1
+metadata:
+  name: agent-config-cluster0
+pxe:
+  baseURL: tftp://192.168.111.1/agent
+  configImage: Downloaded`,
+
+			expectedFound: false,
+			expectedError: "invalid Agent Config configuration: [pxe.baseURL: Invalid value: \"tftp://192.168.111.1/agent\": must be an http or https URL, pxe.configImage: Unsupported value: \"Downloaded\": supported values: \"Embedded\", \"Served\"]",
+		},
 		{
 			name: "invalid-additionalNTPSourceDomain",
 			data: `
@@ -405,6 +436,67 @@ hosts:
 			expectedFound: false,
 			expectedError: "invalid Agent Config configuration: Hosts[0].Host: Forbidden: Host host0 is not of role 'master' and has the rendevousIP assigned to it. The rendevousIP must be assigned to a host of role 'master'",
 		},
+		{
+			name: "multipath-fc-root-device",
+			data: `
+apiVersion: v1alpha1
+metadata:
+  name: agent-config-cluster0
+rendezvousIP: 192.168.111.80
+hosts:
+  - interfaces:
+      - name: enp3s1
+        macAddress: 28:d2:44:d2:b2:1a
+    rootDeviceHints:
+      wwn: "0x600a098038314d4f6a2b4d7a4f6e4b33"
+    rootDevice:
+      transport: FC
+      multipath: true`,
+
+			expectedFound: true,
+			expectedConfig: agentConfig().hosts(
+				agentHost().
+					interfaces(iface("enp3s1", "28:d2:44:d2:b2:1a")).
+					rootDeviceHints(baremetal.RootDeviceHints{WWN: "0x600a098038314d4f6a2b4d7a4f6e4b33"}).
+					rootDevice(agent.RootDeviceTransportFC, true),
+			),
+		},
+		{
+			name: "unsupported-root-device-transport",
+			data: `
+apiVersion: v1alpha1
+metadata:
+  name: agent-config-cluster0
+rendezvousIP: 192.168.111.80
+hosts:
+  - interfaces:
+      - name: enp3s1
+        macAddress: 28:d2:44:d2:b2:1a
+    rootDeviceHints:
+      deviceName: /dev/sdb
+    rootDevice:
+      transport: NVMeoF`,
+
+			expectedFound: false,
+			expectedError: "invalid Agent Config configuration: Hosts[0].RootDevice.Transport: Unsupported value: \"NVMeoF\": supported values: \"iSCSI\", \"FC\"",
+		},
+		{
+			name: "iscsi-root-device-without-hints",
+			data: `
+apiVersion: v1alpha1
+metadata:
+  name: agent-config-cluster0
+rendezvousIP: 192.168.111.80
+hosts:
+  - interfaces:
+      - name: enp3s1
+        macAddress: 28:d2:44:d2:b2:1a
+    rootDevice:
+      transport: iSCSI`,
+
+			expectedFound: false,
+			expectedError: "invalid Agent Config configuration: Hosts[0].RootDeviceHints: Required value: root device hints must select the root device attached over the storage network",
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -529,6 +621,19 @@ func (ahb *AgentHostBuilder) networkConfig(raw string) *AgentHostBuilder {
 	return ahb
 }
 
+func (ahb *AgentHostBuilder) rootDeviceHints(hints baremetal.RootDeviceHints) *AgentHostBuilder {
+	ahb.Host.RootDeviceHints = hints
+	return ahb
+}
+
+func (ahb *AgentHostBuilder) rootDevice(transport agent.RootDeviceTransport, multipath bool) *AgentHostBuilder {
+	ahb.Host.RootDevice = &agent.RootDevice{
+		Transport: transport,
+		Multipath: multipath,
+	}
+	return ahb
+}
+
 // TODO: Create BaremetalRootDeviceHintsBuilder, for the current tests not required
 func (ahb *AgentHostBuilder) defaultRootDeviceHints() *AgentHostBuilder {
 	falseBool := false
@@ -563,3 +668,49 @@ func iface(name string, mac string) *InterfacetBuilder {
 func (ib *InterfacetBuilder) build() *aiv1beta1.Interface {
 	return &ib.Interface
 }
+
+func TestAgentConfig_HostConfigFilesInstallerArgs(t *testing.T) {
+	cases := []struct {
+		name         string
+		rootDevice   *agent.RootDevice
+		expectedArgs string
+	}{
+		{
+			name: "local",
+		},
+		{
+			name:       "fc",
+			rootDevice: &agent.RootDevice{Transport: agent.RootDeviceTransportFC},
+		},
+		{
+			name:         "fc-multipath",
+			rootDevice:   &agent.RootDevice{Transport: agent.RootDeviceTransportFC, Multipath: true},
+			expectedArgs: `["--append-karg","rd.multipath=default","--append-karg","root=/dev/disk/by-label/dm-mpath-root","--append-karg","rw"]`,
+		},
+		{
+			name:         "iscsi",
+			rootDevice:   &agent.RootDevice{Transport: agent.RootDeviceTransportISCSI},
+			expectedArgs: `["--append-karg","rd.iscsi.firmware=1"]`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := &AgentConfig{
+				Config: &agent.Config{
+					Hosts: []agent.Host{{
+						Hostname:   "control-0",
+						RootDevice: tc.rootDevice,
+					}},
+				},
+			}
+			files, err := a.HostConfigFiles()
+			assert.NoError(t, err)
+			args, ok := files["control-0/installer-args.json"]
+			if tc.expectedArgs == "" {
+				assert.False(t, ok)
+			} else {
+				assert.Equal(t, tc.expectedArgs, string(args))
+			}
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package image
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/ignition/v2/config/util"
+	igntypes "github.com/coreos/ignition/v2/config/v3_2/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/assisted-image-service/pkg/isoeditor"
+	"github.com/openshift/assisted-service/models"
+	"github.com/openshift/assisted-service/pkg/executer"
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/agent/joiner"
+	"github.com/openshift/installer/pkg/asset/ignition"
+	"github.com/openshift/installer/pkg/types/agent"
+)
+
+const (
+	addNodesISOFilename = "node.%s.iso"
+	addNodesPath        = "/etc/add-nodes"
+)
+
+// addNodeScript installs RHCOS on the host with the Ignition config of the
+// workers of the cluster, then reboots the host so that it joins the
+// cluster. The host is found among those of nodes-config.yaml by the MAC
+// addresses of its interfaces.
+const addNodeScript = `#!/bin/bash
+set -euo pipefail
+
+host_dir=""
+for address in /sys/class/net/*/address; do
+  mac=$(tr '[:upper:]' '[:lower:]' < "${address}")
+  if [ -f "/etc/add-nodes/macs/${mac}" ]; then
+    host_dir="/etc/add-nodes/hosts/$(cat "/etc/add-nodes/macs/${mac}")"
+    break
+  fi
+done
+if [ -z "${host_dir}" ]; then
+  echo "None of the MAC addresses of this host is listed in nodes-config.yaml" >&2
+  exit 1
+fi
+
+if [ -f "${host_dir}/installation-disk" ]; then
+  disk=$(cat "${host_dir}/installation-disk")
+else
+  disk=$(lsblk -dpno NAME,TYPE,RM | awk '$2 == "disk" && $3 == "0" {print $1; exit}')
+fi
+if [ -z "${disk}" ]; then
+  echo "No installation disk found" >&2
+  exit 1
+fi
+
+echo "Installing the host on ${disk}"
+coreos-installer install --copy-network --ignition-file "${host_dir}/config.ign" "${disk}"
+systemctl reboot
+`
+
+const addNodeUnit = `[Unit]
+Description=Install the host as a worker of the cluster
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+Type=oneshot
+ExecStart=/usr/local/bin/add-node.sh
+
+[Install]
+WantedBy=multi-user.target
+`
+
+const preNetworkManagerConfigUnit = `[Unit]
+Description=Apply the static network configuration of the host
+DefaultDependencies=no
+Before=NetworkManager.service
+
+[Service]
+Type=oneshot
+ExecStart=/usr/local/bin/pre-network-manager-config.sh
+
+[Install]
+WantedBy=NetworkManager.service
+`
+
+// AddNodesImage is the asset generating the bootable ISO which adds the hosts
+// of nodes-config.yaml as workers to an existing cluster.
+type AddNodesImage struct {
+	cpuArch  string
+	tmpPath  string
+	volumeID string
+}
+
+var _ asset.WritableAsset = (*AddNodesImage)(nil)
+
+// Name returns the human-friendly name of the asset.
+func (a *AddNodesImage) Name() string {
+	return "Add Nodes ISO"
+}
+
+// Dependencies returns the assets on which the ISO depends.
+func (a *AddNodesImage) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&joiner.ClusterInfo{},
+		&joiner.NodesConfig{},
+	}
+}
+
+// Generate generates the ISO in a temporary directory.
+func (a *AddNodesImage) Generate(dependencies asset.Parents) error {
+	clusterInfo := &joiner.ClusterInfo{}
+	nodesConfig := &joiner.NodesConfig{}
+	dependencies.Get(clusterInfo, nodesConfig)
+
+	config, err := addNodesIgnition(clusterInfo.WorkerIgnition, nodesConfig.Config)
+	if err != nil {
+		return err
+	}
+	ignitionBytes, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	baseISO, err := addNodesBaseISO(clusterInfo)
+	if err != nil {
+		return err
+	}
+
+	tmpPath, err := os.MkdirTemp("", "add-nodes")
+	if err != nil {
+		return err
+	}
+	a.tmpPath = tmpPath
+	if err := isoeditor.Extract(baseISO, a.tmpPath); err != nil {
+		return err
+	}
+	ca := NewCpioArchive()
+	if err := ca.StoreBytes("config.ign", ignitionBytes); err != nil {
+		return err
+	}
+	if err := ca.Save(filepath.Join(a.tmpPath, "images", "ignition.img")); err != nil {
+		return err
+	}
+	a.volumeID, err = isoeditor.VolumeIdentifier(baseISO)
+	if err != nil {
+		return err
+	}
+	a.cpuArch = archName
+	return nil
+}
+
+// addNodesBaseISO returns the RHCOS live ISO of the release of the cluster,
+// falling back to the one of the installer when it cannot be extracted.
+func addNodesBaseISO(clusterInfo *joiner.ClusterInfo) (string, error) {
+	release := NewRelease(&executer.CommonExecuter{},
+		Config{MaxTries: OcDefaultTries, RetryDelay: OcDefaultRetryDelay},
+		clusterInfo.ReleaseImage, clusterInfo.PullSecret, nil)
+	logrus.Info("Extracting base ISO from the release payload of the cluster")
+	iso, err := release.GetBaseIso(archName)
+	if err == nil {
+		return iso, nil
+	}
+	logrus.Warning(errors.Wrap(err, "failed to extract the base ISO from the release payload"))
+
+	logrus.Info("Downloading base ISO")
+	iso, err2 := newGetIso(GetIsoPluggable).getter()
+	if err2 != nil {
+		return "", errors.Wrap(err2, "failed to get base ISO image")
+	}
+	return iso, nil
+}
+
+// addNodesIgnition returns the Ignition config of the live ISO, which
+// installs each host with the worker Ignition config of the cluster.
+func addNodesIgnition(workerIgnition []byte, nodesConfig *agent.NodesConfig) (*igntypes.Config, error) {
+	config := &igntypes.Config{
+		Ignition: igntypes.Ignition{
+			Version: igntypes.MaxVersion.String(),
+		},
+	}
+	if nodesConfig.SSHKey != "" {
+		config.Passwd.Users = append(config.Passwd.Users, igntypes.PasswdUser{
+			Name:              "core",
+			SSHAuthorizedKeys: []igntypes.SSHAuthorizedKey{igntypes.SSHAuthorizedKey(nodesConfig.SSHKey)},
+		})
+	}
+
+	var staticNetworkConfigs []*models.HostStaticNetworkConfig
+	for i, host := range nodesConfig.Hosts {
+		hostDir := path.Join(addNodesPath, "hosts", strconv.Itoa(i))
+
+		hostIgnition := workerIgnition
+		if host.Hostname != "" {
+			var err error
+			hostIgnition, err = withHostname(workerIgnition, host.Hostname)
+			if err != nil {
+				return nil, err
+			}
+		}
+		config.Storage.Files = append(config.Storage.Files,
+			ignition.FileFromBytes(path.Join(hostDir, "config.ign"), "root", 0600, hostIgnition))
+		if disk := host.RootDeviceHints.DeviceName; disk != "" {
+			config.Storage.Files = append(config.Storage.Files,
+				ignition.FileFromString(path.Join(hostDir, "installation-disk"), "root", 0644, disk))
+		}
+
+		macInterfaceMap := models.MacInterfaceMap{}
+		for _, intf := range host.Interfaces {
+			config.Storage.Files = append(config.Storage.Files,
+				ignition.FileFromString(path.Join(addNodesPath, "macs", strings.ToLower(intf.MacAddress)), "root", 0644, strconv.Itoa(i)))
+			macInterfaceMap = append(macInterfaceMap, &models.MacInterfaceMapItems0{
+				MacAddress:     intf.MacAddress,
+				LogicalNicName: intf.Name,
+			})
+		}
+		if host.NetworkConfig.Raw != nil {
+			staticNetworkConfigs = append(staticNetworkConfigs, &models.HostStaticNetworkConfig{
+				MacInterfaceMap: macInterfaceMap,
+				NetworkYaml:     string(host.NetworkConfig.Raw),
+			})
+		}
+	}
+
+	config.Storage.Files = append(config.Storage.Files,
+		ignition.FileFromString("/usr/local/bin/add-node.sh", "root", 0755, addNodeScript))
+	config.Systemd.Units = append(config.Systemd.Units, igntypes.Unit{
+		Name:     "add-node.service",
+		Enabled:  util.BoolToPtr(true),
+		Contents: util.StrToPtr(addNodeUnit),
+	})
+
+	if len(staticNetworkConfigs) > 0 {
+		if err := addStaticNetworkConfig(config, staticNetworkConfigs); err != nil {
+			return nil, err
+		}
+		config.Systemd.Units = append(config.Systemd.Units, igntypes.Unit{
+			Name:     "pre-network-manager-config.service",
+			Enabled:  util.BoolToPtr(true),
+			Contents: util.StrToPtr(preNetworkManagerConfigUnit),
+		})
+	}
+
+	return config, nil
+}
+
+// withHostname returns the Ignition config with the hostname of the host
+// written to /etc/hostname.
+func withHostname(pointerIgnition []byte, hostname string) ([]byte, error) {
+	config := map[string]interface{}{}
+	if err := json.Unmarshal(pointerIgnition, &config); err != nil {
+		return nil, errors.Wrap(err, "failed to parse the worker Ignition config of the cluster")
+	}
+	storage, _ := config["storage"].(map[string]interface{})
+	if storage == nil {
+		storage = map[string]interface{}{}
+	}
+	files, _ := storage["files"].([]interface{})
+	storage["files"] = append(files, map[string]interface{}{
+		"path":      "/etc/hostname",
+		"mode":      0644,
+		"overwrite": true,
+		"contents": map[string]interface{}{
+			"source": "data:," + hostname,
+		},
+	})
+	config["storage"] = storage
+	return json.Marshal(config)
+}
+
+// PersistToFile writes the ISO in the assets directory.
+func (a *AddNodesImage) PersistToFile(directory string) error {
+	defer os.RemoveAll(a.tmpPath)
+
+	if a.tmpPath == "" || a.volumeID == "" {
+		return errors.New("cannot generate ISO image due to configuration errors")
+	}
+
+	isoFile := filepath.Join(directory, fmt.Sprintf(addNodesISOFilename, a.cpuArch))
+	os.Remove(isoFile)
+	if err := isoeditor.Create(isoFile, a.tmpPath, a.volumeID); err != nil {
+		return err
+	}
+	logrus.Infof("Boot the hosts to add with %s, then approve their certificate signing requests", isoFile)
+	return nil
+}
+
+// Load returns false, since the ISO is not needed by other assets.
+func (a *AddNodesImage) Load(asset.FileFetcher) (bool, error) {
+	return false, nil
+}
+
+// Files returns no files, since the ISO is written by PersistToFile.
+func (a *AddNodesImage) Files() []*asset.File {
+	return []*asset.File{}
+}
@@ -0,0 +1,67 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	aiv1beta1 "github.com/openshift/assisted-service/api/v1beta1"
+	"github.com/openshift/installer/pkg/types/agent"
+	"github.com/openshift/installer/pkg/types/baremetal"
+)
+
+const testWorkerIgnition = `{"ignition":{"config":{"merge":[{"source":"https://api-int.test-cluster.example.com:22623/config/worker"}]},"version":"3.2.0"}}`
+
+func TestAddNodesIgnition(t *testing.T) {
+	config, err := addNodesIgnition([]byte(testWorkerIgnition), &agent.NodesConfig{
+		SSHKey: "ssh-ed25519 AAAA",
+		Hosts: []agent.Host{
+			{
+				Hostname:        "worker-3",
+				RootDeviceHints: baremetal.RootDeviceHints{DeviceName: "/dev/sdb"},
+				Interfaces:      []*aiv1beta1.Interface{{Name: "eth0", MacAddress: "52:54:00:AA:BB:01"}},
+			},
+			{
+				Interfaces: []*aiv1beta1.Interface{{Name: "eth0", MacAddress: "52:54:00:aa:bb:02"}},
+			},
+		},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "core", config.Passwd.Users[0].Name)
+	files := map[string]string{}
+	for _, f := range config.Storage.Files {
+		files[f.Path] = *f.Contents.Source
+	}
+	assert.Equal(t, []string{
+		"/etc/add-nodes/hosts/0/config.ign",
+		"/etc/add-nodes/hosts/0/installation-disk",
+		"/etc/add-nodes/macs/52:54:00:aa:bb:01",
+		"/etc/add-nodes/hosts/1/config.ign",
+		"/etc/add-nodes/macs/52:54:00:aa:bb:02",
+		"/usr/local/bin/add-node.sh",
+	}, func() []string {
+		var paths []string
+		for _, f := range config.Storage.Files {
+			paths = append(paths, f.Path)
+		}
+		return paths
+	}())
+	assert.Equal(t, "data:text/plain;charset=utf-8;base64,MQ==", files["/etc/add-nodes/macs/52:54:00:aa:bb:02"])
+	assert.Len(t, config.Systemd.Units, 1)
+	assert.Equal(t, "add-node.service", config.Systemd.Units[0].Name)
+}
+
+func TestWithHostname(t *testing.T) {
+	config, err := withHostname([]byte(testWorkerIgnition), "worker-3")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+  "ignition": {"config": {"merge": [{"source": "https://api-int.test-cluster.example.com:22623/config/worker"}]}, "version": "3.2.0"},
+  "storage": {"files": [{"path": "/etc/hostname", "mode": 420, "overwrite": true, "contents": {"source": "data:,worker-3"}}]}
+}`, string(config))
+
+	_, err = withHostname([]byte("{"), "worker-3")
+	assert.EqualError(t, err, "failed to parse the worker Ignition config of the cluster: unexpected end of JSON input")
+}
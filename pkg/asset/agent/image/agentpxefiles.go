@@ -7,17 +7,21 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/assisted-image-service/pkg/isoeditor"
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/agent/agentconfig"
+	"github.com/openshift/installer/pkg/types/agent"
 )
 
 const (
 	initrdimg = "initrd"
 	rootfsimg = "rootfs"
 	vmlinuz   = "vmlinuz"
+	configimg = "config"
 	// pxeAssetsPath is the path where pxe files are created.
 	pxeAssetsPath = "pxe"
 )
@@ -27,6 +31,11 @@ type AgentPXEFiles struct {
 	imageReader isoeditor.ImageReader
 	cpuArch     string
 	isoPath     string
+	// ignition is the Ignition config of the agents, which is written to a
+	// separate config image when it is served rather than embedded in the
+	// initrd.
+	ignition []byte
+	pxe      agent.PXE
 }
 
 var _ asset.WritableAsset = (*AgentPXEFiles)(nil)
@@ -36,6 +45,7 @@ func (a *AgentPXEFiles) Dependencies() []asset.Asset {
 	return []asset.Asset{
 		&Ignition{},
 		&BaseIso{},
+		&agentconfig.AgentConfig{},
 	}
 }
 
@@ -47,7 +57,25 @@ func (a *AgentPXEFiles) Generate(dependencies asset.Parents) error {
 	baseImage := &BaseIso{}
 	dependencies.Get(baseImage)
 
+	agentConfig := &agentconfig.AgentConfig{}
+	dependencies.Get(agentConfig)
+
 	a.isoPath = baseImage.File.Filename
+	a.cpuArch = ignition.CPUArch
+	a.pxe = agent.PXE{}
+	if agentConfig.Config != nil && agentConfig.Config.PXE != nil {
+		a.pxe = *agentConfig.Config.PXE
+	}
+
+	ignitionByte, err := json.Marshal(ignition.Config)
+	if err != nil {
+		return err
+	}
+	a.ignition = ignitionByte
+	if a.pxe.ConfigImage == agent.PXEConfigImageServed {
+		// the initrd is copied from the ISO as is
+		return nil
+	}
 
 	tmpdir, err := os.MkdirTemp("", pxeAssetsPath)
 	if err != nil {
@@ -62,11 +90,6 @@ func (a *AgentPXEFiles) Generate(dependencies asset.Parents) error {
 		return err
 	}
 
-	ignitionByte, err := json.Marshal(ignition.Config)
-	if err != nil {
-		return err
-	}
-
 	ignitionContent := &isoeditor.IgnitionContent{Config: ignitionByte}
 
 	custom, err := isoeditor.NewInitRamFSStreamReader(dstfilename, ignitionContent)
@@ -75,20 +98,18 @@ func (a *AgentPXEFiles) Generate(dependencies asset.Parents) error {
 	}
 
 	a.imageReader = custom
-	a.cpuArch = ignition.CPUArch
 
 	return nil
 }
 
 // PersistToFile writes the PXE assets in the assets folder named pxe.
 func (a *AgentPXEFiles) PersistToFile(directory string) error {
-	// If the imageReader is not set then it means that either one of the AgentPXEFiles
+	// If the ignition is not set then it means that either one of the AgentPXEFiles
 	// dependencies or the asset itself failed for some reason
-	if a.imageReader == nil {
+	if a.ignition == nil {
 		return errors.New("cannot generate PXE assets due to configuration errors")
 	}
 
-	defer a.imageReader.Close()
 	pxeAssetsFullPath := filepath.Join(directory, pxeAssetsPath)
 
 	os.RemoveAll(pxeAssetsFullPath)
@@ -99,9 +120,28 @@ func (a *AgentPXEFiles) PersistToFile(directory string) error {
 	}
 
 	agentInitrdFile := filepath.Join(pxeAssetsFullPath, fmt.Sprintf("agent-%s.%s.img", initrdimg, a.cpuArch))
-	err = a.copy(agentInitrdFile, a.imageReader)
-	if err != nil {
-		return err
+	if a.pxe.ConfigImage == agent.PXEConfigImageServed {
+		err = a.extractPXEFileFromISO(a.isoPath, fmt.Sprintf("images/pxeboot/%s.img", initrdimg), agentInitrdFile)
+		if err != nil {
+			return err
+		}
+
+		// the live initramfs reads the Ignition config from /config.ign of
+		// the initrds, as with `coreos-installer pxe ignition wrap`
+		configImage := NewCpioArchive()
+		if err := configImage.StoreBytes("config.ign", a.ignition); err != nil {
+			return err
+		}
+		err = configImage.Save(filepath.Join(pxeAssetsFullPath, fmt.Sprintf("agent-%s.%s.img", configimg, a.cpuArch)))
+		if err != nil {
+			return err
+		}
+	} else {
+		defer a.imageReader.Close()
+		err = a.copy(agentInitrdFile, a.imageReader)
+		if err != nil {
+			return err
+		}
 	}
 
 	srcfilename := fmt.Sprintf("images/pxeboot/%s.img", rootfsimg)
@@ -118,6 +158,16 @@ func (a *AgentPXEFiles) PersistToFile(directory string) error {
 		return err
 	}
 
+	if a.pxe.BaseURL != "" {
+		script := ipxeScript(a.pxe.BaseURL, a.cpuArch, a.pxe.ConfigImage == agent.PXEConfigImageServed)
+		err = os.WriteFile(filepath.Join(pxeAssetsFullPath, fmt.Sprintf("agent.%s.ipxe", a.cpuArch)), []byte(script), 0644)
+		if err != nil {
+			return err
+		}
+	} else {
+		logrus.Info("Set pxe.baseURL in the agent config to generate an iPXE script")
+	}
+
 	logrus.Infof("PXE-files created in: %s", pxeAssetsFullPath)
 
 	return nil
@@ -169,3 +219,30 @@ func (a *AgentPXEFiles) copy(filepath string, src io.Reader) error {
 
 	return nil
 }
+
+// ipxeScript returns the iPXE script booting the PXE files of the
+// architecture from the base URL, loading the config image as a second
+// initrd when it is served.
+func ipxeScript(baseURL string, cpuArch string, servedConfig bool) string {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	initrds := []string{initrdimg}
+	if servedConfig {
+		initrds = append(initrds, configimg)
+	}
+
+	script := &strings.Builder{}
+	fmt.Fprintln(script, "#!ipxe")
+	kernelArgs := []string{}
+	for _, initrd := range initrds {
+		fmt.Fprintf(script, "initrd --name %s %s/agent-%s.%s.img\n", initrd, baseURL, initrd, cpuArch)
+		kernelArgs = append(kernelArgs, "initrd="+initrd)
+	}
+	kernelArgs = append(kernelArgs,
+		fmt.Sprintf("coreos.live.rootfs_url=%s/agent-%s.%s.img", baseURL, rootfsimg, cpuArch),
+		"ignition.firstboot",
+		"ignition.platform.id=metal",
+	)
+	fmt.Fprintf(script, "kernel %s/agent-%s.%s %s\n", baseURL, vmlinuz, cpuArch, strings.Join(kernelArgs, " "))
+	fmt.Fprintln(script, "boot")
+	return script.String()
+}
@@ -0,0 +1,42 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPXEScript(t *testing.T) {
+	cases := []struct {
+		name         string
+		baseURL      string
+		servedConfig bool
+		expected     string
+	}{
+		{
+			name:    "embedded config",
+			baseURL: "http://192.168.111.1/agent/",
+			expected: `#!ipxe
+initrd --name initrd http://192.168.111.1/agent/agent-initrd.x86_64.img
+kernel http://192.168.111.1/agent/agent-vmlinuz.x86_64 initrd=initrd coreos.live.rootfs_url=http://192.168.111.1/agent/agent-rootfs.x86_64.img ignition.firstboot ignition.platform.id=metal
+boot
+`,
+		},
+		{
+			name:         "served config",
+			baseURL:      "http://192.168.111.1/agent",
+			servedConfig: true,
+			expected: `#!ipxe
+initrd --name initrd http://192.168.111.1/agent/agent-initrd.x86_64.img
+initrd --name config http://192.168.111.1/agent/agent-config.x86_64.img
+kernel http://192.168.111.1/agent/agent-vmlinuz.x86_64 initrd=initrd initrd=config coreos.live.rootfs_url=http://192.168.111.1/agent/agent-rootfs.x86_64.img ignition.firstboot ignition.platform.id=metal
+boot
+`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ipxeScript(tc.baseURL, "x86_64", tc.servedConfig))
+		})
+	}
+}
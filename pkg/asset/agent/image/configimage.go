@@ -0,0 +1,227 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	igntypes "github.com/coreos/ignition/v2/config/v3_2/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/vincent-petithory/dataurl"
+
+	"github.com/openshift/assisted-image-service/pkg/isoeditor"
+	"github.com/openshift/installer/pkg/asset"
+)
+
+const (
+	configImageFilename = "agentconfig.noarch.iso"
+	// configImageVolumeLabel is the label of the config image, by which the
+	// generic ISO finds it once it is attached to the host.
+	configImageVolumeLabel = "agent_configimage"
+	// configImageArchiveFilename is the archive of the files of the config
+	// image, which is extracted to the root of the host.
+	configImageArchiveFilename = "config.tar"
+	configImagePath            = "/etc/assisted/config-image"
+	systemdUnitsPath           = "/etc/systemd/system"
+)
+
+// ConfigImage is the asset generating the small per-cluster image which
+// configures the hosts booted with the generic ISO. It contains the files,
+// systemd units and core user settings of the agent Ignition config.
+type ConfigImage struct {
+	rendezvousIP string
+	archive      []byte
+}
+
+var _ asset.WritableAsset = (*ConfigImage)(nil)
+
+// Name returns the human-friendly name of the asset.
+func (a *ConfigImage) Name() string {
+	return "Agent Config Image"
+}
+
+// Dependencies returns the assets on which the config image depends.
+func (a *ConfigImage) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&Ignition{},
+	}
+}
+
+// Generate generates the archive of the config image.
+func (a *ConfigImage) Generate(dependencies asset.Parents) error {
+	ignition := &Ignition{}
+	dependencies.Get(ignition)
+
+	archive, err := configImageArchive(ignition.Config)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the config image")
+	}
+	a.archive = archive
+	a.rendezvousIP = ignition.RendezvousIP
+	return nil
+}
+
+// configImageArchive returns the tar archive of the files, links, systemd
+// units and core user settings of the Ignition config, laid out as they are
+// written to the root of the host. The units to enable are listed in
+// configImagePath/units.
+func configImageArchive(config *igntypes.Config) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+
+	// parent directories are not added, so that tar creates the missing ones
+	// and leaves the existing ones, such as the /usr/local symlink, as they are
+	addFile := func(filePath string, mode int, owner string, contents []byte) error {
+		if err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     strings.TrimPrefix(filePath, "/"),
+			Mode:     int64(mode),
+			Uname:    owner,
+			Size:     int64(len(contents)),
+		}); err != nil {
+			return err
+		}
+		_, err := tw.Write(contents)
+		return err
+	}
+
+	for _, file := range config.Storage.Files {
+		if len(file.Append) > 0 {
+			return nil, errors.Errorf("appending to %s is not supported in a config image", file.Path)
+		}
+		var contents []byte
+		if file.Contents.Source != nil {
+			decoded, err := dataurl.DecodeString(*file.Contents.Source)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to decode the contents of %s", file.Path)
+			}
+			contents = decoded.Data
+		}
+		mode := 0644
+		if file.Mode != nil {
+			mode = *file.Mode
+		}
+		if err := addFile(file.Path, mode, nodeOwner(file.Node), contents); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, link := range config.Storage.Links {
+		typeflag := byte(tar.TypeSymlink)
+		if link.Hard != nil && *link.Hard {
+			typeflag = tar.TypeLink
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Typeflag: typeflag,
+			Name:     strings.TrimPrefix(link.Path, "/"),
+			Linkname: link.Target,
+			Uname:    nodeOwner(link.Node),
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	var enabledUnits []string
+	for _, unit := range config.Systemd.Units {
+		if unit.Contents != nil {
+			if err := addFile(path.Join(systemdUnitsPath, unit.Name), 0644, "root", []byte(*unit.Contents)); err != nil {
+				return nil, err
+			}
+		}
+		for _, dropin := range unit.Dropins {
+			if dropin.Contents == nil {
+				continue
+			}
+			if err := addFile(path.Join(systemdUnitsPath, unit.Name+".d", dropin.Name), 0644, "root", []byte(*dropin.Contents)); err != nil {
+				return nil, err
+			}
+		}
+		if unit.Enabled != nil && *unit.Enabled {
+			enabledUnits = append(enabledUnits, unit.Name)
+		}
+	}
+	sort.Strings(enabledUnits)
+	if err := addFile(path.Join(configImagePath, "units"), 0644, "root", []byte(strings.Join(enabledUnits, "\n")+"\n")); err != nil {
+		return nil, err
+	}
+
+	for _, user := range config.Passwd.Users {
+		if user.Name != "core" {
+			return nil, errors.Errorf("only the core user can be configured by a config image, not %s", user.Name)
+		}
+		var keys []string
+		for _, key := range user.SSHAuthorizedKeys {
+			if key != "" {
+				keys = append(keys, string(key))
+			}
+		}
+		if len(keys) > 0 {
+			if err := addFile("/home/core/.ssh/authorized_keys.d/config-image", 0600, "core", []byte(strings.Join(keys, "\n")+"\n")); err != nil {
+				return nil, err
+			}
+		}
+		if user.PasswordHash != nil && *user.PasswordHash != "" {
+			if err := addFile(path.Join(configImagePath, "core-password-hash"), 0600, "root", []byte(*user.PasswordHash)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func nodeOwner(node igntypes.Node) string {
+	if node.User.Name != nil && *node.User.Name != "" {
+		return *node.User.Name
+	}
+	return "root"
+}
+
+// PersistToFile writes the config image in the assets directory.
+func (a *ConfigImage) PersistToFile(directory string) error {
+	if a.archive == nil {
+		return errors.New("cannot generate the config image due to configuration errors")
+	}
+
+	tmpPath, err := os.MkdirTemp("", "agent-config-image")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpPath)
+
+	if err := os.WriteFile(filepath.Join(tmpPath, configImageArchiveFilename), a.archive, 0600); err != nil {
+		return err
+	}
+
+	configImageFile := filepath.Join(directory, configImageFilename)
+	os.Remove(configImageFile)
+	if err := isoeditor.Create(configImageFile, tmpPath, configImageVolumeLabel); err != nil {
+		return err
+	}
+
+	err = os.WriteFile(filepath.Join(directory, "rendezvousIP"), []byte(a.rendezvousIP), 0o644) //nolint:gosec // no sensitive info
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("Attach %s to the hosts booted with the generic agent ISO to install the cluster", configImageFile)
+	return nil
+}
+
+// Load returns false, since the config image is not needed by other assets.
+func (a *ConfigImage) Load(asset.FileFetcher) (bool, error) {
+	return false, nil
+}
+
+// Files returns no files, since the config image is written by PersistToFile.
+func (a *ConfigImage) Files() []*asset.File {
+	return []*asset.File{}
+}
@@ -0,0 +1,116 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/coreos/ignition/v2/config/util"
+	igntypes "github.com/coreos/ignition/v2/config/v3_2/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset/ignition"
+)
+
+func TestConfigImageArchive(t *testing.T) {
+	cases := []struct {
+		name          string
+		config        *igntypes.Config
+		expectedFiles map[string]string
+		expectedModes map[string]int64
+		expectedError string
+	}{
+		{
+			name: "files, units and core user",
+			config: &igntypes.Config{
+				Passwd: igntypes.Passwd{
+					Users: []igntypes.PasswdUser{{
+						Name:              "core",
+						SSHAuthorizedKeys: []igntypes.SSHAuthorizedKey{"ssh-ed25519 AAAA"},
+						PasswordHash:      util.StrToPtr("$2a$10$hash"),
+					}},
+				},
+				Storage: igntypes.Storage{
+					Files: []igntypes.File{
+						ignition.FileFromString("/etc/assisted/rendezvous-host.env", "root", 0644, "NODE_ZERO_IP=192.168.111.80\n"),
+						ignition.FileFromString("/usr/local/bin/start-cluster-installation.sh", "root", 0755, "#!/bin/bash\n"),
+					},
+				},
+				Systemd: igntypes.Systemd{
+					Units: []igntypes.Unit{
+						{Name: "node-zero.service", Enabled: util.BoolToPtr(true), Contents: util.StrToPtr("[Unit]\n")},
+						{Name: "agent.service", Enabled: util.BoolToPtr(true), Contents: util.StrToPtr("[Unit]\n")},
+						{Name: "zincati.service", Dropins: []igntypes.Dropin{{Name: "disable.conf", Contents: util.StrToPtr("[Unit]\n")}}},
+					},
+				},
+			},
+			expectedFiles: map[string]string{
+				"etc/assisted/rendezvous-host.env":                  "NODE_ZERO_IP=192.168.111.80\n",
+				"usr/local/bin/start-cluster-installation.sh":       "#!/bin/bash\n",
+				"etc/systemd/system/node-zero.service":              "[Unit]\n",
+				"etc/systemd/system/agent.service":                  "[Unit]\n",
+				"etc/systemd/system/zincati.service.d/disable.conf": "[Unit]\n",
+				"etc/assisted/config-image/units":                   "agent.service\nnode-zero.service\n",
+				"home/core/.ssh/authorized_keys.d/config-image":     "ssh-ed25519 AAAA\n",
+				"etc/assisted/config-image/core-password-hash":      "$2a$10$hash",
+			},
+			expectedModes: map[string]int64{
+				"usr/local/bin/start-cluster-installation.sh":   0755,
+				"home/core/.ssh/authorized_keys.d/config-image": 0600,
+			},
+		},
+		{
+			name: "appended file",
+			config: &igntypes.Config{
+				Storage: igntypes.Storage{
+					Files: []igntypes.File{{
+						Node:          igntypes.Node{Path: "/etc/motd"},
+						FileEmbedded1: igntypes.FileEmbedded1{Append: []igntypes.Resource{{Source: util.StrToPtr("data:,hello")}}},
+					}},
+				},
+			},
+			expectedError: "appending to /etc/motd is not supported in a config image",
+		},
+		{
+			name: "other user",
+			config: &igntypes.Config{
+				Passwd: igntypes.Passwd{Users: []igntypes.PasswdUser{{Name: "admin"}}},
+			},
+			expectedError: "only the core user can be configured by a config image, not admin",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			archive, err := configImageArchive(tc.config)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			files := map[string]string{}
+			modes := map[string]int64{}
+			tr := tar.NewReader(bytes.NewReader(archive))
+			for {
+				header, err := tr.Next()
+				if err == io.EOF {
+					break
+				}
+				if !assert.NoError(t, err) {
+					return
+				}
+				data, err := io.ReadAll(tr)
+				assert.NoError(t, err)
+				files[header.Name] = string(data)
+				modes[header.Name] = header.Mode
+			}
+			assert.Equal(t, tc.expectedFiles, files)
+			for name, mode := range tc.expectedModes {
+				assert.Equal(t, mode, modes[name], name)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package image
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/coreos/ignition/v2/config/util"
+	igntypes "github.com/coreos/ignition/v2/config/v3_2/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/assisted-image-service/pkg/isoeditor"
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/ignition"
+)
+
+const (
+	genericISOFilename = "agent-generic.%s.iso"
+)
+
+// loadConfigImageScript waits for the config image to be attached to the
+// host, extracts its files to the root of the host and starts its systemd
+// units.
+const loadConfigImageScript = `#!/bin/bash
+set -euo pipefail
+
+device=/dev/disk/by-label/` + configImageVolumeLabel + `
+until [ -b "${device}" ]; do
+  echo "Waiting for the config image to be attached"
+  sleep 10
+done
+
+mnt=$(mktemp -d)
+mount -o ro "${device}" "${mnt}"
+tar --extract --same-owner --preserve-permissions --directory / --file "${mnt}/` + configImageArchiveFilename + `"
+umount "${mnt}"
+rmdir "${mnt}"
+
+if [ -f ` + configImagePath + `/core-password-hash ]; then
+  usermod --password "$(cat ` + configImagePath + `/core-password-hash)" core
+fi
+
+systemctl daemon-reload
+if [ -f ` + systemdUnitsPath + `/pre-network-manager-config.service ]; then
+  # NetworkManager is already running, so it is restarted to apply the
+  # static network configuration of the host
+  systemctl start pre-network-manager-config.service
+  systemctl restart NetworkManager.service
+fi
+while read -r unit; do
+  systemctl enable --now --no-block "${unit}"
+done < ` + configImagePath + `/units
+`
+
+const loadConfigImageUnit = `[Unit]
+Description=Load the cluster configuration from the agent config image
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=/usr/local/bin/load-config-image.sh
+
+[Install]
+WantedBy=multi-user.target
+`
+
+// GenericImage is the asset generating the cluster-agnostic agent ISO, which
+// hardware vendors can write to the hosts at the factory. The hosts wait for
+// the config image of the cluster, generated by ConfigImage, to be attached
+// before starting the installation. The agent-tui is not included, since it
+// is extracted from the release image of the cluster.
+type GenericImage struct {
+	cpuArch  string
+	tmpPath  string
+	volumeID string
+}
+
+var _ asset.WritableAsset = (*GenericImage)(nil)
+
+// Name returns the human-friendly name of the asset.
+func (a *GenericImage) Name() string {
+	return "Agent Generic ISO"
+}
+
+// Dependencies returns no dependencies, since the ISO is not specific to a
+// cluster.
+func (a *GenericImage) Dependencies() []asset.Asset {
+	return []asset.Asset{}
+}
+
+// Generate generates the ISO in a temporary directory.
+func (a *GenericImage) Generate(asset.Parents) error {
+	ignitionBytes, err := json.Marshal(genericIgnition())
+	if err != nil {
+		return err
+	}
+
+	logrus.Info("Downloading base ISO")
+	baseISO, err := newGetIso(GetIsoPluggable).getter()
+	if err != nil {
+		return errors.Wrap(err, "failed to get base ISO image")
+	}
+
+	tmpPath, err := os.MkdirTemp("", "agent-generic")
+	if err != nil {
+		return err
+	}
+	a.tmpPath = tmpPath
+	if err := isoeditor.Extract(baseISO, a.tmpPath); err != nil {
+		return err
+	}
+	ca := NewCpioArchive()
+	if err := ca.StoreBytes("config.ign", ignitionBytes); err != nil {
+		return err
+	}
+	if err := ca.Save(filepath.Join(a.tmpPath, "images", "ignition.img")); err != nil {
+		return err
+	}
+	a.volumeID, err = isoeditor.VolumeIdentifier(baseISO)
+	if err != nil {
+		return err
+	}
+	a.cpuArch = archName
+	return nil
+}
+
+// genericIgnition returns the Ignition config of the generic ISO, which only
+// loads the config image.
+func genericIgnition() *igntypes.Config {
+	config := &igntypes.Config{
+		Ignition: igntypes.Ignition{
+			Version: igntypes.MaxVersion.String(),
+		},
+	}
+	config.Storage.Files = append(config.Storage.Files,
+		ignition.FileFromString("/usr/local/bin/load-config-image.sh", "root", 0755, loadConfigImageScript))
+	config.Systemd.Units = append(config.Systemd.Units, igntypes.Unit{
+		Name:     "load-config-image.service",
+		Enabled:  util.BoolToPtr(true),
+		Contents: util.StrToPtr(loadConfigImageUnit),
+	})
+	return config
+}
+
+// PersistToFile writes the ISO in the assets directory.
+func (a *GenericImage) PersistToFile(directory string) error {
+	defer os.RemoveAll(a.tmpPath)
+
+	if a.tmpPath == "" || a.volumeID == "" {
+		return errors.New("cannot generate ISO image due to configuration errors")
+	}
+
+	isoFile := filepath.Join(directory, fmt.Sprintf(genericISOFilename, a.cpuArch))
+	os.Remove(isoFile)
+	return isoeditor.Create(isoFile, a.tmpPath, a.volumeID)
+}
+
+// Load returns false, since the ISO is not needed by other assets.
+func (a *GenericImage) Load(asset.FileFetcher) (bool, error) {
+	return false, nil
+}
+
+// Files returns no files, since the ISO is written by PersistToFile.
+func (a *GenericImage) Files() []*asset.File {
+	return []*asset.File{}
+}
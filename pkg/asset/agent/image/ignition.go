@@ -244,6 +244,8 @@ func (a *Ignition) Generate(dependencies asset.Parents) error {
 
 	addHostConfig(&config, agentConfigAsset)
 
+	addISCSIConfig(&config, agentConfigAsset)
+
 	err = addExtraManifests(&config, extraManifests)
 	if err != nil {
 		return err
@@ -381,6 +383,42 @@ func addHostConfig(config *igntypes.Config, agentConfig *agentconfig.AgentConfig
 	return nil
 }
 
+// iscsiFirmwareLoginUnit logs in to the iSCSI targets of the iSCSI Boot
+// Firmware Table, so that the agent finds the root device of the hosts
+// attached over iSCSI. It fails on hosts without the table, which is ignored.
+const iscsiFirmwareLoginUnit = `[Unit]
+Description=Log in to the iSCSI targets of the firmware
+Wants=network-online.target
+After=network-online.target
+Before=agent.service
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=-/usr/sbin/iscsiadm --mode fw --login
+
+[Install]
+WantedBy=multi-user.target
+`
+
+// addISCSIConfig enables the login to the iSCSI targets of the firmware when
+// the root device of a host is attached over iSCSI.
+func addISCSIConfig(config *igntypes.Config, agentConfig *agentconfig.AgentConfig) {
+	if agentConfig.Config == nil {
+		return
+	}
+	for _, host := range agentConfig.Config.Hosts {
+		if host.RootDevice != nil && host.RootDevice.Transport == agent.RootDeviceTransportISCSI {
+			config.Systemd.Units = append(config.Systemd.Units, igntypes.Unit{
+				Name:     "iscsi-firmware-login.service",
+				Enabled:  util.BoolToPtr(true),
+				Contents: util.StrToPtr(iscsiFirmwareLoginUnit),
+			})
+			return
+		}
+	}
+}
+
 func addExtraManifests(config *igntypes.Config, extraManifests *manifests.ExtraManifests) error {
 
 	user := "root"
@@ -299,6 +299,51 @@ func TestAddHostConfig_Roles(t *testing.T) {
 
 }
 
+func TestAddISCSIConfig(t *testing.T) {
+	cases := []struct {
+		name          string
+		agentConfig   *agentconfig.AgentConfig
+		expectedUnits int
+	}{
+		{
+			name:        "no-agent-config",
+			agentConfig: &agentconfig.AgentConfig{},
+		},
+		{
+			name: "fc-root-devices",
+			agentConfig: &agentconfig.AgentConfig{
+				Config: &agent.Config{
+					Hosts: []agent.Host{
+						{RootDevice: &agent.RootDevice{Transport: agent.RootDeviceTransportFC, Multipath: true}},
+					},
+				},
+			},
+		},
+		{
+			name: "iscsi-root-devices",
+			agentConfig: &agentconfig.AgentConfig{
+				Config: &agent.Config{
+					Hosts: []agent.Host{
+						{RootDevice: &agent.RootDevice{Transport: agent.RootDeviceTransportISCSI}},
+						{RootDevice: &agent.RootDevice{Transport: agent.RootDeviceTransportISCSI, Multipath: true}},
+					},
+				},
+			},
+			expectedUnits: 1,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &igntypes.Config{}
+			addISCSIConfig(config, tc.agentConfig)
+			assert.Len(t, config.Systemd.Units, tc.expectedUnits)
+			for _, unit := range config.Systemd.Units {
+				assert.Equal(t, "iscsi-firmware-login.service", unit.Name)
+			}
+		})
+	}
+}
+
 func defaultGeneratedFiles() []string {
 	return []string{
 		"/etc/issue",
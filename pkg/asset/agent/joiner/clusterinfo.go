@@ -0,0 +1,152 @@
+package joiner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	"github.com/openshift/installer/pkg/asset"
+)
+
+var (
+	kubeconfigFilename = filepath.Join("auth", "kubeconfig")
+)
+
+// Kubeconfig is the kubeconfig of the existing cluster, read from the auth
+// directory of the assets directory.
+type Kubeconfig struct {
+	File *asset.File
+}
+
+var _ asset.WritableAsset = (*Kubeconfig)(nil)
+
+// Name returns the human-friendly name of the asset.
+func (*Kubeconfig) Name() string {
+	return "Cluster Kubeconfig"
+}
+
+// Dependencies returns no dependencies.
+func (*Kubeconfig) Dependencies() []asset.Asset {
+	return []asset.Asset{}
+}
+
+// Generate fails, since the kubeconfig must be that of an existing cluster.
+func (*Kubeconfig) Generate(asset.Parents) error {
+	return errors.Errorf("%s is required to read the cluster to add the nodes to", kubeconfigFilename)
+}
+
+// Files returns no files, so that the kubeconfig of the cluster is neither
+// rewritten nor consumed from the assets directory.
+func (*Kubeconfig) Files() []*asset.File {
+	return []*asset.File{}
+}
+
+// Load returns the kubeconfig from disk, decrypted when the assets directory
+// is encrypted.
+func (k *Kubeconfig) Load(f asset.FileFetcher) (bool, error) {
+	file, err := f.FetchByName(kubeconfigFilename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to load %s", kubeconfigFilename)
+	}
+	k.File = file
+	return true, nil
+}
+
+// ClusterInfo is the information about an existing cluster which is needed
+// to add nodes to it. It is read from the cluster with the kubeconfig in the
+// auth directory of the assets directory.
+type ClusterInfo struct {
+	// APIURL is the URL of the API server of the cluster.
+	APIURL string
+	// ReleaseImage is the release image the cluster runs.
+	ReleaseImage string
+	// PullSecret is the pull secret of the cluster.
+	PullSecret string
+	// WorkerIgnition is the pointer Ignition config of the workers, which
+	// fetches their config from the machine config server of the cluster.
+	WorkerIgnition []byte
+}
+
+var _ asset.ContextAsset = (*ClusterInfo)(nil)
+
+// Name returns the human-friendly name of the asset.
+func (*ClusterInfo) Name() string {
+	return "Cluster Info"
+}
+
+// Dependencies returns the kubeconfig of the cluster.
+func (*ClusterInfo) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&Kubeconfig{},
+	}
+}
+
+// Generate fails, since the asset must be read from the cluster by the asset
+// store with GenerateWithContext.
+func (ci *ClusterInfo) Generate(asset.Parents) error {
+	return errors.Errorf("the %q asset must be generated by the asset store", ci.Name())
+}
+
+// GenerateWithContext reads the information from the cluster.
+func (ci *ClusterInfo) GenerateWithContext(ctx context.Context, _ string, dependencies asset.Parents) error {
+	kubeconfig := &Kubeconfig{}
+	dependencies.Get(kubeconfig)
+
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig.File.Data)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load the kubeconfig of the cluster from %s", kubeconfigFilename)
+	}
+	ci.APIURL = config.Host
+	logrus.Infof("Reading the cluster information from %s", ci.APIURL)
+
+	configClient, err := configclient.NewForConfig(config)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the config client")
+	}
+	clusterVersion, err := configClient.ConfigV1().ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to get the cluster version")
+	}
+	ci.ReleaseImage = clusterVersion.Status.Desired.Image
+	if ci.ReleaseImage == "" {
+		return errors.New("the cluster version does not report the release image of the cluster")
+	}
+
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the kubernetes client")
+	}
+	pullSecret, err := secretData(ctx, client, "openshift-config", "pull-secret", corev1.DockerConfigJsonKey)
+	if err != nil {
+		return err
+	}
+	ci.PullSecret = string(pullSecret)
+	ci.WorkerIgnition, err = secretData(ctx, client, "openshift-machine-api", "worker-user-data", "userData")
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func secretData(ctx context.Context, client kubernetes.Interface, namespace, name, key string) ([]byte, error) {
+	secret, err := client.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the %s/%s secret", namespace, name)
+	}
+	data, ok := secret.Data[key]
+	if !ok || len(data) == 0 {
+		return nil, errors.Errorf("the %s/%s secret has no %s", namespace, name, key)
+	}
+	return data, nil
+}
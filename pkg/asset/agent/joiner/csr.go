@@ -0,0 +1,146 @@
+package joiner
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+var (
+	approveCSRsFilename = filepath.Join("add-nodes", "approve-csrs.sh")
+)
+
+// approveCSRsTemplate approves the certificate signing requests of the
+// kubelets of the hosts as they join, until every host is a node of the
+// cluster. A kubelet first requests a client certificate as the node
+// bootstrapper, then a serving certificate as its node. The script exits
+// right away when no host has a hostname, as it could never tell that the
+// hosts joined.
+var approveCSRsTemplate = template.Must(template.New("approve-csrs").Parse(`#!/bin/bash
+# Approves the certificate signing requests of the hosts of nodes-config.yaml
+# while they join the cluster. Run it with the kubeconfig of the cluster once
+# the hosts have booted the add-nodes ISO:
+#
+#   KUBECONFIG=auth/kubeconfig ./add-nodes/approve-csrs.sh
+#
+# Review the pending requests with 'oc get csr' if hosts other than these are
+# expected to join at the same time.
+set -euo pipefail
+
+nodes=({{ range .Hostnames }}{{ . }} {{ end }})
+expected={{ .Hosts }}
+{{- if eq .Hosts 0 }}
+
+echo "No host of nodes-config.yaml has a hostname, so their nodes cannot be told apart from the others: approve their requests with 'oc adm certificate approve' instead" >&2
+exit 1
+{{- end }}
+
+while true; do
+  oc get csr -o go-template='{{"{{"}}range .items{{"}}"}}{{"{{"}}if not .status{{"}}"}}{{"{{"}}.metadata.name{{"}}"}} {{"{{"}}.spec.username{{"}}"}}{{"{{"}}"\n"{{"}}"}}{{"{{"}}end{{"}}"}}{{"{{"}}end{{"}}"}}' |
+    while read -r csr username; do
+      case "${username}" in
+      system:serviceaccount:openshift-machine-config-operator:node-bootstrapper)
+        oc adm certificate approve "${csr}" ;;
+      system:node:*)
+        for node in "${nodes[@]}"; do
+          if [ "${username}" = "system:node:${node}" ]; then
+            oc adm certificate approve "${csr}"
+          fi
+        done ;;
+      esac
+    done
+
+  ready=0
+  for node in "${nodes[@]}"; do
+    if oc get node "${node}" >/dev/null 2>&1; then
+      ready=$((ready + 1))
+    fi
+  done
+  if [ "${ready}" -ge "${expected}" ]; then
+    echo "All ${expected} nodes joined the cluster"
+    exit 0
+  fi
+  sleep 30
+done
+`))
+
+// ApproveCSRsScript is the script approving the certificate signing
+// requests of the hosts added to the cluster.
+type ApproveCSRsScript struct {
+	File *asset.File
+}
+
+var _ asset.WritableAsset = (*ApproveCSRsScript)(nil)
+
+// Name returns the human-friendly name of the asset.
+func (*ApproveCSRsScript) Name() string {
+	return "Approve CSRs Script"
+}
+
+// Dependencies returns the assets on which the script depends.
+func (*ApproveCSRsScript) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&NodesConfig{},
+	}
+}
+
+// Generate generates the script.
+func (a *ApproveCSRsScript) Generate(dependencies asset.Parents) error {
+	nodesConfig := &NodesConfig{}
+	dependencies.Get(nodesConfig)
+
+	var hostnames []string
+	for _, host := range nodesConfig.Config.Hosts {
+		if host.Hostname != "" {
+			hostnames = append(hostnames, host.Hostname)
+		}
+	}
+
+	// hosts without a hostname cannot be told apart from other nodes, so
+	// only the client certificates of their kubelets are approved
+	buf := &bytes.Buffer{}
+	err := approveCSRsTemplate.Execute(buf, struct {
+		Hostnames []string
+		Hosts     int
+	}{
+		Hostnames: hostnames,
+		Hosts:     len(hostnames),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create the approve CSRs script")
+	}
+
+	a.File = &asset.File{
+		Filename: approveCSRsFilename,
+		Data:     []byte(strings.TrimLeft(buf.String(), "\n")),
+	}
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (a *ApproveCSRsScript) Files() []*asset.File {
+	if a.File != nil {
+		return []*asset.File{a.File}
+	}
+	return []*asset.File{}
+}
+
+// Load returns false, since the script is generated from the nodes config.
+func (a *ApproveCSRsScript) Load(asset.FileFetcher) (bool, error) {
+	return false, nil
+}
+
+// PersistToFile writes the script to the assets directory, executable.
+func (a *ApproveCSRsScript) PersistToFile(directory string) error {
+	path := filepath.Join(directory, a.File.Filename)
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, a.File.Data, 0750) //nolint:gosec // the script must be executable
+}
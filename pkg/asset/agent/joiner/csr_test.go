@@ -0,0 +1,48 @@
+package joiner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/types/agent"
+)
+
+func TestApproveCSRsScript(t *testing.T) {
+	cases := []struct {
+		name       string
+		hosts      []agent.Host
+		contains   string
+		exitsEarly bool
+	}{
+		{
+			name:     "hostnames",
+			hosts:    []agent.Host{{Hostname: "worker-3"}, {Hostname: "worker-4"}},
+			contains: "nodes=(worker-3 worker-4 )\nexpected=2\n\nwhile true; do",
+		},
+		{
+			name:       "no hostnames",
+			hosts:      []agent.Host{{}},
+			contains:   "expected=0\n\necho \"No host of nodes-config.yaml has a hostname",
+			exitsEarly: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			parents := asset.Parents{}
+			parents.Add(&NodesConfig{Config: &agent.NodesConfig{Hosts: tc.hosts}})
+			script := &ApproveCSRsScript{}
+			if !assert.NoError(t, script.Generate(parents)) {
+				return
+			}
+			data := string(script.File.Data)
+			assert.Contains(t, data, tc.contains)
+			if tc.exitsEarly {
+				assert.Contains(t, data, "\nexit 1\n")
+			} else {
+				assert.NotContains(t, data, "\nexit 1\n")
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package joiner
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/strictyaml"
+	"github.com/openshift/installer/pkg/types/agent"
+	"github.com/openshift/installer/pkg/types/baremetal"
+	"github.com/openshift/installer/pkg/validate"
+)
+
+var (
+	nodesConfigFilename = "nodes-config.yaml"
+)
+
+// NodesConfig reads the nodes-config.yaml file, which lists the hosts to add
+// to an existing cluster.
+type NodesConfig struct {
+	File   *asset.File
+	Config *agent.NodesConfig
+}
+
+var _ asset.WritableAsset = (*NodesConfig)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*NodesConfig) Name() string {
+	return "Nodes Config"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*NodesConfig) Dependencies() []asset.Asset {
+	return []asset.Asset{}
+}
+
+// Generate fails, since the hosts to add must be listed by the user.
+func (*NodesConfig) Generate(asset.Parents) error {
+	return errors.Errorf("%s is required to list the hosts to add to the cluster", nodesConfigFilename)
+}
+
+// Files returns the files generated by the asset.
+func (n *NodesConfig) Files() []*asset.File {
+	if n.File != nil {
+		return []*asset.File{n.File}
+	}
+	return []*asset.File{}
+}
+
+// Load returns the nodes config from disk.
+func (n *NodesConfig) Load(f asset.FileFetcher) (bool, error) {
+	file, err := f.FetchByName(nodesConfigFilename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, fmt.Sprintf("failed to load %s file", nodesConfigFilename))
+	}
+
+	config := &agent.NodesConfig{}
+	if err := strictyaml.Unmarshal(file.Data, config); err != nil {
+		return false, errors.Wrapf(err, "failed to unmarshal %s", nodesConfigFilename)
+	}
+
+	if err := validateNodesConfig(config).ToAggregate(); err != nil {
+		return false, errors.Wrapf(err, "invalid Nodes Config configuration")
+	}
+
+	n.File, n.Config = file, config
+	return true, nil
+}
+
+func validateNodesConfig(config *agent.NodesConfig) field.ErrorList {
+	var allErrs field.ErrorList
+
+	hostsPath := field.NewPath("hosts")
+	if len(config.Hosts) == 0 {
+		allErrs = append(allErrs, field.Required(hostsPath, "at least one host must be listed"))
+	}
+
+	macs := map[string]bool{}
+	hostnames := map[string]bool{}
+	for i, host := range config.Hosts {
+		hostPath := hostsPath.Index(i)
+
+		if host.Hostname != "" {
+			if hostnames[host.Hostname] {
+				allErrs = append(allErrs, field.Duplicate(hostPath.Child("hostname"), host.Hostname))
+			}
+			hostnames[host.Hostname] = true
+		}
+
+		if host.Role != "" && host.Role != "worker" {
+			allErrs = append(allErrs, field.NotSupported(hostPath.Child("role"), host.Role, []string{"worker"}))
+		}
+
+		interfacesPath := hostPath.Child("interfaces")
+		if len(host.Interfaces) == 0 {
+			allErrs = append(allErrs, field.Required(interfacesPath, "at least one interface must be defined to identify the host"))
+		}
+		for j, intf := range host.Interfaces {
+			macPath := interfacesPath.Index(j).Child("macAddress")
+			if intf.MacAddress == "" {
+				allErrs = append(allErrs, field.Required(macPath, "each interface must have a MAC address defined"))
+				continue
+			}
+			if err := validate.MAC(intf.MacAddress); err != nil {
+				allErrs = append(allErrs, field.Invalid(macPath, intf.MacAddress, err.Error()))
+			}
+			mac := strings.ToLower(intf.MacAddress)
+			if macs[mac] {
+				allErrs = append(allErrs, field.Duplicate(macPath, intf.MacAddress))
+			}
+			macs[mac] = true
+		}
+
+		// the disk is passed as is to coreos-installer
+		hints := host.RootDeviceHints
+		hints.DeviceName = ""
+		if !reflect.DeepEqual(hints, baremetal.RootDeviceHints{}) {
+			allErrs = append(allErrs, field.Forbidden(hostPath.Child("rootDeviceHints"), "only deviceName is supported when adding nodes"))
+		}
+		if name := host.RootDeviceHints.DeviceName; name != "" && !strings.HasPrefix(name, "/dev/") {
+			allErrs = append(allErrs, field.Invalid(hostPath.Child("rootDeviceHints", "deviceName"), name, "must be the path of a block device under /dev"))
+		}
+	}
+
+	if config.SSHKey != "" {
+		if err := validate.SSHPublicKey(config.SSHKey); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("sshKey"), config.SSHKey, err.Error()))
+		}
+	}
+
+	return allErrs
+}
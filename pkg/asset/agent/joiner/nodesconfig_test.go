@@ -0,0 +1,83 @@
+package joiner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	aiv1beta1 "github.com/openshift/assisted-service/api/v1beta1"
+	"github.com/openshift/installer/pkg/types/agent"
+	"github.com/openshift/installer/pkg/types/baremetal"
+)
+
+func TestValidateNodesConfig(t *testing.T) {
+	cases := []struct {
+		name          string
+		config        *agent.NodesConfig
+		expectedError string
+	}{
+		{
+			name: "valid",
+			config: &agent.NodesConfig{
+				Hosts: []agent.Host{{
+					Hostname:        "worker-3",
+					Role:            "worker",
+					RootDeviceHints: baremetal.RootDeviceHints{DeviceName: "/dev/sda"},
+					Interfaces:      []*aiv1beta1.Interface{{Name: "eth0", MacAddress: "52:54:00:aa:bb:01"}},
+				}},
+			},
+		},
+		{
+			name:          "no hosts",
+			config:        &agent.NodesConfig{},
+			expectedError: "hosts: Required value: at least one host must be listed",
+		},
+		{
+			name: "master role",
+			config: &agent.NodesConfig{
+				Hosts: []agent.Host{{
+					Role:       "master",
+					Interfaces: []*aiv1beta1.Interface{{MacAddress: "52:54:00:aa:bb:01"}},
+				}},
+			},
+			expectedError: `hosts[0].role: Unsupported value: "master": supported values: "worker"`,
+		},
+		{
+			name: "host without interfaces",
+			config: &agent.NodesConfig{
+				Hosts: []agent.Host{{Hostname: "worker-3"}},
+			},
+			expectedError: "hosts[0].interfaces: Required value: at least one interface must be defined to identify the host",
+		},
+		{
+			name: "duplicate hostnames and MAC addresses",
+			config: &agent.NodesConfig{
+				Hosts: []agent.Host{
+					{Hostname: "worker-3", Interfaces: []*aiv1beta1.Interface{{MacAddress: "52:54:00:aa:bb:01"}}},
+					{Hostname: "worker-3", Interfaces: []*aiv1beta1.Interface{{MacAddress: "52:54:00:AA:BB:01"}}},
+				},
+			},
+			expectedError: `[hosts[1].hostname: Duplicate value: "worker-3", hosts[1].interfaces[0].macAddress: Duplicate value: "52:54:00:AA:BB:01"]`,
+		},
+		{
+			name: "unsupported root device hints",
+			config: &agent.NodesConfig{
+				Hosts: []agent.Host{{
+					RootDeviceHints: baremetal.RootDeviceHints{Model: "model"},
+					Interfaces:      []*aiv1beta1.Interface{{MacAddress: "52:54:00:aa:bb:01"}},
+				}},
+			},
+			expectedError: "hosts[0].rootDeviceHints: Forbidden: only deviceName is supported when adding nodes",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateNodesConfig(tc.config).ToAggregate()
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
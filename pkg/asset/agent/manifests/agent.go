@@ -13,6 +13,10 @@ import (
 	"github.com/openshift/assisted-service/models"
 	hivev1 "github.com/openshift/hive/apis/hive/v1"
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/agent"
+	"github.com/openshift/installer/pkg/asset/agent/agentconfig"
+	"github.com/openshift/installer/pkg/types"
+	agenttypes "github.com/openshift/installer/pkg/types/agent"
 )
 
 const (
@@ -52,6 +56,8 @@ func (m *AgentManifests) Dependencies() []asset.Asset {
 		&AgentClusterInstall{},
 		&ClusterDeployment{},
 		&ClusterImageSet{},
+		&agentconfig.AgentConfig{},
+		&agent.OptionalInstallConfig{},
 	}
 }
 
@@ -93,7 +99,11 @@ func (m *AgentManifests) Generate(dependencies asset.Parents) error {
 
 	asset.SortFiles(m.FileList)
 
-	return m.finish()
+	agentConfig := &agentconfig.AgentConfig{}
+	installConfig := &agent.OptionalInstallConfig{}
+	dependencies.Get(agentConfig, installConfig)
+
+	return m.finish(installConfig.Config, agentConfig.Config)
 }
 
 // Files returns the files generated by the asset.
@@ -111,21 +121,25 @@ func (m *AgentManifests) GetPullSecretData() string {
 	return m.PullSecret.StringData[".dockerconfigjson"]
 }
 
-func (m *AgentManifests) finish() error {
-	if err := m.validateAgentManifests().ToAggregate(); err != nil {
+func (m *AgentManifests) finish(installConfig *types.InstallConfig, agentConfig *agenttypes.Config) error {
+	if err := m.validateAgentManifests(installConfig, agentConfig).ToAggregate(); err != nil {
 		return errors.Wrapf(err, "invalid agent configuration")
 	}
 
 	return nil
 }
 
-func (m *AgentManifests) validateAgentManifests() field.ErrorList {
+func (m *AgentManifests) validateAgentManifests(installConfig *types.InstallConfig, agentConfig *agenttypes.Config) field.ErrorList {
 	allErrs := field.ErrorList{}
 
 	if err := m.validateNMStateLabelSelector(); err != nil {
 		allErrs = append(allErrs, err...)
 	}
 
+	if err := validateHostsAgainstInstallConfig(installConfig, agentConfig); err != nil {
+		allErrs = append(allErrs, err...)
+	}
+
 	return allErrs
 }
 
@@ -12,6 +12,8 @@ import (
 	"github.com/openshift/assisted-service/models"
 	hivev1 "github.com/openshift/hive/apis/hive/v1"
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/agent"
+	"github.com/openshift/installer/pkg/asset/agent/agentconfig"
 )
 
 func TestAgentManifests_Generate(t *testing.T) {
@@ -52,6 +54,8 @@ func TestAgentManifests_Generate(t *testing.T) {
 				&AgentClusterInstall{Config: fakeAgentClusterInstall},
 				&ClusterDeployment{Config: fakeClusterDeployment},
 				&ClusterImageSet{Config: fakeClusterImageSet},
+				&agentconfig.AgentConfig{},
+				&agent.OptionalInstallConfig{},
 			},
 			ExpectedPullSecret:          fakeSecret,
 			ExpectedInfraEnv:            fakeInfraEnv,
@@ -81,6 +85,8 @@ func TestAgentManifests_Generate(t *testing.T) {
 				&AgentClusterInstall{},
 				&ClusterDeployment{},
 				&ClusterImageSet{},
+				&agentconfig.AgentConfig{},
+				&agent.OptionalInstallConfig{},
 			},
 			ExpectedError: "invalid agent configuration: Spec.NMStateConfigLabelSelector.MatchLabels: Required value: infraEnv and fake-nmState.NMStateConfig labels do not match. Expected: map[missing-label:missing-label] Found: map[]",
 		},
@@ -1,17 +1,32 @@
 package manifests
 
 import (
+	"fmt"
 	"path/filepath"
 
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/openshift/installer/pkg/asset"
 )
 
 const (
 	openshiftManifestDir = "openshift"
+
+	machineConfigRoleLabel = "machineconfiguration.openshift.io/role"
 )
 
+// defaultCatalogSources are the catalog sources of the cluster, which cannot
+// be reached by a disconnected cluster unless they are mirrored.
+var defaultCatalogSources = map[string]bool{
+	"certified-operators": true,
+	"community-operators": true,
+	"redhat-marketplace":  true,
+	"redhat-operators":    true,
+}
+
 // ExtraManifests manages the additional manifests for cluster customization
 type ExtraManifests struct {
 	FileList []*asset.File
@@ -57,5 +72,128 @@ func (em *ExtraManifests) Load(f asset.FileFetcher) (found bool, err error) {
 	em.FileList = append(em.FileList, ymlFileList...)
 	asset.SortFiles(em.FileList)
 
+	if err := validateExtraManifests(em.FileList).ToAggregate(); err != nil {
+		return false, errors.Wrapf(err, "invalid extra manifests")
+	}
+
 	return len(em.FileList) > 0, nil
 }
+
+// validateExtraManifests checks that the documents of the extra manifests
+// are objects which can be applied to the cluster once it is installed,
+// so that errors are reported when the ISO is generated rather than at the
+// end of the installation.
+func validateExtraManifests(files []*asset.File) field.ErrorList {
+	var allErrs field.ErrorList
+
+	var objects []*unstructured.Unstructured
+	var paths []*field.Path
+	for _, file := range files {
+		filePath := field.NewPath(file.Filename)
+		yamlList, err := GetMultipleYamls[map[string]interface{}](file.Data)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(filePath, file.Filename, err.Error()))
+			continue
+		}
+		for n, manifest := range yamlList {
+			if manifest == nil {
+				continue
+			}
+			objects = append(objects, &unstructured.Unstructured{Object: manifest})
+			paths = append(paths, filePath.Index(n))
+		}
+	}
+
+	seen := map[string]bool{}
+	catalogSources := map[string]bool{}
+	for _, obj := range objects {
+		if obj.GetKind() == "CatalogSource" {
+			catalogSources[obj.GetName()] = true
+		}
+	}
+	for i, obj := range objects {
+		objPath := paths[i]
+		if obj.GetAPIVersion() == "" {
+			allErrs = append(allErrs, field.Required(objPath.Child("apiVersion"), "the manifest must have an apiVersion"))
+		}
+		if obj.GetKind() == "" {
+			allErrs = append(allErrs, field.Required(objPath.Child("kind"), "the manifest must have a kind"))
+		}
+		if obj.GetName() == "" {
+			allErrs = append(allErrs, field.Required(objPath.Child("metadata", "name"), "the manifest must have a name"))
+			continue
+		}
+
+		key := fmt.Sprintf("%s/%s/%s/%s", obj.GroupVersionKind().Group, obj.GetKind(), obj.GetNamespace(), obj.GetName())
+		if seen[key] {
+			allErrs = append(allErrs, field.Duplicate(objPath.Child("metadata", "name"), obj.GetName()))
+		}
+		seen[key] = true
+
+		switch obj.GroupVersionKind().GroupKind().String() {
+		case "MachineConfig.machineconfiguration.openshift.io":
+			if obj.GetLabels()[machineConfigRoleLabel] == "" {
+				allErrs = append(allErrs, field.Required(objPath.Child("metadata", "labels").Key(machineConfigRoleLabel),
+					"the machine config pool of the MachineConfig must be set"))
+			}
+		case "ImageContentSourcePolicy.operator.openshift.io":
+			allErrs = append(allErrs, validateImageMirrors(obj, objPath, "repositoryDigestMirrors")...)
+		case "ImageDigestMirrorSet.config.openshift.io":
+			allErrs = append(allErrs, validateImageMirrors(obj, objPath, "imageDigestMirrors")...)
+		case "OperatorGroup.operators.coreos.com":
+			if obj.GetNamespace() == "" {
+				allErrs = append(allErrs, field.Required(objPath.Child("metadata", "namespace"), "the OperatorGroup must have a namespace"))
+			}
+		case "Subscription.operators.coreos.com":
+			allErrs = append(allErrs, validateSubscription(obj, objPath, catalogSources)...)
+		}
+	}
+
+	return allErrs
+}
+
+func validateImageMirrors(obj *unstructured.Unstructured, objPath *field.Path, mirrorsField string) field.ErrorList {
+	var allErrs field.ErrorList
+
+	mirrorsPath := objPath.Child("spec", mirrorsField)
+	mirrors, _, err := unstructured.NestedSlice(obj.Object, "spec", mirrorsField)
+	if err != nil {
+		return append(allErrs, field.Invalid(mirrorsPath, mirrorsField, err.Error()))
+	}
+	if len(mirrors) == 0 {
+		return append(allErrs, field.Required(mirrorsPath, "at least one source must be mirrored"))
+	}
+	for i, m := range mirrors {
+		mirror, _ := m.(map[string]interface{})
+		if source, _, _ := unstructured.NestedString(mirror, "source"); source == "" {
+			allErrs = append(allErrs, field.Required(mirrorsPath.Index(i).Child("source"), "the source must be set"))
+		}
+		if mirrorList, _, _ := unstructured.NestedStringSlice(mirror, "mirrors"); len(mirrorList) == 0 {
+			allErrs = append(allErrs, field.Required(mirrorsPath.Index(i).Child("mirrors"), "at least one mirror must be set"))
+		}
+	}
+	return allErrs
+}
+
+func validateSubscription(obj *unstructured.Unstructured, objPath *field.Path, catalogSources map[string]bool) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if obj.GetNamespace() == "" {
+		allErrs = append(allErrs, field.Required(objPath.Child("metadata", "namespace"), "the Subscription must have a namespace"))
+	}
+	for _, f := range []string{"name", "source", "sourceNamespace"} {
+		if value, _, _ := unstructured.NestedString(obj.Object, "spec", f); value == "" {
+			allErrs = append(allErrs, field.Required(objPath.Child("spec", f), "the Subscription must set the operator and its catalog source"))
+		}
+	}
+
+	source, _, _ := unstructured.NestedString(obj.Object, "spec", "source")
+	if source != "" && !catalogSources[source] {
+		if defaultCatalogSources[source] {
+			logrus.Warnf("%s: the %s catalog source cannot be reached by a disconnected cluster unless it is mirrored", objPath, source)
+		} else {
+			logrus.Warnf("%s: the %s catalog source is not in the extra manifests and must already exist in the cluster", objPath, source)
+		}
+	}
+	return allErrs
+}
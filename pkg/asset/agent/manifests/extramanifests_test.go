@@ -73,7 +73,7 @@ func TestExtraManifests_Load(t *testing.T) {
 			for _, f := range tc.files {
 				assetFile := &asset.File{
 					Filename: f,
-					Data:     []byte(f),
+					Data:     []byte(fmt.Sprintf("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: %s\n  namespace: default\n", filepath.Base(f))),
 				}
 
 				switch filepath.Ext(f) {
@@ -124,3 +124,117 @@ func TestExtraManifests_Load(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateExtraManifests(t *testing.T) {
+	cases := []struct {
+		name          string
+		data          string
+		expectedError string
+	}{
+		{
+			name: "valid",
+			data: `apiVersion: machineconfiguration.openshift.io/v1
+kind: MachineConfig
+metadata:
+  name: 99-worker-chrony
+  labels:
+    machineconfiguration.openshift.io/role: worker
+---
+apiVersion: config.openshift.io/v1
+kind: ImageDigestMirrorSet
+metadata:
+  name: release
+spec:
+  imageDigestMirrors:
+  - source: quay.io/openshift-release-dev/ocp-release
+    mirrors:
+    - registry.example.com/ocp-release
+---
+apiVersion: operators.coreos.com/v1alpha1
+kind: CatalogSource
+metadata:
+  name: mirrored-operators
+  namespace: openshift-marketplace
+---
+apiVersion: operators.coreos.com/v1alpha1
+kind: Subscription
+metadata:
+  name: local-storage-operator
+  namespace: openshift-local-storage
+spec:
+  name: local-storage-operator
+  source: mirrored-operators
+  sourceNamespace: openshift-marketplace
+`,
+		},
+		{
+			name: "missing kind and name",
+			data: `apiVersion: v1
+metadata:
+  namespace: default
+`,
+			expectedError: "[openshift/extra.yaml[0].kind: Required value: the manifest must have a kind, openshift/extra.yaml[0].metadata.name: Required value: the manifest must have a name]",
+		},
+		{
+			name: "duplicate",
+			data: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+  namespace: default
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+  namespace: default
+`,
+			expectedError: `openshift/extra.yaml[1].metadata.name: Duplicate value: "test"`,
+		},
+		{
+			name: "machine config without role",
+			data: `apiVersion: machineconfiguration.openshift.io/v1
+kind: MachineConfig
+metadata:
+  name: 99-chrony
+`,
+			expectedError: "openshift/extra.yaml[0].metadata.labels[machineconfiguration.openshift.io/role]: Required value: the machine config pool of the MachineConfig must be set",
+		},
+		{
+			name: "image content source policy without mirrors",
+			data: `apiVersion: operator.openshift.io/v1alpha1
+kind: ImageContentSourcePolicy
+metadata:
+  name: release
+spec:
+  repositoryDigestMirrors:
+  - source: quay.io/openshift-release-dev/ocp-release
+`,
+			expectedError: "openshift/extra.yaml[0].spec.repositoryDigestMirrors[0].mirrors: Required value: at least one mirror must be set",
+		},
+		{
+			name: "subscription without namespace and source",
+			data: `apiVersion: operators.coreos.com/v1alpha1
+kind: Subscription
+metadata:
+  name: local-storage-operator
+spec:
+  name: local-storage-operator
+`,
+			expectedError: "[openshift/extra.yaml[0].metadata.namespace: Required value: the Subscription must have a namespace, openshift/extra.yaml[0].spec.source: Required value: the Subscription must set the operator and its catalog source, openshift/extra.yaml[0].spec.sourceNamespace: Required value: the Subscription must set the operator and its catalog source]",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateExtraManifests([]*asset.File{{
+				Filename: "openshift/extra.yaml",
+				Data:     []byte(tc.data),
+			}}).ToAggregate()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package manifests
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/openshift/installer/pkg/types"
+	agenttypes "github.com/openshift/installer/pkg/types/agent"
+)
+
+// validateHostsAgainstInstallConfig cross-validates the hosts of the
+// agent-config with the install-config. Every mismatch is reported, so that
+// all of them can be fixed at once.
+func validateHostsAgainstInstallConfig(installConfig *types.InstallConfig, agentConfig *agenttypes.Config) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if installConfig == nil || agentConfig == nil {
+		return allErrs
+	}
+
+	if err := validateHostCounts(installConfig, agentConfig); err != nil {
+		allErrs = append(allErrs, err...)
+	}
+
+	if installConfig.Networking == nil || len(installConfig.Networking.MachineNetwork) == 0 {
+		return allErrs
+	}
+	machineNetworks := installConfig.Networking.MachineNetwork
+
+	if err := validateRendezvousIPInMachineNetwork(agentConfig.RendezvousIP, machineNetworks); err != nil {
+		allErrs = append(allErrs, err...)
+	}
+
+	if err := validateHostStaticIPs(agentConfig.Hosts, machineNetworks); err != nil {
+		allErrs = append(allErrs, err...)
+	}
+
+	return allErrs
+}
+
+func validateHostCounts(installConfig *types.InstallConfig, agentConfig *agenttypes.Config) field.ErrorList {
+	var allErrs field.ErrorList
+
+	fieldPath := field.NewPath("Hosts")
+
+	var masters, workers int64
+	for _, host := range agentConfig.Hosts {
+		switch host.Role {
+		case "master":
+			masters++
+		case "worker":
+			workers++
+		}
+	}
+
+	var controlPlaneReplicas int64
+	if installConfig.ControlPlane != nil && installConfig.ControlPlane.Replicas != nil {
+		controlPlaneReplicas = *installConfig.ControlPlane.Replicas
+	}
+	var computeReplicas int64
+	for _, compute := range installConfig.Compute {
+		if compute.Replicas != nil {
+			computeReplicas += *compute.Replicas
+		}
+	}
+
+	if masters > controlPlaneReplicas {
+		allErrs = append(allErrs, field.Invalid(fieldPath, masters, fmt.Sprintf("the number of hosts with the master role exceeds ControlPlane.Replicas %d", controlPlaneReplicas)))
+	}
+	if workers > computeReplicas {
+		allErrs = append(allErrs, field.Invalid(fieldPath, workers, fmt.Sprintf("the number of hosts with the worker role exceeds the total Compute.Replicas %d", computeReplicas)))
+	}
+	if hosts := int64(len(agentConfig.Hosts)); hosts > controlPlaneReplicas+computeReplicas {
+		allErrs = append(allErrs, field.TooMany(fieldPath, int(hosts), int(controlPlaneReplicas+computeReplicas)))
+	}
+
+	return allErrs
+}
+
+func validateRendezvousIPInMachineNetwork(rendezvousIP string, machineNetworks []types.MachineNetworkEntry) field.ErrorList {
+	var allErrs field.ErrorList
+
+	ip := net.ParseIP(rendezvousIP)
+	if ip == nil {
+		// an invalid IP is reported by the agent-config validation
+		return allErrs
+	}
+
+	if !inMachineNetwork(ip, machineNetworks) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("rendezvousIP"), rendezvousIP, fmt.Sprintf("the rendezvous IP must belong to one of the machine networks %s", machineNetworkCIDRs(machineNetworks))))
+	}
+
+	return allErrs
+}
+
+// validateHostStaticIPs validates that each host with static IPs has at least
+// one of them in a machine network. The other IPs may belong to additional
+// networks, such as a storage network.
+func validateHostStaticIPs(hosts []agenttypes.Host, machineNetworks []types.MachineNetworkEntry) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, host := range hosts {
+		if host.NetworkConfig.Raw == nil {
+			continue
+		}
+		fieldPath := field.NewPath("Hosts").Index(i).Child("networkConfig")
+
+		var config nmStateConfig
+		if err := yaml.Unmarshal([]byte(host.NetworkConfig.Raw), &config); err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath, string(host.NetworkConfig.Raw), err.Error()))
+			continue
+		}
+
+		ips := staticIPs(&config)
+		if len(ips) == 0 {
+			continue
+		}
+		found := false
+		for _, ip := range ips {
+			if parsed := net.ParseIP(ip); parsed != nil && inMachineNetwork(parsed, machineNetworks) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			allErrs = append(allErrs, field.Invalid(fieldPath, strings.Join(ips, ", "), fmt.Sprintf("none of the static IPs of the host belongs to one of the machine networks %s", machineNetworkCIDRs(machineNetworks))))
+		}
+	}
+
+	return allErrs
+}
+
+func staticIPs(config *nmStateConfig) []string {
+	var ips []string
+	for _, intf := range config.Interfaces {
+		for _, addr4 := range intf.IPV4.Address {
+			if addr4.IP != "" {
+				ips = append(ips, addr4.IP)
+			}
+		}
+		for _, addr6 := range intf.IPV6.Address {
+			if addr6.IP != "" {
+				ips = append(ips, addr6.IP)
+			}
+		}
+	}
+	return ips
+}
+
+func inMachineNetwork(ip net.IP, machineNetworks []types.MachineNetworkEntry) bool {
+	for _, network := range machineNetworks {
+		if network.CIDR.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func machineNetworkCIDRs(machineNetworks []types.MachineNetworkEntry) string {
+	cidrs := make([]string, 0, len(machineNetworks))
+	for _, network := range machineNetworks {
+		cidrs = append(cidrs, network.CIDR.String())
+	}
+	return strings.Join(cidrs, ", ")
+}
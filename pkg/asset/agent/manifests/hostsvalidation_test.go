@@ -0,0 +1,100 @@
+package manifests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	"github.com/openshift/installer/pkg/ipnet"
+	"github.com/openshift/installer/pkg/types"
+	agenttypes "github.com/openshift/installer/pkg/types/agent"
+)
+
+func TestValidateHostsAgainstInstallConfig(t *testing.T) {
+	cases := []struct {
+		name           string
+		installConfig  func(*types.InstallConfig)
+		agentConfig    func(*agenttypes.Config)
+		expectedErrors []string
+	}{
+		{
+			name: "valid",
+		},
+		{
+			name: "no machine network",
+			installConfig: func(ic *types.InstallConfig) {
+				ic.Networking.MachineNetwork = nil
+			},
+		},
+		{
+			name: "more masters than control plane replicas",
+			installConfig: func(ic *types.InstallConfig) {
+				ic.ControlPlane.Replicas = pointer.Int64Ptr(1)
+			},
+			expectedErrors: []string{
+				"Hosts: Invalid value: 3: the number of hosts with the master role exceeds ControlPlane.Replicas 1",
+			},
+		},
+		{
+			name: "more workers than compute replicas",
+			installConfig: func(ic *types.InstallConfig) {
+				ic.Compute = nil
+			},
+			agentConfig: func(ac *agenttypes.Config) {
+				ac.Hosts[2].Role = "worker"
+			},
+			expectedErrors: []string{
+				"Hosts: Invalid value: 1: the number of hosts with the worker role exceeds the total Compute.Replicas 0",
+			},
+		},
+		{
+			name: "more hosts than replicas",
+			installConfig: func(ic *types.InstallConfig) {
+				ic.ControlPlane.Replicas = pointer.Int64Ptr(1)
+				ic.Compute = nil
+			},
+			expectedErrors: []string{
+				"Hosts: Invalid value: 3: the number of hosts with the master role exceeds ControlPlane.Replicas 1",
+				"Hosts: Too many: 3: must have at most 1 items",
+			},
+		},
+		{
+			name: "rendezvous IP and static IPs outside of the machine network",
+			installConfig: func(ic *types.InstallConfig) {
+				ic.Networking.MachineNetwork = []types.MachineNetworkEntry{{CIDR: *ipnet.MustParseCIDR("10.10.11.0/24")}}
+			},
+			agentConfig: func(ac *agenttypes.Config) {
+				ac.Hosts = ac.Hosts[:1]
+			},
+			expectedErrors: []string{
+				"rendezvousIP: Invalid value: \"192.168.122.2\": the rendezvous IP must belong to one of the machine networks 10.10.11.0/24",
+				"Hosts[0].networkConfig: Invalid value: \"192.168.122.21\": none of the static IPs of the host belongs to one of the machine networks 10.10.11.0/24",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			installConfig := getValidOptionalInstallConfig().Config
+			installConfig.Networking.MachineNetwork = []types.MachineNetworkEntry{{CIDR: *ipnet.MustParseCIDR("192.168.122.0/24")}}
+			if tc.installConfig != nil {
+				tc.installConfig(installConfig)
+			}
+			agentConfig := getValidAgentConfig().Config
+			if tc.agentConfig != nil {
+				tc.agentConfig(agentConfig)
+			}
+
+			errs := validateHostsAgainstInstallConfig(installConfig, agentConfig)
+			actualErrors := make([]string, 0, len(errs))
+			for _, err := range errs {
+				actualErrors = append(actualErrors, err.Error())
+			}
+			if len(tc.expectedErrors) == 0 {
+				assert.Empty(t, actualErrors)
+			} else {
+				assert.Equal(t, tc.expectedErrors, actualErrors)
+			}
+		})
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/agent"
@@ -34,26 +36,39 @@ func (*CaBundle) Name() string {
 func (*CaBundle) Dependencies() []asset.Asset {
 	return []asset.Asset{
 		&agent.OptionalInstallConfig{},
+		&OCMirror{},
 	}
 }
 
-// Generate generates the Mirror Registries certificate file from install-config.
+// Generate generates the Mirror Registries certificate file from install-config
+// and the CA of the mirror registry of oc-mirror.
 func (i *CaBundle) Generate(dependencies asset.Parents) error {
 	installConfig := &agent.OptionalInstallConfig{}
-	dependencies.Get(installConfig)
-	if !installConfig.Supplied {
-		return nil
+	ocMirror := &OCMirror{}
+	dependencies.Get(installConfig, ocMirror)
+
+	var bundle string
+	if installConfig.Supplied {
+		bundle = installConfig.Config.AdditionalTrustBundle
+		if ocMirror.CABundle != "" && !strings.Contains(bundle, strings.TrimSpace(ocMirror.CABundle)) {
+			logrus.Warnf("The CA of %s is not in the additionalTrustBundle of install-config.yaml, so it is only trusted during the installation", OCMirrorCAFilename)
+		}
+	}
+	if ocMirror.CABundle != "" {
+		bundle = strings.TrimSpace(bundle + "\n" + ocMirror.CABundle)
 	}
 
-	if installConfig.Config.AdditionalTrustBundle == "" {
-		i.File = &asset.File{
-			Filename: CaBundleFilename,
-			Data:     []byte{},
+	if bundle == "" {
+		if installConfig.Supplied {
+			i.File = &asset.File{
+				Filename: CaBundleFilename,
+				Data:     []byte{},
+			}
 		}
 		return nil
 	}
 
-	return i.parseCertificates(installConfig.Config.AdditionalTrustBundle)
+	return i.parseCertificates(bundle)
 }
 
 func (i *CaBundle) parseCertificates(certs string) error {
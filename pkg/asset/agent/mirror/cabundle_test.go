@@ -110,6 +110,7 @@ OIUk31HnM/Fj
 		t.Run(tc.name, func(t *testing.T) {
 
 			parents := asset.Parents{}
+			parents.Add(&OCMirror{})
 			parents.Add(tc.dependencies...)
 
 			asset := &CaBundle{}
@@ -0,0 +1,192 @@
+package mirror
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1alpha1 "github.com/openshift/api/operator/v1alpha1"
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/manifests"
+	"github.com/openshift/installer/pkg/types"
+)
+
+const (
+	ocMirrorDir = "oc-mirror"
+)
+
+var (
+	// OCMirrorCAFilename defines the name of the file on disk of the CA of
+	// the mirror registry.
+	OCMirrorCAFilename = filepath.Join(ocMirrorDir, "ca.crt")
+)
+
+// OCMirror reads the ImageContentSourcePolicy and ImageDigestMirrorSet
+// manifests generated by oc-mirror, such as imageContentSourcePolicy.yaml,
+// and the CA of the mirror registry from the oc-mirror directory, so that
+// they configure the mirrors of the installation.
+type OCMirror struct {
+	FileList []*asset.File
+	// Sources are the mirrors of the manifests.
+	Sources []types.ImageDigestSource
+	// CABundle is the CA of the mirror registry.
+	CABundle string
+}
+
+var _ asset.WritableAsset = (*OCMirror)(nil)
+
+// ocMirrorManifest holds the fields of the manifests generated by oc-mirror
+// which configure the mirrors.
+type ocMirrorManifest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              struct {
+		RepositoryDigestMirrors []operatorv1alpha1.RepositoryDigestMirrors `json:"repositoryDigestMirrors"`
+		ImageDigestMirrors      []configv1.ImageDigestMirrors              `json:"imageDigestMirrors"`
+	} `json:"spec"`
+}
+
+// Name returns a human friendly name for the asset.
+func (*OCMirror) Name() string {
+	return "oc-mirror Results"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*OCMirror) Dependencies() []asset.Asset {
+	return []asset.Asset{}
+}
+
+// Generate is not required, since the results of oc-mirror are optional.
+func (*OCMirror) Generate(asset.Parents) error {
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (o *OCMirror) Files() []*asset.File {
+	return o.FileList
+}
+
+// Load reads the results of oc-mirror from disk.
+func (o *OCMirror) Load(f asset.FileFetcher) (bool, error) {
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		files, err := f.FetchByPattern(filepath.Join(ocMirrorDir, pattern))
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to load %s files", pattern)
+		}
+		o.FileList = append(o.FileList, files...)
+	}
+	asset.SortFiles(o.FileList)
+
+	for _, file := range o.FileList {
+		sources, err := mirrorSourcesFromManifests(file)
+		if err != nil {
+			return false, err
+		}
+		o.Sources = append(o.Sources, sources...)
+	}
+
+	caFile, err := f.FetchByName(OCMirrorCAFilename)
+	if err != nil && !os.IsNotExist(err) {
+		return false, errors.Wrap(err, fmt.Sprintf("failed to load %s file", OCMirrorCAFilename))
+	}
+	if caFile != nil {
+		if _, err := manifests.ParseCertificates(string(caFile.Data)); err != nil {
+			return false, errors.Wrapf(err, "invalid %s", OCMirrorCAFilename)
+		}
+		o.FileList = append(o.FileList, caFile)
+		o.CABundle = string(caFile.Data)
+	}
+
+	return len(o.FileList) > 0, nil
+}
+
+// mirrorSourcesFromManifests returns the mirrors of the
+// ImageContentSourcePolicy and ImageDigestMirrorSet manifests of the file.
+// Other manifests generated by oc-mirror, such as the CatalogSources, are
+// ignored.
+func mirrorSourcesFromManifests(file *asset.File) ([]types.ImageDigestSource, error) {
+	var sources []types.ImageDigestSource
+
+	dec := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(file.Data), 4096)
+	for {
+		manifest := &ocMirrorManifest{}
+		err := dec.Decode(manifest)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal %s", file.Filename)
+		}
+
+		switch manifest.Kind {
+		case "ImageContentSourcePolicy":
+			for _, m := range manifest.Spec.RepositoryDigestMirrors {
+				sources = append(sources, types.ImageDigestSource{Source: m.Source, Mirrors: m.Mirrors})
+			}
+		case "ImageDigestMirrorSet":
+			for _, m := range manifest.Spec.ImageDigestMirrors {
+				source := types.ImageDigestSource{Source: m.Source}
+				for _, mirror := range m.Mirrors {
+					source.Mirrors = append(source.Mirrors, string(mirror))
+				}
+				sources = append(sources, source)
+			}
+		case "":
+		case "CatalogSource":
+			logrus.Warnf("%s: the %s CatalogSource is not applied to the cluster, add it to the openshift directory to install operators from the mirror", file.Filename, manifest.Name)
+		default:
+			logrus.Warnf("%s: ignoring the %s %s, since only ImageContentSourcePolicy and ImageDigestMirrorSet manifests configure the mirrors", file.Filename, manifest.Kind, manifest.Name)
+		}
+	}
+
+	for _, source := range sources {
+		if source.Source == "" {
+			return nil, errors.Errorf("%s: a source of the mirrors is empty", file.Filename)
+		}
+		if len(source.Mirrors) == 0 {
+			return nil, errors.Errorf("%s: no mirror is set for %s", file.Filename, source.Source)
+		}
+	}
+	return sources, nil
+}
+
+// validateReleaseImageMirrored checks that the release image can be pulled
+// from the mirrors, since the mirrors generated by oc-mirror are only used
+// when pulling by digest.
+func validateReleaseImageMirrored(releaseImage string, sources []types.ImageDigestSource) error {
+	repository := imageRepository(releaseImage)
+	mirrored := false
+	for _, source := range sources {
+		if repository == source.Source || strings.HasPrefix(repository, source.Source+"/") {
+			mirrored = true
+			break
+		}
+	}
+	if !mirrored {
+		return errors.Errorf("the release image %s is not mirrored by the manifests of the %s directory", releaseImage, ocMirrorDir)
+	}
+	if !strings.Contains(releaseImage, "@") {
+		logrus.Warnf("The release image %s is not referenced by digest, so it is not pulled from the mirrors of the %s directory", releaseImage, ocMirrorDir)
+	}
+	return nil
+}
+
+// imageRepository returns the repository of the pull spec, without its tag
+// or digest.
+func imageRepository(pullSpec string) string {
+	repository := strings.SplitN(pullSpec, "@", 2)[0]
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository = repository[:i]
+	}
+	return repository
+}
@@ -0,0 +1,146 @@
+package mirror
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestMirrorSourcesFromManifests(t *testing.T) {
+	cases := []struct {
+		name            string
+		data            string
+		expectedSources []types.ImageDigestSource
+		expectedError   string
+	}{
+		{
+			name: "image-content-source-policy",
+			data: `---
+apiVersion: operator.openshift.io/v1alpha1
+kind: ImageContentSourcePolicy
+metadata:
+  name: release-0
+spec:
+  repositoryDigestMirrors:
+  - mirrors:
+    - registry.example.com:8443/openshift/release
+    source: quay.io/openshift-release-dev/ocp-v4.0-art-dev
+  - mirrors:
+    - registry.example.com:8443/openshift/release-images
+    source: quay.io/openshift-release-dev/ocp-release
+---
+apiVersion: operator.openshift.io/v1alpha1
+kind: ImageContentSourcePolicy
+metadata:
+  name: operator-0
+spec:
+  repositoryDigestMirrors:
+  - mirrors:
+    - registry.example.com:8443/redhat
+    source: registry.redhat.io/redhat
+`,
+			expectedSources: []types.ImageDigestSource{
+				{Source: "quay.io/openshift-release-dev/ocp-v4.0-art-dev", Mirrors: []string{"registry.example.com:8443/openshift/release"}},
+				{Source: "quay.io/openshift-release-dev/ocp-release", Mirrors: []string{"registry.example.com:8443/openshift/release-images"}},
+				{Source: "registry.redhat.io/redhat", Mirrors: []string{"registry.example.com:8443/redhat"}},
+			},
+		},
+		{
+			name: "image-digest-mirror-set",
+			data: `apiVersion: config.openshift.io/v1
+kind: ImageDigestMirrorSet
+metadata:
+  name: idms-release-0
+spec:
+  imageDigestMirrors:
+  - mirrors:
+    - registry.example.com:8443/openshift/release
+    source: quay.io/openshift-release-dev/ocp-v4.0-art-dev
+`,
+			expectedSources: []types.ImageDigestSource{
+				{Source: "quay.io/openshift-release-dev/ocp-v4.0-art-dev", Mirrors: []string{"registry.example.com:8443/openshift/release"}},
+			},
+		},
+		{
+			name: "catalog-source",
+			data: `apiVersion: operators.coreos.com/v1alpha1
+kind: CatalogSource
+metadata:
+  name: cs-redhat-operator-index
+  namespace: openshift-marketplace
+spec:
+  image: registry.example.com:8443/redhat/redhat-operator-index:v4.13
+  sourceType: grpc
+`,
+		},
+		{
+			name: "missing-mirrors",
+			data: `apiVersion: config.openshift.io/v1
+kind: ImageDigestMirrorSet
+metadata:
+  name: idms-release-0
+spec:
+  imageDigestMirrors:
+  - source: quay.io/openshift-release-dev/ocp-v4.0-art-dev
+`,
+			expectedError: "oc-mirror/idms-oc-mirror.yaml: no mirror is set for quay.io/openshift-release-dev/ocp-v4.0-art-dev",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sources, err := mirrorSourcesFromManifests(&asset.File{
+				Filename: "oc-mirror/idms-oc-mirror.yaml",
+				Data:     []byte(tc.data),
+			})
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedSources, sources)
+			}
+		})
+	}
+}
+
+func TestValidateReleaseImageMirrored(t *testing.T) {
+	sources := []types.ImageDigestSource{
+		{Source: "quay.io/openshift-release-dev/ocp-release", Mirrors: []string{"registry.example.com:8443/openshift/release-images"}},
+		{Source: "registry.ci.openshift.org", Mirrors: []string{"registry.example.com:8443/ci"}},
+	}
+	cases := []struct {
+		name          string
+		releaseImage  string
+		expectedError string
+	}{
+		{
+			name:         "digest",
+			releaseImage: "quay.io/openshift-release-dev/ocp-release@sha256:0a31c0b1d0e1a6c5c4f7d1f8e4b0d8b4e8e7f8a8e3d0b1a9c6d1e0a2b3c4d5e6",
+		},
+		{
+			name:         "tag",
+			releaseImage: "quay.io/openshift-release-dev/ocp-release:4.13.0-x86_64",
+		},
+		{
+			name:         "registry",
+			releaseImage: "registry.ci.openshift.org/ocp/release:4.13.0-0.ci",
+		},
+		{
+			name:          "not-mirrored",
+			releaseImage:  "quay.io/openshift-release-dev/ocp-release-nightly:4.13.0",
+			expectedError: "the release image quay.io/openshift-release-dev/ocp-release-nightly:4.13.0 is not mirrored by the manifests of the oc-mirror directory",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateReleaseImageMirrored(tc.releaseImage, sources)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/openshift/installer/pkg/asset/agent"
 	"github.com/openshift/installer/pkg/asset/ignition/bootstrap"
 	"github.com/openshift/installer/pkg/asset/releaseimage"
+	"github.com/openshift/installer/pkg/types"
 )
 
 var (
@@ -129,24 +130,32 @@ func (*RegistriesConf) Dependencies() []asset.Asset {
 	return []asset.Asset{
 		&agent.OptionalInstallConfig{},
 		&releaseimage.Image{},
+		&OCMirror{},
 	}
 }
 
-// Generate generates the registries.conf file from install-config.
+// Generate generates the registries.conf file from install-config and the
+// results of oc-mirror.
 func (i *RegistriesConf) Generate(dependencies asset.Parents) error {
 
 	installConfig := &agent.OptionalInstallConfig{}
 	releaseImage := &releaseimage.Image{}
-	dependencies.Get(installConfig, releaseImage)
+	ocMirror := &OCMirror{}
+	dependencies.Get(installConfig, releaseImage, ocMirror)
 
-	if !installConfig.Supplied || len(installConfig.Config.ImageContentSources) == 0 {
+	var sources []types.ImageDigestSource
+	if installConfig.Supplied {
+		sources = append(sources, installConfig.Config.MirrorSources()...)
+	}
+	sources = append(sources, ocMirror.Sources...)
+	if len(sources) == 0 {
 		return i.generateDefaultRegistriesConf()
 	}
 
 	registries := &sysregistriesv2.V2RegistriesConf{
 		Registries: []sysregistriesv2.Registry{},
 	}
-	for _, group := range bootstrap.MergedMirrorSets(installConfig.Config.ImageContentSources) {
+	for _, group := range bootstrap.MergedMirrorSets(sources) {
 		if len(group.Mirrors) == 0 {
 			continue
 		}
@@ -162,9 +171,15 @@ func (i *RegistriesConf) Generate(dependencies asset.Parents) error {
 	i.Config = registries
 	i.setMirrorConfig(i.Config)
 
-	releaseImagePath := strings.Split(releaseImage.PullSpec, ":")[0]
-	if found := i.validateReleaseImageIsSameInRegistriesConf(releaseImagePath); !found {
-		logrus.Warnf(fmt.Sprintf("The ImageContentSources configuration in install-config.yaml should have at-least one source field matching the releaseImage value %s", releaseImagePath))
+	if len(ocMirror.Sources) > 0 {
+		if err := validateReleaseImageMirrored(releaseImage.PullSpec, ocMirror.Sources); err != nil {
+			return err
+		}
+	} else {
+		releaseImagePath := strings.Split(releaseImage.PullSpec, ":")[0]
+		if found := i.validateReleaseImageIsSameInRegistriesConf(releaseImagePath); !found {
+			logrus.Warnf(fmt.Sprintf("The imageDigestSources or imageContentSources configuration in install-config.yaml should have at-least one source field matching the releaseImage value %s", releaseImagePath))
+		}
 	}
 
 	registriesData, err := toml.Marshal(registries)
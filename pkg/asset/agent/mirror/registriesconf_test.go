@@ -132,6 +132,45 @@ func TestRegistriesConf_Generate(t *testing.T) {
 
   [[registry.mirror]]
     location = "virthost.ostest.test.metalkube.org:5000/localimages/local-release-image"
+`,
+		},
+		{
+			name: "oc-mirror",
+			dependencies: []asset.Asset{
+				&agent.OptionalInstallConfig{},
+				&releaseimage.Image{
+					PullSpec: "quay.io/openshift-release-dev/ocp-release@sha256:0a31c0b1d0e1a6c5c4f7d1f8e4b0d8b4e8e7f8a8e3d0b1a9c6d1e0a2b3c4d5e6",
+				},
+				&OCMirror{
+					Sources: []types.ImageDigestSource{
+						{
+							Source:  "quay.io/openshift-release-dev/ocp-release",
+							Mirrors: []string{"registry.example.com:8443/openshift/release-images"},
+						},
+						{
+							Source:  "quay.io/openshift-release-dev/ocp-v4.0-art-dev",
+							Mirrors: []string{"registry.example.com:8443/openshift/release"},
+						},
+					},
+				},
+			},
+			expectedConfig: `unqualified-search-registries = []
+
+[[registry]]
+  location = "quay.io/openshift-release-dev/ocp-release"
+  mirror-by-digest-only = true
+  prefix = ""
+
+  [[registry.mirror]]
+    location = "registry.example.com:8443/openshift/release-images"
+
+[[registry]]
+  location = "quay.io/openshift-release-dev/ocp-v4.0-art-dev"
+  mirror-by-digest-only = true
+  prefix = ""
+
+  [[registry.mirror]]
+    location = "registry.example.com:8443/openshift/release"
 `,
 		},
 	}
@@ -139,6 +178,7 @@ func TestRegistriesConf_Generate(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 
 			parents := asset.Parents{}
+			parents.Add(&OCMirror{})
 			parents.Add(tc.dependencies...)
 
 			asset := &RegistriesConf{}
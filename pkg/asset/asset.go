@@ -1,13 +1,19 @@
 package asset
 
 import (
+	"context"
 	"io"
 	"os"
 	"path/filepath"
 	"sort"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/credentialsfile"
+	"github.com/openshift/installer/pkg/deterministic"
+	"github.com/openshift/installer/pkg/types"
 )
 
 const (
@@ -42,6 +48,68 @@ type WritableAsset interface {
 	Load(FileFetcher) (found bool, err error)
 }
 
+// ContextAsset is an Asset whose generation calls remote services or runs
+// processes in the assets directory, e.g. to provision the infrastructure.
+// The store generates it with GenerateWithContext instead of Generate.
+type ContextAsset interface {
+	Asset
+
+	// GenerateWithContext generates this asset given the states of its
+	// parent assets, with the context of the fetch and the assets directory
+	// of the store.
+	GenerateWithContext(ctx context.Context, dir string, parents Parents) error
+}
+
+// SourcedAsset is an Asset that draws random values or the current time when
+// it is generated. The store sets the source to draw them from before
+// generating it.
+type SourcedAsset interface {
+	Asset
+
+	// SetSource sets the source of the random values and of the time the
+	// asset is generated with.
+	SetSource(*deterministic.Source)
+}
+
+// Options are the options of an install which are not part of the
+// install-config, e.g. those set with the flags of the create command.
+type Options struct {
+	// PolicyDir is the directory of the policies the install-config and
+	// the manifests are evaluated against, or "" for none.
+	PolicyDir string
+	// CertificateValidity is how long the certificates the installer
+	// generates are valid instead of their defaults, by the base name of
+	// their files in the tls directory, e.g. root-ca or
+	// admin-kubeconfig-signer.
+	CertificateValidity map[string]time.Duration
+	// ReleaseImage is the release image to install instead of the one the
+	// installer was built for, or nil.
+	ReleaseImage *types.ReleaseImage
+	// CheckRegistryAccess enables the connectivity checks of the registry
+	// of the release image which go through the network of the installer
+	// host, e.g. through the proxy of the install-config or with the pull
+	// secret.
+	CheckRegistryAccess bool
+	// AllowUnknownFields warns about the unknown fields of the
+	// install-config and ignores them, or decodes those which differ from a
+	// known field only by their case, instead of rejecting them.
+	AllowUnknownFields bool
+	// Credentials are the cloud credentials the sessions of the platform
+	// are created with, in place of those of the credentials file of the
+	// invocation, of the environment and of the user, or nil.
+	Credentials *credentialsfile.File
+}
+
+// ConfiguredAsset is an Asset that depends on the options of the install.
+// The store sets the options before loading or generating it.
+type ConfiguredAsset interface {
+	Asset
+
+	// SetOptions sets the options of the install the asset is generated
+	// with.
+	SetOptions(Options)
+}
+
 // File is a file for an Asset.
 type File struct {
 	// Filename is the name of the file.
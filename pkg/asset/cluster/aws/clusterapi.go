@@ -0,0 +1,58 @@
+package aws
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/installer/pkg/infrastructure/clusterapi"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// ClusterAPIManifests returns the Cluster and the AWSCluster describing the
+// infrastructure of the cluster to cluster-api-provider-aws.
+func ClusterAPIManifests(infraID string, config *types.InstallConfig) []*unstructured.Unstructured {
+	platform := config.Platform.AWS
+
+	tags := map[string]interface{}{
+		fmt.Sprintf("kubernetes.io/cluster/%s", infraID): "owned",
+	}
+	for key, value := range platform.UserTags {
+		tags[key] = value
+	}
+
+	network := map[string]interface{}{}
+	if len(platform.Subnets) == 0 {
+		network["vpc"] = map[string]interface{}{
+			"cidrBlock": config.Networking.MachineNetwork[0].CIDR.String(),
+		}
+	} else {
+		subnets := make([]interface{}, 0, len(platform.Subnets))
+		for _, id := range platform.Subnets {
+			subnets = append(subnets, map[string]interface{}{"id": id})
+		}
+		network["subnets"] = subnets
+	}
+
+	scheme := "internet-facing"
+	if config.Publish == types.InternalPublishingStrategy {
+		scheme = "internal"
+	}
+
+	awsCluster := &unstructured.Unstructured{}
+	awsCluster.SetAPIVersion("infrastructure.cluster.x-k8s.io/v1beta2")
+	awsCluster.SetKind("AWSCluster")
+	awsCluster.SetName(infraID)
+	awsCluster.Object["spec"] = map[string]interface{}{
+		"region":  platform.Region,
+		"network": network,
+		"controlPlaneLoadBalancer": map[string]interface{}{
+			"name":             fmt.Sprintf("%s-int", infraID),
+			"scheme":           scheme,
+			"loadBalancerType": "nlb",
+		},
+		"additionalTags": tags,
+	}
+
+	return []*unstructured.Unstructured{clusterapi.Cluster(awsCluster), awsCluster}
+}
@@ -2,35 +2,31 @@ package cluster
 
 import (
 	"context"
-	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/hashicorp/terraform-exec/tfexec"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/installer/pkg/asset"
-	"github.com/openshift/installer/pkg/asset/cluster/aws"
-	"github.com/openshift/installer/pkg/asset/cluster/azure"
-	"github.com/openshift/installer/pkg/asset/cluster/openstack"
 	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/manifests"
 	"github.com/openshift/installer/pkg/asset/password"
 	"github.com/openshift/installer/pkg/asset/quota"
-	"github.com/openshift/installer/pkg/metrics/timer"
-	"github.com/openshift/installer/pkg/terraform"
-	platformstages "github.com/openshift/installer/pkg/terraform/stages/platform"
-	typesaws "github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/checkpoint"
+	"github.com/openshift/installer/pkg/credentialsfile"
+	"github.com/openshift/installer/pkg/hooks"
+	"github.com/openshift/installer/pkg/infrastructure"
+	"github.com/openshift/installer/pkg/infrastructure/clusterapi"
+	"github.com/openshift/installer/pkg/offline"
+	"github.com/openshift/installer/pkg/types"
 	typesazure "github.com/openshift/installer/pkg/types/azure"
-	typesopenstack "github.com/openshift/installer/pkg/types/openstack"
 	typesvsphere "github.com/openshift/installer/pkg/types/vsphere"
 )
 
-var (
-	// InstallDir is the directory containing install assets.
-	InstallDir string
-)
+// preProvisionHooksCheckpoint is the checkpoint of the hooks run before the
+// infrastructure is provisioned.
+const preProvisionHooksCheckpoint = "pre-provision-hooks"
 
 // Cluster uses the terraform executable to launch a cluster
 // with the given terraform tfvar and generated templates.
@@ -38,7 +34,10 @@ type Cluster struct {
 	FileList []*asset.File
 }
 
-var _ asset.WritableAsset = (*Cluster)(nil)
+var (
+	_ asset.WritableAsset = (*Cluster)(nil)
+	_ asset.ContextAsset  = (*Cluster)(nil)
+)
 
 // Name returns the human-friendly name of the asset.
 func (c *Cluster) Name() string {
@@ -51,29 +50,37 @@ func (c *Cluster) Dependencies() []asset.Asset {
 	return []asset.Asset{
 		&installconfig.ClusterID{},
 		&installconfig.InstallConfig{},
-		// PlatformCredsCheck, PlatformPermsCheck and PlatformProvisionCheck
-		// perform validations & check perms required to provision infrastructure.
+		// PlatformCredsCheck, PlatformPermsCheck, PlatformProvisionCheck,
+		// ConnectivityCheck and ManualCredentialsCheck perform validations &
+		// check perms required to provision infrastructure and bootstrap the
+		// cluster.
 		// We do not actually use them in this asset directly, hence
 		// they are put in the dependencies but not fetched in Generate.
 		&installconfig.PlatformCredsCheck{},
 		&installconfig.PlatformPermsCheck{},
 		&installconfig.PlatformProvisionCheck{},
+		&installconfig.ConnectivityCheck{},
+		&manifests.ManualCredentialsCheck{},
 		&quota.PlatformQuotaCheck{},
 		&TerraformVariables{},
 		&password.KubeadminPassword{},
 	}
 }
 
-// Generate launches the cluster and generates the terraform state file on disk.
-func (c *Cluster) Generate(parents asset.Parents) (err error) {
-	if InstallDir == "" {
-		logrus.Fatalf("InstallDir has not been set for the %q asset", c.Name())
-	}
+// Generate is not supported, since the cluster is launched in the assets
+// directory of the store with GenerateWithContext.
+func (c *Cluster) Generate(asset.Parents) error {
+	return errors.Errorf("the %q asset must be generated by the asset store", c.Name())
+}
 
+// GenerateWithContext launches the cluster and generates the terraform state
+// file in the assets directory.
+func (c *Cluster) GenerateWithContext(ctx context.Context, dir string, parents asset.Parents) (err error) {
 	clusterID := &installconfig.ClusterID{}
 	installConfig := &installconfig.InstallConfig{}
-	terraformVariables := &TerraformVariables{}
-	parents.Get(clusterID, installConfig, terraformVariables)
+	parents.Get(clusterID, installConfig)
+	// terraform runs with the credentials of the install
+	ctx = credentialsfile.NewContext(ctx, installConfig.Credentials())
 
 	if fs := installConfig.Config.FeatureSet; strings.HasSuffix(string(fs), "NoUpgrade") {
 		logrus.Warnf("FeatureSet %q is enabled. This FeatureSet does not allow upgrades and may affect the supportability of the cluster.", fs)
@@ -87,64 +94,61 @@ func (c *Cluster) Generate(parents asset.Parents) (err error) {
 		return errors.New("cluster cannot be created with bootstrapInPlace set")
 	}
 
-	platform := installConfig.Config.Platform.Name()
-
-	if azure := installConfig.Config.Platform.Azure; azure != nil && azure.CloudName == typesazure.StackCloud {
-		platform = typesazure.StackTerraformName
-	}
-
-	if vsphere := installConfig.Config.Platform.VSphere; vsphere != nil {
-		if len(vsphere.FailureDomains) != 0 {
-			platform = typesvsphere.ZoningTerraformName
-		}
+	if err := offline.Require("Provisioning the cluster"); err != nil {
+		return err
 	}
 
-	stages := platformstages.StagesForPlatform(platform)
-
-	terraformDir := filepath.Join(InstallDir, "terraform")
-	if err := os.Mkdir(terraformDir, 0777); err != nil {
-		return errors.Wrap(err, "could not create the terraform directory")
-	}
+	provider := infrastructureProvider(installConfig.Config)
 
-	terraformDirPath, err := filepath.Abs(terraformDir)
+	// the hooks do not run again when an interrupted install is resumed
+	checkpoints, err := checkpoint.Load(dir)
 	if err != nil {
-		return errors.Wrap(err, "cannot get absolute path of terraform directory")
-	}
-
-	defer os.RemoveAll(terraformDir)
-	terraform.UnpackTerraform(terraformDirPath, stages)
-
-	logrus.Infof("Creating infrastructure resources...")
-	switch platform {
-	case typesaws.Name:
-		if err := aws.PreTerraform(context.TODO(), clusterID.InfraID, installConfig); err != nil {
-			return err
-		}
-	case typesazure.Name, typesazure.StackTerraformName:
-		if err := azure.PreTerraform(context.TODO(), clusterID.InfraID, installConfig); err != nil {
+		return err
+	}
+	if !checkpoints.Completed(preProvisionHooksCheckpoint) {
+		err = hooks.Run(ctx, installConfig.Config.Hooks, hooks.Event{
+			Phase:       hooks.PreProvision,
+			ClusterName: installConfig.Config.ObjectMeta.Name,
+			InfraID:     clusterID.InfraID,
+			AssetDir:    dir,
+		})
+		if err != nil {
 			return err
 		}
-	case typesopenstack.Name:
-		if err := openstack.PreTerraform(); err != nil {
+		if err := checkpoints.Complete(preProvisionHooksCheckpoint); err != nil {
 			return err
 		}
 	}
 
-	tfvarsFiles := make([]*asset.File, 0, len(terraformVariables.Files())+len(stages))
-	for _, file := range terraformVariables.Files() {
-		tfvarsFiles = append(tfvarsFiles, file)
-	}
+	logrus.Infof("Creating infrastructure resources...")
+	files, err := provider.Provision(ctx, dir, parents)
+	c.FileList = append(c.FileList, files...)
+	return err
+}
 
-	for _, stage := range stages {
-		outputs, err := c.applyStage(platform, stage, terraformDirPath, tfvarsFiles)
-		if err != nil {
-			return errors.Wrapf(err, "failure applying terraform for %q stage", stage.Name())
+// infrastructureProvider returns the provisioning backend of the platform of
+// the install-config: Cluster API for the platforms registered with it when
+// the TechPreviewNoUpgrade feature set is enabled and the install opts in to
+// it with OPENSHIFT_INSTALL_EXPERIMENTAL_CLUSTER_API, and terraform otherwise.
+func infrastructureProvider(config *types.InstallConfig) infrastructure.Provider {
+	if clusterAPIEnabled(config) {
+		if provider, ok := clusterapi.ProviderForPlatform(config.Platform.Name()); ok {
+			return provider
 		}
-		tfvarsFiles = append(tfvarsFiles, outputs)
-		c.FileList = append(c.FileList, outputs)
 	}
+	return &terraformProvider{platform: terraformPlatform(config)}
+}
 
-	return nil
+// terraformPlatform returns the platform of the terraform stages of the
+// install-config, which differs from the platform for e.g. Azure Stack.
+func terraformPlatform(config *types.InstallConfig) string {
+	if azure := config.Platform.Azure; azure != nil && azure.CloudName == typesazure.StackCloud {
+		return typesazure.StackTerraformName
+	}
+	if vsphere := config.Platform.VSphere; vsphere != nil && len(vsphere.FailureDomains) != 0 {
+		return typesvsphere.ZoningTerraformName
+	}
+	return config.Platform.Name()
 }
 
 // Files returns the FileList generated by the asset.
@@ -165,55 +169,3 @@ func (c *Cluster) Load(f asset.FileFetcher) (found bool, err error) {
 
 	return false, nil
 }
-
-func (c *Cluster) applyStage(platform string, stage terraform.Stage, terraformDir string, tfvarsFiles []*asset.File) (*asset.File, error) {
-	// Copy the terraform.tfvars to a temp directory which will contain the terraform plan.
-	tmpDir, err := os.MkdirTemp("", fmt.Sprintf("openshift-install-%s-", stage.Name()))
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create temp dir for terraform execution")
-	}
-	defer os.RemoveAll(tmpDir)
-
-	var extraOpts []tfexec.ApplyOption
-	for _, file := range tfvarsFiles {
-		if err := os.WriteFile(filepath.Join(tmpDir, file.Filename), file.Data, 0o600); err != nil {
-			return nil, err
-		}
-		extraOpts = append(extraOpts, tfexec.VarFile(filepath.Join(tmpDir, file.Filename)))
-	}
-
-	return c.applyTerraform(tmpDir, platform, stage, terraformDir, extraOpts...)
-}
-
-func (c *Cluster) applyTerraform(tmpDir string, platform string, stage terraform.Stage, terraformDir string, opts ...tfexec.ApplyOption) (*asset.File, error) {
-	timer.StartTimer(stage.Name())
-	defer timer.StopTimer(stage.Name())
-
-	applyErr := terraform.Apply(tmpDir, platform, stage, terraformDir, opts...)
-
-	// Write the state file to the install directory even if the apply failed.
-	if data, err := os.ReadFile(filepath.Join(tmpDir, terraform.StateFilename)); err == nil {
-		c.FileList = append(c.FileList, &asset.File{
-			Filename: stage.StateFilename(),
-			Data:     data,
-		})
-	} else if !os.IsNotExist(err) {
-		logrus.Errorf("Failed to read tfstate: %v", err)
-		return nil, errors.Wrap(err, "failed to read tfstate")
-	}
-
-	if applyErr != nil {
-		return nil, errors.Wrap(applyErr, asset.ClusterCreationError)
-	}
-
-	outputs, err := terraform.Outputs(tmpDir, terraformDir)
-	if err != nil {
-		return nil, errors.Wrapf(err, "could not get outputs from stage %q", stage.Name())
-	}
-
-	outputsFile := &asset.File{
-		Filename: stage.OutputsFilename(),
-		Data:     outputs,
-	}
-	return outputsFile, nil
-}
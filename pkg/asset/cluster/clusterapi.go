@@ -0,0 +1,52 @@
+package cluster
+
+import (
+	"context"
+	"os"
+	"strconv"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/cluster/aws"
+	"github.com/openshift/installer/pkg/asset/cluster/gcp"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/infrastructure/clusterapi"
+	"github.com/openshift/installer/pkg/types"
+	typesaws "github.com/openshift/installer/pkg/types/aws"
+	typesgcp "github.com/openshift/installer/pkg/types/gcp"
+)
+
+// clusterAPIEnv opts the installs of the TechPreviewNoUpgrade feature set in
+// to the Cluster API backend. The backend is experimental: it needs the etcd
+// and kube-apiserver binaries embedded by the build, and only provisions the
+// network and the load balancers of the cluster.
+const clusterAPIEnv = "OPENSHIFT_INSTALL_EXPERIMENTAL_CLUSTER_API"
+
+func init() {
+	clusterapi.Register(typesaws.Name, "cluster-api-provider-aws", clusterAPIManifests(aws.ClusterAPIManifests))
+	clusterapi.Register(typesgcp.Name, "cluster-api-provider-gcp", clusterAPIManifests(gcp.ClusterAPIManifests))
+}
+
+// clusterAPIManifests returns the manifests of the Cluster API backend from
+// the install-config and the infra ID of the parents of the Cluster asset.
+func clusterAPIManifests(manifests func(infraID string, config *types.InstallConfig) []*unstructured.Unstructured) clusterapi.ManifestsFunc {
+	return func(ctx context.Context, parents asset.Parents) ([]*unstructured.Unstructured, error) {
+		clusterID := &installconfig.ClusterID{}
+		installConfig := &installconfig.InstallConfig{}
+		parents.Get(clusterID, installConfig)
+		return manifests(clusterID.InfraID, installConfig.Config), nil
+	}
+}
+
+// clusterAPIEnabled returns whether the infrastructure is provisioned with
+// Cluster API, which is a tech preview for the platforms registered with it
+// that the installs opt in to with clusterAPIEnv.
+func clusterAPIEnabled(config *types.InstallConfig) bool {
+	if config.FeatureSet != configv1.TechPreviewNoUpgrade {
+		return false
+	}
+	enabled, _ := strconv.ParseBool(os.Getenv(clusterAPIEnv))
+	return enabled
+}
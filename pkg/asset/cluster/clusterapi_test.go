@@ -0,0 +1,96 @@
+package cluster
+
+import (
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/installer/pkg/asset/cluster/aws"
+	"github.com/openshift/installer/pkg/infrastructure"
+	"github.com/openshift/installer/pkg/ipnet"
+	"github.com/openshift/installer/pkg/types"
+	typesaws "github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/libvirt"
+)
+
+func TestInfrastructureProvider(t *testing.T) {
+	cases := []struct {
+		name       string
+		platform   types.Platform
+		featureSet configv1.FeatureSet
+		optIn      string
+		expected   string
+	}{
+		{
+			name:     "terraform by default",
+			platform: types.Platform{AWS: &typesaws.Platform{}},
+			expected: infrastructure.Terraform,
+		},
+		{
+			name:       "terraform in tech preview without the opt-in",
+			platform:   types.Platform{AWS: &typesaws.Platform{}},
+			featureSet: configv1.TechPreviewNoUpgrade,
+			expected:   infrastructure.Terraform,
+		},
+		{
+			name:     "terraform with the opt-in outside tech preview",
+			platform: types.Platform{AWS: &typesaws.Platform{}},
+			optIn:    "true",
+			expected: infrastructure.Terraform,
+		},
+		{
+			name:       "Cluster API in tech preview with the opt-in",
+			platform:   types.Platform{AWS: &typesaws.Platform{}},
+			featureSet: configv1.TechPreviewNoUpgrade,
+			optIn:      "true",
+			expected:   infrastructure.ClusterAPI,
+		},
+		{
+			name:       "terraform in tech preview for the platforms without Cluster API",
+			platform:   types.Platform{Libvirt: &libvirt.Platform{}},
+			featureSet: configv1.TechPreviewNoUpgrade,
+			optIn:      "true",
+			expected:   infrastructure.Terraform,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(clusterAPIEnv, tc.optIn)
+			config := &types.InstallConfig{Platform: tc.platform, FeatureSet: tc.featureSet}
+			assert.Equal(t, tc.expected, infrastructureProvider(config).Name())
+		})
+	}
+}
+
+func TestAWSClusterAPIManifests(t *testing.T) {
+	config := &types.InstallConfig{
+		Platform: types.Platform{AWS: &typesaws.Platform{
+			Region:   "us-east-1",
+			UserTags: map[string]string{"team": "installer"},
+		}},
+		Networking: &types.Networking{
+			MachineNetwork: []types.MachineNetworkEntry{{CIDR: *ipnet.MustParseCIDR("10.0.0.0/16")}},
+		},
+		Publish: types.InternalPublishingStrategy,
+	}
+	objects := aws.ClusterAPIManifests("test-abcde", config)
+	if !assert.Len(t, objects, 2) {
+		return
+	}
+	cluster, awsCluster := objects[0], objects[1]
+	assert.Equal(t, "Cluster", cluster.GetKind())
+	assert.Equal(t, "test-abcde", cluster.GetName())
+	ref, _, _ := unstructured.NestedString(cluster.Object, "spec", "infrastructureRef", "kind")
+	assert.Equal(t, "AWSCluster", ref)
+
+	region, _, _ := unstructured.NestedString(awsCluster.Object, "spec", "region")
+	assert.Equal(t, "us-east-1", region)
+	cidr, _, _ := unstructured.NestedString(awsCluster.Object, "spec", "network", "vpc", "cidrBlock")
+	assert.Equal(t, "10.0.0.0/16", cidr)
+	scheme, _, _ := unstructured.NestedString(awsCluster.Object, "spec", "controlPlaneLoadBalancer", "scheme")
+	assert.Equal(t, "internal", scheme)
+	tags, _, _ := unstructured.NestedStringMap(awsCluster.Object, "spec", "additionalTags")
+	assert.Equal(t, map[string]string{"kubernetes.io/cluster/test-abcde": "owned", "team": "installer"}, tags)
+}
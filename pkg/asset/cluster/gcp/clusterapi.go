@@ -0,0 +1,45 @@
+package gcp
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/installer/pkg/infrastructure/clusterapi"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// ClusterAPIManifests returns the Cluster and the GCPCluster describing the
+// infrastructure of the cluster to cluster-api-provider-gcp.
+func ClusterAPIManifests(infraID string, config *types.InstallConfig) []*unstructured.Unstructured {
+	platform := config.Platform.GCP
+
+	network := map[string]interface{}{
+		"name": fmt.Sprintf("%s-network", infraID),
+	}
+	if platform.Network != "" {
+		network["name"] = platform.Network
+		subnets := []interface{}{}
+		for _, subnet := range []string{platform.ControlPlaneSubnet, platform.ComputeSubnet} {
+			if subnet != "" {
+				subnets = append(subnets, map[string]interface{}{"name": subnet, "region": platform.Region})
+			}
+		}
+		network["subnets"] = subnets
+	}
+
+	gcpCluster := &unstructured.Unstructured{}
+	gcpCluster.SetAPIVersion("infrastructure.cluster.x-k8s.io/v1beta1")
+	gcpCluster.SetKind("GCPCluster")
+	gcpCluster.SetName(infraID)
+	gcpCluster.Object["spec"] = map[string]interface{}{
+		"project": platform.ProjectID,
+		"region":  platform.Region,
+		"network": network,
+		"additionalLabels": map[string]interface{}{
+			fmt.Sprintf("kubernetes-io-cluster-%s", infraID): "owned",
+		},
+	}
+
+	return []*unstructured.Unstructured{clusterapi.Cluster(gcpCluster), gcpCluster}
+}
@@ -0,0 +1,99 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/credentialsfile"
+	"github.com/openshift/installer/pkg/infrastructure"
+)
+
+// InfraPlanFileName is the file the infrastructure plan is written to.
+const InfraPlanFileName = "infra-plan.json"
+
+// InfraPlan is the infrastructure the provisioning backend would create for
+// the cluster, for review before creating it.
+type InfraPlan struct {
+	File *asset.File
+}
+
+var (
+	_ asset.WritableAsset = (*InfraPlan)(nil)
+	_ asset.ContextAsset  = (*InfraPlan)(nil)
+)
+
+// Name returns the human-friendly name of the asset.
+func (p *InfraPlan) Name() string {
+	return "Infrastructure Plan"
+}
+
+// Dependencies returns the direct dependencies for planning the
+// infrastructure.
+func (p *InfraPlan) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.ClusterID{},
+		&installconfig.InstallConfig{},
+		// The backends read the platform, so the credentials must be valid.
+		&installconfig.PlatformCredsCheck{},
+		&TerraformVariables{},
+	}
+}
+
+// Generate is not supported, since the infrastructure is planned in the
+// assets directory of the store with GenerateWithContext.
+func (p *InfraPlan) Generate(asset.Parents) error {
+	return errors.Errorf("the %q asset must be generated by the asset store", p.Name())
+}
+
+// GenerateWithContext plans the infrastructure with the provisioning backend.
+func (p *InfraPlan) GenerateWithContext(ctx context.Context, dir string, parents asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	parents.Get(installConfig)
+	// terraform runs with the credentials of the install
+	ctx = credentialsfile.NewContext(ctx, installConfig.Credentials())
+
+	if installConfig.Config.Platform.None != nil {
+		return errors.New("infrastructure cannot be planned with platform set to 'none'")
+	}
+
+	provider := infrastructureProvider(installConfig.Config)
+	planner, ok := provider.(infrastructure.Planner)
+	if !ok {
+		return errors.Errorf("the %s provisioning backend cannot plan the infrastructure", provider.Name())
+	}
+
+	logrus.Infof("Planning infrastructure resources...")
+	plan, err := planner.Plan(ctx, dir, parents)
+	if err != nil {
+		return errors.Wrap(err, "failed to plan the infrastructure")
+	}
+
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the infrastructure plan")
+	}
+	p.File = &asset.File{
+		Filename: InfraPlanFileName,
+		Data:     data,
+	}
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (p *InfraPlan) Files() []*asset.File {
+	if p.File != nil {
+		return []*asset.File{p.File}
+	}
+	return []*asset.File{}
+}
+
+// Load always plans the infrastructure again, since it may have changed
+// since the plan on disk was written.
+func (p *InfraPlan) Load(f asset.FileFetcher) (found bool, err error) {
+	return false, nil
+}
@@ -0,0 +1,168 @@
+package cluster
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// LoadBalancerFileName is the file the backends of a user-managed load
+// balancer are written to.
+const LoadBalancerFileName = "load-balancer.json"
+
+// LoadBalancer is the configuration a user-managed load balancer of an
+// on-prem platform needs: its frontends and, for each of them, the nodes
+// behind it and how to check their health.
+type LoadBalancer struct {
+	File *asset.File
+}
+
+var _ asset.WritableAsset = (*LoadBalancer)(nil)
+
+// loadBalancerConfig is the content of the load balancer file.
+type loadBalancerConfig struct {
+	Frontends []loadBalancerFrontend `json:"frontends"`
+}
+
+type loadBalancerFrontend struct {
+	// Name is the name of the frontend: api, api-int or ingress.
+	Name string `json:"name"`
+	// Host is the DNS name which must resolve to the frontend.
+	Host string `json:"host"`
+	// Addresses are the VIPs of the platform, if set.
+	Addresses []string `json:"addresses,omitempty"`
+	// Ports are the TCP ports to forward to the backends.
+	Ports []loadBalancerPort `json:"ports"`
+	// Backends are the nodes to forward the connections to.
+	Backends loadBalancerBackends `json:"backends"`
+}
+
+type loadBalancerPort struct {
+	Port        int                     `json:"port"`
+	HealthCheck loadBalancerHealthCheck `json:"healthCheck"`
+}
+
+// loadBalancerHealthCheck is the request to check the health of a backend.
+type loadBalancerHealthCheck struct {
+	Protocol string `json:"protocol"`
+	Port     int    `json:"port"`
+	Path     string `json:"path"`
+}
+
+type loadBalancerBackends struct {
+	// Roles are the roles of the nodes to forward the connections to. The
+	// bootstrap machine must be removed once the bootstrapping is complete.
+	Roles []string `json:"roles"`
+	// Hosts are the names of the hosts with those roles, when the
+	// install-config lists them.
+	Hosts []string `json:"hosts,omitempty"`
+}
+
+// healthChecks are the health checks of the ports of the frontends.
+var healthChecks = map[int]loadBalancerHealthCheck{
+	6443:  {Protocol: "HTTPS", Port: 6443, Path: "/readyz"},
+	22623: {Protocol: "HTTPS", Port: 22623, Path: "/healthz"},
+	80:    {Protocol: "HTTP", Port: 1936, Path: "/healthz/ready"},
+	443:   {Protocol: "HTTP", Port: 1936, Path: "/healthz/ready"},
+}
+
+// Name returns the human-friendly name of the asset.
+func (lb *LoadBalancer) Name() string {
+	return "Load Balancer"
+}
+
+// Dependencies returns the direct dependencies of the load balancer.
+func (lb *LoadBalancer) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the load balancer file when the load balancer of the
+// platform is managed by the user.
+func (lb *LoadBalancer) Generate(parents asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	parents.Get(installConfig)
+	ic := installConfig.Config
+
+	lb.File = nil
+	if !ic.Platform.IsLoadBalancerUserManaged() {
+		return nil
+	}
+
+	apiVIPs, ingressVIPs := installconfig.LoadBalancerVIPs(&ic.Platform)
+	masters := loadBalancerBackends{Roles: []string{"master"}, Hosts: hostsWithRole(&ic.Platform, "master")}
+	api := loadBalancerBackends{Roles: []string{"bootstrap", "master"}, Hosts: masters.Hosts}
+	ingress := loadBalancerBackends{Roles: []string{"worker"}, Hosts: hostsWithRole(&ic.Platform, "worker")}
+	if pool := ic.WorkerMachinePool(); pool == nil || (pool.Replicas != nil && *pool.Replicas == 0) {
+		// The routers run on the schedulable control plane.
+		ingress = masters
+	}
+
+	config := loadBalancerConfig{}
+	for _, frontend := range installconfig.LoadBalancerFrontends(ic) {
+		f := loadBalancerFrontend{
+			Name:      frontend.Name,
+			Host:      frontend.Host,
+			Addresses: apiVIPs,
+			Backends:  api,
+		}
+		if frontend.Name == "ingress" {
+			f.Host = "*." + ic.AppsDomainName()
+			f.Addresses = ingressVIPs
+			f.Backends = ingress
+		}
+		for _, port := range frontend.Ports {
+			f.Ports = append(f.Ports, loadBalancerPort{Port: port, HealthCheck: healthChecks[port]})
+		}
+		config.Frontends = append(config.Frontends, f)
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the load balancer")
+	}
+	lb.File = &asset.File{
+		Filename: LoadBalancerFileName,
+		Data:     data,
+	}
+	return nil
+}
+
+// hostsWithRole returns the names of the hosts of the platform with the
+// role.
+func hostsWithRole(p *types.Platform, role string) []string {
+	var names []string
+	switch {
+	case p.BareMetal != nil:
+		for _, host := range p.BareMetal.Hosts {
+			if host.Role == role {
+				names = append(names, host.Name)
+			}
+		}
+	case p.VSphere != nil:
+		for _, host := range p.VSphere.Hosts {
+			if host.Role == role {
+				names = append(names, host.Name)
+			}
+		}
+	}
+	return names
+}
+
+// Files returns the files generated by the asset.
+func (lb *LoadBalancer) Files() []*asset.File {
+	if lb.File != nil {
+		return []*asset.File{lb.File}
+	}
+	return []*asset.File{}
+}
+
+// Load always generates the load balancer again from the install-config.
+func (lb *LoadBalancer) Load(f asset.FileFetcher) (found bool, err error) {
+	return false, nil
+}
@@ -0,0 +1,387 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/cluster/aws"
+	"github.com/openshift/installer/pkg/asset/cluster/azure"
+	"github.com/openshift/installer/pkg/asset/cluster/openstack"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/checkpoint"
+	"github.com/openshift/installer/pkg/infrastructure"
+	"github.com/openshift/installer/pkg/metrics/timer"
+	"github.com/openshift/installer/pkg/terraform"
+	platformstages "github.com/openshift/installer/pkg/terraform/stages/platform"
+	typesaws "github.com/openshift/installer/pkg/types/aws"
+	typesazure "github.com/openshift/installer/pkg/types/azure"
+	typesopenstack "github.com/openshift/installer/pkg/types/openstack"
+)
+
+const (
+	// unpackTimer times unpacking terraform and its providers.
+	unpackTimer = "Terraform Unpack"
+	// preTerraformTimer times the platform steps run before terraform.
+	preTerraformTimer = "Pre-Terraform"
+	// maxParallelStages is how many terraform stages are applied at a time,
+	// when they do not depend on each other.
+	maxParallelStages = 4
+	// preTerraformCheckpoint is the checkpoint of the platform steps run
+	// before terraform.
+	preTerraformCheckpoint = "infrastructure/pre-terraform"
+)
+
+// terraformProvider provisions the infrastructure by applying the terraform
+// stages of the platform.
+type terraformProvider struct {
+	platform string
+}
+
+var (
+	_ infrastructure.Provider = (*terraformProvider)(nil)
+	_ infrastructure.Planner  = (*terraformProvider)(nil)
+)
+
+// stageResult is the state and outputs files of an applied stage.
+type stageResult struct {
+	state   *asset.File
+	outputs *asset.File
+}
+
+// Name returns the name of the provisioning backend.
+func (p *terraformProvider) Name() string {
+	return infrastructure.Terraform
+}
+
+// Provision applies the terraform stages of the platform, and returns their
+// state and outputs files. Terraform is unpacked while the platform steps
+// which come before terraform run, and the stages which do not depend on
+// each other are applied concurrently.
+//
+// Each completed step is checkpointed, so that an interrupted provisioning
+// resumes when it is re-run: the completed steps are skipped, with the state
+// and outputs files of the completed stages read from the assets directory,
+// and the stage which was interrupted is applied again from its state file.
+// Once the context is canceled no more stages are started, but the running
+// ones complete.
+func (p *terraformProvider) Provision(ctx context.Context, dir string, parents asset.Parents) ([]*asset.File, error) {
+	clusterID := &installconfig.ClusterID{}
+	installConfig := &installconfig.InstallConfig{}
+	terraformVariables := &TerraformVariables{}
+	parents.Get(clusterID, installConfig, terraformVariables)
+
+	stages := platformstages.StagesForPlatform(p.platform)
+
+	checkpoints, err := checkpoint.Load(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	terraformDirPath, err := makeTerraformDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(terraformDirPath)
+
+	var wg sync.WaitGroup
+	var unpackErr error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		timer.StartTimer(unpackTimer)
+		defer timer.StopTimer(unpackTimer)
+		unpackErr = terraform.UnpackTerraform(terraformDirPath, stages)
+	}()
+	var preErr error
+	if checkpoints.Completed(preTerraformCheckpoint) {
+		logrus.Info("Resuming the provisioning of the infrastructure from its last completed step")
+	} else {
+		timer.StartTimer(preTerraformTimer)
+		preErr = p.preTerraform(ctx, clusterID.InfraID, installConfig)
+		timer.StopTimer(preTerraformTimer)
+		if preErr == nil {
+			preErr = checkpoints.Complete(preTerraformCheckpoint)
+		}
+	}
+	wg.Wait()
+	if preErr != nil {
+		return nil, preErr
+	}
+	if unpackErr != nil {
+		return nil, errors.Wrap(unpackErr, "failed to unpack terraform")
+	}
+
+	var mu sync.Mutex
+	results := make(map[string]stageResult, len(stages))
+	err = terraform.RunStages(stages, maxParallelStages, func(stage terraform.Stage, dependencies []terraform.Stage) error {
+		tfvarsFiles := append([]*asset.File{}, terraformVariables.Files()...)
+		mu.Lock()
+		for _, dependency := range dependencies {
+			tfvarsFiles = append(tfvarsFiles, results[dependency.Name()].outputs)
+		}
+		mu.Unlock()
+
+		var result stageResult
+		var err error
+		if checkpoints.Completed(stageCheckpoint(stage)) {
+			logrus.Infof("Skipping the %q stage, it already completed", stage.Name())
+			result, err = readStageResult(dir, stage)
+		} else if err = ctx.Err(); err == nil {
+			result, err = p.applyStage(ctx, dir, stage, terraformDirPath, tfvarsFiles)
+			if err == nil {
+				err = checkpoints.Complete(stageCheckpoint(stage))
+			}
+		}
+		mu.Lock()
+		results[stage.Name()] = result
+		mu.Unlock()
+		return errors.Wrapf(err, "failure applying terraform for %q stage", stage.Name())
+	})
+
+	files := make([]*asset.File, 0, 2*len(stages))
+	for _, stage := range stages {
+		if result, ok := results[stage.Name()]; ok {
+			if result.state != nil {
+				files = append(files, result.state)
+			}
+			if result.outputs != nil {
+				files = append(files, result.outputs)
+			}
+		}
+	}
+	return files, err
+}
+
+// Plan plans the terraform stages of the platform which depend on no other
+// stage. The others need the outputs of the stages they depend on, so they
+// are deferred.
+func (p *terraformProvider) Plan(ctx context.Context, dir string, parents asset.Parents) (*infrastructure.Plan, error) {
+	terraformVariables := &TerraformVariables{}
+	parents.Get(terraformVariables)
+
+	stages := platformstages.StagesForPlatform(p.platform)
+	dependencies, err := terraform.StageDependencies(stages)
+	if err != nil {
+		return nil, err
+	}
+
+	terraformDirPath, err := makeTerraformDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(terraformDirPath)
+	if err := terraform.UnpackTerraform(terraformDirPath, stages); err != nil {
+		return nil, errors.Wrap(err, "failed to unpack terraform")
+	}
+
+	plan := &infrastructure.Plan{Backend: infrastructure.Terraform}
+	for _, stage := range stages {
+		stagePlan := infrastructure.StagePlan{Name: stage.Name()}
+		if deps := dependencies[stage.Name()]; len(deps) > 0 {
+			stagePlan.DeferredBy = deps
+			plan.Stages = append(plan.Stages, stagePlan)
+			continue
+		}
+
+		stagePlan.Raw, err = p.planStage(ctx, stage, terraformDirPath, terraformVariables.Files())
+		if err != nil {
+			return nil, errors.Wrapf(err, "failure planning terraform for %q stage", stage.Name())
+		}
+		stagePlan.Changes, err = resourceChanges(stagePlan.Raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read the terraform plan of the %q stage", stage.Name())
+		}
+		plan.Stages = append(plan.Stages, stagePlan)
+	}
+	return plan, nil
+}
+
+func (p *terraformProvider) planStage(ctx context.Context, stage terraform.Stage, terraformDir string, tfvarsFiles []*asset.File) ([]byte, error) {
+	tmpDir, err := os.MkdirTemp("", fmt.Sprintf("openshift-install-%s-", stage.Name()))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp dir for terraform execution")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	varFiles, err := writeVarFiles(tmpDir, tfvarsFiles)
+	if err != nil {
+		return nil, err
+	}
+	opts := make([]tfexec.PlanOption, 0, len(varFiles))
+	for _, varFile := range varFiles {
+		opts = append(opts, varFile)
+	}
+	return terraform.Plan(ctx, tmpDir, p.platform, stage, terraformDir, opts...)
+}
+
+// resourceChanges returns the changes to the resources of the plan in the
+// format of terraform show -json, leaving out the resources left as they
+// are.
+func resourceChanges(plan []byte) ([]infrastructure.ResourceChange, error) {
+	var parsed struct {
+		ResourceChanges []struct {
+			Address string `json:"address"`
+			Type    string `json:"type"`
+			Change  struct {
+				Actions []string `json:"actions"`
+			} `json:"change"`
+		} `json:"resource_changes"`
+	}
+	if err := json.Unmarshal(plan, &parsed); err != nil {
+		return nil, err
+	}
+	changes := []infrastructure.ResourceChange{}
+	for _, rc := range parsed.ResourceChanges {
+		if len(rc.Change.Actions) == 1 && rc.Change.Actions[0] == "no-op" {
+			continue
+		}
+		changes = append(changes, infrastructure.ResourceChange{
+			Address: rc.Address,
+			Type:    rc.Type,
+			Actions: rc.Change.Actions,
+		})
+	}
+	return changes, nil
+}
+
+// makeTerraformDir creates the directory terraform and its providers are
+// unpacked to, and returns its absolute path.
+func makeTerraformDir(dir string) (string, error) {
+	terraformDir := filepath.Join(dir, "terraform")
+	if err := os.Mkdir(terraformDir, 0777); err != nil {
+		return "", errors.Wrap(err, "could not create the terraform directory")
+	}
+
+	terraformDirPath, err := filepath.Abs(terraformDir)
+	if err != nil {
+		os.RemoveAll(terraformDir)
+		return "", errors.Wrap(err, "cannot get absolute path of terraform directory")
+	}
+	return terraformDirPath, nil
+}
+
+// writeVarFiles writes the tfvars files to the directory, and returns the
+// options passing them to terraform.
+func writeVarFiles(dir string, tfvarsFiles []*asset.File) ([]*tfexec.VarFileOption, error) {
+	opts := make([]*tfexec.VarFileOption, 0, len(tfvarsFiles))
+	for _, file := range tfvarsFiles {
+		if err := os.WriteFile(filepath.Join(dir, file.Filename), file.Data, 0o600); err != nil {
+			return nil, err
+		}
+		opts = append(opts, tfexec.VarFile(filepath.Join(dir, file.Filename)))
+	}
+	return opts, nil
+}
+
+// preTerraform runs the steps of the platform which come before terraform.
+func (p *terraformProvider) preTerraform(ctx context.Context, infraID string, installConfig *installconfig.InstallConfig) error {
+	switch p.platform {
+	case typesaws.Name:
+		return aws.PreTerraform(ctx, infraID, installConfig)
+	case typesazure.Name, typesazure.StackTerraformName:
+		return azure.PreTerraform(ctx, infraID, installConfig)
+	case typesopenstack.Name:
+		return openstack.PreTerraform()
+	}
+	return nil
+}
+
+// stageCheckpoint returns the checkpoint of the stage.
+func stageCheckpoint(stage terraform.Stage) string {
+	return "infrastructure/stage/" + stage.Name()
+}
+
+// readStageResult reads the state and outputs files of a completed stage from
+// the assets directory.
+func readStageResult(dir string, stage terraform.Stage) (stageResult, error) {
+	var result stageResult
+	for _, file := range []struct {
+		name string
+		dest **asset.File
+	}{
+		{name: stage.StateFilename(), dest: &result.state},
+		{name: stage.OutputsFilename(), dest: &result.outputs},
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, file.name))
+		if err != nil {
+			return result, errors.Wrapf(err, "failed to read %s of the completed stage, destroy the cluster to install it again", file.name)
+		}
+		*file.dest = &asset.File{Filename: file.name, Data: data}
+	}
+	return result, nil
+}
+
+func (p *terraformProvider) applyStage(ctx context.Context, dir string, stage terraform.Stage, terraformDir string, tfvarsFiles []*asset.File) (stageResult, error) {
+	// Copy the terraform.tfvars to a temp directory which will contain the terraform plan.
+	tmpDir, err := os.MkdirTemp("", fmt.Sprintf("openshift-install-%s-", stage.Name()))
+	if err != nil {
+		return stageResult{}, errors.Wrap(err, "failed to create temp dir for terraform execution")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// An interrupted apply of the stage left its state file in the assets
+	// directory, which terraform continues from.
+	state, err := os.ReadFile(filepath.Join(dir, stage.StateFilename()))
+	if err == nil {
+		logrus.Infof("Resuming the %q stage from its state file", stage.Name())
+		if err := os.WriteFile(filepath.Join(tmpDir, terraform.StateFilename), state, 0o600); err != nil {
+			return stageResult{}, errors.Wrap(err, "failed to copy the state file of the stage")
+		}
+	} else if !os.IsNotExist(err) {
+		return stageResult{}, errors.Wrap(err, "failed to read the state file of the stage")
+	}
+
+	varFiles, err := writeVarFiles(tmpDir, tfvarsFiles)
+	if err != nil {
+		return stageResult{}, err
+	}
+	extraOpts := make([]tfexec.ApplyOption, 0, len(varFiles))
+	for _, varFile := range varFiles {
+		extraOpts = append(extraOpts, varFile)
+	}
+
+	return p.applyTerraform(ctx, tmpDir, stage, terraformDir, extraOpts...)
+}
+
+func (p *terraformProvider) applyTerraform(ctx context.Context, tmpDir string, stage terraform.Stage, terraformDir string, opts ...tfexec.ApplyOption) (stageResult, error) {
+	timer.StartTimer(stage.Name())
+	defer timer.StopTimer(stage.Name())
+
+	applyErr := terraform.Apply(ctx, tmpDir, p.platform, stage, terraformDir, opts...)
+
+	// Write the state file to the install directory even if the apply failed.
+	var result stageResult
+	if data, err := os.ReadFile(filepath.Join(tmpDir, terraform.StateFilename)); err == nil {
+		result.state = &asset.File{
+			Filename: stage.StateFilename(),
+			Data:     data,
+		}
+	} else if !os.IsNotExist(err) {
+		logrus.Errorf("Failed to read tfstate: %v", err)
+		return result, errors.Wrap(err, "failed to read tfstate")
+	}
+
+	if applyErr != nil {
+		return result, errors.Wrap(applyErr, asset.ClusterCreationError)
+	}
+
+	outputs, err := terraform.Outputs(tmpDir, terraformDir)
+	if err != nil {
+		return result, errors.Wrapf(err, "could not get outputs from stage %q", stage.Name())
+	}
+
+	result.outputs = &asset.File{
+		Filename: stage.OutputsFilename(),
+		Data:     outputs,
+	}
+	return result, nil
+}
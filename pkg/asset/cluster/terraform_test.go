@@ -0,0 +1,76 @@
+package cluster
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/infrastructure"
+	platformstages "github.com/openshift/installer/pkg/terraform/stages/platform"
+	typesaws "github.com/openshift/installer/pkg/types/aws"
+)
+
+func TestResourceChanges(t *testing.T) {
+	cases := []struct {
+		name     string
+		plan     string
+		expected []infrastructure.ResourceChange
+		err      bool
+	}{
+		{
+			name:     "no resources",
+			plan:     `{"format_version":"1.1"}`,
+			expected: []infrastructure.ResourceChange{},
+		},
+		{
+			name: "no-op left out",
+			plan: `{"resource_changes":[
+				{"address":"aws_vpc.new_vpc[0]","type":"aws_vpc","change":{"actions":["create"]}},
+				{"address":"data.aws_partition.current","type":"aws_partition","change":{"actions":["no-op"]}},
+				{"address":"aws_route53_record.api","type":"aws_route53_record","change":{"actions":["delete","create"]}}
+			]}`,
+			expected: []infrastructure.ResourceChange{
+				{Address: "aws_vpc.new_vpc[0]", Type: "aws_vpc", Actions: []string{"create"}},
+				{Address: "aws_route53_record.api", Type: "aws_route53_record", Actions: []string{"delete", "create"}},
+			},
+		},
+		{
+			name: "invalid",
+			plan: `{"resource_changes":{}}`,
+			err:  true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			changes, err := resourceChanges([]byte(tc.plan))
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, changes)
+		})
+	}
+}
+
+func TestReadStageResult(t *testing.T) {
+	dir := t.TempDir()
+	stage := platformstages.StagesForPlatform(typesaws.Name)[0]
+
+	_, err := readStageResult(dir, stage)
+	assert.Error(t, err, "the files of the stage are missing")
+
+	for _, name := range []string{stage.StateFilename(), stage.OutputsFilename()} {
+		if !assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name), 0o600)) {
+			return
+		}
+	}
+	result, err := readStageResult(dir, stage)
+	if assert.NoError(t, err) {
+		assert.Equal(t, &asset.File{Filename: stage.StateFilename(), Data: []byte(stage.StateFilename())}, result.state)
+		assert.Equal(t, &asset.File{Filename: stage.OutputsFilename(), Data: []byte(stage.OutputsFilename())}, result.outputs)
+	}
+}
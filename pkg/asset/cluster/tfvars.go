@@ -28,6 +28,7 @@ import (
 	"github.com/openshift/installer/pkg/asset/ignition/bootstrap"
 	baremetalbootstrap "github.com/openshift/installer/pkg/asset/ignition/bootstrap/baremetal"
 	"github.com/openshift/installer/pkg/asset/ignition/machine"
+	"github.com/openshift/installer/pkg/asset/ignition/userdata"
 	"github.com/openshift/installer/pkg/asset/installconfig"
 	awsconfig "github.com/openshift/installer/pkg/asset/installconfig/aws"
 	aztypes "github.com/openshift/installer/pkg/asset/installconfig/azure"
@@ -83,6 +84,12 @@ const (
 	// https://www.terraform.io/docs/configuration/variables.html#variable-files
 	TfPlatformVarsFileName = "terraform.platform.auto.tfvars.json"
 
+	// TfOverridesVarsFileName is the name of the Terraform variables file
+	// overriding the generated variables, from the file named by
+	// OPENSHIFT_INSTALL_TERRAFORM_VARIABLES. It is passed to terraform last,
+	// so that its variables take precedence.
+	TfOverridesVarsFileName = "terraform.overrides.auto.tfvars.json"
+
 	tfvarsAssetName = "Terraform Variables"
 )
 
@@ -139,7 +146,11 @@ func (t *TerraformVariables) Generate(parents asset.Parents) error {
 		return errors.Errorf("cannot create the cluster because %q is a UPI platform", platform)
 	}
 
-	masterIgn := string(masterIgnAsset.Files()[0].Data)
+	masterIgnData, err := userdata.Fit(ctx, platform, clusterID.InfraID, "master", masterIgnAsset.Files()[0].Data, installConfig.Config.Proxy, installConfig.Credentials().AWSCredentials())
+	if err != nil {
+		return err
+	}
+	masterIgn := string(masterIgnData)
 	bootstrapIgn, err := injectInstallInfo(bootstrapIgnAsset.Files()[0].Data)
 	if err != nil {
 		return errors.Wrap(err, "unable to inject installation info")
@@ -181,6 +192,7 @@ func (t *TerraformVariables) Generate(parents asset.Parents) error {
 		clusterID.InfraID,
 		installConfig.Config.ClusterDomain(),
 		installConfig.Config.BaseDomain,
+		installConfig.Config.APIDomainName(),
 		machineV4CIDRs,
 		machineV6CIDRs,
 		useIPv4,
@@ -322,6 +334,8 @@ func (t *TerraformVariables) Generate(parents asset.Parents) error {
 			TenantID:                  session.Credentials.TenantID,
 			ClientCertificatePath:     session.Credentials.ClientCertificatePath,
 			ClientCertificatePassword: session.Credentials.ClientCertificatePassword,
+			UseOIDC:                   session.Credentials.FederatedTokenFile != "",
+			OIDCTokenFilePath:         session.Credentials.FederatedTokenFile,
 		}
 		masters, err := mastersAsset.Machines()
 		if err != nil {
@@ -387,7 +401,7 @@ func (t *TerraformVariables) Generate(parents asset.Parents) error {
 			Data:     data,
 		})
 	case gcp.Name:
-		sess, err := gcpconfig.GetSession(ctx)
+		sess, err := gcpconfig.GetSessionWithCredentials(ctx, installConfig.Credentials().GCPCredentials())
 		if err != nil {
 			return err
 		}
@@ -418,7 +432,7 @@ func (t *TerraformVariables) Generate(parents asset.Parents) error {
 		// In the case of a shared vpn, the firewall rules should only be created if the user has permissions to do so
 		createFirewallRules := true
 		if installConfig.Config.GCP.NetworkProjectID != "" {
-			client, err := gcpconfig.NewClient(context.Background())
+			client, err := gcpconfig.NewClientWithCredentials(context.Background(), installConfig.Credentials().GCPCredentials())
 			if err != nil {
 				return err
 			}
@@ -876,7 +890,7 @@ func (t *TerraformVariables) Generate(parents asset.Parents) error {
 				CISInstanceCRN:       cisCRN,
 				DNSInstanceCRN:       dnsCRN,
 				PublishStrategy:      installConfig.Config.Publish,
-				EnableSNAT:           len(installConfig.Config.ImageContentSources) == 0,
+				EnableSNAT:           len(installConfig.Config.MirrorSources()) == 0,
 			},
 		)
 		if err != nil {
@@ -1075,9 +1089,39 @@ func (t *TerraformVariables) Generate(parents asset.Parents) error {
 		logrus.Warnf("unrecognized platform %s", platform)
 	}
 
+	if path := os.Getenv(tfvars.OverridesEnv); path != "" {
+		file, err := t.overrides(platform, path)
+		if err != nil {
+			return errors.Wrapf(err, "invalid %s", tfvars.OverridesEnv)
+		}
+		t.FileList = append(t.FileList, file)
+	}
+
 	return nil
 }
 
+// overrides returns the variables file overriding the generated variables
+// of the platform with the ones of the file at path.
+func (t *TerraformVariables) overrides(platform string, path string) (*asset.File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	generated := make([][]byte, 0, len(t.FileList))
+	for _, file := range t.FileList {
+		generated = append(generated, file.Data)
+	}
+	data, err = tfvars.Overrides(platform, data, generated...)
+	if err != nil {
+		return nil, err
+	}
+	logrus.Warnf("Overriding the Terraform variables with %s. Overridden variables are not supported by Red Hat.", path)
+	return &asset.File{
+		Filename: TfOverridesVarsFileName,
+		Data:     data,
+	}, nil
+}
+
 // Files returns the files generated by the asset.
 func (t *TerraformVariables) Files() []*asset.File {
 	return t.FileList
@@ -1094,11 +1138,13 @@ func (t *TerraformVariables) Load(f asset.FileFetcher) (found bool, err error) {
 	}
 	t.FileList = []*asset.File{file}
 
-	switch file, err := f.FetchByName(TfPlatformVarsFileName); {
-	case err == nil:
-		t.FileList = append(t.FileList, file)
-	case !os.IsNotExist(err):
-		return false, err
+	for _, name := range []string{TfPlatformVarsFileName, TfOverridesVarsFileName} {
+		switch file, err := f.FetchByName(name); {
+		case err == nil:
+			t.FileList = append(t.FileList, file)
+		case !os.IsNotExist(err):
+			return false, err
+		}
 	}
 
 	return true, nil
@@ -2,18 +2,22 @@ package baremetal
 
 import (
 	"crypto/rand"
+	"io"
 	"math/big"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/deterministic"
 )
 
 // IronicCreds is the asset for the ironic user credentials
 type IronicCreds struct {
 	Username string
 	Password string
+
+	source *deterministic.Source
 }
 
-var _ asset.Asset = (*IronicCreds)(nil)
+var _ asset.SourcedAsset = (*IronicCreds)(nil)
 
 // Dependencies returns no dependencies.
 func (a *IronicCreds) Dependencies() []asset.Asset {
@@ -22,7 +26,7 @@ func (a *IronicCreds) Dependencies() []asset.Asset {
 
 // Generate the ironic password
 func (a *IronicCreds) Generate(asset.Parents) error {
-	pw, err := generateRandomPassword()
+	pw, err := generateRandomPassword(a.source.Reader())
 	if err != nil {
 		return err
 	}
@@ -31,7 +35,7 @@ func (a *IronicCreds) Generate(asset.Parents) error {
 	return nil
 }
 
-func generateRandomPassword() (string, error) {
+func generateRandomPassword(r io.Reader) (string, error) {
 	chars := []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ" +
 		"abcdefghijklmnopqrstuvwxyz" +
 		"0123456789")
@@ -39,7 +43,7 @@ func generateRandomPassword() (string, error) {
 	buf := make([]rune, length)
 	numChars := big.NewInt(int64(len(chars)))
 	for i := range buf {
-		c, err := rand.Int(rand.Reader, numChars)
+		c, err := rand.Int(r, numChars)
 		if err != nil {
 			return "", err
 		}
@@ -52,3 +56,8 @@ func generateRandomPassword() (string, error) {
 func (a *IronicCreds) Name() string {
 	return "Ironic bootstrap credentials"
 }
+
+// SetSource sets the source the password is drawn from.
+func (a *IronicCreds) SetSource(source *deterministic.Source) {
+	a.source = source
+}
@@ -11,6 +11,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 	"time"
@@ -39,6 +40,7 @@ import (
 	"github.com/openshift/installer/pkg/asset/tls"
 	"github.com/openshift/installer/pkg/types"
 	baremetaltypes "github.com/openshift/installer/pkg/types/baremetal"
+	nonetypes "github.com/openshift/installer/pkg/types/none"
 	vspheretypes "github.com/openshift/installer/pkg/types/vsphere"
 )
 
@@ -61,6 +63,24 @@ var (
 	}
 )
 
+// enabledServices returns the systemd units enabled on the bootstrap machine:
+// the common ones, less keepalived when the load balancer of the API is
+// managed by the user, and less the services of the network of the cluster
+// when the bootstrap machine is external.
+func enabledServices(ic *types.InstallConfig, external bool) []string {
+	if !ic.Platform.IsLoadBalancerUserManaged() && !external {
+		return commonEnabledServices
+	}
+	services := make([]string, 0, len(commonEnabledServices))
+	for _, service := range commonEnabledServices {
+		if service == "keepalived.service" || external && externalDisabledServices[service] {
+			continue
+		}
+		services = append(services, service)
+	}
+	return services
+}
+
 // bootstrapTemplateData is the data to use to replace values in bootstrap
 // template files.
 type bootstrapTemplateData struct {
@@ -85,6 +105,7 @@ type bootstrapTemplateData struct {
 	APIServerURL          string
 	APIIntServerURL       string
 	FeatureSet            configv1.FeatureSet
+	ExternalBootstrap     bool
 }
 
 // platformTemplateData is the data to use to replace values in bootstrap
@@ -115,6 +136,8 @@ func (a *Common) Dependencies() []asset.Asset {
 		&machines.Worker{},
 		&manifests.Manifests{},
 		&manifests.Openshift{},
+		&manifests.FeatureSetCheck{},
+		&manifests.PolicyCheck{},
 		&manifests.Proxy{},
 		&tls.AdminKubeConfigCABundle{},
 		&tls.AggregatorCA{},
@@ -173,12 +196,19 @@ func (a *Common) generateConfig(dependencies asset.Parents, templateData *bootst
 	if err := AddStorageFiles(a.Config, "/", "bootstrap/files", templateData); err != nil {
 		return err
 	}
-	if err := AddSystemdUnits(a.Config, "bootstrap/systemd/units", templateData, commonEnabledServices); err != nil {
+	services := enabledServices(installConfig.Config, templateData.ExternalBootstrap)
+	if err := AddSystemdUnits(a.Config, "bootstrap/systemd/units", templateData, services); err != nil {
 		return err
 	}
 
-	// Check for optional platform specific files/units
+	// Check for optional platform specific files/units. These run the
+	// services of a separate bootstrap machine on the platform of the
+	// cluster, which a bootstrap-in-place install and an external bootstrap
+	// machine do not have.
 	platform := installConfig.Config.Platform.Name()
+	if templateData.BootstrapInPlace != nil || templateData.ExternalBootstrap {
+		platform = nonetypes.Name
+	}
 	platformFilePath := fmt.Sprintf("bootstrap/%s/files", platform)
 	directory, err := data.Assets.Open(platformFilePath)
 	if err == nil {
@@ -193,22 +223,43 @@ func (a *Common) generateConfig(dependencies asset.Parents, templateData *bootst
 	directory, err = data.Assets.Open(platformUnitPath)
 	if err == nil {
 		directory.Close()
-		if err = AddSystemdUnits(a.Config, platformUnitPath, templateData, commonEnabledServices); err != nil {
+		if err = AddSystemdUnits(a.Config, platformUnitPath, templateData, services); err != nil {
 			return err
 		}
 	}
 
 	a.addParentFiles(dependencies)
 
+	authorizedKeys := []igntypes.SSHAuthorizedKey{}
+	for _, key := range installConfig.Config.SSHKeys() {
+		authorizedKeys = append(authorizedKeys, igntypes.SSHAuthorizedKey(key))
+	}
+	authorizedKeys = append(authorizedKeys, igntypes.SSHAuthorizedKey(string(bootstrapSSHKeyPair.Public())))
 	a.Config.Passwd.Users = append(
 		a.Config.Passwd.Users,
-		igntypes.PasswdUser{Name: "core", SSHAuthorizedKeys: []igntypes.SSHAuthorizedKey{
-			igntypes.SSHAuthorizedKey(installConfig.Config.SSHKey),
-			igntypes.SSHAuthorizedKey(string(bootstrapSSHKeyPair.Public())),
-		}},
+		igntypes.PasswdUser{Name: "core", SSHAuthorizedKeys: authorizedKeys},
 	)
 
-	return nil
+	// The machine-config operator configures the registry CA certificates
+	// of the image config on the cluster machines, but the bootstrap
+	// machine pulls images before it runs.
+	hosts := make([]string, 0, len(installConfig.Config.AdditionalTrustedCA))
+	for host := range installConfig.Config.AdditionalTrustedCA {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		caPath := path.Join("/etc/containers/certs.d", host, "ca.crt")
+		a.Config.Storage.Files = replaceOrAppend(a.Config.Storage.Files, ignition.FileFromString(caPath, "root", 0644, installConfig.Config.AdditionalTrustedCA[host]))
+	}
+
+	// The bootstrap machine runs the control plane until the pivot, so it
+	// keeps time like the control plane machines.
+	if ntp := installConfig.Config.NTPFor(installConfig.Config.ControlPlane); ntp != nil {
+		a.Config.Storage.Files = replaceOrAppend(a.Config.Storage.Files, ignition.FileFromString(ignition.ChronyConfigPath, "root", 0644, ignition.ChronyConfig(ntp)))
+	}
+
+	return ignition.MergeSnippets(a.Config, installConfig.Config.IgnitionSnippets, "bootstrap")
 }
 
 func (a *Common) generateFile(filename string) error {
@@ -241,14 +292,14 @@ func (a *Common) getTemplateData(dependencies asset.Parents, bootstrapInPlace bo
 	ironicCreds := &baremetal.IronicCreds{}
 	dependencies.Get(installConfig, proxy, releaseImage, rhcosImage, bootstrapSSHKeyPair, ironicCreds)
 
-	etcdEndpoints := make([]string, *installConfig.Config.ControlPlane.Replicas)
+	etcdEndpoints := make([]string, installConfig.Config.EtcdReplicas())
 
 	for i := range etcdEndpoints {
 		etcdEndpoints[i] = fmt.Sprintf("https://etcd-%d.%s:2379", i, installConfig.Config.ClusterDomain())
 	}
 
 	registries := []sysregistriesv2.Registry{}
-	for _, group := range MergedMirrorSets(installConfig.Config.ImageContentSources) {
+	for _, group := range MergedMirrorSets(installConfig.Config.MirrorSources()) {
 		if len(group.Mirrors) == 0 {
 			continue
 		}
@@ -301,7 +352,7 @@ func (a *Common) getTemplateData(dependencies asset.Parents, bootstrapInPlace bo
 		bootstrapInPlaceConfig = installConfig.Config.BootstrapInPlace
 	}
 
-	apiURL := fmt.Sprintf("api.%s", installConfig.Config.ClusterDomain())
+	apiURL := installConfig.Config.APIDomainName()
 	apiIntURL := fmt.Sprintf("api-int.%s", installConfig.Config.ClusterDomain())
 	return &bootstrapTemplateData{
 		AdditionalTrustBundle: installConfig.Config.AdditionalTrustBundle,
@@ -0,0 +1,50 @@
+package bootstrap
+
+import (
+	"github.com/openshift/installer/pkg/asset"
+)
+
+const (
+	externalBootstrapIgnFilename = "bootstrap-external.ign"
+)
+
+// externalDisabledServices are the services of the bootstrap machine which
+// run on the network of the cluster, e.g. to hold the virtual IP of the API,
+// and which a bootstrap machine outside of it cannot run.
+var externalDisabledServices = map[string]bool{
+	"keepalived.service":        true,
+	"coredns.service":           true,
+	"ironic.service":            true,
+	"master-bmh-update.service": true,
+}
+
+// External is an asset that generates the ignition config for a bootstrap
+// machine run on another platform than the cluster, e.g. a local libvirt
+// virtual machine or an existing host. The config has none of the files and
+// services of the platform of the cluster; the endpoints the control plane
+// machines need are exposed by the bootstrap serve command.
+type External struct {
+	Common
+}
+
+var _ asset.WritableAsset = (*External)(nil)
+
+// Generate generates the ignition config for the External asset.
+func (a *External) Generate(dependencies asset.Parents) error {
+	templateData := a.getTemplateData(dependencies, false)
+	templateData.ExternalBootstrap = true
+	if err := a.generateConfig(dependencies, templateData); err != nil {
+		return err
+	}
+	return a.generateFile(externalBootstrapIgnFilename)
+}
+
+// Name returns the human-friendly name of the asset.
+func (a *External) Name() string {
+	return "External Bootstrap Ignition Config"
+}
+
+// Load returns the external bootstrap ignition from disk.
+func (a *External) Load(f asset.FileFetcher) (found bool, err error) {
+	return a.load(f, externalBootstrapIgnFilename)
+}
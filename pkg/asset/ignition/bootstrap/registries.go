@@ -6,9 +6,9 @@ import (
 	"github.com/openshift/installer/pkg/types"
 )
 
-// MergedMirrorSets consolidates a list of ImageContentSources so that each
+// MergedMirrorSets consolidates a list of ImageDigestSources so that each
 // source appears only once.
-func MergedMirrorSets(sources []types.ImageContentSource) []types.ImageContentSource {
+func MergedMirrorSets(sources []types.ImageDigestSource) []types.ImageDigestSource {
 	sourceSet := make(map[string][]string)
 	mirrorSet := make(map[string]sets.String)
 	orderedSources := []string{}
@@ -27,9 +27,9 @@ func MergedMirrorSets(sources []types.ImageContentSource) []types.ImageContentSo
 		}
 	}
 
-	out := []types.ImageContentSource{}
+	out := []types.ImageDigestSource{}
 	for _, source := range orderedSources {
-		out = append(out, types.ImageContentSource{Source: source, Mirrors: sourceSet[source]})
+		out = append(out, types.ImageDigestSource{Source: source, Mirrors: sourceSet[source]})
 	}
 	return out
 }
@@ -0,0 +1,33 @@
+package ignition
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// ChronyConfigPath is the path of the chrony configuration on the machines.
+const ChronyConfigPath = "/etc/chrony.conf"
+
+// ChronyConfig returns the chrony configuration synchronizing with the
+// servers and pools. It steps the clock on the first updates rather than
+// slewing it, so that machines booting with a skewed clock are corrected
+// before they request certificates.
+func ChronyConfig(ntp *types.NTP) string {
+	var b strings.Builder
+	for _, server := range ntp.Servers {
+		fmt.Fprintf(&b, "server %s iburst\n", server)
+	}
+	for _, pool := range ntp.Pools {
+		fmt.Fprintf(&b, "pool %s iburst\n", pool)
+	}
+	b.WriteString(`driftfile /var/lib/chrony/drift
+makestep 1.0 3
+rtcsync
+keyfile /etc/chrony.keys
+leapsectz right/UTC
+logdir /var/log/chrony
+`)
+	return b.String()
+}
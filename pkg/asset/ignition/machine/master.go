@@ -40,6 +40,9 @@ func (a *Master) Generate(dependencies asset.Parents) error {
 	dependencies.Get(installConfig, rootCA)
 
 	a.Config = pointerIgnitionConfig(installConfig.Config, rootCA.Cert(), "master")
+	if err := ignition.MergeSnippets(a.Config, installConfig.Config.IgnitionSnippets, "master"); err != nil {
+		return err
+	}
 
 	data, err := ignition.Marshal(a.Config)
 	if err != nil {
@@ -40,6 +40,9 @@ func (a *Worker) Generate(dependencies asset.Parents) error {
 	dependencies.Get(installConfig, rootCA)
 
 	a.Config = pointerIgnitionConfig(installConfig.Config, rootCA.Cert(), "worker")
+	if err := ignition.MergeSnippets(a.Config, installConfig.Config.IgnitionSnippets, "worker"); err != nil {
+		return err
+	}
 
 	data, err := ignition.Marshal(a.Config)
 	if err != nil {
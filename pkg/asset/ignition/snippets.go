@@ -0,0 +1,95 @@
+package ignition
+
+import (
+	"fmt"
+
+	igntypes "github.com/coreos/ignition/v2/config/v3_2/types"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/butane"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// MergeSnippets merges the Ignition snippets of the install-config for the
+// role into the config. It fails when a snippet installs a path or a systemd
+// unit which the config, or a snippet merged before it, already installs.
+func MergeSnippets(config *igntypes.Config, snippets []types.IgnitionSnippet, role string) error {
+	owners := map[string]string{}
+	claim := func(kind, name, owner string) error {
+		key := kind + " " + name
+		if existing, ok := owners[key]; ok {
+			return errors.Errorf("%s installs the %s, which %s already installs", owner, key, existing)
+		}
+		owners[key] = owner
+		return nil
+	}
+	claimAll := func(c *igntypes.Config, owner string) error {
+		for _, f := range c.Storage.Files {
+			if err := claim("path", f.Path, owner); err != nil {
+				return err
+			}
+		}
+		for _, d := range c.Storage.Directories {
+			if err := claim("path", d.Path, owner); err != nil {
+				return err
+			}
+		}
+		for _, l := range c.Storage.Links {
+			if err := claim("path", l.Path, owner); err != nil {
+				return err
+			}
+		}
+		for _, u := range c.Systemd.Units {
+			if err := claim("systemd unit", u.Name, owner); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := claimAll(config, "the installer"); err != nil {
+		return err
+	}
+
+	for _, snippet := range snippets {
+		if !hasRole(snippet, role) {
+			continue
+		}
+		snippetConfig, err := butane.Translate([]byte(snippet.Butane))
+		if err != nil {
+			return errors.Wrapf(err, "invalid Ignition snippet %q", snippet.Name)
+		}
+		if err := claimAll(snippetConfig, fmt.Sprintf("Ignition snippet %q", snippet.Name)); err != nil {
+			return err
+		}
+
+		config.Storage.Files = append(config.Storage.Files, snippetConfig.Storage.Files...)
+		config.Storage.Directories = append(config.Storage.Directories, snippetConfig.Storage.Directories...)
+		config.Storage.Links = append(config.Storage.Links, snippetConfig.Storage.Links...)
+		config.Systemd.Units = append(config.Systemd.Units, snippetConfig.Systemd.Units...)
+		for _, user := range snippetConfig.Passwd.Users {
+			config.Passwd.Users = mergeUser(config.Passwd.Users, user)
+		}
+	}
+	return nil
+}
+
+func hasRole(snippet types.IgnitionSnippet, role string) bool {
+	for _, r := range snippet.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeUser adds the SSH keys of the user to the user of the same name, or
+// adds the user when there is none.
+func mergeUser(users []igntypes.PasswdUser, user igntypes.PasswdUser) []igntypes.PasswdUser {
+	for i := range users {
+		if users[i].Name == user.Name {
+			users[i].SSHAuthorizedKeys = append(users[i].SSHAuthorizedKeys, user.SSHAuthorizedKeys...)
+			return users
+		}
+	}
+	return append(users, user)
+}
@@ -0,0 +1,86 @@
+package ignition
+
+import (
+	"testing"
+
+	igntypes "github.com/coreos/ignition/v2/config/v3_2/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestMergeSnippets(t *testing.T) {
+	chrony := types.IgnitionSnippet{
+		Name:   "chrony",
+		Roles:  []string{"bootstrap", "master"},
+		Butane: "variant: fcos\nversion: 1.3.0\nstorage:\n  files:\n  - path: /etc/chrony.conf\n    contents:\n      inline: server time.example.com\n",
+	}
+	keys := types.IgnitionSnippet{
+		Name:   "keys",
+		Roles:  []string{"bootstrap"},
+		Butane: "variant: fcos\nversion: 1.3.0\npasswd:\n  users:\n  - name: core\n    ssh_authorized_keys:\n    - ssh-ed25519 BBBB\n",
+	}
+	cases := []struct {
+		name     string
+		snippets []types.IgnitionSnippet
+		role     string
+		files    []string
+		keys     []igntypes.SSHAuthorizedKey
+		err      string
+	}{
+		{
+			name:     "merged for the role",
+			snippets: []types.IgnitionSnippet{chrony, keys},
+			role:     "bootstrap",
+			files:    []string{"/opt/openshift/bootkube.sh", "/etc/chrony.conf"},
+			keys:     []igntypes.SSHAuthorizedKey{"ssh-ed25519 AAAA", "ssh-ed25519 BBBB"},
+		},
+		{
+			name:     "other roles left out",
+			snippets: []types.IgnitionSnippet{chrony, keys},
+			role:     "worker",
+			files:    []string{"/opt/openshift/bootkube.sh"},
+			keys:     []igntypes.SSHAuthorizedKey{"ssh-ed25519 AAAA"},
+		},
+		{
+			name: "collision with the installer",
+			snippets: []types.IgnitionSnippet{{
+				Name:   "bootkube",
+				Roles:  []string{"bootstrap"},
+				Butane: "variant: fcos\nversion: 1.3.0\nstorage:\n  files:\n  - path: /opt/openshift/bootkube.sh\n",
+			}},
+			role: "bootstrap",
+			err:  `Ignition snippet "bootkube" installs the path /opt/openshift/bootkube.sh, which the installer already installs`,
+		},
+		{
+			name: "collision between snippets",
+			snippets: []types.IgnitionSnippet{chrony, {
+				Name:   "chrony-link",
+				Roles:  []string{"master"},
+				Butane: "variant: fcos\nversion: 1.3.0\nstorage:\n  links:\n  - path: /etc/chrony.conf\n    target: /etc/chrony.d/custom.conf\n",
+			}},
+			role: "master",
+			err:  `Ignition snippet "chrony-link" installs the path /etc/chrony.conf, which Ignition snippet "chrony" already installs`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &igntypes.Config{
+				Storage: igntypes.Storage{Files: []igntypes.File{FileFromString("/opt/openshift/bootkube.sh", "root", 0555, "")}},
+				Passwd:  igntypes.Passwd{Users: []igntypes.PasswdUser{{Name: "core", SSHAuthorizedKeys: []igntypes.SSHAuthorizedKey{"ssh-ed25519 AAAA"}}}},
+			}
+			err := MergeSnippets(config, tc.snippets, tc.role)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			files := []string{}
+			for _, f := range config.Storage.Files {
+				files = append(files, f.Path)
+			}
+			assert.Equal(t, tc.files, files)
+			assert.Equal(t, tc.keys, config.Passwd.Users[0].SSHAuthorizedKeys)
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package userdata
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset/ignition/bootstrap"
+	awsconfig "github.com/openshift/installer/pkg/asset/installconfig/aws"
+	"github.com/openshift/installer/pkg/credentialsfile"
+	"github.com/openshift/installer/pkg/types"
+)
+
+const (
+	// OffloadURLEnv is the environment variable selecting where Ignition
+	// configs which exceed the user data limit of the platform are
+	// offloaded to.
+	OffloadURLEnv = "OPENSHIFT_INSTALL_IGNITION_OFFLOAD_URL"
+	// OffloadCAEnv is the environment variable naming a PEM file of the CA
+	// certificates the machines trust when fetching offloaded configs, and
+	// the installer trusts when uploading them over HTTPS.
+	OffloadCAEnv = "OPENSHIFT_INSTALL_IGNITION_OFFLOAD_CA"
+	// presignExpiry is how long the presigned URLs of configs offloaded to
+	// S3 are valid.
+	presignExpiry = 24 * time.Hour
+)
+
+// backend stores offloaded Ignition configs.
+type backend interface {
+	// upload stores the config as the object and returns the URL the
+	// machines fetch it from.
+	upload(ctx context.Context, object string, data []byte) (string, error)
+
+	// String returns a human-readable location of the configs.
+	String() string
+}
+
+// Fit returns the Ignition config of the role when it fits in the user data
+// of the platform. Otherwise it offloads the config to the backend selected
+// by OPENSHIFT_INSTALL_IGNITION_OFFLOAD_URL and returns a config pointing the
+// machines at it, or fails when no backend is selected. Configs are offloaded
+// to S3 with the AWS credentials, or the default ones when they are nil.
+func Fit(ctx context.Context, platform string, infraID string, role string, config []byte, proxy *types.Proxy, credentials *credentialsfile.AWS) ([]byte, error) {
+	err := Check(platform, role, config)
+	var tooLarge *TooLargeError
+	if !errors.As(err, &tooLarge) {
+		return config, err
+	}
+
+	rawURL := os.Getenv(OffloadURLEnv)
+	if rawURL == "" {
+		return nil, errors.Wrapf(err, "set %s to offload it", OffloadURLEnv)
+	}
+	var ca []byte
+	if caFile := os.Getenv(OffloadCAEnv); caFile != "" {
+		ca, err = os.ReadFile(caFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %s", OffloadCAEnv)
+		}
+	}
+	b, err := parseOffloadURL(rawURL, ca, credentials)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s", OffloadURLEnv)
+	}
+
+	configURL, err := b.upload(ctx, fmt.Sprintf("%s-%s.ign", infraID, role), config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to offload the %s Ignition config to %s", role, b)
+	}
+	pointer, err := bootstrap.GenerateIgnitionShimWithCertBundleAndProxy(configURL, string(ca), proxy)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create the pointer Ignition config for %s", role)
+	}
+	if err := Check(platform, role+" pointer", pointer); err != nil {
+		return nil, errors.Wrap(err, "try reducing the size of the CA bundle")
+	}
+	logrus.Warnf("Offloaded the %s Ignition config (%d bytes) to %s, since it exceeds the %d byte limit for %s user data", role, tooLarge.Size, b, tooLarge.Limit, platform)
+	return pointer, nil
+}
+
+// parseOffloadURL returns the backend for an offload URL. The supported forms
+// are:
+//
+//	s3://<bucket>[/<prefix>]
+//	https://<host>[/<path>][?<query>]
+//
+// Configs offloaded to S3 are fetched with presigned URLs. Configs offloaded
+// over HTTPS are uploaded with PUT to <path>/<object>, keeping the query, and
+// fetched from the same URL, so that e.g. an Azure Blob Storage container
+// SAS URL with read and write permissions works.
+func parseOffloadURL(rawURL string, ca []byte, credentials *credentialsfile.AWS) (backend, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" {
+		return nil, errors.Errorf("%q has no host", rawURL)
+	}
+
+	switch u.Scheme {
+	case "s3":
+		return &s3Backend{bucket: u.Host, prefix: strings.Trim(u.Path, "/"), credentials: credentials}, nil
+	case "https":
+		client, err := httpClient(ca)
+		if err != nil {
+			return nil, err
+		}
+		return &httpsBackend{base: u, client: client}, nil
+	default:
+		return nil, errors.Errorf("unsupported scheme %q, must be s3 or https", u.Scheme)
+	}
+}
+
+// s3Backend offloads configs to an AWS S3 bucket.
+type s3Backend struct {
+	bucket      string
+	prefix      string
+	credentials *credentialsfile.AWS
+}
+
+func (b *s3Backend) upload(ctx context.Context, object string, data []byte) (string, error) {
+	sess, err := awsconfig.GetSessionWithCredentials(b.credentials)
+	if err != nil {
+		return "", err
+	}
+	region, err := s3manager.GetBucketRegion(ctx, sess, b.bucket, "us-east-1")
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the region of bucket %q", b.bucket)
+	}
+	client := s3.New(sess, aws.NewConfig().WithRegion(region))
+	key := path.Join(b.prefix, object)
+	_, err = client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:               aws.String(b.bucket),
+		Key:                  aws.String(key),
+		Body:                 bytes.NewReader(data),
+		ServerSideEncryption: aws.String(s3.ServerSideEncryptionAes256),
+	})
+	if err != nil {
+		return "", err
+	}
+	req, _ := client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(key),
+	})
+	return req.Presign(presignExpiry)
+}
+
+func (b *s3Backend) String() string {
+	return fmt.Sprintf("s3://%s/%s", b.bucket, b.prefix)
+}
+
+// httpsBackend offloads configs to an HTTPS server accepting PUT.
+type httpsBackend struct {
+	base   *url.URL
+	client *http.Client
+}
+
+func (b *httpsBackend) upload(ctx context.Context, object string, data []byte) (string, error) {
+	u := *b.base
+	u.Path = path.Join("/", u.Path, object)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// required by Azure Blob Storage, ignored by other servers
+	req.Header.Set("x-ms-blob-type", "BlockBlob")
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", errors.Errorf("unexpected status %s", resp.Status)
+	}
+	return u.String(), nil
+}
+
+func (b *httpsBackend) String() string {
+	return fmt.Sprintf("%s://%s%s", b.base.Scheme, b.base.Host, b.base.Path)
+}
+
+// httpClient returns a client trusting the CA certificates in addition to
+// the system ones.
+func httpClient(ca []byte) (*http.Client, error) {
+	if len(ca) == 0 {
+		return &http.Client{Timeout: time.Minute}, nil
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.Errorf("%s has no PEM certificates", OffloadCAEnv)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	return &http.Client{Transport: transport, Timeout: time.Minute}, nil
+}
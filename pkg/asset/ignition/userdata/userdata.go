@@ -0,0 +1,68 @@
+// Package userdata checks Ignition configs against the limits platforms put
+// on the user data of instances, and offloads the configs which exceed them
+// to remote storage.
+package userdata
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/openshift/installer/pkg/types/alibabacloud"
+	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/azure"
+	"github.com/openshift/installer/pkg/types/gcp"
+	"github.com/openshift/installer/pkg/types/ibmcloud"
+	"github.com/openshift/installer/pkg/types/openstack"
+)
+
+// limit is the largest user data a platform accepts for an instance.
+type limit struct {
+	bytes int
+	// base64 is whether the limit applies to the user data encoded as
+	// base64 rather than to the raw user data.
+	base64 bool
+}
+
+var limits = map[string]limit{
+	// https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/instancedata-add-user-data.html
+	aws.Name:          {bytes: 16000},
+	alibabacloud.Name: {bytes: 16000},
+	// https://learn.microsoft.com/en-us/azure/virtual-machines/custom-data
+	azure.Name: {bytes: 64 * 1024},
+	// https://cloud.google.com/compute/docs/metadata/setting-custom-metadata#limitations
+	gcp.Name:      {bytes: 256 * 1024},
+	ibmcloud.Name: {bytes: 64 * 1024},
+	// https://docs.openstack.org/nova/latest/user/metadata.html#user-data
+	openstack.Name: {bytes: 65535, base64: true},
+}
+
+// TooLargeError is returned when an Ignition config exceeds the user data
+// limit of the platform.
+type TooLargeError struct {
+	Platform string
+	Name     string
+	Size     int
+	Limit    int
+}
+
+func (e *TooLargeError) Error() string {
+	return fmt.Sprintf("the %s Ignition config is %d bytes, which exceeds the %d byte limit for %s user data", e.Name, e.Size, e.Limit, e.Platform)
+}
+
+// Check returns a TooLargeError when the Ignition config exceeds the user
+// data limit of the platform. Platforms without a known limit accept any
+// config.
+func Check(platform string, name string, config []byte) error {
+	l, ok := limits[platform]
+	if !ok {
+		return nil
+	}
+	size := len(config)
+	if l.base64 {
+		size = base64.StdEncoding.EncodedLen(size)
+	}
+	if size > l.bytes {
+		return &TooLargeError{Platform: platform, Name: name, Size: size, Limit: l.bytes}
+	}
+	return nil
+}
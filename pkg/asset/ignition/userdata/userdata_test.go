@@ -0,0 +1,123 @@
+package userdata
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	cases := []struct {
+		name     string
+		platform string
+		size     int
+		err      string
+	}{
+		{
+			name:     "fits",
+			platform: "aws",
+			size:     16000,
+		},
+		{
+			name:     "too large",
+			platform: "aws",
+			size:     16001,
+			err:      "the master Ignition config is 16001 bytes, which exceeds the 16000 byte limit for aws user data",
+		},
+		{
+			name:     "base64 limit",
+			platform: "openstack",
+			size:     49152,
+			err:      "the master Ignition config is 65536 bytes, which exceeds the 65535 byte limit for openstack user data",
+		},
+		{
+			name:     "no limit",
+			platform: "vsphere",
+			size:     1 << 20,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Check(tc.platform, "master", make([]byte, tc.size))
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFit(t *testing.T) {
+	var uploaded []byte
+	var path string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "sig=secret", r.URL.RawQuery)
+		path = r.URL.Path
+		uploaded, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	assert.NoError(t, os.WriteFile(caFile, ca, 0600))
+
+	small := []byte(`{"ignition":{"version":"3.2.0"}}`)
+	large := []byte(`{"ignition":{"version":"3.2.0"},"storage":{"files":[{"path":"/etc/big","contents":{"source":"data:,` + strings.Repeat("x", 17000) + `"}}]}}`)
+
+	t.Run("fits", func(t *testing.T) {
+		config, err := Fit(context.Background(), "aws", "test-x", "master", small, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, small, config)
+	})
+
+	t.Run("no offload URL", func(t *testing.T) {
+		t.Setenv(OffloadURLEnv, "")
+		_, err := Fit(context.Background(), "aws", "test-x", "master", large, nil, nil)
+		assert.EqualError(t, err, "set OPENSHIFT_INSTALL_IGNITION_OFFLOAD_URL to offload it: the master Ignition config is 17105 bytes, which exceeds the 16000 byte limit for aws user data")
+	})
+
+	t.Run("offloaded", func(t *testing.T) {
+		t.Setenv(OffloadURLEnv, server.URL+"/configs?sig=secret")
+		t.Setenv(OffloadCAEnv, caFile)
+		config, err := Fit(context.Background(), "aws", "test-x", "master", large, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "/configs/test-x-master.ign", path)
+		assert.Equal(t, large, uploaded)
+
+		var pointer struct {
+			Ignition struct {
+				Config struct {
+					Replace struct {
+						Source string `json:"source"`
+					} `json:"replace"`
+				} `json:"config"`
+				Security struct {
+					TLS struct {
+						CertificateAuthorities []json.RawMessage `json:"certificateAuthorities"`
+					} `json:"tls"`
+				} `json:"security"`
+			} `json:"ignition"`
+		}
+		assert.NoError(t, json.Unmarshal(config, &pointer))
+		assert.Equal(t, server.URL+"/configs/test-x-master.ign?sig=secret", pointer.Ignition.Config.Replace.Source)
+		assert.Len(t, pointer.Ignition.Security.TLS.CertificateAuthorities, 1)
+	})
+
+	t.Run("unsupported scheme", func(t *testing.T) {
+		t.Setenv(OffloadURLEnv, "ftp://example.com/configs")
+		_, err := Fit(context.Background(), "aws", "test-x", "master", large, nil, nil)
+		assert.EqualError(t, err, `invalid OPENSHIFT_INSTALL_IGNITION_OFFLOAD_URL: unsupported scheme "ftp", must be s3 or https`)
+	})
+}
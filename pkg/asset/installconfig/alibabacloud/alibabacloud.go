@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 	"github.com/openshift/installer/pkg/types/alibabacloud"
 )
 
@@ -112,7 +113,7 @@ func selectRegion(client *Client) (string, error) {
 	sort.Strings(shortRegions)
 
 	var selectedRegion string
-	err = survey.Ask([]*survey.Question{
+	err = answers.Ask(answers.Region, []*survey.Question{
 		{
 			Prompt: &survey.Select{
 				Message: "Region",
@@ -7,6 +7,8 @@ import (
 	"github.com/AlecAivazis/survey/v2/core"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 )
 
 // GetBaseDomain returns a base domain chosen from among the account's domains.
@@ -33,7 +35,7 @@ func GetBaseDomain() (string, error) {
 	}
 
 	var basedomain string
-	if err := survey.AskOne(
+	if err := answers.AskOne(answers.BaseDomain,
 		&survey.Select{
 			Message: "Base Domain",
 			Help:    "The base domain of the cluster. All DNS records will be sub-domains of this base and will also include the cluster name.\n\nIf you don't see you intended base-domain listed, create a new domain and rerun the installer.",
@@ -22,6 +22,8 @@ import (
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
 	"github.com/aliyun/aliyun-oss-go-sdk/oss"
 	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 )
 
 // Environment virables
@@ -105,7 +107,7 @@ func getCredentials() (credential auth.Credential, err error) {
 func askCredentials() (auth.Credential, error) {
 	var accessKeyID string
 
-	err := survey.Ask([]*survey.Question{
+	err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "Alibaba Cloud Access Key ID",
@@ -119,7 +121,7 @@ func askCredentials() (auth.Credential, error) {
 	}
 
 	var accessKeySecret string
-	err = survey.Ask([]*survey.Question{
+	err = answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Password{
 				Message: "Alibaba Cloud Secret Access Key",
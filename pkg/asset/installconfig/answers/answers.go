@@ -0,0 +1,176 @@
+// Package answers answers the survey prompts of the installer from flags and
+// environment variables, so that the install-config can be generated without
+// a terminal.
+package answers
+
+import (
+	"os"
+	"strings"
+
+	survey "github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/core"
+	"github.com/pkg/errors"
+)
+
+// The keys of the prompts that can be answered ahead of time. Each key can
+// be set with the flag of the same name or with the environment variable
+// OPENSHIFT_INSTALL_<KEY>, e.g. OPENSHIFT_INSTALL_BASE_DOMAIN.
+const (
+	Platform    = "platform"
+	Region      = "region"
+	BaseDomain  = "base-domain"
+	ClusterName = "cluster-name"
+	PullSecret  = "pull-secret"
+	SSHKeyFile  = "ssh-key-file"
+)
+
+// Keys lists the prompts that can be answered ahead of time.
+var Keys = []string{Platform, Region, BaseDomain, ClusterName, PullSecret, SSHKeyFile}
+
+var (
+	answers        = map[string]string{}
+	nonInteractive bool
+)
+
+// Set answers the prompt of the key, taking precedence over the environment.
+func Set(key, value string) {
+	answers[key] = value
+}
+
+// SetNonInteractive makes prompts without an answer fail instead of asking
+// the user.
+func SetNonInteractive(enabled bool) {
+	nonInteractive = enabled
+}
+
+// EnvVar returns the environment variable that answers the prompt of the key.
+func EnvVar(key string) string {
+	return "OPENSHIFT_INSTALL_" + strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+}
+
+// Get returns the answer to the prompt of the key, if there is one.
+func Get(key string) (string, bool) {
+	if key == "" {
+		return "", false
+	}
+	if value, ok := answers[key]; ok {
+		return value, true
+	}
+	if value := os.Getenv(EnvVar(key)); value != "" {
+		return value, true
+	}
+	return "", false
+}
+
+// Ask is survey.Ask for the prompts of the key. If the key has an answer,
+// the answer is validated and written to response without prompting; a key
+// can only answer a single question. The key may be empty for prompts that
+// cannot be answered ahead of time.
+func Ask(key string, qs []*survey.Question, response interface{}, opts ...survey.AskOpt) error {
+	value, ok := Get(key)
+	if !ok {
+		if err := checkInteractive(key, qs...); err != nil {
+			return err
+		}
+		return survey.Ask(qs, response, opts...)
+	}
+	if len(qs) != 1 {
+		return errors.Errorf("installer bug: %s cannot answer %d questions", key, len(qs))
+	}
+	return answer(key, value, qs[0], response, opts...)
+}
+
+// AskOne is survey.AskOne for the prompt of the key. See Ask.
+func AskOne(key string, p survey.Prompt, response interface{}, opts ...survey.AskOpt) error {
+	value, ok := Get(key)
+	if !ok {
+		if err := checkInteractive(key, &survey.Question{Prompt: p}); err != nil {
+			return err
+		}
+		return survey.AskOne(p, response, opts...)
+	}
+	return answer(key, value, &survey.Question{Prompt: p}, response, opts...)
+}
+
+func checkInteractive(key string, qs ...*survey.Question) error {
+	if !nonInteractive {
+		return nil
+	}
+	message := ""
+	if len(qs) > 0 {
+		message = promptMessage(qs[0].Prompt)
+	}
+	if key == "" {
+		return errors.Errorf("%q must be answered interactively, write an install-config instead of using --non-interactive", message)
+	}
+	return errors.Errorf("%q is not answered, set --%s or %s", message, key, EnvVar(key))
+}
+
+// answer validates the answer of the question and writes it to response the
+// way survey would.
+func answer(key, value string, q *survey.Question, response interface{}, opts ...survey.AskOpt) error {
+	options := &survey.AskOptions{}
+	for _, opt := range opts {
+		if err := opt(options); err != nil {
+			return err
+		}
+	}
+	validators := options.Validators
+	if q.Validate != nil {
+		validators = append(validators, q.Validate)
+	}
+
+	var ans interface{} = value
+	switch p := q.Prompt.(type) {
+	case *survey.Select:
+		option, err := matchOption(value, p.Options)
+		if err != nil {
+			return errors.Wrapf(err, "invalid %s", key)
+		}
+		ans = option
+	case *survey.Confirm:
+		// survey converts the string when writing the answer
+	case *survey.Input, *survey.Password, *survey.Multiline:
+	default:
+		return errors.Errorf("installer bug: %s cannot answer a %T prompt", key, q.Prompt)
+	}
+
+	for _, validate := range validators {
+		if err := validate(ans); err != nil {
+			return errors.Wrapf(err, "invalid %s", key)
+		}
+	}
+	if q.Transform != nil {
+		ans = q.Transform(ans)
+	}
+	return core.WriteAnswer(response, q.Name, ans)
+}
+
+// matchOption returns the option that is the value, or whose first word is
+// the value, e.g. "us-east-1" for "us-east-1 (N. Virginia)".
+func matchOption(value string, options []string) (core.OptionAnswer, error) {
+	for i, option := range options {
+		if option == value || strings.SplitN(option, " ", 2)[0] == value {
+			return core.OptionAnswer{Value: option, Index: i}, nil
+		}
+	}
+	return core.OptionAnswer{}, errors.Errorf("%q is not one of %s", value, strings.Join(options, ", "))
+}
+
+func promptMessage(p survey.Prompt) string {
+	switch p := p.(type) {
+	case *survey.Select:
+		return p.Message
+	case *survey.MultiSelect:
+		return p.Message
+	case *survey.Input:
+		return p.Message
+	case *survey.Password:
+		return p.Message
+	case *survey.Confirm:
+		return p.Message
+	case *survey.Multiline:
+		return p.Message
+	}
+	return ""
+}
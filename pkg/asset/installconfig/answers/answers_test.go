@@ -0,0 +1,117 @@
+package answers
+
+import (
+	"strings"
+	"testing"
+
+	survey "github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/core"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsk(t *testing.T) {
+	regionTransform := func(ans interface{}) interface{} {
+		v := ans.(core.OptionAnswer)
+		return core.OptionAnswer{Value: strings.SplitN(v.Value, " ", 2)[0], Index: v.Index}
+	}
+	cases := []struct {
+		name     string
+		answer   string
+		question *survey.Question
+		expected string
+		err      string
+	}{
+		{
+			name:     "input",
+			answer:   "example.com",
+			question: &survey.Question{Prompt: &survey.Input{Message: "Base Domain"}},
+			expected: "example.com",
+		},
+		{
+			name:   "input failing validation",
+			answer: "example.com",
+			question: &survey.Question{
+				Prompt:   &survey.Input{Message: "Base Domain"},
+				Validate: func(interface{}) error { return errors.New("bad domain") },
+			},
+			err: `^invalid region: bad domain$`,
+		},
+		{
+			name:   "select by first word",
+			answer: "us-east-1",
+			question: &survey.Question{
+				Prompt:    &survey.Select{Message: "Region", Options: []string{"eu-west-1 (Ireland)", "us-east-1 (N. Virginia)"}},
+				Validate:  survey.Required,
+				Transform: regionTransform,
+			},
+			expected: "us-east-1",
+		},
+		{
+			name:     "select by option",
+			answer:   "eu-west-1 (Ireland)",
+			question: &survey.Question{Prompt: &survey.Select{Message: "Region", Options: []string{"eu-west-1 (Ireland)"}}},
+			expected: "eu-west-1 (Ireland)",
+		},
+		{
+			name:     "select unknown option",
+			answer:   "mars-1",
+			question: &survey.Question{Prompt: &survey.Select{Message: "Region", Options: []string{"eu-west-1 (Ireland)"}}},
+			err:      `^invalid region: "mars-1" is not one of eu-west-1 \(Ireland\)$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			Set(Region, tc.answer)
+			defer delete(answers, Region)
+
+			var response string
+			err := Ask(Region, []*survey.Question{tc.question}, &response)
+			if tc.err != "" {
+				assert.Regexp(t, tc.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, response)
+		})
+	}
+}
+
+func TestAskOneValidators(t *testing.T) {
+	Set(BaseDomain, "b.com")
+	defer delete(answers, BaseDomain)
+
+	var response string
+	err := AskOne(BaseDomain, &survey.Select{Message: "Base Domain", Options: []string{"a.com", "b.com"}}, &response,
+		survey.WithValidator(func(ans interface{}) error {
+			return errors.Errorf("%s is not allowed", ans.(core.OptionAnswer).Value)
+		}))
+	assert.Regexp(t, `^invalid base-domain: b.com is not allowed$`, err)
+}
+
+func TestAnswerFromEnvironment(t *testing.T) {
+	t.Setenv("OPENSHIFT_INSTALL_CLUSTER_NAME", "from-env")
+
+	var response string
+	err := Ask(ClusterName, []*survey.Question{{Prompt: &survey.Input{Message: "Cluster Name"}}}, &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "from-env", response)
+
+	Set(ClusterName, "from-flag")
+	defer delete(answers, ClusterName)
+	err = Ask(ClusterName, []*survey.Question{{Prompt: &survey.Input{Message: "Cluster Name"}}}, &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "from-flag", response)
+}
+
+func TestNonInteractive(t *testing.T) {
+	SetNonInteractive(true)
+	defer SetNonInteractive(false)
+
+	var response string
+	err := Ask(Platform, []*survey.Question{{Prompt: &survey.Select{Message: "Platform", Options: []string{"aws"}}}}, &response)
+	assert.Regexp(t, `^"Platform" is not answered, set --platform or OPENSHIFT_INSTALL_PLATFORM$`, err)
+
+	err = AskOne("", &survey.Password{Message: "Password"}, &response)
+	assert.Regexp(t, `^"Password" must be answered interactively`, err)
+}
@@ -0,0 +1,164 @@
+package aws
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	survey "github.com/AlecAivazis/survey/v2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/processcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
+	"github.com/openshift/installer/pkg/credentialsfile"
+)
+
+var (
+	credentialsFileMu     sync.Mutex
+	credentialsFileCreds  = map[*credentialsfile.AWS]*credentials.Credentials{}
+	credentialsFileLogger sync.Once
+)
+
+func init() {
+	credentialsfile.ResolveAWS = resolveCredentialsFile
+}
+
+// applyCredentialsFile configures the session.Option to use the credentials
+// of a credentials file, in place of the ones of the environment and of the
+// user.
+func applyCredentialsFile(options *session.Options, c *credentialsfile.AWS) error {
+	creds, err := credentialsFromFile(*options, c)
+	if err != nil {
+		return err
+	}
+	options.Config.Credentials = creds
+	return nil
+}
+
+// resolveCredentialsFile resolves the credentials of a credentials file to
+// access keys, for the commands the installer runs.
+func resolveCredentialsFile(c *credentialsfile.AWS) (credentialsfile.AWSKeys, error) {
+	creds, err := credentialsFromFile(session.Options{
+		Config:            aws.Config{MaxRetries: aws.Int(0)},
+		SharedConfigState: session.SharedConfigEnable,
+	}, c)
+	if err != nil {
+		return credentialsfile.AWSKeys{}, err
+	}
+	value, err := creds.Get()
+	if err != nil {
+		return credentialsfile.AWSKeys{}, err
+	}
+	return credentialsfile.AWSKeys{
+		AccessKeyID:     value.AccessKeyID,
+		SecretAccessKey: value.SecretAccessKey,
+		SessionToken:    value.SessionToken,
+	}, nil
+}
+
+// credentialsFromFile returns the credentials of a credentials file. They
+// are created once per credentials, so that the credential process is run,
+// the roles are assumed and the MFA tokens are asked for again only when
+// the credentials expire.
+func credentialsFromFile(options session.Options, c *credentialsfile.AWS) (*credentials.Credentials, error) {
+	credentialsFileMu.Lock()
+	defer credentialsFileMu.Unlock()
+	if creds, ok := credentialsFileCreds[c]; ok {
+		return creds, nil
+	}
+
+	var creds *credentials.Credentials
+	switch {
+	case c.AccessKeyID != "":
+		creds = credentials.NewStaticCredentials(c.AccessKeyID, c.SecretAccessKey, c.SessionToken)
+	case c.CredentialProcess != "":
+		creds = processcreds.NewCredentials(c.CredentialProcess)
+	default:
+		// a profile, or else the default credentials of the SDK which the
+		// roles are assumed with
+		options.AssumeRoleTokenProvider = mfaTokenProvider("")
+		if c.Profile != "" || c.SharedCredentialsFile != "" {
+			options.Profile = c.Profile
+			if options.Profile == "" {
+				options.Profile = "default"
+			}
+			if c.SharedCredentialsFile != "" {
+				options.SharedConfigFiles = []string{c.SharedCredentialsFile}
+			}
+		}
+		sess, err := session.NewSessionWithOptions(options)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load the AWS credentials of the credentials file")
+		}
+		creds = sess.Config.Credentials
+	}
+
+	for _, role := range c.AssumeRoles {
+		cfg := options.Config.Copy().WithCredentials(creds)
+		if aws.StringValue(cfg.Region) == "" {
+			// STS is global, but its client requires a region
+			cfg.Region = aws.String(endpoints.UsEast1RegionID)
+		}
+		sess, err := session.NewSessionWithOptions(session.Options{Config: *cfg})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to assume the role %s", role.RoleARN)
+		}
+		creds = stscreds.NewCredentials(sess, role.RoleARN, func(p *stscreds.AssumeRoleProvider) {
+			if role.ExternalID != "" {
+				p.ExternalID = aws.String(role.ExternalID)
+			}
+			if role.MFASerial != "" {
+				p.SerialNumber = aws.String(role.MFASerial)
+				p.TokenProvider = mfaTokenProvider(role.MFASerial)
+			}
+			if role.SessionName != "" {
+				p.RoleSessionName = role.SessionName
+			}
+			if role.DurationSeconds > 0 {
+				p.Duration = time.Duration(role.DurationSeconds) * time.Second
+			}
+		})
+	}
+
+	value, err := creds.Get()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the AWS credentials of the credentials file")
+	}
+	credentialsFileLogger.Do(func() {
+		if n := len(c.AssumeRoles); n > 0 {
+			logrus.Infof("Credentials loaded from the credentials file, assuming the role %s", c.AssumeRoles[n-1].RoleARN)
+			return
+		}
+		logrus.Infof("Credentials loaded from the credentials file using %q provider", value.ProviderName)
+	})
+	credentialsFileCreds[c] = creds
+	return creds, nil
+}
+
+// mfaTokenProvider returns the provider of the tokens of the MFA device with
+// the serial, which asks for them. Profiles of the shared configuration
+// which require MFA use it with no serial.
+func mfaTokenProvider(serial string) func() (string, error) {
+	return func() (string, error) {
+		message := "AWS MFA token code"
+		if serial != "" {
+			message = fmt.Sprintf("AWS MFA token code for %s", serial)
+		}
+		var token string
+		err := answers.Ask("", []*survey.Question{
+			{
+				Prompt: &survey.Input{
+					Message: message,
+					Help:    "The code of the MFA device which the role to assume requires.",
+				},
+			},
+		}, &token)
+		return token, err
+	}
+}
@@ -13,6 +13,9 @@ import (
 type InstanceType struct {
 	DefaultVCpus int64
 	MemInMiB     int64
+	// GPUManufacturers are the manufacturers of the GPUs of the instance
+	// type, such as NVIDIA.
+	GPUManufacturers []string
 }
 
 // instanceTypes retrieves a list of instance types for the given region.
@@ -24,10 +27,16 @@ func instanceTypes(ctx context.Context, session *session.Session, region string)
 		&ec2.DescribeInstanceTypesInput{},
 		func(page *ec2.DescribeInstanceTypesOutput, lastPage bool) bool {
 			for _, info := range page.InstanceTypes {
-				types[*info.InstanceType] = InstanceType{
+				instanceType := InstanceType{
 					DefaultVCpus: aws.Int64Value(info.VCpuInfo.DefaultVCpus),
 					MemInMiB:     aws.Int64Value(info.MemoryInfo.SizeInMiB),
 				}
+				if info.GpuInfo != nil {
+					for _, gpu := range info.GpuInfo.Gpus {
+						instanceType.GPUManufacturers = append(instanceType.GPUManufacturers, aws.StringValue(gpu.Manufacturer))
+					}
+				}
+				types[*info.InstanceType] = instanceType
 			}
 			return !lastPage
 		}); err != nil {
@@ -2,11 +2,13 @@ package aws
 
 import (
 	"context"
+	"sort"
 	"sync"
 
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/pkg/errors"
 
+	"github.com/openshift/installer/pkg/credentialsfile"
 	typesaws "github.com/openshift/installer/pkg/types/aws"
 )
 
@@ -20,6 +22,7 @@ type Metadata struct {
 	publicSubnets     map[string]Subnet
 	vpc               string
 	instanceTypes     map[string]InstanceType
+	credentials       *credentialsfile.AWS
 
 	Region   string                     `json:"region,omitempty"`
 	Subnets  []string                   `json:"subnets,omitempty"`
@@ -33,6 +36,15 @@ func NewMetadata(region string, subnets []string, services []typesaws.ServiceEnd
 	return &Metadata{Region: region, Subnets: subnets, Services: services}
 }
 
+// SetCredentials sets the credentials the session is created with, in place
+// of those of the credentials file of the invocation, of the environment and
+// of the user. They are not part of the state.
+func (m *Metadata) SetCredentials(credentials *credentialsfile.AWS) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.credentials = credentials
+}
+
 // Session holds an AWS session which can be used for AWS API calls
 // during asset generation.
 func (m *Metadata) Session(ctx context.Context) (*session.Session, error) {
@@ -45,7 +57,7 @@ func (m *Metadata) Session(ctx context.Context) (*session.Session, error) {
 func (m *Metadata) unlockedSession(ctx context.Context) (*session.Session, error) {
 	if m.session == nil {
 		var err error
-		m.session, err = GetSessionWithOptions(WithRegion(m.Region), WithServiceEndpoints(m.Region, m.Services))
+		m.session, err = GetSessionWithCredentials(m.credentials, WithRegion(m.Region), WithServiceEndpoints(m.Region, m.Services))
 		if err != nil {
 			return nil, errors.Wrap(err, "creating AWS session")
 		}
@@ -160,3 +172,24 @@ func (m *Metadata) InstanceTypes(ctx context.Context) (map[string]InstanceType,
 
 	return m.instanceTypes, nil
 }
+
+// SubnetCIDRs retrieves the CIDR blocks of the configured subnets, private
+// and public, sorted.
+func SubnetCIDRs(ctx context.Context, meta *Metadata) ([]string, error) {
+	private, err := meta.PrivateSubnets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	public, err := meta.PublicSubnets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cidrs := make([]string, 0, len(private)+len(public))
+	for _, subnets := range []map[string]Subnet{private, public} {
+		for _, subnet := range subnets {
+			cidrs = append(cidrs, subnet.CIDR)
+		}
+	}
+	sort.Strings(cidrs)
+	return cidrs, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 	"github.com/openshift/installer/pkg/types/aws"
 	"github.com/openshift/installer/pkg/version"
 )
@@ -57,7 +58,7 @@ func Platform() (*aws.Platform, error) {
 	sort.Strings(shortRegions)
 
 	var region string
-	err = survey.Ask([]*survey.Question{
+	err = answers.Ask(answers.Region, []*survey.Question{
 		{
 			Prompt: &survey.Select{
 				Message: "Region",
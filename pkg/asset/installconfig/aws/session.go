@@ -19,6 +19,8 @@ import (
 	"github.com/sirupsen/logrus"
 	ini "gopkg.in/ini.v1"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
+	"github.com/openshift/installer/pkg/credentialsfile"
 	typesaws "github.com/openshift/installer/pkg/types/aws"
 	"github.com/openshift/installer/pkg/version"
 )
@@ -58,21 +60,31 @@ func GetSession() (*session.Session, error) { return GetSessionWithOptions() }
 // GetSessionWithOptions returns an AWS session by checking credentials
 // and, if no creds are found, asks for them and stores them on disk in a config file
 func GetSessionWithOptions(optFuncs ...SessionOptions) (*session.Session, error) {
+	return GetSessionWithCredentials(nil, optFuncs...)
+}
+
+// GetSessionWithCredentials returns an AWS session with the credentials, in
+// place of those of the credentials file of the invocation, of the
+// environment and of the user. When the credentials are nil, it is the same
+// as GetSessionWithOptions.
+func GetSessionWithCredentials(creds *credentialsfile.AWS, optFuncs ...SessionOptions) (*session.Session, error) {
 	options := session.Options{
-		Config:            aws.Config{MaxRetries: aws.Int(0)},
-		SharedConfigState: session.SharedConfigEnable,
+		Config:                  aws.Config{MaxRetries: aws.Int(0)},
+		SharedConfigState:       session.SharedConfigEnable,
+		AssumeRoleTokenProvider: mfaTokenProvider(""),
 	}
 	for _, optFunc := range optFuncs {
 		optFunc(&options)
 	}
 
-	_, err := getCredentials(options)
-	if err != nil && errCodeEquals(err, "NoCredentialProviders") {
-		if err = getUserCredentials(); err != nil {
+	if creds == nil {
+		creds = credentialsfile.Get().AWSCredentials()
+	}
+	if creds != nil {
+		if err := applyCredentialsFile(&options, creds); err != nil {
 			return nil, err
 		}
-	}
-	if err != nil {
+	} else if err := getDefaultCredentials(options); err != nil {
 		return nil, err
 	}
 
@@ -85,6 +97,16 @@ func GetSessionWithOptions(optFuncs ...SessionOptions) (*session.Session, error)
 	return ssn, nil
 }
 
+// getDefaultCredentials checks the credentials of the environment and of the
+// user and, if none are found, asks for them.
+func getDefaultCredentials(options session.Options) error {
+	_, err := getCredentials(options)
+	if err != nil && errCodeEquals(err, "NoCredentialProviders") {
+		return getUserCredentials()
+	}
+	return err
+}
+
 func getCredentials(options session.Options) (*credentials.Credentials, error) {
 	sharedCredentialsProvider := &credentials.SharedCredentialsProvider{}
 	providers := []credentials.Provider{
@@ -165,7 +187,7 @@ func errCodeEquals(err error, code string) bool {
 
 func getUserCredentials() error {
 	var keyID string
-	err := survey.Ask([]*survey.Question{
+	err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "AWS Access Key ID",
@@ -178,7 +200,7 @@ func getUserCredentials() error {
 	}
 
 	var secretKey string
-	err = survey.Ask([]*survey.Question{
+	err = answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Password{
 				Message: "AWS Secret Access Key",
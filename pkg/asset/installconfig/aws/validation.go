@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"sort"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/endpoints"
@@ -18,9 +19,11 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/delegation"
 	"github.com/openshift/installer/pkg/rhcos"
 	"github.com/openshift/installer/pkg/types"
 	awstypes "github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/validate"
 )
 
 type resourceRequirements struct {
@@ -49,13 +52,20 @@ func Validate(ctx context.Context, meta *Metadata, config *types.InstallConfig)
 	allErrs = append(allErrs, validatePlatform(ctx, meta, field.NewPath("platform", "aws"), config.Platform.AWS, config.Networking, config.Publish)...)
 
 	if config.ControlPlane != nil && config.ControlPlane.Platform.AWS != nil {
-		allErrs = append(allErrs, validateMachinePool(ctx, meta, field.NewPath("controlPlane", "platform", "aws"), config.Platform.AWS, config.ControlPlane.Platform.AWS, controlPlaneReq)...)
+		req := controlPlaneReq
+		if config.IsSingleReplicaControlPlane() {
+			req.minimumVCpus = types.SingleReplicaControlPlaneMinimumVCPUs
+		}
+		allErrs = append(allErrs, validateMachinePool(ctx, meta, field.NewPath("controlPlane", "platform", "aws"), config.Platform.AWS, config.ControlPlane.Platform.AWS, req)...)
 	}
 	for idx, compute := range config.Compute {
 		fldPath := field.NewPath("compute").Index(idx)
 		if compute.Platform.AWS != nil {
 			allErrs = append(allErrs, validateMachinePool(ctx, meta, fldPath.Child("platform", "aws"), config.Platform.AWS, compute.Platform.AWS, computeReq)...)
 		}
+		if compute.Accelerators != nil {
+			allErrs = append(allErrs, validateAccelerators(ctx, meta, fldPath, config.Platform.AWS, &compute)...)
+		}
 	}
 	return allErrs.ToAggregate()
 }
@@ -221,16 +231,50 @@ func validateMachinePool(ctx context.Context, meta *Metadata, fldPath *field.Pat
 	return allErrs
 }
 
+// validateAccelerators checks that the instance type of the compute pool
+// provides GPUs of the vendor of its accelerators.
+func validateAccelerators(ctx context.Context, meta *Metadata, fldPath *field.Path, platform *awstypes.Platform, pool *types.MachinePool) field.ErrorList {
+	allErrs := field.ErrorList{}
+	typePath := fldPath.Child("platform", "aws", "type")
+	instanceType := ""
+	if platform.DefaultMachinePlatform != nil {
+		instanceType = platform.DefaultMachinePlatform.InstanceType
+	}
+	if pool.Platform.AWS != nil && pool.Platform.AWS.InstanceType != "" {
+		instanceType = pool.Platform.AWS.InstanceType
+	}
+	if instanceType == "" {
+		return append(allErrs, field.Required(typePath, "an instance type with GPUs is required for accelerators"))
+	}
+
+	instanceTypes, err := meta.InstanceTypes(ctx)
+	if err != nil {
+		return append(allErrs, field.InternalError(fldPath, err))
+	}
+	typeMeta, ok := instanceTypes[instanceType]
+	if !ok {
+		// Unknown instance types are reported by the validation of the machine pool.
+		return allErrs
+	}
+	for _, manufacturer := range typeMeta.GPUManufacturers {
+		if strings.EqualFold(manufacturer, string(pool.Accelerators.Vendor)) {
+			return allErrs
+		}
+	}
+	errMsg := fmt.Sprintf("instance type has no %s GPUs", pool.Accelerators.Vendor)
+	return append(allErrs, field.Invalid(typePath, instanceType, errMsg))
+}
+
 func validateSubnetCIDR(fldPath *field.Path, subnets map[string]Subnet, idxMap map[string]int, networks []types.MachineNetworkEntry) field.ErrorList {
 	allErrs := field.ErrorList{}
 	for id, v := range subnets {
 		fp := fldPath.Index(idxMap[id])
-		cidr, _, err := net.ParseCIDR(v.CIDR)
+		_, cidr, err := net.ParseCIDR(v.CIDR)
 		if err != nil {
 			allErrs = append(allErrs, field.Invalid(fp, id, err.Error()))
 			continue
 		}
-		allErrs = append(allErrs, validateMachineNetworksContainIP(fp, networks, id, cidr)...)
+		allErrs = append(allErrs, validateMachineNetworksContainSubnet(fp, networks, id, cidr)...)
 	}
 	return allErrs
 }
@@ -244,6 +288,20 @@ func validateMachineNetworksContainIP(fldPath *field.Path, networks []types.Mach
 	return field.ErrorList{field.Invalid(fldPath, subnetName, fmt.Sprintf("subnet's CIDR range start %s is outside of the specified machine networks", ip))}
 }
 
+// validateMachineNetworksContainSubnet checks that the whole subnet, not only
+// the start of its range, is in one of the machine networks.
+func validateMachineNetworksContainSubnet(fldPath *field.Path, networks []types.MachineNetworkEntry, subnetName string, subnet *net.IPNet) field.ErrorList {
+	if errs := validateMachineNetworksContainIP(fldPath, networks, subnetName, subnet.IP); len(errs) > 0 {
+		return errs
+	}
+	for _, network := range networks {
+		if validate.CIDRContains(&network.CIDR.IPNet, subnet) {
+			return nil
+		}
+	}
+	return field.ErrorList{field.Invalid(fldPath, subnetName, fmt.Sprintf("subnet's CIDR range %s extends beyond the specified machine networks", subnet))}
+}
+
 func validateDuplicateSubnetZones(fldPath *field.Path, subnets map[string]Subnet, idxMap map[string]int, typ string) field.ErrorList {
 	var keys []string
 	for id := range subnets {
@@ -386,6 +444,28 @@ func ValidateForProvisioning(client API, ic *types.InstallConfig, metadata *Meta
 	return allErrs.ToAggregate()
 }
 
+// ValidateBaseDomainDelegation checks that the public hosted zone of the base
+// domain is delegated to its Route53 name servers.
+func ValidateBaseDomainDelegation(client API, ic *types.InstallConfig) error {
+	if ic.Publish == types.InternalPublishingStrategy {
+		return nil
+	}
+
+	fldPath := field.NewPath("baseDomain")
+	zone, err := client.GetBaseDomain(ic.BaseDomain)
+	if err != nil {
+		return field.Invalid(fldPath, ic.BaseDomain, "cannot find base domain")
+	}
+	zoneOutput, err := client.GetHostedZone(aws.StringValue(zone.Id))
+	if err != nil {
+		return field.InternalError(fldPath, err)
+	}
+	if zoneOutput.DelegationSet == nil {
+		return nil
+	}
+	return delegation.ValidateBaseDomain(context.TODO(), ic.BaseDomain, aws.StringValueSlice(zoneOutput.DelegationSet.NameServers), fldPath).ToAggregate()
+}
+
 func validateHostedZone(hostedZoneOutput *route53.GetHostedZoneOutput, hostedZonePath *field.Path, hostedZoneName string, metadata *Metadata) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -176,6 +176,11 @@ func validInstanceTypes() map[string]InstanceType {
 			DefaultVCpus: 4,
 			MemInMiB:     16384,
 		},
+		"g4dn.xlarge": {
+			DefaultVCpus:     4,
+			MemInMiB:         16384,
+			GPUManufacturers: []string{"NVIDIA"},
+		},
 	}
 }
 
@@ -274,6 +279,43 @@ func TestValidate(t *testing.T) {
 		}(),
 		availZones:    validAvailZones(),
 		instanceTypes: validInstanceTypes(),
+	}, {
+		name: "valid accelerators instance type",
+		installConfig: func() *types.InstallConfig {
+			c := validInstallConfig()
+			c.Platform.AWS = &aws.Platform{Region: "us-east-1"}
+			c.ControlPlane.Platform.AWS.InstanceType = "m5.xlarge"
+			c.Compute[0].Platform.AWS.InstanceType = "g4dn.xlarge"
+			c.Compute[0].Accelerators = &types.Accelerators{Vendor: types.AcceleratorVendorNVIDIA}
+			return c
+		}(),
+		availZones:    validAvailZones(),
+		instanceTypes: validInstanceTypes(),
+	}, {
+		name: "accelerators instance type without GPUs of the vendor",
+		installConfig: func() *types.InstallConfig {
+			c := validInstallConfig()
+			c.Platform.AWS = &aws.Platform{Region: "us-east-1"}
+			c.ControlPlane.Platform.AWS.InstanceType = "m5.xlarge"
+			c.Compute[0].Platform.AWS.InstanceType = "g4dn.xlarge"
+			c.Compute[0].Accelerators = &types.Accelerators{Vendor: types.AcceleratorVendorAMD}
+			return c
+		}(),
+		availZones:    validAvailZones(),
+		instanceTypes: validInstanceTypes(),
+		expectErr:     `^\Qcompute[0].platform.aws.type: Invalid value: "g4dn.xlarge": instance type has no AMD GPUs\E$`,
+	}, {
+		name: "accelerators without instance type",
+		installConfig: func() *types.InstallConfig {
+			c := validInstallConfig()
+			c.Platform.AWS = &aws.Platform{Region: "us-east-1"}
+			c.ControlPlane.Platform.AWS.InstanceType = "m5.xlarge"
+			c.Compute[0].Accelerators = &types.Accelerators{Vendor: types.AcceleratorVendorNVIDIA}
+			return c
+		}(),
+		availZones:    validAvailZones(),
+		instanceTypes: validInstanceTypes(),
+		expectErr:     `^\Qcompute[0].platform.aws.type: Required value: an instance type with GPUs is required for accelerators\E$`,
 	}, {
 		name: "invalid control plane instance type",
 		installConfig: func() *types.InstallConfig {
@@ -286,6 +328,20 @@ func TestValidate(t *testing.T) {
 		availZones:    validAvailZones(),
 		instanceTypes: validInstanceTypes(),
 		expectErr:     `^\Q[controlPlane.platform.aws.type: Invalid value: "t2.small": instance type does not meet minimum resource requirements of 4 vCPUs, controlPlane.platform.aws.type: Invalid value: "t2.small": instance type does not meet minimum resource requirements of 16384 MiB Memory]\E$`,
+	}, {
+		name: "invalid single-node control plane instance type",
+		installConfig: func() *types.InstallConfig {
+			c := validInstallConfig()
+			c.Platform.AWS = &aws.Platform{Region: "us-east-1"}
+			c.ControlPlane.Replicas = pointer.Int64Ptr(1)
+			c.ControlPlane.Platform.AWS.InstanceType = "m5.xlarge"
+			c.Compute[0].Replicas = pointer.Int64Ptr(0)
+			c.Compute[0].Platform.AWS.InstanceType = "m5.large"
+			return c
+		}(),
+		availZones:    validAvailZones(),
+		instanceTypes: validInstanceTypes(),
+		expectErr:     `^\QcontrolPlane.platform.aws.type: Invalid value: "m5.xlarge": instance type does not meet minimum resource requirements of 8 vCPUs\E$`,
 	}, {
 		name: "invalid compute instance type",
 		installConfig: func() *types.InstallConfig {
@@ -354,6 +410,23 @@ func TestValidate(t *testing.T) {
 			return s
 		}(),
 		expectErr: `^platform\.aws\.subnets\[6\]: Invalid value: \"invalid-cidr-subnet\": subnet's CIDR range start 192.168.126.0 is outside of the specified machine networks$`,
+	}, {
+		name: "invalid cidr extends beyond machine CIDR",
+		installConfig: func() *types.InstallConfig {
+			c := validInstallConfig()
+			c.Platform.AWS.Subnets = append(c.Platform.AWS.Subnets, "invalid-cidr-subnet")
+			return c
+		}(),
+		availZones:     validAvailZones(),
+		privateSubnets: validPrivateSubnets(),
+		publicSubnets: func() map[string]Subnet {
+			s := validPublicSubnets()
+			s["invalid-cidr-subnet"] = Subnet{
+				CIDR: "10.0.0.0/15",
+			}
+			return s
+		}(),
+		expectErr: `^platform\.aws\.subnets\[6\]: Invalid value: \"invalid-cidr-subnet\": subnet's CIDR range 10.0.0.0/15 extends beyond the specified machine networks$`,
 	}, {
 		name: "invalid cidr does not belong to machine CIDR",
 		installConfig: func() *types.InstallConfig {
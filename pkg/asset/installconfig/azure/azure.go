@@ -11,6 +11,7 @@ import (
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 	"github.com/openshift/installer/pkg/types/azure"
 )
 
@@ -70,7 +71,7 @@ func Platform() (*azure.Platform, error) {
 	sort.Strings(shortRegions)
 
 	var region string
-	err = survey.Ask([]*survey.Question{
+	err = answers.Ask(answers.Region, []*survey.Question{
 		{
 			Prompt: &survey.Select{
 				Message: "Region",
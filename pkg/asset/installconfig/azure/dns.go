@@ -9,6 +9,8 @@ import (
 	azdns "github.com/Azure/azure-sdk-for-go/profiles/2018-03-01/dns/mgmt/dns"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 )
 
 // DNSConfig exposes functions to choose the DNS settings
@@ -19,6 +21,7 @@ type DNSConfig struct {
 // ZonesGetter fetches the DNS zones available for the installer
 type ZonesGetter interface {
 	GetAllPublicZones() (map[string]string, error)
+	GetNameServers(rgName string, zoneName string) ([]string, error)
 }
 
 // ZonesClient wraps the azure ZonesClient internal
@@ -75,7 +78,7 @@ func (config DNSConfig) GetDNSZone() (*Zone, error) {
 	}
 
 	var zoneName string
-	err := survey.Ask([]*survey.Question{
+	err := answers.Ask(answers.BaseDomain, []*survey.Question{
 		{
 			Prompt: &survey.Select{
 				Message: "Base Domain",
@@ -101,6 +104,13 @@ func (config DNSConfig) GetDNSRecordSet(rgName string, zoneName string, relative
 	return recordsetsClient.GetRecordSet(rgName, zoneName, relativeRecordSetName, recordType)
 }
 
+// GetDNSZoneNameServers returns the name servers of the zone, which its
+// parent zone must delegate it to.
+func (config DNSConfig) GetDNSZoneNameServers(rgName string, zoneName string) ([]string, error) {
+	zonesClient := newZonesClient(config.session)
+	return zonesClient.GetNameServers(rgName, zoneName)
+}
+
 // NewDNSConfig returns a new DNSConfig struct that helps configuring the DNS
 // by querying your subscription and letting you choose
 // which domain you wish to use for the cluster
@@ -139,6 +149,21 @@ func (client *ZonesClient) GetAllPublicZones() (map[string]string, error) {
 	return allZones, nil
 }
 
+// GetNameServers gets the name servers of an Azure DNS zone
+func (client *ZonesClient) GetNameServers(rgName string, zoneName string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.TODO(), 30*time.Second)
+	defer cancel()
+
+	zone, err := client.azureClient.Get(ctx, rgName, zoneName)
+	if err != nil {
+		return nil, err
+	}
+	if zone.ZoneProperties == nil || zone.NameServers == nil {
+		return nil, nil
+	}
+	return *zone.NameServers, nil
+}
+
 // GetRecordSet gets an Azure DNS recordset by zone, name and recordset type
 func (client *RecordSetsClient) GetRecordSet(rgName string, zoneName string, relativeRecordSetName string, recordType azdns.RecordType) (*azdns.RecordSet, error) {
 	ctx, cancel := context.WithTimeout(context.TODO(), 30*time.Second)
@@ -0,0 +1,100 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/pkg/errors"
+)
+
+// clientAssertionType is the type of the federated tokens exchanged for the
+// tokens of a service principal.
+const clientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// federatedTokenCredential is the token credential of a service principal
+// which exchanges a federated token of the workload identity of the
+// installer for the tokens of the service principal, with the client
+// credentials grant of Azure Active Directory.
+type federatedTokenCredential struct {
+	tokenURL  string
+	clientID  string
+	tokenFile string
+	client    *http.Client
+
+	mu     sync.Mutex
+	tokens map[string]azcore.AccessToken
+}
+
+func newFederatedTokenCredential(authorityHost, tenantID, clientID, tokenFile string) *federatedTokenCredential {
+	if authorityHost == "" {
+		authorityHost = "https://login.microsoftonline.com/"
+	}
+	return &federatedTokenCredential{
+		tokenURL:  strings.TrimSuffix(authorityHost, "/") + "/" + tenantID + "/oauth2/v2.0/token",
+		clientID:  clientID,
+		tokenFile: tokenFile,
+		client:    http.DefaultClient,
+		tokens:    map[string]azcore.AccessToken{},
+	}
+}
+
+// GetToken returns a token of the service principal for the scopes. The
+// tokens are reused until they are about to expire. The federated token is
+// read again for each exchange, as it is rotated.
+func (c *federatedTokenCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	scope := strings.Join(options.Scopes, " ")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if token, ok := c.tokens[scope]; ok && time.Until(token.ExpiresOn) > 5*time.Minute {
+		return token, nil
+	}
+
+	assertion, err := os.ReadFile(c.tokenFile)
+	if err != nil {
+		return azcore.AccessToken{}, errors.Wrap(err, "failed to read federated token file")
+	}
+	form := url.Values{
+		"grant_type":            {"client_credentials"},
+		"client_id":             {c.clientID},
+		"scope":                 {scope},
+		"client_assertion_type": {clientAssertionType},
+		"client_assertion":      {strings.TrimSpace(string(assertion))},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return azcore.AccessToken{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return azcore.AccessToken{}, errors.Wrap(err, "failed to exchange federated token")
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return azcore.AccessToken{}, errors.Wrapf(err, "failed to decode the token of the federated token exchange (%s)", resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK || body.AccessToken == "" {
+		return azcore.AccessToken{}, errors.Errorf("failed to exchange federated token: %s: %s", body.Error, body.ErrorDescription)
+	}
+	token := azcore.AccessToken{
+		Token:     body.AccessToken,
+		ExpiresOn: time.Now().Add(time.Duration(body.ExpiresIn) * time.Second),
+	}
+	c.tokens[scope] = token
+	return token, nil
+}
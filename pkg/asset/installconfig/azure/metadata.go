@@ -5,6 +5,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/openshift/installer/pkg/credentialsfile"
 	typesazure "github.com/openshift/installer/pkg/types/azure"
 )
 
@@ -31,6 +32,10 @@ type Metadata struct {
 	// serve multiple users with different credentials via a web server.
 	Credentials *Credentials `json:"credentials,omitempty"`
 
+	// fileCredentials are the credentials the session is created with when
+	// Credentials are not set. Unlike those, they are not part of the state.
+	fileCredentials *credentialsfile.Azure
+
 	mutex sync.Mutex
 }
 
@@ -49,6 +54,15 @@ func NewMetadataWithCredentials(cloudName typesazure.CloudEnvironment, armEndpoi
 	}
 }
 
+// SetCredentials sets the credentials the session is created with when
+// Credentials are not set, in place of those of the credentials file of the
+// invocation and of the user. They are not part of the state.
+func (m *Metadata) SetCredentials(credentials *credentialsfile.Azure) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.fileCredentials = credentials
+}
+
 // Session holds an Azure session which can be used for Azure API calls
 // during asset generation.
 func (m *Metadata) Session() (*Session, error) {
@@ -61,7 +75,11 @@ func (m *Metadata) Session() (*Session, error) {
 func (m *Metadata) unlockedSession() (*Session, error) {
 	if m.session == nil {
 		var err error
-		m.session, err = GetSessionWithCredentials(m.CloudName, m.ARMEndpoint, m.Credentials)
+		credentials := m.Credentials
+		if credentials == nil {
+			credentials = CredentialsFromFile(m.fileCredentials)
+		}
+		m.session, err = GetSessionWithCredentials(m.CloudName, m.ARMEndpoint, credentials)
 		if err != nil {
 			return nil, errors.Wrap(err, "creating Azure session")
 		}
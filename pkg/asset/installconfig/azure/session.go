@@ -19,10 +19,18 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
+	"github.com/openshift/installer/pkg/credentialsfile"
 	"github.com/openshift/installer/pkg/types/azure"
 )
 
-const azureAuthEnv = "AZURE_AUTH_LOCATION"
+const (
+	azureAuthEnv = "AZURE_AUTH_LOCATION"
+
+	// credentialsFileLogger is the logger of the credentials loaded from the
+	// credentials file of the invocation.
+	credentialsFileLogger = "credentialsFile"
+)
 
 var (
 	defaultAuthFilePath = filepath.Join(os.Getenv("HOME"), ".azure", "osServicePrincipal.json")
@@ -45,6 +53,10 @@ type Credentials struct {
 	TenantID                  string `json:"tenantId,omitempty"`
 	ClientCertificatePath     string `json:"certificatePath,omitempty"`
 	ClientCertificatePassword string `json:"certificatePassword,omitempty"`
+	// FederatedTokenFile is the file of a federated token of the workload
+	// identity of the installer, e.g. a projected Kubernetes service account
+	// token, which is exchanged for the tokens of the service principal.
+	FederatedTokenFile string `json:"federatedTokenFile,omitempty"`
 }
 
 // GetSession returns an azure session by using credentials found in ~/.azure/osServicePrincipal.json
@@ -89,16 +101,23 @@ func GetSessionWithCredentials(cloudName azure.CloudEnvironment, armEndpoint str
 		cloudConfig = cloud.AzurePublic
 	}
 
+	if c := credentialsfile.Get().AzureCredentials(); credentials == nil && c != nil {
+		credentials = CredentialsFromFile(c)
+	}
 	if credentials == nil {
 		credentials, err = credentialsFromFileOrUser(&cloudEnv)
 		if err != nil {
 			return nil, err
 		}
 	}
-	if credentials.ClientCertificatePath != "" {
+	switch {
+	case credentials.FederatedTokenFile != "":
+		return newSessionFromFederatedToken(cloudEnv, credentials, cloudConfig)
+	case credentials.ClientCertificatePath != "":
 		return newSessionFromCertificates(cloudEnv, credentials, cloudConfig)
+	default:
+		return newSessionFromCredentials(cloudEnv, credentials, cloudConfig)
 	}
-	return newSessionFromCredentials(cloudEnv, credentials, cloudConfig)
 }
 
 // credentialsFromFileOrUser returns credentials found
@@ -109,6 +128,10 @@ func credentialsFromFileOrUser(cloudEnv *azureenv.Environment) (*Credentials, er
 	if f := os.Getenv(azureAuthEnv); len(f) > 0 {
 		authFilePath = f
 	}
+	// federated credentials are not known to the auth file of the SDK
+	if credentials, err := federatedCredentialsFromFile(authFilePath); err != nil || credentials != nil {
+		return credentials, err
+	}
 	// NewAuthorizerFromFileWithResource uses `auth.GetSettingsFromFile`, which uses the `azureAuthEnv` to fetch the auth credentials.
 	// therefore setting the local env here to authFilePath allows NewAuthorizerFromFileWithResource to load credentials.
 	os.Setenv(azureAuthEnv, authFilePath)
@@ -147,6 +170,81 @@ func credentialsFromFileOrUser(cloudEnv *azureenv.Environment) (*Credentials, er
 	return credentials, nil
 }
 
+// federatedCredentialsFromFile returns the credentials of the auth file when
+// they are federated, and nil otherwise.
+func federatedCredentialsFromFile(authFilePath string) (*Credentials, error) {
+	data, err := os.ReadFile(authFilePath)
+	if err != nil {
+		// the file is read, or asked for, by the SDK
+		return nil, nil
+	}
+	var credentials Credentials
+	if err := json.Unmarshal(data, &credentials); err != nil || credentials.FederatedTokenFile == "" {
+		return nil, nil
+	}
+	if err := validateCredentials(&credentials); err != nil {
+		return nil, errors.Wrapf(err, "invalid credentials in %q", authFilePath)
+	}
+	if _, has := onceLoggers[authFilePath]; !has {
+		onceLoggers[authFilePath] = new(sync.Once)
+	}
+	onceLoggers[authFilePath].Do(func() {
+		logrus.Infof("Federated credentials loaded from file %q", authFilePath)
+	})
+	return &credentials, nil
+}
+
+// validateCredentials validates that the credentials have one way to
+// authenticate: a client secret, a client certificate or a federated token.
+func validateCredentials(c *Credentials) error {
+	switch {
+	case c.SubscriptionID == "":
+		return errors.New("subscriptionId: a subscription is required")
+	case c.TenantID == "":
+		return errors.New("tenantId: a tenant is required")
+	case c.ClientID == "":
+		return errors.New("clientId: a client is required")
+	}
+	methods := 0
+	for _, set := range []bool{c.ClientSecret != "", c.ClientCertificatePath != "", c.FederatedTokenFile != ""} {
+		if set {
+			methods++
+		}
+	}
+	switch {
+	case methods == 0:
+		return errors.New("one of clientSecret, certificatePath or federatedTokenFile is required")
+	case methods > 1:
+		return errors.New("only one of clientSecret, certificatePath or federatedTokenFile may be set")
+	case c.ClientCertificatePassword != "" && c.ClientCertificatePath == "":
+		return errors.New("certificatePassword: a password requires certificatePath")
+	}
+	return nil
+}
+
+// CredentialsFromFile returns the credentials of the Azure credentials of a
+// credentials file, or nil when there are none.
+func CredentialsFromFile(c *credentialsfile.Azure) *Credentials {
+	if c == nil {
+		return nil
+	}
+	if _, has := onceLoggers[credentialsFileLogger]; !has {
+		onceLoggers[credentialsFileLogger] = new(sync.Once)
+	}
+	onceLoggers[credentialsFileLogger].Do(func() {
+		logrus.Info("Credentials loaded from the credentials file")
+	})
+	return &Credentials{
+		SubscriptionID:            c.SubscriptionID,
+		ClientID:                  c.ClientID,
+		ClientSecret:              c.ClientSecret,
+		TenantID:                  c.TenantID,
+		ClientCertificatePath:     c.ClientCertificatePath,
+		ClientCertificatePassword: c.ClientCertificatePassword,
+		FederatedTokenFile:        c.FederatedTokenFile,
+	}
+}
+
 func getCredentials(fs auth.FileSettings) (*Credentials, error) {
 	subscriptionID := fs.GetSubscriptionID()
 	if subscriptionID == "" {
@@ -183,7 +281,7 @@ func getCredentials(fs auth.FileSettings) (*Credentials, error) {
 func askForCredentials() (*Credentials, error) {
 	var subscriptionID, tenantID, clientID, clientSecret string
 
-	err := survey.Ask([]*survey.Question{
+	err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "azure subscription id",
@@ -195,7 +293,7 @@ func askForCredentials() (*Credentials, error) {
 		return nil, err
 	}
 
-	err = survey.Ask([]*survey.Question{
+	err = answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "azure tenant id",
@@ -207,7 +305,7 @@ func askForCredentials() (*Credentials, error) {
 		return nil, err
 	}
 
-	err = survey.Ask([]*survey.Question{
+	err = answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "azure service principal client id",
@@ -219,7 +317,7 @@ func askForCredentials() (*Credentials, error) {
 		return nil, err
 	}
 
-	err = survey.Ask([]*survey.Question{
+	err = answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Password{
 				Message: "azure service principal client secret",
@@ -264,26 +362,7 @@ func newSessionFromCredentials(cloudEnv azureenv.Environment, credentials *Crede
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get client credentials from secret")
 	}
-
-	authProvider, err := azurekiota.NewAzureIdentityAuthenticationProvider(cred)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get Azidentity authentication provider")
-	}
-
-	// Use an adapter so azidentity in the Azure SDK can be used as
-	// Authorizer when calling the Azure Management Packages, which we
-	// currently use. Once the Azure SDK clients (found in /sdk) move to
-	// stable, we can update our clients and they will be able to use the
-	// creds directly without the authorizer. The schedule is here:
-	// https://azure.github.io/azure-sdk/releases/latest/index.html#go
-	authorizer := azidext.NewTokenCredentialAdapter(cred, []string{endpointToScope(cloudEnv.TokenAudience)})
-
-	return &Session{
-		Authorizer:   authorizer,
-		Credentials:  *credentials,
-		Environment:  cloudEnv,
-		AuthProvider: authProvider,
-	}, nil
+	return newSession(cloudEnv, credentials, cred)
 }
 
 func newSessionFromCertificates(cloudEnv azureenv.Environment, credentials *Credentials, cloudConfig cloud.Configuration) (*Session, error) {
@@ -303,7 +382,11 @@ func newSessionFromCertificates(cloudEnv azureenv.Environment, credentials *Cred
 	// certificate data in PEM or PKCS12 format. It handles common scenarios
 	// but has limitations, for example it doesn't load PEM encrypted private
 	// keys.
-	certs, key, err := azidentity.ParseCertificates(data, nil)
+	var password []byte
+	if credentials.ClientCertificatePassword != "" {
+		password = []byte(credentials.ClientCertificatePassword)
+	}
+	certs, key, err := azidentity.ParseCertificates(data, password)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to parse client certificate")
 	}
@@ -312,7 +395,19 @@ func newSessionFromCertificates(cloudEnv azureenv.Environment, credentials *Cred
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get client credentials from certificate")
 	}
+	return newSession(cloudEnv, credentials, cred)
+}
+
+func newSessionFromFederatedToken(cloudEnv azureenv.Environment, credentials *Credentials, cloudConfig cloud.Configuration) (*Session, error) {
+	if _, err := os.Stat(credentials.FederatedTokenFile); err != nil {
+		return nil, errors.Wrap(err, "failed to read federated token file")
+	}
+	cred := newFederatedTokenCredential(cloudConfig.ActiveDirectoryAuthorityHost, credentials.TenantID, credentials.ClientID, credentials.FederatedTokenFile)
+	return newSession(cloudEnv, credentials, cred)
+}
 
+// newSession returns a session authenticating with the token credential.
+func newSession(cloudEnv azureenv.Environment, credentials *Credentials, cred azcore.TokenCredential) (*Session, error) {
 	authProvider, err := azurekiota.NewAzureIdentityAuthenticationProvider(cred)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get Azidentity authentication provider")
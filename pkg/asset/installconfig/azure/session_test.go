@@ -0,0 +1,152 @@
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCredentials(t *testing.T) {
+	valid := func() *Credentials {
+		return &Credentials{SubscriptionID: "subscription", TenantID: "tenant", ClientID: "client"}
+	}
+	cases := []struct {
+		name  string
+		edit  func(*Credentials)
+		error string
+	}{
+		{
+			name: "client secret",
+			edit: func(c *Credentials) { c.ClientSecret = "secret" },
+		},
+		{
+			name: "client certificate",
+			edit: func(c *Credentials) {
+				c.ClientCertificatePath = "/etc/azure/client.pfx"
+				c.ClientCertificatePassword = "password"
+			},
+		},
+		{
+			name: "federated token",
+			edit: func(c *Credentials) { c.FederatedTokenFile = "/var/run/secrets/azure/tokens/azure-identity-token" },
+		},
+		{
+			name: "missing subscription",
+			edit: func(c *Credentials) {
+				c.SubscriptionID = ""
+				c.ClientSecret = "secret"
+			},
+			error: "subscriptionId: a subscription is required",
+		},
+		{
+			name:  "no authentication",
+			edit:  func(c *Credentials) {},
+			error: "one of clientSecret, certificatePath or federatedTokenFile is required",
+		},
+		{
+			name: "secret and federated token",
+			edit: func(c *Credentials) {
+				c.ClientSecret = "secret"
+				c.FederatedTokenFile = "/var/run/secrets/azure/tokens/azure-identity-token"
+			},
+			error: "only one of clientSecret, certificatePath or federatedTokenFile may be set",
+		},
+		{
+			name: "password without certificate",
+			edit: func(c *Credentials) {
+				c.ClientSecret = "secret"
+				c.ClientCertificatePassword = "password"
+			},
+			error: "certificatePassword: a password requires certificatePath",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := valid()
+			tc.edit(c)
+			err := validateCredentials(c)
+			if tc.error == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.error)
+			}
+		})
+	}
+}
+
+func TestFederatedCredentialsFromFile(t *testing.T) {
+	dir := t.TempDir()
+	federated := filepath.Join(dir, "federated.json")
+	secret := filepath.Join(dir, "secret.json")
+	invalid := filepath.Join(dir, "invalid.json")
+	for path, content := range map[string]string{
+		federated: `{"subscriptionId":"subscription","tenantId":"tenant","clientId":"client","federatedTokenFile":"/var/run/token"}`,
+		secret:    `{"subscriptionId":"subscription","tenantId":"tenant","clientId":"client","clientSecret":"secret"}`,
+		invalid:   `{"subscriptionId":"subscription","tenantId":"tenant","clientId":"client","clientSecret":"secret","federatedTokenFile":"/var/run/token"}`,
+	} {
+		if !assert.NoError(t, os.WriteFile(path, []byte(content), 0o600)) {
+			return
+		}
+	}
+
+	credentials, err := federatedCredentialsFromFile(federated)
+	if assert.NoError(t, err) {
+		assert.Equal(t, &Credentials{SubscriptionID: "subscription", TenantID: "tenant", ClientID: "client", FederatedTokenFile: "/var/run/token"}, credentials)
+	}
+	for _, path := range []string{secret, filepath.Join(dir, "missing.json")} {
+		credentials, err := federatedCredentialsFromFile(path)
+		assert.NoError(t, err)
+		assert.Nil(t, credentials)
+	}
+	_, err = federatedCredentialsFromFile(invalid)
+	assert.Error(t, err)
+}
+
+func TestFederatedTokenCredential(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if !assert.NoError(t, os.WriteFile(tokenFile, []byte("federated-token\n"), 0o600)) {
+		return
+	}
+
+	exchanges := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exchanges++
+		assert.Equal(t, "/tenant/oauth2/v2.0/token", r.URL.Path)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "client", r.PostForm.Get("client_id"))
+		assert.Equal(t, "https://management.azure.com/.default", r.PostForm.Get("scope"))
+		assert.Equal(t, clientAssertionType, r.PostForm.Get("client_assertion_type"))
+		if r.PostForm.Get("client_assertion") != "federated-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client","error_description":"the assertion is not valid"}`))
+			return
+		}
+		w.Write([]byte(`{"access_token":"access-token","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	cred := newFederatedTokenCredential(server.URL+"/", "tenant", "client", tokenFile)
+	options := policy.TokenRequestOptions{Scopes: []string{"https://management.azure.com/.default"}}
+	for i := 0; i < 2; i++ {
+		token, err := cred.GetToken(context.Background(), options)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "access-token", token.Token)
+		}
+	}
+	// the token is reused until it is about to expire
+	assert.Equal(t, 1, exchanges)
+
+	cred = newFederatedTokenCredential(server.URL, "tenant", "client", tokenFile)
+	if !assert.NoError(t, os.WriteFile(tokenFile, []byte("rotated-token"), 0o600)) {
+		return
+	}
+	_, err := cred.GetToken(context.Background(), options)
+	assert.EqualError(t, err, "failed to exchange federated token: invalid_client: the assertion is not valid")
+}
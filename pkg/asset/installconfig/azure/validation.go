@@ -17,9 +17,11 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/delegation"
 	"github.com/openshift/installer/pkg/types"
 	aztypes "github.com/openshift/installer/pkg/types/azure"
 	"github.com/openshift/installer/pkg/types/azure/defaults"
+	"github.com/openshift/installer/pkg/validate"
 )
 
 type resourceRequirements struct {
@@ -242,6 +244,30 @@ func validateUltraSSD(client API, fieldPath *field.Path, icZones []string, regio
 	return allErrs
 }
 
+// validateGPUs checks that the instance type of a compute pool with
+// accelerators provides GPUs. The resource SKUs do not report the vendor of
+// the GPUs, so it is not validated.
+func validateGPUs(client API, fieldPath *field.Path, region, instanceType string) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	capabilities, err := client.GetVMCapabilities(context.TODO(), instanceType, region)
+	if err != nil {
+		return append(allErrs, field.Invalid(fieldPath, instanceType, err.Error()))
+	}
+	val, ok := capabilities["GPUs"]
+	if !ok {
+		return append(allErrs, field.Invalid(fieldPath, instanceType, "instance type has no GPUs"))
+	}
+	gpus, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return append(allErrs, field.InternalError(fieldPath, err))
+	}
+	if gpus < 1 {
+		allErrs = append(allErrs, field.Invalid(fieldPath, instanceType, "instance type has no GPUs"))
+	}
+	return allErrs
+}
+
 // ValidateInstanceType ensures the instance type has sufficient Vcpu, Memory, and a valid family type.
 func ValidateInstanceType(client API, fieldPath *field.Path, region, instanceType, diskType string, req resourceRequirements, ultraSSDEnabled bool, vmNetworkingType string, icZones []string, architecture types.Architecture) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -332,7 +358,11 @@ func validateInstanceTypes(client API, ic *types.InstallConfig) field.ErrorList
 			zones = defaultZones
 		}
 		ultraSSDEnabled := strings.EqualFold(ultraSSDCapability, "Enabled")
-		allErrs = append(allErrs, ValidateInstanceType(client, fieldPath, ic.Azure.Region, instanceType, diskType, controlPlaneReq, ultraSSDEnabled, vmNetworkingType, zones, architecture)...)
+		req := controlPlaneReq
+		if ic.IsSingleReplicaControlPlane() {
+			req.minimumVCpus = types.SingleReplicaControlPlaneMinimumVCPUs
+		}
+		allErrs = append(allErrs, ValidateInstanceType(client, fieldPath, ic.Azure.Region, instanceType, diskType, req, ultraSSDEnabled, vmNetworkingType, zones, architecture)...)
 	}
 
 	for idx, compute := range ic.Compute {
@@ -367,6 +397,9 @@ func validateInstanceTypes(client API, ic *types.InstallConfig) field.ErrorList
 			ultraSSDEnabled := strings.EqualFold(ultraSSDCapability, "Enabled")
 			allErrs = append(allErrs, ValidateInstanceType(client, fieldPath.Child("platform", "azure"),
 				ic.Azure.Region, instanceType, diskType, computeReq, ultraSSDEnabled, vmNetworkingType, zones, architecture)...)
+			if compute.Accelerators != nil {
+				allErrs = append(allErrs, validateGPUs(client, fieldPath.Child("platform", "azure", "type"), ic.Azure.Region, instanceType)...)
+			}
 		}
 	}
 
@@ -414,16 +447,37 @@ func validateNetworks(client API, p *aztypes.Platform, machineNetworks []types.M
 	return allErrs
 }
 
+// SubnetCIDRs retrieves the address prefixes of the compute and control plane
+// subnets of the user-provided VNet.
+func SubnetCIDRs(ctx context.Context, client API, p *aztypes.Platform) ([]string, error) {
+	computeSubnet, err := client.GetComputeSubnet(ctx, p.NetworkResourceGroupName, p.VirtualNetwork, p.ComputeSubnet)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve compute subnet %s", p.ComputeSubnet)
+	}
+	controlPlaneSubnet, err := client.GetControlPlaneSubnet(ctx, p.NetworkResourceGroupName, p.VirtualNetwork, p.ControlPlaneSubnet)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve control plane subnet %s", p.ControlPlaneSubnet)
+	}
+	var cidrs []string
+	for _, subnet := range []*aznetwork.Subnet{controlPlaneSubnet, computeSubnet} {
+		if subnet.AddressPrefix == nil {
+			return nil, errors.Errorf("subnet %s has no address prefix", to.String(subnet.Name))
+		}
+		cidrs = append(cidrs, *subnet.AddressPrefix)
+	}
+	return cidrs, nil
+}
+
 // validateSubnet checks that the subnet is in the same network as the machine CIDR
 func validateSubnet(client API, fieldPath *field.Path, subnet *aznetwork.Subnet, subnetName string, networks []types.MachineNetworkEntry) field.ErrorList {
 	allErrs := field.ErrorList{}
 
-	subnetIP, _, err := net.ParseCIDR(*subnet.AddressPrefix)
+	_, subnetCIDR, err := net.ParseCIDR(*subnet.AddressPrefix)
 	if err != nil {
 		return append(allErrs, field.Invalid(fieldPath, subnetName, "unable to parse subnet CIDR"))
 	}
 
-	allErrs = append(allErrs, validateMachineNetworksContainIP(fieldPath, networks, *subnet.Name, subnetIP)...)
+	allErrs = append(allErrs, validateMachineNetworksContainSubnet(fieldPath, networks, *subnet.Name, subnetCIDR)...)
 	return allErrs
 }
 
@@ -436,6 +490,20 @@ func validateMachineNetworksContainIP(fldPath *field.Path, networks []types.Mach
 	return field.ErrorList{field.Invalid(fldPath, subnetName, fmt.Sprintf("subnet %s address prefix is outside of the specified machine networks", ip))}
 }
 
+// validateMachineNetworksContainSubnet checks that the whole subnet, not only
+// the start of its range, is in one of the machine networks.
+func validateMachineNetworksContainSubnet(fldPath *field.Path, networks []types.MachineNetworkEntry, subnetName string, subnet *net.IPNet) field.ErrorList {
+	if errs := validateMachineNetworksContainIP(fldPath, networks, subnetName, subnet.IP); len(errs) > 0 {
+		return errs
+	}
+	for _, network := range networks {
+		if validate.CIDRContains(&network.CIDR.IPNet, subnet) {
+			return nil
+		}
+	}
+	return field.ErrorList{field.Invalid(fldPath, subnetName, fmt.Sprintf("subnet %s address prefix extends beyond the specified machine networks", subnet))}
+}
+
 // validateRegion checks that the desired region is valid and available to the user
 func validateRegion(client API, fieldPath *field.Path, p *aztypes.Platform) field.ErrorList {
 	locations, err := client.ListLocations(context.TODO())
@@ -487,33 +555,48 @@ func ValidatePublicDNS(ic *types.InstallConfig, azureDNS *DNSConfig) error {
 		return nil
 	}
 
-	clusterName := ic.ObjectMeta.Name
-	record := fmt.Sprintf("api.%s", clusterName)
 	rgName := ic.Azure.BaseDomainResourceGroupName
 	zoneName := ic.BaseDomain
-	fmtStr := "api.%s %s record already exists in %s and might be in use by another cluster, please remove it to continue"
+	// the name of the record is relative to the zone of the base domain
+	record := strings.TrimSuffix(ic.APIDomainName(), "."+strings.TrimSuffix(zoneName, "."))
+	fmtStr := "%s %s record already exists in %s and might be in use by another cluster, please remove it to continue"
 
 	// Look for an existing CNAME first
 	rs, err := azureDNS.GetDNSRecordSet(rgName, zoneName, record, azdns.CNAME)
 	if err == nil && rs.CnameRecord != nil {
-		return errors.New(fmt.Sprintf(fmtStr, zoneName, azdns.CNAME, clusterName))
+		return errors.New(fmt.Sprintf(fmtStr, ic.APIDomainName(), azdns.CNAME, zoneName))
 	}
 
 	// Look for an A record
 	rs, err = azureDNS.GetDNSRecordSet(rgName, zoneName, record, azdns.A)
 	if err == nil && rs.ARecords != nil && len(*rs.ARecords) > 0 {
-		return errors.New(fmt.Sprintf(fmtStr, zoneName, azdns.A, clusterName))
+		return errors.New(fmt.Sprintf(fmtStr, ic.APIDomainName(), azdns.A, zoneName))
 	}
 
 	// Look for an AAAA record
 	rs, err = azureDNS.GetDNSRecordSet(rgName, zoneName, record, azdns.AAAA)
 	if err == nil && rs.AaaaRecords != nil && len(*rs.AaaaRecords) > 0 {
-		return errors.New(fmt.Sprintf(fmtStr, zoneName, azdns.AAAA, clusterName))
+		return errors.New(fmt.Sprintf(fmtStr, ic.APIDomainName(), azdns.AAAA, zoneName))
 	}
 
 	return nil
 }
 
+// ValidateBaseDomainDelegation checks that the public zone of the base domain
+// is delegated to its Azure DNS name servers.
+func ValidateBaseDomainDelegation(ic *types.InstallConfig, azureDNS *DNSConfig) error {
+	if ic.Publish == types.InternalPublishingStrategy {
+		return nil
+	}
+
+	fldPath := field.NewPath("baseDomain")
+	nameServers, err := azureDNS.GetDNSZoneNameServers(ic.Azure.BaseDomainResourceGroupName, ic.BaseDomain)
+	if err != nil {
+		return field.InternalError(fldPath, err)
+	}
+	return delegation.ValidateBaseDomain(context.TODO(), ic.BaseDomain, nameServers, fldPath).ToAggregate()
+}
+
 // ValidateForProvisioning validates if the isntall config if valid for provisioning the cluster.
 func ValidateForProvisioning(client API, ic *types.InstallConfig) error {
 	allErrs := field.ErrorList{}
@@ -8,6 +8,7 @@ import (
 	"github.com/AlecAivazis/survey/v2/terminal"
 	"github.com/pkg/errors"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 	"github.com/openshift/installer/pkg/ipnet"
 	"github.com/openshift/installer/pkg/types/baremetal"
 	baremetaldefaults "github.com/openshift/installer/pkg/types/baremetal/defaults"
@@ -19,7 +20,7 @@ func Platform() (*baremetal.Platform, error) {
 	var parsedCIDR *ipnet.IPNet
 	var hosts []*baremetal.Host
 
-	survey.AskOne(&survey.Select{
+	answers.AskOne("", &survey.Select{
 		Message: "Provisioning Network",
 		Help:    "Select whether the provisioning network will be managed, unmanaged, or disabled. In managed mode, the cluster deploys DHCP and TFTP services for PXE provisioning.",
 		Options: []string{"Managed", "Unmanaged", "Disabled"},
@@ -27,7 +28,7 @@ func Platform() (*baremetal.Platform, error) {
 	}, &provisioningNetwork, nil)
 
 	if provisioningNetwork != string(baremetal.DisabledProvisioningNetwork) {
-		if err := survey.Ask([]*survey.Question{
+		if err := answers.Ask("", []*survey.Question{
 			{
 				Prompt: &survey.Input{
 					Message: "Provisioning Network CIDR",
@@ -45,7 +46,7 @@ func Platform() (*baremetal.Platform, error) {
 		}
 		parsedCIDR = provNetCIDR
 
-		if err := survey.Ask([]*survey.Question{
+		if err := answers.Ask("", []*survey.Question{
 			{
 				Prompt: &survey.Input{
 					Message: "Provisioning bridge",
@@ -57,7 +58,7 @@ func Platform() (*baremetal.Platform, error) {
 			return nil, err
 		}
 
-		if err := survey.Ask([]*survey.Question{
+		if err := answers.Ask("", []*survey.Question{
 			{
 				Prompt: &survey.Input{
 					Message: "Provisioning Network Interface",
@@ -69,7 +70,7 @@ func Platform() (*baremetal.Platform, error) {
 		}
 	}
 
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "External bridge",
@@ -84,7 +85,7 @@ func Platform() (*baremetal.Platform, error) {
 	// Keep prompting for hosts
 	for {
 		var hostRole string
-		survey.AskOne(&survey.Select{
+		answers.AskOne("", &survey.Select{
 			Message: "Add a Host:",
 			Options: []string{"control plane", "worker"},
 		}, &hostRole, nil)
@@ -108,7 +109,7 @@ func Platform() (*baremetal.Platform, error) {
 		hosts = append(hosts, host)
 
 		more := false
-		survey.AskOne(&survey.Confirm{
+		answers.AskOne("", &survey.Confirm{
 			Message: "Add another host?",
 		}, &more, nil)
 		if !more {
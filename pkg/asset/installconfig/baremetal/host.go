@@ -3,6 +3,7 @@ package baremetal
 import (
 	"github.com/AlecAivazis/survey/v2"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 	"github.com/openshift/installer/pkg/types/baremetal"
 	"github.com/openshift/installer/pkg/validate"
 )
@@ -11,7 +12,7 @@ import (
 func Host() (*baremetal.Host, error) {
 	var host baremetal.Host
 
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "Name",
@@ -22,7 +23,7 @@ func Host() (*baremetal.Host, error) {
 		return nil, err
 	}
 
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "BMC Address",
@@ -34,7 +35,7 @@ func Host() (*baremetal.Host, error) {
 		return nil, err
 	}
 
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "BMC Username",
@@ -45,7 +46,7 @@ func Host() (*baremetal.Host, error) {
 		return nil, err
 	}
 
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Password{
 				Message: "BMC Password",
@@ -56,7 +57,7 @@ func Host() (*baremetal.Host, error) {
 		return nil, err
 	}
 
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "Boot MAC Address",
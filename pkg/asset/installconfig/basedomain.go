@@ -7,6 +7,7 @@ import (
 
 	"github.com/openshift/installer/pkg/asset"
 	alibabacloudconfig "github.com/openshift/installer/pkg/asset/installconfig/alibabacloud"
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 	awsconfig "github.com/openshift/installer/pkg/asset/installconfig/aws"
 	azureconfig "github.com/openshift/installer/pkg/asset/installconfig/azure"
 	gcpconfig "github.com/openshift/installer/pkg/asset/installconfig/gcp"
@@ -91,7 +92,7 @@ func (a *baseDomain) Generate(parents asset.Parents) error {
 		//Do nothing
 	}
 
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask(answers.BaseDomain, []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "Base Domain",
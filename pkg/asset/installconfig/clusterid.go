@@ -1,14 +1,17 @@
 package installconfig
 
 import (
+	"crypto/rand"
 	"fmt"
+	"io"
+	"math/big"
 	"regexp"
 	"strings"
 
-	"github.com/pborman/uuid"
-	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"github.com/google/uuid"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/deterministic"
 )
 
 const (
@@ -23,9 +26,11 @@ type ClusterID struct {
 	// InfraID is an identifier for the cluster that is more human friendly.
 	// This does not have
 	InfraID string
+
+	source *deterministic.Source
 }
 
-var _ asset.Asset = (*ClusterID)(nil)
+var _ asset.SourcedAsset = (*ClusterID)(nil)
 
 // Dependencies returns install-config.
 func (a *ClusterID) Dependencies() []asset.Asset {
@@ -44,8 +49,19 @@ func (a *ClusterID) Generate(dep asset.Parents) error {
 	maxLen := 27
 
 	// add random chars to the end to randomize
-	a.InfraID = generateInfraID(ica.Config.ObjectMeta.Name, maxLen)
-	a.UUID = uuid.New()
+	infraID, err := generateInfraID(a.source.Reader(), ica.Config.ObjectMeta.Name, maxLen)
+	if err != nil {
+		return err
+	}
+	a.InfraID = infraID
+	a.UUID = a.source.ClusterID()
+	if a.UUID == "" {
+		id, err := uuid.NewRandomFromReader(a.source.Reader())
+		if err != nil {
+			return err
+		}
+		a.UUID = id.String()
+	}
 	return nil
 }
 
@@ -54,10 +70,15 @@ func (a *ClusterID) Name() string {
 	return "Cluster ID"
 }
 
+// SetSource sets the source the IDs are drawn from.
+func (a *ClusterID) SetSource(source *deterministic.Source) {
+	a.source = source
+}
+
 // generateInfraID take base and returns a ID that
 // - is of length maxLen
 // - only contains `alphanum` or `-`
-func generateInfraID(base string, maxLen int) string {
+func generateInfraID(r io.Reader, base string, maxLen int) (string, error) {
 	maxBaseLen := maxLen - (randomLen + 1)
 
 	// replace all characters that are not `alphanum` or `-` with `-`
@@ -75,5 +96,24 @@ func generateInfraID(base string, maxLen int) string {
 	base = strings.TrimRight(base, "-")
 
 	// add random chars to the end to randomize
-	return fmt.Sprintf("%s-%s", base, utilrand.String(randomLen))
+	suffix, err := randomString(r, randomLen)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s-%s", base, suffix), nil
+}
+
+// randomString returns n random characters from the alphabet of
+// k8s.io/apimachinery/pkg/util/rand.String, drawn from r.
+func randomString(r io.Reader, n int) (string, error) {
+	const alphabet = "bcdfghjklmnpqrstvwxz2456789"
+	b := make([]byte, n)
+	for i := range b {
+		c, err := rand.Int(r, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			return "", err
+		}
+		b[i] = alphabet[c.Int64()]
+	}
+	return string(b), nil
 }
@@ -1,9 +1,13 @@
 package installconfig
 
 import (
+	"crypto/rand"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/deterministic"
 )
 
 func Test_generateInfraID(t *testing.T) {
@@ -31,10 +35,22 @@ func Test_generateInfraID(t *testing.T) {
 	}}
 	for _, test := range tests {
 		t.Run("", func(t *testing.T) {
-			got := generateInfraID(test.input, 27)
+			got, err := generateInfraID(rand.Reader, test.input, 27)
+			assert.NoError(t, err)
 			t.Log("InfraID", got)
 			assert.Equal(t, test.expLen, len(got))
 			assert.Equal(t, test.expNonRand, got[:len(got)-randomLen-1])
 		})
 	}
 }
+
+func Test_generateInfraIDDeterministic(t *testing.T) {
+	source, err := deterministic.New(deterministic.Config{Seed: []byte("0123456789abcdef"), Time: time.Unix(1700000000, 0)})
+	assert.NoError(t, err)
+
+	first, err := generateInfraID(source.ForAsset("cluster-id").Reader(), "cluster", 27)
+	assert.NoError(t, err)
+	second, err := generateInfraID(source.ForAsset("cluster-id").Reader(), "cluster", 27)
+	assert.NoError(t, err)
+	assert.Equal(t, first, second, "the infrastructure ID should be reproducible")
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/validate"
 )
@@ -58,7 +59,7 @@ func (a *clusterName) Generate(parents asset.Parents) error {
 		return validate.DomainName(installConfig.ClusterDomain(), false)
 	})
 
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask(answers.ClusterName, []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "Cluster Name",
@@ -0,0 +1,123 @@
+package connectivity
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/alibabacloud"
+	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/azure"
+	"github.com/openshift/installer/pkg/types/gcp"
+	"github.com/openshift/installer/pkg/types/ibmcloud"
+	"github.com/openshift/installer/pkg/types/none"
+	"github.com/openshift/installer/pkg/types/powervs"
+)
+
+// lookupHost is a wrapper for the resolver so we can override it in the tests.
+var lookupHost = func(ctx context.Context, host string) ([]string, error) {
+	return net.DefaultResolver.LookupHost(ctx, host)
+}
+
+// managedDNSPlatforms are the platforms whose DNS records the installer
+// creates in the zone of the base domain.
+var managedDNSPlatforms = sets.NewString(alibabacloud.Name, aws.Name, azure.Name, gcp.Name, ibmcloud.Name, powervs.Name)
+
+// dnsRecord is a record of the cluster checked before the install.
+type dnsRecord struct {
+	// name is the name of the record, api or *.apps.
+	name string
+	// host is the name to resolve. For the wildcard record of the apps
+	// domain, it is one of the names it matches.
+	host string
+	// targets are the addresses the record must resolve to, if known.
+	targets []string
+}
+
+// ValidateDNSRecords checks the DNS records of the API and of the routes of
+// the cluster.
+//
+// On the platforms whose DNS the installer manages, a record which already
+// resolves is most likely left from a previous cluster with the same name
+// and would direct the clients to it, which results in a warning: the
+// record may as well be the one the installer created for this cluster, in
+// an install which is resumed. On the on-prem platforms, the records which
+// resolve must point to the VIPs of the platform. The records which cannot
+// be resolved from the installer host yet, where the user creates them, only
+// result in warnings.
+func ValidateDNSRecords(ctx context.Context, ic *types.InstallConfig) field.ErrorList {
+	platform := ic.Platform.Name()
+	if ic.Platform.IsLoadBalancerUserManaged() {
+		// the records of a user-managed load balancer are checked with the
+		// install-config
+		return nil
+	}
+
+	apiVIPs, ingressVIPs := platformVIPs(&ic.Platform)
+	records := []dnsRecord{
+		{name: "api", host: ic.APIDomainName(), targets: apiVIPs},
+		{name: "*.apps", host: "test." + ic.AppsDomainName(), targets: ingressVIPs},
+	}
+
+	allErrs := field.ErrorList{}
+	for _, record := range records {
+		addrs, err := lookupHost(ctx, record.host)
+		notFound := isNotFound(err)
+		if err != nil && !notFound {
+			logrus.Warnf("Unable to resolve the %s record %s from the installer host: %v", record.name, record.host, err)
+			continue
+		}
+
+		switch {
+		case managedDNSPlatforms.Has(platform):
+			if record.name == "*.apps" && !strings.HasSuffix(ic.AppsDomainName(), "."+ic.ClusterDomain()) {
+				// the records of routes outside of the cluster domain are
+				// not managed by the installer
+				continue
+			}
+			if !notFound {
+				logrus.Warnf("The %s record %s already resolves to %v; unless the installer created it for this cluster, it may be left from a previous cluster with the same name and must be removed", record.name, record.host, addrs)
+			}
+		case len(record.targets) > 0:
+			if notFound {
+				logrus.Warnf("The %s record %s does not resolve from the installer host; the clients outside of the cluster need it to point to %v", record.name, record.host, record.targets)
+				continue
+			}
+			if !sets.NewString(addrs...).HasAny(record.targets...) {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("metadata", "name"), ic.ObjectMeta.Name, fmt.Sprintf("the %s record %s resolves to %v, none of the VIPs %v; it may be left from a previous cluster with the same name", record.name, record.host, addrs, record.targets)))
+			}
+		case platform == none.Name:
+			if notFound {
+				logrus.Warnf("The %s record %s does not resolve from the installer host; it must be created before the install", record.name, record.host)
+			}
+		}
+	}
+	return allErrs
+}
+
+// isNotFound returns whether the name does not exist.
+func isNotFound(err error) bool {
+	dnsErr, ok := err.(*net.DNSError)
+	return ok && dnsErr.IsNotFound
+}
+
+// platformVIPs returns the API and ingress VIPs of the on-prem platform.
+func platformVIPs(p *types.Platform) (api, ingress []string) {
+	switch {
+	case p.BareMetal != nil:
+		return p.BareMetal.APIVIPs, p.BareMetal.IngressVIPs
+	case p.OpenStack != nil:
+		return p.OpenStack.APIVIPs, p.OpenStack.IngressVIPs
+	case p.VSphere != nil:
+		return p.VSphere.APIVIPs, p.VSphere.IngressVIPs
+	case p.Nutanix != nil:
+		return p.Nutanix.APIVIPs, p.Nutanix.IngressVIPs
+	}
+	return nil, nil
+}
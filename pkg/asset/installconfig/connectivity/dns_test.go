@@ -0,0 +1,130 @@
+package connectivity
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/baremetal"
+	"github.com/openshift/installer/pkg/types/loadbalancer"
+	"github.com/openshift/installer/pkg/types/none"
+)
+
+func TestValidateDNSRecords(t *testing.T) {
+	defaultLookupHost := lookupHost
+	t.Cleanup(func() { lookupHost = defaultLookupHost })
+
+	cases := []struct {
+		name            string
+		platform        types.Platform
+		appsDomain      string
+		records         map[string][]string
+		expectedError   string
+		expectedWarning string
+	}{
+		{
+			name:     "managed dns without records",
+			platform: types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+		},
+		{
+			name:     "managed dns with stale api record",
+			platform: types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			records: map[string][]string{
+				"api.test-cluster.example.com": {"192.0.2.1"},
+			},
+			expectedWarning: `^The api record api\.test-cluster\.example\.com already resolves to \[192\.0\.2\.1\]; unless the installer created it for this cluster, it may be left from a previous cluster with the same name and must be removed$`,
+		},
+		{
+			name:     "managed dns with stale apps record",
+			platform: types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			records: map[string][]string{
+				"test.apps.test-cluster.example.com": {"192.0.2.2"},
+			},
+			expectedWarning: `^The \*\.apps record test\.apps\.test-cluster\.example\.com already resolves to \[192\.0\.2\.2\]`,
+		},
+		{
+			name:       "managed dns with apps domain outside of the cluster domain",
+			platform:   types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			appsDomain: "apps.example.com",
+			records: map[string][]string{
+				"test.apps.example.com": {"192.0.2.2"},
+			},
+		},
+		{
+			name:     "vips without records",
+			platform: types.Platform{BareMetal: &baremetal.Platform{APIVIPs: []string{"192.168.111.5"}, IngressVIPs: []string{"192.168.111.4"}}},
+		},
+		{
+			name:     "records pointing to the vips",
+			platform: types.Platform{BareMetal: &baremetal.Platform{APIVIPs: []string{"192.168.111.5"}, IngressVIPs: []string{"192.168.111.4"}}},
+			records: map[string][]string{
+				"api.test-cluster.example.com":       {"192.168.111.5"},
+				"test.apps.test-cluster.example.com": {"192.168.111.4"},
+			},
+		},
+		{
+			name:     "record not pointing to the vips",
+			platform: types.Platform{BareMetal: &baremetal.Platform{APIVIPs: []string{"192.168.111.5"}, IngressVIPs: []string{"192.168.111.4"}}},
+			records: map[string][]string{
+				"api.test-cluster.example.com":       {"192.168.111.5"},
+				"test.apps.test-cluster.example.com": {"192.168.100.4"},
+			},
+			expectedError: `^metadata\.name: Invalid value: "test-cluster": the \*\.apps record test\.apps\.test-cluster\.example\.com resolves to \[192\.168\.100\.4\], none of the VIPs \[192\.168\.111\.4\]; it may be left from a previous cluster with the same name$`,
+		},
+		{
+			name: "user-managed load balancer",
+			platform: types.Platform{BareMetal: &baremetal.Platform{
+				APIVIPs:      []string{"192.168.111.5"},
+				IngressVIPs:  []string{"192.168.111.4"},
+				LoadBalancer: &loadbalancer.LoadBalancer{Type: loadbalancer.UserManaged},
+			}},
+			records: map[string][]string{
+				"api.test-cluster.example.com": {"192.168.100.5"},
+			},
+		},
+		{
+			name:     "none platform without records",
+			platform: types.Platform{None: &none.Platform{}},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			lookupHost = func(_ context.Context, host string) ([]string, error) {
+				if addrs, ok := tc.records[host]; ok {
+					return addrs, nil
+				}
+				return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+			}
+			ic := &types.InstallConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				BaseDomain: "example.com",
+				AppsDomain: tc.appsDomain,
+				Platform:   tc.platform,
+			}
+			hook := logrustest.NewGlobal()
+			defer hook.Reset()
+			err := ValidateDNSRecords(context.Background(), ic).ToAggregate()
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+			warnings := []string{}
+			for _, entry := range hook.AllEntries() {
+				if entry.Level == logrus.WarnLevel {
+					warnings = append(warnings, entry.Message)
+				}
+			}
+			if tc.expectedWarning != "" && assert.Len(t, warnings, 1) {
+				assert.Regexp(t, tc.expectedWarning, warnings[0])
+			}
+		})
+	}
+}
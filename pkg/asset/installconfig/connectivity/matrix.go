@@ -0,0 +1,379 @@
+package connectivity
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/azure"
+	"github.com/openshift/installer/pkg/types/gcp"
+	"github.com/openshift/installer/pkg/types/ibmcloud"
+	"github.com/openshift/installer/pkg/types/nutanix"
+	"github.com/openshift/installer/pkg/types/powervs"
+	"github.com/openshift/installer/pkg/types/vsphere"
+)
+
+// The kinds of the endpoints of the connectivity matrix.
+const (
+	KindReleaseRegistry = "release registry"
+	KindMirror          = "mirror"
+	KindCloudAPI        = "cloud API"
+	KindTangServer      = "Tang server"
+	KindNTPServer       = "NTP server"
+	KindProxy           = "proxy"
+)
+
+// Direct is the Via of the endpoints reached without a proxy.
+const Direct = "direct"
+
+// Endpoint is a service the cluster depends on.
+type Endpoint struct {
+	Kind string
+	// URL is the https or http URL of the endpoint, or an ntp URL for the
+	// NTP servers.
+	URL *url.URL
+}
+
+// Address returns the address of the endpoint, without the credentials of
+// its URL.
+func (e Endpoint) Address() string {
+	if e.URL.Scheme == "ntp" {
+		return e.URL.Host
+	}
+	return e.URL.Redacted()
+}
+
+// Result is the reachability of an endpoint from the installer host.
+type Result struct {
+	Endpoint
+	// Via is the proxy of the install-config the endpoint was reached
+	// through, or Direct.
+	Via string
+	// Err is why the endpoint could not be reached, if it could not.
+	Err error
+}
+
+// CheckMatrix checks concurrently that each endpoint the cluster depends on
+// can be reached from the installer host: the registries of the release
+// image and of its mirrors, the APIs of the platform, the Tang and NTP
+// servers of the machine pools and the proxies of the install-config. The
+// HTTPS endpoints must complete a TLS handshake trusted by the system roots
+// and the additional trust bundle, through the proxy of the install-config
+// unless noProxy excludes them. The results are in the order of
+// MatrixEndpoints.
+func CheckMatrix(ctx context.Context, ic *types.InstallConfig, releaseImage string) ([]Result, error) {
+	endpoints, err := MatrixEndpoints(ic, releaseImage)
+	if err != nil {
+		return nil, err
+	}
+	roots, err := trustedRoots(ic.AdditionalTrustBundle)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid additionalTrustBundle")
+	}
+	proxyConfig := &httpproxy.Config{}
+	if ic.Proxy != nil {
+		proxyConfig = &httpproxy.Config{HTTPProxy: ic.Proxy.HTTPProxy, HTTPSProxy: ic.Proxy.HTTPSProxy, NoProxy: ic.Proxy.NoProxy}
+	}
+	proxyFunc := proxyConfig.ProxyFunc()
+
+	results := make([]Result, len(endpoints))
+	var wg sync.WaitGroup
+	for i, endpoint := range endpoints {
+		wg.Add(1)
+		go func(i int, endpoint Endpoint) {
+			defer wg.Done()
+			results[i] = checkEndpoint(ctx, endpoint, proxyFunc, roots)
+		}(i, endpoint)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// checkEndpoint reaches the endpoint, through the proxy proxyFunc returns
+// for it, if any.
+func checkEndpoint(ctx context.Context, endpoint Endpoint, proxyFunc func(*url.URL) (*url.URL, error), roots *x509.CertPool) Result {
+	result := Result{Endpoint: endpoint, Via: Direct}
+	u := endpoint.URL
+	target := hostPort(u)
+
+	switch {
+	case endpoint.Kind == KindProxy:
+		ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+		defer cancel()
+		conn, err := dialProxy(ctx, u, roots)
+		if err == nil {
+			conn.Close()
+		}
+		result.Err = err
+		return result
+	case u.Scheme == "ntp":
+		result.Err = queryNTP(ctx, target)
+		return result
+	}
+
+	proxyURL, err := proxyFunc(u)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if proxyURL != nil {
+		result.Via = proxyURL.Redacted()
+		if u.Scheme == "https" {
+			result.Err = connectThroughProxy(ctx, proxyURL, target, roots)
+		} else {
+			result.Err = forwardThroughProxy(ctx, proxyURL, target, roots)
+		}
+		return result
+	}
+	if u.Scheme == "https" {
+		result.Err = dialTLS(ctx, target, roots)
+	} else {
+		result.Err = dialTCP(ctx, target)
+	}
+	return result
+}
+
+// MatrixEndpoints returns the endpoints CheckMatrix checks, without
+// duplicates.
+func MatrixEndpoints(ic *types.InstallConfig, releaseImage string) ([]Endpoint, error) {
+	var endpoints []Endpoint
+	seen := map[string]bool{}
+	add := func(kind string, rawURL string) error {
+		u, err := url.Parse(rawURL)
+		if err != nil || u.Host == "" {
+			return errors.Errorf("invalid URL of the %s %q", kind, rawURL)
+		}
+		key := kind + " " + u.String()
+		if seen[key] {
+			return nil
+		}
+		seen[key] = true
+		endpoints = append(endpoints, Endpoint{Kind: kind, URL: u})
+		return nil
+	}
+
+	registry, err := registryAddress(releaseImage)
+	if err != nil {
+		return nil, err
+	}
+	if err := add(KindReleaseRegistry, "https://"+registry); err != nil {
+		return nil, err
+	}
+	for _, source := range ic.MirrorSources() {
+		for _, mirror := range source.Mirrors {
+			registry, err := registryAddress(mirror)
+			if err != nil {
+				return nil, err
+			}
+			if err := add(KindMirror, "https://"+registry); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, api := range cloudAPIs(&ic.Platform) {
+		if err := add(KindCloudAPI, api); err != nil {
+			return nil, err
+		}
+	}
+
+	pools := []*types.MachinePool{ic.ControlPlane}
+	for i := range ic.Compute {
+		pools = append(pools, &ic.Compute[i])
+	}
+	for _, pool := range pools {
+		if pool != nil && pool.DiskEncryption != nil {
+			for _, tang := range pool.DiskEncryption.Tang {
+				if err := add(KindTangServer, tang.URL); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	for _, pool := range pools {
+		if ntp := ic.NTPFor(pool); ntp != nil {
+			for _, server := range append(append([]string{}, ntp.Servers...), ntp.Pools...) {
+				if err := add(KindNTPServer, "ntp://"+server); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if ic.Proxy != nil {
+		for _, proxy := range []string{ic.Proxy.HTTPProxy, ic.Proxy.HTTPSProxy} {
+			if proxy == "" {
+				continue
+			}
+			if err := add(KindProxy, proxy); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return endpoints, nil
+}
+
+// cloudAPIs returns the URLs of the APIs of the platform the installer and
+// the cluster call.
+func cloudAPIs(p *types.Platform) []string {
+	switch p.Name() {
+	case aws.Name:
+		return awsAPIs(p.AWS)
+	case azure.Name:
+		switch p.Azure.CloudName {
+		case azure.USGovernmentCloud:
+			return []string{"https://management.usgovcloudapi.net", "https://login.microsoftonline.us"}
+		case azure.ChinaCloud:
+			return []string{"https://management.chinacloudapi.cn", "https://login.chinacloudapi.cn"}
+		case azure.GermanCloud:
+			return []string{"https://management.microsoftazure.de", "https://login.microsoftonline.de"}
+		case azure.StackCloud:
+			return []string{p.Azure.ARMEndpoint}
+		default:
+			return []string{"https://management.azure.com", "https://login.microsoftonline.com"}
+		}
+	case gcp.Name:
+		var apis []string
+		for _, service := range []string{"cloudresourcemanager", "compute", "dns", "iam", "oauth2", "storage"} {
+			apis = append(apis, fmt.Sprintf("https://%s.googleapis.com", service))
+		}
+		return apis
+	case ibmcloud.Name:
+		return []string{
+			"https://iam.cloud.ibm.com",
+			"https://resource-controller.cloud.ibm.com",
+			"https://api.cis.cloud.ibm.com",
+			fmt.Sprintf("https://%s.iaas.cloud.ibm.com", p.IBMCloud.Region),
+		}
+	case powervs.Name:
+		return []string{
+			"https://iam.cloud.ibm.com",
+			"https://resource-controller.cloud.ibm.com",
+			"https://api.cis.cloud.ibm.com",
+			fmt.Sprintf("https://%s.power-iaas.cloud.ibm.com", p.PowerVS.Region),
+		}
+	case nutanix.Name:
+		endpoint := p.Nutanix.PrismCentral.Endpoint
+		return []string{fmt.Sprintf("https://%s", net.JoinHostPort(endpoint.Address, strconv.Itoa(int(endpoint.Port))))}
+	case vsphere.Name:
+		var apis []string
+		for _, vcenter := range p.VSphere.VCenters {
+			apis = append(apis, "https://"+vcenter.Server)
+		}
+		for _, fd := range p.VSphere.FailureDomains {
+			apis = append(apis, "https://"+fd.Server)
+		}
+		return apis
+	default:
+		return nil
+	}
+}
+
+// awsAPIs returns the endpoints of the AWS services the installer calls, the
+// ones of serviceEndpoints replacing the defaults of the region.
+func awsAPIs(p *aws.Platform) []string {
+	suffix := "amazonaws.com"
+	iam, route53 := "https://iam.amazonaws.com", "https://route53.amazonaws.com"
+	switch {
+	case strings.HasPrefix(p.Region, "cn-"):
+		suffix = "amazonaws.com.cn"
+		iam, route53 = "https://iam.cn-north-1.amazonaws.com.cn", "https://route53.amazonaws.com.cn"
+	case strings.HasPrefix(p.Region, "us-gov-"):
+		iam, route53 = "https://iam.us-gov.amazonaws.com", "https://route53.us-gov.amazonaws.com"
+	}
+	services := map[string]string{
+		"iam":     iam,
+		"route53": route53,
+	}
+	for _, service := range []string{"ec2", "elasticloadbalancing", "s3", "sts", "tagging"} {
+		services[service] = fmt.Sprintf("https://%s.%s.%s", service, p.Region, suffix)
+	}
+	for _, endpoint := range p.ServiceEndpoints {
+		services[endpoint.Name] = endpoint.URL
+	}
+
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	apis := make([]string, 0, len(names))
+	for _, name := range names {
+		apis = append(apis, services[name])
+	}
+	return apis
+}
+
+// hostPort returns the host:port of the URL, with the default port of its
+// scheme.
+func hostPort(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	port := "443"
+	switch u.Scheme {
+	case "http":
+		port = "80"
+	case "ntp":
+		port = "123"
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// dialTCP opens a TCP connection to target.
+func dialTCP(ctx context.Context, target string) error {
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", target)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// queryNTP sends an SNTP client request to the NTP server at target and
+// waits for its response.
+func queryNTP(ctx context.Context, target string) error {
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "udp", target)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	request := make([]byte, 48)
+	// version 4, mode 3 (client)
+	request[0] = 4<<3 | 3
+	if _, err := conn.Write(request); err != nil {
+		return err
+	}
+	response := make([]byte, 48)
+	n, err := conn.Read(response)
+	if err != nil {
+		return errors.Wrap(err, "no response from the NTP server")
+	}
+	switch {
+	case n < 48 || response[0]&0x7 != 4:
+		return errors.New("invalid response from the NTP server")
+	case response[1] == 0:
+		return errors.Errorf("the NTP server refused the request: %s", strings.TrimRight(string(response[12:16]), "\x00"))
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package connectivity
+
+import (
+	"context"
+	"strings"
+
+	dockerref "github.com/containers/image/docker/reference"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// ValidateMirrors checks that the release image can be pulled by digest,
+// with the pull secret, from at least one of the mirrors configured for its
+// repository in imageDigestSources or imageContentSources. Every mirror which
+// cannot serve the release image is reported, as an error when no mirror can
+// serve it and as a warning otherwise.
+func ValidateMirrors(ctx context.Context, ic *types.InstallConfig, releaseImage string) field.ErrorList {
+	allErrs := field.ErrorList{}
+	sources := ic.MirrorSources()
+	if len(sources) == 0 {
+		return allErrs
+	}
+	fldPath := mirrorSourcesPath(ic)
+
+	ref, err := dockerref.ParseNamed(releaseImage)
+	if err != nil {
+		return append(allErrs, field.InternalError(fldPath, err))
+	}
+	digested, ok := ref.(dockerref.Digested)
+	if !ok {
+		logrus.Warnf("The release image %s is not referenced by digest, so %s will not be used to pull it", releaseImage, fldPath)
+		return allErrs
+	}
+	digest := digested.Digest().String()
+
+	client, err := newRegistryClient(ic.PullSecret, ic.AdditionalTrustBundle, ic.Proxy)
+	if err != nil {
+		return append(allErrs, field.InternalError(fldPath, err))
+	}
+
+	var failures field.ErrorList
+	checked := false
+	for i, source := range sources {
+		suffix, ok := repositorySuffix(ref.Name(), source.Source)
+		if !ok {
+			continue
+		}
+		for j, mirror := range source.Mirrors {
+			checked = true
+			repository := mirror + suffix
+			if err := client.manifestExists(ctx, repository, digest); err != nil {
+				failures = append(failures, field.Invalid(fldPath.Index(i).Child("mirrors").Index(j), mirror, err.Error()))
+				continue
+			}
+			logrus.Debugf("The release image is available from the mirror %s", repository)
+			return warnMirrorFailures(failures)
+		}
+	}
+	if !checked {
+		logrus.Warnf("None of the %s apply to the release image repository %s", fldPath, ref.Name())
+	}
+	return failures
+}
+
+// mirrorSourcesPath returns the path of the install-config field which the
+// mirror sources are given in.
+func mirrorSourcesPath(ic *types.InstallConfig) *field.Path {
+	if len(ic.ImageDigestSources) > 0 {
+		return field.NewPath("imageDigestSources")
+	}
+	return field.NewPath("imageContentSources")
+}
+
+// warnMirrorFailures logs the mirrors which could not serve the release image
+// when another mirror could.
+func warnMirrorFailures(failures field.ErrorList) field.ErrorList {
+	for _, failure := range failures {
+		logrus.Warnf("The release image could not be pulled from a mirror: %v", failure)
+	}
+	return field.ErrorList{}
+}
+
+// repositorySuffix returns the part of the repository which follows source,
+// when source is the repository itself or one of its parents.
+func repositorySuffix(repository string, source string) (string, bool) {
+	if repository == source {
+		return "", true
+	}
+	if strings.HasPrefix(repository, source+"/") {
+		return strings.TrimPrefix(repository, source), true
+	}
+	return "", false
+}
@@ -0,0 +1,154 @@
+package connectivity
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+const (
+	testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	testAuth   = "user:password"
+)
+
+// newTestRegistry returns a registry which serves the manifest of testDigest
+// in the repositories given, to clients holding a token obtained with
+// testAuth.
+func newTestRegistry(repositories ...string) *httptest.Server {
+	mux := http.NewServeMux()
+	var registry *httptest.Server
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Basic "+base64.StdEncoding.EncodeToString([]byte(testAuth)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"token": "test-token"})
+	})
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, registry.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		for _, repository := range repositories {
+			if r.URL.Path == fmt.Sprintf("/v2/%s/manifests/%s", repository, testDigest) {
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	registry = httptest.NewTLSServer(mux)
+	return registry
+}
+
+func TestValidateMirrors(t *testing.T) {
+	registry := newTestRegistry("mirror/release")
+	defer registry.Close()
+	host := strings.TrimPrefix(registry.URL, "https://")
+	trustBundle := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: registry.Certificate().Raw}))
+	pullSecret := fmt.Sprintf(`{"auths":{%q:{"auth":%q}}}`, host, base64.StdEncoding.EncodeToString([]byte(testAuth)))
+
+	cases := []struct {
+		name          string
+		releaseImage  string
+		sources       []types.ImageContentSource
+		digestSources []types.ImageDigestSource
+		pullSecret    string
+		expectedError string
+	}{
+		{
+			name:         "no image content sources",
+			releaseImage: "quay.io/openshift-release-dev/ocp-release@" + testDigest,
+		},
+		{
+			name:         "release image available from mirror",
+			releaseImage: "quay.io/openshift-release-dev/ocp-release@" + testDigest,
+			sources: []types.ImageContentSource{{
+				Source:  "quay.io/openshift-release-dev/ocp-release",
+				Mirrors: []string{host + "/missing/release", host + "/mirror/release"},
+			}},
+		},
+		{
+			name:         "mirror of parent repository",
+			releaseImage: "quay.io/openshift-release-dev/ocp-release@" + testDigest,
+			sources: []types.ImageContentSource{{
+				Source:  "quay.io/openshift-release-dev",
+				Mirrors: []string{host + "/mirror"},
+			}},
+			expectedError: `^imageContentSources\[0\]\.mirrors\[0\]: Invalid value: "127.0.0.1:\d+/mirror": 127.0.0.1:\d+/mirror/ocp-release@sha256:[0-9a-f]+ was not found$`,
+		},
+		{
+			name:         "release image missing from every mirror",
+			releaseImage: "quay.io/openshift-release-dev/ocp-release@" + testDigest,
+			sources: []types.ImageContentSource{{
+				Source:  "quay.io/openshift-release-dev/ocp-release",
+				Mirrors: []string{host + "/missing/release"},
+			}},
+			expectedError: `^imageContentSources\[0\]\.mirrors\[0\]: Invalid value: "127.0.0.1:\d+/missing/release": 127.0.0.1:\d+/missing/release@sha256:[0-9a-f]+ was not found$`,
+		},
+		{
+			name:         "pull secret rejected by mirror",
+			releaseImage: "quay.io/openshift-release-dev/ocp-release@" + testDigest,
+			sources: []types.ImageContentSource{{
+				Source:  "quay.io/openshift-release-dev/ocp-release",
+				Mirrors: []string{host + "/mirror/release"},
+			}},
+			pullSecret:    fmt.Sprintf(`{"auths":{%q:{"auth":%q}}}`, host, base64.StdEncoding.EncodeToString([]byte("user:wrong"))),
+			expectedError: `^imageContentSources\[0\]\.mirrors\[0\]: Invalid value: "127.0.0.1:\d+/mirror/release": the pull secret was rejected by 127.0.0.1:\d+: 401 Unauthorized$`,
+		},
+		{
+			name:         "release image available from digest source mirror",
+			releaseImage: "quay.io/openshift-release-dev/ocp-release@" + testDigest,
+			digestSources: []types.ImageDigestSource{{
+				Source:  "quay.io/openshift-release-dev/ocp-release",
+				Mirrors: []string{host + "/mirror/release"},
+			}},
+		},
+		{
+			name:         "release image missing from every digest source mirror",
+			releaseImage: "quay.io/openshift-release-dev/ocp-release@" + testDigest,
+			digestSources: []types.ImageDigestSource{{
+				Source:  "quay.io/openshift-release-dev/ocp-release",
+				Mirrors: []string{host + "/missing/release"},
+			}},
+			expectedError: `^imageDigestSources\[0\]\.mirrors\[0\]: Invalid value: "127.0.0.1:\d+/missing/release": 127.0.0.1:\d+/missing/release@sha256:[0-9a-f]+ was not found$`,
+		},
+		{
+			name:         "release image referenced by tag",
+			releaseImage: "quay.io/openshift-release-dev/ocp-release:4.12",
+			sources: []types.ImageContentSource{{
+				Source:  "quay.io/openshift-release-dev/ocp-release",
+				Mirrors: []string{host + "/missing/release"},
+			}},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ic := &types.InstallConfig{
+				PullSecret:            pullSecret,
+				AdditionalTrustBundle: trustBundle,
+				ImageContentSources:   tc.sources,
+				ImageDigestSources:    tc.digestSources,
+			}
+			if tc.pullSecret != "" {
+				ic.PullSecret = tc.pullSecret
+			}
+			err := ValidateMirrors(context.Background(), ic, tc.releaseImage).ToAggregate()
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
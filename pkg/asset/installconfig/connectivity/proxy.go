@@ -0,0 +1,294 @@
+// Package connectivity contains online checks that the services the cluster
+// depends on, such as the release image registry, can be reached with the
+// network configuration given in the install-config.
+package connectivity
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	dockerref "github.com/containers/image/docker/reference"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/http/httpproxy"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// dialTimeout bounds each connection attempt made by the checks.
+const dialTimeout = 15 * time.Second
+
+// ValidateProxy checks that the registry hosting the release image can be
+// reached with the proxy configured in the install-config. The HTTPS proxy
+// must accept a CONNECT to the registry and the TLS certificates presented by
+// the proxy and, through the tunnel, by the registry must be trusted by the
+// system roots combined with the additional trust bundle. The HTTP proxy must
+// accept requests forwarded through it. A registry excluded from the proxy by
+// noProxy must be reachable directly.
+//
+// A proxy which cannot be dialed from the installer host only results in a
+// warning, since the proxy may only be reachable from the cluster network.
+func ValidateProxy(ctx context.Context, proxy *types.Proxy, trustBundle string, releaseImage string) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if proxy == nil || (proxy.HTTPProxy == "" && proxy.HTTPSProxy == "") {
+		return allErrs
+	}
+	fldPath := field.NewPath("proxy")
+
+	registry, err := registryAddress(releaseImage)
+	if err != nil {
+		return append(allErrs, field.InternalError(fldPath, err))
+	}
+	host, _, err := net.SplitHostPort(registry)
+	if err != nil {
+		return append(allErrs, field.InternalError(fldPath, err))
+	}
+
+	roots, err := trustedRoots(trustBundle)
+	if err != nil {
+		return append(allErrs, field.Invalid(field.NewPath("additionalTrustBundle"), trustBundle, err.Error()))
+	}
+
+	proxyFunc := (&httpproxy.Config{
+		HTTPProxy:  proxy.HTTPProxy,
+		HTTPSProxy: proxy.HTTPSProxy,
+		NoProxy:    proxy.NoProxy,
+	}).ProxyFunc()
+
+	excluded := false
+	checks := []struct {
+		fldPath *field.Path
+		value   string
+		target  *url.URL
+		check   func(context.Context, *url.URL, string, *x509.CertPool) error
+	}{
+		{
+			fldPath: fldPath.Child("httpsProxy"),
+			value:   proxy.HTTPSProxy,
+			target:  &url.URL{Scheme: "https", Host: registry},
+			check:   connectThroughProxy,
+		},
+		{
+			fldPath: fldPath.Child("httpProxy"),
+			value:   proxy.HTTPProxy,
+			target:  &url.URL{Scheme: "http", Host: host},
+			check:   forwardThroughProxy,
+		},
+	}
+	for _, c := range checks {
+		if c.value == "" {
+			continue
+		}
+		proxyURL, err := proxyFunc(c.target)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(c.fldPath, c.value, err.Error()))
+			continue
+		}
+		if proxyURL == nil {
+			excluded = true
+			continue
+		}
+		if err := c.check(ctx, proxyURL, c.target.Host, roots); err != nil {
+			var unreachable *unreachableError
+			if errors.As(err, &unreachable) {
+				logrus.Warnf("Unable to check the proxy from the installer host: %v", err)
+				continue
+			}
+			allErrs = append(allErrs, field.Invalid(c.fldPath, proxyURL.Redacted(), err.Error()))
+		}
+	}
+
+	if excluded {
+		logrus.Debugf("The release image registry %s is excluded from the proxy by noProxy", registry)
+		if err := dialDirect(ctx, registry, roots); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("noProxy"), proxy.NoProxy, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// unreachableError is returned when the proxy itself cannot be dialed.
+type unreachableError struct {
+	err error
+}
+
+func (e *unreachableError) Error() string {
+	return e.err.Error()
+}
+
+// dialProxy opens a connection to the proxy, over TLS when the proxy is
+// served over HTTPS.
+func dialProxy(ctx context.Context, proxyURL *url.URL, roots *x509.CertPool) (net.Conn, error) {
+	proxyAddr := proxyURL.Host
+	if proxyURL.Port() == "" {
+		port := "80"
+		if proxyURL.Scheme == "https" {
+			port = "443"
+		}
+		proxyAddr = net.JoinHostPort(proxyURL.Hostname(), port)
+	}
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, &unreachableError{err: err}
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if proxyURL.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{RootCAs: roots, ServerName: proxyURL.Hostname(), MinVersion: tls.VersionTLS12})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, errors.Wrap(err, "the certificate of the proxy is not trusted, add the CA which signed it to additionalTrustBundle")
+		}
+		conn = tlsConn
+	}
+	return conn, nil
+}
+
+// setProxyAuthorization sets the credentials of the proxy URL, if any, on
+// the request.
+func setProxyAuthorization(req *http.Request, proxyURL *url.URL) {
+	if user := proxyURL.User; user != nil {
+		password, _ := user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+}
+
+// connectThroughProxy opens a tunnel to target through the proxy and
+// completes a TLS handshake with target over it.
+func connectThroughProxy(ctx context.Context, proxyURL *url.URL, target string, roots *x509.CertPool) error {
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	conn, err := dialProxy(ctx, proxyURL, roots)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: target},
+		Host:   target,
+		Header: http.Header{},
+	}
+	setProxyAuthorization(req, proxyURL)
+	if err := req.Write(conn); err != nil {
+		return errors.Wrap(err, "failed to send CONNECT request to the proxy")
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the response of the proxy to CONNECT")
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("the proxy refused to CONNECT to %s: %s", target, resp.Status)
+	}
+
+	host, _, err := net.SplitHostPort(target)
+	if err != nil {
+		return err
+	}
+	tlsConn := tls.Client(conn, &tls.Config{RootCAs: roots, ServerName: host, MinVersion: tls.VersionTLS12})
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return errors.Wrapf(err, "the certificate presented for %s through the proxy is not trusted, if the proxy intercepts TLS add its CA to additionalTrustBundle", host)
+	}
+	return nil
+}
+
+// forwardThroughProxy sends a plain HTTP request for target through the
+// proxy. Only the responses of the proxy itself are checked, since the
+// registry need not serve plain HTTP.
+func forwardThroughProxy(ctx context.Context, proxyURL *url.URL, target string, roots *x509.CertPool) error {
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	conn, err := dialProxy(ctx, proxyURL, roots)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	req := &http.Request{
+		Method: http.MethodHead,
+		URL:    &url.URL{Scheme: "http", Host: target, Path: "/"},
+		Host:   target,
+		Header: http.Header{},
+	}
+	setProxyAuthorization(req, proxyURL)
+	if err := req.WriteProxy(conn); err != nil {
+		return errors.Wrap(err, "failed to send request to the proxy")
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the response of the proxy")
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusProxyAuthRequired {
+		return errors.Errorf("the proxy requires authentication: %s", resp.Status)
+	}
+	return nil
+}
+
+// dialDirect completes a TLS handshake with target without going through a
+// proxy.
+func dialDirect(ctx context.Context, target string, roots *x509.CertPool) error {
+	if err := dialTLS(ctx, target, roots); err != nil {
+		return errors.Wrapf(err, "failed to reach %s directly, which noProxy excludes from the proxy", target)
+	}
+	return nil
+}
+
+// dialTLS completes a TLS handshake with target.
+func dialTLS(ctx context.Context, target string, roots *x509.CertPool) error {
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	host, _, err := net.SplitHostPort(target)
+	if err != nil {
+		return err
+	}
+	dialer := &tls.Dialer{Config: &tls.Config{RootCAs: roots, ServerName: host, MinVersion: tls.VersionTLS12}}
+	conn, err := dialer.DialContext(ctx, "tcp", target)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// registryAddress returns the host:port of the registry serving the image.
+func registryAddress(image string) (string, error) {
+	ref, err := dockerref.ParseNamed(image)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse image %q", image)
+	}
+	registry := dockerref.Domain(ref)
+	if _, _, err := net.SplitHostPort(registry); err != nil {
+		registry = net.JoinHostPort(registry, "443")
+	}
+	return registry, nil
+}
+
+// trustedRoots returns the system roots extended by the trust bundle.
+func trustedRoots(trustBundle string) (*x509.CertPool, error) {
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+	if trustBundle != "" && !roots.AppendCertsFromPEM([]byte(trustBundle)) {
+		return nil, errors.New("no certificates found in the trust bundle")
+	}
+	return roots, nil
+}
@@ -0,0 +1,123 @@
+package connectivity
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+const testReleaseImage = "registry.example.com/ocp/release:4.12"
+
+// newConnectProxy returns a proxy which tunnels every CONNECT request to
+// target, regardless of the requested host, or refuses them with status.
+func newConnectProxy(t *testing.T, target string, status int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect || status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		upstream, err := net.Dial("tcp", target)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		go func() {
+			defer upstream.Close()
+			defer conn.Close()
+			go io.Copy(upstream, conn)
+			io.Copy(conn, upstream)
+		}()
+	}))
+}
+
+func TestValidateProxy(t *testing.T) {
+	registry := httptest.NewTLSServer(http.NotFoundHandler())
+	defer registry.Close()
+	registryCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: registry.Certificate().Raw}))
+
+	tunnel := newConnectProxy(t, registry.Listener.Addr().String(), http.StatusOK)
+	defer tunnel.Close()
+	forbidden := newConnectProxy(t, registry.Listener.Addr().String(), http.StatusForbidden)
+	defer forbidden.Close()
+	authRequired := newConnectProxy(t, registry.Listener.Addr().String(), http.StatusProxyAuthRequired)
+	defer authRequired.Close()
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+
+	cases := []struct {
+		name          string
+		proxy         *types.Proxy
+		trustBundle   string
+		releaseImage  string
+		expectedError string
+	}{
+		{
+			name: "no proxy",
+		},
+		{
+			name:        "registry reachable through proxy",
+			proxy:       &types.Proxy{HTTPProxy: tunnel.URL, HTTPSProxy: tunnel.URL},
+			trustBundle: registryCA,
+		},
+		{
+			name:          "intercepted certificate not in trust bundle",
+			proxy:         &types.Proxy{HTTPSProxy: tunnel.URL},
+			expectedError: `^proxy.httpsProxy: Invalid value: "http://127.0.0.1:\d+": the certificate presented for registry.example.com through the proxy is not trusted`,
+		},
+		{
+			name:          "proxy refuses CONNECT",
+			proxy:         &types.Proxy{HTTPSProxy: forbidden.URL},
+			trustBundle:   registryCA,
+			expectedError: `^proxy.httpsProxy: Invalid value: "http://127.0.0.1:\d+": the proxy refused to CONNECT to registry.example.com:443: 403 Forbidden$`,
+		},
+		{
+			name:          "http proxy requires authentication",
+			proxy:         &types.Proxy{HTTPProxy: authRequired.URL},
+			expectedError: `^proxy.httpProxy: Invalid value: "http://127.0.0.1:\d+": the proxy requires authentication: 407 Proxy Authentication Required$`,
+		},
+		{
+			name:         "registry excluded by noProxy and reachable directly",
+			proxy:        &types.Proxy{HTTPProxy: forbidden.URL, HTTPSProxy: forbidden.URL, NoProxy: "127.0.0.1"},
+			trustBundle:  registryCA,
+			releaseImage: registry.Listener.Addr().String() + "/ocp/release:4.12",
+		},
+		{
+			name:          "registry excluded by noProxy and unreachable directly",
+			proxy:         &types.Proxy{HTTPSProxy: forbidden.URL, NoProxy: ".invalid"},
+			releaseImage:  "registry.invalid/ocp/release:4.12",
+			expectedError: `^proxy.noProxy: Invalid value: ".invalid": failed to reach registry.invalid:443 directly, which noProxy excludes from the proxy`,
+		},
+		{
+			name:  "proxy unreachable from installer host",
+			proxy: &types.Proxy{HTTPProxy: unreachable.URL, HTTPSProxy: unreachable.URL},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			releaseImage := tc.releaseImage
+			if releaseImage == "" {
+				releaseImage = testReleaseImage
+			}
+			err := ValidateProxy(context.Background(), tc.proxy, tc.trustBundle, releaseImage).ToAggregate()
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
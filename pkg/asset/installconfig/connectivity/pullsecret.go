@@ -0,0 +1,121 @@
+package connectivity
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	dockerref "github.com/containers/image/docker/reference"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// ValidatePullSecret checks that the pull secret is accepted by the registry
+// hosting the release image. When mirror sources are configured the release
+// image is pulled from the mirrors, whose credentials are checked by
+// ValidateMirrors, so the registry check is skipped.
+//
+// Only the registry refusing the credentials is an error. Failing to reach
+// the registry from the installer host, or the registry not serving the
+// release image, results in a warning.
+func ValidatePullSecret(ctx context.Context, ic *types.InstallConfig, releaseImage string) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(ic.MirrorSources()) > 0 {
+		return allErrs
+	}
+
+	fldPath := field.NewPath("pullSecret")
+	ref, err := dockerref.ParseNamed(releaseImage)
+	if err != nil {
+		return append(allErrs, field.InternalError(fldPath, err))
+	}
+	client, err := newRegistryClient(ic.PullSecret, ic.AdditionalTrustBundle, ic.Proxy)
+	if err != nil {
+		return append(allErrs, field.InternalError(fldPath, err))
+	}
+	if err := client.manifestExists(ctx, ref.Name(), imageReference(ref)); err != nil {
+		var rejected *authError
+		if errors.As(err, &rejected) {
+			return append(allErrs, field.Forbidden(fldPath, err.Error()))
+		}
+		logrus.Warnf("Unable to check the pull secret against the release image registry: %v", err)
+	}
+	return allErrs
+}
+
+// ValidatePullSecretTokens checks, without reaching the registries, that
+// the tokens in the pull secret have not expired.
+func ValidatePullSecretTokens(ic *types.InstallConfig) field.ErrorList {
+	return validatePullSecretTokens(ic.PullSecret, field.NewPath("pullSecret"))
+}
+
+// validatePullSecretTokens checks that the passwords of the pull secret
+// which are JSON Web Tokens, such as those issued for cloud.openshift.com,
+// have not expired. An auth which is not the base64 encoding of
+// user:password cannot be checked and only results in a warning.
+func validatePullSecretTokens(pullSecret string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	var secret struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal([]byte(pullSecret), &secret); err != nil {
+		// an invalid pull secret is reported by the install-config validation
+		return allErrs
+	}
+	registries := make([]string, 0, len(secret.Auths))
+	for registry := range secret.Auths {
+		registries = append(registries, registry)
+	}
+	sort.Strings(registries)
+
+	for _, registry := range registries {
+		auth := secret.Auths[registry].Auth
+		if auth == "" {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(auth)
+		if err != nil {
+			logrus.Warnf("Unable to check the pull secret auth for %s, it is not base64 encoded: %v", registry, err)
+			continue
+		}
+		_, password, ok := strings.Cut(string(decoded), ":")
+		if !ok {
+			logrus.Warnf("Unable to check the pull secret auth for %s, it is not the base64 encoding of user:password", registry)
+			continue
+		}
+		if expiry, ok := tokenExpiry(password); ok && expiry.Before(time.Now()) {
+			allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("the token for %s expired at %s, download a new pull secret", registry, expiry.UTC().Format(time.RFC3339))))
+		}
+	}
+	return allErrs
+}
+
+// tokenExpiry returns the expiry of the password when it is a JSON Web Token
+// with an exp claim.
+func tokenExpiry(password string) (time.Time, bool) {
+	parts := strings.Split(password, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(claims.Exp, 0), true
+}
@@ -0,0 +1,115 @@
+package connectivity
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestValidatePullSecret(t *testing.T) {
+	registry := newTestRegistry("ocp/release")
+	defer registry.Close()
+	host := strings.TrimPrefix(registry.URL, "https://")
+	trustBundle := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: registry.Certificate().Raw}))
+	pullSecret := func(auth string) string {
+		return fmt.Sprintf(`{"auths":{%q:{"auth":%q}}}`, host, base64.StdEncoding.EncodeToString([]byte(auth)))
+	}
+
+	cases := []struct {
+		name          string
+		releaseImage  string
+		pullSecret    string
+		sources       []types.ImageContentSource
+		expectedError string
+	}{
+		{
+			name:         "pull secret accepted",
+			releaseImage: host + "/ocp/release@" + testDigest,
+			pullSecret:   pullSecret(testAuth),
+		},
+		{
+			name:          "pull secret rejected",
+			releaseImage:  host + "/ocp/release@" + testDigest,
+			pullSecret:    pullSecret("user:wrong"),
+			expectedError: `^pullSecret: Forbidden: the pull secret was rejected by 127.0.0.1:\d+: 401 Unauthorized$`,
+		},
+		{
+			name:          "no credentials for the registry",
+			releaseImage:  host + "/ocp/release@" + testDigest,
+			pullSecret:    `{"auths":{"example.com":{"auth":"dXNlcjpwYXNz"}}}`,
+			expectedError: `^pullSecret: Forbidden: the pull secret was rejected by 127.0.0.1:\d+: 401 Unauthorized$`,
+		},
+		{
+			name:         "release image not found",
+			releaseImage: host + "/ocp/release:4.12",
+			pullSecret:   pullSecret(testAuth),
+		},
+		{
+			name:         "release image pulled from mirrors",
+			releaseImage: host + "/ocp/release@" + testDigest,
+			pullSecret:   pullSecret("user:wrong"),
+			sources: []types.ImageContentSource{{
+				Source:  host + "/ocp/release",
+				Mirrors: []string{"mirror.example.com/ocp/release"},
+			}},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ic := &types.InstallConfig{
+				PullSecret:            tc.pullSecret,
+				AdditionalTrustBundle: trustBundle,
+				ImageContentSources:   tc.sources,
+			}
+			err := ValidatePullSecret(context.Background(), ic, tc.releaseImage).ToAggregate()
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestValidatePullSecretTokens(t *testing.T) {
+	cases := []struct {
+		name          string
+		pullSecret    string
+		expectedError string
+	}{
+		{
+			name:       "unexpired token",
+			pullSecret: `{"auths":{"cloud.openshift.com":{"auth":"dXNlcjpleUpoYkdjaU9pSlNVekkxTmlKOS5leUpsZUhBaU9pQTBNVEF5TkRRME9EQXdmUS5jMmxuYm1GMGRYSmw="}}}`,
+		},
+		{
+			name:          "expired token",
+			pullSecret:    `{"auths":{"cloud.openshift.com":{"auth":"dXNlcjpleUpoYkdjaU9pSlNVekkxTmlKOS5leUpsZUhBaU9pQXhOVGMzT0RNMk9EQXdmUS5jMmxuYm1GMGRYSmw="}}}`,
+			expectedError: `^pullSecret: Forbidden: the token for cloud.openshift.com expired at 2020-01-01T00:00:00Z, download a new pull secret$`,
+		},
+		{
+			name:       "password not a token",
+			pullSecret: `{"auths":{"example.com":{"auth":"dXNlcjpwYXNz"}}}`,
+		},
+		{
+			name:       "auth not base64 encoded",
+			pullSecret: `{"auths":{"example.com":{"auth":"authorization value"}}}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidatePullSecretTokens(&types.InstallConfig{PullSecret: tc.pullSecret}).ToAggregate()
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package connectivity
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// dockerHubRegistry is the registry serving images referenced as docker.io,
+// and dockerHubAuthKey the key its credentials are stored under in pull
+// secrets.
+const (
+	dockerHubRegistry = "registry-1.docker.io"
+	dockerHubAuthKey  = "https://index.docker.io/v1/"
+)
+
+// manifestMediaTypes are the manifest types accepted from registries. A
+// release image may be a single or a multi-arch manifest.
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.image.index.v1+json",
+}
+
+// maxContentSize is the largest manifest or blob read from a registry.
+const maxContentSize = 4 << 20
+
+// authError is returned when a registry does not accept the credentials
+// from the pull secret.
+type authError struct {
+	err error
+}
+
+func (e *authError) Error() string {
+	return e.err.Error()
+}
+
+// registryClient looks up image manifests in container registries, using
+// the credentials from a pull secret.
+type registryClient struct {
+	client *http.Client
+	auths  map[string]string
+}
+
+// newRegistryClient returns a client authenticating with the credentials
+// in the pull secret, trusting the system roots combined with the trust
+// bundle and going through the proxy, if any.
+func newRegistryClient(pullSecret string, trustBundle string, proxy *types.Proxy) (*registryClient, error) {
+	var secret struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal([]byte(pullSecret), &secret); err != nil {
+		return nil, errors.Wrap(err, "failed to parse the pull secret")
+	}
+	auths := map[string]string{}
+	for registry, auth := range secret.Auths {
+		auths[registry] = auth.Auth
+	}
+
+	roots, err := trustedRoots(trustBundle)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+	if proxy != nil {
+		proxyFunc := (&httpproxy.Config{
+			HTTPProxy:  proxy.HTTPProxy,
+			HTTPSProxy: proxy.HTTPSProxy,
+			NoProxy:    proxy.NoProxy,
+		}).ProxyFunc()
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+	return &registryClient{
+		client: &http.Client{Transport: transport, Timeout: dialTimeout},
+		auths:  auths,
+	}, nil
+}
+
+// auth returns the base64 encoded credentials for the registry, if any.
+func (c *registryClient) auth(registry string) string {
+	if registry == dockerHubRegistry {
+		return c.auths[dockerHubAuthKey]
+	}
+	return c.auths[registry]
+}
+
+// manifestExists returns nil if the registry serves the manifest for the
+// reference, which is either a tag or a digest, in the repository. The
+// repository is given in the host/path form.
+func (c *registryClient) manifestExists(ctx context.Context, repository string, reference string) error {
+	resp, err := c.fetch(ctx, http.MethodHead, repository, "manifests/"+reference)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// digest returns the digest of the manifest for the reference, which is
+// either a tag or a digest, in the repository.
+func (c *registryClient) digest(ctx context.Context, repository string, reference string) (string, error) {
+	resp, err := c.fetch(ctx, http.MethodHead, repository, "manifests/"+reference)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", errors.Errorf("%s did not return the digest of %s", repository, reference)
+	}
+	return digest, nil
+}
+
+// get returns the media type and the contents of the manifest or blob at
+// the path in the repository, e.g. manifests/<reference> or blobs/<digest>.
+func (c *registryClient) get(ctx context.Context, repository string, path string) (string, []byte, error) {
+	resp, err := c.fetch(ctx, http.MethodGet, repository, path)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxContentSize))
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "failed to read %s of %s", path, repository)
+	}
+	return resp.Header.Get("Content-Type"), body, nil
+}
+
+// fetch sends the request for the path in the repository, answering the
+// authentication challenge of the registry if needed, and returns the
+// successful response.
+func (c *registryClient) fetch(ctx context.Context, method string, repository string, path string) (*http.Response, error) {
+	registry, repositoryPath := splitRepository(repository)
+	fetchURL := fmt.Sprintf("https://%s/v2/%s/%s", registry, repositoryPath, path)
+
+	resp, err := c.send(ctx, method, fetchURL, "")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		resp.Body.Close()
+		authorization, err := c.authorize(ctx, registry, resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return nil, err
+		}
+		resp, err = c.send(ctx, method, fetchURL, authorization)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp, nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		resp.Body.Close()
+		return nil, &authError{errors.Errorf("the pull secret is not authorized to pull from %s: %s", repository, resp.Status)}
+	case http.StatusNotFound:
+		resp.Body.Close()
+		reference := strings.TrimPrefix(strings.TrimPrefix(path, "manifests/"), "blobs/")
+		return nil, errors.Errorf("%s@%s was not found", repository, reference)
+	default:
+		resp.Body.Close()
+		return nil, errors.Errorf("unexpected response from %s: %s", registry, resp.Status)
+	}
+}
+
+func (c *registryClient) send(ctx context.Context, method string, fetchURL string, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, fetchURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	return c.client.Do(req)
+}
+
+var challengeParamRE = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// authorize answers the authentication challenge of the registry and
+// returns the value for the Authorization header.
+func (c *registryClient) authorize(ctx context.Context, registry string, challenge string) (string, error) {
+	auth := c.auth(registry)
+	scheme, params, _ := strings.Cut(challenge, " ")
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if auth == "" {
+			return "", &authError{errors.Errorf("%s requires credentials but the pull secret has none for it", registry)}
+		}
+		return "Basic " + auth, nil
+	case "bearer":
+	default:
+		return "", errors.Errorf("unsupported authentication challenge from %s: %q", registry, challenge)
+	}
+
+	values := url.Values{}
+	var realm string
+	for _, match := range challengeParamRE.FindAllStringSubmatch(params, -1) {
+		if match[1] == "realm" {
+			realm = match[2]
+			continue
+		}
+		values.Set(match[1], match[2])
+	}
+	if realm == "" {
+		return "", errors.Errorf("authentication challenge from %s has no realm", registry)
+	}
+	tokenURL, err := url.Parse(realm)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid token realm from %s", registry)
+	}
+	tokenURL.RawQuery = values.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if auth != "" {
+		req.Header.Set("Authorization", "Basic "+auth)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get a token for %s", registry)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", &authError{errors.Errorf("the pull secret was rejected by %s: %s", tokenURL.Host, resp.Status)}
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", errors.Wrapf(err, "failed to decode the token for %s", registry)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	return "Bearer " + token.Token, nil
+}
+
+// splitRepository splits a host/path repository into the registry serving
+// it and the path of the repository within the registry.
+func splitRepository(repository string) (string, string) {
+	registry, path, _ := strings.Cut(repository, "/")
+	if registry == "docker.io" {
+		registry = dockerHubRegistry
+	}
+	return registry, path
+}
@@ -0,0 +1,276 @@
+package connectivity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	dockerref "github.com/containers/image/docker/reference"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/openpgp"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// DefaultSignatureStore is the store the signatures of OpenShift release
+// images are published in.
+const DefaultSignatureStore = "https://mirror.openshift.com/pub/openshift-v4/signatures/openshift/release"
+
+// maxSignatures is the most signatures looked up for a release image in a
+// single signature store.
+const maxSignatures = 16
+
+// atomicSignatureType is the type of the signatures of container images.
+const atomicSignatureType = "atomic container signature"
+
+// VerifyReleaseImageSignature checks that one of the signature stores holds
+// a signature of the release image made with one of the verification keys.
+// The release image must be referenced by digest, which the signature must
+// cover. Stores are looked up with the layout of the OpenShift signature
+// store, <store>/<algorithm>=<hex>/signature-<n>.
+func VerifyReleaseImageSignature(ctx context.Context, ic *types.InstallConfig, releaseImage string, keys []string, stores []string) field.ErrorList {
+	allErrs := field.ErrorList{}
+	fldPath := field.NewPath("releaseImage")
+
+	ref, err := dockerref.ParseNamed(releaseImage)
+	if err != nil {
+		return append(allErrs, field.Invalid(fldPath.Child("pullSpec"), releaseImage, err.Error()))
+	}
+	digested, ok := ref.(dockerref.Digested)
+	if !ok {
+		return append(allErrs, field.Invalid(fldPath.Child("pullSpec"), releaseImage, "must reference the image by digest to verify its signature"))
+	}
+	digest := digested.Digest()
+
+	var keyring openpgp.EntityList
+	for i, key := range keys {
+		entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key))
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("verificationKeys").Index(i), "", err.Error()))
+			continue
+		}
+		keyring = append(keyring, entities...)
+	}
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+
+	client, err := newRegistryClient(ic.PullSecret, ic.AdditionalTrustBundle, ic.Proxy)
+	if err != nil {
+		return append(allErrs, field.InternalError(fldPath, err))
+	}
+	if len(stores) == 0 {
+		stores = []string{DefaultSignatureStore}
+	}
+
+	var failures []string
+	for _, store := range stores {
+		for n := 1; n <= maxSignatures; n++ {
+			signatureURL := fmt.Sprintf("%s/%s=%s/signature-%d", strings.TrimSuffix(store, "/"), digest.Algorithm(), digest.Encoded(), n)
+			signature, err := client.signature(ctx, signatureURL)
+			if err != nil {
+				failures = append(failures, err.Error())
+				break
+			}
+			if signature == nil {
+				if n == 1 {
+					failures = append(failures, fmt.Sprintf("%s has no signature of the release image", store))
+				}
+				break
+			}
+			if err := verifySignature(keyring, signature, digest.String()); err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", signatureURL, err))
+				continue
+			}
+			logrus.Infof("Verified the signature of the release image %s from %s", releaseImage, signatureURL)
+			return allErrs
+		}
+	}
+	return append(allErrs, field.Forbidden(fldPath.Child("pullSpec"), fmt.Sprintf("no valid signature of %s made with the verification keys was found: %s", releaseImage, strings.Join(failures, "; "))))
+}
+
+// signature returns the signature at the URL, or nil when the store has no
+// signature there.
+func (c *registryClient) signature(ctx context.Context, signatureURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, signatureURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch the signature")
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, errors.Errorf("unexpected response from %s: %s", signatureURL, resp.Status)
+	}
+	signature, err := io.ReadAll(io.LimitReader(resp.Body, maxContentSize))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", signatureURL)
+	}
+	return signature, nil
+}
+
+// verifySignature checks that the signature was made with a key of the
+// keyring and that it covers the manifest digest.
+func verifySignature(keyring openpgp.EntityList, signature []byte, digest string) error {
+	md, err := openpgp.ReadMessage(bytes.NewReader(signature), keyring, nil, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the signature")
+	}
+	if !md.IsSigned || md.SignedBy == nil {
+		return errors.New("not signed with any of the verification keys")
+	}
+	content, err := io.ReadAll(io.LimitReader(md.UnverifiedBody, maxContentSize))
+	if err != nil {
+		return errors.Wrap(err, "failed to read the signed content")
+	}
+	// The signature is only checked once the whole content has been read.
+	if md.SignatureError != nil {
+		return errors.Wrap(md.SignatureError, "invalid signature")
+	}
+
+	var claims struct {
+		Critical struct {
+			Type  string `json:"type"`
+			Image struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+		} `json:"critical"`
+	}
+	if err := json.Unmarshal(content, &claims); err != nil {
+		return errors.Wrap(err, "failed to parse the signed content")
+	}
+	if claims.Critical.Type != atomicSignatureType {
+		return errors.Errorf("unsupported signature type %q", claims.Critical.Type)
+	}
+	if claims.Critical.Image.DockerManifestDigest != digest {
+		return errors.Errorf("signature is for %s", claims.Critical.Image.DockerManifestDigest)
+	}
+	return nil
+}
+
+// ValidateReleaseImageArchitecture checks that the release image provides
+// the architecture of every machine pool. When mirror sources are
+// configured the release image is pulled from the mirrors, so the check is
+// skipped. Failing to fetch the release image from the installer host
+// results in a warning.
+func ValidateReleaseImageArchitecture(ctx context.Context, ic *types.InstallConfig, releaseImage string) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(ic.MirrorSources()) > 0 {
+		return allErrs
+	}
+
+	ref, err := dockerref.ParseNamed(releaseImage)
+	if err != nil {
+		return append(allErrs, field.InternalError(field.NewPath("releaseImage"), err))
+	}
+	client, err := newRegistryClient(ic.PullSecret, ic.AdditionalTrustBundle, ic.Proxy)
+	if err != nil {
+		return append(allErrs, field.InternalError(field.NewPath("releaseImage"), err))
+	}
+	architectures, err := client.architectures(ctx, ref.Name(), imageReference(ref))
+	if err != nil {
+		logrus.Warnf("Unable to check the architecture of the release image: %v", err)
+		return allErrs
+	}
+
+	check := func(fldPath *field.Path, architecture types.Architecture) {
+		if architecture != "" && !architectures.Has(string(architecture)) {
+			allErrs = append(allErrs, field.Invalid(fldPath, architecture, fmt.Sprintf("the release image %s provides only %s", releaseImage, strings.Join(architectures.List(), ", "))))
+		}
+	}
+	if ic.ControlPlane != nil {
+		check(field.NewPath("controlPlane", "architecture"), ic.ControlPlane.Architecture)
+	}
+	for i, pool := range ic.Compute {
+		check(field.NewPath("compute").Index(i).Child("architecture"), pool.Architecture)
+	}
+	return allErrs
+}
+
+// architectures returns the architectures of the image, those of its
+// manifests for a multi-arch image and that of its config otherwise.
+func (c *registryClient) architectures(ctx context.Context, repository string, reference string) (sets.String, error) {
+	_, body, err := c.get(ctx, repository, "manifests/"+reference)
+	if err != nil {
+		return nil, err
+	}
+	var manifest struct {
+		Manifests []struct {
+			Platform struct {
+				Architecture string `json:"architecture"`
+			} `json:"platform"`
+		} `json:"manifests"`
+		Config struct {
+			Digest string `json:"digest"`
+		} `json:"config"`
+	}
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the manifest of %s", repository)
+	}
+
+	architectures := sets.NewString()
+	if manifest.Config.Digest == "" {
+		for _, m := range manifest.Manifests {
+			architectures.Insert(m.Platform.Architecture)
+		}
+	} else {
+		_, body, err := c.get(ctx, repository, "blobs/"+manifest.Config.Digest)
+		if err != nil {
+			return nil, err
+		}
+		var config struct {
+			Architecture string `json:"architecture"`
+		}
+		if err := json.Unmarshal(body, &config); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the config of %s", repository)
+		}
+		architectures.Insert(config.Architecture)
+	}
+	architectures.Delete("")
+	if architectures.Len() == 0 {
+		return nil, errors.Errorf("the manifest of %s has no architecture", repository)
+	}
+	return architectures, nil
+}
+
+// ResolveDigest returns the digest of the image, looking it up anonymously
+// in its registry unless the image is referenced by digest.
+func ResolveDigest(ctx context.Context, image string) (string, error) {
+	ref, err := dockerref.ParseNamed(image)
+	if err != nil {
+		return "", err
+	}
+	if digested, ok := ref.(dockerref.Digested); ok {
+		return digested.Digest().String(), nil
+	}
+	client, err := newRegistryClient(`{"auths":{}}`, "", nil)
+	if err != nil {
+		return "", err
+	}
+	return client.digest(ctx, ref.Name(), imageReference(ref))
+}
+
+// imageReference returns the digest or tag the image is referenced by.
+func imageReference(ref dockerref.Named) string {
+	switch r := ref.(type) {
+	case dockerref.Digested:
+		return r.Digest().String()
+	case dockerref.Tagged:
+		return r.Tag()
+	default:
+		return "latest"
+	}
+}
@@ -0,0 +1,210 @@
+package connectivity
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// newTestKey returns a signing key and its ASCII-armored public key.
+func newTestKey(t *testing.T) (*openpgp.Entity, string) {
+	entity, err := openpgp.NewEntity("test", "", "test@example.com", nil)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+	return entity, buf.String()
+}
+
+// sign returns an atomic container signature of the digest made with the key.
+func sign(t *testing.T, key *openpgp.Entity, digest string) []byte {
+	var buf bytes.Buffer
+	w, err := openpgp.Sign(&buf, key, nil, nil)
+	require.NoError(t, err)
+	fmt.Fprintf(w, `{"critical":{"identity":{"docker-reference":"quay.io/openshift-release-dev/ocp-release"},"image":{"docker-manifest-digest":%q},"type":"atomic container signature"},"optional":{}}`, digest)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestVerifyReleaseImageSignature(t *testing.T) {
+	trusted, trustedKey := newTestKey(t)
+	untrusted, _ := newTestKey(t)
+	otherDigest := "sha256:" + strings.Repeat("f", 64)
+
+	signatures := map[string][]byte{}
+	store := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature, ok := signatures[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(signature)
+	}))
+	defer store.Close()
+	trustBundle := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: store.Certificate().Raw}))
+	signaturePath := "/signatures/sha256=" + strings.TrimPrefix(testDigest, "sha256:") + "/signature-%d"
+
+	cases := []struct {
+		name          string
+		releaseImage  string
+		signatures    [][]byte
+		keys          []string
+		expectedError string
+	}{
+		{
+			name:         "signed with trusted key",
+			releaseImage: "quay.io/openshift-release-dev/ocp-release@" + testDigest,
+			signatures:   [][]byte{sign(t, trusted, testDigest)},
+			keys:         []string{trustedKey},
+		},
+		{
+			name:         "trusted signature after untrusted one",
+			releaseImage: "quay.io/openshift-release-dev/ocp-release@" + testDigest,
+			signatures:   [][]byte{sign(t, untrusted, testDigest), sign(t, trusted, testDigest)},
+			keys:         []string{trustedKey},
+		},
+		{
+			name:          "signed with untrusted key",
+			releaseImage:  "quay.io/openshift-release-dev/ocp-release@" + testDigest,
+			signatures:    [][]byte{sign(t, untrusted, testDigest)},
+			keys:          []string{trustedKey},
+			expectedError: `^releaseImage.pullSpec: Forbidden: no valid signature of .* made with the verification keys was found: https://127.0.0.1:\d+/signatures/sha256=[0-9a-f]+/signature-1: not signed with any of the verification keys$`,
+		},
+		{
+			name:          "signature of another image",
+			releaseImage:  "quay.io/openshift-release-dev/ocp-release@" + testDigest,
+			signatures:    [][]byte{sign(t, trusted, otherDigest)},
+			keys:          []string{trustedKey},
+			expectedError: `signature-1: signature is for sha256:f+$`,
+		},
+		{
+			name:          "unsigned",
+			releaseImage:  "quay.io/openshift-release-dev/ocp-release@" + testDigest,
+			keys:          []string{trustedKey},
+			expectedError: `found: https://127.0.0.1:\d+/signatures has no signature of the release image$`,
+		},
+		{
+			name:          "referenced by tag",
+			releaseImage:  "quay.io/openshift-release-dev/ocp-release:4.12",
+			keys:          []string{trustedKey},
+			expectedError: `^releaseImage.pullSpec: Invalid value: "quay.io/openshift-release-dev/ocp-release:4.12": must reference the image by digest to verify its signature$`,
+		},
+		{
+			name:          "invalid key",
+			releaseImage:  "quay.io/openshift-release-dev/ocp-release@" + testDigest,
+			keys:          []string{"not a key"},
+			expectedError: `^releaseImage.verificationKeys\[0\]: Invalid value: "": .*$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for k := range signatures {
+				delete(signatures, k)
+			}
+			for i, signature := range tc.signatures {
+				signatures[fmt.Sprintf(signaturePath, i+1)] = signature
+			}
+			ic := &types.InstallConfig{
+				PullSecret:            `{"auths":{}}`,
+				AdditionalTrustBundle: trustBundle,
+			}
+			err := VerifyReleaseImageSignature(context.Background(), ic, tc.releaseImage, tc.keys, []string{store.URL + "/signatures"}).ToAggregate()
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestValidateReleaseImageArchitecture(t *testing.T) {
+	const configDigest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	registry := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/release/single/manifests/4.12":
+			fmt.Fprintf(w, `{"schemaVersion":2,"mediaType":"application/vnd.docker.distribution.manifest.v2+json","config":{"digest":%q}}`, configDigest)
+		case "/v2/release/single/blobs/" + configDigest:
+			fmt.Fprint(w, `{"architecture":"amd64","os":"linux"}`)
+		case "/v2/release/multi/manifests/4.12":
+			fmt.Fprint(w, `{"schemaVersion":2,"mediaType":"application/vnd.docker.distribution.manifest.list.v2+json","manifests":[{"platform":{"architecture":"amd64","os":"linux"}},{"platform":{"architecture":"arm64","os":"linux"}}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer registry.Close()
+	host := strings.TrimPrefix(registry.URL, "https://")
+	trustBundle := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: registry.Certificate().Raw}))
+
+	cases := []struct {
+		name          string
+		repository    string
+		control       types.Architecture
+		compute       types.Architecture
+		expectedError string
+	}{
+		{
+			name:       "single arch image",
+			repository: "release/single",
+			control:    types.ArchitectureAMD64,
+			compute:    types.ArchitectureAMD64,
+		},
+		{
+			name:          "single arch image for another architecture",
+			repository:    "release/single",
+			control:       types.ArchitectureAMD64,
+			compute:       types.ArchitectureARM64,
+			expectedError: `^compute\[0\].architecture: Invalid value: "arm64": the release image 127.0.0.1:\d+/release/single:4.12 provides only amd64$`,
+		},
+		{
+			name:       "multi arch image",
+			repository: "release/multi",
+			control:    types.ArchitectureARM64,
+			compute:    types.ArchitectureAMD64,
+		},
+		{
+			name:          "multi arch image without architecture",
+			repository:    "release/multi",
+			control:       types.ArchitectureS390X,
+			compute:       types.ArchitectureAMD64,
+			expectedError: `^controlPlane.architecture: Invalid value: "s390x": the release image 127.0.0.1:\d+/release/multi:4.12 provides only amd64, arm64$`,
+		},
+		{
+			name:       "missing image",
+			repository: "release/missing",
+			control:    types.ArchitectureS390X,
+			compute:    types.ArchitectureS390X,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ic := &types.InstallConfig{
+				PullSecret:            fmt.Sprintf(`{"auths":{%q:{"auth":%q}}}`, host, base64.StdEncoding.EncodeToString([]byte(testAuth))),
+				AdditionalTrustBundle: trustBundle,
+				ControlPlane:          &types.MachinePool{Name: "master", Architecture: tc.control},
+				Compute:               []types.MachinePool{{Name: "worker", Architecture: tc.compute}},
+			}
+			err := ValidateReleaseImageArchitecture(context.Background(), ic, fmt.Sprintf("%s/%s:4.12", host, tc.repository)).ToAggregate()
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package connectivity
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// ValidateTangServers checks that the Tang servers the root disk encryption
+// of the machine pools is bound to serve their advertisement.
+//
+// A Tang server which cannot be reached from the installer host only results
+// in a warning, since it may only be reachable from the machine network.
+func ValidateTangServers(ctx context.Context, ic *types.InstallConfig) field.ErrorList {
+	allErrs := field.ErrorList{}
+	client := &http.Client{Timeout: dialTimeout}
+
+	check := func(pool *types.MachinePool, fldPath *field.Path) {
+		if pool == nil || pool.DiskEncryption == nil {
+			return
+		}
+		for i, tang := range pool.DiskEncryption.Tang {
+			err := tangAdvertisement(ctx, client, tang.URL)
+			if err == nil {
+				continue
+			}
+			var unreachable *unreachableError
+			if errors.As(err, &unreachable) {
+				logrus.Warnf("Unable to check the Tang server %s from the installer host: %v", tang.URL, err)
+				continue
+			}
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("diskEncryption", "tang").Index(i).Child("url"), tang.URL, err.Error()))
+		}
+	}
+	check(ic.ControlPlane, field.NewPath("controlPlane"))
+	for i := range ic.Compute {
+		check(&ic.Compute[i], field.NewPath("compute").Index(i))
+	}
+	return allErrs
+}
+
+// tangAdvertisement fetches the advertisement of the Tang server.
+func tangAdvertisement(ctx context.Context, client *http.Client, server string) error {
+	advURL, err := url.Parse(server)
+	if err != nil {
+		return err
+	}
+	advURL.Path = strings.TrimSuffix(advURL.Path, "/") + "/adv"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, advURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return &unreachableError{err: err}
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("the Tang server did not serve its advertisement: %s", resp.Status)
+	}
+	return nil
+}
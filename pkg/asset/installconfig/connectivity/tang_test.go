@@ -0,0 +1,67 @@
+package connectivity
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestValidateTangServers(t *testing.T) {
+	tang := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/adv" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/jose+json")
+		w.Write([]byte(`{"payload":"","protected":"","signature":""}`))
+	}))
+	defer tang.Close()
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+
+	cases := []struct {
+		name          string
+		servers       []types.TangServer
+		expectedError string
+	}{
+		{
+			name: "no disk encryption",
+		},
+		{
+			name:    "tang server available",
+			servers: []types.TangServer{{URL: tang.URL, Thumbprint: "test-thumbprint"}},
+		},
+		{
+			name:          "tang server without advertisement",
+			servers:       []types.TangServer{{URL: tang.URL, Thumbprint: "test-thumbprint"}, {URL: tang.URL + "/other", Thumbprint: "test-thumbprint"}},
+			expectedError: `^compute\[0\]\.diskEncryption\.tang\[1\]\.url: Invalid value: "http://127.0.0.1:\d+/other": the Tang server did not serve its advertisement: 404 Not Found$`,
+		},
+		{
+			name:    "tang server unreachable from installer host",
+			servers: []types.TangServer{{URL: unreachable.URL, Thumbprint: "test-thumbprint"}},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pool := types.MachinePool{Name: "worker"}
+			if tc.servers != nil {
+				pool.DiskEncryption = &types.DiskEncryption{Tang: tc.servers, Threshold: 1}
+			}
+			ic := &types.InstallConfig{
+				ControlPlane: &types.MachinePool{Name: "master"},
+				Compute:      []types.MachinePool{pool},
+			}
+			err := ValidateTangServers(context.Background(), ic).ToAggregate()
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package installconfig
+
+import (
+	"context"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig/connectivity"
+	"github.com/openshift/installer/pkg/asset/releaseimage"
+	"github.com/openshift/installer/pkg/offline"
+)
+
+// ConnectivityCheck is an asset that checks that the services the cluster
+// depends on can be reached with the network configuration from the
+// install-config.
+type ConnectivityCheck struct {
+	checkRegistryAccess bool
+}
+
+var (
+	_ asset.ContextAsset    = (*ConnectivityCheck)(nil)
+	_ asset.ConfiguredAsset = (*ConnectivityCheck)(nil)
+)
+
+// SetOptions sets whether the access to the registry of the release image
+// is checked.
+func (a *ConnectivityCheck) SetOptions(options asset.Options) {
+	a.checkRegistryAccess = options.CheckRegistryAccess
+}
+
+// Dependencies returns the dependencies for ConnectivityCheck
+func (a *ConnectivityCheck) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&InstallConfig{},
+		&releaseimage.Image{},
+	}
+}
+
+// Generate performs the connectivity checks.
+func (a *ConnectivityCheck) Generate(dependencies asset.Parents) error {
+	return a.GenerateWithContext(context.Background(), "", dependencies)
+}
+
+// GenerateWithContext performs the connectivity checks, which are canceled
+// with the context.
+func (a *ConnectivityCheck) GenerateWithContext(ctx context.Context, _ string, dependencies asset.Parents) error {
+	ic := &InstallConfig{}
+	releaseImage := &releaseimage.Image{}
+	dependencies.Get(ic, releaseImage)
+
+	// The signature of the release image is verified even when the
+	// pre-flight validations are disabled, so that they cannot be used to
+	// install an image the verification keys do not trust.
+	if len(releaseImage.VerificationKeys) > 0 {
+		if err := offline.Require("Verifying the signature of the release image"); err != nil {
+			return err
+		}
+		if errs := connectivity.VerifyReleaseImageSignature(ctx, ic.Config, releaseImage.PullSpec, releaseImage.VerificationKeys, releaseImage.SignatureStores); len(errs) > 0 {
+			return errs.ToAggregate()
+		}
+	}
+
+	if skip := os.Getenv("OPENSHIFT_INSTALL_SKIP_PREFLIGHT_VALIDATIONS"); skip == "1" {
+		logrus.Warnf("OVERRIDE: pre-flight validation disabled.")
+		return nil
+	}
+
+	// The tokens of the pull secret are checked without reaching the
+	// registries, so even when offline.
+	allErrs := connectivity.ValidatePullSecretTokens(ic.Config)
+	if offline.Skip("Connectivity checks") {
+		return allErrs.ToAggregate()
+	}
+
+	if releaseImage.Overridden {
+		allErrs = append(allErrs, connectivity.ValidateReleaseImageArchitecture(ctx, ic.Config, releaseImage.PullSpec)...)
+	}
+	allErrs = append(allErrs, connectivity.ValidateMirrors(ctx, ic.Config, releaseImage.PullSpec)...)
+	if a.checkRegistryAccess {
+		allErrs = append(allErrs, connectivity.ValidateProxy(ctx, ic.Config.Proxy, ic.Config.AdditionalTrustBundle, releaseImage.PullSpec)...)
+		allErrs = append(allErrs, connectivity.ValidatePullSecret(ctx, ic.Config, releaseImage.PullSpec)...)
+	} else {
+		logrus.Debug("Not checking the access to the release image registry through the proxy and with the pull secret, enable it with --check-registry-access")
+	}
+	allErrs = append(allErrs, connectivity.ValidateTangServers(ctx, ic.Config)...)
+	allErrs = append(allErrs, connectivity.ValidateDNSRecords(ctx, ic.Config)...)
+	return allErrs.ToAggregate()
+}
+
+// Name returns the human-friendly name of the asset.
+func (a *ConnectivityCheck) Name() string {
+	return "Connectivity Check"
+}
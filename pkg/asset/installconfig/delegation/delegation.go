@@ -0,0 +1,61 @@
+// Package delegation checks that the public zone of the base domain is
+// delegated to the name servers of the DNS provider which serves it, so that
+// the records the installer creates in the zone resolve.
+package delegation
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// lookupNS is a wrapper for the resolver so we can override it in the tests.
+var lookupNS = func(ctx context.Context, name string) ([]*net.NS, error) {
+	return net.DefaultResolver.LookupNS(ctx, name)
+}
+
+// ValidateBaseDomain checks that the NS records of the base domain, resolved
+// from the installer host, include one of the name servers of its zone. A
+// base domain whose NS records cannot be resolved, other than because it does
+// not exist, only results in a warning.
+func ValidateBaseDomain(ctx context.Context, baseDomain string, nameServers []string, fldPath *field.Path) field.ErrorList {
+	if len(nameServers) == 0 {
+		return nil
+	}
+	expected := sets.NewString()
+	for _, ns := range nameServers {
+		expected.Insert(normalize(ns))
+	}
+
+	records, err := lookupNS(ctx, baseDomain)
+	if err != nil {
+		if dnsErr, ok := err.(*net.DNSError); ok && dnsErr.IsNotFound {
+			return field.ErrorList{field.Invalid(fldPath, baseDomain, fmt.Sprintf("the base domain has no NS records in the public DNS; delegate it to the name servers of its zone %v", expected.List()))}
+		}
+		logrus.Warnf("Unable to check the delegation of the base domain %s from the installer host: %v", baseDomain, err)
+		return nil
+	}
+
+	delegated := make([]string, 0, len(records))
+	for _, record := range records {
+		host := normalize(record.Host)
+		if expected.Has(host) {
+			return nil
+		}
+		delegated = append(delegated, host)
+	}
+	sort.Strings(delegated)
+	return field.ErrorList{field.Invalid(fldPath, baseDomain, fmt.Sprintf("the base domain is delegated to %v, none of the name servers of its zone %v", delegated, expected.List()))}
+}
+
+// normalize returns the name of the name server without its trailing dot, in
+// lower case.
+func normalize(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
@@ -0,0 +1,60 @@
+package delegation
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateBaseDomain(t *testing.T) {
+	cases := []struct {
+		name          string
+		nameServers   []string
+		records       []*net.NS
+		lookupErr     error
+		expectedError string
+	}{
+		{
+			name: "no name servers",
+		},
+		{
+			name:        "delegated",
+			nameServers: []string{"ns-1.example.net.", "ns-2.example.net."},
+			records:     []*net.NS{{Host: "NS-2.example.net."}},
+		},
+		{
+			name:          "delegated elsewhere",
+			nameServers:   []string{"ns-1.example.net.", "ns-2.example.net."},
+			records:       []*net.NS{{Host: "ns2.other.org."}, {Host: "ns1.other.org."}},
+			expectedError: `^baseDomain: Invalid value: "example\.com": the base domain is delegated to \[ns1\.other\.org ns2\.other\.org\], none of the name servers of its zone \[ns-1\.example\.net ns-2\.example\.net\]$`,
+		},
+		{
+			name:          "not delegated",
+			nameServers:   []string{"ns-1.example.net"},
+			lookupErr:     &net.DNSError{Err: "no such host", Name: "example.com", IsNotFound: true},
+			expectedError: `^baseDomain: Invalid value: "example\.com": the base domain has no NS records in the public DNS; delegate it to the name servers of its zone \[ns-1\.example\.net\]$`,
+		},
+		{
+			name:        "lookup failure",
+			nameServers: []string{"ns-1.example.net"},
+			lookupErr:   errors.New("i/o timeout"),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			lookupNS = func(_ context.Context, name string) ([]*net.NS, error) {
+				return tc.records, tc.lookupErr
+			}
+			err := ValidateBaseDomain(context.Background(), "example.com", tc.nameServers, field.NewPath("baseDomain")).ToAggregate()
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
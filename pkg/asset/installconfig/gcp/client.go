@@ -14,6 +14,8 @@ import (
 	"google.golang.org/api/option"
 	"google.golang.org/api/serviceusage/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/installer/pkg/credentialsfile"
 )
 
 //go:generate mockgen -source=./client.go -destination=./mock/gcpclient_generated.go -package=mock
@@ -49,10 +51,16 @@ type Client struct {
 
 // NewClient initializes a client with a session.
 func NewClient(ctx context.Context) (*Client, error) {
+	return NewClientWithCredentials(ctx, nil)
+}
+
+// NewClientWithCredentials initializes a client with a session with the
+// credentials, or the default ones when they are nil.
+func NewClientWithCredentials(ctx context.Context, credentials *credentialsfile.GCP) (*Client, error) {
 	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
 	defer cancel()
 
-	ssn, err := GetSession(ctx)
+	ssn, err := GetSessionWithCredentials(ctx, credentials)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get session")
 	}
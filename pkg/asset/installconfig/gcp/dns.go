@@ -11,6 +11,8 @@ import (
 	"github.com/pkg/errors"
 	dns "google.golang.org/api/dns/v1"
 	"google.golang.org/api/googleapi"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 )
 
 // GetPublicZone returns a DNS managed zone from the provided project which matches the baseDomain
@@ -49,7 +51,7 @@ func GetBaseDomain(project string) (string, error) {
 	sort.Strings(publicZones)
 
 	var domain string
-	if err := survey.AskOne(
+	if err := answers.AskOne(answers.BaseDomain,
 		&survey.Select{
 			Message: "Base Domain",
 			Help:    "The base domain of the cluster. All DNS records will be sub-domains of this base and will also include the cluster name.\n\nIf you don't see you intended base-domain listed, create a new public hosted zone and rerun the installer.",
@@ -11,6 +11,7 @@ import (
 	"github.com/AlecAivazis/survey/v2/core"
 	"github.com/pkg/errors"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 	"github.com/openshift/installer/pkg/types/gcp"
 	gcpValidation "github.com/openshift/installer/pkg/types/gcp/validation"
 )
@@ -68,7 +69,7 @@ func selectProject(ctx context.Context) (string, error) {
 	sort.Strings(options)
 
 	var selectedProject string
-	err = survey.Ask([]*survey.Question{
+	err = answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Select{
 				Message: "Project ID",
@@ -146,7 +147,7 @@ func selectRegion(ctx context.Context, project string) (string, error) {
 	}
 
 	var selectedRegion string
-	err = survey.Ask([]*survey.Question{
+	err = answers.Ask(answers.Region, []*survey.Question{
 		{
 			Prompt: &survey.Select{
 				Message: "Region",
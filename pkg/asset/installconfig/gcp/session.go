@@ -2,6 +2,7 @@ package gcp
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,6 +14,9 @@ import (
 	"github.com/sirupsen/logrus"
 	googleoauth "golang.org/x/oauth2/google"
 	compute "google.golang.org/api/compute/v1"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
+	"github.com/openshift/installer/pkg/credentialsfile"
 )
 
 var (
@@ -20,6 +24,7 @@ var (
 	defaultAuthFilePath = filepath.Join(os.Getenv("HOME"), ".gcp", "osServiceAccount.json")
 	credLoaders         = []credLoader{}
 	onceLoggers         = map[credLoader]*sync.Once{}
+	credentialsFileOnce sync.Once
 )
 
 // Session is an object representing session for GCP API.
@@ -33,9 +38,18 @@ type Session struct {
 // env GCLOUD_KEYFILE_JSON,
 // file ~/.gcp/osServiceAccount.json, and
 // gcloud cli defaults
+// unless the credentials file of the invocation has some,
 // and, if no creds are found, asks for them and stores them on disk in a config file
 func GetSession(ctx context.Context) (*Session, error) {
-	creds, err := loadCredentials(ctx)
+	return GetSessionWithCredentials(ctx, nil)
+}
+
+// GetSessionWithCredentials returns a GCP session with the credentials, in
+// place of those of the credentials file of the invocation and of the
+// default locations. When the credentials are nil, it is the same as
+// GetSession.
+func GetSessionWithCredentials(ctx context.Context, credentials *credentialsfile.GCP) (*Session, error) {
+	creds, err := loadCredentials(ctx, credentials)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to load credentials")
 	}
@@ -45,7 +59,13 @@ func GetSession(ctx context.Context) (*Session, error) {
 	}, nil
 }
 
-func loadCredentials(ctx context.Context) (*googleoauth.Credentials, error) {
+func loadCredentials(ctx context.Context, credentials *credentialsfile.GCP) (*googleoauth.Credentials, error) {
+	if credentials == nil {
+		credentials = credentialsfile.Get().GCPCredentials()
+	}
+	if credentials != nil {
+		return loadCredentialsFile(ctx, credentials)
+	}
 	if len(credLoaders) == 0 {
 		for _, authEnv := range authEnvs {
 			credLoaders = append(credLoaders, &envLoader{env: authEnv})
@@ -71,6 +91,31 @@ func loadCredentials(ctx context.Context) (*googleoauth.Credentials, error) {
 	return getCredentials(ctx)
 }
 
+// loadCredentialsFile loads the credentials of a credentials file, which
+// take the place of the ones of the default locations.
+func loadCredentialsFile(ctx context.Context, c *credentialsfile.GCP) (*googleoauth.Credentials, error) {
+	var loader credLoader
+	switch {
+	case c.ServiceAccountFile != "":
+		loader = &fileLoader{path: c.ServiceAccountFile}
+	case c.ServiceAccount != "":
+		loader = &contentLoader{content: c.ServiceAccount}
+	default:
+		loader = &cliLoader{}
+	}
+	if c.ImpersonateServiceAccount != "" {
+		loader = &impersonationLoader{source: loader, serviceAccount: c.ImpersonateServiceAccount, delegates: c.Delegates}
+	}
+	creds, err := loader.Load(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the credentials of the credentials file")
+	}
+	credentialsFileOnce.Do(func() {
+		logrus.Infof("Credentials loaded from %s of the credentials file", loader)
+	})
+	return creds, nil
+}
+
 func getCredentials(ctx context.Context) (*googleoauth.Credentials, error) {
 	creds, err := (&userLoader{}).Load(ctx)
 	if err != nil {
@@ -166,6 +211,43 @@ func (f *contentLoader) String() string {
 	return "content <redacted>"
 }
 
+// impersonationLoader loads the credentials of a service account which the
+// credentials of the source impersonate, through the chain of delegation of
+// the delegates.
+type impersonationLoader struct {
+	source         credLoader
+	serviceAccount string
+	delegates      []string
+}
+
+func (i *impersonationLoader) Load(ctx context.Context) (*googleoauth.Credentials, error) {
+	source, err := i.source.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(source.JSON) == 0 {
+		return nil, errors.Errorf("the credentials of %s cannot impersonate a service account", i.source)
+	}
+	delegates := make([]string, 0, len(i.delegates))
+	for _, delegate := range i.delegates {
+		delegates = append(delegates, "projects/-/serviceAccounts/"+delegate)
+	}
+	content, err := json.Marshal(map[string]interface{}{
+		"type":                              "impersonated_service_account",
+		"service_account_impersonation_url": fmt.Sprintf("https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/%s:generateAccessToken", i.serviceAccount),
+		"delegates":                         delegates,
+		"source_credentials":                json.RawMessage(source.JSON),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return (&contentLoader{content: string(content)}).Load(ctx)
+}
+
+func (i *impersonationLoader) String() string {
+	return fmt.Sprintf("%s impersonating %q", i.source, i.serviceAccount)
+}
+
 type cliLoader struct{}
 
 func (c *cliLoader) Load(ctx context.Context) (*googleoauth.Credentials, error) {
@@ -180,7 +262,7 @@ type userLoader struct{}
 
 func (u *userLoader) Load(ctx context.Context) (*googleoauth.Credentials, error) {
 	var content string
-	err := survey.Ask([]*survey.Question{
+	err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Multiline{
 				Message: "Service Account (absolute path to file or JSON content)",
@@ -0,0 +1,37 @@
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImpersonationLoader(t *testing.T) {
+	source := &contentLoader{content: `{"type":"authorized_user","client_id":"client","client_secret":"secret","refresh_token":"token"}`}
+	loader := &impersonationLoader{
+		source:         source,
+		serviceAccount: "installer@lab.iam.gserviceaccount.com",
+		delegates:      []string{"hub@lab.iam.gserviceaccount.com"},
+	}
+
+	creds, err := loader.Load(context.Background())
+	if !assert.NoError(t, err) {
+		return
+	}
+	var content map[string]interface{}
+	if !assert.NoError(t, json.Unmarshal(creds.JSON, &content)) {
+		return
+	}
+	assert.Equal(t, "impersonated_service_account", content["type"])
+	assert.Equal(t, "https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/installer@lab.iam.gserviceaccount.com:generateAccessToken", content["service_account_impersonation_url"])
+	assert.Equal(t, []interface{}{"projects/-/serviceAccounts/hub@lab.iam.gserviceaccount.com"}, content["delegates"])
+	assert.Equal(t, map[string]interface{}{
+		"type":          "authorized_user",
+		"client_id":     "client",
+		"client_secret": "secret",
+		"refresh_token": "token",
+	}, content["source_credentials"])
+	assert.Equal(t, `content <redacted> impersonating "installer@lab.iam.gserviceaccount.com"`, loader.String())
+}
@@ -14,6 +14,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/delegation"
 	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/validate"
 )
@@ -94,8 +95,12 @@ func validateInstanceTypes(client API, ic *types.InstallConfig) field.ErrorList
 		// Default requirements can be relaxed when the controlPlane type is set explicitly.
 		defaultInstanceReq = computeReq
 
+		req := controlPlaneReq
+		if ic.IsSingleReplicaControlPlane() {
+			req.minimumVCpus = types.SingleReplicaControlPlaneMinimumVCPUs
+		}
 		allErrs = append(allErrs, ValidateInstanceType(client, field.NewPath("controlPlane", "platform", "gcp"), ic.GCP.ProjectID, zones[0].Name,
-			ic.ControlPlane.Platform.GCP.InstanceType, controlPlaneReq)...)
+			ic.ControlPlane.Platform.GCP.InstanceType, req)...)
 	}
 
 	if ic.Platform.GCP.DefaultMachinePlatform != nil && ic.Platform.GCP.DefaultMachinePlatform.InstanceType != "" {
@@ -124,7 +129,7 @@ func ValidatePreExistingPublicDNS(client API, ic *types.InstallConfig) error {
 		return nil
 	}
 
-	record := fmt.Sprintf("api.%s.", strings.TrimSuffix(ic.ClusterDomain(), "."))
+	record := fmt.Sprintf("%s.", ic.APIDomainName())
 
 	zone, err := client.GetPublicDNSZone(context.TODO(), ic.Platform.GCP.ProjectID, ic.BaseDomain)
 	if err != nil {
@@ -151,6 +156,21 @@ func ValidatePreExistingPublicDNS(client API, ic *types.InstallConfig) error {
 	return nil
 }
 
+// ValidateBaseDomainDelegation checks that the public managed zone of the base
+// domain is delegated to its Cloud DNS name servers.
+func ValidateBaseDomainDelegation(client API, ic *types.InstallConfig) error {
+	if ic.Publish == types.InternalPublishingStrategy {
+		return nil
+	}
+
+	fldPath := field.NewPath("baseDomain")
+	zone, err := client.GetPublicDNSZone(context.TODO(), ic.Platform.GCP.ProjectID, ic.BaseDomain)
+	if err != nil {
+		return field.InternalError(fldPath, err)
+	}
+	return delegation.ValidateBaseDomain(context.TODO(), ic.BaseDomain, zone.NameServers, fldPath).ToAggregate()
+}
+
 func validateProject(client API, ic *types.InstallConfig, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -210,6 +230,28 @@ func validateNetworks(client API, ic *types.InstallConfig, fieldPath *field.Path
 	return allErrs
 }
 
+// SubnetCIDRs retrieves the CIDR ranges of the compute and control plane
+// subnets of the user-provided VPC.
+func SubnetCIDRs(ctx context.Context, client API, ic *types.InstallConfig) ([]string, error) {
+	networkProjectID := ic.GCP.NetworkProjectID
+	if networkProjectID == "" {
+		networkProjectID = ic.GCP.ProjectID
+	}
+	subnets, err := client.GetSubnetworks(ctx, ic.GCP.Network, networkProjectID, ic.GCP.Region)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve the subnets of network %s", ic.GCP.Network)
+	}
+	var cidrs []string
+	for _, name := range []string{ic.GCP.ControlPlaneSubnet, ic.GCP.ComputeSubnet} {
+		subnet, errMsg := findSubnet(subnets, name, ic.GCP.Network, ic.GCP.Region)
+		if subnet == nil {
+			return nil, errors.New(errMsg)
+		}
+		cidrs = append(cidrs, subnet.IpCidrRange)
+	}
+	return cidrs, nil
+}
+
 func validateSubnet(client API, ic *types.InstallConfig, fieldPath *field.Path, subnets []*compute.Subnetwork, name string) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -218,12 +260,12 @@ func validateSubnet(client API, ic *types.InstallConfig, fieldPath *field.Path,
 		return append(allErrs, field.Invalid(fieldPath, name, errMsg))
 	}
 
-	subnetIP, _, err := net.ParseCIDR(subnet.IpCidrRange)
+	_, subnetCIDR, err := net.ParseCIDR(subnet.IpCidrRange)
 	if err != nil {
 		return append(allErrs, field.Invalid(fieldPath, name, "unable to parse subnet CIDR"))
 	}
 
-	allErrs = append(allErrs, validateMachineNetworksContainIP(fieldPath, ic.Networking.MachineNetwork, name, subnetIP)...)
+	allErrs = append(allErrs, validateMachineNetworksContainSubnet(fieldPath, ic.Networking.MachineNetwork, name, subnetCIDR)...)
 	return allErrs
 }
 
@@ -246,6 +288,20 @@ func validateMachineNetworksContainIP(fldPath *field.Path, networks []types.Mach
 	return field.ErrorList{field.Invalid(fldPath, subnetName, fmt.Sprintf("subnet CIDR range start %s is outside of the specified machine networks", ip))}
 }
 
+// validateMachineNetworksContainSubnet checks that the whole subnet, not only
+// the start of its range, is in one of the machine networks.
+func validateMachineNetworksContainSubnet(fldPath *field.Path, networks []types.MachineNetworkEntry, subnetName string, subnet *net.IPNet) field.ErrorList {
+	if errs := validateMachineNetworksContainIP(fldPath, networks, subnetName, subnet.IP); len(errs) > 0 {
+		return errs
+	}
+	for _, network := range networks {
+		if validate.CIDRContains(&network.CIDR.IPNet, subnet) {
+			return nil
+		}
+	}
+	return field.ErrorList{field.Invalid(fldPath, subnetName, fmt.Sprintf("subnet CIDR range %s extends beyond the specified machine networks", subnet))}
+}
+
 // ValidateEnabledServices gets all the enabled services for a project and validate if any of the required services are not enabled.
 // also warns the user if optional services are not enabled.
 func ValidateEnabledServices(ctx context.Context, client API, project string) error {
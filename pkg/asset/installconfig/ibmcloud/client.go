@@ -103,6 +103,10 @@ type DNSZoneResponse struct {
 
 	// ResourceGroupID is the resource group ID of the service instance.
 	ResourceGroupID string
+
+	// NameServers are the name servers the parent zone must delegate a CIS
+	// zone to.
+	NameServers []string
 }
 
 // EncryptionKeyResponse represents an encryption key response.
@@ -387,6 +391,7 @@ func (c *Client) getCISDNSZones(ctx context.Context) ([]DNSZoneResponse, error)
 					InstanceCRN:     *instance.CRN,
 					InstanceName:    *instance.Name,
 					ResourceGroupID: *instance.ResourceGroupID,
+					NameServers:     zone.NameServers,
 				}
 				allZones = append(allZones, zoneStruct)
 			}
@@ -10,6 +10,7 @@ import (
 	"github.com/AlecAivazis/survey/v2/core"
 	"github.com/pkg/errors"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 	"github.com/openshift/installer/pkg/types"
 )
 
@@ -55,7 +56,7 @@ func GetDNSZone() (*Zone, error) {
 	sort.Strings(options)
 
 	var zoneChoice string
-	if err := survey.AskOne(&survey.Select{
+	if err := answers.AskOne(answers.BaseDomain, &survey.Select{
 		Message: "Base Domain",
 		Help:    "The base domain of the cluster. All DNS records will be sub-domains of this base and will also include the cluster name.\n\nIf you don't see your intended base-domain listed, create a new public hosted zone and rerun the installer.",
 		Options: options,
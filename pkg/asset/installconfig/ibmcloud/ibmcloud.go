@@ -9,6 +9,7 @@ import (
 	"github.com/AlecAivazis/survey/v2/core"
 	"github.com/pkg/errors"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 	"github.com/openshift/installer/pkg/types/ibmcloud"
 	"github.com/openshift/installer/pkg/types/ibmcloud/validation"
 )
@@ -52,7 +53,7 @@ func selectRegion() (string, error) {
 	defaultRegion := longRegions[0]
 
 	var selectedRegion string
-	err := survey.Ask([]*survey.Question{
+	err := answers.Ask(answers.Region, []*survey.Question{
 		{
 			Prompt: &survey.Select{
 				Message: "Region",
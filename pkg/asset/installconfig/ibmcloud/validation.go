@@ -9,6 +9,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/delegation"
 	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/ibmcloud"
 )
@@ -399,7 +400,7 @@ func ValidatePreExistingPublicDNS(client API, ic *types.InstallConfig, metadata
 	}
 
 	// Get CIS DNS record by name
-	recordName := fmt.Sprintf("api.%s", ic.ClusterDomain())
+	recordName := ic.APIDomainName()
 	records, err := client.GetDNSRecordsByName(context.TODO(), crn, zoneID, recordName)
 	if err != nil {
 		return field.InternalError(field.NewPath("baseDomain"), err)
@@ -413,6 +414,26 @@ func ValidatePreExistingPublicDNS(client API, ic *types.InstallConfig, metadata
 	return nil
 }
 
+// ValidateBaseDomainDelegation checks that the CIS zone of the base domain is
+// delegated to its name servers.
+func ValidateBaseDomainDelegation(client API, ic *types.InstallConfig) error {
+	if ic.Publish == types.InternalPublishingStrategy {
+		return nil
+	}
+
+	fldPath := field.NewPath("baseDomain")
+	zones, err := client.GetDNSZones(context.TODO(), types.ExternalPublishingStrategy)
+	if err != nil {
+		return field.InternalError(fldPath, err)
+	}
+	for _, zone := range zones {
+		if zone.Name == ic.BaseDomain {
+			return delegation.ValidateBaseDomain(context.TODO(), ic.BaseDomain, zone.NameServers, fldPath).ToAggregate()
+		}
+	}
+	return field.NotFound(fldPath, ic.BaseDomain)
+}
+
 // getMachinePoolZones will return the zones if they have been specified or return nil if the MachinePoolPlatform or values are not specified
 func getMachinePoolZones(mp types.MachinePool) []string {
 	if mp.Platform.IBMCloud == nil || mp.Platform.IBMCloud.Zones == nil {
@@ -2,8 +2,8 @@ package installconfig
 
 import (
 	"context"
+	"fmt"
 	"os"
-	"strings"
 
 	"github.com/ghodss/yaml"
 	"github.com/pkg/errors"
@@ -22,6 +22,10 @@ import (
 	icovirt "github.com/openshift/installer/pkg/asset/installconfig/ovirt"
 	icpowervs "github.com/openshift/installer/pkg/asset/installconfig/powervs"
 	icvsphere "github.com/openshift/installer/pkg/asset/installconfig/vsphere"
+	"github.com/openshift/installer/pkg/credentialsfile"
+	"github.com/openshift/installer/pkg/hostcrypt"
+	"github.com/openshift/installer/pkg/offline"
+	"github.com/openshift/installer/pkg/strictyaml"
 	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/conversion"
 	"github.com/openshift/installer/pkg/types/defaults"
@@ -41,9 +45,40 @@ type InstallConfig struct {
 	IBMCloud     *icibmcloud.Metadata   `json:"ibmcloud,omitempty"`
 	AlibabaCloud *alibabacloud.Metadata `json:"alibabacloud,omitempty"`
 	PowerVS      *icpowervs.Metadata    `json:"powervs,omitempty"`
+
+	allowUnknownFields bool
+	credentials        *credentialsfile.File
+}
+
+var (
+	_ asset.WritableAsset   = (*InstallConfig)(nil)
+	_ asset.ConfiguredAsset = (*InstallConfig)(nil)
+)
+
+// SetOptions sets whether the unknown fields of the install-config are
+// allowed, and the credentials of the platform.
+func (a *InstallConfig) SetOptions(options asset.Options) {
+	a.allowUnknownFields = options.AllowUnknownFields
+	a.credentials = options.Credentials
+	a.setCredentials()
+}
+
+// Credentials returns the cloud credentials the sessions of the platform are
+// created with, or nil for the default ones.
+func (a *InstallConfig) Credentials() *credentialsfile.File {
+	return a.credentials
 }
 
-var _ asset.WritableAsset = (*InstallConfig)(nil)
+// setCredentials sets the credentials of the metadata of the platform, which
+// is also loaded from the state file without them.
+func (a *InstallConfig) setCredentials() {
+	if a.AWS != nil {
+		a.AWS.SetCredentials(a.credentials.AWSCredentials())
+	}
+	if a.Azure != nil {
+		a.Azure.SetCredentials(a.credentials.AzureCredentials())
+	}
+}
 
 // Dependencies returns all of the dependencies directly needed by an
 // InstallConfig asset.
@@ -131,18 +166,21 @@ func (a *InstallConfig) Load(f asset.FileFetcher) (found bool, err error) {
 	}
 
 	config := &types.InstallConfig{}
-	if err := yaml.UnmarshalStrict(file.Data, config, yaml.DisallowUnknownFields); err != nil {
-		err = errors.Wrapf(err, "failed to unmarshal %s", installConfigFilename)
-		if !strings.Contains(err.Error(), "unknown field") {
-			return false, errors.Wrap(err, asset.InstallConfigError)
-		}
-		err = errors.Wrapf(err, "failed to parse first occurence of unknown field")
-		logrus.Warnf(err.Error())
-		logrus.Info("Attempting to unmarshal while ignoring unknown keys because strict unmarshaling failed")
-		if err = yaml.UnmarshalStrict(file.Data, config); err != nil {
+	if a.allowUnknownFields {
+		unknown, err := strictyaml.UnmarshalIgnoringUnknownFields(file.Data, config)
+		if err != nil {
 			err = errors.Wrapf(err, "failed to unmarshal %s", installConfigFilename)
 			return false, errors.Wrap(err, asset.InstallConfigError)
 		}
+		for _, field := range unknown {
+			logrus.Warnf("%s: %v", installConfigFilename, field)
+		}
+		if len(unknown) > 0 {
+			logrus.Warnf("The unknown fields of %s are ignored with --allow-unknown-fields, which will be removed in a future release", installConfigFilename)
+		}
+	} else if err := strictyaml.Unmarshal(file.Data, config); err != nil {
+		err = errors.Wrapf(err, "failed to unmarshal %s", installConfigFilename)
+		return false, errors.Wrap(err, asset.InstallConfigError)
 	}
 	a.Config = config
 
@@ -159,6 +197,7 @@ func (a *InstallConfig) Load(f asset.FileFetcher) (found bool, err error) {
 }
 
 func (a *InstallConfig) finish(filename string) error {
+	machineNetworkOmitted := a.Config.Networking == nil || len(a.Config.Networking.MachineNetwork) == 0
 	defaults.SetInstallConfigDefaults(a.Config)
 
 	if a.Config.AWS != nil {
@@ -176,6 +215,12 @@ func (a *InstallConfig) finish(filename string) error {
 	if a.Config.PowerVS != nil {
 		a.PowerVS = icpowervs.NewMetadata(a.Config.BaseDomain)
 	}
+	a.setCredentials()
+	if machineNetworkOmitted {
+		if err := a.setMachineNetworkFromSubnets(); err != nil {
+			return err
+		}
+	}
 
 	if err := validation.ValidateInstallConfig(a.Config).ToAggregate(); err != nil {
 		if filename == "" {
@@ -183,10 +228,21 @@ func (a *InstallConfig) finish(filename string) error {
 		}
 		return errors.Wrapf(err, "invalid %q file", filename)
 	}
+	for _, warning := range validation.ClusterNetworkWarnings(a.Config) {
+		logrus.Warn(warning)
+	}
+	if a.Config.FIPS && os.Getenv("OPENSHIFT_INSTALL_SKIP_HOSTCRYPT_VALIDATION") == "1" {
+		logrus.Warnf("OVERRIDE: host cryptography validation disabled.")
+	} else if err := hostcrypt.VerifyHostTargetState(a.Config.FIPS); err != nil {
+		return err
+	}
 
 	if err := a.platformValidation(); err != nil {
 		return err
 	}
+	if err := validateUserManagedLoadBalancer(a.Config).ToAggregate(); err != nil {
+		return err
+	}
 
 	data, err := yaml.Marshal(a.Config)
 	if err != nil {
@@ -200,6 +256,13 @@ func (a *InstallConfig) finish(filename string) error {
 }
 
 func (a *InstallConfig) platformValidation() error {
+	// The validations of these platforms query their APIs.
+	platform := a.Config.Platform
+	online := platform.AlibabaCloud != nil || platform.Azure != nil || platform.GCP != nil || platform.IBMCloud != nil ||
+		platform.AWS != nil || platform.Ovirt != nil || platform.OpenStack != nil
+	if online && offline.Skip(fmt.Sprintf("Validation of the %s platform", platform.Name())) {
+		return nil
+	}
 	if a.Config.Platform.AlibabaCloud != nil {
 		client, err := a.AlibabaCloud.Client()
 		if err != nil {
@@ -215,7 +278,7 @@ func (a *InstallConfig) platformValidation() error {
 		return icazure.Validate(client, a.Config)
 	}
 	if a.Config.Platform.GCP != nil {
-		client, err := icgcp.NewClient(context.TODO())
+		client, err := icgcp.NewClientWithCredentials(context.TODO(), a.credentials.GCPCredentials())
 		if err != nil {
 			return err
 		}
@@ -3,9 +3,12 @@ package installconfig
 import (
 	"errors"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
@@ -87,12 +90,13 @@ func TestInstallConfigGenerate_FillsInDefaults(t *testing.T) {
 
 func TestInstallConfigLoad(t *testing.T) {
 	cases := []struct {
-		name           string
-		data           string
-		fetchError     error
-		expectedFound  bool
-		expectedError  bool
-		expectedConfig *types.InstallConfig
+		name               string
+		data               string
+		fetchError         error
+		allowUnknownFields bool
+		expectedFound      bool
+		expectedError      bool
+		expectedConfig     *types.InstallConfig
 	}{
 		{
 			name: "valid InstallConfig",
@@ -183,6 +187,37 @@ metadata:
 			name: "unknown field",
 			data: `
 apiVersion: v1
+metadata:
+  name: test-cluster
+baseDomain: test-domain
+platform:
+  aws:
+    region: us-east-1
+pullSecret: "{\"auths\":{\"example.com\":{\"auth\":\"authorization value\"}}}"
+wrong_key: wrong_value
+`,
+			expectedError: true,
+		},
+		{
+			name: "field with the wrong case",
+			data: `
+apiVersion: v1
+metadata:
+  name: test-cluster
+baseDomain: test-domain
+controlplane:
+  replicas: 1
+platform:
+  aws:
+    region: us-east-1
+pullSecret: "{\"auths\":{\"example.com\":{\"auth\":\"authorization value\"}}}"
+`,
+			expectedError: true,
+		},
+		{
+			name: "unknown field allowed",
+			data: `
+apiVersion: v1
 metadata:
   name: test-cluster
 additionalTrustBundlePolicy: Proxyonly
@@ -193,7 +228,70 @@ platform:
 pullSecret: "{\"auths\":{\"example.com\":{\"auth\":\"authorization value\"}}}"
 wrong_key: wrong_value 
 `,
-			expectedFound: true,
+			allowUnknownFields: true,
+			expectedFound:      true,
+			expectedConfig: &types.InstallConfig{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: types.InstallConfigVersion,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-cluster",
+				},
+				AdditionalTrustBundlePolicy: types.PolicyProxyOnly,
+				BaseDomain:                  "test-domain",
+				Networking: &types.Networking{
+					MachineNetwork: []types.MachineNetworkEntry{
+						{CIDR: *ipnet.MustParseCIDR("10.0.0.0/16")},
+					},
+					NetworkType:    "OVNKubernetes",
+					ServiceNetwork: []ipnet.IPNet{*ipnet.MustParseCIDR("172.30.0.0/16")},
+					ClusterNetwork: []types.ClusterNetworkEntry{
+						{
+							CIDR:       *ipnet.MustParseCIDR("10.128.0.0/14"),
+							HostPrefix: 23,
+						},
+					},
+				},
+				ControlPlane: &types.MachinePool{
+					Name:           "master",
+					Replicas:       pointer.Int64Ptr(3),
+					Hyperthreading: types.HyperthreadingEnabled,
+					Architecture:   types.ArchitectureAMD64,
+				},
+				Compute: []types.MachinePool{
+					{
+						Name:           "worker",
+						Replicas:       pointer.Int64Ptr(3),
+						Hyperthreading: types.HyperthreadingEnabled,
+						Architecture:   types.ArchitectureAMD64,
+					},
+				},
+				Platform: types.Platform{
+					AWS: &aws.Platform{
+						Region: "us-east-1",
+					},
+				},
+				PullSecret: `{"auths":{"example.com":{"auth":"authorization value"}}}`,
+				Publish:    types.ExternalPublishingStrategy,
+			},
+		},
+		{
+			name: "field with the wrong case allowed",
+			data: `
+apiVersion: v1
+metadata:
+  name: test-cluster
+additionalTrustBundlePolicy: Proxyonly
+baseDomain: test-domain
+controlplane:
+  replicas: 3
+platform:
+  aws:
+    region: us-east-1
+pullSecret: "{\"auths\":{\"example.com\":{\"auth\":\"authorization value\"}}}"
+`,
+			allowUnknownFields: true,
+			expectedFound:      true,
 			expectedConfig: &types.InstallConfig{
 				TypeMeta: metav1.TypeMeta{
 					APIVersion: types.InstallConfigVersion,
@@ -497,6 +595,7 @@ pullSecret: "{\"auths\":{\"example.com\":{\"auth\":\"authorization value\"}}}"
 				)
 
 			ic := &InstallConfig{}
+			ic.SetOptions(asset.Options{AllowUnknownFields: tc.allowUnknownFields})
 			found, err := ic.Load(fileFetcher)
 			assert.Equal(t, tc.expectedFound, found, "unexpected found value returned from Load")
 			if tc.expectedError {
@@ -510,3 +609,69 @@ pullSecret: "{\"auths\":{\"example.com\":{\"auth\":\"authorization value\"}}}"
 		})
 	}
 }
+
+func TestInstallConfigLoadRejectsUnknownFields(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	fileFetcher := mock.NewMockFileFetcher(mockCtrl)
+	fileFetcher.EXPECT().FetchByName(installConfigFilename).Return(&asset.File{
+		Filename: installConfigFilename,
+		Data: []byte(`apiVersion: v1
+metadata:
+  name: test-cluster
+baseDomain: test-domain
+controlPlane:
+  replicsa: 3
+platform:
+  aws:
+    region: us-east-1
+pullSecret: "{\"auths\":{\"example.com\":{\"auth\":\"authorization value\"}}}"
+`),
+	}, nil)
+
+	found, err := (&InstallConfig{}).Load(fileFetcher)
+	assert.False(t, found)
+	assert.EqualError(t, err, asset.InstallConfigError+`: failed to unmarshal install-config.yaml: line 6, column 3: unknown field "controlPlane.replicsa", did you mean "controlPlane.replicas"?`)
+}
+
+func TestInstallConfigLoadWarnsUnknownFields(t *testing.T) {
+	hook := logrustest.NewGlobal()
+	defer hook.Reset()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	fileFetcher := mock.NewMockFileFetcher(mockCtrl)
+	fileFetcher.EXPECT().FetchByName(installConfigFilename).Return(&asset.File{
+		Filename: installConfigFilename,
+		Data: []byte(`apiVersion: v1
+metadata:
+  name: test-cluster
+baseDomain: test-domain
+controlplane:
+  replicas: 3
+platform:
+  aws:
+    region: us-east-1
+pullSecret: "{\"auths\":{\"example.com\":{\"auth\":\"authorization value\"}}}"
+wrong_key: wrong_value
+`),
+	}, nil)
+
+	ic := &InstallConfig{}
+	ic.SetOptions(asset.Options{AllowUnknownFields: true})
+	found, err := ic.Load(fileFetcher)
+	assert.True(t, found)
+	assert.NoError(t, err)
+
+	var warnings []string
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == logrus.WarnLevel && strings.Contains(entry.Message, "unknown field") {
+			warnings = append(warnings, entry.Message)
+		}
+	}
+	assert.Equal(t, []string{
+		`install-config.yaml: line 5, column 1: unknown field "controlplane", did you mean "controlPlane"?`,
+		`install-config.yaml: line 11, column 1: unknown field "wrong_key"`,
+		"The unknown fields of install-config.yaml are ignored with --allow-unknown-fields, which will be removed in a future release",
+	}, warnings)
+}
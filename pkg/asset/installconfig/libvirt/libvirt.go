@@ -5,6 +5,7 @@ import (
 	survey "github.com/AlecAivazis/survey/v2"
 	"github.com/pkg/errors"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 	"github.com/openshift/installer/pkg/types/libvirt"
 	libvirtdefaults "github.com/openshift/installer/pkg/types/libvirt/defaults"
 	"github.com/openshift/installer/pkg/validate"
@@ -13,7 +14,7 @@ import (
 // Platform collects libvirt-specific configuration.
 func Platform() (*libvirt.Platform, error) {
 	var uri string
-	err := survey.Ask([]*survey.Question{
+	err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "Libvirt Connection URI",
@@ -0,0 +1,108 @@
+package installconfig
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// Wrappers for net.LookupHost and net.DialTimeout so we can override them in
+// the tests.
+var (
+	lookupHost = func(host string) (addrs []string, err error) {
+		return net.LookupHost(host)
+	}
+	dialTimeout = func(address string, timeout time.Duration) error {
+		conn, err := net.DialTimeout("tcp", address, timeout)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+)
+
+const loadBalancerDialTimeout = 5 * time.Second
+
+// LoadBalancerFrontend is a frontend of the load balancer of a cluster: a
+// name, which the DNS resolves to the load balancer, and its ports.
+type LoadBalancerFrontend struct {
+	// Name is the name of the frontend: api, api-int or ingress.
+	Name string
+	// Host is the DNS name of the frontend. For the ingress, it is a name of
+	// the wildcard record of the apps domain.
+	Host string
+	// Ports are the TCP ports of the frontend.
+	Ports []int
+}
+
+// LoadBalancerFrontends returns the frontends the load balancer of the
+// cluster serves.
+func LoadBalancerFrontends(ic *types.InstallConfig) []LoadBalancerFrontend {
+	return []LoadBalancerFrontend{
+		{Name: "api", Host: ic.APIDomainName(), Ports: []int{6443}},
+		{Name: "api-int", Host: "api-int." + ic.ClusterDomain(), Ports: []int{6443, 22623}},
+		{Name: "ingress", Host: "test." + ic.AppsDomainName(), Ports: []int{80, 443}},
+	}
+}
+
+// LoadBalancerVIPs returns the API and ingress VIPs of the on-prem platform,
+// the addresses of its load balancer.
+func LoadBalancerVIPs(p *types.Platform) (api, ingress []string) {
+	switch {
+	case p.BareMetal != nil:
+		return p.BareMetal.APIVIPs, p.BareMetal.IngressVIPs
+	case p.OpenStack != nil:
+		return p.OpenStack.APIVIPs, p.OpenStack.IngressVIPs
+	case p.VSphere != nil:
+		return p.VSphere.APIVIPs, p.VSphere.IngressVIPs
+	case p.Nutanix != nil:
+		return p.Nutanix.APIVIPs, p.Nutanix.IngressVIPs
+	}
+	return nil, nil
+}
+
+// validateUserManagedLoadBalancer checks, for a load balancer managed by the
+// user, that the names of the frontends resolve, to the VIPs of the platform
+// when they are set. Since the load balancer may refuse the connections
+// until the nodes behind it are up, the frontends which cannot be reached are
+// only reported as warnings.
+func validateUserManagedLoadBalancer(ic *types.InstallConfig) field.ErrorList {
+	if !ic.Platform.IsLoadBalancerUserManaged() {
+		return nil
+	}
+	fldPath := field.NewPath("platform", ic.Platform.Name(), "loadBalancer")
+	apiVIPs, ingressVIPs := LoadBalancerVIPs(&ic.Platform)
+
+	allErrs := field.ErrorList{}
+	for _, frontend := range LoadBalancerFrontends(ic) {
+		addrs, err := lookupHost(frontend.Host)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath, frontend.Host, fmt.Sprintf("the %s of the user-managed load balancer does not resolve: %v", frontend.Name, err)))
+			continue
+		}
+
+		vips := apiVIPs
+		if frontend.Name == "ingress" {
+			vips = ingressVIPs
+		}
+		if len(vips) > 0 && !sets.NewString(addrs...).HasAny(vips...) {
+			allErrs = append(allErrs, field.Invalid(fldPath, frontend.Host, fmt.Sprintf("the %s of the user-managed load balancer resolves to %v, none of the VIPs %v", frontend.Name, addrs, vips)))
+			continue
+		}
+
+		for _, port := range frontend.Ports {
+			address := net.JoinHostPort(frontend.Host, strconv.Itoa(port))
+			if err := dialTimeout(address, loadBalancerDialTimeout); err != nil {
+				logrus.Warnf("The %s of the user-managed load balancer is not reachable on %s yet: %v", frontend.Name, address, err)
+			}
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,95 @@
+package installconfig
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/loadbalancer"
+	"github.com/openshift/installer/pkg/types/vsphere"
+)
+
+func TestValidateUserManagedLoadBalancer(t *testing.T) {
+	records := map[string][]string{
+		"api.test-cluster.test-domain":       {"192.168.1.10"},
+		"api-int.test-cluster.test-domain":   {"192.168.1.10"},
+		"test.apps.test-cluster.test-domain": {"192.168.1.11"},
+	}
+	cases := []struct {
+		name        string
+		lb          *loadbalancer.LoadBalancer
+		apiVIPs     []string
+		ingressVIPs []string
+		records     map[string][]string
+		expected    string
+	}{
+		{
+			name:    "default load balancer",
+			records: map[string][]string{},
+		},
+		{
+			name:    "user-managed",
+			lb:      &loadbalancer.LoadBalancer{Type: loadbalancer.UserManaged},
+			records: records,
+		},
+		{
+			name:        "user-managed with VIPs",
+			lb:          &loadbalancer.LoadBalancer{Type: loadbalancer.UserManaged},
+			apiVIPs:     []string{"192.168.1.10"},
+			ingressVIPs: []string{"192.168.1.11"},
+			records:     records,
+		},
+		{
+			name: "unresolved",
+			lb:   &loadbalancer.LoadBalancer{Type: loadbalancer.UserManaged},
+			records: map[string][]string{
+				"api.test-cluster.test-domain":       {"192.168.1.10"},
+				"test.apps.test-cluster.test-domain": {"192.168.1.11"},
+			},
+			expected: `^platform\.vsphere\.loadBalancer: Invalid value: "api-int\.test-cluster\.test-domain": the api-int of the user-managed load balancer does not resolve: no such host$`,
+		},
+		{
+			name:        "resolved to another address",
+			lb:          &loadbalancer.LoadBalancer{Type: loadbalancer.UserManaged},
+			apiVIPs:     []string{"192.168.1.10"},
+			ingressVIPs: []string{"192.168.1.12"},
+			records:     records,
+			expected:    `^platform\.vsphere\.loadBalancer: Invalid value: "test\.apps\.test-cluster\.test-domain": the ingress of the user-managed load balancer resolves to \[192\.168\.1\.11\], none of the VIPs \[192\.168\.1\.12\]$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			lookupHost = func(host string) ([]string, error) {
+				if addrs, ok := tc.records[host]; ok {
+					return addrs, nil
+				}
+				return nil, errors.New("no such host")
+			}
+			dialTimeout = func(string, time.Duration) error {
+				return errors.New("connection refused")
+			}
+
+			ic := &types.InstallConfig{
+				BaseDomain: "test-domain",
+				Platform: types.Platform{
+					VSphere: &vsphere.Platform{
+						APIVIPs:      tc.apiVIPs,
+						IngressVIPs:  tc.ingressVIPs,
+						LoadBalancer: tc.lb,
+					},
+				},
+			}
+			ic.ObjectMeta.Name = "test-cluster"
+
+			err := validateUserManagedLoadBalancer(ic).ToAggregate()
+			if tc.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package installconfig
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/aws"
+	icazure "github.com/openshift/installer/pkg/asset/installconfig/azure"
+	icgcp "github.com/openshift/installer/pkg/asset/installconfig/gcp"
+	"github.com/openshift/installer/pkg/ipnet"
+	"github.com/openshift/installer/pkg/offline"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/validate"
+)
+
+// existingSubnetCIDRs returns the CIDRs of the existing subnets the cluster
+// is installed into, or none when the installer creates the network.
+func (a *InstallConfig) existingSubnetCIDRs() ([]string, error) {
+	switch {
+	case a.Config.AWS != nil && len(a.Config.AWS.Subnets) > 0:
+		return aws.SubnetCIDRs(context.TODO(), a.AWS)
+	case a.Config.Azure != nil && a.Config.Azure.VirtualNetwork != "":
+		client, err := a.Azure.Client()
+		if err != nil {
+			return nil, err
+		}
+		return icazure.SubnetCIDRs(context.TODO(), client, a.Config.Azure)
+	case a.Config.GCP != nil && a.Config.GCP.Network != "":
+		client, err := icgcp.NewClientWithCredentials(context.TODO(), a.credentials.GCPCredentials())
+		if err != nil {
+			return nil, err
+		}
+		return icgcp.SubnetCIDRs(context.TODO(), client, a.Config)
+	}
+	return nil, nil
+}
+
+// existingSubnets returns whether the cluster is installed into existing
+// subnets, whose CIDRs existingSubnetCIDRs retrieves.
+func (a *InstallConfig) existingSubnets() bool {
+	return (a.Config.AWS != nil && len(a.Config.AWS.Subnets) > 0) ||
+		(a.Config.Azure != nil && a.Config.Azure.VirtualNetwork != "") ||
+		(a.Config.GCP != nil && a.Config.GCP.Network != "")
+}
+
+// setMachineNetworkFromSubnets sets the machine networks to the CIDRs of the
+// existing subnets the cluster is installed into. It is called when the
+// install-config omits the machine networks, before the default
+// 10.0.0.0/16, which rarely matches an existing network, is replaced.
+// Retrieving the CIDRs needs the API of the platform, so the machine networks
+// must be set in the offline mode.
+func (a *InstallConfig) setMachineNetworkFromSubnets() error {
+	if !a.existingSubnets() {
+		return nil
+	}
+	if err := offline.Require("Retrieving the CIDRs of the existing subnets"); err != nil {
+		return errors.Wrap(err, "set the machine networks of the install-config to those of the existing subnets")
+	}
+	cidrs, err := a.existingSubnetCIDRs()
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the CIDRs of the existing subnets")
+	}
+	if len(cidrs) == 0 {
+		return nil
+	}
+	networks, err := machineNetworksFromCIDRs(cidrs)
+	if err != nil {
+		return err
+	}
+	a.Config.Networking.MachineNetwork = networks
+	for _, network := range networks {
+		logrus.Infof("Using the machine network %s of the existing subnets", network.CIDR.String())
+	}
+	return nil
+}
+
+// machineNetworksFromCIDRs returns the machine networks covering the CIDRs:
+// the CIDRs themselves, sorted, less those contained in another.
+func machineNetworksFromCIDRs(cidrs []string) ([]types.MachineNetworkEntry, error) {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid subnet CIDR %q", cidr)
+		}
+		nets = append(nets, n)
+	}
+	sort.Slice(nets, func(i, j int) bool {
+		if c := bytes.Compare(nets[i].IP.To16(), nets[j].IP.To16()); c != 0 {
+			return c < 0
+		}
+		onesI, _ := nets[i].Mask.Size()
+		onesJ, _ := nets[j].Mask.Size()
+		return onesI < onesJ
+	})
+
+	var networks []types.MachineNetworkEntry
+	var last *net.IPNet
+	for _, n := range nets {
+		if last != nil && validate.CIDRContains(last, n) {
+			continue
+		}
+		networks = append(networks, types.MachineNetworkEntry{CIDR: ipnet.IPNet{IPNet: *n}})
+		last = n
+	}
+	return networks, nil
+}
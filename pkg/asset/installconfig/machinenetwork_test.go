@@ -0,0 +1,106 @@
+package installconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/ipnet"
+	"github.com/openshift/installer/pkg/offline"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/gcp"
+)
+
+func TestMachineNetworksFromCIDRs(t *testing.T) {
+	cases := []struct {
+		name     string
+		cidrs    []string
+		expected []string
+		err      string
+	}{
+		{
+			name:     "single subnet",
+			cidrs:    []string{"10.0.1.0/24"},
+			expected: []string{"10.0.1.0/24"},
+		},
+		{
+			name:     "sorted",
+			cidrs:    []string{"10.0.3.0/24", "10.0.1.0/24", "10.0.2.0/24"},
+			expected: []string{"10.0.1.0/24", "10.0.2.0/24", "10.0.3.0/24"},
+		},
+		{
+			name:     "duplicates",
+			cidrs:    []string{"10.0.1.0/24", "10.0.1.0/24"},
+			expected: []string{"10.0.1.0/24"},
+		},
+		{
+			name:     "contained subnets",
+			cidrs:    []string{"10.0.1.0/24", "10.0.0.0/16", "10.0.2.0/24", "10.1.0.0/24"},
+			expected: []string{"10.0.0.0/16", "10.1.0.0/24"},
+		},
+		{
+			name:  "invalid",
+			cidrs: []string{"10.0.1.0"},
+			err:   `^invalid subnet CIDR "10.0.1.0": invalid CIDR address: 10.0.1.0$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			networks, err := machineNetworksFromCIDRs(tc.cidrs)
+			if tc.err != "" {
+				assert.Regexp(t, tc.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			var actual []string
+			for _, network := range networks {
+				actual = append(actual, network.CIDR.String())
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestSetMachineNetworkFromSubnetsOffline(t *testing.T) {
+	offline.SetEnabled(true)
+	defer offline.SetEnabled(false)
+
+	cases := []struct {
+		name     string
+		platform types.Platform
+		err      string
+	}{
+		{
+			name:     "new network",
+			platform: types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+		},
+		{
+			name:     "existing aws subnets",
+			platform: types.Platform{AWS: &aws.Platform{Region: "us-east-1", Subnets: []string{"subnet-1"}}},
+			err:      "set the machine networks of the install-config to those of the existing subnets: Retrieving the CIDRs of the existing subnets requires the network, which is disabled by --offline",
+		},
+		{
+			name:     "existing gcp network",
+			platform: types.Platform{GCP: &gcp.Platform{Region: "us-east1", Network: "network"}},
+			err:      "set the machine networks of the install-config to those of the existing subnets: Retrieving the CIDRs of the existing subnets requires the network, which is disabled by --offline",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// the metadata of the platforms is not set, so any lookup of
+			// the subnets would panic
+			a := &InstallConfig{Config: &types.InstallConfig{
+				Platform:   tc.platform,
+				Networking: &types.Networking{MachineNetwork: []types.MachineNetworkEntry{{CIDR: *ipnet.MustParseCIDR("10.0.0.0/16")}}},
+			}}
+			err := a.setMachineNetworkFromSubnets()
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, "10.0.0.0/16", a.Config.Networking.MachineNetwork[0].CIDR.String())
+		})
+	}
+}
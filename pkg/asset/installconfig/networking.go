@@ -4,6 +4,7 @@ import (
 	survey "github.com/AlecAivazis/survey/v2"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 	"github.com/openshift/installer/pkg/types"
 )
 
@@ -30,7 +31,7 @@ func (a *networking) Generate(parents asset.Parents) error {
 func selectMachineNetworkCIDR() (string, error) {
 	var selectedCIDR string
 
-	err := survey.Ask([]*survey.Question{
+	err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "Machine Network CIDR",
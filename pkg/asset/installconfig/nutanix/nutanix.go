@@ -14,6 +14,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/defaults"
 	"github.com/openshift/installer/pkg/types/nutanix"
@@ -86,7 +87,7 @@ func Platform() (*nutanix.Platform, error) {
 // If creating the client fails, an error is returned.
 func getClients() (*PrismCentralClient, error) {
 	var prismCentral, port, username, password string
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "Prism Central",
@@ -100,7 +101,7 @@ func getClients() (*PrismCentralClient, error) {
 		return nil, errors.Wrap(err, "failed UserInput")
 	}
 
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "Port",
@@ -113,7 +114,7 @@ func getClients() (*PrismCentralClient, error) {
 		return nil, errors.Wrap(err, "failed UserInput")
 	}
 
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "Username",
@@ -125,7 +126,7 @@ func getClients() (*PrismCentralClient, error) {
 		return nil, errors.Wrap(err, "failed UserInput")
 	}
 
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Password{
 				Message: "Password",
@@ -191,7 +192,7 @@ func getPrismElement(ctx context.Context, client *nutanixclientv3.Client) (*nuta
 	}
 
 	var selectedPe string
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Select{
 				Message: "Prism Element",
@@ -250,7 +251,7 @@ func getSubnet(ctx context.Context, client *nutanixclientv3.Client, peUUID strin
 	sort.Strings(subnetChoices)
 
 	var selectedSubnet string
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Select{
 				Message: "Subnet",
@@ -278,7 +279,7 @@ func getVIPs() (string, string, error) {
 	}
 
 	//TODO: Add support to specify multiple VIPs (-> dual-stack)
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "Virtual IP Address for API",
@@ -296,7 +297,7 @@ func getVIPs() (string, string, error) {
 		return "", "", errors.Wrap(err, "failed UserInput")
 	}
 
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "Virtual IP Address for Ingress",
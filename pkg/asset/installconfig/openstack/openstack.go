@@ -9,6 +9,7 @@ import (
 	survey "github.com/AlecAivazis/survey/v2"
 	"github.com/AlecAivazis/survey/v2/core"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 	"github.com/openshift/installer/pkg/types/openstack"
 )
 
@@ -25,7 +26,7 @@ func Platform() (*openstack.Platform, error) {
 	// Sort cloudNames so we can use sort.SearchStrings
 	sort.Strings(cloudNames)
 	var cloud string
-	err = survey.Ask([]*survey.Question{
+	err = answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Select{
 				Message: "Cloud",
@@ -53,7 +54,7 @@ func Platform() (*openstack.Platform, error) {
 	networkNames = append(networkNames, noExtNet)
 	sort.Strings(networkNames)
 	var extNet string
-	err = survey.Ask([]*survey.Question{
+	err = answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Select{
 				Message: "ExternalNetwork",
@@ -85,7 +86,7 @@ func Platform() (*openstack.Platform, error) {
 			return nil, err
 		}
 		sort.Sort(floatingIPs)
-		err = survey.Ask([]*survey.Question{
+		err = answers.Ask("", []*survey.Question{
 			{
 				Prompt: &survey.Select{
 					Message:     "APIFloatingIPAddress",
@@ -112,7 +113,7 @@ func Platform() (*openstack.Platform, error) {
 	}
 	sort.Strings(flavorNames)
 	var flavor string
-	err = survey.Ask([]*survey.Question{
+	err = answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Select{
 				Message: "FlavorName",
@@ -45,6 +45,10 @@ type CloudInfo struct {
 	NetworkExtensions []extensions.Extension
 	Quotas            []quota.Quota
 
+	// MachinesNetworkMTU is the MTU of the network holding MachinesSubnet,
+	// or 0 when it is unknown.
+	MachinesNetworkMTU int
+
 	clients *clients
 }
 
@@ -183,6 +187,13 @@ func (ci *CloudInfo) collectInfo(ic *types.InstallConfig, opts *clientconfig.Cli
 		return fmt.Errorf("failed to fetch machine subnet info: %w", err)
 	}
 
+	if ci.MachinesSubnet != nil {
+		ci.MachinesNetworkMTU, err = ci.getNetworkMTU(ci.MachinesSubnet.NetworkID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch machine network MTU: %w", err)
+		}
+	}
+
 	ci.APIFIP, err = ci.getFloatingIP(ic.OpenStack.APIFloatingIP)
 	if err != nil {
 		return err
@@ -242,6 +253,18 @@ func (ci *CloudInfo) getSubnet(subnetID string) (*subnets.Subnet, error) {
 	return subnet, nil
 }
 
+// getNetworkMTU returns the MTU of the network. Neutron omits the MTU when
+// the net-mtu extension is not enabled, in which case 0 is returned.
+func (ci *CloudInfo) getNetworkMTU(networkID string) (int, error) {
+	var network struct {
+		MTU int `json:"mtu"`
+	}
+	if err := networks.Get(ci.clients.networkClient, networkID).ExtractInto(&network); err != nil {
+		return 0, err
+	}
+	return network.MTU, nil
+}
+
 func isNotFoundError(err error) bool {
 	var errNotFound gophercloud.ErrResourceNotFound
 	var pErrNotFound *gophercloud.ErrResourceNotFound
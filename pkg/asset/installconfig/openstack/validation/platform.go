@@ -1,7 +1,6 @@
 package validation
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"net"
@@ -12,6 +11,7 @@ import (
 
 	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/openstack"
+	"github.com/openshift/installer/pkg/types/validation"
 )
 
 // ValidatePlatform checks that the specified platform is valid.
@@ -51,6 +51,11 @@ func validateMachinesSubnet(p *openstack.Platform, n *types.Networking, ci *Clou
 			if n.MachineNetwork[0].CIDR.String() != ci.MachinesSubnet.CIDR {
 				allErrs = append(allErrs, field.InternalError(fldPath.Child("machinesSubnet"), fmt.Errorf("the first CIDR in machineNetwork, %s, doesn't match the CIDR of the machineSubnet, %s", n.MachineNetwork[0].CIDR.String(), ci.MachinesSubnet.CIDR)))
 			}
+			// The pod network needs room for the 100 bytes of the Geneve header
+			// inside the MTU of the provider network.
+			if c := n.OVNKubernetesConfig; c != nil && c.MTU != 0 && ci.MachinesNetworkMTU != 0 && int(c.MTU) > ci.MachinesNetworkMTU-100 {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("networking", "ovnKubernetesConfig", "mtu"), int(c.MTU), fmt.Sprintf("must not be larger than %d, the MTU of the network of the machinesSubnet less 100 bytes of overlay overhead", ci.MachinesNetworkMTU-100)))
+			}
 		}
 	}
 
@@ -92,39 +97,27 @@ func validateFloatingIPs(p *openstack.Platform, ci *CloudInfo, fldPath *field.Pa
 	return allErrs
 }
 
-// validateVIPs adds some OpenStack specific VIP validation. The universal
-// platform VIP validation is done in pkg/types/validation/installconfig.go,
-// validateAPIAndIngressVIPs().
-func validateVIPs(p *openstack.Platform, ci *CloudInfo, fldPath *field.Path) (allErrs field.ErrorList) {
+// validateVIPs adds some OpenStack specific VIP validation: the VIPs must
+// not be in the allocation pools of the machines subnet. The universal
+// platform VIP validation is done in pkg/types/validation/vips.go.
+func validateVIPs(p *openstack.Platform, ci *CloudInfo, fldPath *field.Path) field.ErrorList {
 	// If the subnet is not found in the CloudInfo object, abandon validation
-	if ci.MachinesSubnet != nil {
-		for _, allocationPool := range ci.MachinesSubnet.AllocationPools {
-			start := net.ParseIP(allocationPool.Start)
-			end := net.ParseIP(allocationPool.End)
-
-			// If the allocation pool is undefined, abandon validation
-			if start == nil || end == nil {
-				continue
-			}
-
-			for _, apiVIPString := range p.APIVIPs {
-				apiVIP := net.ParseIP(apiVIPString)
-				if bytes.Compare(start, apiVIP) <= 0 && bytes.Compare(end, apiVIP) >= 0 {
-					allErrs = append(allErrs, field.Invalid(fldPath.Child("apiVIPs"), apiVIPString, "apiVIP can not fall in a MachineNetwork allocation pool"))
-				}
+	if ci.MachinesSubnet == nil {
+		return nil
+	}
 
-			}
+	var pools []validation.IPRange
+	for _, allocationPool := range ci.MachinesSubnet.AllocationPools {
+		start := net.ParseIP(allocationPool.Start)
+		end := net.ParseIP(allocationPool.End)
 
-			for _, ingressVIPString := range p.IngressVIPs {
-				ingressVIP := net.ParseIP(ingressVIPString)
-				if bytes.Compare(start, ingressVIP) <= 0 && bytes.Compare(end, ingressVIP) >= 0 {
-					allErrs = append(allErrs, field.Invalid(fldPath.Child("ingressVIPs"), ingressVIPString, "ingressVIP can not fall in a MachineNetwork allocation pool"))
-				}
-			}
+		// If the allocation pool is undefined, abandon validation
+		if start == nil || end == nil {
+			continue
 		}
+		pools = append(pools, validation.IPRange{Name: "allocation pool of the machines subnet", Start: start, End: end})
 	}
-
-	return allErrs
+	return validation.ValidateVIPsNotInRanges(p.APIVIPs, p.IngressVIPs, pools, fldPath)
 }
 
 // validateExternalNetwork validates the user's input for the clusterOSImage and returns a list of all validation errors
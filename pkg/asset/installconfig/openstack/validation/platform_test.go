@@ -226,7 +226,7 @@ func TestOpenStackPlatformValidation(t *testing.T) {
 			)),
 			networking:     validNetworking(),
 			expectedError:  true,
-			expectedErrMsg: "platform.openstack.apiVIPs: Invalid value: \"10.0.128.10\": apiVIP can not fall in a MachineNetwork allocation pool",
+			expectedErrMsg: "platform.openstack.apiVIPs: Invalid value: \"10.0.128.10\": IP expected to be outside of the allocation pool of the machines subnet 10.0.128.8-10.0.128.255",
 		},
 		{
 			name: "ingressVIP inside subnet allocation pool",
@@ -242,7 +242,7 @@ func TestOpenStackPlatformValidation(t *testing.T) {
 			)),
 			networking:     validNetworking(),
 			expectedError:  true,
-			expectedErrMsg: "platform.openstack.ingressVIPs: Invalid value: \"10.0.128.42\": ingressVIP can not fall in a MachineNetwork allocation pool",
+			expectedErrMsg: "platform.openstack.ingressVIPs: Invalid value: \"10.0.128.42\": IP expected to be outside of the allocation pool of the machines subnet 10.0.128.8-10.0.128.255",
 		},
 	}
 
@@ -469,6 +469,30 @@ func TestMachineSubnet(t *testing.T) {
 			}(),
 			expectedErrMsg: "",
 		},
+		{
+			name: "pod network MTU too large for machine network",
+			platform: func() *openstack.Platform {
+				p := validPlatform()
+				p.MachinesSubnet = "031a5b9d-5a89-4465-8d54-3517ec2bad48"
+				return p
+			}(),
+			cloudInfo: func() *CloudInfo {
+				ci := validPlatformCloudInfo()
+				ci.MachinesSubnet = &subnets.Subnet{
+					ID:   "031a5b9d-5a89-4465-8d54-3517ec2bad48",
+					CIDR: "172.0.0.1/24",
+				}
+				ci.MachinesNetworkMTU = 1450
+				return ci
+			}(),
+			networking: func() *types.Networking {
+				n := validNetworking()
+				n.MachineNetwork = []types.MachineNetworkEntry{{CIDR: *ipnet.MustParseCIDR("172.0.0.1/24")}}
+				n.OVNKubernetesConfig = &types.OVNKubernetesConfig{MTU: 1400}
+				return n
+			}(),
+			expectedErrMsg: `networking.ovnKubernetesConfig.mtu: Invalid value: 1400: must not be larger than 1350, the MTU of the network of the machinesSubnet less 100 bytes of overlay overhead`,
+		},
 	}
 
 	for _, tc := range cases {
@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 	"github.com/openshift/installer/pkg/types/ovirt"
 )
 
@@ -39,7 +40,7 @@ func askCluster(c *ovirtsdk4.Connection, p *ovirt.Platform) (string, error) {
 			clusterNames = append(clusterNames, cluster.MustName())
 		}
 	}
-	if err := survey.AskOne(
+	if err := answers.AskOne("",
 		&survey.Select{
 			Message: "Cluster",
 			Help:    "The Cluster where the VMs will be created.",
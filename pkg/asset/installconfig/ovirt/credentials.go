@@ -14,6 +14,8 @@ import (
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 )
 
 var errHTTPNotFound = errors.New("http response 404")
@@ -49,11 +51,12 @@ func (c *clientHTTP) addTrustBundle(pemContent string, engineConfig *Config) err
 // downloadFile from specificed URL and store via filepath
 // Return error in case of failure
 func (c *clientHTTP) downloadFile() error {
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: c.skipVerify,
-			RootCAs:            c.certPool,
-		},
+	// a clone of the default transport goes through the proxy of the
+	// installer
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	tr.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: c.skipVerify,
+		RootCAs:            c.certPool,
 	}
 
 	if c.saveFilePath == "" {
@@ -90,11 +93,12 @@ func (c *clientHTTP) checkURLResponse() error {
 
 	logrus.Debugf("checking URL response... urlAddr: %s skipVerify: %s", c.urlAddr, strconv.FormatBool(c.skipVerify))
 
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: c.skipVerify,
-			RootCAs:            c.certPool,
-		},
+	// a clone of the default transport goes through the proxy of the
+	// installer
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	tr.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: c.skipVerify,
+		RootCAs:            c.certPool,
 	}
 
 	client := &http.Client{Transport: tr}
@@ -111,7 +115,7 @@ func (c *clientHTTP) checkURLResponse() error {
 // The password provided will be added in the Config struct.
 // If an error happens, it will ask again username for users.
 func askPassword(c *Config) error {
-	err := survey.Ask([]*survey.Question{
+	err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Password{
 				Message: "Engine password",
@@ -131,7 +135,7 @@ func askPassword(c *Config) error {
 // The username provided will be added in the Config struct.
 // Returns Config and error if failure.
 func askUsername(c *Config) error {
-	err := survey.Ask([]*survey.Question{
+	err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "Engine username",
@@ -152,7 +156,7 @@ func askUsername(c *Config) error {
 // requires true (Yes) or false (No) as answer
 func askQuestionTrueOrFalse(question string, helpMessage string) (bool, error) {
 	value := false
-	err := survey.AskOne(
+	err := answers.AskOne("",
 		&survey.Confirm{
 			Message: question,
 			Help:    helpMessage,
@@ -233,7 +237,7 @@ func showPEM(pemFilePath string) error {
 // or in case of failure returns error
 func askPEMFile() (string, error) {
 	bundlePEM := ""
-	err := survey.AskOne(
+	err := answers.AskOne("",
 		&survey.Multiline{
 			Message: "Certificate bundle",
 			Help:    "The certificate bundle to installer be able to communicate with oVirt API",
@@ -254,7 +258,7 @@ func engineSetup() (Config, error) {
 	engineConfig := Config{}
 	httpResource := clientHTTP{}
 
-	err := survey.Ask([]*survey.Question{
+	err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "Engine FQDN[:PORT]",
@@ -9,6 +9,7 @@ import (
 	ovirtsdk4 "github.com/ovirt/go-ovirt"
 	"github.com/pkg/errors"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/defaults"
 	"github.com/openshift/installer/pkg/types/ovirt"
@@ -34,7 +35,7 @@ func askNetwork(c *ovirtsdk4.Connection, p *ovirt.Platform) error {
 		networkByNames[network.MustName()] = network
 		networkNames = append(networkNames, network.MustName())
 	}
-	if err := survey.AskOne(
+	if err := answers.AskOne("",
 		&survey.Select{
 			Message: "Network",
 			Help:    "The Engine network of the deployed VMs. 'ovirtmgmt' is the default network. It is recommended to use a dedicated network for each OpenShift cluster.",
@@ -81,7 +82,7 @@ func askVNICProfileID(c *ovirtsdk4.Connection, p *ovirt.Platform) error {
 	}
 
 	// we have multiple vnic profile for the selected network
-	if err := survey.AskOne(
+	if err := answers.AskOne("",
 		&survey.Select{
 			Message: "VNIC Profile",
 			Help:    "The Engine VNIC profile of the VMs.",
@@ -120,7 +121,7 @@ func askVIPs(p *ovirt.Platform) error {
 		},
 	}
 
-	err := survey.Ask([]*survey.Question{
+	err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "Internal API virtual IP",
@@ -141,7 +142,7 @@ func askVIPs(p *ovirt.Platform) error {
 	}
 	p.APIVIPs = []string{apiVIP}
 
-	err = survey.Ask([]*survey.Question{
+	err = answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "Ingress virtual IP",
@@ -9,6 +9,7 @@ import (
 	ovirtsdk4 "github.com/ovirt/go-ovirt"
 	"github.com/pkg/errors"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 	"github.com/openshift/installer/pkg/types/ovirt"
 )
 
@@ -30,7 +31,7 @@ func askStorage(c *ovirtsdk4.Connection, p *ovirt.Platform, clusterName string)
 		domainsForCluster[domain.MustName()] = domain
 		domainNames = append(domainNames, domain.MustName())
 	}
-	if err := survey.AskOne(
+	if err := answers.AskOne("",
 		&survey.Select{
 			Message: "Storage domain",
 			Help:    "The storage domain will be used to create the disks of all the cluster nodes.",
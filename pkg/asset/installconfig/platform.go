@@ -10,6 +10,7 @@ import (
 
 	"github.com/openshift/installer/pkg/asset"
 	alibabacloudconfig "github.com/openshift/installer/pkg/asset/installconfig/alibabacloud"
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 	awsconfig "github.com/openshift/installer/pkg/asset/installconfig/aws"
 	azureconfig "github.com/openshift/installer/pkg/asset/installconfig/azure"
 	baremetalconfig "github.com/openshift/installer/pkg/asset/installconfig/baremetal"
@@ -133,7 +134,7 @@ func (a *platform) Name() string {
 }
 
 func (a *platform) queryUserForPlatform() (platform string, err error) {
-	err = survey.Ask([]*survey.Question{
+	err = answers.Ask(answers.Platform, []*survey.Question{
 		{
 			Prompt: &survey.Select{
 				Message: "Platform",
@@ -12,6 +12,7 @@ import (
 	openstackconfig "github.com/openshift/installer/pkg/asset/installconfig/openstack"
 	ovirtconfig "github.com/openshift/installer/pkg/asset/installconfig/ovirt"
 	powervsconfig "github.com/openshift/installer/pkg/asset/installconfig/powervs"
+	"github.com/openshift/installer/pkg/offline"
 	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/alibabacloud"
 	"github.com/openshift/installer/pkg/types/aws"
@@ -44,6 +45,9 @@ func (a *PlatformCredsCheck) Dependencies() []asset.Asset {
 
 // Generate queries for input from the user.
 func (a *PlatformCredsCheck) Generate(dependencies asset.Parents) error {
+	if offline.Skip("Platform credentials check") {
+		return nil
+	}
 	ctx := context.TODO()
 	ic := &InstallConfig{}
 	dependencies.Get(ic)
@@ -63,7 +67,7 @@ func (a *PlatformCredsCheck) Generate(dependencies asset.Parents) error {
 			return err
 		}
 	case gcp.Name:
-		client, err := gcpconfig.NewClient(context.TODO())
+		client, err := gcpconfig.NewClientWithCredentials(context.TODO(), ic.credentials.GCPCredentials())
 		if err != nil {
 			return err
 		}
@@ -101,6 +105,9 @@ func (a *PlatformCredsCheck) Generate(dependencies asset.Parents) error {
 		if azureSession.Credentials.ClientCertificatePath != "" && ic.Config.CredentialsMode != types.ManualCredentialsMode {
 			return fmt.Errorf("authentication with client certificates is only supported in manual credentials mode")
 		}
+		if azureSession.Credentials.FederatedTokenFile != "" && ic.Config.CredentialsMode != types.ManualCredentialsMode {
+			return fmt.Errorf("authentication with federated tokens is only supported in manual credentials mode")
+		}
 	case ovirt.Name:
 		con, err := ovirtconfig.NewConnection()
 		if err != nil {
@@ -10,6 +10,7 @@ import (
 	awsconfig "github.com/openshift/installer/pkg/asset/installconfig/aws"
 	gcpconfig "github.com/openshift/installer/pkg/asset/installconfig/gcp"
 	powervsconfig "github.com/openshift/installer/pkg/asset/installconfig/powervs"
+	"github.com/openshift/installer/pkg/offline"
 	"github.com/openshift/installer/pkg/types/alibabacloud"
 	"github.com/openshift/installer/pkg/types/aws"
 	"github.com/openshift/installer/pkg/types/azure"
@@ -41,6 +42,9 @@ func (a *PlatformPermsCheck) Dependencies() []asset.Asset {
 
 // Generate queries for input from the user.
 func (a *PlatformPermsCheck) Generate(dependencies asset.Parents) error {
+	if offline.Skip("Platform permissions check") {
+		return nil
+	}
 	ctx := context.TODO()
 	ic := &InstallConfig{}
 	dependencies.Get(ic)
@@ -80,7 +84,7 @@ func (a *PlatformPermsCheck) Generate(dependencies asset.Parents) error {
 			return errors.Wrap(err, "validate AWS credentials")
 		}
 	case gcp.Name:
-		client, err := gcpconfig.NewClient(context.TODO())
+		client, err := gcpconfig.NewClientWithCredentials(context.TODO(), ic.credentials.GCPCredentials())
 		if err != nil {
 			return err
 		}
@@ -16,6 +16,7 @@ import (
 	ovirtconfig "github.com/openshift/installer/pkg/asset/installconfig/ovirt"
 	powervsconfig "github.com/openshift/installer/pkg/asset/installconfig/powervs"
 	vsconfig "github.com/openshift/installer/pkg/asset/installconfig/vsphere"
+	"github.com/openshift/installer/pkg/offline"
 	"github.com/openshift/installer/pkg/types/alibabacloud"
 	"github.com/openshift/installer/pkg/types/aws"
 	"github.com/openshift/installer/pkg/types/azure"
@@ -47,6 +48,9 @@ func (a *PlatformProvisionCheck) Dependencies() []asset.Asset {
 
 // Generate queries for input from the user.
 func (a *PlatformProvisionCheck) Generate(dependencies asset.Parents) error {
+	if offline.Skip("Platform provisioning check") {
+		return nil
+	}
 	ic := &InstallConfig{}
 	dependencies.Get(ic)
 	platform := ic.Config.Platform.Name()
@@ -57,7 +61,11 @@ func (a *PlatformProvisionCheck) Generate(dependencies asset.Parents) error {
 			return err
 		}
 		client := awsconfig.NewClient(session)
-		return awsconfig.ValidateForProvisioning(client, ic.Config, ic.AWS)
+		err = awsconfig.ValidateForProvisioning(client, ic.Config, ic.AWS)
+		if err != nil {
+			return err
+		}
+		return awsconfig.ValidateBaseDomainDelegation(client, ic.Config)
 	case azure.Name:
 		dnsConfig, err := ic.Azure.DNSConfig()
 		if err != nil {
@@ -67,6 +75,10 @@ func (a *PlatformProvisionCheck) Generate(dependencies asset.Parents) error {
 		if err != nil {
 			return err
 		}
+		err = azconfig.ValidateBaseDomainDelegation(ic.Config, dnsConfig)
+		if err != nil {
+			return err
+		}
 		client, err := ic.Azure.Client()
 		if err != nil {
 			return err
@@ -86,7 +98,7 @@ func (a *PlatformProvisionCheck) Generate(dependencies asset.Parents) error {
 			return err
 		}
 	case gcp.Name:
-		client, err := gcpconfig.NewClient(context.TODO())
+		client, err := gcpconfig.NewClientWithCredentials(context.TODO(), ic.credentials.GCPCredentials())
 		if err != nil {
 			return err
 		}
@@ -94,6 +106,10 @@ func (a *PlatformProvisionCheck) Generate(dependencies asset.Parents) error {
 		if err != nil {
 			return err
 		}
+		err = gcpconfig.ValidateBaseDomainDelegation(client, ic.Config)
+		if err != nil {
+			return err
+		}
 	case ibmcloud.Name:
 		client, err := ibmcloudconfig.NewClient()
 		if err != nil {
@@ -103,6 +119,10 @@ func (a *PlatformProvisionCheck) Generate(dependencies asset.Parents) error {
 		if err != nil {
 			return err
 		}
+		err = ibmcloudconfig.ValidateBaseDomainDelegation(client, ic.Config)
+		if err != nil {
+			return err
+		}
 	case openstack.Name:
 		err := osconfig.ValidateForProvisioning(ic.Config)
 		if err != nil {
@@ -141,6 +161,10 @@ func (a *PlatformProvisionCheck) Generate(dependencies asset.Parents) error {
 		if err != nil {
 			return err
 		}
+		err = powervsconfig.ValidateBaseDomainDelegation(client, ic.Config)
+		if err != nil {
+			return err
+		}
 		err = powervsconfig.ValidateCustomVPCSetup(client, ic.Config)
 		if err != nil {
 			return err
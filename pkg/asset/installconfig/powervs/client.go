@@ -72,6 +72,10 @@ type DNSZoneResponse struct {
 
 	// ResourceGroupID is the resource group ID of the CIS instance.
 	ResourceGroupID string
+
+	// NameServers are the name servers the parent zone must delegate a CIS
+	// zone to.
+	NameServers []string
 }
 
 // DNSRecordResponse represents a DNS record response.
@@ -262,6 +266,7 @@ func (c *Client) GetDNSZones(ctx context.Context, publish types.PublishingStrate
 						InstanceCRN:     *instance.CRN,
 						InstanceName:    *instance.Name,
 						ResourceGroupID: *instance.ResourceGroupID,
+						NameServers:     zone.NameServers,
 					}
 					allZones = append(allZones, zoneStruct)
 				}
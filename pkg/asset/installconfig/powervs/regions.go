@@ -9,6 +9,7 @@ import (
 	"github.com/AlecAivazis/survey/v2/core"
 	"github.com/pkg/errors"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 	"github.com/openshift/installer/pkg/types/powervs"
 )
 
@@ -73,7 +74,7 @@ func GetRegion() (string, error) {
 
 	var region string
 
-	err := survey.Ask([]*survey.Question{
+	err := answers.Ask(answers.Region, []*survey.Question{
 		{
 			Prompt: &survey.Select{
 				Message: "Region",
@@ -114,7 +115,7 @@ func GetZone(region string) (string, error) {
 	}
 
 	var zone string
-	err := survey.Ask([]*survey.Question{
+	err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Select{
 				Message: "Zone",
@@ -25,6 +25,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/powervs"
 )
@@ -373,7 +374,7 @@ func getPISessionVarsFromUser(pisv *PISessionVars) error {
 	}
 
 	if len(pisv.ID) == 0 {
-		err = survey.Ask([]*survey.Question{
+		err = answers.Ask("", []*survey.Question{
 			{
 				Prompt: &survey.Input{
 					Message: "IBM Cloud User ID",
@@ -388,7 +389,7 @@ func getPISessionVarsFromUser(pisv *PISessionVars) error {
 	}
 
 	if len(pisv.APIKey) == 0 {
-		err = survey.Ask([]*survey.Question{
+		err = answers.Ask("", []*survey.Question{
 			{
 				Prompt: &survey.Password{
 					Message: "IBM Cloud API Key",
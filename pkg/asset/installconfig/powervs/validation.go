@@ -6,6 +6,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/delegation"
 	"github.com/openshift/installer/pkg/types"
 	powervstypes "github.com/openshift/installer/pkg/types/powervs"
 )
@@ -67,7 +68,7 @@ func validatePreExistingPublicDNS(fldPath *field.Path, client API, ic *types.Ins
 	}
 
 	// Search for existing records
-	recordNames := [...]string{fmt.Sprintf("api.%s", ic.ClusterDomain()), fmt.Sprintf("api-int.%s", ic.ClusterDomain())}
+	recordNames := [...]string{ic.APIDomainName(), fmt.Sprintf("api-int.%s", ic.ClusterDomain())}
 	for _, recordName := range recordNames {
 		records, err := client.GetDNSRecordsByName(context.TODO(), crn, zoneID, recordName, types.ExternalPublishingStrategy)
 		if err != nil {
@@ -112,6 +113,26 @@ func validatePreExistingPrivateDNS(fldPath *field.Path, client API, ic *types.In
 	return allErrs
 }
 
+// ValidateBaseDomainDelegation checks that the CIS zone of the base domain is
+// delegated to its name servers.
+func ValidateBaseDomainDelegation(client API, ic *types.InstallConfig) error {
+	if ic.Publish == types.InternalPublishingStrategy {
+		return nil
+	}
+
+	fldPath := field.NewPath("baseDomain")
+	zones, err := client.GetDNSZones(context.TODO(), types.ExternalPublishingStrategy)
+	if err != nil {
+		return field.InternalError(fldPath, err)
+	}
+	for _, zone := range zones {
+		if zone.Name == ic.BaseDomain {
+			return delegation.ValidateBaseDomain(context.TODO(), ic.BaseDomain, zone.NameServers, fldPath).ToAggregate()
+		}
+	}
+	return field.NotFound(fldPath, ic.BaseDomain)
+}
+
 // ValidateCustomVPCSetup ensures optional VPC settings, if specified, are all legit.
 func ValidateCustomVPCSetup(client API, ic *types.InstallConfig) error {
 	allErrs := field.ErrorList{}
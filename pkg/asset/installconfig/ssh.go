@@ -11,6 +11,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 	"github.com/openshift/installer/pkg/validate"
 )
 
@@ -47,6 +48,19 @@ func readSSHKey(path string) (string, error) {
 
 // Generate generates the SSH public key asset.
 func (a *sshPublicKey) Generate(asset.Parents) error {
+	if path, ok := answers.Get(answers.SSHKeyFile); ok {
+		if path == noSSHKey {
+			a.Key = ""
+			return nil
+		}
+		key, err := readSSHKey(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read SSH public key %s", path)
+		}
+		a.Key = key
+		return nil
+	}
+
 	pubKeys := map[string]string{
 		noSSHKey: "",
 	}
@@ -79,7 +93,7 @@ func (a *sshPublicKey) Generate(asset.Parents) error {
 	sort.Strings(paths)
 
 	var path string
-	if err := survey.AskOne(
+	if err := answers.AskOne(answers.SSHKeyFile,
 		&survey.Select{
 			Message: "SSH Public Key",
 			Help:    "The SSH public key used to access all nodes within the cluster. This is optional.",
@@ -437,7 +437,7 @@ func ensureLoadBalancerDNS(installConfig *types.InstallConfig, fldPath *field.Pa
 	tcpContext, cancel := context.WithTimeout(context.TODO(), tcpTimeout)
 	defer cancel()
 
-	uris = append(uris, fmt.Sprintf("api.%s", installConfig.ClusterDomain()))
+	uris = append(uris, installConfig.APIDomainName())
 	uris = append(uris, fmt.Sprintf("api-int.%s", installConfig.ClusterDomain()))
 
 	apiURIPort := fmt.Sprintf("%s:%s", uris[0], "6443")
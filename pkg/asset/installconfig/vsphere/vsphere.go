@@ -15,6 +15,7 @@ import (
 	"github.com/vmware/govmomi/vim25"
 	"k8s.io/apimachinery/pkg/util/sets"
 
+	"github.com/openshift/installer/pkg/asset/installconfig/answers"
 	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/defaults"
 	"github.com/openshift/installer/pkg/types/validation"
@@ -92,7 +93,7 @@ func Platform() (*vsphere.Platform, error) {
 func getClients() (*vCenterClient, error) {
 	var vcenter, username, password string
 
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "vCenter",
@@ -106,7 +107,7 @@ func getClients() (*vCenterClient, error) {
 		return nil, errors.Wrap(err, "failed UserInput")
 	}
 
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "Username",
@@ -118,7 +119,7 @@ func getClients() (*vCenterClient, error) {
 		return nil, errors.Wrap(err, "failed UserInput")
 	}
 
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Password{
 				Message: "Password",
@@ -185,7 +186,7 @@ func getDataCenter(ctx context.Context, finder Finder, client *vim25.Client) (st
 	sort.Strings(dataCenterChoices)
 
 	var selectedDataCenter string
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Select{
 				Message: "Datacenter",
@@ -228,7 +229,7 @@ func getCluster(ctx context.Context, path string, finder Finder, client *vim25.C
 	sort.Strings(clusterChoices)
 
 	var selectedcluster string
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Select{
 				Message: "Cluster",
@@ -269,7 +270,7 @@ func getDataStore(ctx context.Context, path string, finder Finder, client *vim25
 	sort.Strings(dataStoreChoices)
 
 	var selectedDataStore string
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Select{
 				Message: "Default Datastore",
@@ -331,7 +332,7 @@ func getNetwork(ctx context.Context, datacenter string, cluster string, finder F
 	sort.Strings(networkChoices)
 
 	var selectednetwork string
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Select{
 				Message: "Network",
@@ -358,7 +359,7 @@ func getVIPs() (string, string, error) {
 		},
 	}
 
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "Virtual IP Address for API",
@@ -376,7 +377,7 @@ func getVIPs() (string, string, error) {
 		return "", "", errors.Wrap(err, "failed UserInput")
 	}
 
-	if err := survey.Ask([]*survey.Question{
+	if err := answers.Ask("", []*survey.Question{
 		{
 			Prompt: &survey.Input{
 				Message: "Virtual IP Address for Ingress",
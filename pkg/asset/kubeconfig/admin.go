@@ -1,11 +1,13 @@
 package kubeconfig
 
 import (
+	"bytes"
 	"path/filepath"
 
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/installconfig"
 	"github.com/openshift/installer/pkg/asset/tls"
+	"github.com/openshift/installer/pkg/types"
 )
 
 var (
@@ -36,11 +38,11 @@ func (k *AdminClient) Generate(parents asset.Parents) error {
 	parents.Get(ca, clientCertKey, installConfig)
 
 	return k.kubeconfig.generate(
-		ca,
+		apiCABundle(ca, installConfig.Config),
 		clientCertKey,
 		getExtAPIServerURL(installConfig.Config),
 		installConfig.Config.GetName(),
-		"admin",
+		adminUser(installConfig.Config.GetName()),
 		kubeconfigAdminPath,
 	)
 }
@@ -54,3 +56,25 @@ func (k *AdminClient) Name() string {
 func (k *AdminClient) Load(f asset.FileFetcher) (found bool, err error) {
 	return k.load(f, kubeconfigAdminPath)
 }
+
+// caBundle is a bundle of CA certificates.
+type caBundle []byte
+
+// Cert returns the bundle.
+func (b caBundle) Cert() []byte {
+	return b
+}
+
+// apiCABundle returns the CA bundle clients trust the API with: that of the
+// cluster and, when the API serves a certificate of the install-config, the
+// CAs which issued it.
+func apiCABundle(ca tls.CertInterface, ic *types.InstallConfig) tls.CertInterface {
+	if ic.ServingCertificates == nil || ic.ServingCertificates.API == nil || ic.ServingCertificates.API.CA == "" {
+		return ca
+	}
+	bundle := append([]byte{}, ca.Cert()...)
+	if len(bundle) > 0 && !bytes.HasSuffix(bundle, []byte("\n")) {
+		bundle = append(bundle, '\n')
+	}
+	return caBundle(append(bundle, ic.ServingCertificates.API.CA...))
+}
@@ -40,7 +40,7 @@ func (k *AgentAdminClient) Generate(parents asset.Parents) error {
 		clientCertKey,
 		extAPIServerURL,
 		clusterName,
-		"admin",
+		adminUser(clusterName),
 		kubeconfigAdminPath,
 	)
 }
@@ -98,8 +98,15 @@ func (k *kubeconfig) load(f asset.FileFetcher, name string) (found bool, err err
 	return true, nil
 }
 
+// adminUser returns the name of the admin user and context in the kubeconfig
+// given to users. It includes the name of the cluster, so that kubeconfigs of
+// several clusters can be merged.
+func adminUser(cluster string) string {
+	return fmt.Sprintf("admin@%s", cluster)
+}
+
 func getExtAPIServerURL(ic *types.InstallConfig) string {
-	return fmt.Sprintf("https://api.%s:6443", ic.ClusterDomain())
+	return fmt.Sprintf("https://%s:6443", ic.APIDomainName())
 }
 
 func getIntAPIServerURL(ic *types.InstallConfig) string {
@@ -0,0 +1,59 @@
+package kubeconfig
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Merge merges the clusters, users and contexts of the kubeconfig source
+// into the kubeconfig at target, which is created when it does not exist.
+// Entries of target with the same name as entries of source are replaced.
+// The current context of source becomes the current context of target when
+// useContext is set, or when target has none.
+func Merge(source []byte, target string, useContext bool) error {
+	src, err := clientcmd.Load(source)
+	if err != nil {
+		return errors.Wrap(err, "failed to load the admin kubeconfig")
+	}
+	dst, err := clientcmd.LoadFromFile(target)
+	if os.IsNotExist(err) {
+		dst, err = clientcmdapi.NewConfig(), nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to load %s", target)
+	}
+
+	for name, cluster := range src.Clusters {
+		logMerge("cluster", name, dst.Clusters[name] != nil)
+		dst.Clusters[name] = cluster
+	}
+	for name, authInfo := range src.AuthInfos {
+		logMerge("user", name, dst.AuthInfos[name] != nil)
+		dst.AuthInfos[name] = authInfo
+	}
+	for name, context := range src.Contexts {
+		logMerge("context", name, dst.Contexts[name] != nil)
+		dst.Contexts[name] = context
+	}
+	if useContext || dst.CurrentContext == "" {
+		dst.CurrentContext = src.CurrentContext
+	}
+
+	if err := clientcmd.WriteToFile(*dst, target); err != nil {
+		return errors.Wrapf(err, "failed to write %s", target)
+	}
+	logrus.Infof("Merged the kubeconfig into %s, with current context %q", target, dst.CurrentContext)
+	return nil
+}
+
+func logMerge(kind string, name string, replaced bool) {
+	if replaced {
+		logrus.Infof("Replacing %s %q", kind, name)
+	} else {
+		logrus.Debugf("Adding %s %q", kind, name)
+	}
+}
@@ -0,0 +1,103 @@
+package kubeconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const mergeSource = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://api.new.example.com:6443
+  name: new
+contexts:
+- context:
+    cluster: new
+    user: admin@new
+  name: admin@new
+current-context: admin@new
+users:
+- name: admin@new
+  user:
+    token: new-token
+`
+
+const mergeTarget = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://api.old.example.com:6443
+  name: old
+- cluster:
+    server: https://api.stale.example.com:6443
+  name: new
+contexts:
+- context:
+    cluster: old
+    user: admin@old
+  name: admin@old
+current-context: admin@old
+users:
+- name: admin@old
+  user:
+    token: old-token
+`
+
+func TestMerge(t *testing.T) {
+	cases := []struct {
+		name            string
+		target          string
+		useContext      bool
+		expectedContext string
+		expectedUsers   []string
+	}{
+		{
+			name:            "new target",
+			expectedContext: "admin@new",
+			expectedUsers:   []string{"admin@new"},
+		},
+		{
+			name:            "existing target",
+			target:          mergeTarget,
+			expectedContext: "admin@old",
+			expectedUsers:   []string{"admin@new", "admin@old"},
+		},
+		{
+			name:            "existing target, use context",
+			target:          mergeTarget,
+			useContext:      true,
+			expectedContext: "admin@new",
+			expectedUsers:   []string{"admin@new", "admin@old"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			target := filepath.Join(dir, ".kube", "config")
+			if tc.target != "" {
+				assert.NoError(t, os.MkdirAll(filepath.Dir(target), 0700))
+				assert.NoError(t, os.WriteFile(target, []byte(tc.target), 0600))
+			}
+
+			assert.NoError(t, Merge([]byte(mergeSource), target, tc.useContext))
+
+			merged, err := clientcmd.LoadFromFile(target)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.expectedContext, merged.CurrentContext)
+			users := []string{}
+			for name := range merged.AuthInfos {
+				users = append(users, name)
+			}
+			assert.ElementsMatch(t, tc.expectedUsers, users)
+			assert.Equal(t, "https://api.new.example.com:6443", merged.Clusters["new"].Server)
+			assert.Equal(t, "new-token", merged.AuthInfos["admin@new"].Token)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package machines
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/types"
+)
+
+const (
+	// clusterAutoscalerFileName is the filename used for the ClusterAutoscaler.
+	clusterAutoscalerFileName = "99_openshift-cluster-api_cluster-autoscaler.yaml"
+
+	// workerMachineAutoscalerFileName is the format string for constructing the worker MachineAutoscaler filenames.
+	workerMachineAutoscalerFileName = "99_openshift-cluster-api_worker-machineautoscaler-%s.yaml"
+)
+
+var workerMachineAutoscalerFileNamePattern = fmt.Sprintf(workerMachineAutoscalerFileName, "*")
+
+// clusterAutoscaler is the ClusterAutoscaler of the cluster-autoscaler
+// operator. Its spec is left to the defaults of the operator.
+type clusterAutoscaler struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              struct{} `json:"spec"`
+}
+
+// machineAutoscaler is the MachineAutoscaler of the cluster-autoscaler
+// operator, which sets the range within which a MachineSet is scaled.
+type machineAutoscaler struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              machineAutoscalerSpec `json:"spec"`
+}
+
+type machineAutoscalerSpec struct {
+	MinReplicas    int32                 `json:"minReplicas"`
+	MaxReplicas    int32                 `json:"maxReplicas"`
+	ScaleTargetRef crossVersionObjectRef `json:"scaleTargetRef"`
+}
+
+type crossVersionObjectRef struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+}
+
+// clusterAutoscalerManifest returns the ClusterAutoscaler, which must exist
+// for the MachineAutoscalers to take effect.
+func clusterAutoscalerManifest() ([]byte, error) {
+	return yaml.Marshal(&clusterAutoscaler{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "autoscaling.openshift.io/v1",
+			Kind:       "ClusterAutoscaler",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "default",
+		},
+	})
+}
+
+// poolMachineAutoscalers returns the MachineAutoscalers of the MachineSets of
+// a pool. The minimum and maximum of the pool are distributed across the
+// MachineSets in the same way as the replicas. MachineSets whose share of the
+// maximum is zero are not autoscaled.
+func poolMachineAutoscalers(autoscaling *types.MachinePoolAutoscaling, machineSets []runtime.Object) ([]*machineAutoscaler, error) {
+	total := int64(len(machineSets))
+	autoscalers := make([]*machineAutoscaler, 0, len(machineSets))
+	for idx, machineSet := range machineSets {
+		accessor, err := meta.Accessor(machineSet)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the metadata of machine set %d", idx)
+		}
+		minReplicas := int32(autoscaling.MinReplicas / total)
+		if int64(idx) < autoscaling.MinReplicas%total {
+			minReplicas++
+		}
+		maxReplicas := int32(autoscaling.MaxReplicas / total)
+		if int64(idx) < autoscaling.MaxReplicas%total {
+			maxReplicas++
+		}
+		if maxReplicas == 0 {
+			continue
+		}
+		autoscalers = append(autoscalers, &machineAutoscaler{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "autoscaling.openshift.io/v1beta1",
+				Kind:       "MachineAutoscaler",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      accessor.GetName(),
+				Namespace: accessor.GetNamespace(),
+			},
+			Spec: machineAutoscalerSpec{
+				MinReplicas: minReplicas,
+				MaxReplicas: maxReplicas,
+				ScaleTargetRef: crossVersionObjectRef{
+					APIVersion: "machine.openshift.io/v1beta1",
+					Kind:       "MachineSet",
+					Name:       accessor.GetName(),
+				},
+			},
+		})
+	}
+	return autoscalers, nil
+}
+
+// autoscalerFiles returns the ClusterAutoscaler and MachineAutoscaler files,
+// or no files when no MachineSet is autoscaled.
+func autoscalerFiles(autoscalers []*machineAutoscaler) (*asset.File, []*asset.File, error) {
+	if len(autoscalers) == 0 {
+		return nil, nil, nil
+	}
+
+	data, err := clusterAutoscalerManifest()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "marshal cluster autoscaler")
+	}
+	clusterAutoscalerFile := &asset.File{
+		Filename: filepath.Join(directory, clusterAutoscalerFileName),
+		Data:     data,
+	}
+
+	machineAutoscalerFiles := make([]*asset.File, len(autoscalers))
+	padFormat := fmt.Sprintf("%%0%dd", len(fmt.Sprintf("%d", len(autoscalers))))
+	for i, autoscaler := range autoscalers {
+		data, err := yaml.Marshal(autoscaler)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "marshal worker machine autoscaler %d", i)
+		}
+		padded := fmt.Sprintf(padFormat, i)
+		machineAutoscalerFiles[i] = &asset.File{
+			Filename: filepath.Join(directory, fmt.Sprintf(workerMachineAutoscalerFileName, padded)),
+			Data:     data,
+		}
+	}
+	return clusterAutoscalerFile, machineAutoscalerFiles, nil
+}
@@ -0,0 +1,72 @@
+package machines
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestPoolMachineAutoscalers(t *testing.T) {
+	machineSets := []runtime.Object{}
+	for _, zone := range []string{"a", "b", "c"} {
+		machineSets = append(machineSets, &machinev1beta1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-infra-id-worker-us-east-1" + zone,
+				Namespace: "openshift-machine-api",
+			},
+		})
+	}
+
+	cases := []struct {
+		name        string
+		autoscaling *types.MachinePoolAutoscaling
+		expected    map[string][2]int32
+	}{
+		{
+			name:        "even",
+			autoscaling: &types.MachinePoolAutoscaling{MinReplicas: 3, MaxReplicas: 6},
+			expected: map[string][2]int32{
+				"test-infra-id-worker-us-east-1a": {1, 2},
+				"test-infra-id-worker-us-east-1b": {1, 2},
+				"test-infra-id-worker-us-east-1c": {1, 2},
+			},
+		},
+		{
+			name:        "uneven",
+			autoscaling: &types.MachinePoolAutoscaling{MinReplicas: 1, MaxReplicas: 5},
+			expected: map[string][2]int32{
+				"test-infra-id-worker-us-east-1a": {1, 2},
+				"test-infra-id-worker-us-east-1b": {0, 2},
+				"test-infra-id-worker-us-east-1c": {0, 1},
+			},
+		},
+		{
+			name:        "fewer than the machine sets",
+			autoscaling: &types.MachinePoolAutoscaling{MinReplicas: 0, MaxReplicas: 2},
+			expected: map[string][2]int32{
+				"test-infra-id-worker-us-east-1a": {0, 1},
+				"test-infra-id-worker-us-east-1b": {0, 1},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			autoscalers, err := poolMachineAutoscalers(tc.autoscaling, machineSets)
+			if !assert.NoError(t, err) {
+				return
+			}
+			actual := map[string][2]int32{}
+			for _, autoscaler := range autoscalers {
+				assert.Equal(t, "openshift-machine-api", autoscaler.Namespace)
+				assert.Equal(t, autoscaler.Name, autoscaler.Spec.ScaleTargetRef.Name)
+				actual[autoscaler.Name] = [2]int32{autoscaler.Spec.MinReplicas, autoscaler.Spec.MaxReplicas}
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
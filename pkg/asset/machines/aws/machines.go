@@ -252,3 +252,29 @@ func ConfigMasters(machines []machineapi.Machine, controlPlane *machinev1.Contro
 	providerSpec := controlPlane.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value.Object.(*machineapi.AWSMachineProviderConfig)
 	providerSpec.LoadBalancers = lbrefs
 }
+
+// EtcdDiskDevice is the device of the dedicated etcd disk. The udev rules of
+// RHCOS link the NVMe device of an EBS volume to the device name it is
+// attached with.
+const EtcdDiskDevice = "/dev/xvdb"
+
+// ConfigEtcdDisk attaches the dedicated etcd disk to the given machines,
+// encrypted like their root volume.
+func ConfigEtcdDisk(machines []machineapi.Machine, controlPlane *machinev1.ControlPlaneMachineSet, disk *types.EtcdDisk) {
+	add := func(providerSpec *machineapi.AWSMachineProviderConfig) {
+		root := providerSpec.BlockDevices[0].EBS
+		providerSpec.BlockDevices = append(providerSpec.BlockDevices, machineapi.BlockDeviceMappingSpec{
+			DeviceName: pointer.StringPtr(EtcdDiskDevice),
+			EBS: &machineapi.EBSBlockDeviceSpec{
+				VolumeType: pointer.StringPtr(disk.Type),
+				VolumeSize: pointer.Int64Ptr(disk.SizeGB),
+				Encrypted:  root.Encrypted,
+				KMSKey:     root.KMSKey,
+			},
+		})
+	}
+	for _, machine := range machines {
+		add(machine.Spec.ProviderSpec.Value.Object.(*machineapi.AWSMachineProviderConfig))
+	}
+	add(controlPlane.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value.Object.(*machineapi.AWSMachineProviderConfig))
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
 
 	machineapi "github.com/openshift/api/machine/v1beta1"
 	"github.com/openshift/installer/pkg/types"
@@ -97,3 +98,30 @@ func MachineSets(clusterID string, region string, subnets map[string]string, poo
 
 	return machinesets, nil
 }
+
+// ContainerStorageDiskDevice is the device of the dedicated container storage
+// disk of the compute machines, which is attached with the device name of the
+// etcd disk of the control plane machines.
+const ContainerStorageDiskDevice = EtcdDiskDevice
+
+// ConfigContainerStorageDisk attaches the dedicated container storage disk to
+// the machines of the given MachineSets, encrypted like their root volume.
+func ConfigContainerStorageDisk(machineSets []*machineapi.MachineSet, storage *types.ContainerStorage) error {
+	for _, machineSet := range machineSets {
+		providerSpec, ok := machineSet.Spec.Template.Spec.ProviderSpec.Value.Object.(*machineapi.AWSMachineProviderConfig)
+		if !ok {
+			return errors.Errorf("unable to add the container storage disk to machine set %s", machineSet.Name)
+		}
+		root := providerSpec.BlockDevices[0].EBS
+		providerSpec.BlockDevices = append(providerSpec.BlockDevices, machineapi.BlockDeviceMappingSpec{
+			DeviceName: pointer.StringPtr(ContainerStorageDiskDevice),
+			EBS: &machineapi.EBSBlockDeviceSpec{
+				VolumeType: pointer.StringPtr(storage.Type),
+				VolumeSize: pointer.Int64Ptr(storage.SizeGB),
+				Encrypted:  root.Encrypted,
+				KMSKey:     root.KMSKey,
+			},
+		})
+	}
+	return nil
+}
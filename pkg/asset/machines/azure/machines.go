@@ -293,3 +293,31 @@ func getNetworkInfo(platform *azure.Platform, clusterID, role string) (string, s
 func getVMNetworkingType(value string) bool {
 	return value == string(azure.VMnetworkingTypeAccelerated)
 }
+
+// EtcdDiskDevice is the device of the dedicated etcd disk, which is attached
+// as the data disk of LUN 0.
+const EtcdDiskDevice = "/dev/disk/azure/scsi1/lun0"
+
+// ConfigEtcdDisk attaches the dedicated etcd disk to the given machines.
+func ConfigEtcdDisk(machines []machineapi.Machine, controlPlane *machinev1.ControlPlaneMachineSet, disk *types.EtcdDisk) error {
+	dataDisk := machineapi.DataDisk{
+		NameSuffix: "etcd",
+		DiskSizeGB: int32(disk.SizeGB),
+		ManagedDisk: machineapi.DataDiskManagedDiskParameters{
+			StorageAccountType: machineapi.StorageAccountType(disk.Type),
+		},
+		Lun:            0,
+		CachingType:    machineapi.CachingTypeNone,
+		DeletionPolicy: machineapi.DiskDeletionPolicyTypeDelete,
+	}
+	for _, machine := range machines {
+		providerSpec := machine.Spec.ProviderSpec.Value.Object.(*machineapi.AzureMachineProviderSpec)
+		providerSpec.DataDisks = append(providerSpec.DataDisks, dataDisk)
+	}
+	providerSpec, ok := controlPlane.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value.Object.(*machineapi.AzureMachineProviderSpec)
+	if !ok {
+		return errors.New("Unable to add the etcd disk to control plane machine set")
+	}
+	providerSpec.DataDisks = append(providerSpec.DataDisks, dataDisk)
+	return nil
+}
@@ -90,3 +90,30 @@ func MachineSets(clusterID string, config *types.InstallConfig, pool *types.Mach
 	}
 	return machinesets, nil
 }
+
+// ContainerStorageDiskDevice is the device of the dedicated container storage
+// disk of the compute machines, which is attached as the data disk of LUN 0.
+const ContainerStorageDiskDevice = EtcdDiskDevice
+
+// ConfigContainerStorageDisk attaches the dedicated container storage disk to
+// the machines of the given MachineSets.
+func ConfigContainerStorageDisk(machineSets []*clusterapi.MachineSet, storage *types.ContainerStorage) error {
+	dataDisk := clusterapi.DataDisk{
+		NameSuffix: "containers",
+		DiskSizeGB: int32(storage.SizeGB),
+		ManagedDisk: clusterapi.DataDiskManagedDiskParameters{
+			StorageAccountType: clusterapi.StorageAccountType(storage.Type),
+		},
+		Lun:            0,
+		CachingType:    clusterapi.CachingTypeNone,
+		DeletionPolicy: clusterapi.DiskDeletionPolicyTypeDelete,
+	}
+	for _, machineSet := range machineSets {
+		providerSpec, ok := machineSet.Spec.Template.Spec.ProviderSpec.Value.Object.(*clusterapi.AzureMachineProviderSpec)
+		if !ok {
+			return errors.Errorf("unable to add the container storage disk to machine set %s", machineSet.Name)
+		}
+		providerSpec.DataDisks = append(providerSpec.DataDisks, dataDisk)
+	}
+	return nil
+}
@@ -16,12 +16,15 @@ import (
 	machinev1 "github.com/openshift/api/machine/v1"
 	machineapi "github.com/openshift/api/machine/v1beta1"
 	gcpconfig "github.com/openshift/installer/pkg/asset/installconfig/gcp"
+	"github.com/openshift/installer/pkg/credentialsfile"
 	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/gcp"
 )
 
-// Machines returns a list of machines for a machinepool.
-func Machines(clusterID string, config *types.InstallConfig, pool *types.MachinePool, osImage, role, userDataSecret string) ([]machineapi.Machine, *machinev1.ControlPlaneMachineSet, error) {
+// Machines returns a list of machines for a machinepool. The credentials,
+// or the default ones when they are nil, are those of the service account of
+// the machines in the passthrough credentials mode of a shared VPC.
+func Machines(clusterID string, config *types.InstallConfig, credentials *credentialsfile.GCP, pool *types.MachinePool, osImage, role, userDataSecret string) ([]machineapi.Machine, *machinev1.ControlPlaneMachineSet, error) {
 	if configPlatform := config.Platform.Name(); configPlatform != gcp.Name {
 		return nil, nil, fmt.Errorf("non-GCP configuration: %q", configPlatform)
 	}
@@ -43,7 +46,7 @@ func Machines(clusterID string, config *types.InstallConfig, pool *types.Machine
 	machineSetProvider := &machineapi.GCPMachineProviderSpec{}
 	for idx := int64(0); idx < total; idx++ {
 		azIndex := int(idx) % len(azs)
-		provider, err := provider(clusterID, platform, mpool, osImage, azIndex, role, userDataSecret, credentialsMode)
+		provider, err := provider(clusterID, platform, mpool, osImage, azIndex, role, userDataSecret, credentialsMode, credentials)
 		if err != nil {
 			return nil, nil, errors.Wrap(err, "failed to create provider")
 		}
@@ -130,7 +133,7 @@ func Machines(clusterID string, config *types.InstallConfig, pool *types.Machine
 	return machines, controlPlaneMachineSet, nil
 }
 
-func provider(clusterID string, platform *gcp.Platform, mpool *gcp.MachinePool, osImage string, azIdx int, role, userDataSecret string, credentialsMode types.CredentialsMode) (*machineapi.GCPMachineProviderSpec, error) {
+func provider(clusterID string, platform *gcp.Platform, mpool *gcp.MachinePool, osImage string, azIdx int, role, userDataSecret string, credentialsMode types.CredentialsMode, credentials *credentialsfile.GCP) (*machineapi.GCPMachineProviderSpec, error) {
 	az := mpool.Zones[azIdx]
 	if len(platform.Licenses) > 0 {
 		osImage = fmt.Sprintf("%s-rhcos-image", clusterID)
@@ -157,7 +160,7 @@ func provider(clusterID string, platform *gcp.Platform, mpool *gcp.MachinePool,
 	instanceServiceAccount := fmt.Sprintf("%s-%s@%s.iam.gserviceaccount.com", clusterID, role[0:1], platform.ProjectID)
 	// Passthrough service accounts are only needed for GCP XPN.
 	if len(platform.NetworkProjectID) > 0 && credentialsMode == types.PassthroughCredentialsMode {
-		sess, err := gcpconfig.GetSession(context.TODO())
+		sess, err := gcpconfig.GetSessionWithCredentials(context.TODO(), credentials)
 		if err != nil {
 			return nil, err
 		}
@@ -229,6 +232,33 @@ func ConfigMasters(machines []machineapi.Machine, controlPlane *machinev1.Contro
 	providerSpec.TargetPools = targetPools
 	return nil
 }
+
+// EtcdDiskDevice is the device of the dedicated etcd disk. GCP names the
+// first disk attached after the boot disk persistent-disk-1.
+const EtcdDiskDevice = "/dev/disk/by-id/google-persistent-disk-1"
+
+// ConfigEtcdDisk attaches the dedicated etcd disk to the given machines,
+// encrypted like their boot disk.
+func ConfigEtcdDisk(machines []machineapi.Machine, controlPlane *machinev1.ControlPlaneMachineSet, disk *types.EtcdDisk) error {
+	add := func(providerSpec *machineapi.GCPMachineProviderSpec) {
+		providerSpec.Disks = append(providerSpec.Disks, &machineapi.GCPDisk{
+			AutoDelete:    true,
+			SizeGB:        disk.SizeGB,
+			Type:          disk.Type,
+			EncryptionKey: providerSpec.Disks[0].EncryptionKey,
+		})
+	}
+	for _, machine := range machines {
+		add(machine.Spec.ProviderSpec.Value.Object.(*machineapi.GCPMachineProviderSpec))
+	}
+	providerSpec, ok := controlPlane.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value.Object.(*machineapi.GCPMachineProviderSpec)
+	if !ok {
+		return errors.New("Unable to add the etcd disk to control plane machine set")
+	}
+	add(providerSpec)
+	return nil
+}
+
 func getNetworks(platform *gcp.Platform, clusterID, role string) (string, string, error) {
 	if platform.Network == "" {
 		return fmt.Sprintf("%s-network", clusterID), fmt.Sprintf("%s-%s-subnet", clusterID, role), nil
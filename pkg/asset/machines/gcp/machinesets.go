@@ -10,12 +10,14 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	machineapi "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/installer/pkg/credentialsfile"
 	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/gcp"
 )
 
-// MachineSets returns a list of machinesets for a machinepool.
-func MachineSets(clusterID string, config *types.InstallConfig, pool *types.MachinePool, osImage, role, userDataSecret string) ([]*machineapi.MachineSet, error) {
+// MachineSets returns a list of machinesets for a machinepool. The
+// credentials are those of Machines.
+func MachineSets(clusterID string, config *types.InstallConfig, credentials *credentialsfile.GCP, pool *types.MachinePool, osImage, role, userDataSecret string) ([]*machineapi.MachineSet, error) {
 	if configPlatform := config.Platform.Name(); configPlatform != gcp.Name {
 		return nil, fmt.Errorf("non-GCP configuration: %q", configPlatform)
 	}
@@ -40,7 +42,7 @@ func MachineSets(clusterID string, config *types.InstallConfig, pool *types.Mach
 			replicas++
 		}
 
-		provider, err := provider(clusterID, platform, mpool, osImage, idx, role, userDataSecret, credentialsMode)
+		provider, err := provider(clusterID, platform, mpool, osImage, idx, role, userDataSecret, credentialsMode, credentials)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to create provider")
 		}
@@ -88,3 +90,25 @@ func MachineSets(clusterID string, config *types.InstallConfig, pool *types.Mach
 
 	return machinesets, nil
 }
+
+// ContainerStorageDiskDevice is the device of the dedicated container storage
+// disk of the compute machines, the first disk attached after the boot disk.
+const ContainerStorageDiskDevice = EtcdDiskDevice
+
+// ConfigContainerStorageDisk attaches the dedicated container storage disk to
+// the machines of the given MachineSets, encrypted like their boot disk.
+func ConfigContainerStorageDisk(machineSets []*machineapi.MachineSet, storage *types.ContainerStorage) error {
+	for _, machineSet := range machineSets {
+		providerSpec, ok := machineSet.Spec.Template.Spec.ProviderSpec.Value.Object.(*machineapi.GCPMachineProviderSpec)
+		if !ok {
+			return errors.Errorf("unable to add the container storage disk to machine set %s", machineSet.Name)
+		}
+		providerSpec.Disks = append(providerSpec.Disks, &machineapi.GCPDisk{
+			AutoDelete:    true,
+			SizeGB:        storage.SizeGB,
+			Type:          storage.Type,
+			EncryptionKey: providerSpec.Disks[0].EncryptionKey,
+		})
+	}
+	return nil
+}
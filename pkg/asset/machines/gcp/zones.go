@@ -11,14 +11,16 @@ import (
 	"google.golang.org/api/option"
 
 	gcpconfig "github.com/openshift/installer/pkg/asset/installconfig/gcp"
+	"github.com/openshift/installer/pkg/credentialsfile"
 )
 
-// AvailabilityZones retrieves a list of availability zones for the given project and region.
-func AvailabilityZones(project, region string) ([]string, error) {
+// AvailabilityZones retrieves a list of availability zones for the given project and region,
+// with the credentials or the default ones when they are nil.
+func AvailabilityZones(credentials *credentialsfile.GCP, project, region string) ([]string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
 	defer cancel()
 
-	ssn, err := gcpconfig.GetSession(ctx)
+	ssn, err := gcpconfig.GetSessionWithCredentials(ctx, credentials)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get session")
 	}
@@ -10,15 +10,20 @@ import (
 	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 )
 
-// ForAuthorizedKeys creates the MachineConfig to set the authorized key for `core` user.
-func ForAuthorizedKeys(key string, role string) (*mcfgv1.MachineConfig, error) {
+// ForAuthorizedKeys creates the MachineConfig to set the authorized keys for `core` user.
+func ForAuthorizedKeys(keys []string, role string) (*mcfgv1.MachineConfig, error) {
+	authorizedKeys := make([]igntypes.SSHAuthorizedKey, 0, len(keys))
+	for _, key := range keys {
+		authorizedKeys = append(authorizedKeys, igntypes.SSHAuthorizedKey(key))
+	}
+
 	ignConfig := igntypes.Config{
 		Ignition: igntypes.Ignition{
 			Version: igntypes.MaxVersion.String(),
 		},
 		Passwd: igntypes.Passwd{
 			Users: []igntypes.PasswdUser{{
-				Name: "core", SSHAuthorizedKeys: []igntypes.SSHAuthorizedKey{igntypes.SSHAuthorizedKey(key)},
+				Name: "core", SSHAuthorizedKeys: authorizedKeys,
 			}},
 		},
 	}
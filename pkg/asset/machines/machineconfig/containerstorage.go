@@ -0,0 +1,101 @@
+package machineconfig
+
+import (
+	"fmt"
+	"strings"
+
+	ignutil "github.com/coreos/ignition/v2/config/util"
+	igntypes "github.com/coreos/ignition/v2/config/v3_2/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/asset/ignition"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+)
+
+const containerStorageMountUnit = `[Unit]
+Description=Mount the container storage disk to %[1]s
+Before=local-fs.target
+
+[Mount]
+What=/dev/disk/by-label/%[2]s
+Where=%[1]s
+Type=xfs
+Options=defaults,prjquota
+
+[Install]
+RequiredBy=local-fs.target
+`
+
+const containerStorageRestoreconUnit = `[Unit]
+Description=Restore the SELinux context of %[1]s
+Requires=%[2]s
+After=%[2]s
+Before=crio.service kubelet.service
+
+[Service]
+Type=oneshot
+ExecStart=/sbin/restorecon -R %[1]s
+RemainAfterExit=yes
+
+[Install]
+WantedBy=multi-user.target
+`
+
+// ForContainerStorage creates the MachineConfig to format the dedicated
+// container storage disk on the device and mount it to the mount path. The
+// filesystem is also mounted by Ignition, so that the files Ignition writes
+// to the mount path on the first boot are placed on the disk.
+func ForContainerStorage(device string, mountPath string, role string) (*mcfgv1.MachineConfig, error) {
+	label := mountPath[strings.LastIndex(mountPath, "/")+1:]
+	unitName := strings.ReplaceAll(strings.TrimPrefix(mountPath, "/"), "/", "-")
+	ignConfig := igntypes.Config{
+		Ignition: igntypes.Ignition{
+			Version: igntypes.MaxVersion.String(),
+		},
+		Storage: igntypes.Storage{
+			Filesystems: []igntypes.Filesystem{{
+				Device:         device,
+				Format:         ignutil.StrToPtr("xfs"),
+				Label:          ignutil.StrToPtr(label),
+				Path:           ignutil.StrToPtr(mountPath),
+				MountOptions:   []igntypes.MountOption{"defaults", "prjquota"},
+				WipeFilesystem: ignutil.BoolToPtr(true),
+			}},
+		},
+		Systemd: igntypes.Systemd{
+			Units: []igntypes.Unit{
+				{
+					Name:     unitName + ".mount",
+					Enabled:  ignutil.BoolToPtr(true),
+					Contents: ignutil.StrToPtr(fmt.Sprintf(containerStorageMountUnit, mountPath, label)),
+				},
+				{
+					Name:     fmt.Sprintf("restorecon-%s.service", unitName),
+					Enabled:  ignutil.BoolToPtr(true),
+					Contents: ignutil.StrToPtr(fmt.Sprintf(containerStorageRestoreconUnit, mountPath, unitName+".mount")),
+				},
+			},
+		},
+	}
+
+	rawExt, err := ignition.ConvertToRawExtension(ignConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mcfgv1.MachineConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: mcfgv1.SchemeGroupVersion.String(),
+			Kind:       "MachineConfig",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("99-%s-container-storage", role),
+			Labels: map[string]string{
+				"machineconfiguration.openshift.io/role": role,
+			},
+		},
+		Spec: mcfgv1.MachineConfigSpec{
+			Config: rawExt,
+		},
+	}, nil
+}
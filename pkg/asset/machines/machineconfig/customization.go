@@ -0,0 +1,68 @@
+package machineconfig
+
+import (
+	"encoding/json"
+	"fmt"
+
+	ignutil "github.com/coreos/ignition/v2/config/util"
+	igntypes "github.com/coreos/ignition/v2/config/v3_2/types"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/asset/ignition"
+	"github.com/openshift/installer/pkg/types"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+)
+
+// ForCustomization creates the MachineConfig adding the files, units and
+// kernel arguments of the machine pool's machineConfig.
+func ForCustomization(c *types.MachineConfigCustomization, role string) (*mcfgv1.MachineConfig, error) {
+	ignConfig := igntypes.Config{}
+	if c.Ignition != "" {
+		if err := json.Unmarshal([]byte(c.Ignition), &ignConfig); err != nil {
+			return nil, errors.Wrap(err, "failed to parse the Ignition config")
+		}
+	}
+	// Ignition configs of earlier 3.x versions are valid 3.2 configs.
+	ignConfig.Ignition.Version = igntypes.MaxVersion.String()
+
+	for _, f := range c.Files {
+		mode := 0644
+		if f.Mode != nil {
+			mode = *f.Mode
+		}
+		ignConfig.Storage.Files = append(ignConfig.Storage.Files, ignition.FileFromString(f.Path, "root", mode, f.Contents))
+	}
+	for _, u := range c.Units {
+		unit := igntypes.Unit{
+			Name:     u.Name,
+			Contents: ignutil.StrToPtr(u.Contents),
+		}
+		if u.Enabled {
+			unit.Enabled = ignutil.BoolToPtr(true)
+		}
+		ignConfig.Systemd.Units = append(ignConfig.Systemd.Units, unit)
+	}
+
+	rawExt, err := ignition.ConvertToRawExtension(ignConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mcfgv1.MachineConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: mcfgv1.SchemeGroupVersion.String(),
+			Kind:       "MachineConfig",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("99-%s-custom", role),
+			Labels: map[string]string{
+				"machineconfiguration.openshift.io/role": role,
+			},
+		},
+		Spec: mcfgv1.MachineConfigSpec{
+			Config:          rawExt,
+			KernelArguments: c.KernelArguments,
+		},
+	}, nil
+}
@@ -0,0 +1,158 @@
+package machineconfig
+
+import (
+	"fmt"
+
+	ignutil "github.com/coreos/ignition/v2/config/util"
+	igntypes "github.com/coreos/ignition/v2/config/v3_2/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/asset/ignition"
+	"github.com/openshift/installer/pkg/types"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+)
+
+// Partition type GUIDs of the firmware partitions created on each mirrored
+// disk.
+const (
+	biosBootTypeGUID = "21686148-6449-6E6F-744E-656564454649"
+	espTypeGUID      = "C12A7328-F81F-11D2-BA4B-00A0C93EC93B"
+	prepTypeGUID     = "9E1A2D38-C612-4316-AA26-8B49521E5A8B"
+)
+
+// ForDiskSetup creates the MachineConfig to encrypt and/or mirror the root
+// disk, following the layout Butane generates for its boot_device section.
+// See https://coreos.github.io/butane/config-openshift-v4_12/
+func ForDiskSetup(pool *types.MachinePool, role string) (*mcfgv1.MachineConfig, error) {
+	storage := igntypes.Storage{}
+	rootDevice := "/dev/disk/by-partlabel/root"
+
+	if m := pool.DiskMirroring; m != nil {
+		var bootDevices, rootDevices []igntypes.Device
+		for i, device := range m.Devices {
+			n := i + 1
+			disk := igntypes.Disk{Device: device, WipeTable: ignutil.BoolToPtr(true)}
+			for _, p := range firmwarePartitions(pool.Architecture) {
+				disk.Partitions = append(disk.Partitions, igntypes.Partition{
+					Label:    ignutil.StrToPtr(fmt.Sprintf("%s-%d", p.label, n)),
+					SizeMiB:  ignutil.IntToPtr(p.sizeMiB),
+					TypeGUID: ignutil.StrToPtr(p.typeGUID),
+				})
+				if p.label == "esp" {
+					label := fmt.Sprintf("esp-%d", n)
+					storage.Filesystems = append(storage.Filesystems, igntypes.Filesystem{
+						Device:         "/dev/disk/by-partlabel/" + label,
+						Format:         ignutil.StrToPtr("vfat"),
+						Label:          ignutil.StrToPtr(label),
+						WipeFilesystem: ignutil.BoolToPtr(true),
+					})
+				}
+			}
+			disk.Partitions = append(disk.Partitions,
+				igntypes.Partition{Label: ignutil.StrToPtr(fmt.Sprintf("boot-%d", n)), SizeMiB: ignutil.IntToPtr(384)},
+				igntypes.Partition{Label: ignutil.StrToPtr(fmt.Sprintf("root-%d", n))},
+			)
+			storage.Disks = append(storage.Disks, disk)
+			bootDevices = append(bootDevices, igntypes.Device(fmt.Sprintf("/dev/disk/by-partlabel/boot-%d", n)))
+			rootDevices = append(rootDevices, igntypes.Device(fmt.Sprintf("/dev/disk/by-partlabel/root-%d", n)))
+		}
+		storage.Raid = []igntypes.Raid{
+			{Name: "md-boot", Level: "raid1", Devices: bootDevices, Options: []igntypes.RaidOption{"--metadata=1.0"}},
+			{Name: "md-root", Level: "raid1", Devices: rootDevices},
+		}
+		rootDevice = "/dev/md/md-root"
+		storage.Filesystems = append(storage.Filesystems, igntypes.Filesystem{
+			Device:         "/dev/md/md-boot",
+			Format:         ignutil.StrToPtr("ext4"),
+			Label:          ignutil.StrToPtr("boot"),
+			WipeFilesystem: ignutil.BoolToPtr(true),
+		})
+	}
+
+	var kernelArguments []string
+	if e := pool.DiskEncryption; e != nil {
+		clevis := &igntypes.Clevis{Threshold: ignutil.IntToPtr(e.Threshold)}
+		if e.TPM2 {
+			clevis.Tpm2 = ignutil.BoolToPtr(true)
+		}
+		for _, tang := range e.Tang {
+			clevis.Tang = append(clevis.Tang, igntypes.Tang{URL: tang.URL, Thumbprint: ignutil.StrToPtr(tang.Thumbprint)})
+		}
+		if len(e.Tang) > 0 {
+			// the Tang servers are contacted from the initramfs
+			kernelArguments = append(kernelArguments, "rd.neednet=1")
+		}
+		storage.Luks = []igntypes.Luks{{
+			Name:       "root",
+			Device:     ignutil.StrToPtr(rootDevice),
+			Label:      ignutil.StrToPtr("luks-root"),
+			Clevis:     clevis,
+			WipeVolume: ignutil.BoolToPtr(true),
+		}}
+		rootDevice = "/dev/mapper/root"
+	}
+	storage.Filesystems = append(storage.Filesystems, igntypes.Filesystem{
+		Device:         rootDevice,
+		Format:         ignutil.StrToPtr("xfs"),
+		Label:          ignutil.StrToPtr("root"),
+		WipeFilesystem: ignutil.BoolToPtr(true),
+	})
+
+	ignConfig := igntypes.Config{
+		Ignition: igntypes.Ignition{
+			Version: igntypes.MaxVersion.String(),
+		},
+		Storage: storage,
+	}
+
+	rawExt, err := ignition.ConvertToRawExtension(ignConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mcfgv1.MachineConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: mcfgv1.SchemeGroupVersion.String(),
+			Kind:       "MachineConfig",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("99-%s-disk-setup", role),
+			Labels: map[string]string{
+				"machineconfiguration.openshift.io/role": role,
+			},
+		},
+		Spec: mcfgv1.MachineConfigSpec{
+			Config:          rawExt,
+			KernelArguments: kernelArguments,
+		},
+	}, nil
+}
+
+type firmwarePartition struct {
+	label    string
+	sizeMiB  int
+	typeGUID string
+}
+
+// firmwarePartitions returns the partitions the firmware of the architecture
+// boots from, which are created on each mirrored disk ahead of the boot and
+// root partitions.
+func firmwarePartitions(arch types.Architecture) []firmwarePartition {
+	switch arch {
+	case types.ArchitectureAMD64:
+		return []firmwarePartition{
+			{label: "bios", sizeMiB: 1, typeGUID: biosBootTypeGUID},
+			{label: "esp", sizeMiB: 127, typeGUID: espTypeGUID},
+		}
+	case types.ArchitectureARM64:
+		return []firmwarePartition{
+			{label: "esp", sizeMiB: 127, typeGUID: espTypeGUID},
+		}
+	case types.ArchitecturePPC64LE:
+		return []firmwarePartition{
+			{label: "prep", sizeMiB: 4, typeGUID: prepTypeGUID},
+		}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,86 @@
+package machineconfig
+
+import (
+	"fmt"
+
+	ignutil "github.com/coreos/ignition/v2/config/util"
+	igntypes "github.com/coreos/ignition/v2/config/v3_2/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/asset/ignition"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+)
+
+const etcdMountUnit = `[Unit]
+Description=Mount the etcd disk to /var/lib/etcd
+Before=local-fs.target
+
+[Mount]
+What=/dev/disk/by-label/etcd
+Where=/var/lib/etcd
+Type=xfs
+Options=defaults,prjquota
+
+[Install]
+WantedBy=local-fs.target
+`
+
+const etcdRestoreconUnit = `[Unit]
+Description=Restore the SELinux context of /var/lib/etcd
+Requires=var-lib-etcd.mount
+After=var-lib-etcd.mount
+Before=kubelet.service
+
+[Service]
+Type=oneshot
+ExecStart=/sbin/restorecon -R /var/lib/etcd
+RemainAfterExit=yes
+
+[Install]
+WantedBy=multi-user.target
+`
+
+// ForEtcdDisk creates the MachineConfig to format the dedicated etcd disk on
+// the device and mount it to /var/lib/etcd.
+func ForEtcdDisk(device string, role string) (*mcfgv1.MachineConfig, error) {
+	ignConfig := igntypes.Config{
+		Ignition: igntypes.Ignition{
+			Version: igntypes.MaxVersion.String(),
+		},
+		Storage: igntypes.Storage{
+			Filesystems: []igntypes.Filesystem{{
+				Device:         device,
+				Format:         ignutil.StrToPtr("xfs"),
+				Label:          ignutil.StrToPtr("etcd"),
+				WipeFilesystem: ignutil.BoolToPtr(true),
+			}},
+		},
+		Systemd: igntypes.Systemd{
+			Units: []igntypes.Unit{
+				{Name: "var-lib-etcd.mount", Enabled: ignutil.BoolToPtr(true), Contents: ignutil.StrToPtr(etcdMountUnit)},
+				{Name: "restorecon-var-lib-etcd.service", Enabled: ignutil.BoolToPtr(true), Contents: ignutil.StrToPtr(etcdRestoreconUnit)},
+			},
+		},
+	}
+
+	rawExt, err := ignition.ConvertToRawExtension(ignConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mcfgv1.MachineConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: mcfgv1.SchemeGroupVersion.String(),
+			Kind:       "MachineConfig",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("99-%s-etcd-disk", role),
+			Labels: map[string]string{
+				"machineconfiguration.openshift.io/role": role,
+			},
+		},
+		Spec: mcfgv1.MachineConfigSpec{
+			Config: rawExt,
+		},
+	}, nil
+}
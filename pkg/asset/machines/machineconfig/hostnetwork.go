@@ -0,0 +1,129 @@
+package machineconfig
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"strings"
+
+	ignutil "github.com/coreos/ignition/v2/config/util"
+	igntypes "github.com/coreos/ignition/v2/config/v3_2/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/asset/ignition"
+	"github.com/openshift/installer/pkg/types/hostnetwork"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+)
+
+const hostNetworkDir = "/etc/openshift-host-network"
+
+// hostNetworkScript applies the NMState configuration of the host whose
+// identity file lists one of the MAC addresses or the serial number of the
+// machine it runs on.
+const hostNetworkScript = `#!/bin/bash
+set -euo pipefail
+
+dir=` + hostNetworkDir + `
+macs=$(cat /sys/class/net/*/address 2>/dev/null || true)
+serial=$(cat /sys/class/dmi/id/product_serial 2>/dev/null || true)
+
+matches() {
+  while read -r kind value; do
+    case "${kind}" in
+      mac) grep -qixF "${value}" <<< "${macs}" && return 0 ;;
+      serial) [ -n "${serial}" ] && [ "${value}" = "${serial}" ] && return 0 ;;
+    esac
+  done < "$1"
+  return 1
+}
+
+for id in "${dir}"/*.id; do
+  [ -e "${id}" ] || continue
+  if matches "${id}"; then
+    config="${id%.id}.yml"
+    echo "Applying ${config}"
+    nmstatectl apply "${config}"
+    touch "${dir}/.applied"
+    exit 0
+  fi
+done
+
+echo "No host network configuration matches this host"
+touch "${dir}/.applied"
+`
+
+const hostNetworkUnit = `[Unit]
+Description=Apply the day-1 host network configuration
+After=NetworkManager.service
+Before=network-online.target kubelet.service
+ConditionPathExists=!` + hostNetworkDir + `/.applied
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=/usr/local/bin/openshift-host-network.sh
+
+[Install]
+WantedBy=multi-user.target
+`
+
+// ForHostNetworks creates the MachineConfig to apply the NMState network
+// configuration of each host on first boot. Every machine of the role gets
+// the configuration of all the hosts, and applies the one matching its
+// identity.
+func ForHostNetworks(hosts []hostnetwork.Host, role string) (*mcfgv1.MachineConfig, error) {
+	ignConfig := igntypes.Config{
+		Ignition: igntypes.Ignition{
+			Version: igntypes.MaxVersion.String(),
+		},
+		Storage: igntypes.Storage{
+			Files: []igntypes.File{
+				ignition.FileFromString("/usr/local/bin/openshift-host-network.sh", "root", 0755, hostNetworkScript),
+			},
+		},
+		Systemd: igntypes.Systemd{
+			Units: []igntypes.Unit{{
+				Name:     "openshift-host-network.service",
+				Enabled:  ignutil.BoolToPtr(true),
+				Contents: ignutil.StrToPtr(hostNetworkUnit),
+			}},
+		},
+	}
+	for _, host := range hosts {
+		var id strings.Builder
+		for _, mac := range host.MACAddresses {
+			if hw, err := net.ParseMAC(mac); err == nil {
+				mac = hw.String()
+			}
+			fmt.Fprintf(&id, "mac %s\n", mac)
+		}
+		if host.SerialNumber != "" {
+			fmt.Fprintf(&id, "serial %s\n", host.SerialNumber)
+		}
+		ignConfig.Storage.Files = append(ignConfig.Storage.Files,
+			ignition.FileFromString(path.Join(hostNetworkDir, host.Name+".id"), "root", 0644, id.String()),
+			ignition.FileFromBytes(path.Join(hostNetworkDir, host.Name+".yml"), "root", 0600, host.NetworkConfig.Raw),
+		)
+	}
+
+	rawExt, err := ignition.ConvertToRawExtension(ignConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mcfgv1.MachineConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: mcfgv1.SchemeGroupVersion.String(),
+			Kind:       "MachineConfig",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("99-%s-host-network", role),
+			Labels: map[string]string{
+				"machineconfiguration.openshift.io/role": role,
+			},
+		},
+		Spec: mcfgv1.MachineConfigSpec{
+			Config: rawExt,
+		},
+	}, nil
+}
@@ -0,0 +1,62 @@
+package machineconfig
+
+import (
+	"fmt"
+
+	igntypes "github.com/coreos/ignition/v2/config/v3_2/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/asset/ignition"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+)
+
+const crioWorkloadPartitioning = `[crio.runtime.workloads.management]
+activation_annotation = "target.workload.openshift.io/management"
+annotation_prefix = "resources.workload.openshift.io"
+resources = { "cpushares" = 0, "cpuset" = "%s" }
+`
+
+const kubeletWorkloadPinning = `{
+  "management": {
+    "cpuset": "%s"
+  }
+}
+`
+
+// ForWorkloadPartitioning creates the MachineConfig to pin the platform
+// workloads to the reserved CPUs.
+// See https://docs.openshift.com/container-platform/4.12/scalability_and_performance/enabling-workload-partitioning.html
+func ForWorkloadPartitioning(reservedCPUs string, role string) (*mcfgv1.MachineConfig, error) {
+	ignConfig := igntypes.Config{
+		Ignition: igntypes.Ignition{
+			Version: igntypes.MaxVersion.String(),
+		},
+		Storage: igntypes.Storage{
+			Files: []igntypes.File{
+				ignition.FileFromString("/etc/crio/crio.conf.d/01-workload-partitioning", "root", 0644, fmt.Sprintf(crioWorkloadPartitioning, reservedCPUs)),
+				ignition.FileFromString("/etc/kubernetes/openshift-workload-pinning", "root", 0644, fmt.Sprintf(kubeletWorkloadPinning, reservedCPUs)),
+			},
+		},
+	}
+
+	rawExt, err := ignition.ConvertToRawExtension(ignConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mcfgv1.MachineConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: mcfgv1.SchemeGroupVersion.String(),
+			Kind:       "MachineConfig",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("02-%s-workload-partitioning", role),
+			Labels: map[string]string{
+				"machineconfiguration.openshift.io/role": role,
+			},
+		},
+		Spec: mcfgv1.MachineConfigSpec{
+			Config: rawExt,
+		},
+	}, nil
+}
@@ -52,6 +52,7 @@ import (
 	azuredefaults "github.com/openshift/installer/pkg/types/azure/defaults"
 	baremetaltypes "github.com/openshift/installer/pkg/types/baremetal"
 	gcptypes "github.com/openshift/installer/pkg/types/gcp"
+	"github.com/openshift/installer/pkg/types/hostnetwork"
 	ibmcloudtypes "github.com/openshift/installer/pkg/types/ibmcloud"
 	libvirttypes "github.com/openshift/installer/pkg/types/libvirt"
 	nonetypes "github.com/openshift/installer/pkg/types/none"
@@ -158,7 +159,16 @@ func (m *Master) Generate(dependencies asset.Parents) error {
 	var err error
 	machines := []machinev1beta1.Machine{}
 	var controlPlaneMachineSet *machinev1.ControlPlaneMachineSet
-	switch ic.Platform.Name() {
+	// etcdDiskDevice is the device of the dedicated etcd disk the platform
+	// attaches to the machines, if any
+	var etcdDiskDevice string
+	platform := ic.Platform.Name()
+	if ic.IsBootstrapInPlace() {
+		// the single node installs itself from the live ISO, it is not
+		// provisioned by the machine API
+		platform = nonetypes.Name
+	}
+	switch platform {
 	case alibabacloudtypes.Name:
 		client, err := installConfig.AlibabaCloud.Client()
 		if err != nil {
@@ -230,10 +240,15 @@ func (m *Master) Generate(dependencies asset.Parents) error {
 		}
 
 		if mpool.InstanceType == "" {
-			mpool.InstanceType, err = aws.PreferredInstanceType(ctx, installConfig.AWS, awsDefaultMachineTypes(installConfig.Config.Platform.AWS.Region, installConfig.Config.ControlPlane.Architecture), mpool.Zones)
+			defaultTypes := awsDefaultMachineTypes(installConfig.Config.Platform.AWS.Region, installConfig.Config.ControlPlane.Architecture)
+			if ic.IsSingleReplicaControlPlane() {
+				// the single node also runs the workloads
+				defaultTypes = awsMachineTypesOfSize(installConfig.Config.Platform.AWS.Region, installConfig.Config.ControlPlane.Architecture, "2xlarge")
+			}
+			mpool.InstanceType, err = aws.PreferredInstanceType(ctx, installConfig.AWS, defaultTypes, mpool.Zones)
 			if err != nil {
 				logrus.Warn(errors.Wrap(err, "failed to find default instance type"))
-				mpool.InstanceType = awsDefaultMachineTypes(installConfig.Config.Platform.AWS.Region, installConfig.Config.ControlPlane.Architecture)[0]
+				mpool.InstanceType = defaultTypes[0]
 			}
 		}
 
@@ -259,19 +274,27 @@ func (m *Master) Generate(dependencies asset.Parents) error {
 			return errors.Wrap(err, "failed to create master machine objects")
 		}
 		aws.ConfigMasters(machines, controlPlaneMachineSet, clusterID.InfraID, ic.Publish)
+		if ic.Etcd != nil && ic.Etcd.Disk != nil {
+			aws.ConfigEtcdDisk(machines, controlPlaneMachineSet, ic.Etcd.Disk)
+			etcdDiskDevice = aws.EtcdDiskDevice
+		}
 	case gcptypes.Name:
 		mpool := defaultGCPMachinePoolPlatform()
+		if ic.IsSingleReplicaControlPlane() {
+			// the single node also runs the workloads
+			mpool.InstanceType = "n2-standard-8"
+		}
 		mpool.Set(ic.Platform.GCP.DefaultMachinePlatform)
 		mpool.Set(pool.Platform.GCP)
 		if len(mpool.Zones) == 0 {
-			azs, err := gcp.AvailabilityZones(ic.Platform.GCP.ProjectID, ic.Platform.GCP.Region)
+			azs, err := gcp.AvailabilityZones(installConfig.Credentials().GCPCredentials(), ic.Platform.GCP.ProjectID, ic.Platform.GCP.Region)
 			if err != nil {
 				return errors.Wrap(err, "failed to fetch availability zones")
 			}
 			mpool.Zones = azs
 		}
 		pool.Platform.GCP = &mpool
-		machines, controlPlaneMachineSet, err = gcp.Machines(clusterID.InfraID, ic, &pool, string(*rhcosImage), "master", masterUserDataSecretName)
+		machines, controlPlaneMachineSet, err = gcp.Machines(clusterID.InfraID, ic, installConfig.Credentials().GCPCredentials(), &pool, string(*rhcosImage), "master", masterUserDataSecretName)
 		if err != nil {
 			return errors.Wrap(err, "failed to create master machine objects")
 		}
@@ -279,6 +302,12 @@ func (m *Master) Generate(dependencies asset.Parents) error {
 		if err != nil {
 			return err
 		}
+		if ic.Etcd != nil && ic.Etcd.Disk != nil {
+			if err := gcp.ConfigEtcdDisk(machines, controlPlaneMachineSet, ic.Etcd.Disk); err != nil {
+				return err
+			}
+			etcdDiskDevice = gcp.EtcdDiskDevice
+		}
 	case ibmcloudtypes.Name:
 		subnets := map[string]string{}
 		if len(ic.Platform.IBMCloud.ControlPlaneSubnets) > 0 {
@@ -379,6 +408,12 @@ func (m *Master) Generate(dependencies asset.Parents) error {
 		if err != nil {
 			return err
 		}
+		if ic.Etcd != nil && ic.Etcd.Disk != nil {
+			if err := azure.ConfigEtcdDisk(machines, controlPlaneMachineSet, ic.Etcd.Disk); err != nil {
+				return err
+			}
+			etcdDiskDevice = azure.EtcdDiskDevice
+		}
 	case baremetaltypes.Name:
 		mpool := defaultBareMetalMachinePoolPlatform()
 		mpool.Set(ic.Platform.BareMetal.DefaultMachinePlatform)
@@ -511,12 +546,54 @@ func (m *Master) Generate(dependencies asset.Parents) error {
 		machineConfigs = append(machineConfigs, ignHT)
 	}
 	if ic.SSHKey != "" {
-		ignSSH, err := machineconfig.ForAuthorizedKeys(ic.SSHKey, "master")
+		ignSSH, err := machineconfig.ForAuthorizedKeys(ic.SSHKeys(), "master")
 		if err != nil {
 			return errors.Wrap(err, "failed to create ignition for authorized SSH keys for master machines")
 		}
 		machineConfigs = append(machineConfigs, ignSSH)
 	}
+	if pool.DiskEncryption != nil || pool.DiskMirroring != nil {
+		ignDisks, err := machineconfig.ForDiskSetup(&pool, "master")
+		if err != nil {
+			return errors.Wrap(err, "failed to create ignition for disk setup for master machines")
+		}
+		machineConfigs = append(machineConfigs, ignDisks)
+	}
+	if etcdDiskDevice != "" {
+		ignEtcdDisk, err := machineconfig.ForEtcdDisk(etcdDiskDevice, "master")
+		if err != nil {
+			return errors.Wrap(err, "failed to create ignition for the etcd disk of master machines")
+		}
+		machineConfigs = append(machineConfigs, ignEtcdDisk)
+	}
+	if ic.NodeConfig != nil && ic.NodeConfig.CPUPartitioning != nil {
+		ignPartitioning, err := machineconfig.ForWorkloadPartitioning(ic.NodeConfig.CPUPartitioning.ReservedCPUs, "master")
+		if err != nil {
+			return errors.Wrap(err, "failed to create ignition for workload partitioning for master machines")
+		}
+		machineConfigs = append(machineConfigs, ignPartitioning)
+	}
+	if pool.MachineConfig != nil {
+		ignCustom, err := machineconfig.ForCustomization(pool.MachineConfig, "master")
+		if err != nil {
+			return errors.Wrap(err, "failed to create ignition for the machine config of master machines")
+		}
+		machineConfigs = append(machineConfigs, ignCustom)
+	}
+	if hosts := hostNetworks(ic, "master"); len(hosts) > 0 {
+		ignHostNetwork, err := machineconfig.ForHostNetworks(hosts, "master")
+		if err != nil {
+			return errors.Wrap(err, "failed to create ignition for host networks of master machines")
+		}
+		machineConfigs = append(machineConfigs, ignHostNetwork)
+	}
+	if ntp := ic.NTPFor(&pool); ntp != nil {
+		ignNTP, err := machineconfig.ForNTP(ntp, "master")
+		if err != nil {
+			return errors.Wrap(err, "failed to create ignition for NTP of master machines")
+		}
+		machineConfigs = append(machineConfigs, ignNTP)
+	}
 	if ic.FIPS {
 		ignFIPS, err := machineconfig.ForFIPSEnabled("master")
 		if err != nil {
@@ -532,6 +609,9 @@ func (m *Master) Generate(dependencies asset.Parents) error {
 
 	m.MachineFiles = make([]*asset.File, len(machines))
 	if controlPlaneMachineSet != nil && *pool.Replicas > 1 {
+		if pool.ControlPlaneMachineSetState == types.ControlPlaneMachineSetStateInactive {
+			controlPlaneMachineSet.Spec.State = machinev1.ControlPlaneMachineSetStateInactive
+		}
 		data, err := yaml.Marshal(controlPlaneMachineSet)
 		if err != nil {
 			return errors.Wrapf(err, "marshal control plane machine set")
@@ -678,6 +758,25 @@ func (m *Master) Machines() ([]machinev1beta1.Machine, error) {
 	return machines, nil
 }
 
+// hostNetworks returns the hosts of the role that have a day-1 network
+// configuration on the platforms which support it.
+func hostNetworks(ic *types.InstallConfig, role string) []hostnetwork.Host {
+	var hosts []hostnetwork.Host
+	switch {
+	case ic.Platform.None != nil:
+		hosts = ic.Platform.None.Hosts
+	case ic.Platform.VSphere != nil:
+		hosts = ic.Platform.VSphere.Hosts
+	}
+	var roleHosts []hostnetwork.Host
+	for _, host := range hosts {
+		if host.Role == role {
+			roleHosts = append(roleHosts, host)
+		}
+	}
+	return roleHosts
+}
+
 // IsMachineManifest tests whether a file is a manifest that belongs to the
 // Master Machines or Worker Machines asset.
 func IsMachineManifest(file *asset.File) bool {
@@ -12,6 +12,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 
+	machinev1 "github.com/openshift/api/machine/v1"
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/ignition/machine"
 	"github.com/openshift/installer/pkg/asset/installconfig"
@@ -26,6 +27,10 @@ func TestMasterGenerateMachineConfigs(t *testing.T) {
 		name                  string
 		key                   string
 		hyperthreading        types.HyperthreadingMode
+		diskEncryption        *types.DiskEncryption
+		diskMirroring         *types.DiskMirroring
+		machineConfig         *types.MachineConfigCustomization
+		etcd                  *types.Etcd
 		expectedMachineConfig []string
 	}{
 		{
@@ -123,6 +128,206 @@ spec:
   kernelArguments: null
   kernelType: ""
   osImageURL: ""
+`},
+		},
+		{
+			name:           "disk encryption and mirroring",
+			hyperthreading: types.HyperthreadingEnabled,
+			diskEncryption: &types.DiskEncryption{
+				TPM2:      true,
+				Tang:      []types.TangServer{{URL: "http://tang.example.com", Thumbprint: "test-thumbprint"}},
+				Threshold: 2,
+			},
+			diskMirroring: &types.DiskMirroring{Devices: []string{"/dev/sda", "/dev/sdb"}},
+			expectedMachineConfig: []string{`apiVersion: machineconfiguration.openshift.io/v1
+kind: MachineConfig
+metadata:
+  creationTimestamp: null
+  labels:
+    machineconfiguration.openshift.io/role: master
+  name: 99-master-disk-setup
+spec:
+  config:
+    ignition:
+      version: 3.2.0
+    storage:
+      disks:
+      - device: /dev/sda
+        partitions:
+        - label: bios-1
+          sizeMiB: 1
+          typeGuid: 21686148-6449-6E6F-744E-656564454649
+        - label: esp-1
+          sizeMiB: 127
+          typeGuid: C12A7328-F81F-11D2-BA4B-00A0C93EC93B
+        - label: boot-1
+          sizeMiB: 384
+        - label: root-1
+        wipeTable: true
+      - device: /dev/sdb
+        partitions:
+        - label: bios-2
+          sizeMiB: 1
+          typeGuid: 21686148-6449-6E6F-744E-656564454649
+        - label: esp-2
+          sizeMiB: 127
+          typeGuid: C12A7328-F81F-11D2-BA4B-00A0C93EC93B
+        - label: boot-2
+          sizeMiB: 384
+        - label: root-2
+        wipeTable: true
+      filesystems:
+      - device: /dev/disk/by-partlabel/esp-1
+        format: vfat
+        label: esp-1
+        wipeFilesystem: true
+      - device: /dev/disk/by-partlabel/esp-2
+        format: vfat
+        label: esp-2
+        wipeFilesystem: true
+      - device: /dev/md/md-boot
+        format: ext4
+        label: boot
+        wipeFilesystem: true
+      - device: /dev/mapper/root
+        format: xfs
+        label: root
+        wipeFilesystem: true
+      luks:
+      - clevis:
+          tang:
+          - thumbprint: test-thumbprint
+            url: http://tang.example.com
+          threshold: 2
+          tpm2: true
+        device: /dev/md/md-root
+        label: luks-root
+        name: root
+        wipeVolume: true
+      raid:
+      - devices:
+        - /dev/disk/by-partlabel/boot-1
+        - /dev/disk/by-partlabel/boot-2
+        level: raid1
+        name: md-boot
+        options:
+        - --metadata=1.0
+      - devices:
+        - /dev/disk/by-partlabel/root-1
+        - /dev/disk/by-partlabel/root-2
+        level: raid1
+        name: md-root
+  extensions: null
+  fips: false
+  kernelArguments:
+  - rd.neednet=1
+  kernelType: ""
+  osImageURL: ""
+`},
+		},
+		{
+			name:           "machine config",
+			hyperthreading: types.HyperthreadingEnabled,
+			machineConfig: &types.MachineConfigCustomization{
+				Files:           []types.MachineConfigFile{{Path: "/etc/example.conf", Contents: "example"}},
+				Units:           []types.MachineConfigUnit{{Name: "example.service", Contents: "[Unit]", Enabled: true}},
+				KernelArguments: []string{"example=1"},
+				Ignition:        `{"ignition":{"version":"3.1.0"},"systemd":{"units":[{"name":"other.service","mask":true}]}}`,
+			},
+			expectedMachineConfig: []string{`apiVersion: machineconfiguration.openshift.io/v1
+kind: MachineConfig
+metadata:
+  creationTimestamp: null
+  labels:
+    machineconfiguration.openshift.io/role: master
+  name: 99-master-custom
+spec:
+  config:
+    ignition:
+      version: 3.2.0
+    storage:
+      files:
+      - contents:
+          source: data:text/plain;charset=utf-8;base64,ZXhhbXBsZQ==
+        mode: 420
+        overwrite: true
+        path: /etc/example.conf
+        user:
+          name: root
+    systemd:
+      units:
+      - mask: true
+        name: other.service
+      - contents: '[Unit]'
+        enabled: true
+        name: example.service
+  extensions: null
+  fips: false
+  kernelArguments:
+  - example=1
+  kernelType: ""
+  osImageURL: ""
+`},
+		},
+		{
+			name:           "etcd disk",
+			hyperthreading: types.HyperthreadingEnabled,
+			etcd:           &types.Etcd{Disk: &types.EtcdDisk{SizeGB: 32, Type: "gp3"}},
+			expectedMachineConfig: []string{`apiVersion: machineconfiguration.openshift.io/v1
+kind: MachineConfig
+metadata:
+  creationTimestamp: null
+  labels:
+    machineconfiguration.openshift.io/role: master
+  name: 99-master-etcd-disk
+spec:
+  config:
+    ignition:
+      version: 3.2.0
+    storage:
+      filesystems:
+      - device: /dev/xvdb
+        format: xfs
+        label: etcd
+        wipeFilesystem: true
+    systemd:
+      units:
+      - contents: |
+          [Unit]
+          Description=Mount the etcd disk to /var/lib/etcd
+          Before=local-fs.target
+
+          [Mount]
+          What=/dev/disk/by-label/etcd
+          Where=/var/lib/etcd
+          Type=xfs
+          Options=defaults,prjquota
+
+          [Install]
+          WantedBy=local-fs.target
+        enabled: true
+        name: var-lib-etcd.mount
+      - contents: |
+          [Unit]
+          Description=Restore the SELinux context of /var/lib/etcd
+          Requires=var-lib-etcd.mount
+          After=var-lib-etcd.mount
+          Before=kubelet.service
+
+          [Service]
+          Type=oneshot
+          ExecStart=/sbin/restorecon -R /var/lib/etcd
+          RemainAfterExit=yes
+
+          [Install]
+          WantedBy=multi-user.target
+        enabled: true
+        name: restorecon-var-lib-etcd.service
+  extensions: null
+  fips: false
+  kernelArguments: null
+  kernelType: ""
+  osImageURL: ""
 `},
 		},
 	}
@@ -141,6 +346,7 @@ spec:
 						},
 						SSHKey:     tc.key,
 						BaseDomain: "test-domain",
+						Etcd:       tc.etcd,
 						Platform: types.Platform{
 							AWS: &awstypes.Platform{
 								Region: "us-east-1",
@@ -149,6 +355,10 @@ spec:
 						ControlPlane: &types.MachinePool{
 							Hyperthreading: tc.hyperthreading,
 							Replicas:       pointer.Int64Ptr(1),
+							Architecture:   types.ArchitectureAMD64,
+							DiskEncryption: tc.diskEncryption,
+							DiskMirroring:  tc.diskMirroring,
+							MachineConfig:  tc.machineConfig,
 							Platform: types.MachinePoolPlatform{
 								AWS: &awstypes.MachinePool{
 									Zones:        []string{"us-east-1a"},
@@ -237,6 +447,90 @@ func TestControlPlaneIsNotModified(t *testing.T) {
 	}
 }
 
+func TestControlPlaneMachineSetState(t *testing.T) {
+	cases := []struct {
+		name          string
+		state         types.ControlPlaneMachineSetState
+		expectedState machinev1.ControlPlaneMachineSetState
+	}{
+		{
+			name:          "default",
+			expectedState: machinev1.ControlPlaneMachineSetStateActive,
+		},
+		{
+			name:          "active",
+			state:         types.ControlPlaneMachineSetStateActive,
+			expectedState: machinev1.ControlPlaneMachineSetStateActive,
+		},
+		{
+			name:          "inactive",
+			state:         types.ControlPlaneMachineSetStateInactive,
+			expectedState: machinev1.ControlPlaneMachineSetStateInactive,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			parents := asset.Parents{}
+			installConfig := installconfig.InstallConfig{
+				Config: &types.InstallConfig{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-cluster",
+					},
+					SSHKey:     "ssh-rsa: dummy-key",
+					BaseDomain: "test-domain",
+					Platform: types.Platform{
+						AWS: &awstypes.Platform{
+							Region: "us-east-1",
+							DefaultMachinePlatform: &awstypes.MachinePool{
+								InstanceType: "TEST_INSTANCE_TYPE",
+							},
+						},
+					},
+					ControlPlane: &types.MachinePool{
+						Hyperthreading: types.HyperthreadingDisabled,
+						Replicas:       pointer.Int64Ptr(3),
+						Platform: types.MachinePoolPlatform{
+							AWS: &awstypes.MachinePool{
+								Zones: []string{"us-east-1a", "us-east-1b", "us-east-1c"},
+							},
+						},
+						ControlPlaneMachineSetState: tc.state,
+					},
+				},
+			}
+
+			parents.Add(
+				&installconfig.ClusterID{
+					UUID:    "test-uuid",
+					InfraID: "test-infra-id",
+				},
+				&installConfig,
+				(*rhcos.Image)(pointer.StringPtr("test-image")),
+				(*rhcos.Release)(pointer.StringPtr("412.86.202208101040-0")),
+				&machine.Master{
+					File: &asset.File{
+						Filename: "master-ignition",
+						Data:     []byte("test-ignition"),
+					},
+				},
+			)
+			master := &Master{}
+			if err := master.Generate(parents); err != nil {
+				t.Fatalf("failed to generate master machines: %v", err)
+			}
+
+			if !assert.NotNil(t, master.ControlPlaneMachineSet) {
+				return
+			}
+			controlPlaneMachineSet := &machinev1.ControlPlaneMachineSet{}
+			if err := yaml.Unmarshal(master.ControlPlaneMachineSet.Data, controlPlaneMachineSet); err != nil {
+				t.Fatalf("failed to unmarshal control plane machine set: %v", err)
+			}
+			assert.Equal(t, tc.expectedState, controlPlaneMachineSet.Spec.State)
+		})
+	}
+}
+
 func TestBaremetalGeneratedAssetFiles(t *testing.T) {
 	parents := asset.Parents{}
 	installConfig := installconfig.InstallConfig{
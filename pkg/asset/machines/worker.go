@@ -10,9 +10,12 @@ import (
 	"github.com/ghodss/yaml"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/pointer"
 
 	machinev1 "github.com/openshift/api/machine/v1"
 	machinev1alpha1 "github.com/openshift/api/machine/v1alpha1"
@@ -70,6 +73,14 @@ const (
 	// workerUserDataFileName is the filename used for the worker user-data secret.
 	workerUserDataFileName = "99_openshift-cluster-api_worker-user-data-secret.yaml"
 
+	// windowsUserDataSecretName is the user-data secret of the Windows
+	// machines, which is created by the Windows Machine Config Operator.
+	windowsUserDataSecretName = "windows-user-data"
+
+	// windowsOSIDLabel is the label by which the Windows Machine Config
+	// Operator selects the Windows machines.
+	windowsOSIDLabel = "machine.openshift.io/os-id"
+
 	// decimalRootVolumeSize is the size in GB we use for some platforms.
 	// See below.
 	decimalRootVolumeSize = 120
@@ -183,19 +194,25 @@ func defaultNutanixMachinePoolPlatform() nutanixtypes.MachinePool {
 }
 
 func awsDefaultMachineTypes(region string, arch types.Architecture) []string {
+	return awsMachineTypesOfSize(region, arch, "xlarge")
+}
+
+func awsMachineTypesOfSize(region string, arch types.Architecture, size string) []string {
 	classes := awsdefaults.InstanceClasses(region, arch)
 	types := make([]string, len(classes))
 	for i, c := range classes {
-		types[i] = fmt.Sprintf("%s.xlarge", c)
+		types[i] = fmt.Sprintf("%s.%s", c, size)
 	}
 	return types
 }
 
 // Worker generates the machinesets for `worker` machine pool.
 type Worker struct {
-	UserDataFile       *asset.File
-	MachineConfigFiles []*asset.File
-	MachineSetFiles    []*asset.File
+	UserDataFile           *asset.File
+	MachineConfigFiles     []*asset.File
+	MachineSetFiles        []*asset.File
+	ClusterAutoscalerFile  *asset.File
+	MachineAutoscalerFiles []*asset.File
 }
 
 // Name returns a human friendly name for the Worker Asset.
@@ -233,30 +250,87 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 
 	machineConfigs := []*mcfgv1.MachineConfig{}
 	machineSets := []runtime.Object{}
+	machineAutoscalers := []*machineAutoscaler{}
 	var err error
 	ic := installConfig.Config
+	// the infra machines are in the worker MachineConfigPool, so they get
+	// the container storage disk of the worker pool as well
+	var containerStorage *types.ContainerStorage
+	for _, pool := range ic.Compute {
+		if pool.Name == types.MachinePoolComputeRoleName {
+			containerStorage = pool.ContainerStorage
+		}
+	}
 	for _, pool := range ic.Compute {
 		pool := pool // this makes golint happy... G601: Implicit memory aliasing in for loop. (gosec)
-		if pool.Hyperthreading == types.HyperthreadingDisabled {
-			ignHT, err := machineconfig.ForHyperthreadingDisabled("worker")
-			if err != nil {
-				return errors.Wrap(err, "failed to create ignition for hyperthreading disabled for worker machines")
+		firstMachineSet := len(machineSets)
+		// the infra machines are in the worker MachineConfigPool and the
+		// Windows machines are not managed by the machine-config operator,
+		// so the MachineConfigs are only generated for the worker pool
+		if pool.Name == types.MachinePoolComputeRoleName {
+			if pool.Hyperthreading == types.HyperthreadingDisabled {
+				ignHT, err := machineconfig.ForHyperthreadingDisabled("worker")
+				if err != nil {
+					return errors.Wrap(err, "failed to create ignition for hyperthreading disabled for worker machines")
+				}
+				machineConfigs = append(machineConfigs, ignHT)
 			}
-			machineConfigs = append(machineConfigs, ignHT)
-		}
-		if ic.SSHKey != "" {
-			ignSSH, err := machineconfig.ForAuthorizedKeys(ic.SSHKey, "worker")
-			if err != nil {
-				return errors.Wrap(err, "failed to create ignition for authorized SSH keys for worker machines")
+			if ic.SSHKey != "" {
+				ignSSH, err := machineconfig.ForAuthorizedKeys(ic.SSHKeys(), "worker")
+				if err != nil {
+					return errors.Wrap(err, "failed to create ignition for authorized SSH keys for worker machines")
+				}
+				machineConfigs = append(machineConfigs, ignSSH)
 			}
-			machineConfigs = append(machineConfigs, ignSSH)
-		}
-		if ic.FIPS {
-			ignFIPS, err := machineconfig.ForFIPSEnabled("worker")
-			if err != nil {
-				return errors.Wrap(err, "failed to create ignition for FIPS enabled for worker machines")
+			if pool.DiskEncryption != nil || pool.DiskMirroring != nil {
+				ignDisks, err := machineconfig.ForDiskSetup(&pool, "worker")
+				if err != nil {
+					return errors.Wrap(err, "failed to create ignition for disk setup for worker machines")
+				}
+				machineConfigs = append(machineConfigs, ignDisks)
+			}
+			if containerStorage != nil {
+				ignStorage, err := machineconfig.ForContainerStorage(containerStorageDevice(ic.Platform.Name(), containerStorage), containerStorage.MountPath, "worker")
+				if err != nil {
+					return errors.Wrap(err, "failed to create ignition for container storage of worker machines")
+				}
+				machineConfigs = append(machineConfigs, ignStorage)
+			}
+			if ic.NodeConfig != nil && ic.NodeConfig.CPUPartitioning != nil {
+				ignPartitioning, err := machineconfig.ForWorkloadPartitioning(ic.NodeConfig.CPUPartitioning.ReservedCPUs, "worker")
+				if err != nil {
+					return errors.Wrap(err, "failed to create ignition for workload partitioning for worker machines")
+				}
+				machineConfigs = append(machineConfigs, ignPartitioning)
+			}
+			if pool.MachineConfig != nil {
+				ignCustom, err := machineconfig.ForCustomization(pool.MachineConfig, "worker")
+				if err != nil {
+					return errors.Wrap(err, "failed to create ignition for the machine config of worker machines")
+				}
+				machineConfigs = append(machineConfigs, ignCustom)
+			}
+			if hosts := hostNetworks(ic, "worker"); len(hosts) > 0 {
+				ignHostNetwork, err := machineconfig.ForHostNetworks(hosts, "worker")
+				if err != nil {
+					return errors.Wrap(err, "failed to create ignition for host networks of worker machines")
+				}
+				machineConfigs = append(machineConfigs, ignHostNetwork)
+			}
+			if ntp := ic.NTPFor(&pool); ntp != nil {
+				ignNTP, err := machineconfig.ForNTP(ntp, "worker")
+				if err != nil {
+					return errors.Wrap(err, "failed to create ignition for NTP of worker machines")
+				}
+				machineConfigs = append(machineConfigs, ignNTP)
+			}
+			if ic.FIPS {
+				ignFIPS, err := machineconfig.ForFIPSEnabled("worker")
+				if err != nil {
+					return errors.Wrap(err, "failed to create ignition for FIPS enabled for worker machines")
+				}
+				machineConfigs = append(machineConfigs, ignFIPS)
 			}
-			machineConfigs = append(machineConfigs, ignFIPS)
 		}
 		switch ic.Platform.Name() {
 		case alibabacloudtypes.Name:
@@ -371,6 +445,43 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 			for _, set := range sets {
 				machineSets = append(machineSets, set)
 			}
+			if pool.MachineSetsInAllZones {
+				regionZones := make([]string, 0, len(subnets))
+				for zone := range subnets {
+					regionZones = append(regionZones, zone)
+				}
+				if len(subnets) == 0 {
+					regionZones, err = installConfig.AWS.AvailabilityZones(ctx)
+					if err != nil {
+						return err
+					}
+					regionZones, err = aws.FilterZonesBasedOnInstanceType(ctx, installConfig.AWS, mpool.InstanceType, regionZones)
+					if err != nil {
+						logrus.Warn(errors.Wrap(err, "failed to filter zone list"))
+					}
+				}
+				extraMpool := mpool
+				extraMpool.Zones = zonesWithoutMachineSets(regionZones, mpool.Zones)
+				if len(extraMpool.Zones) > 0 {
+					extraPool := zeroReplicaPool(pool)
+					extraPool.Platform.AWS = &extraMpool
+					sets, err := aws.MachineSets(
+						clusterID.InfraID,
+						installConfig.Config.Platform.AWS.Region,
+						subnets,
+						&extraPool,
+						"worker",
+						workerUserDataSecretName,
+						installConfig.Config.Platform.AWS.UserTags,
+					)
+					if err != nil {
+						return errors.Wrap(err, "failed to create worker machine objects")
+					}
+					for _, set := range sets {
+						machineSets = append(machineSets, set)
+					}
+				}
+			}
 		case azuretypes.Name:
 			mpool := defaultAzureMachinePoolPlatform()
 			mpool.InstanceType = azuredefaults.ComputeInstanceType(
@@ -420,6 +531,25 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 			for _, set := range sets {
 				machineSets = append(machineSets, set)
 			}
+			if pool.MachineSetsInAllZones {
+				regionZones, err := client.GetAvailabilityZones(context.TODO(), ic.Platform.Azure.Region, mpool.InstanceType)
+				if err != nil {
+					return errors.Wrap(err, "failed to fetch availability zones")
+				}
+				extraMpool := mpool
+				extraMpool.Zones = zonesWithoutMachineSets(regionZones, mpool.Zones)
+				if len(extraMpool.Zones) > 0 {
+					extraPool := zeroReplicaPool(pool)
+					extraPool.Platform.Azure = &extraMpool
+					sets, err := azure.MachineSets(clusterID.InfraID, ic, &extraPool, string(*rhcosImage), "worker", workerUserDataSecretName, capabilities, useImageGallery)
+					if err != nil {
+						return errors.Wrap(err, "failed to create worker machine objects")
+					}
+					for _, set := range sets {
+						machineSets = append(machineSets, set)
+					}
+				}
+			}
 		case baremetaltypes.Name:
 			mpool := defaultBareMetalMachinePoolPlatform()
 			mpool.Set(ic.Platform.BareMetal.DefaultMachinePlatform)
@@ -441,20 +571,39 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 			mpool.Set(ic.Platform.GCP.DefaultMachinePlatform)
 			mpool.Set(pool.Platform.GCP)
 			if len(mpool.Zones) == 0 {
-				azs, err := gcp.AvailabilityZones(ic.Platform.GCP.ProjectID, ic.Platform.GCP.Region)
+				azs, err := gcp.AvailabilityZones(installConfig.Credentials().GCPCredentials(), ic.Platform.GCP.ProjectID, ic.Platform.GCP.Region)
 				if err != nil {
 					return errors.Wrap(err, "failed to fetch availability zones")
 				}
 				mpool.Zones = azs
 			}
 			pool.Platform.GCP = &mpool
-			sets, err := gcp.MachineSets(clusterID.InfraID, ic, &pool, string(*rhcosImage), "worker", workerUserDataSecretName)
+			sets, err := gcp.MachineSets(clusterID.InfraID, ic, installConfig.Credentials().GCPCredentials(), &pool, string(*rhcosImage), "worker", workerUserDataSecretName)
 			if err != nil {
 				return errors.Wrap(err, "failed to create worker machine objects")
 			}
 			for _, set := range sets {
 				machineSets = append(machineSets, set)
 			}
+			if pool.MachineSetsInAllZones {
+				regionZones, err := gcp.AvailabilityZones(installConfig.Credentials().GCPCredentials(), ic.Platform.GCP.ProjectID, ic.Platform.GCP.Region)
+				if err != nil {
+					return errors.Wrap(err, "failed to fetch availability zones")
+				}
+				extraMpool := mpool
+				extraMpool.Zones = zonesWithoutMachineSets(regionZones, mpool.Zones)
+				if len(extraMpool.Zones) > 0 {
+					extraPool := zeroReplicaPool(pool)
+					extraPool.Platform.GCP = &extraMpool
+					sets, err := gcp.MachineSets(clusterID.InfraID, ic, installConfig.Credentials().GCPCredentials(), &extraPool, string(*rhcosImage), "worker", workerUserDataSecretName)
+					if err != nil {
+						return errors.Wrap(err, "failed to create worker machine objects")
+					}
+					for _, set := range sets {
+						machineSets = append(machineSets, set)
+					}
+				}
+			}
 		case ibmcloudtypes.Name:
 			subnets := map[string]string{}
 			if len(ic.Platform.IBMCloud.ComputeSubnets) > 0 {
@@ -578,6 +727,34 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 		default:
 			return fmt.Errorf("invalid Platform")
 		}
+
+		if pool.Name == types.MachinePoolInfraRoleName {
+			if err := configInfraMachineSets(machineSets[firstMachineSet:]); err != nil {
+				return errors.Wrap(err, "failed to configure infra machine objects")
+			}
+		}
+		if pool.OSType == types.OSTypeWindows {
+			if err := configWindowsMachineSets(machineSets[firstMachineSet:]); err != nil {
+				return errors.Wrap(err, "failed to configure Windows machine objects")
+			}
+		}
+		if containerStorage != nil && pool.OSType != types.OSTypeWindows {
+			if err := configContainerStorageMachineSets(ic.Platform.Name(), machineSets[firstMachineSet:], containerStorage); err != nil {
+				return errors.Wrap(err, "failed to add the container storage disk to worker machine objects")
+			}
+		}
+		if pool.Accelerators != nil {
+			if err := configAcceleratorMachineSets(machineSets[firstMachineSet:], pool.Accelerators.Vendor); err != nil {
+				return errors.Wrap(err, "failed to configure GPU machine objects")
+			}
+		}
+		if pool.Autoscaling != nil {
+			autoscalers, err := poolMachineAutoscalers(pool.Autoscaling, machineSets[firstMachineSet:])
+			if err != nil {
+				return errors.Wrap(err, "failed to create worker machine autoscalers")
+			}
+			machineAutoscalers = append(machineAutoscalers, autoscalers...)
+		}
 	}
 
 	data, err := userDataSecret(workerUserDataSecretName, wign.File.Data)
@@ -594,6 +771,11 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 		return errors.Wrap(err, "failed to create MachineConfig manifests for worker machines")
 	}
 
+	w.ClusterAutoscalerFile, w.MachineAutoscalerFiles, err = autoscalerFiles(machineAutoscalers)
+	if err != nil {
+		return err
+	}
+
 	w.MachineSetFiles = make([]*asset.File, len(machineSets))
 	padFormat := fmt.Sprintf("%%0%dd", len(fmt.Sprintf("%d", len(machineSets))))
 	for i, machineSet := range machineSets {
@@ -613,12 +795,16 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 
 // Files returns the files generated by the asset.
 func (w *Worker) Files() []*asset.File {
-	files := make([]*asset.File, 0, 1+len(w.MachineConfigFiles)+len(w.MachineSetFiles))
+	files := make([]*asset.File, 0, 2+len(w.MachineConfigFiles)+len(w.MachineSetFiles)+len(w.MachineAutoscalerFiles))
 	if w.UserDataFile != nil {
 		files = append(files, w.UserDataFile)
 	}
 	files = append(files, w.MachineConfigFiles...)
 	files = append(files, w.MachineSetFiles...)
+	if w.ClusterAutoscalerFile != nil {
+		files = append(files, w.ClusterAutoscalerFile)
+	}
+	files = append(files, w.MachineAutoscalerFiles...)
 	return files
 }
 
@@ -644,6 +830,16 @@ func (w *Worker) Load(f asset.FileFetcher) (found bool, err error) {
 	}
 
 	w.MachineSetFiles = fileList
+
+	w.ClusterAutoscalerFile, err = f.FetchByName(filepath.Join(directory, clusterAutoscalerFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return true, err
+	}
+
+	w.MachineAutoscalerFiles, err = f.FetchByPattern(filepath.Join(directory, workerMachineAutoscalerFileNamePattern))
+	if err != nil {
+		return true, err
+	}
 	return true, nil
 }
 
@@ -699,3 +895,130 @@ func (w *Worker) MachineSets() ([]machinev1beta1.MachineSet, error) {
 
 	return machineSets, nil
 }
+
+// configInfraMachineSets labels and taints the nodes of the infra
+// MachineSets, so that only the ingress controller and monitoring, which
+// tolerate the taint, run on them.
+func configInfraMachineSets(machineSets []runtime.Object) error {
+	for _, obj := range machineSets {
+		machineSet, ok := obj.(*machinev1beta1.MachineSet)
+		if !ok {
+			return errors.Errorf("unexpected machine set type %T", obj)
+		}
+		spec := &machineSet.Spec.Template.Spec
+		if spec.ObjectMeta.Labels == nil {
+			spec.ObjectMeta.Labels = map[string]string{}
+		}
+		spec.ObjectMeta.Labels[types.InfraNodeRoleLabel] = ""
+		spec.Taints = append(spec.Taints, corev1.Taint{
+			Key:    types.InfraNodeRoleLabel,
+			Effect: corev1.TaintEffectNoSchedule,
+		})
+	}
+	return nil
+}
+
+// configAcceleratorMachineSets labels and taints the nodes of the
+// MachineSets of a pool with GPUs of the vendor, so that only workloads which
+// tolerate the taint of the GPU resource, such as the GPU operator of the
+// vendor, run on them.
+func configAcceleratorMachineSets(machineSets []runtime.Object, vendor types.AcceleratorVendor) error {
+	resource := vendor.ResourceName()
+	for _, obj := range machineSets {
+		machineSet, ok := obj.(*machinev1beta1.MachineSet)
+		if !ok {
+			return errors.Errorf("unexpected machine set type %T", obj)
+		}
+		spec := &machineSet.Spec.Template.Spec
+		if spec.ObjectMeta.Labels == nil {
+			spec.ObjectMeta.Labels = map[string]string{}
+		}
+		spec.ObjectMeta.Labels[resource+".present"] = "true"
+		spec.Taints = append(spec.Taints, corev1.Taint{
+			Key:    resource,
+			Effect: corev1.TaintEffectNoSchedule,
+		})
+	}
+	return nil
+}
+
+// containerStorageDevice returns the device of the dedicated container storage
+// disk: the disk attached by the installer, or the existing disk of the
+// machines on the platforms on which no disk is attached.
+func containerStorageDevice(platform string, storage *types.ContainerStorage) string {
+	switch platform {
+	case awstypes.Name:
+		return aws.ContainerStorageDiskDevice
+	case azuretypes.Name:
+		return azure.ContainerStorageDiskDevice
+	case gcptypes.Name:
+		return gcp.ContainerStorageDiskDevice
+	default:
+		return storage.Device
+	}
+}
+
+// configContainerStorageMachineSets attaches the dedicated container storage
+// disk to the machines of the MachineSets, on the platforms on which the
+// installer attaches the disk.
+func configContainerStorageMachineSets(platform string, machineSets []runtime.Object, storage *types.ContainerStorage) error {
+	typed := make([]*machinev1beta1.MachineSet, 0, len(machineSets))
+	for _, obj := range machineSets {
+		machineSet, ok := obj.(*machinev1beta1.MachineSet)
+		if !ok {
+			return errors.Errorf("unexpected machine set type %T", obj)
+		}
+		typed = append(typed, machineSet)
+	}
+	switch platform {
+	case awstypes.Name:
+		return aws.ConfigContainerStorageDisk(typed, storage)
+	case azuretypes.Name:
+		return azure.ConfigContainerStorageDisk(typed, storage)
+	case gcptypes.Name:
+		return gcp.ConfigContainerStorageDisk(typed, storage)
+	default:
+		return nil
+	}
+}
+
+// configWindowsMachineSets labels the Windows MachineSets for the Windows
+// Machine Config Operator, which configures their machines with the user data
+// secret it manages.
+func configWindowsMachineSets(machineSets []runtime.Object) error {
+	for _, obj := range machineSets {
+		machineSet, ok := obj.(*machinev1beta1.MachineSet)
+		if !ok {
+			return errors.Errorf("unexpected machine set type %T", obj)
+		}
+		template := &machineSet.Spec.Template
+		if template.ObjectMeta.Labels == nil {
+			template.ObjectMeta.Labels = map[string]string{}
+		}
+		template.ObjectMeta.Labels[windowsOSIDLabel] = "Windows"
+
+		switch provider := template.Spec.ProviderSpec.Value.Object.(type) {
+		case *machinev1beta1.AWSMachineProviderConfig:
+			provider.UserDataSecret = &corev1.LocalObjectReference{Name: windowsUserDataSecretName}
+		case *machinev1beta1.AzureMachineProviderSpec:
+			provider.UserDataSecret = &corev1.SecretReference{Name: windowsUserDataSecretName}
+			provider.OSDisk.OSType = "Windows"
+		default:
+			return errors.Errorf("Windows machines are not supported by the %T provider", provider)
+		}
+	}
+	return nil
+}
+
+// zonesWithoutMachineSets returns the zones of the region, sorted, in which
+// the pool has no MachineSets.
+func zonesWithoutMachineSets(regionZones, poolZones []string) []string {
+	return sets.NewString(regionZones...).Delete(poolZones...).Delete("").List()
+}
+
+// zeroReplicaPool returns a copy of the pool without replicas, for the
+// MachineSets of the zones in which the pool has no machines at install.
+func zeroReplicaPool(pool types.MachinePool) types.MachinePool {
+	pool.Replicas = pointer.Int64(0)
+	return pool
+}
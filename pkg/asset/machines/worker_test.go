@@ -4,9 +4,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/pointer"
 
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/ignition/machine"
 	"github.com/openshift/installer/pkg/asset/installconfig"
@@ -235,3 +238,93 @@ func TestComputeIsNotModified(t *testing.T) {
 		t.Fatalf("compute in the install config has been modified")
 	}
 }
+
+func TestZonesWithoutMachineSets(t *testing.T) {
+	cases := []struct {
+		name        string
+		regionZones []string
+		poolZones   []string
+		expected    []string
+	}{
+		{
+			name:        "all zones used",
+			regionZones: []string{"us-east-1a", "us-east-1b"},
+			poolZones:   []string{"us-east-1b", "us-east-1a"},
+			expected:    []string{},
+		},
+		{
+			name:        "unused zones",
+			regionZones: []string{"us-east-1c", "us-east-1a", "us-east-1b"},
+			poolZones:   []string{"us-east-1a"},
+			expected:    []string{"us-east-1b", "us-east-1c"},
+		},
+		{
+			name:      "no zones in the region",
+			poolZones: []string{""},
+			expected:  []string{},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, zonesWithoutMachineSets(tc.regionZones, tc.poolZones))
+		})
+	}
+}
+
+func TestConfigWindowsMachineSets(t *testing.T) {
+	machineSet := &machinev1beta1.MachineSet{}
+	machineSet.Spec.Template.Spec.ProviderSpec.Value = &runtime.RawExtension{
+		Object: &machinev1beta1.AzureMachineProviderSpec{
+			UserDataSecret: &corev1.SecretReference{Name: "worker-user-data"},
+			OSDisk:         machinev1beta1.OSDisk{OSType: "Linux"},
+		},
+	}
+	if !assert.NoError(t, configWindowsMachineSets([]runtime.Object{machineSet})) {
+		return
+	}
+	assert.Equal(t, "Windows", machineSet.Spec.Template.ObjectMeta.Labels["machine.openshift.io/os-id"])
+	provider := machineSet.Spec.Template.Spec.ProviderSpec.Value.Object.(*machinev1beta1.AzureMachineProviderSpec)
+	assert.Equal(t, "windows-user-data", provider.UserDataSecret.Name)
+	assert.Equal(t, "Windows", provider.OSDisk.OSType)
+
+	machineSet.Spec.Template.Spec.ProviderSpec.Value.Object = &machinev1beta1.GCPMachineProviderSpec{}
+	assert.EqualError(t, configWindowsMachineSets([]runtime.Object{machineSet}), "Windows machines are not supported by the *v1beta1.GCPMachineProviderSpec provider")
+}
+
+func TestConfigAcceleratorMachineSets(t *testing.T) {
+	machineSet := &machinev1beta1.MachineSet{}
+	if !assert.NoError(t, configAcceleratorMachineSets([]runtime.Object{machineSet}, types.AcceleratorVendorNVIDIA)) {
+		return
+	}
+	assert.Equal(t, map[string]string{"nvidia.com/gpu.present": "true"}, machineSet.Spec.Template.Spec.ObjectMeta.Labels)
+	assert.Equal(t, []corev1.Taint{{Key: "nvidia.com/gpu", Effect: corev1.TaintEffectNoSchedule}}, machineSet.Spec.Template.Spec.Taints)
+}
+
+func TestConfigContainerStorageMachineSets(t *testing.T) {
+	machineSet := &machinev1beta1.MachineSet{}
+	machineSet.Spec.Template.Spec.ProviderSpec.Value = &runtime.RawExtension{
+		Object: &machinev1beta1.AWSMachineProviderConfig{
+			BlockDevices: []machinev1beta1.BlockDeviceMappingSpec{{
+				EBS: &machinev1beta1.EBSBlockDeviceSpec{Encrypted: pointer.BoolPtr(true)},
+			}},
+		},
+	}
+	storage := &types.ContainerStorage{MountPath: types.ContainerStorageMountPathContainers, SizeGB: 100, Type: "gp3"}
+	if !assert.NoError(t, configContainerStorageMachineSets(awstypes.Name, []runtime.Object{machineSet}, storage)) {
+		return
+	}
+	provider := machineSet.Spec.Template.Spec.ProviderSpec.Value.Object.(*machinev1beta1.AWSMachineProviderConfig)
+	if assert.Len(t, provider.BlockDevices, 2) {
+		assert.Equal(t, machinev1beta1.BlockDeviceMappingSpec{
+			DeviceName: pointer.StringPtr("/dev/xvdb"),
+			EBS: &machinev1beta1.EBSBlockDeviceSpec{
+				VolumeType: pointer.StringPtr("gp3"),
+				VolumeSize: pointer.Int64Ptr(100),
+				Encrypted:  pointer.BoolPtr(true),
+			},
+		}, provider.BlockDevices[1])
+	}
+
+	assert.Equal(t, "/dev/xvdb", containerStorageDevice(awstypes.Name, storage))
+	assert.Equal(t, "/dev/sdb", containerStorageDevice("baremetal", &types.ContainerStorage{Device: "/dev/sdb"}))
+}
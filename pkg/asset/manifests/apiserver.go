@@ -0,0 +1,127 @@
+package manifests
+
+import (
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
+)
+
+var apiServerCfgFilename = filepath.Join(manifestDir, "cluster-apiserver-02-config.yml")
+
+// APIServerConfig generates the APIServer config of the cluster, with the
+// audit policy, the encryption and the API serving certificate of the
+// install-config.
+type APIServerConfig struct {
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*APIServerConfig)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*APIServerConfig) Name() string {
+	return "APIServer Config"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*APIServerConfig) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the APIServer config. It is only generated when the
+// install-config configures the API servers or their serving certificate, so
+// that the defaults of the cluster apply otherwise.
+func (a *APIServerConfig) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	a.FileList = nil
+	ic := installConfig.Config
+	servingCert := ic.ServingCertificates != nil && ic.ServingCertificates.API != nil
+	if ic.APIServer == nil && !servingCert {
+		return nil
+	}
+
+	config := &apiServer{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: configv1.SchemeGroupVersion.String(),
+			Kind:       "APIServer",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+			// not namespaced
+		},
+	}
+	if servingCert {
+		// the secret is generated with the Openshift asset
+		config.Spec.ServingCerts.NamedCertificates = []configv1.APIServerNamedServingCert{{
+			Names:              []string{ic.APIDomainName()},
+			ServingCertificate: configv1.SecretNameReference{Name: apiServingCertSecret},
+		}}
+	}
+	if ic.APIServer != nil {
+		config.Spec.Audit.Profile = ic.APIServer.AuditProfile
+		if e := ic.APIServer.Encryption; e != nil {
+			config.Spec.Encryption.Type = configv1.EncryptionType(e.Type)
+			if e.KMS != nil && e.KMS.AWS != nil {
+				config.Spec.Encryption.KMS = &kmsConfig{Type: "AWS", AWS: e.KMS.AWS}
+			}
+		}
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", a.Name())
+	}
+	a.FileList = []*asset.File{{
+		Filename: apiServerCfgFilename,
+		Data:     data,
+	}}
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (a *APIServerConfig) Files() []*asset.File {
+	return a.FileList
+}
+
+// Load returns false since this asset is not written to disk by the installer.
+func (a *APIServerConfig) Load(f asset.FileFetcher) (bool, error) {
+	return false, nil
+}
+
+// apiServer is the APIServer config with the KMS of the encryption, which
+// the vendored API does not have yet.
+type apiServer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   apiServerSpec            `json:"spec"`
+	Status configv1.APIServerStatus `json:"status"`
+}
+
+type apiServerSpec struct {
+	configv1.APIServerSpec `json:",inline"`
+
+	Encryption apiServerEncryption `json:"encryption"`
+}
+
+type apiServerEncryption struct {
+	configv1.APIServerEncryption `json:",inline"`
+
+	KMS *kmsConfig `json:"kms,omitempty"`
+}
+
+type kmsConfig struct {
+	Type string              `json:"type"`
+	AWS  *types.AWSKMSConfig `json:"aws,omitempty"`
+}
@@ -0,0 +1,115 @@
+package manifests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestGenerateAPIServerConfig(t *testing.T) {
+	cases := []struct {
+		name                string
+		apiServer           *types.APIServer
+		servingCertificates *types.ServingCertificates
+		expectedData        string
+	}{
+		{
+			name: "defaults",
+		},
+		{
+			name: "ingress serving certificate",
+			servingCertificates: &types.ServingCertificates{
+				Ingress: &types.ServingCertificate{Certificate: "test-cert", Key: "test-key"},
+			},
+		},
+		{
+			name: "audit profile and aesgcm encryption",
+			apiServer: &types.APIServer{
+				AuditProfile: configv1.WriteRequestBodiesAuditProfileType,
+				Encryption:   &types.APIServerEncryption{Type: types.EncryptionTypeAESGCM},
+			},
+			expectedData: `apiVersion: config.openshift.io/v1
+kind: APIServer
+metadata:
+  creationTimestamp: null
+  name: cluster
+spec:
+  audit:
+    profile: WriteRequestBodies
+  clientCA:
+    name: ""
+  encryption:
+    type: aesgcm
+  servingCerts: {}
+status: {}
+`,
+		},
+		{
+			name: "KMS encryption and API serving certificate",
+			apiServer: &types.APIServer{
+				Encryption: &types.APIServerEncryption{
+					Type: types.EncryptionTypeKMS,
+					KMS: &types.KMSConfig{
+						AWS: &types.AWSKMSConfig{
+							KeyARN: "arn:aws:kms:us-east-1:123456789012:key/test-key",
+							Region: "us-east-1",
+						},
+					},
+				},
+			},
+			servingCertificates: &types.ServingCertificates{
+				API: &types.ServingCertificate{Certificate: "test-cert", Key: "test-key"},
+			},
+			expectedData: `apiVersion: config.openshift.io/v1
+kind: APIServer
+metadata:
+  creationTimestamp: null
+  name: cluster
+spec:
+  audit: {}
+  clientCA:
+    name: ""
+  encryption:
+    kms:
+      aws:
+        keyARN: arn:aws:kms:us-east-1:123456789012:key/test-key
+        region: us-east-1
+      type: AWS
+    type: KMS
+  servingCerts:
+    namedCertificates:
+    - names:
+      - api.test-cluster.test-domain
+      servingCertificate:
+        name: api-serving-cert
+status: {}
+`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ic := icBuild.build(icBuild.forAWS())
+			ic.APIServer = tc.apiServer
+			ic.ServingCertificates = tc.servingCertificates
+			parents := asset.Parents{}
+			parents.Add(&installconfig.InstallConfig{Config: ic})
+			apiServerConfig := &APIServerConfig{}
+			if !assert.NoError(t, apiServerConfig.Generate(parents), "failed to generate asset") {
+				return
+			}
+			if tc.expectedData == "" {
+				assert.Empty(t, apiServerConfig.Files())
+				return
+			}
+			if assert.Len(t, apiServerConfig.Files(), 1) {
+				assert.Equal(t, "manifests/cluster-apiserver-02-config.yml", apiServerConfig.Files()[0].Filename)
+				assert.Equal(t, tc.expectedData, string(apiServerConfig.Files()[0].Data))
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package manifests
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/alibabacloud"
+	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/azure"
+	"github.com/openshift/installer/pkg/types/gcp"
+	"github.com/openshift/installer/pkg/types/ibmcloud"
+	"github.com/openshift/installer/pkg/types/nutanix"
+	"github.com/openshift/installer/pkg/types/powervs"
+)
+
+// credentialsSecret is a Secret the cloud-credential-operator creates from a
+// CredentialsRequest of the release image, which must be provided with the
+// manifests in the Manual credentials mode.
+type credentialsSecret struct {
+	namespace string
+	name      string
+	// capability is the optional capability of the operator consuming the
+	// secret, the secret is not required when the capability is disabled.
+	capability configv1.ClusterVersionCapability
+}
+
+func (s credentialsSecret) String() string {
+	return fmt.Sprintf("%s/%s", s.namespace, s.name)
+}
+
+// manualCredentialsSecrets are the secrets of the CredentialsRequests of each
+// platform, see `oc adm release extract --credentials-requests`.
+var manualCredentialsSecrets = map[string][]credentialsSecret{
+	alibabacloud.Name: {
+		{namespace: "openshift-machine-api", name: "alibabacloud-credentials"},
+		{namespace: "openshift-image-registry", name: "installer-cloud-credentials"},
+		{namespace: "openshift-ingress-operator", name: "cloud-credentials"},
+		{namespace: "openshift-cluster-csi-drivers", name: "alibaba-disk-credentials", capability: configv1.ClusterVersionCapabilityStorage},
+	},
+	aws.Name: {
+		{namespace: "openshift-machine-api", name: "aws-cloud-credentials"},
+		{namespace: "openshift-cloud-credential-operator", name: "cloud-credential-operator-iam-ro-creds"},
+		{namespace: "openshift-image-registry", name: "installer-cloud-credentials"},
+		{namespace: "openshift-ingress-operator", name: "cloud-credentials"},
+		{namespace: "openshift-cloud-network-config-controller", name: "cloud-credentials"},
+		{namespace: "openshift-cluster-csi-drivers", name: "ebs-cloud-credentials", capability: configv1.ClusterVersionCapabilityStorage},
+	},
+	azure.Name: {
+		{namespace: "openshift-machine-api", name: "azure-cloud-credentials"},
+		{namespace: "openshift-image-registry", name: "installer-cloud-credentials"},
+		{namespace: "openshift-ingress-operator", name: "cloud-credentials"},
+		{namespace: "openshift-cloud-network-config-controller", name: "cloud-credentials"},
+		{namespace: "openshift-cloud-controller-manager", name: "azure-cloud-credentials"},
+		{namespace: "openshift-cluster-csi-drivers", name: "azure-disk-credentials", capability: configv1.ClusterVersionCapabilityStorage},
+		{namespace: "openshift-cluster-csi-drivers", name: "azure-file-credentials", capability: configv1.ClusterVersionCapabilityStorage},
+	},
+	gcp.Name: {
+		{namespace: "openshift-machine-api", name: "gcp-cloud-credentials"},
+		{namespace: "openshift-cloud-credential-operator", name: "cloud-credential-operator-gcp-ro-creds"},
+		{namespace: "openshift-image-registry", name: "installer-cloud-credentials"},
+		{namespace: "openshift-ingress-operator", name: "cloud-credentials"},
+		{namespace: "openshift-cloud-network-config-controller", name: "cloud-credentials"},
+		{namespace: "openshift-cloud-controller-manager", name: "gcp-ccm-cloud-credentials"},
+		{namespace: "openshift-cluster-csi-drivers", name: "gcp-pd-cloud-credentials", capability: configv1.ClusterVersionCapabilityStorage},
+	},
+	ibmcloud.Name: {
+		{namespace: "openshift-machine-api", name: "ibmcloud-credentials"},
+		{namespace: "openshift-image-registry", name: "installer-cloud-credentials"},
+		{namespace: "openshift-ingress-operator", name: "cloud-credentials"},
+		{namespace: "openshift-cloud-controller-manager", name: "ibm-cloud-credentials"},
+		{namespace: "openshift-cluster-csi-drivers", name: "ibm-cloud-credentials", capability: configv1.ClusterVersionCapabilityStorage},
+	},
+	nutanix.Name: {
+		{namespace: "openshift-machine-api", name: "nutanix-credentials"},
+	},
+	powervs.Name: {
+		{namespace: "openshift-machine-api", name: "powervs-credentials"},
+		{namespace: "openshift-image-registry", name: "installer-cloud-credentials"},
+		{namespace: "openshift-ingress-operator", name: "cloud-credentials"},
+		{namespace: "openshift-cloud-controller-manager", name: "ibm-cloud-credentials"},
+	},
+}
+
+// ManualCredentialsCheck is an asset that checks that the secrets of the
+// CredentialsRequests have been added to the manifests when the credentials
+// mode is Manual, as the operators consuming them would otherwise be degraded.
+type ManualCredentialsCheck struct {
+}
+
+var _ asset.Asset = (*ManualCredentialsCheck)(nil)
+
+// Name returns the human-friendly name of the asset.
+func (a *ManualCredentialsCheck) Name() string {
+	return "Manual Credentials Check"
+}
+
+// Dependencies returns the dependencies for ManualCredentialsCheck.
+func (a *ManualCredentialsCheck) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+		&Manifests{},
+		&Openshift{},
+	}
+}
+
+// Generate checks that the secrets required by the platform are provided.
+func (a *ManualCredentialsCheck) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	manifests := &Manifests{}
+	openshiftManifests := &Openshift{}
+	dependencies.Get(installConfig, manifests, openshiftManifests)
+
+	if installConfig.Config.CredentialsMode != types.ManualCredentialsMode {
+		return nil
+	}
+	if skip := os.Getenv("OPENSHIFT_INSTALL_SKIP_PREFLIGHT_VALIDATIONS"); skip == "1" {
+		logrus.Warnf("OVERRIDE: pre-flight validation disabled.")
+		return nil
+	}
+
+	files := append(manifests.Files(), openshiftManifests.Files()...)
+	missing, err := missingCredentialsSecrets(installConfig.Config, files)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return errors.Errorf("credentialsMode is Manual but the manifests do not include the secrets %s, extract the CredentialsRequests of the release image with `oc adm release extract --credentials-requests` and add a secret for each of them", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// missingCredentialsSecrets returns the secrets required by the platform and
+// enabled capabilities which are not included in the files.
+func missingCredentialsSecrets(ic *types.InstallConfig, files []*asset.File) ([]string, error) {
+	provided := map[string]bool{}
+	for _, file := range files {
+		for _, doc := range bytes.Split(file.Data, []byte("\n---")) {
+			obj := struct {
+				Kind     string `json:"kind"`
+				Metadata struct {
+					Name      string `json:"name"`
+					Namespace string `json:"namespace"`
+				} `json:"metadata"`
+			}{}
+			if err := yaml.Unmarshal(doc, &obj); err != nil {
+				return nil, errors.Wrapf(err, "failed to unmarshal %s", file.Filename)
+			}
+			if obj.Kind == "Secret" {
+				provided[credentialsSecret{namespace: obj.Metadata.Namespace, name: obj.Metadata.Name}.String()] = true
+			}
+		}
+	}
+
+	var missing []string
+	for _, secret := range manualCredentialsSecrets[ic.Platform.Name()] {
+		if secret.capability != "" && !ic.IsCapabilityEnabled(secret.capability) {
+			continue
+		}
+		if !provided[secret.String()] {
+			missing = append(missing, secret.String())
+		}
+	}
+	return missing, nil
+}
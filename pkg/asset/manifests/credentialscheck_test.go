@@ -0,0 +1,102 @@
+package manifests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/ibmcloud"
+	"github.com/openshift/installer/pkg/types/nutanix"
+)
+
+func secretFile(namespace, name string) *asset.File {
+	return &asset.File{
+		Filename: fmt.Sprintf("manifests/%s-%s-credentials.yaml", namespace, name),
+		Data: []byte(fmt.Sprintf(`apiVersion: v1
+kind: Secret
+metadata:
+  name: %s
+  namespace: %s
+stringData:
+  credentials: ""
+`, name, namespace)),
+	}
+}
+
+func TestMissingCredentialsSecrets(t *testing.T) {
+	cases := []struct {
+		name         string
+		platform     types.Platform
+		capabilities *types.Capabilities
+		files        []*asset.File
+		expected     []string
+	}{
+		{
+			name:     "nutanix secret missing",
+			platform: types.Platform{Nutanix: &nutanix.Platform{}},
+			expected: []string{"openshift-machine-api/nutanix-credentials"},
+		},
+		{
+			name:     "nutanix secret provided",
+			platform: types.Platform{Nutanix: &nutanix.Platform{}},
+			files:    []*asset.File{secretFile("openshift-machine-api", "nutanix-credentials")},
+		},
+		{
+			name:     "secret provided in a multi-document manifest",
+			platform: types.Platform{Nutanix: &nutanix.Platform{}},
+			files: []*asset.File{{
+				Filename: "manifests/credentials.yaml",
+				Data:     append([]byte("apiVersion: v1\nkind: Namespace\nmetadata:\n  name: openshift-machine-api\n---\n"), secretFile("openshift-machine-api", "nutanix-credentials").Data...),
+			}},
+		},
+		{
+			name:     "configmap is not a secret",
+			platform: types.Platform{Nutanix: &nutanix.Platform{}},
+			files: []*asset.File{{
+				Filename: "manifests/credentials.yaml",
+				Data:     []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: nutanix-credentials\n  namespace: openshift-machine-api\n"),
+			}},
+			expected: []string{"openshift-machine-api/nutanix-credentials"},
+		},
+		{
+			name:     "storage secrets missing",
+			platform: types.Platform{IBMCloud: &ibmcloud.Platform{}},
+			files: []*asset.File{
+				secretFile("openshift-machine-api", "ibmcloud-credentials"),
+				secretFile("openshift-image-registry", "installer-cloud-credentials"),
+				secretFile("openshift-ingress-operator", "cloud-credentials"),
+				secretFile("openshift-cloud-controller-manager", "ibm-cloud-credentials"),
+			},
+			expected: []string{"openshift-cluster-csi-drivers/ibm-cloud-credentials"},
+		},
+		{
+			name:     "storage capability disabled",
+			platform: types.Platform{IBMCloud: &ibmcloud.Platform{}},
+			capabilities: &types.Capabilities{
+				BaselineCapabilitySet: configv1.ClusterVersionCapabilitySetNone,
+			},
+			files: []*asset.File{
+				secretFile("openshift-machine-api", "ibmcloud-credentials"),
+				secretFile("openshift-image-registry", "installer-cloud-credentials"),
+				secretFile("openshift-ingress-operator", "cloud-credentials"),
+				secretFile("openshift-cloud-controller-manager", "ibm-cloud-credentials"),
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ic := &types.InstallConfig{
+				Platform:        tc.platform,
+				Capabilities:    tc.capabilities,
+				CredentialsMode: types.ManualCredentialsMode,
+			}
+			missing, err := missingCredentialsSecrets(ic, tc.files)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, missing)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package manifests
+
+import (
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+)
+
+var etcdCfgFilename = filepath.Join(manifestDir, "cluster-etcd-02-config.yml")
+
+// EtcdConfig generates the etcd operator config selecting the latency profile
+// of the etcd section of the install-config.
+type EtcdConfig struct {
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*EtcdConfig)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*EtcdConfig) Name() string {
+	return "Etcd Config"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*EtcdConfig) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the etcd operator config.
+func (e *EtcdConfig) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	e.FileList = nil
+	etcd := installConfig.Config.Etcd
+	if etcd == nil || etcd.LatencyProfile == "" {
+		return nil
+	}
+
+	// the vendored operator API predates controlPlaneHardwareSpeed
+	config := map[string]interface{}{
+		"apiVersion": "operator.openshift.io/v1",
+		"kind":       "Etcd",
+		"metadata": map[string]string{
+			"name": "cluster",
+		},
+		"spec": map[string]string{
+			"managementState":           "Managed",
+			"controlPlaneHardwareSpeed": string(etcd.LatencyProfile),
+		},
+	}
+	configData, err := yaml.Marshal(config)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", e.Name())
+	}
+	e.FileList = []*asset.File{{
+		Filename: etcdCfgFilename,
+		Data:     configData,
+	}}
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (e *EtcdConfig) Files() []*asset.File {
+	return e.FileList
+}
+
+// Load returns false since this asset is not written to disk by the installer.
+func (e *EtcdConfig) Load(f asset.FileFetcher) (bool, error) {
+	return false, nil
+}
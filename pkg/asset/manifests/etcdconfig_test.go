@@ -0,0 +1,59 @@
+package manifests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestGenerateEtcdConfig(t *testing.T) {
+	cases := []struct {
+		name         string
+		etcd         *types.Etcd
+		expectedData string
+	}{
+		{
+			name: "no etcd",
+		},
+		{
+			name: "no latency profile",
+			etcd: &types.Etcd{Disk: &types.EtcdDisk{SizeGB: 32, Type: "gp3"}},
+		},
+		{
+			name: "slower latency profile",
+			etcd: &types.Etcd{LatencyProfile: types.EtcdLatencyProfileSlower},
+			expectedData: `apiVersion: operator.openshift.io/v1
+kind: Etcd
+metadata:
+  name: cluster
+spec:
+  controlPlaneHardwareSpeed: Slower
+  managementState: Managed
+`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ic := icBuild.build(icBuild.forNone())
+			ic.Etcd = tc.etcd
+			parents := asset.Parents{}
+			parents.Add(&installconfig.InstallConfig{Config: ic})
+			etcdConfig := &EtcdConfig{}
+			if !assert.NoError(t, etcdConfig.Generate(parents), "failed to generate asset") {
+				return
+			}
+			if tc.expectedData == "" {
+				assert.Empty(t, etcdConfig.Files())
+				return
+			}
+			if assert.Len(t, etcdConfig.Files(), 1) {
+				assert.Equal(t, "manifests/cluster-etcd-02-config.yml", etcdConfig.Files()[0].Filename)
+				assert.Equal(t, tc.expectedData, string(etcdConfig.Files()[0].Data))
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package manifests
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+)
+
+// FeatureSetCheck is an asset that checks that the feature set of the cluster
+// FeatureGate in the manifests is the featureSet of the install-config.
+//
+// The installer validates the fields of the install-config gated by a feature
+// set, and renders the bootstrap control plane, with the featureSet of the
+// install-config. A FeatureGate of the manifests which sets another feature
+// set, or its removal, would make the cluster silently drop the gated
+// features once the bootstrapping is complete.
+type FeatureSetCheck struct {
+}
+
+var _ asset.Asset = (*FeatureSetCheck)(nil)
+
+// Name returns the human-friendly name of the asset.
+func (a *FeatureSetCheck) Name() string {
+	return "Feature Set Check"
+}
+
+// Dependencies returns the dependencies for FeatureSetCheck.
+func (a *FeatureSetCheck) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+		&Manifests{},
+		&Openshift{},
+	}
+}
+
+// Generate checks the feature set of the FeatureGate of the manifests.
+func (a *FeatureSetCheck) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	manifests := &Manifests{}
+	openshiftManifests := &Openshift{}
+	dependencies.Get(installConfig, manifests, openshiftManifests)
+
+	files := append(manifests.Files(), openshiftManifests.Files()...)
+	return checkFeatureSet(installConfig.Config.FeatureSet, files)
+}
+
+// checkFeatureSet returns an error if the cluster FeatureGate of the files does
+// not set the feature set, or if there is none and the feature set is not the
+// default one.
+func checkFeatureSet(featureSet configv1.FeatureSet, files []*asset.File) error {
+	found := false
+	for _, file := range files {
+		for _, doc := range bytes.Split(file.Data, []byte("\n---")) {
+			obj := struct {
+				APIVersion string `json:"apiVersion"`
+				Kind       string `json:"kind"`
+				Metadata   struct {
+					Name string `json:"name"`
+				} `json:"metadata"`
+				Spec configv1.FeatureGateSelection `json:"spec"`
+			}{}
+			if err := yaml.Unmarshal(doc, &obj); err != nil {
+				return errors.Wrapf(err, "failed to unmarshal %s", file.Filename)
+			}
+			if obj.APIVersion != configv1.SchemeGroupVersion.String() || obj.Kind != "FeatureGate" || obj.Metadata.Name != "cluster" {
+				continue
+			}
+			found = true
+			if obj.Spec.FeatureSet != featureSet {
+				return errors.Errorf("the FeatureGate of %s sets the feature set %s, but the featureSet of the install-config is %s; set the feature set in the install-config instead, so that its gated fields are validated and rendered with it", file.Filename, featureSetName(obj.Spec.FeatureSet), featureSetName(featureSet))
+			}
+		}
+	}
+	if !found && featureSet != configv1.Default {
+		return errors.Errorf("the featureSet of the install-config is %s, but the manifests do not include the FeatureGate enabling it, %s", featureSetName(featureSet), fgFileName)
+	}
+	return nil
+}
+
+// featureSetName returns the quoted name of the feature set, including the
+// default one, whose name is empty.
+func featureSetName(featureSet configv1.FeatureSet) string {
+	if featureSet == configv1.Default {
+		return `"Default"`
+	}
+	return fmt.Sprintf("%q", featureSet)
+}
@@ -0,0 +1,70 @@
+package manifests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/installer/pkg/asset"
+)
+
+func featureGateFile(filename string, featureSet configv1.FeatureSet) *asset.File {
+	data := "apiVersion: config.openshift.io/v1\nkind: FeatureGate\nmetadata:\n  name: cluster\nspec: {}\n"
+	if featureSet != configv1.Default {
+		data = "apiVersion: config.openshift.io/v1\nkind: FeatureGate\nmetadata:\n  name: cluster\nspec:\n  featureSet: " + string(featureSet) + "\n"
+	}
+	return &asset.File{Filename: filename, Data: []byte(data)}
+}
+
+func TestCheckFeatureSet(t *testing.T) {
+	cases := []struct {
+		name          string
+		featureSet    configv1.FeatureSet
+		files         []*asset.File
+		expectedError string
+	}{
+		{
+			name: "default feature set without feature gate",
+		},
+		{
+			name:       "feature gate of the install-config",
+			featureSet: configv1.TechPreviewNoUpgrade,
+			files:      []*asset.File{featureGateFile(fgFileName, configv1.TechPreviewNoUpgrade)},
+		},
+		{
+			name:          "feature gate removed",
+			featureSet:    configv1.TechPreviewNoUpgrade,
+			expectedError: `^the featureSet of the install-config is "TechPreviewNoUpgrade", but the manifests do not include the FeatureGate enabling it, openshift/99_feature-gate\.yaml$`,
+		},
+		{
+			name:          "feature gate added to the manifests",
+			files:         []*asset.File{featureGateFile("manifests/feature-gate.yaml", configv1.TechPreviewNoUpgrade)},
+			expectedError: `^the FeatureGate of manifests/feature-gate\.yaml sets the feature set "TechPreviewNoUpgrade", but the featureSet of the install-config is "Default"`,
+		},
+		{
+			name:          "feature gate reset to the default feature set",
+			featureSet:    configv1.TechPreviewNoUpgrade,
+			files:         []*asset.File{featureGateFile(fgFileName, configv1.Default)},
+			expectedError: `^the FeatureGate of openshift/99_feature-gate\.yaml sets the feature set "Default", but the featureSet of the install-config is "TechPreviewNoUpgrade"`,
+		},
+		{
+			name:       "other feature gate in a multi-document manifest",
+			featureSet: configv1.TechPreviewNoUpgrade,
+			files: []*asset.File{{
+				Filename: "manifests/feature-gates.yaml",
+				Data:     append([]byte("apiVersion: config.openshift.io/v1\nkind: FeatureGate\nmetadata:\n  name: other\nspec: {}\n---\n"), featureGateFile("", configv1.TechPreviewNoUpgrade).Data...),
+			}},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkFeatureSet(tc.featureSet, tc.files)
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package manifests
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+)
+
+var (
+	imageCfgFilename             = filepath.Join(manifestDir, "cluster-image-02-config.yml")
+	registryCAsConfigMapFilename = filepath.Join(manifestDir, "registry-cas-config.yaml")
+)
+
+const registryCAsConfigMapName = "registry-cas"
+
+// ImageConfig generates the image config trusting the CA certificates of the
+// registries in the additionalTrustedCA of the install-config.
+type ImageConfig struct {
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*ImageConfig)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*ImageConfig) Name() string {
+	return "Image Config"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*ImageConfig) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the image config and the config map of the registry CA
+// certificates it references.
+func (i *ImageConfig) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	i.FileList = nil
+	cas := installConfig.Config.AdditionalTrustedCA
+	if len(cas) == 0 {
+		return nil
+	}
+
+	data := make(map[string]string, len(cas))
+	for host, ca := range cas {
+		data[registryCAKey(host)] = ca
+	}
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "openshift-config",
+			Name:      registryCAsConfigMapName,
+		},
+		Data: data,
+	}
+	config := &configv1.Image{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: configv1.SchemeGroupVersion.String(),
+			Kind:       "Image",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+			// not namespaced
+		},
+		Spec: configv1.ImageSpec{
+			AdditionalTrustedCA: configv1.ConfigMapNameReference{Name: registryCAsConfigMapName},
+		},
+	}
+
+	for _, m := range []struct {
+		filename string
+		object   interface{}
+	}{
+		{filename: registryCAsConfigMapFilename, object: cm},
+		{filename: imageCfgFilename, object: config},
+	} {
+		configData, err := yaml.Marshal(m.object)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", i.Name())
+		}
+		i.FileList = append(i.FileList, &asset.File{
+			Filename: m.filename,
+			Data:     configData,
+		})
+	}
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (i *ImageConfig) Files() []*asset.File {
+	return i.FileList
+}
+
+// Load returns false since this asset is not written to disk by the installer.
+func (i *ImageConfig) Load(f asset.FileFetcher) (bool, error) {
+	return false, nil
+}
+
+// registryCAKey returns the key of the CA certificates of the registry host
+// in the additionalTrustedCA config map. Config map keys cannot contain
+// colons, so the port is separated by two dots instead.
+func registryCAKey(host string) string {
+	return strings.Replace(host, ":", "..", 1)
+}
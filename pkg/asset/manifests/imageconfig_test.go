@@ -0,0 +1,73 @@
+package manifests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+)
+
+func TestGenerateImageConfig(t *testing.T) {
+	cases := []struct {
+		name          string
+		cas           map[string]string
+		expectedFiles map[string]string
+	}{
+		{
+			name: "no additional trusted CA",
+		},
+		{
+			name: "additional trusted CA",
+			cas: map[string]string{
+				"registry.example.com":    "registry-ca",
+				"mirror.example.com:5000": "mirror-ca",
+			},
+			expectedFiles: map[string]string{
+				"manifests/registry-cas-config.yaml": `apiVersion: v1
+data:
+  mirror.example.com..5000: mirror-ca
+  registry.example.com: registry-ca
+kind: ConfigMap
+metadata:
+  creationTimestamp: null
+  name: registry-cas
+  namespace: openshift-config
+`,
+				"manifests/cluster-image-02-config.yml": `apiVersion: config.openshift.io/v1
+kind: Image
+metadata:
+  creationTimestamp: null
+  name: cluster
+spec:
+  additionalTrustedCA:
+    name: registry-cas
+  registrySources: {}
+status: {}
+`,
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ic := icBuild.build(icBuild.forNone())
+			ic.AdditionalTrustedCA = tc.cas
+			parents := asset.Parents{}
+			parents.Add(&installconfig.InstallConfig{Config: ic})
+			imageConfig := &ImageConfig{}
+			if !assert.NoError(t, imageConfig.Generate(parents), "failed to generate asset") {
+				return
+			}
+			files := map[string]string{}
+			for _, f := range imageConfig.Files() {
+				files[f.Filename] = string(f.Data)
+			}
+			if len(tc.expectedFiles) == 0 {
+				assert.Empty(t, files)
+				return
+			}
+			assert.Equal(t, tc.expectedFiles, files)
+		})
+	}
+}
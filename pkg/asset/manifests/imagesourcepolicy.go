@@ -8,14 +8,21 @@ import (
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	configv1 "github.com/openshift/api/config/v1"
 	operatorv1alpha1 "github.com/openshift/api/operator/v1alpha1"
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
 )
 
-var imageContentSourcePolicyFilenameFormat = "image-content-source-policy-%s.yaml"
+var (
+	imageContentSourcePolicyFilenameFormat = "image-content-source-policy-%s.yaml"
+	imageDigestMirrorSetFilenameFormat     = "image-digest-mirror-set-%s.yaml"
+)
 
-// ImageContentSourcePolicy generates the image-content-source-policy.yaml files.
+// ImageContentSourcePolicy generates the image-content-source-policy.yaml
+// files from imageContentSources, and the image-digest-mirror-set.yaml files
+// from imageDigestSources.
 type ImageContentSourcePolicy struct {
 	FileList []*asset.File
 }
@@ -35,43 +42,88 @@ func (*ImageContentSourcePolicy) Dependencies() []asset.Asset {
 	}
 }
 
-// Generate generates the ImageContentSourcePolicy config and its CRD.
+// Generate generates the ImageContentSourcePolicy and ImageDigestMirrorSet
+// configs.
 func (p *ImageContentSourcePolicy) Generate(dependencies asset.Parents) error {
 	installconfig := &installconfig.InstallConfig{}
 	dependencies.Get(installconfig)
 
-	padFormat := fmt.Sprintf("%%0%dd", len(fmt.Sprintf("%d", len(installconfig.Config.ImageContentSources))))
+	policies, err := imageContentSourcePolicies(installconfig.Config)
+	if err != nil {
+		return err
+	}
+	mirrorSets, err := imageDigestMirrorSets(installconfig.Config)
+	if err != nil {
+		return err
+	}
+	p.FileList = append(policies, mirrorSets...)
+	return nil
+}
+
+func imageContentSourcePolicies(ic *types.InstallConfig) ([]*asset.File, error) {
+	padFormat := fmt.Sprintf("%%0%dd", len(fmt.Sprintf("%d", len(ic.ImageContentSources))))
 
-	var policies []*operatorv1alpha1.ImageContentSourcePolicy
-	for gidx, group := range installconfig.Config.ImageContentSources {
-		policies = append(policies, &operatorv1alpha1.ImageContentSourcePolicy{
+	files := make([]*asset.File, 0, len(ic.ImageContentSources))
+	for gidx, group := range ic.ImageContentSources {
+		padded := fmt.Sprintf(padFormat, gidx)
+		policy := &operatorv1alpha1.ImageContentSourcePolicy{
 			TypeMeta: metav1.TypeMeta{
 				APIVersion: operatorv1alpha1.SchemeGroupVersion.String(),
 				Kind:       "ImageContentSourcePolicy",
 			},
 			ObjectMeta: metav1.ObjectMeta{
-				Name: fmt.Sprintf("image-policy-%s", fmt.Sprintf(padFormat, gidx)),
+				Name: fmt.Sprintf("image-policy-%s", padded),
 				// not namespaced
 			},
 			Spec: operatorv1alpha1.ImageContentSourcePolicySpec{
 				RepositoryDigestMirrors: []operatorv1alpha1.RepositoryDigestMirrors{{Source: group.Source, Mirrors: group.Mirrors}},
 			},
-		})
-	}
-
-	p.FileList = make([]*asset.File, len(policies))
-	for i, policy := range policies {
+		}
 		policyData, err := yaml.Marshal(policy)
 		if err != nil {
-			return errors.Wrapf(err, "failed to marshal ImageContentSourcePolicy")
+			return nil, errors.Wrapf(err, "failed to marshal ImageContentSourcePolicy")
 		}
-		padded := fmt.Sprintf(padFormat, i)
-		p.FileList[i] = &asset.File{
+		files = append(files, &asset.File{
 			Filename: filepath.Join(manifestDir, fmt.Sprintf(imageContentSourcePolicyFilenameFormat, padded)),
 			Data:     policyData,
+		})
+	}
+	return files, nil
+}
+
+func imageDigestMirrorSets(ic *types.InstallConfig) ([]*asset.File, error) {
+	padFormat := fmt.Sprintf("%%0%dd", len(fmt.Sprintf("%d", len(ic.ImageDigestSources))))
+
+	files := make([]*asset.File, 0, len(ic.ImageDigestSources))
+	for gidx, group := range ic.ImageDigestSources {
+		padded := fmt.Sprintf(padFormat, gidx)
+		mirrors := make([]configv1.ImageMirror, 0, len(group.Mirrors))
+		for _, mirror := range group.Mirrors {
+			mirrors = append(mirrors, configv1.ImageMirror(mirror))
 		}
+		mirrorSet := &configv1.ImageDigestMirrorSet{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: configv1.SchemeGroupVersion.String(),
+				Kind:       "ImageDigestMirrorSet",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: fmt.Sprintf("image-digest-mirror-%s", padded),
+				// not namespaced
+			},
+			Spec: configv1.ImageDigestMirrorSetSpec{
+				ImageDigestMirrors: []configv1.ImageDigestMirrors{{Source: group.Source, Mirrors: mirrors}},
+			},
+		}
+		mirrorSetData, err := yaml.Marshal(mirrorSet)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal ImageDigestMirrorSet")
+		}
+		files = append(files, &asset.File{
+			Filename: filepath.Join(manifestDir, fmt.Sprintf(imageDigestMirrorSetFilenameFormat, padded)),
+			Data:     mirrorSetData,
+		})
 	}
-	return nil
+	return files, nil
 }
 
 // Files returns the files generated by the asset.
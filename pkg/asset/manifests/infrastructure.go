@@ -147,11 +147,14 @@ func (i *Infrastructure) Generate(dependencies asset.Parents) error {
 		}
 	case baremetal.Name:
 		config.Spec.PlatformSpec.Type = configv1.BareMetalPlatformType
-		config.Status.PlatformStatus.BareMetal = &configv1.BareMetalPlatformStatus{
-			APIServerInternalIP:  installConfig.Config.Platform.BareMetal.APIVIPs[0],
-			IngressIP:            installConfig.Config.Platform.BareMetal.IngressVIPs[0],
-			APIServerInternalIPs: installConfig.Config.Platform.BareMetal.APIVIPs,
-			IngressIPs:           installConfig.Config.Platform.BareMetal.IngressVIPs,
+		config.Status.PlatformStatus.BareMetal = &configv1.BareMetalPlatformStatus{}
+		if !installConfig.Config.Platform.IsLoadBalancerUserManaged() {
+			config.Status.PlatformStatus.BareMetal = &configv1.BareMetalPlatformStatus{
+				APIServerInternalIP:  installConfig.Config.Platform.BareMetal.APIVIPs[0],
+				IngressIP:            installConfig.Config.Platform.BareMetal.IngressVIPs[0],
+				APIServerInternalIPs: installConfig.Config.Platform.BareMetal.APIVIPs,
+				IngressIPs:           installConfig.Config.Platform.BareMetal.IngressVIPs,
+			}
 		}
 	case gcp.Name:
 		config.Spec.PlatformSpec.Type = configv1.GCPPlatformType
@@ -197,15 +200,18 @@ func (i *Infrastructure) Generate(dependencies asset.Parents) error {
 		config.Spec.PlatformSpec.Type = configv1.NonePlatformType
 	case openstack.Name:
 		config.Spec.PlatformSpec.Type = configv1.OpenStackPlatformType
-		config.Status.PlatformStatus.OpenStack = &configv1.OpenStackPlatformStatus{
-			APIServerInternalIP:  installConfig.Config.OpenStack.APIVIPs[0],
-			IngressIP:            installConfig.Config.OpenStack.IngressVIPs[0],
-			APIServerInternalIPs: installConfig.Config.OpenStack.APIVIPs,
-			IngressIPs:           installConfig.Config.OpenStack.IngressVIPs,
+		config.Status.PlatformStatus.OpenStack = &configv1.OpenStackPlatformStatus{}
+		if !installConfig.Config.Platform.IsLoadBalancerUserManaged() {
+			config.Status.PlatformStatus.OpenStack = &configv1.OpenStackPlatformStatus{
+				APIServerInternalIP:  installConfig.Config.OpenStack.APIVIPs[0],
+				IngressIP:            installConfig.Config.OpenStack.IngressVIPs[0],
+				APIServerInternalIPs: installConfig.Config.OpenStack.APIVIPs,
+				IngressIPs:           installConfig.Config.OpenStack.IngressVIPs,
+			}
 		}
 	case vsphere.Name:
 		config.Spec.PlatformSpec.Type = configv1.VSpherePlatformType
-		if len(installConfig.Config.VSphere.APIVIPs) > 0 {
+		if len(installConfig.Config.VSphere.APIVIPs) > 0 && !installConfig.Config.Platform.IsLoadBalancerUserManaged() {
 			config.Status.PlatformStatus.VSphere = &configv1.VSpherePlatformStatus{
 				APIServerInternalIP:  installConfig.Config.VSphere.APIVIPs[0],
 				IngressIP:            installConfig.Config.VSphere.IngressVIPs[0],
@@ -289,7 +295,7 @@ func (i *Infrastructure) Generate(dependencies asset.Parents) error {
 			}},
 		}
 
-		if len(installConfig.Config.Nutanix.APIVIPs) > 0 {
+		if len(installConfig.Config.Nutanix.APIVIPs) > 0 && !installConfig.Config.Platform.IsLoadBalancerUserManaged() {
 			config.Status.PlatformStatus.Nutanix = &configv1.NutanixPlatformStatus{
 				APIServerInternalIP:  installConfig.Config.Nutanix.APIVIPs[0],
 				IngressIP:            installConfig.Config.Nutanix.IngressVIPs[0],
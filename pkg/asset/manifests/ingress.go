@@ -1,11 +1,11 @@
 package manifests
 
 import (
-	"fmt"
 	"path/filepath"
 
 	"github.com/ghodss/yaml"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -46,8 +46,10 @@ func (*Ingress) Dependencies() []asset.Asset {
 // A cluster ingress config is always created.
 //
 // A default ingresscontroller is only created if the cluster is using an internal
-// publishing strategy. In this case, the default ingresscontroller is also set
-// to use the internal publishing strategy.
+// publishing strategy, has an infra pool or an ingress serving certificate. In
+// these cases, the default ingresscontroller is also set to use the internal
+// publishing strategy, to run on the infra nodes and to serve the routes with
+// the certificate, respectively.
 func (ing *Ingress) Generate(dependencies asset.Parents) error {
 	installConfig := &installconfig.InstallConfig{}
 	dependencies.Get(installConfig)
@@ -109,7 +111,7 @@ func (ing *Ingress) generateClusterConfig(config *types.InstallConfig) ([]byte,
 			// not namespaced
 		},
 		Spec: configv1.IngressSpec{
-			Domain: fmt.Sprintf("apps.%s", config.ClusterDomain()),
+			Domain: config.AppsDomainName(),
 		},
 		Status: configv1.IngressStatus{
 			DefaultPlacement: defaultPlacement,
@@ -135,30 +137,45 @@ func (ing *Ingress) generateClusterConfig(config *types.InstallConfig) ([]byte,
 }
 
 func (ing *Ingress) generateDefaultIngressController(config *types.InstallConfig) ([]byte, error) {
-	switch config.Publish {
-	case types.InternalPublishingStrategy:
-		obj := &operatorv1.IngressController{
-			TypeMeta: metav1.TypeMeta{
-				APIVersion: operatorv1.GroupVersion.String(),
-				Kind:       "IngressController",
-			},
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace: "openshift-ingress-operator",
-				Name:      "default",
+	internal := config.Publish == types.InternalPublishingStrategy
+	infraPool := config.InfraPool()
+	servingCert := config.ServingCertificates != nil && config.ServingCertificates.Ingress != nil
+	if !internal && infraPool == nil && !servingCert {
+		return nil, nil
+	}
+
+	obj := &operatorv1.IngressController{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: operatorv1.GroupVersion.String(),
+			Kind:       "IngressController",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "openshift-ingress-operator",
+			Name:      "default",
+		},
+	}
+	if internal {
+		obj.Spec.EndpointPublishingStrategy = &operatorv1.EndpointPublishingStrategy{
+			Type: operatorv1.LoadBalancerServiceStrategyType,
+			LoadBalancer: &operatorv1.LoadBalancerStrategy{
+				Scope: operatorv1.InternalLoadBalancer,
 			},
-			Spec: operatorv1.IngressControllerSpec{
-				EndpointPublishingStrategy: &operatorv1.EndpointPublishingStrategy{
-					Type: operatorv1.LoadBalancerServiceStrategyType,
-					LoadBalancer: &operatorv1.LoadBalancerStrategy{
-						Scope: operatorv1.InternalLoadBalancer,
-					},
-				},
+		}
+	}
+	if infraPool != nil {
+		// the router runs on the infra nodes
+		obj.Spec.NodePlacement = &operatorv1.NodePlacement{
+			NodeSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{types.InfraNodeRoleLabel: ""},
 			},
+			Tolerations: []corev1.Toleration{infraToleration},
 		}
-		return yaml.Marshal(obj)
-	default:
-		return nil, nil
 	}
+	if servingCert {
+		// the secret is generated with the Openshift asset
+		obj.Spec.DefaultCertificate = &corev1.LocalObjectReference{Name: ingressServingCertSecret}
+	}
+	return yaml.Marshal(obj)
 }
 
 // Files returns the files generated by the asset.
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/installconfig"
 	"github.com/openshift/installer/pkg/types"
@@ -185,3 +186,55 @@ func TestGenerateIngerssDefaultPlacement(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateDefaultIngressControllerInfraPlacement(t *testing.T) {
+	installConfig := icBuild.build(icBuild.forAWS())
+	installConfig.Compute = []types.MachinePool{
+		{Name: types.MachinePoolComputeRoleName},
+		{Name: types.MachinePoolInfraRoleName},
+	}
+	parents := asset.Parents{}
+	parents.Add(&installconfig.InstallConfig{Config: installConfig})
+	ingressAsset := &Ingress{}
+	if !assert.NoError(t, ingressAsset.Generate(parents), "failed to generate asset") {
+		return
+	}
+	if !assert.Len(t, ingressAsset.FileList, 2) {
+		return
+	}
+	assert.Equal(t, "manifests/cluster-ingress-default-ingresscontroller.yaml", ingressAsset.FileList[1].Filename)
+	var actualController operatorv1.IngressController
+	if !assert.NoError(t, yaml.Unmarshal(ingressAsset.FileList[1].Data, &actualController)) {
+		return
+	}
+	assert.Nil(t, actualController.Spec.EndpointPublishingStrategy)
+	if assert.NotNil(t, actualController.Spec.NodePlacement) {
+		assert.Equal(t, map[string]string{types.InfraNodeRoleLabel: ""}, actualController.Spec.NodePlacement.NodeSelector.MatchLabels)
+		assert.Len(t, actualController.Spec.NodePlacement.Tolerations, 1)
+	}
+}
+
+func TestGenerateDefaultIngressControllerServingCertificate(t *testing.T) {
+	installConfig := icBuild.build(icBuild.forAWS())
+	installConfig.ServingCertificates = &types.ServingCertificates{
+		Ingress: &types.ServingCertificate{Certificate: "test-cert", Key: "test-key"},
+	}
+	parents := asset.Parents{}
+	parents.Add(&installconfig.InstallConfig{Config: installConfig})
+	ingressAsset := &Ingress{}
+	if !assert.NoError(t, ingressAsset.Generate(parents), "failed to generate asset") {
+		return
+	}
+	if !assert.Len(t, ingressAsset.FileList, 2) {
+		return
+	}
+	var actualController operatorv1.IngressController
+	if !assert.NoError(t, yaml.Unmarshal(ingressAsset.FileList[1].Data, &actualController)) {
+		return
+	}
+	assert.Nil(t, actualController.Spec.EndpointPublishingStrategy)
+	assert.Nil(t, actualController.Spec.NodePlacement)
+	if assert.NotNil(t, actualController.Spec.DefaultCertificate) {
+		assert.Equal(t, "ingress-serving-cert", actualController.Spec.DefaultCertificate.Name)
+	}
+}
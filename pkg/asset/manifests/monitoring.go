@@ -0,0 +1,104 @@
+package manifests
+
+import (
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
+)
+
+var monitoringCfgFilename = filepath.Join(manifestDir, "cluster-monitoring-02-config.yml")
+
+// infraToleration tolerates the taint of the nodes of the infra pool.
+var infraToleration = corev1.Toleration{
+	Key:      types.InfraNodeRoleLabel,
+	Operator: corev1.TolerationOpExists,
+	Effect:   corev1.TaintEffectNoSchedule,
+}
+
+// monitoringComponents are the components of the cluster monitoring config
+// which are moved to the infra nodes.
+var monitoringComponents = []string{
+	"alertmanagerMain",
+	"k8sPrometheusAdapter",
+	"kubeStateMetrics",
+	"openshiftStateMetrics",
+	"prometheusK8s",
+	"prometheusOperator",
+	"telemeterClient",
+	"thanosQuerier",
+}
+
+// Monitoring generates the cluster monitoring config, which runs the
+// monitoring stack on the infra nodes when the install-config has an infra
+// pool.
+type Monitoring struct {
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*Monitoring)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*Monitoring) Name() string {
+	return "Monitoring Config"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*Monitoring) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the cluster monitoring config.
+func (m *Monitoring) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	m.FileList = nil
+	if installConfig.Config.InfraPool() == nil {
+		return nil
+	}
+
+	placement := map[string]interface{}{
+		"nodeSelector": map[string]string{types.InfraNodeRoleLabel: ""},
+		"tolerations":  []corev1.Toleration{infraToleration},
+	}
+	config := map[string]interface{}{}
+	for _, component := range monitoringComponents {
+		config[component] = placement
+	}
+	configData, err := yaml.Marshal(config)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", m.Name())
+	}
+
+	cm := configMap("openshift-monitoring", "cluster-monitoring-config", genericData{
+		"config.yaml": string(configData),
+	})
+	cmData, err := yaml.Marshal(cm)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", m.Name())
+	}
+	m.FileList = []*asset.File{{
+		Filename: monitoringCfgFilename,
+		Data:     cmData,
+	}}
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (m *Monitoring) Files() []*asset.File {
+	return m.FileList
+}
+
+// Load returns false since this asset is not written to disk by the installer.
+func (m *Monitoring) Load(f asset.FileFetcher) (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,74 @@
+package manifests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestGenerateMonitoringConfig(t *testing.T) {
+	placement := `      nodeSelector:
+        node-role.kubernetes.io/infra: ""
+      tolerations:
+      - effect: NoSchedule
+        key: node-role.kubernetes.io/infra
+        operator: Exists
+`
+	cases := []struct {
+		name         string
+		compute      []types.MachinePool
+		expectedData string
+	}{
+		{
+			name:    "no infra pool",
+			compute: []types.MachinePool{{Name: types.MachinePoolComputeRoleName}},
+		},
+		{
+			name: "infra pool",
+			compute: []types.MachinePool{
+				{Name: types.MachinePoolComputeRoleName},
+				{Name: types.MachinePoolInfraRoleName},
+			},
+			expectedData: `apiVersion: v1
+data:
+  config.yaml: |
+    alertmanagerMain:
+` + placement + `    k8sPrometheusAdapter:
+` + placement + `    kubeStateMetrics:
+` + placement + `    openshiftStateMetrics:
+` + placement + `    prometheusK8s:
+` + placement + `    prometheusOperator:
+` + placement + `    telemeterClient:
+` + placement + `    thanosQuerier:
+` + placement + `kind: ConfigMap
+metadata:
+  name: cluster-monitoring-config
+  namespace: openshift-monitoring
+`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ic := icBuild.build(icBuild.forAWS())
+			ic.Compute = tc.compute
+			parents := asset.Parents{}
+			parents.Add(&installconfig.InstallConfig{Config: ic})
+			monitoring := &Monitoring{}
+			if !assert.NoError(t, monitoring.Generate(parents), "failed to generate asset") {
+				return
+			}
+			if tc.expectedData == "" {
+				assert.Empty(t, monitoring.Files())
+				return
+			}
+			if assert.Len(t, monitoring.Files(), 1) {
+				assert.Equal(t, "manifests/cluster-monitoring-02-config.yml", monitoring.Files()[0].Filename)
+				assert.Equal(t, tc.expectedData, string(monitoring.Files()[0].Data))
+			}
+		})
+	}
+}
@@ -9,9 +9,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/installconfig"
 	"github.com/openshift/installer/pkg/asset/templates/content/openshift"
+	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/powervs"
 )
 
@@ -118,19 +120,21 @@ func (no *Networking) Generate(dependencies asset.Parents) error {
 		},
 	}
 
-	switch installConfig.Config.Platform.Name() {
-	case powervs.Name:
-		if netConfig.NetworkType == "OVNKubernetes" {
-			ovnConfig, err := OvnKubeConfig(clusterNet, serviceNet, true)
+	if netConfig.NetworkType == string(operatorv1.NetworkTypeOVNKubernetes) {
+		useHostRouting := installConfig.Config.Platform.Name() == powervs.Name
+		if ovnConfig := netConfig.OVNKubernetesConfig; ovnConfig != nil && ovnConfig.GatewayMode != "" {
+			useHostRouting = ovnConfig.GatewayMode == types.OVNGatewayModeLocal
+		}
+		if useHostRouting || netConfig.OVNKubernetesConfig != nil {
+			ovnConfig, err := OvnKubeConfig(clusterNet, serviceNet, useHostRouting, netConfig.OVNKubernetesConfig)
 			if err != nil {
-				return errors.Wrapf(err, "cannot marshal Power VS OVNKube Config")
+				return errors.Wrapf(err, "cannot marshal OVNKube Config")
 			}
 			no.FileList = append(no.FileList, &asset.File{
 				Filename: ovnKubeFilename,
 				Data:     ovnConfig,
 			})
 		}
-
 	}
 
 	return nil
@@ -0,0 +1,121 @@
+package manifests
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/templates/content/openshift"
+	"github.com/openshift/installer/pkg/ipnet"
+	"github.com/openshift/installer/pkg/types"
+	nonetypes "github.com/openshift/installer/pkg/types/none"
+	powervstypes "github.com/openshift/installer/pkg/types/powervs"
+)
+
+func TestGenerateNetworking(t *testing.T) {
+	cases := []struct {
+		name               string
+		platform           types.Platform
+		ovnConfig          *types.OVNKubernetesConfig
+		expectedOVNKConfig *operatorv1.OVNKubernetesConfig
+	}{
+		{
+			name:     "no tunables",
+			platform: types.Platform{None: &nonetypes.Platform{}},
+		},
+		{
+			name:     "power vs uses host routing by default",
+			platform: types.Platform{PowerVS: &powervstypes.Platform{}},
+			expectedOVNKConfig: &operatorv1.OVNKubernetesConfig{
+				GatewayConfig: &operatorv1.GatewayConfig{RoutingViaHost: true},
+			},
+		},
+		{
+			name:     "gateway mode overrides the power vs default",
+			platform: types.Platform{PowerVS: &powervstypes.Platform{}},
+			ovnConfig: &types.OVNKubernetesConfig{
+				GatewayMode: types.OVNGatewayModeShared,
+			},
+			expectedOVNKConfig: &operatorv1.OVNKubernetesConfig{
+				GatewayConfig: &operatorv1.GatewayConfig{RoutingViaHost: false},
+			},
+		},
+		{
+			name:     "tunables applied",
+			platform: types.Platform{None: &nonetypes.Platform{}},
+			ovnConfig: &types.OVNKubernetesConfig{
+				V4InternalSubnet: ipnet.MustParseCIDR("100.99.0.0/16"),
+				GatewayMode:      types.OVNGatewayModeLocal,
+				IPsec:            true,
+				MTU:              1400,
+			},
+			expectedOVNKConfig: &operatorv1.OVNKubernetesConfig{
+				MTU:              func(mtu uint32) *uint32 { return &mtu }(1400),
+				V4InternalSubnet: "100.99.0.0/16",
+				IPsecConfig:      &operatorv1.IPsecConfig{},
+				GatewayConfig:    &operatorv1.GatewayConfig{RoutingViaHost: true},
+			},
+		},
+		{
+			name:     "hybrid overlay",
+			platform: types.Platform{None: &nonetypes.Platform{}},
+			ovnConfig: &types.OVNKubernetesConfig{
+				HybridOverlay: &types.HybridOverlayConfig{
+					HybridClusterNetwork: []types.ClusterNetworkEntry{{CIDR: *ipnet.MustParseCIDR("10.132.0.0/14"), HostPrefix: 23}},
+					VXLANPort:            9898,
+				},
+			},
+			expectedOVNKConfig: &operatorv1.OVNKubernetesConfig{
+				GatewayConfig: &operatorv1.GatewayConfig{RoutingViaHost: false},
+				HybridOverlayConfig: &operatorv1.HybridOverlayConfig{
+					HybridClusterNetwork:   []operatorv1.ClusterNetworkEntry{{CIDR: "10.132.0.0/14", HostPrefix: 23}},
+					HybridOverlayVXLANPort: func(port uint32) *uint32 { return &port }(9898),
+				},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ic := icBuild.build()
+			ic.Platform = tc.platform
+			ic.Networking = &types.Networking{
+				NetworkType:         string(operatorv1.NetworkTypeOVNKubernetes),
+				ClusterNetwork:      []types.ClusterNetworkEntry{{CIDR: *ipnet.MustParseCIDR("10.128.0.0/14"), HostPrefix: 23}},
+				ServiceNetwork:      []ipnet.IPNet{*ipnet.MustParseCIDR("172.30.0.0/16")},
+				OVNKubernetesConfig: tc.ovnConfig,
+			}
+			parents := asset.Parents{}
+			parents.Add(
+				&installconfig.InstallConfig{Config: ic},
+				&openshift.NetworkCRDs{},
+			)
+			networkAsset := &Networking{}
+			if !assert.NoError(t, networkAsset.Generate(parents), "failed to generate asset") {
+				return
+			}
+
+			var ovnKubeFile *asset.File
+			for _, f := range networkAsset.FileList {
+				if f.Filename == "manifests/cluster-network-03-config.yml" {
+					ovnKubeFile = f
+				}
+			}
+			if tc.expectedOVNKConfig == nil {
+				assert.Nil(t, ovnKubeFile, "expected no OVNKubernetes config to be generated")
+				return
+			}
+			if !assert.NotNil(t, ovnKubeFile, "expected an OVNKubernetes config to be generated") {
+				return
+			}
+			var actual operatorv1.Network
+			if !assert.NoError(t, yaml.Unmarshal(ovnKubeFile.Data, &actual), "failed to unmarshal OVNKubernetes config") {
+				return
+			}
+			assert.Equal(t, tc.expectedOVNKConfig, actual.Spec.DefaultNetwork.OVNKubernetesConfig)
+		})
+	}
+}
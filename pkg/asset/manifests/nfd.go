@@ -0,0 +1,132 @@
+package manifests
+
+import (
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
+)
+
+const nfdNamespace = "openshift-nfd"
+
+var (
+	nfdNamespaceFilename     = filepath.Join(manifestDir, "nfd-01-namespace.yaml")
+	nfdOperatorGroupFilename = filepath.Join(manifestDir, "nfd-02-operatorgroup.yaml")
+	nfdSubscriptionFilename  = filepath.Join(manifestDir, "nfd-03-subscription.yaml")
+)
+
+// NodeFeatureDiscovery generates the manifests which install the Node Feature
+// Discovery operator from the Red Hat operator catalog, when a compute pool
+// with accelerators enables it.
+type NodeFeatureDiscovery struct {
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*NodeFeatureDiscovery)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*NodeFeatureDiscovery) Name() string {
+	return "Node Feature Discovery Config"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*NodeFeatureDiscovery) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the Node Feature Discovery manifests.
+func (n *NodeFeatureDiscovery) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	n.FileList = nil
+	if !nodeFeatureDiscoveryEnabled(installConfig.Config) {
+		return nil
+	}
+
+	manifests := []struct {
+		filename string
+		object   map[string]interface{}
+	}{
+		{
+			filename: nfdNamespaceFilename,
+			object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Namespace",
+				"metadata": map[string]interface{}{
+					"name": nfdNamespace,
+				},
+			},
+		},
+		{
+			filename: nfdOperatorGroupFilename,
+			object: map[string]interface{}{
+				"apiVersion": "operators.coreos.com/v1",
+				"kind":       "OperatorGroup",
+				"metadata": map[string]interface{}{
+					"name":      "nfd",
+					"namespace": nfdNamespace,
+				},
+				"spec": map[string]interface{}{
+					"targetNamespaces": []string{nfdNamespace},
+				},
+			},
+		},
+		{
+			filename: nfdSubscriptionFilename,
+			object: map[string]interface{}{
+				"apiVersion": "operators.coreos.com/v1alpha1",
+				"kind":       "Subscription",
+				"metadata": map[string]interface{}{
+					"name":      "nfd",
+					"namespace": nfdNamespace,
+				},
+				"spec": map[string]interface{}{
+					"channel":         "stable",
+					"name":            "nfd",
+					"source":          "redhat-operators",
+					"sourceNamespace": "openshift-marketplace",
+				},
+			},
+		},
+	}
+	for _, m := range manifests {
+		data, err := yaml.Marshal(m.object)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", n.Name())
+		}
+		n.FileList = append(n.FileList, &asset.File{
+			Filename: m.filename,
+			Data:     data,
+		})
+	}
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (n *NodeFeatureDiscovery) Files() []*asset.File {
+	return n.FileList
+}
+
+// Load returns false since this asset is not written to disk by the installer.
+func (n *NodeFeatureDiscovery) Load(f asset.FileFetcher) (bool, error) {
+	return false, nil
+}
+
+// nodeFeatureDiscoveryEnabled returns whether a compute pool with
+// accelerators enables Node Feature Discovery.
+func nodeFeatureDiscoveryEnabled(ic *types.InstallConfig) bool {
+	for _, pool := range ic.Compute {
+		if pool.Accelerators != nil && pool.Accelerators.NodeFeatureDiscovery {
+			return true
+		}
+	}
+	return false
+}
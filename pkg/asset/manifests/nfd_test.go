@@ -0,0 +1,78 @@
+package manifests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestGenerateNodeFeatureDiscovery(t *testing.T) {
+	cases := []struct {
+		name          string
+		accelerators  *types.Accelerators
+		expectedFiles []string
+	}{
+		{
+			name: "no accelerators",
+		},
+		{
+			name:         "accelerators without node feature discovery",
+			accelerators: &types.Accelerators{Vendor: types.AcceleratorVendorNVIDIA},
+		},
+		{
+			name:         "node feature discovery",
+			accelerators: &types.Accelerators{Vendor: types.AcceleratorVendorNVIDIA, NodeFeatureDiscovery: true},
+			expectedFiles: []string{
+				"manifests/nfd-01-namespace.yaml",
+				"manifests/nfd-02-operatorgroup.yaml",
+				"manifests/nfd-03-subscription.yaml",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ic := icBuild.build(icBuild.forAWS())
+			ic.Compute = []types.MachinePool{{Name: types.MachinePoolComputeRoleName, Accelerators: tc.accelerators}}
+			parents := asset.Parents{}
+			parents.Add(&installconfig.InstallConfig{Config: ic})
+			nfd := &NodeFeatureDiscovery{}
+			if !assert.NoError(t, nfd.Generate(parents), "failed to generate asset") {
+				return
+			}
+			var filenames []string
+			for _, f := range nfd.Files() {
+				filenames = append(filenames, f.Filename)
+			}
+			assert.Equal(t, tc.expectedFiles, filenames)
+		})
+	}
+}
+
+func TestNodeFeatureDiscoverySubscription(t *testing.T) {
+	ic := icBuild.build(icBuild.forAWS())
+	ic.Compute = []types.MachinePool{{
+		Name:         types.MachinePoolComputeRoleName,
+		Accelerators: &types.Accelerators{Vendor: types.AcceleratorVendorNVIDIA, NodeFeatureDiscovery: true},
+	}}
+	parents := asset.Parents{}
+	parents.Add(&installconfig.InstallConfig{Config: ic})
+	nfd := &NodeFeatureDiscovery{}
+	if !assert.NoError(t, nfd.Generate(parents), "failed to generate asset") {
+		return
+	}
+	assert.Equal(t, `apiVersion: operators.coreos.com/v1alpha1
+kind: Subscription
+metadata:
+  name: nfd
+  namespace: openshift-nfd
+spec:
+  channel: stable
+  name: nfd
+  source: redhat-operators
+  sourceNamespace: openshift-marketplace
+`, string(nfd.Files()[2].Data))
+}
@@ -0,0 +1,134 @@
+package manifests
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+)
+
+var (
+	nodeCfgFilename          = filepath.Join(manifestDir, "cluster-node-02-config.yml")
+	kubeletConfigFilenameFmt = filepath.Join(manifestDir, "cluster-kubelet-02-config-%s.yml")
+)
+
+// NodeConfig generates the node and kubelet configuration manifests from the
+// nodeConfig section of the install-config.
+type NodeConfig struct {
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*NodeConfig)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*NodeConfig) Name() string {
+	return "Node Config"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*NodeConfig) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the Node config and the KubeletConfigs of the master
+// and worker pools.
+func (n *NodeConfig) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	n.FileList = nil
+	nodeConfig := installConfig.Config.NodeConfig
+	if nodeConfig == nil {
+		return nil
+	}
+
+	if nodeConfig.CgroupMode != configv1.CgroupModeEmpty {
+		config := &configv1.Node{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: configv1.SchemeGroupVersion.String(),
+				Kind:       "Node",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster",
+				// not namespaced
+			},
+			Spec: configv1.NodeSpec{
+				CgroupMode: nodeConfig.CgroupMode,
+			},
+		}
+		configData, err := yaml.Marshal(config)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", n.Name())
+		}
+		n.FileList = append(n.FileList, &asset.File{
+			Filename: nodeCfgFilename,
+			Data:     configData,
+		})
+	}
+
+	kubelet := map[string]interface{}{}
+	if nodeConfig.MaxPods != 0 {
+		kubelet["maxPods"] = nodeConfig.MaxPods
+	}
+	if len(nodeConfig.SystemReserved) > 0 {
+		kubelet["systemReserved"] = nodeConfig.SystemReserved
+	}
+	if len(kubelet) == 0 {
+		return nil
+	}
+	raw, err := json.Marshal(kubelet)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal kubelet configuration")
+	}
+	for _, role := range []string{"master", "worker"} {
+		config := &mcfgv1.KubeletConfig{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: mcfgv1.SchemeGroupVersion.String(),
+				Kind:       "KubeletConfig",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: fmt.Sprintf("99-%s-kubelet", role),
+			},
+			Spec: mcfgv1.KubeletConfigSpec{
+				MachineConfigPoolSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						fmt.Sprintf("pools.operator.machineconfiguration.io/%s", role): "",
+					},
+				},
+				KubeletConfig: &runtime.RawExtension{Raw: raw},
+			},
+		}
+		configData, err := yaml.Marshal(config)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", n.Name())
+		}
+		n.FileList = append(n.FileList, &asset.File{
+			Filename: fmt.Sprintf(kubeletConfigFilenameFmt, role),
+			Data:     configData,
+		})
+	}
+
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (n *NodeConfig) Files() []*asset.File {
+	return n.FileList
+}
+
+// Load returns false since this asset is not written to disk by the installer.
+func (n *NodeConfig) Load(f asset.FileFetcher) (bool, error) {
+	return false, nil
+}
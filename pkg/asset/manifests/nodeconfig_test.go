@@ -0,0 +1,101 @@
+package manifests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestGenerateNodeConfig(t *testing.T) {
+	cases := []struct {
+		name          string
+		nodeConfig    *types.NodeConfig
+		expectedFiles map[string]string
+	}{
+		{
+			name: "no node config",
+		},
+		{
+			name:       "cgroup mode",
+			nodeConfig: &types.NodeConfig{CgroupMode: configv1.CgroupModeV2},
+			expectedFiles: map[string]string{
+				"manifests/cluster-node-02-config.yml": `apiVersion: config.openshift.io/v1
+kind: Node
+metadata:
+  creationTimestamp: null
+  name: cluster
+spec:
+  cgroupMode: v2
+status: {}
+`,
+			},
+		},
+		{
+			name: "kubelet config",
+			nodeConfig: &types.NodeConfig{
+				MaxPods:        500,
+				SystemReserved: map[string]string{"cpu": "500m"},
+			},
+			expectedFiles: map[string]string{
+				"manifests/cluster-kubelet-02-config-master.yml": `apiVersion: machineconfiguration.openshift.io/v1
+kind: KubeletConfig
+metadata:
+  creationTimestamp: null
+  name: 99-master-kubelet
+spec:
+  kubeletConfig:
+    maxPods: 500
+    systemReserved:
+      cpu: 500m
+  machineConfigPoolSelector:
+    matchLabels:
+      pools.operator.machineconfiguration.io/master: ""
+status:
+  conditions: null
+`,
+				"manifests/cluster-kubelet-02-config-worker.yml": `apiVersion: machineconfiguration.openshift.io/v1
+kind: KubeletConfig
+metadata:
+  creationTimestamp: null
+  name: 99-worker-kubelet
+spec:
+  kubeletConfig:
+    maxPods: 500
+    systemReserved:
+      cpu: 500m
+  machineConfigPoolSelector:
+    matchLabels:
+      pools.operator.machineconfiguration.io/worker: ""
+status:
+  conditions: null
+`,
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ic := icBuild.build(icBuild.forNone())
+			ic.NodeConfig = tc.nodeConfig
+			parents := asset.Parents{}
+			parents.Add(&installconfig.InstallConfig{Config: ic})
+			nodeConfig := &NodeConfig{}
+			if !assert.NoError(t, nodeConfig.Generate(parents), "failed to generate asset") {
+				return
+			}
+			files := map[string]string{}
+			for _, f := range nodeConfig.Files() {
+				files[f.Filename] = string(f.Data)
+			}
+			if len(tc.expectedFiles) == 0 {
+				assert.Empty(t, files)
+				return
+			}
+			assert.Equal(t, tc.expectedFiles, files)
+		})
+	}
+}
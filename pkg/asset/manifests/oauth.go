@@ -0,0 +1,193 @@
+package manifests
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// oauthManifests returns the manifests of the OAuth config of the cluster
+// and of the secrets and config maps its identity providers reference. The
+// identity providers are those of the install-config, and the break-glass
+// users, who are bound to the cluster-admin role. It returns no manifests
+// when there are no identity providers.
+func oauthManifests(ic *types.InstallConfig) (map[string][]byte, error) {
+	objects := map[string]interface{}{}
+	providers := ic.IdentityProviders
+	if ic.Kubeadmin != nil && ic.Kubeadmin.HTPasswd != "" {
+		providers = append(providers[:len(providers):len(providers)], types.IdentityProvider{
+			Name:     types.BreakGlassIdentityProvider,
+			HTPasswd: &types.HTPasswdIdentityProvider{Contents: ic.Kubeadmin.HTPasswd},
+		})
+		objects["99_break-glass-cluster-role-binding.yaml"] = breakGlassClusterRoleBinding(ic.Kubeadmin.HTPasswd)
+	}
+	if len(providers) == 0 {
+		return nil, nil
+	}
+
+	oauth := &configv1.OAuth{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: configv1.SchemeGroupVersion.String(),
+			Kind:       "OAuth",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+			// not namespaced
+		},
+	}
+	for _, p := range providers {
+		idp := configv1.IdentityProvider{
+			Name:          p.Name,
+			MappingMethod: p.MappingMethod,
+		}
+		if idp.MappingMethod == "" {
+			idp.MappingMethod = configv1.MappingMethodClaim
+		}
+		secretName := fmt.Sprintf("idp-%s", p.Name)
+		caName := fmt.Sprintf("idp-%s-ca", p.Name)
+		var secretData map[string]string
+		var ca string
+		switch {
+		case p.HTPasswd != nil:
+			idp.Type = configv1.IdentityProviderTypeHTPasswd
+			idp.HTPasswd = &configv1.HTPasswdIdentityProvider{
+				FileData: configv1.SecretNameReference{Name: secretName},
+			}
+			secretData = map[string]string{"htpasswd": p.HTPasswd.Contents}
+		case p.OpenID != nil:
+			idp.Type = configv1.IdentityProviderTypeOpenID
+			idp.OpenID = &configv1.OpenIDIdentityProvider{
+				Issuer:       p.OpenID.Issuer,
+				ClientID:     p.OpenID.ClientID,
+				ClientSecret: configv1.SecretNameReference{Name: secretName},
+				ExtraScopes:  p.OpenID.ExtraScopes,
+				Claims: configv1.OpenIDClaims{
+					PreferredUsername: []string{"preferred_username"},
+					Name:              []string{"name"},
+					Email:             []string{"email"},
+				},
+			}
+			if p.OpenID.Claims != nil {
+				idp.OpenID.Claims = *p.OpenID.Claims
+			}
+			secretData = map[string]string{"clientSecret": p.OpenID.ClientSecret}
+			ca = p.OpenID.CA
+			if ca != "" {
+				idp.OpenID.CA = configv1.ConfigMapNameReference{Name: caName}
+			}
+		case p.LDAP != nil:
+			idp.Type = configv1.IdentityProviderTypeLDAP
+			idp.LDAP = &configv1.LDAPIdentityProvider{
+				URL:      p.LDAP.URL,
+				BindDN:   p.LDAP.BindDN,
+				Insecure: p.LDAP.Insecure,
+				Attributes: configv1.LDAPAttributeMapping{
+					ID:                []string{"dn"},
+					PreferredUsername: []string{"uid"},
+					Name:              []string{"cn"},
+					Email:             []string{"mail"},
+				},
+			}
+			if p.LDAP.Attributes != nil {
+				idp.LDAP.Attributes = *p.LDAP.Attributes
+			}
+			if p.LDAP.BindPassword != "" {
+				idp.LDAP.BindPassword = configv1.SecretNameReference{Name: secretName}
+				secretData = map[string]string{"bindPassword": p.LDAP.BindPassword}
+			}
+			ca = p.LDAP.CA
+			if ca != "" {
+				idp.LDAP.CA = configv1.ConfigMapNameReference{Name: caName}
+			}
+		default:
+			return nil, errors.Errorf("identity provider %q has no type", p.Name)
+		}
+		oauth.Spec.IdentityProviders = append(oauth.Spec.IdentityProviders, idp)
+
+		if secretData != nil {
+			objects[fmt.Sprintf("99_%s-secret.yaml", secretName)] = &corev1.Secret{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: corev1.SchemeGroupVersion.String(),
+					Kind:       "Secret",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "openshift-config",
+					Name:      secretName,
+				},
+				Type:       corev1.SecretTypeOpaque,
+				StringData: secretData,
+			}
+		}
+		if ca != "" {
+			objects[fmt.Sprintf("99_%s.yaml", caName)] = &corev1.ConfigMap{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: corev1.SchemeGroupVersion.String(),
+					Kind:       "ConfigMap",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "openshift-config",
+					Name:      caName,
+				},
+				Data: map[string]string{"ca.crt": ca},
+			}
+		}
+	}
+	objects["99_oauth.yaml"] = oauth
+
+	manifests := make(map[string][]byte, len(objects))
+	for name, object := range objects {
+		data, err := yaml.Marshal(object)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create %s", name)
+		}
+		manifests[name] = data
+	}
+	return manifests, nil
+}
+
+// breakGlassClusterRoleBinding returns the binding of the users of the
+// htpasswd file to the cluster-admin role.
+func breakGlassClusterRoleBinding(htpasswd string) map[string]interface{} {
+	var subjects []map[string]string
+	for _, user := range htpasswdUsers(htpasswd) {
+		subjects = append(subjects, map[string]string{
+			"apiGroup": "rbac.authorization.k8s.io",
+			"kind":     "User",
+			"name":     user,
+		})
+	}
+	return map[string]interface{}{
+		"apiVersion": "rbac.authorization.k8s.io/v1",
+		"kind":       "ClusterRoleBinding",
+		"metadata": map[string]string{
+			"name": "break-glass-cluster-admin",
+		},
+		"roleRef": map[string]string{
+			"apiGroup": "rbac.authorization.k8s.io",
+			"kind":     "ClusterRole",
+			"name":     "cluster-admin",
+		},
+		"subjects": subjects,
+	}
+}
+
+// htpasswdUsers returns the users of the htpasswd file, skipping blank lines
+// and comments.
+func htpasswdUsers(htpasswd string) []string {
+	var users []string
+	for _, line := range strings.Split(htpasswd, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		users = append(users, strings.SplitN(line, ":", 2)[0])
+	}
+	return users
+}
@@ -0,0 +1,139 @@
+package manifests
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestOAuthManifests(t *testing.T) {
+	cases := []struct {
+		name          string
+		kubeadmin     *types.Kubeadmin
+		providers     []types.IdentityProvider
+		expectedFiles []string
+		expectedFile  string
+		expectedData  string
+	}{
+		{
+			name: "no identity providers",
+		},
+		{
+			name: "openID and break-glass users",
+			kubeadmin: &types.Kubeadmin{
+				Disabled: true,
+				HTPasswd: "# break-glass\nalice:$2y$05$hash\n\nbob:$2y$05$hash\n",
+			},
+			providers: []types.IdentityProvider{{
+				Name: "oidc",
+				OpenID: &types.OpenIDIdentityProvider{
+					Issuer:       "https://oidc.example.com",
+					ClientID:     "openshift",
+					ClientSecret: "secret",
+					CA:           "oidc-ca",
+				},
+			}},
+			expectedFiles: []string{
+				"99_break-glass-cluster-role-binding.yaml",
+				"99_idp-break-glass-secret.yaml",
+				"99_idp-oidc-ca.yaml",
+				"99_idp-oidc-secret.yaml",
+				"99_oauth.yaml",
+			},
+			expectedFile: "99_oauth.yaml",
+			expectedData: `apiVersion: config.openshift.io/v1
+kind: OAuth
+metadata:
+  creationTimestamp: null
+  name: cluster
+spec:
+  identityProviders:
+  - mappingMethod: claim
+    name: oidc
+    openID:
+      ca:
+        name: idp-oidc-ca
+      claims:
+        email:
+        - email
+        name:
+        - name
+        preferredUsername:
+        - preferred_username
+      clientID: openshift
+      clientSecret:
+        name: idp-oidc
+      issuer: https://oidc.example.com
+    type: OpenID
+  - htpasswd:
+      fileData:
+        name: idp-break-glass
+    mappingMethod: claim
+    name: break-glass
+    type: HTPasswd
+  templates:
+    error:
+      name: ""
+    login:
+      name: ""
+    providerSelection:
+      name: ""
+  tokenConfig: {}
+status: {}
+`,
+		},
+		{
+			name: "break-glass users bound to cluster-admin",
+			kubeadmin: &types.Kubeadmin{
+				HTPasswd: "alice:$2y$05$hash\nbob:$2y$05$hash\n",
+			},
+			expectedFiles: []string{
+				"99_break-glass-cluster-role-binding.yaml",
+				"99_idp-break-glass-secret.yaml",
+				"99_oauth.yaml",
+			},
+			expectedFile: "99_break-glass-cluster-role-binding.yaml",
+			expectedData: `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: break-glass-cluster-admin
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: cluster-admin
+subjects:
+- apiGroup: rbac.authorization.k8s.io
+  kind: User
+  name: alice
+- apiGroup: rbac.authorization.k8s.io
+  kind: User
+  name: bob
+`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ic := icBuild.build(icBuild.forNone())
+			ic.Kubeadmin = tc.kubeadmin
+			ic.IdentityProviders = tc.providers
+			manifests, err := oauthManifests(ic)
+			if !assert.NoError(t, err) {
+				return
+			}
+			files := []string{}
+			for name := range manifests {
+				files = append(files, name)
+			}
+			sort.Strings(files)
+			if len(tc.expectedFiles) == 0 {
+				assert.Empty(t, files)
+				return
+			}
+			assert.Equal(t, tc.expectedFiles, files)
+			assert.Equal(t, tc.expectedData, string(manifests[tc.expectedFile]))
+		})
+	}
+}
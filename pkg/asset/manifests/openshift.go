@@ -126,7 +126,7 @@ func (o *Openshift) Generate(dependencies asset.Parents) error {
 			},
 		}
 	case gcptypes.Name:
-		session, err := gcp.GetSession(context.TODO())
+		session, err := gcp.GetSessionWithCredentials(context.TODO(), installConfig.Credentials().GCPCredentials())
 		if err != nil {
 			return err
 		}
@@ -248,8 +248,23 @@ func (o *Openshift) Generate(dependencies asset.Parents) error {
 		baremetalConfig,
 		rhcosImage)
 
-	assetData := map[string][]byte{
-		"99_kubeadmin-password-secret.yaml": applyTemplateData(kubeadminPasswordSecret.Files()[0].Data, templateData),
+	assetData := map[string][]byte{}
+	if !installConfig.Config.KubeadminDisabled() {
+		assetData["99_kubeadmin-password-secret.yaml"] = applyTemplateData(kubeadminPasswordSecret.Files()[0].Data, templateData)
+	}
+	oauth, err := oauthManifests(installConfig.Config)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the OAuth config manifests")
+	}
+	for name, data := range oauth {
+		assetData[name] = data
+	}
+	servingCerts, err := servingCertificateManifests(installConfig.Config)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the serving certificate manifests")
+	}
+	for name, data := range servingCerts {
+		assetData[name] = data
 	}
 
 	switch platform {
@@ -61,6 +61,12 @@ func (m *Manifests) Dependencies() []asset.Asset {
 		&Proxy{},
 		&Scheduler{},
 		&ImageContentSourcePolicy{},
+		&ImageConfig{},
+		&NodeConfig{},
+		&EtcdConfig{},
+		&Monitoring{},
+		&NodeFeatureDiscovery{},
+		&APIServerConfig{},
 		&tls.RootCA{},
 		&tls.MCSCertKey{},
 
@@ -82,7 +88,13 @@ func (m *Manifests) Generate(dependencies asset.Parents) error {
 	proxy := &Proxy{}
 	scheduler := &Scheduler{}
 	imageContentSourcePolicy := &ImageContentSourcePolicy{}
-	dependencies.Get(installConfig, ingress, dns, network, infra, proxy, scheduler, imageContentSourcePolicy)
+	imageConfig := &ImageConfig{}
+	nodeConfig := &NodeConfig{}
+	etcdConfig := &EtcdConfig{}
+	monitoring := &Monitoring{}
+	nfd := &NodeFeatureDiscovery{}
+	apiServerConfig := &APIServerConfig{}
+	dependencies.Get(installConfig, ingress, dns, network, infra, proxy, scheduler, imageContentSourcePolicy, imageConfig, nodeConfig, etcdConfig, monitoring, nfd, apiServerConfig)
 
 	redactedConfig, err := redactedInstallConfig(*installConfig.Config)
 	if err != nil {
@@ -117,6 +129,12 @@ func (m *Manifests) Generate(dependencies asset.Parents) error {
 	m.FileList = append(m.FileList, proxy.Files()...)
 	m.FileList = append(m.FileList, scheduler.Files()...)
 	m.FileList = append(m.FileList, imageContentSourcePolicy.Files()...)
+	m.FileList = append(m.FileList, imageConfig.Files()...)
+	m.FileList = append(m.FileList, nodeConfig.Files()...)
+	m.FileList = append(m.FileList, etcdConfig.Files()...)
+	m.FileList = append(m.FileList, monitoring.Files()...)
+	m.FileList = append(m.FileList, nfd.Files()...)
+	m.FileList = append(m.FileList, apiServerConfig.Files()...)
 
 	asset.SortFiles(m.FileList)
 
@@ -232,6 +250,44 @@ func redactedInstallConfig(config types.InstallConfig) ([]byte, error) {
 		p.Password = ""
 		config.Platform.VSphere = &p
 	}
+	if config.Kubeadmin != nil {
+		k := *config.Kubeadmin
+		k.HTPasswd = ""
+		config.Kubeadmin = &k
+	}
+	if len(config.IdentityProviders) > 0 {
+		providers := make([]types.IdentityProvider, 0, len(config.IdentityProviders))
+		for _, p := range config.IdentityProviders {
+			switch {
+			case p.HTPasswd != nil:
+				p.HTPasswd = &types.HTPasswdIdentityProvider{}
+			case p.OpenID != nil:
+				openID := *p.OpenID
+				openID.ClientSecret = ""
+				p.OpenID = &openID
+			case p.LDAP != nil:
+				ldap := *p.LDAP
+				ldap.BindPassword = ""
+				p.LDAP = &ldap
+			}
+			providers = append(providers, p)
+		}
+		config.IdentityProviders = providers
+	}
+	if c := config.ServingCertificates; c != nil {
+		certs := *c
+		if c.API != nil {
+			api := *c.API
+			api.Key = ""
+			certs.API = &api
+		}
+		if c.Ingress != nil {
+			ingress := *c.Ingress
+			ingress.Key = ""
+			certs.Ingress = &ingress
+		}
+		config.ServingCertificates = &certs
+	}
 	return yaml.Marshal(config)
 }
 
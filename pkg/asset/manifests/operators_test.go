@@ -56,6 +56,24 @@ func TestRedactedInstallConfig(t *testing.T) {
 				},
 			},
 			PullSecret: "test-pull-secret",
+			Kubeadmin: &types.Kubeadmin{
+				Disabled: true,
+				HTPasswd: "test-user:test-hash",
+			},
+			IdentityProviders: []types.IdentityProvider{{
+				Name: "test-oidc",
+				OpenID: &types.OpenIDIdentityProvider{
+					Issuer:       "https://oidc.example.com",
+					ClientID:     "test-client",
+					ClientSecret: "test-client-secret",
+				},
+			}},
+			ServingCertificates: &types.ServingCertificates{
+				Ingress: &types.ServingCertificate{
+					Certificate: "test-cert",
+					Key:         "test-key",
+				},
+			},
 		}
 	}
 	expectedConfig := createInstallConfig()
@@ -70,6 +88,14 @@ controlPlane:
   name: control-plane
   platform: {}
   replicas: 3
+identityProviders:
+- name: test-oidc
+  openID:
+    clientID: test-client
+    clientSecret: ""
+    issuer: https://oidc.example.com
+kubeadmin:
+  disabled: true
 metadata:
   creationTimestamp: null
   name: test-cluster
@@ -90,6 +116,10 @@ platform:
     username: ""
     vCenter: test-server-1
 pullSecret: ""
+servingCertificates:
+  ingress:
+    certificate: test-cert
+    key: ""
 sshKey: test-ssh-key
 `
 	ic := createInstallConfig()
@@ -6,10 +6,12 @@ import (
 
 	configv1 "github.com/openshift/api/config/v1"
 	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/installer/pkg/types"
 )
 
-// OvnKubeConfig creates a config file for the OVNKubernetes CNI provider
-func OvnKubeConfig(cns []configv1.ClusterNetworkEntry, sn []string, useHostRouting bool) ([]byte, error) {
+// OvnKubeConfig creates a config file for the OVNKubernetes CNI provider.
+// The optional install-config tunables are applied on top of the defaults.
+func OvnKubeConfig(cns []configv1.ClusterNetworkEntry, sn []string, useHostRouting bool, tunables *types.OVNKubernetesConfig) ([]byte, error) {
 
 	operCNs := []operatorv1.ClusterNetworkEntry{}
 	for _, cn := range cns {
@@ -43,5 +45,36 @@ func OvnKubeConfig(cns []configv1.ClusterNetworkEntry, sn []string, useHostRouti
 		Status: operatorv1.NetworkStatus{},
 	}
 
+	if tunables != nil {
+		ovnKubeConfig := ovnConfig.Spec.DefaultNetwork.OVNKubernetesConfig
+		if tunables.V4InternalSubnet != nil {
+			ovnKubeConfig.V4InternalSubnet = tunables.V4InternalSubnet.String()
+		}
+		if tunables.V6InternalSubnet != nil {
+			ovnKubeConfig.V6InternalSubnet = tunables.V6InternalSubnet.String()
+		}
+		if tunables.IPsec {
+			ovnKubeConfig.IPsecConfig = &operatorv1.IPsecConfig{}
+		}
+		if tunables.MTU != 0 {
+			mtu := tunables.MTU
+			ovnKubeConfig.MTU = &mtu
+		}
+		if hybrid := tunables.HybridOverlay; hybrid != nil {
+			hybridConfig := &operatorv1.HybridOverlayConfig{}
+			for _, hcn := range hybrid.HybridClusterNetwork {
+				hybridConfig.HybridClusterNetwork = append(hybridConfig.HybridClusterNetwork, operatorv1.ClusterNetworkEntry{
+					CIDR:       hcn.CIDR.String(),
+					HostPrefix: uint32(hcn.HostPrefix),
+				})
+			}
+			if hybrid.VXLANPort != 0 {
+				port := hybrid.VXLANPort
+				hybridConfig.HybridOverlayVXLANPort = &port
+			}
+			ovnKubeConfig.HybridOverlayConfig = hybridConfig
+		}
+	}
+
 	return yaml.Marshal(ovnConfig)
 }
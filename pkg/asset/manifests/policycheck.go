@@ -0,0 +1,107 @@
+package manifests
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/machines"
+	"github.com/openshift/installer/pkg/policy"
+)
+
+// PolicyCheck is an asset that evaluates the install-config and the
+// manifests against the policies of the policy directory, if one is set.
+type PolicyCheck struct {
+	dir string
+}
+
+var (
+	_ asset.ContextAsset    = (*PolicyCheck)(nil)
+	_ asset.ConfiguredAsset = (*PolicyCheck)(nil)
+)
+
+// SetOptions sets the policy directory the install is evaluated against.
+func (a *PolicyCheck) SetOptions(options asset.Options) {
+	a.dir = options.PolicyDir
+}
+
+// Dependencies returns the dependencies for PolicyCheck
+func (a *PolicyCheck) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+		&machines.Master{},
+		&machines.Worker{},
+		&Manifests{},
+		&Openshift{},
+	}
+}
+
+// Generate evaluates the policies.
+func (a *PolicyCheck) Generate(dependencies asset.Parents) error {
+	return a.GenerateWithContext(context.Background(), "", dependencies)
+}
+
+// GenerateWithContext evaluates the policies, which is canceled with the
+// context.
+func (a *PolicyCheck) GenerateWithContext(ctx context.Context, _ string, dependencies asset.Parents) error {
+	dir := a.dir
+	if dir == "" {
+		return nil
+	}
+	ic := &installconfig.InstallConfig{}
+	master := &machines.Master{}
+	worker := &machines.Worker{}
+	manifests := &Manifests{}
+	openshift := &Openshift{}
+	dependencies.Get(ic, master, worker, manifests, openshift)
+
+	input, err := policyInput(ic, master, worker, manifests, openshift)
+	if err != nil {
+		return errors.Wrap(err, "failed to prepare the input of the policies")
+	}
+	logrus.Infof("Evaluating the install against the policies of %s", dir)
+	if err := policy.Evaluate(ctx, dir, input); err != nil {
+		if _, ok := err.(policy.Violations); ok {
+			return errors.Wrap(err, asset.InstallConfigError)
+		}
+		return err
+	}
+	return nil
+}
+
+// Name returns the human-friendly name of the asset.
+func (a *PolicyCheck) Name() string {
+	return "Policy Check"
+}
+
+// policyInput returns the input of the policies: the install-config, without
+// its pull secret, and the manifests.
+func policyInput(ic *installconfig.InstallConfig, assets ...asset.WritableAsset) (*policy.Input, error) {
+	config := *ic.Config
+	config.PullSecret = ""
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	input := &policy.Input{}
+	if err := json.Unmarshal(data, &input.InstallConfig); err != nil {
+		return nil, err
+	}
+
+	for _, a := range assets {
+		for _, f := range a.Files() {
+			object := map[string]interface{}{}
+			if err := yaml.Unmarshal(f.Data, &object); err != nil {
+				logrus.Debugf("Leaving %s out of the input of the policies: %v", f.Filename, err)
+				continue
+			}
+			input.Manifests = append(input.Manifests, policy.Manifest{Filename: f.Filename, Object: object})
+		}
+	}
+	return input, nil
+}
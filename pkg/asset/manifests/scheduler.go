@@ -62,7 +62,11 @@ func (s *Scheduler) Generate(dependencies asset.Parents) error {
 			computeReplicas += *pool.Replicas
 		}
 	}
-	if computeReplicas == 0 {
+	if installConfig.Config.IsArbiterEnabled() {
+		// The workloads of two-node clusters run on the control plane, the
+		// arbiter is tainted by the machine-config-operator to only run etcd.
+		config.Spec.MastersSchedulable = true
+	} else if computeReplicas == 0 {
 		// A schedulable host is required for a successful install to complete.
 		// If the install config has 0 replicas for compute hosts, it's one of two cases:
 		//   1. An IPI deployment with no compute hosts.  The deployment can not succeed
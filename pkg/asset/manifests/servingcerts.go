@@ -0,0 +1,69 @@
+package manifests
+
+import (
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+const (
+	// apiServingCertSecret is the secret of the openshift-config namespace
+	// with the serving certificate of the API.
+	apiServingCertSecret = "api-serving-cert"
+
+	// ingressServingCertSecret is the secret of the openshift-ingress
+	// namespace with the default certificate of the default ingress
+	// controller.
+	ingressServingCertSecret = "ingress-serving-cert"
+)
+
+// servingCertificateManifests returns the manifests of the secrets with the
+// serving certificates of the install-config. The APIServer config which
+// serves the API domain with the API certificate and the default ingress
+// controller which serves the routes with the ingress certificate are
+// generated with the APIServerConfig and Ingress assets.
+func servingCertificateManifests(ic *types.InstallConfig) (map[string][]byte, error) {
+	if ic.ServingCertificates == nil {
+		return nil, nil
+	}
+	objects := map[string]interface{}{}
+	if cert := ic.ServingCertificates.API; cert != nil {
+		objects["99_api-serving-cert-secret.yaml"] = tlsSecret("openshift-config", apiServingCertSecret, cert)
+	}
+	if cert := ic.ServingCertificates.Ingress; cert != nil {
+		objects["99_ingress-serving-cert-secret.yaml"] = tlsSecret("openshift-ingress", ingressServingCertSecret, cert)
+	}
+
+	manifests := make(map[string][]byte, len(objects))
+	for name, object := range objects {
+		data, err := yaml.Marshal(object)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create %s", name)
+		}
+		manifests[name] = data
+	}
+	return manifests, nil
+}
+
+// tlsSecret returns the TLS secret with the certificate, followed by its
+// chain, and the key.
+func tlsSecret(namespace, name string, cert *types.ServingCertificate) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		Type: corev1.SecretTypeTLS,
+		StringData: map[string]string{
+			corev1.TLSCertKey:       cert.Certificate,
+			corev1.TLSPrivateKeyKey: cert.Key,
+		},
+	}
+}
@@ -0,0 +1,41 @@
+package manifests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestServingCertificateManifests(t *testing.T) {
+	ic := &types.InstallConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		BaseDomain: "example.com",
+		ServingCertificates: &types.ServingCertificates{
+			API:     &types.ServingCertificate{Certificate: "api-cert", Key: "api-key", CA: "api-ca"},
+			Ingress: &types.ServingCertificate{Certificate: "ingress-cert", Key: "ingress-key"},
+		},
+	}
+	manifests, err := servingCertificateManifests(ic)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, manifests, 2)
+	assert.Equal(t, `apiVersion: v1
+kind: Secret
+metadata:
+  creationTimestamp: null
+  name: ingress-serving-cert
+  namespace: openshift-ingress
+stringData:
+  tls.crt: ingress-cert
+  tls.key: ingress-key
+type: kubernetes.io/tls
+`, string(manifests["99_ingress-serving-cert-secret.yaml"]))
+
+	manifests, err = servingCertificateManifests(&types.InstallConfig{})
+	assert.NoError(t, err)
+	assert.Empty(t, manifests)
+}
@@ -5,10 +5,16 @@ import (
 	"github.com/openshift/installer/pkg/types"
 )
 
+// highlyAvailableArbiterTopologyMode is the control plane topology of two-node
+// clusters with an arbiter, which the vendored API predates.
+const highlyAvailableArbiterTopologyMode configv1.TopologyMode = "HighlyAvailableArbiter"
+
 // determineTopologies determines the Infrastructure CR's
 // infrastructureTopology and controlPlaneTopology given an install config file
 func determineTopologies(installConfig *types.InstallConfig) (controlPlaneTopology configv1.TopologyMode, infrastructureTopology configv1.TopologyMode) {
-	if installConfig.ControlPlane.Replicas != nil && *installConfig.ControlPlane.Replicas < 3 {
+	if installConfig.IsArbiterEnabled() {
+		controlPlaneTopology = highlyAvailableArbiterTopologyMode
+	} else if installConfig.ControlPlane.Replicas != nil && *installConfig.ControlPlane.Replicas < 3 {
 		controlPlaneTopology = configv1.SingleReplicaTopologyMode
 	} else {
 		controlPlaneTopology = configv1.HighlyAvailableTopologyMode
@@ -23,6 +29,10 @@ func determineTopologies(installConfig *types.InstallConfig) (controlPlaneTopolo
 	switch numOfWorkers {
 	case 0:
 		infrastructureTopology = controlPlaneTopology
+		if installConfig.IsArbiterEnabled() {
+			// the infrastructure workloads run on the two control plane machines
+			infrastructureTopology = configv1.HighlyAvailableTopologyMode
+		}
 	case 1:
 		infrastructureTopology = configv1.SingleReplicaTopologyMode
 	default:
@@ -36,7 +36,7 @@ func configMap(namespace, name string, data genericData) *configurationObject {
 }
 
 func getAPIServerURL(ic *types.InstallConfig) string {
-	return fmt.Sprintf("https://api.%s:6443", ic.ClusterDomain())
+	return fmt.Sprintf("https://%s:6443", ic.APIDomainName())
 }
 
 func getInternalAPIServerURL(ic *types.InstallConfig) string {
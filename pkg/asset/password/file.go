@@ -0,0 +1,64 @@
+package password
+
+import (
+	"os"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+)
+
+// KubeadminPasswordFile is the asset writing the kubeadmin password to the
+// asset directory, unless the install-config disables the kubeadmin user.
+type KubeadminPasswordFile struct {
+	File *asset.File
+}
+
+var _ asset.WritableAsset = (*KubeadminPasswordFile)(nil)
+
+// Dependencies returns the dependencies of the password file.
+func (a *KubeadminPasswordFile) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+		&KubeadminPassword{},
+	}
+}
+
+// Generate generates the password file.
+func (a *KubeadminPasswordFile) Generate(parents asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	kubeadminPassword := &KubeadminPassword{}
+	parents.Get(installConfig, kubeadminPassword)
+
+	a.File = nil
+	if !installConfig.Config.KubeadminDisabled() {
+		a.File = kubeadminPassword.File
+	}
+	return nil
+}
+
+// Name returns the human-friendly name of the asset.
+func (a *KubeadminPasswordFile) Name() string {
+	return "Kubeadmin Password File"
+}
+
+// Files returns the password file.
+func (a *KubeadminPasswordFile) Files() []*asset.File {
+	if a.File != nil {
+		return []*asset.File{a.File}
+	}
+	return []*asset.File{}
+}
+
+// Load loads the password file from the asset directory, so that a password
+// file supplied by the user is kept rather than regenerated.
+func (a *KubeadminPasswordFile) Load(f asset.FileFetcher) (bool, error) {
+	file, err := f.FetchByName(kubeadminPasswordPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	a.File = file
+	return true, nil
+}
@@ -9,6 +9,7 @@ import (
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/deterministic"
 )
 
 var (
@@ -21,9 +22,14 @@ type KubeadminPassword struct {
 	Password     string
 	PasswordHash []byte
 	File         *asset.File
+
+	source *deterministic.Source
 }
 
-var _ asset.WritableAsset = (*KubeadminPassword)(nil)
+var (
+	_ asset.WritableAsset = (*KubeadminPassword)(nil)
+	_ asset.SourcedAsset  = (*KubeadminPassword)(nil)
+)
 
 // Dependencies returns no dependencies.
 func (a *KubeadminPassword) Dependencies() []asset.Asset {
@@ -50,7 +56,7 @@ func (a *KubeadminPassword) generateRandomPasswordHash(length int) error {
 	)
 	var password string
 	for i := 0; i < length; i++ {
-		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(all))))
+		n, err := rand.Int(a.source.Reader(), big.NewInt(int64(len(all))))
 		if err != nil {
 			return err
 		}
@@ -59,7 +65,7 @@ func (a *KubeadminPassword) generateRandomPasswordHash(length int) error {
 			password = newchar
 		}
 		if i < length-1 {
-			n, err = rand.Int(rand.Reader, big.NewInt(int64(len(password)+1)))
+			n, err = rand.Int(a.source.Reader(), big.NewInt(int64(len(password)+1)))
 			if err != nil {
 				return err
 			}
@@ -93,6 +99,12 @@ func (a *KubeadminPassword) Name() string {
 	return "Kubeadmin Password"
 }
 
+// SetSource sets the source the password is drawn from. The salt of its hash
+// is always drawn from crypto/rand.
+func (a *KubeadminPassword) SetSource(source *deterministic.Source) {
+	a.source = source
+}
+
 // Files returns the password file.
 func (a *KubeadminPassword) Files() []*asset.File {
 	if a.File != nil {
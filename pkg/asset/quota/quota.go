@@ -19,6 +19,7 @@ import (
 	"github.com/openshift/installer/pkg/asset/quota/gcp"
 	"github.com/openshift/installer/pkg/asset/quota/openstack"
 	"github.com/openshift/installer/pkg/diagnostics"
+	"github.com/openshift/installer/pkg/offline"
 	"github.com/openshift/installer/pkg/quota"
 	quotaaws "github.com/openshift/installer/pkg/quota/aws"
 	quotagcp "github.com/openshift/installer/pkg/quota/gcp"
@@ -55,6 +56,9 @@ func (a *PlatformQuotaCheck) Dependencies() []asset.Asset {
 
 // Generate queries for input from the user.
 func (a *PlatformQuotaCheck) Generate(dependencies asset.Parents) error {
+	if offline.Skip("Platform quota check") {
+		return nil
+	}
 	ic := &installconfig.InstallConfig{}
 	mastersAsset := &machines.Master{}
 	workersAsset := &machines.Worker{}
@@ -106,7 +110,7 @@ func (a *PlatformQuotaCheck) Generate(dependencies asset.Parents) error {
 		summarizeReport(reports)
 	case typesgcp.Name:
 		services := []string{"compute.googleapis.com", "iam.googleapis.com"}
-		q, err := quotagcp.Load(context.TODO(), ic.Config.Platform.GCP.ProjectID, services...)
+		q, err := quotagcp.Load(context.TODO(), ic.Credentials().GCPCredentials(), ic.Config.Platform.GCP.ProjectID, services...)
 		if quotagcp.IsUnauthorized(err) {
 			logrus.Warnf("Missing permissions to fetch Quotas and therefore will skip checking them: %v, make sure you have `roles/servicemanagement.quotaViewer` assigned to the user.", err)
 			return nil
@@ -114,7 +118,7 @@ func (a *PlatformQuotaCheck) Generate(dependencies asset.Parents) error {
 		if err != nil {
 			return errors.Wrapf(err, "failed to load Quota for services: %s", strings.Join(services, ", "))
 		}
-		session, err := configgcp.GetSession(context.TODO())
+		session, err := configgcp.GetSessionWithCredentials(context.TODO(), ic.Credentials().GCPCredentials())
 		if err != nil {
 			return errors.Wrap(err, "failed to load GCP session")
 		}
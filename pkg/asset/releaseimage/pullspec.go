@@ -8,15 +8,38 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/types"
 )
 
 // Image asset generates the release-image pullspec for the cluster
 type Image struct {
 	PullSpec   string
 	Repository string
+
+	// Overridden is set when the release image is not the one the
+	// installer was built for.
+	Overridden bool
+	// VerificationKeys are the ASCII-armored GPG public keys the release
+	// image must be signed with, if any.
+	VerificationKeys []string
+	// SignatureStores are the stores the signatures of the release image
+	// are looked up in.
+	SignatureStores []string
+
+	override *types.ReleaseImage
 }
 
-var _ asset.Asset = (*Image)(nil)
+var (
+	_ asset.Asset           = (*Image)(nil)
+	_ asset.ConfiguredAsset = (*Image)(nil)
+)
+
+// SetOptions sets the release image to install instead of the default one,
+// from the --release-image flag or the install-config. It takes precedence
+// over the deprecated OPENSHIFT_INSTALL_RELEASE_IMAGE_OVERRIDE.
+func (a *Image) SetOptions(options asset.Options) {
+	a.override = options.ReleaseImage
+}
 
 // Dependencies is the list of assets required to generate ReleaseImage.
 func (a *Image) Dependencies() []asset.Asset {
@@ -26,9 +49,16 @@ func (a *Image) Dependencies() []asset.Asset {
 // Generate creates the asset using the dependencies.
 func (a *Image) Generate(dependencies asset.Parents) error {
 	var pullSpec string
-	if ri, ok := os.LookupEnv("OPENSHIFT_INSTALL_RELEASE_IMAGE_OVERRIDE"); ok && ri != "" {
-		logrus.Warnf("Found override for release image (%s). Please be warned, this is not advised", ri)
+	if a.override != nil && a.override.PullSpec != "" {
+		logrus.Infof("Using release image %s", a.override.PullSpec)
+		pullSpec = a.override.PullSpec
+		a.Overridden = true
+		a.VerificationKeys = a.override.VerificationKeys
+		a.SignatureStores = a.override.SignatureStores
+	} else if ri, ok := os.LookupEnv("OPENSHIFT_INSTALL_RELEASE_IMAGE_OVERRIDE"); ok && ri != "" {
+		logrus.Warnf("Found override for release image (%s). OPENSHIFT_INSTALL_RELEASE_IMAGE_OVERRIDE is deprecated, use --release-image or releaseImage in the install-config instead", ri)
 		pullSpec = ri
+		a.Overridden = true
 	} else {
 		var err error
 		pullSpec, err = Default()
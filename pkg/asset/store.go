@@ -1,11 +1,17 @@
 package asset
 
+import (
+	"context"
+)
+
 // Store is a store for the states of assets.
 type Store interface {
 	// Fetch retrieves the state of the given asset, generating it and its
-	// dependencies if necessary. When purging consumed assets, none of the
-	// assets in assetsToPreserve will be purged.
-	Fetch(assetToFetch Asset, assetsToPreserve ...WritableAsset) error
+	// dependencies if necessary. The context is passed to the assets
+	// generated with a context, and checked before generating each asset.
+	// When purging consumed assets, none of the assets in assetsToPreserve
+	// will be purged.
+	Fetch(ctx context.Context, assetToFetch Asset, assetsToPreserve ...WritableAsset) error
 
 	// Destroy removes the asset from all its internal state and also from
 	// disk if possible.
@@ -15,6 +21,11 @@ type Store interface {
 	// state file
 	DestroyState() error
 
+	// Invalidate removes the asset from the internal state, the state file
+	// and disk, so that it is regenerated the next time it is fetched. Unlike
+	// Destroy, the files of an asset which is only on disk are removed too.
+	Invalidate(Asset) error
+
 	// Load retrieves the state of the given asset but does not generate it if it
 	// does not exist and instead will return nil if not found.
 	Load(Asset) (Asset, error)
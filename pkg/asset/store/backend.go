@@ -0,0 +1,217 @@
+package store
+
+import (
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// stateURLEnv is the environment variable that selects a remote backend for
+// the state file. When it is unset, the state file is kept in the asset
+// directory.
+const stateURLEnv = "OPENSHIFT_INSTALL_STATE_URL"
+
+// ErrStateConflict is the cause of the errors of the remote backends when
+// the state file was changed since the backend read it, e.g. by another
+// installer using the same OPENSHIFT_INSTALL_STATE_URL.
+var ErrStateConflict = errors.New("the state file was changed by another installer since it was read")
+
+// StateBackend persists the contents of the state file.
+type StateBackend interface {
+	// Read returns the contents of the state file, or nil if there is no
+	// state file.
+	Read() ([]byte, error)
+
+	// Write replaces the contents of the state file. The remote backends
+	// only replace the state file they last read or wrote, and fail with
+	// ErrStateConflict if it changed since.
+	Write(data []byte) error
+
+	// Delete removes the state file. Deleting a state file that does not
+	// exist is not an error. The remote backends only delete the state file
+	// they last read or wrote, and fail with ErrStateConflict if it changed
+	// since.
+	Delete() error
+
+	// String returns a human-readable location of the state file.
+	String() string
+}
+
+// newStateBackend returns the backend selected by OPENSHIFT_INSTALL_STATE_URL,
+// or the state file in dir if the variable is unset.
+func newStateBackend(dir string) (StateBackend, error) {
+	rawURL := os.Getenv(stateURLEnv)
+	if rawURL == "" {
+		return &fileBackend{path: filepath.Join(dir, stateFileName)}, nil
+	}
+	backend, err := parseStateURL(rawURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s", stateURLEnv)
+	}
+	return backend, nil
+}
+
+// parseStateURL returns the backend for a state URL. The supported forms are:
+//
+//	s3://<bucket>[/<prefix>]
+//	gs://<bucket>[/<prefix>]
+//	azblob://<account>/<container>[/<prefix>]
+//	etcd://<host>:<port>[/<prefix>] (etcds:// for TLS)
+//
+// The state is stored as <prefix>/.openshift_install_state.json.
+func parseStateURL(rawURL string) (StateBackend, error) {
+	return parseObjectURL(rawURL, stateFileName)
+}
+
+// WriteObject writes the data to the object name under the prefix of the URL,
+// one of the s3, gs and azblob forms of the state URLs, e.g. to keep files of
+// the install next to its state.
+func WriteObject(rawURL, name string, data []byte) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme == "etcd" || u.Scheme == "etcds" {
+		return errors.Errorf("unsupported scheme %q, must be one of s3, gs or azblob", u.Scheme)
+	}
+	backend, err := parseObjectURL(rawURL, name)
+	if err != nil {
+		return err
+	}
+	if err := backend.Write(data); err != nil {
+		return errors.Wrapf(err, "failed to write %s", backend)
+	}
+	return nil
+}
+
+// parseObjectURL returns the backend of the object name under the prefix of
+// the URL.
+func parseObjectURL(rawURL, name string) (StateBackend, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" {
+		return nil, errors.Errorf("%q has no host", rawURL)
+	}
+	prefix := strings.Trim(u.Path, "/")
+
+	switch u.Scheme {
+	case "s3":
+		return &s3Backend{bucket: u.Host, key: path.Join(prefix, name)}, nil
+	case "gs":
+		return &gcsBackend{bucket: u.Host, object: path.Join(prefix, name)}, nil
+	case "azblob":
+		parts := strings.SplitN(prefix, "/", 2)
+		if parts[0] == "" {
+			return nil, errors.Errorf("%q has no container", rawURL)
+		}
+		blob := name
+		if len(parts) == 2 {
+			blob = path.Join(parts[1], name)
+		}
+		return &azureBlobBackend{account: u.Host, container: parts[0], blob: blob}, nil
+	case "etcd", "etcds":
+		scheme := "http"
+		if u.Scheme == "etcds" {
+			scheme = "https"
+		}
+		return &etcdBackend{endpoint: scheme + "://" + u.Host, key: "/" + path.Join(prefix, name)}, nil
+	default:
+		return nil, errors.Errorf("unsupported scheme %q, must be one of s3, gs, azblob, etcd or etcds", u.Scheme)
+	}
+}
+
+// stateVersion is the version of the state file a remote backend last read
+// or wrote, e.g. its ETag or its generation, which its writes are
+// conditional on.
+type stateVersion struct {
+	mu      sync.Mutex
+	known   bool
+	version string
+}
+
+// get returns the version of the state file, "" if there was none, and
+// whether the state file was read or written at all. Writing a state file
+// which was never read, e.g. with WriteObject, is not conditional.
+func (v *stateVersion) get() (string, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.version, v.known
+}
+
+// set records the version of the state file which was read or written, ""
+// if there is none.
+func (v *stateVersion) set(version string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.known = true
+	v.version = version
+}
+
+// fileBackend keeps the state file on the local filesystem.
+type fileBackend struct {
+	path string
+}
+
+func (b *fileBackend) Read() ([]byte, error) {
+	data, err := os.ReadFile(b.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
+func (b *fileBackend) Write(data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(b.path), 0750); err != nil {
+		return err
+	}
+	return os.WriteFile(b.path, data, 0o640) //nolint:gosec // no sensitive info
+}
+
+func (b *fileBackend) Delete() error {
+	err := os.Remove(b.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (b *fileBackend) String() string {
+	return b.path
+}
+
+// NewMemoryBackend returns a backend that keeps the state file in memory,
+// starting from data. It lets a store generate assets from an existing
+// state without saving the result.
+func NewMemoryBackend(data []byte) StateBackend {
+	return &memoryBackend{data: data}
+}
+
+// memoryBackend keeps the state file in memory.
+type memoryBackend struct {
+	data []byte
+}
+
+func (b *memoryBackend) Read() ([]byte, error) {
+	return b.data, nil
+}
+
+func (b *memoryBackend) Write(data []byte) error {
+	b.data = data
+	return nil
+}
+
+func (b *memoryBackend) Delete() error {
+	b.data = nil
+	return nil
+}
+
+func (b *memoryBackend) String() string {
+	return "memory"
+}
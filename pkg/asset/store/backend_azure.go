@@ -0,0 +1,147 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/pkg/errors"
+)
+
+// azureBlobBackend keeps the state file in an Azure Storage blob container.
+// It authenticates with the default Azure credential chain, i.e. the
+// AZURE_* environment variables, a managed identity or the Azure CLI. Its
+// writes are conditional on the ETag of the blob it last read or wrote.
+type azureBlobBackend struct {
+	account   string
+	container string
+	blob      string
+
+	version stateVersion
+}
+
+func (b *azureBlobBackend) client() (*azblob.BlockBlobClient, error) {
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get Azure credentials")
+	}
+	return azblob.NewBlockBlobClient(b.url(), cred, nil)
+}
+
+func (b *azureBlobBackend) url() string {
+	return fmt.Sprintf("https://%s.blob.core.windows.net/%s/%s", b.account, b.container, b.blob)
+}
+
+func (b *azureBlobBackend) Read() ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	client, err := b.client()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Download(ctx, nil)
+	if err != nil {
+		if isBlobNotFound(err) {
+			b.version.set("")
+			return nil, nil
+		}
+		return nil, err
+	}
+	body := resp.Body(nil)
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	b.version.set(stringValue(resp.ETag))
+	return data, nil
+}
+
+func (b *azureBlobBackend) Write(data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	client, err := b.client()
+	if err != nil {
+		return err
+	}
+	options := &azblob.BlockBlobUploadOptions{BlobAccessConditions: b.accessConditions(true)}
+	resp, err := client.Upload(ctx, streaming.NopCloser(bytes.NewReader(data)), options)
+	if err != nil {
+		if isBlobConflict(err) {
+			return ErrStateConflict
+		}
+		return err
+	}
+	b.version.set(stringValue(resp.ETag))
+	return nil
+}
+
+func (b *azureBlobBackend) Delete() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	client, err := b.client()
+	if err != nil {
+		return err
+	}
+	_, err = client.Delete(ctx, &azblob.BlobDeleteOptions{BlobAccessConditions: b.accessConditions(false)})
+	switch {
+	case isBlobConflict(err):
+		return ErrStateConflict
+	case err != nil && !isBlobNotFound(err):
+		return err
+	}
+	b.version.set("")
+	return nil
+}
+
+// accessConditions returns the conditions on the ETag of the blob it was
+// last read or written with, if any. A missing blob is only required to be
+// missing by writes.
+func (b *azureBlobBackend) accessConditions(write bool) *azblob.BlobAccessConditions {
+	version, ok := b.version.get()
+	switch {
+	case !ok:
+		return nil
+	case version != "":
+		return &azblob.BlobAccessConditions{ModifiedAccessConditions: &azblob.ModifiedAccessConditions{IfMatch: &version}}
+	case write:
+		anyETag := azblob.ETagAny
+		return &azblob.BlobAccessConditions{ModifiedAccessConditions: &azblob.ModifiedAccessConditions{IfNoneMatch: &anyETag}}
+	default:
+		return nil
+	}
+}
+
+func (b *azureBlobBackend) String() string {
+	return b.url()
+}
+
+func isBlobNotFound(err error) bool {
+	var storageErr *azblob.StorageError
+	return errors.As(err, &storageErr) && storageErr.ErrorCode == azblob.StorageErrorCodeBlobNotFound
+}
+
+func isBlobConflict(err error) bool {
+	var storageErr *azblob.StorageError
+	if !errors.As(err, &storageErr) {
+		return false
+	}
+	switch storageErr.ErrorCode {
+	case azblob.StorageErrorCodeConditionNotMet, azblob.StorageErrorCodeBlobAlreadyExists:
+		return true
+	default:
+		return false
+	}
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
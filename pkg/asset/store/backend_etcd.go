@@ -0,0 +1,201 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// etcdBackend keeps the state file in an etcd v3 key, using the JSON gRPC
+// gateway of etcd. The state is gzipped to stay under the default request
+// size limit of etcd. For etcds:// endpoints, the CA bundle and client
+// certificate are read from the files named by ETCDCTL_CACERT, ETCDCTL_CERT
+// and ETCDCTL_KEY, like etcdctl does. Its writes are transactions
+// conditional on the revision of the key it last read or wrote.
+type etcdBackend struct {
+	endpoint string
+	key      string
+
+	version stateVersion
+}
+
+type etcdKeyValue struct {
+	Key         []byte `json:"key"`
+	Value       []byte `json:"value,omitempty"`
+	ModRevision int64  `json:"mod_revision,omitempty,string"`
+}
+
+type etcdRangeResponse struct {
+	Kvs []etcdKeyValue `json:"kvs"`
+}
+
+// etcdCompare compares the revision of the last modification of a key,
+// which is 0 for a missing key.
+type etcdCompare struct {
+	Key         []byte `json:"key"`
+	Target      string `json:"target"`
+	Result      string `json:"result"`
+	ModRevision int64  `json:"mod_revision,string"`
+}
+
+type etcdRequestOp struct {
+	RequestPut         *etcdKeyValue `json:"request_put,omitempty"`
+	RequestDeleteRange *etcdKeyValue `json:"request_delete_range,omitempty"`
+}
+
+type etcdTxnRequest struct {
+	Compare []etcdCompare   `json:"compare,omitempty"`
+	Success []etcdRequestOp `json:"success"`
+}
+
+type etcdResponseHeader struct {
+	Revision int64 `json:"revision,string"`
+}
+
+type etcdTxnResponse struct {
+	Header    etcdResponseHeader `json:"header"`
+	Succeeded bool               `json:"succeeded"`
+}
+
+func (b *etcdBackend) client() (*http.Client, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile := os.Getenv("ETCDCTL_CACERT"); caFile != "" {
+		ca, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read etcd CA bundle")
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, errors.Errorf("no certificates found in %s", caFile)
+		}
+	}
+	if certFile, keyFile := os.Getenv("ETCDCTL_CERT"), os.Getenv("ETCDCTL_KEY"); certFile != "" && keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load etcd client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: transport, Timeout: time.Minute}, nil
+}
+
+// call posts the request to the given gateway method and decodes the
+// response into out, if it is not nil.
+func (b *etcdBackend) call(method string, in interface{}, out interface{}) error {
+	client, err := b.client()
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, b.endpoint+"/v3/kv/"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return errors.Errorf("etcd %s of %s failed: %s: %s", method, b.key, resp.Status, bytes.TrimSpace(msg))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (b *etcdBackend) Read() ([]byte, error) {
+	var resp etcdRangeResponse
+	if err := b.call("range", etcdKeyValue{Key: []byte(b.key)}, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		b.version.set("")
+		return nil, nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(resp.Kvs[0].Value))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decompress state")
+	}
+	defer zr.Close()
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, err
+	}
+	b.version.set(strconv.FormatInt(resp.Kvs[0].ModRevision, 10))
+	return data, nil
+}
+
+func (b *etcdBackend) Write(data []byte) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	revision, err := b.txn(etcdRequestOp{RequestPut: &etcdKeyValue{Key: []byte(b.key), Value: buf.Bytes()}})
+	if err != nil {
+		return err
+	}
+	// the revision of the transaction is the revision of the put
+	b.version.set(strconv.FormatInt(revision, 10))
+	return nil
+}
+
+func (b *etcdBackend) Delete() error {
+	if _, err := b.txn(etcdRequestOp{RequestDeleteRange: &etcdKeyValue{Key: []byte(b.key)}}); err != nil {
+		return err
+	}
+	b.version.set("")
+	return nil
+}
+
+// txn runs the operation in a transaction conditional on the key having
+// the revision it was last read or written with, and returns the revision
+// of the transaction.
+func (b *etcdBackend) txn(op etcdRequestOp) (int64, error) {
+	req := etcdTxnRequest{Success: []etcdRequestOp{op}}
+	if version, ok := b.version.get(); ok {
+		var revision int64
+		if version != "" {
+			var err error
+			if revision, err = strconv.ParseInt(version, 10, 64); err != nil {
+				return 0, errors.Wrapf(err, "invalid revision %q", version)
+			}
+		}
+		req.Compare = []etcdCompare{{Key: []byte(b.key), Target: "MOD", Result: "EQUAL", ModRevision: revision}}
+	}
+	var resp etcdTxnResponse
+	if err := b.call("txn", req, &resp); err != nil {
+		return 0, err
+	}
+	if !resp.Succeeded {
+		return 0, ErrStateConflict
+	}
+	return resp.Header.Revision, nil
+}
+
+func (b *etcdBackend) String() string {
+	return fmt.Sprintf("%s%s", b.endpoint, b.key)
+}
@@ -0,0 +1,135 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	storage "google.golang.org/api/storage/v1"
+
+	gcpconfig "github.com/openshift/installer/pkg/asset/installconfig/gcp"
+)
+
+// gcsBackend keeps the state file in a Google Cloud Storage bucket. Its
+// writes are conditional on the generation of the object it last read or
+// wrote.
+type gcsBackend struct {
+	bucket string
+	object string
+
+	version stateVersion
+}
+
+func (b *gcsBackend) client(ctx context.Context) (*storage.Service, error) {
+	session, err := gcpconfig.GetSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return storage.NewService(ctx, option.WithCredentials(session.Credentials))
+}
+
+func (b *gcsBackend) Read() ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	client, err := b.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Objects.Get(b.bucket, b.object).Context(ctx).Download()
+	if err != nil {
+		if isGCSNotFound(err) {
+			b.version.set("")
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	b.version.set(resp.Header.Get("X-Goog-Generation"))
+	return data, nil
+}
+
+func (b *gcsBackend) Write(data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	client, err := b.client(ctx)
+	if err != nil {
+		return err
+	}
+	call := client.Objects.Insert(b.bucket, &storage.Object{Name: b.object}).Media(bytes.NewReader(data)).Context(ctx)
+	if version, ok := b.version.get(); ok {
+		generation, err := gcsGeneration(version)
+		if err != nil {
+			return err
+		}
+		// generation 0 only matches a missing object
+		call = call.IfGenerationMatch(generation)
+	}
+	object, err := call.Do()
+	if err != nil {
+		if isGCSConflict(err) {
+			return ErrStateConflict
+		}
+		return err
+	}
+	b.version.set(strconv.FormatInt(object.Generation, 10))
+	return nil
+}
+
+func (b *gcsBackend) Delete() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	client, err := b.client(ctx)
+	if err != nil {
+		return err
+	}
+	call := client.Objects.Delete(b.bucket, b.object).Context(ctx)
+	if version, ok := b.version.get(); ok && version != "" {
+		generation, err := gcsGeneration(version)
+		if err != nil {
+			return err
+		}
+		call = call.IfGenerationMatch(generation)
+	}
+	err = call.Do()
+	switch {
+	case isGCSConflict(err):
+		return ErrStateConflict
+	case err != nil && !isGCSNotFound(err):
+		return err
+	}
+	b.version.set("")
+	return nil
+}
+
+func (b *gcsBackend) String() string {
+	return "gs://" + b.bucket + "/" + b.object
+}
+
+// gcsGeneration parses the generation of the object, 0 if there is none.
+func gcsGeneration(version string) (int64, error) {
+	if version == "" {
+		return 0, nil
+	}
+	generation, err := strconv.ParseInt(version, 10, 64)
+	return generation, errors.Wrapf(err, "invalid generation %q", version)
+}
+
+func isGCSNotFound(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
+
+func isGCSConflict(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusPreconditionFailed
+}
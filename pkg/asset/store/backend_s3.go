@@ -0,0 +1,148 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/pkg/errors"
+
+	awsconfig "github.com/openshift/installer/pkg/asset/installconfig/aws"
+)
+
+// s3Backend keeps the state file in an AWS S3 bucket. Its writes are
+// conditional on the ETag of the object it last read or wrote.
+type s3Backend struct {
+	bucket string
+	key    string
+
+	version stateVersion
+}
+
+func (b *s3Backend) client(ctx context.Context) (*s3.S3, error) {
+	sess, err := awsconfig.GetSession()
+	if err != nil {
+		return nil, err
+	}
+	region, err := s3manager.GetBucketRegion(ctx, sess, b.bucket, "us-east-1")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the region of bucket %q", b.bucket)
+	}
+	return s3.New(sess, aws.NewConfig().WithRegion(region)), nil
+}
+
+func (b *s3Backend) Read() ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	client, err := b.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out, err := client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(b.key),
+	})
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == s3.ErrCodeNoSuchKey {
+			b.version.set("")
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer out.Body.Close()
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, err
+	}
+	b.version.set(aws.StringValue(out.ETag))
+	return data, nil
+}
+
+func (b *s3Backend) Write(data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	client, err := b.client(ctx)
+	if err != nil {
+		return err
+	}
+	req, out := client.PutObjectRequest(&s3.PutObjectInput{
+		Bucket:               aws.String(b.bucket),
+		Key:                  aws.String(b.key),
+		Body:                 bytes.NewReader(data),
+		ServerSideEncryption: aws.String(s3.ServerSideEncryptionAes256),
+	})
+	req.SetContext(ctx)
+	// the conditional headers are not fields of the input in this version
+	// of the SDK
+	if version, ok := b.version.get(); ok {
+		if version == "" {
+			req.HTTPRequest.Header.Set("If-None-Match", "*")
+		} else {
+			req.HTTPRequest.Header.Set("If-Match", version)
+		}
+	}
+	if err := req.Send(); err != nil {
+		if isS3Conflict(err) {
+			return ErrStateConflict
+		}
+		return err
+	}
+	b.version.set(aws.StringValue(out.ETag))
+	return nil
+}
+
+func (b *s3Backend) Delete() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	client, err := b.client(ctx)
+	if err != nil {
+		return err
+	}
+	// S3 does not report an error when the key does not exist.
+	req, _ := client.DeleteObjectRequest(&s3.DeleteObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(b.key),
+	})
+	req.SetContext(ctx)
+	if version, ok := b.version.get(); ok && version != "" {
+		req.HTTPRequest.Header.Set("If-Match", version)
+	}
+	if err := req.Send(); err != nil {
+		var awsErr awserr.Error
+		switch {
+		case isS3Conflict(err):
+			return ErrStateConflict
+		case errors.As(err, &awsErr) && awsErr.Code() == s3.ErrCodeNoSuchKey:
+			// deleted already, e.g. by the other installer
+		default:
+			return err
+		}
+	}
+	b.version.set("")
+	return nil
+}
+
+func (b *s3Backend) String() string {
+	return "s3://" + b.bucket + "/" + b.key
+}
+
+// isS3Conflict returns whether the error is the failure of the condition of
+// a request, or of a concurrent conditional request.
+func isS3Conflict(err error) bool {
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) {
+		return false
+	}
+	switch awsErr.Code() {
+	case "PreconditionFailed", "ConditionalRequestConflict":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,229 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStateURL(t *testing.T) {
+	cases := []struct {
+		name     string
+		url      string
+		expected StateBackend
+		err      string
+	}{
+		{
+			name:     "s3",
+			url:      "s3://bucket/ci/cluster",
+			expected: &s3Backend{bucket: "bucket", key: "ci/cluster/.openshift_install_state.json"},
+		},
+		{
+			name:     "s3 without prefix",
+			url:      "s3://bucket",
+			expected: &s3Backend{bucket: "bucket", key: ".openshift_install_state.json"},
+		},
+		{
+			name:     "gcs",
+			url:      "gs://bucket/cluster/",
+			expected: &gcsBackend{bucket: "bucket", object: "cluster/.openshift_install_state.json"},
+		},
+		{
+			name:     "azure",
+			url:      "azblob://account/container/cluster",
+			expected: &azureBlobBackend{account: "account", container: "container", blob: "cluster/.openshift_install_state.json"},
+		},
+		{
+			name:     "azure without prefix",
+			url:      "azblob://account/container",
+			expected: &azureBlobBackend{account: "account", container: "container", blob: ".openshift_install_state.json"},
+		},
+		{
+			name: "azure without container",
+			url:  "azblob://account",
+			err:  `"azblob://account" has no container`,
+		},
+		{
+			name:     "etcd",
+			url:      "etcd://etcd.example.com:2379/installer/cluster",
+			expected: &etcdBackend{endpoint: "http://etcd.example.com:2379", key: "/installer/cluster/.openshift_install_state.json"},
+		},
+		{
+			name:     "etcd with TLS",
+			url:      "etcds://etcd.example.com:2379",
+			expected: &etcdBackend{endpoint: "https://etcd.example.com:2379", key: "/.openshift_install_state.json"},
+		},
+		{
+			name: "no host",
+			url:  "s3:///cluster",
+			err:  `"s3:///cluster" has no host`,
+		},
+		{
+			name: "unsupported scheme",
+			url:  "ftp://host/cluster",
+			err:  `unsupported scheme "ftp", must be one of s3, gs, azblob, etcd or etcds`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			backend, err := parseStateURL(tc.url)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, backend)
+		})
+	}
+}
+
+func TestFileBackend(t *testing.T) {
+	dir := t.TempDir()
+	backend := &fileBackend{path: filepath.Join(dir, "sub", stateFileName)}
+
+	data, err := backend.Read()
+	assert.NoError(t, err)
+	assert.Nil(t, data, "missing state file should read as no state")
+
+	assert.NoError(t, backend.Write([]byte("{}")))
+	data, err = backend.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("{}"), data)
+
+	assert.NoError(t, backend.Delete())
+	_, err = os.Stat(backend.path)
+	assert.True(t, os.IsNotExist(err), "state file should be deleted")
+	assert.NoError(t, backend.Delete(), "deleting a missing state file should succeed")
+}
+
+// fakeEtcd serves the range and txn methods of the JSON gRPC gateway of
+// etcd, for a single key.
+type fakeEtcd struct {
+	mu       sync.Mutex
+	revision int64
+	kv       *etcdKeyValue
+}
+
+func (f *fakeEtcd) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.URL.Path {
+	case "/v3/kv/range":
+		resp := etcdRangeResponse{}
+		if f.kv != nil {
+			resp.Kvs = []etcdKeyValue{*f.kv}
+		}
+		json.NewEncoder(w).Encode(resp)
+	case "/v3/kv/txn":
+		var req etcdTxnRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		succeeded := true
+		for _, c := range req.Compare {
+			if c.Target != "MOD" || c.Result != "EQUAL" {
+				http.Error(w, fmt.Sprintf("unsupported comparison %s %s", c.Target, c.Result), http.StatusBadRequest)
+				return
+			}
+			var modRevision int64
+			if f.kv != nil {
+				modRevision = f.kv.ModRevision
+			}
+			succeeded = succeeded && modRevision == c.ModRevision
+		}
+		if succeeded {
+			f.revision++
+			for _, op := range req.Success {
+				switch {
+				case op.RequestPut != nil:
+					f.kv = &etcdKeyValue{Key: op.RequestPut.Key, Value: op.RequestPut.Value, ModRevision: f.revision}
+				case op.RequestDeleteRange != nil:
+					f.kv = nil
+				}
+			}
+		}
+		json.NewEncoder(w).Encode(etcdTxnResponse{Header: etcdResponseHeader{Revision: f.revision}, Succeeded: succeeded})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestEtcdBackendContendedWrites(t *testing.T) {
+	server := httptest.NewServer(&fakeEtcd{})
+	defer server.Close()
+	testContendedWrites(t, func() StateBackend {
+		return &etcdBackend{endpoint: server.URL, key: "/cluster/" + stateFileName}
+	})
+}
+
+// testContendedWrites checks that the backends of installers sharing a
+// state file only replace or delete the state file they last read or
+// wrote.
+func testContendedWrites(t *testing.T, newBackend func() StateBackend) {
+	read := func(backend StateBackend) string {
+		t.Helper()
+		data, err := backend.Read()
+		assert.NoError(t, err)
+		return string(data)
+	}
+	first, second := newBackend(), newBackend()
+
+	// both installers find no state, and the first one creates it
+	assert.Equal(t, "", read(first))
+	assert.Equal(t, "", read(second))
+	assert.NoError(t, first.Write([]byte("first")))
+	assert.ErrorIs(t, second.Write([]byte("second")), ErrStateConflict, "the state created by another installer was replaced")
+
+	// once the second installer reads the state again, it replaces it, and
+	// the writes of the first one conflict
+	assert.Equal(t, "first", read(second))
+	assert.NoError(t, second.Write([]byte("second")))
+	assert.NoError(t, second.Write([]byte("second again")), "the writes of an installer conflicted with each other")
+	assert.ErrorIs(t, first.Write([]byte("first again")), ErrStateConflict, "the state written by another installer was replaced")
+	assert.ErrorIs(t, first.Delete(), ErrStateConflict, "the state written by another installer was deleted")
+	assert.Equal(t, "second again", read(first))
+	assert.NoError(t, first.Delete())
+	assert.ErrorIs(t, second.Write([]byte("second")), ErrStateConflict, "the state deleted by another installer was written again")
+
+	// a backend which did not read the state, e.g. for WriteObject, writes
+	// it unconditionally
+	assert.NoError(t, newBackend().Write([]byte("object")))
+	assert.Equal(t, "object", read(first))
+
+	// of the installers which read the same state, only one writes it
+	backends := make([]StateBackend, 10)
+	for i := range backends {
+		backends[i] = newBackend()
+		assert.Equal(t, "object", read(backends[i]))
+	}
+	errs := make([]error, len(backends))
+	var wg sync.WaitGroup
+	for i, backend := range backends {
+		wg.Add(1)
+		go func(i int, backend StateBackend) {
+			defer wg.Done()
+			errs[i] = backend.Write([]byte(fmt.Sprintf("installer %d", i)))
+		}(i, backend)
+	}
+	wg.Wait()
+	written := -1
+	for i, err := range errs {
+		if err == nil {
+			assert.Equal(t, -1, written, "several installers wrote the state")
+			written = i
+			continue
+		}
+		assert.ErrorIs(t, err, ErrStateConflict)
+	}
+	if assert.NotEqual(t, -1, written, "no installer wrote the state") {
+		assert.Equal(t, fmt.Sprintf("installer %d", written), read(first))
+	}
+}
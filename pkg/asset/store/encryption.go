@@ -0,0 +1,320 @@
+package store
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+
+	awsconfig "github.com/openshift/installer/pkg/asset/installconfig/aws"
+)
+
+const (
+	// statePassphraseEnv is the environment variable holding the passphrase
+	// the state file is encrypted with.
+	statePassphraseEnv = "OPENSHIFT_INSTALL_STATE_PASSPHRASE"
+	// stateKMSKeyEnv is the environment variable holding the ID, ARN or
+	// alias of the AWS KMS key the state file is encrypted with.
+	stateKMSKeyEnv = "OPENSHIFT_INSTALL_STATE_KMS_KEY_ID"
+
+	// encryptedDir is the directory of the assets directory whose files,
+	// the kubeconfigs and the kubeadmin password, are encrypted along with
+	// the state file.
+	encryptedDir = "auth"
+)
+
+// encryptedStateHeader starts every encrypted state file, so that encrypted
+// and plaintext state files can be told apart.
+var encryptedStateHeader = []byte("openshift-install-encrypted-state/v1\n")
+
+// encryptedFileHeader starts every encrypted file of the assets directory.
+var encryptedFileHeader = []byte("openshift-install-encrypted-file/v1\n")
+
+// encryptedState is the envelope of an encrypted state file or asset file.
+// The data is sealed with AES-256-GCM under a data key that is either derived from a
+// passphrase with scrypt, or generated and wrapped by AWS KMS.
+type encryptedState struct {
+	// Salt is the scrypt salt, set for passphrase encryption.
+	Salt []byte `json:"salt,omitempty"`
+	// KMSKeyID is the KMS key that wrapped the data key, set for KMS
+	// encryption.
+	KMSKeyID string `json:"kmsKeyId,omitempty"`
+	// EncryptedKey is the data key wrapped by KMS.
+	EncryptedKey []byte `json:"encryptedKey,omitempty"`
+	Nonce        []byte `json:"nonce"`
+	Ciphertext   []byte `json:"ciphertext"`
+}
+
+// dataKeySource provides the AES keys the state file is sealed with.
+type dataKeySource interface {
+	// newKey returns a new data key and records in envelope what is needed
+	// to recover it.
+	newKey(envelope *encryptedState) ([]byte, error)
+	// key recovers the data key of envelope.
+	key(envelope *encryptedState) ([]byte, error)
+}
+
+// encryptedBackend encrypts the state file before handing it to the
+// wrapped backend. Plaintext state files are still read, so that existing
+// installs are encrypted the next time their state is saved.
+type encryptedBackend struct {
+	StateBackend
+	keys dataKeySource
+}
+
+// stateKeys returns the source of the data keys configured by
+// OPENSHIFT_INSTALL_STATE_PASSPHRASE or OPENSHIFT_INSTALL_STATE_KMS_KEY_ID,
+// or nil when neither is set.
+func stateKeys() (dataKeySource, error) {
+	passphrase, kmsKeyID := os.Getenv(statePassphraseEnv), os.Getenv(stateKMSKeyEnv)
+	switch {
+	case passphrase != "" && kmsKeyID != "":
+		return nil, errors.Errorf("only one of %s and %s may be set", statePassphraseEnv, stateKMSKeyEnv)
+	case passphrase != "":
+		return &passphraseKeySource{passphrase: []byte(passphrase)}, nil
+	case kmsKeyID != "":
+		return &kmsKeySource{keyID: kmsKeyID}, nil
+	}
+	return nil, nil
+}
+
+// newEncryptedBackend wraps backend with the encryption configured by
+// OPENSHIFT_INSTALL_STATE_PASSPHRASE or OPENSHIFT_INSTALL_STATE_KMS_KEY_ID.
+// Without either, the state is written in plaintext.
+func newEncryptedBackend(backend StateBackend) (StateBackend, error) {
+	keys, err := stateKeys()
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedBackend{StateBackend: backend, keys: keys}, nil
+}
+
+func (b *encryptedBackend) Read() ([]byte, error) {
+	data, err := b.StateBackend.Read()
+	if err != nil || !bytes.HasPrefix(data, encryptedStateHeader) {
+		return data, err
+	}
+	if b.keys == nil {
+		return nil, errors.Errorf("the state file is encrypted, set %s or %s to decrypt it", statePassphraseEnv, stateKMSKeyEnv)
+	}
+	return decryptState(data, b.keys)
+}
+
+func (b *encryptedBackend) Write(data []byte) error {
+	if b.keys == nil {
+		return b.StateBackend.Write(data)
+	}
+	sealed, err := encryptState(data, b.keys)
+	if err != nil {
+		return errors.Wrap(err, "failed to encrypt state")
+	}
+	return b.StateBackend.Write(sealed)
+}
+
+// encryptedFile returns whether the file of the assets directory is encrypted
+// when a passphrase or KMS key is set: the files under auth/, the
+// kubeconfigs and the kubeadmin password.
+func encryptedFile(filename string) bool {
+	return strings.HasPrefix(filepath.ToSlash(filepath.Clean(filename)), encryptedDir+"/")
+}
+
+// EncryptFile returns the data of the file of the assets directory as it is
+// written to the directory: encrypted with the passphrase or KMS key of the
+// state when the file is one of those encryptedFile reports, and unchanged
+// otherwise.
+func EncryptFile(filename string, data []byte) ([]byte, error) {
+	if !encryptedFile(filename) {
+		return data, nil
+	}
+	keys, err := stateKeys()
+	if err != nil || keys == nil {
+		return data, err
+	}
+	sealed, err := seal(data, keys, encryptedFileHeader)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to encrypt %s", filename)
+	}
+	return sealed, nil
+}
+
+// Encrypted returns whether the data of a file of the assets directory is
+// encrypted.
+func Encrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedFileHeader)
+}
+
+// DecryptFile returns the plaintext of the data of a file of the assets
+// directory, which is returned unchanged when it is not encrypted.
+func DecryptFile(filename string, data []byte) ([]byte, error) {
+	if !Encrypted(data) {
+		return data, nil
+	}
+	keys, err := stateKeys()
+	if err != nil {
+		return nil, err
+	}
+	if keys == nil {
+		return nil, errors.Errorf("%s is encrypted, set %s or %s to decrypt it", filename, statePassphraseEnv, stateKMSKeyEnv)
+	}
+	return unseal(data, keys, encryptedFileHeader, filename)
+}
+
+// ReadFile reads the file of the assets directory at path, decrypting it when
+// it is encrypted.
+func ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return DecryptFile(path, data)
+}
+
+func encryptState(data []byte, keys dataKeySource) ([]byte, error) {
+	return seal(data, keys, encryptedStateHeader)
+}
+
+func decryptState(data []byte, keys dataKeySource) ([]byte, error) {
+	return unseal(data, keys, encryptedStateHeader, "state")
+}
+
+// seal encrypts the data into an envelope which starts with the header.
+func seal(data []byte, keys dataKeySource, header []byte) ([]byte, error) {
+	envelope := &encryptedState{}
+	key, err := keys.newKey(envelope)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	envelope.Nonce = make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, envelope.Nonce); err != nil {
+		return nil, err
+	}
+	envelope.Ciphertext = aead.Seal(nil, envelope.Nonce, data, header)
+	raw, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, header...), raw...), nil
+}
+
+// unseal decrypts the envelope of the data, which starts with the header, and
+// names what it decrypts in its errors.
+func unseal(data []byte, keys dataKeySource, header []byte, what string) ([]byte, error) {
+	envelope := &encryptedState{}
+	if err := json.Unmarshal(bytes.TrimPrefix(data, header), envelope); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse encrypted %s", what)
+	}
+	key, err := keys.key(envelope)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(envelope.Nonce) != aead.NonceSize() {
+		return nil, errors.Errorf("invalid nonce in encrypted %s", what)
+	}
+	plaintext, err := aead.Open(nil, envelope.Nonce, envelope.Ciphertext, header)
+	if err != nil {
+		return nil, errors.Errorf("failed to decrypt %s, the passphrase or key may be wrong", what)
+	}
+	return plaintext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// passphraseKeySource derives data keys from a passphrase with scrypt, using
+// a new salt for every write.
+type passphraseKeySource struct {
+	passphrase []byte
+}
+
+func (s *passphraseKeySource) newKey(envelope *encryptedState) ([]byte, error) {
+	envelope.Salt = make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, envelope.Salt); err != nil {
+		return nil, err
+	}
+	return s.key(envelope)
+}
+
+func (s *passphraseKeySource) key(envelope *encryptedState) ([]byte, error) {
+	if len(envelope.Salt) == 0 {
+		return nil, errors.Errorf("the data was not encrypted with a passphrase, set %s instead", stateKMSKeyEnv)
+	}
+	return scrypt.Key(s.passphrase, envelope.Salt, 1<<15, 8, 1, 32)
+}
+
+// kmsKeySource generates data keys with AWS KMS.
+type kmsKeySource struct {
+	keyID string
+}
+
+func (s *kmsKeySource) client(keyID string) (*kms.KMS, error) {
+	sess, err := awsconfig.GetSession()
+	if err != nil {
+		return nil, err
+	}
+	config := aws.NewConfig()
+	// KMS keys are regional, so use the region of the key when it is
+	// given as an ARN.
+	if parsed, err := arn.Parse(keyID); err == nil {
+		config = config.WithRegion(parsed.Region)
+	}
+	return kms.New(sess, config), nil
+}
+
+func (s *kmsKeySource) newKey(envelope *encryptedState) ([]byte, error) {
+	client, err := s.client(s.keyID)
+	if err != nil {
+		return nil, err
+	}
+	out, err := client.GenerateDataKey(&kms.GenerateDataKeyInput{
+		KeyId:   aws.String(s.keyID),
+		KeySpec: aws.String(kms.DataKeySpecAes256),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to generate a data key with %s", s.keyID)
+	}
+	envelope.KMSKeyID = aws.StringValue(out.KeyId)
+	envelope.EncryptedKey = out.CiphertextBlob
+	return out.Plaintext, nil
+}
+
+func (s *kmsKeySource) key(envelope *encryptedState) ([]byte, error) {
+	if len(envelope.EncryptedKey) == 0 {
+		return nil, errors.Errorf("the data was not encrypted with KMS, set %s instead", statePassphraseEnv)
+	}
+	client, err := s.client(envelope.KMSKeyID)
+	if err != nil {
+		return nil, err
+	}
+	out, err := client.Decrypt(&kms.DecryptInput{
+		KeyId:          aws.String(envelope.KMSKeyID),
+		CiphertextBlob: envelope.EncryptedKey,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decrypt the data key with %s", envelope.KMSKeyID)
+	}
+	return out.Plaintext, nil
+}
@@ -0,0 +1,126 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptedBackend(t *testing.T) {
+	state := []byte(`{"*password.KubeadminPassword": {"Password": "secret"}}`)
+
+	t.Run("passphrase round trip", func(t *testing.T) {
+		t.Setenv(statePassphraseEnv, "correct horse battery staple")
+		inner := &memoryBackend{}
+		backend, err := newEncryptedBackend(inner)
+		assert.NoError(t, err)
+
+		assert.NoError(t, backend.Write(state))
+		assert.NotContains(t, string(inner.data), "secret")
+		assert.True(t, len(inner.data) > len(encryptedStateHeader))
+		assert.Equal(t, encryptedStateHeader, inner.data[:len(encryptedStateHeader)])
+
+		data, err := backend.Read()
+		assert.NoError(t, err)
+		assert.Equal(t, state, data)
+	})
+
+	t.Run("wrong passphrase", func(t *testing.T) {
+		inner := &memoryBackend{}
+		sealed, err := encryptState(state, &passphraseKeySource{passphrase: []byte("one")})
+		assert.NoError(t, err)
+		inner.data = sealed
+
+		t.Setenv(statePassphraseEnv, "two")
+		backend, err := newEncryptedBackend(inner)
+		assert.NoError(t, err)
+		_, err = backend.Read()
+		assert.EqualError(t, err, "failed to decrypt state, the passphrase or key may be wrong")
+	})
+
+	t.Run("encrypted without key", func(t *testing.T) {
+		sealed, err := encryptState(state, &passphraseKeySource{passphrase: []byte("one")})
+		assert.NoError(t, err)
+		backend, err := newEncryptedBackend(&memoryBackend{data: sealed})
+		assert.NoError(t, err)
+		_, err = backend.Read()
+		assert.EqualError(t, err, "the state file is encrypted, set OPENSHIFT_INSTALL_STATE_PASSPHRASE or OPENSHIFT_INSTALL_STATE_KMS_KEY_ID to decrypt it")
+	})
+
+	t.Run("plaintext state is read and encrypted on write", func(t *testing.T) {
+		t.Setenv(statePassphraseEnv, "passphrase")
+		inner := &memoryBackend{data: state}
+		backend, err := newEncryptedBackend(inner)
+		assert.NoError(t, err)
+
+		data, err := backend.Read()
+		assert.NoError(t, err)
+		assert.Equal(t, state, data)
+
+		assert.NoError(t, backend.Write(data))
+		assert.NotContains(t, string(inner.data), "secret")
+	})
+
+	t.Run("passphrase and KMS key", func(t *testing.T) {
+		t.Setenv(statePassphraseEnv, "passphrase")
+		t.Setenv(stateKMSKeyEnv, "alias/installer")
+		_, err := newEncryptedBackend(&memoryBackend{})
+		assert.EqualError(t, err, "only one of OPENSHIFT_INSTALL_STATE_PASSPHRASE and OPENSHIFT_INSTALL_STATE_KMS_KEY_ID may be set")
+	})
+}
+
+func TestEncryptFile(t *testing.T) {
+	kubeconfig := []byte("users:\n- name: admin\n  user:\n    client-key-data: secret\n")
+
+	t.Run("without key", func(t *testing.T) {
+		data, err := EncryptFile("auth/kubeconfig", kubeconfig)
+		assert.NoError(t, err)
+		assert.Equal(t, kubeconfig, data)
+	})
+
+	t.Run("only auth is encrypted", func(t *testing.T) {
+		t.Setenv(statePassphraseEnv, "passphrase")
+		data, err := EncryptFile("manifests/cvo-overrides.yaml", kubeconfig)
+		assert.NoError(t, err)
+		assert.Equal(t, kubeconfig, data)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Setenv(statePassphraseEnv, "passphrase")
+		data, err := EncryptFile("auth/kubeconfig", kubeconfig)
+		assert.NoError(t, err)
+		assert.True(t, Encrypted(data))
+		assert.NotContains(t, string(data), "secret")
+
+		dir := t.TempDir()
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, "auth"), 0o750))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "auth", "kubeconfig"), data, 0o640))
+		file, err := (&fileFetcher{directory: dir}).FetchByName("auth/kubeconfig")
+		if assert.NoError(t, err) {
+			assert.Equal(t, kubeconfig, file.Data)
+		}
+	})
+
+	t.Run("encrypted without key", func(t *testing.T) {
+		data, err := seal(kubeconfig, &passphraseKeySource{passphrase: []byte("one")}, encryptedFileHeader)
+		assert.NoError(t, err)
+		_, err = DecryptFile("auth/kubeconfig", data)
+		assert.EqualError(t, err, "auth/kubeconfig is encrypted, set OPENSHIFT_INSTALL_STATE_PASSPHRASE or OPENSHIFT_INSTALL_STATE_KMS_KEY_ID to decrypt it")
+	})
+
+	t.Run("wrong passphrase", func(t *testing.T) {
+		data, err := seal(kubeconfig, &passphraseKeySource{passphrase: []byte("one")}, encryptedFileHeader)
+		assert.NoError(t, err)
+		t.Setenv(statePassphraseEnv, "two")
+		_, err = DecryptFile("auth/kubeconfig", data)
+		assert.EqualError(t, err, "failed to decrypt auth/kubeconfig, the passphrase or key may be wrong")
+	})
+
+	t.Run("state is not a file", func(t *testing.T) {
+		data, err := encryptState(kubeconfig, &passphraseKeySource{passphrase: []byte("passphrase")})
+		assert.NoError(t, err)
+		assert.False(t, Encrypted(data))
+	})
+}
@@ -0,0 +1,339 @@
+package store
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/installer/pkg/version"
+)
+
+const (
+	// exportManifestName is the name of the manifest of an export in its
+	// archive.
+	exportManifestName = ".openshift_install_export.json"
+
+	// redacted replaces the secrets of a redacted export.
+	redacted = "REDACTED"
+)
+
+var (
+	// redactedStateAssets are the assets of the state which a redacted
+	// export keeps: the ones gather and destroy use. The others are
+	// left out, as their files, e.g. the ignition configs, embed secrets.
+	redactedStateAssets = map[string]bool{
+		"*installconfig.InstallConfig": true,
+		"*installconfig.ClusterID":     true,
+	}
+
+	// secretKey matches the keys of the JSON and YAML files whose values a
+	// redacted export replaces, e.g. the pull secret of the install-config
+	// and the ignition configs of the terraform variables and state.
+	secretKey = regexp.MustCompile(`(?i)password|passwd|secret|token|private[-_]?key|credentials$|auths|ignition|user[-_]?data|custom_data|service_account|kubeconfig|^content$`)
+
+	// logPassword matches the kubeadmin password in the log file.
+	logPassword = regexp.MustCompile(`(password: \\?")[^"\\]*(\\?")`)
+)
+
+// ExportManifest describes an export of the assets directory.
+type ExportManifest struct {
+	// Version is the version of the installer which exported the directory.
+	Version string `json:"version"`
+	// Created is when the directory was exported.
+	Created time.Time `json:"created"`
+	// Redacted is whether the secrets were left out of the export.
+	Redacted bool `json:"redacted"`
+}
+
+// Export writes the assets directory dir and its state, wherever the state
+// is kept, to w as a gzipped tarball. The state and the files are exported
+// as they are stored, so an encrypted state stays encrypted. When
+// redactSecrets is set, the state is decrypted and the kubeadmin password,
+// the kubeconfigs, the private keys, the ignition configs and the other
+// secrets are left out or replaced, keeping what destroy and gather use.
+// The install cannot be resumed from such an export.
+func Export(dir string, w io.Writer, redactSecrets bool) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	raw, err := version.Version()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the version of the installer")
+	}
+	manifest, err := json.MarshalIndent(ExportManifest{Version: raw, Created: time.Now().UTC(), Redacted: redactSecrets}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, exportManifestName, 0o640, manifest); err != nil {
+		return err
+	}
+
+	backend, err := newStateBackend(dir)
+	if err != nil {
+		return err
+	}
+	// only a redacted export needs the plaintext of the state
+	if redactSecrets {
+		if backend, err = newEncryptedBackend(backend); err != nil {
+			return err
+		}
+	}
+	state, err := backend.Read()
+	if err != nil {
+		return errors.Wrapf(err, "failed to read state file %q", backend)
+	}
+	if state != nil {
+		if redactSecrets {
+			if state, err = redactState(state); err != nil {
+				return errors.Wrapf(err, "failed to redact state file %q", backend)
+			}
+		}
+		if err := writeTarFile(tw, stateFileName, 0o640, state); err != nil {
+			return err
+		}
+	}
+
+	err = filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		name = filepath.ToSlash(name)
+		switch {
+		case info.IsDir():
+			// the working directory of terraform is temporary
+			if name == "terraform" {
+				return filepath.SkipDir
+			}
+			return nil
+		case !info.Mode().IsRegular(), name == stateFileName, name == exportManifestName:
+			// the state is exported from its backend
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if redactSecrets {
+			if data = redactFile(name, data); data == nil {
+				logrus.Debugf("Left %s out of the export", name)
+				return nil
+			}
+		}
+		return writeTarFile(tw, name, info.Mode().Perm(), data)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to export the assets directory")
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// Import extracts an export of an assets directory into dir, which must
+// not have a state, and writes the state to the backend of dir. An
+// encrypted state is written as it is, a plaintext one is encrypted when a
+// passphrase or KMS key is set. It returns the manifest of the export.
+func Import(dir string, r io.Reader) (*ExportManifest, error) {
+	backend, err := newStateBackend(dir)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := newEncryptedBackend(backend)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := backend.Read()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read state file %q", backend)
+	}
+	if existing != nil {
+		return nil, errors.Errorf("the assets directory already has a state in %s", backend)
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the export")
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	var manifest *ExportManifest
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read the export")
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(header.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, errors.Errorf("the export has a file outside of the assets directory: %s", header.Name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %s from the export", name)
+		}
+
+		switch name {
+		case exportManifestName:
+			manifest = &ExportManifest{}
+			if err := json.Unmarshal(data, manifest); err != nil {
+				return nil, errors.Wrap(err, "failed to unmarshal the manifest of the export")
+			}
+		case stateFileName:
+			writer := encrypted
+			if bytes.HasPrefix(data, encryptedStateHeader) {
+				writer = backend
+			}
+			if err := writer.Write(data); err != nil {
+				return nil, errors.Wrapf(err, "failed to write state file %q", backend)
+			}
+		default:
+			if err := writeNewFile(filepath.Join(dir, filepath.FromSlash(name)), data, os.FileMode(header.Mode).Perm()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if manifest == nil {
+		return nil, errors.New("the archive is not an export of an assets directory")
+	}
+	return manifest, nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, mode os.FileMode, data []byte) error {
+	header := &tar.Header{
+		Name:     name,
+		Mode:     int64(mode),
+		Size:     int64(len(data)),
+		ModTime:  time.Now(),
+		Typeflag: tar.TypeReg,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// writeNewFile writes a file which must not exist.
+func writeNewFile(p string, data []byte, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return errors.Wrap(err, "failed to import the file")
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// redactState keeps the assets of the state which destroy and gather use,
+// with their secrets replaced.
+func redactState(data []byte) ([]byte, error) {
+	assets := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &assets); err != nil {
+		return nil, err
+	}
+	kept := map[string]interface{}{}
+	for name, raw := range assets {
+		if !redactedStateAssets[name] {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+		kept[name] = redactValue(value, false)
+	}
+	return json.MarshalIndent(kept, "", "    ")
+}
+
+// redactFile returns the file with its secrets replaced, or nil when it is
+// left out of the export.
+func redactFile(name string, data []byte) []byte {
+	switch {
+	case strings.HasPrefix(name, "auth/"), strings.HasSuffix(name, ".ign"), bytes.Contains(data, []byte("PRIVATE KEY-----")):
+		return nil
+	case name == ".openshift_install.log":
+		return logPassword.ReplaceAll(data, []byte("${1}"+redacted+"${2}"))
+	case strings.HasSuffix(name, ".json"), strings.HasSuffix(name, ".tfstate"):
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil
+		}
+		redactedData, err := json.MarshalIndent(redactValue(value, false), "", "  ")
+		if err != nil {
+			return nil
+		}
+		return redactedData
+	case strings.HasSuffix(name, ".yaml"), strings.HasSuffix(name, ".yml"):
+		var value interface{}
+		if err := yaml.Unmarshal(data, &value); err != nil {
+			return nil
+		}
+		if object, ok := value.(map[string]interface{}); ok && object["kind"] == "Secret" {
+			return nil
+		}
+		redactedData, err := yaml.Marshal(redactValue(value, false))
+		if err != nil {
+			return nil
+		}
+		return redactedData
+	}
+	return data
+}
+
+// redactValue replaces the strings of the value under the keys of secrets,
+// keeping the types of the values so that the files can still be read, e.g.
+// the terraform state. The contents of the files of assets are removed.
+func redactValue(value interface{}, secret bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if _, ok := v["Filename"]; ok {
+			if _, ok := v["Data"]; ok {
+				v["Data"] = nil
+			}
+		}
+		for key, child := range v {
+			v[key] = redactValue(child, secret || secretKey.MatchString(key))
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = redactValue(child, secret)
+		}
+		return v
+	case string:
+		if secret || strings.Contains(v, "PRIVATE KEY-----") {
+			return redacted
+		}
+		return v
+	default:
+		return v
+	}
+}
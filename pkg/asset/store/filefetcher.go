@@ -1,7 +1,6 @@
 package store
 
 import (
-	"os"
 	"path/filepath"
 
 	"github.com/openshift/installer/pkg/asset"
@@ -11,9 +10,10 @@ type fileFetcher struct {
 	directory string
 }
 
-// FetchByName returns the file with the given name.
+// FetchByName returns the file with the given name, decrypted when it was
+// encrypted with the state.
 func (f *fileFetcher) FetchByName(name string) (*asset.File, error) {
-	data, err := os.ReadFile(filepath.Join(f.directory, name))
+	data, err := ReadFile(filepath.Join(f.directory, name))
 	if err != nil {
 		return nil, err
 	}
@@ -29,7 +29,7 @@ func (f *fileFetcher) FetchByPattern(pattern string) (files []*asset.File, err e
 
 	files = make([]*asset.File, 0, len(matches))
 	for _, path := range matches {
-		data, err := os.ReadFile(path)
+		data, err := ReadFile(path)
 		if err != nil {
 			return nil, err
 		}
@@ -1,15 +1,16 @@
 package store
 
 import (
+	"context"
 	"encoding/json"
-	"os"
-	"path/filepath"
 	"reflect"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/deterministic"
+	"github.com/openshift/installer/pkg/tracing"
 )
 
 const (
@@ -49,18 +50,97 @@ type storeImpl struct {
 	assets          map[reflect.Type]*assetState
 	stateFileAssets map[string]json.RawMessage
 	fileFetcher     asset.FileFetcher
+	backend         StateBackend
+	source          *deterministic.Source
+	options         asset.Options
+	// contextAssetFailed is set when the generation of a ContextAsset
+	// failed, e.g. the provisioning of the infrastructure.
+	contextAssetFailed bool
+	// readOnly is set when the fetched assets are neither saved in the state
+	// file nor purged from the directory.
+	readOnly bool
+}
+
+// Option configures an asset store.
+type Option func(*storeImpl)
+
+// WithSource generates the assets with the random values and the time of the
+// source, instead of crypto/rand and the wall clock.
+func WithSource(source *deterministic.Source) Option {
+	return func(s *storeImpl) {
+		s.source = source
+	}
+}
+
+// WithOptions generates the assets with the options of the install.
+func WithOptions(options asset.Options) Option {
+	return func(s *storeImpl) {
+		s.options = options
+	}
+}
+
+// WithReadOnlyState fetches the assets without saving them in the state file
+// or purging the consumed assets from the directory, so that generating the
+// assets leaves the directory and the state as they were.
+func WithReadOnlyState() Option {
+	return func(s *storeImpl) {
+		s.readOnly = true
+	}
 }
 
 // NewStore returns an asset store that implements the asset.Store interface.
-func NewStore(dir string) (asset.Store, error) {
-	return newStore(dir)
+// The state file is kept in dir, unless OPENSHIFT_INSTALL_STATE_URL selects a
+// remote backend for it, and is encrypted when OPENSHIFT_INSTALL_STATE_PASSPHRASE
+// or OPENSHIFT_INSTALL_STATE_KMS_KEY_ID is set, along with the files under
+// auth/ in dir.
+func NewStore(dir string, options ...Option) (asset.Store, error) {
+	store, err := newStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, option := range options {
+		option(store)
+	}
+	return store, nil
+}
+
+// NewStoreWithBackend returns an asset store that keeps its state file in
+// the given backend.
+func NewStoreWithBackend(dir string, backend StateBackend, options ...Option) (asset.Store, error) {
+	store, err := newStoreWithBackend(dir, backend)
+	if err != nil {
+		return nil, err
+	}
+	for _, option := range options {
+		option(store)
+	}
+	return store, nil
+}
+
+// NewStateBackend returns the backend NewStore keeps the state file of dir
+// in.
+func NewStateBackend(dir string) (StateBackend, error) {
+	backend, err := newStateBackend(dir)
+	if err != nil {
+		return nil, err
+	}
+	return newEncryptedBackend(backend)
 }
 
 func newStore(dir string) (*storeImpl, error) {
+	backend, err := NewStateBackend(dir)
+	if err != nil {
+		return nil, err
+	}
+	return newStoreWithBackend(dir, backend)
+}
+
+func newStoreWithBackend(dir string, backend StateBackend) (*storeImpl, error) {
 	store := &storeImpl{
 		directory:   dir,
 		fileFetcher: &fileFetcher{directory: dir},
 		assets:      map[reflect.Type]*assetState{},
+		backend:     backend,
 	}
 
 	if err := store.loadStateFile(); err != nil {
@@ -72,10 +152,21 @@ func newStore(dir string) (*storeImpl, error) {
 // Fetch retrieves the state of the given asset, generating it and its
 // dependencies if necessary. When purging consumed assets, none of the
 // assets in preserved will be purged.
-func (s *storeImpl) Fetch(a asset.Asset, preserved ...asset.WritableAsset) error {
-	if err := s.fetch(a, ""); err != nil {
+func (s *storeImpl) Fetch(ctx context.Context, a asset.Asset, preserved ...asset.WritableAsset) error {
+	if err := s.fetch(ctx, a, ""); err != nil {
+		// The assets generated before a ContextAsset failed are saved, so
+		// that re-running resumes it with them, e.g. with the same
+		// infrastructure ID, instead of generating them again.
+		if s.contextAssetFailed && !s.readOnly {
+			if err2 := s.saveStateFile(); err2 != nil {
+				logrus.Error(errors.Wrap(err2, "failed to save state"))
+			}
+		}
 		return err
 	}
+	if s.readOnly {
+		return nil
+	}
 	if err := s.saveStateFile(); err != nil {
 		return errors.Wrap(err, "failed to save state")
 	}
@@ -110,35 +201,59 @@ func (s *storeImpl) Destroy(a asset.Asset) error {
 	return s.saveStateFile()
 }
 
-// DestroyState removes the state file from disk
-func (s *storeImpl) DestroyState() error {
-	s.stateFileAssets = nil
-	path := filepath.Join(s.directory, stateFileName)
-	err := os.Remove(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil
+// Invalidate removes the asset from the internal state, the state file and
+// disk, so that it is regenerated the next time it is fetched. Files of the
+// asset which are on disk but not in the state, such as user-provided ones,
+// are removed as well, since they would otherwise be loaded in place of the
+// regenerated asset.
+func (s *storeImpl) Invalidate(a asset.Asset) error {
+	if wa, ok := a.(asset.WritableAsset); ok {
+		found := true
+		if sa, ok := s.assets[reflect.TypeOf(a)]; ok && sa.source != unfetched {
+			reflect.ValueOf(a).Elem().Set(reflect.ValueOf(sa.asset).Elem())
+		} else if s.isAssetInState(a) {
+			if err := s.loadAssetFromState(a); err != nil {
+				return err
+			}
+		} else {
+			var err error
+			found, err = wa.Load(s.fileFetcher)
+			if err != nil {
+				return errors.Wrapf(err, "failed to load asset %q from disk", a.Name())
+			}
+		}
+		if found {
+			if err := asset.DeleteAssetFromDisk(wa, s.directory); err != nil {
+				return err
+			}
 		}
-		return err
 	}
-	return nil
+
+	delete(s.assets, reflect.TypeOf(a))
+	delete(s.stateFileAssets, reflect.TypeOf(a).String())
+	return s.saveStateFile()
+}
+
+// DestroyState removes the state file from its backend
+func (s *storeImpl) DestroyState() error {
+	s.stateFileAssets = nil
+	return errors.Wrapf(s.backend.Delete(), "failed to delete state file %q", s.backend)
 }
 
-// loadStateFile retrieves the state from the state file present in the given directory
+// loadStateFile retrieves the state from the state file in the backend
 // and returns the assets map
 func (s *storeImpl) loadStateFile() error {
-	path := filepath.Join(s.directory, stateFileName)
 	assets := map[string]json.RawMessage{}
-	data, err := os.ReadFile(path)
+	data, err := s.backend.Read()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil
-		}
-		return err
+		return errors.Wrapf(err, "failed to read state file %q", s.backend)
+	}
+	if data == nil {
+		return nil
 	}
 	err = json.Unmarshal(data, &assets)
 	if err != nil {
-		return errors.Wrapf(err, "failed to unmarshal state file %q", path)
+		return errors.Wrapf(err, "failed to unmarshal state file %q", s.backend)
 	}
 	s.stateFileAssets = assets
 	return nil
@@ -159,7 +274,7 @@ func (s *storeImpl) isAssetInState(a asset.Asset) bool {
 	return ok
 }
 
-// saveStateFile dumps the entire state map into a file
+// saveStateFile dumps the entire state map into the backend
 func (s *storeImpl) saveStateFile() error {
 	if s.stateFileAssets == nil {
 		s.stateFileAssets = map[string]json.RawMessage{}
@@ -179,20 +294,13 @@ func (s *storeImpl) saveStateFile() error {
 		return err
 	}
 
-	path := filepath.Join(s.directory, stateFileName)
-	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
-		return err
-	}
-	if err := os.WriteFile(path, data, 0o640); err != nil { //nolint:gosec // no sensitive info
-		return err
-	}
-	return nil
+	return errors.Wrapf(s.backend.Write(data), "failed to write state file %q", s.backend)
 }
 
 // fetch populates the given asset, generating it and its dependencies if
 // necessary, and returns whether or not the asset had to be regenerated and
 // any errors.
-func (s *storeImpl) fetch(a asset.Asset, indent string) error {
+func (s *storeImpl) fetch(ctx context.Context, a asset.Asset, indent string) error {
 	logrus.Debugf("%sFetching %s...", indent, a.Name())
 
 	assetState, ok := s.assets[reflect.TypeOf(a)]
@@ -217,13 +325,32 @@ func (s *storeImpl) fetch(a asset.Asset, indent string) error {
 	dependencies := a.Dependencies()
 	parents := make(asset.Parents, len(dependencies))
 	for _, d := range dependencies {
-		if err := s.fetch(d, increaseIndent(indent)); err != nil {
+		if err := s.fetch(ctx, d, increaseIndent(indent)); err != nil {
 			return errors.Wrapf(err, "failed to fetch dependency of %q", a.Name())
 		}
 		parents.Add(d)
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	logrus.Debugf("%sGenerating %s...", indent, a.Name())
-	if err := a.Generate(parents); err != nil {
+	if sa, ok := a.(asset.SourcedAsset); ok {
+		sa.SetSource(s.source.ForAsset(reflect.TypeOf(a).String()))
+	}
+	if ca, ok := a.(asset.ConfiguredAsset); ok {
+		ca.SetOptions(s.options)
+	}
+	ctx, span := tracing.Start(ctx, "Generate "+a.Name(), tracing.String("asset", reflect.TypeOf(a).String()))
+	defer span.End()
+	var err error
+	if ca, ok := a.(asset.ContextAsset); ok {
+		err = ca.GenerateWithContext(ctx, s.directory, parents)
+		s.contextAssetFailed = err != nil
+	} else {
+		err = a.Generate(parents)
+	}
+	if err != nil {
+		span.RecordError(err)
 		return errors.Wrapf(err, "failed to generate asset %q", a.Name())
 	}
 	assetState.asset = a
@@ -259,6 +386,9 @@ func (s *storeImpl) load(a asset.Asset, indent string) (*assetState, error) {
 	)
 	if _, isWritable := a.(asset.WritableAsset); isWritable {
 		onDiskAsset = reflect.New(reflect.TypeOf(a).Elem()).Interface().(asset.WritableAsset)
+		if ca, ok := onDiskAsset.(asset.ConfiguredAsset); ok {
+			ca.SetOptions(s.options)
+		}
 		var err error
 		foundOnDisk, err = onDiskAsset.Load(s.fileFetcher)
 		if err != nil {
@@ -281,6 +411,9 @@ func (s *storeImpl) load(a asset.Asset, indent string) (*assetState, error) {
 			if err := s.loadAssetFromState(stateFileAsset); err != nil {
 				return nil, errors.Wrapf(err, "failed to load asset %q from state file", a.Name())
 			}
+			if ca, ok := stateFileAsset.(asset.ConfiguredAsset); ok {
+				ca.SetOptions(s.options)
+			}
 		}
 
 		if foundOnDisk && foundInStateFile {
@@ -1,11 +1,13 @@
 package store
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"reflect"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/openshift/installer/pkg/asset"
@@ -131,6 +133,86 @@ func (a *testStoreAssetD) Load(asset.FileFetcher) (bool, error) {
 	return loadTestStoreAsset(a)
 }
 
+// testStoreContextAsset depends on a, and fails to generate while
+// provisionFails is set.
+type testStoreContextAsset struct{}
+
+var provisionFails bool
+
+func (a *testStoreContextAsset) Name() string {
+	return "provision"
+}
+
+func (a *testStoreContextAsset) Dependencies() []asset.Asset {
+	return []asset.Asset{&testStoreAssetA{}}
+}
+
+func (a *testStoreContextAsset) Generate(asset.Parents) error {
+	return errors.New("must be generated with context")
+}
+
+func (a *testStoreContextAsset) GenerateWithContext(context.Context, string, asset.Parents) error {
+	if provisionFails {
+		return errors.New("failed to provision")
+	}
+	return generateTestStoreAsset(a)
+}
+
+// testStoreConfiguredAsset records the options it is generated with.
+type testStoreConfiguredAsset struct {
+	options   asset.Options
+	Generated asset.Options
+}
+
+func (a *testStoreConfiguredAsset) Name() string {
+	return "configured"
+}
+
+func (a *testStoreConfiguredAsset) Dependencies() []asset.Asset {
+	return nil
+}
+
+func (a *testStoreConfiguredAsset) SetOptions(options asset.Options) {
+	a.options = options
+}
+
+func (a *testStoreConfiguredAsset) Generate(asset.Parents) error {
+	a.Generated = a.options
+	return nil
+}
+
+// testStoreConfiguredOnDiskAsset is on disk, and records the options it is
+// loaded with.
+type testStoreConfiguredOnDiskAsset struct {
+	options asset.Options
+	Loaded  asset.Options
+}
+
+func (a *testStoreConfiguredOnDiskAsset) Name() string {
+	return "configured on disk"
+}
+
+func (a *testStoreConfiguredOnDiskAsset) Dependencies() []asset.Asset {
+	return nil
+}
+
+func (a *testStoreConfiguredOnDiskAsset) SetOptions(options asset.Options) {
+	a.options = options
+}
+
+func (a *testStoreConfiguredOnDiskAsset) Generate(asset.Parents) error {
+	return errors.New("must be loaded")
+}
+
+func (a *testStoreConfiguredOnDiskAsset) Files() []*asset.File {
+	return nil
+}
+
+func (a *testStoreConfiguredOnDiskAsset) Load(asset.FileFetcher) (bool, error) {
+	a.Loaded = a.options
+	return true, nil
+}
+
 func newTestStoreAsset(name string) asset.Asset {
 	switch name {
 	case "a":
@@ -259,9 +341,11 @@ func TestStoreFetch(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			clearAssetBehaviors()
+			dir := t.TempDir()
 			store := &storeImpl{
-				directory: t.TempDir(),
+				directory: dir,
 				assets:    map[reflect.Type]*assetState{},
+				backend:   &fileBackend{path: filepath.Join(dir, stateFileName)},
 			}
 			assets := make(map[string]asset.Asset, len(tc.assets))
 			for name := range tc.assets {
@@ -281,7 +365,7 @@ func TestStoreFetch(t *testing.T) {
 					source: generatedSource,
 				}
 			}
-			err := store.Fetch(assets[tc.target])
+			err := store.Fetch(context.Background(), assets[tc.target])
 			assert.NoError(t, err, "error fetching asset")
 			assert.EqualValues(t, tc.expectedGenerationLog, generationLog)
 		})
@@ -395,7 +479,7 @@ func TestStoreFetchIdempotency(t *testing.T) {
 		}
 		assets := []asset.WritableAsset{&testStoreAssetA{}, &testStoreAssetB{}}
 		for _, a := range assets {
-			err = store.Fetch(a, assets...)
+			err = store.Fetch(context.Background(), a, assets...)
 			if !assert.NoError(t, err, "(loop %d) unexpected error fetching asset %q", a.Name()) {
 				t.Fatal()
 			}
@@ -420,6 +504,84 @@ func TestStoreFetchIdempotency(t *testing.T) {
 	assert.Equal(t, expectedFiles, actualFiles, "unexpected files on disk")
 }
 
+func TestStoreFetchResumesContextAsset(t *testing.T) {
+	clearAssetBehaviors()
+	defer func() { provisionFails = false }()
+
+	dir := t.TempDir()
+	backend := &memoryBackend{}
+
+	provisionFails = true
+	store, err := newStoreWithBackend(dir, backend)
+	if !assert.NoError(t, err) {
+		return
+	}
+	err = store.Fetch(context.Background(), &testStoreContextAsset{})
+	assert.EqualError(t, err, `failed to generate asset "provision": failed to provision`)
+	assert.Equal(t, []string{"a"}, generationLog)
+
+	// the re-run reuses the assets generated before the failure
+	provisionFails = false
+	store, err = newStoreWithBackend(dir, backend)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, store.Fetch(context.Background(), &testStoreContextAsset{}))
+	assert.Equal(t, []string{"a", "provision"}, generationLog)
+}
+
+func TestStoreFetchReadOnlyState(t *testing.T) {
+	clearAssetBehaviors()
+
+	dir := t.TempDir()
+	backend := &memoryBackend{}
+	dependencies[reflect.TypeOf(&testStoreAssetA{})] = []asset.Asset{&testStoreAssetB{}}
+	onDiskAssets[reflect.TypeOf(&testStoreAssetB{})] = true
+	if !assert.NoError(t, os.WriteFile(filepath.Join(dir, "b"), nil, 0o640)) {
+		return
+	}
+
+	store, err := newStoreWithBackend(dir, backend)
+	if !assert.NoError(t, err) {
+		return
+	}
+	WithReadOnlyState()(store)
+	assert.NoError(t, store.Fetch(context.Background(), &testStoreAssetA{}))
+	assert.Equal(t, []string{"a"}, generationLog)
+
+	// the consumed asset is left on disk and the state is not saved
+	assert.FileExists(t, filepath.Join(dir, "b"))
+	assert.Nil(t, backend.data)
+}
+
+func TestStoreFetchConfiguredAsset(t *testing.T) {
+	clearAssetBehaviors()
+
+	store, err := newStoreWithBackend(t.TempDir(), &memoryBackend{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	options := asset.Options{PolicyDir: "/policies"}
+	WithOptions(options)(store)
+	a := &testStoreConfiguredAsset{}
+	assert.NoError(t, store.Fetch(context.Background(), a))
+	assert.Equal(t, options, a.Generated)
+}
+
+func TestStoreLoadConfiguredAsset(t *testing.T) {
+	clearAssetBehaviors()
+
+	store, err := newStoreWithBackend(t.TempDir(), &memoryBackend{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	options := asset.Options{AllowUnknownFields: true}
+	WithOptions(options)(store)
+	a := &testStoreConfiguredOnDiskAsset{}
+	assert.NoError(t, store.Fetch(context.Background(), a))
+	assert.Equal(t, options, a.Loaded)
+}
+
 func TestStoreLoadOnDiskAssets(t *testing.T) {
 	cases := []struct {
 		name               string
@@ -474,3 +636,50 @@ func TestStoreLoadOnDiskAssets(t *testing.T) {
 		})
 	}
 }
+
+// TestStoreInvalidate tests that invalidated assets are regenerated from
+// the state file of a new store, while the other assets are not.
+func TestStoreInvalidate(t *testing.T) {
+	clearAssetBehaviors()
+	a, b := newTestStoreAsset("a"), newTestStoreAsset("b")
+	dependencies[reflect.TypeOf(a)] = []asset.Asset{b}
+
+	dir := t.TempDir()
+	backend := &memoryBackend{}
+	store, err := newStoreWithBackend(dir, backend)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Fetch(context.Background(), a))
+	assert.EqualValues(t, []string{"b", "a"}, generationLog)
+	assert.NoError(t, asset.PersistToFile(a.(asset.WritableAsset), dir))
+	assert.NoError(t, asset.PersistToFile(b.(asset.WritableAsset), dir))
+
+	assert.NoError(t, store.Invalidate(a))
+	assert.False(t, store.isAssetInState(a), "invalidated asset should not be in the state")
+	assert.True(t, store.isAssetInState(b), "other assets should stay in the state")
+	assert.NoFileExists(t, filepath.Join(dir, "a"), "invalidated asset should be removed from disk")
+	assert.FileExists(t, filepath.Join(dir, "b"), "other assets should stay on disk")
+
+	generationLog = []string{}
+	store, err = newStoreWithBackend(dir, backend)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Fetch(context.Background(), a))
+	assert.EqualValues(t, []string{"a"}, generationLog)
+}
+
+func TestStoreInvalidateOnDisk(t *testing.T) {
+	clearAssetBehaviors()
+	a := newTestStoreAsset("a")
+	onDiskAssets[reflect.TypeOf(a)] = true
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a"), []byte("user-provided"), 0o640))
+
+	store, err := newStoreWithBackend(dir, &memoryBackend{})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Invalidate(a))
+	assert.NoFileExists(t, filepath.Join(dir, "a"), "invalidated asset should be removed from disk")
+
+	onDiskAssets[reflect.TypeOf(a)] = false
+	assert.NoError(t, store.Fetch(context.Background(), a))
+	assert.EqualValues(t, []string{"a"}, generationLog)
+}
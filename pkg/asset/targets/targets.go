@@ -13,6 +13,7 @@ import (
 	"github.com/openshift/installer/pkg/asset/templates/content/bootkube"
 	"github.com/openshift/installer/pkg/asset/templates/content/openshift"
 	"github.com/openshift/installer/pkg/asset/tls"
+	"github.com/openshift/installer/pkg/asset/upi"
 )
 
 var (
@@ -45,22 +46,48 @@ var (
 	// IgnitionConfigs are the ignition-configs targeted assets.
 	IgnitionConfigs = []asset.WritableAsset{
 		&kubeconfig.AdminClient{},
-		&password.KubeadminPassword{},
+		&password.KubeadminPasswordFile{},
 		&machine.Master{},
 		&machine.Worker{},
 		&bootstrap.Bootstrap{},
 		&cluster.Metadata{},
+		&cluster.LoadBalancer{},
 	}
 
 	// SingleNodeIgnitionConfig is the bootstrap-in-place ignition-config targeted assets.
 	SingleNodeIgnitionConfig = []asset.WritableAsset{
 		&kubeconfig.AdminClient{},
-		&password.KubeadminPassword{},
+		&password.KubeadminPasswordFile{},
 		&machine.Worker{},
 		&bootstrap.SingleNodeBootstrapInPlace{},
 		&cluster.Metadata{},
 	}
 
+	// ExternalBootstrapIgnitionConfig are the external-bootstrap-ignition-config targeted assets.
+	ExternalBootstrapIgnitionConfig = []asset.WritableAsset{
+		&kubeconfig.AdminClient{},
+		&password.KubeadminPasswordFile{},
+		&machine.Master{},
+		&machine.Worker{},
+		&bootstrap.External{},
+		&cluster.Metadata{},
+	}
+
+	// RootCASigningRequest are the root-ca-signing-request targeted assets.
+	RootCASigningRequest = []asset.WritableAsset{
+		&tls.RootCASigningRequest{},
+	}
+
+	// InfraPlan are the infra-plan targeted assets.
+	InfraPlan = []asset.WritableAsset{
+		&cluster.InfraPlan{},
+	}
+
+	// UPITemplates are the upi-templates targeted assets.
+	UPITemplates = []asset.WritableAsset{
+		&upi.Templates{},
+	}
+
 	// Cluster are the cluster targeted assets.
 	Cluster = []asset.WritableAsset{
 		&cluster.Metadata{},
@@ -68,8 +95,9 @@ var (
 		&machine.WorkerIgnitionCustomizations{},
 		&cluster.TerraformVariables{},
 		&kubeconfig.AdminClient{},
-		&password.KubeadminPassword{},
+		&password.KubeadminPasswordFile{},
 		&tls.JournalCertKey{},
+		&cluster.LoadBalancer{},
 		&cluster.Cluster{},
 	}
 )
@@ -3,10 +3,25 @@ package tls
 import (
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/installer/pkg/asset"
 )
 
+// AdminKubeConfigValidityEnv is the environment variable setting how long
+// the client certificate of the admin kubeconfig is valid, e.g. 24h, for
+// clusters which are accessed through an identity provider once installed.
+// The default is ten years.
+const AdminKubeConfigValidityEnv = "OPENSHIFT_INSTALL_ADMIN_KUBECONFIG_VALIDITY"
+
+// minAdminKubeConfigValidity is the shortest validity accepted, which leaves
+// the installer time to wait for the cluster with the admin kubeconfig.
+const minAdminKubeConfigValidity = 2 * time.Hour
+
 // AdminKubeConfigSignerCertKey is a key/cert pair that signs the admin kubeconfig client certs.
 type AdminKubeConfigSignerCertKey struct {
 	SelfSignedCertKey
@@ -92,11 +107,19 @@ func (a *AdminKubeConfigClientCertKey) Generate(dependencies asset.Parents) erro
 	ca := &AdminKubeConfigSignerCertKey{}
 	dependencies.Get(ca)
 
+	validity, err := adminKubeConfigValidity()
+	if err != nil {
+		return err
+	}
+	if validity != ValidityTenYears {
+		logrus.Infof("The admin kubeconfig client certificate expires after %s", validity)
+	}
+
 	cfg := &CertCfg{
 		Subject:      pkix.Name{CommonName: "system:admin", Organization: []string{"system:masters"}},
 		KeyUsages:    x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
 		ExtKeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
-		Validity:     ValidityTenYears,
+		Validity:     validity,
 	}
 
 	return a.SignedCertKey.Generate(cfg, ca, "admin-kubeconfig-client", DoNotAppendParent)
@@ -111,3 +134,20 @@ func (a *AdminKubeConfigClientCertKey) Load(f asset.FileFetcher) (bool, error) {
 func (a *AdminKubeConfigClientCertKey) Name() string {
 	return "Certificate (admin-kubeconfig-client)"
 }
+
+// adminKubeConfigValidity returns the validity of the admin kubeconfig client
+// certificate set by OPENSHIFT_INSTALL_ADMIN_KUBECONFIG_VALIDITY.
+func adminKubeConfigValidity() (time.Duration, error) {
+	value := os.Getenv(AdminKubeConfigValidityEnv)
+	if value == "" {
+		return ValidityTenYears, nil
+	}
+	validity, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid %s", AdminKubeConfigValidityEnv)
+	}
+	if validity < minAdminKubeConfigValidity || validity > ValidityTenYears {
+		return 0, errors.Errorf("invalid %s: %s must be between two hours and ten years", AdminKubeConfigValidityEnv, value)
+	}
+	return validity, nil
+}
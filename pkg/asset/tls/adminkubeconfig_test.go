@@ -0,0 +1,49 @@
+package tls
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminKubeConfigValidity(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		err      string
+	}{
+		{
+			name:     "default",
+			expected: ValidityTenYears,
+		},
+		{
+			name:     "one day",
+			value:    "24h",
+			expected: ValidityOneDay,
+		},
+		{
+			name:  "too short",
+			value: "30m",
+			err:   "invalid OPENSHIFT_INSTALL_ADMIN_KUBECONFIG_VALIDITY: 30m must be between two hours and ten years",
+		},
+		{
+			name:  "not a duration",
+			value: "1d",
+			err:   `invalid OPENSHIFT_INSTALL_ADMIN_KUBECONFIG_VALIDITY: time: unknown unit "d" in duration "1d"`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(AdminKubeConfigValidityEnv, tc.value)
+			validity, err := adminKubeConfigValidity()
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, validity)
+		})
+	}
+}
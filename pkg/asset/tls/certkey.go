@@ -5,11 +5,13 @@ import (
 	"crypto/rsa"
 	"crypto/x509"
 	"os"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/deterministic"
 )
 
 // CertInterface contains cert.
@@ -30,6 +32,21 @@ type CertKey struct {
 	CertRaw  []byte
 	KeyRaw   []byte
 	FileList []*asset.File
+
+	source   *deterministic.Source
+	validity map[string]time.Duration
+}
+
+// SetSource sets the source the certificate draws its serial number and
+// validity period from.
+func (c *CertKey) SetSource(source *deterministic.Source) {
+	c.source = source
+}
+
+// SetOptions sets how long the certificates are valid instead of their
+// defaults, by the base name of their files in the tls directory.
+func (c *CertKey) SetOptions(options asset.Options) {
+	c.validity = options.CertificateValidity
 }
 
 // Cert returns the certificate.
@@ -143,7 +160,19 @@ func (c *SignedCertKey) Generate(
 		return errors.Wrap(err, "failed to parse x509 certificate")
 	}
 
-	key, crt, err = GenerateSignedCertificate(caKey, caCert, cfg)
+	// the certificate expires with its CA at the latest
+	certCfg := *cfg
+	maxValidity := caCert.NotAfter.Sub(c.source.Now())
+	if v, ok := c.validity[filenameBase]; ok {
+		if v > maxValidity {
+			return errors.Errorf("the %s certificate cannot be valid for %s, its CA %s expires on %s", filenameBase, v, caCert.Subject.CommonName, caCert.NotAfter.UTC().Format(time.RFC3339))
+		}
+		certCfg.Validity = v
+	} else if certCfg.Validity > maxValidity {
+		certCfg.Validity = maxValidity
+	}
+
+	key, crt, err = GenerateSignedCertificate(caKey, caCert, &certCfg, c.source)
 	if err != nil {
 		logrus.Debugf("Failed to generate signed cert/key pair: %s", err)
 		return errors.Wrap(err, "failed to generate signed cert/key pair")
@@ -171,7 +200,11 @@ func (c *SelfSignedCertKey) Generate(
 	cfg *CertCfg,
 	filenameBase string,
 ) error {
-	key, crt, err := GenerateSelfSignedCertificate(cfg)
+	certCfg := *cfg
+	if v, ok := c.validity[filenameBase]; ok {
+		certCfg.Validity = v
+	}
+	key, crt, err := GenerateSelfSignedCertificate(&certCfg, c.source)
 	if err != nil {
 		return errors.Wrap(err, "failed to generate self-signed cert/key pair")
 	}
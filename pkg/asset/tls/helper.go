@@ -19,7 +19,7 @@ func assetFilePath(filename string) string {
 }
 
 func apiAddress(cfg *types.InstallConfig) string {
-	return fmt.Sprintf("api.%s", cfg.ClusterDomain())
+	return cfg.APIDomainName()
 }
 
 func internalAPIAddress(cfg *types.InstallConfig) string {
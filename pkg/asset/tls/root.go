@@ -3,6 +3,11 @@ package tls
 import (
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/installer/pkg/asset"
 )
@@ -32,7 +37,64 @@ func (c *RootCA) Generate(parents asset.Parents) error {
 	return c.SelfSignedCertKey.Generate(cfg, "root-ca")
 }
 
+// Load reads the root CA from disk: the certificate an external CA issued for
+// the signing request of RootCASigningRequest, followed by the certificates
+// of its chain, and the private key of the request. Without the certificate
+// or a pending signing request, the root CA is self-signed.
+func (c *RootCA) Load(f asset.FileFetcher) (bool, error) {
+	cert, err := f.FetchByName(assetFilePath(rootCAFilenameBase + ".crt"))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return false, err
+		}
+		// a pending signing request is not silently replaced with a
+		// self-signed root CA
+		if _, err := f.FetchByName(RootCASigningRequestFile); err == nil {
+			return false, errors.Errorf("%s is not signed yet, supply the certificate the CA issued for it in %s", RootCASigningRequestFile, assetFilePath(rootCAFilenameBase+".crt"))
+		}
+		return false, nil
+	}
+	key, err := f.FetchByName(assetFilePath(rootCAFilenameBase + ".key"))
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to load the private key of %s", cert.Filename)
+	}
+	if err := validateCA(cert.Data, key.Data); err != nil {
+		return false, errors.Wrapf(err, "invalid %s", cert.Filename)
+	}
+	logrus.Infof("Using the root CA of %s", cert.Filename)
+
+	c.CertRaw, c.KeyRaw = cert.Data, key.Data
+	c.FileList = []*asset.File{key, cert}
+	return true, nil
+}
+
 // Name returns the human-friendly name of the asset.
 func (c *RootCA) Name() string {
 	return "Root CA"
 }
+
+// validateCA checks that the certificate is that of a valid CA with the
+// private key.
+func validateCA(certPEM, keyPEM []byte) error {
+	cert, err := PemToCertificate(certPEM)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse the certificate")
+	}
+	key, err := PemToPrivateKey(keyPEM)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse the RSA private key")
+	}
+	if !key.PublicKey.Equal(cert.PublicKey) {
+		return errors.New("the certificate is not that of the private key")
+	}
+	if !cert.BasicConstraintsValid || !cert.IsCA {
+		return errors.New("the certificate is not that of a CA")
+	}
+	if cert.KeyUsage != 0 && cert.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return errors.New("the certificate does not allow signing certificates")
+	}
+	if now := time.Now(); now.After(cert.NotAfter) || now.Before(cert.NotBefore) {
+		return errors.Errorf("the certificate is valid from %s to %s", cert.NotBefore.UTC().Format(time.RFC3339), cert.NotAfter.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
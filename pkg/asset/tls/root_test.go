@@ -0,0 +1,165 @@
+package tls
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+// fileFetcher fetches the files of a map.
+type fileFetcher map[string][]byte
+
+func (f fileFetcher) FetchByName(name string) (*asset.File, error) {
+	data, ok := f[name]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return &asset.File{Filename: name, Data: data}, nil
+}
+
+func (f fileFetcher) FetchByPattern(pattern string) ([]*asset.File, error) {
+	return nil, nil
+}
+
+func TestRootCALoad(t *testing.T) {
+	external := &SelfSignedCertKey{}
+	err := external.Generate(&CertCfg{
+		Subject:   pkix.Name{CommonName: "external-ca", OrganizationalUnit: []string{"test"}},
+		KeyUsages: x509.KeyUsageCertSign,
+		Validity:  ValidityOneYear,
+		IsCA:      true,
+	}, "external-ca")
+	if !assert.NoError(t, err) {
+		return
+	}
+	externalCert, _ := PemToCertificate(external.Cert())
+	externalKey, _ := PemToPrivateKey(external.Key())
+
+	request := &RootCASigningRequest{}
+	if !assert.NoError(t, request.Generate(nil)) {
+		return
+	}
+	block, _ := pem.Decode(request.CSRRaw)
+	if !assert.NotNil(t, block) {
+		return
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "root-ca", csr.Subject.CommonName)
+	key, _ := PemToPrivateKey(request.KeyRaw)
+	signed := func(isCA bool) []byte {
+		cert, err := SignedCertificate(&CertCfg{KeyUsages: x509.KeyUsageCertSign, Validity: ValidityOneDay, IsCA: isCA}, csr, key, externalCert, externalKey, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return append(CertToPem(cert), external.Cert()...)
+	}
+
+	cases := []struct {
+		name          string
+		files         fileFetcher
+		expectedFound bool
+		expectedError string
+	}{
+		{
+			name: "self-signed",
+		},
+		{
+			name:          "externally signed",
+			files:         fileFetcher{"tls/root-ca.key": request.KeyRaw, "tls/root-ca.csr": request.CSRRaw, "tls/root-ca.crt": signed(true)},
+			expectedFound: true,
+		},
+		{
+			name:          "pending signing request",
+			files:         fileFetcher{"tls/root-ca.key": request.KeyRaw, "tls/root-ca.csr": request.CSRRaw},
+			expectedError: `^tls/root-ca\.csr is not signed yet, supply the certificate the CA issued for it in tls/root-ca\.crt$`,
+		},
+		{
+			name:          "not a CA",
+			files:         fileFetcher{"tls/root-ca.key": request.KeyRaw, "tls/root-ca.crt": signed(false)},
+			expectedError: `^invalid tls/root-ca\.crt: the certificate is not that of a CA$`,
+		},
+		{
+			name:          "other key",
+			files:         fileFetcher{"tls/root-ca.key": external.Key(), "tls/root-ca.crt": signed(true)},
+			expectedError: `^invalid tls/root-ca\.crt: the certificate is not that of the private key$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rootCA := &RootCA{}
+			found, err := rootCA.Load(tc.files)
+			if tc.expectedError != "" {
+				assert.Regexp(t, tc.expectedError, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.expectedFound, found)
+			if !found {
+				return
+			}
+
+			// the certificates the root CA signs chain to the external CA
+			certKey := &SignedCertKey{}
+			if !assert.NoError(t, certKey.Generate(&CertCfg{
+				Subject:      pkix.Name{CommonName: "test", OrganizationalUnit: []string{"openshift"}},
+				ExtKeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+				Validity:     ValidityTenYears,
+			}, rootCA, "test", DoNotAppendParent)) {
+				return
+			}
+			cert, _ := PemToCertificate(certKey.Cert())
+			rootCACert, _ := PemToCertificate(rootCA.Cert())
+			assert.Equal(t, rootCACert.NotAfter, cert.NotAfter, "the certificate outlives its CA")
+			roots, intermediates := x509.NewCertPool(), x509.NewCertPool()
+			roots.AddCert(externalCert)
+			intermediates.AddCert(rootCACert)
+			_, err = cert.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCertificateValidity(t *testing.T) {
+	options := asset.Options{CertificateValidity: map[string]time.Duration{"test-ca": 48 * time.Hour, "test": 24 * time.Hour}}
+	ca := &SelfSignedCertKey{}
+	ca.SetOptions(options)
+	if !assert.NoError(t, ca.Generate(&CertCfg{
+		Subject:   pkix.Name{CommonName: "test-ca", OrganizationalUnit: []string{"openshift"}},
+		KeyUsages: x509.KeyUsageCertSign,
+		Validity:  ValidityTenYears,
+		IsCA:      true,
+	}, "test-ca")) {
+		return
+	}
+	caCert, _ := PemToCertificate(ca.Cert())
+	assert.Equal(t, 48*time.Hour, caCert.NotAfter.Sub(caCert.NotBefore))
+
+	cfg := &CertCfg{
+		Subject:  pkix.Name{CommonName: "test", OrganizationalUnit: []string{"openshift"}},
+		Validity: ValidityOneYear,
+	}
+	certKey := &SignedCertKey{}
+	certKey.SetOptions(options)
+	if !assert.NoError(t, certKey.Generate(cfg, ca, "test", DoNotAppendParent)) {
+		return
+	}
+	cert, _ := PemToCertificate(certKey.Cert())
+	assert.InDelta(t, float64(24*time.Hour), float64(cert.NotAfter.Sub(time.Now())), float64(time.Minute))
+	assert.Equal(t, ValidityOneYear, cfg.Validity, "the configuration was modified")
+
+	certKey.SetOptions(asset.Options{CertificateValidity: map[string]time.Duration{"test": 72 * time.Hour}})
+	err := certKey.Generate(cfg, ca, "test", DoNotAppendParent)
+	assert.Regexp(t, `^the test certificate cannot be valid for 72h0m0s, its CA test-ca expires on .*$`, err)
+}
@@ -0,0 +1,118 @@
+package tls
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+const (
+	rootCAFilenameBase = "root-ca"
+
+	// RootCASigningRequestFile is the file of the certificate signing request
+	// of the root CA, which is submitted to an external signer.
+	RootCASigningRequestFile = tlsDir + "/" + rootCAFilenameBase + ".csr"
+)
+
+// oidBasicConstraints is the object identifier of the basic constraints
+// extension.
+var oidBasicConstraints = asn1.ObjectIdentifier{2, 5, 29, 19}
+
+// RootCASigningRequest is the private key of the root CA and the request to
+// sign its certificate, for the root CA to be issued by an external CA
+// instead of being self-signed. The certificate the external CA issues is
+// supplied in tls/root-ca.crt, along with tls/root-ca.key, to the commands
+// generating the other assets.
+type RootCASigningRequest struct {
+	KeyRaw   []byte
+	CSRRaw   []byte
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*RootCASigningRequest)(nil)
+
+// Dependencies returns the dependency of the signing request, which is
+// empty.
+func (r *RootCASigningRequest) Dependencies() []asset.Asset {
+	return []asset.Asset{}
+}
+
+// Generate generates the private key of the root CA and the request to sign
+// its certificate as a CA.
+func (r *RootCASigningRequest) Generate(parents asset.Parents) error {
+	key, err := PrivateKey()
+	if err != nil {
+		return errors.Wrap(err, "failed to generate private key")
+	}
+	// the issued certificate must be a CA
+	basicConstraints, err := asn1.Marshal(struct {
+		IsCA bool `asn1:"optional"`
+	}{IsCA: true})
+	if err != nil {
+		return err
+	}
+	template := &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: rootCAFilenameBase, OrganizationalUnit: []string{"openshift"}},
+		ExtraExtensions: []pkix.Extension{{
+			Id:       oidBasicConstraints,
+			Critical: true,
+			Value:    basicConstraints,
+		}},
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the certificate signing request")
+	}
+	csr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		return err
+	}
+
+	r.KeyRaw = PrivateKeyToPem(key)
+	r.CSRRaw = CSRToPem(csr)
+	r.FileList = []*asset.File{
+		{
+			Filename: assetFilePath(rootCAFilenameBase + ".key"),
+			Data:     r.KeyRaw,
+		},
+		{
+			Filename: RootCASigningRequestFile,
+			Data:     r.CSRRaw,
+		},
+	}
+	return nil
+}
+
+// Name returns the human-friendly name of the asset.
+func (r *RootCASigningRequest) Name() string {
+	return "Root CA Signing Request"
+}
+
+// Files returns the files generated by the asset.
+func (r *RootCASigningRequest) Files() []*asset.File {
+	return r.FileList
+}
+
+// Load reads the private key and the signing request from disk.
+func (r *RootCASigningRequest) Load(f asset.FileFetcher) (bool, error) {
+	csr, err := f.FetchByName(RootCASigningRequestFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	key, err := f.FetchByName(assetFilePath(rootCAFilenameBase + ".key"))
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to load the private key of %s", RootCASigningRequestFile)
+	}
+	r.KeyRaw, r.CSRRaw = key.Data, csr.Data
+	r.FileList = []*asset.File{key, csr}
+	return true, nil
+}
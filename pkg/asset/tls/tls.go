@@ -17,6 +17,8 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/deterministic"
 )
 
 const (
@@ -59,9 +61,10 @@ func PrivateKey() (*rsa.PrivateKey, error) {
 	return rsaKey, nil
 }
 
-// SelfSignedCertificate creates a self signed certificate
-func SelfSignedCertificate(cfg *CertCfg, key *rsa.PrivateKey) (*x509.Certificate, error) {
-	serial, err := rand.Int(rand.Reader, new(big.Int).SetInt64(math.MaxInt64))
+// SelfSignedCertificate creates a self signed certificate, drawing its serial
+// number and validity period from the source.
+func SelfSignedCertificate(cfg *CertCfg, key *rsa.PrivateKey, source *deterministic.Source) (*x509.Certificate, error) {
+	serial, err := rand.Int(source.Reader(), new(big.Int).SetInt64(math.MaxInt64))
 	if err != nil {
 		return nil, err
 	}
@@ -69,8 +72,8 @@ func SelfSignedCertificate(cfg *CertCfg, key *rsa.PrivateKey) (*x509.Certificate
 		BasicConstraintsValid: true,
 		IsCA:                  cfg.IsCA,
 		KeyUsage:              cfg.KeyUsages,
-		NotAfter:              time.Now().Add(cfg.Validity),
-		NotBefore:             time.Now(),
+		NotAfter:              source.Now().Add(cfg.Validity),
+		NotBefore:             source.Now(),
 		SerialNumber:          serial,
 		Subject:               cfg.Subject,
 	}
@@ -90,15 +93,17 @@ func SelfSignedCertificate(cfg *CertCfg, key *rsa.PrivateKey) (*x509.Certificate
 	return x509.ParseCertificate(certBytes)
 }
 
-// SignedCertificate creates a new X.509 certificate based on a template.
+// SignedCertificate creates a new X.509 certificate based on a template,
+// drawing its serial number and validity period from the source.
 func SignedCertificate(
 	cfg *CertCfg,
 	csr *x509.CertificateRequest,
 	key *rsa.PrivateKey,
 	caCert *x509.Certificate,
 	caKey *rsa.PrivateKey,
+	source *deterministic.Source,
 ) (*x509.Certificate, error) {
-	serial, err := rand.Int(rand.Reader, new(big.Int).SetInt64(math.MaxInt64))
+	serial, err := rand.Int(source.Reader(), new(big.Int).SetInt64(math.MaxInt64))
 	if err != nil {
 		return nil, err
 	}
@@ -108,7 +113,7 @@ func SignedCertificate(
 		ExtKeyUsage:           cfg.ExtKeyUsages,
 		IPAddresses:           csr.IPAddresses,
 		KeyUsage:              cfg.KeyUsages,
-		NotAfter:              time.Now().Add(cfg.Validity),
+		NotAfter:              source.Now().Add(cfg.Validity),
 		NotBefore:             caCert.NotBefore,
 		SerialNumber:          serial,
 		Subject:               csr.Subject,
@@ -151,7 +156,7 @@ func generateSubjectKeyID(pub crypto.PublicKey) ([]byte, error) {
 
 // GenerateSignedCertificate generate a key and cert defined by CertCfg and signed by CA.
 func GenerateSignedCertificate(caKey *rsa.PrivateKey, caCert *x509.Certificate,
-	cfg *CertCfg) (*rsa.PrivateKey, *x509.Certificate, error) {
+	cfg *CertCfg, source *deterministic.Source) (*rsa.PrivateKey, *x509.Certificate, error) {
 
 	// create a private key
 	key, err := PrivateKey()
@@ -173,7 +178,7 @@ func GenerateSignedCertificate(caKey *rsa.PrivateKey, caCert *x509.Certificate,
 	}
 
 	// create a cert
-	cert, err := SignedCertificate(cfg, csr, key, caCert, caKey)
+	cert, err := SignedCertificate(cfg, csr, key, caCert, caKey, source)
 	if err != nil {
 		logrus.Debugf("Failed to create a signed certificate: %s", err)
 		return nil, nil, errors.Wrap(err, "failed to create a signed certificate")
@@ -182,14 +187,14 @@ func GenerateSignedCertificate(caKey *rsa.PrivateKey, caCert *x509.Certificate,
 }
 
 // GenerateSelfSignedCertificate generates a key/cert pair defined by CertCfg.
-func GenerateSelfSignedCertificate(cfg *CertCfg) (*rsa.PrivateKey, *x509.Certificate, error) {
+func GenerateSelfSignedCertificate(cfg *CertCfg, source *deterministic.Source) (*rsa.PrivateKey, *x509.Certificate, error) {
 	key, err := PrivateKey()
 	if err != nil {
 		logrus.Debugf("Failed to generate a private key: %s", err)
 		return nil, nil, errors.Wrap(err, "failed to generate private key")
 	}
 
-	crt, err := SelfSignedCertificate(cfg, key)
+	crt, err := SelfSignedCertificate(cfg, key, source)
 	if err != nil {
 		logrus.Debugf("Failed to create self-signed certificate: %s", err)
 		return nil, nil, errors.Wrap(err, "failed to create self-signed certificate")
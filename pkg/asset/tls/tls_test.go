@@ -50,7 +50,7 @@ func TestSelfSignedCertificate(t *testing.T) {
 		},
 	}
 	for i, c := range cases {
-		if _, err := SelfSignedCertificate(c.cfg, key); (err != nil) != c.err {
+		if _, err := SelfSignedCertificate(c.cfg, key, nil); (err != nil) != c.err {
 			no := "no"
 			if c.err {
 				no = "an"
@@ -0,0 +1,366 @@
+package upi
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// awsTemplateFileName is the CloudFormation template of the cluster.
+const awsTemplateFileName = "upi/aws/cluster.yaml"
+
+// awsInstance is an EC2 instance of the template.
+type awsInstance struct {
+	logicalID    string
+	name         string
+	role         string
+	config       *machinev1beta1.AWSMachineProviderConfig
+	userData     []byte
+	securityRole string
+}
+
+// awsTemplates renders the CloudFormation template of the VPC, unless the
+// install-config sets its subnets, of the security groups and of the
+// instances of the cluster.
+func awsTemplates(in *templateInputs) ([]*asset.File, error) {
+	ic := in.installConfig
+	network, err := machineNetwork(ic)
+	if err != nil {
+		return nil, err
+	}
+	byoSubnets := len(ic.Platform.AWS.Subnets) > 0
+	external := ic.Publish == types.ExternalPublishingStrategy
+
+	var instances []awsInstance
+	for i, m := range in.masters {
+		config, ok := m.Spec.ProviderSpec.Value.Object.(*machinev1beta1.AWSMachineProviderConfig)
+		if !ok {
+			return nil, errors.Errorf("unexpected provider spec of the machine %s", m.Name)
+		}
+		instances = append(instances, awsInstance{logicalID: fmt.Sprintf("Master%d", i), name: m.Name, role: "master", config: config, userData: in.masterIgnition, securityRole: "Master"})
+	}
+	for _, ms := range in.workers {
+		config, ok := ms.Spec.Template.Spec.ProviderSpec.Value.Object.(*machinev1beta1.AWSMachineProviderConfig)
+		if !ok {
+			return nil, errors.Errorf("unexpected provider spec of the MachineSet %s", ms.Name)
+		}
+		for i := 0; i < replicas(ms); i++ {
+			instances = append(instances, awsInstance{logicalID: fmt.Sprintf("Worker%d", len(instances)-len(in.masters)), name: workerName(ms, i), role: "worker", config: config, userData: in.workerIgnition, securityRole: "Worker"})
+		}
+	}
+	if len(instances) == 0 {
+		return nil, errors.New("the cluster has no machines")
+	}
+
+	clusterTag := map[string]interface{}{"Key": fmt.Sprintf("kubernetes.io/cluster/%s", in.infraID), "Value": "owned"}
+	tags := func(name string) []interface{} {
+		return []interface{}{map[string]interface{}{"Key": "Name", "Value": name}, clusterTag}
+	}
+
+	parameters := map[string]interface{}{
+		"RhcosAmi": map[string]interface{}{
+			"Type":        "AWS::EC2::Image::Id",
+			"Default":     strings.SplitN(in.image, ",", 2)[0],
+			"Description": "The RHCOS AMI of the instances.",
+		},
+		"BootstrapIgnitionLocation": map[string]interface{}{
+			"Type":        "String",
+			"Default":     fmt.Sprintf("s3://%s-bootstrap/bootstrap.ign", in.infraID),
+			"Description": "The location to upload bootstrap.ign to, which the bootstrap instance fetches it from.",
+		},
+	}
+	resources := map[string]interface{}{}
+	outputs := map[string]interface{}{}
+
+	// subnetOf returns the subnet of an instance: the one of the provider
+	// spec with existing subnets, and otherwise the subnet of its zone.
+	var subnetOf func(config *machinev1beta1.AWSMachineProviderConfig, public bool) (interface{}, error)
+	var vpc interface{}
+	if byoSubnets {
+		parameters["VpcId"] = map[string]interface{}{
+			"Type":        "AWS::EC2::VPC::Id",
+			"Description": "The VPC of the subnets of the install-config.",
+		}
+		vpc = map[string]interface{}{"Ref": "VpcId"}
+		subnetOf = func(config *machinev1beta1.AWSMachineProviderConfig, public bool) (interface{}, error) {
+			if config.Subnet.ID == nil {
+				return nil, errors.Errorf("the instance in %s has no subnet ID", config.Placement.AvailabilityZone)
+			}
+			return *config.Subnet.ID, nil
+		}
+	} else {
+		zones := awsZones(instances)
+		zoneIndex := map[string]int{}
+		for i, zone := range zones {
+			zoneIndex[zone] = i
+		}
+		if err := addAWSNetwork(resources, outputs, network, zones, external, tags); err != nil {
+			return nil, err
+		}
+		vpc = map[string]interface{}{"Ref": "VPC"}
+		subnetOf = func(config *machinev1beta1.AWSMachineProviderConfig, public bool) (interface{}, error) {
+			kind := "Private"
+			if public {
+				kind = "Public"
+			}
+			return map[string]interface{}{"Ref": fmt.Sprintf("%sSubnet%d", kind, zoneIndex[config.Placement.AvailabilityZone])}, nil
+		}
+	}
+
+	apiSource := "0.0.0.0/0"
+	if !external {
+		apiSource = network.String()
+	}
+	resources["MasterSecurityGroup"] = map[string]interface{}{
+		"Type": "AWS::EC2::SecurityGroup",
+		"Properties": map[string]interface{}{
+			"GroupDescription": "Cluster master security group",
+			"VpcId":            vpc,
+			"SecurityGroupIngress": []interface{}{
+				awsIngress("-1", 0, 0, network.String()),
+				awsIngress("tcp", 6443, 6443, apiSource),
+			},
+			"Tags": tags(fmt.Sprintf("%s-master-sg", in.infraID)),
+		},
+	}
+	resources["WorkerSecurityGroup"] = map[string]interface{}{
+		"Type": "AWS::EC2::SecurityGroup",
+		"Properties": map[string]interface{}{
+			"GroupDescription": "Cluster worker security group",
+			"VpcId":            vpc,
+			"SecurityGroupIngress": []interface{}{
+				awsIngress("-1", 0, 0, network.String()),
+				awsIngress("tcp", 80, 80, apiSource),
+				awsIngress("tcp", 443, 443, apiSource),
+			},
+			"Tags": tags(fmt.Sprintf("%s-worker-sg", in.infraID)),
+		},
+	}
+
+	bootstrapSubnet, err := subnetOf(instances[0].config, external)
+	if err != nil {
+		return nil, err
+	}
+	resources["BootstrapInstance"] = map[string]interface{}{
+		"Type": "AWS::EC2::Instance",
+		"Properties": map[string]interface{}{
+			"ImageId":          map[string]interface{}{"Ref": "RhcosAmi"},
+			"InstanceType":     instances[0].config.InstanceType,
+			"SubnetId":         bootstrapSubnet,
+			"SecurityGroupIds": []interface{}{map[string]interface{}{"Ref": "MasterSecurityGroup"}},
+			"UserData": map[string]interface{}{
+				"Fn::Base64": map[string]interface{}{"Fn::Sub": bootstrapPointer("${BootstrapIgnitionLocation}")},
+			},
+			"Tags": tags(fmt.Sprintf("%s-bootstrap", in.infraID)),
+		},
+	}
+	for _, instance := range instances {
+		subnet, err := subnetOf(instance.config, false)
+		if err != nil {
+			return nil, err
+		}
+		properties := map[string]interface{}{
+			"ImageId":          map[string]interface{}{"Ref": "RhcosAmi"},
+			"InstanceType":     instance.config.InstanceType,
+			"SubnetId":         subnet,
+			"SecurityGroupIds": []interface{}{map[string]interface{}{"Ref": instance.securityRole + "SecurityGroup"}},
+			"UserData":         base64.StdEncoding.EncodeToString(instance.userData),
+			"Tags":             tags(instance.name),
+		}
+		if len(instance.config.BlockDevices) > 0 && instance.config.BlockDevices[0].EBS != nil {
+			ebs := map[string]interface{}{}
+			if size := instance.config.BlockDevices[0].EBS.VolumeSize; size != nil {
+				ebs["VolumeSize"] = *size
+			}
+			if volumeType := instance.config.BlockDevices[0].EBS.VolumeType; volumeType != nil {
+				ebs["VolumeType"] = *volumeType
+			}
+			properties["BlockDeviceMappings"] = []interface{}{map[string]interface{}{"DeviceName": "/dev/xvda", "Ebs": ebs}}
+		}
+		resources[instance.logicalID] = map[string]interface{}{
+			"Type":       "AWS::EC2::Instance",
+			"Properties": properties,
+		}
+		outputs[instance.logicalID+"PrivateIp"] = map[string]interface{}{
+			"Description": fmt.Sprintf("The private IP of %s, a %s.", instance.name, instance.role),
+			"Value":       map[string]interface{}{"Fn::GetAtt": []interface{}{instance.logicalID, "PrivateIp"}},
+		}
+	}
+
+	template := map[string]interface{}{
+		"AWSTemplateFormatVersion": "2010-09-09",
+		"Description":              fmt.Sprintf("The network, security groups and instances of the cluster %s. The load balancers and the DNS records of the API and of the ingress are left to create.", in.infraID),
+		"Parameters":               parameters,
+		"Resources":                resources,
+		"Outputs":                  outputs,
+	}
+	data, err := yaml.Marshal(template)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the CloudFormation template")
+	}
+	return []*asset.File{{Filename: awsTemplateFileName, Data: data}}, nil
+}
+
+// awsZones returns the sorted zones of the instances.
+func awsZones(instances []awsInstance) []string {
+	seen := map[string]bool{}
+	var zones []string
+	for _, instance := range instances {
+		zone := instance.config.Placement.AvailabilityZone
+		if !seen[zone] {
+			seen[zone] = true
+			zones = append(zones, zone)
+		}
+	}
+	sort.Strings(zones)
+	return zones
+}
+
+// addAWSNetwork adds the VPC of the machine network to the resources, with a
+// public and a private subnet in each zone, as the installer creates it: the
+// first half of the network is split between the public subnets and the
+// second one between the private subnets, which reach the internet through
+// a NAT gateway in the public subnet of their zone.
+func addAWSNetwork(resources, outputs map[string]interface{}, network *net.IPNet, zones []string, external bool, tags func(string) []interface{}) error {
+	halves, err := subnets(network, 2)
+	if err != nil {
+		return err
+	}
+	publicCIDRs, err := subnets(halves[0], len(zones))
+	if err != nil {
+		return err
+	}
+	privateCIDRs, err := subnets(halves[1], len(zones))
+	if err != nil {
+		return err
+	}
+
+	vpc := map[string]interface{}{"Ref": "VPC"}
+	resources["VPC"] = map[string]interface{}{
+		"Type": "AWS::EC2::VPC",
+		"Properties": map[string]interface{}{
+			"CidrBlock":          network.String(),
+			"EnableDnsSupport":   true,
+			"EnableDnsHostnames": true,
+		},
+	}
+	resources["InternetGateway"] = map[string]interface{}{"Type": "AWS::EC2::InternetGateway"}
+	resources["GatewayToInternet"] = map[string]interface{}{
+		"Type": "AWS::EC2::VPCGatewayAttachment",
+		"Properties": map[string]interface{}{
+			"VpcId":             vpc,
+			"InternetGatewayId": map[string]interface{}{"Ref": "InternetGateway"},
+		},
+	}
+	resources["PublicRouteTable"] = map[string]interface{}{
+		"Type":       "AWS::EC2::RouteTable",
+		"Properties": map[string]interface{}{"VpcId": vpc},
+	}
+	resources["PublicRoute"] = map[string]interface{}{
+		"Type":      "AWS::EC2::Route",
+		"DependsOn": "GatewayToInternet",
+		"Properties": map[string]interface{}{
+			"RouteTableId":         map[string]interface{}{"Ref": "PublicRouteTable"},
+			"DestinationCidrBlock": "0.0.0.0/0",
+			"GatewayId":            map[string]interface{}{"Ref": "InternetGateway"},
+		},
+	}
+
+	var publicSubnets, privateSubnets []interface{}
+	for i, zone := range zones {
+		public := fmt.Sprintf("PublicSubnet%d", i)
+		private := fmt.Sprintf("PrivateSubnet%d", i)
+		resources[public] = map[string]interface{}{
+			"Type": "AWS::EC2::Subnet",
+			"Properties": map[string]interface{}{
+				"VpcId":               vpc,
+				"CidrBlock":           publicCIDRs[i].String(),
+				"AvailabilityZone":    zone,
+				"MapPublicIpOnLaunch": external,
+				"Tags":                tags(fmt.Sprintf("public-%s", zone)),
+			},
+		}
+		resources[fmt.Sprintf("PublicSubnetRouteTableAssociation%d", i)] = map[string]interface{}{
+			"Type": "AWS::EC2::SubnetRouteTableAssociation",
+			"Properties": map[string]interface{}{
+				"SubnetId":     map[string]interface{}{"Ref": public},
+				"RouteTableId": map[string]interface{}{"Ref": "PublicRouteTable"},
+			},
+		}
+		resources[fmt.Sprintf("EIP%d", i)] = map[string]interface{}{
+			"Type":       "AWS::EC2::EIP",
+			"DependsOn":  "GatewayToInternet",
+			"Properties": map[string]interface{}{"Domain": "vpc"},
+		}
+		resources[fmt.Sprintf("NatGateway%d", i)] = map[string]interface{}{
+			"Type": "AWS::EC2::NatGateway",
+			"Properties": map[string]interface{}{
+				"AllocationId": map[string]interface{}{"Fn::GetAtt": []interface{}{fmt.Sprintf("EIP%d", i), "AllocationId"}},
+				"SubnetId":     map[string]interface{}{"Ref": public},
+			},
+		}
+		resources[private] = map[string]interface{}{
+			"Type": "AWS::EC2::Subnet",
+			"Properties": map[string]interface{}{
+				"VpcId":            vpc,
+				"CidrBlock":        privateCIDRs[i].String(),
+				"AvailabilityZone": zone,
+				"Tags":             tags(fmt.Sprintf("private-%s", zone)),
+			},
+		}
+		resources[fmt.Sprintf("PrivateRouteTable%d", i)] = map[string]interface{}{
+			"Type":       "AWS::EC2::RouteTable",
+			"Properties": map[string]interface{}{"VpcId": vpc},
+		}
+		resources[fmt.Sprintf("PrivateRoute%d", i)] = map[string]interface{}{
+			"Type": "AWS::EC2::Route",
+			"Properties": map[string]interface{}{
+				"RouteTableId":         map[string]interface{}{"Ref": fmt.Sprintf("PrivateRouteTable%d", i)},
+				"DestinationCidrBlock": "0.0.0.0/0",
+				"NatGatewayId":         map[string]interface{}{"Ref": fmt.Sprintf("NatGateway%d", i)},
+			},
+		}
+		resources[fmt.Sprintf("PrivateSubnetRouteTableAssociation%d", i)] = map[string]interface{}{
+			"Type": "AWS::EC2::SubnetRouteTableAssociation",
+			"Properties": map[string]interface{}{
+				"SubnetId":     map[string]interface{}{"Ref": private},
+				"RouteTableId": map[string]interface{}{"Ref": fmt.Sprintf("PrivateRouteTable%d", i)},
+			},
+		}
+		publicSubnets = append(publicSubnets, map[string]interface{}{"Ref": public})
+		privateSubnets = append(privateSubnets, map[string]interface{}{"Ref": private})
+	}
+
+	outputs["VpcId"] = map[string]interface{}{"Description": "The ID of the VPC.", "Value": vpc}
+	outputs["PublicSubnetIds"] = map[string]interface{}{
+		"Description": "The IDs of the public subnets.",
+		"Value":       map[string]interface{}{"Fn::Join": []interface{}{",", publicSubnets}},
+	}
+	outputs["PrivateSubnetIds"] = map[string]interface{}{
+		"Description": "The IDs of the private subnets.",
+		"Value":       map[string]interface{}{"Fn::Join": []interface{}{",", privateSubnets}},
+	}
+	return nil
+}
+
+// awsIngress returns an ingress rule of a security group.
+func awsIngress(protocol string, fromPort, toPort int, source string) map[string]interface{} {
+	rule := map[string]interface{}{
+		"IpProtocol": protocol,
+		"CidrIp":     source,
+	}
+	if protocol != "-1" {
+		rule["FromPort"] = fromPort
+		rule["ToPort"] = toPort
+	}
+	return rule
+}
@@ -0,0 +1,255 @@
+package upi
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/apparentlymart/go-cidr/cidr"
+	"github.com/pkg/errors"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/types"
+)
+
+const (
+	// azureTemplateFileName is the ARM template of the cluster.
+	azureTemplateFileName = "upi/azure/cluster.json"
+
+	azureNetworkAPIVersion = "2020-06-01"
+	azureComputeAPIVersion = "2021-07-01"
+)
+
+// azureVM is a virtual machine of the template.
+type azureVM struct {
+	name       string
+	spec       *machinev1beta1.AzureMachineProviderSpec
+	customData string
+}
+
+// azureTemplates renders the ARM template of the virtual network, unless the
+// install-config sets one, of the network security group and of the virtual
+// machines of the cluster, to deploy in the resource group of the cluster.
+func azureTemplates(in *templateInputs) ([]*asset.File, error) {
+	ic := in.installConfig
+	network, err := machineNetwork(ic)
+	if err != nil {
+		return nil, err
+	}
+
+	var vms []azureVM
+	masterSubnet, workerSubnet := fmt.Sprintf("%s-master-subnet", in.infraID), fmt.Sprintf("%s-worker-subnet", in.infraID)
+	for _, m := range in.masters {
+		spec, ok := m.Spec.ProviderSpec.Value.Object.(*machinev1beta1.AzureMachineProviderSpec)
+		if !ok {
+			return nil, errors.Errorf("unexpected provider spec of the machine %s", m.Name)
+		}
+		masterSubnet = spec.Subnet
+		vms = append(vms, azureVM{name: m.Name, spec: spec, customData: base64.StdEncoding.EncodeToString(in.masterIgnition)})
+	}
+	for _, ms := range in.workers {
+		spec, ok := ms.Spec.Template.Spec.ProviderSpec.Value.Object.(*machinev1beta1.AzureMachineProviderSpec)
+		if !ok {
+			return nil, errors.Errorf("unexpected provider spec of the MachineSet %s", ms.Name)
+		}
+		workerSubnet = spec.Subnet
+		for i := 0; i < replicas(ms); i++ {
+			vms = append(vms, azureVM{name: workerName(ms, i), spec: spec, customData: base64.StdEncoding.EncodeToString(in.workerIgnition)})
+		}
+	}
+	if len(vms) == 0 {
+		return nil, errors.New("the cluster has no machines")
+	}
+
+	tags := map[string]interface{}{fmt.Sprintf("kubernetes.io_cluster.%s", in.infraID): "owned"}
+	nsg := fmt.Sprintf("%s-nsg", in.infraID)
+	var resources []interface{}
+	var networkDependency []interface{}
+	if ic.Platform.Azure.VirtualNetwork == "" {
+		masterCIDR, err := cidr.Subnet(network, 3, 0)
+		if err != nil {
+			return nil, err
+		}
+		workerCIDR, err := cidr.Subnet(network, 3, 1)
+		if err != nil {
+			return nil, err
+		}
+		vnet := vms[0].spec.Vnet
+		resources = append(resources, map[string]interface{}{
+			"type":       "Microsoft.Network/virtualNetworks",
+			"apiVersion": azureNetworkAPIVersion,
+			"name":       vnet,
+			"location":   "[resourceGroup().location]",
+			"tags":       tags,
+			"dependsOn":  []interface{}{fmt.Sprintf("[resourceId('Microsoft.Network/networkSecurityGroups', '%s')]", nsg)},
+			"properties": map[string]interface{}{
+				"addressSpace": map[string]interface{}{"addressPrefixes": []interface{}{network.String()}},
+				"subnets": []interface{}{
+					azureSubnet(masterSubnet, masterCIDR.String(), nsg),
+					azureSubnet(workerSubnet, workerCIDR.String(), nsg),
+				},
+			},
+		})
+		networkDependency = []interface{}{fmt.Sprintf("[resourceId('Microsoft.Network/virtualNetworks', '%s')]", vnet)}
+	}
+
+	apiSource := "Internet"
+	if ic.Publish != types.ExternalPublishingStrategy {
+		apiSource = "VirtualNetwork"
+	}
+	resources = append(resources, map[string]interface{}{
+		"type":       "Microsoft.Network/networkSecurityGroups",
+		"apiVersion": azureNetworkAPIVersion,
+		"name":       nsg,
+		"location":   "[resourceGroup().location]",
+		"tags":       tags,
+		"properties": map[string]interface{}{
+			"securityRules": []interface{}{
+				azureSecurityRule("apiserver_in", 101, "6443", apiSource),
+				azureSecurityRule("http_in", 102, "80", apiSource),
+				azureSecurityRule("https_in", 103, "443", apiSource),
+			},
+		},
+	})
+
+	bootstrapData := base64.StdEncoding.EncodeToString([]byte(bootstrapPointer("BOOTSTRAP_IGNITION_LOCATION")))
+	bootstrap := azureVM{name: fmt.Sprintf("%s-bootstrap", in.infraID), spec: vms[0].spec}
+	resources = append(resources, azureVMResources(bootstrap, fmt.Sprintf("[base64(replace(base64ToString('%s'), 'BOOTSTRAP_IGNITION_LOCATION', parameters('bootstrapIgnitionLocation')))]", bootstrapData), ic.SSHKey, tags, networkDependency)...)
+	for _, vm := range vms {
+		resources = append(resources, azureVMResources(vm, vm.customData, ic.SSHKey, tags, networkDependency)...)
+	}
+
+	template := map[string]interface{}{
+		"$schema":        "https://schema.management.azure.com/schemas/2019-04-01/deploymentTemplate.json#",
+		"contentVersion": "1.0.0.0",
+		"parameters": map[string]interface{}{
+			"imageId": map[string]interface{}{
+				"type": "string",
+				"metadata": map[string]interface{}{
+					"description": fmt.Sprintf("The ID of the RHCOS image of the virtual machines, created from the VHD %s.", in.image),
+				},
+			},
+			"bootstrapIgnitionLocation": map[string]interface{}{
+				"type": "string",
+				"metadata": map[string]interface{}{
+					"description": "The URL to upload bootstrap.ign to, e.g. a SAS URL of a blob, which the bootstrap virtual machine fetches it from.",
+				},
+			},
+		},
+		"resources": resources,
+	}
+	data, err := json.MarshalIndent(template, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the ARM template")
+	}
+	return []*asset.File{{Filename: azureTemplateFileName, Data: data}}, nil
+}
+
+// azureSubnet returns a subnet of the virtual network.
+func azureSubnet(name, prefix, nsg string) map[string]interface{} {
+	return map[string]interface{}{
+		"name": name,
+		"properties": map[string]interface{}{
+			"addressPrefix": prefix,
+			"networkSecurityGroup": map[string]interface{}{
+				"id": fmt.Sprintf("[resourceId('Microsoft.Network/networkSecurityGroups', '%s')]", nsg),
+			},
+		},
+	}
+}
+
+// azureSecurityRule returns a rule allowing TCP connections to the port.
+func azureSecurityRule(name string, priority int, port, source string) map[string]interface{} {
+	return map[string]interface{}{
+		"name": name,
+		"properties": map[string]interface{}{
+			"protocol":                 "Tcp",
+			"sourcePortRange":          "*",
+			"destinationPortRange":     port,
+			"sourceAddressPrefix":      source,
+			"destinationAddressPrefix": "*",
+			"access":                   "Allow",
+			"priority":                 priority,
+			"direction":                "Inbound",
+		},
+	}
+}
+
+// azureVMResources returns the network interface and the virtual machine.
+func azureVMResources(vm azureVM, customData, sshKey string, tags map[string]interface{}, networkDependency []interface{}) []interface{} {
+	nic := fmt.Sprintf("%s-nic", vm.name)
+	subnetID := fmt.Sprintf("[resourceId('Microsoft.Network/virtualNetworks/subnets', '%s', '%s')]", vm.spec.Vnet, vm.spec.Subnet)
+	if vm.spec.NetworkResourceGroup != "" {
+		subnetID = fmt.Sprintf("[resourceId('%s', 'Microsoft.Network/virtualNetworks/subnets', '%s', '%s')]", vm.spec.NetworkResourceGroup, vm.spec.Vnet, vm.spec.Subnet)
+	}
+	osProfile := map[string]interface{}{
+		"computerName":  vm.name,
+		"adminUsername": "core",
+		"customData":    customData,
+		"linuxConfiguration": map[string]interface{}{
+			"disablePasswordAuthentication": true,
+			"ssh": map[string]interface{}{
+				"publicKeys": []interface{}{map[string]interface{}{
+					"path":    "/home/core/.ssh/authorized_keys",
+					"keyData": sshKey,
+				}},
+			},
+		},
+	}
+	if sshKey == "" {
+		// Azure requires a password or a key; the machines are accessed
+		// with the keys of their Ignition configs, if any.
+		osProfile["linuxConfiguration"] = map[string]interface{}{"disablePasswordAuthentication": false}
+		osProfile["adminPassword"] = "[concat('P', uniqueString(resourceGroup().id, deployment().name), 'x!')]"
+	}
+	machine := map[string]interface{}{
+		"type":       "Microsoft.Compute/virtualMachines",
+		"apiVersion": azureComputeAPIVersion,
+		"name":       vm.name,
+		"location":   "[resourceGroup().location]",
+		"tags":       tags,
+		"dependsOn":  []interface{}{fmt.Sprintf("[resourceId('Microsoft.Network/networkInterfaces', '%s')]", nic)},
+		"properties": map[string]interface{}{
+			"hardwareProfile": map[string]interface{}{"vmSize": vm.spec.VMSize},
+			"osProfile":       osProfile,
+			"storageProfile": map[string]interface{}{
+				"imageReference": map[string]interface{}{"id": "[parameters('imageId')]"},
+				"osDisk": map[string]interface{}{
+					"name":         fmt.Sprintf("%s_OSDisk", vm.name),
+					"createOption": "FromImage",
+					"diskSizeGB":   vm.spec.OSDisk.DiskSizeGB,
+					"managedDisk":  map[string]interface{}{"storageAccountType": vm.spec.OSDisk.ManagedDisk.StorageAccountType},
+				},
+			},
+			"networkProfile": map[string]interface{}{
+				"networkInterfaces": []interface{}{map[string]interface{}{
+					"id": fmt.Sprintf("[resourceId('Microsoft.Network/networkInterfaces', '%s')]", nic),
+				}},
+			},
+		},
+	}
+	if vm.spec.Zone != nil && *vm.spec.Zone != "" {
+		machine["zones"] = []interface{}{*vm.spec.Zone}
+	}
+	return []interface{}{
+		map[string]interface{}{
+			"type":       "Microsoft.Network/networkInterfaces",
+			"apiVersion": azureNetworkAPIVersion,
+			"name":       nic,
+			"location":   "[resourceGroup().location]",
+			"tags":       tags,
+			"dependsOn":  networkDependency,
+			"properties": map[string]interface{}{
+				"ipConfigurations": []interface{}{map[string]interface{}{
+					"name": "pipConfig",
+					"properties": map[string]interface{}{
+						"privateIPAllocationMethod": "Dynamic",
+						"subnet":                    map[string]interface{}{"id": subnetID},
+					},
+				}},
+			},
+		},
+		machine,
+	}
+}
@@ -0,0 +1,215 @@
+package upi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apparentlymart/go-cidr/cidr"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// gcpTemplateFileName is the Deployment Manager configuration of the cluster.
+const gcpTemplateFileName = "upi/gcp/cluster.yaml"
+
+// gcpInstance is a compute instance of the configuration.
+type gcpInstance struct {
+	name     string
+	spec     *machinev1beta1.GCPMachineProviderSpec
+	userData string
+}
+
+// gcpTemplates renders the Deployment Manager configuration of the network,
+// unless the install-config sets one, of the firewall rules and of the
+// instances of the cluster.
+func gcpTemplates(in *templateInputs) ([]*asset.File, error) {
+	ic := in.installConfig
+	network, err := machineNetwork(ic)
+	if err != nil {
+		return nil, err
+	}
+
+	var instances []gcpInstance
+	for _, m := range in.masters {
+		spec, ok := m.Spec.ProviderSpec.Value.Object.(*machinev1beta1.GCPMachineProviderSpec)
+		if !ok {
+			return nil, errors.Errorf("unexpected provider spec of the machine %s", m.Name)
+		}
+		instances = append(instances, gcpInstance{name: m.Name, spec: spec, userData: string(in.masterIgnition)})
+	}
+	for _, ms := range in.workers {
+		spec, ok := ms.Spec.Template.Spec.ProviderSpec.Value.Object.(*machinev1beta1.GCPMachineProviderSpec)
+		if !ok {
+			return nil, errors.Errorf("unexpected provider spec of the MachineSet %s", ms.Name)
+		}
+		for i := 0; i < replicas(ms); i++ {
+			instances = append(instances, gcpInstance{name: workerName(ms, i), spec: spec, userData: string(in.workerIgnition)})
+		}
+	}
+	if len(instances) == 0 {
+		return nil, errors.New("the cluster has no machines")
+	}
+	for _, instance := range instances {
+		if len(instance.spec.Disks) == 0 || len(instance.spec.NetworkInterfaces) == 0 {
+			return nil, errors.Errorf("the machine %s has no disk or no network interface", instance.name)
+		}
+	}
+
+	networkName := fmt.Sprintf("%s-network", in.infraID)
+	networkRef := fmt.Sprintf("$(ref.%s.selfLink)", networkName)
+	var resources []interface{}
+	if ic.Platform.GCP.Network == "" {
+		masterCIDR, err := cidr.Subnet(network, 3, 0)
+		if err != nil {
+			return nil, err
+		}
+		workerCIDR, err := cidr.Subnet(network, 3, 1)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, map[string]interface{}{
+			"name": networkName,
+			"type": "compute.v1.network",
+			"properties": map[string]interface{}{
+				"autoCreateSubnetworks": false,
+			},
+		})
+		masterSubnet := instances[0].spec.NetworkInterfaces[0].Subnetwork
+		workerSubnet := instances[len(instances)-1].spec.NetworkInterfaces[0].Subnetwork
+		resources = append(resources, gcpSubnetwork(masterSubnet, ic.Platform.GCP.Region, networkRef, masterCIDR.String()))
+		if workerSubnet != masterSubnet {
+			resources = append(resources, gcpSubnetwork(workerSubnet, ic.Platform.GCP.Region, networkRef, workerCIDR.String()))
+		}
+	} else {
+		networkRef = fmt.Sprintf("projects/%s/global/networks/%s", networkProject(ic), ic.Platform.GCP.Network)
+	}
+
+	apiSource := "0.0.0.0/0"
+	if ic.Publish != types.ExternalPublishingStrategy {
+		apiSource = network.String()
+	}
+	resources = append(resources,
+		gcpFirewall(fmt.Sprintf("%s-api", in.infraID), networkRef, apiSource, []string{"6443"}, fmt.Sprintf("%s-master", in.infraID)),
+		gcpFirewall(fmt.Sprintf("%s-ingress", in.infraID), networkRef, apiSource, []string{"80", "443"}, fmt.Sprintf("%s-worker", in.infraID)),
+		map[string]interface{}{
+			"name": fmt.Sprintf("%s-internal", in.infraID),
+			"type": "compute.v1.firewall",
+			"properties": map[string]interface{}{
+				"network":      networkRef,
+				"sourceRanges": []interface{}{network.String()},
+				"allowed": []interface{}{
+					map[string]interface{}{"IPProtocol": "tcp"},
+					map[string]interface{}{"IPProtocol": "udp"},
+					map[string]interface{}{"IPProtocol": "icmp"},
+				},
+			},
+		},
+	)
+
+	bootstrap := gcpInstance{name: fmt.Sprintf("%s-bootstrap", in.infraID), spec: instances[0].spec, userData: bootstrapPointer("BOOTSTRAP_IGNITION_LOCATION")}
+	resources = append(resources, gcpInstanceResource(bootstrap, in.image, networkRef))
+	for _, instance := range instances {
+		resources = append(resources, gcpInstanceResource(instance, in.image, networkRef))
+	}
+
+	data, err := yaml.Marshal(map[string]interface{}{"resources": resources})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the Deployment Manager configuration")
+	}
+	header := fmt.Sprintf(`# The Deployment Manager configuration of the network, the firewall rules and
+# the instances of the cluster %s. The load balancers and the DNS records are
+# left to the user. Replace BOOTSTRAP_IGNITION_LOCATION with the URL, e.g. a
+# signed URL, the bootstrap instance fetches bootstrap.ign from.
+`, in.infraID)
+	return []*asset.File{{Filename: gcpTemplateFileName, Data: append([]byte(header), data...)}}, nil
+}
+
+// networkProject returns the project of the network of the cluster.
+func networkProject(ic *types.InstallConfig) string {
+	if ic.Platform.GCP.NetworkProjectID != "" {
+		return ic.Platform.GCP.NetworkProjectID
+	}
+	return ic.Platform.GCP.ProjectID
+}
+
+// gcpSubnetwork returns a subnetwork of the network.
+func gcpSubnetwork(name, region, network, cidr string) map[string]interface{} {
+	return map[string]interface{}{
+		"name": name,
+		"type": "compute.v1.subnetwork",
+		"properties": map[string]interface{}{
+			"region":      region,
+			"network":     network,
+			"ipCidrRange": cidr,
+		},
+	}
+}
+
+// gcpFirewall returns a rule allowing TCP connections to the ports of the
+// instances with the tag.
+func gcpFirewall(name, network, source string, ports []string, tag string) map[string]interface{} {
+	return map[string]interface{}{
+		"name": name,
+		"type": "compute.v1.firewall",
+		"properties": map[string]interface{}{
+			"network":      network,
+			"sourceRanges": []interface{}{source},
+			"targetTags":   []interface{}{tag},
+			"allowed": []interface{}{map[string]interface{}{
+				"IPProtocol": "tcp",
+				"ports":      ports,
+			}},
+		},
+	}
+}
+
+// gcpInstanceResource returns the compute instance.
+func gcpInstanceResource(instance gcpInstance, image, network string) map[string]interface{} {
+	disk := instance.spec.Disks[0]
+	nic := instance.spec.NetworkInterfaces[0]
+	sourceImage := disk.Image
+	if sourceImage == "" {
+		sourceImage = image
+	}
+	subnetwork := fmt.Sprintf("$(ref.%s.selfLink)", nic.Subnetwork)
+	if !strings.HasPrefix(network, "$(ref.") {
+		project := nic.ProjectID
+		if project == "" {
+			project = instance.spec.ProjectID
+		}
+		subnetwork = fmt.Sprintf("projects/%s/regions/%s/subnetworks/%s", project, instance.spec.Region, nic.Subnetwork)
+	}
+	return map[string]interface{}{
+		"name": instance.name,
+		"type": "compute.v1.instance",
+		"properties": map[string]interface{}{
+			"zone":        instance.spec.Zone,
+			"machineType": fmt.Sprintf("zones/%s/machineTypes/%s", instance.spec.Zone, instance.spec.MachineType),
+			"disks": []interface{}{map[string]interface{}{
+				"autoDelete": true,
+				"boot":       true,
+				"initializeParams": map[string]interface{}{
+					"diskSizeGb":  disk.SizeGB,
+					"diskType":    fmt.Sprintf("zones/%s/diskTypes/%s", instance.spec.Zone, disk.Type),
+					"sourceImage": sourceImage,
+				},
+			}},
+			"networkInterfaces": []interface{}{map[string]interface{}{
+				"subnetwork": subnetwork,
+			}},
+			"metadata": map[string]interface{}{
+				"items": []interface{}{map[string]interface{}{
+					"key":   "user-data",
+					"value": instance.userData,
+				}},
+			},
+			"tags": map[string]interface{}{
+				"items": instance.spec.Tags,
+			},
+		},
+	}
+}
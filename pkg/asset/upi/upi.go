@@ -0,0 +1,171 @@
+// Package upi generates the templates of the infrastructure of a cluster on
+// user-provisioned infrastructure, filled in with the zones, the CIDRs and the
+// instance types of the install-config: the CloudFormation, ARM or Deployment
+// Manager templates of the network, the firewall rules and the machines, and
+// on vSphere the terraform configuration of the virtual machines only.
+package upi
+
+import (
+	"fmt"
+	"math"
+	"net"
+
+	"github.com/apparentlymart/go-cidr/cidr"
+	"github.com/pkg/errors"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/ignition/machine"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/machines"
+	"github.com/openshift/installer/pkg/asset/rhcos"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/azure"
+	"github.com/openshift/installer/pkg/types/gcp"
+	"github.com/openshift/installer/pkg/types/vsphere"
+)
+
+// Templates is the templates of the infrastructure of the cluster, in the
+// upi directory.
+type Templates struct {
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*Templates)(nil)
+
+// templateInputs are what the templates are rendered from.
+type templateInputs struct {
+	infraID       string
+	installConfig *types.InstallConfig
+	image         string
+	// masters and workers are the machines of the control plane and of
+	// the compute MachineSets, with their provider specs decoded.
+	masters []machinev1beta1.Machine
+	workers []machinev1beta1.MachineSet
+	// masterIgnition and workerIgnition are the Ignition configs the
+	// machines boot with, which fetch their configs from the machine
+	// config server.
+	masterIgnition []byte
+	workerIgnition []byte
+}
+
+// Name returns the human-friendly name of the asset.
+func (t *Templates) Name() string {
+	return "UPI Templates"
+}
+
+// Dependencies returns the direct dependencies of the templates.
+func (t *Templates) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.ClusterID{},
+		&installconfig.InstallConfig{},
+		new(rhcos.Image),
+		&machines.Master{},
+		&machines.Worker{},
+		&machine.Master{},
+		&machine.Worker{},
+	}
+}
+
+// Generate renders the templates of the platform of the install-config.
+func (t *Templates) Generate(parents asset.Parents) error {
+	clusterID := &installconfig.ClusterID{}
+	installConfig := &installconfig.InstallConfig{}
+	rhcosImage := new(rhcos.Image)
+	mastersAsset := &machines.Master{}
+	workersAsset := &machines.Worker{}
+	masterIgnition := &machine.Master{}
+	workerIgnition := &machine.Worker{}
+	parents.Get(clusterID, installConfig, rhcosImage, mastersAsset, workersAsset, masterIgnition, workerIgnition)
+
+	masters, err := mastersAsset.Machines()
+	if err != nil {
+		return err
+	}
+	workers, err := workersAsset.MachineSets()
+	if err != nil {
+		return err
+	}
+	in := &templateInputs{
+		infraID:        clusterID.InfraID,
+		installConfig:  installConfig.Config,
+		image:          string(*rhcosImage),
+		masters:        masters,
+		workers:        workers,
+		masterIgnition: masterIgnition.File.Data,
+		workerIgnition: workerIgnition.File.Data,
+	}
+
+	platform := installConfig.Config.Platform.Name()
+	var files []*asset.File
+	switch platform {
+	case aws.Name:
+		files, err = awsTemplates(in)
+	case azure.Name:
+		files, err = azureTemplates(in)
+	case gcp.Name:
+		files, err = gcpTemplates(in)
+	case vsphere.Name:
+		files, err = vsphereTemplates(in)
+	default:
+		return errors.Errorf("the UPI templates are not available for the %s platform", platform)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to render the UPI templates of the %s platform", platform)
+	}
+	t.FileList = files
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (t *Templates) Files() []*asset.File {
+	return t.FileList
+}
+
+// Load always renders the templates again from the install-config.
+func (t *Templates) Load(f asset.FileFetcher) (found bool, err error) {
+	return false, nil
+}
+
+// machineNetwork returns the first machine network of the install-config.
+func machineNetwork(ic *types.InstallConfig) (*net.IPNet, error) {
+	if ic.Networking == nil || len(ic.Networking.MachineNetwork) == 0 {
+		return nil, errors.New("the install-config has no machine network")
+	}
+	return &ic.Networking.MachineNetwork[0].CIDR.IPNet, nil
+}
+
+// subnets splits the network in count subnets of the same size, the smallest
+// power of two no smaller than count, as the installer splits it.
+func subnets(network *net.IPNet, count int) ([]*net.IPNet, error) {
+	newBits := int(math.Ceil(math.Log2(float64(count))))
+	nets := make([]*net.IPNet, 0, count)
+	for i := 0; i < count; i++ {
+		subnet, err := cidr.Subnet(network, newBits, i)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to split %s in %d subnets", network, count)
+		}
+		nets = append(nets, subnet)
+	}
+	return nets, nil
+}
+
+// workerName returns the name of a machine of the MachineSet.
+func workerName(ms machinev1beta1.MachineSet, i int) string {
+	return fmt.Sprintf("%s-%d", ms.Name, i)
+}
+
+// replicas returns the number of machines of the MachineSet.
+func replicas(ms machinev1beta1.MachineSet) int {
+	if ms.Spec.Replicas == nil {
+		return 0
+	}
+	return int(*ms.Spec.Replicas)
+}
+
+// bootstrapPointer returns the Ignition config of the bootstrap machine, which
+// fetches the bootstrap Ignition config from the location.
+func bootstrapPointer(location string) string {
+	return fmt.Sprintf(`{"ignition":{"config":{"replace":{"source":"%s"}},"version":"3.2.0"}}`, location)
+}
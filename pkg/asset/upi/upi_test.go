@@ -0,0 +1,317 @@
+package upi
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/ipnet"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/azure"
+	"github.com/openshift/installer/pkg/types/gcp"
+	"github.com/openshift/installer/pkg/types/vsphere"
+)
+
+func TestSubnets(t *testing.T) {
+	cases := []struct {
+		network string
+		count   int
+		expect  []string
+	}{
+		{network: "10.0.0.0/16", count: 1, expect: []string{"10.0.0.0/16"}},
+		{network: "10.0.0.0/16", count: 2, expect: []string{"10.0.0.0/17", "10.0.128.0/17"}},
+		{network: "10.0.0.0/16", count: 3, expect: []string{"10.0.0.0/18", "10.0.64.0/18", "10.0.128.0/18"}},
+		{network: "10.0.0.0/30", count: 8},
+	}
+	for _, tc := range cases {
+		_, network, err := net.ParseCIDR(tc.network)
+		if !assert.NoError(t, err) {
+			return
+		}
+		nets, err := subnets(network, tc.count)
+		if tc.expect == nil {
+			assert.Error(t, err)
+			continue
+		}
+		if !assert.NoError(t, err) {
+			continue
+		}
+		var actual []string
+		for _, n := range nets {
+			actual = append(actual, n.String())
+		}
+		assert.Equal(t, tc.expect, actual, tc.network)
+	}
+}
+
+func TestBootstrapPointer(t *testing.T) {
+	var config struct {
+		Ignition struct {
+			Config struct {
+				Replace struct {
+					Source string `json:"source"`
+				} `json:"replace"`
+			} `json:"config"`
+			Version string `json:"version"`
+		} `json:"ignition"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(bootstrapPointer("s3://bucket/bootstrap.ign")), &config))
+	assert.Equal(t, "s3://bucket/bootstrap.ign", config.Ignition.Config.Replace.Source)
+	assert.Equal(t, "3.2.0", config.Ignition.Version)
+}
+
+func TestTerraformName(t *testing.T) {
+	assert.Equal(t, "dc1_my_datastore_1", terraformName("dc1", "my datastore/1"))
+}
+
+type templatesTestCase struct {
+	name        string
+	platform    types.Platform
+	publish     types.PublishingStrategy
+	spec        runtime.Object
+	noMachines  bool
+	files       []string
+	contains    []string
+	notContains []string
+	err         string
+}
+
+// testTemplates renders the templates of each case with a master and a
+// worker of the provider spec and checks the rendered files.
+func testTemplates(t *testing.T, render func(*templateInputs) ([]*asset.File, error), cases []templatesTestCase) {
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			publish := tc.publish
+			if publish == "" {
+				publish = types.ExternalPublishingStrategy
+			}
+			in := &templateInputs{
+				infraID: "lab-x7k2p",
+				installConfig: &types.InstallConfig{
+					Networking: &types.Networking{
+						MachineNetwork: []types.MachineNetworkEntry{{CIDR: *ipnet.MustParseCIDR("10.0.0.0/16")}},
+					},
+					Platform: tc.platform,
+					Publish:  publish,
+				},
+				image:          "rhcos-image",
+				masterIgnition: []byte(`{"ignition":{"version":"3.2.0"}}`),
+				workerIgnition: []byte(`{"ignition":{"version":"3.2.0"}}`),
+			}
+			if !tc.noMachines {
+				in.masters = []machinev1beta1.Machine{{
+					ObjectMeta: metav1.ObjectMeta{Name: "lab-x7k2p-master-0"},
+					Spec: machinev1beta1.MachineSpec{
+						ProviderSpec: machinev1beta1.ProviderSpec{Value: &runtime.RawExtension{Object: tc.spec}},
+					},
+				}}
+				in.workers = []machinev1beta1.MachineSet{{
+					ObjectMeta: metav1.ObjectMeta{Name: "lab-x7k2p-worker-a"},
+					Spec: machinev1beta1.MachineSetSpec{
+						Replicas: pointer.Int32(1),
+						Template: machinev1beta1.MachineTemplateSpec{
+							Spec: machinev1beta1.MachineSpec{
+								ProviderSpec: machinev1beta1.ProviderSpec{Value: &runtime.RawExtension{Object: tc.spec}},
+							},
+						},
+					},
+				}}
+			}
+
+			files, err := render(in)
+			if tc.err != "" {
+				assert.Regexp(t, tc.err, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			var names []string
+			for _, f := range files {
+				names = append(names, f.Filename)
+			}
+			assert.Equal(t, tc.files, names)
+			data := string(files[0].Data)
+			for _, s := range tc.contains {
+				assert.Contains(t, data, s)
+			}
+			for _, s := range tc.notContains {
+				assert.NotContains(t, data, s)
+			}
+		})
+	}
+}
+
+func TestAWSTemplates(t *testing.T) {
+	spec := func(subnetID *string) *machinev1beta1.AWSMachineProviderConfig {
+		return &machinev1beta1.AWSMachineProviderConfig{
+			InstanceType: "m6i.xlarge",
+			Placement:    machinev1beta1.Placement{AvailabilityZone: "us-east-1a"},
+			Subnet:       machinev1beta1.AWSResourceReference{ID: subnetID},
+		}
+	}
+	testTemplates(t, awsTemplates, []templatesTestCase{
+		{
+			name:     "new VPC",
+			platform: types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			spec:     spec(nil),
+			files:    []string{awsTemplateFileName},
+			contains: []string{"Type: AWS::EC2::VPC\n", "PublicSubnet0:", "PrivateSubnet0:", "MasterSecurityGroup:", "BootstrapInstance:", "Master0:", "Worker0:"},
+		},
+		{
+			name:        "existing subnets",
+			platform:    types.Platform{AWS: &aws.Platform{Region: "us-east-1", Subnets: []string{"subnet-private"}}},
+			spec:        spec(pointer.String("subnet-private")),
+			files:       []string{awsTemplateFileName},
+			contains:    []string{"AWS::EC2::VPC::Id", "SubnetId: subnet-private", "BootstrapInstance:"},
+			notContains: []string{"Type: AWS::EC2::VPC\n", "NatGateway"},
+		},
+		{
+			name:     "existing subnets without subnet ID",
+			platform: types.Platform{AWS: &aws.Platform{Region: "us-east-1", Subnets: []string{"subnet-private"}}},
+			spec:     spec(nil),
+			err:      `^the instance in us-east-1a has no subnet ID$`,
+		},
+		{
+			name:       "no machines",
+			platform:   types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			noMachines: true,
+			err:        `^the cluster has no machines$`,
+		},
+	})
+}
+
+func TestAzureTemplates(t *testing.T) {
+	spec := &machinev1beta1.AzureMachineProviderSpec{
+		VMSize: "Standard_D8s_v3",
+		Vnet:   "lab-x7k2p-vnet",
+		Subnet: "lab-x7k2p-master-subnet",
+		OSDisk: machinev1beta1.OSDisk{DiskSizeGB: 1024},
+	}
+	testTemplates(t, azureTemplates, []templatesTestCase{
+		{
+			name:     "new virtual network",
+			platform: types.Platform{Azure: &azure.Platform{Region: "eastus"}},
+			spec:     spec,
+			files:    []string{azureTemplateFileName},
+			contains: []string{`"Microsoft.Network/virtualNetworks"`, `"Microsoft.Network/networkSecurityGroups"`, `"lab-x7k2p-bootstrap"`, `"lab-x7k2p-master-0"`, `"lab-x7k2p-worker-a-0"`, `"Internet"`},
+		},
+		{
+			name:        "existing virtual network",
+			platform:    types.Platform{Azure: &azure.Platform{Region: "eastus", VirtualNetwork: "shared-vnet"}},
+			publish:     types.InternalPublishingStrategy,
+			spec:        spec,
+			files:       []string{azureTemplateFileName},
+			contains:    []string{`"Microsoft.Network/networkSecurityGroups"`, `"VirtualNetwork"`},
+			notContains: []string{`"Microsoft.Network/virtualNetworks"`},
+		},
+		{
+			name:       "no machines",
+			platform:   types.Platform{Azure: &azure.Platform{Region: "eastus"}},
+			noMachines: true,
+			err:        `^the cluster has no machines$`,
+		},
+	})
+}
+
+func TestGCPTemplates(t *testing.T) {
+	spec := &machinev1beta1.GCPMachineProviderSpec{
+		MachineType:       "n2-standard-4",
+		Region:            "us-central1",
+		Zone:              "us-central1-a",
+		ProjectID:         "project",
+		Disks:             []*machinev1beta1.GCPDisk{{Type: "pd-ssd", SizeGB: 128}},
+		NetworkInterfaces: []*machinev1beta1.GCPNetworkInterface{{Subnetwork: "lab-x7k2p-master-subnet"}},
+	}
+	testTemplates(t, gcpTemplates, []templatesTestCase{
+		{
+			name:     "new network",
+			platform: types.Platform{GCP: &gcp.Platform{ProjectID: "project", Region: "us-central1"}},
+			spec:     spec,
+			files:    []string{gcpTemplateFileName},
+			contains: []string{"type: compute.v1.network", "type: compute.v1.subnetwork", "type: compute.v1.firewall", "name: lab-x7k2p-bootstrap", "name: lab-x7k2p-master-0", "name: lab-x7k2p-worker-a-0"},
+		},
+		{
+			name:        "existing network",
+			platform:    types.Platform{GCP: &gcp.Platform{ProjectID: "project", Region: "us-central1", Network: "shared", NetworkProjectID: "host-project"}},
+			spec:        spec,
+			files:       []string{gcpTemplateFileName},
+			contains:    []string{"projects/host-project/global/networks/shared", "type: compute.v1.firewall"},
+			notContains: []string{"type: compute.v1.network\n", "type: compute.v1.subnetwork"},
+		},
+		{
+			name:     "no disk",
+			platform: types.Platform{GCP: &gcp.Platform{ProjectID: "project", Region: "us-central1"}},
+			spec:     &machinev1beta1.GCPMachineProviderSpec{NetworkInterfaces: spec.NetworkInterfaces},
+			err:      `^the machine lab-x7k2p-master-0 has no disk or no network interface$`,
+		},
+	})
+}
+
+func TestVSphereTemplates(t *testing.T) {
+	spec := &machinev1beta1.VSphereMachineProviderSpec{
+		Template: "lab-x7k2p-rhcos",
+		Workspace: &machinev1beta1.Workspace{
+			Server:       "vcenter.example.com",
+			Datacenter:   "dc1",
+			Datastore:    "ds1",
+			Folder:       "/dc1/vm/lab-x7k2p",
+			ResourcePool: "/dc1/host/cluster1/Resources",
+		},
+		Network:   machinev1beta1.NetworkSpec{Devices: []machinev1beta1.NetworkDeviceSpec{{NetworkName: "VM Network"}}},
+		NumCPUs:   4,
+		MemoryMiB: 16384,
+		DiskGiB:   120,
+	}
+	testTemplates(t, vsphereTemplates, []templatesTestCase{
+		{
+			name:     "virtual machines",
+			platform: types.Platform{VSphere: &vsphere.Platform{}},
+			spec:     spec,
+			files:    []string{vsphereTemplateFileName},
+			contains: []string{`"lab-x7k2p-bootstrap"`, `"lab-x7k2p-master-0"`, `"lab-x7k2p-worker-a-0"`, `"default": "vcenter.example.com"`, `"dc1_VM_Network"`},
+		},
+		{
+			name:     "no workspace",
+			platform: types.Platform{VSphere: &vsphere.Platform{}},
+			spec:     &machinev1beta1.VSphereMachineProviderSpec{Network: spec.Network},
+			err:      `^the machine lab-x7k2p-master-0 has no workspace or no network device$`,
+		},
+	})
+}
+
+func TestVSphereTemplatesOnlyVirtualMachines(t *testing.T) {
+	files, err := vsphereTemplates(&templateInputs{
+		infraID: "lab-x7k2p",
+		masters: []machinev1beta1.Machine{{
+			ObjectMeta: metav1.ObjectMeta{Name: "lab-x7k2p-master-0"},
+			Spec: machinev1beta1.MachineSpec{
+				ProviderSpec: machinev1beta1.ProviderSpec{Value: &runtime.RawExtension{Object: &machinev1beta1.VSphereMachineProviderSpec{
+					Workspace: &machinev1beta1.Workspace{Datacenter: "dc1"},
+					Network:   machinev1beta1.NetworkSpec{Devices: []machinev1beta1.NetworkDeviceSpec{{NetworkName: "VM Network"}}},
+				}}},
+			},
+		}},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	var config struct {
+		Resource map[string]json.RawMessage `json:"resource"`
+	}
+	if assert.NoError(t, json.Unmarshal(files[0].Data, &config)) {
+		var kinds []string
+		for kind := range config.Resource {
+			kinds = append(kinds, kind)
+		}
+		assert.Equal(t, []string{"vsphere_virtual_machine"}, kinds)
+	}
+}
@@ -0,0 +1,155 @@
+package upi
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/installer/pkg/asset"
+)
+
+// vsphereTemplateFileName is the terraform configuration of the cluster.
+const vsphereTemplateFileName = "upi/vsphere/main.tf.json"
+
+// vsphereVM is a virtual machine of the configuration.
+type vsphereVM struct {
+	name string
+	spec *machinev1beta1.VSphereMachineProviderSpec
+	// ignition is the base64-encoded Ignition config, or the terraform
+	// expression of it.
+	ignition string
+}
+
+// vsphereTemplates renders the terraform configuration, in the JSON syntax,
+// of the virtual machines of the cluster, cloned from the RHCOS template of
+// their provider specs.
+func vsphereTemplates(in *templateInputs) ([]*asset.File, error) {
+	var vms []vsphereVM
+	for _, m := range in.masters {
+		spec, ok := m.Spec.ProviderSpec.Value.Object.(*machinev1beta1.VSphereMachineProviderSpec)
+		if !ok {
+			return nil, errors.Errorf("unexpected provider spec of the machine %s", m.Name)
+		}
+		vms = append(vms, vsphereVM{name: m.Name, spec: spec, ignition: base64.StdEncoding.EncodeToString(in.masterIgnition)})
+	}
+	for _, ms := range in.workers {
+		spec, ok := ms.Spec.Template.Spec.ProviderSpec.Value.Object.(*machinev1beta1.VSphereMachineProviderSpec)
+		if !ok {
+			return nil, errors.Errorf("unexpected provider spec of the MachineSet %s", ms.Name)
+		}
+		for i := 0; i < replicas(ms); i++ {
+			vms = append(vms, vsphereVM{name: workerName(ms, i), spec: spec, ignition: base64.StdEncoding.EncodeToString(in.workerIgnition)})
+		}
+	}
+	if len(vms) == 0 {
+		return nil, errors.New("the cluster has no machines")
+	}
+	for _, vm := range vms {
+		if vm.spec.Workspace == nil || len(vm.spec.Network.Devices) == 0 {
+			return nil, errors.Errorf("the machine %s has no workspace or no network device", vm.name)
+		}
+	}
+	bootstrap := vsphereVM{
+		name:     fmt.Sprintf("%s-bootstrap", in.infraID),
+		spec:     vms[0].spec,
+		ignition: fmt.Sprintf(`${base64encode(replace(%q, "BOOTSTRAP_IGNITION_LOCATION", var.bootstrap_ignition_url))}`, bootstrapPointer("BOOTSTRAP_IGNITION_LOCATION")),
+	}
+	vms = append([]vsphereVM{bootstrap}, vms...)
+
+	data := map[string]map[string]interface{}{
+		"vsphere_datacenter":      {},
+		"vsphere_datastore":       {},
+		"vsphere_network":         {},
+		"vsphere_resource_pool":   {},
+		"vsphere_virtual_machine": {},
+	}
+	machines := map[string]interface{}{}
+	for _, vm := range vms {
+		ws := vm.spec.Workspace
+		datacenter := terraformName(ws.Datacenter)
+		data["vsphere_datacenter"][datacenter] = map[string]interface{}{"name": ws.Datacenter}
+		datacenterID := fmt.Sprintf("${data.vsphere_datacenter.%s.id}", datacenter)
+
+		datastore := terraformName(ws.Datacenter, ws.Datastore)
+		data["vsphere_datastore"][datastore] = map[string]interface{}{"name": ws.Datastore, "datacenter_id": datacenterID}
+		networkName := vm.spec.Network.Devices[0].NetworkName
+		network := terraformName(ws.Datacenter, networkName)
+		data["vsphere_network"][network] = map[string]interface{}{"name": networkName, "datacenter_id": datacenterID}
+		pool := terraformName(ws.Datacenter, ws.ResourcePool)
+		data["vsphere_resource_pool"][pool] = map[string]interface{}{"name": ws.ResourcePool, "datacenter_id": datacenterID}
+		template := terraformName(ws.Datacenter, vm.spec.Template)
+		data["vsphere_virtual_machine"][template] = map[string]interface{}{"name": vm.spec.Template, "datacenter_id": datacenterID}
+
+		machines[terraformName(vm.name)] = map[string]interface{}{
+			"name":                        vm.name,
+			"folder":                      strings.TrimPrefix(ws.Folder, fmt.Sprintf("/%s/vm/", ws.Datacenter)),
+			"resource_pool_id":            fmt.Sprintf("${data.vsphere_resource_pool.%s.id}", pool),
+			"datastore_id":                fmt.Sprintf("${data.vsphere_datastore.%s.id}", datastore),
+			"num_cpus":                    vm.spec.NumCPUs,
+			"num_cores_per_socket":        vm.spec.NumCoresPerSocket,
+			"memory":                      vm.spec.MemoryMiB,
+			"guest_id":                    fmt.Sprintf("${data.vsphere_virtual_machine.%s.guest_id}", template),
+			"enable_disk_uuid":            true,
+			"wait_for_guest_net_timeout":  0,
+			"wait_for_guest_net_routable": false,
+			"network_interface": map[string]interface{}{
+				"network_id": fmt.Sprintf("${data.vsphere_network.%s.id}", network),
+			},
+			"disk": map[string]interface{}{
+				"label":            "disk0",
+				"size":             vm.spec.DiskGiB,
+				"thin_provisioned": fmt.Sprintf("${data.vsphere_virtual_machine.%s.disks.0.thin_provisioned}", template),
+			},
+			"clone": map[string]interface{}{
+				"template_uuid": fmt.Sprintf("${data.vsphere_virtual_machine.%s.id}", template),
+			},
+			"extra_config": map[string]interface{}{
+				"guestinfo.ignition.config.data":          vm.ignition,
+				"guestinfo.ignition.config.data.encoding": "base64",
+			},
+		}
+	}
+
+	config := map[string]interface{}{
+		"//": fmt.Sprintf("The virtual machines of the cluster %s. The load balancers and the DNS records are left to the user.", in.infraID),
+		"variable": map[string]interface{}{
+			"vsphere_server":   map[string]interface{}{"type": "string", "default": vms[0].spec.Workspace.Server},
+			"vsphere_user":     map[string]interface{}{"type": "string"},
+			"vsphere_password": map[string]interface{}{"type": "string", "sensitive": true},
+			"bootstrap_ignition_url": map[string]interface{}{
+				"type":        "string",
+				"description": "The URL to upload bootstrap.ign to, which the bootstrap virtual machine fetches it from.",
+			},
+		},
+		"provider": map[string]interface{}{
+			"vsphere": map[string]interface{}{
+				"vsphere_server":       "${var.vsphere_server}",
+				"user":                 "${var.vsphere_user}",
+				"password":             "${var.vsphere_password}",
+				"allow_unverified_ssl": false,
+			},
+		},
+		"data":     data,
+		"resource": map[string]interface{}{"vsphere_virtual_machine": machines},
+	}
+	out, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the terraform configuration")
+	}
+	return []*asset.File{{Filename: vsphereTemplateFileName, Data: out}}, nil
+}
+
+// terraformName returns a terraform identifier from the names.
+func terraformName(names ...string) string {
+	name := strings.Join(names, "_")
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' {
+			return r
+		}
+		return '_'
+	}, name)
+}
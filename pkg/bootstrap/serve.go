@@ -0,0 +1,239 @@
+// Package bootstrap serves the endpoints of a bootstrap machine run on another
+// platform than the cluster, e.g. a local libvirt virtual machine or an
+// existing host: the Ignition config the bootstrap machine boots from, and
+// relays of the Kubernetes API and of the machine config server the control
+// plane machines reach the bootstrap machine at.
+package bootstrap
+
+import (
+	"context"
+	"crypto/subtle"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	ignutil "github.com/coreos/ignition/v2/config/util"
+	igntypes "github.com/coreos/ignition/v2/config/v3_2/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset/ignition"
+)
+
+const (
+	// APIPort is the port of the Kubernetes API.
+	APIPort = 6443
+	// MachineConfigServerPort is the port of the machine config server.
+	MachineConfigServerPort = 22623
+	// IgnitionPath is the path the Ignition config is served at.
+	IgnitionPath = "/bootstrap.ign"
+)
+
+// dialTimeout is how long to wait for a connection to the bootstrap machine.
+var dialTimeout = 10 * time.Second
+
+// Server serves the endpoints of an external bootstrap machine.
+type Server struct {
+	// Ignition is the Ignition config of the bootstrap machine.
+	Ignition []byte
+	// IgnitionAddress is the address to serve the Ignition config on, or
+	// empty to not serve it.
+	IgnitionAddress string
+	// Token is the bearer token the requests of the Ignition config must
+	// carry, as the config holds the secrets of the cluster.
+	Token string
+	// ListenHost is the host to listen on for the relays.
+	ListenHost string
+	// BootstrapHost is the host of the bootstrap machine the relays
+	// connect to.
+	BootstrapHost string
+	// Ports are the ports to relay, the same on both ends.
+	Ports []int
+}
+
+// Serve serves the endpoints until the context is done or one of them fails.
+func (s *Server) Serve(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var listeners []net.Listener
+	defer func() {
+		for _, l := range listeners {
+			l.Close()
+		}
+	}()
+	for _, port := range s.Ports {
+		address := net.JoinHostPort(s.ListenHost, strconv.Itoa(port))
+		l, err := net.Listen("tcp", address)
+		if err != nil {
+			return errors.Wrapf(err, "failed to listen on %s", address)
+		}
+		listeners = append(listeners, l)
+	}
+
+	errs := make(chan error, len(listeners)+1)
+	var httpServer *http.Server
+	if s.IgnitionAddress != "" {
+		l, err := net.Listen("tcp", s.IgnitionAddress)
+		if err != nil {
+			return errors.Wrapf(err, "failed to listen on %s", s.IgnitionAddress)
+		}
+		httpServer = &http.Server{
+			Handler:           s.ignitionHandler(),
+			ReadHeaderTimeout: 30 * time.Second,
+		}
+		logrus.Infof("Serving the bootstrap Ignition config at http://%s%s", l.Addr(), IgnitionPath)
+		go func() {
+			if err := httpServer.Serve(l); !errors.Is(err, http.ErrServerClosed) {
+				errs <- errors.Wrap(err, "failed to serve the Ignition config")
+			}
+		}()
+	}
+
+	var wg sync.WaitGroup
+	for i, l := range listeners {
+		target := net.JoinHostPort(s.BootstrapHost, strconv.Itoa(s.Ports[i]))
+		logrus.Infof("Relaying %s to the bootstrap machine at %s", l.Addr(), target)
+		wg.Add(1)
+		go func(l net.Listener) {
+			defer wg.Done()
+			if err := relay(ctx, l, target); err != nil {
+				errs <- err
+			}
+		}(l)
+	}
+
+	var err error
+	select {
+	case <-ctx.Done():
+	case err = <-errs:
+	}
+	cancel()
+	for _, l := range listeners {
+		l.Close()
+	}
+	if httpServer != nil {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer shutdownCancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			logrus.Warnf("Failed to stop serving the bootstrap Ignition config: %v", err)
+		}
+	}
+	wg.Wait()
+	return err
+}
+
+// ignitionHandler serves the Ignition config.
+func (s *Server) ignitionHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(IgnitionPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+s.Token)) != 1 {
+			logrus.Warnf("Refused the bootstrap Ignition config to %s, which did not send the token", r.RemoteAddr)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		logrus.Debugf("Serving the bootstrap Ignition config to %s", r.RemoteAddr)
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(s.Ignition); err != nil {
+			logrus.Warnf("Failed to serve the bootstrap Ignition config to %s: %v", r.RemoteAddr, err)
+		}
+	})
+	return mux
+}
+
+// PointerIgnition returns the Ignition config which fetches the Ignition
+// config served at the URL with the token.
+func PointerIgnition(url, token string) ([]byte, error) {
+	config := igntypes.Config{
+		Ignition: igntypes.Ignition{
+			Version: igntypes.MaxVersion.String(),
+			Config: igntypes.IgnitionConfig{
+				Replace: igntypes.Resource{
+					Source: ignutil.StrToPtr(url),
+					HTTPHeaders: igntypes.HTTPHeaders{{
+						Name:  "Authorization",
+						Value: ignutil.StrToPtr("Bearer " + token),
+					}},
+				},
+			},
+		},
+	}
+	data, err := ignition.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the pointer Ignition config")
+	}
+	return data, nil
+}
+
+// relay accepts the connections of the listener and copies them to and from
+// connections to the target until the context is done.
+func relay(ctx context.Context, l net.Listener, target string) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return errors.Wrapf(err, "failed to accept a connection on %s", l.Addr())
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer conn.Close()
+			dialer := net.Dialer{Timeout: dialTimeout}
+			upstream, err := dialer.DialContext(ctx, "tcp", target)
+			if err != nil {
+				// the bootstrap machine may not be up yet, the
+				// client retries
+				logrus.Debugf("Failed to connect to the bootstrap machine at %s: %v", target, err)
+				return
+			}
+			defer upstream.Close()
+			done := make(chan struct{}, 2)
+			go func() {
+				pipe(upstream, conn, target)
+				done <- struct{}{}
+			}()
+			go func() {
+				pipe(conn, upstream, target)
+				done <- struct{}{}
+			}()
+			for copying := 2; copying > 0; copying-- {
+				select {
+				case <-done:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+}
+
+// pipe copies src to dst. Once src is done, the write half of dst is closed
+// so that its end sees the end of the stream too. When the copy fails, both
+// connections are closed, which ends the copy in the other direction.
+func pipe(dst, src net.Conn, target string) {
+	if _, err := io.Copy(dst, src); err != nil {
+		if !errors.Is(err, net.ErrClosed) {
+			logrus.Debugf("Failed to relay a connection to the bootstrap machine at %s: %v", target, err)
+		}
+		dst.Close()
+		src.Close()
+		return
+	}
+	if tcp, ok := dst.(*net.TCPConn); ok {
+		if err := tcp.CloseWrite(); err == nil {
+			return
+		}
+	}
+	dst.Close()
+}
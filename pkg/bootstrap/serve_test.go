@@ -0,0 +1,120 @@
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeRelay(t *testing.T) {
+	upstream, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer upstream.Close()
+	go func() {
+		for {
+			conn, err := upstream.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+	port := upstream.Addr().(*net.TCPAddr).Port
+
+	// the relay listens on the port of the upstream, on another address
+	s := &Server{ListenHost: "127.0.0.2", BootstrapHost: "127.0.0.1", Ports: []int{port}}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Serve(ctx) }()
+
+	var conn net.Conn
+	for i := 0; i < 50; i++ {
+		conn, err = net.Dial("tcp", net.JoinHostPort("127.0.0.2", strconv.Itoa(port)))
+		if err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if assert.NoError(t, err) {
+		_, err = conn.Write([]byte("ping"))
+		assert.NoError(t, err)
+		buf := make([]byte, 4)
+		_, err = io.ReadFull(conn, buf)
+		assert.NoError(t, err)
+		assert.Equal(t, "ping", string(buf))
+		conn.Close()
+	}
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestIgnitionHandler(t *testing.T) {
+	s := &Server{Ignition: []byte(`{"ignition":{}}`), Token: "secret"}
+	handler := s.ignitionHandler()
+
+	cases := []struct {
+		name          string
+		authorization string
+		expect        int
+	}{
+		{name: "token", authorization: "Bearer secret", expect: http.StatusOK},
+		{name: "no token", expect: http.StatusUnauthorized},
+		{name: "wrong token", authorization: "Bearer other", expect: http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, IgnitionPath, nil)
+			if tc.authorization != "" {
+				r.Header.Set("Authorization", tc.authorization)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			assert.Equal(t, tc.expect, w.Code)
+			if tc.expect == http.StatusOK {
+				assert.Equal(t, string(s.Ignition), w.Body.String())
+			}
+		})
+	}
+}
+
+func TestPointerIgnition(t *testing.T) {
+	data, err := PointerIgnition("http://10.0.0.1:22624/bootstrap.ign", "secret")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var config struct {
+		Ignition struct {
+			Config struct {
+				Replace struct {
+					Source      string `json:"source"`
+					HTTPHeaders []struct {
+						Name  string `json:"name"`
+						Value string `json:"value"`
+					} `json:"httpHeaders"`
+				} `json:"replace"`
+			} `json:"config"`
+		} `json:"ignition"`
+	}
+	if !assert.NoError(t, json.Unmarshal(data, &config)) {
+		return
+	}
+	assert.Equal(t, "http://10.0.0.1:22624/bootstrap.ign", config.Ignition.Config.Replace.Source)
+	if assert.Len(t, config.Ignition.Config.Replace.HTTPHeaders, 1) {
+		assert.Equal(t, "Authorization", config.Ignition.Config.Replace.HTTPHeaders[0].Name)
+		assert.Equal(t, "Bearer secret", config.Ignition.Config.Replace.HTTPHeaders[0].Value)
+	}
+}
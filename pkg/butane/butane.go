@@ -0,0 +1,219 @@
+// Package butane translates Butane configs into Ignition configs. It
+// supports the part of the fcos and openshift variants which installs
+// files, directories, links, systemd units and SSH keys.
+package butane
+
+import (
+	"strings"
+
+	ignutil "github.com/coreos/ignition/v2/config/util"
+	igntypes "github.com/coreos/ignition/v2/config/v3_2/types"
+	"github.com/pkg/errors"
+	"github.com/vincent-petithory/dataurl"
+	"sigs.k8s.io/yaml"
+)
+
+// variants are the supported Butane variants.
+var variants = map[string]bool{
+	"fcos":      true,
+	"openshift": true,
+}
+
+type config struct {
+	Variant string `json:"variant"`
+	Version string `json:"version"`
+	// Metadata names the MachineConfig of the openshift variant, and is
+	// ignored.
+	Metadata *metadata `json:"metadata,omitempty"`
+	Storage  storage   `json:"storage,omitempty"`
+	Systemd  systemd   `json:"systemd,omitempty"`
+	Passwd   passwd    `json:"passwd,omitempty"`
+}
+
+type metadata struct {
+	Name   string            `json:"name,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type storage struct {
+	Files       []file      `json:"files,omitempty"`
+	Directories []directory `json:"directories,omitempty"`
+	Links       []link      `json:"links,omitempty"`
+}
+
+type node struct {
+	Path      string `json:"path"`
+	Overwrite *bool  `json:"overwrite,omitempty"`
+	User      *owner `json:"user,omitempty"`
+	Group     *owner `json:"group,omitempty"`
+}
+
+type owner struct {
+	ID   *int    `json:"id,omitempty"`
+	Name *string `json:"name,omitempty"`
+}
+
+type file struct {
+	node
+	Mode     *int       `json:"mode,omitempty"`
+	Contents *resource  `json:"contents,omitempty"`
+	Append   []resource `json:"append,omitempty"`
+}
+
+type directory struct {
+	node
+	Mode *int `json:"mode,omitempty"`
+}
+
+type link struct {
+	node
+	Target string `json:"target"`
+	Hard   *bool  `json:"hard,omitempty"`
+}
+
+type resource struct {
+	Inline       *string       `json:"inline,omitempty"`
+	Source       *string       `json:"source,omitempty"`
+	Local        *string       `json:"local,omitempty"`
+	Compression  *string       `json:"compression,omitempty"`
+	Verification *verification `json:"verification,omitempty"`
+}
+
+type verification struct {
+	Hash *string `json:"hash,omitempty"`
+}
+
+type systemd struct {
+	Units []unit `json:"units,omitempty"`
+}
+
+type unit struct {
+	Name     string   `json:"name"`
+	Enabled  *bool    `json:"enabled,omitempty"`
+	Mask     *bool    `json:"mask,omitempty"`
+	Contents *string  `json:"contents,omitempty"`
+	Dropins  []dropin `json:"dropins,omitempty"`
+}
+
+type dropin struct {
+	Name     string  `json:"name"`
+	Contents *string `json:"contents,omitempty"`
+}
+
+type passwd struct {
+	Users []user `json:"users,omitempty"`
+}
+
+type user struct {
+	Name              string   `json:"name"`
+	SSHAuthorizedKeys []string `json:"ssh_authorized_keys,omitempty"`
+}
+
+// Translate returns the Ignition config of the Butane config. It fails on
+// fields outside of the supported part of Butane, rather than ignoring
+// them.
+func Translate(data []byte) (*igntypes.Config, error) {
+	c := &config{}
+	if err := yaml.UnmarshalStrict(data, c); err != nil {
+		return nil, errors.Wrap(err, "failed to parse the Butane config")
+	}
+	if !variants[c.Variant] {
+		return nil, errors.Errorf("unsupported variant %q, must be fcos or openshift", c.Variant)
+	}
+	if c.Version == "" {
+		return nil, errors.New("version is required")
+	}
+
+	ign := &igntypes.Config{
+		Ignition: igntypes.Ignition{Version: igntypes.MaxVersion.String()},
+	}
+	for _, f := range c.Storage.Files {
+		n, err := f.node.translate()
+		if err != nil {
+			return nil, err
+		}
+		translated := igntypes.File{Node: n, FileEmbedded1: igntypes.FileEmbedded1{Mode: f.Mode}}
+		if f.Contents != nil {
+			if translated.Contents, err = f.Contents.translate(f.Path); err != nil {
+				return nil, err
+			}
+		}
+		for _, r := range f.Append {
+			res, err := r.translate(f.Path)
+			if err != nil {
+				return nil, err
+			}
+			translated.Append = append(translated.Append, res)
+		}
+		ign.Storage.Files = append(ign.Storage.Files, translated)
+	}
+	for _, d := range c.Storage.Directories {
+		n, err := d.node.translate()
+		if err != nil {
+			return nil, err
+		}
+		ign.Storage.Directories = append(ign.Storage.Directories, igntypes.Directory{Node: n, DirectoryEmbedded1: igntypes.DirectoryEmbedded1{Mode: d.Mode}})
+	}
+	for _, l := range c.Storage.Links {
+		n, err := l.node.translate()
+		if err != nil {
+			return nil, err
+		}
+		if l.Target == "" {
+			return nil, errors.Errorf("link %s has no target", l.Path)
+		}
+		ign.Storage.Links = append(ign.Storage.Links, igntypes.Link{Node: n, LinkEmbedded1: igntypes.LinkEmbedded1{Target: l.Target, Hard: l.Hard}})
+	}
+	for _, u := range c.Systemd.Units {
+		if u.Name == "" {
+			return nil, errors.New("systemd unit has no name")
+		}
+		translated := igntypes.Unit{Name: u.Name, Enabled: u.Enabled, Mask: u.Mask, Contents: u.Contents}
+		for _, d := range u.Dropins {
+			translated.Dropins = append(translated.Dropins, igntypes.Dropin{Name: d.Name, Contents: d.Contents})
+		}
+		ign.Systemd.Units = append(ign.Systemd.Units, translated)
+	}
+	for _, u := range c.Passwd.Users {
+		translated := igntypes.PasswdUser{Name: u.Name}
+		for _, key := range u.SSHAuthorizedKeys {
+			translated.SSHAuthorizedKeys = append(translated.SSHAuthorizedKeys, igntypes.SSHAuthorizedKey(key))
+		}
+		ign.Passwd.Users = append(ign.Passwd.Users, translated)
+	}
+	return ign, nil
+}
+
+func (n node) translate() (igntypes.Node, error) {
+	if !strings.HasPrefix(n.Path, "/") {
+		return igntypes.Node{}, errors.Errorf("path %q must be absolute", n.Path)
+	}
+	translated := igntypes.Node{Path: n.Path, Overwrite: n.Overwrite}
+	if n.User != nil {
+		translated.User = igntypes.NodeUser{ID: n.User.ID, Name: n.User.Name}
+	}
+	if n.Group != nil {
+		translated.Group = igntypes.NodeGroup{ID: n.Group.ID, Name: n.Group.Name}
+	}
+	return translated, nil
+}
+
+func (r resource) translate(path string) (igntypes.Resource, error) {
+	if r.Local != nil {
+		return igntypes.Resource{}, errors.Errorf("local contents of %s are not supported, use inline or source", path)
+	}
+	if r.Inline != nil && r.Source != nil {
+		return igntypes.Resource{}, errors.Errorf("contents of %s cannot be both inline and from a source", path)
+	}
+	if r.Inline != nil && r.Compression != nil {
+		return igntypes.Resource{}, errors.Errorf("inline contents of %s cannot be compressed", path)
+	}
+	translated := igntypes.Resource{Source: r.Source, Compression: r.Compression}
+	if r.Inline != nil {
+		translated.Source = ignutil.StrToPtr(dataurl.EncodeBytes([]byte(*r.Inline)))
+	}
+	if r.Verification != nil {
+		translated.Verification.Hash = r.Verification.Hash
+	}
+	return translated, nil
+}
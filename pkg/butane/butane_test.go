@@ -0,0 +1,131 @@
+package butane
+
+import (
+	"testing"
+
+	ignutil "github.com/coreos/ignition/v2/config/util"
+	igntypes "github.com/coreos/ignition/v2/config/v3_2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslate(t *testing.T) {
+	cases := []struct {
+		name     string
+		butane   string
+		expected *igntypes.Config
+		err      string
+	}{
+		{
+			name: "files, units and keys",
+			butane: `variant: fcos
+version: 1.3.0
+storage:
+  files:
+  - path: /etc/chrony.conf
+    mode: 0644
+    overwrite: true
+    contents:
+      inline: |
+        server time.example.com iburst
+  directories:
+  - path: /etc/debug
+  links:
+  - path: /etc/localtime
+    target: /usr/share/zoneinfo/UTC
+systemd:
+  units:
+  - name: debug.service
+    enabled: true
+    contents: |
+      [Service]
+      ExecStart=/bin/true
+passwd:
+  users:
+  - name: core
+    ssh_authorized_keys:
+    - ssh-ed25519 AAAA
+`,
+			expected: &igntypes.Config{
+				Ignition: igntypes.Ignition{Version: igntypes.MaxVersion.String()},
+				Storage: igntypes.Storage{
+					Files: []igntypes.File{{
+						Node: igntypes.Node{Path: "/etc/chrony.conf", Overwrite: ignutil.BoolToPtr(true)},
+						FileEmbedded1: igntypes.FileEmbedded1{
+							Mode:     ignutil.IntToPtr(0644),
+							Contents: igntypes.Resource{Source: ignutil.StrToPtr("data:text/plain;charset=utf-8;base64,c2VydmVyIHRpbWUuZXhhbXBsZS5jb20gaWJ1cnN0Cg==")},
+						},
+					}},
+					Directories: []igntypes.Directory{{Node: igntypes.Node{Path: "/etc/debug"}}},
+					Links: []igntypes.Link{{
+						Node:          igntypes.Node{Path: "/etc/localtime"},
+						LinkEmbedded1: igntypes.LinkEmbedded1{Target: "/usr/share/zoneinfo/UTC"},
+					}},
+				},
+				Systemd: igntypes.Systemd{Units: []igntypes.Unit{{
+					Name:     "debug.service",
+					Enabled:  ignutil.BoolToPtr(true),
+					Contents: ignutil.StrToPtr("[Service]\nExecStart=/bin/true\n"),
+				}}},
+				Passwd: igntypes.Passwd{Users: []igntypes.PasswdUser{{
+					Name:              "core",
+					SSHAuthorizedKeys: []igntypes.SSHAuthorizedKey{"ssh-ed25519 AAAA"},
+				}}},
+			},
+		},
+		{
+			name: "openshift variant",
+			butane: `variant: openshift
+version: 4.12.0
+metadata:
+  name: 99-worker-registry
+  labels:
+    machineconfiguration.openshift.io/role: worker
+storage:
+  files:
+  - path: /etc/pki/ca-trust/source/anchors/registry.crt
+    contents:
+      source: https://example.com/registry.crt
+`,
+			expected: &igntypes.Config{
+				Ignition: igntypes.Ignition{Version: igntypes.MaxVersion.String()},
+				Storage: igntypes.Storage{
+					Files: []igntypes.File{{
+						Node:          igntypes.Node{Path: "/etc/pki/ca-trust/source/anchors/registry.crt"},
+						FileEmbedded1: igntypes.FileEmbedded1{Contents: igntypes.Resource{Source: ignutil.StrToPtr("https://example.com/registry.crt")}},
+					}},
+				},
+			},
+		},
+		{
+			name:   "unsupported variant",
+			butane: "variant: flatcar\nversion: 1.0.0\n",
+			err:    `unsupported variant "flatcar", must be fcos or openshift`,
+		},
+		{
+			name:   "unsupported field",
+			butane: "variant: openshift\nversion: 4.12.0\nopenshift:\n  fips: true\n",
+			err:    `failed to parse the Butane config: error unmarshaling JSON: while decoding JSON: json: unknown field "openshift"`,
+		},
+		{
+			name:   "local contents",
+			butane: "variant: fcos\nversion: 1.3.0\nstorage:\n  files:\n  - path: /etc/motd\n    contents:\n      local: motd\n",
+			err:    "local contents of /etc/motd are not supported, use inline or source",
+		},
+		{
+			name:   "relative path",
+			butane: "variant: fcos\nversion: 1.3.0\nstorage:\n  directories:\n  - path: etc/debug\n",
+			err:    `path "etc/debug" must be absolute`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := Translate([]byte(tc.butane))
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, config)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// Package checkpoint records the steps of the install of a cluster which
+// completed, so that an install interrupted by a failure or a signal
+// continues from the last completed step when it is re-run, instead of
+// requiring the cluster to be destroyed and installed again.
+package checkpoint
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FileName is the name of the file of the assets directory the completed
+// steps are recorded in.
+const FileName = ".openshift_install_checkpoints.json"
+
+// Step is a completed step of the install.
+type Step struct {
+	Name string    `json:"name"`
+	Time time.Time `json:"time"`
+}
+
+// Checkpoints are the completed steps of the install of an assets
+// directory. They are safe for concurrent use, as the infrastructure may be
+// provisioned concurrently.
+type Checkpoints struct {
+	dir string
+
+	mu    sync.Mutex
+	steps []Step
+}
+
+// Exists returns whether steps of an install are recorded in the assets
+// directory, i.e. whether there is an interrupted install to resume.
+func Exists(dir string) (bool, error) {
+	_, err := os.Stat(filepath.Join(dir, FileName))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "failed to read the checkpoints file")
+	}
+	return true, nil
+}
+
+// Load returns the checkpoints of the assets directory, which have no
+// completed steps when none are recorded.
+func Load(dir string) (*Checkpoints, error) {
+	c := &Checkpoints{dir: dir}
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the checkpoints file")
+	}
+	if err := json.Unmarshal(data, &c.steps); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal the checkpoints file")
+	}
+	return c, nil
+}
+
+// Remove removes the checkpoints of the assets directory, once the install
+// completed or the cluster is destroyed.
+func Remove(dir string) error {
+	if err := os.Remove(filepath.Join(dir, FileName)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to remove the checkpoints file")
+	}
+	return nil
+}
+
+// Completed returns whether the step completed.
+func (c *Checkpoints) Completed(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, step := range c.steps {
+		if step.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Steps returns the completed steps, in the order they completed.
+func (c *Checkpoints) Steps() []Step {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Step{}, c.steps...)
+}
+
+// Complete records that the step completed. Completing a step again has no
+// effect. The checkpoints file is replaced atomically, so that an
+// interruption never leaves it partially written.
+func (c *Checkpoints) Complete(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, step := range c.steps {
+		if step.Name == name {
+			return nil
+		}
+	}
+
+	steps := append(c.steps, Step{Name: name, Time: time.Now().UTC()})
+	data, err := json.MarshalIndent(steps, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the checkpoints")
+	}
+	path := filepath.Join(c.dir, FileName)
+	if err := os.WriteFile(path+".tmp", data, 0o640); err != nil {
+		return errors.Wrap(err, "failed to write the checkpoints file")
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return errors.Wrap(err, "failed to write the checkpoints file")
+	}
+	c.steps = steps
+	return nil
+}
@@ -0,0 +1,87 @@
+package checkpoint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComplete(t *testing.T) {
+	dir := t.TempDir()
+
+	exists, err := Exists(dir)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	checkpoints, err := Load(dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.False(t, checkpoints.Completed("infrastructure/pre-terraform"))
+	assert.Empty(t, checkpoints.Steps())
+
+	assert.NoError(t, checkpoints.Complete("infrastructure/pre-terraform"))
+	assert.NoError(t, checkpoints.Complete("infrastructure/stage/cluster"))
+	// completing a step again has no effect
+	assert.NoError(t, checkpoints.Complete("infrastructure/pre-terraform"))
+
+	exists, err = Exists(dir)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	// a re-run of the install sees the steps completed by the interrupted one
+	resumed, err := Load(dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, resumed.Completed("infrastructure/pre-terraform"))
+	assert.True(t, resumed.Completed("infrastructure/stage/cluster"))
+	assert.False(t, resumed.Completed("infrastructure/stage/bootstrap"))
+	steps := resumed.Steps()
+	if assert.Len(t, steps, 2) {
+		assert.Equal(t, "infrastructure/pre-terraform", steps[0].Name)
+		assert.Equal(t, "infrastructure/stage/cluster", steps[1].Name)
+	}
+
+	assert.NoError(t, Remove(dir))
+	assert.NoError(t, Remove(dir))
+	exists, err = Exists(dir)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestCompleteConcurrently(t *testing.T) {
+	dir := t.TempDir()
+	checkpoints, err := Load(dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, checkpoints.Complete(fmt.Sprintf("infrastructure/stage/%d", i)))
+		}(i)
+	}
+	wg.Wait()
+
+	resumed, err := Load(dir)
+	if assert.NoError(t, err) {
+		assert.Len(t, resumed.Steps(), 8)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	dir := t.TempDir()
+	if !assert.NoError(t, os.WriteFile(filepath.Join(dir, FileName), []byte("{"), 0o640)) {
+		return
+	}
+	_, err := Load(dir)
+	assert.EqualError(t, err, "failed to unmarshal the checkpoints file: unexpected end of JSON input")
+}
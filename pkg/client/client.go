@@ -0,0 +1,186 @@
+// Package client drives the installer in-process. It generates the assets of
+// a cluster, provisions the cluster and destroys it, the same as the create
+// and destroy commands of openshift-install, for controllers and tools which
+// would otherwise run the binary.
+//
+// The options of a client reach the assets through the asset store, so the
+// operations of different clients run concurrently. The operations read the
+// cloud credentials from the environment and the credential files of the
+// process, as openshift-install does, unless the client has credentials of
+// its own: those are passed to the sessions of the platforms, terraform and
+// the destroyers, without changing the environment of the process.
+package client
+
+import (
+	"context"
+	"crypto"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/credentialsfile"
+	"github.com/openshift/installer/pkg/deterministic"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// CredentialsProvider provides the cloud credentials an operation runs with.
+type CredentialsProvider interface {
+	// Credentials returns the credentials of the platforms, as in a
+	// credentials file.
+	Credentials(ctx context.Context) (*credentialsfile.File, error)
+}
+
+// StaticCredentials are fixed credentials.
+type StaticCredentials credentialsfile.File
+
+// Credentials returns the credentials.
+func (c *StaticCredentials) Credentials(context.Context) (*credentialsfile.File, error) {
+	return (*credentialsfile.File)(c), nil
+}
+
+// Client runs the installer for the cluster of an assets directory.
+type Client struct {
+	dir          string
+	logger       logrus.FieldLogger
+	credentials  CredentialsProvider
+	releaseImage *types.ReleaseImage
+	source       *deterministic.Source
+
+	provenanceKey       crypto.Signer
+	policyDir           string
+	certificateValidity map[string]time.Duration
+	checkRegistryAccess bool
+	allowUnknownFields  bool
+
+	// output is where Generate writes the assets, if not the assets
+	// directory.
+	output output
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithLogger sends the logs of the client and of destroying the cluster to
+// the logger instead of the standard logrus logger. The assets and terraform
+// log to the standard logrus logger regardless.
+func WithLogger(logger logrus.FieldLogger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithCredentials runs the operations with the credentials of the provider
+// instead of those of the process.
+func WithCredentials(provider CredentialsProvider) Option {
+	return func(c *Client) {
+		c.credentials = provider
+	}
+}
+
+// WithReleaseImage installs the release image instead of the one of the
+// install-config or the one the installer is pinned to.
+func WithReleaseImage(releaseImage *types.ReleaseImage) Option {
+	return func(c *Client) {
+		c.releaseImage = releaseImage
+	}
+}
+
+// WithSource generates the assets with the random values and the time of the
+// source, so that the same install-config generates the same assets.
+func WithSource(source *deterministic.Source) Option {
+	return func(c *Client) {
+		c.source = source
+	}
+}
+
+// WithPolicyDir evaluates the install-config and the manifests against the
+// Rego and CEL policies of the directory before the Ignition configs are
+// generated. The Rego policies are evaluated with the opa command: Generate
+// fails before generating any asset when it is not installed.
+func WithPolicyDir(dir string) Option {
+	return func(c *Client) {
+		c.policyDir = dir
+	}
+}
+
+// WithCertificateValidity sets how long the certificates the installer
+// generates are valid instead of their defaults, by the base name of their
+// files in the tls directory, e.g. root-ca. A certificate cannot be valid for
+// longer than its CA.
+func WithCertificateValidity(validity map[string]time.Duration) Option {
+	return func(c *Client) {
+		c.certificateValidity = validity
+	}
+}
+
+// WithRegistryAccessChecks checks that the registry of the release image
+// can be reached from the host of the client through the proxy of the
+// install-config, and accepts the pull secret, before the cluster is
+// provisioned.
+func WithRegistryAccessChecks() Option {
+	return func(c *Client) {
+		c.checkRegistryAccess = true
+	}
+}
+
+// WithUnknownFields warns about the unknown fields of the install-config and
+// ignores them instead of rejecting them, for the install-configs written for
+// installers which did not reject them.
+func WithUnknownFields() Option {
+	return func(c *Client) {
+		c.allowUnknownFields = true
+	}
+}
+
+// New returns a client for the cluster of the assets directory, creating the
+// directory if it does not exist.
+func New(dir string, options ...Option) (*Client, error) {
+	if dir == "" {
+		return nil, errors.New("the assets directory is required")
+	}
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return nil, errors.Wrap(err, "failed to create the assets directory")
+	}
+	c := &Client{dir: dir, logger: logrus.StandardLogger()}
+	for _, option := range options {
+		option(c)
+	}
+	return c, nil
+}
+
+// Dir returns the assets directory of the client.
+func (c *Client) Dir() string {
+	return c.dir
+}
+
+// IsInstallConfigError returns whether the operation failed because the
+// install-config is invalid.
+func IsInstallConfigError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), asset.InstallConfigError)
+}
+
+// IsInfrastructureError returns whether the operation failed to provision
+// the infrastructure of the cluster.
+func IsInfrastructureError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), asset.ClusterCreationError)
+}
+
+// run runs the operation with the credentials of the client, which are nil
+// when the operation runs with those of the process.
+func (c *Client) run(ctx context.Context, operation func(credentials *credentialsfile.File) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if c.credentials == nil {
+		return operation(nil)
+	}
+	credentials, err := c.credentials.Credentials(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the credentials")
+	}
+	return operation(credentials)
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/credentialsfile"
+)
+
+func TestLogger(t *testing.T) {
+	c, err := New(t.TempDir())
+	assert.NoError(t, err)
+	assert.Equal(t, logrus.StandardLogger(), c.logger)
+
+	logger := logrus.New()
+	c, err = New(t.TempDir(), WithLogger(logger))
+	assert.NoError(t, err)
+	assert.Equal(t, logger, c.logger)
+}
+
+func TestRunCredentials(t *testing.T) {
+	credentials := &StaticCredentials{
+		AWS: &credentialsfile.AWS{AccessKeyID: "id", SecretAccessKey: "secret"},
+	}
+	c, err := New(t.TempDir(), WithCredentials(credentials))
+	assert.NoError(t, err)
+	err = c.run(context.Background(), func(got *credentialsfile.File) error {
+		assert.Equal(t, (*credentialsfile.File)(credentials), got)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	c, err = New(t.TempDir())
+	assert.NoError(t, err)
+	err = c.run(context.Background(), func(got *credentialsfile.File) error {
+		assert.Nil(t, got)
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestRunConcurrent(t *testing.T) {
+	first, err := New(t.TempDir(), WithCredentials(&StaticCredentials{
+		AWS: &credentialsfile.AWS{Profile: "first"},
+	}))
+	assert.NoError(t, err)
+	second, err := New(t.TempDir(), WithCredentials(&StaticCredentials{
+		AWS: &credentialsfile.AWS{Profile: "second"},
+	}))
+	assert.NoError(t, err)
+
+	// the operation of the first client only returns once the one of the
+	// second client runs
+	running := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- first.run(context.Background(), func(*credentialsfile.File) error {
+			select {
+			case <-running:
+				return nil
+			case <-time.After(10 * time.Second):
+				return errors.New("the operations were serialized")
+			}
+		})
+	}()
+	assert.NoError(t, second.run(context.Background(), func(*credentialsfile.File) error {
+		close(running)
+		return nil
+	}))
+	assert.NoError(t, <-done)
+}
+
+func TestRunCanceled(t *testing.T) {
+	c, err := New(t.TempDir())
+	assert.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = c.run(ctx, func(*credentialsfile.File) error {
+		t.Fatal("the operation was run")
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestErrors(t *testing.T) {
+	cases := []struct {
+		name           string
+		err            error
+		installConfig  bool
+		infrastructure bool
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name: "other",
+			err:  errors.New("failed to fetch Master Machines"),
+		},
+		{
+			name:          "install-config",
+			err:           errors.Wrap(errors.Wrap(errors.New("invalid"), asset.InstallConfigError), "failed to fetch Cluster"),
+			installConfig: true,
+		},
+		{
+			name:           "infrastructure",
+			err:            errors.Wrap(errors.New("timed out"), asset.ClusterCreationError),
+			infrastructure: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.installConfig, IsInstallConfigError(tc.err))
+			assert.Equal(t, tc.infrastructure, IsInfrastructureError(tc.err))
+		})
+	}
+}
+
+func TestNewRequiresDir(t *testing.T) {
+	_, err := New("")
+	assert.EqualError(t, err, "the assets directory is required")
+}
@@ -0,0 +1,165 @@
+package client
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+	"github.com/openshift/installer/pkg/asset/targets"
+	"github.com/openshift/installer/pkg/credentialsfile"
+	"github.com/openshift/installer/pkg/policy"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// Generate generates the assets and writes them to the assets directory,
+// along with the assets they depend on in the state file, and the provenance
+// file of the manifests and Ignition configs generated so far. The context is
+// passed to the assets which call remote services or run processes, e.g. to
+// provision the infrastructure, and checked between the assets. With
+// WithOutputDir or WithOutputArchive, the assets and the provenance file are
+// written there instead, and the state file is not updated.
+func (c *Client) Generate(ctx context.Context, assets ...asset.WritableAsset) error {
+	return c.run(ctx, func(credentials *credentialsfile.File) error {
+		if c.policyDir != "" {
+			if err := policy.Check(c.policyDir); err != nil {
+				return err
+			}
+		}
+		backend, err := assetstore.NewStateBackend(c.dir)
+		if err != nil {
+			return errors.Wrap(err, "failed to create asset store")
+		}
+		storeOptions := []assetstore.Option{
+			assetstore.WithSource(c.source),
+			assetstore.WithOptions(asset.Options{
+				PolicyDir:           c.policyDir,
+				CertificateValidity: c.certificateValidity,
+				ReleaseImage:        c.installReleaseImage(backend),
+				CheckRegistryAccess: c.checkRegistryAccess,
+				AllowUnknownFields:  c.allowUnknownFields,
+				Credentials:         credentials,
+			}),
+		}
+		out := c.output
+		if out == nil {
+			out = &dirOutput{dir: c.dir}
+		} else {
+			storeOptions = append(storeOptions, assetstore.WithReadOnlyState())
+		}
+		if archive, ok := out.(*archiveOutput); ok {
+			archive.modTime = c.source.Now().UTC()
+		}
+		store, err := assetstore.NewStoreWithBackend(c.dir, backend, storeOptions...)
+		if err != nil {
+			return errors.Wrap(err, "failed to create asset store")
+		}
+
+		err = c.generate(ctx, store, out, assets)
+		if err2 := out.close(); err2 != nil {
+			if err != nil {
+				c.logger.Error(err2)
+				return err
+			}
+			return err2
+		}
+		return err
+	})
+}
+
+func (c *Client) generate(ctx context.Context, store asset.Store, out output, assets []asset.WritableAsset) error {
+	for _, a := range assets {
+		err := store.Fetch(ctx, a, assets...)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to fetch %s", a.Name())
+		}
+
+		// the assets generated before a failure, e.g. the terraform state
+		// of the cluster, are written regardless
+		if err2 := out.write(a); err2 != nil {
+			err2 = errors.Wrapf(err2, "failed to write asset (%s) to disk", a.Name())
+			if err != nil {
+				c.logger.Error(err2)
+				return err
+			}
+			return err2
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return c.writeProvenance(store, out)
+}
+
+// CreateInstallConfig generates the install-config.
+func (c *Client) CreateInstallConfig(ctx context.Context) error {
+	return c.Generate(ctx, targets.InstallConfig...)
+}
+
+// CreateRootCASigningRequest generates the private key of the root CA and a
+// request for an external CA to sign its certificate.
+func (c *Client) CreateRootCASigningRequest(ctx context.Context) error {
+	return c.Generate(ctx, targets.RootCASigningRequest...)
+}
+
+// CreateManifests generates the manifests.
+func (c *Client) CreateManifests(ctx context.Context) error {
+	return c.Generate(ctx, targets.Manifests...)
+}
+
+// CreateIgnitionConfigs generates the Ignition configs.
+func (c *Client) CreateIgnitionConfigs(ctx context.Context) error {
+	return c.Generate(ctx, targets.IgnitionConfigs...)
+}
+
+// CreateInfraPlan plans the infrastructure of the cluster without creating
+// it, and writes the plan to infra-plan.json.
+func (c *Client) CreateInfraPlan(ctx context.Context) error {
+	return c.Generate(ctx, targets.InfraPlan...)
+}
+
+// CreateCluster provisions the infrastructure of the cluster. It returns
+// once the infrastructure is provisioned; the installation then goes on in
+// the cluster, which the caller watches with Kubeconfig.
+func (c *Client) CreateCluster(ctx context.Context) error {
+	return c.Generate(ctx, targets.Cluster...)
+}
+
+// Kubeconfig returns the path of the admin kubeconfig of the cluster.
+func (c *Client) Kubeconfig() string {
+	return filepath.Join(c.dir, "auth", "kubeconfig")
+}
+
+// installReleaseImage returns the release image of the client, or else the
+// one of the install-config of the state in the backend, if any.
+func (c *Client) installReleaseImage(backend assetstore.StateBackend) *types.ReleaseImage {
+	if c.releaseImage != nil {
+		return c.releaseImage
+	}
+	store, err := assetstore.NewStoreWithBackend(c.dir, backend, assetstore.WithReadOnlyState())
+	if err != nil {
+		c.logger.Debugf("Failed to load the install-config for its release image: %v", err)
+		return nil
+	}
+	config, err := store.Load(&installconfig.InstallConfig{})
+	if err != nil {
+		c.logger.Debugf("Failed to load the install-config for its release image: %v", err)
+		return nil
+	}
+	if config != nil && config.(*installconfig.InstallConfig).Config != nil {
+		return config.(*installconfig.InstallConfig).Config.ReleaseImage
+	}
+	return nil
+}
+
+func asFileWriter(a asset.WritableAsset) asset.FileWriter {
+	switch v := a.(type) {
+	case asset.FileWriter:
+		return v
+	default:
+		return asset.NewDefaultFileWriter(a)
+	}
+}
@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/cluster"
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+	"github.com/openshift/installer/pkg/asset/targets"
+	"github.com/openshift/installer/pkg/checkpoint"
+	"github.com/openshift/installer/pkg/credentialsfile"
+	"github.com/openshift/installer/pkg/destroy"
+	"github.com/openshift/installer/pkg/destroy/bootstrap"
+	"github.com/openshift/installer/pkg/destroy/providers"
+	quotaasset "github.com/openshift/installer/pkg/destroy/quota"
+
+	_ "github.com/openshift/installer/pkg/destroy/alibabacloud"
+	_ "github.com/openshift/installer/pkg/destroy/aws"
+	_ "github.com/openshift/installer/pkg/destroy/azure"
+	_ "github.com/openshift/installer/pkg/destroy/baremetal"
+	_ "github.com/openshift/installer/pkg/destroy/gcp"
+	_ "github.com/openshift/installer/pkg/destroy/ibmcloud"
+	_ "github.com/openshift/installer/pkg/destroy/libvirt"
+	_ "github.com/openshift/installer/pkg/destroy/nutanix"
+	_ "github.com/openshift/installer/pkg/destroy/openstack"
+	_ "github.com/openshift/installer/pkg/destroy/ovirt"
+	_ "github.com/openshift/installer/pkg/destroy/powervs"
+	_ "github.com/openshift/installer/pkg/destroy/vsphere"
+)
+
+// DestroyOptions configures DestroyCluster.
+type DestroyOptions struct {
+	providers.Options
+
+	// ReportQuota records the quota the cluster used in the assets
+	// directory.
+	ReportQuota bool
+}
+
+// DestroyBootstrap destroys the bootstrap resources of the cluster.
+func (c *Client) DestroyBootstrap(ctx context.Context) error {
+	return c.run(ctx, func(credentials *credentialsfile.File) error {
+		return bootstrap.Destroy(credentialsfile.NewContext(ctx, credentials), c.dir)
+	})
+}
+
+// DestroyCluster destroys the cluster and, unless it is a dry run, its
+// assets. It returns the report of the resources of the cluster, which is
+// returned along with the error when the cluster is not fully destroyed.
+func (c *Client) DestroyCluster(ctx context.Context, options DestroyOptions) (report *providers.Report, err error) {
+	err = c.run(ctx, func(credentials *credentialsfile.File) error {
+		if err := c.restoreMetadata(); err != nil {
+			return err
+		}
+		if credentials != nil {
+			options.Credentials = credentials
+		}
+		destroyer, r, err := destroy.NewWithOptions(c.logger, c.dir, options.Options)
+		if err != nil {
+			return errors.Wrap(err, "Failed while preparing to destroy cluster")
+		}
+		report = r
+		quota, err := destroyer.Run()
+		if err != nil {
+			report.Error = err.Error()
+			return errors.Wrap(err, "Failed to destroy cluster")
+		}
+		if options.DryRun {
+			return nil
+		}
+
+		if options.ReportQuota {
+			if err := quotaasset.WriteQuota(c.dir, quota); err != nil {
+				return errors.Wrap(err, "failed to record quota")
+			}
+		}
+		return c.destroyAssets()
+	})
+	return report, err
+}
+
+// destroyAssets removes the assets of the cluster, the state file and the
+// terraform files from the assets directory.
+func (c *Client) destroyAssets() error {
+	store, err := assetstore.NewStore(c.dir)
+	if err != nil {
+		return errors.Wrap(err, "failed to create asset store")
+	}
+	for _, asset := range append(targets.InfraPlan, targets.Cluster...) {
+		if err := store.Destroy(asset); err != nil {
+			return errors.Wrapf(err, "failed to destroy asset %q", asset.Name())
+		}
+	}
+
+	// delete the state file as well
+	err = store.DestroyState()
+	if err != nil {
+		return errors.Wrap(err, "failed to remove state file")
+	}
+
+	// an install of the destroyed cluster cannot be resumed
+	if err := checkpoint.Remove(c.dir); err != nil {
+		return err
+	}
+
+	// delete terraform files
+	tfstateFiles, err := filepath.Glob(filepath.Join(c.dir, "*.tfstate"))
+	if err != nil {
+		return errors.Wrap(err, "failed to glob for tfstate files")
+	}
+	tfvarsFiles, err := filepath.Glob(filepath.Join(c.dir, "*.tfvars.json"))
+	if err != nil {
+		return errors.Wrap(err, "failed to glob for tfvars files")
+	}
+	for _, f := range append(tfstateFiles, tfvarsFiles...) {
+		if err := os.Remove(f); err != nil {
+			return errors.Wrapf(err, "failed to remove terraform file %q", f)
+		}
+	}
+	return nil
+}
+
+// restoreMetadata writes the cluster metadata from the state file into the
+// asset directory when it is missing there, so that a cluster whose state is
+// kept in a remote backend can be destroyed from a different machine.
+func (c *Client) restoreMetadata() error {
+	if _, err := cluster.LoadMetadata(c.dir); err == nil || !os.IsNotExist(err) {
+		return nil
+	}
+	store, err := assetstore.NewStore(c.dir)
+	if err != nil {
+		return errors.Wrap(err, "failed to create asset store")
+	}
+	metadata, err := store.Load(&cluster.Metadata{})
+	if err != nil {
+		return errors.Wrap(err, "failed to load cluster metadata from the state file")
+	}
+	if metadata == nil {
+		return nil
+	}
+	c.logger.Infof("Restoring cluster metadata from the state file")
+	return errors.Wrap(asset.PersistToFile(metadata.(asset.WritableAsset), c.dir), "failed to restore cluster metadata")
+}
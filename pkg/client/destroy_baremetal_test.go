@@ -0,0 +1,12 @@
+//go:build baremetal
+// +build baremetal
+
+package client
+
+import (
+	"github.com/openshift/installer/pkg/destroy/baremetal"
+)
+
+func init() {
+	destroyers["baremetal"] = &baremetal.ClusterUninstaller{}
+}
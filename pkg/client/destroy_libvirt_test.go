@@ -0,0 +1,12 @@
+//go:build libvirt
+// +build libvirt
+
+package client
+
+import (
+	"github.com/openshift/installer/pkg/destroy/libvirt"
+)
+
+func init() {
+	destroyers["libvirt"] = &libvirt.ClusterUninstaller{}
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/destroy/alibabacloud"
+	"github.com/openshift/installer/pkg/destroy/aws"
+	"github.com/openshift/installer/pkg/destroy/azure"
+	"github.com/openshift/installer/pkg/destroy/gcp"
+	"github.com/openshift/installer/pkg/destroy/ibmcloud"
+	"github.com/openshift/installer/pkg/destroy/nutanix"
+	"github.com/openshift/installer/pkg/destroy/openstack"
+	"github.com/openshift/installer/pkg/destroy/ovirt"
+	"github.com/openshift/installer/pkg/destroy/powervs"
+	"github.com/openshift/installer/pkg/destroy/providers"
+	"github.com/openshift/installer/pkg/destroy/vsphere"
+)
+
+// destroyers are the destroyers of the registered platforms. The destroyers
+// of the platforms behind build tags are added by the tagged test files.
+var destroyers = map[string]providers.Destroyer{
+	"alibabacloud": &alibabacloud.ClusterUninstaller{},
+	"aws":          &aws.ClusterUninstaller{},
+	"azure":        &azure.ClusterUninstaller{},
+	"gcp":          &gcp.ClusterUninstaller{},
+	"ibmcloud":     &ibmcloud.ClusterUninstaller{},
+	"nutanix":      &nutanix.ClusterUninstaller{},
+	"openstack":    &openstack.ClusterUninstaller{},
+	"ovirt":        &ovirt.ClusterUninstaller{},
+	"powervs":      &powervs.ClusterUninstaller{},
+	"vsphere":      &vsphere.ClusterUninstaller{},
+}
+
+func TestDestroyersAreConfigurable(t *testing.T) {
+	registered := make([]string, 0, len(providers.Registry))
+	for platform := range providers.Registry {
+		registered = append(registered, platform)
+	}
+	tested := make([]string, 0, len(destroyers))
+	for platform := range destroyers {
+		tested = append(tested, platform)
+	}
+	sort.Strings(registered)
+	sort.Strings(tested)
+	assert.Equal(t, registered, tested, "every registered platform must be tested")
+
+	for platform, destroyer := range destroyers {
+		t.Run(platform, func(t *testing.T) {
+			assert.Implements(t, (*providers.ConfigurableDestroyer)(nil), destroyer)
+		})
+	}
+}
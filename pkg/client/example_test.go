@@ -0,0 +1,36 @@
+package client_test
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/client"
+	"github.com/openshift/installer/pkg/credentialsfile"
+)
+
+func Example() {
+	ctx := context.Background()
+	installer, err := client.New("/var/lib/installs/mycluster",
+		client.WithLogger(logrus.New()),
+		client.WithCredentials(&client.StaticCredentials{
+			AWS: &credentialsfile.AWS{
+				AccessKeyID:     "...",
+				SecretAccessKey: "...",
+			},
+		}),
+	)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	// the install-config.yaml written to the assets directory beforehand is
+	// consumed by the cluster
+	if err := installer.CreateCluster(ctx); err != nil {
+		if client.IsInstallConfigError(err) {
+			logrus.Fatalf("invalid install-config: %v", err)
+		}
+		logrus.Fatal(err)
+	}
+	logrus.Infof("Watch the installation with %s", installer.Kubeconfig())
+}
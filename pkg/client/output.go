@@ -0,0 +1,146 @@
+package client
+
+import (
+	"archive/tar"
+	"crypto"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+	"github.com/openshift/installer/pkg/provenance"
+)
+
+// WithOutputDir writes the generated assets and their provenance file to the
+// directory instead of the assets directory. The assets directory is left as
+// it was: the generated assets are not saved in its state file, and the
+// assets they consume, e.g. the install-config, are not removed from it.
+func WithOutputDir(dir string) Option {
+	return func(c *Client) {
+		c.output = &dirOutput{dir: dir}
+	}
+}
+
+// WithOutputArchive writes the generated assets and their provenance file to
+// w as a tar archive instead of the assets directory, which is left as it was,
+// as with WithOutputDir.
+func WithOutputArchive(w io.Writer) Option {
+	return func(c *Client) {
+		c.output = &archiveOutput{tw: tar.NewWriter(w)}
+	}
+}
+
+// output is where the files of the generated assets are written.
+type output interface {
+	// write writes the files of the asset.
+	write(a asset.WritableAsset) error
+	// writeProvenance writes the provenance file, signed with the key if set.
+	writeProvenance(p *provenance.Provenance, key crypto.Signer) error
+	// close completes the output once all of the files are written.
+	close() error
+}
+
+// dirOutput writes the files to a directory.
+type dirOutput struct {
+	dir string
+}
+
+func (o *dirOutput) write(a asset.WritableAsset) error {
+	a, err := encryptFiles(a)
+	if err != nil {
+		return err
+	}
+	return asFileWriter(a).PersistToFile(o.dir)
+}
+
+func (o *dirOutput) writeProvenance(p *provenance.Provenance, key crypto.Signer) error {
+	return provenance.Write(o.dir, p, key)
+}
+
+func (o *dirOutput) close() error {
+	return nil
+}
+
+// archiveOutput writes the files to a tar archive, with the modification time
+// of the provenance, so that the same assets give the same archive.
+type archiveOutput struct {
+	tw      *tar.Writer
+	modTime time.Time
+}
+
+func (o *archiveOutput) write(a asset.WritableAsset) error {
+	a, err := encryptFiles(a)
+	if err != nil {
+		return err
+	}
+	for _, f := range a.Files() {
+		if err := o.writeFile(f.Filename, f.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *archiveOutput) writeProvenance(p *provenance.Provenance, key crypto.Signer) error {
+	data, sig, err := provenance.Marshal(p, key)
+	if err != nil {
+		return err
+	}
+	if err := o.writeFile(provenance.FileName, data); err != nil {
+		return err
+	}
+	if sig == nil {
+		return nil
+	}
+	return o.writeFile(provenance.SignatureFileName, sig)
+}
+
+func (o *archiveOutput) writeFile(name string, data []byte) error {
+	header := &tar.Header{
+		Name:     name,
+		Mode:     0o640,
+		Size:     int64(len(data)),
+		ModTime:  o.modTime,
+		Typeflag: tar.TypeReg,
+	}
+	if err := o.tw.WriteHeader(header); err != nil {
+		return errors.Wrapf(err, "failed to write %s to the archive", name)
+	}
+	_, err := o.tw.Write(data)
+	return errors.Wrapf(err, "failed to write %s to the archive", name)
+}
+
+func (o *archiveOutput) close() error {
+	return errors.Wrap(o.tw.Close(), "failed to write the archive")
+}
+
+// encryptedAsset is an asset whose files are written encrypted with the
+// state, e.g. the kubeconfigs and the kubeadmin password.
+type encryptedAsset struct {
+	asset.WritableAsset
+	files []*asset.File
+}
+
+func (a *encryptedAsset) Files() []*asset.File {
+	return a.files
+}
+
+// encryptFiles returns the asset with its files as they are written, which
+// assetstore.EncryptFile encrypts when a passphrase or KMS key is set for the
+// state. The assets which write their own files are returned as they are.
+func encryptFiles(a asset.WritableAsset) (asset.WritableAsset, error) {
+	if _, ok := a.(asset.FileWriter); ok {
+		return a, nil
+	}
+	files := make([]*asset.File, 0, len(a.Files()))
+	for _, f := range a.Files() {
+		data, err := assetstore.EncryptFile(f.Filename, f.Data)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &asset.File{Filename: f.Filename, Data: data})
+	}
+	return &encryptedAsset{WritableAsset: a, files: files}, nil
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+	"github.com/openshift/installer/pkg/provenance"
+)
+
+type testOutputAsset struct{}
+
+func (a *testOutputAsset) Name() string {
+	return "test"
+}
+
+func (a *testOutputAsset) Dependencies() []asset.Asset {
+	return nil
+}
+
+func (a *testOutputAsset) Generate(asset.Parents) error {
+	return nil
+}
+
+func (a *testOutputAsset) Files() []*asset.File {
+	return []*asset.File{
+		{Filename: "manifests/a.yaml", Data: []byte("a")},
+		{Filename: "b.ign", Data: []byte("b")},
+	}
+}
+
+func (a *testOutputAsset) Load(asset.FileFetcher) (bool, error) {
+	return false, nil
+}
+
+func TestArchiveOutput(t *testing.T) {
+	buf := &bytes.Buffer{}
+	c, err := New(t.TempDir(), WithOutputArchive(buf))
+	if !assert.NoError(t, err) {
+		return
+	}
+	out := c.output.(*archiveOutput)
+	out.modTime = time.Unix(0, 0)
+
+	assert.NoError(t, out.write(&testOutputAsset{}))
+	assert.NoError(t, out.writeProvenance(&provenance.Provenance{}, nil))
+	assert.NoError(t, out.close())
+
+	files := map[string]string{}
+	tr := tar.NewReader(buf)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			return
+		}
+		data, err := io.ReadAll(tr)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Unix(0, 0), header.ModTime)
+		files[header.Name] = string(data)
+	}
+	assert.Equal(t, "a", files["manifests/a.yaml"])
+	assert.Equal(t, "b", files["b.ign"])
+	assert.Contains(t, files, provenance.FileName)
+	assert.NotContains(t, files, provenance.SignatureFileName)
+}
+
+type testAuthAsset struct {
+	testOutputAsset
+}
+
+func (a *testAuthAsset) Files() []*asset.File {
+	return []*asset.File{
+		{Filename: "auth/kubeadmin-password", Data: []byte("secret")},
+		{Filename: "metadata.json", Data: []byte("{}")},
+	}
+}
+
+func TestDirOutputEncryptsAuth(t *testing.T) {
+	t.Setenv("OPENSHIFT_INSTALL_STATE_PASSPHRASE", "passphrase")
+	dir := t.TempDir()
+	out := &dirOutput{dir: dir}
+	assert.NoError(t, out.write(&testAuthAsset{}))
+
+	data, err := os.ReadFile(filepath.Join(dir, "auth", "kubeadmin-password"))
+	if assert.NoError(t, err) {
+		assert.True(t, assetstore.Encrypted(data))
+		assert.NotContains(t, string(data), "secret")
+	}
+	data, err = assetstore.ReadFile(filepath.Join(dir, "auth", "kubeadmin-password"))
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", string(data))
+
+	data, err = os.ReadFile(filepath.Join(dir, "metadata.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", string(data))
+}
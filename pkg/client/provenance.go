@@ -0,0 +1,72 @@
+package client
+
+import (
+	"crypto"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/ignition/bootstrap"
+	"github.com/openshift/installer/pkg/asset/ignition/machine"
+	"github.com/openshift/installer/pkg/asset/machines"
+	"github.com/openshift/installer/pkg/asset/manifests"
+	"github.com/openshift/installer/pkg/asset/releaseimage"
+	"github.com/openshift/installer/pkg/provenance"
+	"github.com/openshift/installer/pkg/version"
+)
+
+// provenanceAssets are the assets whose files the provenance file lists:
+// the manifests and the Ignition configs the cluster boots from.
+var provenanceAssets = []asset.WritableAsset{
+	&machines.Master{},
+	&machines.Worker{},
+	&manifests.Manifests{},
+	&manifests.Openshift{},
+	&bootstrap.Bootstrap{},
+	&bootstrap.SingleNodeBootstrapInPlace{},
+	&machine.Master{},
+	&machine.Worker{},
+}
+
+// WithProvenanceKey signs the provenance file of the generated manifests and
+// Ignition configs with the key.
+func WithProvenanceKey(key crypto.Signer) Option {
+	return func(c *Client) {
+		c.provenanceKey = key
+	}
+}
+
+// writeProvenance writes the provenance file of the manifests and Ignition
+// configs in the store, if any were generated. They are listed whether or
+// not they are still in the assets directory, as the manifests are consumed
+// by the Ignition configs and the Ignition configs by the cluster.
+func (c *Client) writeProvenance(store asset.Store, out output) error {
+	p := &provenance.Provenance{Created: c.source.Now().UTC()}
+	for _, a := range provenanceAssets {
+		loaded, err := store.Load(a)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load %s", a.Name())
+		}
+		if loaded == nil {
+			continue
+		}
+		for _, f := range loaded.(asset.WritableAsset).Files() {
+			p.Add(f.Filename, f.Data)
+		}
+	}
+	if len(p.Files) == 0 {
+		return nil
+	}
+
+	p.Version, _ = version.Version()
+	if image, err := store.Load(&releaseimage.Image{}); err == nil && image != nil {
+		p.ReleaseImage = image.(*releaseimage.Image).PullSpec
+	}
+	if err := out.writeProvenance(p, c.provenanceKey); err != nil {
+		return err
+	}
+	if c.provenanceKey != nil {
+		c.logger.Infof("Signed the provenance of %d generated files in %s", len(p.Files), provenance.FileName)
+	}
+	return nil
+}
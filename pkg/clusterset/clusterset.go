@@ -0,0 +1,141 @@
+// Package clusterset creates a set of clusters concurrently, each in its own
+// assets directory, for fleet and lab provisioning without an external
+// orchestrator.
+package clusterset
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+// installConfigFilename is the name of the install-config in an assets
+// directory.
+const installConfigFilename = "install-config.yaml"
+
+// ClusterSet is the configuration of a set of clusters.
+type ClusterSet struct {
+	// Parallelism is how many clusters are created at a time. It defaults
+	// to all of them.
+	// +optional
+	Parallelism int `json:"parallelism,omitempty"`
+
+	// ImageCacheDir is the directory of the cache of the images the installs
+	// download, which they share. It defaults to the cache of the user.
+	// +optional
+	ImageCacheDir string `json:"imageCacheDir,omitempty"`
+
+	// Clusters are the clusters of the set.
+	Clusters []Cluster `json:"clusters"`
+}
+
+// Cluster is a cluster of a set.
+type Cluster struct {
+	// Name is the name of the cluster in the reports of the set.
+	Name string `json:"name"`
+
+	// Dir is the assets directory of the cluster. It defaults to the name.
+	// +optional
+	Dir string `json:"dir,omitempty"`
+
+	// InstallConfig is the install-config of the cluster, which is copied to
+	// its assets directory unless the directory has one already.
+	// +optional
+	InstallConfig string `json:"installConfig,omitempty"`
+
+	// CredentialsFile is the file of the cloud credentials of the install,
+	// so that the clusters of a set may be created in different accounts.
+	// It defaults to the credentials of the environment and of the user.
+	// +optional
+	CredentialsFile string `json:"credentialsFile,omitempty"`
+}
+
+// Load loads the cluster set of the file. The relative paths of the set are
+// relative to the directory of the file.
+func Load(path string) (*ClusterSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the cluster set")
+	}
+	set := &ClusterSet{}
+	if err := yaml.UnmarshalStrict(data, set); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal the cluster set %s", path)
+	}
+
+	base := filepath.Dir(path)
+	resolve := func(p string) string {
+		if p == "" || filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(base, p)
+	}
+	set.ImageCacheDir = resolve(set.ImageCacheDir)
+	for i := range set.Clusters {
+		c := &set.Clusters[i]
+		if c.Dir == "" {
+			c.Dir = c.Name
+		}
+		c.Dir = resolve(c.Dir)
+		c.InstallConfig = resolve(c.InstallConfig)
+		c.CredentialsFile = resolve(c.CredentialsFile)
+	}
+	if set.Parallelism == 0 {
+		set.Parallelism = len(set.Clusters)
+	}
+
+	if err := set.validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid cluster set %s", path)
+	}
+	return set, nil
+}
+
+func (s *ClusterSet) validate() error {
+	if len(s.Clusters) == 0 {
+		return errors.New("clusters: at least one cluster is required")
+	}
+	if s.Parallelism < 0 {
+		return errors.Errorf("parallelism: %d must not be negative", s.Parallelism)
+	}
+	names := sets.NewString()
+	dirs := sets.NewString()
+	for i, c := range s.Clusters {
+		if c.Name == "" {
+			return errors.Errorf("clusters[%d].name: a name is required", i)
+		}
+		if names.Has(c.Name) {
+			return errors.Errorf("clusters[%d].name: duplicate name %q", i, c.Name)
+		}
+		names.Insert(c.Name)
+		dir := filepath.Clean(c.Dir)
+		if dirs.Has(dir) {
+			return errors.Errorf("clusters[%d].dir: %s is the assets directory of another cluster", i, c.Dir)
+		}
+		dirs.Insert(dir)
+	}
+	return nil
+}
+
+// Prepare creates the assets directory of the cluster, and copies its
+// install-config to it unless the directory has one already or an install
+// in it started, i.e. it has a state file.
+func Prepare(c Cluster) error {
+	if err := os.MkdirAll(c.Dir, 0o750); err != nil {
+		return errors.Wrapf(err, "failed to create the assets directory of %s", c.Name)
+	}
+	if c.InstallConfig == "" {
+		return nil
+	}
+	for _, name := range []string{installConfigFilename, ".openshift_install_state.json"} {
+		if _, err := os.Stat(filepath.Join(c.Dir, name)); err == nil {
+			return nil
+		}
+	}
+	data, err := os.ReadFile(c.InstallConfig)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the install-config of %s", c.Name)
+	}
+	return errors.Wrapf(os.WriteFile(filepath.Join(c.Dir, installConfigFilename), data, 0o640), "failed to copy the install-config of %s", c.Name)
+}
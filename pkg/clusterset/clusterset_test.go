@@ -0,0 +1,147 @@
+package clusterset
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	cases := []struct {
+		name     string
+		config   string
+		expected func(dir string) *ClusterSet
+		err      string
+	}{
+		{
+			name: "defaults",
+			config: `clusters:
+- name: lab-1
+  installConfig: configs/lab-1.yaml
+  credentialsFile: credentials/lab-1.yaml
+- name: lab-2
+  dir: /srv/lab-2
+`,
+			expected: func(dir string) *ClusterSet {
+				return &ClusterSet{
+					Parallelism: 2,
+					Clusters: []Cluster{
+						{Name: "lab-1", Dir: filepath.Join(dir, "lab-1"), InstallConfig: filepath.Join(dir, "configs/lab-1.yaml"), CredentialsFile: filepath.Join(dir, "credentials/lab-1.yaml")},
+						{Name: "lab-2", Dir: "/srv/lab-2"},
+					},
+				}
+			},
+		},
+		{
+			name: "parallelism and image cache",
+			config: `parallelism: 1
+imageCacheDir: cache
+clusters:
+- name: lab-1
+`,
+			expected: func(dir string) *ClusterSet {
+				return &ClusterSet{
+					Parallelism:   1,
+					ImageCacheDir: filepath.Join(dir, "cache"),
+					Clusters:      []Cluster{{Name: "lab-1", Dir: filepath.Join(dir, "lab-1")}},
+				}
+			},
+		},
+		{
+			name:   "no clusters",
+			config: `parallelism: 2`,
+			err:    "clusters: at least one cluster is required",
+		},
+		{
+			name: "negative parallelism",
+			config: `parallelism: -1
+clusters:
+- name: lab-1
+`,
+			err: "parallelism: -1 must not be negative",
+		},
+		{
+			name: "missing name",
+			config: `clusters:
+- dir: lab-1
+`,
+			err: "clusters[0].name: a name is required",
+		},
+		{
+			name: "duplicate name",
+			config: `clusters:
+- name: lab-1
+- name: lab-1
+  dir: other
+`,
+			err: `clusters[1].name: duplicate name "lab-1"`,
+		},
+		{
+			name: "shared dir",
+			config: `clusters:
+- name: lab-1
+- name: lab-2
+  dir: lab-1/
+`,
+			err: "clusters[1].dir: ",
+		},
+		{
+			name: "unknown field",
+			config: `clusters:
+- name: lab-1
+  region: us-east-1
+`,
+			err: `unknown field "region"`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "clusterset.yaml")
+			if !assert.NoError(t, os.WriteFile(path, []byte(tc.config), 0o600)) {
+				return
+			}
+			set, err := Load(path)
+			if tc.err != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.err)
+				}
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expected(dir), set)
+			}
+		})
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	dir := t.TempDir()
+	installConfig := filepath.Join(dir, "lab-1.yaml")
+	if !assert.NoError(t, os.WriteFile(installConfig, []byte("metadata:\n  name: lab-1\n"), 0o600)) {
+		return
+	}
+	c := Cluster{Name: "lab-1", Dir: filepath.Join(dir, "lab-1"), InstallConfig: installConfig}
+
+	if !assert.NoError(t, Prepare(c)) {
+		return
+	}
+	data, err := os.ReadFile(filepath.Join(c.Dir, installConfigFilename))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "metadata:\n  name: lab-1\n", string(data))
+	}
+
+	// the install-config of the assets directory is left as it is, e.g.
+	// when the install is re-run
+	if !assert.NoError(t, os.WriteFile(filepath.Join(c.Dir, installConfigFilename), []byte("edited"), 0o600)) {
+		return
+	}
+	if assert.NoError(t, Prepare(c)) {
+		data, err := os.ReadFile(filepath.Join(c.Dir, installConfigFilename))
+		if assert.NoError(t, err) {
+			assert.Equal(t, "edited", string(data))
+		}
+	}
+}
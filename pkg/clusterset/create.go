@@ -0,0 +1,161 @@
+package clusterset
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/openshift/installer/pkg/metrics/progress"
+	"github.com/openshift/installer/pkg/metrics/timer"
+)
+
+// State is the state of the install of a cluster of a set.
+type State string
+
+const (
+	// StatePending indicates that the install did not start.
+	StatePending State = "pending"
+	// StateRunning indicates that the install is running.
+	StateRunning State = "running"
+	// StateSucceeded indicates that the install succeeded.
+	StateSucceeded State = "succeeded"
+	// StateFailed indicates that the install failed.
+	StateFailed State = "failed"
+)
+
+// Status is the status of the install of a cluster of a set.
+type Status struct {
+	Name  string
+	Dir   string
+	State State
+	// Phase is the phase of the install which is running or which was
+	// running when the install ended.
+	Phase string
+	// Percent is the completion of the phase, when it reports one.
+	Percent *int
+	// Message is the last message of the phase.
+	Message  string
+	Started  time.Time
+	Finished time.Time
+	Err      error
+}
+
+// RunFunc runs the install of the cluster, writing its progress events to
+// events as JSON lines.
+type RunFunc func(ctx context.Context, cluster Cluster, events io.Writer) error
+
+// Create creates the clusters of the set, at most Parallelism at a time,
+// with run. report is called with the status of a cluster whenever it
+// changes, and may be called concurrently. Once the context is canceled, no
+// more installs are started. Create returns the statuses of the clusters,
+// in the order of the set.
+func Create(ctx context.Context, set *ClusterSet, run RunFunc, report func(Status)) []Status {
+	statuses := make([]Status, len(set.Clusters))
+	for i, c := range set.Clusters {
+		statuses[i] = Status{Name: c.Name, Dir: c.Dir, State: StatePending}
+	}
+
+	var mu sync.Mutex
+	update := func(i int, f func(*Status)) {
+		mu.Lock()
+		f(&statuses[i])
+		status := statuses[i]
+		mu.Unlock()
+		report(status)
+	}
+
+	parallelism := set.Parallelism
+	if parallelism < 1 {
+		parallelism = len(set.Clusters)
+	}
+	slots := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, c := range set.Clusters {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int, c Cluster) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			update(i, func(s *Status) {
+				s.State = StateRunning
+				s.Started = time.Now()
+			})
+			err := install(ctx, c, run, func(event progress.Event) {
+				update(i, func(s *Status) { applyEvent(s, event) })
+			})
+			update(i, func(s *Status) {
+				s.Finished = time.Now()
+				s.Err = err
+				s.State = StateSucceeded
+				if err != nil {
+					s.State = StateFailed
+				}
+			})
+		}(i, c)
+	}
+	wg.Wait()
+	return statuses
+}
+
+// install prepares the assets directory of the cluster and runs its install,
+// calling onEvent with its progress events.
+func install(ctx context.Context, c Cluster, run RunFunc, onEvent func(progress.Event)) error {
+	if err := Prepare(c); err != nil {
+		return err
+	}
+
+	r, w := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			var event progress.Event
+			// events which cannot be read are left out of the reports
+			if err := json.Unmarshal(scanner.Bytes(), &event); err == nil {
+				onEvent(event)
+			}
+		}
+		// drain the events, so that the install never blocks on them
+		io.Copy(io.Discard, r)
+	}()
+	err := run(ctx, c, w)
+	w.Close()
+	<-done
+	return err
+}
+
+// applyEvent updates the status with the progress event. The total time of
+// the install is not a phase of its own.
+func applyEvent(s *Status, event progress.Event) {
+	if event.Phase == timer.TotalTimeElapsed {
+		return
+	}
+	switch event.Type {
+	case progress.PhaseStartedEvent:
+		s.Phase = event.Phase
+		s.Percent = nil
+		s.Message = ""
+	case progress.PhaseCompletedEvent:
+		if event.Phase == s.Phase {
+			s.Message = "completed"
+		}
+	case progress.ProgressEvent, progress.StatusEvent:
+		if event.Phase != "" {
+			s.Phase = event.Phase
+		}
+		if event.Percent != nil {
+			s.Percent = event.Percent
+		}
+		s.Message = event.Message
+	}
+}
@@ -0,0 +1,166 @@
+package clusterset
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSet returns a set of n clusters, lab-1 to lab-n, in dir.
+func newSet(dir string, n int, parallelism int) *ClusterSet {
+	set := &ClusterSet{Parallelism: parallelism}
+	for i := 1; i <= n; i++ {
+		name := fmt.Sprintf("lab-%d", i)
+		set.Clusters = append(set.Clusters, Cluster{Name: name, Dir: filepath.Join(dir, name)})
+	}
+	return set
+}
+
+func TestCreateParallelism(t *testing.T) {
+	cases := []struct {
+		parallelism int
+		expected    int
+	}{
+		{parallelism: 1, expected: 1},
+		{parallelism: 2, expected: 2},
+		{parallelism: 3, expected: 3},
+		{parallelism: 5, expected: 5},
+		{parallelism: 8, expected: 5},
+		{parallelism: 0, expected: 5},
+	}
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("parallelism %d", tc.parallelism), func(t *testing.T) {
+			set := newSet(t.TempDir(), 5, tc.parallelism)
+
+			var mu sync.Mutex
+			running, maxRunning, finished := 0, 0, 0
+			run := func(ctx context.Context, c Cluster, events io.Writer) error {
+				mu.Lock()
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				mu.Unlock()
+				defer func() {
+					mu.Lock()
+					running--
+					finished++
+					mu.Unlock()
+				}()
+
+				// the install waits for the installs which can run along
+				// with it to start, so that the bound is reached
+				deadline := time.Now().Add(10 * time.Second)
+				for {
+					mu.Lock()
+					full := running >= tc.expected || running >= len(set.Clusters)-finished
+					mu.Unlock()
+					if full {
+						return nil
+					}
+					if time.Now().After(deadline) {
+						return errors.New("the other installs did not start")
+					}
+					time.Sleep(time.Millisecond)
+				}
+			}
+			statuses := Create(context.Background(), set, run, func(Status) {})
+
+			assert.Equal(t, tc.expected, maxRunning)
+			for _, s := range statuses {
+				assert.Equal(t, StateSucceeded, s.State, s.Name)
+				assert.NoError(t, s.Err, s.Name)
+			}
+		})
+	}
+}
+
+func TestCreateFailure(t *testing.T) {
+	for failing := 0; failing < 3; failing++ {
+		t.Run(fmt.Sprintf("lab-%d fails", failing+1), func(t *testing.T) {
+			set := newSet(t.TempDir(), 3, 2)
+			run := func(ctx context.Context, c Cluster, events io.Writer) error {
+				fmt.Fprintln(events, `{"type":"phaseStarted","phase":"Total"}`)
+				fmt.Fprintln(events, `{"type":"phaseStarted","phase":"Bootstrap Complete"}`)
+				fmt.Fprintln(events, `not an event`)
+				fmt.Fprintln(events, `{"type":"status","phase":"Bootstrap Complete","message":"waiting for the API"}`)
+				if c.Name == set.Clusters[failing].Name {
+					return errors.Errorf("bootstrap of %s failed", c.Name)
+				}
+				fmt.Fprintln(events, `{"type":"phaseStarted","phase":"Cluster Operators"}`)
+				fmt.Fprintln(events, `{"type":"progress","phase":"Cluster Operators","percent":100,"message":"done"}`)
+				return nil
+			}
+
+			var mu sync.Mutex
+			var reported []Status
+			statuses := Create(context.Background(), set, run, func(s Status) {
+				mu.Lock()
+				defer mu.Unlock()
+				reported = append(reported, s)
+			})
+
+			if !assert.Len(t, statuses, len(set.Clusters)) {
+				return
+			}
+			// the failure of an install does not stop the others
+			for i, s := range statuses {
+				assert.Equal(t, set.Clusters[i].Name, s.Name)
+				assert.DirExists(t, s.Dir)
+				assert.False(t, s.Started.IsZero())
+				assert.False(t, s.Finished.IsZero())
+
+				if i == failing {
+					assert.Equal(t, StateFailed, s.State)
+					assert.Equal(t, "Bootstrap Complete", s.Phase)
+					assert.Nil(t, s.Percent)
+					assert.Equal(t, "waiting for the API", s.Message)
+					assert.EqualError(t, s.Err, fmt.Sprintf("bootstrap of %s failed", s.Name))
+					continue
+				}
+				assert.Equal(t, StateSucceeded, s.State)
+				assert.Equal(t, "Cluster Operators", s.Phase)
+				if assert.NotNil(t, s.Percent) {
+					assert.Equal(t, 100, *s.Percent)
+				}
+				assert.Equal(t, "done", s.Message)
+				assert.NoError(t, s.Err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if assert.NotEmpty(t, reported) {
+				last := map[string]Status{}
+				for _, s := range reported {
+					last[s.Name] = s
+				}
+				assert.Equal(t, StateFailed, last[set.Clusters[failing].Name].State, "the failure was not reported")
+			}
+		})
+	}
+}
+
+func TestCreateCanceled(t *testing.T) {
+	dir := t.TempDir()
+	set := newSet(dir, 2, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	run := func(ctx context.Context, c Cluster, events io.Writer) error {
+		// the running install completes once canceled
+		cancel()
+		return nil
+	}
+	statuses := Create(ctx, set, run, func(Status) {})
+
+	assert.Equal(t, StateSucceeded, statuses[0].State)
+	assert.Equal(t, StatePending, statuses[1].State)
+	assert.NoDirExists(t, statuses[1].Dir)
+}
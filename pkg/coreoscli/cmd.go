@@ -2,23 +2,82 @@ package coreoscli
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"sort"
 
+	"github.com/coreos/stream-metadata-go/arch"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/installer/pkg/rhcos"
+	"github.com/openshift/installer/pkg/rhcos/cache"
+	"github.com/openshift/installer/pkg/types"
 )
 
+var printStreamOpts streamOptions
+
+var downloadOpts struct {
+	platform     string
+	architecture string
+	format       string
+}
+
 // printStreamJSON is the implementation of print-stream-json
 func printStreamJSON(cmd *cobra.Command, _ []string) error {
 	streamData, err := rhcos.FetchRawCoreOSStream(context.Background())
 	if err != nil {
 		return err
 	}
+	streamData, err = filterStream(streamData, printStreamOpts)
+	if err != nil {
+		return err
+	}
 	os.Stdout.Write(streamData)
 	return nil
 }
 
+// download is the implementation of download
+func download(cmd *cobra.Command, _ []string) error {
+	st, err := rhcos.FetchCoreOSBuild(context.Background())
+	if err != nil {
+		return err
+	}
+	archName := arch.RpmArch(downloadOpts.architecture)
+	streamArch, err := st.GetArchitecture(archName)
+	if err != nil {
+		return err
+	}
+	artifacts, ok := streamArch.Artifacts[downloadOpts.platform]
+	if !ok {
+		var platforms []string
+		for platform := range streamArch.Artifacts {
+			platforms = append(platforms, platform)
+		}
+		sort.Strings(platforms)
+		return errors.Errorf("%s: no %s build found, only %v", st.FormatPrefix(archName), downloadOpts.platform, platforms)
+	}
+
+	var imageURL string
+	if downloadOpts.format == "" {
+		imageURL, err = rhcos.FindArtifactURL(artifacts)
+	} else if format, ok := artifacts.Formats[downloadOpts.format]; ok && format.Disk != nil {
+		imageURL, err = rhcos.FormatURLWithIntegrity(format.Disk)
+	} else {
+		err = errors.Errorf("%s: no %s disk image found for %s", st.FormatPrefix(archName), downloadOpts.format, downloadOpts.platform)
+	}
+	if err != nil {
+		return err
+	}
+
+	path, err := cache.DownloadImageFile(imageURL)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stdout, path)
+	return nil
+}
+
 // NewCmd returns a subcommand for explain
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -36,7 +95,27 @@ func NewCmd() *cobra.Command {
 		Args:  cobra.ExactArgs(0),
 		RunE:  printStreamJSON,
 	}
+	printStreamCmd.Flags().StringVar(&printStreamOpts.architecture, "arch", "", "print only the boot images of the architecture, e.g. x86_64 or amd64")
+	printStreamCmd.Flags().StringVar(&printStreamOpts.platform, "platform", "", "print only the boot images of the platform, e.g. openstack or aws")
+	printStreamCmd.Flags().StringVar(&printStreamOpts.mirrorBaseURL, "mirror-base-url", "", "rewrite the artifact locations to files of the same name below the URL of a mirror")
 	cmd.AddCommand(printStreamCmd)
 
+	downloadCmd := &cobra.Command{
+		Use:   "download",
+		Short: "Downloads a CoreOS boot image into the image cache",
+		Long: fmt.Sprintf(`Downloads a CoreOS boot image into the image cache, verifying its checksum, and prints its path.
+
+Installs reuse the cached images instead of downloading them again. The cache
+is kept in the directory set by %s, and the least recently used
+images are evicted when it grows beyond the size set by %s.`, cache.CacheDirEnv, cache.CacheMaxSizeEnv),
+		Args: cobra.ExactArgs(0),
+		RunE: download,
+	}
+	downloadCmd.Flags().StringVar(&downloadOpts.platform, "platform", "", "platform of the image in the stream metadata, e.g. openstack, qemu, vmware or ibmcloud")
+	downloadCmd.Flags().StringVar(&downloadOpts.architecture, "arch", types.ArchitectureAMD64, "architecture of the image, e.g. amd64 or x86_64")
+	downloadCmd.Flags().StringVar(&downloadOpts.format, "format", "", "format of the image, e.g. qcow2.gz or ova, when the platform has several")
+	downloadCmd.MarkFlagRequired("platform")
+	cmd.AddCommand(downloadCmd)
+
 	return cmd
 }
@@ -0,0 +1,119 @@
+package coreoscli
+
+import (
+	"encoding/json"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/coreos/stream-metadata-go/arch"
+	"github.com/pkg/errors"
+)
+
+// streamOptions select the parts of the stream metadata to print and where
+// its artifacts are served from.
+type streamOptions struct {
+	// architecture keeps only the architecture, given either as a Go or an
+	// RPM architecture name.
+	architecture string
+	// platform keeps only the artifacts, images and extensions of the
+	// platform.
+	platform string
+	// mirrorBaseURL rewrites the artifact locations to the file of the same
+	// name below it.
+	mirrorBaseURL string
+}
+
+// filterStream returns the stream metadata restricted and rewritten as the
+// options say. The metadata is handled as plain JSON so that fields unknown
+// to the installer are kept.
+func filterStream(data []byte, options streamOptions) ([]byte, error) {
+	if options == (streamOptions{}) {
+		return data, nil
+	}
+	if options.mirrorBaseURL != "" {
+		if u, err := url.Parse(options.mirrorBaseURL); err != nil || u.Scheme == "" || (u.Host == "" && u.Scheme != "file") {
+			return nil, errors.Errorf("invalid mirror base URL %q", options.mirrorBaseURL)
+		}
+	}
+
+	var st map[string]interface{}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, errors.Wrap(err, "failed to parse CoreOS stream metadata")
+	}
+	architectures, _ := st["architectures"].(map[string]interface{})
+
+	if options.architecture != "" {
+		archName := arch.RpmArch(options.architecture)
+		if _, ok := architectures[archName]; !ok {
+			return nil, errors.Errorf("no %s architecture in the CoreOS stream metadata", archName)
+		}
+		for name := range architectures {
+			if name != archName {
+				delete(architectures, name)
+			}
+		}
+	}
+
+	for name, a := range architectures {
+		streamArch, _ := a.(map[string]interface{})
+		if options.platform != "" {
+			found := false
+			for _, key := range []string{"artifacts", "images", "rhel-coreos-extensions"} {
+				entries, _ := streamArch[key].(map[string]interface{})
+				for entry := range entries {
+					if entry != options.platform && !strings.HasPrefix(entry, options.platform+"-") {
+						delete(entries, entry)
+					}
+				}
+				found = found || len(entries) > 0
+			}
+			if !found {
+				delete(architectures, name)
+				continue
+			}
+		}
+		if options.mirrorBaseURL != "" {
+			if err := rewriteLocations(streamArch["artifacts"], options.mirrorBaseURL); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if options.platform != "" && len(architectures) == 0 {
+		return nil, errors.Errorf("no %s boot images in the CoreOS stream metadata", options.platform)
+	}
+
+	out, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// rewriteLocations replaces the location and signature URLs found anywhere
+// in the artifacts with the file of the same name below the mirror.
+func rewriteLocations(artifacts interface{}, mirrorBaseURL string) error {
+	switch v := artifacts.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if s, ok := value.(string); ok && (key == "location" || key == "signature") {
+				u, err := url.Parse(s)
+				if err != nil {
+					return errors.Wrapf(err, "invalid artifact %s %q", key, s)
+				}
+				v[key] = strings.TrimSuffix(mirrorBaseURL, "/") + "/" + path.Base(u.Path)
+				continue
+			}
+			if err := rewriteLocations(value, mirrorBaseURL); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, value := range v {
+			if err := rewriteLocations(value, mirrorBaseURL); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package coreoscli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testStream = `{
+  "stream": "rhcos-4.12",
+  "architectures": {
+    "aarch64": {
+      "artifacts": {
+        "openstack": {"release": "412.86", "formats": {"qcow2.gz": {"disk": {"location": "https://rhcos.mirror.openshift.com/art/412.86/aarch64/rhcos-openstack.aarch64.qcow2.gz", "sha256": "a"}}}}
+      }
+    },
+    "x86_64": {
+      "artifacts": {
+        "openstack": {"release": "412.86", "formats": {"qcow2.gz": {"disk": {"location": "https://rhcos.mirror.openshift.com/art/412.86/x86_64/rhcos-openstack.x86_64.qcow2.gz", "signature": "https://rhcos.mirror.openshift.com/art/412.86/x86_64/rhcos-openstack.x86_64.qcow2.gz.sig", "sha256": "b"}}}},
+        "vmware": {"release": "412.86", "formats": {"ova": {"disk": {"location": "https://rhcos.mirror.openshift.com/art/412.86/x86_64/rhcos-vmware.x86_64.ova", "sha256": "c"}}}}
+      },
+      "images": {
+        "gcp": {"project": "rhcos-cloud", "name": "rhcos-412-86"}
+      },
+      "rhel-coreos-extensions": {
+        "azure-disk": {"release": "412.86", "url": "https://rhcos.blob.core.windows.net/imagebucket/rhcos-412.86.vhd"}
+      }
+    }
+  }
+}`
+
+func TestFilterStream(t *testing.T) {
+	cases := []struct {
+		name          string
+		options       streamOptions
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "no options",
+			expected: testStream,
+		},
+		{
+			name:    "architecture and platform",
+			options: streamOptions{architecture: "amd64", platform: "openstack"},
+			expected: `{
+  "architectures": {
+    "x86_64": {
+      "artifacts": {
+        "openstack": {
+          "formats": {
+            "qcow2.gz": {
+              "disk": {
+                "location": "https://rhcos.mirror.openshift.com/art/412.86/x86_64/rhcos-openstack.x86_64.qcow2.gz",
+                "sha256": "b",
+                "signature": "https://rhcos.mirror.openshift.com/art/412.86/x86_64/rhcos-openstack.x86_64.qcow2.gz.sig"
+              }
+            }
+          },
+          "release": "412.86"
+        }
+      },
+      "images": {},
+      "rhel-coreos-extensions": {}
+    }
+  },
+  "stream": "rhcos-4.12"
+}
+`,
+		},
+		{
+			name:    "platform with an image only",
+			options: streamOptions{platform: "gcp"},
+			expected: `{
+  "architectures": {
+    "x86_64": {
+      "artifacts": {},
+      "images": {
+        "gcp": {
+          "name": "rhcos-412-86",
+          "project": "rhcos-cloud"
+        }
+      },
+      "rhel-coreos-extensions": {}
+    }
+  },
+  "stream": "rhcos-4.12"
+}
+`,
+		},
+		{
+			name:    "mirror",
+			options: streamOptions{architecture: "aarch64", mirrorBaseURL: "https://mirror.example.com/rhcos/"},
+			expected: `{
+  "architectures": {
+    "aarch64": {
+      "artifacts": {
+        "openstack": {
+          "formats": {
+            "qcow2.gz": {
+              "disk": {
+                "location": "https://mirror.example.com/rhcos/rhcos-openstack.aarch64.qcow2.gz",
+                "sha256": "a"
+              }
+            }
+          },
+          "release": "412.86"
+        }
+      }
+    }
+  },
+  "stream": "rhcos-4.12"
+}
+`,
+		},
+		{
+			name:          "unknown architecture",
+			options:       streamOptions{architecture: "s390x"},
+			expectedError: "no s390x architecture in the CoreOS stream metadata",
+		},
+		{
+			name:          "unknown platform",
+			options:       streamOptions{platform: "nutanix"},
+			expectedError: "no nutanix boot images in the CoreOS stream metadata",
+		},
+		{
+			name:          "invalid mirror",
+			options:       streamOptions{mirrorBaseURL: "mirror.example.com/rhcos"},
+			expectedError: `invalid mirror base URL "mirror.example.com/rhcos"`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := filterStream([]byte(testStream), tc.options)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, string(out))
+		})
+	}
+}
@@ -0,0 +1,376 @@
+// Package credentialsfile loads the cloud credentials of an invocation of the
+// installer from a file, so that concurrent installs on one host can use
+// different accounts without changing the environment or the credentials of
+// the user, e.g. ~/.aws/credentials.
+//
+// The same credentials may be passed to the session constructors of the
+// platforms explicitly instead, e.g. by the installs of pkg/client, which
+// take the place of those of the file.
+package credentialsfile
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// File is a credentials file, with a section per platform.
+type File struct {
+	// AWS are the credentials of the AWS platform.
+	// +optional
+	AWS *AWS `json:"aws,omitempty"`
+
+	// Azure are the credentials of the Azure platform.
+	// +optional
+	Azure *Azure `json:"azure,omitempty"`
+
+	// GCP are the credentials of the GCP platform.
+	// +optional
+	GCP *GCP `json:"gcp,omitempty"`
+}
+
+// AWS are credentials of the AWS platform: either access keys, a profile of
+// a shared credentials file, or a credential process. Roles may then be
+// assumed with them in turn.
+type AWS struct {
+	// AccessKeyID is the ID of the access key.
+	// +optional
+	AccessKeyID string `json:"accessKeyID,omitempty"`
+
+	// SecretAccessKey is the secret of the access key.
+	// +optional
+	SecretAccessKey string `json:"secretAccessKey,omitempty"`
+
+	// SessionToken is the token of temporary access keys.
+	// +optional
+	SessionToken string `json:"sessionToken,omitempty"`
+
+	// Profile is the profile of the shared credentials file.
+	// +optional
+	Profile string `json:"profile,omitempty"`
+
+	// SharedCredentialsFile is the shared credentials file of the profile.
+	// It defaults to the one of the user.
+	// +optional
+	SharedCredentialsFile string `json:"sharedCredentialsFile,omitempty"`
+
+	// CredentialProcess is a command printing the credentials, as the
+	// credential_process of a profile.
+	// +optional
+	CredentialProcess string `json:"credentialProcess,omitempty"`
+
+	// AssumeRoles are the roles assumed in turn, each with the credentials
+	// of the previous one. The first role is assumed with the credentials
+	// above or, when there are none, with the default credentials, e.g. the
+	// ones of the instance profile of the host.
+	// +optional
+	AssumeRoles []AssumeRole `json:"assumeRoles,omitempty"`
+}
+
+// AssumeRole is an AWS role to assume.
+type AssumeRole struct {
+	RoleARN string `json:"roleARN"`
+
+	// ExternalID is the external ID the trust policy of the role requires.
+	// +optional
+	ExternalID string `json:"externalID,omitempty"`
+
+	// MFASerial is the serial number or ARN of the MFA device the trust
+	// policy of the role requires. Its token is asked for when the role is
+	// assumed.
+	// +optional
+	MFASerial string `json:"mfaSerial,omitempty"`
+
+	// SessionName is the name of the session of the role. It defaults to
+	// one generated by the SDK.
+	// +optional
+	SessionName string `json:"sessionName,omitempty"`
+
+	// DurationSeconds is the duration of the session of the role. It
+	// defaults to 15 minutes.
+	// +optional
+	DurationSeconds int `json:"durationSeconds,omitempty"`
+}
+
+// AWSKeys are access keys of the AWS platform.
+type AWSKeys struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// Resolved returns whether the credentials must be resolved to access keys
+// to be passed to the commands the installer runs, i.e. they are not keys
+// or a profile.
+func (c *AWS) Resolved() bool {
+	return c.CredentialProcess != "" || len(c.AssumeRoles) > 0
+}
+
+// Azure are credentials of a service principal of the Azure platform.
+type Azure struct {
+	SubscriptionID string `json:"subscriptionId"`
+	TenantID       string `json:"tenantId"`
+	ClientID       string `json:"clientId"`
+
+	// ClientSecret is the secret of the service principal. Either it, a
+	// client certificate or a federated token is required.
+	// +optional
+	ClientSecret string `json:"clientSecret,omitempty"`
+
+	// +optional
+	ClientCertificatePath string `json:"certificatePath,omitempty"`
+
+	// +optional
+	ClientCertificatePassword string `json:"certificatePassword,omitempty"`
+
+	// FederatedTokenFile is the file of a federated token of the workload
+	// identity of the installer, which is exchanged for the tokens of the
+	// service principal.
+	// +optional
+	FederatedTokenFile string `json:"federatedTokenFile,omitempty"`
+}
+
+// GCP are credentials of a service account of the GCP platform, or of the
+// account which impersonates it.
+type GCP struct {
+	// ServiceAccountFile is the JSON key file of the service account.
+	// +optional
+	ServiceAccountFile string `json:"serviceAccountFile,omitempty"`
+
+	// ServiceAccount is the JSON key of the service account, when it is not
+	// in a file.
+	// +optional
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+
+	// ImpersonateServiceAccount is the email of the service account to
+	// impersonate with the credentials above or, when there are none, with
+	// the default credentials.
+	// +optional
+	ImpersonateServiceAccount string `json:"impersonateServiceAccount,omitempty"`
+
+	// Delegates are the emails of the service accounts of the chain of
+	// delegation of the impersonation, each of which impersonates the next.
+	// +optional
+	Delegates []string `json:"delegates,omitempty"`
+}
+
+var (
+	mu      sync.RWMutex
+	current *File
+
+	// ResolveAWS resolves AWS credentials which must be resolved to access
+	// keys. It is set by the package of the AWS session.
+	ResolveAWS func(*AWS) (AWSKeys, error)
+)
+
+// Load loads the credentials file. The relative paths of the file are
+// relative to its directory.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the credentials file")
+	}
+	f := &File{}
+	if err := yaml.UnmarshalStrict(data, f); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal the credentials file %s", path)
+	}
+
+	base := filepath.Dir(path)
+	resolve := func(p string) string {
+		if p == "" || filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(base, p)
+	}
+	if f.AWS != nil {
+		f.AWS.SharedCredentialsFile = resolve(f.AWS.SharedCredentialsFile)
+	}
+	if f.Azure != nil {
+		f.Azure.ClientCertificatePath = resolve(f.Azure.ClientCertificatePath)
+		f.Azure.FederatedTokenFile = resolve(f.Azure.FederatedTokenFile)
+	}
+	if f.GCP != nil {
+		f.GCP.ServiceAccountFile = resolve(f.GCP.ServiceAccountFile)
+	}
+
+	if err := f.validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid credentials file %s", path)
+	}
+	return f, nil
+}
+
+func (f *File) validate() error {
+	if f.AWS == nil && f.Azure == nil && f.GCP == nil {
+		return errors.New("the credentials of at least one platform are required")
+	}
+	if c := f.AWS; c != nil {
+		keys := c.AccessKeyID != "" || c.SecretAccessKey != "" || c.SessionToken != ""
+		profile := c.Profile != "" || c.SharedCredentialsFile != ""
+		sources := 0
+		for _, set := range []bool{keys, profile, c.CredentialProcess != ""} {
+			if set {
+				sources++
+			}
+		}
+		switch {
+		case sources > 1:
+			return errors.New("aws: only one of access keys, a profile or a credential process may be set")
+		case keys && (c.AccessKeyID == "" || c.SecretAccessKey == ""):
+			return errors.New("aws: both accessKeyID and secretAccessKey are required")
+		case sources == 0 && len(c.AssumeRoles) == 0:
+			return errors.New("aws: either access keys, a profile, a credential process or roles to assume are required")
+		}
+		for i, role := range c.AssumeRoles {
+			if role.RoleARN == "" {
+				return errors.Errorf("aws.assumeRoles[%d].roleARN: a role is required", i)
+			}
+			if role.DurationSeconds < 0 {
+				return errors.Errorf("aws.assumeRoles[%d].durationSeconds: %d must not be negative", i, role.DurationSeconds)
+			}
+		}
+	}
+	if c := f.Azure; c != nil {
+		switch {
+		case c.SubscriptionID == "":
+			return errors.New("azure.subscriptionId: a subscription is required")
+		case c.TenantID == "":
+			return errors.New("azure.tenantId: a tenant is required")
+		case c.ClientID == "":
+			return errors.New("azure.clientId: a client is required")
+		}
+		methods := 0
+		for _, set := range []bool{c.ClientSecret != "", c.ClientCertificatePath != "", c.FederatedTokenFile != ""} {
+			if set {
+				methods++
+			}
+		}
+		if methods != 1 {
+			return errors.New("azure: one of clientSecret, certificatePath or federatedTokenFile is required")
+		}
+	}
+	if c := f.GCP; c != nil {
+		switch {
+		case c.ServiceAccountFile != "" && c.ServiceAccount != "":
+			return errors.New("gcp: only one of serviceAccountFile or serviceAccount may be set")
+		case c.ServiceAccountFile == "" && c.ServiceAccount == "" && c.ImpersonateServiceAccount == "":
+			return errors.New("gcp: either serviceAccountFile, serviceAccount or impersonateServiceAccount is required")
+		case len(c.Delegates) > 0 && c.ImpersonateServiceAccount == "":
+			return errors.New("gcp.delegates: delegates require impersonateServiceAccount")
+		}
+	}
+	return nil
+}
+
+// Set sets the credentials of the invocation, which take the place of the
+// credentials of the environment and of the user.
+func Set(f *File) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = f
+}
+
+// Get returns the credentials of the invocation, or nil when none were set.
+func Get() *File {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// AWSCredentials returns the AWS credentials of the file, or nil when there
+// is no file or it has none.
+func (f *File) AWSCredentials() *AWS {
+	if f == nil {
+		return nil
+	}
+	return f.AWS
+}
+
+// AzureCredentials returns the Azure credentials of the file, or nil when
+// there is no file or it has none.
+func (f *File) AzureCredentials() *Azure {
+	if f == nil {
+		return nil
+	}
+	return f.Azure
+}
+
+// GCPCredentials returns the GCP credentials of the file, or nil when there
+// is no file or it has none.
+func (f *File) GCPCredentials() *GCP {
+	if f == nil {
+		return nil
+	}
+	return f.GCP
+}
+
+type contextKey struct{}
+
+// NewContext returns a context carrying the credentials, for the commands
+// the installer runs with it, such as terraform.
+func NewContext(ctx context.Context, f *File) context.Context {
+	return context.WithValue(ctx, contextKey{}, f)
+}
+
+// FromContext returns the credentials of the context or, when it carries
+// none, those set with Set.
+func FromContext(ctx context.Context) *File {
+	if f, ok := ctx.Value(contextKey{}).(*File); ok && f != nil {
+		return f
+	}
+	return Get()
+}
+
+// awsEnvs are the variables of the environment with the AWS credentials.
+var awsEnvs = []string{
+	"AWS_ACCESS_KEY_ID",
+	"AWS_SECRET_ACCESS_KEY",
+	"AWS_SESSION_TOKEN",
+	"AWS_PROFILE",
+	"AWS_SHARED_CREDENTIALS_FILE",
+	"AWS_CONFIG_FILE",
+}
+
+// ApplyEnvironment replaces the credentials in env, the environment of a
+// command run by the installer such as terraform, with the ones of the file.
+// Credentials which must be resolved are passed as the access keys they
+// resolve to. The environment of the installer itself is left as it is.
+func (f *File) ApplyEnvironment(env map[string]string) error {
+	if f == nil || f.AWS == nil {
+		return nil
+	}
+	c := f.AWS
+	keys := AWSKeys{AccessKeyID: c.AccessKeyID, SecretAccessKey: c.SecretAccessKey, SessionToken: c.SessionToken}
+	if c.Resolved() {
+		if ResolveAWS == nil {
+			return errors.New("the AWS credentials of the credentials file cannot be resolved")
+		}
+		var err error
+		if keys, err = ResolveAWS(c); err != nil {
+			return errors.Wrap(err, "failed to resolve the AWS credentials of the credentials file")
+		}
+	}
+
+	for _, name := range awsEnvs {
+		delete(env, name)
+	}
+	if keys.AccessKeyID != "" {
+		env["AWS_ACCESS_KEY_ID"] = keys.AccessKeyID
+		env["AWS_SECRET_ACCESS_KEY"] = keys.SecretAccessKey
+		if keys.SessionToken != "" {
+			env["AWS_SESSION_TOKEN"] = keys.SessionToken
+		}
+		return nil
+	}
+	if c.Profile != "" {
+		env["AWS_PROFILE"] = c.Profile
+	}
+	if c.SharedCredentialsFile != "" {
+		env["AWS_SHARED_CREDENTIALS_FILE"] = c.SharedCredentialsFile
+		env["AWS_CONFIG_FILE"] = c.SharedCredentialsFile
+	}
+	return nil
+}
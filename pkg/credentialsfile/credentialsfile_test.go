@@ -0,0 +1,249 @@
+package credentialsfile
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	cases := []struct {
+		name     string
+		file     string
+		expected func(dir string) *File
+		err      string
+	}{
+		{
+			name: "access keys",
+			file: `aws:
+  accessKeyID: AKIA
+  secretAccessKey: secret
+`,
+			expected: func(string) *File {
+				return &File{AWS: &AWS{AccessKeyID: "AKIA", SecretAccessKey: "secret"}}
+			},
+		},
+		{
+			name: "relative paths",
+			file: `aws:
+  profile: lab
+  sharedCredentialsFile: aws/credentials
+azure:
+  subscriptionId: subscription
+  tenantId: tenant
+  clientId: client
+  federatedTokenFile: /var/run/secrets/azure/tokens/azure-identity-token
+gcp:
+  serviceAccountFile: gcp.json
+`,
+			expected: func(dir string) *File {
+				return &File{
+					AWS: &AWS{Profile: "lab", SharedCredentialsFile: filepath.Join(dir, "aws/credentials")},
+					Azure: &Azure{
+						SubscriptionID:     "subscription",
+						TenantID:           "tenant",
+						ClientID:           "client",
+						FederatedTokenFile: "/var/run/secrets/azure/tokens/azure-identity-token",
+					},
+					GCP: &GCP{ServiceAccountFile: filepath.Join(dir, "gcp.json")},
+				}
+			},
+		},
+		{
+			name: "assumed roles",
+			file: `aws:
+  credentialProcess: vault-aws-credentials lab
+  assumeRoles:
+  - roleARN: arn:aws:iam::111111111111:role/hub
+    mfaSerial: arn:aws:iam::111111111111:mfa/user
+  - roleARN: arn:aws:iam::222222222222:role/installer
+    externalID: lab
+    durationSeconds: 3600
+gcp:
+  impersonateServiceAccount: installer@lab.iam.gserviceaccount.com
+  delegates:
+  - hub@lab.iam.gserviceaccount.com
+`,
+			expected: func(string) *File {
+				return &File{
+					AWS: &AWS{
+						CredentialProcess: "vault-aws-credentials lab",
+						AssumeRoles: []AssumeRole{
+							{RoleARN: "arn:aws:iam::111111111111:role/hub", MFASerial: "arn:aws:iam::111111111111:mfa/user"},
+							{RoleARN: "arn:aws:iam::222222222222:role/installer", ExternalID: "lab", DurationSeconds: 3600},
+						},
+					},
+					GCP: &GCP{
+						ImpersonateServiceAccount: "installer@lab.iam.gserviceaccount.com",
+						Delegates:                 []string{"hub@lab.iam.gserviceaccount.com"},
+					},
+				}
+			},
+		},
+		{
+			name: "empty",
+			file: `{}`,
+			err:  "the credentials of at least one platform are required",
+		},
+		{
+			name: "aws keys and profile",
+			file: `aws:
+  accessKeyID: AKIA
+  secretAccessKey: secret
+  profile: lab
+`,
+			err: "aws: only one of access keys, a profile or a credential process may be set",
+		},
+		{
+			name: "aws missing secret",
+			file: `aws:
+  accessKeyID: AKIA
+`,
+			err: "aws: both accessKeyID and secretAccessKey are required",
+		},
+		{
+			name: "aws role without arn",
+			file: `aws:
+  assumeRoles:
+  - externalID: lab
+`,
+			err: "aws.assumeRoles[0].roleARN: a role is required",
+		},
+		{
+			name: "gcp delegates without impersonation",
+			file: `gcp:
+  serviceAccountFile: gcp.json
+  delegates:
+  - hub@lab.iam.gserviceaccount.com
+`,
+			err: "gcp.delegates: delegates require impersonateServiceAccount",
+		},
+		{
+			name: "azure missing secret",
+			file: `azure:
+  subscriptionId: subscription
+  tenantId: tenant
+  clientId: client
+`,
+			err: "azure: one of clientSecret, certificatePath or federatedTokenFile is required",
+		},
+		{
+			name: "gcp file and content",
+			file: `gcp:
+  serviceAccountFile: gcp.json
+  serviceAccount: "{}"
+`,
+			err: "gcp: only one of serviceAccountFile or serviceAccount may be set",
+		},
+		{
+			name: "unknown platform",
+			file: `ibmcloud:
+  apiKey: key
+`,
+			err: `unknown field "ibmcloud"`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "credentials.yaml")
+			if !assert.NoError(t, os.WriteFile(path, []byte(tc.file), 0o600)) {
+				return
+			}
+			f, err := Load(path)
+			if tc.err != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.err)
+				}
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expected(dir), f)
+			}
+		})
+	}
+}
+
+func TestApplyEnvironment(t *testing.T) {
+	defer func(resolve func(*AWS) (AWSKeys, error)) { ResolveAWS = resolve }(ResolveAWS)
+	ResolveAWS = func(*AWS) (AWSKeys, error) {
+		return AWSKeys{AccessKeyID: "ASIA", SecretAccessKey: "assumed", SessionToken: "session"}, nil
+	}
+
+	ambient := func() map[string]string {
+		return map[string]string{
+			"AWS_ACCESS_KEY_ID":     "ambient",
+			"AWS_SECRET_ACCESS_KEY": "ambient",
+			"AWS_PROFILE":           "ambient",
+			"HOME":                  "/home/user",
+		}
+	}
+	cases := []struct {
+		name     string
+		file     *File
+		expected map[string]string
+	}{
+		{
+			name:     "no file",
+			expected: ambient(),
+		},
+		{
+			name:     "no aws credentials",
+			file:     &File{GCP: &GCP{ServiceAccount: "{}"}},
+			expected: ambient(),
+		},
+		{
+			name: "access keys",
+			file: &File{AWS: &AWS{AccessKeyID: "AKIA", SecretAccessKey: "secret", SessionToken: "token"}},
+			expected: map[string]string{
+				"AWS_ACCESS_KEY_ID":     "AKIA",
+				"AWS_SECRET_ACCESS_KEY": "secret",
+				"AWS_SESSION_TOKEN":     "token",
+				"HOME":                  "/home/user",
+			},
+		},
+		{
+			name: "profile",
+			file: &File{AWS: &AWS{Profile: "lab", SharedCredentialsFile: "/srv/lab/credentials"}},
+			expected: map[string]string{
+				"AWS_PROFILE":                 "lab",
+				"AWS_SHARED_CREDENTIALS_FILE": "/srv/lab/credentials",
+				"AWS_CONFIG_FILE":             "/srv/lab/credentials",
+				"HOME":                        "/home/user",
+			},
+		},
+		{
+			name: "assumed role",
+			file: &File{AWS: &AWS{Profile: "lab", AssumeRoles: []AssumeRole{{RoleARN: "arn:aws:iam::222222222222:role/installer"}}}},
+			expected: map[string]string{
+				"AWS_ACCESS_KEY_ID":     "ASIA",
+				"AWS_SECRET_ACCESS_KEY": "assumed",
+				"AWS_SESSION_TOKEN":     "session",
+				"HOME":                  "/home/user",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := ambient()
+			if assert.NoError(t, tc.file.ApplyEnvironment(env)) {
+				assert.Equal(t, tc.expected, env)
+			}
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	defer Set(Get())
+	invocation := &File{AWS: &AWS{Profile: "invocation"}}
+	Set(invocation)
+
+	assert.Equal(t, invocation, FromContext(context.Background()))
+	assert.Equal(t, invocation, FromContext(NewContext(context.Background(), nil)))
+
+	install := &File{AWS: &AWS{Profile: "install"}}
+	assert.Equal(t, install, FromContext(NewContext(context.Background(), install)))
+}
@@ -61,6 +61,8 @@ type ClusterUninstaller struct {
 	slbClient      *slb.Client
 	ossClient      *oss.Client
 	rmanagerClient *resourcemanager.Client
+
+	report *providers.Report
 }
 
 // ResourceArn holds the information contained in the cloud resource Arn string
@@ -158,6 +160,12 @@ func New(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (providers.
 	}, nil
 }
 
+// Configure sets the options of the uninstall and the report to record the
+// resources in.
+func (o *ClusterUninstaller) Configure(options providers.Options, report *providers.Report) {
+	o.report = report
+}
+
 // Run is the entrypoint to start the uninstall process.
 func (o *ClusterUninstaller) Run() (*types.ClusterQuota, error) {
 	var err error
@@ -177,6 +185,22 @@ func (o *ClusterUninstaller) Run() (*types.ClusterQuota, error) {
 		return nil, errors.Wrap(err, "failed to destroy cluster")
 	}
 
+	// the stages are retried until all of their resources are deleted
+	for _, resources := range [][]ResourceArn{
+		o.TagResources.ecsInstances,
+		o.TagResources.securityGroups,
+		o.TagResources.vpcs,
+		o.TagResources.vSwitchs,
+		o.TagResources.eips,
+		o.TagResources.natgateways,
+		o.TagResources.slbs,
+		o.TagResources.buckets,
+	} {
+		for _, resourceArn := range resources {
+			o.report.Deleted(arnResourceType(resourceArn), resourceArn.Arn)
+		}
+	}
+
 	return nil, nil
 }
 
@@ -295,36 +319,36 @@ func (o *ClusterUninstaller) tidyResourceByArn(resourceArns []ResourceArn) {
 		case "ecs":
 			switch resourceArn.ResourceType {
 			case "instance":
-				o.TagResources.ecsInstances = append(o.TagResources.ecsInstances, resourceArn)
+				o.insertResource(&o.TagResources.ecsInstances, resourceArn)
 			case "securitygroup":
-				o.TagResources.securityGroups = append(o.TagResources.securityGroups, resourceArn)
+				o.insertResource(&o.TagResources.securityGroups, resourceArn)
 			default:
 				o.TagResources.others = append(o.TagResources.others, resourceArn)
 			}
 		case "vpc":
 			switch resourceArn.ResourceType {
 			case "vpc":
-				o.TagResources.vpcs = append(o.TagResources.vpcs, resourceArn)
+				o.insertResource(&o.TagResources.vpcs, resourceArn)
 			case "vswitch":
-				o.TagResources.vSwitchs = append(o.TagResources.vSwitchs, resourceArn)
+				o.insertResource(&o.TagResources.vSwitchs, resourceArn)
 			case "eip":
-				o.TagResources.eips = append(o.TagResources.eips, resourceArn)
+				o.insertResource(&o.TagResources.eips, resourceArn)
 			case "natgateway":
-				o.TagResources.natgateways = append(o.TagResources.natgateways, resourceArn)
+				o.insertResource(&o.TagResources.natgateways, resourceArn)
 			default:
 				o.TagResources.others = append(o.TagResources.others, resourceArn)
 			}
 		case "slb":
 			switch resourceArn.ResourceType {
 			case "instance":
-				o.TagResources.slbs = append(o.TagResources.slbs, resourceArn)
+				o.insertResource(&o.TagResources.slbs, resourceArn)
 			default:
 				o.TagResources.others = append(o.TagResources.others, resourceArn)
 			}
 		case "oss":
 			switch resourceArn.ResourceType {
 			case "bucket":
-				o.TagResources.buckets = append(o.TagResources.buckets, resourceArn)
+				o.insertResource(&o.TagResources.buckets, resourceArn)
 			default:
 				o.TagResources.others = append(o.TagResources.others, resourceArn)
 			}
@@ -334,6 +358,20 @@ func (o *ClusterUninstaller) tidyResourceByArn(resourceArns []ResourceArn) {
 	}
 }
 
+// insertResource adds the resource to the resources to delete, unless the
+// report leaves it in place.
+func (o *ClusterUninstaller) insertResource(resources *[]ResourceArn, resourceArn ResourceArn) {
+	if o.report.ShouldDelete(arnResourceType(resourceArn), resourceArn.Arn) {
+		*resources = append(*resources, resourceArn)
+	}
+}
+
+// arnResourceType returns the type of the resource of the ARN for reports
+// and filters, e.g. ecs:instance.
+func arnResourceType(resourceArn ResourceArn) string {
+	return resourceArn.Service + ":" + resourceArn.ResourceType
+}
+
 func (o *ClusterUninstaller) deleteResourceGroup(logger logrus.FieldLogger) (err error) {
 	resourceGroupName := fmt.Sprintf("%s-rg", o.InfraID)
 	logger = logger.WithField("name", resourceGroupName)
@@ -353,9 +391,13 @@ func (o *ClusterUninstaller) deleteResourceGroup(logger logrus.FieldLogger) (err
 	if resourceGroupID == "" {
 		return
 	}
+	if !o.report.ShouldDelete("resourcemanager:resourcegroup", resourceGroupName) {
+		return nil
+	}
 
 	err = o.deleteResourceGroupByID(resourceGroupID, logger)
 	if err != nil {
+		o.report.Failed("resourcemanager:resourcegroup", resourceGroupName, err)
 		return err
 	}
 
@@ -376,6 +418,11 @@ func (o *ClusterUninstaller) deleteResourceGroup(logger logrus.FieldLogger) (err
 		},
 	)
 
+	if err != nil {
+		o.report.Failed("resourcemanager:resourcegroup", resourceGroupName, err)
+		return err
+	}
+	o.report.Deleted("resourcemanager:resourcegroup", resourceGroupName)
 	logger.Info("Resource group deleted")
 	return
 }
@@ -1085,18 +1132,26 @@ func (o *ClusterUninstaller) deleteRAMRoles(logger logrus.FieldLogger) (err erro
 		roleName := fmt.Sprintf("%s-role-%s", o.InfraID, role)
 		policyName := fmt.Sprintf("%s-policy-%s", o.InfraID, role)
 
+		// the policy of the role is deleted with it
+		if !o.report.ShouldDelete("ram:role", roleName) {
+			continue
+		}
 		err = o.detachRAMPolicy(policyName, logger)
 		if err != nil {
+			o.report.Failed("ram:role", roleName, err)
 			return err
 		}
 		err = o.deletePolicyByName(policyName, logger)
 		if err != nil {
+			o.report.Failed("ram:role", roleName, err)
 			return err
 		}
 		err = o.deleteRAMRole(roleName, logger)
 		if err != nil && !strings.Contains(err.Error(), "EntityNotExist.Role") {
+			o.report.Failed("ram:role", roleName, err)
 			return err
 		}
+		o.report.Deleted("ram:role", roleName)
 	}
 
 	logger.Info("RAM roles deleted")
@@ -1226,6 +1281,16 @@ func (o *ClusterUninstaller) deletePrivateZones(logger logrus.FieldLogger) (err
 	if zoneID == "" {
 		return nil
 	}
+	if !o.report.ShouldDelete("pvtz:zone", zoneID) {
+		return nil
+	}
+	defer func() {
+		if err != nil {
+			o.report.Failed("pvtz:zone", zoneID, err)
+		} else {
+			o.report.Deleted("pvtz:zone", zoneID)
+		}
+	}()
 
 	err = o.bindZoneVpc(zoneID, logger)
 	if err != nil {
@@ -1396,12 +1461,20 @@ func (o *ClusterUninstaller) deleteDNSRecords(logger logrus.FieldLogger) (err er
 		recordLogger := logger.WithFields(logrus.Fields{"recordID": record.RecordId, "domain": baseDomain, "rr": record.RR})
 		key := recordSetKey(record.Type, record.RR)
 		if privateRecords[key] {
+			if !o.report.ShouldDelete("alidns:record", record.RecordId) {
+				// the wait below only covers the records being deleted
+				privateRecords[key] = false
+				continue
+			}
 			err = o.deleteRecord(record.RecordId, recordLogger)
 			if err != nil {
 				privateRecords[key] = false
 				lastErr = errors.Wrap(err, fmt.Sprintf("DNS record %q", record.RecordId))
+				o.report.Failed("alidns:record", record.RecordId, err)
 				o.Logger.Info(lastErr)
+				continue
 			}
+			o.report.Deleted("alidns:record", record.RecordId)
 		}
 	}
 
@@ -63,9 +63,14 @@ type ClusterUninstaller struct {
 	ClusterDomain string
 
 	// Session is the AWS session to be used for deletion.  If nil, a
-	// new session will be created based on the usual credential
+	// new session will be created with the credentials of the options
+	// or, when they have none, based on the usual credential
 	// configuration (AWS_PROFILE, AWS_ACCESS_KEY_ID, etc.).
 	Session *session.Session
+
+	serviceEndpoints []awstypes.ServiceEndpoint
+	options          providers.Options
+	report           *providers.Report
 }
 
 // New returns an AWS destroyer from ClusterMetadata.
@@ -74,22 +79,14 @@ func New(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (providers.
 	for _, filter := range metadata.ClusterPlatformMetadata.AWS.Identifier {
 		filters = append(filters, filter)
 	}
-	region := metadata.ClusterPlatformMetadata.AWS.Region
-	session, err := awssession.GetSessionWithOptions(
-		awssession.WithRegion(region),
-		awssession.WithServiceEndpoints(region, metadata.ClusterPlatformMetadata.AWS.ServiceEndpoints),
-	)
-	if err != nil {
-		return nil, err
-	}
-
+	// the session is created by Run, with the credentials of the options
 	return &ClusterUninstaller{
-		Filters:       filters,
-		Region:        region,
-		Logger:        logger,
-		ClusterID:     metadata.InfraID,
-		ClusterDomain: metadata.AWS.ClusterDomain,
-		Session:       session,
+		Filters:          filters,
+		Region:           metadata.ClusterPlatformMetadata.AWS.Region,
+		Logger:           logger,
+		ClusterID:        metadata.InfraID,
+		ClusterDomain:    metadata.AWS.ClusterDomain,
+		serviceEndpoints: metadata.ClusterPlatformMetadata.AWS.ServiceEndpoints,
 	}, nil
 }
 
@@ -103,6 +100,13 @@ func (o *ClusterUninstaller) validate() error {
 	return nil
 }
 
+// Configure sets the options of the uninstall and the report to record the
+// resources in.
+func (o *ClusterUninstaller) Configure(options providers.Options, report *providers.Report) {
+	o.options = options
+	o.report = report
+}
+
 // Run is the entrypoint to start the uninstall process
 func (o *ClusterUninstaller) Run() (*types.ClusterQuota, error) {
 	_, err := o.RunWithContext(context.Background())
@@ -119,8 +123,11 @@ func (o *ClusterUninstaller) RunWithContext(ctx context.Context) ([]string, erro
 
 	awsSession := o.Session
 	if awsSession == nil {
-		// Relying on appropriate AWS ENV vars (eg AWS_PROFILE, AWS_ACCESS_KEY_ID, etc)
-		awsSession, err = session.NewSession(aws.NewConfig().WithRegion(o.Region))
+		awsSession, err = awssession.GetSessionWithCredentials(
+			o.options.Credentials.AWSCredentials(),
+			awssession.WithRegion(o.Region),
+			awssession.WithServiceEndpoints(o.Region, o.serviceEndpoints),
+		)
 		if err != nil {
 			return nil, err
 		}
@@ -249,6 +256,9 @@ func (o *ClusterUninstaller) RunWithContext(ctx context.Context) ([]string, erro
 		return resourcesToDelete.UnsortedList(), err
 	}
 
+	if o.options.DryRun {
+		return nil, nil
+	}
 	err = o.removeSharedTags(ctx, awsSession, tagClients, tracker)
 	if err != nil {
 		return nil, err
@@ -391,18 +401,36 @@ func (o *ClusterUninstaller) deleteResources(ctx context.Context, awsSession *se
 			logger.WithError(err).Debug("could not parse ARN")
 			continue
 		}
+		resourceType := arnResourceType(parsedARN)
+		if !o.report.ShouldDelete(resourceType, arnString) {
+			// resources left in place are done with
+			deleted.Insert(arnString)
+			continue
+		}
 		if err := deleteARN(ctx, awsSession, parsedARN, o.Logger); err != nil {
+			o.report.Failed(resourceType, arnString, err)
 			tracker.suppressWarning(arnString, err, logger)
 			if err := ctx.Err(); err != nil {
 				return deleted, err
 			}
 			continue
 		}
+		o.report.Deleted(resourceType, arnString)
 		deleted.Insert(arnString)
 	}
 	return deleted, nil
 }
 
+// arnResourceType returns the type of the resource of the ARN for reports
+// and filters, e.g. ec2:instance or s3.
+func arnResourceType(arn arn.ARN) string {
+	i := strings.IndexAny(arn.Resource, "/:")
+	if i < 0 {
+		return arn.Service
+	}
+	return arn.Service + ":" + arn.Resource[:i]
+}
+
 func splitSlash(name string, input string) (base string, suffix string, err error) {
 	segments := strings.SplitN(input, "/", 2)
 	if len(segments) != 2 {
@@ -55,9 +55,35 @@ type ClusterUninstaller struct {
 	privateRecordSetsClient privatedns.RecordSetsClient
 	privateZonesClient      privatedns.PrivateZonesClient
 	msgraphClient           *msgraphsdk.GraphServiceClient
+
+	armEndpoint string
+	options     providers.Options
+	report      *providers.Report
+}
+
+// configureSession creates the session of the uninstall with the credentials
+// of the options, unless it was given one.
+func (o *ClusterUninstaller) configureSession() error {
+	if o.Authorizer != nil {
+		return nil
+	}
+	session, err := azuresession.GetSessionWithCredentials(o.CloudName, o.armEndpoint, azuresession.CredentialsFromFile(o.options.Credentials.AzureCredentials()))
+	if err != nil {
+		return err
+	}
+	o.SubscriptionID = session.Credentials.SubscriptionID
+	o.TenantID = session.Credentials.TenantID
+	o.Authorizer = session.Authorizer
+	o.Environment = session.Environment
+	o.AuthProvider = session.AuthProvider
+	return nil
 }
 
 func (o *ClusterUninstaller) configureClients() error {
+	if err := o.configureSession(); err != nil {
+		return err
+	}
+
 	o.resourceGroupsClient = resources.NewGroupsClientWithBaseURI(o.Environment.ResourceManagerEndpoint, o.SubscriptionID)
 	o.resourceGroupsClient.Authorizer = o.Authorizer
 
@@ -88,27 +114,20 @@ func New(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (providers.
 	if cloudName == "" {
 		cloudName = azure.PublicCloud
 	}
-	session, err := azuresession.GetSession(cloudName, metadata.Azure.ARMEndpoint)
-	if err != nil {
-		return nil, err
-	}
 
 	group := metadata.Azure.ResourceGroupName
 	if len(group) == 0 {
 		group = metadata.InfraID + "-rg"
 	}
 
+	// the session is created by Run, with the credentials of the options
 	return &ClusterUninstaller{
-		SubscriptionID:              session.Credentials.SubscriptionID,
-		TenantID:                    session.Credentials.TenantID,
-		Authorizer:                  session.Authorizer,
-		Environment:                 session.Environment,
-		AuthProvider:                session.AuthProvider,
 		InfraID:                     metadata.InfraID,
 		ResourceGroupName:           group,
 		Logger:                      logger,
 		BaseDomainResourceGroupName: metadata.Azure.BaseDomainResourceGroupName,
 		CloudName:                   cloudName,
+		armEndpoint:                 metadata.Azure.ARMEndpoint,
 	}, nil
 }
 
@@ -127,9 +146,15 @@ func (o *ClusterUninstaller) Run() (*types.ClusterQuota, error) {
 	waitCtx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
+	shouldDelete := o.report.ShouldDelete("dns:records", o.ResourceGroupName)
+	failures := len(errs)
 	wait.UntilWithContext(
 		waitCtx,
 		func(ctx context.Context) {
+			if !shouldDelete {
+				cancel()
+				return
+			}
 			o.Logger.Debugf("deleting public records")
 			if o.CloudName == azure.StackCloud {
 				err = deleteAzureStackPublicRecords(ctx, o)
@@ -153,6 +178,7 @@ func (o *ClusterUninstaller) Run() (*types.ClusterQuota, error) {
 		errs = append(errs, errors.Wrap(err, "failed to delete public DNS records"))
 		o.Logger.Debug(err)
 	}
+	o.recordStage(shouldDelete, "dns:records", o.ResourceGroupName, errs[failures:])
 
 	deadline, _ := waitCtx.Deadline()
 	diff := time.Until(deadline)
@@ -160,9 +186,15 @@ func (o *ClusterUninstaller) Run() (*types.ClusterQuota, error) {
 		waitCtx, cancel = context.WithTimeout(context.Background(), diff)
 	}
 
+	shouldDelete = o.report.ShouldDelete("resourcegroup", o.ResourceGroupName)
+	failures = len(errs)
 	wait.UntilWithContext(
 		waitCtx,
 		func(ctx context.Context) {
+			if !shouldDelete {
+				cancel()
+				return
+			}
 			o.Logger.Debugf("deleting resource group")
 			err = deleteResourceGroup(ctx, o.resourceGroupsClient, o.Logger, o.ResourceGroupName)
 			if err != nil {
@@ -185,6 +217,7 @@ func (o *ClusterUninstaller) Run() (*types.ClusterQuota, error) {
 		errs = append(errs, errors.Wrap(err, "failed to delete resource group"))
 		o.Logger.Debug(err)
 	}
+	o.recordStage(shouldDelete, "resourcegroup", o.ResourceGroupName, errs[failures:])
 
 	deadline, _ = waitCtx.Deadline()
 	diff = time.Until(deadline)
@@ -192,9 +225,15 @@ func (o *ClusterUninstaller) Run() (*types.ClusterQuota, error) {
 		waitCtx, cancel = context.WithTimeout(context.Background(), diff)
 	}
 
+	shouldDelete = o.report.ShouldDelete("applicationregistration", o.InfraID)
+	failures = len(errs)
 	wait.UntilWithContext(
 		waitCtx,
 		func(ctx context.Context) {
+			if !shouldDelete {
+				cancel()
+				return
+			}
 			o.Logger.Debugf("deleting application registrations")
 			err = deleteApplicationRegistrations(ctx, o.msgraphClient, o.Logger, o.InfraID)
 			if err != nil {
@@ -215,10 +254,31 @@ func (o *ClusterUninstaller) Run() (*types.ClusterQuota, error) {
 		errs = append(errs, errors.Wrap(err, "failed to delete application registrations and their service principals"))
 		o.Logger.Debug(err)
 	}
+	o.recordStage(shouldDelete, "applicationregistration", o.InfraID, errs[failures:])
 
 	return nil, utilerrors.NewAggregate(errs)
 }
 
+// Configure sets the options of the uninstall and the report to record the
+// resources in.
+func (o *ClusterUninstaller) Configure(options providers.Options, report *providers.Report) {
+	o.options = options
+	o.report = report
+}
+
+// recordStage records the outcome of deleting the resources of a stage of
+// the uninstall in the report.
+func (o *ClusterUninstaller) recordStage(deleted bool, resourceType, id string, errs []error) {
+	if !deleted {
+		return
+	}
+	if len(errs) > 0 {
+		o.report.Failed(resourceType, id, utilerrors.NewAggregate(errs))
+		return
+	}
+	o.report.Deleted(resourceType, id)
+}
+
 func deleteAzureStackPublicRecords(ctx context.Context, o *ClusterUninstaller) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
 	defer cancel()
@@ -18,6 +18,14 @@ type ClusterUninstaller struct {
 	LibvirtURI              string
 	BootstrapProvisioningIP string
 	Logger                  logrus.FieldLogger
+
+	report *providers.Report
+}
+
+// Configure sets the options of the uninstall and the report to record the
+// resources in.
+func (o *ClusterUninstaller) Configure(options providers.Options, report *providers.Report) {
+	o.report = report
 }
 
 // Run is the entrypoint to start the uninstall process.
@@ -62,6 +70,11 @@ func (o *ClusterUninstaller) deleteStoragePool(conn *libvirt.Connect) error {
 	}
 	defer pool.Free()
 
+	// the volumes of the pool are deleted with it
+	if !o.report.ShouldDelete("pool", pName) {
+		return nil
+	}
+
 	// delete vols
 	vols, err := pool.ListAllStorageVolumes(0)
 	if err != nil {
@@ -78,6 +91,7 @@ func (o *ClusterUninstaller) deleteStoragePool(conn *libvirt.Connect) error {
 		}
 		if err := vol.Delete(0); err != nil {
 			o.Logger.Warnf("Unable to delete volume %s in storage pool %s: %s", vName, pName, err)
+			o.report.Failed("pool", pName, errors.Wrapf(err, "delete volume %q", vName))
 			return nil
 		}
 		o.Logger.WithField("volume", vName).Info("Deleted volume")
@@ -93,7 +107,10 @@ func (o *ClusterUninstaller) deleteStoragePool(conn *libvirt.Connect) error {
 
 	if err := pool.Undefine(); err != nil {
 		o.Logger.Warnf("Unable to undefine storage pool %s: %s", pName, err)
+		o.report.Failed("pool", pName, err)
+		return nil
 	}
+	o.report.Deleted("pool", pName)
 	o.Logger.WithField("pool", pName).Info("Deleted pool")
 
 	return nil
@@ -2,6 +2,7 @@
 package bootstrap
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -18,8 +19,9 @@ import (
 	typesvsphere "github.com/openshift/installer/pkg/types/vsphere"
 )
 
-// Destroy uses Terraform to remove bootstrap resources.
-func Destroy(dir string) (err error) {
+// Destroy uses Terraform to remove bootstrap resources. Terraform is
+// canceled with the context.
+func Destroy(ctx context.Context, dir string) (err error) {
 	metadata, err := cluster.LoadMetadata(dir)
 	if err != nil {
 		return err
@@ -52,7 +54,7 @@ func Destroy(dir string) (err error) {
 		}
 	}
 
-	varFiles := []string{cluster.TfVarsFileName, cluster.TfPlatformVarsFileName}
+	varFiles := []string{cluster.TfVarsFileName, cluster.TfPlatformVarsFileName, cluster.TfOverridesVarsFileName}
 	tfStages := platformstages.StagesForPlatform(platform)
 	for _, stage := range tfStages {
 		varFiles = append(varFiles, stage.OutputsFilename())
@@ -95,8 +97,9 @@ func Destroy(dir string) (err error) {
 			sourcePath := filepath.Join(dir, filename)
 			targetPath := filepath.Join(tempDir, filename)
 			if err := copy(sourcePath, targetPath); err != nil {
-				// platform may not need platform-specific Terraform variables
-				if filename == cluster.TfPlatformVarsFileName {
+				// platform may not need platform-specific Terraform variables,
+				// and the variables are not necessarily overridden
+				if filename == cluster.TfPlatformVarsFileName || filename == cluster.TfOverridesVarsFileName {
 					if os.IsNotExist(err) && err.(*os.PathError).Path == sourcePath {
 						continue
 					}
@@ -106,7 +109,7 @@ func Destroy(dir string) (err error) {
 			targetVarFiles = append(targetVarFiles, targetPath)
 		}
 
-		if err := stage.Destroy(tempDir, terraformDirPath, targetVarFiles); err != nil {
+		if err := stage.Destroy(ctx, tempDir, terraformDirPath, targetVarFiles); err != nil {
 			return err
 		}
 
@@ -6,23 +6,47 @@ import (
 
 	"github.com/openshift/installer/pkg/asset/cluster"
 	"github.com/openshift/installer/pkg/destroy/providers"
+	"github.com/openshift/installer/pkg/types"
 )
 
 // New returns a Destroyer based on `metadata.json` in `rootDir`.
 func New(logger logrus.FieldLogger, rootDir string) (providers.Destroyer, error) {
+	destroyer, _, err := NewWithOptions(logger, rootDir, providers.Options{})
+	return destroyer, err
+}
+
+// NewWithOptions returns a Destroyer based on `metadata.json` in `rootDir`
+// configured with the options, and the report it records the resources of
+// the cluster in. Platforms whose destroyer does not support options only
+// accept the default options, and report no resources.
+func NewWithOptions(logger logrus.FieldLogger, rootDir string, options providers.Options) (providers.Destroyer, *providers.Report, error) {
 	metadata, err := cluster.LoadMetadata(rootDir)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	return newFromMetadata(logger, metadata, options)
+}
 
+func newFromMetadata(logger logrus.FieldLogger, metadata *types.ClusterMetadata, options providers.Options) (providers.Destroyer, *providers.Report, error) {
 	platform := metadata.Platform()
 	if platform == "" {
-		return nil, errors.New("no platform configured in metadata")
+		return nil, nil, errors.New("no platform configured in metadata")
 	}
 
 	creator, ok := providers.Registry[platform]
 	if !ok {
-		return nil, errors.Errorf("no destroyers registered for %q", platform)
+		return nil, nil, errors.Errorf("no destroyers registered for %q", platform)
+	}
+	destroyer, err := creator(logger, metadata)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report := providers.NewReport(platform, metadata.InfraID, options)
+	if configurable, ok := destroyer.(providers.ConfigurableDestroyer); ok {
+		configurable.Configure(options, report)
+	} else if !options.IsZero() {
+		return nil, nil, errors.Errorf("dry runs and kept resource types are not supported on %s", platform)
 	}
-	return creator(logger, metadata)
+	return destroyer, report, nil
 }
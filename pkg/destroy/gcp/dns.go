@@ -165,6 +165,9 @@ func (o *ClusterUninstaller) destroyDNS() error {
 		o.Logger.Debugf("Private DNS zone not found")
 		return nil
 	}
+	if !o.pendingItemTracker.report.ShouldDelete("dnszone", privateZone.name) {
+		return nil
+	}
 
 	zoneRecordSets, err := o.listDNSZoneRecordSets(privateZone)
 	if err != nil {
@@ -193,5 +196,6 @@ func (o *ClusterUninstaller) destroyDNS() error {
 	if err != nil {
 		return err
 	}
+	o.pendingItemTracker.report.Deleted("dnszone", privateZone.name)
 	return nil
 }
@@ -21,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 
 	gcpconfig "github.com/openshift/installer/pkg/asset/installconfig/gcp"
+	"github.com/openshift/installer/pkg/credentialsfile"
 	"github.com/openshift/installer/pkg/destroy/providers"
 	"github.com/openshift/installer/pkg/types"
 	gcptypes "github.com/openshift/installer/pkg/types/gcp"
@@ -55,6 +56,10 @@ type ClusterUninstaller struct {
 	// from metadata or by inferring it from existing cluster resources.
 	cloudControllerUID string
 
+	// credentials are the credentials of the uninstall, when they are not
+	// read from the environment.
+	credentials *credentialsfile.GCP
+
 	errorTracker
 	requestIDTracker
 	pendingItemTracker
@@ -75,12 +80,19 @@ func New(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (providers.
 	}, nil
 }
 
+// Configure sets the options of the uninstall and the report to record the
+// resources in.
+func (o *ClusterUninstaller) Configure(options providers.Options, report *providers.Report) {
+	o.credentials = options.Credentials.GCPCredentials()
+	o.pendingItemTracker.report = report
+}
+
 // Run is the entrypoint to start the uninstall process
 func (o *ClusterUninstaller) Run() (*types.ClusterQuota, error) {
 	ctx, cancel := o.contextWithTimeout()
 	defer cancel()
 
-	ssn, err := gcpconfig.GetSession(ctx)
+	ssn, err := gcpconfig.GetSessionWithCredentials(ctx, o.credentials)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get session")
 	}
@@ -294,6 +306,7 @@ func (t requestIDTracker) resetRequestID(identifier ...string) {
 // pendingItemTracker tracks a set of pending item names for a given type of resource
 type pendingItemTracker struct {
 	pendingItems map[string]cloudResources
+	report       *providers.Report
 	removedQuota []gcptypes.QuotaUsage
 }
 
@@ -323,13 +336,18 @@ func (t *pendingItemTracker) getPendingItems(itemType string) []cloudResource {
 	return lastFound.list()
 }
 
-// insertPendingItems adds to the list of resources to be deleted.
+// insertPendingItems adds to the list of resources to be deleted the items
+// that the report allows to delete.
 func (t *pendingItemTracker) insertPendingItems(itemType string, items []cloudResource) []cloudResource {
 	lastFound, exists := t.pendingItems[itemType]
 	if !exists {
 		lastFound = cloudResources{}
 	}
-	lastFound = lastFound.insert(items...)
+	for _, item := range items {
+		if t.report.ShouldDelete(reportType(itemType, item), item.key) {
+			lastFound = lastFound.insert(item)
+		}
+	}
 	t.pendingItems[itemType] = lastFound
 	return lastFound.list()
 }
@@ -340,6 +358,11 @@ func (t *pendingItemTracker) deletePendingItems(itemType string, items []cloudRe
 	if !exists {
 		lastFound = cloudResources{}
 	}
+	for _, item := range items {
+		if item.typeName == "" || item.typeName == itemType {
+			t.report.Deleted(reportType(itemType, item), item.key)
+		}
+	}
 	for _, item := range items {
 		t.removedQuota = mergeAllUsage(t.removedQuota, item.quota)
 	}
@@ -348,6 +371,14 @@ func (t *pendingItemTracker) deletePendingItems(itemType string, items []cloudRe
 	return lastFound.list()
 }
 
+// reportType returns the resource type of the item in reports and filters.
+func reportType(itemType string, item cloudResource) string {
+	if item.typeName != "" {
+		return item.typeName
+	}
+	return itemType
+}
+
 func isErrorStatus(code int64) bool {
 	return code != 0 && (code < 200 || code >= 300)
 }
@@ -109,6 +109,12 @@ func (o *ClusterUninstaller) Retry(funcToRetry func() (error, bool)) error {
 	return err
 }
 
+// Configure sets the options of the uninstall and the report to record the
+// resources in.
+func (o *ClusterUninstaller) Configure(options providers.Options, report *providers.Report) {
+	o.pendingItemTracker.report = report
+}
+
 // Run is the entrypoint to start the uninstall process
 func (o *ClusterUninstaller) Run() (*types.ClusterQuota, error) {
 	err := o.loadSDKServices()
@@ -429,6 +435,7 @@ func aggregateError(errs []error, pending ...int) error {
 // pendingItemTracker tracks a set of pending item names for a given type of resource
 type pendingItemTracker struct {
 	pendingItems map[string]cloudResources
+	report       *providers.Report
 }
 
 func newPendingItemTracker() pendingItemTracker {
@@ -457,13 +464,18 @@ func (t pendingItemTracker) getPendingItems(itemType string) []cloudResource {
 	return lastFound.list()
 }
 
-// insertPendingItems adds to the list of resources to be deleted.
+// insertPendingItems adds to the list of resources to be deleted the items
+// that the report allows to delete.
 func (t pendingItemTracker) insertPendingItems(itemType string, items []cloudResource) []cloudResource {
 	lastFound, exists := t.pendingItems[itemType]
 	if !exists {
 		lastFound = cloudResources{}
 	}
-	lastFound = lastFound.insert(items...)
+	for _, item := range items {
+		if t.report.ShouldDelete(reportType(itemType, item), item.key) {
+			lastFound = lastFound.insert(item)
+		}
+	}
 	t.pendingItems[itemType] = lastFound
 	return lastFound.list()
 }
@@ -474,11 +486,24 @@ func (t pendingItemTracker) deletePendingItems(itemType string, items []cloudRes
 	if !exists {
 		lastFound = cloudResources{}
 	}
+	for _, item := range items {
+		if item.typeName == "" || item.typeName == itemType {
+			t.report.Deleted(reportType(itemType, item), item.key)
+		}
+	}
 	lastFound = lastFound.delete(items...)
 	t.pendingItems[itemType] = lastFound
 	return lastFound.list()
 }
 
+// reportType returns the resource type of the item in reports and filters.
+func reportType(itemType string, item cloudResource) string {
+	if item.typeName != "" {
+		return item.typeName
+	}
+	return itemType
+}
+
 func isErrorStatus(code int64) bool {
 	return code != 0 && (code < 200 || code >= 300)
 }
@@ -39,13 +39,17 @@ var AlwaysTrueFilter = func() filterFunc {
 }
 
 // deleteFunc is the interface a function needs to implement to be delete resources.
-type deleteFunc func(conn *libvirt.Connect, filter filterFunc, logger logrus.FieldLogger) error
+// The resources are recorded in the report, and left in place when it does
+// not allow to delete them.
+type deleteFunc func(conn *libvirt.Connect, filter filterFunc, logger logrus.FieldLogger, report *providers.Report) error
 
 // ClusterUninstaller holds the various options for the cluster we want to delete.
 type ClusterUninstaller struct {
 	LibvirtURI string
 	Filter     filterFunc
 	Logger     logrus.FieldLogger
+
+	report *providers.Report
 }
 
 // New returns libvirt Uninstaller from ClusterMetadata.
@@ -57,6 +61,12 @@ func New(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (providers.
 	}, nil
 }
 
+// Configure sets the options of the uninstall and the report to record the
+// resources in.
+func (o *ClusterUninstaller) Configure(options providers.Options, report *providers.Report) {
+	o.report = report
+}
+
 // Run is the entrypoint to start the uninstall process.
 func (o *ClusterUninstaller) Run() (*types.ClusterQuota, error) {
 	conn, err := libvirt.NewConnect(o.LibvirtURI)
@@ -69,7 +79,7 @@ func (o *ClusterUninstaller) Run() (*types.ClusterQuota, error) {
 		deleteNetwork,
 		deleteStoragePool,
 	} {
-		err = del(conn, o.Filter, o.Logger)
+		err = del(conn, o.Filter, o.Logger, o.report)
 		if err != nil {
 			return nil, err
 		}
@@ -82,13 +92,13 @@ func (o *ClusterUninstaller) Run() (*types.ClusterQuota, error) {
 // matching domains.  This guards against the machine-API launching
 // additional nodes after the initial list call.  We continue deleting
 // domains until we either hit an error or we have a list call with no
-// matching domains.
-func deleteDomains(conn *libvirt.Connect, filter filterFunc, logger logrus.FieldLogger) error {
+// matching domains, other than those the report leaves in place.
+func deleteDomains(conn *libvirt.Connect, filter filterFunc, logger logrus.FieldLogger, report *providers.Report) error {
 	logger.Debug("Deleting libvirt domains")
 	var err error
 	nothingToDelete := false
 	for !nothingToDelete {
-		nothingToDelete, err = deleteDomainsSinglePass(conn, filter, logger)
+		nothingToDelete, err = deleteDomainsSinglePass(conn, filter, logger, report)
 		if err != nil {
 			return err
 		}
@@ -96,7 +106,7 @@ func deleteDomains(conn *libvirt.Connect, filter filterFunc, logger logrus.Field
 	return nil
 }
 
-func deleteDomainsSinglePass(conn *libvirt.Connect, filter filterFunc, logger logrus.FieldLogger) (nothingToDelete bool, err error) {
+func deleteDomainsSinglePass(conn *libvirt.Connect, filter filterFunc, logger logrus.FieldLogger, report *providers.Report) (nothingToDelete bool, err error) {
 	domains, err := conn.ListAllDomains(0)
 	if err != nil {
 		return false, errors.Wrap(err, "list domains")
@@ -109,38 +119,47 @@ func deleteDomainsSinglePass(conn *libvirt.Connect, filter filterFunc, logger lo
 		if err != nil {
 			return false, errors.Wrap(err, "get domain name")
 		}
-		if !filter(dName) {
+		if !filter(dName) || !report.ShouldDelete("domain", dName) {
 			continue
 		}
 
 		nothingToDelete = false
-		dState, _, err := domain.GetState()
-		if err != nil {
-			return false, errors.Wrapf(err, "get domain state %d", dName)
+		if err := deleteDomain(domain, dName, logger); err != nil {
+			report.Failed("domain", dName, err)
+			return false, err
 		}
+		report.Deleted("domain", dName)
+		logger.WithField("domain", dName).Info("Deleted domain")
+	}
 
-		if dState != libvirt.DOMAIN_SHUTOFF && dState != libvirt.DOMAIN_SHUTDOWN {
-			if err := domain.Destroy(); err != nil {
-				return false, errors.Wrapf(err, "destroy domain %q", dName)
-			}
+	return nothingToDelete, nil
+}
+
+func deleteDomain(domain libvirt.Domain, dName string, logger logrus.FieldLogger) error {
+	dState, _, err := domain.GetState()
+	if err != nil {
+		return errors.Wrapf(err, "get domain state %d", dName)
+	}
+
+	if dState != libvirt.DOMAIN_SHUTOFF && dState != libvirt.DOMAIN_SHUTDOWN {
+		if err := domain.Destroy(); err != nil {
+			return errors.Wrapf(err, "destroy domain %q", dName)
 		}
-		if err := domain.UndefineFlags(libvirt.DOMAIN_UNDEFINE_NVRAM); err != nil {
-			if e := err.(libvirt.Error); e.Code == libvirt.ERR_NO_SUPPORT || e.Code == libvirt.ERR_INVALID_ARG {
-				logger.WithField("domain", dName).Info("libvirt does not support undefine flags: will try again without flags")
-				if err := domain.Undefine(); err != nil {
-					return false, errors.Wrapf(err, "could not undefine libvirt domain: %q", dName)
-				}
-			} else {
-				return false, errors.Wrapf(err, "could not undefine libvirt domain %q with flags", dName)
+	}
+	if err := domain.UndefineFlags(libvirt.DOMAIN_UNDEFINE_NVRAM); err != nil {
+		if e := err.(libvirt.Error); e.Code == libvirt.ERR_NO_SUPPORT || e.Code == libvirt.ERR_INVALID_ARG {
+			logger.WithField("domain", dName).Info("libvirt does not support undefine flags: will try again without flags")
+			if err := domain.Undefine(); err != nil {
+				return errors.Wrapf(err, "could not undefine libvirt domain: %q", dName)
 			}
+		} else {
+			return errors.Wrapf(err, "could not undefine libvirt domain %q with flags", dName)
 		}
-		logger.WithField("domain", dName).Info("Deleted domain")
 	}
-
-	return nothingToDelete, nil
+	return nil
 }
 
-func deleteStoragePool(conn *libvirt.Connect, filter filterFunc, logger logrus.FieldLogger) error {
+func deleteStoragePool(conn *libvirt.Connect, filter filterFunc, logger logrus.FieldLogger, report *providers.Report) error {
 	logger.Debug("Deleting libvirt volumes")
 
 	pools, err := conn.ListStoragePools()
@@ -154,6 +173,11 @@ func deleteStoragePool(conn *libvirt.Connect, filter filterFunc, logger logrus.F
 			continue
 		}
 
+		// the volumes of the pool are deleted with it
+		if !report.ShouldDelete("pool", pname) {
+			continue
+		}
+
 		pool, err := conn.LookupStoragePoolByName(pname)
 		if err != nil {
 			return errors.Wrapf(err, "get storage pool %q", pname)
@@ -173,30 +197,39 @@ func deleteStoragePool(conn *libvirt.Connect, filter filterFunc, logger logrus.F
 				return errors.Wrapf(err, "get volume names in %q", pname)
 			}
 			if err := vol.Delete(0); err != nil {
-				return errors.Wrapf(err, "delete volume %q from %q", vName, pname)
+				err = errors.Wrapf(err, "delete volume %q from %q", vName, pname)
+				report.Failed("pool", pname, err)
+				return err
 			}
 			logger.WithField("volume", vName).Info("Deleted volume")
 		}
 
 		// blow away entire pool.
 		if err := pool.Destroy(); err != nil {
-			return errors.Wrapf(err, "destroy pool %q", pname)
+			err = errors.Wrapf(err, "destroy pool %q", pname)
+			report.Failed("pool", pname, err)
+			return err
 		}
 
 		if err := pool.Delete(0); err != nil {
-			return errors.Wrapf(err, "delete pool %q", pname)
+			err = errors.Wrapf(err, "delete pool %q", pname)
+			report.Failed("pool", pname, err)
+			return err
 		}
 
 		if err := pool.Undefine(); err != nil {
-			return errors.Wrapf(err, "undefine pool %q", pname)
+			err = errors.Wrapf(err, "undefine pool %q", pname)
+			report.Failed("pool", pname, err)
+			return err
 		}
+		report.Deleted("pool", pname)
 		logger.WithField("pool", pname).Info("Deleted pool")
 	}
 
 	return nil
 }
 
-func deleteNetwork(conn *libvirt.Connect, filter filterFunc, logger logrus.FieldLogger) error {
+func deleteNetwork(conn *libvirt.Connect, filter filterFunc, logger logrus.FieldLogger, report *providers.Report) error {
 	logger.Debug("Deleting libvirt network")
 
 	networks, err := conn.ListNetworks()
@@ -205,7 +238,7 @@ func deleteNetwork(conn *libvirt.Connect, filter filterFunc, logger logrus.Field
 	}
 
 	for _, nName := range networks {
-		if !filter(nName) {
+		if !filter(nName) || !report.ShouldDelete("network", nName) {
 			continue
 		}
 		network, err := conn.LookupNetworkByName(nName)
@@ -215,12 +248,17 @@ func deleteNetwork(conn *libvirt.Connect, filter filterFunc, logger logrus.Field
 		defer network.Free()
 
 		if err := network.Destroy(); err != nil {
-			return errors.Wrapf(err, "destroy network %q", nName)
+			err = errors.Wrapf(err, "destroy network %q", nName)
+			report.Failed("network", nName, err)
+			return err
 		}
 
 		if err := network.Undefine(); err != nil {
-			return errors.Wrapf(err, "undefine network %q", nName)
+			err = errors.Wrapf(err, "undefine network %q", nName)
+			report.Failed("network", nName, err)
+			return err
 		}
+		report.Deleted("network", nName)
 		logger.WithField("network", nName).Info("Deleted network")
 	}
 	return nil
@@ -22,12 +22,13 @@ const (
 	expectedCategoryValueOwned = "owned"
 )
 
-// clusterUninstaller holds the various options for the cluster we want to delete.
-type clusterUninstaller struct {
+// ClusterUninstaller holds the various options for the cluster we want to delete.
+type ClusterUninstaller struct {
 	clusterID string
 	infraID   string
 	v3Client  *nutanixclientv3.Client
 	logger    logrus.FieldLogger
+	report    *providers.Report
 }
 
 // New returns an Nutanix destroyer from ClusterMetadata.
@@ -42,7 +43,7 @@ func New(logger logrus.FieldLogger, metadata *installertypes.ClusterMetadata) (p
 		return nil, err
 	}
 
-	return &clusterUninstaller{
+	return &ClusterUninstaller{
 		clusterID: metadata.ClusterID,
 		infraID:   metadata.InfraID,
 		v3Client:  v3Client,
@@ -50,8 +51,14 @@ func New(logger logrus.FieldLogger, metadata *installertypes.ClusterMetadata) (p
 	}, nil
 }
 
+// Configure sets the options of the uninstall and the report to record the
+// resources in.
+func (o *ClusterUninstaller) Configure(options providers.Options, report *providers.Report) {
+	o.report = report
+}
+
 // Run is the entrypoint to start the uninstall process.
-func (o *clusterUninstaller) Run() (*installertypes.ClusterQuota, error) {
+func (o *ClusterUninstaller) Run() (*installertypes.ClusterQuota, error) {
 	o.logger.Infof("Starting deletion of Nutanix infrastructure for Openshift cluster %q", o.infraID)
 	err := wait.PollImmediateInfinite(time.Second*30, o.destroyCluster)
 	if err != nil {
@@ -61,10 +68,10 @@ func (o *clusterUninstaller) Run() (*installertypes.ClusterQuota, error) {
 	return nil, nil
 }
 
-func (o *clusterUninstaller) destroyCluster() (bool, error) {
+func (o *ClusterUninstaller) destroyCluster() (bool, error) {
 	cleanupFuncs := []struct {
 		name    string
-		execute func(*clusterUninstaller) error
+		execute func(*ClusterUninstaller) error
 	}{
 		{name: "VMs", execute: cleanupVMs},
 		{name: "Images", execute: cleanupImages},
@@ -81,7 +88,7 @@ func (o *clusterUninstaller) destroyCluster() (bool, error) {
 	return done, nil
 }
 
-func cleanupVMs(o *clusterUninstaller) error {
+func cleanupVMs(o *ClusterUninstaller) error {
 	matchedVirtualMachineList := make([]*nutanixclientv3.VMIntentResource, 0)
 	allVMs, err := o.v3Client.V3.ListAllVM(emptyFilter)
 	if err != nil {
@@ -89,7 +96,7 @@ func cleanupVMs(o *clusterUninstaller) error {
 	}
 
 	for _, v := range allVMs.Entities {
-		if hasCategoryOwned(v.Metadata, expectedCategoryKey(o.infraID)) {
+		if hasCategoryOwned(v.Metadata, expectedCategoryKey(o.infraID)) && o.report.ShouldDelete("vm", *v.Spec.Name) {
 			matchedVirtualMachineList = append(matchedVirtualMachineList, v)
 		}
 	}
@@ -98,7 +105,7 @@ func cleanupVMs(o *clusterUninstaller) error {
 		o.logger.Infof("No VMs found that require deletion for cluster %q", o.clusterID)
 	} else {
 		logToBeDeletedVMs(matchedVirtualMachineList, o.logger)
-		err := deleteVMs(o.v3Client.V3, matchedVirtualMachineList, o.logger)
+		err := deleteVMs(o.v3Client.V3, matchedVirtualMachineList, o.logger, o.report)
 		if err != nil {
 			return err
 		}
@@ -106,7 +113,7 @@ func cleanupVMs(o *clusterUninstaller) error {
 	return nil
 }
 
-func cleanupImages(o *clusterUninstaller) error {
+func cleanupImages(o *ClusterUninstaller) error {
 	allImages, err := o.v3Client.V3.ListAllImage(emptyFilter)
 	if err != nil {
 		return err
@@ -117,18 +124,25 @@ func cleanupImages(o *clusterUninstaller) error {
 		if hasCategoryOwned(image.Metadata, expectedCategoryKey(o.infraID)) {
 			imageName := *image.Spec.Name
 			imageUUID := *image.Metadata.UUID
+			if !o.report.ShouldDelete("image", imageName) {
+				continue
+			}
 			o.logger.Infof("Deleting image %q with UUID %q", imageName, imageUUID)
 			response, err := o.v3Client.V3.DeleteImage(imageUUID)
 			if err != nil {
 				o.logger.Errorf("Failed to delete image %q: %v", imageUUID, err)
+				o.report.Failed("image", imageName, err)
 				imageDeletionFailed = true
 				continue
 			}
 
 			if err := nutanixtypes.WaitForTask(o.v3Client.V3, response.Status.ExecutionContext.TaskUUID.(string)); err != nil {
 				o.logger.Errorf("Failed to confirm image deletion %q: %v", imageUUID, err)
+				o.report.Failed("image", imageName, err)
 				imageDeletionFailed = true
+				continue
 			}
+			o.report.Deleted("image", imageName)
 		}
 	}
 
@@ -139,7 +153,7 @@ func cleanupImages(o *clusterUninstaller) error {
 	return nil
 }
 
-func cleanupCategories(o *clusterUninstaller) error {
+func cleanupCategories(o *ClusterUninstaller) error {
 	expCatKey := expectedCategoryKey(o.infraID)
 	key, err := o.v3Client.V3.GetCategoryKey(expCatKey)
 	if err != nil {
@@ -149,6 +163,10 @@ func cleanupCategories(o *clusterUninstaller) error {
 		}
 		return err
 	}
+	// the values of the category are deleted with it
+	if !o.report.ShouldDelete("category", expCatKey) {
+		return nil
+	}
 
 	values, err := o.v3Client.V3.ListCategoryValues(*key.Name, &nutanixclientv3.CategoryListMetadata{})
 	if err != nil {
@@ -161,6 +179,7 @@ func cleanupCategories(o *clusterUninstaller) error {
 		err := o.v3Client.V3.DeleteCategoryValue(expCatKey, *value.Value)
 		if err != nil {
 			o.logger.Errorf("Failed to delete category value %q: %v", *value.Value, err)
+			o.report.Failed("category", expCatKey, err)
 			categoryDeletionFailed = true
 		}
 	}
@@ -169,6 +188,7 @@ func cleanupCategories(o *clusterUninstaller) error {
 	err = o.v3Client.V3.DeleteCategoryKey(expCatKey)
 	if err != nil {
 		o.logger.Errorf("Failed to delete category key %q: %v", expCatKey, err)
+		o.report.Failed("category", expCatKey, err)
 		categoryDeletionFailed = true
 	}
 
@@ -176,25 +196,36 @@ func cleanupCategories(o *clusterUninstaller) error {
 		return fmt.Errorf("failed to delete category")
 	}
 
+	o.report.Deleted("category", expCatKey)
 	return nil
 }
 
-func deleteVMs(clientV3 nutanixclientv3.Service, vms []*nutanixclientv3.VMIntentResource, l logrus.FieldLogger) error {
+func deleteVMs(clientV3 nutanixclientv3.Service, vms []*nutanixclientv3.VMIntentResource, l logrus.FieldLogger, report *providers.Report) error {
 	taskUUIDs := make([]string, 0)
+	deleting := make([]string, 0)
 	vmDeletionFailed := false
 	for _, vm := range vms {
 		l.Infof("Deleting VM %s with ID %s", *vm.Spec.Name, *vm.Metadata.UUID)
 		response, err := clientV3.DeleteVM(*vm.Metadata.UUID)
 		if err != nil {
 			l.Errorf("Failed to delete VM %q: %v", *vm.Metadata.UUID, err)
+			report.Failed("vm", *vm.Spec.Name, err)
 			vmDeletionFailed = true
 			continue
 		}
 
 		taskUUIDs = append(taskUUIDs, response.Status.ExecutionContext.TaskUUID.(string))
+		deleting = append(deleting, *vm.Spec.Name)
 	}
 
 	err := nutanixtypes.WaitForTasks(clientV3, taskUUIDs)
+	for _, name := range deleting {
+		if err != nil {
+			report.Failed("vm", name, err)
+		} else {
+			report.Deleted("vm", name)
+		}
+	}
 	if err != nil {
 		l.Errorf("Failed to confirm deletion of VMs: %v", err)
 		vmDeletionFailed = true
@@ -63,7 +63,7 @@ type ObjectWithTags struct {
 // deleteFunc type is the interface a function needs to implement to be called as a goroutine.
 // The (bool, error) return type mimics wait.ExponentialBackoff where the bool indicates successful
 // completion, and the error is for unrecoverable errors.
-type deleteFunc func(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error)
+type deleteFunc func(opts *clientconfig.ClientOpts, filter Filter, report *providers.Report, logger logrus.FieldLogger) (bool, error)
 
 // ClusterUninstaller holds the various options for the cluster we want to delete.
 type ClusterUninstaller struct {
@@ -74,6 +74,8 @@ type ClusterUninstaller struct {
 	// InfraID contains unique cluster identifier
 	InfraID string
 	Logger  logrus.FieldLogger
+
+	report *providers.Report
 }
 
 // New returns an OpenStack destroyer from ClusterMetadata.
@@ -86,6 +88,12 @@ func New(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (providers.
 	}, nil
 }
 
+// Configure sets the options of the uninstall and the report to record the
+// resources in.
+func (o *ClusterUninstaller) Configure(options providers.Options, report *providers.Report) {
+	o.report = report
+}
+
 // Run is the entrypoint to start the uninstall process.
 func (o *ClusterUninstaller) Run() (*types.ClusterQuota, error) {
 	opts := openstackdefaults.DefaultClientOpts(o.Cloud)
@@ -120,7 +128,7 @@ func (o *ClusterUninstaller) Run() (*types.ClusterQuota, error) {
 
 	// launch goroutines
 	for name, function := range deleteFuncs {
-		go deleteRunner(name, function, opts, o.Filter, o.Logger, returnChannel)
+		go deleteRunner(name, function, opts, o.Filter, o.report, o.Logger, returnChannel)
 	}
 
 	// wait for them to finish
@@ -132,13 +140,13 @@ func (o *ClusterUninstaller) Run() (*types.ClusterQuota, error) {
 	// we want to remove routers as the last thing as it requires detaching the
 	// FIPs and that will cause it impossible to track which FIPs are tied to
 	// LBs being deleted.
-	err := deleteRouterRunner(opts, o.Filter, o.Logger)
+	err := deleteRouterRunner(opts, o.Filter, o.report, o.Logger)
 	if err != nil {
 		return nil, err
 	}
 
 	// we need to untag the custom network if it was provided by the user
-	err = untagRunner(opts, o.InfraID, o.Logger)
+	err = untagRunner(opts, o.InfraID, o.report, o.Logger)
 	if err != nil {
 		return nil, err
 	}
@@ -146,7 +154,7 @@ func (o *ClusterUninstaller) Run() (*types.ClusterQuota, error) {
 	return nil, nil
 }
 
-func deleteRunner(deleteFuncName string, dFunction deleteFunc, opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger, channel chan string) {
+func deleteRunner(deleteFuncName string, dFunction deleteFunc, opts *clientconfig.ClientOpts, filter Filter, report *providers.Report, logger logrus.FieldLogger, channel chan string) {
 	backoffSettings := wait.Backoff{
 		Duration: time.Second * 15,
 		Factor:   1.3,
@@ -154,7 +162,7 @@ func deleteRunner(deleteFuncName string, dFunction deleteFunc, opts *clientconfi
 	}
 
 	err := wait.ExponentialBackoff(backoffSettings, func() (bool, error) {
-		return dFunction(opts, filter, logger)
+		return dFunction(opts, filter, report, logger)
 	})
 
 	if err != nil {
@@ -210,7 +218,7 @@ func filterTags(filters Filter) []string {
 	return tags
 }
 
-func deleteServers(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteServers(opts *clientconfig.ClientOpts, filter Filter, report *providers.Report, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack servers")
 	defer logger.Debugf("Exiting deleting openstack servers")
 
@@ -244,6 +252,10 @@ func deleteServers(opts *clientconfig.ClientOpts, filter Filter, logger logrus.F
 	numberToDelete := len(filteredServers)
 	numberDeleted := 0
 	for _, server := range filteredServers {
+		if !report.ShouldDelete("server", server.ID) {
+			numberDeleted++
+			continue
+		}
 		logger.Debugf("Deleting Server %q", server.ID)
 		err = servers.Delete(conn, server.ID).ExtractErr()
 		if err != nil {
@@ -251,17 +263,19 @@ func deleteServers(opts *clientconfig.ClientOpts, filter Filter, logger logrus.F
 			var gerr gophercloud.ErrDefault404
 			if !errors.As(err, &gerr) {
 				// Just log the error and move on to the next server
+				report.Failed("server", server.ID, err)
 				logger.Errorf("Deleting server %q failed: %v", server.ID, err)
 				continue
 			}
 			logger.Debugf("Cannot find server %q. It's probably already been deleted.", server.ID)
 		}
+		report.Deleted("server", server.ID)
 		numberDeleted++
 	}
 	return numberDeleted == numberToDelete, nil
 }
 
-func deleteServerGroups(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteServerGroups(opts *clientconfig.ClientOpts, filter Filter, report *providers.Report, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack server groups")
 	defer logger.Debugf("Exiting deleting openstack server groups")
 
@@ -303,6 +317,10 @@ func deleteServerGroups(opts *clientconfig.ClientOpts, filter Filter, logger log
 	numberToDelete := len(filteredGroups)
 	numberDeleted := 0
 	for _, serverGroup := range filteredGroups {
+		if !report.ShouldDelete("servergroup", serverGroup.ID) {
+			numberDeleted++
+			continue
+		}
 		logger.Debugf("Deleting Server Group %q", serverGroup.ID)
 		if err = servergroups.Delete(conn, serverGroup.ID).ExtractErr(); err != nil {
 			// Ignore the error if the server cannot be found and
@@ -311,23 +329,25 @@ func deleteServerGroups(opts *clientconfig.ClientOpts, filter Filter, logger log
 			var gerr gophercloud.ErrDefault404
 			if !errors.As(err, &gerr) {
 				// Just log the error and move on to the next server group
+				report.Failed("servergroup", serverGroup.ID, err)
 				logger.Errorf("Deleting server group %q failed: %v", serverGroup.ID, err)
 				continue
 			}
 			logger.Debugf("Cannot find server group %q. It's probably already been deleted.", serverGroup.ID)
 		}
+		report.Deleted("servergroup", serverGroup.ID)
 		numberDeleted++
 	}
 	return numberDeleted == numberToDelete, nil
 }
 
-func deletePortsByNetwork(opts *clientconfig.ClientOpts, networkID string, logger logrus.FieldLogger) (bool, error) {
+func deletePortsByNetwork(opts *clientconfig.ClientOpts, networkID string, report *providers.Report, logger logrus.FieldLogger) (bool, error) {
 
 	listOpts := ports.ListOpts{
 		NetworkID: networkID,
 	}
 
-	result, err := deletePorts(opts, listOpts, logger)
+	result, err := deletePorts(opts, listOpts, report, logger)
 	if err != nil {
 		logger.Error(err)
 		return false, nil
@@ -335,14 +355,14 @@ func deletePortsByNetwork(opts *clientconfig.ClientOpts, networkID string, logge
 	return result, err
 }
 
-func deletePortsByFilter(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deletePortsByFilter(opts *clientconfig.ClientOpts, filter Filter, report *providers.Report, logger logrus.FieldLogger) (bool, error) {
 
 	tags := filterTags(filter)
 	listOpts := ports.ListOpts{
 		TagsAny: strings.Join(tags, ","),
 	}
 
-	result, err := deletePorts(opts, listOpts, logger)
+	result, err := deletePorts(opts, listOpts, report, logger)
 	if err != nil {
 		logger.Error(err)
 		return false, nil
@@ -350,7 +370,7 @@ func deletePortsByFilter(opts *clientconfig.ClientOpts, filter Filter, logger lo
 	return result, err
 }
 
-func deletePorts(opts *clientconfig.ClientOpts, listOpts ports.ListOpts, logger logrus.FieldLogger) (bool, error) {
+func deletePorts(opts *clientconfig.ClientOpts, listOpts ports.ListOpts, report *providers.Report, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack ports")
 	defer logger.Debugf("Exiting deleting openstack ports")
 
@@ -395,6 +415,10 @@ func deletePorts(opts *clientconfig.ClientOpts, listOpts ports.ListOpts, logger
 	deletePortsWorker := func(portsChannel <-chan ports.Port, deletedChannel chan<- int) {
 		localDeleted := 0
 		for port := range portsChannel {
+			if !report.ShouldDelete("port", port.ID) {
+				localDeleted++
+				continue
+			}
 			// If a user provisioned floating ip was used, it needs to be dissociated.
 			// Any floating Ip's associated with ports that are going to be deleted will be dissociated.
 			if fip, ok := fipByPort[port.ID]; ok {
@@ -413,15 +437,17 @@ func deletePorts(opts *clientconfig.ClientOpts, listOpts ports.ListOpts, logger
 			}
 
 			logger.Debugf("Deleting Port %q", port.ID)
-			err = ports.Delete(conn, port.ID).ExtractErr()
+			err := ports.Delete(conn, port.ID).ExtractErr()
 			if err != nil {
 				// This can fail when port is still in use so return/retry
 				// Just log the error and move on to the next port
+				report.Failed("port", port.ID, err)
 				logger.Debugf("Deleting Port %q failed with error: %v", port.ID, err)
 				// Try to delete associated trunk
-				deleteAssociatedTrunk(conn, logger, port.ID)
+				deleteAssociatedTrunk(conn, report, logger, port.ID)
 				continue
 			}
+			report.Deleted("port", port.ID)
 			localDeleted++
 		}
 		deletedChannel <- localDeleted
@@ -450,7 +476,7 @@ func deletePorts(opts *clientconfig.ClientOpts, listOpts ports.ListOpts, logger
 	return numberDeleted == numberToDelete, nil
 }
 
-func deleteSecurityGroups(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteSecurityGroups(opts *clientconfig.ClientOpts, filter Filter, report *providers.Report, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack security-groups")
 	defer logger.Debugf("Exiting deleting openstack security-groups")
 
@@ -478,6 +504,10 @@ func deleteSecurityGroups(opts *clientconfig.ClientOpts, filter Filter, logger l
 	numberToDelete := len(allGroups)
 	numberDeleted := 0
 	for _, group := range allGroups {
+		if !report.ShouldDelete("securitygroup", group.ID) {
+			numberDeleted++
+			continue
+		}
 		logger.Debugf("Deleting Security Group: %q", group.ID)
 		err = sg.Delete(conn, group.ID).ExtractErr()
 		if err != nil {
@@ -486,11 +516,13 @@ func deleteSecurityGroups(opts *clientconfig.ClientOpts, filter Filter, logger l
 			if !errors.As(err, &gerr) {
 				// This can fail when sg is still in use by servers
 				// Just log the error and move on to the next security group
+				report.Failed("securitygroup", group.ID, err)
 				logger.Debugf("Deleting Security Group %q failed with error: %v", group.ID, err)
 				continue
 			}
 			logger.Debugf("Cannot find security group %q. It's probably already been deleted.", group.ID)
 		}
+		report.Deleted("securitygroup", group.ID)
 		numberDeleted++
 	}
 	return numberDeleted == numberToDelete, nil
@@ -519,7 +551,7 @@ func updateFips(allFIPs []floatingips.FloatingIP, opts *clientconfig.ClientOpts,
 }
 
 // deletePortFIPs looks up FIPs associated to the port and attempts to delete them
-func deletePortFIPs(portID string, opts *clientconfig.ClientOpts, logger logrus.FieldLogger) error {
+func deletePortFIPs(portID string, opts *clientconfig.ClientOpts, report *providers.Report, logger logrus.FieldLogger) error {
 	conn, err := clientconfig.NewServiceClient("network", opts)
 	if err != nil {
 		return err
@@ -539,17 +571,22 @@ func deletePortFIPs(portID string, opts *clientconfig.ClientOpts, logger logrus.
 	}
 
 	for _, fip := range fips {
+		if !report.ShouldDelete("floatingip", fip.ID) {
+			continue
+		}
 		logger.Debugf("Deleting FIP %q", fip.ID)
 		err = floatingips.Delete(conn, fip.ID).ExtractErr()
 		if err != nil {
 			// Ignore the error if the FIP cannot be found
 			var gerr gophercloud.ErrDefault404
 			if !errors.As(err, &gerr) {
+				report.Failed("floatingip", fip.ID, err)
 				logger.Errorf("Deleting FIP %q failed: %v", fip.ID, err)
 				return err
 			}
 			logger.Debugf("Cannot find FIP %q. It's probably already been deleted.", fip.ID)
 		}
+		report.Deleted("floatingip", fip.ID)
 	}
 	return nil
 }
@@ -576,7 +613,7 @@ func getRouters(opts *clientconfig.ClientOpts, filter Filter, logger logrus.Fiel
 	return allRouters, nil
 }
 
-func deleteRouters(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteRouters(opts *clientconfig.ClientOpts, filter Filter, report *providers.Report, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack routers")
 	defer logger.Debugf("Exiting deleting openstack routers")
 
@@ -595,6 +632,10 @@ func deleteRouters(opts *clientconfig.ClientOpts, filter Filter, logger logrus.F
 	numberToDelete := len(allRouters)
 	numberDeleted := 0
 	for _, router := range allRouters {
+		if !report.ShouldDelete("router", router.ID) {
+			numberDeleted++
+			continue
+		}
 		fipOpts := floatingips.ListOpts{
 			RouterID: router.ID,
 		}
@@ -634,11 +675,13 @@ func deleteRouters(opts *clientconfig.ClientOpts, filter Filter, logger logrus.F
 			var gerr gophercloud.ErrDefault404
 			if !errors.As(err, &gerr) {
 				// Just log the error and move on to the next router
+				report.Failed("router", router.ID, err)
 				logger.Errorf("Deleting router %q failed: %v", router.ID, err)
 				continue
 			}
 			logger.Debugf("Cannot find router %q. It's probably already been deleted.", router.ID)
 		}
+		report.Deleted("router", router.ID)
 		numberDeleted++
 	}
 	return numberDeleted == numberToDelete, nil
@@ -689,7 +732,7 @@ func getRouterInterfaces(conn *gophercloud.ServiceClient, allNetworks []networks
 	return routerPorts, nil
 }
 
-func clearRouterInterfaces(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func clearRouterInterfaces(opts *clientconfig.ClientOpts, filter Filter, report *providers.Report, logger logrus.FieldLogger) (bool, error) {
 	logger.Debugf("Removing interfaces from router")
 	defer logger.Debug("Exiting removal of interfaces from router")
 	conn, err := clientconfig.NewServiceClient("network", opts)
@@ -734,7 +777,7 @@ func clearRouterInterfaces(opts *clientconfig.ClientOpts, filter Filter, logger
 		return false, nil
 	}
 
-	removed, err := removeRouterInterfaces(conn, filter, *router, logger)
+	removed, err := removeRouterInterfaces(conn, filter, *router, report, logger)
 	if err != nil {
 		logger.Debug(err)
 		return false, nil
@@ -742,7 +785,7 @@ func clearRouterInterfaces(opts *clientconfig.ClientOpts, filter Filter, logger
 	return removed, nil
 }
 
-func removeRouterInterfaces(client *gophercloud.ServiceClient, filter Filter, router routers.Router, logger logrus.FieldLogger) (bool, error) {
+func removeRouterInterfaces(client *gophercloud.ServiceClient, filter Filter, router routers.Router, report *providers.Report, logger logrus.FieldLogger) (bool, error) {
 	// Get router interface ports
 	portListOpts := ports.ListOpts{
 		DeviceID: router.ID,
@@ -792,6 +835,11 @@ func removeRouterInterfaces(client *gophercloud.ServiceClient, filter Filter, ro
 				continue
 			}
 			if !removedSubnets[IP.SubnetID] {
+				if !report.ShouldDelete("router:interface", port.ID) {
+					removedSubnets[IP.SubnetID] = true
+					numberDeleted++
+					continue
+				}
 				removeOpts := routers.RemoveInterfaceOpts{
 					SubnetID: IP.SubnetID,
 				}
@@ -801,11 +849,13 @@ func removeRouterInterfaces(client *gophercloud.ServiceClient, filter Filter, ro
 					var gerr gophercloud.ErrDefault404
 					if !errors.As(err, &gerr) {
 						// This can fail when subnet is still in use
+						report.Failed("router:interface", port.ID, err)
 						logger.Debugf("Removing Subnet %q from Router %q failed: %v", IP.SubnetID, router.ID, err)
 						return false, nil
 					}
 					logger.Debugf("Cannot find subnet %q. It's probably already been removed from router %q.", IP.SubnetID, router.ID)
 				}
+				report.Deleted("router:interface", port.ID)
 				removedSubnets[IP.SubnetID] = true
 				numberDeleted++
 			}
@@ -846,7 +896,7 @@ func getRouterByPort(client *gophercloud.ServiceClient, allPorts []ports.Port) (
 	return empty, nil
 }
 
-func deleteLeftoverLoadBalancers(opts *clientconfig.ClientOpts, logger logrus.FieldLogger, networkID string) error {
+func deleteLeftoverLoadBalancers(opts *clientconfig.ClientOpts, report *providers.Report, logger logrus.FieldLogger, networkID string) error {
 	conn, err := clientconfig.NewServiceClient("load-balancer", opts)
 	if err != nil {
 		// Ignore the error if Octavia is not available for the cloud
@@ -882,10 +932,14 @@ func deleteLeftoverLoadBalancers(opts *clientconfig.ClientOpts, logger logrus.Fi
 			logger.Debugf("Not deleting LoadBalancer %q with description %q", loadbalancer.ID, loadbalancer.Description)
 			continue
 		}
+		if !report.ShouldDelete("loadbalancer", loadbalancer.ID) {
+			deleted++
+			continue
+		}
 		logger.Debugf("Deleting LoadBalancer %q", loadbalancer.ID)
 
 		// Cascade delete of an LB won't remove the associated FIP, we have to do it ourselves.
-		err := deletePortFIPs(loadbalancer.VipPortID, opts, logger)
+		err := deletePortFIPs(loadbalancer.VipPortID, opts, report, logger)
 		if err != nil {
 			// Go to the next LB, but do not delete current one or we'll lose reference to the FIP that failed deletion.
 			continue
@@ -898,11 +952,13 @@ func deleteLeftoverLoadBalancers(opts *clientconfig.ClientOpts, logger logrus.Fi
 			if !errors.As(err, &gerr) {
 				// This can fail when the load balancer is still in use so return/retry
 				// Just log the error and move on to the next LB
+				report.Failed("loadbalancer", loadbalancer.ID, err)
 				logger.Debugf("Deleting load balancer %q failed: %v", loadbalancer.ID, err)
 				continue
 			}
 			logger.Debugf("Cannot find load balancer %q. It's probably already been deleted.", loadbalancer.ID)
 		}
+		report.Deleted("loadbalancer", loadbalancer.ID)
 		deleted++
 	}
 
@@ -921,7 +977,7 @@ func isClusterSubnet(subnets []subnets.Subnet, subnetID string) bool {
 	return false
 }
 
-func deleteSubnets(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteSubnets(opts *clientconfig.ClientOpts, filter Filter, report *providers.Report, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack subnets")
 	defer logger.Debugf("Exiting deleting openstack subnets")
 
@@ -950,6 +1006,10 @@ func deleteSubnets(opts *clientconfig.ClientOpts, filter Filter, logger logrus.F
 	numberToDelete := len(allSubnets)
 	numberDeleted := 0
 	for _, subnet := range allSubnets {
+		if !report.ShouldDelete("subnet", subnet.ID) {
+			numberDeleted++
+			continue
+		}
 		logger.Debugf("Deleting Subnet: %q", subnet.ID)
 		err = subnets.Delete(conn, subnet.ID).ExtractErr()
 		if err != nil {
@@ -958,17 +1018,19 @@ func deleteSubnets(opts *clientconfig.ClientOpts, filter Filter, logger logrus.F
 			if !errors.As(err, &gerr) {
 				// This can fail when subnet is still in use
 				// Just log the error and move on to the next subnet
+				report.Failed("subnet", subnet.ID, err)
 				logger.Debugf("Deleting Subnet %q failed: %v", subnet.ID, err)
 				continue
 			}
 			logger.Debugf("Cannot find subnet %q. It's probably already been deleted.", subnet.ID)
 		}
+		report.Deleted("subnet", subnet.ID)
 		numberDeleted++
 	}
 	return numberDeleted == numberToDelete, nil
 }
 
-func deleteNetworks(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteNetworks(opts *clientconfig.ClientOpts, filter Filter, report *providers.Report, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack networks")
 	defer logger.Debugf("Exiting deleting openstack networks")
 
@@ -996,6 +1058,10 @@ func deleteNetworks(opts *clientconfig.ClientOpts, filter Filter, logger logrus.
 	numberToDelete := len(allNetworks)
 	numberDeleted := 0
 	for _, network := range allNetworks {
+		if !report.ShouldDelete("network", network.ID) {
+			numberDeleted++
+			continue
+		}
 		logger.Debugf("Deleting network: %q", network.ID)
 		err = networks.Delete(conn, network.ID).ExtractErr()
 		if err != nil {
@@ -1003,11 +1069,12 @@ func deleteNetworks(opts *clientconfig.ClientOpts, filter Filter, logger logrus.
 			var gerr gophercloud.ErrDefault404
 			if !errors.As(err, &gerr) {
 				// This can fail when network is still in use. Let's log an error and try to fix this.
+				report.Failed("network", network.ID, err)
 				logger.Debugf("Deleting Network %q failed: %v", network.ID, err)
 
 				// First try to delete eventual leftover load balancers
 				// *This has to be done before attempt to remove ports or we'll delete LB ports!*
-				err := deleteLeftoverLoadBalancers(opts, logger, network.ID)
+				err := deleteLeftoverLoadBalancers(opts, report, logger, network.ID)
 				if err != nil {
 					logger.Error(err)
 					// Do not attempt to delete ports on LB removal problem or we'll lose FIP associations!
@@ -1016,7 +1083,7 @@ func deleteNetworks(opts *clientconfig.ClientOpts, filter Filter, logger logrus.
 
 				// Only then try to remove all the ports it may still contain (untagged as well).
 				// *We cannot delete ports before LBs because we'll lose FIP associations!*
-				_, err = deletePortsByNetwork(opts, network.ID, logger)
+				_, err = deletePortsByNetwork(opts, network.ID, report, logger)
 				if err != nil {
 					logger.Error(err)
 				}
@@ -1024,12 +1091,13 @@ func deleteNetworks(opts *clientconfig.ClientOpts, filter Filter, logger logrus.
 			}
 			logger.Debugf("Cannot find network %q. It's probably already been deleted.", network.ID)
 		}
+		report.Deleted("network", network.ID)
 		numberDeleted++
 	}
 	return numberDeleted == numberToDelete, nil
 }
 
-func deleteContainers(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteContainers(opts *clientconfig.ClientOpts, filter Filter, report *providers.Report, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack containers")
 	defer logger.Debugf("Exiting deleting openstack containers")
 
@@ -1091,6 +1159,9 @@ func deleteContainers(opts *clientconfig.ClientOpts, filter Filter, logger logru
 			// Openshiftclusterid in the X-Container-Meta- HEAD output
 			titlekey := strings.Title(strings.ToLower(key))
 			if metadata[titlekey] == val {
+				if !report.ShouldDelete("container", container) {
+					break
+				}
 				logger.Debugf("Bulk deleting container %q objects", container)
 				pager := objects.List(conn, container, &objects.ListOpts{
 					Full:  false,
@@ -1122,6 +1193,7 @@ func deleteContainers(opts *clientconfig.ClientOpts, filter Filter, logger logru
 				if err != nil {
 					var gerr gophercloud.ErrDefault404
 					if !errors.As(err, &gerr) {
+						report.Failed("container", container, err)
 						logger.Errorf("Bulk deleting of container %q objects failed: %v", container, err)
 						return false, nil
 					}
@@ -1132,11 +1204,13 @@ func deleteContainers(opts *clientconfig.ClientOpts, filter Filter, logger logru
 					// Ignore the error if the container cannot be found and return with an appropriate message if it's another type of error
 					var gerr gophercloud.ErrDefault404
 					if !errors.As(err, &gerr) {
+						report.Failed("container", container, err)
 						logger.Errorf("Deleting container %q failed: %v", container, err)
 						return false, nil
 					}
 					logger.Debugf("Cannot find container %q. It's probably already been deleted.", container)
 				}
+				report.Deleted("container", container)
 				// If a metadata key matched, we're done so break from the loop
 				break
 			}
@@ -1145,7 +1219,7 @@ func deleteContainers(opts *clientconfig.ClientOpts, filter Filter, logger logru
 	return true, nil
 }
 
-func deleteTrunks(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteTrunks(opts *clientconfig.ClientOpts, filter Filter, report *providers.Report, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack trunks")
 	defer logger.Debugf("Exiting deleting openstack trunks")
 
@@ -1178,6 +1252,10 @@ func deleteTrunks(opts *clientconfig.ClientOpts, filter Filter, logger logrus.Fi
 	numberToDelete := len(allTrunks)
 	numberDeleted := 0
 	for _, trunk := range allTrunks {
+		if !report.ShouldDelete("trunk", trunk.ID) {
+			numberDeleted++
+			continue
+		}
 		logger.Debugf("Deleting Trunk %q", trunk.ID)
 		err = trunks.Delete(conn, trunk.ID).ExtractErr()
 		if err != nil {
@@ -1186,17 +1264,19 @@ func deleteTrunks(opts *clientconfig.ClientOpts, filter Filter, logger logrus.Fi
 			if !errors.As(err, &gerr) {
 				// This can fail when the trunk is still in use so return/retry
 				// Just log the error and move on to the next trunk
+				report.Failed("trunk", trunk.ID, err)
 				logger.Debugf("Deleting Trunk %q failed: %v", trunk.ID, err)
 				continue
 			}
 			logger.Debugf("Cannot find trunk %q. It's probably already been deleted.", trunk.ID)
 		}
+		report.Deleted("trunk", trunk.ID)
 		numberDeleted++
 	}
 	return numberDeleted == numberToDelete, nil
 }
 
-func deleteAssociatedTrunk(conn *gophercloud.ServiceClient, logger logrus.FieldLogger, portID string) {
+func deleteAssociatedTrunk(conn *gophercloud.ServiceClient, report *providers.Report, logger logrus.FieldLogger, portID string) {
 	logger.Debug("Deleting associated trunk")
 	defer logger.Debugf("Exiting deleting associated trunk")
 
@@ -1220,6 +1300,9 @@ func deleteAssociatedTrunk(conn *gophercloud.ServiceClient, logger logrus.FieldL
 		return
 	}
 	for _, trunk := range allTrunks {
+		if !report.ShouldDelete("trunk", trunk.ID) {
+			continue
+		}
 		logger.Debugf("Deleting Trunk %q", trunk.ID)
 		err = trunks.Delete(conn, trunk.ID).ExtractErr()
 		if err != nil {
@@ -1228,16 +1311,18 @@ func deleteAssociatedTrunk(conn *gophercloud.ServiceClient, logger logrus.FieldL
 			if !errors.As(err, &gerr) {
 				// This can fail when the trunk is still in use so return/retry
 				// Just log the error and move on to the next trunk
+				report.Failed("trunk", trunk.ID, err)
 				logger.Debugf("Deleting Trunk %q failed: %v", trunk.ID, err)
 				continue
 			}
 			logger.Debugf("Cannot find trunk %q. It's probably already been deleted.", trunk.ID)
 		}
+		report.Deleted("trunk", trunk.ID)
 	}
 	return
 }
 
-func deleteLoadBalancers(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteLoadBalancers(opts *clientconfig.ClientOpts, filter Filter, report *providers.Report, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack load balancers")
 	defer logger.Debugf("Exiting deleting openstack load balancers")
 
@@ -1315,6 +1400,10 @@ func deleteLoadBalancers(opts *clientconfig.ClientOpts, filter Filter, logger lo
 	numberToDelete := len(allLoadBalancers)
 	numberDeleted := 0
 	for _, loadbalancer := range allLoadBalancers {
+		if !report.ShouldDelete("loadbalancer", loadbalancer.ID) {
+			numberDeleted++
+			continue
+		}
 		logger.Debugf("Deleting LoadBalancer %q", loadbalancer.ID)
 		err = loadbalancers.Delete(conn, loadbalancer.ID, deleteOpts).ExtractErr()
 		if err != nil {
@@ -1323,18 +1412,20 @@ func deleteLoadBalancers(opts *clientconfig.ClientOpts, filter Filter, logger lo
 			if !errors.As(err, &gerr) {
 				// This can fail when the load balancer is still in use so return/retry
 				// Just log the error and move on to the next port
+				report.Failed("loadbalancer", loadbalancer.ID, err)
 				logger.Debugf("Deleting load balancer %q failed: %v", loadbalancer.ID, err)
 				continue
 			}
 			logger.Debugf("Cannot find load balancer %q. It's probably already been deleted.", loadbalancer.ID)
 		}
+		report.Deleted("loadbalancer", loadbalancer.ID)
 		numberDeleted++
 	}
 
 	return numberDeleted == numberToDelete, nil
 }
 
-func deleteSubnetPools(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteSubnetPools(opts *clientconfig.ClientOpts, filter Filter, report *providers.Report, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack subnet-pools")
 	defer logger.Debugf("Exiting deleting openstack subnet-pools")
 
@@ -1362,6 +1453,10 @@ func deleteSubnetPools(opts *clientconfig.ClientOpts, filter Filter, logger logr
 	numberToDelete := len(allSubnetPools)
 	numberDeleted := 0
 	for _, subnetPool := range allSubnetPools {
+		if !report.ShouldDelete("subnetpool", subnetPool.ID) {
+			numberDeleted++
+			continue
+		}
 		logger.Debugf("Deleting Subnet Pool %q", subnetPool.ID)
 		err = subnetpools.Delete(conn, subnetPool.ID).ExtractErr()
 		if err != nil {
@@ -1369,17 +1464,19 @@ func deleteSubnetPools(opts *clientconfig.ClientOpts, filter Filter, logger logr
 			var gerr gophercloud.ErrDefault404
 			if !errors.As(err, &gerr) {
 				// Just log the error and move on to the next subnet pool
+				report.Failed("subnetpool", subnetPool.ID, err)
 				logger.Debugf("Deleting subnet pool %q failed: %v", subnetPool.ID, err)
 				continue
 			}
 			logger.Debugf("Cannot find subnet pool %q. It's probably already been deleted.", subnetPool.ID)
 		}
+		report.Deleted("subnetpool", subnetPool.ID)
 		numberDeleted++
 	}
 	return numberDeleted == numberToDelete, nil
 }
 
-func deleteVolumes(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteVolumes(opts *clientconfig.ClientOpts, filter Filter, report *providers.Report, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting OpenStack volumes")
 	defer logger.Debugf("Exiting deleting OpenStack volumes")
 
@@ -1432,6 +1529,10 @@ func deleteVolumes(opts *clientconfig.ClientOpts, filter Filter, logger logrus.F
 	numberToDelete := len(volumeIDs)
 	numberDeleted := 0
 	for _, volumeID := range volumeIDs {
+		if !report.ShouldDelete("volume", volumeID) {
+			numberDeleted++
+			continue
+		}
 		logger.Debugf("Deleting volume %q", volumeID)
 		err = volumes.Delete(conn, volumeID, deleteOpts).ExtractErr()
 		if err != nil {
@@ -1439,18 +1540,20 @@ func deleteVolumes(opts *clientconfig.ClientOpts, filter Filter, logger logrus.F
 			var gerr gophercloud.ErrDefault404
 			if !errors.As(err, &gerr) {
 				// Just log the error and move on to the next volume
+				report.Failed("volume", volumeID, err)
 				logger.Debugf("Deleting volume %q failed: %v", volumeID, err)
 				continue
 			}
 			logger.Debugf("Cannot find volume %q. It's probably already been deleted.", volumeID)
 		}
+		report.Deleted("volume", volumeID)
 		numberDeleted++
 	}
 
 	return numberDeleted == numberToDelete, nil
 }
 
-func deleteVolumeSnapshots(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteVolumeSnapshots(opts *clientconfig.ClientOpts, filter Filter, report *providers.Report, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting OpenStack volume snapshots")
 	defer logger.Debugf("Exiting deleting OpenStack volume snapshots")
 
@@ -1487,6 +1590,10 @@ func deleteVolumeSnapshots(opts *clientconfig.ClientOpts, filter Filter, logger
 	for _, snapshot := range allSnapshots {
 		// Delete only those snapshots that contain cluster ID in the metadata
 		if val, ok := snapshot.Metadata[cinderCSIClusterIDKey]; ok && val == clusterID {
+			if !report.ShouldDelete("volumesnapshot", snapshot.ID) {
+				numberDeleted++
+				continue
+			}
 			logger.Debugf("Deleting volume snapshot %q", snapshot.ID)
 			err = snapshots.Delete(conn, snapshot.ID).ExtractErr()
 			if err != nil {
@@ -1494,11 +1601,13 @@ func deleteVolumeSnapshots(opts *clientconfig.ClientOpts, filter Filter, logger
 				var gerr gophercloud.ErrDefault404
 				if !errors.As(err, &gerr) {
 					// Just log the error and move on to the next volume snapshot
+					report.Failed("volumesnapshot", snapshot.ID, err)
 					logger.Debugf("Deleting volume snapshot %q failed: %v", snapshot.ID, err)
 					continue
 				}
 				logger.Debugf("Cannot find volume snapshot %q. It's probably already been deleted.", snapshot.ID)
 			}
+			report.Deleted("volumesnapshot", snapshot.ID)
 		}
 		numberDeleted++
 	}
@@ -1506,7 +1615,7 @@ func deleteVolumeSnapshots(opts *clientconfig.ClientOpts, filter Filter, logger
 	return numberDeleted == numberToDelete, nil
 }
 
-func deleteShares(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteShares(opts *clientconfig.ClientOpts, filter Filter, report *providers.Report, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting OpenStack shares")
 	defer logger.Debugf("Exiting deleting OpenStack shares")
 
@@ -1549,7 +1658,7 @@ func deleteShares(opts *clientconfig.ClientOpts, filter Filter, logger logrus.Fi
 	numberToDelete := len(allShares)
 	numberDeleted := 0
 	for _, share := range allShares {
-		deleted, err := deleteShareSnapshots(conn, share.ID, logger)
+		deleted, err := deleteShareSnapshots(conn, share.ID, report, logger)
 		if err != nil {
 			return false, err
 		}
@@ -1557,6 +1666,10 @@ func deleteShares(opts *clientconfig.ClientOpts, filter Filter, logger logrus.Fi
 			return false, nil
 		}
 
+		if !report.ShouldDelete("share", share.ID) {
+			numberDeleted++
+			continue
+		}
 		logger.Debugf("Deleting share %q", share.ID)
 		err = shares.Delete(conn, share.ID).ExtractErr()
 		if err != nil {
@@ -1564,18 +1677,20 @@ func deleteShares(opts *clientconfig.ClientOpts, filter Filter, logger logrus.Fi
 			var gerr gophercloud.ErrDefault404
 			if !errors.As(err, &gerr) {
 				// Just log the error and move on to the next share
+				report.Failed("share", share.ID, err)
 				logger.Debugf("Deleting share %q failed: %v", share.ID, err)
 				continue
 			}
 			logger.Debugf("Cannot find share %q. It's probably already been deleted.", share.ID)
 		}
+		report.Deleted("share", share.ID)
 		numberDeleted++
 	}
 
 	return numberDeleted == numberToDelete, nil
 }
 
-func deleteShareSnapshots(conn *gophercloud.ServiceClient, shareID string, logger logrus.FieldLogger) (bool, error) {
+func deleteShareSnapshots(conn *gophercloud.ServiceClient, shareID string, report *providers.Report, logger logrus.FieldLogger) (bool, error) {
 	logger.Debugf("Deleting OpenStack snapshots for share %v", shareID)
 	defer logger.Debugf("Exiting deleting OpenStack snapshots for share %v", shareID)
 
@@ -1598,6 +1713,10 @@ func deleteShareSnapshots(conn *gophercloud.ServiceClient, shareID string, logge
 	numberToDelete := len(allSnapshots)
 	numberDeleted := 0
 	for _, snapshot := range allSnapshots {
+		if !report.ShouldDelete("share:snapshot", snapshot.ID) {
+			numberDeleted++
+			continue
+		}
 		logger.Debugf("Deleting share snapshot %q", snapshot.ID)
 		err = sharesnapshots.Delete(conn, snapshot.ID).ExtractErr()
 		if err != nil {
@@ -1605,18 +1724,20 @@ func deleteShareSnapshots(conn *gophercloud.ServiceClient, shareID string, logge
 			var gerr gophercloud.ErrDefault404
 			if !errors.As(err, &gerr) {
 				// Just log the error and move on to the next share snapshot
+				report.Failed("share:snapshot", snapshot.ID, err)
 				logger.Debugf("Deleting share snapshot %q failed: %v", snapshot.ID, err)
 				continue
 			}
 			logger.Debugf("Cannot find share snapshot %q. It's probably already been deleted.", snapshot.ID)
 		}
+		report.Deleted("share:snapshot", snapshot.ID)
 		numberDeleted++
 	}
 
 	return numberDeleted == numberToDelete, nil
 }
 
-func deleteFloatingIPs(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteFloatingIPs(opts *clientconfig.ClientOpts, filter Filter, report *providers.Report, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack floating ips")
 	defer logger.Debugf("Exiting deleting openstack floating ips")
 
@@ -1645,6 +1766,10 @@ func deleteFloatingIPs(opts *clientconfig.ClientOpts, filter Filter, logger logr
 	numberToDelete := len(allFloatingIPs)
 	numberDeleted := 0
 	for _, floatingIP := range allFloatingIPs {
+		if !report.ShouldDelete("floatingip", floatingIP.ID) {
+			numberDeleted++
+			continue
+		}
 		logger.Debugf("Deleting Floating IP %q", floatingIP.ID)
 		err = floatingips.Delete(conn, floatingIP.ID).ExtractErr()
 		if err != nil {
@@ -1652,17 +1777,19 @@ func deleteFloatingIPs(opts *clientconfig.ClientOpts, filter Filter, logger logr
 			var gerr gophercloud.ErrDefault404
 			if !errors.As(err, &gerr) {
 				// Just log the error and move on to the next floating IP
+				report.Failed("floatingip", floatingIP.ID, err)
 				logger.Debugf("Deleting floating ip %q failed: %v", floatingIP.ID, err)
 				continue
 			}
 			logger.Debugf("Cannot find floating ip %q. It's probably already been deleted.", floatingIP.ID)
 		}
+		report.Deleted("floatingip", floatingIP.ID)
 		numberDeleted++
 	}
 	return numberDeleted == numberToDelete, nil
 }
 
-func deleteImages(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteImages(opts *clientconfig.ClientOpts, filter Filter, report *providers.Report, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack base image")
 	defer logger.Debugf("Exiting deleting openstack base image")
 
@@ -1691,27 +1818,33 @@ func deleteImages(opts *clientconfig.ClientOpts, filter Filter, logger logrus.Fi
 	numberToDelete := len(allImages)
 	numberDeleted := 0
 	for _, image := range allImages {
+		if !report.ShouldDelete("image", image.ID) {
+			numberDeleted++
+			continue
+		}
 		logger.Debugf("Deleting image: %+v", image.ID)
 		err := images.Delete(conn, image.ID).ExtractErr()
 		if err != nil {
 			// This can fail if the image is still in use by other VMs
 			// Just log the error and move on to the next image
+			report.Failed("image", image.ID, err)
 			logger.Debugf("Deleting Image failed: %v", err)
 			continue
 		}
+		report.Deleted("image", image.ID)
 		numberDeleted++
 	}
 	return numberDeleted == numberToDelete, nil
 }
 
-func untagRunner(opts *clientconfig.ClientOpts, infraID string, logger logrus.FieldLogger) error {
+func untagRunner(opts *clientconfig.ClientOpts, infraID string, report *providers.Report, logger logrus.FieldLogger) error {
 	backoffSettings := wait.Backoff{
 		Duration: time.Second * 10,
 		Steps:    25,
 	}
 
 	err := wait.ExponentialBackoff(backoffSettings, func() (bool, error) {
-		return untagPrimaryNetwork(opts, infraID, logger)
+		return untagPrimaryNetwork(opts, infraID, report, logger)
 	})
 	if err != nil {
 		if err == wait.ErrWaitTimeout {
@@ -1723,7 +1856,7 @@ func untagRunner(opts *clientconfig.ClientOpts, infraID string, logger logrus.Fi
 	return nil
 }
 
-func deleteRouterRunner(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) error {
+func deleteRouterRunner(opts *clientconfig.ClientOpts, filter Filter, report *providers.Report, logger logrus.FieldLogger) error {
 	backoffSettings := wait.Backoff{
 		Duration: time.Second * 15,
 		Factor:   1.3,
@@ -1731,7 +1864,7 @@ func deleteRouterRunner(opts *clientconfig.ClientOpts, filter Filter, logger log
 	}
 
 	err := wait.ExponentialBackoff(backoffSettings, func() (bool, error) {
-		return deleteRouters(opts, filter, logger)
+		return deleteRouters(opts, filter, report, logger)
 	})
 	if err != nil {
 		if err == wait.ErrWaitTimeout {
@@ -1744,7 +1877,7 @@ func deleteRouterRunner(opts *clientconfig.ClientOpts, filter Filter, logger log
 }
 
 // untagNetwork removes the tag from the primary cluster network based on unfra id
-func untagPrimaryNetwork(opts *clientconfig.ClientOpts, infraID string, logger logrus.FieldLogger) (bool, error) {
+func untagPrimaryNetwork(opts *clientconfig.ClientOpts, infraID string, report *providers.Report, logger logrus.FieldLogger) (bool, error) {
 	networkTag := infraID + "-primaryClusterNetwork"
 
 	logger.Debugf("Removing tag %v from openstack networks", networkTag)
@@ -1781,10 +1914,15 @@ func untagPrimaryNetwork(opts *clientconfig.ClientOpts, infraID string, logger l
 		return true, nil
 	}
 
+	if !report.ShouldDelete("network:tag", allNetworks[0].ID) {
+		return true, nil
+	}
 	err = attributestags.Delete(conn, "networks", allNetworks[0].ID, networkTag).ExtractErr()
 	if err != nil {
+		report.Failed("network:tag", allNetworks[0].ID, err)
 		return false, nil
 	}
+	report.Deleted("network:tag", allNetworks[0].ID)
 
 	return true, nil
 }
@@ -0,0 +1,90 @@
+package destroy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+	"github.com/openshift/installer/pkg/types"
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+	gcptypes "github.com/openshift/installer/pkg/types/gcp"
+)
+
+// Orphan identifies the resources of a cluster whose asset directory was lost
+// by the tags and labels the installer puts on them.
+type Orphan struct {
+	// Platform is the platform of the cluster, e.g. aws.
+	Platform string
+	// InfraID is the infra ID of the cluster.
+	InfraID string
+	// Region is the region of the cluster.
+	Region string
+	// ProjectID is the GCP project of the cluster.
+	ProjectID string
+}
+
+// OrphanPlatforms are the platforms whose resources can be found by the infra
+// ID of the cluster alone. The clusters of the other platforms can only be
+// destroyed from their assets directory.
+var OrphanPlatforms = []string{awstypes.Name, gcptypes.Name}
+
+// unsupportedOrphanPlatform returns the error of finding the resources of an
+// orphan on a platform which is not one of OrphanPlatforms.
+func unsupportedOrphanPlatform(platform string) error {
+	return errors.Errorf("destroying the resources of an infra ID is not supported on the %q platform, only on %s; destroy the cluster from its assets directory instead", platform, strings.Join(OrphanPlatforms, ", "))
+}
+
+// Metadata returns the cluster metadata matching the resources of the orphan.
+func (o Orphan) Metadata() (*types.ClusterMetadata, error) {
+	supported := false
+	for _, platform := range OrphanPlatforms {
+		if o.Platform == platform {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return nil, unsupportedOrphanPlatform(o.Platform)
+	}
+	if o.InfraID == "" {
+		return nil, errors.New("an infra ID is required")
+	}
+	if o.Region == "" {
+		return nil, errors.New("a region is required")
+	}
+
+	metadata := &types.ClusterMetadata{InfraID: o.InfraID}
+	switch o.Platform {
+	case awstypes.Name:
+		metadata.AWS = &awstypes.Metadata{
+			Region: o.Region,
+			Identifier: []map[string]string{{
+				fmt.Sprintf("kubernetes.io/cluster/%s", o.InfraID): "owned",
+			}},
+		}
+	case gcptypes.Name:
+		if o.ProjectID == "" {
+			return nil, errors.New("a project is required on gcp")
+		}
+		metadata.GCP = &gcptypes.Metadata{
+			Region:    o.Region,
+			ProjectID: o.ProjectID,
+		}
+	default:
+		return nil, unsupportedOrphanPlatform(o.Platform)
+	}
+	return metadata, nil
+}
+
+// NewForOrphan returns a Destroyer of the resources of the orphan configured
+// with the options, and the report it records the resources in.
+func NewForOrphan(logger logrus.FieldLogger, orphan Orphan, options providers.Options) (providers.Destroyer, *providers.Report, error) {
+	metadata, err := orphan.Metadata()
+	if err != nil {
+		return nil, nil, err
+	}
+	return newFromMetadata(logger, metadata, options)
+}
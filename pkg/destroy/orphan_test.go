@@ -0,0 +1,75 @@
+package destroy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+	gcptypes "github.com/openshift/installer/pkg/types/gcp"
+)
+
+func TestOrphanMetadata(t *testing.T) {
+	cases := []struct {
+		name     string
+		orphan   Orphan
+		expected *types.ClusterMetadata
+		err      string
+	}{
+		{
+			name:   "aws",
+			orphan: Orphan{Platform: "aws", InfraID: "test-abcde", Region: "us-east-1"},
+			expected: &types.ClusterMetadata{
+				InfraID: "test-abcde",
+				ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+					AWS: &awstypes.Metadata{
+						Region:     "us-east-1",
+						Identifier: []map[string]string{{"kubernetes.io/cluster/test-abcde": "owned"}},
+					},
+				},
+			},
+		},
+		{
+			name:   "gcp",
+			orphan: Orphan{Platform: "gcp", InfraID: "test-abcde", Region: "us-east1", ProjectID: "project"},
+			expected: &types.ClusterMetadata{
+				InfraID: "test-abcde",
+				ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+					GCP: &gcptypes.Metadata{Region: "us-east1", ProjectID: "project"},
+				},
+			},
+		},
+		{
+			name:   "gcp without project",
+			orphan: Orphan{Platform: "gcp", InfraID: "test-abcde", Region: "us-east1"},
+			err:    "a project is required on gcp",
+		},
+		{
+			name:   "missing region",
+			orphan: Orphan{Platform: "aws", InfraID: "test-abcde"},
+			err:    "a region is required",
+		},
+		{
+			name:   "unsupported platform",
+			orphan: Orphan{Platform: "openstack", InfraID: "test-abcde", Region: "regionOne"},
+			err:    `destroying the resources of an infra ID is not supported on the "openstack" platform, only on aws, gcp; destroy the cluster from its assets directory instead`,
+		},
+		{
+			name:   "unsupported platform without region",
+			orphan: Orphan{Platform: "azure", InfraID: "test-abcde"},
+			err:    `destroying the resources of an infra ID is not supported on the "azure" platform, only on aws, gcp; destroy the cluster from its assets directory instead`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			metadata, err := tc.orphan.Metadata()
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, metadata)
+		})
+	}
+}
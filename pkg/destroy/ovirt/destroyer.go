@@ -18,6 +18,14 @@ import (
 type ClusterUninstaller struct {
 	Metadata types.ClusterMetadata
 	Logger   logrus.FieldLogger
+
+	report *providers.Report
+}
+
+// Configure sets the options of the uninstall and the report to record the
+// resources in.
+func (uninstaller *ClusterUninstaller) Configure(options providers.Options, report *providers.Report) {
+	uninstaller.report = report
 }
 
 // Run is the entrypoint to start the uninstall process.
@@ -66,6 +74,10 @@ func (uninstaller *ClusterUninstaller) removeVMs(con *ovirtsdk.Connection, tag s
 	wg := sync.WaitGroup{}
 	wg.Add(len(vms))
 	for _, vm := range vms {
+		if !uninstaller.report.ShouldDelete("vm", vm.MustName()) {
+			wg.Done()
+			continue
+		}
 		go func(vm *ovirtsdk.Vm) {
 			uninstaller.stopVM(vmsService, vm)
 			uninstaller.removeVM(vmsService, vm)
@@ -86,11 +98,16 @@ func (uninstaller *ClusterUninstaller) removeTag(con *ovirtsdk.Connection, tag s
 	if tagsServiceListResponse != nil {
 		for _, t := range tagsServiceListResponse.MustTags().Slice() {
 			if t.MustName() == tag {
+				if !uninstaller.report.ShouldDelete("tag", tag) {
+					continue
+				}
 				uninstaller.Logger.Infof("Removing tag %s", t.MustName())
 				_, err := tagsService.TagService(t.MustId()).Remove().Send()
 				if err != nil {
+					uninstaller.report.Failed("tag", tag, err)
 					return err
 				}
+				uninstaller.report.Deleted("tag", tag)
 			}
 		}
 	}
@@ -120,8 +137,10 @@ func (uninstaller *ClusterUninstaller) removeVM(vmsService *ovirtsdk.VmsService,
 	_, err := vmService.Remove().Send()
 	if err == nil {
 		uninstaller.Logger.Infof("Removing VM %s", vm.MustName())
+		uninstaller.report.Deleted("vm", vm.MustName())
 	} else {
 		uninstaller.Logger.Errorf("Failed to remove VM %s: %s", vm.MustName(), err)
+		uninstaller.report.Failed("vm", vm.MustName(), err)
 	}
 }
 
@@ -136,12 +155,17 @@ func (uninstaller *ClusterUninstaller) removeTemplate(con *ovirtsdk.Connection)
 			// the results can potentially return a list of template
 			// because the search uses wildcards
 			for _, tmp := range result.Slice() {
+				if !uninstaller.report.ShouldDelete("template", tmp.MustName()) {
+					continue
+				}
 				uninstaller.Logger.Infof("Removing Template %s", tmp.MustName())
 				service := con.SystemService().TemplatesService().TemplateService(tmp.MustId())
 				_, err := service.Remove().Send()
 				if err != nil {
+					uninstaller.report.Failed("template", tmp.MustName(), err)
 					return err
 				}
+				uninstaller.report.Deleted("template", tmp.MustName())
 			}
 		}
 	}
@@ -157,11 +181,17 @@ func (uninstaller *ClusterUninstaller) removeAffinityGroups(con *ovirtsdk.Connec
 	}
 	for _, ag := range res.MustGroups().Slice() {
 		if strings.HasPrefix(ag.MustName(), fmt.Sprintf("%s-", uninstaller.Metadata.InfraID)) {
+			if !uninstaller.report.ShouldDelete("affinitygroup", ag.MustName()) {
+				continue
+			}
 			uninstaller.Logger.Infof("Removing AffinityGroup %s", ag.MustName())
 			_, err := affinityGroupService.GroupService(ag.MustId()).Remove().Send()
 			if err != nil {
 				uninstaller.Logger.Errorf("failed to remove AffinityGroup: %s", err)
+				uninstaller.report.Failed("affinitygroup", ag.MustName(), err)
+				continue
 			}
+			uninstaller.report.Deleted("affinitygroup", ag.MustName())
 		}
 	}
 	return nil
@@ -0,0 +1,121 @@
+package providers
+
+import (
+	"strings"
+	"sync"
+)
+
+// The statuses of the resources of a Report.
+const (
+	// ResourceFound is a resource that a dry run would delete.
+	ResourceFound = "found"
+	// ResourceKept is a resource left in place by Options.Keep.
+	ResourceKept = "kept"
+	// ResourceDeleted is a resource that was deleted.
+	ResourceDeleted = "deleted"
+	// ResourceFailed is a resource that could not be deleted.
+	ResourceFailed = "failed"
+)
+
+// Resource is a cloud resource of the cluster.
+type Resource struct {
+	Type   string `json:"type"`
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report is the inventory of the resources a destroyer found, deleted, kept
+// or failed to delete. It is safe for concurrent use, and a nil Report deletes
+// everything and records nothing.
+type Report struct {
+	Platform  string     `json:"platform"`
+	InfraID   string     `json:"infraID"`
+	DryRun    bool       `json:"dryRun"`
+	Resources []Resource `json:"resources"`
+	Error     string     `json:"error,omitempty"`
+
+	mu      sync.Mutex
+	options Options
+	index   map[string]int
+}
+
+// NewReport returns an empty report for the options.
+func NewReport(platform, infraID string, options Options) *Report {
+	return &Report{
+		Platform:  platform,
+		InfraID:   infraID,
+		DryRun:    options.DryRun,
+		Resources: []Resource{},
+		options:   options,
+		index:     map[string]int{},
+	}
+}
+
+// ShouldDelete records the resource and returns true if the destroyer should
+// delete it, i.e. it is not a dry run and the type of the resource is not
+// kept.
+func (r *Report) ShouldDelete(resourceType, id string) bool {
+	if r == nil {
+		return true
+	}
+	if r.keeps(resourceType) {
+		r.record(resourceType, id, ResourceKept, nil)
+		return false
+	}
+	r.record(resourceType, id, ResourceFound, nil)
+	return !r.options.DryRun
+}
+
+// Kept records the resource as left in place although its type is not kept,
+// e.g. because it contains resources that are.
+func (r *Report) Kept(resourceType, id string) {
+	if r != nil {
+		r.record(resourceType, id, ResourceKept, nil)
+	}
+}
+
+// Deleted records the resource as deleted.
+func (r *Report) Deleted(resourceType, id string) {
+	if r != nil {
+		r.record(resourceType, id, ResourceDeleted, nil)
+	}
+}
+
+// Failed records the last error deleting the resource.
+func (r *Report) Failed(resourceType, id string, err error) {
+	if r != nil {
+		r.record(resourceType, id, ResourceFailed, err)
+	}
+}
+
+func (r *Report) keeps(resourceType string) bool {
+	for _, keep := range r.options.Keep {
+		if resourceType == keep || strings.HasPrefix(resourceType, keep+":") {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Report) record(resourceType, id, status string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	resource := Resource{Type: resourceType, ID: id, Status: status}
+	if err != nil {
+		resource.Error = err.Error()
+	}
+	key := resourceType + "/" + id
+	if i, ok := r.index[key]; ok {
+		// a resource found again after it failed keeps its error until it
+		// is deleted
+		if status == ResourceFound && r.Resources[i].Status == ResourceFailed {
+			return
+		}
+		r.Resources[i] = resource
+		return
+	}
+	r.index[key] = len(r.Resources)
+	r.Resources = append(r.Resources, resource)
+}
@@ -0,0 +1,97 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReport(t *testing.T) {
+	cases := []struct {
+		name     string
+		options  Options
+		record   func(*Report) []bool
+		deletes  []bool
+		expected []Resource
+	}{
+		{
+			name:    "deletes everything by default",
+			options: Options{},
+			record: func(r *Report) []bool {
+				deleteInstance := r.ShouldDelete("ec2:instance", "i-1")
+				r.Deleted("ec2:instance", "i-1")
+				return []bool{deleteInstance}
+			},
+			deletes:  []bool{true},
+			expected: []Resource{{Type: "ec2:instance", ID: "i-1", Status: ResourceDeleted}},
+		},
+		{
+			name:    "dry run",
+			options: Options{DryRun: true},
+			record: func(r *Report) []bool {
+				return []bool{r.ShouldDelete("ec2:instance", "i-1")}
+			},
+			deletes:  []bool{false},
+			expected: []Resource{{Type: "ec2:instance", ID: "i-1", Status: ResourceFound}},
+		},
+		{
+			name:    "keeps types and their subtypes",
+			options: Options{Keep: []string{"route53"}},
+			record: func(r *Report) []bool {
+				return []bool{
+					r.ShouldDelete("route53:hostedzone", "Z1"),
+					r.ShouldDelete("route53", "Z2"),
+					r.ShouldDelete("route53resolver:rule", "rr-1"),
+				}
+			},
+			deletes: []bool{false, false, true},
+			expected: []Resource{
+				{Type: "route53:hostedzone", ID: "Z1", Status: ResourceKept},
+				{Type: "route53", ID: "Z2", Status: ResourceKept},
+				{Type: "route53resolver:rule", ID: "rr-1", Status: ResourceFound},
+			},
+		},
+		{
+			name:    "kept for the resources it contains",
+			options: Options{Keep: []string{"virtualmachine"}},
+			record: func(r *Report) []bool {
+				deleteVM := r.ShouldDelete("virtualmachine", "master-0")
+				r.Kept("folder", "infra-id")
+				return []bool{deleteVM}
+			},
+			deletes: []bool{false},
+			expected: []Resource{
+				{Type: "virtualmachine", ID: "master-0", Status: ResourceKept},
+				{Type: "folder", ID: "infra-id", Status: ResourceKept},
+			},
+		},
+		{
+			name:    "failure kept when found again",
+			options: Options{},
+			record: func(r *Report) []bool {
+				first := r.ShouldDelete("s3", "bucket")
+				r.Failed("s3", "bucket", errors.New("access denied"))
+				second := r.ShouldDelete("s3", "bucket")
+				return []bool{first, second}
+			},
+			deletes:  []bool{true, true},
+			expected: []Resource{{Type: "s3", ID: "bucket", Status: ResourceFailed, Error: "access denied"}},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			report := NewReport("aws", "infra-id", tc.options)
+			assert.Equal(t, tc.deletes, tc.record(report))
+			assert.Equal(t, tc.expected, report.Resources)
+		})
+	}
+}
+
+func TestNilReport(t *testing.T) {
+	var report *Report
+	assert.True(t, report.ShouldDelete("ec2:instance", "i-1"))
+	report.Deleted("ec2:instance", "i-1")
+	report.Kept("ec2:instance", "i-1")
+	report.Failed("ec2:instance", "i-1", errors.New("failed"))
+}
@@ -3,6 +3,7 @@ package providers
 import (
 	"github.com/sirupsen/logrus"
 
+	"github.com/openshift/installer/pkg/credentialsfile"
 	"github.com/openshift/installer/pkg/types"
 )
 
@@ -12,5 +13,37 @@ type Destroyer interface {
 	Run() (*types.ClusterQuota, error)
 }
 
+// Options configure how a destroyer removes the resources of a cluster.
+type Options struct {
+	// DryRun lists the resources of the cluster without deleting them.
+	DryRun bool
+
+	// Keep lists the resource types to leave in place. An entry matches a
+	// resource type equal to it or starting with it and a colon, e.g. route53
+	// matches route53:hostedzone.
+	Keep []string
+
+	// Credentials are the cloud credentials the destroyer creates its
+	// sessions with, in place of those of the credentials file of the
+	// invocation, of the environment and of the user, or nil.
+	Credentials *credentialsfile.File
+}
+
+// IsZero returns true if the options are the defaults, which delete
+// everything.
+func (o Options) IsZero() bool {
+	return !o.DryRun && len(o.Keep) == 0
+}
+
+// ConfigurableDestroyer is a Destroyer that supports Options and records the
+// resources it finds in a Report.
+type ConfigurableDestroyer interface {
+	Destroyer
+
+	// Configure sets the options of the destroyer and the report to record
+	// the resources in. It must be called before Run.
+	Configure(options Options, report *Report)
+}
+
 // NewFunc is an interface for creating platform-specific destroyers.
 type NewFunc func(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (Destroyer, error)
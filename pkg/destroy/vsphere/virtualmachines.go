@@ -66,6 +66,9 @@ func (o *ClusterUninstaller) stopVirtualMachines() error {
 
 	var errs []error
 	for _, vmMO := range found {
+		if !o.report.ShouldDelete("virtualmachine", vmMO.Name) {
+			continue
+		}
 		if !isPoweredOff(vmMO) {
 			if err := o.stopVirtualMachine(ctx, vmMO); err != nil {
 				errs = append(errs, err)
@@ -85,8 +88,10 @@ func (o *ClusterUninstaller) deleteVirtualMachine(ctx context.Context, vmMO mo.V
 	}
 	if err != nil {
 		virtualMachineLogger.Debug(err)
+		o.report.Failed("virtualmachine", vmMO.Name, err)
 		return err
 	}
+	o.report.Deleted("virtualmachine", vmMO.Name)
 	virtualMachineLogger.Info("Destroyed")
 	return nil
 }
@@ -104,6 +109,12 @@ func (o *ClusterUninstaller) deleteVirtualMachines() error {
 
 	var errs []error
 	for _, vmMO := range found {
+		if !o.report.ShouldDelete("virtualmachine", vmMO.Name) {
+			// the folder and the tag of a kept virtual machine are kept
+			// with it, unless this is a dry run
+			o.keptVirtualMachines = o.keptVirtualMachines || !o.options.DryRun
+			continue
+		}
 		if err := o.deleteVirtualMachine(ctx, vmMO); err != nil {
 			errs = append(errs, err)
 		}
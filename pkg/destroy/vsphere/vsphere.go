@@ -44,6 +44,12 @@ type ClusterUninstaller struct {
 	Logger logrus.FieldLogger
 
 	context context.Context
+	options providers.Options
+	report  *providers.Report
+
+	// keptVirtualMachines is set when virtual machines are left in place,
+	// with the folder and the tag that contain them.
+	keptVirtualMachines bool
 }
 
 // New returns an VSphere destroyer from ClusterMetadata.
@@ -61,6 +67,23 @@ func New(logger logrus.FieldLogger, metadata *installertypes.ClusterMetadata) (p
 	}, nil
 }
 
+// Configure sets the options of the uninstall and the report to record the
+// resources in.
+func (o *ClusterUninstaller) Configure(options providers.Options, report *providers.Report) {
+	o.options = options
+	o.report = report
+}
+
+// shouldDelete returns true if the resource is to be deleted. Resources which
+// contain kept virtual machines are kept too.
+func (o *ClusterUninstaller) shouldDelete(resourceType, id string) bool {
+	if o.keptVirtualMachines {
+		o.report.Kept(resourceType, id)
+		return false
+	}
+	return o.report.ShouldDelete(resourceType, id)
+}
+
 func isNotFound(err error) bool {
 	return err != nil && strings.HasSuffix(err.Error(), http.StatusText(http.StatusNotFound))
 }
@@ -146,6 +169,9 @@ func (o *ClusterUninstaller) deleteFolder() error {
 
 	for _, f := range folderMoList {
 		folderLogger := o.Logger.WithField("Folder", f.Name)
+		if !o.shouldDelete("folder", f.Name) {
+			continue
+		}
 		if numChildren := len(f.ChildEntity); numChildren > 0 {
 			entities := make([]string, 0, numChildren)
 			for _, child := range f.ChildEntity {
@@ -161,8 +187,10 @@ func (o *ClusterUninstaller) deleteFolder() error {
 		}
 		if err != nil {
 			folderLogger.Debug(err)
+			o.report.Failed("folder", f.Name, err)
 			return err
 		}
+		o.report.Deleted("folder", f.Name)
 		folderLogger.Info("Destroyed")
 	}
 
@@ -204,11 +232,13 @@ func (o *ClusterUninstaller) deleteStoragePolicy() error {
 			matchingProfileIds = append(matchingProfileIds, profileID)
 		}
 	}
-	if len(matchingProfileIds) > 0 {
+	if len(matchingProfileIds) > 0 && o.report.ShouldDelete("storagepolicy", policyName) {
 		_, err = pbmClient.DeleteProfile(ctx, matchingProfileIds)
 		if err != nil {
+			o.report.Failed("storagepolicy", policyName, err)
 			return err
 		}
+		o.report.Deleted("storagepolicy", policyName)
 		policyLogger.Info("Destroyed")
 
 	}
@@ -225,9 +255,15 @@ func (o *ClusterUninstaller) deleteTag() error {
 	tagManager := tags.NewManager(o.RestClient)
 	tag, err := tagManager.GetTag(ctx, o.InfraID)
 	if err == nil {
+		if !o.shouldDelete("tag", o.InfraID) {
+			return nil
+		}
 		err = tagManager.DeleteTag(ctx, tag)
 		if err == nil {
+			o.report.Deleted("tag", o.InfraID)
 			tagLogger.Info("Deleted")
+		} else if !isNotFound(err) {
+			o.report.Failed("tag", o.InfraID, err)
 		}
 	}
 	if isNotFound(err) {
@@ -261,10 +297,15 @@ func (o *ClusterUninstaller) deleteTagCategory() error {
 			continue
 		}
 		if category.Name == categoryID {
+			if !o.shouldDelete("tag:category", categoryID) {
+				return nil
+			}
 			if err = tagManager.DeleteCategory(ctx, category); err != nil {
 				tcLogger.Errorln(err)
+				o.report.Failed("tag:category", categoryID, err)
 				return err
 			}
+			o.report.Deleted("tag:category", categoryID)
 			tcLogger.Info("Deleted")
 			return nil
 		}
@@ -0,0 +1,117 @@
+// Package deterministic provides the sources of randomness and time used to
+// generate assets. The nil Source draws from crypto/rand and the wall clock.
+// A Source created with New derives its random values from a secret seed and
+// uses a fixed time instead, so that generating the same assets from the same
+// install-config draws the same values.
+//
+// crypto/rsa and golang.org/x/crypto/bcrypt do not generate reproducible keys
+// and salts from a caller-provided reader, so private keys and password
+// hashes are random even with a seeded Source.
+package deterministic
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Config configures deterministic generation.
+type Config struct {
+	// Seed is the secret all random values are derived from. Anyone who
+	// knows it can derive the passwords of the cluster.
+	Seed []byte
+	// Time is the time assets are generated at, e.g. the NotBefore of
+	// certificates.
+	Time time.Time
+	// ClusterID overrides the generated cluster ID, if set.
+	ClusterID string
+}
+
+// Source is the source of the random values and of the time an asset is
+// generated with.
+type Source struct {
+	config Config
+	reader io.Reader
+}
+
+// New returns a source which derives its random values from the seed of the
+// config.
+func New(c Config) (*Source, error) {
+	if len(c.Seed) < 16 {
+		return nil, errors.New("the seed must be at least 16 bytes long")
+	}
+	if c.Time.IsZero() {
+		return nil, errors.New("the time must be set")
+	}
+	return &Source{config: c, reader: newStream(c.Seed, "")}, nil
+}
+
+// ForAsset returns the source for generating the named asset. Each asset
+// draws from its own stream, so that the values it draws do not depend on
+// which other assets were generated before it.
+func (s *Source) ForAsset(name string) *Source {
+	if s == nil {
+		return nil
+	}
+	return &Source{config: s.config, reader: newStream(s.config.Seed, name)}
+}
+
+// Reader returns the reader random bytes are drawn from.
+func (s *Source) Reader() io.Reader {
+	if s == nil {
+		return rand.Reader
+	}
+	return s.reader
+}
+
+// Now returns the time assets are generated at.
+func (s *Source) Now() time.Time {
+	if s == nil {
+		return time.Now()
+	}
+	return s.config.Time
+}
+
+// ClusterID returns the injected cluster ID, or "" to generate one.
+func (s *Source) ClusterID() string {
+	if s == nil {
+		return ""
+	}
+	return s.config.ClusterID
+}
+
+// stream is an HMAC-SHA256 keystream: block i is HMAC(seed, label || i).
+type stream struct {
+	seed    []byte
+	label   string
+	counter uint64
+	buf     []byte
+}
+
+func newStream(seed []byte, label string) *stream {
+	return &stream{seed: seed, label: label}
+}
+
+func (s *stream) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(s.buf) == 0 {
+			h := hmac.New(sha256.New, s.seed)
+			h.Write([]byte(s.label))
+			var counter [8]byte
+			binary.BigEndian.PutUint64(counter[:], s.counter)
+			h.Write(counter[:])
+			s.buf = h.Sum(nil)
+			s.counter++
+		}
+		c := copy(p[n:], s.buf)
+		s.buf = s.buf[c:]
+		n += c
+	}
+	return n, nil
+}
@@ -0,0 +1,56 @@
+package deterministic
+
+import (
+	"crypto/rand"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newSource(t *testing.T) *Source {
+	t.Helper()
+	s, err := New(Config{Seed: []byte("0123456789abcdef"), Time: time.Unix(1700000000, 0)})
+	assert.NoError(t, err)
+	return s
+}
+
+func read(t *testing.T, r io.Reader, n int) []byte {
+	t.Helper()
+	b := make([]byte, n)
+	_, err := io.ReadFull(r, b)
+	assert.NoError(t, err)
+	return b
+}
+
+func TestReader(t *testing.T) {
+	s := newSource(t)
+
+	a := read(t, s.ForAsset("a").Reader(), 100)
+	b := read(t, s.ForAsset("b").Reader(), 100)
+	assert.Equal(t, a, read(t, newSource(t).ForAsset("a").Reader(), 100), "the stream of an asset should not depend on other assets")
+	assert.NotEqual(t, a, b, "assets should have different streams")
+
+	r := s.ForAsset("a").Reader()
+	assert.Equal(t, a, append(read(t, r, 33), read(t, r, 67)...), "the stream should not depend on the size of reads")
+}
+
+func TestNilSource(t *testing.T) {
+	var s *Source
+	assert.Nil(t, s.ForAsset("a"))
+	assert.Equal(t, rand.Reader, s.Reader())
+	assert.WithinDuration(t, time.Now(), s.Now(), time.Minute)
+	assert.Equal(t, "", s.ClusterID())
+}
+
+func TestNow(t *testing.T) {
+	assert.Equal(t, time.Unix(1700000000, 0), newSource(t).ForAsset("a").Now())
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Config{Seed: []byte("short"), Time: time.Now()})
+	assert.EqualError(t, err, "the seed must be at least 16 bytes long")
+	_, err = New(Config{Seed: []byte("0123456789abcdef")})
+	assert.EqualError(t, err, "the time must be set")
+}
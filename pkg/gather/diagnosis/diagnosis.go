@@ -0,0 +1,292 @@
+package diagnosis
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/installer/pkg/gather/findings"
+	"github.com/openshift/installer/pkg/gather/service"
+)
+
+var (
+	// clusterOperatorsFileRegex matches the cluster operators gathered by
+	// the bootstrap machine, e.g. "log-bundle-20210329190553/resources/co.json".
+	clusterOperatorsFileRegex = regexp.MustCompile(`^[^/]+(?:/log-bundle-bootstrap)?/resources/co\.json$`)
+
+	// kubeletLogFileRegexes match the kubelet journals of a host. The
+	// captured group is the host, e.g. "bootstrap", "control-plane/10.0.0.5"
+	// or the name of a node whose journal was gathered through the API.
+	kubeletLogFileRegexes = []*regexp.Regexp{
+		regexp.MustCompile(`^[^/]+(?:/log-bundle-bootstrap)?/(bootstrap|control-plane/[^/]+)/journals/kubelet\.log$`),
+		regexp.MustCompile(`^[^/]+/journals/(.+)-kubelet\.log$`),
+	}
+
+	// kubeletErrorRegex matches the error lines of klog, e.g.
+	// "E0329 19:05:53.123456    1234 kubelet.go:2448] ...".
+	kubeletErrorRegex = regexp.MustCompile(`\bE\d{4} \d{2}:\d{2}:\d{2}\.\d+ `)
+)
+
+// OperatorStatus is the status of a cluster operator.
+type OperatorStatus struct {
+	Name      string `json:"name"`
+	Available bool   `json:"available"`
+	Degraded  bool   `json:"degraded"`
+	// Message is the message of the Degraded condition of a degraded
+	// operator, or else of its Available condition.
+	Message string `json:"message,omitempty"`
+}
+
+// KubeletLog is the summary of the kubelet journal of a host.
+type KubeletLog struct {
+	Host string `json:"host"`
+	// Errors is the number of error lines.
+	Errors int `json:"errors"`
+	// LastError is the last error line.
+	LastError string `json:"lastError,omitempty"`
+}
+
+// Report is the diagnosis of a gather bundle.
+type Report struct {
+	Bundle           string                    `json:"bundle"`
+	Services         []service.ServiceAnalysis `json:"services"`
+	KubeletLogs      []KubeletLog              `json:"kubeletLogs"`
+	ClusterOperators []OperatorStatus          `json:"clusterOperators"`
+	Findings         []findings.Finding        `json:"findings"`
+	// Diagnosis are the conclusions drawn from the rest of the report, the
+	// most likely cause first.
+	Diagnosis []string `json:"diagnosis"`
+}
+
+// AnalyzeBundle diagnoses the gzipped tar gather bundle at the path.
+func AnalyzeBundle(bundlePath string) (*Report, error) {
+	bundleFile, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open the gather bundle")
+	}
+	defer bundleFile.Close()
+
+	report, err := analyze(bundleFile)
+	if err != nil {
+		return nil, err
+	}
+	report.Bundle = bundlePath
+	return report, nil
+}
+
+func analyze(bundle io.Reader) (*Report, error) {
+	uncompressedStream, err := gzip.NewReader(bundle)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decompress the gather bundle")
+	}
+	defer uncompressedStream.Close()
+
+	report := &Report{
+		Services:         []service.ServiceAnalysis{},
+		KubeletLogs:      []KubeletLog{},
+		ClusterOperators: []OperatorStatus{},
+	}
+	collector := findings.NewCollector()
+	tarReader := tar.NewReader(uncompressedStream)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "encountered an error reading from the gather bundle")
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		contents, err := io.ReadAll(tarReader)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read %s from the gather bundle", header.Name)
+		}
+
+		if name, ok := service.ServiceName(header.Name); ok {
+			if analysis, err := service.AnalyzeService(name, bytes.NewReader(contents)); err == nil {
+				report.Services = append(report.Services, analysis)
+			}
+		}
+		if clusterOperatorsFileRegex.MatchString(header.Name) {
+			operators, err := operatorStatuses(contents)
+			if err != nil {
+				return nil, errors.Wrapf(err, "could not decode %s", header.Name)
+			}
+			report.ClusterOperators = operators
+		}
+		for _, regex := range kubeletLogFileRegexes {
+			if submatch := regex.FindStringSubmatch(header.Name); submatch != nil {
+				report.KubeletLogs = append(report.KubeletLogs, kubeletLog(submatch[1], contents))
+				break
+			}
+		}
+		// findings are best effort, the lines of the file past a line too
+		// long to scan are skipped
+		_ = collector.Scan(header.Name, bytes.NewReader(contents))
+	}
+
+	sort.Slice(report.Services, func(i, j int) bool { return report.Services[i].Name < report.Services[j].Name })
+	sort.Slice(report.KubeletLogs, func(i, j int) bool { return report.KubeletLogs[i].Host < report.KubeletLogs[j].Host })
+	report.Findings = collector.Findings()
+	report.Diagnosis = diagnose(report)
+	return report, nil
+}
+
+func operatorStatuses(contents []byte) ([]OperatorStatus, error) {
+	var list configv1.ClusterOperatorList
+	if err := json.Unmarshal(contents, &list); err != nil {
+		return nil, err
+	}
+	operators := make([]OperatorStatus, 0, len(list.Items))
+	for _, operator := range list.Items {
+		status := OperatorStatus{Name: operator.Name}
+		for _, condition := range operator.Status.Conditions {
+			switch condition.Type {
+			case configv1.OperatorAvailable:
+				status.Available = condition.Status == configv1.ConditionTrue
+				if status.Message == "" && !status.Available {
+					status.Message = condition.Message
+				}
+			case configv1.OperatorDegraded:
+				status.Degraded = condition.Status == configv1.ConditionTrue
+				if status.Degraded {
+					status.Message = condition.Message
+				}
+			}
+		}
+		operators = append(operators, status)
+	}
+	sort.Slice(operators, func(i, j int) bool { return operators[i].Name < operators[j].Name })
+	return operators, nil
+}
+
+func kubeletLog(host string, contents []byte) KubeletLog {
+	log := KubeletLog{Host: host}
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Text(); kubeletErrorRegex.MatchString(line) {
+			log.Errors++
+			log.LastError = strings.TrimSpace(line)
+		}
+	}
+	return log
+}
+
+// diagnose correlates the services, kubelet logs, cluster operators and
+// findings of the report into conclusions, the most likely cause first.
+func diagnose(r *Report) []string {
+	hasFinding := map[findings.Category]findings.Finding{}
+	for _, finding := range r.Findings {
+		hasFinding[finding.Category] = finding
+	}
+	because := func(category findings.Category) string {
+		if finding, ok := hasFinding[category]; ok {
+			return fmt.Sprintf("; %s", finding.Summary)
+		}
+		return ""
+	}
+
+	diagnosis := []string{}
+	services := map[string]service.ServiceAnalysis{}
+	for _, s := range r.Services {
+		services[s.Name] = s
+	}
+	if s, ok := services["release-image"]; ok && !s.Successful {
+		diagnosis = append(diagnosis, "The bootstrap machine failed to pull the release image"+because(findings.ImagePullFailure))
+	}
+	if s, ok := services["bootkube"]; ok && !s.Successful {
+		diagnosis = append(diagnosis, "The bootstrap machine is unable to resolve the API and/or API-Int server URLs"+because(findings.DNSResolutionFailure))
+	}
+	for _, s := range r.Services {
+		if s.Successful || s.Name == "release-image" || s.Name == "bootkube" {
+			continue
+		}
+		diagnosis = append(diagnosis, fmt.Sprintf("The %s.service of the bootstrap machine failed in stage %q: %s", s.Name, s.FailingStage, lastLine(s.LastError)))
+	}
+
+	for _, log := range r.KubeletLogs {
+		if log.Errors == 0 {
+			continue
+		}
+		cause := ""
+		switch {
+		case strings.Contains(log.LastError, "x509"):
+			cause = because(findings.CertificateError)
+		case strings.Contains(log.LastError, "no such host"):
+			cause = because(findings.DNSResolutionFailure)
+		}
+		diagnosis = append(diagnosis, fmt.Sprintf("The kubelet of %s logged %d errors, the last: %s%s", log.Host, log.Errors, log.LastError, cause))
+	}
+
+	for _, operator := range r.ClusterOperators {
+		switch {
+		case operator.Degraded:
+			diagnosis = append(diagnosis, fmt.Sprintf("Cluster operator %s is degraded: %s", operator.Name, operator.Message))
+		case !operator.Available:
+			diagnosis = append(diagnosis, fmt.Sprintf("Cluster operator %s is not available: %s", operator.Name, operator.Message))
+		}
+	}
+
+	if len(diagnosis) == 0 {
+		for _, finding := range r.Findings {
+			diagnosis = append(diagnosis, finding.Summary)
+		}
+	}
+	return diagnosis
+}
+
+func lastLine(s string) string {
+	s = strings.TrimSpace(s)
+	return s[strings.LastIndex(s, "\n")+1:]
+}
+
+// Print prints the report in a human-readable form.
+func (r *Report) Print(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Diagnosis of %s:\n", r.Bundle)
+	if len(r.Diagnosis) == 0 {
+		b.WriteString("  No cause of the failure found\n")
+	}
+	for _, conclusion := range r.Diagnosis {
+		fmt.Fprintf(&b, "  - %s\n", conclusion)
+	}
+
+	if len(r.Services) > 0 {
+		b.WriteString("\nBootstrap services:\n")
+		for _, s := range r.Services {
+			status := "succeeded"
+			if !s.Successful {
+				status = fmt.Sprintf("failed in stage %q", s.FailingStage)
+			}
+			fmt.Fprintf(&b, "  %s: %s after %d starts\n", s.Name, status, s.Starts)
+		}
+	}
+	if len(r.KubeletLogs) > 0 {
+		b.WriteString("\nKubelet logs:\n")
+		for _, log := range r.KubeletLogs {
+			fmt.Fprintf(&b, "  %s: %d errors\n", log.Host, log.Errors)
+		}
+	}
+	if len(r.ClusterOperators) > 0 {
+		b.WriteString("\nCluster operators:\n")
+		for _, operator := range r.ClusterOperators {
+			fmt.Fprintf(&b, "  %s: available=%t degraded=%t\n", operator.Name, operator.Available, operator.Degraded)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
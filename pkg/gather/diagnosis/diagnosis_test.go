@@ -0,0 +1,87 @@
+package diagnosis
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func bundle(t *testing.T, files map[string]string) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, contents := range files {
+		err := tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents)), Typeflag: tar.TypeReg})
+		assert.NoError(t, err)
+		_, err = tarWriter.Write([]byte(contents))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tarWriter.Close())
+	assert.NoError(t, gzipWriter.Close())
+	return buf
+}
+
+const failedReleaseImage = `[
+{"phase":"service start"},
+{"phase":"stage start", "stage":"pull"},
+{"phase":"stage end", "stage":"pull", "result":"failure", "errorMessage":"Pulling quay.io/release\nunauthorized: authentication required"}
+]`
+
+const succeededBootkube = `[
+{"phase":"service start"},
+{"phase":"service end", "result":"success"}
+]`
+
+const clusterOperators = `{"items":[
+{"metadata":{"name":"authentication"},"status":{"conditions":[
+	{"type":"Available","status":"False","message":"OAuth server unreachable"},
+	{"type":"Degraded","status":"False"}]}},
+{"metadata":{"name":"dns"},"status":{"conditions":[
+	{"type":"Available","status":"True"},
+	{"type":"Degraded","status":"False"}]}}
+]}`
+
+func TestAnalyze(t *testing.T) {
+	cases := []struct {
+		name      string
+		files     map[string]string
+		diagnosis []string
+	}{
+		{
+			name:      "no files",
+			diagnosis: []string{},
+		},
+		{
+			name: "release image pull failure",
+			files: map[string]string{
+				"log-bundle/bootstrap/services/release-image.json": failedReleaseImage,
+				"log-bundle/bootstrap/services/bootkube.json":      succeededBootkube,
+			},
+			diagnosis: []string{
+				"The bootstrap machine failed to pull the release image; Container images failed to pull; check the pull secret, the image mirrors and the reachability of the registries",
+			},
+		},
+		{
+			name: "kubelet errors and unavailable operators",
+			files: map[string]string{
+				"log-bundle/control-plane/10.0.0.5/journals/kubelet.log": "Mar 29 kubenswrapper[1234]: I0329 19:05:53.123456 1234 kubelet.go:1] starting\n" +
+					"Mar 29 kubenswrapper[1234]: E0329 19:05:54.123456 1234 reflector.go:1] x509: certificate signed by unknown authority\n",
+				"log-bundle/resources/co.json": clusterOperators,
+			},
+			diagnosis: []string{
+				"The kubelet of control-plane/10.0.0.5 logged 1 errors, the last: Mar 29 kubenswrapper[1234]: E0329 19:05:54.123456 1234 reflector.go:1] x509: certificate signed by unknown authority; TLS certificates failed to verify; check the clock of the hosts, the additionalTrustBundle and any TLS-intercepting proxy",
+				"Cluster operator authentication is not available: OAuth server unreachable",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := analyze(bundle(t, tc.files))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.diagnosis, report.Diagnosis)
+		})
+	}
+}
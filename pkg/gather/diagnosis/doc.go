@@ -0,0 +1,4 @@
+// Package diagnosis correlates the bootstrap services, kubelet logs and
+// cluster operator status of a gather bundle into a diagnosis of an
+// installation failure.
+package diagnosis
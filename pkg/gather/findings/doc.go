@@ -0,0 +1,3 @@
+// Package findings scans the logs of a gather bundle for the likely root
+// causes of an installation failure.
+package findings
@@ -0,0 +1,206 @@
+package findings
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Category is a kind of likely root cause of an installation failure.
+type Category string
+
+const (
+	// CertificateError is a TLS certificate that failed to verify, e.g.
+	// because it expired or is signed by an unknown authority.
+	CertificateError Category = "certificate"
+	// ImagePullFailure is a container image that could not be pulled, e.g.
+	// because of a missing pull secret or an unreachable registry.
+	ImagePullFailure Category = "image-pull"
+	// DNSResolutionFailure is a host name that could not be resolved.
+	DNSResolutionFailure Category = "dns"
+)
+
+// maxExamples is how many matching lines a finding keeps as examples.
+const maxExamples = 3
+
+// Finding is a likely root cause found in the logs of a gather bundle.
+type Finding struct {
+	Category Category `json:"category"`
+	// Summary is a human-readable description of the finding.
+	Summary string `json:"summary"`
+	// Count is the number of matching log lines.
+	Count int `json:"count"`
+	// Files are the files of the bundle with matching log lines.
+	Files []string `json:"files"`
+	// Examples are the first matching log lines.
+	Examples []string `json:"examples"`
+}
+
+// Report is the findings of a gather bundle, ordered by their number of
+// matching log lines.
+type Report struct {
+	Bundle   string    `json:"bundle"`
+	Findings []Finding `json:"findings"`
+}
+
+type rule struct {
+	category Category
+	summary  string
+	pattern  *regexp.Regexp
+}
+
+var rules = []rule{
+	{
+		category: CertificateError,
+		summary:  "TLS certificates failed to verify; check the clock of the hosts, the additionalTrustBundle and any TLS-intercepting proxy",
+		pattern:  regexp.MustCompile(`x509: |certificate has expired|certificate signed by unknown authority|tls: failed to verify certificate`),
+	},
+	{
+		category: ImagePullFailure,
+		summary:  "Container images failed to pull; check the pull secret, the image mirrors and the reachability of the registries",
+		pattern:  regexp.MustCompile(`ErrImagePull|ImagePullBackOff|[Ff]ailed to pull image|manifest unknown|unauthorized: authentication required`),
+	},
+	{
+		category: DNSResolutionFailure,
+		summary:  "Host names failed to resolve; check the DNS records of the API and the upstream resolvers of the hosts",
+		pattern:  regexp.MustCompile(`no such host|server misbehaving|[Tt]emporary failure in name resolution|could not resolve host|NXDOMAIN`),
+	},
+}
+
+// AnalyzeBundle scans the logs of the gzipped tar gather bundle at the path
+// for the likely root causes of an installation failure.
+func AnalyzeBundle(bundlePath string) (*Report, error) {
+	bundleFile, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open the gather bundle")
+	}
+	defer bundleFile.Close()
+
+	report, err := analyze(bundleFile)
+	if err != nil {
+		return nil, err
+	}
+	report.Bundle = bundlePath
+	return report, nil
+}
+
+func analyze(bundle io.Reader) (*Report, error) {
+	uncompressedStream, err := gzip.NewReader(bundle)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decompress the gather bundle")
+	}
+	defer uncompressedStream.Close()
+
+	collector := NewCollector()
+	tarReader := tar.NewReader(uncompressedStream)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "encountered an error reading from the gather bundle")
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := collector.Scan(header.Name, tarReader); err != nil {
+			logrus.Debugf("Could not scan %s for findings: %v", header.Name, err)
+		}
+	}
+	return &Report{Findings: collector.Findings()}, nil
+}
+
+// Collector collects the findings of the files of a gather bundle.
+type Collector struct {
+	findings map[Category]*Finding
+}
+
+// NewCollector returns a Collector without findings.
+func NewCollector() *Collector {
+	return &Collector{findings: map[Category]*Finding{}}
+}
+
+// Scan adds the lines of the file of the bundle with the name that match the
+// rules to the findings.
+func (c *Collector) Scan(name string, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		for _, rule := range rules {
+			if !rule.pattern.MatchString(line) {
+				continue
+			}
+			finding, ok := c.findings[rule.category]
+			if !ok {
+				finding = &Finding{Category: rule.category, Summary: rule.summary, Files: []string{}, Examples: []string{}}
+				c.findings[rule.category] = finding
+			}
+			finding.Count++
+			if len(finding.Files) == 0 || finding.Files[len(finding.Files)-1] != name {
+				finding.Files = append(finding.Files, name)
+			}
+			if len(finding.Examples) < maxExamples {
+				finding.Examples = append(finding.Examples, strings.TrimSpace(line))
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// Findings returns the findings collected so far, ordered by their number of
+// matching log lines.
+func (c *Collector) Findings() []Finding {
+	findings := []Finding{}
+	for _, finding := range c.findings {
+		f := *finding
+		f.Files = append([]string{}, finding.Files...)
+		sort.Strings(f.Files)
+		findings = append(findings, f)
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Count != findings[j].Count {
+			return findings[i].Count > findings[j].Count
+		}
+		return findings[i].Category < findings[j].Category
+	})
+	return findings
+}
+
+// Log logs a human-readable summary of the findings.
+func (r *Report) Log(logger logrus.FieldLogger) {
+	if len(r.Findings) == 0 {
+		logger.Info("No likely root causes found in the gather bundle")
+		return
+	}
+	for _, finding := range r.Findings {
+		logger.Errorf("%s (%d log lines in %d files)", finding.Summary, finding.Count, len(finding.Files))
+		for _, example := range finding.Examples {
+			logger.Infof("  %s", example)
+		}
+	}
+}
+
+// WriteFile writes the findings as JSON to the file.
+func (r *Report) WriteFile(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the findings")
+	}
+	return errors.Wrap(os.WriteFile(path, append(data, '\n'), 0o640), "failed to write the findings")
+}
+
+// FindingsFile returns the path of the findings JSON of the gather bundle.
+func FindingsFile(bundlePath string) string {
+	return strings.TrimSuffix(bundlePath, ".tar.gz") + "-findings.json"
+}
@@ -0,0 +1,94 @@
+package findings
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func bundle(t *testing.T, files map[string]string) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, contents := range files {
+		err := tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents)), Typeflag: tar.TypeReg})
+		assert.NoError(t, err)
+		_, err = tarWriter.Write([]byte(contents))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tarWriter.Close())
+	assert.NoError(t, gzipWriter.Close())
+	return buf
+}
+
+func TestAnalyze(t *testing.T) {
+	cases := []struct {
+		name     string
+		files    map[string]string
+		expected []Finding
+	}{
+		{
+			name:     "no files",
+			expected: []Finding{},
+		},
+		{
+			name: "no findings",
+			files: map[string]string{
+				"log-bundle/bootstrap/journals/bootkube.log": "bootkube.service complete\n",
+			},
+			expected: []Finding{},
+		},
+		{
+			name: "findings ordered by count",
+			files: map[string]string{
+				"log-bundle/bootstrap/journals/release-image.log": "Error: initializing source docker://quay.io/release: unauthorized: authentication required\n",
+				"log-bundle/journals/master-0-kubelet.log": "E0101 dial tcp: lookup api-int.test.example.com on 10.0.0.2:53: no such host\n" +
+					"E0101 dial tcp: lookup api-int.test.example.com on 10.0.0.2:53: no such host\n",
+			},
+			expected: []Finding{
+				{
+					Category: DNSResolutionFailure,
+					Summary:  rules[2].summary,
+					Count:    2,
+					Files:    []string{"log-bundle/journals/master-0-kubelet.log"},
+					Examples: []string{
+						"E0101 dial tcp: lookup api-int.test.example.com on 10.0.0.2:53: no such host",
+						"E0101 dial tcp: lookup api-int.test.example.com on 10.0.0.2:53: no such host",
+					},
+				},
+				{
+					Category: ImagePullFailure,
+					Summary:  rules[1].summary,
+					Count:    1,
+					Files:    []string{"log-bundle/bootstrap/journals/release-image.log"},
+					Examples: []string{"Error: initializing source docker://quay.io/release: unauthorized: authentication required"},
+				},
+			},
+		},
+		{
+			name: "examples are capped",
+			files: map[string]string{
+				"log-bundle/journals/master-0-kubelet.log": "x509: certificate has expired\nx509: certificate has expired\nx509: certificate has expired\nx509: certificate has expired\n",
+			},
+			expected: []Finding{
+				{
+					Category: CertificateError,
+					Summary:  rules[0].summary,
+					Count:    4,
+					Files:    []string{"log-bundle/journals/master-0-kubelet.log"},
+					Examples: []string{"x509: certificate has expired", "x509: certificate has expired", "x509: certificate has expired"},
+				},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := analyze(bundle(t, tc.files))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, report.Findings)
+		})
+	}
+}
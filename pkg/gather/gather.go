@@ -3,10 +3,13 @@ package gather
 import (
 	"archive/tar"
 	"compress/gzip"
+	"encoding/json"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -15,6 +18,9 @@ import (
 	"github.com/openshift/installer/pkg/gather/providers"
 )
 
+// now returns the modification time of the index of a combined archive.
+var now = time.Now
+
 // New returns a Gather based on `metadata.json` in `rootDir`.
 func New(logger logrus.FieldLogger, serialLogBundle string, bootstrap string, masters []string, rootDir string) (providers.Gather, error) {
 	metadata, err := cluster.LoadMetadata(rootDir)
@@ -89,9 +95,29 @@ func addToArchive(tarWriter *tar.Writer, filename string) error {
 	return nil
 }
 
+// IndexFile is the name of the index of the files of a combined archive.
+const IndexFile = "index.json"
+
+// Index lists the files of a combined archive.
+type Index struct {
+	Files []IndexEntry `json:"files"`
+}
+
+// IndexEntry is a file of a combined archive.
+type IndexEntry struct {
+	// Name is the path of the file in the archive.
+	Name string `json:"name"`
+	// Size is the size of the file in bytes.
+	Size int64 `json:"size"`
+	// Source is the subdirectory of the archive the file came from, or empty
+	// for the top level.
+	Source string `json:"source,omitempty"`
+}
+
 // CombineArchives creates a single gzipped tar file from multiple archives.
 // archiveName is the target gzipped tar file. archives maps the existing
-// gzipped tar files to a subdirectory in the new gzipped tar file.
+// gzipped tar files to a subdirectory in the new gzipped tar file. The
+// combined archive also gets an IndexFile listing its files.
 func CombineArchives(archiveName string, archives map[string]string) error {
 	suffix := ".tar.gz"
 
@@ -112,6 +138,7 @@ func CombineArchives(archiveName string, archives map[string]string) error {
 		combinedDirectory = strings.TrimSuffix(filepath.Base(archiveName), suffix)
 	}
 
+	index := Index{Files: []IndexEntry{}}
 	for archive, subDirectory := range archives {
 		_, err := os.Stat(archive)
 		if err != nil {
@@ -152,6 +179,13 @@ func CombineArchives(archiveName string, archives map[string]string) error {
 				newHeaderName = filepath.Join(combinedDirectory, newHeaderName)
 			}
 			header.Name = newHeaderName
+			if header.Typeflag == tar.TypeReg {
+				index.Files = append(index.Files, IndexEntry{
+					Name:   strings.TrimPrefix(newHeaderName, combinedDirectory+"/"),
+					Size:   header.Size,
+					Source: strings.TrimSuffix(subDirectory, "/"),
+				})
+			}
 
 			err = combinedTarWriter.WriteHeader(header)
 			if err != nil {
@@ -170,7 +204,29 @@ func CombineArchives(archiveName string, archives map[string]string) error {
 		}
 	}
 
-	return nil
+	return writeIndex(combinedTarWriter, filepath.Join(combinedDirectory, IndexFile), index, now())
+}
+
+// writeIndex writes the index, sorted by the names of its files, to the
+// archive as the file name modified at modTime.
+func writeIndex(tarWriter *tar.Writer, name string, index Index, modTime time.Time) error {
+	sort.Slice(index.Files, func(i, j int) bool { return index.Files[i].Name < index.Files[j].Name })
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if err := tarWriter.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0644,
+		Size:     int64(len(data)),
+		Typeflag: tar.TypeReg,
+		ModTime:  modTime,
+	}); err != nil {
+		return err
+	}
+	_, err = tarWriter.Write(data)
+	return err
 }
 
 // DeleteArchiveDirectory deletes an archive directory
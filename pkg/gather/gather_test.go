@@ -0,0 +1,138 @@
+package gather
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeArchive creates the gzipped tar file archiveName, with the files of
+// the directory named after it.
+func writeArchive(t *testing.T, archiveName string, files map[string]string) {
+	t.Helper()
+	directory := strings.TrimSuffix(archiveName, ".tar.gz")
+	var filenames []string
+	for name, data := range files {
+		filename := filepath.Join(directory, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+		assert.NoError(t, os.WriteFile(filename, []byte(data), 0644))
+		filenames = append(filenames, filename)
+	}
+	assert.NoError(t, CreateArchive(filenames, archiveName))
+}
+
+// readArchive returns the headers and the contents of the files of the
+// gzipped tar file archiveName by their names.
+func readArchive(t *testing.T, archiveName string) (map[string]*tar.Header, map[string]string) {
+	t.Helper()
+	headers, files := map[string]*tar.Header{}, map[string]string{}
+	file, err := os.Open(archiveName)
+	if !assert.NoError(t, err) {
+		return headers, files
+	}
+	defer file.Close()
+	gzipReader, err := gzip.NewReader(file)
+	if !assert.NoError(t, err) {
+		return headers, files
+	}
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			break
+		}
+		data, err := io.ReadAll(tarReader)
+		assert.NoError(t, err)
+		headers[header.Name] = header
+		files[header.Name] = string(data)
+	}
+	return headers, files
+}
+
+func TestCombineArchives(t *testing.T) {
+	modTime := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	defer func(previous func() time.Time) { now = previous }(now)
+	now = func() time.Time { return modTime }
+
+	dir := t.TempDir()
+	serial := filepath.Join(dir, "serial-log-bundle-1.tar.gz")
+	writeArchive(t, serial, map[string]string{"master-0-serial.log": "serial"})
+	journals := filepath.Join(dir, "journal-log-bundle-1.tar.gz")
+	writeArchive(t, journals, map[string]string{"master-0-kubelet.log": "kubelet", "master-0-crio.log": "crio"})
+	bootstrap := filepath.Join(dir, "bootstrap-1.tar.gz")
+	writeArchive(t, bootstrap, map[string]string{"rendered-assets/openshift/99_kubeadmin-password-secret.yaml": "secret"})
+
+	logBundle := filepath.Join(dir, "log-bundle-1.tar.gz")
+	err := CombineArchives(logBundle, map[string]string{
+		serial:    "serial",
+		journals:  "journals",
+		bootstrap: "",
+		// the archives which were not gathered are skipped
+		filepath.Join(dir, "missing.tar.gz"): "missing",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	headers, files := readArchive(t, logBundle)
+	assert.Equal(t, map[string]string{
+		"log-bundle-1/serial/master-0-serial.log":                                  "serial",
+		"log-bundle-1/journals/master-0-kubelet.log":                               "kubelet",
+		"log-bundle-1/journals/master-0-crio.log":                                  "crio",
+		"log-bundle-1/rendered-assets/openshift/99_kubeadmin-password-secret.yaml": "secret",
+		"log-bundle-1/index.json": `{
+  "files": [
+    {
+      "name": "journals/master-0-crio.log",
+      "size": 4,
+      "source": "journals"
+    },
+    {
+      "name": "journals/master-0-kubelet.log",
+      "size": 7,
+      "source": "journals"
+    },
+    {
+      "name": "rendered-assets/openshift/99_kubeadmin-password-secret.yaml",
+      "size": 6
+    },
+    {
+      "name": "serial/master-0-serial.log",
+      "size": 6,
+      "source": "serial"
+    }
+  ]
+}
+`,
+	}, files)
+	if index, ok := headers["log-bundle-1/index.json"]; assert.True(t, ok) {
+		assert.Equal(t, modTime, index.ModTime.UTC())
+		assert.Equal(t, byte(tar.TypeReg), index.Typeflag)
+	}
+
+	// the combined archives are removed
+	for _, archive := range []string{serial, journals, bootstrap} {
+		_, err := os.Stat(archive)
+		assert.True(t, os.IsNotExist(err), "%s was not removed", archive)
+	}
+}
+
+func TestCombineArchivesEmpty(t *testing.T) {
+	dir := t.TempDir()
+	logBundle := filepath.Join(dir, "log-bundle-1.tar.gz")
+	if !assert.NoError(t, CombineArchives(logBundle, map[string]string{filepath.Join(dir, "missing.tar.gz"): "missing"})) {
+		return
+	}
+	_, files := readArchive(t, logBundle)
+	assert.Equal(t, map[string]string{"log-bundle-1/index.json": "{\n  \"files\": []\n}\n"}, files)
+}
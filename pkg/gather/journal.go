@@ -0,0 +1,80 @@
+package gather
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// JournalUnits are the systemd units whose journals GatherNodeJournals
+// collects from every node.
+var JournalUnits = []string{"kubelet", "crio"}
+
+// GatherNodeJournals collects the journals of the units of every node of the
+// cluster through the node log endpoint of the API, like `oc adm node-logs`,
+// into the gzipped tar file archiveName. It reaches the control plane nodes
+// that the bootstrap machine cannot, as long as the API is up.
+func GatherNodeJournals(ctx context.Context, logger logrus.FieldLogger, config *rest.Config, archiveName string, units []string) error {
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return errors.Wrap(err, "failed to create a kubernetes client")
+	}
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list nodes")
+	}
+	if len(nodes.Items) == 0 {
+		logger.Infoln("Skipping node journal gathering: no nodes found")
+		return nil
+	}
+
+	directory := filepath.Join(filepath.Dir(archiveName), strings.TrimSuffix(filepath.Base(archiveName), ".tar.gz"))
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return err
+	}
+	defer func() {
+		if err := DeleteArchiveDirectory(directory); err != nil {
+			// Note: cleanup is best effort, it shouldn't fail the gather
+			logger.Debugf("Failed to remove archive directory: %v", err)
+		}
+	}()
+
+	var errs []error
+	var files []string
+	for _, node := range nodes.Items {
+		for _, unit := range units {
+			logger.Debugf("Gathering the %s journal of node %s", unit, node.Name)
+			journal, err := client.CoreV1().RESTClient().Get().
+				Resource("nodes").Name(node.Name).
+				SubResource("proxy", "logs", "journal").
+				Param("unit", unit).
+				DoRaw(ctx)
+			if err != nil {
+				errs = append(errs, errors.Wrapf(err, "failed to gather the %s journal of node %s", unit, node.Name))
+				continue
+			}
+			filename := filepath.Join(directory, fmt.Sprintf("%s-%s.log", node.Name, unit))
+			if err := os.WriteFile(filename, journal, 0644); err != nil {
+				errs = append(errs, errors.Wrap(err, "failed to write to file"))
+				continue
+			}
+			files = append(files, filename)
+		}
+	}
+
+	if len(files) > 0 {
+		if err := CreateArchive(files, archiveName); err != nil {
+			errs = append(errs, errors.Wrap(err, "failed to create archive"))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,78 @@
+package gather
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/rest"
+)
+
+// fakeNodeLogs serves the nodes and their journals, by node and unit, and
+// fails for the journals it does not have.
+func fakeNodeLogs(nodes []string, journals map[string]string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/nodes", func(w http.ResponseWriter, r *http.Request) {
+		items := make([]string, 0, len(nodes))
+		for _, node := range nodes {
+			items = append(items, fmt.Sprintf(`{"metadata":{"name":%q}}`, node))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"kind":"NodeList","apiVersion":"v1","items":[%s]}`, strings.Join(items, ","))
+	})
+	mux.HandleFunc("/api/v1/nodes/", func(w http.ResponseWriter, r *http.Request) {
+		node := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v1/nodes/"), "/")[0]
+		journal, ok := journals[node+"/"+r.URL.Query().Get("unit")]
+		if !ok {
+			http.Error(w, "the journal is unavailable", http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, journal)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestGatherNodeJournals(t *testing.T) {
+	server := fakeNodeLogs([]string{"master-0", "master-1"}, map[string]string{
+		"master-0/kubelet": "master-0 kubelet",
+		"master-0/crio":    "master-0 crio",
+		"master-1/kubelet": "master-1 kubelet",
+	})
+	defer server.Close()
+
+	dir := t.TempDir()
+	archiveName := filepath.Join(dir, "journal-log-bundle-1.tar.gz")
+	err := GatherNodeJournals(context.Background(), logrus.StandardLogger(), &rest.Config{Host: server.URL}, archiveName, JournalUnits)
+	// the journals which could not be gathered are reported, and the others
+	// are archived nonetheless
+	assert.ErrorContains(t, err, "failed to gather the crio journal of node master-1")
+
+	_, files := readArchive(t, archiveName)
+	directory := filepath.Join(dir, "journal-log-bundle-1")
+	assert.Equal(t, map[string]string{
+		filepath.Join(directory, "master-0-kubelet.log"): "master-0 kubelet",
+		filepath.Join(directory, "master-0-crio.log"):    "master-0 crio",
+		filepath.Join(directory, "master-1-kubelet.log"): "master-1 kubelet",
+	}, files)
+
+	// the journals are only kept in the archive
+	_, err = os.Stat(directory)
+	assert.True(t, os.IsNotExist(err), "the archive directory was not removed")
+}
+
+func TestGatherNodeJournalsNoNodes(t *testing.T) {
+	server := fakeNodeLogs(nil, nil)
+	defer server.Close()
+
+	archiveName := filepath.Join(t.TempDir(), "journal-log-bundle-1.tar.gz")
+	assert.NoError(t, GatherNodeJournals(context.Background(), logrus.StandardLogger(), &rest.Config{Host: server.URL}, archiveName, JournalUnits))
+	_, err := os.Stat(archiveName)
+	assert.True(t, os.IsNotExist(err), "an archive was created without nodes")
+}
@@ -0,0 +1,146 @@
+package openstack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/utils/openstack/clientconfig"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/openshift/installer/pkg/gather"
+	"github.com/openshift/installer/pkg/gather/providers"
+	"github.com/openshift/installer/pkg/types"
+	openstackdefaults "github.com/openshift/installer/pkg/types/openstack/defaults"
+)
+
+// Gather holds options for resources we want to gather.
+type Gather struct {
+	logger          logrus.FieldLogger
+	cloud           string
+	identifier      map[string]string
+	serialLogBundle string
+	bootstrap       string
+	masters         []string
+	directory       string
+}
+
+// New returns an OpenStack Gather from ClusterMetadata.
+func New(logger logrus.FieldLogger, serialLogBundle string, bootstrap string, masters []string, metadata *types.ClusterMetadata) (providers.Gather, error) {
+	return &Gather{
+		logger:          logger,
+		cloud:           metadata.ClusterPlatformMetadata.OpenStack.Cloud,
+		identifier:      metadata.ClusterPlatformMetadata.OpenStack.Identifier,
+		serialLogBundle: serialLogBundle,
+		bootstrap:       bootstrap,
+		masters:         masters,
+		directory:       filepath.Dir(serialLogBundle),
+	}, nil
+}
+
+// Run is the entrypoint to start the gather process.
+func (g *Gather) Run() error {
+	conn, err := clientconfig.NewServiceClient("compute", openstackdefaults.DefaultClientOpts(g.cloud))
+	if err != nil {
+		return errors.Wrap(err, "failed to create compute client")
+	}
+	return g.gatherConsoleLogs(conn)
+}
+
+// gatherConsoleLogs collects the console logs of the servers of the cluster
+// into the serial log bundle.
+func (g *Gather) gatherConsoleLogs(conn *gophercloud.ServiceClient) error {
+	instances, err := g.findServers(conn)
+	if err != nil {
+		return err
+	}
+
+	if len(instances) == 0 {
+		g.logger.Infoln("Skipping console log gathering: no instances found")
+		return nil
+	}
+
+	serialLogBundleDir := strings.TrimSuffix(filepath.Base(g.serialLogBundle), ".tar.gz")
+	filePathDir := filepath.Join(g.directory, serialLogBundleDir)
+	err = os.MkdirAll(filePathDir, 0755)
+	if err != nil && !errors.Is(err, os.ErrExist) {
+		return err
+	}
+
+	var errs []error
+	var files []string
+	for _, instance := range instances {
+		filePath, err := g.downloadConsoleOutput(conn, instance, filePathDir)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			files = append(files, filePath)
+		}
+	}
+
+	if len(files) > 0 {
+		err := gather.CreateArchive(files, g.serialLogBundle)
+		if err != nil {
+			errs = append(errs, errors.Wrap(err, "failed to create archive"))
+		}
+	}
+
+	if err := gather.DeleteArchiveDirectory(filePathDir); err != nil {
+		// Note: cleanup is best effort, it shouldn't fail the gather
+		g.logger.Debugf("Failed to remove archive directory: %v", err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// findServers returns the servers with metadata that matches the identifier
+// of the cluster.
+func (g *Gather) findServers(conn *gophercloud.ServiceClient) ([]servers.Server, error) {
+	allPages, err := servers.List(conn, servers.ListOpts{}).AllPages()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list servers")
+	}
+	allServers, err := servers.ExtractServers(allPages)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to extract servers")
+	}
+
+	var matched []servers.Server
+	for _, server := range allServers {
+		matches := true
+		for key, value := range g.identifier {
+			if server.Metadata[key] != value {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			matched = append(matched, server)
+		}
+	}
+	return matched, nil
+}
+
+func (g *Gather) downloadConsoleOutput(conn *gophercloud.ServiceClient, server servers.Server, filePathDir string) (string, error) {
+	logger := g.logger.WithField("Server", server.Name)
+
+	logger.Debugf("Attemping to download console logs for %s", server.Name)
+	output, err := servers.ShowConsoleOutput(conn, server.ID, servers.ShowConsoleOutputOpts{}).Extract()
+	if err != nil {
+		logger.Errorln(err)
+		return "", errors.Wrapf(err, "failed to get the console output of %s", server.Name)
+	}
+
+	filename := filepath.Join(filePathDir, fmt.Sprintf("%s-serial.log", server.Name))
+	if err := os.WriteFile(filename, []byte(output), 0o644); err != nil {
+		return "", errors.Wrap(err, "failed to write to file")
+	}
+	logger.Debug("Download complete")
+
+	return filename, nil
+}
@@ -0,0 +1,133 @@
+package openstack
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeCompute serves the servers and the console outputs of those it has
+// one for, and fails for the others.
+func fakeCompute(servers string, outputs map[string]string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/servers/detail", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"servers":[%s]}`, servers)
+	})
+	mux.HandleFunc("/servers/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/servers/"), "/action")
+		output, ok := outputs[id]
+		if r.Method != http.MethodPost || !ok {
+			http.Error(w, "the console output is unavailable", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"output":%q}`, output)
+	})
+	return httptest.NewServer(mux)
+}
+
+func newGather(t *testing.T, identifier map[string]string) *Gather {
+	serialLogBundle := filepath.Join(t.TempDir(), "serial-log-bundle-1.tar.gz")
+	return &Gather{
+		logger:          logrus.StandardLogger(),
+		identifier:      identifier,
+		serialLogBundle: serialLogBundle,
+		directory:       filepath.Dir(serialLogBundle),
+	}
+}
+
+// archivedFiles returns the contents of the files of the gzipped tar file
+// archiveName by their base names.
+func archivedFiles(t *testing.T, archiveName string) map[string]string {
+	files := map[string]string{}
+	file, err := os.Open(archiveName)
+	if !assert.NoError(t, err) {
+		return files
+	}
+	defer file.Close()
+	gzipReader, err := gzip.NewReader(file)
+	if !assert.NoError(t, err) {
+		return files
+	}
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			break
+		}
+		data, err := io.ReadAll(tarReader)
+		assert.NoError(t, err)
+		files[filepath.Base(header.Name)] = string(data)
+	}
+	return files
+}
+
+const clusterServers = `
+{"id":"1","name":"test-abcde-master-0","metadata":{"openshiftClusterID":"test-abcde"}},
+{"id":"2","name":"test-abcde-master-1","metadata":{"openshiftClusterID":"test-abcde"}},
+{"id":"3","name":"other-fghij-master-0","metadata":{"openshiftClusterID":"other-fghij"}},
+{"id":"4","name":"unlabeled","metadata":{}}`
+
+func TestGatherConsoleLogs(t *testing.T) {
+	server := fakeCompute(clusterServers, map[string]string{
+		"1": "master-0 console",
+		"2": "master-1 console",
+		"3": "other console",
+	})
+	defer server.Close()
+	conn := &gophercloud.ServiceClient{ProviderClient: &gophercloud.ProviderClient{}, Endpoint: server.URL + "/"}
+
+	g := newGather(t, map[string]string{"openshiftClusterID": "test-abcde"})
+	if !assert.NoError(t, g.gatherConsoleLogs(conn)) {
+		return
+	}
+	// only the servers of the cluster are gathered
+	assert.Equal(t, map[string]string{
+		"test-abcde-master-0-serial.log": "master-0 console",
+		"test-abcde-master-1-serial.log": "master-1 console",
+	}, archivedFiles(t, g.serialLogBundle))
+
+	// the console logs are only kept in the archive
+	_, err := os.Stat(filepath.Join(g.directory, "serial-log-bundle-1"))
+	assert.True(t, os.IsNotExist(err), "the archive directory was not removed")
+}
+
+func TestGatherConsoleLogsFailure(t *testing.T) {
+	server := fakeCompute(clusterServers, map[string]string{"2": "master-1 console"})
+	defer server.Close()
+	conn := &gophercloud.ServiceClient{ProviderClient: &gophercloud.ProviderClient{}, Endpoint: server.URL + "/"}
+
+	g := newGather(t, map[string]string{"openshiftClusterID": "test-abcde"})
+	// the console logs which could not be gathered are reported, and the
+	// others are archived nonetheless
+	assert.ErrorContains(t, g.gatherConsoleLogs(conn), "failed to get the console output of test-abcde-master-0")
+	assert.Equal(t, map[string]string{
+		"test-abcde-master-1-serial.log": "master-1 console",
+	}, archivedFiles(t, g.serialLogBundle))
+}
+
+func TestGatherConsoleLogsNoServers(t *testing.T) {
+	server := fakeCompute(clusterServers, nil)
+	defer server.Close()
+	conn := &gophercloud.ServiceClient{ProviderClient: &gophercloud.ProviderClient{}, Endpoint: server.URL + "/"}
+
+	g := newGather(t, map[string]string{"openshiftClusterID": "missing-klmno"})
+	assert.NoError(t, g.gatherConsoleLogs(conn))
+	_, err := os.Stat(g.serialLogBundle)
+	assert.True(t, os.IsNotExist(err), "an archive was created without servers")
+}
@@ -0,0 +1,7 @@
+package openstack
+
+import "github.com/openshift/installer/pkg/gather/providers"
+
+func init() {
+	providers.Registry["openstack"] = New
+}
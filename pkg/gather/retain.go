@@ -0,0 +1,94 @@
+package gather
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// RetainedArtifactsNamespace is the namespace of the Secrets of the
+	// retained bootstrap artifacts.
+	RetainedArtifactsNamespace = "kube-system"
+	// RetainedArtifactsLabel labels the Secrets of the retained bootstrap
+	// artifacts with the name of the bundle.
+	RetainedArtifactsLabel = "installer.openshift.io/bootstrap-artifacts"
+	// retainedArtifactsPartAnnotation is the index of the part of the
+	// bundle a Secret holds, and the number of parts.
+	retainedArtifactsPartAnnotation = "installer.openshift.io/part"
+)
+
+var (
+	// secretPartSize is the size of the parts of the bundle, under the
+	// size limit of a Secret.
+	secretPartSize = 768 << 10
+	// maxSecretParts is the number of Secrets a bundle may take. Larger
+	// bundles belong in an object store rather than in etcd.
+	maxSecretParts = 16
+)
+
+// RetainInSecrets stores the bundle in Secrets of the cluster, split in parts
+// under the size limit of a Secret, for post-mortem debugging once the
+// bootstrap machine is destroyed. The parts are labeled with the name of the
+// bundle, and concatenated in the order of their part annotation they are
+// the bundle.
+func RetainInSecrets(ctx context.Context, config *rest.Config, name string, data []byte) error {
+	secrets, err := retainedSecrets(name, data)
+	if err != nil {
+		return err
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return errors.Wrap(err, "failed to create a kubernetes client")
+	}
+	for _, secret := range secrets {
+		_, err := client.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			_, err = client.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to store the Secret %s/%s", secret.Namespace, secret.Name)
+		}
+	}
+	return nil
+}
+
+// retainedSecrets returns the Secrets of the parts of the bundle.
+func retainedSecrets(name string, data []byte) ([]*corev1.Secret, error) {
+	parts := (len(data) + secretPartSize - 1) / secretPartSize
+	if parts == 0 {
+		parts = 1
+	}
+	if parts > maxSecretParts {
+		return nil, errors.Errorf("the bundle of %d bytes would take %d Secrets, more than %d; retain it in an object store instead", len(data), parts, maxSecretParts)
+	}
+	label := name
+	if len(label) > 63 {
+		label = label[:63]
+	}
+	secrets := make([]*corev1.Secret, 0, parts)
+	for i := 0; i < parts; i++ {
+		end := (i + 1) * secretPartSize
+		if end > len(data) {
+			end = len(data)
+		}
+		secrets = append(secrets, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        fmt.Sprintf("bootstrap-artifacts-%d", i),
+				Namespace:   RetainedArtifactsNamespace,
+				Labels:      map[string]string{RetainedArtifactsLabel: label},
+				Annotations: map[string]string{retainedArtifactsPartAnnotation: strconv.Itoa(i) + "/" + strconv.Itoa(parts)},
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{name: data[i*secretPartSize : end]},
+		})
+	}
+	return secrets, nil
+}
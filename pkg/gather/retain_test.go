@@ -0,0 +1,46 @@
+package gather
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetainedSecrets(t *testing.T) {
+	defer func(size, parts int) { secretPartSize, maxSecretParts = size, parts }(secretPartSize, maxSecretParts)
+	secretPartSize, maxSecretParts = 4, 3
+
+	cases := []struct {
+		name   string
+		data   string
+		expect []string
+		err    string
+	}{
+		{name: "empty", data: "", expect: []string{""}},
+		{name: "one part", data: "abcd", expect: []string{"abcd"}},
+		{name: "parts", data: "abcdefghij", expect: []string{"abcd", "efgh", "ij"}},
+		{name: "too large", data: "abcdefghijklm", err: `^the bundle of 13 bytes would take 4 Secrets, more than 3; retain it in an object store instead$`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			secrets, err := retainedSecrets("log-bundle.tar.gz", []byte(tc.data))
+			if tc.err != "" {
+				assert.Regexp(t, tc.err, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			var joined bytes.Buffer
+			for i, secret := range secrets {
+				assert.Equal(t, RetainedArtifactsNamespace, secret.Namespace)
+				assert.Equal(t, "log-bundle.tar.gz", secret.Labels[RetainedArtifactsLabel])
+				assert.Equal(t, tc.expect[i], string(secret.Data["log-bundle.tar.gz"]))
+				joined.Write(secret.Data["log-bundle.tar.gz"])
+			}
+			assert.Len(t, secrets, len(tc.expect))
+			assert.Equal(t, tc.data, joined.String())
+		})
+	}
+}
@@ -178,6 +178,45 @@ func analyzeService(r io.Reader) (analysis, error) {
 	return a, nil
 }
 
+// ServiceAnalysis is the outcome of the runs of a service of the bootstrap
+// machine.
+type ServiceAnalysis struct {
+	Name string `json:"name"`
+	// Starts is the number of times that the service started.
+	Starts int `json:"starts"`
+	// Successful is true if the last invocation of the service ended in success.
+	Successful bool `json:"successful"`
+	// FailingStage is the stage that failed in the last unsuccessful invocation of the service.
+	FailingStage string `json:"failingStage,omitempty"`
+	// LastError is the last error recorded in the last failure of the service.
+	LastError string `json:"lastError,omitempty"`
+}
+
+// ServiceName returns the name of the service whose entries file is at the
+// path in a gather bundle, and false if the path is not a service entries file.
+func ServiceName(path string) (string, bool) {
+	submatch := serviceEntriesFilePathRegex.FindStringSubmatch(path)
+	if submatch == nil {
+		return "", false
+	}
+	return submatch[1], true
+}
+
+// AnalyzeService analyzes the entries file of the service with the name.
+func AnalyzeService(name string, r io.Reader) (ServiceAnalysis, error) {
+	a, err := analyzeService(r)
+	if err != nil {
+		return ServiceAnalysis{Name: name}, err
+	}
+	return ServiceAnalysis{
+		Name:         name,
+		Starts:       a.starts,
+		Successful:   a.successful,
+		FailingStage: a.failingStage,
+		LastError:    a.lastError,
+	}, nil
+}
+
 func (a analysis) logLastError() {
 	for _, l := range strings.Split(a.lastError, "\n") {
 		logrus.Info(l)
@@ -2,6 +2,7 @@
 package ssh
 
 import (
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/ssh"
 	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/net/proxy"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 
 	"github.com/openshift/installer/pkg/lineprinter"
@@ -20,12 +22,29 @@ import (
 //
 // if keys list is empty, it tries to load the keys from the user's environment.
 func NewClient(user, address string, keys []string) (*ssh.Client, error) {
+	return NewClientWithProxy(user, address, keys, Proxy{})
+}
+
+// Proxy is how an SSH client tunnels its connection to a host that is not
+// reachable directly. At most one of its fields is set; the zero Proxy
+// connects directly.
+type Proxy struct {
+	// Bastion is the [user@]host[:port] of an SSH jump host. The user
+	// defaults to core and the port to 22.
+	Bastion string
+	// SOCKS is the host:port of a SOCKS5 proxy.
+	SOCKS string
+}
+
+// NewClientWithProxy creates a new SSH client like NewClient, connecting to
+// address through the proxy. The same keys authenticate with a bastion host.
+func NewClientWithProxy(user, address string, keys []string, proxy Proxy) (*ssh.Client, error) {
 	ag, agentType, err := getAgent(keys)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to initialize the SSH agent")
 	}
 
-	client, err := ssh.Dial("tcp", address, &ssh.ClientConfig{
+	config := &ssh.ClientConfig{
 		User: user,
 		Auth: []ssh.AuthMethod{
 			// Use a callback rather than PublicKeys
@@ -34,7 +53,8 @@ func NewClient(user, address string, keys []string) (*ssh.Client, error) {
 			ssh.PublicKeysCallback(ag.Signers),
 		},
 		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-	})
+	}
+	client, err := proxy.dial(address, config)
 	if err != nil {
 		if strings.Contains(err.Error(), "ssh: handshake failed: ssh: unable to authenticate") {
 			if agentType == "agent" {
@@ -50,6 +70,67 @@ func NewClient(user, address string, keys []string) (*ssh.Client, error) {
 	return client, nil
 }
 
+// dial connects an SSH client to address through the proxy.
+func (p Proxy) dial(address string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	var conn net.Conn
+	switch {
+	case p.Bastion != "":
+		bastionConfig := *config
+		bastionConfig.User = "core"
+		bastionAddress := p.Bastion
+		if i := strings.LastIndex(bastionAddress, "@"); i >= 0 {
+			bastionConfig.User = bastionAddress[:i]
+			bastionAddress = bastionAddress[i+1:]
+		}
+		if _, _, err := net.SplitHostPort(bastionAddress); err != nil {
+			bastionAddress = net.JoinHostPort(bastionAddress, "22")
+		}
+		bastion, err := ssh.Dial("tcp", bastionAddress, &bastionConfig)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to connect to the bastion host %s", bastionAddress)
+		}
+		bastionConn, err := bastion.Dial("tcp", address)
+		if err != nil {
+			bastion.Close()
+			return nil, errors.Wrapf(err, "failed to connect to %s through the bastion host", address)
+		}
+		conn = &bastionConnection{Conn: bastionConn, bastion: bastion}
+	case p.SOCKS != "":
+		dialer, err := proxy.SOCKS5("tcp", p.SOCKS, nil, proxy.Direct)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create the SOCKS dialer")
+		}
+		conn, err = dialer.Dial("tcp", address)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to connect to %s through the SOCKS proxy", address)
+		}
+	default:
+		return ssh.Dial("tcp", address, config)
+	}
+
+	c, chans, reqs, err := ssh.NewClientConn(conn, address, config)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
+// bastionConnection is a connection through a bastion host that closes the
+// client of the bastion host with it.
+type bastionConnection struct {
+	net.Conn
+	bastion *ssh.Client
+}
+
+func (c *bastionConnection) Close() error {
+	err := c.Conn.Close()
+	if bastionErr := c.bastion.Close(); err == nil {
+		err = bastionErr
+	}
+	return err
+}
+
 // Run uses an SSH client to execute commands.
 func Run(client *ssh.Client, command string) error {
 	sess, err := client.NewSession()
@@ -0,0 +1,134 @@
+// Package hooks runs the site-specific hooks of the install-config between
+// the phases of the install.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/lineprinter"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// The phases hooks run at.
+const (
+	PreProvision  = "preProvision"
+	PostBootstrap = "postBootstrap"
+	PostInstall   = "postInstall"
+)
+
+// timeout is how long a single hook may run.
+const timeout = 30 * time.Minute
+
+// Event describes the phase to a hook. Executables get it as environment
+// variables and URLs get it as the JSON body of a POST request.
+type Event struct {
+	Phase       string `json:"phase"`
+	ClusterName string `json:"clusterName"`
+	InfraID     string `json:"infraID,omitempty"`
+	AssetDir    string `json:"assetDir"`
+	Kubeconfig  string `json:"kubeconfig,omitempty"`
+}
+
+// ForPhase returns the hooks of the phase.
+func ForPhase(hooks *types.Hooks, phase string) []string {
+	if hooks == nil {
+		return nil
+	}
+	switch phase {
+	case PreProvision:
+		return hooks.PreProvision
+	case PostBootstrap:
+		return hooks.PostBootstrap
+	case PostInstall:
+		return hooks.PostInstall
+	}
+	return nil
+}
+
+// Run runs the hooks of the event's phase in order, stopping at the first
+// hook that fails.
+func Run(ctx context.Context, hooks *types.Hooks, event Event) error {
+	// the executables run in the asset directory
+	for _, path := range []*string{&event.AssetDir, &event.Kubeconfig} {
+		if *path == "" {
+			continue
+		}
+		abs, err := filepath.Abs(*path)
+		if err != nil {
+			return err
+		}
+		*path = abs
+	}
+
+	for _, hook := range ForPhase(hooks, event.Phase) {
+		logrus.Infof("Running %s hook %s", event.Phase, hook)
+		hookCtx, cancel := context.WithTimeout(ctx, timeout)
+		var err error
+		if strings.HasPrefix(hook, "http://") || strings.HasPrefix(hook, "https://") {
+			err = post(hookCtx, hook, event)
+		} else {
+			err = execute(hookCtx, hook, event)
+		}
+		cancel()
+		if err != nil {
+			return errors.Wrapf(err, "%s hook %s failed", event.Phase, hook)
+		}
+	}
+	return nil
+}
+
+func execute(ctx context.Context, path string, event Event) error {
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Env = append(os.Environ(),
+		"OPENSHIFT_INSTALL_HOOK_PHASE="+event.Phase,
+		"OPENSHIFT_INSTALL_CLUSTER_NAME="+event.ClusterName,
+		"OPENSHIFT_INSTALL_INFRA_ID="+event.InfraID,
+		"OPENSHIFT_INSTALL_ASSET_DIR="+event.AssetDir,
+	)
+	if event.Kubeconfig != "" {
+		cmd.Env = append(cmd.Env, "KUBECONFIG="+event.Kubeconfig)
+	}
+	cmd.Dir = event.AssetDir
+	stdout := &lineprinter.LinePrinter{Print: (&lineprinter.Trimmer{WrappedPrint: logrus.Debug}).Print}
+	stderr := &lineprinter.LinePrinter{Print: (&lineprinter.Trimmer{WrappedPrint: logrus.Info}).Print}
+	defer stdout.Close()
+	defer stderr.Close()
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+func post(ctx context.Context, url string, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
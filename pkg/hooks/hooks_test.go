@@ -0,0 +1,65 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestRunExecutable(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "hook.sh")
+	err := os.WriteFile(script, []byte("#!/bin/sh\necho \"$OPENSHIFT_INSTALL_HOOK_PHASE $OPENSHIFT_INSTALL_CLUSTER_NAME $KUBECONFIG\" > \"$OPENSHIFT_INSTALL_ASSET_DIR/out\"\n"), 0755) //nolint:gosec // test executable
+	require.NoError(t, err)
+
+	err = Run(context.Background(), &types.Hooks{PostInstall: []string{script}}, Event{
+		Phase:       PostInstall,
+		ClusterName: "test-cluster",
+		AssetDir:    dir,
+		Kubeconfig:  "/dir/auth/kubeconfig",
+	})
+	require.NoError(t, err)
+
+	out, err := os.ReadFile(filepath.Join(dir, "out"))
+	require.NoError(t, err)
+	assert.Equal(t, "postInstall test-cluster /dir/auth/kubeconfig\n", string(out))
+}
+
+func TestRunURL(t *testing.T) {
+	var received Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	event := Event{Phase: PreProvision, ClusterName: "test-cluster", InfraID: "test-cluster-abcde", AssetDir: "/dir"}
+	err := Run(context.Background(), &types.Hooks{PreProvision: []string{server.URL}}, event)
+	require.NoError(t, err)
+	assert.Equal(t, event, received)
+}
+
+func TestRunStopsAtFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "ticket rejected", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	never := filepath.Join(dir, "never")
+	err := Run(context.Background(), &types.Hooks{PostBootstrap: []string{server.URL, never}}, Event{Phase: PostBootstrap, AssetDir: dir})
+	assert.Regexp(t, `^postBootstrap hook http://.* failed: 403 Forbidden: ticket rejected$`, err)
+}
+
+func TestRunNoHooks(t *testing.T) {
+	assert.NoError(t, Run(context.Background(), nil, Event{Phase: PostInstall}))
+}
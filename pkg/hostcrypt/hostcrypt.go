@@ -0,0 +1,41 @@
+// Package hostcrypt checks that the host running the installer can create a
+// cluster with the cryptographic requirements of the install-config.
+package hostcrypt
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// fipsFile reports whether the kernel of the host is in FIPS mode.
+	fipsFile = "/proc/sys/crypto/fips_enabled"
+
+	binaryInstructions = "To obtain a suitable binary, download the openshift-install-rhel8 archive from the client mirror, or extract the openshift-install-fips command from the release payload."
+)
+
+// fipsCapable is set when the installer is built with the fipscapable tag,
+// which is only given to builds with a Go toolchain using the FIPS validated
+// OpenSSL of the host instead of the Go cryptographic libraries.
+var fipsCapable = false
+
+// VerifyHostTargetState returns an error when the cluster is to be installed
+// in FIPS mode but the installer binary was not built with FIPS validated
+// cryptography or the host is not in FIPS mode.
+func VerifyHostTargetState(fips bool) error {
+	if !fips {
+		return nil
+	}
+	if !fipsCapable {
+		return fmt.Errorf("target cluster is in FIPS mode, operation requires a FIPS capable installer binary. %s", binaryInstructions)
+	}
+	data, err := os.ReadFile(fipsFile)
+	if err != nil {
+		return fmt.Errorf("target cluster is in FIPS mode, but unable to determine whether FIPS is enabled on this host: %w", err)
+	}
+	if strings.TrimSpace(string(data)) != "1" {
+		return fmt.Errorf("target cluster is in FIPS mode, operation requires FIPS to be enabled on this host")
+	}
+	return nil
+}
@@ -0,0 +1,7 @@
+//go:build fipscapable
+
+package hostcrypt
+
+func init() {
+	fipsCapable = true
+}
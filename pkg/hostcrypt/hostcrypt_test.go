@@ -0,0 +1,14 @@
+package hostcrypt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyHostTargetState(t *testing.T) {
+	assert.NoError(t, VerifyHostTargetState(false))
+	if !fipsCapable {
+		assert.Regexp(t, "^target cluster is in FIPS mode, operation requires a FIPS capable installer binary", VerifyHostTargetState(true))
+	}
+}
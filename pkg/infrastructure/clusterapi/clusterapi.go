@@ -0,0 +1,220 @@
+// Package clusterapi provisions the infrastructure of a cluster with the
+// Cluster API controllers of its platform, run as local processes against
+// a local control plane which exists only for the duration of the install.
+package clusterapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/infrastructure"
+)
+
+const (
+	// coreComponent is the component of the core Cluster API controllers.
+	coreComponent = "cluster-api"
+	// namespace is the namespace of the Cluster API objects of the cluster.
+	namespace = "openshift-cluster-api-guests"
+	// outputDir is the directory of the install directory the logs of the
+	// local control plane and controllers, and the provisioned objects, are
+	// written to.
+	outputDir = ".clusterapi_output"
+	// provisionTimeout is how long to wait for the infrastructure to be
+	// provisioned.
+	provisionTimeout = 30 * time.Minute
+)
+
+// ManifestsFunc returns the Cluster API objects describing the
+// infrastructure of the cluster, from the parents of the Cluster asset. One
+// of them must be the Cluster.
+type ManifestsFunc func(ctx context.Context, parents asset.Parents) ([]*unstructured.Unstructured, error)
+
+type platformProvider struct {
+	controller string
+	manifests  ManifestsFunc
+}
+
+var platforms = map[string]platformProvider{}
+
+// Register makes the Cluster API backend available for the platform, with
+// the embedded controller of the platform, e.g. cluster-api-provider-aws,
+// and the manifests describing the infrastructure.
+func Register(platform string, controller string, manifests ManifestsFunc) {
+	platforms[platform] = platformProvider{controller: controller, manifests: manifests}
+}
+
+// Platforms returns the platforms the Cluster API backend is available for.
+func Platforms() []string {
+	names := make([]string, 0, len(platforms))
+	for name := range platforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Cluster returns the Cluster of the infrastructure object of a platform,
+// e.g. an AWSCluster, which has the same name.
+func Cluster(infra *unstructured.Unstructured) *unstructured.Unstructured {
+	cluster := &unstructured.Unstructured{}
+	cluster.SetAPIVersion("cluster.x-k8s.io/v1beta1")
+	cluster.SetKind("Cluster")
+	cluster.SetName(infra.GetName())
+	cluster.Object["spec"] = map[string]interface{}{
+		"clusterNetwork": map[string]interface{}{
+			"apiServerPort": int64(6443),
+		},
+		"infrastructureRef": map[string]interface{}{
+			"apiVersion": infra.GetAPIVersion(),
+			"kind":       infra.GetKind(),
+			"name":       infra.GetName(),
+		},
+	}
+	return cluster
+}
+
+// Provider provisions the infrastructure of a cluster with Cluster API.
+type Provider struct {
+	platform string
+	platformProvider
+}
+
+var (
+	_ infrastructure.Provider = (*Provider)(nil)
+	_ infrastructure.Planner  = (*Provider)(nil)
+)
+
+// ProviderForPlatform returns the Cluster API backend of the platform, and
+// whether the platform is registered with it.
+func ProviderForPlatform(platform string) (infrastructure.Provider, bool) {
+	p, ok := platforms[platform]
+	if !ok {
+		return nil, false
+	}
+	return &Provider{platform: platform, platformProvider: p}, true
+}
+
+// Name returns the name of the provisioning backend.
+func (p *Provider) Name() string {
+	return infrastructure.ClusterAPI
+}
+
+// Plan returns the Cluster API objects Provision would create, as a single
+// stage. The infrastructure they describe is only known once the
+// controllers reconcile them.
+func (p *Provider) Plan(ctx context.Context, dir string, parents asset.Parents) (*infrastructure.Plan, error) {
+	objects, err := p.manifests(ctx, parents)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate the Cluster API manifests")
+	}
+	stage := infrastructure.StagePlan{Name: p.platform}
+	for _, obj := range objects {
+		stage.Changes = append(stage.Changes, infrastructure.ResourceChange{
+			Address: fmt.Sprintf("%s/%s", obj.GetKind(), obj.GetName()),
+			Type:    obj.GetKind(),
+			Actions: []string{"create"},
+		})
+	}
+	stage.Raw, err = json.Marshal(objects)
+	if err != nil {
+		return nil, err
+	}
+	return &infrastructure.Plan{
+		Backend: infrastructure.ClusterAPI,
+		Stages:  []infrastructure.StagePlan{stage},
+	}, nil
+}
+
+// Provision starts the local control plane and the controllers, creates the
+// objects describing the infrastructure and waits for the Cluster to report
+// its infrastructure ready. It returns the provisioned objects.
+func (p *Provider) Provision(ctx context.Context, dir string, parents asset.Parents) ([]*asset.File, error) {
+	objects, err := p.manifests(ctx, parents)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate the Cluster API manifests")
+	}
+	var cluster *unstructured.Unstructured
+	for _, obj := range objects {
+		if obj.GetKind() == "Cluster" {
+			cluster = obj
+		}
+	}
+	if cluster == nil {
+		return nil, errors.Errorf("the Cluster API manifests of %s have no Cluster", p.platform)
+	}
+
+	workDir, err := os.MkdirTemp("", "openshift-install-clusterapi-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(workDir)
+	logDir := filepath.Join(dir, outputDir)
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Starting the local control plane for Cluster API")
+	cp, err := startLocalControlPlane(ctx, workDir, logDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to start the local control plane")
+	}
+	defer cp.stop()
+
+	for _, component := range []string{coreComponent, p.controller} {
+		if err := cp.installCRDs(ctx, component); err != nil {
+			return nil, errors.Wrapf(err, "failed to install the CustomResourceDefinitions of %s", component)
+		}
+		if err := cp.startController(component); err != nil {
+			return nil, errors.Wrapf(err, "failed to start %s", component)
+		}
+	}
+
+	if err := cp.apply(ctx, namespace, objects); err != nil {
+		return nil, errors.Wrap(err, "failed to create the Cluster API objects")
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, provisionTimeout)
+	defer cancel()
+	waitErr := cp.waitForInfrastructure(waitCtx, namespace, cluster.GetName())
+
+	files, err := p.collect(ctx, cp, objects)
+	if err != nil {
+		logrus.Warnf("Failed to collect the Cluster API objects: %v", err)
+	}
+	if waitErr != nil {
+		return files, errors.Wrap(waitErr, asset.ClusterCreationError)
+	}
+	return files, nil
+}
+
+// collect returns the current state of the objects as files.
+func (p *Provider) collect(ctx context.Context, cp *localControlPlane, objects []*unstructured.Unstructured) ([]*asset.File, error) {
+	files := make([]*asset.File, 0, len(objects))
+	for _, obj := range objects {
+		current, err := cp.get(ctx, namespace, obj)
+		if err != nil {
+			return files, err
+		}
+		data, err := yaml.Marshal(current.Object)
+		if err != nil {
+			return files, err
+		}
+		files = append(files, &asset.File{
+			Filename: filepath.Join(outputDir, fmt.Sprintf("%s-%s.yaml", strings.ToLower(obj.GetKind()), obj.GetName())),
+			Data:     data,
+		})
+	}
+	return files, nil
+}
@@ -0,0 +1,97 @@
+package clusterapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestCRDs(t *testing.T) {
+	cases := []struct {
+		name     string
+		manifest string
+		expected []string
+		err      string
+	}{
+		{
+			name:     "empty",
+			manifest: "",
+		},
+		{
+			name: "only CRDs are kept",
+			manifest: `apiVersion: v1
+kind: Namespace
+metadata:
+  name: capi-system
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: clusters.cluster.x-k8s.io
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: capi-controller-manager
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: machines.cluster.x-k8s.io
+`,
+			expected: []string{"clusters.cluster.x-k8s.io", "machines.cluster.x-k8s.io"},
+		},
+		{
+			name:     "invalid",
+			manifest: "kind: [",
+			err:      "failed to decode the manifest",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			list, err := crds([]byte(tc.manifest))
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			names := []string{}
+			for _, crd := range list {
+				names = append(names, crd.GetName())
+			}
+			assert.ElementsMatch(t, tc.expected, names)
+		})
+	}
+}
+
+func TestCRDsRemoveConversion(t *testing.T) {
+	list, err := crds([]byte(`apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: clusters.cluster.x-k8s.io
+spec:
+  group: cluster.x-k8s.io
+  conversion:
+    strategy: Webhook
+`))
+	assert.NoError(t, err)
+	if assert.Len(t, list, 1) {
+		_, found, _ := unstructured.NestedFieldNoCopy(list[0].Object, "spec", "conversion")
+		assert.False(t, found)
+		group, _, _ := unstructured.NestedString(list[0].Object, "spec", "group")
+		assert.Equal(t, "cluster.x-k8s.io", group)
+	}
+}
+
+func TestProviderForPlatform(t *testing.T) {
+	_, ok := ProviderForPlatform("none")
+	assert.False(t, ok)
+
+	Register("test", "cluster-api-provider-test", nil)
+	defer delete(platforms, "test")
+	provider, ok := ProviderForPlatform("test")
+	if assert.True(t, ok) {
+		assert.Equal(t, "clusterapi", provider.Name())
+	}
+}
@@ -0,0 +1,79 @@
+package clusterapi
+
+import (
+	"bytes"
+	"embed"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// mirror holds the binaries and manifests of the local control plane and
+// the controllers, copied in at build time as described in mirror/README.
+//
+//go:embed mirror/*
+var mirror embed.FS
+
+// extract writes the embedded binary to the directory and returns its path.
+func extract(name string, dir string) (string, error) {
+	src, err := mirror.Open(path.Join("mirror", name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", errors.Errorf("%s is not embedded in this installer", name)
+	}
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	dest := filepath.Join(dir, path.Base(name))
+	file, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, src); err != nil {
+		return "", errors.Wrapf(err, "failed to extract %s", name)
+	}
+	return dest, nil
+}
+
+// componentCRDs returns the CustomResourceDefinitions of the embedded
+// manifest of the component.
+func componentCRDs(component string) ([]*unstructured.Unstructured, error) {
+	data, err := mirror.ReadFile(path.Join("mirror", component, "components.yaml"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, errors.Errorf("the manifest of %s is not embedded in this installer", component)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return crds(data)
+}
+
+// crds returns the CustomResourceDefinitions of the multi-document manifest,
+// without their conversion webhooks, which are not served by controllers
+// run outside of a cluster.
+func crds(data []byte) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	var list []*unstructured.Unstructured
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return list, nil
+			}
+			return nil, errors.Wrap(err, "failed to decode the manifest")
+		}
+		if obj.GetKind() != "CustomResourceDefinition" {
+			continue
+		}
+		unstructured.RemoveNestedField(obj.Object, "spec", "conversion")
+		list = append(list, obj)
+	}
+}
@@ -0,0 +1,429 @@
+package clusterapi
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/openshift/installer/pkg/asset/tls"
+	"github.com/openshift/installer/pkg/metrics/progress"
+)
+
+var (
+	crdResource       = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	namespaceResource = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	clusterResource   = schema.GroupVersionResource{Group: "cluster.x-k8s.io", Version: "v1beta1", Resource: "clusters"}
+)
+
+// localControlPlane is an etcd and a kube-apiserver listening on the
+// loopback interface, and the controllers run against them.
+type localControlPlane struct {
+	binDir     string
+	logDir     string
+	kubeconfig string
+	config     *rest.Config
+	client     dynamic.Interface
+	mapper     meta.ResettableRESTMapper
+	processes  []*exec.Cmd
+}
+
+// startLocalControlPlane starts etcd and kube-apiserver from the embedded
+// binaries, keeping their data in workDir and their logs in logDir.
+func startLocalControlPlane(ctx context.Context, workDir string, logDir string) (cp *localControlPlane, err error) {
+	cp = &localControlPlane{
+		binDir: filepath.Join(workDir, "bin"),
+		logDir: logDir,
+	}
+	defer func() {
+		if err != nil {
+			cp.stop()
+		}
+	}()
+	if err := os.MkdirAll(cp.binDir, 0755); err != nil {
+		return nil, err
+	}
+
+	ports, err := freePorts(3)
+	if err != nil {
+		return nil, err
+	}
+	etcdURL := fmt.Sprintf("http://127.0.0.1:%d", ports[0])
+	etcd, err := extract("etcd", cp.binDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := cp.start("etcd", etcd,
+		"--data-dir", filepath.Join(workDir, "etcd"),
+		"--listen-client-urls", etcdURL,
+		"--advertise-client-urls", etcdURL,
+		"--listen-peer-urls", fmt.Sprintf("http://127.0.0.1:%d", ports[1]),
+		"--unsafe-no-fsync",
+	); err != nil {
+		return nil, err
+	}
+
+	certDir := filepath.Join(workDir, "certs")
+	if err := os.MkdirAll(certDir, 0700); err != nil {
+		return nil, err
+	}
+	certs, err := generateCerts(certDir)
+	if err != nil {
+		return nil, err
+	}
+	apiserver, err := extract("kube-apiserver", cp.binDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := cp.start("kube-apiserver", apiserver,
+		"--etcd-servers", etcdURL,
+		"--bind-address", "127.0.0.1",
+		"--secure-port", fmt.Sprint(ports[2]),
+		"--tls-cert-file", filepath.Join(certDir, "serving.crt"),
+		"--tls-private-key-file", filepath.Join(certDir, "serving.key"),
+		"--client-ca-file", filepath.Join(certDir, "ca.crt"),
+		"--service-account-key-file", filepath.Join(certDir, "service-account.pub"),
+		"--service-account-signing-key-file", filepath.Join(certDir, "service-account.key"),
+		"--service-account-issuer", "https://127.0.0.1",
+		"--service-cluster-ip-range", "10.0.0.0/24",
+		"--authorization-mode", "RBAC",
+		"--disable-admission-plugins", "ServiceAccount",
+	); err != nil {
+		return nil, err
+	}
+
+	cp.config = &rest.Config{
+		Host: fmt.Sprintf("https://127.0.0.1:%d", ports[2]),
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData:   certs.ca,
+			CertData: certs.clientCert,
+			KeyData:  certs.clientKey,
+		},
+	}
+	cp.kubeconfig = filepath.Join(workDir, "kubeconfig")
+	if err := writeKubeconfig(cp.kubeconfig, cp.config); err != nil {
+		return nil, err
+	}
+	if cp.client, err = dynamic.NewForConfig(cp.config); err != nil {
+		return nil, err
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cp.config)
+	if err != nil {
+		return nil, err
+	}
+	cp.mapper = restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+
+	err = wait.PollImmediateWithContext(ctx, time.Second, time.Minute, func(ctx context.Context) (bool, error) {
+		_, err := discoveryClient.RESTClient().Get().AbsPath("/readyz").DoRaw(ctx)
+		return err == nil, nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "kube-apiserver did not become ready, see %s", filepath.Join(logDir, "kube-apiserver.log"))
+	}
+	return cp, nil
+}
+
+// start runs the binary in the background, logging to <name>.log in the
+// log directory.
+func (cp *localControlPlane) start(name string, binary string, args ...string) error {
+	log, err := os.Create(filepath.Join(cp.logDir, name+".log"))
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(binary, args...)
+	cmd.Stdout = log
+	cmd.Stderr = log
+	if err := cmd.Start(); err != nil {
+		log.Close()
+		return errors.Wrapf(err, "failed to start %s", name)
+	}
+	logrus.Debugf("Started %s (pid %d)", name, cmd.Process.Pid)
+	cp.processes = append(cp.processes, cmd)
+	go func() {
+		cmd.Wait()
+		log.Close()
+	}()
+	return nil
+}
+
+// startController runs the embedded controller of the component against
+// the local control plane.
+func (cp *localControlPlane) startController(component string) error {
+	binary, err := extract(filepath.Join(component, component), cp.binDir)
+	if err != nil {
+		return err
+	}
+	return cp.start(component, binary,
+		"--kubeconfig", cp.kubeconfig,
+		"--leader-elect=false",
+		"--metrics-bind-addr", "0",
+	)
+}
+
+// stop stops the controllers and the control plane, in the reverse order
+// they were started in.
+func (cp *localControlPlane) stop() {
+	for i := len(cp.processes) - 1; i >= 0; i-- {
+		if err := cp.processes[i].Process.Kill(); err != nil {
+			logrus.Debugf("Failed to stop %s: %v", cp.processes[i].Path, err)
+		}
+	}
+	cp.processes = nil
+}
+
+// installCRDs creates the CustomResourceDefinitions of the component and
+// waits for them to be established.
+func (cp *localControlPlane) installCRDs(ctx context.Context, component string) error {
+	list, err := componentCRDs(component)
+	if err != nil {
+		return err
+	}
+	for _, crd := range list {
+		if _, err := cp.client.Resource(crdResource).Create(ctx, crd, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "failed to create %s", crd.GetName())
+		}
+	}
+	for _, crd := range list {
+		err := wait.PollImmediateWithContext(ctx, time.Second, time.Minute, func(ctx context.Context) (bool, error) {
+			current, err := cp.client.Resource(crdResource).Get(ctx, crd.GetName(), metav1.GetOptions{})
+			if err != nil {
+				return false, nil
+			}
+			return conditionTrue(current, "Established"), nil
+		})
+		if err != nil {
+			return errors.Wrapf(err, "%s was not established", crd.GetName())
+		}
+	}
+	cp.mapper.Reset()
+	return nil
+}
+
+// apply creates the namespace and the objects in it.
+func (cp *localControlPlane) apply(ctx context.Context, namespace string, objects []*unstructured.Unstructured) error {
+	ns := &unstructured.Unstructured{}
+	ns.SetAPIVersion("v1")
+	ns.SetKind("Namespace")
+	ns.SetName(namespace)
+	if _, err := cp.client.Resource(namespaceResource).Create(ctx, ns, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	for _, obj := range objects {
+		resource, err := cp.resource(namespace, obj)
+		if err != nil {
+			return err
+		}
+		if _, err := resource.Create(ctx, obj, metav1.CreateOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to create %s %s", obj.GetKind(), obj.GetName())
+		}
+		logrus.Debugf("Created %s %s", obj.GetKind(), obj.GetName())
+	}
+	return nil
+}
+
+// get returns the current state of the object.
+func (cp *localControlPlane) get(ctx context.Context, namespace string, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	resource, err := cp.resource(namespace, obj)
+	if err != nil {
+		return nil, err
+	}
+	return resource.Get(ctx, obj.GetName(), metav1.GetOptions{})
+}
+
+// resource returns the client of the resource of the object, in the
+// namespace if the resource is namespaced.
+func (cp *localControlPlane) resource(namespace string, obj *unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	gvk := obj.GroupVersionKind()
+	mapping, err := cp.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "no resource for %s", gvk)
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		obj.SetNamespace(namespace)
+		return cp.client.Resource(mapping.Resource).Namespace(namespace), nil
+	}
+	return cp.client.Resource(mapping.Resource), nil
+}
+
+// waitForInfrastructure waits for the Cluster to report its infrastructure
+// ready, reporting the changes of its Ready condition on the way.
+func (cp *localControlPlane) waitForInfrastructure(ctx context.Context, namespace string, name string) error {
+	var lastMessage string
+	err := wait.PollImmediateUntilWithContext(ctx, 5*time.Second, func(ctx context.Context) (bool, error) {
+		cluster, err := cp.client.Resource(clusterResource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			logrus.Debugf("Failed to get the Cluster: %v", err)
+			return false, nil
+		}
+		if message, _, _ := unstructured.NestedString(cluster.Object, "status", "failureMessage"); message != "" {
+			return false, errors.New(message)
+		}
+		if message := conditionMessage(cluster, "Ready"); message != "" && message != lastMessage {
+			lastMessage = message
+			logrus.Info(message)
+			progress.Status("infrastructure", message)
+		}
+		ready, _, _ := unstructured.NestedBool(cluster.Object, "status", "infrastructureReady")
+		return ready, nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "the infrastructure of the cluster was not provisioned, see the controller logs in %s", cp.logDir)
+	}
+	logrus.Info("The infrastructure of the cluster is provisioned")
+	return nil
+}
+
+// conditionTrue returns whether the condition of the object is True.
+func conditionTrue(obj *unstructured.Unstructured, conditionType string) bool {
+	condition := findCondition(obj, conditionType)
+	return condition != nil && condition["status"] == "True"
+}
+
+// conditionMessage returns the reason and message of the condition of the
+// object.
+func conditionMessage(obj *unstructured.Unstructured, conditionType string) string {
+	condition := findCondition(obj, conditionType)
+	if condition == nil {
+		return ""
+	}
+	reason, _ := condition["reason"].(string)
+	message, _ := condition["message"].(string)
+	switch {
+	case reason == "":
+		return message
+	case message == "":
+		return reason
+	default:
+		return fmt.Sprintf("%s: %s", reason, message)
+	}
+}
+
+func findCondition(obj *unstructured.Unstructured, conditionType string) map[string]interface{} {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		if condition, ok := c.(map[string]interface{}); ok && condition["type"] == conditionType {
+			return condition
+		}
+	}
+	return nil
+}
+
+// freePorts returns n ports free on the loopback interface.
+func freePorts(n int) ([]int, error) {
+	ports := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return nil, err
+		}
+		defer listener.Close()
+		ports = append(ports, listener.Addr().(*net.TCPAddr).Port)
+	}
+	return ports, nil
+}
+
+// localCerts are the PEM-encoded CA and the admin client certificate of the
+// local control plane.
+type localCerts struct {
+	ca         []byte
+	clientCert []byte
+	clientKey  []byte
+}
+
+// generateCerts writes the CA, the serving certificate and the service
+// account key pair of the local control plane to the directory, and returns
+// the CA and a client certificate for the system:masters group.
+func generateCerts(dir string) (*localCerts, error) {
+	caKey, caCert, err := tls.GenerateSelfSignedCertificate(&tls.CertCfg{
+		Subject:   pkix.Name{CommonName: "clusterapi-local-ca", OrganizationalUnit: []string{"openshift"}},
+		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		Validity:  tls.ValidityOneDay,
+		IsCA:      true,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	servingKey, servingCert, err := tls.GenerateSignedCertificate(caKey, caCert, &tls.CertCfg{
+		Subject:      pkix.Name{CommonName: "kube-apiserver"},
+		KeyUsages:    x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		Validity:     tls.ValidityOneDay,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	clientKey, clientCert, err := tls.GenerateSignedCertificate(caKey, caCert, &tls.CertCfg{
+		Subject:      pkix.Name{CommonName: "openshift-install", Organization: []string{"system:masters"}},
+		KeyUsages:    x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		Validity:     tls.ValidityOneDay,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	serviceAccountKey, err := tls.PrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	serviceAccountPub, err := tls.PublicKeyToPem(&serviceAccountKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, data := range map[string][]byte{
+		"ca.crt":              tls.CertToPem(caCert),
+		"serving.crt":         tls.CertToPem(servingCert),
+		"serving.key":         tls.PrivateKeyToPem(servingKey),
+		"service-account.key": tls.PrivateKeyToPem(serviceAccountKey),
+		"service-account.pub": serviceAccountPub,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			return nil, err
+		}
+	}
+	return &localCerts{
+		ca:         tls.CertToPem(caCert),
+		clientCert: tls.CertToPem(clientCert),
+		clientKey:  tls.PrivateKeyToPem(clientKey),
+	}, nil
+}
+
+// writeKubeconfig writes a kubeconfig for the REST config, which the
+// controllers are given.
+func writeKubeconfig(path string, config *rest.Config) error {
+	kubeconfig := clientcmdapi.NewConfig()
+	kubeconfig.Clusters["local"] = &clientcmdapi.Cluster{
+		Server:                   config.Host,
+		CertificateAuthorityData: config.CAData,
+	}
+	kubeconfig.AuthInfos["admin"] = &clientcmdapi.AuthInfo{
+		ClientCertificateData: config.CertData,
+		ClientKeyData:         config.KeyData,
+	}
+	kubeconfig.Contexts["local"] = &clientcmdapi.Context{Cluster: "local", AuthInfo: "admin"}
+	kubeconfig.CurrentContext = "local"
+	return clientcmd.WriteToFile(*kubeconfig, path)
+}
@@ -0,0 +1,49 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+// Planner is implemented by the provisioning backends which can preview the
+// infrastructure they would create, without creating it.
+type Planner interface {
+	// Plan returns the changes Provision would make for the cluster
+	// described by the parents of the Cluster asset.
+	Plan(ctx context.Context, dir string, parents asset.Parents) (*Plan, error)
+}
+
+// Plan is the changes a provisioning backend would make to create the
+// infrastructure of a cluster.
+type Plan struct {
+	// Backend is the name of the provisioning backend.
+	Backend string `json:"backend"`
+	// Stages are the stages of the infrastructure, in the order they are
+	// created.
+	Stages []StagePlan `json:"stages"`
+}
+
+// StagePlan is the changes of a stage of the infrastructure.
+type StagePlan struct {
+	Name string `json:"name"`
+	// DeferredBy is the stages the stage depends on the outputs of, when it
+	// cannot be planned before they are created.
+	DeferredBy []string `json:"deferredBy,omitempty"`
+	// Changes are the changes to the resources of the stage.
+	Changes []ResourceChange `json:"changes,omitempty"`
+	// Raw is the plan of the stage in the format of the backend, e.g. the
+	// output of terraform show -json.
+	Raw json.RawMessage `json:"raw,omitempty"`
+}
+
+// ResourceChange is a change to a resource.
+type ResourceChange struct {
+	// Address identifies the resource, e.g. aws_vpc.new_vpc[0].
+	Address string `json:"address"`
+	// Type is the type of the resource, e.g. aws_vpc.
+	Type string `json:"type"`
+	// Actions are the actions on the resource, e.g. create.
+	Actions []string `json:"actions"`
+}
@@ -0,0 +1,30 @@
+// Package infrastructure defines the provisioning backends which create the
+// infrastructure of a cluster.
+package infrastructure
+
+import (
+	"context"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+const (
+	// Terraform provisions the infrastructure with the embedded terraform
+	// binary and providers.
+	Terraform = "terraform"
+	// ClusterAPI provisions the infrastructure with the embedded Cluster API
+	// providers, run against a local control plane.
+	ClusterAPI = "clusterapi"
+)
+
+// Provider creates the infrastructure of a cluster.
+type Provider interface {
+	// Name is the name of the provisioning backend.
+	Name() string
+
+	// Provision creates the infrastructure of the cluster described by the
+	// parents of the Cluster asset, using dir as the install directory. It
+	// returns the files recording the infrastructure, which are returned
+	// along with the error when provisioning fails part way.
+	Provision(ctx context.Context, dir string, parents asset.Parents) ([]*asset.File, error)
+}
@@ -0,0 +1,171 @@
+// Package installerproxy routes the outbound HTTP(S) requests of the installer
+// itself, e.g. of the cloud SDKs, of the release image metadata fetch, of the
+// RHCOS stream download and of the quota checks, through a proxy, and records
+// which endpoints the requests reached through which proxy.
+//
+// The requests of the clients built on the default HTTP transport, or on a
+// clone of it made after Configure, go through the proxy. The proxy
+// environment variables are set too, for the subprocesses of the installer.
+package installerproxy
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Direct is the proxy of the endpoints reached without a proxy.
+const Direct = "direct"
+
+// Config is the proxy of the requests of the installer.
+type Config struct {
+	// HTTPProxy is the URL of the proxy of the HTTP requests.
+	HTTPProxy string
+	// HTTPSProxy is the URL of the proxy of the HTTPS requests.
+	HTTPSProxy string
+	// NoProxy is a comma-separated list of the domains, IPs and CIDRs
+	// reached without a proxy, or * for all of them.
+	NoProxy string
+}
+
+// Endpoint is a host the installer sent requests to.
+type Endpoint struct {
+	// Host is the host and port of the endpoint.
+	Host string
+	// Proxy is the URL of the proxy the requests went through, without
+	// its credentials, or Direct.
+	Proxy string
+}
+
+var (
+	mu        sync.Mutex
+	endpoints = map[Endpoint]bool{}
+)
+
+// Configure routes the requests of the default HTTP transport through the
+// proxies of the config, or of the proxy environment variables if the config
+// is nil, and records the endpoints they reach if record is set.
+func Configure(config *Config, record bool) error {
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return errors.Errorf("the default HTTP transport is a %T", http.DefaultTransport)
+	}
+	proxy := http.ProxyFromEnvironment
+	if config != nil {
+		var err error
+		if proxy, err = config.proxyFunc(); err != nil {
+			return err
+		}
+		config.setEnvironment()
+	}
+	if record {
+		next := proxy
+		proxy = func(req *http.Request) (*url.URL, error) {
+			u, err := next(req)
+			if err == nil {
+				recordEndpoint(req.URL, u)
+			}
+			return u, err
+		}
+	}
+	transport.Proxy = proxy
+	return nil
+}
+
+// Endpoints returns the endpoints recorded, sorted by host.
+func Endpoints() []Endpoint {
+	mu.Lock()
+	defer mu.Unlock()
+	list := make([]Endpoint, 0, len(endpoints))
+	for endpoint := range endpoints {
+		list = append(list, endpoint)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Host != list[j].Host {
+			return list[i].Host < list[j].Host
+		}
+		return list[i].Proxy < list[j].Proxy
+	})
+	return list
+}
+
+func recordEndpoint(target *url.URL, proxy *url.URL) {
+	host := target.Host
+	if target.Port() == "" {
+		port := "80"
+		if target.Scheme == "https" {
+			port = "443"
+		}
+		host = net.JoinHostPort(target.Hostname(), port)
+	}
+	via := Direct
+	if proxy != nil {
+		via = proxy.Redacted()
+	}
+	mu.Lock()
+	endpoints[Endpoint{Host: host, Proxy: via}] = true
+	mu.Unlock()
+}
+
+// proxyFunc returns the proxy function of the config.
+func (c *Config) proxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	if err := validateProxy(c.HTTPProxy); err != nil {
+		return nil, errors.Wrap(err, "invalid HTTP proxy")
+	}
+	if err := validateProxy(c.HTTPSProxy); err != nil {
+		return nil, errors.Wrap(err, "invalid HTTPS proxy")
+	}
+	proxyFunc := (&httpproxy.Config{
+		HTTPProxy:  c.HTTPProxy,
+		HTTPSProxy: c.HTTPSProxy,
+		NoProxy:    c.NoProxy,
+	}).ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}, nil
+}
+
+// setEnvironment sets the proxy environment variables of the config.
+func (c *Config) setEnvironment() {
+	for name, value := range map[string]string{
+		"HTTP_PROXY":  c.HTTPProxy,
+		"HTTPS_PROXY": c.HTTPSProxy,
+		"NO_PROXY":    c.NoProxy,
+	} {
+		for _, name := range []string{name, strings.ToLower(name)} {
+			if value == "" {
+				os.Unsetenv(name)
+			} else {
+				os.Setenv(name, value)
+			}
+		}
+	}
+}
+
+// validateProxy returns an error if the proxy is not empty and not the URL of
+// a proxy.
+func validateProxy(proxy string) error {
+	if proxy == "" {
+		return nil
+	}
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return err
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return errors.Errorf("%q must be an http, https or socks5 URL", u.Redacted())
+	}
+	if u.Host == "" {
+		return errors.Errorf("%q has no host", u.Redacted())
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+// Package manifestdiff compares the manifests the installer generates with
+// the manifests in an asset directory.
+package manifestdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// Status is how a manifest in the asset directory differs from the
+// generated one.
+type Status string
+
+const (
+	// Modified is a manifest whose contents differ.
+	Modified Status = "modified"
+	// Missing is a generated manifest that is not in the asset directory.
+	Missing Status = "missing from the asset directory"
+	// Extra is a manifest in the asset directory that is not generated.
+	Extra Status = "only in the asset directory"
+)
+
+// FileDiff is the difference between the generated and the on-disk
+// versions of a manifest.
+type FileDiff struct {
+	Filename string
+	Status   Status
+	// Changes lists the fields of a modified manifest that differ. It is
+	// empty when either version could not be parsed as YAML.
+	Changes []Change
+}
+
+// Change is a field whose value differs. A nil value means that the field
+// is not set in that version of the manifest.
+type Change struct {
+	Path      string
+	Generated *string
+	OnDisk    *string
+}
+
+// documentSeparator splits multi-document YAML files.
+var documentSeparator = regexp.MustCompile(`(?m)^---[ \t]*$`)
+
+// Compare returns the differences between the generated manifests and the
+// manifests on disk, both keyed by filename, sorted by filename. Manifests
+// whose YAML is equivalent are not reported, even if they are formatted
+// differently.
+func Compare(generated, onDisk map[string][]byte) []FileDiff {
+	var diffs []FileDiff
+	for name, gen := range generated {
+		disk, ok := onDisk[name]
+		if !ok {
+			diffs = append(diffs, FileDiff{Filename: name, Status: Missing})
+			continue
+		}
+		if changes, equal := compareFile(gen, disk); !equal {
+			diffs = append(diffs, FileDiff{Filename: name, Status: Modified, Changes: changes})
+		}
+	}
+	for name := range onDisk {
+		if _, ok := generated[name]; !ok {
+			diffs = append(diffs, FileDiff{Filename: name, Status: Extra})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Filename < diffs[j].Filename })
+	return diffs
+}
+
+// compareFile returns the changed fields of the two versions of a file and
+// whether they are equivalent.
+func compareFile(generated, onDisk []byte) ([]Change, bool) {
+	genFields, genErr := flattenFile(generated)
+	diskFields, diskErr := flattenFile(onDisk)
+	if genErr != nil || diskErr != nil {
+		return nil, string(generated) == string(onDisk)
+	}
+	if reflect.DeepEqual(genFields, diskFields) {
+		return nil, true
+	}
+
+	var changes []Change
+	for path, gen := range genFields {
+		gen := gen
+		disk, ok := diskFields[path]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Path: path, Generated: &gen})
+		case gen != disk:
+			disk := disk
+			changes = append(changes, Change{Path: path, Generated: &gen, OnDisk: &disk})
+		}
+	}
+	for path, disk := range diskFields {
+		disk := disk
+		if _, ok := genFields[path]; !ok {
+			changes = append(changes, Change{Path: path, OnDisk: &disk})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, false
+}
+
+// flattenFile maps the path of every leaf field of the YAML documents in
+// data to its JSON-encoded value. The paths of all but the first document
+// are prefixed with the index of the document.
+func flattenFile(data []byte) (map[string]string, error) {
+	fields := map[string]string{}
+	index := 0
+	for _, doc := range documentSeparator.Split(string(data), -1) {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		var value interface{}
+		if err := yaml.Unmarshal([]byte(doc), &value); err != nil {
+			return nil, err
+		}
+		prefix := ""
+		if index > 0 {
+			prefix = fmt.Sprintf("(document %d)", index)
+		}
+		if err := flatten(prefix, value, fields); err != nil {
+			return nil, err
+		}
+		index++
+	}
+	return fields, nil
+}
+
+func flatten(path string, value interface{}, fields map[string]string) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) > 0 {
+			for key, child := range v {
+				if err := flatten(joinPath(path, key), child, fields); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case []interface{}:
+		if len(v) > 0 {
+			for i, child := range v {
+				if err := flatten(fmt.Sprintf("%s[%d]", path, i), child, fields); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	fields[path] = string(raw)
+	return nil
+}
+
+// joinPath appends key to path, quoting keys that contain dots.
+func joinPath(path, key string) string {
+	if strings.ContainsAny(key, ".[]") {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,67 @@
+package manifestdiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func value(v string) *string {
+	return &v
+}
+
+func TestCompare(t *testing.T) {
+	generated := map[string][]byte{
+		"manifests/same.yaml":        []byte("kind: ConfigMap\ndata:\n  a: b\n"),
+		"manifests/reformatted.yaml": []byte("kind: ConfigMap\ndata: {a: b}\n"),
+		"manifests/modified.yaml": []byte(`kind: ConfigMap
+metadata:
+  name: config
+  labels:
+    app.kubernetes.io/name: installer
+data:
+  a: b
+  c: d
+items:
+- x
+`),
+		"manifests/multi.yaml":   []byte("kind: A\n---\nkind: B\n"),
+		"manifests/missing.yaml": []byte("kind: ConfigMap\n"),
+		"manifests/invalid.yaml": []byte("kind: [\n"),
+	}
+	onDisk := map[string][]byte{
+		"manifests/same.yaml":        []byte("kind: ConfigMap\ndata:\n  a: b\n"),
+		"manifests/reformatted.yaml": []byte("data:\n  a: b\nkind: ConfigMap\n"),
+		"manifests/modified.yaml": []byte(`kind: ConfigMap
+metadata:
+  name: config
+  labels:
+    app.kubernetes.io/name: custom
+data:
+  a: b
+  e: f
+items:
+- x
+- z
+`),
+		"manifests/multi.yaml":   []byte("kind: A\n---\nkind: C\n"),
+		"manifests/invalid.yaml": []byte("kind: ]\n"),
+		"openshift/extra.yaml":   []byte("kind: ConfigMap\n"),
+	}
+
+	expected := []FileDiff{
+		{Filename: "manifests/invalid.yaml", Status: Modified},
+		{Filename: "manifests/missing.yaml", Status: Missing},
+		{Filename: "manifests/modified.yaml", Status: Modified, Changes: []Change{
+			{Path: "data.c", Generated: value(`"d"`)},
+			{Path: "data.e", OnDisk: value(`"f"`)},
+			{Path: "items[1]", OnDisk: value(`"z"`)},
+			{Path: `metadata.labels["app.kubernetes.io/name"]`, Generated: value(`"installer"`), OnDisk: value(`"custom"`)},
+		}},
+		{Filename: "manifests/multi.yaml", Status: Modified, Changes: []Change{
+			{Path: "(document 1).kind", Generated: value(`"B"`), OnDisk: value(`"C"`)},
+		}},
+		{Filename: "openshift/extra.yaml", Status: Extra},
+	}
+	assert.Equal(t, expected, Compare(generated, onDisk))
+}
@@ -0,0 +1,399 @@
+// Package manifestlint checks the manifests and openshift directories of an
+// assets directory, e.g. after they were modified by hand, for the mistakes
+// which would otherwise only show once the cluster is installing: documents
+// which do not match the schema of their kind, unknown fields, references to
+// secrets and machine config pools which do not exist, and files the
+// installer ignores.
+package manifestlint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	operatorv1alpha1 "github.com/openshift/api/operator/v1alpha1"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Dirs are the directories of the assets directory with the manifests of the
+// cluster.
+var Dirs = []string{"manifests", "openshift"}
+
+const (
+	machineConfigRoleLabel = "machineconfiguration.openshift.io/role"
+	nodeRoleLabelPrefix    = "node-role.kubernetes.io/"
+)
+
+// Severity is the severity of a problem.
+type Severity string
+
+const (
+	// Error is a problem which breaks the install or the cluster.
+	Error Severity = "error"
+	// Warning is a problem which is likely a mistake.
+	Warning Severity = "warning"
+)
+
+// Problem is a problem of a manifest.
+type Problem struct {
+	// Filename is the path of the file, relative to the assets directory.
+	Filename string `json:"filename"`
+	// Document is the index of the document in the file.
+	Document int `json:"document"`
+	// Field is the path of the field with the problem, if any.
+	Field    string   `json:"field,omitempty"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+func (p Problem) String() string {
+	location := fmt.Sprintf("%s[%d]", p.Filename, p.Document)
+	if p.Field != "" {
+		location += " " + p.Field
+	}
+	return fmt.Sprintf("%s: %s: %s", location, p.Severity, p.Message)
+}
+
+// apiServer is the APIServer config with the KMS of the encryption, which
+// the vendored API does not have yet. The KMS is not checked.
+type apiServer struct {
+	configv1.APIServer `json:",inline"`
+
+	Spec struct {
+		configv1.APIServerSpec `json:",inline"`
+
+		Encryption struct {
+			configv1.APIServerEncryption `json:",inline"`
+
+			KMS json.RawMessage `json:"kms,omitempty"`
+		} `json:"encryption"`
+	} `json:"spec"`
+}
+
+// schemas are the types of the kinds whose schema is checked.
+var schemas = map[schema.GroupKind]func() interface{}{
+	{Kind: "ConfigMap"}: func() interface{} { return &corev1.ConfigMap{} },
+	{Kind: "Namespace"}: func() interface{} { return &corev1.Namespace{} },
+	{Kind: "Secret"}:    func() interface{} { return &corev1.Secret{} },
+
+	{Group: configv1.GroupName, Kind: "APIServer"}:            func() interface{} { return &apiServer{} },
+	{Group: configv1.GroupName, Kind: "DNS"}:                  func() interface{} { return &configv1.DNS{} },
+	{Group: configv1.GroupName, Kind: "FeatureGate"}:          func() interface{} { return &configv1.FeatureGate{} },
+	{Group: configv1.GroupName, Kind: "Image"}:                func() interface{} { return &configv1.Image{} },
+	{Group: configv1.GroupName, Kind: "ImageDigestMirrorSet"}: func() interface{} { return &configv1.ImageDigestMirrorSet{} },
+	{Group: configv1.GroupName, Kind: "Infrastructure"}:       func() interface{} { return &configv1.Infrastructure{} },
+	{Group: configv1.GroupName, Kind: "Ingress"}:              func() interface{} { return &configv1.Ingress{} },
+	{Group: configv1.GroupName, Kind: "Network"}:              func() interface{} { return &configv1.Network{} },
+	{Group: configv1.GroupName, Kind: "OAuth"}:                func() interface{} { return &configv1.OAuth{} },
+	{Group: configv1.GroupName, Kind: "Proxy"}:                func() interface{} { return &configv1.Proxy{} },
+	{Group: configv1.GroupName, Kind: "Scheduler"}:            func() interface{} { return &configv1.Scheduler{} },
+
+	{Group: machinev1beta1.GroupName, Kind: "Machine"}:           func() interface{} { return &machinev1beta1.Machine{} },
+	{Group: machinev1beta1.GroupName, Kind: "MachineSet"}:        func() interface{} { return &machinev1beta1.MachineSet{} },
+	{Group: machinev1.GroupName, Kind: "ControlPlaneMachineSet"}: func() interface{} { return &machinev1.ControlPlaneMachineSet{} },
+	{Group: mcfgv1.GroupName, Kind: "ContainerRuntimeConfig"}:    func() interface{} { return &mcfgv1.ContainerRuntimeConfig{} },
+	{Group: mcfgv1.GroupName, Kind: "KubeletConfig"}:             func() interface{} { return &mcfgv1.KubeletConfig{} },
+	{Group: mcfgv1.GroupName, Kind: "MachineConfig"}:             func() interface{} { return &mcfgv1.MachineConfig{} },
+	{Group: mcfgv1.GroupName, Kind: "MachineConfigPool"}:         func() interface{} { return &mcfgv1.MachineConfigPool{} },
+	{Group: operatorv1.GroupName, Kind: "IngressController"}:     func() interface{} { return &operatorv1.IngressController{} },
+	{Group: operatorv1alpha1.GroupName, Kind: "ImageContentSourcePolicy"}: func() interface{} {
+		return &operatorv1alpha1.ImageContentSourcePolicy{}
+	},
+}
+
+// clusterScoped are the kinds, other than those of config.openshift.io,
+// which have no namespace.
+var clusterScoped = map[schema.GroupKind]bool{
+	{Kind: "Namespace"}: true,
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}:     true,
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}:             true,
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}:      true,
+	{Group: "storage.k8s.io", Kind: "StorageClass"}:                       true,
+	{Group: mcfgv1.GroupName, Kind: "ContainerRuntimeConfig"}:             true,
+	{Group: mcfgv1.GroupName, Kind: "KubeletConfig"}:                      true,
+	{Group: mcfgv1.GroupName, Kind: "MachineConfig"}:                      true,
+	{Group: mcfgv1.GroupName, Kind: "MachineConfigPool"}:                  true,
+	{Group: operatorv1alpha1.GroupName, Kind: "ImageContentSourcePolicy"}: true,
+}
+
+// namespaced are the kinds which must have a namespace.
+var namespaced = map[schema.GroupKind]bool{
+	{Kind: "ConfigMap"}: true,
+	{Kind: "Secret"}:    true,
+	{Group: machinev1beta1.GroupName, Kind: "Machine"}:           true,
+	{Group: machinev1beta1.GroupName, Kind: "MachineSet"}:        true,
+	{Group: machinev1.GroupName, Kind: "ControlPlaneMachineSet"}: true,
+	{Group: operatorv1.GroupName, Kind: "IngressController"}:     true,
+}
+
+// document is a document of a manifest.
+type document struct {
+	filename string
+	index    int
+	raw      []byte
+	object   *unstructured.Unstructured
+}
+
+func (d *document) problem(severity Severity, field, format string, args ...interface{}) Problem {
+	return Problem{Filename: d.filename, Document: d.index, Field: field, Severity: severity, Message: fmt.Sprintf(format, args...)}
+}
+
+// LintDir lints the manifests of the assets directory.
+func LintDir(dir string) ([]Problem, error) {
+	files := map[string][]byte{}
+	for _, manifestDir := range Dirs {
+		err := filepath.Walk(filepath.Join(dir, manifestDir), func(p string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			name, err := filepath.Rel(dir, p)
+			if err != nil {
+				return err
+			}
+			files[filepath.ToSlash(name)] = data
+			return nil
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return nil, errors.Wrapf(err, "failed to read %s", manifestDir)
+		}
+	}
+	if len(files) == 0 {
+		return nil, errors.Errorf("%s has no manifests, create them with openshift-install create manifests", dir)
+	}
+	return Lint(files), nil
+}
+
+// Lint lints the manifests, by their paths relative to the assets
+// directory. The problems are sorted by file.
+func Lint(files map[string][]byte) []Problem {
+	var problems []Problem
+	var documents []*document
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		file := &document{filename: name}
+		switch ext := path.Ext(name); {
+		case strings.Count(name, "/") > 1:
+			problems = append(problems, file.problem(Warning, "", "the installer ignores the files of subdirectories"))
+			continue
+		case ext != ".yaml" && ext != ".yml" && ext != ".json":
+			problems = append(problems, file.problem(Warning, "", "the installer ignores the files without a .yaml, .yml or .json extension"))
+			continue
+		}
+		parsed, err := parse(name, files[name])
+		if err != nil {
+			problems = append(problems, file.problem(Error, "", "%v", err))
+			continue
+		}
+		if len(parsed) == 0 {
+			problems = append(problems, file.problem(Warning, "", "the file has no manifest"))
+		}
+		documents = append(documents, parsed...)
+	}
+
+	for _, d := range documents {
+		problems = append(problems, lintDocument(d)...)
+	}
+	problems = append(problems, lintReferences(documents)...)
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Filename != problems[j].Filename {
+			return problems[i].Filename < problems[j].Filename
+		}
+		return problems[i].Document < problems[j].Document
+	})
+	return problems
+}
+
+// parse returns the documents of the file.
+func parse(name string, data []byte) ([]*document, error) {
+	decoder := yaml.NewYAMLToJSONDecoder(bytes.NewReader(data))
+	var documents []*document
+	for index := 0; ; index++ {
+		var raw json.RawMessage
+		err := decoder.Decode(&raw)
+		if err == io.EOF {
+			return documents, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "document %d is not valid YAML", index)
+		}
+		if len(raw) == 0 || string(raw) == "null" {
+			continue
+		}
+		object := map[string]interface{}{}
+		if err := json.Unmarshal(raw, &object); err != nil {
+			return nil, errors.Errorf("document %d is not an object", index)
+		}
+		documents = append(documents, &document{filename: name, index: index, raw: raw, object: &unstructured.Unstructured{Object: object}})
+	}
+}
+
+// lintDocument checks the document on its own.
+func lintDocument(d *document) []Problem {
+	var problems []Problem
+	gk := d.object.GroupVersionKind().GroupKind()
+	if d.object.GetAPIVersion() == "" {
+		problems = append(problems, d.problem(Error, "apiVersion", "the manifest must have an apiVersion"))
+	}
+	if gk.Kind == "" {
+		problems = append(problems, d.problem(Error, "kind", "the manifest must have a kind"))
+	}
+	if d.object.GetName() == "" {
+		problems = append(problems, d.problem(Error, "metadata.name", "the manifest must have a name"))
+	}
+
+	namespace := d.object.GetNamespace()
+	switch {
+	case namespace != "" && (clusterScoped[gk] || gk.Group == configv1.GroupName):
+		problems = append(problems, d.problem(Warning, "metadata.namespace", "%s is cluster-scoped, its namespace is ignored", gk.Kind))
+	case namespace == "" && namespaced[gk]:
+		problems = append(problems, d.problem(Warning, "metadata.namespace", "the %s has no namespace and is created in the default namespace", gk.Kind))
+	}
+
+	if newObject, ok := schemas[gk]; ok {
+		decoder := json.NewDecoder(bytes.NewReader(d.raw))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(newObject()); err != nil {
+			problems = append(problems, d.problem(Error, "", "the manifest does not match the schema of %s: %v", gk.Kind, err))
+		}
+	}
+
+	if gk == (schema.GroupKind{Group: mcfgv1.GroupName, Kind: "MachineConfig"}) {
+		version, found, _ := unstructured.NestedString(d.object.Object, "spec", "config", "ignition", "version")
+		if _, hasConfig, _ := unstructured.NestedFieldNoCopy(d.object.Object, "spec", "config"); hasConfig {
+			switch {
+			case !found || version == "":
+				problems = append(problems, d.problem(Error, "spec.config.ignition.version", "the Ignition config must have a version"))
+			case !strings.HasPrefix(version, "3."):
+				problems = append(problems, d.problem(Error, "spec.config.ignition.version", "the Ignition config version %s is not supported, use a 3.x version", version))
+			}
+		}
+	}
+
+	if gk == (schema.GroupKind{Group: machinev1beta1.GroupName, Kind: "MachineSet"}) {
+		selector, _, _ := unstructured.NestedStringMap(d.object.Object, "spec", "selector", "matchLabels")
+		labels, _, _ := unstructured.NestedStringMap(d.object.Object, "spec", "template", "metadata", "labels")
+		for key, value := range selector {
+			if labels[key] != value {
+				problems = append(problems, d.problem(Error, "spec.template.metadata.labels", "the labels of the machines must match the selector %s=%s", key, value))
+			}
+		}
+	}
+	return problems
+}
+
+// lintReferences checks the references between the documents: the objects
+// they reference must be among them, and the objects must not be repeated.
+func lintReferences(documents []*document) []Problem {
+	var problems []Problem
+
+	seen := map[string]*document{}
+	secrets := map[string]bool{}
+	// the roles of the machine configs which the pools select, and the node
+	// labels the pools select nodes with
+	roles := map[string]bool{"master": true, "worker": true}
+	poolNodeLabels := map[string]*document{}
+	nodeLabels := map[string]bool{}
+	for _, d := range documents {
+		gk := d.object.GroupVersionKind().GroupKind()
+		key := fmt.Sprintf("%s/%s/%s", gk, d.object.GetNamespace(), d.object.GetName())
+		if first, ok := seen[key]; ok && d.object.GetName() != "" {
+			problems = append(problems, d.problem(Error, "metadata.name", "the %s %s is also defined in %s[%d]", gk.Kind, d.object.GetName(), first.filename, first.index))
+		}
+		seen[key] = d
+
+		switch gk {
+		case schema.GroupKind{Kind: "Secret"}:
+			secrets[d.object.GetNamespace()+"/"+d.object.GetName()] = true
+		case schema.GroupKind{Group: mcfgv1.GroupName, Kind: "MachineConfigPool"}:
+			if role, ok, _ := unstructured.NestedString(d.object.Object, "spec", "machineConfigSelector", "matchLabels", machineConfigRoleLabel); ok {
+				roles[role] = true
+			}
+			expressions, _, _ := unstructured.NestedSlice(d.object.Object, "spec", "machineConfigSelector", "matchExpressions")
+			for _, e := range expressions {
+				expression, _ := e.(map[string]interface{})
+				if expression["key"] != machineConfigRoleLabel || expression["operator"] != "In" {
+					continue
+				}
+				values, _, _ := unstructured.NestedStringSlice(expression, "values")
+				for _, role := range values {
+					roles[role] = true
+				}
+			}
+			selector, _, _ := unstructured.NestedStringMap(d.object.Object, "spec", "nodeSelector", "matchLabels")
+			for label := range selector {
+				if strings.HasPrefix(label, nodeRoleLabelPrefix) {
+					poolNodeLabels[label] = d
+				}
+			}
+		case schema.GroupKind{Group: machinev1beta1.GroupName, Kind: "MachineSet"}:
+			labels, _, _ := unstructured.NestedStringMap(d.object.Object, "spec", "template", "spec", "metadata", "labels")
+			for label := range labels {
+				nodeLabels[label] = true
+			}
+		}
+	}
+
+	for _, d := range documents {
+		gk := d.object.GroupVersionKind().GroupKind()
+		var providerSpec []string
+		switch gk {
+		case schema.GroupKind{Group: machinev1beta1.GroupName, Kind: "Machine"}:
+			providerSpec = []string{"spec", "providerSpec", "value"}
+		case schema.GroupKind{Group: machinev1beta1.GroupName, Kind: "MachineSet"}:
+			providerSpec = []string{"spec", "template", "spec", "providerSpec", "value"}
+		case schema.GroupKind{Group: machinev1.GroupName, Kind: "ControlPlaneMachineSet"}:
+			providerSpec = []string{"spec", "template", "machines_v1beta1_machine_openshift_io", "spec", "providerSpec", "value"}
+		case schema.GroupKind{Group: mcfgv1.GroupName, Kind: "MachineConfig"}:
+			role := d.object.GetLabels()[machineConfigRoleLabel]
+			switch {
+			case role == "":
+				problems = append(problems, d.problem(Error, "metadata.labels", "the machine config pool of the MachineConfig must be set with the %s label", machineConfigRoleLabel))
+			case !roles[role]:
+				problems = append(problems, d.problem(Error, "metadata.labels", "no MachineConfigPool selects the machine configs of the %s role", role))
+			}
+		}
+		if providerSpec != nil {
+			fields := append(append([]string{}, providerSpec...), "userDataSecret", "name")
+			name, ok, _ := unstructured.NestedString(d.object.Object, fields...)
+			namespace := d.object.GetNamespace()
+			if ok && name != "" && !secrets[namespace+"/"+name] {
+				problems = append(problems, d.problem(Error, strings.Join(fields, "."), "the user data secret %s/%s is not among the manifests", namespace, name))
+			}
+		}
+	}
+
+	labels := make([]string, 0, len(poolNodeLabels))
+	for label := range poolNodeLabels {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	for _, label := range labels {
+		if d := poolNodeLabels[label]; !nodeLabels[label] {
+			problems = append(problems, d.problem(Warning, "spec.nodeSelector.matchLabels", "no MachineSet labels its nodes with %s, the pool has no nodes until they are labeled", label))
+		}
+	}
+	return problems
+}
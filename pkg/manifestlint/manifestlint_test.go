@@ -0,0 +1,202 @@
+package manifestlint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	userDataSecret = `apiVersion: v1
+kind: Secret
+metadata:
+  name: worker-user-data
+  namespace: openshift-machine-api
+data:
+  userData: e30=
+`
+
+	machineSet = `apiVersion: machine.openshift.io/v1beta1
+kind: MachineSet
+metadata:
+  name: infra-a
+  namespace: openshift-machine-api
+spec:
+  selector:
+    matchLabels:
+      machine.openshift.io/cluster-api-machineset: infra-a
+  template:
+    metadata:
+      labels:
+        machine.openshift.io/cluster-api-machineset: infra-a
+    spec:
+      metadata:
+        labels:
+          node-role.kubernetes.io/infra: ""
+      providerSpec:
+        value:
+          userDataSecret:
+            name: worker-user-data
+`
+
+	infraPool = `apiVersion: machineconfiguration.openshift.io/v1
+kind: MachineConfigPool
+metadata:
+  name: infra
+spec:
+  machineConfigSelector:
+    matchExpressions:
+    - key: machineconfiguration.openshift.io/role
+      operator: In
+      values: [worker, infra]
+  nodeSelector:
+    matchLabels:
+      node-role.kubernetes.io/infra: ""
+`
+
+	infraMachineConfig = `apiVersion: machineconfiguration.openshift.io/v1
+kind: MachineConfig
+metadata:
+  name: 99-infra-chrony
+  labels:
+    machineconfiguration.openshift.io/role: infra
+spec:
+  config:
+    ignition:
+      version: 3.2.0
+`
+)
+
+func TestLint(t *testing.T) {
+	cases := []struct {
+		name   string
+		files  map[string][]byte
+		expect []string
+	}{
+		{
+			name: "valid",
+			files: map[string][]byte{
+				"openshift/99_user-data.yaml":    []byte(userDataSecret),
+				"openshift/99_infra-a.yaml":      []byte(machineSet),
+				"openshift/99_infra-pool.yaml":   []byte(infraPool),
+				"openshift/99_infra-chrony.yaml": []byte(infraMachineConfig),
+			},
+		},
+		{
+			name: "ignored files",
+			files: map[string][]byte{
+				"manifests/README.md":           []byte("notes"),
+				"openshift/extra/99_extra.yaml": []byte(userDataSecret),
+				"openshift/99_empty.yaml":       []byte("---\n"),
+			},
+			expect: []string{
+				"manifests/README.md[0]: warning: the installer ignores the files without a .yaml, .yml or .json extension",
+				"openshift/99_empty.yaml[0]: warning: the file has no manifest",
+				"openshift/extra/99_extra.yaml[0]: warning: the installer ignores the files of subdirectories",
+			},
+		},
+		{
+			name: "invalid documents",
+			files: map[string][]byte{
+				"manifests/invalid.yaml": []byte("kind: [\n"),
+				"manifests/list.yaml":    []byte("- a\n"),
+				"manifests/object.yaml":  []byte("metadata:\n  namespace: test\n"),
+			},
+			expect: []string{
+				"manifests/invalid.yaml[0]: error: document 0 is not valid YAML: error converting YAML to JSON: yaml: line 1: did not find expected node content",
+				"manifests/list.yaml[0]: error: document 0 is not an object",
+				"manifests/object.yaml[0] apiVersion: error: the manifest must have an apiVersion",
+				"manifests/object.yaml[0] kind: error: the manifest must have a kind",
+				"manifests/object.yaml[0] metadata.name: error: the manifest must have a name",
+			},
+		},
+		{
+			name: "unknown field",
+			files: map[string][]byte{
+				"openshift/99_user-data.yaml": []byte(userDataSecret + "strinData:\n  key: value\n"),
+			},
+			expect: []string{
+				`openshift/99_user-data.yaml[0]: error: the manifest does not match the schema of Secret: json: unknown field "strinData"`,
+			},
+		},
+		{
+			name: "namespaces",
+			files: map[string][]byte{
+				"manifests/cm.yaml": []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n---\napiVersion: config.openshift.io/v1\nkind: Proxy\nmetadata:\n  name: cluster\n  namespace: openshift-config\n"),
+			},
+			expect: []string{
+				"manifests/cm.yaml[0] metadata.namespace: warning: the ConfigMap has no namespace and is created in the default namespace",
+				"manifests/cm.yaml[1] metadata.namespace: warning: Proxy is cluster-scoped, its namespace is ignored",
+			},
+		},
+		{
+			name: "references",
+			files: map[string][]byte{
+				"openshift/99_infra-a.yaml":      []byte(machineSet),
+				"openshift/99_infra-a-copy.yaml": []byte(machineSet),
+				"openshift/99_infra-chrony.yaml": []byte(infraMachineConfig),
+				"openshift/99_mc.yaml":           []byte("apiVersion: machineconfiguration.openshift.io/v1\nkind: MachineConfig\nmetadata:\n  name: 99-mc\nspec:\n  config:\n    ignition:\n      version: 2.2.0\n"),
+			},
+			expect: []string{
+				"openshift/99_infra-a-copy.yaml[0] spec.template.spec.providerSpec.value.userDataSecret.name: error: the user data secret openshift-machine-api/worker-user-data is not among the manifests",
+				"openshift/99_infra-a.yaml[0] metadata.name: error: the MachineSet infra-a is also defined in openshift/99_infra-a-copy.yaml[0]",
+				"openshift/99_infra-a.yaml[0] spec.template.spec.providerSpec.value.userDataSecret.name: error: the user data secret openshift-machine-api/worker-user-data is not among the manifests",
+				"openshift/99_infra-chrony.yaml[0] metadata.labels: error: no MachineConfigPool selects the machine configs of the infra role",
+				"openshift/99_mc.yaml[0] spec.config.ignition.version: error: the Ignition config version 2.2.0 is not supported, use a 3.x version",
+				"openshift/99_mc.yaml[0] metadata.labels: error: the machine config pool of the MachineConfig must be set with the machineconfiguration.openshift.io/role label",
+			},
+		},
+		{
+			name: "pool without nodes and selector mismatch",
+			files: map[string][]byte{
+				"openshift/99_infra-pool.yaml": []byte(infraPool),
+				"openshift/99_user-data.yaml":  []byte(userDataSecret),
+				"openshift/99_infra-b.yaml": []byte(`apiVersion: machine.openshift.io/v1beta1
+kind: MachineSet
+metadata:
+  name: infra-b
+  namespace: openshift-machine-api
+spec:
+  selector:
+    matchLabels:
+      machine.openshift.io/cluster-api-machineset: infra-b
+  template:
+    metadata:
+      labels:
+        machine.openshift.io/cluster-api-machineset: infra-a
+`),
+			},
+			expect: []string{
+				"openshift/99_infra-b.yaml[0] spec.template.metadata.labels: error: the labels of the machines must match the selector machine.openshift.io/cluster-api-machineset=infra-b",
+				"openshift/99_infra-pool.yaml[0] spec.nodeSelector.matchLabels: warning: no MachineSet labels its nodes with node-role.kubernetes.io/infra, the pool has no nodes until they are labeled",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var problems []string
+			for _, p := range Lint(tc.files) {
+				problems = append(problems, p.String())
+			}
+			assert.Equal(t, tc.expect, problems)
+		})
+	}
+}
+
+func TestLintDir(t *testing.T) {
+	dir := t.TempDir()
+	_, err := LintDir(dir)
+	assert.EqualError(t, err, dir+" has no manifests, create them with openshift-install create manifests")
+
+	if !assert.NoError(t, os.MkdirAll(filepath.Join(dir, "openshift"), 0o750)) {
+		return
+	}
+	if !assert.NoError(t, os.WriteFile(filepath.Join(dir, "openshift", "99_user-data.yaml"), []byte(userDataSecret), 0o640)) {
+		return
+	}
+	problems, err := LintDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+}
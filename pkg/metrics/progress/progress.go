@@ -0,0 +1,132 @@
+// Package progress reports the progress of the installer as a stream of
+// JSON events, one per line, for CI systems and user interfaces.
+package progress
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// EventType is the type of a progress event.
+type EventType string
+
+const (
+	// PhaseStartedEvent is sent when a phase of the install starts.
+	PhaseStartedEvent EventType = "phaseStarted"
+	// PhaseCompletedEvent is sent when a phase of the install completes.
+	PhaseCompletedEvent EventType = "phaseCompleted"
+	// ProgressEvent reports how far a phase has progressed.
+	ProgressEvent EventType = "progress"
+	// ResourceCreatedEvent is sent when an infrastructure resource is
+	// created.
+	ResourceCreatedEvent EventType = "resourceCreated"
+	// StatusEvent reports what a phase is doing, e.g. the stages of the
+	// bootstrap machine.
+	StatusEvent EventType = "status"
+)
+
+// Event is a progress event.
+type Event struct {
+	Time  time.Time `json:"time"`
+	Type  EventType `json:"type"`
+	Phase string    `json:"phase,omitempty"`
+	// DurationSeconds is the duration of a completed phase, or how long
+	// creating a resource took.
+	DurationSeconds float64 `json:"durationSeconds,omitempty"`
+	// Percent is the completion of a phase, from 0 to 100.
+	Percent *int `json:"percent,omitempty"`
+	// Resource is the address of a created resource.
+	Resource string `json:"resource,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+var (
+	mu   sync.Mutex
+	sink io.WriteCloser
+)
+
+// Open starts writing events to target, which is either the path of a
+// file, which events are appended to, or unix:<path> for a unix socket
+// that is listened on by the consumer. It returns a function that stops
+// writing events.
+func Open(target string) (func(), error) {
+	var w io.WriteCloser
+	var err error
+	if path := strings.TrimPrefix(target, "unix:"); path != target {
+		w, err = net.Dial("unix", path)
+	} else {
+		w, err = os.OpenFile(target, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open progress events destination %q", target)
+	}
+	return OpenWriter(w), nil
+}
+
+// OpenWriter starts writing events to w, one JSON event per Write. It
+// returns a function that stops writing events and closes w.
+func OpenWriter(w io.WriteCloser) func() {
+	mu.Lock()
+	sink = w
+	mu.Unlock()
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if sink == w {
+			sink = nil
+		}
+		w.Close()
+	}
+}
+
+// PhaseStarted reports that the named phase started.
+func PhaseStarted(phase string) {
+	send(Event{Type: PhaseStartedEvent, Phase: phase})
+}
+
+// PhaseCompleted reports that the named phase completed after duration.
+func PhaseCompleted(phase string, duration time.Duration) {
+	send(Event{Type: PhaseCompletedEvent, Phase: phase, DurationSeconds: duration.Seconds()})
+}
+
+// Progress reports the completion of the named phase.
+func Progress(phase string, percent int, message string) {
+	send(Event{Type: ProgressEvent, Phase: phase, Percent: &percent, Message: message})
+}
+
+// Status reports what the named phase is doing.
+func Status(phase string, message string) {
+	send(Event{Type: StatusEvent, Phase: phase, Message: message})
+}
+
+// ResourceCreated reports that the resource at the given address was
+// created, which took duration.
+func ResourceCreated(resource string, duration time.Duration) {
+	send(Event{Type: ResourceCreatedEvent, Resource: resource, DurationSeconds: duration.Seconds()})
+}
+
+func send(event Event) {
+	mu.Lock()
+	defer mu.Unlock()
+	if sink == nil {
+		return
+	}
+	event.Time = time.Now().UTC()
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	// Progress events must never fail the install, so a consumer that
+	// went away only stops the stream.
+	if _, err := sink.Write(append(data, '\n')); err != nil {
+		sink.Close()
+		sink = nil
+	}
+}
@@ -0,0 +1,86 @@
+package progress
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readEvents(t *testing.T, path string) []Event {
+	t.Helper()
+	f, err := os.Open(path)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		assert.False(t, e.Time.IsZero(), "events should have a time")
+		e.Time = time.Time{}
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.json")
+
+	// events without a destination are dropped
+	PhaseStarted("dropped")
+
+	closeFn, err := Open(path)
+	assert.NoError(t, err)
+	PhaseStarted("Infrastructure")
+	ResourceCreated("aws_vpc.new_vpc[0]", 3*time.Second)
+	Progress("Cluster Operators", 42, "Working towards 4.12.0: 42% complete")
+	Status("Bootstrap Complete", "Pulled the release image")
+	PhaseCompleted("Infrastructure", 90*time.Second)
+	closeFn()
+	PhaseStarted("dropped")
+
+	percent := 42
+	assert.Equal(t, []Event{
+		{Type: PhaseStartedEvent, Phase: "Infrastructure"},
+		{Type: ResourceCreatedEvent, Resource: "aws_vpc.new_vpc[0]", DurationSeconds: 3},
+		{Type: ProgressEvent, Phase: "Cluster Operators", Percent: &percent, Message: "Working towards 4.12.0: 42% complete"},
+		{Type: StatusEvent, Phase: "Bootstrap Complete", Message: "Pulled the release image"},
+		{Type: PhaseCompletedEvent, Phase: "Infrastructure", DurationSeconds: 90},
+	}, readEvents(t, path))
+}
+
+func TestUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.sock")
+	listener, err := net.Listen("unix", path)
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan string)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			close(received)
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		received <- line
+	}()
+
+	closeFn, err := Open("unix:" + path)
+	assert.NoError(t, err)
+	defer closeFn()
+	PhaseStarted("Bootstrap Complete")
+
+	var e Event
+	assert.NoError(t, json.Unmarshal([]byte(<-received), &e))
+	assert.Equal(t, PhaseStartedEvent, e.Type)
+	assert.Equal(t, "Bootstrap Complete", e.Phase)
+}
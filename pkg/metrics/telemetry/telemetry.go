@@ -0,0 +1,143 @@
+// Package telemetry records anonymized timings of the runs of the installer,
+// when the user opts in. The records are appended to a file of the assets
+// directory and optionally sent to an endpoint of the organization, to find
+// systemic slowness across many installs.
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FileName is the name of the file of the assets directory the records are
+// appended to, one JSON record per line.
+const FileName = ".openshift_install_telemetry.jsonl"
+
+// Result is the result of a run of the installer.
+type Result string
+
+const (
+	// ResultSucceeded indicates that the run succeeded.
+	ResultSucceeded Result = "succeeded"
+	// ResultFailed indicates that the run failed.
+	ResultFailed Result = "failed"
+)
+
+// FailureCategory is the category of the failure of a run, which follows
+// the exit codes of the installer.
+type FailureCategory string
+
+const (
+	// FailureInstallConfig indicates an invalid install-config.
+	FailureInstallConfig FailureCategory = "install-config"
+	// FailureInfrastructure indicates that the infrastructure failed to
+	// provision.
+	FailureInfrastructure FailureCategory = "infrastructure"
+	// FailureBootstrap indicates that the bootstrap failed to complete.
+	FailureBootstrap FailureCategory = "bootstrap"
+	// FailureInstall indicates that the cluster failed to initialize.
+	FailureInstall FailureCategory = "install"
+	// FailureOther indicates any other failure.
+	FailureOther FailureCategory = "other"
+)
+
+// Phase is the duration of a completed phase of a run.
+type Phase struct {
+	Name            string  `json:"name"`
+	DurationSeconds float64 `json:"durationSeconds"`
+}
+
+// Record is the record of a run of the installer. It holds no identifiers
+// of the cluster, its infrastructure or the user.
+type Record struct {
+	Time time.Time `json:"time"`
+	// Command is the command of the run, e.g. "create cluster".
+	Command  string `json:"command"`
+	Version  string `json:"version"`
+	Platform string `json:"platform,omitempty"`
+	Result   Result `json:"result"`
+	// FailureCategory is the category of the failure of a failed run.
+	FailureCategory FailureCategory `json:"failureCategory,omitempty"`
+	// FailedPhase is the phase which was running when the run failed.
+	FailedPhase string  `json:"failedPhase,omitempty"`
+	Phases      []Phase `json:"phases"`
+}
+
+// Write appends the record to the telemetry file of the assets directory.
+func Write(dir string, record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the telemetry record")
+	}
+	f, err := os.OpenFile(filepath.Join(dir, FileName), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o640)
+	if err != nil {
+		return errors.Wrap(err, "failed to open the telemetry file")
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return errors.Wrap(err, "failed to write the telemetry record")
+	}
+	return nil
+}
+
+// Read returns the records of the telemetry file of the assets directory,
+// oldest first. It returns no records when the file does not exist.
+func Read(dir string) ([]Record, error) {
+	f, err := os.Open(filepath.Join(dir, FileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open the telemetry file")
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal line %d of the telemetry file", line)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read the telemetry file")
+	}
+	return records, nil
+}
+
+// Send POSTs the record as JSON to the endpoint.
+func Send(ctx context.Context, endpoint string, record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the telemetry record")
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return errors.Wrap(err, "failed to create the telemetry request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to send the telemetry record to %s", endpoint)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("failed to send the telemetry record to %s: %s", endpoint, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRecord(result Result) *Record {
+	return &Record{
+		Time:     time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC),
+		Command:  "create cluster",
+		Version:  "v4.13.0",
+		Platform: "aws",
+		Result:   result,
+		Phases: []Phase{
+			{Name: "Infrastructure", DurationSeconds: 420},
+			{Name: "Bootstrap Complete", DurationSeconds: 900},
+		},
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	dir := t.TempDir()
+
+	records, err := Read(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+
+	succeeded := testRecord(ResultSucceeded)
+	failed := testRecord(ResultFailed)
+	failed.FailureCategory = FailureBootstrap
+	failed.FailedPhase = "Bootstrap Complete"
+	for _, record := range []*Record{succeeded, failed} {
+		if !assert.NoError(t, Write(dir, record)) {
+			return
+		}
+	}
+
+	records, err = Read(dir)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []Record{*succeeded, *failed}, records)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	dir := t.TempDir()
+	if !assert.NoError(t, os.WriteFile(filepath.Join(dir, FileName), []byte("{}\nnot json\n"), 0o640)) {
+		return
+	}
+	_, err := Read(dir)
+	assert.EqualError(t, err, "failed to unmarshal line 2 of the telemetry file: invalid character 'o' in literal null (expecting 'u')")
+}
+
+func TestSend(t *testing.T) {
+	var received Record
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	record := testRecord(ResultSucceeded)
+	if assert.NoError(t, Send(context.Background(), server.URL, record)) {
+		assert.Equal(t, *record, received)
+	}
+}
+
+func TestSendRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := Send(context.Background(), server.URL, testRecord(ResultSucceeded))
+	assert.EqualError(t, err, "failed to send the telemetry record to "+server.URL+": 403 Forbidden")
+}
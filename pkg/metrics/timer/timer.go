@@ -2,9 +2,13 @@ package timer
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/metrics/progress"
+	"github.com/openshift/installer/pkg/tracing"
 )
 
 // Timer is the struct that keeps track of each of the sections.
@@ -20,23 +24,77 @@ const (
 	TotalTimeElapsed = "Total"
 )
 
-var timer = NewTimer()
+var (
+	// mu makes the package-level timer safe for concurrent use, as the
+	// stages of the infrastructure may be timed concurrently.
+	mu    sync.Mutex
+	timer = NewTimer()
+
+	// spans are the tracing spans of the running stages. The total time
+	// elapsed is the root span of the command, so it has no span of its own.
+	spans = map[string]*tracing.Span{}
+)
 
 // StartTimer initiailzes the timer object with the current timestamp information.
+// It also reports the start of the stage as a progress event and a tracing span.
 func StartTimer(key string) {
+	mu.Lock()
 	timer.StartTimer(key)
+	if key != TotalTimeElapsed {
+		_, spans[key] = tracing.Start(tracing.RootContext(), key)
+	}
+	mu.Unlock()
+	progress.PhaseStarted(key)
 }
 
 // StopTimer records the duration for the current stage sent as the key parameter and stores the information.
+// It also reports the completion of the stage as a progress event and ends its tracing span.
 func StopTimer(key string) {
+	mu.Lock()
 	timer.StopTimer(key)
+	duration := timer.stageTimes[key]
+	spans[key].End()
+	delete(spans, key)
+	mu.Unlock()
+	progress.PhaseCompleted(key, duration)
 }
 
 // LogSummary prints the summary of all the times collected so far into the INFO section.
 func LogSummary() {
+	mu.Lock()
+	defer mu.Unlock()
 	timer.LogSummary(logrus.StandardLogger())
 }
 
+// Stage is the duration of a completed stage.
+type Stage struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Started returns whether the stage started.
+func Started(key string) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	_, found := timer.startTimes[key]
+	return found
+}
+
+// CompletedStages returns the completed stages, in the order they started.
+func CompletedStages() []Stage {
+	mu.Lock()
+	defer mu.Unlock()
+	return timer.CompletedStages()
+}
+
+// RunningStage returns the last stage that started and did not complete, or
+// an empty string when all stages completed.
+func RunningStage() string {
+	mu.Lock()
+	defer mu.Unlock()
+	return timer.RunningStage()
+}
+
 // NewTimer returns a new timer that can be used to track sections and
 func NewTimer() Timer {
 	return Timer{
@@ -61,6 +119,30 @@ func (t *Timer) StopTimer(key string) time.Duration {
 	return time.Since(time.Now())
 }
 
+// CompletedStages returns the completed stages, in the order they started.
+func (t *Timer) CompletedStages() []Stage {
+	var stages []Stage
+	for _, item := range t.listOfStages {
+		if duration, ok := t.stageTimes[item]; ok {
+			stages = append(stages, Stage{Name: item, Duration: duration})
+		}
+	}
+	return stages
+}
+
+// RunningStage returns the last stage that started and did not complete, or
+// an empty string when all stages completed. The total time elapsed is not a
+// stage of its own.
+func (t *Timer) RunningStage() string {
+	for i := len(t.listOfStages) - 1; i >= 0; i-- {
+		item := t.listOfStages[i]
+		if _, ok := t.stageTimes[item]; !ok && item != TotalTimeElapsed {
+			return item
+		}
+	}
+	return ""
+}
+
 // LogSummary prints the summary of all the times collected so far into the INFO section.
 // The format of printing will be the following:
 // If there are no stages except the total time stage, then it only prints the following
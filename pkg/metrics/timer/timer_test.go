@@ -129,3 +129,24 @@ func TestNewTimer(t *testing.T) {
 		t.Fatalf("Expected empty list of startTimes property in the new timer created, got %d", len(timer.stageTimes))
 	}
 }
+
+func TestCompletedAndRunningStages(t *testing.T) {
+	timer := NewTimer()
+	timer.StartTimer(TotalTimeElapsed)
+	timer.StartTimer("testStage1")
+	timer.StartTimer("testStage2")
+	timer.StopTimer("testStage1")
+
+	stages := timer.CompletedStages()
+	if len(stages) != 1 || stages[0].Name != "testStage1" {
+		t.Fatalf("Expected testStage1 to be the only completed stage, got %v", stages)
+	}
+	if running := timer.RunningStage(); running != "testStage2" {
+		t.Fatalf("Expected testStage2 to be running, got %q", running)
+	}
+
+	timer.StopTimer("testStage2")
+	if running := timer.RunningStage(); running != "" {
+		t.Fatalf("Expected no stage to be running, got %q", running)
+	}
+}
@@ -0,0 +1,41 @@
+// Package offline disables the validations of the installer which need the
+// network, for airgapped pipelines which need the same behavior on every run.
+// Each validation skipped is reported, and the operations which cannot be
+// done without the network fail instead of trying to reach it.
+package offline
+
+import (
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+var enabled bool
+
+// SetEnabled enables or disables the offline mode.
+func SetEnabled(e bool) {
+	enabled = e
+}
+
+// Enabled returns whether the offline mode is enabled.
+func Enabled() bool {
+	return enabled
+}
+
+// Skip reports that the validation is skipped and returns true in the offline
+// mode, and returns false otherwise.
+func Skip(validation string) bool {
+	if !enabled {
+		return false
+	}
+	logrus.Infof("%s: skipped (offline)", validation)
+	return true
+}
+
+// Require returns an error in the offline mode, for the operations which
+// cannot be done without the network.
+func Require(operation string) error {
+	if !enabled {
+		return nil
+	}
+	return errors.Errorf("%s requires the network, which is disabled by --offline", operation)
+}
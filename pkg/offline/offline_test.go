@@ -0,0 +1,18 @@
+package offline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOffline(t *testing.T) {
+	defer SetEnabled(false)
+
+	assert.False(t, Skip("Connectivity checks"))
+	assert.NoError(t, Require("creating an AWS session"))
+
+	SetEnabled(true)
+	assert.True(t, Skip("Connectivity checks"))
+	assert.EqualError(t, Require("creating an AWS session"), "creating an AWS session requires the network, which is disabled by --offline")
+}
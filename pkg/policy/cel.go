@@ -0,0 +1,86 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	"github.com/pkg/errors"
+)
+
+// celVariable is the variable the CEL policies read the input from, e.g.
+// input.installConfig.platform.aws.region.
+const celVariable = "input"
+
+// celEngine evaluates CEL policies. Each policy is a file holding a single
+// expression, which evaluates either to the messages of its violations, a
+// list of strings, or to whether the install complies with it, a bool.
+type celEngine struct{}
+
+func init() {
+	Register(".cel", &celEngine{})
+}
+
+// Check returns nil, since the CEL policies are evaluated by the installer
+// itself.
+func (*celEngine) Check() error {
+	return nil
+}
+
+// Evaluate compiles and evaluates each CEL policy against the input.
+func (*celEngine) Evaluate(ctx context.Context, policies []string, input *Input) ([]string, error) {
+	env, err := cel.NewEnv(cel.Declarations(decls.NewVar(celVariable, decls.Dyn)))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the CEL environment")
+	}
+	// the policies see the input as it is marshaled, as the Rego ones do
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	var value map[string]interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	activation := map[string]interface{}{celVariable: value}
+
+	var messages []string
+	for _, policy := range policies {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		source, err := os.ReadFile(policy)
+		if err != nil {
+			return nil, err
+		}
+		ast, issues := env.Compile(string(source))
+		if issues != nil && issues.Err() != nil {
+			return nil, errors.Wrapf(issues.Err(), "failed to compile %s", policy)
+		}
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compile %s", policy)
+		}
+		out, _, err := program.Eval(activation)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to evaluate %s", policy)
+		}
+
+		if complies, ok := out.Value().(bool); ok {
+			if !complies {
+				messages = append(messages, fmt.Sprintf("the install does not comply with %s", policy))
+			}
+			continue
+		}
+		violations, err := out.ConvertToNative(reflect.TypeOf([]string{}))
+		if err != nil {
+			return nil, errors.Errorf("%s must evaluate to a bool or to a list of strings, not to a %s", policy, out.Type().TypeName())
+		}
+		messages = append(messages, violations.([]string)...)
+	}
+	return messages, nil
+}
@@ -0,0 +1,138 @@
+// Package policy evaluates the install-config and the manifests of a cluster
+// against the policies of the user, e.g. to enforce the regions, instance
+// types and tags of an organization. The policies are the files of a
+// directory, each evaluated by the engine registered for its extension.
+//
+// The Rego policies, the .rego files, are evaluated with the opa command,
+// which must be installed. The CEL policies, the .cel files, are evaluated
+// by the installer.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Input is what the policies are evaluated against.
+type Input struct {
+	// InstallConfig is the install-config, without its pull secret.
+	InstallConfig map[string]interface{} `json:"installConfig"`
+	// Manifests are the manifests generated for the cluster.
+	Manifests []Manifest `json:"manifests"`
+}
+
+// Manifest is a manifest generated for the cluster.
+type Manifest struct {
+	// Filename is the path of the manifest, relative to the assets
+	// directory, e.g. manifests/cluster-config.yaml.
+	Filename string `json:"filename"`
+	// Object is the Kubernetes object of the manifest.
+	Object map[string]interface{} `json:"object"`
+}
+
+// Engine evaluates the policies of one policy language.
+type Engine interface {
+	// Evaluate evaluates the policy files against the input, returning a
+	// message for each violation.
+	Evaluate(ctx context.Context, policies []string, input *Input) ([]string, error)
+
+	// Check returns an error when the engine cannot evaluate policies,
+	// e.g. because the command it runs is not installed.
+	Check() error
+}
+
+var engines = map[string]Engine{}
+
+// Register registers the engine evaluating the policy files with the
+// extension, e.g. ".rego".
+func Register(extension string, engine Engine) {
+	engines[extension] = engine
+}
+
+// Violations is the error of an install which violates policies.
+type Violations []string
+
+func (v Violations) Error() string {
+	return fmt.Sprintf("the install violates %d policies:\n  - %s", len(v), strings.Join(v, "\n  - "))
+}
+
+// Check checks that the policies of the directory can be evaluated, so that
+// an install fails before its assets are generated rather than once its
+// manifests are.
+func Check(dir string) error {
+	policies, err := files(dir)
+	if err != nil {
+		return err
+	}
+	for _, extension := range sortedExtensions(policies) {
+		if err := engines[extension].Check(); err != nil {
+			return errors.Wrapf(err, "cannot evaluate the %s policies of %s", extension, dir)
+		}
+	}
+	return nil
+}
+
+// Evaluate evaluates the policies of the directory and of its
+// subdirectories against the input. It returns the Violations of the
+// policies, if any. The files no engine is registered for are ignored.
+func Evaluate(ctx context.Context, dir string, input *Input) error {
+	policies, err := files(dir)
+	if err != nil {
+		return err
+	}
+	var violations Violations
+	for _, extension := range sortedExtensions(policies) {
+		logrus.Debugf("Evaluating the %s policies %v", extension, policies[extension])
+		messages, err := engines[extension].Evaluate(ctx, policies[extension], input)
+		if err != nil {
+			return errors.Wrapf(err, "failed to evaluate the %s policies", extension)
+		}
+		violations = append(violations, messages...)
+	}
+	if len(violations) > 0 {
+		return violations
+	}
+	return nil
+}
+
+// files returns the policy files of the directory and of its
+// subdirectories, by extension.
+func files(dir string) (map[string][]string, error) {
+	policies := map[string][]string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		extension := filepath.Ext(path)
+		if _, ok := engines[extension]; !ok {
+			logrus.Debugf("Ignoring %s of the policy directory: no policy engine evaluates %q files", path, extension)
+			return nil
+		}
+		policies[extension] = append(policies[extension], path)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the policy directory")
+	}
+	if len(policies) == 0 {
+		return nil, errors.Errorf("the policy directory %s has no policies, only %s files are supported", dir, strings.Join(sortedExtensions(engines), ", "))
+	}
+	return policies, nil
+}
+
+// sortedExtensions returns the extensions of the map, sorted.
+func sortedExtensions[V any](m map[string]V) []string {
+	extensions := make([]string, 0, len(m))
+	for extension := range m {
+		extensions = append(extensions, extension)
+	}
+	sort.Strings(extensions)
+	return extensions
+}
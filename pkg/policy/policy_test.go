@@ -0,0 +1,169 @@
+package policy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeEngine struct {
+	policies []string
+}
+
+func (e *fakeEngine) Evaluate(_ context.Context, policies []string, input *Input) ([]string, error) {
+	e.policies = policies
+	var messages []string
+	if region := input.InstallConfig["region"]; region != "us-east-1" {
+		messages = append(messages, "the region must be us-east-1")
+	}
+	for _, m := range input.Manifests {
+		if m.Object["kind"] == "Secret" {
+			messages = append(messages, m.Filename+" must not be a secret")
+		}
+	}
+	return messages, nil
+}
+
+func (e *fakeEngine) Check() error {
+	return nil
+}
+
+func TestEvaluate(t *testing.T) {
+	engine := &fakeEngine{}
+	Register(".fake", engine)
+	defer delete(engines, ".fake")
+
+	dir := t.TempDir()
+	for _, name := range []string{"region.fake", "README.md", "tags/tags.fake"} {
+		path := filepath.Join(dir, name)
+		if !assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750)) {
+			return
+		}
+		if !assert.NoError(t, os.WriteFile(path, nil, 0o640)) {
+			return
+		}
+	}
+
+	cases := []struct {
+		name   string
+		input  *Input
+		expect string
+	}{
+		{
+			name:  "valid",
+			input: &Input{InstallConfig: map[string]interface{}{"region": "us-east-1"}},
+		},
+		{
+			name: "violations",
+			input: &Input{
+				InstallConfig: map[string]interface{}{"region": "eu-west-1"},
+				Manifests:     []Manifest{{Filename: "openshift/secret.yaml", Object: map[string]interface{}{"kind": "Secret"}}},
+			},
+			expect: "the install violates 2 policies:\n  - the region must be us-east-1\n  - openshift/secret.yaml must not be a secret",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Evaluate(context.Background(), dir, tc.input)
+			if tc.expect == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expect)
+			}
+			assert.Equal(t, []string{filepath.Join(dir, "region.fake"), filepath.Join(dir, "tags/tags.fake")}, engine.policies)
+		})
+	}
+}
+
+func TestEvaluateNoPolicies(t *testing.T) {
+	dir := t.TempDir()
+	assert.EqualError(t, Evaluate(context.Background(), dir, &Input{}), "the policy directory "+dir+" has no policies, only .cel, .rego files are supported")
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	if !assert.NoError(t, os.WriteFile(filepath.Join(dir, "region.rego"), nil, 0o640)) {
+		return
+	}
+	defer func(engine Engine) { engines[".rego"] = engine }(engines[".rego"])
+
+	engines[".rego"] = &regoEngine{command: filepath.Join(dir, "opa")}
+	err := Check(dir)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "cannot evaluate the .rego policies of "+dir+": Rego policies are evaluated with the "+filepath.Join(dir, "opa")+" command, which must be installed")
+	}
+
+	if !assert.NoError(t, os.WriteFile(filepath.Join(dir, "opa"), []byte("#!/bin/sh\n"), 0o750)) {
+		return
+	}
+	assert.NoError(t, Check(dir))
+}
+
+func TestRegoEngine(t *testing.T) {
+	dir := t.TempDir()
+	// the fake opa checks its arguments and input, and prints the result
+	// of the query
+	opa := filepath.Join(dir, "opa")
+	script := `#!/bin/sh
+[ "$*" = "eval --format json --stdin-input --data a.rego --data b.rego data.openshift.install.deny" ] || { echo "unexpected arguments: $*" >&2; exit 1; }
+grep -q '"region":"eu-west-1"' || { echo "unexpected input" >&2; exit 1; }
+echo '{"result":[{"expressions":[{"value":["the region must be us-east-1",{"msg":"the tag owner is required"}]}]}]}'
+`
+	if !assert.NoError(t, os.WriteFile(opa, []byte(script), 0o750)) {
+		return
+	}
+
+	engine := &regoEngine{command: opa}
+	messages, err := engine.Evaluate(context.Background(), []string{"a.rego", "b.rego"}, &Input{InstallConfig: map[string]interface{}{"region": "eu-west-1"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"the region must be us-east-1", "the tag owner is required"}, messages)
+
+	_, err = engine.Evaluate(context.Background(), []string{"a.rego"}, &Input{})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unexpected arguments")
+	}
+}
+
+func TestCELEngine(t *testing.T) {
+	dir := t.TempDir()
+	policy := func(name, source string) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(source), 0o640))
+		return path
+	}
+	region := policy("region.cel", `input.installConfig.region == "us-east-1"`)
+	secrets := policy("secrets.cel", `// the secrets are provided by the user
+input.manifests.filter(m, m.object.kind == "Secret").map(m, m.filename + " must not be a secret")`)
+	invalid := policy("invalid.cel", `input.installConfig.region ==`)
+	number := policy("number.cel", `1`)
+
+	engine := &celEngine{}
+	messages, err := engine.Evaluate(context.Background(), []string{region, secrets}, &Input{
+		InstallConfig: map[string]interface{}{"region": "eu-west-1"},
+		Manifests: []Manifest{
+			{Filename: "openshift/99_secret.yaml", Object: map[string]interface{}{"kind": "Secret"}},
+			{Filename: "manifests/cluster-config.yaml", Object: map[string]interface{}{"kind": "ConfigMap"}},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"the install does not comply with " + region, "openshift/99_secret.yaml must not be a secret"}, messages)
+
+	messages, err = engine.Evaluate(context.Background(), []string{region, secrets}, &Input{
+		InstallConfig: map[string]interface{}{"region": "us-east-1"},
+		Manifests: []Manifest{
+			{Filename: "manifests/cluster-config.yaml", Object: map[string]interface{}{"kind": "ConfigMap"}},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, messages)
+
+	_, err = engine.Evaluate(context.Background(), []string{invalid}, &Input{})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "failed to compile "+invalid)
+	}
+	_, err = engine.Evaluate(context.Background(), []string{number}, &Input{})
+	assert.EqualError(t, err, number+" must evaluate to a bool or to a list of strings, not to a int")
+}
@@ -0,0 +1,98 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+
+	"github.com/pkg/errors"
+)
+
+// regoQuery is the query of the Rego policies: the deny rules of the
+// openshift.install package, whose values are the messages of the
+// violations, either strings or objects with a msg, as for conftest.
+const regoQuery = "data.openshift.install.deny"
+
+// regoEngine evaluates Rego policies with the opa command.
+type regoEngine struct {
+	command string
+}
+
+func init() {
+	Register(".rego", &regoEngine{command: "opa"})
+}
+
+// Check checks that the opa command is installed.
+func (e *regoEngine) Check() error {
+	_, err := e.path()
+	return err
+}
+
+// path returns the path of the opa command.
+func (e *regoEngine) path() (string, error) {
+	path, err := exec.LookPath(e.command)
+	if err != nil {
+		return "", errors.Wrapf(err, "Rego policies are evaluated with the %s command, which must be installed", e.command)
+	}
+	return path, nil
+}
+
+// Evaluate evaluates the Rego policies with opa eval, which reads the input
+// from its standard input.
+func (e *regoEngine) Evaluate(ctx context.Context, policies []string, input *Input) ([]string, error) {
+	path, err := e.path()
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"eval", "--format", "json", "--stdin-input"}
+	for _, policy := range policies {
+		args = append(args, "--data", policy)
+	}
+	args = append(args, regoQuery)
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stdin = bytes.NewReader(data)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "opa eval failed: %s", bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	var result struct {
+		Result []struct {
+			Expressions []struct {
+				Value []interface{} `json:"value"`
+			} `json:"expressions"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to decode the result of opa eval")
+	}
+	var messages []string
+	for _, r := range result.Result {
+		for _, expression := range r.Expressions {
+			for _, value := range expression.Value {
+				switch v := value.(type) {
+				case string:
+					messages = append(messages, v)
+				case map[string]interface{}:
+					if msg, ok := v["msg"].(string); ok {
+						messages = append(messages, msg)
+						continue
+					}
+					messages = append(messages, fmt.Sprint(v))
+				default:
+					messages = append(messages, fmt.Sprint(v))
+				}
+			}
+		}
+	}
+	return messages, nil
+}
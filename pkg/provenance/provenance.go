@@ -0,0 +1,203 @@
+// Package provenance records the checksums of the manifests and Ignition
+// configs the installer generates in a provenance file, optionally signed
+// with a key of the user, so that what the cluster booted from can be
+// proven afterwards.
+//
+// The signature is the base64-encoded signature of the file, as written by
+// cosign sign-blob, so that it is verified with:
+//
+//	cosign verify-blob --key key.pub --signature provenance.json.sig provenance.json
+package provenance
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// FileName is the name of the provenance file in the assets directory.
+	FileName = "provenance.json"
+
+	// SignatureFileName is the name of the signature of the provenance file
+	// in the assets directory.
+	SignatureFileName = FileName + ".sig"
+)
+
+// Provenance lists the files generated for a cluster and their checksums.
+type Provenance struct {
+	// Version is the version of the installer which generated the files.
+	Version string `json:"version"`
+	// ReleaseImage is the pull spec of the release image of the cluster.
+	ReleaseImage string `json:"releaseImage,omitempty"`
+	// Created is when the provenance was recorded.
+	Created time.Time `json:"created"`
+	// Files are the generated files, sorted by name.
+	Files []File `json:"files"`
+}
+
+// File is a generated file.
+type File struct {
+	// Name is the path of the file, relative to the assets directory.
+	Name string `json:"name"`
+	// SHA256 is the hex-encoded SHA-256 checksum of the contents of the file.
+	SHA256 string `json:"sha256"`
+}
+
+// Add records the file with its contents, replacing any earlier record of
+// it.
+func (p *Provenance) Add(name string, data []byte) {
+	sum := sha256.Sum256(data)
+	file := File{Name: filepath.ToSlash(name), SHA256: hex.EncodeToString(sum[:])}
+	i := sort.Search(len(p.Files), func(i int) bool { return p.Files[i].Name >= file.Name })
+	if i < len(p.Files) && p.Files[i].Name == file.Name {
+		p.Files[i] = file
+		return
+	}
+	p.Files = append(p.Files, File{})
+	copy(p.Files[i+1:], p.Files[i:])
+	p.Files[i] = file
+}
+
+// Write writes the provenance file to the assets directory and, when key is
+// set, its signature. A signature left from an earlier provenance file is
+// removed otherwise.
+func Write(dir string, p *Provenance, key crypto.Signer) error {
+	data, sig, err := Marshal(p, key)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, FileName), data, 0o640); err != nil {
+		return errors.Wrap(err, "failed to write the provenance file")
+	}
+
+	sigPath := filepath.Join(dir, SignatureFileName)
+	if sig == nil {
+		if err := os.Remove(sigPath); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "failed to remove the signature of the previous provenance file")
+		}
+		return nil
+	}
+	return errors.Wrap(os.WriteFile(sigPath, sig, 0o640), "failed to write the signature of the provenance file")
+}
+
+// Marshal returns the contents of the provenance file and, when key is set,
+// of its signature file.
+func Marshal(p *Provenance, key crypto.Signer) (data, sig []byte, err error) {
+	data, err = json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+	if key == nil {
+		return data, nil, nil
+	}
+	rawSig, err := Sign(data, key)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to sign the provenance file")
+	}
+	return data, []byte(base64.StdEncoding.EncodeToString(rawSig)), nil
+}
+
+// Sign signs the data with the key: the SHA-256 digest of the data with an
+// ECDSA (ASN.1) or RSA (PKCS #1 v1.5) key, or the data itself with an
+// Ed25519 key.
+func Sign(data []byte, key crypto.Signer) ([]byte, error) {
+	if _, ok := key.(ed25519.PrivateKey); ok {
+		return key.Sign(rand.Reader, data, crypto.Hash(0))
+	}
+	digest := sha256.Sum256(data)
+	return key.Sign(rand.Reader, digest[:], crypto.SHA256)
+}
+
+// Verify verifies the signature of the data, as signed by Sign, with the
+// public key.
+func Verify(data, sig []byte, key crypto.PublicKey) error {
+	digest := sha256.Sum256(data)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(k, digest[:], sig) {
+			return errors.New("invalid signature")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig); err != nil {
+			return errors.New("invalid signature")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, data, sig) {
+			return errors.New("invalid signature")
+		}
+	default:
+		return errors.Errorf("unsupported public key type %T", key)
+	}
+	return nil
+}
+
+// LoadPrivateKey loads a PEM-encoded ECDSA, RSA or Ed25519 private key, in
+// PKCS #8 or its traditional format. Encrypted keys, e.g. those generated by
+// cosign generate-key-pair, are not supported.
+func LoadPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(block.Type, "ENCRYPTED") || block.Headers["Proc-Type"] != "" {
+		return nil, errors.Errorf("the private key %s is encrypted, decrypt it first", path)
+	}
+	var key interface{}
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the private key %s", path)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
+
+// LoadPublicKey loads a PEM-encoded PKIX public key.
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the public key %s", path)
+	}
+	return key, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the key")
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.Errorf("%s is not a PEM-encoded key", path)
+	}
+	return block, nil
+}
@@ -0,0 +1,130 @@
+package provenance
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdd(t *testing.T) {
+	p := &Provenance{}
+	p.Add("openshift/99_openshift-cluster-api_master-machines-0.yaml", []byte("machine"))
+	p.Add("bootstrap.ign", []byte("old"))
+	p.Add("manifests/cluster-config.yaml", []byte("config"))
+	p.Add("bootstrap.ign", []byte("{}"))
+
+	assert.Equal(t, []File{
+		{Name: "bootstrap.ign", SHA256: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"},
+		{Name: "manifests/cluster-config.yaml", SHA256: "b79606fb3afea5bd1609ed40b622142f1c98125abcfe89a76a661b0e8e343910"},
+		{Name: "openshift/99_openshift-cluster-api_master-machines-0.yaml", SHA256: "bc020a35b7f9cb1382e7b534c68e3c531d849b119bf14f75ddead6cc45c3ccc1"},
+	}, p.Files)
+}
+
+func TestWriteAndVerify(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if !assert.NoError(t, err) {
+		return
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	cases := []struct {
+		name string
+		key  crypto.Signer
+	}{
+		{name: "ecdsa", key: ecKey},
+		{name: "rsa", key: rsaKey},
+		{name: "ed25519", key: edKey},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			der, err := x509.MarshalPKCS8PrivateKey(tc.key)
+			if !assert.NoError(t, err) {
+				return
+			}
+			keyPath := filepath.Join(dir, "key.pem")
+			if !assert.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600)) {
+				return
+			}
+			der, err = x509.MarshalPKIXPublicKey(tc.key.Public())
+			if !assert.NoError(t, err) {
+				return
+			}
+			pubPath := filepath.Join(dir, "key.pub")
+			if !assert.NoError(t, os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600)) {
+				return
+			}
+
+			key, err := LoadPrivateKey(keyPath)
+			if !assert.NoError(t, err) {
+				return
+			}
+			p := &Provenance{Version: "v0.0.0", Created: time.Unix(0, 0).UTC()}
+			p.Add("bootstrap.ign", []byte("{}"))
+			if !assert.NoError(t, Write(dir, p, key)) {
+				return
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, FileName))
+			if !assert.NoError(t, err) {
+				return
+			}
+			written := &Provenance{}
+			if !assert.NoError(t, json.Unmarshal(data, written)) {
+				return
+			}
+			assert.Equal(t, p, written)
+
+			encoded, err := os.ReadFile(filepath.Join(dir, SignatureFileName))
+			if !assert.NoError(t, err) {
+				return
+			}
+			sig, err := base64.StdEncoding.DecodeString(string(encoded))
+			if !assert.NoError(t, err) {
+				return
+			}
+			pub, err := LoadPublicKey(pubPath)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.NoError(t, Verify(data, sig, pub))
+			assert.EqualError(t, Verify(append(data, '\n'), sig, pub), "invalid signature")
+
+			// the signature is removed when the provenance is no longer
+			// signed
+			if !assert.NoError(t, Write(dir, p, nil)) {
+				return
+			}
+			assert.NoFileExists(t, filepath.Join(dir, SignatureFileName))
+		})
+	}
+}
+
+func TestLoadPrivateKeyEncrypted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cosign.key")
+	if !assert.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED SIGSTORE PRIVATE KEY", Bytes: []byte("key")}), 0o600)) {
+		return
+	}
+	_, err := LoadPrivateKey(path)
+	assert.EqualError(t, err, "the private key "+path+" is encrypted, decrypt it first")
+}
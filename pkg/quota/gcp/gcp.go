@@ -15,14 +15,16 @@ import (
 	"google.golang.org/grpc/status"
 
 	gcpconfig "github.com/openshift/installer/pkg/asset/installconfig/gcp"
+	"github.com/openshift/installer/pkg/credentialsfile"
 	"github.com/openshift/installer/pkg/quota"
 )
 
 // Load load the quota information for a project and provided services. It provides information
 // about the usage and limit for each resource quota.
 // roles/servicemanagement.quotaViewer role allows users to fetch the required details.
-func Load(ctx context.Context, project string, services ...string) ([]quota.Quota, error) {
-	ssn, err := gcpconfig.GetSession(ctx)
+// The credentials may be nil for the default ones.
+func Load(ctx context.Context, credentials *credentialsfile.GCP, project string, services ...string) ([]quota.Quota, error) {
+	ssn, err := gcpconfig.GetSessionWithCredentials(ctx, credentials)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get session")
 	}
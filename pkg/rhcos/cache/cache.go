@@ -10,36 +10,54 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/h2non/filetype/matchers"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/ulikunitz/xz"
 	"golang.org/x/sys/unix"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/openshift/installer/pkg/offline"
 )
 
 const (
 	applicationName = "openshift-installer"
 	imageDataType   = "image"
+
+	// CacheDirEnv overrides the directory the cache is kept in, which is
+	// <user_cache_dir>/openshift-installer by default.
+	CacheDirEnv = "OPENSHIFT_INSTALL_CACHE_DIR"
+	// CacheMaxSizeEnv limits the total size of the files of each data type
+	// in the cache, as a quantity such as 20Gi. The least recently used
+	// files are evicted when a download exceeds it. The cache is unlimited
+	// by default.
+	CacheMaxSizeEnv = "OPENSHIFT_INSTALL_CACHE_MAX_SIZE"
 )
 
 // getCacheDir returns a local path of the cache, where the installer should put the data:
-// <user_cache_dir>/openshift-installer/<dataType>_cache
+// <cache_dir>/<dataType>_cache
 // If the directory doesn't exist, it will be automatically created.
 func getCacheDir(dataType string) (string, error) {
 	if dataType == "" {
 		return "", errors.Errorf("data type can't be an empty string")
 	}
 
-	userCacheDir, err := os.UserCacheDir()
-	if err != nil {
-		return "", err
+	baseDir := os.Getenv(CacheDirEnv)
+	if baseDir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return "", err
+		}
+		baseDir = filepath.Join(userCacheDir, applicationName)
 	}
 
-	cacheDir := filepath.Join(userCacheDir, applicationName, dataType+"_cache")
+	cacheDir := filepath.Join(baseDir, dataType+"_cache")
 
-	_, err = os.Stat(cacheDir)
+	_, err := os.Stat(cacheDir)
 	if err != nil {
 		if os.IsNotExist(err) {
 			err = os.MkdirAll(cacheDir, 0755)
@@ -83,9 +101,12 @@ func cacheFile(reader io.Reader, filePath string, sha256Checksum string) (err er
 	}()
 
 	_, err = os.Stat(filePath)
-	if err != nil && !os.IsNotExist(err) {
+	if err == nil {
 		return nil // another cacheFile beat us to it
 	}
+	if !os.IsNotExist(err) {
+		return err
+	}
 
 	tempPath := fmt.Sprintf("%s.tmp", filePath)
 
@@ -113,10 +134,11 @@ func cacheFile(reader io.Reader, filePath string, sha256Checksum string) (err er
 	// Detect whether we know how to decompress the file
 	// See http://golang.org/pkg/net/http/#DetectContentType for why we use 512
 	buf := make([]byte, 512)
-	_, err = reader.Read(buf)
-	if err != nil {
+	n, err := io.ReadFull(reader, buf)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
 		return err
 	}
+	buf = buf[:n]
 
 	reader = io.MultiReader(bytes.NewReader(buf), reader)
 	switch {
@@ -194,13 +216,27 @@ func (u *urlWithIntegrity) download(dataType string) (string, error) {
 	}
 	filePath := filepath.Join(cacheDir, fileName)
 
-	// If the file has already been cached, return its path
+	// If the file has already been cached, and is intact, return its path
 	_, err = os.Stat(filePath)
 	if err == nil {
-		logrus.Infof("The file was found in cache: %v. Reusing...", filePath)
-		return filePath, nil
+		if err := verifyCachedFile(filePath, u.uncompressedSHA256); err != nil {
+			logrus.Warnf("Discarding the cached file %v: %v", filePath, err)
+			if err := os.Remove(filePath); err != nil {
+				return "", err
+			}
+		} else {
+			logrus.Infof("The file was found in cache: %v. Reusing...", filePath)
+			now := time.Now()
+			if err := os.Chtimes(filePath, now, now); err != nil {
+				logrus.Debugf("Failed to mark %v as recently used: %v", filePath, err)
+			}
+			return filePath, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return "", err
 	}
-	if !os.IsNotExist(err) {
+
+	if err := offline.Require(fmt.Sprintf("Downloading %s", u.location.String())); err != nil {
 		return "", err
 	}
 
@@ -221,9 +257,96 @@ func (u *urlWithIntegrity) download(dataType string) (string, error) {
 		return "", err
 	}
 
+	maxSize, err := cacheMaxSize()
+	if err != nil {
+		return "", err
+	}
+	if err := evict(cacheDir, maxSize, filePath); err != nil {
+		logrus.Warnf("Failed to evict files from the cache: %v", err)
+	}
+
 	return filePath, nil
 }
 
+// verifyCachedFile checks that the sha256 checksum of the cached file, if
+// known, is still the expected one.
+func verifyCachedFile(filePath string, sha256Checksum string) error {
+	if sha256Checksum == "" {
+		return nil
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return err
+	}
+	if foundChecksum := fmt.Sprintf("%x", hasher.Sum(nil)); foundChecksum != sha256Checksum {
+		return errors.Errorf("checksum mismatch; expected=%s found=%s", sha256Checksum, foundChecksum)
+	}
+	return nil
+}
+
+// cacheMaxSize returns the size limit of the cache in bytes, or 0 when it is
+// unlimited.
+func cacheMaxSize() (int64, error) {
+	value := os.Getenv(CacheMaxSizeEnv)
+	if value == "" {
+		return 0, nil
+	}
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid %s", CacheMaxSizeEnv)
+	}
+	return quantity.Value(), nil
+}
+
+// evict removes the least recently used files from the cache directory until
+// their total size is within maxSize. The file just added is kept even if it
+// alone exceeds maxSize.
+func evict(cacheDir string, maxSize int64, keep string) error {
+	if maxSize <= 0 {
+		return nil
+	}
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		return err
+	}
+	var files []os.FileInfo
+	var total int64
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || strings.HasSuffix(entry.Name(), ".lock") || strings.HasSuffix(entry.Name(), ".tmp") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, info)
+		total += info.Size()
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().Before(files[j].ModTime())
+	})
+	for _, info := range files {
+		if total <= maxSize {
+			break
+		}
+		path := filepath.Join(cacheDir, info.Name())
+		if path == keep {
+			continue
+		}
+		logrus.Infof("Evicting %v from the cache", path)
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		total -= info.Size()
+	}
+	return nil
+}
+
 // DownloadImageFile is a helper function that obtains an image file from a given URL,
 // puts it in the cache and returns the local file path.  If the file is compressed
 // by a known compressor, the file is uncompressed prior to being returned.
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadImageFile(t *testing.T) {
+	content := []byte("rhcos image")
+	checksum := fmt.Sprintf("%x", sha256.Sum256(content))
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(content)
+	}))
+	defer server.Close()
+	t.Setenv(CacheDirEnv, t.TempDir())
+
+	path, err := DownloadImageFile(server.URL + "/rhcos.qcow2?sha256=" + checksum)
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+
+	_, err = DownloadImageFile(server.URL + "/rhcos.qcow2?sha256=" + checksum)
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests, "the cached file should be reused")
+
+	require.NoError(t, os.Chmod(path, 0644))
+	require.NoError(t, os.WriteFile(path, []byte("corrupted"), 0644))
+	_, err = DownloadImageFile(server.URL + "/rhcos.qcow2?sha256=" + checksum)
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests, "the corrupted file should be downloaded again")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, content, data)
+
+	_, err = DownloadImageFile(server.URL + "/other.qcow2?sha256=" + checksum[1:] + "0")
+	assert.Regexp(t, "^Checksum mismatch for .*other.qcow2", err)
+}
+
+func TestEvict(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	for i, name := range []string{"oldest", "older", "newest"} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, make([]byte, 10), 0644))
+		modTime := now.Add(time.Duration(i) * time.Hour)
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	require.NoError(t, evict(dir, 20, filepath.Join(dir, "oldest")))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"newest", "oldest"}, names)
+}
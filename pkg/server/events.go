@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// eventLog records the progress events of an operation, one JSON event per
+// line, and lets any number of readers follow them.
+type eventLog struct {
+	mu      sync.Mutex
+	lines   [][]byte
+	closed  bool
+	changed chan struct{}
+}
+
+func newEventLog() *eventLog {
+	return &eventLog{changed: make(chan struct{})}
+}
+
+// Write records an event.
+func (l *eventLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return 0, io.ErrClosedPipe
+	}
+	l.lines = append(l.lines, append([]byte(nil), p...))
+	l.notify()
+	return len(p), nil
+}
+
+// Close marks the end of the events.
+func (l *eventLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.closed {
+		l.closed = true
+		l.notify()
+	}
+	return nil
+}
+
+// notify wakes up the readers. It must be called with the lock held.
+func (l *eventLog) notify() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// follow writes the events to w as they are recorded, calling flush after
+// each batch, until the log is closed or the context is done.
+func (l *eventLog) follow(ctx context.Context, w io.Writer, flush func()) error {
+	next := 0
+	for {
+		l.mu.Lock()
+		lines := l.lines[next:]
+		closed, changed := l.closed, l.changed
+		l.mu.Unlock()
+
+		for _, line := range lines {
+			if _, err := w.Write(line); err != nil {
+				return err
+			}
+		}
+		next += len(lines)
+		flush()
+		if closed {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
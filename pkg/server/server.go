@@ -0,0 +1,339 @@
+// Package server exposes the installer as an HTTP API, for provisioning
+// platforms which manage installs without access to their assets
+// directories.
+//
+// The API is:
+//
+//	PUT    /v1/installs/{name}            create the cluster of the install-config in the body
+//	GET    /v1/installs/{name}            the state of the last operation of the install
+//	GET    /v1/installs/{name}/events     the progress events of the last operation, as JSON lines, followed until it ends
+//	GET    /v1/installs/{name}/kubeconfig the admin kubeconfig of the cluster
+//	DELETE /v1/installs/{name}            destroy the cluster
+//
+// Operations are run one at a time, in the order they are requested.
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+	"github.com/openshift/installer/pkg/client"
+	"github.com/openshift/installer/pkg/metrics/progress"
+)
+
+// State is the state of the operation of an install.
+type State string
+
+const (
+	// Queued operations wait for the operations requested before them.
+	Queued State = "Queued"
+	// Running operations are being run.
+	Running State = "Running"
+	// Succeeded operations are done.
+	Succeeded State = "Succeeded"
+	// Failed operations are done with an error.
+	Failed State = "Failed"
+)
+
+const (
+	// CreateOperation creates the cluster. It succeeds once the
+	// infrastructure is provisioned; the installation then goes on in the
+	// cluster.
+	CreateOperation = "create"
+	// DestroyOperation destroys the cluster.
+	DestroyOperation = "destroy"
+)
+
+// maxInstallConfigSize is the largest install-config accepted.
+const maxInstallConfigSize = 1 << 20
+
+var nameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// Status is the status of an install.
+type Status struct {
+	Name      string `json:"name"`
+	Operation string `json:"operation"`
+	State     State  `json:"state"`
+	Error     string `json:"error,omitempty"`
+}
+
+type install struct {
+	status Status
+	events *eventLog
+}
+
+// Server serves the API for the installs kept in subdirectories of its
+// directory, named after them.
+type Server struct {
+	dir   string
+	token string
+
+	mu       sync.Mutex
+	installs map[string]*install
+	queue    chan func(context.Context)
+}
+
+// New returns a server for the installs in dir. Requests must carry the
+// token as a bearer token, unless it is empty.
+func New(dir string, token string) *Server {
+	return &Server{
+		dir:      dir,
+		token:    token,
+		installs: map[string]*install{},
+		queue:    make(chan func(context.Context), 100),
+	}
+}
+
+// Run runs the requested operations until the context is done.
+func (s *Server) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case operation := <-s.queue:
+			operation(ctx)
+		}
+	}
+}
+
+// ServeHTTP serves the API.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.token != "" {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	path := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/installs/"), "/")
+	if path[0] == r.URL.Path || len(path) > 2 || !nameRegexp.MatchString(path[0]) {
+		http.NotFound(w, r)
+		return
+	}
+	name := path[0]
+	switch {
+	case len(path) == 1 && r.Method == http.MethodPut:
+		s.create(w, r, name)
+	case len(path) == 1 && r.Method == http.MethodGet:
+		s.status(w, r, name)
+	case len(path) == 1 && r.Method == http.MethodDelete:
+		s.destroy(w, r, name)
+	case len(path) == 2 && path[1] == "events" && r.Method == http.MethodGet:
+		s.followEvents(w, r, name)
+	case len(path) == 2 && path[1] == "kubeconfig" && r.Method == http.MethodGet:
+		s.kubeconfig(w, r, name)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request, name string) {
+	installConfig, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxInstallConfigSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	i, release := s.reserve(w, name, CreateOperation)
+	if i == nil {
+		return
+	}
+	if err := os.MkdirAll(s.dir, 0750); err != nil {
+		release()
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// Mkdir fails on an existing directory, so that only the directory
+	// created by this request is removed on failure.
+	dir := filepath.Join(s.dir, name)
+	if err := os.Mkdir(dir, 0750); err != nil {
+		release()
+		if errors.Is(err, os.ErrExist) {
+			http.Error(w, "the install already exists", http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := os.WriteFile(filepath.Join(dir, "install-config.yaml"), installConfig, 0640); err != nil {
+		os.RemoveAll(dir)
+		release()
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	queued := s.enqueue(w, i, release, func(ctx context.Context, installer *client.Client) error {
+		return installer.CreateCluster(ctx)
+	})
+	if !queued {
+		os.RemoveAll(dir)
+	}
+}
+
+func (s *Server) destroy(w http.ResponseWriter, r *http.Request, name string) {
+	if _, err := os.Stat(filepath.Join(s.dir, name)); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	i, release := s.reserve(w, name, DestroyOperation)
+	if i == nil {
+		return
+	}
+	s.enqueue(w, i, release, func(ctx context.Context, installer *client.Client) error {
+		_, err := installer.DestroyCluster(ctx, client.DestroyOptions{})
+		return err
+	})
+}
+
+// reserve records the operation as queued on the install, unless an
+// operation of the install is already queued or running, so that concurrent
+// requests for the install conflict before they touch its directory. It
+// returns the install and the function undoing the reservation, or nil if
+// the install is busy.
+func (s *Server) reserve(w http.ResponseWriter, name string, operation string) (*install, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, ok := s.installs[name]
+	if ok && (previous.status.State == Queued || previous.status.State == Running) {
+		http.Error(w, "an operation of the install is in progress", http.StatusConflict)
+		return nil, nil
+	}
+
+	i := &install{
+		status: Status{Name: name, Operation: operation, State: Queued},
+		events: newEventLog(),
+	}
+	s.installs[name] = i
+	release := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.installs[name] != i {
+			return
+		}
+		if previous != nil {
+			s.installs[name] = previous
+		} else {
+			delete(s.installs, name)
+		}
+	}
+	return i, release
+}
+
+// enqueue queues the operation of the reserved install and returns whether it
+// did. The reservation is released if the queue is full.
+func (s *Server) enqueue(w http.ResponseWriter, i *install, release func(), run func(context.Context, *client.Client) error) bool {
+	status := i.status
+	select {
+	case s.queue <- func(ctx context.Context) { s.run(ctx, i, run) }:
+	default:
+		release()
+		http.Error(w, "too many operations are queued", http.StatusServiceUnavailable)
+		return false
+	}
+	writeJSON(w, http.StatusAccepted, status)
+	return true
+}
+
+// run runs the operation, sending the progress events to the event log of
+// the install.
+func (s *Server) run(ctx context.Context, i *install, run func(context.Context, *client.Client) error) {
+	s.setState(i, Running, nil)
+	stop := progress.OpenWriter(i.events)
+	defer stop()
+
+	logger := logrus.StandardLogger().WithField("install", i.status.Name)
+	logger.Infof("Starting %s", i.status.Operation)
+	installer, err := client.New(filepath.Join(s.dir, i.status.Name))
+	if err == nil {
+		err = run(ctx, installer)
+	}
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to %s", i.status.Operation)
+		s.setState(i, Failed, err)
+		return
+	}
+	logger.Infof("Finished %s", i.status.Operation)
+	s.setState(i, Succeeded, nil)
+}
+
+func (s *Server) setState(i *install, state State, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i.status.State = state
+	if err != nil {
+		i.status.Error = err.Error()
+	}
+}
+
+func (s *Server) get(name string) *install {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.installs[name]
+}
+
+func (s *Server) status(w http.ResponseWriter, r *http.Request, name string) {
+	s.mu.Lock()
+	i, ok := s.installs[name]
+	var status Status
+	if ok {
+		status = i.status
+	}
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+func (s *Server) followEvents(w http.ResponseWriter, r *http.Request, name string) {
+	i := s.get(name)
+	if i == nil {
+		http.NotFound(w, r)
+		return
+	}
+	flush := func() {}
+	if flusher, ok := w.(http.Flusher); ok {
+		flush = flusher.Flush
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	if err := i.events.follow(r.Context(), w, flush); err != nil && !errors.Is(err, context.Canceled) {
+		logrus.Debugf("Stopped sending the events of %s: %v", name, err)
+	}
+}
+
+func (s *Server) kubeconfig(w http.ResponseWriter, r *http.Request, name string) {
+	data, err := assetstore.ReadFile(filepath.Join(s.dir, name, "auth", "kubeconfig"))
+	if errors.Is(err, os.ErrNotExist) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/yaml")
+	if _, err := w.Write(data); err != nil {
+		logrus.Debugf("Failed to send the kubeconfig of %s: %v", name, err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logrus.Debugf("Failed to send the response: %v", err)
+	}
+}
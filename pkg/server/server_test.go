@@ -0,0 +1,207 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeHTTP(t *testing.T) {
+	dir := t.TempDir()
+	s := New(dir, "secret")
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "existing", "auth"), 0750))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "existing", "auth", "kubeconfig"), []byte("kind: Config\n"), 0600))
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		body   string
+		code   int
+		check  func(t *testing.T, body string)
+	}{
+		{
+			name:   "missing token",
+			method: http.MethodGet,
+			path:   "/v1/installs/existing",
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "wrong token",
+			method: http.MethodGet,
+			path:   "/v1/installs/existing",
+			token:  "guess",
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "unknown path",
+			method: http.MethodGet,
+			path:   "/v2/installs/existing",
+			token:  "secret",
+			code:   http.StatusNotFound,
+		},
+		{
+			name:   "invalid name",
+			method: http.MethodPut,
+			path:   "/v1/installs/..",
+			token:  "secret",
+			code:   http.StatusNotFound,
+		},
+		{
+			name:   "no operation",
+			method: http.MethodGet,
+			path:   "/v1/installs/existing",
+			token:  "secret",
+			code:   http.StatusNotFound,
+		},
+		{
+			name:   "kubeconfig",
+			method: http.MethodGet,
+			path:   "/v1/installs/existing/kubeconfig",
+			token:  "secret",
+			code:   http.StatusOK,
+			check: func(t *testing.T, body string) {
+				assert.Equal(t, "kind: Config\n", body)
+			},
+		},
+		{
+			name:   "no kubeconfig",
+			method: http.MethodGet,
+			path:   "/v1/installs/new/kubeconfig",
+			token:  "secret",
+			code:   http.StatusNotFound,
+		},
+		{
+			name:   "create existing",
+			method: http.MethodPut,
+			path:   "/v1/installs/existing",
+			token:  "secret",
+			body:   "apiVersion: v1\n",
+			code:   http.StatusConflict,
+		},
+		{
+			name:   "destroy unknown",
+			method: http.MethodDelete,
+			path:   "/v1/installs/unknown",
+			token:  "secret",
+			code:   http.StatusNotFound,
+		},
+		{
+			name:   "create",
+			method: http.MethodPut,
+			path:   "/v1/installs/new",
+			token:  "secret",
+			body:   "apiVersion: v1\n",
+			code:   http.StatusAccepted,
+			check: func(t *testing.T, body string) {
+				var status Status
+				assert.NoError(t, json.Unmarshal([]byte(body), &status))
+				assert.Equal(t, Status{Name: "new", Operation: CreateOperation, State: Queued}, status)
+				data, err := os.ReadFile(filepath.Join(dir, "new", "install-config.yaml"))
+				assert.NoError(t, err)
+				assert.Equal(t, "apiVersion: v1\n", string(data))
+			},
+		},
+		{
+			name:   "destroy while queued",
+			method: http.MethodDelete,
+			path:   "/v1/installs/new",
+			token:  "secret",
+			code:   http.StatusConflict,
+		},
+		{
+			name:   "status",
+			method: http.MethodGet,
+			path:   "/v1/installs/new",
+			token:  "secret",
+			code:   http.StatusOK,
+			check: func(t *testing.T, body string) {
+				assert.Contains(t, body, `"state":"Queued"`)
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			if tc.token != "" {
+				r.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, r)
+			assert.Equal(t, tc.code, w.Code)
+			if tc.check != nil {
+				tc.check(t, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestConcurrentCreate(t *testing.T) {
+	dir := t.TempDir()
+	s := New(dir, "")
+
+	const requests = 10
+	codes := make([]int, requests)
+	var wg sync.WaitGroup
+	for n := 0; n < requests; n++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			r := httptest.NewRequest(http.MethodPut, "/v1/installs/new", strings.NewReader("apiVersion: v1\n"))
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, r)
+			codes[n] = w.Code
+		}(n)
+	}
+	wg.Wait()
+
+	accepted := 0
+	for _, code := range codes {
+		if code == http.StatusAccepted {
+			accepted++
+			continue
+		}
+		assert.Equal(t, http.StatusConflict, code)
+	}
+	assert.Equal(t, 1, accepted)
+	data, err := os.ReadFile(filepath.Join(dir, "new", "install-config.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "apiVersion: v1\n", string(data))
+	assert.Equal(t, Queued, s.get("new").status.State)
+}
+
+func TestEventLogFollow(t *testing.T) {
+	l := newEventLog()
+	l.Write([]byte("{\"type\":\"phaseStarted\"}\n"))
+
+	var buf bytes.Buffer
+	done := make(chan error)
+	go func() {
+		done <- l.follow(context.Background(), &buf, func() {})
+	}()
+	l.Write([]byte("{\"type\":\"phaseCompleted\"}\n"))
+	l.Close()
+	assert.NoError(t, <-done)
+	assert.Equal(t, "{\"type\":\"phaseStarted\"}\n{\"type\":\"phaseCompleted\"}\n", buf.String())
+
+	_, err := l.Write([]byte("{}\n"))
+	assert.Error(t, err)
+}
+
+func TestEventLogFollowCanceled(t *testing.T) {
+	l := newEventLog()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var buf bytes.Buffer
+	assert.ErrorIs(t, l.follow(ctx, &buf, func() {}), context.Canceled)
+}
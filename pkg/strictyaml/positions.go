@@ -0,0 +1,124 @@
+package strictyaml
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// position is the line and column of a key of the document.
+type position struct {
+	line, column int
+}
+
+// positions are the positions of the keys of the document by their paths.
+type positions map[string]position
+
+// find returns the position of the key with the path or, when it is not
+// known, of its closest ancestor.
+func (p positions) find(path string) (line, column int) {
+	for path != "" {
+		if pos, ok := p[path]; ok {
+			return pos.line, pos.column
+		}
+		i := strings.LastIndexAny(path, ".[")
+		if i < 0 {
+			break
+		}
+		path = path[:i]
+	}
+	return 0, 0
+}
+
+// keyPattern matches a key of a block mapping and the rest of the line.
+var keyPattern = regexp.MustCompile(`^("[^"]*"|'[^']*'|[^\s"'#:\-{\[][^:#]*?|-[^\s:#][^:#]*?)\s*:(\s+(.*))?$`)
+
+// frame is a mapping key or a sequence item of the document which the
+// following lines may be nested in.
+type frame struct {
+	path string
+	// column is the column of the key or of the dash of the item.
+	column int
+	// content is the column of the content of an item, -1 for a key.
+	content int
+}
+
+// keyPositions returns the positions of the keys of the block mappings and of
+// the items of the block sequences of the document. The keys of flow
+// mappings, e.g. {a: b}, are not located.
+func keyPositions(data []byte) positions {
+	pos := positions{}
+	items := map[string]int{}
+	stack := []frame{{column: -1, content: -1}}
+	blockIndent := -1
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), " \t\r")
+		rest := strings.TrimLeft(text, " ")
+		col := len(text) - len(rest)
+		if blockIndent >= 0 {
+			if rest == "" || col > blockIndent {
+				continue
+			}
+			blockIndent = -1
+		}
+		if rest == "" || strings.HasPrefix(rest, "#") || rest == "---" || rest == "..." {
+			continue
+		}
+
+		for {
+			if rest == "-" || strings.HasPrefix(rest, "- ") {
+				for len(stack) > 1 {
+					top := stack[len(stack)-1]
+					if (top.content < 0 && top.column > col) || (top.content >= 0 && top.column >= col) {
+						stack = stack[:len(stack)-1]
+						continue
+					}
+					break
+				}
+				parent := stack[len(stack)-1].path
+				index := items[parent]
+				items[parent] = index + 1
+				item := strings.TrimLeft(strings.TrimPrefix(rest, "-"), " ")
+				content := col + len(rest) - len(item)
+				path := fmt.Sprintf("%s[%d]", parent, index)
+				pos[path] = position{line: line, column: content + 1}
+				stack = append(stack, frame{path: path, column: col, content: content})
+				rest, col = item, content
+				if rest == "" {
+					break
+				}
+				continue
+			}
+
+			match := keyPattern.FindStringSubmatch(rest)
+			if match == nil {
+				break
+			}
+			for len(stack) > 1 {
+				top := stack[len(stack)-1]
+				if (top.content < 0 && top.column >= col) || (top.content >= 0 && top.content > col) {
+					stack = stack[:len(stack)-1]
+					continue
+				}
+				break
+			}
+			key := strings.Trim(match[1], `"'`)
+			path := join(stack[len(stack)-1].path, key)
+			pos[path] = position{line: line, column: col + 1}
+
+			value := match[3]
+			switch {
+			case value == "" || strings.HasPrefix(value, "#"):
+				stack = append(stack, frame{path: path, column: col, content: -1})
+			case strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">"):
+				blockIndent = col
+			}
+			break
+		}
+	}
+	return pos
+}
@@ -0,0 +1,328 @@
+// Package strictyaml decodes YAML documents into the API types of the
+// installer, rejecting the fields the types do not have. Each unknown field is
+// reported with its position in the document and, when the type has a field
+// with a close name or the field belongs to another level of the document,
+// with a suggestion.
+package strictyaml
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	yamlv2 "gopkg.in/yaml.v2"
+)
+
+// UnknownField is a field of the document which the type does not have.
+type UnknownField struct {
+	// Path is the path of the field in the document, e.g. compute[0].name.
+	Path string
+	// Line and Column are the position of the field in the document, or
+	// zero when it is not known, e.g. in a flow mapping.
+	Line   int
+	Column int
+	// Suggestion is the path of the field which was likely meant, if any.
+	Suggestion string
+}
+
+func (f UnknownField) Error() string {
+	msg := fmt.Sprintf("unknown field %q", f.Path)
+	if f.Line > 0 {
+		msg = fmt.Sprintf("line %d, column %d: %s", f.Line, f.Column, msg)
+	}
+	if f.Suggestion != "" {
+		msg = fmt.Sprintf("%s, did you mean %q?", msg, f.Suggestion)
+	}
+	return msg
+}
+
+// UnknownFieldsError is returned when the document has unknown fields.
+type UnknownFieldsError []UnknownField
+
+func (e UnknownFieldsError) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, f := range e {
+		msgs = append(msgs, f.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unmarshal decodes the YAML document into the object, as yaml.UnmarshalStrict
+// does, and returns an UnknownFieldsError listing all of the fields of the
+// document the object does not have. Unlike the JSON decoding, the names of
+// the fields are case-sensitive.
+func Unmarshal(data []byte, obj interface{}) error {
+	unknown, err := check(data, obj)
+	if err != nil {
+		return err
+	}
+	if len(unknown) > 0 {
+		return unknown
+	}
+	return yaml.UnmarshalStrict(data, obj, yaml.DisallowUnknownFields)
+}
+
+// UnmarshalIgnoringUnknownFields decodes the YAML document into the object,
+// as yaml.UnmarshalStrict does without disallowing the unknown fields, and
+// returns the fields of the document the object does not have, which Unmarshal
+// would reject. As the JSON decoding matches the names of the fields
+// case-insensitively, the fields which differ from those of the object only
+// by their case are decoded nonetheless.
+func UnmarshalIgnoringUnknownFields(data []byte, obj interface{}) (UnknownFieldsError, error) {
+	unknown, err := check(data, obj)
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.UnmarshalStrict(data, obj); err != nil {
+		return nil, err
+	}
+	return unknown, nil
+}
+
+// check returns the fields of the YAML document the object does not have,
+// sorted by their positions.
+func check(data []byte, obj interface{}) (UnknownFieldsError, error) {
+	var doc interface{}
+	if err := yamlv2.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	t := reflect.TypeOf(obj)
+	unknown := unknownFields(doc, t, "")
+	if len(unknown) == 0 {
+		return nil, nil
+	}
+	positions := keyPositions(data)
+	for i := range unknown {
+		unknown[i].Line, unknown[i].Column = positions.find(unknown[i].Path)
+		if unknown[i].Suggestion == "" {
+			unknown[i].Suggestion = fieldElsewhere(t, lastKey(unknown[i].Path))
+		}
+	}
+	sort.SliceStable(unknown, func(i, j int) bool {
+		return unknown[i].Line < unknown[j].Line
+	})
+	return UnknownFieldsError(unknown), nil
+}
+
+var (
+	jsonUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// leaf returns whether the fields of the type are not checked: the types
+// which decode themselves and the ones which accept any value.
+func leaf(t reflect.Type) bool {
+	if t.Kind() == reflect.Interface {
+		return true
+	}
+	pt := reflect.PtrTo(t)
+	return t.Implements(jsonUnmarshaler) || pt.Implements(jsonUnmarshaler) || t.Implements(textUnmarshaler) || pt.Implements(textUnmarshaler)
+}
+
+// fields returns the fields of the struct by their JSON names, including the
+// ones of its inlined structs.
+func fields(t reflect.Type) map[string]reflect.Type {
+	fs := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name == "-" || (f.PkgPath != "" && !f.Anonymous) {
+			continue
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct && !leaf(ft) {
+			for n, t := range fields(ft) {
+				if _, ok := fs[n]; !ok {
+					fs[n] = t
+				}
+			}
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fs[name] = f.Type
+	}
+	return fs
+}
+
+// unknownFields returns the fields of the value, decoded from YAML, which the
+// type does not have.
+func unknownFields(value interface{}, t reflect.Type, path string) []UnknownField {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if value == nil || leaf(t) {
+		return nil
+	}
+
+	var unknown []UnknownField
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return nil
+		}
+		fs := fields(t)
+		for _, k := range sortedKeys(m) {
+			ft, ok := fs[k]
+			if !ok {
+				f := UnknownField{Path: join(path, k)}
+				if name := closestName(k, fs); name != "" {
+					f.Suggestion = join(path, name)
+				}
+				unknown = append(unknown, f)
+				continue
+			}
+			unknown = append(unknown, unknownFields(m[k], ft, join(path, k))...)
+		}
+	case reflect.Map:
+		m, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return nil
+		}
+		for _, k := range sortedKeys(m) {
+			unknown = append(unknown, unknownFields(m[k], t.Elem(), join(path, k))...)
+		}
+	case reflect.Slice, reflect.Array:
+		s, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+		for i, v := range s {
+			unknown = append(unknown, unknownFields(v, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return unknown
+}
+
+func sortedKeys(m map[interface{}]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, fmt.Sprint(k))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// lastKey returns the last key of the path.
+func lastKey(path string) string {
+	return path[strings.LastIndex(path, ".")+1:]
+}
+
+// closestName returns the name of the field which differs from the key only
+// by its case or by at most two edits, and one edit for every three
+// characters of the key, if any.
+func closestName(key string, fs map[string]reflect.Type) string {
+	best, bestDistance := "", 3
+	for name := range fs {
+		if strings.EqualFold(name, key) {
+			return name
+		}
+		d := distance(strings.ToLower(key), strings.ToLower(name))
+		if d > len(key)/3 {
+			continue
+		}
+		if d < bestDistance || (d == bestDistance && name < best) {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// distance returns the edit distance of the strings, where the transposition
+// of two adjacent characters is a single edit.
+func distance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// maxMisplacedDepth is the depth of the fields of the type searched for a
+// misplaced field.
+const maxMisplacedDepth = 4
+
+// fieldElsewhere returns the shortest path of a field of the type with the
+// name, for a field of the document at the wrong level. The items of the
+// lists are written [*].
+func fieldElsewhere(t reflect.Type, name string) string {
+	type level struct {
+		t    reflect.Type
+		path string
+	}
+	levels := []level{{t: t}}
+	for depth := 0; depth < maxMisplacedDepth && len(levels) > 0; depth++ {
+		var next []level
+		var found []string
+		for _, l := range levels {
+			ft := l.t
+			for ft.Kind() == reflect.Ptr || ft.Kind() == reflect.Slice || ft.Kind() == reflect.Array || ft.Kind() == reflect.Map {
+				if ft.Kind() == reflect.Slice || ft.Kind() == reflect.Array {
+					l.path += "[*]"
+				} else if ft.Kind() == reflect.Map {
+					l.path += ".*"
+				}
+				ft = ft.Elem()
+			}
+			if ft.Kind() != reflect.Struct || leaf(ft) {
+				continue
+			}
+			for n, nt := range fields(ft) {
+				if n == name {
+					found = append(found, join(l.path, n))
+				}
+				next = append(next, level{t: nt, path: join(l.path, n)})
+			}
+		}
+		if len(found) > 0 {
+			sort.Strings(found)
+			return found[0]
+		}
+		levels = next
+	}
+	return ""
+}
@@ -0,0 +1,201 @@
+package strictyaml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testNetwork struct {
+	CIDR string `json:"cidr"`
+}
+
+type testHost struct {
+	Name     string            `json:"name"`
+	Role     string            `json:"role,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Networks []testNetwork     `json:"networks,omitempty"`
+}
+
+type testPlatform struct {
+	Hosts []testHost `json:"hosts,omitempty"`
+}
+
+type testMeta struct {
+	Kind string `json:"kind,omitempty"`
+}
+
+type testConfig struct {
+	testMeta     `json:",inline"`
+	Name         string                 `json:"name"`
+	ControlPlane *testHost              `json:"controlPlane,omitempty"`
+	Platform     testPlatform           `json:"platform"`
+	Extra        map[string]interface{} `json:"extra,omitempty"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	cases := []struct {
+		name          string
+		data          string
+		expected      *testConfig
+		expectedError string
+	}{
+		{
+			name: "known fields",
+			data: `kind: Test
+name: test
+controlPlane:
+  name: master
+platform:
+  hosts:
+  - name: host-0
+    labels:
+      any: value
+    networks:
+    - cidr: 10.0.0.0/16
+extra:
+  any:
+    field: value
+`,
+			expected: &testConfig{
+				testMeta:     testMeta{Kind: "Test"},
+				Name:         "test",
+				ControlPlane: &testHost{Name: "master"},
+				Platform: testPlatform{Hosts: []testHost{{
+					Name:     "host-0",
+					Labels:   map[string]string{"any": "value"},
+					Networks: []testNetwork{{CIDR: "10.0.0.0/16"}},
+				}}},
+				Extra: map[string]interface{}{"any": map[string]interface{}{"field": "value"}},
+			},
+		},
+		{
+			name: "field with the wrong case",
+			data: `name: test
+controlplane:
+  name: master
+`,
+			expectedError: `^line 2, column 1: unknown field "controlplane", did you mean "controlPlane"\?$`,
+		},
+		{
+			name: "misspelled field in a sequence",
+			data: `name: test
+platform:
+  hosts:
+  - name: host-0
+  - name: host-1
+    roel: master
+`,
+			expectedError: `^line 6, column 5: unknown field "platform\.hosts\[1\]\.roel", did you mean "platform\.hosts\[1\]\.role"\?$`,
+		},
+		{
+			name: "misplaced field",
+			data: `name: test
+hosts:
+- name: host-0
+`,
+			expectedError: `^line 2, column 1: unknown field "hosts", did you mean "platform\.hosts"\?$`,
+		},
+		{
+			name: "unknown fields in a compact and a flow sequence",
+			data: `name: test
+platform:
+  hosts:
+    - name: host-0
+      networks:
+      - cidr: 10.0.0.0/16
+        gateway: 10.0.0.1
+    - {name: host-1, other: value}
+`,
+			expectedError: `^line 7, column 9: unknown field "platform\.hosts\[0\]\.networks\[0\]\.gateway"; line 8, column 7: unknown field "platform\.hosts\[1\]\.other"$`,
+		},
+		{
+			name: "unknown field after a block scalar",
+			data: `name: |
+  unknown: not a field
+foo: bar
+`,
+			expectedError: `^line 3, column 1: unknown field "foo"$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &testConfig{}
+			err := Unmarshal([]byte(tc.data), config)
+			if tc.expectedError == "" {
+				if assert.NoError(t, err) {
+					assert.Equal(t, tc.expected, config)
+				}
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestUnmarshalIgnoringUnknownFields(t *testing.T) {
+	cases := []struct {
+		name            string
+		data            string
+		expected        *testConfig
+		expectedUnknown string
+		expectedError   string
+	}{
+		{
+			name: "known fields",
+			data: `name: test
+controlPlane:
+  name: master
+`,
+			expected: &testConfig{Name: "test", ControlPlane: &testHost{Name: "master"}},
+		},
+		{
+			name: "unknown fields",
+			data: `name: test
+platform:
+  hosts:
+  - name: host-0
+    roel: master
+foo: bar
+`,
+			expected:        &testConfig{Name: "test", Platform: testPlatform{Hosts: []testHost{{Name: "host-0"}}}},
+			expectedUnknown: `^line 5, column 5: unknown field "platform\.hosts\[0\]\.roel", did you mean "platform\.hosts\[0\]\.role"\?; line 6, column 1: unknown field "foo"$`,
+		},
+		{
+			// the JSON decoding matches the names case-insensitively
+			name: "field with the wrong case",
+			data: `name: test
+controlplane:
+  name: master
+`,
+			expected:        &testConfig{Name: "test", ControlPlane: &testHost{Name: "master"}},
+			expectedUnknown: `^line 2, column 1: unknown field "controlplane", did you mean "controlPlane"\?$`,
+		},
+		{
+			name: "duplicate field",
+			data: `name: test
+name: other
+`,
+			expectedError: `key "name" already set in map`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &testConfig{}
+			unknown, err := UnmarshalIgnoringUnknownFields([]byte(tc.data), config)
+			if tc.expectedError != "" {
+				assert.Regexp(t, tc.expectedError, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.expected, config)
+			if tc.expectedUnknown == "" {
+				assert.Empty(t, unknown)
+			} else {
+				assert.Regexp(t, tc.expectedUnknown, unknown)
+			}
+		})
+	}
+}
@@ -56,14 +56,12 @@ func unpackAndInit(dir string, platform string, target string, terraformDir stri
 		return errors.Wrap(err, "failed to write versions.tf files")
 	}
 
-	tf, err := newTFExec(dir, terraformDir)
+	// terraform does not reach the platform, so the credentials are not needed
+	tf, err := newTFExec(dir, terraformDir, nil, nil)
 	if err != nil {
 		return errors.Wrap(err, "failed to create a new tfexec")
 	}
 
-	// Explicitly specify the CLI config file to use so that we control the providers that are used.
-	os.Setenv("TF_CLI_CONFIG_FILE", filepath.Join(dir, "terraform.rc"))
-
 	return errors.Wrap(
 		tf.Init(context.Background(), tfexec.PluginDir(filepath.Join(terraformDir, "plugins"))),
 		"failed doing terraform init",
@@ -1,9 +1,90 @@
 package terraform
 
 import (
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
 	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/metrics/progress"
 )
 
+// resourceCreatedRegexp matches the line terraform apply prints when it
+// has created a resource, capturing the address of the resource and how
+// long creating it took.
+var resourceCreatedRegexp = regexp.MustCompile(`^(\S+): Creation complete after (\S+)`)
+
+// slowestResources is how many of the resources which took the longest to
+// create are logged after an apply.
+const slowestResources = 5
+
+// resourceTimes records how long terraform took to create each resource.
+type resourceTimes struct {
+	mu    sync.Mutex
+	times map[string]time.Duration
+}
+
+// debugWithProgress logs the terraform output at the debug level and
+// reports the resources it created as progress events, recording how long
+// they took when r is not nil.
+func (r *resourceTimes) debugWithProgress(args ...interface{}) {
+	logrus.Debug(args...)
+	m := resourceCreatedRegexp.FindStringSubmatch(fmt.Sprint(args...))
+	if m == nil {
+		return
+	}
+	duration, _ := time.ParseDuration(m[2])
+	progress.ResourceCreated(m[1], duration)
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.times == nil {
+		r.times = map[string]time.Duration{}
+	}
+	r.times[m[1]] = duration
+}
+
+// slowest returns the n resources which took the longest to create, slowest
+// first.
+func (r *resourceTimes) slowest(n int) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	resources := make([]string, 0, len(r.times))
+	for resource := range r.times {
+		resources = append(resources, resource)
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		if r.times[resources[i]] != r.times[resources[j]] {
+			return r.times[resources[i]] > r.times[resources[j]]
+		}
+		return resources[i] < resources[j]
+	})
+	if len(resources) > n {
+		resources = resources[:n]
+	}
+	return resources
+}
+
+// logSlowest logs the resources of the stage which took the longest to
+// create at the debug level.
+func (r *resourceTimes) logSlowest(stage string) {
+	resources := r.slowest(slowestResources)
+	if len(resources) == 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	logrus.Debugf("Slowest resources of the %s stage:", stage)
+	for _, resource := range resources {
+		logrus.Debugf("  %s: %s", resource, r.times[resource])
+	}
+}
+
 type printfer struct {
 	logger *logrus.Logger
 	level  logrus.Level
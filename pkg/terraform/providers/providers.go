@@ -2,8 +2,10 @@ package providers
 
 import (
 	"embed"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -125,6 +127,32 @@ func unpackFile(srcPath, destPath string) error {
 	return nil
 }
 
+// Versions are the upstream versions of the terraform binary and providers
+// embedded in the installer.
+type Versions struct {
+	// Terraform is the version of the terraform binary.
+	Terraform string `json:"terraform"`
+	// Providers are the versions of the providers, by provider name.
+	Providers map[string]string `json:"providers"`
+}
+
+// EmbeddedVersions returns the versions recorded in the mirror by
+// hack/build.sh, or nil if the installer was built without them.
+func EmbeddedVersions() (*Versions, error) {
+	data, err := mirror.ReadFile("mirror/versions.json")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	versions := &Versions{}
+	if err := json.Unmarshal(data, versions); err != nil {
+		return nil, errors.Wrap(err, "failed to parse the embedded terraform versions")
+	}
+	return versions, nil
+}
+
 // UnpackTerraformBinary unpacks the terraform binary from the embedded data so that it can be run to create the
 // infrastructure for the cluster.
 func UnpackTerraformBinary(dir string) error {
@@ -0,0 +1,132 @@
+package terraform
+
+import (
+	"github.com/pkg/errors"
+)
+
+// dependencies returns the indexes of the stages each stage depends on,
+// directly or not, in the order of the stages.
+func dependencies(stages []Stage) ([][]int, error) {
+	index := make(map[string]int, len(stages))
+	deps := make([][]int, len(stages))
+	for i, stage := range stages {
+		var names []string
+		if dependent, ok := stage.(DependentStage); ok {
+			names = dependent.Dependencies()
+		}
+		if names == nil {
+			for j := 0; j < i; j++ {
+				names = append(names, stages[j].Name())
+			}
+		}
+
+		set := map[int]bool{}
+		for _, name := range names {
+			j, ok := index[name]
+			if !ok {
+				return nil, errors.Errorf("stage %q depends on %q, which is not a stage before it", stage.Name(), name)
+			}
+			set[j] = true
+			for _, k := range deps[j] {
+				set[k] = true
+			}
+		}
+		for j := 0; j < i; j++ {
+			if set[j] {
+				deps[i] = append(deps[i], j)
+			}
+		}
+		index[stage.Name()] = i
+	}
+	return deps, nil
+}
+
+// StageDependencies returns the names of the stages each stage depends on,
+// directly or not, by the name of the stage.
+func StageDependencies(stages []Stage) (map[string][]string, error) {
+	deps, err := dependencies(stages)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string][]string, len(stages))
+	for i, stage := range stages {
+		names[stage.Name()] = []string{}
+		for _, j := range deps[i] {
+			names[stage.Name()] = append(names[stage.Name()], stages[j].Name())
+		}
+	}
+	return names, nil
+}
+
+// RunStages calls run for each stage, with the stages it depends on directly
+// or not, once they are done. At most parallelism stages are run at a time.
+// No stage is started once one fails, and the error of the earliest of the
+// stages which failed is returned once the running stages are done.
+func RunStages(stages []Stage, parallelism int, run func(stage Stage, dependencies []Stage) error) error {
+	deps, err := dependencies(stages)
+	if err != nil {
+		return err
+	}
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	type result struct {
+		index int
+		err   error
+	}
+	results := make(chan result)
+	started := make([]bool, len(stages))
+	done := make([]bool, len(stages))
+	errs := make([]error, len(stages))
+	running := 0
+	failed := false
+
+	ready := func(i int) bool {
+		for _, j := range deps[i] {
+			if !done[j] {
+				return false
+			}
+		}
+		return true
+	}
+
+	for {
+		for i := range stages {
+			if failed || running >= parallelism {
+				break
+			}
+			if started[i] || !ready(i) {
+				continue
+			}
+			started[i] = true
+			running++
+			stageDeps := make([]Stage, 0, len(deps[i]))
+			for _, j := range deps[i] {
+				stageDeps = append(stageDeps, stages[j])
+			}
+			go func(i int) {
+				results <- result{index: i, err: run(stages[i], stageDeps)}
+			}(i)
+		}
+		if running == 0 {
+			break
+		}
+
+		r := <-results
+		running--
+		if r.err != nil {
+			errs[r.index] = r.err
+			failed = true
+		} else {
+			done[r.index] = true
+		}
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
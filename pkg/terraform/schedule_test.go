@@ -0,0 +1,142 @@
+package terraform
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/terraform/providers"
+	"github.com/openshift/installer/pkg/types"
+)
+
+type fakeStage struct {
+	name         string
+	dependencies []string
+}
+
+func (s fakeStage) Name() string                                            { return s.name }
+func (s fakeStage) StateFilename() string                                   { return s.name + ".tfstate" }
+func (s fakeStage) OutputsFilename() string                                 { return s.name + ".tfvars.json" }
+func (s fakeStage) Providers() []providers.Provider                         { return nil }
+func (s fakeStage) DestroyWithBootstrap() bool                              { return false }
+func (s fakeStage) Destroy(context.Context, string, string, []string) error { return nil }
+func (s fakeStage) Dependencies() []string                                  { return s.dependencies }
+func (s fakeStage) ExtractHostAddresses(string, *types.InstallConfig) (string, int, []string, error) {
+	return "", 0, nil, nil
+}
+
+func TestRunStages(t *testing.T) {
+	cases := []struct {
+		name   string
+		stages []Stage
+		fail   string
+		// ran is the stages run, with the stages they were given
+		ran map[string][]string
+		err string
+	}{
+		{
+			name:   "sequential by default",
+			stages: []Stage{fakeStage{name: "vnet"}, fakeStage{name: "bootstrap"}, fakeStage{name: "cluster"}},
+			ran: map[string][]string{
+				"vnet":      {},
+				"bootstrap": {"vnet"},
+				"cluster":   {"vnet", "bootstrap"},
+			},
+		},
+		{
+			name: "declared dependencies",
+			stages: []Stage{
+				fakeStage{name: "network", dependencies: []string{}},
+				fakeStage{name: "iam", dependencies: []string{}},
+				fakeStage{name: "bootstrap", dependencies: []string{"network"}},
+				fakeStage{name: "cluster", dependencies: []string{"bootstrap", "iam"}},
+			},
+			ran: map[string][]string{
+				"network":   {},
+				"iam":       {},
+				"bootstrap": {"network"},
+				"cluster":   {"network", "iam", "bootstrap"},
+			},
+		},
+		{
+			name: "failure stops dependent stages",
+			stages: []Stage{
+				fakeStage{name: "network", dependencies: []string{}},
+				fakeStage{name: "bootstrap", dependencies: []string{"network"}},
+			},
+			fail: "network",
+			ran: map[string][]string{
+				"network": {},
+			},
+			err: "network failed",
+		},
+		{
+			name: "unknown dependency",
+			stages: []Stage{
+				fakeStage{name: "cluster", dependencies: []string{"bootstrap"}},
+				fakeStage{name: "bootstrap"},
+			},
+			ran: map[string][]string{},
+			err: `stage "cluster" depends on "bootstrap", which is not a stage before it`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			ran := map[string][]string{}
+			err := RunStages(tc.stages, 2, func(stage Stage, dependencies []Stage) error {
+				names := []string{}
+				for _, dependency := range dependencies {
+					names = append(names, dependency.Name())
+				}
+				mu.Lock()
+				ran[stage.Name()] = names
+				mu.Unlock()
+				if stage.Name() == tc.fail {
+					return errors.Errorf("%s failed", stage.Name())
+				}
+				return nil
+			})
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.ran, ran)
+		})
+	}
+}
+
+func TestRunStagesParallelism(t *testing.T) {
+	stages := []Stage{
+		fakeStage{name: "a", dependencies: []string{}},
+		fakeStage{name: "b", dependencies: []string{}},
+		fakeStage{name: "c", dependencies: []string{}},
+	}
+	release := make(chan struct{})
+	started := make(chan string, len(stages))
+	done := make(chan error)
+	go func() {
+		done <- RunStages(stages, 2, func(stage Stage, _ []Stage) error {
+			started <- stage.Name()
+			<-release
+			return nil
+		})
+	}()
+
+	first := []string{<-started, <-started}
+	sort.Strings(first)
+	assert.Equal(t, []string{"a", "b"}, first)
+	select {
+	case name := <-started:
+		t.Fatalf("%s started beyond the parallelism", name)
+	default:
+	}
+	close(release)
+	assert.NoError(t, <-done)
+	assert.Equal(t, "c", <-started)
+}
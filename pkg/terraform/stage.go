@@ -1,6 +1,8 @@
 package terraform
 
 import (
+	"context"
+
 	"github.com/openshift/installer/pkg/terraform/providers"
 	"github.com/openshift/installer/pkg/types"
 )
@@ -24,8 +26,17 @@ type Stage interface {
 
 	// Destroy destroys the resources created in the stage. This should only be called if the stage should be destroyed
 	// when destroying the bootstrap resources.
-	Destroy(directory string, terraformDir string, varFiles []string) error
+	Destroy(ctx context.Context, directory string, terraformDir string, varFiles []string) error
 
 	// ExtractHostAddresses extracts the IPs of the bootstrap and control plane machines.
 	ExtractHostAddresses(directory string, config *types.InstallConfig) (bootstrap string, port int, masters []string, err error)
 }
+
+// DependentStage is implemented by the stages which declare the stages whose
+// outputs they consume, so that they can be applied alongside the other
+// stages. A stage which does not, or which declares nil, depends on all the
+// stages before it.
+type DependentStage interface {
+	// Dependencies is the names of the stages the stage depends on.
+	Dependencies() []string
+}
@@ -18,12 +18,16 @@ var PlatformStages = []terraform.Stage{
 		typesazure.Name,
 		"bootstrap",
 		[]providers.Provider{providers.AzureRM, providers.Ignition, providers.Local},
+		// the bootstrap VM and the control plane are independent of each other
+		stages.WithDependencies("vnet"),
 		stages.WithNormalBootstrapDestroy(),
 	),
 	stages.NewStage(
 		typesazure.Name,
 		"cluster",
 		[]providers.Provider{providers.AzureRM, providers.Time},
+		// the control plane only consumes the network and load balancers of vnet
+		stages.WithDependencies("vnet"),
 	),
 }
 
@@ -38,11 +42,15 @@ var StackPlatformStages = []terraform.Stage{
 		typesazure.StackTerraformName,
 		"bootstrap",
 		[]providers.Provider{providers.AzureStack, providers.Ignition, providers.Local},
+		// the bootstrap VM and the control plane are independent of each other
+		stages.WithDependencies("vnet"),
 		stages.WithNormalBootstrapDestroy(),
 	),
 	stages.NewStage(
 		typesazure.StackTerraformName,
 		"cluster",
 		[]providers.Provider{providers.AzureStack},
+		// the control plane only consumes the network and load balancers of vnet
+		stages.WithDependencies("vnet"),
 	),
 }
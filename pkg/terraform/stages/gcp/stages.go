@@ -1,6 +1,8 @@
 package gcp
 
 import (
+	"context"
+
 	"github.com/hashicorp/terraform-exec/tfexec"
 	"github.com/pkg/errors"
 
@@ -31,14 +33,14 @@ var PlatformStages = []terraform.Stage{
 	),
 }
 
-func removeFromLoadBalancers(s stages.SplitStage, directory string, terraformDir string, varFiles []string) error {
+func removeFromLoadBalancers(ctx context.Context, s stages.SplitStage, directory string, terraformDir string, varFiles []string) error {
 	opts := make([]tfexec.ApplyOption, 0, len(varFiles)+1)
 	for _, varFile := range varFiles {
 		opts = append(opts, tfexec.VarFile(varFile))
 	}
 	opts = append(opts, tfexec.Var("gcp_bootstrap_lb=false"))
 	return errors.Wrap(
-		terraform.Apply(directory, gcptypes.Name, s, terraformDir, opts...),
+		terraform.Apply(ctx, directory, gcptypes.Name, s, terraformDir, opts...),
 		"failed disabling bootstrap load balancing",
 	)
 }
@@ -17,11 +17,15 @@ var PlatformStages = []terraform.Stage{
 		"ibmcloud",
 		"bootstrap",
 		[]providers.Provider{providers.IBM},
+		// the bootstrap instance and the control plane are independent of each other
+		stages.WithDependencies("network"),
 		stages.WithNormalBootstrapDestroy(),
 	),
 	stages.NewStage(
 		"ibmcloud",
 		"master",
 		[]providers.Provider{providers.IBM},
+		// the control plane only consumes the network, image and load balancers of network
+		stages.WithDependencies("network"),
 	),
 }
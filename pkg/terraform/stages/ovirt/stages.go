@@ -34,12 +34,16 @@ var PlatformStages = []terraform.Stage{
 		ovirttypes.Name,
 		"cluster",
 		[]providers.Provider{providers.OVirt},
+		// the control plane only consumes the template of image
+		stages.WithDependencies("image"),
 		stages.WithCustomExtractHostAddresses(extractOutputHostAddresses),
 	),
 	stages.NewStage(
 		ovirttypes.Name,
 		"bootstrap",
 		[]providers.Provider{providers.OVirt},
+		// the bootstrap VM and the control plane are independent of each other
+		stages.WithDependencies("image"),
 		stages.WithNormalBootstrapDestroy(),
 		stages.WithCustomExtractHostAddresses(extractOutputHostAddresses),
 	),
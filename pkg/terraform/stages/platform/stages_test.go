@@ -0,0 +1,60 @@
+package platform
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/terraform"
+	azuretypes "github.com/openshift/installer/pkg/types/azure"
+	ibmcloudtypes "github.com/openshift/installer/pkg/types/ibmcloud"
+	ovirttypes "github.com/openshift/installer/pkg/types/ovirt"
+	vspheretypes "github.com/openshift/installer/pkg/types/vsphere"
+)
+
+func TestIndependentStagesOverlap(t *testing.T) {
+	cases := []struct {
+		platform    string
+		independent []string
+	}{
+		{platform: azuretypes.Name, independent: []string{"bootstrap", "cluster"}},
+		{platform: azuretypes.StackTerraformName, independent: []string{"bootstrap", "cluster"}},
+		{platform: ibmcloudtypes.Name, independent: []string{"bootstrap", "master"}},
+		{platform: ovirttypes.Name, independent: []string{"cluster", "bootstrap"}},
+		{platform: vspheretypes.Name, independent: []string{"bootstrap", "master"}},
+		{platform: vspheretypes.ZoningTerraformName, independent: []string{"bootstrap", "master"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.platform, func(t *testing.T) {
+			var mu sync.Mutex
+			running := map[string]bool{}
+			overlapping := make(chan struct{})
+			err := terraform.RunStages(StagesForPlatform(tc.platform), 4, func(stage terraform.Stage, _ []terraform.Stage) error {
+				independent := false
+				for _, name := range tc.independent {
+					independent = independent || name == stage.Name()
+				}
+				if !independent {
+					return nil
+				}
+				mu.Lock()
+				running[stage.Name()] = true
+				if len(running) == len(tc.independent) {
+					close(overlapping)
+				}
+				mu.Unlock()
+				// each independent stage waits for the others to be running
+				select {
+				case <-overlapping:
+					return nil
+				case <-time.After(5 * time.Second):
+					return errors.Errorf("the %q stage was not run alongside %v", stage.Name(), tc.independent)
+				}
+			})
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package powervs
 
 import (
+	"context"
+
 	"github.com/hashicorp/terraform-exec/tfexec"
 	"github.com/pkg/errors"
 
@@ -25,14 +27,14 @@ var PlatformStages = []terraform.Stage{
 		stages.WithCustomBootstrapDestroy(removeFromLoadBalancers)),
 }
 
-func removeFromLoadBalancers(s stages.SplitStage, directory string, terraformDir string, varFiles []string) error {
+func removeFromLoadBalancers(ctx context.Context, s stages.SplitStage, directory string, terraformDir string, varFiles []string) error {
 	opts := make([]tfexec.ApplyOption, 0, len(varFiles)+1)
 	for _, varFile := range varFiles {
 		opts = append(opts, tfexec.VarFile(varFile))
 	}
 	opts = append(opts, tfexec.Var("powervs_expose_bootstrap=false"))
 	return errors.Wrap(
-		terraform.Apply(directory, powervstypes.Name, s, terraformDir, opts...),
+		terraform.Apply(ctx, directory, powervstypes.Name, s, terraformDir, opts...),
 		"failed disabling bootstrap load balancing",
 	)
 }
@@ -1,6 +1,7 @@
 package stages
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -57,18 +58,27 @@ func WithCustomExtractHostAddresses(extractHostAddresses ExtractFunc) StageOptio
 	}
 }
 
+// WithDependencies returns an option for specifying the stages whose outputs a split stage consumes, so that it is
+// applied alongside the other stages. By default a split stage depends on all the stages before it.
+func WithDependencies(names ...string) StageOption {
+	return func(s *SplitStage) {
+		s.dependencies = append([]string{}, names...)
+	}
+}
+
 // SplitStage is a split stage.
 type SplitStage struct {
 	platform             string
 	name                 string
 	providers            []providers.Provider
+	dependencies         []string
 	destroyWithBootstrap bool
 	destroy              DestroyFunc
 	extractHostAddresses ExtractFunc
 }
 
 // DestroyFunc is a function for destroying the stage.
-type DestroyFunc func(s SplitStage, directory string, terraformDir string, varFiles []string) error
+type DestroyFunc func(ctx context.Context, s SplitStage, directory string, terraformDir string, varFiles []string) error
 
 // ExtractFunc is a function for extracting host addresses.
 type ExtractFunc func(s SplitStage, directory string, ic *types.InstallConfig) (string, int, []string, error)
@@ -83,6 +93,11 @@ func (s SplitStage) Providers() []providers.Provider {
 	return s.providers
 }
 
+// Dependencies implements pkg/terraform/DependentStage.Dependencies
+func (s SplitStage) Dependencies() []string {
+	return s.dependencies
+}
+
 // StateFilename implements pkg/terraform/Stage.StateFilename
 func (s SplitStage) StateFilename() string {
 	return fmt.Sprintf("terraform.%s.tfstate", s.name)
@@ -99,8 +114,8 @@ func (s SplitStage) DestroyWithBootstrap() bool {
 }
 
 // Destroy implements pkg/terraform/Stage.Destroy
-func (s SplitStage) Destroy(directory string, terraformDir string, varFiles []string) error {
-	return s.destroy(s, directory, terraformDir, varFiles)
+func (s SplitStage) Destroy(ctx context.Context, directory string, terraformDir string, varFiles []string) error {
+	return s.destroy(ctx, s, directory, terraformDir, varFiles)
 }
 
 // ExtractHostAddresses implements pkg/terraform/Stage.ExtractHostAddresses
@@ -164,10 +179,10 @@ func normalExtractHostAddresses(s SplitStage, directory string, _ *types.Install
 	return bootstrap, 0, masters, nil
 }
 
-func normalDestroy(s SplitStage, directory string, terraformDir string, varFiles []string) error {
+func normalDestroy(ctx context.Context, s SplitStage, directory string, terraformDir string, varFiles []string) error {
 	opts := make([]tfexec.DestroyOption, len(varFiles))
 	for i, varFile := range varFiles {
 		opts[i] = tfexec.VarFile(varFile)
 	}
-	return errors.Wrap(terraform.Destroy(directory, s.platform, s, terraformDir, opts...), "terraform destroy")
+	return errors.Wrap(terraform.Destroy(ctx, directory, s.platform, s, terraformDir, opts...), "terraform destroy")
 }
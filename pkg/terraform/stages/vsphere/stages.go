@@ -27,6 +27,8 @@ var PlatformStages = []terraform.Stage{
 		"vsphere",
 		"bootstrap",
 		[]providers.Provider{providers.VSphere},
+		// the bootstrap VM and the control plane are independent of each other
+		stages.WithDependencies("pre-bootstrap"),
 		stages.WithNormalBootstrapDestroy(),
 		stages.WithCustomExtractHostAddresses(extractOutputHostAddresses),
 	),
@@ -34,6 +36,8 @@ var PlatformStages = []terraform.Stage{
 		"vsphere",
 		"master",
 		[]providers.Provider{providers.VSphere},
+		// the control plane only consumes the folder, tags and template of pre-bootstrap
+		stages.WithDependencies("pre-bootstrap"),
 		stages.WithCustomExtractHostAddresses(extractOutputHostAddresses),
 	),
 }
@@ -50,6 +54,8 @@ var ZoningPlatformStages = []terraform.Stage{
 		"vspherezoning",
 		"bootstrap",
 		[]providers.Provider{providers.VSphere},
+		// the bootstrap VM and the control plane are independent of each other
+		stages.WithDependencies("pre-bootstrap"),
 		stages.WithNormalBootstrapDestroy(),
 		stages.WithCustomExtractHostAddresses(extractOutputHostAddresses),
 	),
@@ -57,6 +63,8 @@ var ZoningPlatformStages = []terraform.Stage{
 		"vspherezoning",
 		"master",
 		[]providers.Provider{providers.VSphere},
+		// the control plane only consumes the folder, tags and template of pre-bootstrap
+		stages.WithDependencies("pre-bootstrap"),
 		stages.WithCustomExtractHostAddresses(extractOutputHostAddresses),
 	),
 }
@@ -12,7 +12,8 @@ const StateFilename = "terraform.tfstate"
 
 // Outputs reads the terraform state file and returns the outputs of the stage as json.
 func Outputs(dir string, terraformDir string) ([]byte, error) {
-	tf, err := newTFExec(dir, terraformDir)
+	// terraform does not reach the platform, so the credentials are not needed
+	tf, err := newTFExec(dir, terraformDir, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -2,23 +2,32 @@ package terraform
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-exec/tfexec"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	"github.com/openshift/installer/pkg/credentialsfile"
 	"github.com/openshift/installer/pkg/lineprinter"
 )
 
+// ParallelismEnv bounds how many resources terraform creates or destroys at
+// a time in a stage, 10 by default.
+const ParallelismEnv = "OPENSHIFT_INSTALL_TERRAFORM_PARALLELISM"
+
 // newTFExec creates a tfexec.Terraform for executing Terraform CLI commands.
 // The `datadir` is the location to which the terraform plan (tf files, etc) has been unpacked.
 // The `terraformDir` is the location to which Terraform, provider binaries, & .terraform data dir have been unpacked.
 // The stdout and stderr will be sent to the logger at the debug and error levels,
-// respectively.
-func newTFExec(datadir string, terraformDir string) (*tfexec.Terraform, error) {
+// respectively, and the creation times of the resources recorded in times, if not nil.
+// The credentials, if not nil, replace those of the environment of terraform.
+func newTFExec(datadir string, terraformDir string, times *resourceTimes, credentials *credentialsfile.File) (*tfexec.Terraform, error) {
 	tfPath := filepath.Join(terraformDir, "bin", "terraform")
 	tf, err := tfexec.NewTerraform(datadir, tfPath)
 	if err != nil {
@@ -45,7 +54,7 @@ func newTFExec(datadir string, terraformDir string) (*tfexec.Terraform, error) {
 	}
 
 	// Add terraform info logs to the installer log
-	lpDebug := &lineprinter.LinePrinter{Print: (&lineprinter.Trimmer{WrappedPrint: logrus.Debug}).Print}
+	lpDebug := &lineprinter.LinePrinter{Print: (&lineprinter.Trimmer{WrappedPrint: times.debugWithProgress}).Print}
 	lpError := &lineprinter.LinePrinter{Print: (&lineprinter.Trimmer{WrappedPrint: logrus.Error}).Print}
 	defer lpDebug.Close()
 	defer lpError.Close()
@@ -57,43 +66,123 @@ func newTFExec(datadir string, terraformDir string) (*tfexec.Terraform, error) {
 	// Set the Terraform data dir to be the same as the terraformDir so that
 	// files we unpack are contained and, more importantly, we can ensure the
 	// provider binaries unpacked in the Terraform data dir have the same permission
-	// levels as the Terraform binary.
-	dd := path.Join(terraformDir, ".terraform")
-	os.Setenv("TF_DATA_DIR", dd)
+	// levels as the Terraform binary. The environment is set per command
+	// rather than for the process, as the stages may be run concurrently.
+	env := map[string]string{}
+	for _, kv := range os.Environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env[k] = v
+		}
+	}
+	for _, k := range tfexec.ProhibitedEnv(env) {
+		delete(env, k)
+	}
+	if err := credentials.ApplyEnvironment(env); err != nil {
+		return nil, err
+	}
+	env["TF_DATA_DIR"] = path.Join(terraformDir, ".terraform")
+	// Explicitly specify the CLI config file to use so that we control the providers that are used.
+	if rc := filepath.Join(datadir, "terraform.rc"); fileExists(rc) {
+		env["TF_CLI_CONFIG_FILE"] = rc
+	}
+	if err := tf.SetEnv(env); err != nil {
+		return nil, err
+	}
 
 	return tf, nil
 }
 
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// parallelism returns the parallelism set by OPENSHIFT_INSTALL_TERRAFORM_PARALLELISM,
+// or 0 when it is not set.
+func parallelism() (int, error) {
+	value := os.Getenv(ParallelismEnv)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, errors.Errorf("invalid %s %q, must be a positive integer", ParallelismEnv, value)
+	}
+	return n, nil
+}
+
 // Apply unpacks the platform-specific Terraform modules into the
 // given directory and then runs 'terraform init' and 'terraform
-// apply'.
-func Apply(dir string, platform string, stage Stage, terraformDir string, extraOpts ...tfexec.ApplyOption) error {
+// apply', which is canceled with the context and runs with its credentials.
+func Apply(ctx context.Context, dir string, platform string, stage Stage, terraformDir string, extraOpts ...tfexec.ApplyOption) error {
 	if err := unpackAndInit(dir, platform, stage.Name(), terraformDir, stage.Providers()); err != nil {
 		return err
 	}
 
-	tf, err := newTFExec(dir, terraformDir)
+	n, err := parallelism()
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		extraOpts = append(extraOpts, tfexec.Parallelism(n))
+	}
+
+	times := &resourceTimes{}
+	tf, err := newTFExec(dir, terraformDir, times, credentialsfile.FromContext(ctx))
 	if err != nil {
 		return errors.Wrap(err, "failed to create a new tfexec")
 	}
-	err = tf.Apply(context.Background(), extraOpts...)
+	err = tf.Apply(ctx, extraOpts...)
+	times.logSlowest(stage.Name())
 	return errors.Wrap(diagnoseApplyError(err), "failed to apply Terraform")
 }
 
+// Plan unpacks the platform-specific Terraform modules into the given
+// directory, runs 'terraform init' and 'terraform plan', and returns the
+// plan as JSON, in the format of 'terraform show -json'.
+func Plan(ctx context.Context, dir string, platform string, stage Stage, terraformDir string, extraOpts ...tfexec.PlanOption) ([]byte, error) {
+	if err := unpackAndInit(dir, platform, stage.Name(), terraformDir, stage.Providers()); err != nil {
+		return nil, err
+	}
+
+	tf, err := newTFExec(dir, terraformDir, nil, credentialsfile.FromContext(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create a new tfexec")
+	}
+	planFile := filepath.Join(dir, "terraform.tfplan")
+	if _, err := tf.Plan(ctx, append(extraOpts, tfexec.Out(planFile))...); err != nil {
+		return nil, errors.Wrap(diagnoseApplyError(err), "failed to plan Terraform")
+	}
+	plan, err := tf.ShowPlanFile(ctx, planFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the Terraform plan")
+	}
+	data, err := json.Marshal(plan)
+	return data, errors.Wrap(err, "failed to marshal the Terraform plan")
+}
+
 // Destroy unpacks the platform-specific Terraform modules into the
 // given directory and then runs 'terraform init' and 'terraform
-// destroy'.
-func Destroy(dir string, platform string, stage Stage, terraformDir string, extraOpts ...tfexec.DestroyOption) error {
+// destroy', which is canceled with the context and runs with its credentials.
+func Destroy(ctx context.Context, dir string, platform string, stage Stage, terraformDir string, extraOpts ...tfexec.DestroyOption) error {
 	if err := unpackAndInit(dir, platform, stage.Name(), terraformDir, stage.Providers()); err != nil {
 		return err
 	}
 
-	tf, err := newTFExec(dir, terraformDir)
+	n, err := parallelism()
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		extraOpts = append(extraOpts, tfexec.Parallelism(n))
+	}
+
+	tf, err := newTFExec(dir, terraformDir, nil, credentialsfile.FromContext(ctx))
 	if err != nil {
 		return errors.Wrap(err, "failed to create a new tfexec")
 	}
 	return errors.Wrap(
-		tf.Destroy(context.Background(), extraOpts...),
+		tf.Destroy(ctx, extraOpts...),
 		"failed doing terraform destroy",
 	)
 }
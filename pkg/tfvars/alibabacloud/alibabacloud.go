@@ -2,13 +2,14 @@ package alibabacloud
 
 import (
 	"encoding/json"
-	"fmt"
 
 	"github.com/pkg/errors"
 
 	machinev1 "github.com/openshift/api/machine/v1"
 	"github.com/openshift/installer/pkg/asset/ignition/bootstrap"
+	"github.com/openshift/installer/pkg/asset/ignition/userdata"
 	"github.com/openshift/installer/pkg/types"
+	typesalibabacloud "github.com/openshift/installer/pkg/types/alibabacloud"
 )
 
 // Auth is the collection of credentials that will be used by terrform.
@@ -107,9 +108,8 @@ func TFVars(sources TFVarsSources) ([]byte, error) {
 		return nil, errors.Wrap(err, "failed to create stub Ignition config for bootstrap")
 	}
 
-	// Check the size of the raw ignition stub is less than 16KB for user-data
-	if len(stubIgn) > 16000 {
-		return nil, fmt.Errorf("rendered bootstrap ignition shim exceeds the 16KB limit for Alibaba Cloud user data -- try reducing the size of your CA cert bundle")
+	if err := userdata.Check(typesalibabacloud.Name, "bootstrap shim", stubIgn); err != nil {
+		return nil, errors.Wrap(err, "try reducing the size of your CA cert bundle")
 	}
 	cfg.BootstrapIgnitionStub = string(stubIgn)
 
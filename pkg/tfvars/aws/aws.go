@@ -3,13 +3,13 @@ package aws
 
 import (
 	"encoding/json"
-	"fmt"
 	"strings"
 
 	"github.com/pkg/errors"
 
 	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
 	"github.com/openshift/installer/pkg/asset/ignition/bootstrap"
+	"github.com/openshift/installer/pkg/asset/ignition/userdata"
 	"github.com/openshift/installer/pkg/types"
 	typesaws "github.com/openshift/installer/pkg/types/aws"
 )
@@ -28,6 +28,8 @@ type config struct {
 	Type                         string            `json:"aws_master_root_volume_type,omitempty"`
 	Encrypted                    bool              `json:"aws_master_root_volume_encrypted"`
 	KMSKeyID                     string            `json:"aws_master_root_volume_kms_key_id,omitempty"`
+	EtcdVolumeSize               int64             `json:"aws_master_etcd_volume_size,omitempty"`
+	EtcdVolumeType               string            `json:"aws_master_etcd_volume_type,omitempty"`
 	Region                       string            `json:"aws_region,omitempty"`
 	VPC                          string            `json:"aws_vpc,omitempty"`
 	PrivateSubnets               []string          `json:"aws_private_subnets,omitempty"`
@@ -142,10 +144,8 @@ func TFVars(sources TFVarsSources) ([]byte, error) {
 		return nil, errors.Wrap(err, "failed to create stub Ignition config for bootstrap")
 	}
 
-	// Check the size of the raw ignition stub is less than 16KB for aws user-data
-	// see https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/instancedata-add-user-data.html
-	if len(stubIgn) > 16000 {
-		return nil, fmt.Errorf("rendered bootstrap ignition shim exceeds the 16KB limit for AWS user data -- try reducing the size of your CA cert bundle")
+	if err := userdata.Check(typesaws.Name, "bootstrap shim", stubIgn); err != nil {
+		return nil, errors.Wrap(err, "try reducing the size of your CA cert bundle")
 	}
 	cfg.BootstrapIgnitionStub = string(stubIgn)
 
@@ -171,6 +171,12 @@ func TFVars(sources TFVarsSources) ([]byte, error) {
 		cfg.KMSKeyID = *rootVolume.EBS.KMSKey.ARN
 	}
 
+	if len(masterConfig.BlockDevices) > 1 {
+		etcdVolume := masterConfig.BlockDevices[1].EBS
+		cfg.EtcdVolumeSize = *etcdVolume.VolumeSize
+		cfg.EtcdVolumeType = *etcdVolume.VolumeType
+	}
+
 	if masterConfig.AMI.ID != nil && *masterConfig.AMI.ID != "" {
 		cfg.AMI = *masterConfig.AMI.ID
 		cfg.AMIRegion = masterConfig.Placement.Region
@@ -21,6 +21,8 @@ type Auth struct {
 	TenantID                  string `json:"azure_tenant_id,omitempty"`
 	ClientCertificatePath     string `json:"azure_certificate_path,omitempty"`
 	ClientCertificatePassword string `json:"azure_certificate_password,omitempty"`
+	UseOIDC                   bool   `json:"azure_use_oidc,omitempty"`
+	OIDCTokenFilePath         string `json:"azure_oidc_token_file_path,omitempty"`
 }
 
 type config struct {
@@ -35,6 +37,8 @@ type config struct {
 	ControlPlaneUltraSSDEnabled     bool              `json:"azure_control_plane_ultra_ssd_enabled"`
 	VolumeType                      string            `json:"azure_master_root_volume_type"`
 	VolumeSize                      int32             `json:"azure_master_root_volume_size"`
+	EtcdVolumeType                  string            `json:"azure_master_etcd_volume_type,omitempty"`
+	EtcdVolumeSize                  int32             `json:"azure_master_etcd_volume_size,omitempty"`
 	ImageURL                        string            `json:"azure_image_url,omitempty"`
 	ImageRelease                    string            `json:"azure_image_release,omitempty"`
 	Region                          string            `json:"azure_region,omitempty"`
@@ -137,6 +141,11 @@ func TFVars(sources TFVarsSources) ([]byte, error) {
 		VMArchitecture:                  vmarch,
 	}
 
+	if len(masterConfig.DataDisks) > 0 {
+		cfg.EtcdVolumeType = string(masterConfig.DataDisks[0].ManagedDisk.StorageAccountType)
+		cfg.EtcdVolumeSize = masterConfig.DataDisks[0].DiskSizeGB
+	}
+
 	return json.MarshalIndent(cfg, "", "  ")
 }
 
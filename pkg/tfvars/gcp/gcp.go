@@ -34,6 +34,8 @@ type config struct {
 	VolumeType              string   `json:"gcp_master_root_volume_type"`
 	VolumeSize              int64    `json:"gcp_master_root_volume_size"`
 	VolumeKMSKeyLink        string   `json:"gcp_root_volume_kms_key_link"`
+	EtcdVolumeType          string   `json:"gcp_master_etcd_volume_type,omitempty"`
+	EtcdVolumeSize          int64    `json:"gcp_master_etcd_volume_size,omitempty"`
 	PublicZoneName          string   `json:"gcp_public_zone_name,omitempty"`
 	PublishStrategy         string   `json:"gcp_publish_strategy,omitempty"`
 	PreexistingNetwork      bool     `json:"gcp_preexisting_network,omitempty"`
@@ -95,6 +97,11 @@ func TFVars(sources TFVarsSources) ([]byte, error) {
 		cfg.PreexistingImage = false
 	}
 
+	if len(masterConfig.Disks) > 1 {
+		cfg.EtcdVolumeType = masterConfig.Disks[1].Type
+		cfg.EtcdVolumeSize = masterConfig.Disks[1].SizeGB
+	}
+
 	if masterConfig.Disks[0].EncryptionKey != nil {
 		cfg.VolumeKMSKeyLink = generateDiskEncryptionKeyLink(masterConfig.Disks[0].EncryptionKey, masterConfig.ProjectID)
 	}
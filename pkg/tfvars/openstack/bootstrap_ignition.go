@@ -2,7 +2,6 @@ package openstack
 
 import (
 	"bytes"
-	"encoding/base64"
 	"encoding/pem"
 	"fmt"
 	"strings"
@@ -12,11 +11,14 @@ import (
 	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/imagedata"
 	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
 	"github.com/gophercloud/utils/openstack/clientconfig"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/vincent-petithory/dataurl"
 
 	"github.com/openshift/installer/pkg/asset/ignition"
+	"github.com/openshift/installer/pkg/asset/ignition/userdata"
 	"github.com/openshift/installer/pkg/types"
+	types_openstack "github.com/openshift/installer/pkg/types/openstack"
 	openstackdefaults "github.com/openshift/installer/pkg/types/openstack/defaults"
 )
 
@@ -168,10 +170,8 @@ func generateIgnitionShim(userCA string, clusterID string, bootstrapConfigURL st
 		return "", err
 	}
 
-	// Check the size of the base64-rendered ignition shim isn't to big for nova
-	// https://docs.openstack.org/nova/latest/user/metadata.html#user-data
-	if len(base64.StdEncoding.EncodeToString(data)) > 65535 {
-		return "", fmt.Errorf("rendered bootstrap ignition shim exceeds the 64KB limit for nova user data -- try reducing the size of your CA cert bundle")
+	if err := userdata.Check(types_openstack.Name, "bootstrap shim", data); err != nil {
+		return "", errors.Wrap(err, "try reducing the size of your CA cert bundle")
 	}
 
 	return string(data), nil
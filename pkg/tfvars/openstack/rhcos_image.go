@@ -14,12 +14,17 @@ import (
 	"github.com/gophercloud/utils/openstack/clientconfig"
 	"github.com/sirupsen/logrus"
 
-	"github.com/openshift/installer/pkg/tfvars/internal/cache"
+	"github.com/openshift/installer/pkg/offline"
+	"github.com/openshift/installer/pkg/rhcos/cache"
 	openstackdefaults "github.com/openshift/installer/pkg/types/openstack/defaults"
 )
 
 // uploadBaseImage creates a new image in Glance and uploads the RHCOS image there
 func uploadBaseImage(cloud string, baseImage, imageName string, infraID string, imageProperties map[string]string) error {
+	if err := offline.Require("Importing the RHCOS image into Glance"); err != nil {
+		return err
+	}
+
 	var localFilePath string
 
 	url, err := url.Parse(baseImage)
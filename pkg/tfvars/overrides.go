@@ -0,0 +1,114 @@
+package tfvars
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/azure"
+	"github.com/openshift/installer/pkg/types/gcp"
+	"github.com/openshift/installer/pkg/types/openstack"
+	"github.com/openshift/installer/pkg/types/vsphere"
+)
+
+// OverridesEnv is the environment variable naming a JSON file of Terraform
+// variables which override the ones generated for the platform.
+const OverridesEnv = "OPENSHIFT_INSTALL_TERRAFORM_VARIABLES"
+
+// overridable are the Terraform variables of each platform which may be
+// overridden. Credentials and variables derived from the install-config
+// which the rest of the install relies on are left out.
+var overridable = map[string][]string{
+	aws.Name: {
+		"aws_bootstrap_instance_type",
+		"aws_extra_tags",
+		"aws_master_instance_metadata_authentication",
+		"aws_master_root_volume_iops",
+		"aws_master_root_volume_size",
+		"aws_master_root_volume_type",
+	},
+	azure.Name: {
+		"azure_control_plane_vm_networking_type",
+		"azure_extra_tags",
+		"azure_master_encryption_at_host_enabled",
+		"azure_master_root_volume_size",
+		"azure_master_root_volume_type",
+	},
+	gcp.Name: {
+		"gcp_bootstrap_instance_type",
+		"gcp_control_plane_tags",
+		"gcp_master_root_volume_size",
+		"gcp_master_root_volume_type",
+	},
+	openstack.Name: {
+		"openstack_master_root_volume_size",
+		"openstack_master_root_volume_type",
+		"openstack_master_server_group_policy",
+	},
+	vsphere.Name: {
+		"vsphere_control_plane_cores_per_socket",
+		"vsphere_control_plane_disk_gib",
+		"vsphere_control_plane_memory_mib",
+		"vsphere_control_plane_num_cpus",
+		"vsphere_disk_type",
+	},
+}
+
+// Overridable returns the Terraform variables of the platform which may be
+// overridden.
+func Overridable(platform string) []string {
+	names := append([]string{}, overridable[platform]...)
+	sort.Strings(names)
+	return names
+}
+
+// Overrides validates the Terraform variables overriding the generated ones
+// of the platform against the variables which may be overridden, and
+// returns them as a variables file. Maps, e.g. extra tags, are merged into
+// the generated maps instead of replacing them.
+func Overrides(platform string, data []byte, generated ...[]byte) ([]byte, error) {
+	overrides := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, errors.Wrap(err, "failed to parse the Terraform variables")
+	}
+
+	allowed := map[string]bool{}
+	for _, name := range overridable[platform] {
+		allowed[name] = true
+	}
+	for name := range overrides {
+		if !allowed[name] {
+			return nil, errors.Errorf("the Terraform variable %q cannot be overridden on %s, only %v", name, platform, Overridable(platform))
+		}
+	}
+
+	vars := map[string]json.RawMessage{}
+	for _, file := range generated {
+		if err := json.Unmarshal(file, &vars); err != nil {
+			return nil, errors.Wrap(err, "failed to parse the generated Terraform variables")
+		}
+	}
+	for name, value := range overrides {
+		merged, err := merge(vars[name], value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to merge the Terraform variable %q", name)
+		}
+		overrides[name] = merged
+	}
+	return json.MarshalIndent(overrides, "", "  ")
+}
+
+// merge merges the override into the generated value when both are
+// objects, and otherwise returns the override.
+func merge(generated, override json.RawMessage) (json.RawMessage, error) {
+	var generatedMap, overrideMap map[string]json.RawMessage
+	if json.Unmarshal(generated, &generatedMap) != nil || json.Unmarshal(override, &overrideMap) != nil || generatedMap == nil || overrideMap == nil {
+		return override, nil
+	}
+	for key, value := range overrideMap {
+		generatedMap[key] = value
+	}
+	return json.Marshal(generatedMap)
+}
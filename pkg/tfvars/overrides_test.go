@@ -0,0 +1,72 @@
+package tfvars
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverrides(t *testing.T) {
+	cases := []struct {
+		name      string
+		platform  string
+		overrides string
+		generated []string
+		expected  string
+		err       string
+	}{
+		{
+			name:      "scalar replaces the generated value",
+			platform:  "aws",
+			overrides: `{"aws_master_instance_metadata_authentication": "required"}`,
+			generated: []string{`{"aws_master_instance_metadata_authentication": "optional"}`},
+			expected:  `{"aws_master_instance_metadata_authentication": "required"}`,
+		},
+		{
+			name:      "map merged into the generated map",
+			platform:  "aws",
+			overrides: `{"aws_extra_tags": {"team": "infra", "owner": "me"}}`,
+			generated: []string{`{"cluster_id": "test-x"}`, `{"aws_extra_tags": {"owner": "installer", "cost-center": "42"}}`},
+			expected:  `{"aws_extra_tags": {"cost-center": "42", "owner": "me", "team": "infra"}}`,
+		},
+		{
+			name:      "map without a generated map",
+			platform:  "azure",
+			overrides: `{"azure_extra_tags": {"team": "infra"}}`,
+			expected:  `{"azure_extra_tags": {"team": "infra"}}`,
+		},
+		{
+			name:      "not allowed",
+			platform:  "aws",
+			overrides: `{"aws_region": "us-west-2"}`,
+			err:       `the Terraform variable "aws_region" cannot be overridden on aws, only [aws_bootstrap_instance_type aws_extra_tags aws_master_instance_metadata_authentication aws_master_root_volume_iops aws_master_root_volume_size aws_master_root_volume_type]`,
+		},
+		{
+			name:      "platform without overridable variables",
+			platform:  "libvirt",
+			overrides: `{"libvirt_master_memory": "16384"}`,
+			err:       `the Terraform variable "libvirt_master_memory" cannot be overridden on libvirt, only []`,
+		},
+		{
+			name:      "invalid",
+			platform:  "aws",
+			overrides: `{"aws_extra_tags": `,
+			err:       "failed to parse the Terraform variables: unexpected end of JSON input",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			generated := make([][]byte, 0, len(tc.generated))
+			for _, file := range tc.generated {
+				generated = append(generated, []byte(file))
+			}
+			data, err := Overrides(tc.platform, []byte(tc.overrides), generated...)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.JSONEq(t, tc.expected, string(data))
+		})
+	}
+}
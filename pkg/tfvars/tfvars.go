@@ -13,6 +13,7 @@ type config struct {
 	ClusterID          string   `json:"cluster_id,omitempty"`
 	ClusterDomain      string   `json:"cluster_domain,omitempty"`
 	BaseDomain         string   `json:"base_domain,omitempty"`
+	APIDomain          string   `json:"api_domain,omitempty"`
 	Masters            int      `json:"master_count,omitempty"`
 	MastersSchedulable bool     `json:"masters_schedulable,omitempty"`
 	MachineV4CIDRs     []string `json:"machine_v4_cidrs"`
@@ -27,7 +28,7 @@ type config struct {
 }
 
 // TFVars generates terraform.tfvar JSON for launching the cluster.
-func TFVars(clusterID string, clusterDomain string, baseDomain string, machineV4CIDRs []string, machineV6CIDRs []string, useIPv4, useIPv6 bool, bootstrapIgn string, masterIgn string, masterCount int, mastersSchedulable bool) ([]byte, error) {
+func TFVars(clusterID string, clusterDomain string, baseDomain string, apiDomain string, machineV4CIDRs []string, machineV6CIDRs []string, useIPv4, useIPv6 bool, bootstrapIgn string, masterIgn string, masterCount int, mastersSchedulable bool) ([]byte, error) {
 	f, err := os.CreateTemp("", "openshift-install-bootstrap-*.ign")
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create tmp file for bootstrap ignition")
@@ -42,6 +43,7 @@ func TFVars(clusterID string, clusterDomain string, baseDomain string, machineV4
 		ClusterID:             clusterID,
 		ClusterDomain:         strings.TrimSuffix(clusterDomain, "."),
 		BaseDomain:            strings.TrimSuffix(baseDomain, "."),
+		APIDomain:             apiDomain,
 		MachineV4CIDRs:        machineV4CIDRs,
 		MachineV6CIDRs:        machineV6CIDRs,
 		UseIPv4:               useIPv4,
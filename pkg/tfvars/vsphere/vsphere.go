@@ -9,7 +9,7 @@ import (
 
 	machineapi "github.com/openshift/api/machine/v1beta1"
 	"github.com/openshift/installer/pkg/asset/installconfig"
-	"github.com/openshift/installer/pkg/tfvars/internal/cache"
+	"github.com/openshift/installer/pkg/rhcos/cache"
 	vtypes "github.com/openshift/installer/pkg/types/vsphere"
 )
 
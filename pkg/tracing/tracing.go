@@ -0,0 +1,304 @@
+// Package tracing records the install flow as OpenTelemetry spans and
+// exports them with OTLP, when an OTLP endpoint is configured with the
+// standard OTEL_EXPORTER_OTLP_* environment variables. Both the grpc and the
+// http/protobuf protocols are supported. When no endpoint is configured,
+// spans are not recorded.
+//
+// The spans are exported in batches as they end, as configured by the
+// OTEL_BSP_* environment variables, and the rest when tracing is shut down.
+// The trace of a command continues the one of the W3C trace context in the
+// TRACEPARENT and TRACESTATE environment variables, when they are set.
+package tracing
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// defaultServiceName is the service name of the spans when
+	// OTEL_SERVICE_NAME is not set.
+	defaultServiceName = "openshift-install"
+
+	// scopeName is the instrumentation scope of the spans.
+	scopeName = "github.com/openshift/installer"
+
+	// exportTimeout bounds how long exporting the spans may delay the exit
+	// of the installer.
+	exportTimeout = 30 * time.Second
+
+	// failedMessage is the status of the spans still running when a
+	// command fails.
+	failedMessage = "the installer exited before the operation completed"
+)
+
+// The OTLP protocols of OTEL_EXPORTER_OTLP_PROTOCOL.
+const (
+	protocolGRPC         = "grpc"
+	protocolHTTPProtobuf = "http/protobuf"
+)
+
+// Attribute is a key-value attribute of a span.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// String returns a string attribute.
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int returns an integer attribute.
+func Int(key string, value int64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span is a timed operation of the install flow. A nil span is valid and
+// records nothing, which is what Start returns when tracing is disabled.
+type Span struct {
+	span   trace.Span
+	tracer *tracer
+
+	// failed is set once an error is recorded, and guarded by the mutex of
+	// the tracer.
+	failed bool
+}
+
+// tracer holds the provider of the spans and the spans which are running.
+type tracer struct {
+	provider *sdktrace.TracerProvider
+	tracer   trace.Tracer
+	root     *Span
+
+	mu      sync.Mutex
+	running map[*Span]struct{}
+	// err is the last error reported by the SDK, e.g. a failed export.
+	err error
+}
+
+var (
+	mu     sync.Mutex
+	active *tracer
+)
+
+// propagator reads and writes the W3C trace context and baggage.
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Init enables tracing when an OTLP endpoint is configured, and starts the
+// root span of the command. It returns the context of the root span, which
+// the spans of the command descend from.
+func Init(ctx context.Context, command string, version string) (context.Context, error) {
+	if os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" {
+		return ctx, nil
+	}
+	exporter, err := newExporter(ctx)
+	if err != nil {
+		return ctx, err
+	}
+	return start(ctx, exporter, command, version)
+}
+
+// newExporter returns the OTLP exporter of the protocol configured by
+// OTEL_EXPORTER_OTLP_TRACES_PROTOCOL or OTEL_EXPORTER_OTLP_PROTOCOL. The
+// exporters read the endpoint, headers, certificates, compression and
+// timeout from the OTEL_EXPORTER_OTLP_* environment variables themselves.
+func newExporter(ctx context.Context) (sdktrace.SpanExporter, error) {
+	variable := "OTEL_EXPORTER_OTLP_TRACES_PROTOCOL"
+	protocol := os.Getenv(variable)
+	if protocol == "" {
+		variable = "OTEL_EXPORTER_OTLP_PROTOCOL"
+		protocol = os.Getenv(variable)
+	}
+	switch protocol {
+	case protocolGRPC:
+		return otlptracegrpc.New(ctx)
+	case "", protocolHTTPProtobuf:
+		return otlptracehttp.New(ctx)
+	default:
+		return nil, errors.Errorf("unsupported %s %q, only %s and %s are supported", variable, protocol, protocolGRPC, protocolHTTPProtobuf)
+	}
+}
+
+// start enables tracing with the exporter, and starts the root span of the
+// command as the child of the trace context of the environment, if any.
+func start(ctx context.Context, exporter sdktrace.SpanExporter, command string, version string) (context.Context, error) {
+	// the resource of the environment overrides the defaults
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String(defaultServiceName),
+			semconv.ServiceVersionKey.String(version),
+		),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return ctx, errors.Wrap(err, "invalid OTEL_RESOURCE_ATTRIBUTES")
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	t := &tracer{
+		provider: provider,
+		tracer:   provider.Tracer(scopeName),
+		running:  map[*Span]struct{}{},
+	}
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagator)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(t.handleError))
+	mu.Lock()
+	active = t
+	mu.Unlock()
+
+	ctx = propagator.Extract(ctx, environmentCarrier{})
+	ctx, t.root = Start(ctx, command)
+	return ctx, nil
+}
+
+// Start starts a span, which is the child of the span of the context. It
+// returns the context of the new span.
+func Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, *Span) {
+	mu.Lock()
+	t := active
+	mu.Unlock()
+	if t == nil {
+		return ctx, nil
+	}
+
+	ctx, span := t.tracer.Start(ctx, name, trace.WithAttributes(convert(attributes)...))
+	s := &Span{span: span, tracer: t}
+	t.mu.Lock()
+	t.running[s] = struct{}{}
+	t.mu.Unlock()
+	return ctx, s
+}
+
+// RootContext returns the context of the root span, for the operations
+// which are not passed the context of the command.
+func RootContext() context.Context {
+	mu.Lock()
+	t := active
+	mu.Unlock()
+	if t == nil || t.root == nil {
+		return context.Background()
+	}
+	return trace.ContextWithSpan(context.Background(), t.root.span)
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attributes ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.span.SetAttributes(convert(attributes)...)
+}
+
+// RecordError sets the status of the span to the error, when err is not nil.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.tracer.mu.Lock()
+	s.failed = true
+	s.tracer.mu.Unlock()
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End ends the span, which is exported with the next batch.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.tracer.mu.Lock()
+	delete(s.tracer.running, s)
+	s.tracer.mu.Unlock()
+	s.span.End()
+}
+
+// Shutdown ends the spans which are still running, including the root span,
+// and exports the spans which were not exported yet. When failed is set, the
+// spans which are still running are marked as failed. Tracing is disabled
+// afterwards.
+func Shutdown(ctx context.Context, failed bool) error {
+	mu.Lock()
+	t := active
+	active = nil
+	mu.Unlock()
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	running := make([]*Span, 0, len(t.running))
+	for span := range t.running {
+		if failed && !span.failed {
+			span.span.SetStatus(codes.Error, failedMessage)
+		}
+		running = append(running, span)
+	}
+	t.mu.Unlock()
+	for _, span := range running {
+		span.End()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, exportTimeout)
+	defer cancel()
+	if err := t.provider.Shutdown(ctx); err != nil {
+		return errors.Wrap(err, "failed to export the spans")
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return errors.Wrap(t.err, "failed to export the spans")
+}
+
+// handleError records the errors of the SDK, which exports the spans in the
+// background, for Shutdown to report them.
+func (t *tracer) handleError(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.err = err
+}
+
+func convert(attributes []Attribute) []attribute.KeyValue {
+	converted := make([]attribute.KeyValue, 0, len(attributes))
+	for _, a := range attributes {
+		switch v := a.Value.(type) {
+		case int64:
+			converted = append(converted, attribute.Int64(a.Key, v))
+		case string:
+			converted = append(converted, attribute.String(a.Key, v))
+		}
+	}
+	return converted
+}
+
+// environmentCarrier reads the trace context from the environment variables
+// named after the upper-cased keys, e.g. TRACEPARENT.
+type environmentCarrier struct{}
+
+func (environmentCarrier) Get(key string) string {
+	return os.Getenv(strings.ToUpper(key))
+}
+
+func (environmentCarrier) Set(key string, value string) {}
+
+func (environmentCarrier) Keys() []string {
+	return []string{"traceparent", "tracestate", "baggage"}
+}
@@ -0,0 +1,199 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordingExporter records the exported spans, or fails the exports with
+// err.
+type recordingExporter struct {
+	mu    sync.Mutex
+	spans tracetest.SpanStubs
+	err   error
+}
+
+func (e *recordingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.err != nil {
+		return e.err
+	}
+	e.spans = append(e.spans, tracetest.SpanStubsFromReadOnlySpans(spans)...)
+	return nil
+}
+
+func (e *recordingExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (e *recordingExporter) byName() map[string]tracetest.SpanStub {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	byName := map[string]tracetest.SpanStub{}
+	for _, span := range e.spans {
+		byName[span.Name] = span
+	}
+	return byName
+}
+
+func TestDisabled(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+
+	ctx, err := Init(context.Background(), "openshift-install create cluster", "v4.13.0")
+	assert.NoError(t, err)
+	_, span := Start(ctx, "Generate Cluster")
+	assert.Nil(t, span)
+	span.RecordError(errors.New("failed"))
+	span.End()
+	assert.NoError(t, Shutdown(context.Background(), false))
+}
+
+func TestInitInvalid(t *testing.T) {
+	cases := []struct {
+		name           string
+		protocol       string
+		tracesProtocol string
+		err            string
+	}{
+		{
+			name:     "unsupported protocol",
+			protocol: "http/json",
+			err:      `unsupported OTEL_EXPORTER_OTLP_PROTOCOL "http/json", only grpc and http/protobuf are supported`,
+		},
+		{
+			name:           "unsupported traces protocol",
+			protocol:       "grpc",
+			tracesProtocol: "http/json",
+			err:            `unsupported OTEL_EXPORTER_OTLP_TRACES_PROTOCOL "http/json", only grpc and http/protobuf are supported`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318")
+			t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", tc.protocol)
+			t.Setenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", tc.tracesProtocol)
+			_, err := Init(context.Background(), "openshift-install create cluster", "v4.13.0")
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestSpans(t *testing.T) {
+	t.Setenv("OTEL_SERVICE_NAME", "")
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "")
+	t.Setenv("TRACEPARENT", "")
+
+	exporter := &recordingExporter{}
+	ctx, err := start(context.Background(), exporter, "openshift-install create cluster", "v4.13.0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, generate := Start(ctx, "Generate Cluster", String("asset", "*cluster.Cluster"))
+	generate.RecordError(errors.New("failed to provision"))
+	generate.End()
+	_, stage := Start(RootContext(), "Bootstrap Complete")
+	stage.SetAttributes(Int("attempt", 2))
+	if !assert.NoError(t, Shutdown(context.Background(), true)) {
+		return
+	}
+
+	spans := exporter.byName()
+	if !assert.Len(t, spans, 3) {
+		return
+	}
+	root, generateSpan, stageSpan := spans["openshift-install create cluster"], spans["Generate Cluster"], spans["Bootstrap Complete"]
+
+	serviceName, _ := root.Resource.Set().Value("service.name")
+	assert.Equal(t, "openshift-install", serviceName.AsString())
+	serviceVersion, _ := root.Resource.Set().Value("service.version")
+	assert.Equal(t, "v4.13.0", serviceVersion.AsString())
+
+	assert.False(t, root.Parent.IsValid())
+	assert.Equal(t, sdktrace.Status{Code: codes.Error, Description: "the installer exited before the operation completed"}, root.Status)
+
+	assert.Equal(t, root.SpanContext.TraceID(), generateSpan.SpanContext.TraceID())
+	assert.Equal(t, root.SpanContext.SpanID(), generateSpan.Parent.SpanID())
+	assert.Equal(t, sdktrace.Status{Code: codes.Error, Description: "failed to provision"}, generateSpan.Status)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("asset", "*cluster.Cluster")}, generateSpan.Attributes)
+
+	assert.Equal(t, root.SpanContext.SpanID(), stageSpan.Parent.SpanID())
+	assert.Equal(t, []attribute.KeyValue{attribute.Int64("attempt", 2)}, stageSpan.Attributes)
+	assert.Equal(t, codes.Error, stageSpan.Status.Code)
+
+	// tracing is disabled once the spans are exported
+	_, span := Start(context.Background(), "Generate Cluster")
+	assert.Nil(t, span)
+}
+
+func TestTraceContextPropagated(t *testing.T) {
+	t.Setenv("TRACEPARENT", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	exporter := &recordingExporter{}
+	_, err := start(context.Background(), exporter, "openshift-install create cluster", "v4.13.0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.NoError(t, Shutdown(context.Background(), false)) {
+		return
+	}
+
+	root := exporter.byName()["openshift-install create cluster"]
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	assert.Equal(t, traceID, root.SpanContext.TraceID())
+	assert.Equal(t, spanID, root.Parent.SpanID())
+	assert.True(t, root.Parent.IsRemote())
+	assert.Equal(t, codes.Unset, root.Status.Code)
+}
+
+func TestExportFailed(t *testing.T) {
+	exporter := &recordingExporter{err: errors.New("connection refused")}
+	_, err := start(context.Background(), exporter, "openshift-install create manifests", "v4.13.0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	err = Shutdown(context.Background(), false)
+	assert.EqualError(t, err, "failed to export the spans: connection refused")
+}
+
+func TestExportHTTPProtobuf(t *testing.T) {
+	exported := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer a token", r.Header.Get("Authorization"))
+		select {
+		case exported <- struct{}{}:
+		default:
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", server.URL)
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "http/protobuf")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "Authorization=Bearer%20a%20token")
+
+	ctx, err := Init(context.Background(), "openshift-install create cluster", "v4.13.0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, span := Start(ctx, "Generate Install Config")
+	span.End()
+	assert.NoError(t, Shutdown(context.Background(), false))
+	assert.Len(t, exported, 1)
+}
@@ -26,6 +26,59 @@ type Config struct {
 	// ip address of node0
 	RendezvousIP string `json:"rendezvousIP,omitempty"`
 	Hosts        []Host `json:"hosts,omitempty"`
+	// PXE configures the files generated by `agent create pxe-files`.
+	// +optional
+	PXE *PXE `json:"pxe,omitempty"`
+}
+
+// PXEConfigImage is how the Ignition config of the agents is delivered to
+// hosts booting over PXE.
+type PXEConfigImage string
+
+const (
+	// PXEConfigImageEmbedded embeds the Ignition config in the initrd.
+	PXEConfigImageEmbedded PXEConfigImage = "Embedded"
+	// PXEConfigImageServed writes the Ignition config to a separate config
+	// image, which is served alongside the initrd and loaded as a second
+	// initrd, so that the config can be replaced without rebuilding the
+	// initrd.
+	PXEConfigImageServed PXEConfigImage = "Served"
+)
+
+// PXE configures the files generated for hosts booting over PXE.
+type PXE struct {
+	// BaseURL is the URL the PXE files are served from. When set, an iPXE
+	// script booting the files from it is generated with them.
+	// +optional
+	BaseURL string `json:"baseURL,omitempty"`
+	// ConfigImage is how the Ignition config is delivered: Embedded or
+	// Served.
+	// Defaults to Embedded.
+	// +optional
+	ConfigImage PXEConfigImage `json:"configImage,omitempty"`
+}
+
+// RootDeviceTransport is how the root device of a host is attached.
+type RootDeviceTransport string
+
+const (
+	// RootDeviceTransportISCSI is a root device attached over iSCSI, with
+	// the targets configured in the iSCSI Boot Firmware Table of the host.
+	RootDeviceTransportISCSI RootDeviceTransport = "iSCSI"
+	// RootDeviceTransportFC is a root device attached over Fibre Channel.
+	RootDeviceTransportFC RootDeviceTransport = "FC"
+)
+
+// RootDevice configures the root device of a host attached over a storage
+// network. The device is selected by the root device hints of the host.
+type RootDevice struct {
+	// Transport is how the root device is attached: iSCSI or FC.
+	// +optional
+	Transport RootDeviceTransport `json:"transport,omitempty"`
+	// Multipath installs the host on the device-mapper multipath device of
+	// the root device, so that the host keeps running when a path fails.
+	// +optional
+	Multipath bool `json:"multipath,omitempty"`
 }
 
 // Host defines per host configurations
@@ -33,6 +86,10 @@ type Host struct {
 	Hostname        string                    `json:"hostname,omitempty"`
 	Role            string                    `json:"role,omitempty"`
 	RootDeviceHints baremetal.RootDeviceHints `json:"rootDeviceHints,omitempty"`
+	// RootDevice configures the root device when it is attached over iSCSI
+	// or Fibre Channel, or through multiple paths.
+	// +optional
+	RootDevice *RootDevice `json:"rootDevice,omitempty"`
 	// list of interfaces and mac addresses
 	Interfaces    []*aiv1beta1.Interface `json:"interfaces,omitempty"`
 	NetworkConfig aiv1beta1.NetConfig    `json:"networkConfig,omitempty"`
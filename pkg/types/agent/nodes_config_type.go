@@ -0,0 +1,21 @@
+package agent
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodesConfig is the API for specifying the hosts to add as workers to an
+// existing cluster with `agent create add-nodes-iso`.
+type NodesConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Hosts are the hosts to add. Each host is identified by the MAC
+	// addresses of its interfaces.
+	Hosts []Host `json:"hosts"`
+
+	// SSHKey is authorized for the core user while the hosts boot the ISO,
+	// to debug hosts which fail to join the cluster.
+	// +optional
+	SSHKey string `json:"sshKey,omitempty"`
+}
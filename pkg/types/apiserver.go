@@ -0,0 +1,69 @@
+package types
+
+import (
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+// EncryptionType is the type of the encryption of the resources the API
+// servers store in etcd.
+type EncryptionType string
+
+const (
+	// EncryptionTypeIdentity stores the resources unencrypted.
+	EncryptionTypeIdentity EncryptionType = "identity"
+
+	// EncryptionTypeAESCBC encrypts the resources with AES-CBC, with keys
+	// the cluster generates and rotates.
+	EncryptionTypeAESCBC EncryptionType = "aescbc"
+
+	// EncryptionTypeAESGCM encrypts the resources with AES-GCM, with keys
+	// the cluster generates and rotates.
+	EncryptionTypeAESGCM EncryptionType = "aesgcm"
+
+	// EncryptionTypeKMS encrypts the resources with keys encrypted by a key
+	// management service.
+	EncryptionTypeKMS EncryptionType = "KMS"
+)
+
+// APIServer configures the API servers of the cluster.
+type APIServer struct {
+	// AuditProfile is the audit policy of the API servers: Default,
+	// WriteRequestBodies, AllRequestBodies or None.
+	// Defaults to Default.
+	// +optional
+	AuditProfile configv1.AuditProfileType `json:"auditProfile,omitempty"`
+
+	// Encryption configures the encryption of the resources the API servers
+	// store in etcd, e.g. the secrets and config maps.
+	// +optional
+	Encryption *APIServerEncryption `json:"encryption,omitempty"`
+}
+
+// APIServerEncryption configures the encryption of the resources the API
+// servers store in etcd.
+type APIServerEncryption struct {
+	// Type is the type of the encryption: identity, aescbc, aesgcm or KMS.
+	// KMS requires the TechPreviewNoUpgrade feature set.
+	Type EncryptionType `json:"type"`
+
+	// KMS configures the key management service of the KMS encryption.
+	// +optional
+	KMS *KMSConfig `json:"kms,omitempty"`
+}
+
+// KMSConfig configures the key management service the encryption keys are
+// encrypted with.
+type KMSConfig struct {
+	// AWS configures the AWS Key Management Service.
+	AWS *AWSKMSConfig `json:"aws,omitempty"`
+}
+
+// AWSKMSConfig configures the AWS Key Management Service.
+type AWSKMSConfig struct {
+	// KeyARN is the ARN of the KMS key the encryption keys are encrypted
+	// with.
+	KeyARN string `json:"keyARN"`
+
+	// Region is the region of the KMS key.
+	Region string `json:"region"`
+}
@@ -125,14 +125,14 @@ func SetPlatformDefaults(p *baremetal.Platform, c *types.InstallConfig) {
 
 	if len(p.APIVIPs) == 0 && p.DeprecatedAPIVIP == "" {
 		// This name should resolve to exactly one address
-		if vip, err := lookupHost("api." + c.ClusterDomain()); err == nil {
+		if vip, err := lookupHost(c.APIDomainName()); err == nil {
 			p.APIVIPs = []string{vip[0]}
 		}
 	}
 
 	if len(p.IngressVIPs) == 0 && p.DeprecatedIngressVIP == "" {
 		// This name should resolve to exactly one address
-		if vip, err := lookupHost("test.apps." + c.ClusterDomain()); err == nil {
+		if vip, err := lookupHost("test." + c.AppsDomainName()); err == nil {
 			p.IngressVIPs = []string{vip[0]}
 		}
 	}
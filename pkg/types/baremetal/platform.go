@@ -4,6 +4,7 @@ import (
 	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 
 	"github.com/openshift/installer/pkg/ipnet"
+	"github.com/openshift/installer/pkg/types/loadbalancer"
 )
 
 // BMC stores the information about a baremetal host's management controller.
@@ -201,6 +202,14 @@ type Platform struct {
 	// +optional
 	IngressVIPs []string `json:"ingressVIPs,omitempty"`
 
+	// LoadBalancer is the load balancer of the API and of the ingress. With a
+	// UserManaged load balancer, the API and ingress VIPs are optional: they
+	// are the addresses of the load balancer, which the cluster does not
+	// serve with keepalived and haproxy.
+	//
+	// +optional
+	LoadBalancer *loadbalancer.LoadBalancer `json:"loadBalancer,omitempty"`
+
 	// BootstrapOSImage is a URL to override the default OS image
 	// for the bootstrap node. The URL must contain a sha256 hash of the image
 	// e.g https://mirror.example.com/images/qemu.qcow2.gz?sha256=a07bd...
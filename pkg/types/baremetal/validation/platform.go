@@ -10,7 +10,6 @@ import (
 	"strings"
 
 	"github.com/apparentlymart/go-cidr/cidr"
-	"github.com/ghodss/yaml"
 	"github.com/go-playground/validator/v10"
 	"github.com/metal3-io/baremetal-operator/pkg/hardwareutils/bmc"
 	"github.com/pkg/errors"
@@ -22,6 +21,7 @@ import (
 	"github.com/openshift/installer/pkg/ipnet"
 	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/baremetal"
+	hostnetworkvalidation "github.com/openshift/installer/pkg/types/hostnetwork/validation"
 	"github.com/openshift/installer/pkg/validate"
 )
 
@@ -335,11 +335,7 @@ func validateHostsCount(hosts []*baremetal.Host, installConfig *types.InstallCon
 func validateNetworkConfig(hosts []*baremetal.Host, fldPath *field.Path) (errors field.ErrorList) {
 	for idx, host := range hosts {
 		if host.NetworkConfig != nil {
-			networkConfig := make(map[string]interface{})
-			err := yaml.Unmarshal(host.NetworkConfig.Raw, &networkConfig)
-			if err != nil {
-				errors = append(errors, field.Invalid(fldPath.Index(idx).Child("networkConfig"), host.NetworkConfig, fmt.Sprintf("Not a valid yaml: %s", err.Error())))
-			}
+			errors = append(errors, hostnetworkvalidation.ValidateNetworkConfig(host.NetworkConfig, fldPath.Index(idx).Child("networkConfig"))...)
 		}
 	}
 	return
@@ -404,7 +400,9 @@ func ValidatePlatform(p *baremetal.Platform, n *types.Networking, fldPath *field
 		}
 	}
 
-	agentBasedInstallation := validate.IsAgentBasedInstallation()
+	// the hosts are not provisioned by the installer when the node boots the
+	// agent or bootstrap-in-place live ISO
+	agentBasedInstallation := validate.IsAgentBasedInstallation() || c.IsSingleNodeOpenShift()
 
 	if !agentBasedInstallation && p.Hosts == nil {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("hosts"), p.Hosts, "bare metal hosts are missing"))
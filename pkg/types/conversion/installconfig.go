@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	utilsslice "k8s.io/utils/strings/slices"
 
@@ -22,17 +23,26 @@ import (
 // how deprecated values are upconverted.
 // This updates the APIVersion to reflect the fact that we've internally
 // upconverted.
+//
+// The fields deprecated by the earlier versions are removed from the current
+// one. They are upconverted with a warning when the install-config is of an
+// earlier version, and forbidden when it is of the current version.
 func ConvertInstallConfig(config *types.InstallConfig) error {
 	// check that the version is convertible
 	switch config.APIVersion {
-	case types.InstallConfigVersion, "v1beta3", "v1beta4":
+	case types.InstallConfigVersion, "v1", "v1beta3", "v1beta4":
 		// works
 	case "":
 		return field.Required(field.NewPath("apiVersion"), "no version was provided")
 	default:
 		return field.Invalid(field.NewPath("apiVersion"), config.APIVersion, fmt.Sprintf("cannot upconvert from version %s", config.APIVersion))
 	}
-	convertNetworking(config)
+	if err := convertNetworking(config); err != nil {
+		return err
+	}
+	if err := convertImageContentSources(config); err != nil {
+		return err
+	}
 
 	switch config.Platform.Name() {
 	case baremetal.Name:
@@ -65,13 +75,52 @@ func ConvertInstallConfig(config *types.InstallConfig) error {
 	return nil
 }
 
+// deprecatedField checks a deprecated field which is set in the
+// install-config. It returns an error if the install-config is of the current
+// version, which removed the field, and otherwise warns that the field is
+// deprecated in favor of the replacement, if any.
+func deprecatedField(config *types.InstallConfig, fldPath *field.Path, replacement string) error {
+	if config.APIVersion == types.InstallConfigVersion {
+		if replacement == "" {
+			return field.Forbidden(fldPath, fmt.Sprintf("removed in %s, the field is no longer used", types.InstallConfigVersion))
+		}
+		return field.Forbidden(fldPath, fmt.Sprintf("removed in %s, use %s instead", types.InstallConfigVersion, replacement))
+	}
+	if replacement == "" {
+		logrus.Warnf("%s is deprecated and ignored; run \"openshift-install migrate install-config\" to convert the install-config to %s", fldPath, types.InstallConfigVersion)
+	} else {
+		logrus.Warnf("%s is deprecated, use %s instead; run \"openshift-install migrate install-config\" to convert the install-config to %s", fldPath, replacement, types.InstallConfigVersion)
+	}
+	return nil
+}
+
 // convertNetworking upconverts deprecated fields in networking
-func convertNetworking(config *types.InstallConfig) {
+func convertNetworking(config *types.InstallConfig) error {
 	if config.Networking == nil {
-		return
+		return nil
 	}
 
 	netconf := config.Networking
+	fldPath := field.NewPath("networking")
+
+	deprecated := []struct {
+		set         bool
+		name        string
+		replacement string
+	}{
+		{set: len(netconf.DeprecatedClusterNetworks) > 0, name: "clusterNetworks", replacement: "clusterNetwork"},
+		{set: netconf.DeprecatedMachineCIDR != nil, name: "machineCIDR", replacement: "machineNetwork"},
+		{set: netconf.DeprecatedServiceCIDR != nil, name: "serviceCIDR", replacement: "serviceNetwork"},
+		{set: netconf.DeprecatedType != "", name: "type", replacement: "networkType"},
+	}
+	for _, d := range deprecated {
+		if !d.set {
+			continue
+		}
+		if err := deprecatedField(config, fldPath.Child(d.name), fldPath.Child(d.replacement).String()); err != nil {
+			return err
+		}
+	}
 
 	if len(netconf.ClusterNetwork) == 0 {
 		netconf.ClusterNetwork = netconf.DeprecatedClusterNetworks
@@ -100,11 +149,30 @@ func convertNetworking(config *types.InstallConfig) {
 
 	// Convert hostSubnetLength to hostPrefix
 	for i, entry := range netconf.ClusterNetwork {
-		if entry.HostPrefix == 0 && entry.DeprecatedHostSubnetLength != 0 {
+		if entry.DeprecatedHostSubnetLength == 0 {
+			continue
+		}
+		entryPath := fldPath.Child("clusterNetwork").Index(i)
+		if err := deprecatedField(config, entryPath.Child("hostSubnetLength"), entryPath.Child("hostPrefix").String()); err != nil {
+			return err
+		}
+		if entry.HostPrefix == 0 {
 			_, size := entry.CIDR.Mask.Size()
 			netconf.ClusterNetwork[i].HostPrefix = int32(size) - entry.DeprecatedHostSubnetLength
 		}
 	}
+	return nil
+}
+
+// convertImageContentSources checks the deprecated imageContentSources, which
+// has been replaced by imageDigestSources. It is not upconverted, since the
+// install-configs setting it are given ImageContentSourcePolicies rather than
+// ImageDigestMirrorSets.
+func convertImageContentSources(config *types.InstallConfig) error {
+	if len(config.ImageContentSources) == 0 {
+		return nil
+	}
+	return deprecatedField(config, field.NewPath("imageContentSources"), "imageDigestSources")
 }
 
 // convertBaremetal upconverts deprecated fields in the baremetal platform.
@@ -113,6 +181,21 @@ func convertNetworking(config *types.InstallConfig) {
 // ClusterProvisioningIP, apiVIP has been replaced by apiVIPs and ingressVIP has
 // been replaced by ingressVIPs.
 func convertBaremetal(config *types.InstallConfig) error {
+	fldPath := field.NewPath("platform").Child("baremetal")
+	if config.Platform.BareMetal.DeprecatedProvisioningDHCPExternal {
+		if err := deprecatedField(config, fldPath.Child("provisioningDHCPExternal"), fmt.Sprintf("%s: %s", fldPath.Child("provisioningNetwork"), baremetal.UnmanagedProvisioningNetwork)); err != nil {
+			return err
+		}
+	}
+	if config.Platform.BareMetal.DeprecatedProvisioningHostIP != "" {
+		if err := deprecatedField(config, fldPath.Child("provisioningHostIP"), fldPath.Child("clusterProvisioningIP").String()); err != nil {
+			return err
+		}
+	}
+	if err := deprecatedVIPs(config, config.Platform.BareMetal.DeprecatedAPIVIP, config.Platform.BareMetal.DeprecatedIngressVIP, "apiVIP", "ingressVIP", fldPath); err != nil {
+		return err
+	}
+
 	if config.Platform.BareMetal.DeprecatedProvisioningDHCPExternal && config.Platform.BareMetal.ProvisioningNetwork == "" {
 		config.Platform.BareMetal.ProvisioningNetwork = baremetal.UnmanagedProvisioningNetwork
 	}
@@ -141,6 +224,30 @@ func convertBaremetal(config *types.InstallConfig) error {
 
 // convertOpenStack upconverts deprecated fields in the OpenStack platform.
 func convertOpenStack(config *types.InstallConfig) error {
+	fldPath := field.NewPath("platform").Child("openstack")
+	deprecated := []struct {
+		set         bool
+		name        string
+		replacement string
+	}{
+		{set: config.Platform.OpenStack.DeprecatedRegion != "", name: "region"},
+		{set: config.Platform.OpenStack.DeprecatedFlavorName != "", name: "computeFlavor", replacement: fldPath.Child("defaultMachinePlatform", "type").String()},
+		{set: config.Platform.OpenStack.DeprecatedLbFloatingIP != "", name: "lbFloatingIP", replacement: fldPath.Child("apiFloatingIP").String()},
+		{set: config.Platform.OpenStack.DeprecatedTrunkSupport != "", name: "trunkSupport"},
+		{set: config.Platform.OpenStack.DeprecatedOctaviaSupport != "", name: "octaviaSupport"},
+	}
+	for _, d := range deprecated {
+		if !d.set {
+			continue
+		}
+		if err := deprecatedField(config, fldPath.Child(d.name), d.replacement); err != nil {
+			return err
+		}
+	}
+	if err := deprecatedVIPs(config, config.Platform.OpenStack.DeprecatedAPIVIP, config.Platform.OpenStack.DeprecatedIngressVIP, "apiVIP", "ingressVIP", fldPath); err != nil {
+		return err
+	}
+
 	// LbFloatingIP has been renamed to APIFloatingIP
 	if config.Platform.OpenStack.DeprecatedLbFloatingIP != "" {
 		if config.Platform.OpenStack.APIFloatingIP == "" {
@@ -178,6 +285,10 @@ func convertOpenStack(config *types.InstallConfig) error {
 
 // convertNutanix upconverts deprecated fields in the Nutanix platform.
 func convertNutanix(config *types.InstallConfig) error {
+	if err := deprecatedVIPs(config, config.Platform.Nutanix.DeprecatedAPIVIP, config.Platform.Nutanix.DeprecatedIngressVIP, "apiVIP", "ingressVIP", field.NewPath("platform").Child("nutanix")); err != nil {
+		return err
+	}
+
 	if err := upconvertVIP(&config.Platform.Nutanix.APIVIPs, config.Platform.Nutanix.DeprecatedAPIVIP, "apiVIP", "apiVIPs", field.NewPath("platform").Child("nutanix")); err != nil {
 		return err
 	}
@@ -191,6 +302,10 @@ func convertNutanix(config *types.InstallConfig) error {
 
 // convertVSphere upconverts deprecated fields in the VSphere platform.
 func convertVSphere(config *types.InstallConfig) error {
+	if err := deprecatedVIPs(config, config.Platform.VSphere.DeprecatedAPIVIP, config.Platform.VSphere.DeprecatedIngressVIP, "apiVIP", "ingressVIP", field.NewPath("platform").Child("vsphere")); err != nil {
+		return err
+	}
+
 	if err := upconvertVIP(&config.Platform.VSphere.APIVIPs, config.Platform.VSphere.DeprecatedAPIVIP, "apiVIP", "apiVIPs", field.NewPath("platform").Child("vsphere")); err != nil {
 		return err
 	}
@@ -204,6 +319,10 @@ func convertVSphere(config *types.InstallConfig) error {
 
 // convertOVirt upconverts deprecated fields in the OVirt platform.
 func convertOVirt(config *types.InstallConfig) error {
+	if err := deprecatedVIPs(config, config.Platform.Ovirt.DeprecatedAPIVIP, config.Platform.Ovirt.DeprecatedIngressVIP, "api_vip", "ingress_vip", field.NewPath("platform").Child("ovirt")); err != nil {
+		return err
+	}
+
 	if err := upconvertVIP(&config.Platform.Ovirt.APIVIPs, config.Platform.Ovirt.DeprecatedAPIVIP, "api_vip", "api_vips", field.NewPath("platform").Child("ovirt")); err != nil {
 		return err
 	}
@@ -215,6 +334,22 @@ func convertOVirt(config *types.InstallConfig) error {
 	return nil
 }
 
+// deprecatedVIPs checks the deprecated API and Ingress VIPs of the platform,
+// which have been replaced by the lists of VIPs named after them.
+func deprecatedVIPs(config *types.InstallConfig, apiVIP, ingressVIP, apiVIPName, ingressVIPName string, fldPath *field.Path) error {
+	if apiVIP != "" {
+		if err := deprecatedField(config, fldPath.Child(apiVIPName), fldPath.Child(apiVIPName+"s").String()); err != nil {
+			return err
+		}
+	}
+	if ingressVIP != "" {
+		if err := deprecatedField(config, fldPath.Child(ingressVIPName), fldPath.Child(ingressVIPName+"s").String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // upconvertVIP upconverts the deprecated VIP (oldVIPValue) to the new VIPs
 // slice (newVIPValues). It returns errors, if both fields are set and all
 // contain unique values
@@ -235,6 +370,10 @@ func upconvertVIP(newVIPValues *[]string, oldVIPValue, newFieldName, oldFieldNam
 func convertAWS(config *types.InstallConfig) error {
 	// Deprecated ExperimentalPropagateUserTag takes precedence when set
 	if config.Platform.AWS.ExperimentalPropagateUserTag != nil {
+		fldPath := field.NewPath("platform").Child("aws")
+		if err := deprecatedField(config, fldPath.Child("experimentalPropagateUserTags"), fldPath.Child("propagateUserTags").String()); err != nil {
+			return err
+		}
 		config.Platform.AWS.PropagateUserTag = *config.Platform.AWS.ExperimentalPropagateUserTag
 	}
 	return nil
@@ -177,7 +177,7 @@ func TestConvertInstallConfig(t *testing.T) {
 			name: "deprecated OpenStack LbFloatingIP",
 			config: &types.InstallConfig{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: types.InstallConfigVersion,
+					APIVersion: "v1",
 				},
 				Platform: types.Platform{
 					OpenStack: &openstack.Platform{
@@ -201,7 +201,7 @@ func TestConvertInstallConfig(t *testing.T) {
 			name: "deprecated OpenStack LbFloatingIP is the same as APIFloatingIP",
 			config: &types.InstallConfig{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: types.InstallConfigVersion,
+					APIVersion: "v1",
 				},
 				Platform: types.Platform{
 					OpenStack: &openstack.Platform{
@@ -226,7 +226,7 @@ func TestConvertInstallConfig(t *testing.T) {
 			name: "deprecated OpenStack LbFloatingIP with APIFloatingIP",
 			config: &types.InstallConfig{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: types.InstallConfigVersion,
+					APIVersion: "v1",
 				},
 				Platform: types.Platform{
 					OpenStack: &openstack.Platform{
@@ -243,7 +243,7 @@ func TestConvertInstallConfig(t *testing.T) {
 			name: "baremetal external DHCP",
 			config: &types.InstallConfig{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: types.InstallConfigVersion,
+					APIVersion: "v1",
 				},
 				Platform: types.Platform{
 					BareMetal: &baremetal.Platform{
@@ -267,7 +267,7 @@ func TestConvertInstallConfig(t *testing.T) {
 			name: "baremetal provisioningHostIP -> clusterProvisioningIP",
 			config: &types.InstallConfig{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: types.InstallConfigVersion,
+					APIVersion: "v1",
 				},
 				Platform: types.Platform{
 					BareMetal: &baremetal.Platform{
@@ -291,7 +291,7 @@ func TestConvertInstallConfig(t *testing.T) {
 			name: "baremetal provisioningHostIP mismatch clusterProvisioningIP",
 			config: &types.InstallConfig{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: types.InstallConfigVersion,
+					APIVersion: "v1",
 				},
 				Platform: types.Platform{
 					BareMetal: &baremetal.Platform{
@@ -306,7 +306,7 @@ func TestConvertInstallConfig(t *testing.T) {
 			name: "baremetal deprecated apiVIP",
 			config: &types.InstallConfig{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: types.InstallConfigVersion,
+					APIVersion: "v1",
 				},
 				Platform: types.Platform{
 					BareMetal: &baremetal.Platform{
@@ -330,7 +330,7 @@ func TestConvertInstallConfig(t *testing.T) {
 			name: "baremetal deprecated ingressVIP",
 			config: &types.InstallConfig{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: types.InstallConfigVersion,
+					APIVersion: "v1",
 				},
 				Platform: types.Platform{
 					BareMetal: &baremetal.Platform{
@@ -354,7 +354,7 @@ func TestConvertInstallConfig(t *testing.T) {
 			name: "deprecated OpenStack computeFlavor",
 			config: &types.InstallConfig{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: types.InstallConfigVersion,
+					APIVersion: "v1",
 				},
 				Platform: types.Platform{
 					OpenStack: &openstack.Platform{
@@ -380,7 +380,7 @@ func TestConvertInstallConfig(t *testing.T) {
 			name: "deprecated OpenStack computeFlavor with type in defaultMachinePlatform",
 			config: &types.InstallConfig{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: types.InstallConfigVersion,
+					APIVersion: "v1",
 				},
 				Platform: types.Platform{
 					OpenStack: &openstack.Platform{
@@ -397,7 +397,7 @@ func TestConvertInstallConfig(t *testing.T) {
 			name: "openstack deprecated apiVIP",
 			config: &types.InstallConfig{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: types.InstallConfigVersion,
+					APIVersion: "v1",
 				},
 				Platform: types.Platform{
 					OpenStack: &openstack.Platform{
@@ -421,7 +421,7 @@ func TestConvertInstallConfig(t *testing.T) {
 			name: "openstack deprecated ingressVIP",
 			config: &types.InstallConfig{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: types.InstallConfigVersion,
+					APIVersion: "v1",
 				},
 				Platform: types.Platform{
 					OpenStack: &openstack.Platform{
@@ -445,7 +445,7 @@ func TestConvertInstallConfig(t *testing.T) {
 			name: "vsphere deprecated apiVIP",
 			config: &types.InstallConfig{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: types.InstallConfigVersion,
+					APIVersion: "v1",
 				},
 				Platform: types.Platform{
 					VSphere: &vsphere.Platform{
@@ -469,7 +469,7 @@ func TestConvertInstallConfig(t *testing.T) {
 			name: "vsphere deprecated ingressVIP",
 			config: &types.InstallConfig{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: types.InstallConfigVersion,
+					APIVersion: "v1",
 				},
 				Platform: types.Platform{
 					VSphere: &vsphere.Platform{
@@ -493,7 +493,7 @@ func TestConvertInstallConfig(t *testing.T) {
 			name: "ovirt deprecated apiVIP",
 			config: &types.InstallConfig{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: types.InstallConfigVersion,
+					APIVersion: "v1",
 				},
 				Platform: types.Platform{
 					Ovirt: &ovirt.Platform{
@@ -517,7 +517,7 @@ func TestConvertInstallConfig(t *testing.T) {
 			name: "ovirt deprecated ingressVIP",
 			config: &types.InstallConfig{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: types.InstallConfigVersion,
+					APIVersion: "v1",
 				},
 				Platform: types.Platform{
 					Ovirt: &ovirt.Platform{
@@ -541,7 +541,7 @@ func TestConvertInstallConfig(t *testing.T) {
 			name: "nutanix deprecated apiVIP",
 			config: &types.InstallConfig{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: types.InstallConfigVersion,
+					APIVersion: "v1",
 				},
 				Platform: types.Platform{
 					Nutanix: &nutanix.Platform{
@@ -565,7 +565,7 @@ func TestConvertInstallConfig(t *testing.T) {
 			name: "nutanix deprecated ingressVIP",
 			config: &types.InstallConfig{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: types.InstallConfigVersion,
+					APIVersion: "v1",
 				},
 				Platform: types.Platform{
 					Nutanix: &nutanix.Platform{
@@ -585,6 +585,71 @@ func TestConvertInstallConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "deprecated networking field removed in the current version",
+			config: &types.InstallConfig{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: types.InstallConfigVersion,
+				},
+				Networking: &types.Networking{
+					DeprecatedType: "OVNKubernetes",
+				},
+			},
+			expectedError: `^networking\.type: Forbidden: removed in v2, use networking\.networkType instead$`,
+		},
+		{
+			name: "unused OpenStack field removed in the current version",
+			config: &types.InstallConfig{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: types.InstallConfigVersion,
+				},
+				Platform: types.Platform{
+					OpenStack: &openstack.Platform{
+						DeprecatedRegion: "region",
+					},
+				},
+			},
+			expectedError: `^platform\.openstack\.region: Forbidden: removed in v2, the field is no longer used$`,
+		},
+		{
+			name: "deprecated VIP removed in the current version",
+			config: &types.InstallConfig{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: types.InstallConfigVersion,
+				},
+				Platform: types.Platform{
+					Ovirt: &ovirt.Platform{
+						DeprecatedAPIVIP: "1.2.3.4",
+					},
+				},
+			},
+			expectedError: `^platform\.ovirt\.api_vip: Forbidden: removed in v2, use platform\.ovirt\.api_vips instead$`,
+		},
+		{
+			name: "deprecated imageContentSources",
+			config: &types.InstallConfig{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "v1",
+				},
+				ImageContentSources: []types.ImageContentSource{{Source: "quay.io/openshift", Mirrors: []string{"mirror.example.com/openshift"}}},
+			},
+			expected: &types.InstallConfig{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: types.InstallConfigVersion,
+				},
+				ImageContentSources: []types.ImageContentSource{{Source: "quay.io/openshift", Mirrors: []string{"mirror.example.com/openshift"}}},
+			},
+		},
+		{
+			name: "deprecated imageContentSources removed in the current version",
+			config: &types.InstallConfig{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: types.InstallConfigVersion,
+				},
+				ImageContentSources: []types.ImageContentSource{{Source: "quay.io/openshift", Mirrors: []string{"mirror.example.com/openshift"}}},
+			},
+			expectedError: `^imageContentSources: Forbidden: removed in v2, use imageDigestSources instead$`,
+		},
 	}
 
 	for _, tc := range cases {
@@ -0,0 +1,65 @@
+package conversion
+
+import (
+	"github.com/openshift/installer/pkg/types"
+)
+
+// MigrateInstallConfig upconverts the install-config and moves the values of
+// its deprecated fields to their replacements, so that it is in the form of
+// the current version, which does not accept them.
+//
+// Unlike the upconversion, the migration replaces imageContentSources with
+// imageDigestSources, so the migrated install-config gives the cluster
+// ImageDigestMirrorSets rather than ImageContentSourcePolicies.
+func MigrateInstallConfig(config *types.InstallConfig) error {
+	if err := ConvertInstallConfig(config); err != nil {
+		return err
+	}
+
+	if len(config.ImageContentSources) > 0 {
+		if len(config.ImageDigestSources) == 0 {
+			config.ImageDigestSources = config.MirrorSources()
+		}
+		config.ImageContentSources = nil
+	}
+
+	if netconf := config.Networking; netconf != nil {
+		netconf.DeprecatedClusterNetworks = nil
+		netconf.DeprecatedMachineCIDR = nil
+		netconf.DeprecatedServiceCIDR = nil
+		netconf.DeprecatedType = ""
+		for i := range netconf.ClusterNetwork {
+			netconf.ClusterNetwork[i].DeprecatedHostSubnetLength = 0
+		}
+	}
+
+	switch {
+	case config.Platform.AWS != nil:
+		config.Platform.AWS.ExperimentalPropagateUserTag = nil
+	case config.Platform.BareMetal != nil:
+		p := config.Platform.BareMetal
+		p.DeprecatedProvisioningDHCPExternal = false
+		p.DeprecatedProvisioningHostIP = ""
+		p.DeprecatedAPIVIP = ""
+		p.DeprecatedIngressVIP = ""
+	case config.Platform.Nutanix != nil:
+		config.Platform.Nutanix.DeprecatedAPIVIP = ""
+		config.Platform.Nutanix.DeprecatedIngressVIP = ""
+	case config.Platform.OpenStack != nil:
+		p := config.Platform.OpenStack
+		p.DeprecatedRegion = ""
+		p.DeprecatedFlavorName = ""
+		p.DeprecatedLbFloatingIP = ""
+		p.DeprecatedTrunkSupport = ""
+		p.DeprecatedOctaviaSupport = ""
+		p.DeprecatedAPIVIP = ""
+		p.DeprecatedIngressVIP = ""
+	case config.Platform.Ovirt != nil:
+		config.Platform.Ovirt.DeprecatedAPIVIP = ""
+		config.Platform.Ovirt.DeprecatedIngressVIP = ""
+	case config.Platform.VSphere != nil:
+		config.Platform.VSphere.DeprecatedAPIVIP = ""
+		config.Platform.VSphere.DeprecatedIngressVIP = ""
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package conversion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/ipnet"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/vsphere"
+)
+
+func TestMigrateInstallConfig(t *testing.T) {
+	cases := []struct {
+		name          string
+		config        *types.InstallConfig
+		expected      *types.InstallConfig
+		expectedError string
+	}{
+		{
+			name: "deprecated fields",
+			config: &types.InstallConfig{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "v1",
+				},
+				Networking: &types.Networking{
+					DeprecatedMachineCIDR: ipnet.MustParseCIDR("10.0.0.0/16"),
+					DeprecatedType:        "OVNKubernetes",
+					ClusterNetwork: []types.ClusterNetworkEntry{
+						{
+							CIDR:                       *ipnet.MustParseCIDR("10.128.0.0/14"),
+							DeprecatedHostSubnetLength: 9,
+						},
+					},
+				},
+				ImageContentSources: []types.ImageContentSource{{Source: "quay.io/openshift", Mirrors: []string{"mirror.example.com/openshift"}}},
+				Platform: types.Platform{
+					VSphere: &vsphere.Platform{
+						DeprecatedAPIVIP:     "1.2.3.4",
+						DeprecatedIngressVIP: "1.2.3.5",
+					},
+				},
+			},
+			expected: &types.InstallConfig{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: types.InstallConfigVersion,
+				},
+				Networking: &types.Networking{
+					MachineNetwork: []types.MachineNetworkEntry{{CIDR: *ipnet.MustParseCIDR("10.0.0.0/16")}},
+					NetworkType:    "OVNKubernetes",
+					ClusterNetwork: []types.ClusterNetworkEntry{
+						{
+							CIDR:       *ipnet.MustParseCIDR("10.128.0.0/14"),
+							HostPrefix: 23,
+						},
+					},
+				},
+				ImageDigestSources: []types.ImageDigestSource{{Source: "quay.io/openshift", Mirrors: []string{"mirror.example.com/openshift"}}},
+				Platform: types.Platform{
+					VSphere: &vsphere.Platform{
+						APIVIPs:     []string{"1.2.3.4"},
+						IngressVIPs: []string{"1.2.3.5"},
+					},
+				},
+			},
+		},
+		{
+			name: "current version",
+			config: &types.InstallConfig{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: types.InstallConfigVersion,
+				},
+				ImageDigestSources: []types.ImageDigestSource{{Source: "quay.io/openshift", Mirrors: []string{"mirror.example.com/openshift"}}},
+			},
+			expected: &types.InstallConfig{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: types.InstallConfigVersion,
+				},
+				ImageDigestSources: []types.ImageDigestSource{{Source: "quay.io/openshift", Mirrors: []string{"mirror.example.com/openshift"}}},
+			},
+		},
+		{
+			name: "conflicting deprecated field",
+			config: &types.InstallConfig{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "v1",
+				},
+				Platform: types.Platform{
+					VSphere: &vsphere.Platform{
+						DeprecatedAPIVIP: "1.2.3.4",
+						APIVIPs:          []string{"1.2.3.5"},
+					},
+				},
+			},
+			expectedError: `^platform\.vsphere\.apiVIPs: Invalid value: "1\.2\.3\.4"`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := MigrateInstallConfig(tc.config)
+			if tc.expectedError == "" {
+				if assert.NoError(t, err) {
+					assert.Equal(t, tc.expected, tc.config)
+				}
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -1,13 +1,16 @@
 package defaults
 
 import (
+	configv1 "github.com/openshift/api/config/v1"
 	operv1 "github.com/openshift/api/operator/v1"
 	"github.com/openshift/installer/pkg/ipnet"
 	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/aws"
 	awsdefaults "github.com/openshift/installer/pkg/types/aws/defaults"
 	"github.com/openshift/installer/pkg/types/azure"
 	azuredefaults "github.com/openshift/installer/pkg/types/azure/defaults"
 	baremetaldefaults "github.com/openshift/installer/pkg/types/baremetal/defaults"
+	"github.com/openshift/installer/pkg/types/gcp"
 	gcpdefaults "github.com/openshift/installer/pkg/types/gcp/defaults"
 	ibmclouddefaults "github.com/openshift/installer/pkg/types/ibmcloud/defaults"
 	libvirtdefaults "github.com/openshift/installer/pkg/types/libvirt/defaults"
@@ -17,6 +20,7 @@ import (
 	ovirtdefaults "github.com/openshift/installer/pkg/types/ovirt/defaults"
 	powervsdefaults "github.com/openshift/installer/pkg/types/powervs/defaults"
 	vspheredefaults "github.com/openshift/installer/pkg/types/vsphere/defaults"
+	"k8s.io/utils/pointer"
 )
 
 var (
@@ -26,6 +30,15 @@ var (
 	defaultClusterNetwork = ipnet.MustParseCIDR("10.128.0.0/14")
 	defaultHostPrefix     = 23
 	defaultNetworkType    = string(operv1.NetworkTypeOVNKubernetes)
+
+	defaultEtcdDiskSizeGB = int64(32)
+	// defaultEtcdDiskTypes are the default types of the dedicated etcd disk
+	// on the platforms which support it.
+	defaultEtcdDiskTypes = map[string]string{
+		aws.Name:   "gp3",
+		azure.Name: "Premium_LRS",
+		gcp.Name:   "pd-ssd",
+	}
 )
 
 // SetInstallConfigDefaults sets the defaults for the install config.
@@ -71,6 +84,16 @@ func SetInstallConfigDefaults(c *types.InstallConfig) {
 		c.ControlPlane = &types.MachinePool{}
 	}
 	c.ControlPlane.Name = "master"
+	if c.Arbiter != nil {
+		c.Arbiter.Name = types.MachinePoolArbiterRoleName
+		if c.Arbiter.Replicas == nil {
+			c.Arbiter.Replicas = pointer.Int64Ptr(1)
+		}
+		SetMachinePoolDefaults(c.Arbiter, c.Platform.Name())
+		if c.ControlPlane.Replicas == nil {
+			c.ControlPlane.Replicas = pointer.Int64Ptr(2)
+		}
+	}
 	SetMachinePoolDefaults(c.ControlPlane, c.Platform.Name())
 	if len(c.Compute) == 0 {
 		c.Compute = []types.MachinePool{{Name: "worker"}}
@@ -123,4 +146,38 @@ func SetInstallConfigDefaults(c *types.InstallConfig) {
 	if c.AdditionalTrustBundlePolicy == "" {
 		c.AdditionalTrustBundlePolicy = types.PolicyProxyOnly
 	}
+
+	if c.Capabilities != nil {
+		expandCapabilityPreset(c.Capabilities)
+	}
+
+	if c.Etcd != nil && c.Etcd.Disk != nil {
+		if c.Etcd.Disk.SizeGB == 0 {
+			c.Etcd.Disk.SizeGB = defaultEtcdDiskSizeGB
+		}
+		if c.Etcd.Disk.Type == "" {
+			c.Etcd.Disk.Type = defaultEtcdDiskTypes[c.Platform.Name()]
+		}
+	}
+}
+
+// expandCapabilityPreset replaces an installer-defined capability preset with
+// the None baseline and the preset's capabilities, so that only capability
+// sets known to the cluster-version operator are rendered into manifests.
+func expandCapabilityPreset(c *types.Capabilities) {
+	capabilities, ok := types.CapabilityPreset(c.BaselineCapabilitySet)
+	if !ok {
+		return
+	}
+	c.BaselineCapabilitySet = configv1.ClusterVersionCapabilitySetNone
+	capabilities = append(capabilities, c.AdditionalEnabledCapabilities...)
+	seen := map[configv1.ClusterVersionCapability]bool{}
+	c.AdditionalEnabledCapabilities = capabilities[:0]
+	for _, capability := range capabilities {
+		if seen[capability] {
+			continue
+		}
+		seen[capability] = true
+		c.AdditionalEnabledCapabilities = append(c.AdditionalEnabledCapabilities, capability)
+	}
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"k8s.io/utils/pointer"
 
+	configv1 "github.com/openshift/api/config/v1"
 	"github.com/openshift/installer/pkg/ipnet"
 	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/aws"
@@ -216,6 +217,19 @@ func TestSetInstallConfigDefaults(t *testing.T) {
 			},
 			expected: defaultInstallConfig(),
 		},
+		{
+			name: "arbiter present",
+			config: &types.InstallConfig{
+				Arbiter: &types.MachinePool{},
+			},
+			expected: func() *types.InstallConfig {
+				c := defaultInstallConfig()
+				c.ControlPlane.Replicas = pointer.Int64Ptr(2)
+				c.Arbiter = defaultMachinePool("arbiter")
+				c.Arbiter.Replicas = pointer.Int64Ptr(1)
+				return c
+			}(),
+		},
 		{
 			name: "Compute present",
 			config: &types.InstallConfig{
@@ -239,6 +253,20 @@ func TestSetInstallConfigDefaults(t *testing.T) {
 				return c
 			}(),
 		},
+		{
+			name: "AWS etcd disk",
+			config: &types.InstallConfig{
+				Platform: types.Platform{
+					AWS: &aws.Platform{},
+				},
+				Etcd: &types.Etcd{Disk: &types.EtcdDisk{}},
+			},
+			expected: func() *types.InstallConfig {
+				c := defaultAWSInstallConfig()
+				c.Etcd = &types.Etcd{Disk: &types.EtcdDisk{SizeGB: 32, Type: "gp3"}}
+				return c
+			}(),
+		},
 		{
 			name: "Azure platform present",
 			config: &types.InstallConfig{
@@ -290,6 +318,42 @@ func TestSetInstallConfigDefaults(t *testing.T) {
 				return c
 			}(),
 		},
+		{
+			name: "Capability preset expanded",
+			config: &types.InstallConfig{
+				Capabilities: &types.Capabilities{
+					BaselineCapabilitySet:         types.CapabilitySetMinimal,
+					AdditionalEnabledCapabilities: []configv1.ClusterVersionCapability{configv1.ClusterVersionCapabilityStorage, configv1.ClusterVersionCapabilityConsole},
+				},
+			},
+			expected: func() *types.InstallConfig {
+				c := defaultInstallConfig()
+				c.Capabilities = &types.Capabilities{
+					BaselineCapabilitySet: configv1.ClusterVersionCapabilitySetNone,
+					AdditionalEnabledCapabilities: []configv1.ClusterVersionCapability{
+						configv1.ClusterVersionCapabilityStorage,
+						configv1.ClusterVersionCapabilityCSISnapshot,
+						configv1.ClusterVersionCapabilityConsole,
+					},
+				}
+				return c
+			}(),
+		},
+		{
+			name: "Capability set known to the cluster left as is",
+			config: &types.InstallConfig{
+				Capabilities: &types.Capabilities{
+					BaselineCapabilitySet: configv1.ClusterVersionCapabilitySet4_11,
+				},
+			},
+			expected: func() *types.InstallConfig {
+				c := defaultInstallConfig()
+				c.Capabilities = &types.Capabilities{
+					BaselineCapabilitySet: configv1.ClusterVersionCapabilitySet4_11,
+				}
+				return c
+			}(),
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
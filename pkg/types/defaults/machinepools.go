@@ -6,6 +6,10 @@ import (
 	"github.com/openshift/installer/pkg/version"
 )
 
+// defaultContainerStorageSizeGB is the default size of the dedicated
+// container storage disk.
+const defaultContainerStorageSizeGB = int64(100)
+
 // SetMachinePoolDefaults sets the defaults for the machine pool.
 func SetMachinePoolDefaults(p *types.MachinePool, platform string) {
 	defaultReplicaCount := int64(3)
@@ -21,4 +25,22 @@ func SetMachinePoolDefaults(p *types.MachinePool, platform string) {
 	if p.Architecture == "" {
 		p.Architecture = version.DefaultArch()
 	}
+	if p.DiskEncryption != nil && p.DiskEncryption.Threshold == 0 {
+		p.DiskEncryption.Threshold = 1
+	}
+	if s := p.ContainerStorage; s != nil {
+		if s.MountPath == "" {
+			s.MountPath = types.ContainerStorageMountPathContainers
+		}
+		// the disk is only attached by the installer on the platforms
+		// which support a dedicated etcd disk
+		if diskType, ok := defaultEtcdDiskTypes[platform]; ok {
+			if s.SizeGB == 0 {
+				s.SizeGB = defaultContainerStorageSizeGB
+			}
+			if s.Type == "" {
+				s.Type = diskType
+			}
+		}
+	}
 }
@@ -48,6 +48,35 @@ func TestSetMahcinePoolDefaults(t *testing.T) {
 				return p
 			}(),
 		},
+		{
+			name: "disk encryption threshold",
+			pool: &types.MachinePool{DiskEncryption: &types.DiskEncryption{TPM2: true}},
+			expected: func() *types.MachinePool {
+				p := defaultMachinePool("")
+				p.DiskEncryption = &types.DiskEncryption{TPM2: true, Threshold: 1}
+				return p
+			}(),
+		},
+		{
+			name:     "aws container storage",
+			pool:     &types.MachinePool{ContainerStorage: &types.ContainerStorage{}},
+			platform: "aws",
+			expected: func() *types.MachinePool {
+				p := defaultMachinePool("")
+				p.ContainerStorage = &types.ContainerStorage{MountPath: "/var/lib/containers", SizeGB: 100, Type: "gp3"}
+				return p
+			}(),
+		},
+		{
+			name:     "baremetal container storage",
+			pool:     &types.MachinePool{ContainerStorage: &types.ContainerStorage{MountPath: "/var", Device: "/dev/sdb"}},
+			platform: "baremetal",
+			expected: func() *types.MachinePool {
+				p := defaultMachinePool("")
+				p.ContainerStorage = &types.ContainerStorage{MountPath: "/var", Device: "/dev/sdb"}
+				return p
+			}(),
+		},
 		{
 			name:     "libvirt replicas",
 			pool:     &types.MachinePool{},
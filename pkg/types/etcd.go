@@ -0,0 +1,45 @@
+package types
+
+// EtcdLatencyProfile tunes the heartbeat interval and leader election
+// timeout of etcd for the latency of the disks and network of the control
+// plane.
+// +kubebuilder:validation:Enum="";Standard;Slower
+type EtcdLatencyProfile string
+
+const (
+	// EtcdLatencyProfileStandard is the default tuning of etcd, a heartbeat
+	// interval of 100ms and a leader election timeout of 1000ms.
+	EtcdLatencyProfileStandard EtcdLatencyProfile = "Standard"
+	// EtcdLatencyProfileSlower tunes etcd for slower disks and networks, a
+	// heartbeat interval of 500ms and a leader election timeout of 2500ms.
+	EtcdLatencyProfileSlower EtcdLatencyProfile = "Slower"
+)
+
+// Etcd configures the etcd members of the cluster.
+type Etcd struct {
+	// Disk places the data of etcd on a dedicated disk of the control plane
+	// machines, so that etcd does not share the IOPS of the root disk.
+	// It is supported on AWS, Azure and GCP.
+	// +optional
+	Disk *EtcdDisk `json:"disk,omitempty"`
+
+	// LatencyProfile tunes etcd for the latency of the control plane:
+	// Standard or Slower.
+	// Defaults to Standard.
+	// +optional
+	LatencyProfile EtcdLatencyProfile `json:"latencyProfile,omitempty"`
+}
+
+// EtcdDisk is the dedicated disk the data of etcd is placed on.
+type EtcdDisk struct {
+	// SizeGB is the size of the disk in GB, at least 10.
+	// Defaults to 32.
+	// +optional
+	SizeGB int64 `json:"sizeGB,omitempty"`
+
+	// Type is the type of the disk: gp2 or gp3 on AWS, Premium_LRS on
+	// Azure, and pd-ssd or pd-balanced on GCP.
+	// Defaults to gp3 on AWS, Premium_LRS on Azure and pd-ssd on GCP.
+	// +optional
+	Type string `json:"type,omitempty"`
+}
@@ -0,0 +1,4 @@
+// Package hostnetwork contains the day-1 host network configuration shared by
+// the platforms which do not configure the network of the machines through a
+// cloud API.
+package hostnetwork
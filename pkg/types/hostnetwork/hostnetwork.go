@@ -0,0 +1,28 @@
+package hostnetwork
+
+import (
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// Host is the network configuration of a host, delivered to the host matching
+// its identity on first boot.
+type Host struct {
+	// Name is the name of the host.
+	Name string `json:"name"`
+
+	// Role is the role of the host, master or worker.
+	Role string `json:"role"`
+
+	// MACAddresses identifies the host by the MAC address of any of its
+	// interfaces.
+	// +optional
+	MACAddresses []string `json:"macAddresses,omitempty"`
+
+	// SerialNumber identifies the host by its system serial number.
+	// +optional
+	SerialNumber string `json:"serialNumber,omitempty"`
+
+	// NetworkConfig is the NMState configuration of the host, declaring
+	// e.g. its bonds, VLANs and static routes.
+	NetworkConfig *apiextv1.JSON `json:"networkConfig"`
+}
@@ -0,0 +1,126 @@
+// Package validation contains validation for the day-1 host network
+// configuration.
+package validation
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/ghodss/yaml"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types/hostnetwork"
+	"github.com/openshift/installer/pkg/validate"
+)
+
+var validRoles = sets.NewString("master", "worker")
+
+// ValidateHosts checks that the hosts have unique names and identities and a
+// valid network configuration.
+func ValidateHosts(hosts []hostnetwork.Host, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := sets.NewString()
+	macs := sets.NewString()
+	serials := sets.NewString()
+	for i, host := range hosts {
+		hostPath := fldPath.Index(i)
+		if err := validate.DomainName(host.Name, false); err != nil {
+			allErrs = append(allErrs, field.Invalid(hostPath.Child("name"), host.Name, err.Error()))
+		} else if names.Has(host.Name) {
+			allErrs = append(allErrs, field.Duplicate(hostPath.Child("name"), host.Name))
+		}
+		names.Insert(host.Name)
+
+		if !validRoles.Has(host.Role) {
+			allErrs = append(allErrs, field.NotSupported(hostPath.Child("role"), host.Role, validRoles.List()))
+		}
+
+		if len(host.MACAddresses) == 0 && host.SerialNumber == "" {
+			allErrs = append(allErrs, field.Required(hostPath, "at least one of macAddresses or serialNumber must be specified to identify the host"))
+		}
+		for j, mac := range host.MACAddresses {
+			hw, err := net.ParseMAC(mac)
+			if err != nil {
+				allErrs = append(allErrs, field.Invalid(hostPath.Child("macAddresses").Index(j), mac, err.Error()))
+				continue
+			}
+			if macs.Has(hw.String()) {
+				allErrs = append(allErrs, field.Duplicate(hostPath.Child("macAddresses").Index(j), mac))
+			}
+			macs.Insert(hw.String())
+		}
+		if host.SerialNumber != "" {
+			if serials.Has(host.SerialNumber) {
+				allErrs = append(allErrs, field.Duplicate(hostPath.Child("serialNumber"), host.SerialNumber))
+			}
+			serials.Insert(host.SerialNumber)
+		}
+
+		if host.NetworkConfig == nil {
+			allErrs = append(allErrs, field.Required(hostPath.Child("networkConfig"), "the network configuration of the host is required"))
+			continue
+		}
+		allErrs = append(allErrs, ValidateNetworkConfig(host.NetworkConfig, hostPath.Child("networkConfig"))...)
+	}
+	return allErrs
+}
+
+// nmstateState is the part of the NMState schema which is validated.
+type nmstateState struct {
+	Interfaces []struct {
+		Name            string                 `json:"name"`
+		Type            string                 `json:"type"`
+		LinkAggregation map[string]interface{} `json:"link-aggregation"`
+		VLAN            map[string]interface{} `json:"vlan"`
+	} `json:"interfaces"`
+	Routes *struct {
+		Config []map[string]interface{} `json:"config"`
+	} `json:"routes"`
+}
+
+// ValidateNetworkConfig checks that the network configuration is an NMState
+// document whose interfaces are named and typed, with the settings required
+// by bond and VLAN interfaces.
+func ValidateNetworkConfig(networkConfig *apiextv1.JSON, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	raw := make(map[string]interface{})
+	if err := yaml.Unmarshal(networkConfig.Raw, &raw); err != nil {
+		return append(allErrs, field.Invalid(fldPath, networkConfig, fmt.Sprintf("Not a valid yaml: %s", err.Error())))
+	}
+	var state nmstateState
+	if err := yaml.Unmarshal(networkConfig.Raw, &state); err != nil {
+		return append(allErrs, field.Invalid(fldPath, networkConfig, fmt.Sprintf("Not a valid NMState configuration: %s", err.Error())))
+	}
+	names := sets.NewString()
+	for i, iface := range state.Interfaces {
+		ifacePath := fldPath.Child("interfaces").Index(i)
+		if iface.Name == "" {
+			allErrs = append(allErrs, field.Required(ifacePath.Child("name"), "interface name is required"))
+		} else if names.Has(iface.Name) {
+			allErrs = append(allErrs, field.Duplicate(ifacePath.Child("name"), iface.Name))
+		}
+		names.Insert(iface.Name)
+		switch iface.Type {
+		case "":
+			allErrs = append(allErrs, field.Required(ifacePath.Child("type"), "interface type is required"))
+		case "bond":
+			if iface.LinkAggregation == nil {
+				allErrs = append(allErrs, field.Required(ifacePath.Child("link-aggregation"), "bond interfaces require link-aggregation"))
+			}
+		case "vlan":
+			if iface.VLAN == nil || iface.VLAN["base-iface"] == nil || iface.VLAN["id"] == nil {
+				allErrs = append(allErrs, field.Required(ifacePath.Child("vlan"), "vlan interfaces require vlan.base-iface and vlan.id"))
+			}
+		}
+	}
+	if state.Routes != nil {
+		for i, route := range state.Routes.Config {
+			if route["destination"] == nil {
+				allErrs = append(allErrs, field.Required(fldPath.Child("routes", "config").Index(i).Child("destination"), "route destination is required"))
+			}
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,180 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types/hostnetwork"
+)
+
+const bondConfig = `interfaces:
+- name: bond0
+  type: bond
+  state: up
+  link-aggregation:
+    mode: active-backup
+    port:
+    - eno1
+    - eno2
+- name: bond0.100
+  type: vlan
+  state: up
+  vlan:
+    base-iface: bond0
+    id: 100
+routes:
+  config:
+  - destination: 0.0.0.0/0
+    next-hop-address: 192.168.100.1
+    next-hop-interface: bond0.100
+`
+
+func validHost() hostnetwork.Host {
+	return hostnetwork.Host{
+		Name:          "master-0",
+		Role:          "master",
+		MACAddresses:  []string{"52:54:00:00:00:01"},
+		NetworkConfig: &apiextv1.JSON{Raw: []byte(bondConfig)},
+	}
+}
+
+func TestValidateHosts(t *testing.T) {
+	cases := []struct {
+		name     string
+		hosts    func() []hostnetwork.Host
+		expected string
+	}{
+		{
+			name:  "valid",
+			hosts: func() []hostnetwork.Host { return []hostnetwork.Host{validHost()} },
+		},
+		{
+			name: "valid serial number",
+			hosts: func() []hostnetwork.Host {
+				h := validHost()
+				h.MACAddresses = nil
+				h.SerialNumber = "VMware-42 1a"
+				return []hostnetwork.Host{h}
+			},
+		},
+		{
+			name: "invalid role",
+			hosts: func() []hostnetwork.Host {
+				h := validHost()
+				h.Role = "infra"
+				return []hostnetwork.Host{h}
+			},
+			expected: `^hosts\[0\]\.role: Unsupported value: "infra": supported values: "master", "worker"$`,
+		},
+		{
+			name: "no identity",
+			hosts: func() []hostnetwork.Host {
+				h := validHost()
+				h.MACAddresses = nil
+				return []hostnetwork.Host{h}
+			},
+			expected: `^hosts\[0\]: Required value: at least one of macAddresses or serialNumber must be specified to identify the host$`,
+		},
+		{
+			name: "invalid MAC address",
+			hosts: func() []hostnetwork.Host {
+				h := validHost()
+				h.MACAddresses = []string{"52:54:00"}
+				return []hostnetwork.Host{h}
+			},
+			expected: `^hosts\[0\]\.macAddresses\[0\]: Invalid value: "52:54:00": address 52:54:00: invalid MAC address$`,
+		},
+		{
+			name: "duplicate hosts",
+			hosts: func() []hostnetwork.Host {
+				h := validHost()
+				h.MACAddresses = []string{"52-54-00-00-00-01"}
+				return []hostnetwork.Host{validHost(), h}
+			},
+			expected: `^\[hosts\[1\]\.name: Duplicate value: "master-0", hosts\[1\]\.macAddresses\[0\]: Duplicate value: "52-54-00-00-00-01"\]$`,
+		},
+		{
+			name: "missing network config",
+			hosts: func() []hostnetwork.Host {
+				h := validHost()
+				h.NetworkConfig = nil
+				return []hostnetwork.Host{h}
+			},
+			expected: `^hosts\[0\]\.networkConfig: Required value: the network configuration of the host is required$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateHosts(tc.hosts(), field.NewPath("hosts")).ToAggregate()
+			if tc.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestValidateNetworkConfig(t *testing.T) {
+	cases := []struct {
+		name     string
+		config   string
+		expected string
+	}{
+		{
+			name:   "valid",
+			config: bondConfig,
+		},
+		{
+			name:     "not yaml",
+			config:   "Not a valid yaml content",
+			expected: `^networkConfig: Invalid value: .*: Not a valid yaml: `,
+		},
+		{
+			name: "missing interface name and type",
+			config: `interfaces:
+- state: up
+`,
+			expected: `^\[networkConfig\.interfaces\[0\]\.name: Required value: interface name is required, networkConfig\.interfaces\[0\]\.type: Required value: interface type is required\]$`,
+		},
+		{
+			name: "bond without link aggregation",
+			config: `interfaces:
+- name: bond0
+  type: bond
+`,
+			expected: `^networkConfig\.interfaces\[0\]\.link-aggregation: Required value: bond interfaces require link-aggregation$`,
+		},
+		{
+			name: "vlan without id",
+			config: `interfaces:
+- name: eno1.100
+  type: vlan
+  vlan:
+    base-iface: eno1
+`,
+			expected: `^networkConfig\.interfaces\[0\]\.vlan: Required value: vlan interfaces require vlan.base-iface and vlan.id$`,
+		},
+		{
+			name: "route without destination",
+			config: `routes:
+  config:
+  - next-hop-address: 192.168.100.1
+`,
+			expected: `^networkConfig\.routes\.config\[0\]\.destination: Required value: route destination is required$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateNetworkConfig(&apiextv1.JSON{Raw: []byte(tc.config)}, field.NewPath("networkConfig")).ToAggregate()
+			if tc.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package types
+
+import (
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+// IdentityProvider is an identity provider of the OAuth server of the
+// cluster. Exactly one of htpasswd, openID and ldap must be set.
+type IdentityProvider struct {
+	// Name is the name of the identity provider, which is shown on the login
+	// page and prefixes the identities of its users.
+	Name string `json:"name"`
+
+	// MappingMethod determines how the identities of the provider are
+	// mapped to users: claim, lookup, generate or add.
+	// Defaults to claim.
+	// +optional
+	MappingMethod configv1.MappingMethodType `json:"mappingMethod,omitempty"`
+
+	// HTPasswd authenticates users against an htpasswd file.
+	// +optional
+	HTPasswd *HTPasswdIdentityProvider `json:"htpasswd,omitempty"`
+
+	// OpenID authenticates users with an OpenID Connect provider.
+	// +optional
+	OpenID *OpenIDIdentityProvider `json:"openID,omitempty"`
+
+	// LDAP authenticates users against an LDAP server.
+	// +optional
+	LDAP *LDAPIdentityProvider `json:"ldap,omitempty"`
+}
+
+// HTPasswdIdentityProvider authenticates users against an htpasswd file.
+type HTPasswdIdentityProvider struct {
+	// Contents is the htpasswd file, with bcrypt password hashes.
+	Contents string `json:"contents"`
+}
+
+// OpenIDIdentityProvider authenticates users with an OpenID Connect
+// provider.
+type OpenIDIdentityProvider struct {
+	// Issuer is the https URL of the issuer, which serves the OpenID Connect
+	// discovery document.
+	Issuer string `json:"issuer"`
+
+	// ClientID is the ID of the client registered with the provider.
+	ClientID string `json:"clientID"`
+
+	// ClientSecret is the secret of the client registered with the
+	// provider.
+	ClientSecret string `json:"clientSecret"`
+
+	// CA is a PEM-encoded bundle of the CA certificates trusted when
+	// connecting to the provider, in addition to the system ones.
+	// +optional
+	CA string `json:"ca,omitempty"`
+
+	// ExtraScopes are requested in addition to the openid scope.
+	// +optional
+	ExtraScopes []string `json:"extraScopes,omitempty"`
+
+	// Claims maps the claims of the ID token to the identity.
+	// Defaults to the preferred_username, name and email claims.
+	// +optional
+	Claims *configv1.OpenIDClaims `json:"claims,omitempty"`
+}
+
+// LDAPIdentityProvider authenticates users against an LDAP server.
+type LDAPIdentityProvider struct {
+	// URL is an RFC 2255 URL of the server and the search for users, e.g.
+	// ldaps://ldap.example.com/ou=users,dc=example,dc=com?uid.
+	URL string `json:"url"`
+
+	// BindDN is the DN to bind with during the search.
+	// +optional
+	BindDN string `json:"bindDN,omitempty"`
+
+	// BindPassword is the password to bind with during the search.
+	// +optional
+	BindPassword string `json:"bindPassword,omitempty"`
+
+	// Insecure connects to an ldap:// URL without TLS.
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+
+	// CA is a PEM-encoded bundle of the CA certificates trusted when
+	// connecting to the server, in addition to the system ones.
+	// +optional
+	CA string `json:"ca,omitempty"`
+
+	// Attributes maps the attributes of the LDAP entries to the identity.
+	// Defaults to the dn, uid, cn and mail attributes.
+	// +optional
+	Attributes *configv1.LDAPAttributeMapping `json:"attributes,omitempty"`
+}
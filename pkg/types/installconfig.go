@@ -15,6 +15,7 @@ import (
 	"github.com/openshift/installer/pkg/types/gcp"
 	"github.com/openshift/installer/pkg/types/ibmcloud"
 	"github.com/openshift/installer/pkg/types/libvirt"
+	"github.com/openshift/installer/pkg/types/loadbalancer"
 	"github.com/openshift/installer/pkg/types/none"
 	"github.com/openshift/installer/pkg/types/nutanix"
 	"github.com/openshift/installer/pkg/types/openstack"
@@ -27,7 +28,7 @@ const (
 	// InstallConfigVersion is the version supported by this package.
 	// If you bump this, you must also update the list of convertable values in
 	// pkg/types/conversion/installconfig.go
-	InstallConfigVersion  = "v1"
+	InstallConfigVersion  = "v2"
 	workerMachinePoolName = "worker"
 )
 
@@ -54,6 +55,15 @@ var (
 		baremetal.Name,
 		none.Name,
 	}
+	// BootstrapInPlacePlatforms is a slice with the platform names,
+	// in alphabetical order, on which the single node of a cluster can be
+	// booted from the live ISO to install itself. Single-node clusters on
+	// the other platforms are installed with a bootstrap machine.
+	BootstrapInPlacePlatforms = []string{
+		baremetal.Name,
+		none.Name,
+		vsphere.Name,
+	}
 
 	// FCOS is a setting to enable Fedora CoreOS-only modifications
 	FCOS = false
@@ -98,6 +108,15 @@ type InstallConfig struct {
 	// +optional
 	AdditionalTrustBundle string `json:"additionalTrustBundle,omitempty"`
 
+	// AdditionalTrustedCA maps the hosts of image registries, with an
+	// optional port, e.g. registry.example.com:5000, to the PEM-encoded CA
+	// certificates trusted when pulling images from them. It is configured
+	// as the additionalTrustedCA of the cluster image config, and on the
+	// bootstrap machine.
+	//
+	// +optional
+	AdditionalTrustedCA map[string]string `json:"additionalTrustedCA,omitempty"`
+
 	// AdditionalTrustBundlePolicy determines when to add the AdditionalTrustBundle
 	// to the nodes' trusted certificate store. "Proxyonly" is the default.
 	// The field can be set to following specified values.
@@ -106,12 +125,29 @@ type InstallConfig struct {
 	AdditionalTrustBundlePolicy PolicyType `json:"additionalTrustBundlePolicy,omitempty"`
 
 	// SSHKey is the public Secure Shell (SSH) key to provide access to instances.
+	// Several keys may be given, one per line, in the authorized_keys format.
 	// +optional
 	SSHKey string `json:"sshKey,omitempty"`
 
 	// BaseDomain is the base domain to which the cluster should belong.
 	BaseDomain string `json:"baseDomain"`
 
+	// APIDomain is the domain name of the API of the cluster, instead of
+	// api.<name>.<baseDomain>. The certificates of the API and the
+	// kubeconfigs use it. The internal API keeps api-int.<name>.<baseDomain>.
+	// On the platforms whose DNS records the installer creates, it must be a
+	// subdomain of the base domain.
+	// +optional
+	APIDomain string `json:"apiDomain,omitempty"`
+
+	// AppsDomain is the domain of the routes of the cluster, served at
+	// *.<appsDomain>, instead of apps.<name>.<baseDomain>. On the platforms
+	// whose DNS records the installer creates, it must be a subdomain of the
+	// base domain; the cluster only manages the records of the routes when it
+	// is a subdomain of <name>.<baseDomain>.
+	// +optional
+	AppsDomain string `json:"appsDomain,omitempty"`
+
 	// Networking is the configuration for the pod network provider in
 	// the cluster.
 	*Networking `json:"networking,omitempty"`
@@ -121,6 +157,13 @@ type InstallConfig struct {
 	// +optional
 	ControlPlane *MachinePool `json:"controlPlane,omitempty"`
 
+	// Arbiter is the configuration for the machine that runs the etcd member
+	// breaking ties between the two control plane machines of a two-node
+	// cluster. The arbiter does not run the control plane nor workloads.
+	// Arbiter is available in TechPreview.
+	// +optional
+	Arbiter *MachinePool `json:"arbiter,omitempty"`
+
 	// Compute is the configuration for the machines that comprise the
 	// compute nodes.
 	// +optional
@@ -139,9 +182,15 @@ type InstallConfig struct {
 	Proxy *Proxy `json:"proxy,omitempty"`
 
 	// ImageContentSources lists sources/repositories for the release-image content.
+	// Deprecated: use ImageDigestSources.
 	// +optional
 	ImageContentSources []ImageContentSource `json:"imageContentSources,omitempty"`
 
+	// ImageDigestSources lists sources/repositories for the release-image content.
+	// It may not be set along with ImageContentSources.
+	// +optional
+	ImageDigestSources []ImageDigestSource `json:"imageDigestSources,omitempty"`
+
 	// Publish controls how the user facing endpoints of the cluster like the Kubernetes API, OpenShift routes etc. are exposed.
 	// When no strategy is specified, the strategy is "External".
 	//
@@ -190,6 +239,69 @@ type InstallConfig struct {
 	// FeatureSet enables features that are not part of the default feature set.
 	// +optional
 	FeatureSet configv1.FeatureSet `json:"featureSet,omitempty"`
+
+	// NodeConfig configures the kubelet and tunes the nodes of the cluster.
+	// +optional
+	NodeConfig *NodeConfig `json:"nodeConfig,omitempty"`
+
+	// WaitTimeouts configures how long the installer waits for the phases
+	// of the install to complete. The --wait-timeout flag takes precedence.
+	// +optional
+	WaitTimeouts *WaitTimeouts `json:"waitTimeouts,omitempty"`
+
+	// Hooks are run by the installer between the phases of the install.
+	// +optional
+	Hooks *Hooks `json:"hooks,omitempty"`
+
+	// GatherProxy configures how `openshift-install gather bootstrap`
+	// reaches the hosts of the cluster over SSH. The --bastion and
+	// --socks-proxy flags take precedence.
+	// +optional
+	GatherProxy *GatherProxy `json:"gatherProxy,omitempty"`
+
+	// ReleaseImage is the release payload to install instead of the one
+	// the installer was built for. The --release-image flag takes
+	// precedence.
+	// +optional
+	ReleaseImage *ReleaseImage `json:"releaseImage,omitempty"`
+
+	// IgnitionSnippets are Butane configs merged into the Ignition config of
+	// the bootstrap machine and the pointer Ignition configs of the control
+	// plane and compute machines.
+	// +optional
+	IgnitionSnippets []IgnitionSnippet `json:"ignitionSnippets,omitempty"`
+
+	// NTP configures the time sources chrony synchronizes the clocks of the
+	// bootstrap, control plane and compute machines with. The ntp of a
+	// machine pool overrides it for the machines of the pool.
+	// +optional
+	NTP *NTP `json:"ntp,omitempty"`
+
+	// Kubeadmin configures the kubeadmin user, and the break-glass users
+	// which can replace it.
+	// +optional
+	Kubeadmin *Kubeadmin `json:"kubeadmin,omitempty"`
+
+	// IdentityProviders are the identity providers the OAuth server of the
+	// cluster authenticates users with.
+	// +optional
+	IdentityProviders []IdentityProvider `json:"identityProviders,omitempty"`
+
+	// Etcd configures the disk and the tuning of the etcd members of the
+	// cluster.
+	// +optional
+	Etcd *Etcd `json:"etcd,omitempty"`
+
+	// ServingCertificates are the certificates the API and the default
+	// ingress controller serve from the start, instead of ones issued by the
+	// cluster.
+	// +optional
+	ServingCertificates *ServingCertificates `json:"servingCertificates,omitempty"`
+
+	// APIServer configures the audit policy of the API servers and the
+	// encryption of the resources they store in etcd.
+	// +optional
+	APIServer *APIServer `json:"apiServer,omitempty"`
 }
 
 // ClusterDomain returns the DNS domain that all records for a cluster must belong to.
@@ -197,6 +309,22 @@ func (c *InstallConfig) ClusterDomain() string {
 	return fmt.Sprintf("%s.%s", c.ObjectMeta.Name, strings.TrimSuffix(c.BaseDomain, "."))
 }
 
+// APIDomainName returns the domain name of the API of the cluster.
+func (c *InstallConfig) APIDomainName() string {
+	if c.APIDomain != "" {
+		return strings.TrimSuffix(c.APIDomain, ".")
+	}
+	return fmt.Sprintf("api.%s", c.ClusterDomain())
+}
+
+// AppsDomainName returns the domain of the routes of the cluster.
+func (c *InstallConfig) AppsDomainName() string {
+	if c.AppsDomain != "" {
+		return strings.TrimSuffix(c.AppsDomain, ".")
+	}
+	return fmt.Sprintf("apps.%s", c.ClusterDomain())
+}
+
 // IsFCOS returns true if Fedora CoreOS-only modifications are enabled
 func (c *InstallConfig) IsFCOS() bool {
 	return FCOS
@@ -218,6 +346,109 @@ func (c *InstallConfig) IsSingleNodeOpenShift() bool {
 	return c.BootstrapInPlace != nil
 }
 
+// IsBootstrapInPlace returns true if the single node of the cluster is
+// booted from the live ISO to install itself, without a bootstrap machine or
+// the machine API provisioning it.
+func (c *InstallConfig) IsBootstrapInPlace() bool {
+	if !c.IsSingleNodeOpenShift() {
+		return false
+	}
+	for _, platform := range BootstrapInPlacePlatforms {
+		if platform == c.Platform.Name() {
+			return true
+		}
+	}
+	return false
+}
+
+// SingleReplicaControlPlaneMinimumVCPUs is the minimum number of vCPUs of the
+// machine of a single-replica control plane, which also runs the workloads of
+// the cluster when there are no compute replicas.
+const SingleReplicaControlPlaneMinimumVCPUs = 8
+
+// IsSingleReplicaControlPlane returns true if the install-config has been
+// configured for a control plane of a single replica, which runs the workloads
+// of the cluster when there are no compute replicas.
+func (c *InstallConfig) IsSingleReplicaControlPlane() bool {
+	return c.ControlPlane != nil && c.ControlPlane.Replicas != nil && *c.ControlPlane.Replicas == 1
+}
+
+// IsArbiterEnabled returns true if the install-config has been configured for
+// a two-node cluster with an arbiter.
+func (c *InstallConfig) IsArbiterEnabled() bool {
+	return c.Arbiter != nil
+}
+
+// EtcdReplicas returns the number of etcd members of the cluster, which run
+// on the control plane machines and on the arbiter.
+func (c *InstallConfig) EtcdReplicas() int64 {
+	var replicas int64
+	if c.ControlPlane != nil && c.ControlPlane.Replicas != nil {
+		replicas += *c.ControlPlane.Replicas
+	}
+	if c.Arbiter != nil && c.Arbiter.Replicas != nil {
+		replicas += *c.Arbiter.Replicas
+	}
+	return replicas
+}
+
+// IsCapabilityEnabled returns true if the capability is enabled by the
+// baseline capability set or the additional enabled capabilities.
+func (c *InstallConfig) IsCapabilityEnabled(capability configv1.ClusterVersionCapability) bool {
+	if c.Capabilities == nil {
+		return true
+	}
+	baseline := c.Capabilities.BaselineCapabilitySet
+	if baseline == "" {
+		baseline = configv1.ClusterVersionCapabilitySetCurrent
+	}
+	for _, enabled := range append(configv1.ClusterVersionCapabilitySets[baseline], c.Capabilities.AdditionalEnabledCapabilities...) {
+		if enabled == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// KubeadminDisabled returns whether the kubeadmin user is disabled.
+func (c *InstallConfig) KubeadminDisabled() bool {
+	return c.Kubeadmin != nil && c.Kubeadmin.Disabled
+}
+
+// NTPFor returns the time sources of the machines in the pool: the ntp of
+// the pool when set, and the ntp of the install-config otherwise.
+func (c *InstallConfig) NTPFor(pool *MachinePool) *NTP {
+	if pool != nil && pool.NTP != nil {
+		return pool.NTP
+	}
+	return c.NTP
+}
+
+// InfraPool returns the infra compute pool, or nil when the install-config
+// has none.
+func (c *InstallConfig) InfraPool() *MachinePool {
+	for i := range c.Compute {
+		if c.Compute[i].Name == MachinePoolInfraRoleName {
+			return &c.Compute[i]
+		}
+	}
+	return nil
+}
+
+// SSHKeys returns the public keys listed in SSHKey, skipping blank lines and
+// comments.
+func (c *InstallConfig) SSHKeys() []string {
+	var keys []string
+	for _, line := range strings.Split(c.SSHKey, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keys = append(keys, line)
+	}
+	return keys
+}
+
 // Platform is the configuration for the specific platform upon which to perform
 // the installation. Only one of the platform configuration should be set.
 type Platform struct {
@@ -312,6 +543,31 @@ func (p *Platform) Name() string {
 	}
 }
 
+// LoadBalancer returns the load balancer of the API and of the ingress of
+// the on-prem platforms, or nil if the platform has none or the default.
+func (p *Platform) LoadBalancer() *loadbalancer.LoadBalancer {
+	switch {
+	case p == nil:
+		return nil
+	case p.BareMetal != nil:
+		return p.BareMetal.LoadBalancer
+	case p.OpenStack != nil:
+		return p.OpenStack.LoadBalancer
+	case p.VSphere != nil:
+		return p.VSphere.LoadBalancer
+	case p.Nutanix != nil:
+		return p.Nutanix.LoadBalancer
+	default:
+		return nil
+	}
+}
+
+// IsLoadBalancerUserManaged returns whether the API and the ingress are
+// served by a load balancer the user manages instead of VIPs of the cluster.
+func (p *Platform) IsLoadBalancerUserManaged() bool {
+	return loadbalancer.IsUserManaged(p.LoadBalancer())
+}
+
 // Networking defines the pod network provider in the cluster.
 type Networking struct {
 	// NetworkType is the type of network to install.
@@ -327,6 +583,8 @@ type Networking struct {
 	// Default is 10.0.0.0/16 for all platforms other than libvirt and Power VS.
 	// For libvirt, the default is 192.168.126.0/24.
 	// For Power VS, the default is 192.168.0.0/24.
+	// When installing into existing AWS subnets, Azure VNet or GCP network,
+	// the default is the CIDRs of the subnets.
 	//
 	// +optional
 	MachineNetwork []MachineNetworkEntry `json:"machineNetwork,omitempty"`
@@ -345,6 +603,12 @@ type Networking struct {
 	// +optional
 	ServiceNetwork []ipnet.IPNet `json:"serviceNetwork,omitempty"`
 
+	// OVNKubernetesConfig contains day-1 configuration for the OVNKubernetes
+	// network plugin. It may only be set when networkType is OVNKubernetes.
+	//
+	// +optional
+	OVNKubernetesConfig *OVNKubernetesConfig `json:"ovnKubernetesConfig,omitempty"`
+
 	// Deprecated types, scheduled to be removed
 
 	// Deprecated way to configure an IP address pool for machines.
@@ -366,6 +630,85 @@ type Networking struct {
 	DeprecatedClusterNetworks []ClusterNetworkEntry `json:"clusterNetworks,omitempty"`
 }
 
+// OVNGatewayMode is the mode used by OVNKubernetes to route egress traffic
+// from pods.
+// +kubebuilder:validation:Enum="";Shared;Local
+type OVNGatewayMode string
+
+const (
+	// OVNGatewayModeShared sends pod egress traffic directly from OVN to the
+	// node's external interface.
+	OVNGatewayModeShared OVNGatewayMode = "Shared"
+
+	// OVNGatewayModeLocal routes pod egress traffic through the host's
+	// networking stack.
+	OVNGatewayModeLocal OVNGatewayMode = "Local"
+)
+
+// OVNKubernetesConfig contains day-1 configuration for the OVNKubernetes
+// network plugin.
+type OVNKubernetesConfig struct {
+	// V4InternalSubnet is the IPv4 subnet used internally by OVNKubernetes to
+	// connect nodes. It must not overlap with any other network used by the
+	// cluster. The default is 100.64.0.0/16.
+	//
+	// +optional
+	V4InternalSubnet *ipnet.IPNet `json:"v4InternalSubnet,omitempty"`
+
+	// V6InternalSubnet is the IPv6 subnet used internally by OVNKubernetes to
+	// connect nodes. It must not overlap with any other network used by the
+	// cluster. The default is fd98::/48.
+	//
+	// +optional
+	V6InternalSubnet *ipnet.IPNet `json:"v6InternalSubnet,omitempty"`
+
+	// GatewayMode selects how pod egress traffic leaves the node. It is
+	// rendered as the routingViaHost setting of the OVNKubernetes gateway
+	// config and controls nothing else: "Local" sets routingViaHost so that
+	// traffic is routed through the host's networking stack, "Shared" sends
+	// it directly from OVN. The default is Shared, except on Power VS where
+	// host routing is required.
+	//
+	// +optional
+	GatewayMode OVNGatewayMode `json:"gatewayMode,omitempty"`
+
+	// IPsec enables IPsec encryption of traffic between pods on different nodes.
+	//
+	// +optional
+	IPsec bool `json:"ipsec,omitempty"`
+
+	// MTU is the MTU to use for the pod network. It must be at least 100
+	// bytes smaller than the MTU of the machine network to leave room for
+	// the Geneve overlay header. The default is detected from the node's
+	// primary interface.
+	//
+	// +kubebuilder:validation:Minimum=576
+	// +optional
+	MTU uint32 `json:"mtu,omitempty"`
+
+	// HybridOverlay configures the hybrid overlay network, which connects
+	// the Windows machines of the windows compute pool to the pod network.
+	// It is required by the windows compute pool.
+	//
+	// +optional
+	HybridOverlay *HybridOverlayConfig `json:"hybridOverlay,omitempty"`
+}
+
+// HybridOverlayConfig configures the hybrid overlay network of OVNKubernetes.
+type HybridOverlayConfig struct {
+	// HybridClusterNetwork are the IP address pools of the pods of the
+	// Windows machines. They must not overlap with the other networks of the
+	// cluster.
+	HybridClusterNetwork []ClusterNetworkEntry `json:"hybridClusterNetwork"`
+
+	// VXLANPort is the VXLAN port of the hybrid overlay network.
+	// The default is 4789.
+	//
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	VXLANPort uint32 `json:"vxlanPort,omitempty"`
+}
+
 // MachineNetworkEntry is a single IP address block for node IP blocks.
 type MachineNetworkEntry struct {
 	// CIDR is the IP block address pool for machines within the cluster.
@@ -416,6 +759,30 @@ type ImageContentSource struct {
 	Mirrors []string `json:"mirrors,omitempty"`
 }
 
+// ImageDigestSource defines a list of sources/repositories that can be used to pull content.
+type ImageDigestSource struct {
+	// Source is the repository that users refer to, e.g. in image pull specifications.
+	Source string `json:"source"`
+
+	// Mirrors is one or more repositories that may also contain the same images.
+	// +optional
+	Mirrors []string `json:"mirrors,omitempty"`
+}
+
+// MirrorSources returns the sources/repositories for the release-image
+// content, whether they are given as ImageDigestSources or as the deprecated
+// ImageContentSources.
+func (c *InstallConfig) MirrorSources() []ImageDigestSource {
+	if len(c.ImageDigestSources) > 0 {
+		return c.ImageDigestSources
+	}
+	sources := make([]ImageDigestSource, 0, len(c.ImageContentSources))
+	for _, source := range c.ImageContentSources {
+		sources = append(sources, ImageDigestSource{Source: source.Source, Mirrors: source.Mirrors})
+	}
+	return sources
+}
+
 // CredentialsMode is the mode by which CredentialsRequests will be satisfied.
 // +kubebuilder:validation:Enum="";Mint;Passthrough;Manual
 type CredentialsMode string
@@ -439,11 +806,64 @@ type BootstrapInPlace struct {
 	InstallationDisk string `json:"installationDisk"`
 }
 
+const (
+	// CapabilitySetMinimal is an installer-defined preset enabling only the
+	// capabilities required for a cluster with persistent storage.
+	CapabilitySetMinimal configv1.ClusterVersionCapabilitySet = "Minimal"
+
+	// CapabilitySetVirtualization is an installer-defined preset enabling the
+	// capabilities needed to run OpenShift Virtualization.
+	CapabilitySetVirtualization configv1.ClusterVersionCapabilitySet = "Virtualization"
+)
+
+// capabilityPresets are the installer-defined capability sets which may be
+// selected as the baselineCapabilitySet. The cluster-version operator does not
+// know about these sets, so they are expanded into the None baseline plus
+// additionalEnabledCapabilities before the install-config is consumed.
+var capabilityPresets = map[configv1.ClusterVersionCapabilitySet][]configv1.ClusterVersionCapability{
+	CapabilitySetMinimal: {
+		configv1.ClusterVersionCapabilityStorage,
+		configv1.ClusterVersionCapabilityCSISnapshot,
+	},
+	CapabilitySetVirtualization: {
+		configv1.ClusterVersionCapabilityConsole,
+		configv1.ClusterVersionCapabilityMarketplace,
+		configv1.ClusterVersionCapabilityStorage,
+		configv1.ClusterVersionCapabilityCSISnapshot,
+		configv1.ClusterVersionCapabilityNodeTuning,
+	},
+}
+
+// CapabilityPreset returns the capabilities enabled by the named
+// installer-defined preset and whether such a preset exists. The returned
+// slice is a copy and may be modified by the caller.
+func CapabilityPreset(name configv1.ClusterVersionCapabilitySet) ([]configv1.ClusterVersionCapability, bool) {
+	preset, ok := capabilityPresets[name]
+	if !ok {
+		return nil, false
+	}
+	return append([]configv1.ClusterVersionCapability(nil), preset...), true
+}
+
+// CapabilityPresetNames returns the names of the installer-defined presets.
+func CapabilityPresetNames() []configv1.ClusterVersionCapabilitySet {
+	names := make([]configv1.ClusterVersionCapabilitySet, 0, len(capabilityPresets))
+	for name := range capabilityPresets {
+		names = append(names, name)
+	}
+	return names
+}
+
 // Capabilities selects the managed set of optional, core cluster components.
 type Capabilities struct {
 	// baselineCapabilitySet selects an initial set of
 	// optional capabilities to enable, which can be extended via
 	// additionalEnabledCapabilities. The default is vCurrent.
+	// In addition to the sets known to the cluster, the installer-defined
+	// presets Minimal and Virtualization may be used. A preset is replaced by
+	// the None set, with the preset's capabilities added to
+	// additionalEnabledCapabilities, in the install-config consumed by the
+	// installer.
 	// +optional
 	BaselineCapabilitySet configv1.ClusterVersionCapabilitySet `json:"baselineCapabilitySet,omitempty"`
 
@@ -454,6 +874,157 @@ type Capabilities struct {
 	AdditionalEnabledCapabilities []configv1.ClusterVersionCapability `json:"additionalEnabledCapabilities,omitempty"`
 }
 
+// NodeConfig is the kubelet configuration and tuning applied to all the nodes
+// of the cluster.
+type NodeConfig struct {
+	// MaxPods is the maximum number of pods which can run on a node.
+	// The default is 250.
+	// +optional
+	MaxPods int32 `json:"maxPods,omitempty"`
+
+	// SystemReserved are the resources reserved on each node for the
+	// operating system daemons, keyed by cpu, memory or ephemeral-storage,
+	// e.g. cpu: 500m.
+	// +optional
+	SystemReserved map[string]string `json:"systemReserved,omitempty"`
+
+	// CgroupMode selects the cgroups version of the nodes, v1 or v2.
+	// +optional
+	CgroupMode configv1.CgroupMode `json:"cgroupMode,omitempty"`
+
+	// CPUPartitioning pins the platform workloads to a set of CPUs on each
+	// node.
+	// +optional
+	CPUPartitioning *CPUPartitioning `json:"cpuPartitioning,omitempty"`
+}
+
+// CPUPartitioning configures workload partitioning.
+type CPUPartitioning struct {
+	// ReservedCPUs is the cpuset the platform workloads are pinned to, in
+	// the Linux CPU list format, e.g. 0-1,4.
+	ReservedCPUs string `json:"reservedCPUs"`
+}
+
+// WaitTimeouts are the durations the installer waits for the phases of the
+// install. Phases left unset use the default of the installer.
+type WaitTimeouts struct {
+	// BootstrapComplete is how long to wait for bootstrapping to complete
+	// once the Kubernetes API is up. The default is 30 minutes, or 60
+	// minutes on baremetal.
+	// +optional
+	BootstrapComplete *metav1.Duration `json:"bootstrapComplete,omitempty"`
+
+	// ClusterOperatorsStable is how long to wait for the cluster operators
+	// to become available once bootstrapping is complete. The default is 40
+	// minutes, or 60 minutes on baremetal.
+	// +optional
+	ClusterOperatorsStable *metav1.Duration `json:"clusterOperatorsStable,omitempty"`
+
+	// ConsoleRoute is how long to wait for the route of the web console.
+	// The default is 2 minutes.
+	// +optional
+	ConsoleRoute *metav1.Duration `json:"consoleRoute,omitempty"`
+}
+
+// Hooks are the site-specific steps run between the phases of the install.
+// Each hook is either the absolute path of an executable, which is run with
+// the asset directory and the kubeconfig in its environment, or an http(s)
+// URL, which is sent a POST request describing the phase. A failing hook
+// stops the install.
+type Hooks struct {
+	// PreProvision hooks run before the infrastructure is created.
+	// +optional
+	PreProvision []string `json:"preProvision,omitempty"`
+
+	// PostBootstrap hooks run once bootstrapping is complete and the
+	// bootstrap resources are destroyed.
+	// +optional
+	PostBootstrap []string `json:"postBootstrap,omitempty"`
+
+	// PostInstall hooks run once the install is complete.
+	// +optional
+	PostInstall []string `json:"postInstall,omitempty"`
+}
+
+// BreakGlassIdentityProvider is the name of the htpasswd identity provider
+// of the users of Kubeadmin.HTPasswd.
+const BreakGlassIdentityProvider = "break-glass"
+
+// Kubeadmin configures the temporary kubeadmin user of the cluster.
+type Kubeadmin struct {
+	// Disabled skips creating the kubeadmin user and its password.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// HTPasswd is an htpasswd file with bcrypt password hashes. Its users are
+	// configured as the break-glass htpasswd identity provider of the
+	// cluster and bound to the cluster-admin role.
+	// +optional
+	HTPasswd string `json:"htpasswd,omitempty"`
+}
+
+// NTP configures the time sources of chrony.
+type NTP struct {
+	// Servers are the hostnames or IP addresses of NTP servers.
+	// +optional
+	Servers []string `json:"servers,omitempty"`
+
+	// Pools are the hostnames of NTP pools, which resolve to several
+	// servers.
+	// +optional
+	Pools []string `json:"pools,omitempty"`
+}
+
+// IgnitionSnippet is a Butane config merged into the Ignition configs of
+// machines.
+type IgnitionSnippet struct {
+	// Name identifies the snippet.
+	Name string `json:"name"`
+
+	// Roles are the roles of the machines the snippet is merged into:
+	// bootstrap, master or worker.
+	Roles []string `json:"roles"`
+
+	// Butane is the Butane config, of the fcos or openshift variant. Its
+	// files, directories, links, systemd units and SSH keys of users are
+	// merged; files must be inline or remote.
+	Butane string `json:"butane"`
+}
+
+// GatherProxy is how the installer tunnels its SSH connections to the hosts
+// of a cluster that are not reachable directly, e.g. in a private network.
+// At most one of its fields may be set.
+type GatherProxy struct {
+	// Bastion is the [user@]host[:port] of an SSH jump host to connect
+	// through. The user defaults to core and the port to 22.
+	// +optional
+	Bastion string `json:"bastion,omitempty"`
+
+	// SOCKS is the host:port of a SOCKS5 proxy to connect through.
+	// +optional
+	SOCKS string `json:"socks,omitempty"`
+}
+
+// ReleaseImage is a release payload to install instead of the one the
+// installer was built for.
+type ReleaseImage struct {
+	// PullSpec is the pull spec of the release image. It must reference the
+	// image by digest when verification keys are given.
+	PullSpec string `json:"pullSpec"`
+
+	// VerificationKeys are ASCII-armored GPG public keys. When set, the
+	// install does not proceed unless the release image has a signature
+	// made with one of them.
+	// +optional
+	VerificationKeys []string `json:"verificationKeys,omitempty"`
+
+	// SignatureStores are the base URLs of the stores the signatures of
+	// the release image are looked up in. The default is the OpenShift
+	// signature store.
+	// +optional
+	SignatureStores []string `json:"signatureStores,omitempty"`
+}
+
 // WorkerMachinePool retrieves the worker MachinePool from InstallConfig.Compute
 func (c *InstallConfig) WorkerMachinePool() *MachinePool {
 	for _, machinePool := range c.Compute {
@@ -0,0 +1,4 @@
+// Package loadbalancer contains the load balancer configuration shared by the
+// on-prem platforms, whose API and ingress are by default served from VIPs
+// managed by keepalived and haproxy on the nodes.
+package loadbalancer
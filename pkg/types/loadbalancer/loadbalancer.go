@@ -0,0 +1,31 @@
+package loadbalancer
+
+// Type is the type of the load balancer of the API and of the ingress.
+// +kubebuilder:validation:Enum:="OpenShiftManagedDefault";"UserManaged"
+type Type string
+
+const (
+	// OpenShiftManagedDefault is the load balancer of the cluster: the VIPs
+	// of the API and of the ingress, which keepalived moves between the
+	// nodes, in front of haproxy.
+	OpenShiftManagedDefault Type = "OpenShiftManagedDefault"
+
+	// UserManaged is a load balancer the user provisions outside of the
+	// cluster, in front of the nodes. The cluster runs neither keepalived
+	// nor haproxy.
+	UserManaged Type = "UserManaged"
+)
+
+// LoadBalancer is the load balancer of the API and of the ingress.
+type LoadBalancer struct {
+	// Type is the type of the load balancer.
+	//
+	// +kubebuilder:default:="OpenShiftManagedDefault"
+	// +optional
+	Type Type `json:"type,omitempty"`
+}
+
+// IsUserManaged returns whether the load balancer is managed by the user.
+func IsUserManaged(lb *LoadBalancer) bool {
+	return lb != nil && lb.Type == UserManaged
+}
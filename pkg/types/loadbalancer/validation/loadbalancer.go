@@ -0,0 +1,28 @@
+// Package validation contains validation for the load balancer configuration
+// of the on-prem platforms.
+package validation
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types/loadbalancer"
+)
+
+var validTypes = []string{
+	string(loadbalancer.OpenShiftManagedDefault),
+	string(loadbalancer.UserManaged),
+}
+
+// ValidateLoadBalancer checks that the type of the load balancer is
+// supported.
+func ValidateLoadBalancer(lb *loadbalancer.LoadBalancer, fldPath *field.Path) field.ErrorList {
+	if lb == nil {
+		return nil
+	}
+	switch lb.Type {
+	case "", loadbalancer.OpenShiftManagedDefault, loadbalancer.UserManaged:
+		return nil
+	default:
+		return field.ErrorList{field.NotSupported(fldPath.Child("type"), lb.Type, validTypes)}
+	}
+}
@@ -20,6 +20,18 @@ const (
 	MachinePoolComputeRoleName = "worker"
 	// MachinePoolControlPlaneRoleName name associated with the control plane machinepool
 	MachinePoolControlPlaneRoleName = "master"
+	// MachinePoolArbiterRoleName name associated with the arbiter machinepool
+	MachinePoolArbiterRoleName = "arbiter"
+	// MachinePoolInfraRoleName name associated with the infra machinepool,
+	// whose machines run the ingress controller and monitoring
+	MachinePoolInfraRoleName = "infra"
+	// MachinePoolWindowsRoleName name associated with the machinepool of
+	// Windows machines
+	MachinePoolWindowsRoleName = "windows"
+
+	// InfraNodeRoleLabel is the label and taint key of the nodes of the
+	// infra machinepool
+	InfraNodeRoleLabel = "node-role.kubernetes.io/infra"
 )
 
 // HyperthreadingMode is the mode of hyperthreading for a machine.
@@ -52,7 +64,10 @@ const (
 type MachinePool struct {
 	// Name is the name of the machine pool.
 	// For the control plane machine pool, the name will always be "master".
-	// For the compute machine pools, the only valid name is "worker".
+	// For the compute machine pools, the valid names are "worker", "infra"
+	// and "windows". The machines of the "infra" pool are labeled and tainted
+	// to run the ingress controller and monitoring only. The "windows" pool
+	// is the pool of Windows machines.
 	Name string `json:"name"`
 
 	// Replicas is the machine count for the machine pool.
@@ -75,6 +90,270 @@ type MachinePool struct {
 	// +kubebuilder:default=amd64
 	// +optional
 	Architecture Architecture `json:"architecture,omitempty"`
+
+	// DiskEncryption encrypts the root disk of the machines in the pool with
+	// LUKS, unlocked on boot by the TPM2 of the machine and/or Tang servers.
+	// +optional
+	DiskEncryption *DiskEncryption `json:"diskEncryption,omitempty"`
+
+	// DiskMirroring mirrors the boot and root partitions of the machines in
+	// the pool across several disks with software RAID-1.
+	// +optional
+	DiskMirroring *DiskMirroring `json:"diskMirroring,omitempty"`
+
+	// MachineConfig adds files, systemd units and kernel arguments to the
+	// machines in the pool.
+	// +optional
+	MachineConfig *MachineConfigCustomization `json:"machineConfig,omitempty"`
+
+	// NTP overrides the time sources of the install-config for the machines
+	// in the pool.
+	// +optional
+	NTP *NTP `json:"ntp,omitempty"`
+
+	// MachineSetsInAllZones generates a MachineSet in every zone of the
+	// region for the compute pool. The zones of the region in which the pool
+	// has no machines at install get MachineSets of zero replicas, which can
+	// be scaled up on day 2. Only supported on AWS, Azure and GCP.
+	// +optional
+	MachineSetsInAllZones bool `json:"machineSetsInAllZones,omitempty"`
+
+	// ControlPlaneMachineSetState is the state of the ControlPlaneMachineSet
+	// generated for the control plane pool on AWS, Azure and GCP. When
+	// Active, the control plane machines can be resized right after install
+	// by updating the ControlPlaneMachineSet. When Inactive, it has to be
+	// activated on day 2 first.
+	// Defaults to Active.
+	// +optional
+	ControlPlaneMachineSetState ControlPlaneMachineSetState `json:"controlPlaneMachineSetState,omitempty"`
+
+	// Autoscaling enables the cluster autoscaler for the compute pool, which
+	// scales the machines of the pool within the given range.
+	// +optional
+	Autoscaling *MachinePoolAutoscaling `json:"autoscaling,omitempty"`
+
+	// OSType is the operating system of the machines of the compute pool.
+	// Windows machines are only supported in the "windows" pool on AWS and
+	// Azure, booted from the image set in platform.aws.amiID or
+	// platform.azure.osImage. They are configured by the Windows Machine
+	// Config Operator, which is installed on day 2, and require the hybrid
+	// overlay of OVNKubernetes.
+	// Defaults to Linux.
+	// +optional
+	OSType OSType `json:"osType,omitempty"`
+
+	// Accelerators configures the GPUs of the machines of the compute pool.
+	// The instance type of the pool must provide GPUs of the vendor. The
+	// nodes are labeled and tainted for the GPUs, so that only workloads
+	// which request them are scheduled on the nodes. Only supported on AWS
+	// and Azure.
+	// +optional
+	Accelerators *Accelerators `json:"accelerators,omitempty"`
+
+	// ContainerStorage places the container storage of the compute
+	// machines on a dedicated disk, so that the images and writable layers
+	// of the containers do not fill the root disk. On AWS, Azure and GCP the
+	// disk is attached to the machines by the installer. On baremetal and
+	// none an existing disk of the machines is used.
+	// +optional
+	ContainerStorage *ContainerStorage `json:"containerStorage,omitempty"`
+}
+
+// ContainerStorage is the dedicated disk of the container storage of a
+// compute pool. The disk is formatted as a single XFS filesystem and mounted
+// to the mount path.
+type ContainerStorage struct {
+	// MountPath is the directory placed on the disk: /var/lib/containers,
+	// which holds the containers of CRI-O, or /var, which also holds the
+	// logs and the data of the kubelet.
+	// Defaults to /var/lib/containers.
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+
+	// SizeGB is the size of the disk attached on AWS, Azure and GCP in GB,
+	// at least 20.
+	// Defaults to 100.
+	// +optional
+	SizeGB int64 `json:"sizeGB,omitempty"`
+
+	// Type is the type of the disk attached on AWS, Azure and GCP: gp2 or
+	// gp3 on AWS, Premium_LRS on Azure, and pd-ssd or pd-balanced on GCP.
+	// Defaults to gp3 on AWS, Premium_LRS on Azure and pd-ssd on GCP.
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Device is the existing disk of the machines on baremetal and none,
+	// such as /dev/disk/by-path/pci-0000:00:1f.2-ata-2. It is wiped when
+	// the machines are installed.
+	// +optional
+	Device string `json:"device,omitempty"`
+}
+
+const (
+	// ContainerStorageMountPathContainers places /var/lib/containers on the
+	// dedicated disk.
+	ContainerStorageMountPathContainers = "/var/lib/containers"
+	// ContainerStorageMountPathVar places /var on the dedicated disk.
+	ContainerStorageMountPathVar = "/var"
+)
+
+// Accelerators configures the GPUs of the machines of a compute pool.
+type Accelerators struct {
+	// Vendor is the vendor of the GPUs of the instance type of the pool.
+	Vendor AcceleratorVendor `json:"vendor"`
+
+	// NodeFeatureDiscovery installs the Node Feature Discovery operator,
+	// which labels the nodes with the details of their GPUs, such as the
+	// PCI devices required by the GPU operator of the vendor.
+	// +optional
+	NodeFeatureDiscovery bool `json:"nodeFeatureDiscovery,omitempty"`
+}
+
+// AcceleratorVendor is the vendor of the GPUs of a pool.
+// +kubebuilder:validation:Enum=NVIDIA;AMD
+type AcceleratorVendor string
+
+const (
+	// AcceleratorVendorNVIDIA indicates NVIDIA GPUs.
+	AcceleratorVendorNVIDIA AcceleratorVendor = "NVIDIA"
+	// AcceleratorVendorAMD indicates AMD GPUs.
+	AcceleratorVendorAMD AcceleratorVendor = "AMD"
+)
+
+// ResourceName returns the name of the extended resource of the GPUs of the
+// vendor, which is also the key of their node label and taint.
+func (v AcceleratorVendor) ResourceName() string {
+	switch v {
+	case AcceleratorVendorNVIDIA:
+		return "nvidia.com/gpu"
+	case AcceleratorVendorAMD:
+		return "amd.com/gpu"
+	default:
+		return ""
+	}
+}
+
+// OSType is the operating system of the machines of a pool.
+// +kubebuilder:validation:Enum="";Linux;Windows
+type OSType string
+
+const (
+	// OSTypeLinux indicates RHCOS machines.
+	OSTypeLinux OSType = "Linux"
+	// OSTypeWindows indicates Windows machines.
+	OSTypeWindows OSType = "Windows"
+)
+
+// MachinePoolAutoscaling is the range within which the cluster autoscaler
+// scales the machines of a compute pool. The range is distributed across the
+// MachineSets of the pool.
+type MachinePoolAutoscaling struct {
+	// MinReplicas is the minimum number of machines of the pool.
+	MinReplicas int64 `json:"minReplicas"`
+
+	// MaxReplicas is the maximum number of machines of the pool.
+	MaxReplicas int64 `json:"maxReplicas"`
+}
+
+// ControlPlaneMachineSetState is the state of the ControlPlaneMachineSet of
+// the control plane.
+// +kubebuilder:validation:Enum="";Active;Inactive
+type ControlPlaneMachineSetState string
+
+const (
+	// ControlPlaneMachineSetStateActive indicates that the
+	// ControlPlaneMachineSet manages the control plane machines.
+	ControlPlaneMachineSetStateActive ControlPlaneMachineSetState = "Active"
+	// ControlPlaneMachineSetStateInactive indicates that the
+	// ControlPlaneMachineSet does not manage the control plane machines
+	// until it is activated.
+	ControlPlaneMachineSetStateInactive ControlPlaneMachineSetState = "Inactive"
+)
+
+// MachineConfigCustomization is the configuration rendered into a
+// MachineConfig for the role of the machine pool.
+type MachineConfigCustomization struct {
+	// Files are the files to write to the machines.
+	// +optional
+	Files []MachineConfigFile `json:"files,omitempty"`
+
+	// Units are the systemd units to install on the machines.
+	// +optional
+	Units []MachineConfigUnit `json:"units,omitempty"`
+
+	// KernelArguments are the kernel arguments to add to the machines.
+	// +optional
+	KernelArguments []string `json:"kernelArguments,omitempty"`
+
+	// Ignition is an Ignition config in JSON, e.g. as generated by Butane,
+	// which is merged with the files and units above. Ignition spec versions
+	// 3.0.0 to 3.2.0 are supported.
+	// +optional
+	Ignition string `json:"ignition,omitempty"`
+}
+
+// MachineConfigFile is a file written to the machines.
+type MachineConfigFile struct {
+	// Path is the absolute path of the file.
+	Path string `json:"path"`
+
+	// Contents are the contents of the file.
+	Contents string `json:"contents"`
+
+	// Mode is the permission mode of the file, in decimal.
+	// Defaults to 420 (0644).
+	// +optional
+	Mode *int `json:"mode,omitempty"`
+}
+
+// MachineConfigUnit is a systemd unit installed on the machines.
+type MachineConfigUnit struct {
+	// Name is the name of the unit, including its type suffix,
+	// e.g. example.service.
+	Name string `json:"name"`
+
+	// Contents are the contents of the unit.
+	Contents string `json:"contents"`
+
+	// Enabled enables the unit.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// DiskEncryption configures the LUKS encryption of the root disk and the
+// clevis bindings which unlock it.
+type DiskEncryption struct {
+	// TPM2 binds the encryption key to the TPM2 of the machine.
+	// +optional
+	TPM2 bool `json:"tpm2,omitempty"`
+
+	// Tang binds the encryption key to each of the Tang servers.
+	// +optional
+	Tang []TangServer `json:"tang,omitempty"`
+
+	// Threshold is the number of TPM2 and Tang bindings which must succeed
+	// to unlock the disk.
+	// Defaults to 1.
+	// +optional
+	Threshold int `json:"threshold,omitempty"`
+}
+
+// TangServer is a Tang server the root disk encryption key is bound to.
+type TangServer struct {
+	// URL is the URL of the Tang server.
+	URL string `json:"url"`
+
+	// Thumbprint is the thumbprint of the key advertised by the Tang server
+	// which is trusted.
+	Thumbprint string `json:"thumbprint"`
+}
+
+// DiskMirroring configures the disks the boot and root partitions are
+// mirrored across.
+type DiskMirroring struct {
+	// Devices are the paths of the disks to mirror across, e.g. /dev/sda.
+	// All data on the disks is erased.
+	Devices []string `json:"devices"`
 }
 
 // MachinePoolPlatform is the platform-specific configuration for a machine
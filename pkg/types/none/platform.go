@@ -1,5 +1,14 @@
 package none
 
+import (
+	"github.com/openshift/installer/pkg/types/hostnetwork"
+)
+
 // Platform stores any global configuration used for generic
 // platforms.
-type Platform struct{}
+type Platform struct {
+	// Hosts is the day-1 network configuration of the hosts, such as bonds,
+	// VLANs and static routes, applied to each host matched by its identity.
+	// +optional
+	Hosts []hostnetwork.Host `json:"hosts,omitempty"`
+}
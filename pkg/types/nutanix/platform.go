@@ -1,5 +1,7 @@
 package nutanix
 
+import "github.com/openshift/installer/pkg/types/loadbalancer"
+
 // Platform stores any global configuration used for Nutanix platforms.
 type Platform struct {
 	// PrismCentral is the endpoint (address and port) and credentials to
@@ -47,6 +49,14 @@ type Platform struct {
 	// +optional
 	IngressVIPs []string `json:"ingressVIPs,omitempty"`
 
+	// LoadBalancer is the load balancer of the API and of the ingress. With a
+	// UserManaged load balancer, the API and ingress VIPs are optional: they
+	// are the addresses of the load balancer, which the cluster does not
+	// serve with keepalived and haproxy.
+	//
+	// +optional
+	LoadBalancer *loadbalancer.LoadBalancer `json:"loadBalancer,omitempty"`
+
 	// DefaultMachinePlatform is the default configuration used when
 	// installing on Nutanix for machine pools which do not define their own
 	// platform configuration.
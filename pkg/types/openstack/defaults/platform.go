@@ -7,6 +7,7 @@ import (
 	"github.com/apparentlymart/go-cidr/cidr"
 
 	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/loadbalancer"
 	"github.com/openshift/installer/pkg/types/openstack"
 )
 
@@ -23,6 +24,12 @@ func SetPlatformDefaults(p *openstack.Platform, n *types.Networking) {
 			p.Cloud = DefaultCloudName
 		}
 	}
+	// A load balancer managed by the user has no VIPs on the machine
+	// networks.
+	if loadbalancer.IsUserManaged(p.LoadBalancer) {
+		return
+	}
+
 	// APIVIP returns the internal virtual IP address (VIP) put in front
 	// of the Kubernetes API server for use by components inside the
 	// cluster. The DNS static pods running on the nodes resolve the
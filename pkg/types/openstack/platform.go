@@ -1,5 +1,7 @@
 package openstack
 
+import "github.com/openshift/installer/pkg/types/loadbalancer"
+
 // Platform stores all the global configuration that all
 // machinesets use.
 type Platform struct {
@@ -109,6 +111,14 @@ type Platform struct {
 	// +optional
 	IngressVIPs []string `json:"ingressVIPs,omitempty"`
 
+	// LoadBalancer is the load balancer of the API and of the ingress. With a
+	// UserManaged load balancer, the API and ingress VIPs are optional: they
+	// are the addresses of the load balancer, which the cluster does not
+	// serve with keepalived and haproxy.
+	//
+	// +optional
+	LoadBalancer *loadbalancer.LoadBalancer `json:"loadBalancer,omitempty"`
+
 	// MachinesSubnet is the UUIDv4 of an openstack subnet. This subnet will be used by all nodes created by the installer.
 	// By setting this, the installer will no longer create a network and subnet.
 	// The subnet and network specified in MachinesSubnet will not be deleted or modified by the installer.
@@ -0,0 +1,31 @@
+package types
+
+// ServingCertificates are the certificates the cluster serves its endpoints
+// with, instead of ones issued by the certificate authorities of the
+// cluster.
+type ServingCertificates struct {
+	// API is the certificate of the API, for the domain name of the API.
+	// +optional
+	API *ServingCertificate `json:"api,omitempty"`
+
+	// Ingress is the certificate of the default ingress controller, for the
+	// wildcard of the apps domain.
+	// +optional
+	Ingress *ServingCertificate `json:"ingress,omitempty"`
+}
+
+// ServingCertificate is a certificate, with its private key.
+type ServingCertificate struct {
+	// Certificate is the PEM-encoded certificate, followed by the
+	// intermediate certificates of its chain.
+	Certificate string `json:"certificate"`
+
+	// Key is the PEM-encoded private key of the certificate.
+	Key string `json:"key"`
+
+	// CA is a PEM-encoded bundle of the CA certificates which issued the
+	// certificate. When set for the API certificate, it is trusted by the
+	// admin kubeconfig in addition to the CAs of the cluster.
+	// +optional
+	CA string `json:"ca,omitempty"`
+}
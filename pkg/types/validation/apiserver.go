@@ -0,0 +1,77 @@
+package validation
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/aws"
+)
+
+var (
+	validAuditProfiles = sets.NewString(
+		string(configv1.DefaultAuditProfileType),
+		string(configv1.WriteRequestBodiesAuditProfileType),
+		string(configv1.AllRequestBodiesAuditProfileType),
+		string(configv1.NoneAuditProfileType),
+	)
+
+	validEncryptionTypes = sets.NewString(
+		string(types.EncryptionTypeIdentity),
+		string(types.EncryptionTypeAESCBC),
+		string(types.EncryptionTypeAESGCM),
+		string(types.EncryptionTypeKMS),
+	)
+)
+
+func validateAPIServer(c *types.InstallConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	a := c.APIServer
+	if a.AuditProfile != "" && !validAuditProfiles.Has(string(a.AuditProfile)) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("auditProfile"), a.AuditProfile, validAuditProfiles.List()))
+	}
+	if e := a.Encryption; e != nil {
+		allErrs = append(allErrs, validateAPIServerEncryption(e, c.Platform.Name(), fldPath.Child("encryption"))...)
+	}
+	return allErrs
+}
+
+func validateAPIServerEncryption(e *types.APIServerEncryption, platform string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !validEncryptionTypes.Has(string(e.Type)) {
+		return append(allErrs, field.NotSupported(fldPath.Child("type"), e.Type, validEncryptionTypes.List()))
+	}
+	kmsPath := fldPath.Child("kms")
+	if e.Type != types.EncryptionTypeKMS {
+		if e.KMS != nil {
+			allErrs = append(allErrs, field.Forbidden(kmsPath, "only allowed with the KMS encryption type"))
+		}
+		return allErrs
+	}
+
+	// the cluster supports the AWS Key Management Service only
+	if platform != aws.Name {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("type"), e.Type, "the KMS encryption is only supported on AWS"))
+	}
+	if e.KMS == nil || e.KMS.AWS == nil {
+		return append(allErrs, field.Required(kmsPath.Child("aws"), "the KMS key is required for the KMS encryption"))
+	}
+	awsPath := kmsPath.Child("aws")
+	key, err := arn.Parse(e.KMS.AWS.KeyARN)
+	switch {
+	case err != nil:
+		allErrs = append(allErrs, field.Invalid(awsPath.Child("keyARN"), e.KMS.AWS.KeyARN, err.Error()))
+	case key.Service != "kms" || !strings.HasPrefix(key.Resource, "key/"):
+		allErrs = append(allErrs, field.Invalid(awsPath.Child("keyARN"), e.KMS.AWS.KeyARN, "must be the ARN of a KMS key"))
+	case e.KMS.AWS.Region != "" && key.Region != e.KMS.AWS.Region:
+		allErrs = append(allErrs, field.Invalid(awsPath.Child("region"), e.KMS.AWS.Region, "must be the region of the key "+e.KMS.AWS.KeyARN))
+	}
+	if e.KMS.AWS.Region == "" {
+		allErrs = append(allErrs, field.Required(awsPath.Child("region"), "the region of the KMS key is required"))
+	}
+	return allErrs
+}
@@ -0,0 +1,138 @@
+package validation
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/validate"
+)
+
+var validMappingMethods = sets.NewString(
+	string(configv1.MappingMethodClaim),
+	string(configv1.MappingMethodLookup),
+	string(configv1.MappingMethodGenerate),
+	string(configv1.MappingMethodAdd),
+)
+
+func validateIdentityProviders(providers []types.IdentityProvider, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := sets.NewString()
+	for i, p := range providers {
+		idpPath := fldPath.Index(i)
+		namePath := idpPath.Child("name")
+		switch {
+		case p.Name == "":
+			allErrs = append(allErrs, field.Required(namePath, "identity providers must be named"))
+		case len(utilvalidation.IsDNS1123Label(p.Name)) > 0:
+			allErrs = append(allErrs, field.Invalid(namePath, p.Name, "must be a lowercase RFC 1123 label, since it names the secrets of the identity provider"))
+		case p.Name == types.BreakGlassIdentityProvider:
+			allErrs = append(allErrs, field.Invalid(namePath, p.Name, "the name is reserved for the break-glass users of kubeadmin.htpasswd"))
+		case names.Has(p.Name):
+			allErrs = append(allErrs, field.Duplicate(namePath, p.Name))
+		}
+		names.Insert(p.Name)
+		if p.MappingMethod != "" && !validMappingMethods.Has(string(p.MappingMethod)) {
+			allErrs = append(allErrs, field.NotSupported(idpPath.Child("mappingMethod"), p.MappingMethod, validMappingMethods.List()))
+		}
+
+		set := 0
+		if p.HTPasswd != nil {
+			set++
+			allErrs = append(allErrs, validateHTPasswd(p.HTPasswd.Contents, idpPath.Child("htpasswd", "contents"))...)
+		}
+		if p.OpenID != nil {
+			set++
+			allErrs = append(allErrs, validateOpenIDIdentityProvider(p.OpenID, idpPath.Child("openID"))...)
+		}
+		if p.LDAP != nil {
+			set++
+			allErrs = append(allErrs, validateLDAPIdentityProvider(p.LDAP, idpPath.Child("ldap"))...)
+		}
+		switch {
+		case set == 0:
+			allErrs = append(allErrs, field.Required(idpPath, "one of htpasswd, openID and ldap is required"))
+		case set > 1:
+			allErrs = append(allErrs, field.Forbidden(idpPath, "only one of htpasswd, openID and ldap may be set"))
+		}
+	}
+	return allErrs
+}
+
+func validateOpenIDIdentityProvider(p *types.OpenIDIdentityProvider, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if u, err := url.Parse(p.Issuer); err != nil || u.Scheme != "https" || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("issuer"), p.Issuer, "must be an https URL without a query or fragment"))
+	}
+	if p.ClientID == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("clientID"), "the ID of the client registered with the provider is required"))
+	}
+	if p.ClientSecret == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("clientSecret"), "the secret of the client registered with the provider is required"))
+	}
+	if p.CA != "" {
+		if err := validate.CABundle(p.CA); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ca"), p.CA, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+func validateLDAPIdentityProvider(p *types.LDAPIdentityProvider, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	u, err := url.Parse(p.URL)
+	switch {
+	case err != nil || (u.Scheme != "ldap" && u.Scheme != "ldaps") || u.Host == "":
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("url"), p.URL, "must be an ldap or ldaps URL"))
+	case p.Insecure && u.Scheme == "ldaps":
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("insecure"), p.Insecure, "ldaps URLs always use TLS"))
+	}
+	switch {
+	case p.BindDN != "" && p.BindPassword == "":
+		allErrs = append(allErrs, field.Required(fldPath.Child("bindPassword"), "bindPassword is required with bindDN"))
+	case p.BindDN == "" && p.BindPassword != "":
+		allErrs = append(allErrs, field.Required(fldPath.Child("bindDN"), "bindDN is required with bindPassword"))
+	}
+	if p.CA != "" {
+		if p.Insecure {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("ca"), "a CA cannot be used without TLS"))
+		} else if err := validate.CABundle(p.CA); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ca"), p.CA, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// validateHTPasswd checks that every line of the htpasswd file is a user with
+// a bcrypt password hash. The errors name the users or lines rather than
+// including the hashes.
+func validateHTPasswd(htpasswd string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	users := sets.NewString()
+	for i, line := range strings.Split(htpasswd, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		switch {
+		case len(parts) != 2 || parts[0] == "":
+			allErrs = append(allErrs, field.Invalid(fldPath, fmt.Sprintf("line %d", i+1), "must be of the form user:hash"))
+		case !strings.HasPrefix(parts[1], "$2a$") && !strings.HasPrefix(parts[1], "$2b$") && !strings.HasPrefix(parts[1], "$2y$"):
+			allErrs = append(allErrs, field.Invalid(fldPath, parts[0], "the password hash must be bcrypt, e.g. from htpasswd -B"))
+		case users.Has(parts[0]):
+			allErrs = append(allErrs, field.Duplicate(fldPath, parts[0]))
+		}
+		users.Insert(parts[0])
+	}
+	if users.Len() == 0 {
+		allErrs = append(allErrs, field.Required(fldPath, "at least one user is required"))
+	}
+	return allErrs
+}
@@ -2,9 +2,11 @@ package validation
 
 import (
 	"fmt"
+	"math"
 	"net"
 	"net/url"
 	"os"
+	"path"
 	"regexp"
 	"sort"
 	"strconv"
@@ -15,12 +17,14 @@ import (
 	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/ssh"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
-	utilsnet "k8s.io/utils/net"
 
 	configv1 "github.com/openshift/api/config/v1"
 	operv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/installer/pkg/butane"
 	"github.com/openshift/installer/pkg/ipnet"
 	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/alibabacloud"
@@ -33,10 +37,12 @@ import (
 	baremetalvalidation "github.com/openshift/installer/pkg/types/baremetal/validation"
 	"github.com/openshift/installer/pkg/types/gcp"
 	gcpvalidation "github.com/openshift/installer/pkg/types/gcp/validation"
+	hostnetworkvalidation "github.com/openshift/installer/pkg/types/hostnetwork/validation"
 	"github.com/openshift/installer/pkg/types/ibmcloud"
 	ibmcloudvalidation "github.com/openshift/installer/pkg/types/ibmcloud/validation"
 	"github.com/openshift/installer/pkg/types/libvirt"
 	libvirtvalidation "github.com/openshift/installer/pkg/types/libvirt/validation"
+	"github.com/openshift/installer/pkg/types/none"
 	"github.com/openshift/installer/pkg/types/nutanix"
 	nutanixvalidation "github.com/openshift/installer/pkg/types/nutanix/validation"
 	"github.com/openshift/installer/pkg/types/openstack"
@@ -67,13 +73,10 @@ func ValidateInstallConfig(c *types.InstallConfig) field.ErrorList {
 	}
 
 	if c.SSHKey != "" {
-		if c.FIPS == true {
-			allErrs = append(allErrs, validateFIPSconfig(c)...)
-		} else {
-			if err := validate.SSHPublicKey(c.SSHKey); err != nil {
-				allErrs = append(allErrs, field.Invalid(field.NewPath("sshKey"), c.SSHKey, err.Error()))
-			}
-		}
+		allErrs = append(allErrs, validateSSHKeys(c)...)
+	}
+	if c.FIPS {
+		allErrs = append(allErrs, validateFIPS(c)...)
 	}
 
 	if c.AdditionalTrustBundle != "" {
@@ -81,6 +84,9 @@ func ValidateInstallConfig(c *types.InstallConfig) field.ErrorList {
 			allErrs = append(allErrs, field.Invalid(field.NewPath("additionalTrustBundle"), c.AdditionalTrustBundle, err.Error()))
 		}
 	}
+	if len(c.AdditionalTrustedCA) > 0 {
+		allErrs = append(allErrs, validateAdditionalTrustedCA(c.AdditionalTrustedCA, field.NewPath("additionalTrustedCA"))...)
+	}
 	if c.AdditionalTrustBundlePolicy != "" {
 		if err := validateAdditionalCABundlePolicy(c); err != nil {
 			allErrs = append(allErrs, field.Invalid(field.NewPath("additionalTrustBundlePolicy"), c.AdditionalTrustBundlePolicy, err.Error()))
@@ -106,6 +112,9 @@ func ValidateInstallConfig(c *types.InstallConfig) field.ErrorList {
 			allErrs = append(allErrs, field.Invalid(field.NewPath("baseDomain"), clusterDomain, err.Error()))
 		}
 	}
+	if baseDomainErr == nil {
+		allErrs = append(allErrs, validateClusterDomains(c)...)
+	}
 	if c.Networking != nil {
 		allErrs = append(allErrs, validateNetworking(c.Networking, c.IsSingleNodeOpenShift(), field.NewPath("networking"))...)
 		allErrs = append(allErrs, validateNetworkingIPVersion(c.Networking, &c.Platform)...)
@@ -116,11 +125,21 @@ func ValidateInstallConfig(c *types.InstallConfig) field.ErrorList {
 	}
 	allErrs = append(allErrs, validatePlatform(&c.Platform, field.NewPath("platform"), c.Networking, c)...)
 	if c.ControlPlane != nil {
-		allErrs = append(allErrs, validateControlPlane(&c.Platform, c.ControlPlane, field.NewPath("controlPlane"))...)
+		allErrs = append(allErrs, validateControlPlane(&c.Platform, c.ControlPlane, c.Arbiter, field.NewPath("controlPlane"))...)
 	} else {
 		allErrs = append(allErrs, field.Required(field.NewPath("controlPlane"), "controlPlane is required"))
 	}
+	if c.BootstrapInPlace != nil {
+		allErrs = append(allErrs, validateBootstrapInPlace(c, field.NewPath("bootstrapInPlace"))...)
+	}
+	if c.Arbiter != nil {
+		allErrs = append(allErrs, validateArbiter(&c.Platform, c.Arbiter, c.ControlPlane, field.NewPath("arbiter"))...)
+	}
 	allErrs = append(allErrs, validateCompute(&c.Platform, c.ControlPlane, c.Compute, field.NewPath("compute"))...)
+	allErrs = append(allErrs, validateWindowsNetworking(c)...)
+	if c.Networking != nil {
+		allErrs = append(allErrs, validateClusterNetworkCapacity(c, field.NewPath("networking", "clusterNetwork"))...)
+	}
 	if err := validate.ImagePullSecret(c.PullSecret); err != nil {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("pullSecret"), c.PullSecret, err.Error()))
 	}
@@ -128,6 +147,10 @@ func ValidateInstallConfig(c *types.InstallConfig) field.ErrorList {
 		allErrs = append(allErrs, validateProxy(c.Proxy, c, field.NewPath("proxy"))...)
 	}
 	allErrs = append(allErrs, validateImageContentSources(c.ImageContentSources, field.NewPath("imageContentSources"))...)
+	allErrs = append(allErrs, validateImageDigestSources(c.ImageDigestSources, field.NewPath("imageDigestSources"))...)
+	if len(c.ImageContentSources) > 0 && len(c.ImageDigestSources) > 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("imageContentSources"), "ImageContentSources", "cannot set imageContentSources and imageDigestSources at the same time"))
+	}
 	if _, ok := validPublishingStrategies[c.Publish]; !ok {
 		allErrs = append(allErrs, field.NotSupported(field.NewPath("publish"), c.Publish, validPublishingStrategyValues))
 	}
@@ -145,7 +168,324 @@ func ValidateInstallConfig(c *types.InstallConfig) field.ErrorList {
 	}
 
 	allErrs = append(allErrs, validateFeatureSet(c)...)
+	if c.NodeConfig != nil {
+		allErrs = append(allErrs, validateNodeConfig(c, field.NewPath("nodeConfig"))...)
+	}
+	if c.WaitTimeouts != nil {
+		allErrs = append(allErrs, validateWaitTimeouts(c.WaitTimeouts, field.NewPath("waitTimeouts"))...)
+	}
+	if c.Hooks != nil {
+		allErrs = append(allErrs, validateHooks(c.Hooks, field.NewPath("hooks"))...)
+	}
+	if c.GatherProxy != nil {
+		allErrs = append(allErrs, validateGatherProxy(c.GatherProxy, field.NewPath("gatherProxy"))...)
+	}
+	if c.ReleaseImage != nil {
+		allErrs = append(allErrs, validateReleaseImage(c.ReleaseImage, field.NewPath("releaseImage"))...)
+	}
+	allErrs = append(allErrs, validateIgnitionSnippets(c.IgnitionSnippets, field.NewPath("ignitionSnippets"))...)
+	if c.NTP != nil {
+		allErrs = append(allErrs, validateNTP(c.NTP, field.NewPath("ntp"))...)
+	}
+	if c.Kubeadmin != nil && c.Kubeadmin.HTPasswd != "" {
+		allErrs = append(allErrs, validateHTPasswd(c.Kubeadmin.HTPasswd, field.NewPath("kubeadmin", "htpasswd"))...)
+	}
+	allErrs = append(allErrs, validateIdentityProviders(c.IdentityProviders, field.NewPath("identityProviders"))...)
+	if c.Etcd != nil {
+		allErrs = append(allErrs, validateEtcd(c.Etcd, c.Platform.Name(), field.NewPath("etcd"))...)
+	}
+	if c.ServingCertificates != nil {
+		allErrs = append(allErrs, validateServingCertificates(c, field.NewPath("servingCertificates"))...)
+	}
+	if c.APIServer != nil {
+		allErrs = append(allErrs, validateAPIServer(c, field.NewPath("apiServer"))...)
+	}
+
+	return allErrs
+}
+
+// managedDNSPlatforms are the platforms whose DNS records the installer
+// creates in the zone of the base domain.
+var managedDNSPlatforms = sets.NewString(alibabacloud.Name, aws.Name, azure.Name, gcp.Name, ibmcloud.Name, powervs.Name)
+
+// validateClusterDomains checks the domains of the API and of the routes
+// which replace the ones of the cluster domain.
+func validateClusterDomains(c *types.InstallConfig) field.ErrorList {
+	allErrs := field.ErrorList{}
+	baseDomain := strings.TrimSuffix(c.BaseDomain, ".")
+	for _, d := range []struct {
+		value string
+		path  *field.Path
+	}{
+		{value: c.APIDomain, path: field.NewPath("apiDomain")},
+		{value: c.AppsDomain, path: field.NewPath("appsDomain")},
+	} {
+		if d.value == "" {
+			continue
+		}
+		if err := validate.DomainName(d.value, true); err != nil {
+			allErrs = append(allErrs, field.Invalid(d.path, d.value, err.Error()))
+			continue
+		}
+		if managedDNSPlatforms.Has(c.Platform.Name()) && !strings.HasSuffix(strings.TrimSuffix(d.value, "."), "."+baseDomain) {
+			allErrs = append(allErrs, field.Invalid(d.path, d.value, fmt.Sprintf("must be a subdomain of the base domain %s, in whose zone the installer creates the DNS records", baseDomain)))
+		}
+	}
+	if len(allErrs) > 0 || (c.APIDomain == "" && c.AppsDomain == "") {
+		return allErrs
+	}
+
+	// the routers serve the apps domain and all of its subdomains
+	api, apps := c.APIDomainName(), c.AppsDomainName()
+	if api == apps || strings.HasSuffix(api, "."+apps) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("apiDomain"), c.APIDomain, fmt.Sprintf("must not be in the apps domain %s, whose names resolve to the routers", apps)))
+	}
+	if internal := "api-int." + c.ClusterDomain(); api == internal {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("apiDomain"), c.APIDomain, fmt.Sprintf("%s is the domain name of the internal API", internal)))
+	}
+	return allErrs
+}
+
+func validateIgnitionSnippets(snippets []types.IgnitionSnippet, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	validRoles := sets.NewString("bootstrap", "master", "worker")
+	names := sets.NewString()
+	for i, snippet := range snippets {
+		snippetPath := fldPath.Index(i)
+		switch {
+		case snippet.Name == "":
+			allErrs = append(allErrs, field.Required(snippetPath.Child("name"), "snippets must be named"))
+		case names.Has(snippet.Name):
+			allErrs = append(allErrs, field.Duplicate(snippetPath.Child("name"), snippet.Name))
+		}
+		names.Insert(snippet.Name)
+		if len(snippet.Roles) == 0 {
+			allErrs = append(allErrs, field.Required(snippetPath.Child("roles"), "snippets must be merged into at least one role"))
+		}
+		for j, role := range snippet.Roles {
+			if !validRoles.Has(role) {
+				allErrs = append(allErrs, field.NotSupported(snippetPath.Child("roles").Index(j), role, validRoles.List()))
+			}
+		}
+		if _, err := butane.Translate([]byte(snippet.Butane)); err != nil {
+			allErrs = append(allErrs, field.Invalid(snippetPath.Child("butane"), snippet.Butane, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+func validateAdditionalTrustedCA(cas map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	hosts := make([]string, 0, len(cas))
+	for host := range cas {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		hostPath := fldPath.Key(host)
+		name, port := host, ""
+		if h, p, err := net.SplitHostPort(host); err == nil {
+			name, port = h, p
+		}
+		if strings.Contains(host, "/") || validate.Host(name) != nil {
+			allErrs = append(allErrs, field.Invalid(hostPath, host, "must be a registry host with an optional port"))
+			continue
+		}
+		if port != "" {
+			if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+				allErrs = append(allErrs, field.Invalid(hostPath, host, "the port must be between 1 and 65535"))
+				continue
+			}
+		}
+		if err := validate.CABundle(cas[host]); err != nil {
+			allErrs = append(allErrs, field.Invalid(hostPath, cas[host], err.Error()))
+		}
+	}
+	return allErrs
+}
+
+func validateNTP(ntp *types.NTP, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(ntp.Servers) == 0 && len(ntp.Pools) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath, "at least one of servers or pools must be specified"))
+	}
+	sources := sets.NewString()
+	for _, list := range []struct {
+		name    string
+		sources []string
+	}{
+		{name: "servers", sources: ntp.Servers},
+		{name: "pools", sources: ntp.Pools},
+	} {
+		for i, source := range list.sources {
+			sourcePath := fldPath.Child(list.name).Index(i)
+			if err := validate.Host(source); err != nil {
+				allErrs = append(allErrs, field.Invalid(sourcePath, source, "must be a hostname or an IP address"))
+			} else if sources.Has(source) {
+				allErrs = append(allErrs, field.Duplicate(sourcePath, source))
+			}
+			sources.Insert(source)
+		}
+	}
+	return allErrs
+}
+
+// etcdDiskTypes are the types of the dedicated etcd disk supported by each
+// platform.
+var etcdDiskTypes = map[string]sets.String{
+	aws.Name:   sets.NewString("gp2", "gp3"),
+	azure.Name: sets.NewString("Premium_LRS"),
+	gcp.Name:   sets.NewString("pd-balanced", "pd-ssd"),
+}
+
+// containerStorageDevicePlatforms are the platforms on which the dedicated
+// container storage disk is an existing disk of the machines. On the
+// platforms of etcdDiskTypes, the disk is attached by the installer.
+var containerStorageDevicePlatforms = sets.NewString(baremetal.Name, none.Name)
+
+func validateContainerStorage(platform string, storage *types.ContainerStorage, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch storage.MountPath {
+	case types.ContainerStorageMountPathContainers, types.ContainerStorageMountPathVar:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("mountPath"), storage.MountPath, []string{types.ContainerStorageMountPathContainers, types.ContainerStorageMountPathVar}))
+	}
+	if diskTypes, ok := etcdDiskTypes[platform]; ok {
+		if !diskTypes.Has(storage.Type) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("type"), storage.Type, diskTypes.List()))
+		}
+		if storage.SizeGB < 20 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("sizeGB"), storage.SizeGB, "must be at least 20 to hold the container images"))
+		}
+		if storage.Device != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("device"), fmt.Sprintf("the disk is attached by the installer on %s", platform)))
+		}
+		return allErrs
+	}
+	if !containerStorageDevicePlatforms.Has(platform) {
+		return append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("a dedicated container storage disk is not supported on %s", platform)))
+	}
+	if storage.SizeGB != 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("sizeGB"), fmt.Sprintf("the disk is not attached by the installer on %s", platform)))
+	}
+	if storage.Type != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("type"), fmt.Sprintf("the disk is not attached by the installer on %s", platform)))
+	}
+	if !strings.HasPrefix(storage.Device, "/dev/") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("device"), storage.Device, "must be the path of a device in /dev"))
+	}
+	return allErrs
+}
+
+func validateEtcd(etcd *types.Etcd, platform string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if disk := etcd.Disk; disk != nil {
+		diskPath := fldPath.Child("disk")
+		diskTypes, ok := etcdDiskTypes[platform]
+		switch {
+		case !ok:
+			allErrs = append(allErrs, field.Forbidden(diskPath, fmt.Sprintf("a dedicated etcd disk is not supported on %s", platform)))
+		case !diskTypes.Has(disk.Type):
+			allErrs = append(allErrs, field.NotSupported(diskPath.Child("type"), disk.Type, diskTypes.List()))
+		}
+		if disk.SizeGB < 10 {
+			allErrs = append(allErrs, field.Invalid(diskPath.Child("sizeGB"), disk.SizeGB, "must be at least 10 to hold the etcd database"))
+		}
+	}
+	switch etcd.LatencyProfile {
+	case "", types.EtcdLatencyProfileStandard, types.EtcdLatencyProfileSlower:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("latencyProfile"), etcd.LatencyProfile, []string{string(types.EtcdLatencyProfileStandard), string(types.EtcdLatencyProfileSlower)}))
+	}
+	return allErrs
+}
+
+func validateWaitTimeouts(t *types.WaitTimeouts, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, timeout := range []struct {
+		name     string
+		duration *metav1.Duration
+	}{
+		{name: "bootstrapComplete", duration: t.BootstrapComplete},
+		{name: "clusterOperatorsStable", duration: t.ClusterOperatorsStable},
+		{name: "consoleRoute", duration: t.ConsoleRoute},
+	} {
+		if timeout.duration != nil && timeout.duration.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child(timeout.name), timeout.duration.Duration.String(), "must be positive"))
+		}
+	}
+	return allErrs
+}
+
+func validateHooks(h *types.Hooks, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, phase := range []struct {
+		name  string
+		hooks []string
+	}{
+		{name: "preProvision", hooks: h.PreProvision},
+		{name: "postBootstrap", hooks: h.PostBootstrap},
+		{name: "postInstall", hooks: h.PostInstall},
+	} {
+		for i, hook := range phase.hooks {
+			if u, err := url.Parse(hook); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+				if u.Host == "" {
+					allErrs = append(allErrs, field.Invalid(fldPath.Child(phase.name).Index(i), hook, "must have a host"))
+				}
+				continue
+			}
+			if !path.IsAbs(hook) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child(phase.name).Index(i), hook, "must be an absolute path or an http(s) URL"))
+			}
+		}
+	}
+	return allErrs
+}
+
+func validateGatherProxy(p *types.GatherProxy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if p.Bastion != "" && p.SOCKS != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "only one of bastion and socks may be set"))
+	}
+	if p.Bastion != "" {
+		host := p.Bastion[strings.LastIndex(p.Bastion, "@")+1:]
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if host == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("bastion"), p.Bastion, "must be [user@]host[:port]"))
+		}
+	}
+	if p.SOCKS != "" {
+		if host, _, err := net.SplitHostPort(p.SOCKS); err != nil || host == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("socks"), p.SOCKS, "must be host:port"))
+		}
+	}
+	return allErrs
+}
 
+func validateReleaseImage(r *types.ReleaseImage, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if r.PullSpec == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("pullSpec"), "the pull spec of the release image is required"))
+	} else if ref, err := dockerref.ParseNamed(r.PullSpec); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("pullSpec"), r.PullSpec, err.Error()))
+	} else if _, digested := ref.(dockerref.Digested); !digested && len(r.VerificationKeys) > 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("pullSpec"), r.PullSpec, "must reference the image by digest when verificationKeys are given"))
+	}
+	for i, key := range r.VerificationKeys {
+		if !strings.Contains(key, "-----BEGIN PGP PUBLIC KEY BLOCK-----") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("verificationKeys").Index(i), "", "must be an ASCII-armored GPG public key"))
+		}
+	}
+	for i, store := range r.SignatureStores {
+		if u, err := url.Parse(store); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("signatureStores").Index(i), store, "must be an http(s) URL"))
+		}
+	}
+	if len(r.SignatureStores) > 0 && len(r.VerificationKeys) == 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("signatureStores"), "signatureStores may only be set with verificationKeys"))
+	}
 	return allErrs
 }
 
@@ -214,12 +554,46 @@ func ipnetworksToStrings(networks []ipnet.IPNet) []string {
 	return diag
 }
 
+// ipFamilySupport describes the IP address family configurations supported
+// by a platform.
+type ipFamilySupport struct {
+	// IPv6 is whether single-stack IPv6 is supported.
+	IPv6 bool
+	// DualStack is whether dual-stack IPv4/IPv6 is supported.
+	DualStack bool
+}
+
+// platformIPFamilySupport maps platform names to the IP address family
+// configurations they support. Platforms which are not listed only support
+// single-stack IPv4.
+var platformIPFamilySupport = map[string]ipFamilySupport{
+	baremetal.Name: {IPv6: true, DualStack: true},
+	none.Name:      {IPv6: true, DualStack: true},
+	nutanix.Name:   {IPv6: true, DualStack: true},
+	openstack.Name: {IPv6: true, DualStack: true},
+	ovirt.Name:     {IPv6: true, DualStack: true},
+	vsphere.Name:   {IPv6: true, DualStack: true},
+}
+
+// ipFamilySupportForPlatform returns the IP address family configurations
+// supported by the given platform.
+func ipFamilySupportForPlatform(p *types.Platform) ipFamilySupport {
+	support := platformIPFamilySupport[p.Name()]
+	if p.Azure != nil {
+		experimentalDualStackEnabled, _ := strconv.ParseBool(os.Getenv("OPENSHIFT_INSTALL_EXPERIMENTAL_DUAL_STACK"))
+		support.DualStack = experimentalDualStackEnabled
+	}
+	return support
+}
+
 // validateNetworkingIPVersion checks parameters for consistency when the user
 // requests single-stack IPv6 or dual-stack modes.
 func validateNetworkingIPVersion(n *types.Networking, p *types.Platform) field.ErrorList {
 	var allErrs field.ErrorList
 
 	hasIPv4, hasIPv6, presence, addresses := inferIPVersionFromInstallConfig(n)
+	support := ipFamilySupportForPlatform(p)
+	platformName := p.Name()
 
 	switch {
 	case hasIPv4 && hasIPv6:
@@ -231,31 +605,30 @@ func validateNetworkingIPVersion(n *types.Networking, p *types.Platform) field.E
 			allErrs = append(allErrs, field.Invalid(field.NewPath("networking", "serviceNetwork"), strings.Join(ipnetworksToStrings(n.ServiceNetwork), ", "), "when installing dual-stack IPv4/IPv6 you must provide two service networks, one for each IP address type"))
 		}
 
-		experimentalDualStackEnabled, _ := strconv.ParseBool(os.Getenv("OPENSHIFT_INSTALL_EXPERIMENTAL_DUAL_STACK"))
-		switch {
-		case p.Azure != nil && experimentalDualStackEnabled:
+		if !support.DualStack {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("networking"), "DualStack", fmt.Sprintf("dual-stack IPv4/IPv6 is not supported on the %q platform, specify only one type of address", platformName)))
+		} else if p.Azure != nil {
 			logrus.Warnf("Using experimental Azure dual-stack support")
-		case p.BareMetal != nil:
-		case p.VSphere != nil:
-		case p.OpenStack != nil:
-		case p.Ovirt != nil:
-		case p.Nutanix != nil:
-		case p.None != nil:
-		default:
-			allErrs = append(allErrs, field.Invalid(field.NewPath("networking"), "DualStack", "dual-stack IPv4/IPv6 is not supported for this platform, specify only one type of address"))
 		}
-		for k, v := range presence {
+
+		for _, k := range []string{"machineNetwork", "clusterNetwork", "serviceNetwork"} {
+			v, ok := presence[k]
+			if !ok {
+				continue
+			}
 			switch {
 			case v.IPv4 && !v.IPv6:
 				allErrs = append(allErrs, field.Invalid(field.NewPath("networking", k), strings.Join(ipnetworksToStrings(addresses[k]), ", "), "dual-stack IPv4/IPv6 requires an IPv6 network in this list"))
+				continue
 			case !v.IPv4 && v.IPv6:
 				allErrs = append(allErrs, field.Invalid(field.NewPath("networking", k), strings.Join(ipnetworksToStrings(addresses[k]), ", "), "dual-stack IPv4/IPv6 requires an IPv4 network in this list"))
+				continue
 			}
 
 			// FIXME: we should allow either all-networks-IPv4Primary or
 			// all-networks-IPv6Primary, but the latter currently causes
 			// confusing install failures, so block it.
-			if v.IPv4 && v.IPv6 && v.Primary != corev1.IPv4Protocol {
+			if v.Primary == corev1.IPv6Protocol {
 				allErrs = append(allErrs, field.Invalid(field.NewPath("networking", k), strings.Join(ipnetworksToStrings(addresses[k]), ", "), "IPv4 addresses must be listed before IPv6 addresses"))
 			}
 		}
@@ -266,16 +639,11 @@ func validateNetworkingIPVersion(n *types.Networking, p *types.Platform) field.E
 		}
 
 		switch {
-		case p.BareMetal != nil:
-		case p.VSphere != nil:
-		case p.OpenStack != nil:
-		case p.Ovirt != nil:
-		case p.Nutanix != nil:
-		case p.None != nil:
+		case support.IPv6:
 		case p.Azure != nil && p.Azure.CloudName == azure.StackCloud:
 			allErrs = append(allErrs, field.Invalid(field.NewPath("networking"), "IPv6", "Azure Stack does not support IPv6"))
 		default:
-			allErrs = append(allErrs, field.Invalid(field.NewPath("networking"), "IPv6", "single-stack IPv6 is not supported for this platform"))
+			allErrs = append(allErrs, field.Invalid(field.NewPath("networking"), "IPv6", fmt.Sprintf("single-stack IPv6 is not supported on the %q platform", platformName)))
 		}
 
 	case hasIPv4:
@@ -340,11 +708,143 @@ func validateNetworking(n *types.Networking, singleNodeOpenShift bool, fldPath *
 	if len(n.ClusterNetwork) == 0 {
 		allErrs = append(allErrs, field.Required(fldPath.Child("clusterNetwork"), "cluster network required"))
 	}
+
+	if n.OVNKubernetesConfig != nil {
+		if n.NetworkType != string(operv1.NetworkTypeOVNKubernetes) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("ovnKubernetesConfig"), fmt.Sprintf("may only be set when networkType is %s", operv1.NetworkTypeOVNKubernetes)))
+		}
+		allErrs = append(allErrs, validateOVNKubernetesConfig(n, fldPath.Child("ovnKubernetesConfig"))...)
+	}
+	return allErrs
+}
+
+const (
+	// ovnGeneveOverhead is the number of bytes of each packet taken by the
+	// OVNKubernetes Geneve overlay header.
+	ovnGeneveOverhead = 100
+
+	// minOVNMTU is the smallest pod network MTU accepted by OVNKubernetes.
+	minOVNMTU = 576
+
+	// defaultMaxPods is the default maximum number of pods the kubelet will
+	// run on a single node.
+	defaultMaxPods = 250
+)
+
+// platformMaxMTU is the largest MTU available to machines on the platforms
+// which put a fixed limit on it. Platforms where the MTU of the machine
+// network is configurable, such as GCP VPCs, are not listed.
+var platformMaxMTU = map[string]uint32{
+	// https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/network_mtu.html
+	aws.Name: 9001,
+}
+
+var validOVNGatewayModes = sets.NewString(string(types.OVNGatewayModeShared), string(types.OVNGatewayModeLocal))
+
+// validateOVNKubernetesConfig checks the OVNKubernetes tunables, in particular
+// that the internal subnets do not overlap with the networks used by the cluster.
+// The deprecated machineCIDR has already been converted into machineNetwork by
+// the time the install-config is validated, so it is not checked separately.
+func validateOVNKubernetesConfig(n *types.Networking, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	c := n.OVNKubernetesConfig
+
+	if c.GatewayMode != "" && !validOVNGatewayModes.Has(string(c.GatewayMode)) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("gatewayMode"), c.GatewayMode, validOVNGatewayModes.List()))
+	}
+
+	validateInternalSubnet := func(subnet *ipnet.IPNet, ipv6 bool, fldPath *field.Path) {
+		if subnet == nil {
+			return
+		}
+		if isIPv6 := subnet.IP.To4() == nil; isIPv6 != ipv6 {
+			family := "IPv4"
+			if ipv6 {
+				family = "IPv6"
+			}
+			allErrs = append(allErrs, field.Invalid(fldPath, subnet.String(), fmt.Sprintf("must be an %s subnet", family)))
+			return
+		}
+		if ones, bits := subnet.Mask.Size(); bits-ones < 8 {
+			allErrs = append(allErrs, field.Invalid(fldPath, subnet.String(), "must be large enough to provide an address to every node (at least /24 for IPv4 or /120 for IPv6)"))
+		}
+		for i, mn := range n.MachineNetwork {
+			if validate.DoCIDRsOverlap(&subnet.IPNet, &mn.CIDR.IPNet) {
+				allErrs = append(allErrs, field.Invalid(fldPath, subnet.String(), fmt.Sprintf("must not overlap with machine network %d", i)))
+			}
+		}
+		for i, cn := range n.ClusterNetwork {
+			if validate.DoCIDRsOverlap(&subnet.IPNet, &cn.CIDR.IPNet) {
+				allErrs = append(allErrs, field.Invalid(fldPath, subnet.String(), fmt.Sprintf("must not overlap with cluster network %d", i)))
+			}
+		}
+		for i, sn := range n.ServiceNetwork {
+			if validate.DoCIDRsOverlap(&subnet.IPNet, &sn.IPNet) {
+				allErrs = append(allErrs, field.Invalid(fldPath, subnet.String(), fmt.Sprintf("must not overlap with service network %d", i)))
+			}
+		}
+	}
+	validateInternalSubnet(c.V4InternalSubnet, false, fldPath.Child("v4InternalSubnet"))
+	validateInternalSubnet(c.V6InternalSubnet, true, fldPath.Child("v6InternalSubnet"))
+
+	if c.MTU != 0 && c.MTU < minOVNMTU {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("mtu"), int(c.MTU), fmt.Sprintf("must be at least %d", minOVNMTU)))
+	}
+
+	if c.HybridOverlay != nil {
+		allErrs = append(allErrs, validateHybridOverlay(n, fldPath.Child("hybridOverlay"))...)
+	}
+
+	return allErrs
+}
+
+// validateHybridOverlay checks that the hybrid cluster networks do not
+// overlap with the other networks of the cluster.
+func validateHybridOverlay(n *types.Networking, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	c := n.OVNKubernetesConfig.HybridOverlay
+
+	if len(c.HybridClusterNetwork) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("hybridClusterNetwork"), "hybrid cluster network required"))
+	}
+	for i, hcn := range c.HybridClusterNetwork {
+		hcnPath := fldPath.Child("hybridClusterNetwork").Index(i)
+		if ones, bits := hcn.CIDR.Mask.Size(); hcn.HostPrefix < int32(ones) || hcn.HostPrefix > int32(bits) {
+			allErrs = append(allErrs, field.Invalid(hcnPath.Child("hostPrefix"), hcn.HostPrefix, fmt.Sprintf("must be between %d and %d", ones, bits)))
+		}
+		for j, mn := range n.MachineNetwork {
+			if validate.DoCIDRsOverlap(&hcn.CIDR.IPNet, &mn.CIDR.IPNet) {
+				allErrs = append(allErrs, field.Invalid(hcnPath.Child("cidr"), hcn.CIDR.String(), fmt.Sprintf("must not overlap with machine network %d", j)))
+			}
+		}
+		for j, cn := range n.ClusterNetwork {
+			if validate.DoCIDRsOverlap(&hcn.CIDR.IPNet, &cn.CIDR.IPNet) {
+				allErrs = append(allErrs, field.Invalid(hcnPath.Child("cidr"), hcn.CIDR.String(), fmt.Sprintf("must not overlap with cluster network %d", j)))
+			}
+		}
+		for j, sn := range n.ServiceNetwork {
+			if validate.DoCIDRsOverlap(&hcn.CIDR.IPNet, &sn.IPNet) {
+				allErrs = append(allErrs, field.Invalid(hcnPath.Child("cidr"), hcn.CIDR.String(), fmt.Sprintf("must not overlap with service network %d", j)))
+			}
+		}
+	}
+	if c.VXLANPort > 65535 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("vxlanPort"), c.VXLANPort, "must be a valid port number"))
+	}
 	return allErrs
 }
 
 func validateNetworkingForPlatform(n *types.Networking, platform *types.Platform, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
+	if platform.PowerVS != nil && n.OVNKubernetesConfig != nil && n.OVNKubernetesConfig.GatewayMode == types.OVNGatewayModeShared {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("ovnKubernetesConfig", "gatewayMode"), n.OVNKubernetesConfig.GatewayMode, "Power VS requires the Local gateway mode"))
+	}
+	if n.OVNKubernetesConfig != nil && n.OVNKubernetesConfig.MTU != 0 {
+		if maxMTU, ok := platformMaxMTU[platform.Name()]; ok && n.OVNKubernetesConfig.MTU > maxMTU-ovnGeneveOverhead {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ovnKubernetesConfig", "mtu"), int(n.OVNKubernetesConfig.MTU),
+				fmt.Sprintf("must not be larger than %d, the %s machine MTU of %d less %d bytes of overlay overhead", maxMTU-ovnGeneveOverhead, platform.Name(), maxMTU, ovnGeneveOverhead)))
+		}
+	}
 	switch {
 	case platform.Libvirt != nil:
 		errMsg := "overlaps with default Docker Bridge subnet"
@@ -418,259 +918,387 @@ func validateClusterNetwork(n *types.Networking, cn *types.ClusterNetworkEntry,
 	return allErrs
 }
 
-func validateControlPlane(platform *types.Platform, pool *types.MachinePool, fldPath *field.Path) field.ErrorList {
-	allErrs := field.ErrorList{}
-	if pool.Name != types.MachinePoolControlPlaneRoleName {
-		allErrs = append(allErrs, field.NotSupported(fldPath.Child("name"), pool.Name, []string{types.MachinePoolControlPlaneRoleName}))
+// clusterNetworkHostSubnets returns the number of host subnets the cluster
+// networks provide for each IP family, along with the number of machines
+// requested in the install-config. Host subnets are allocated per IP family,
+// so every family must have enough of them for all of the nodes.
+func clusterNetworkHostSubnets(c *types.InstallConfig) (map[corev1.IPFamily]float64, int64) {
+	replicas := c.EtcdReplicas()
+	for _, p := range c.Compute {
+		if p.Replicas != nil {
+			replicas += *p.Replicas
+		}
 	}
-	if pool.Replicas != nil && *pool.Replicas == 0 {
-		allErrs = append(allErrs, field.Invalid(fldPath.Child("replicas"), pool.Replicas, "number of control plane replicas must be positive"))
+
+	hostSubnets := map[corev1.IPFamily]float64{}
+	for _, cn := range c.Networking.ClusterNetwork {
+		ones, bits := cn.CIDR.Mask.Size()
+		if cn.HostPrefix < int32(ones) || cn.HostPrefix > int32(bits) {
+			continue
+		}
+		family := corev1.IPv4Protocol
+		if bits == 128 {
+			family = corev1.IPv6Protocol
+		}
+		hostSubnets[family] += math.Pow(2, float64(cn.HostPrefix)-float64(ones))
 	}
-	allErrs = append(allErrs, ValidateMachinePool(platform, pool, fldPath)...)
-	return allErrs
+	return hostSubnets, replicas
 }
 
-func validateCompute(platform *types.Platform, control *types.MachinePool, pools []types.MachinePool, fldPath *field.Path) field.ErrorList {
+// validateClusterNetworkCapacity checks that the cluster networks provide a
+// host subnet for every machine requested in the install-config.
+func validateClusterNetworkCapacity(c *types.InstallConfig, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
-	poolNames := map[string]bool{}
-	for i, p := range pools {
-		poolFldPath := fldPath.Index(i)
-		if p.Name != types.MachinePoolComputeRoleName {
-			allErrs = append(allErrs, field.NotSupported(poolFldPath.Child("name"), p.Name, []string{"worker"}))
-		}
-		if poolNames[p.Name] {
-			allErrs = append(allErrs, field.Duplicate(poolFldPath.Child("name"), p.Name))
-		}
-		poolNames[p.Name] = true
-		if control != nil && control.Architecture != p.Architecture {
-			allErrs = append(allErrs, field.Invalid(poolFldPath.Child("architecture"), p.Architecture, "heteregeneous multi-arch is not supported; compute pool architecture must match control plane"))
+	if !pluginsUsingHostPrefix.Has(c.Networking.NetworkType) {
+		return allErrs
+	}
+	hostSubnets, replicas := clusterNetworkHostSubnets(c)
+	for _, family := range []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol} {
+		if count, ok := hostSubnets[family]; ok && count < float64(replicas) {
+			allErrs = append(allErrs, field.Invalid(fldPath, strings.Join(clusterNetworksToStrings(c.Networking.ClusterNetwork), ", "),
+				fmt.Sprintf("the %s cluster networks provide only %d host subnets, which is fewer than the %d machines requested", family, int(count), replicas)))
 		}
-		allErrs = append(allErrs, ValidateMachinePool(platform, &p, poolFldPath)...)
 	}
 	return allErrs
 }
 
-// vips defines the VIPs to validate
-type vips struct {
-	API     []string
-	Ingress []string
-}
-
-// vipFields defines the field names to which validation errors for each VIP
-// type should be assigned to
-type vipFields struct {
-	APIVIPs     string
-	IngressVIPs string
-}
-
-// validateVIPsForPlatform validates the VIPs (for API and Ingress) for the
-// given platform
-func validateVIPsForPlatform(network *types.Networking, platform *types.Platform, fldPath *field.Path) field.ErrorList {
-	allErrs := field.ErrorList{}
-
-	virtualIPs := vips{}
-	newVIPsFields := vipFields{
-		APIVIPs:     "apiVIPs",
-		IngressVIPs: "ingressVIPs",
+// ClusterNetworkWarnings returns warnings about cluster networks which are
+// valid but are likely to cause problems once the cluster is running: host
+// subnets which leave little room to scale the cluster and host prefixes
+// which limit the number of pods that can run on a node. It assumes the
+// install-config has already been validated.
+func ClusterNetworkWarnings(c *types.InstallConfig) []string {
+	var warnings []string
+	if c.Networking == nil || !pluginsUsingHostPrefix.Has(c.Networking.NetworkType) {
+		return warnings
 	}
-	switch {
-	case platform.BareMetal != nil:
-		allErrs = append(allErrs, ensureIPv4IsFirstInDualStackSlice(&platform.BareMetal.APIVIPs, fldPath.Child(baremetal.Name, newVIPsFields.APIVIPs))...)
-		allErrs = append(allErrs, ensureIPv4IsFirstInDualStackSlice(&platform.BareMetal.IngressVIPs, fldPath.Child(baremetal.Name, newVIPsFields.IngressVIPs))...)
+	fldPath := field.NewPath("networking", "clusterNetwork")
 
-		virtualIPs = vips{
-			API:     platform.BareMetal.APIVIPs,
-			Ingress: platform.BareMetal.IngressVIPs,
+	for i, cn := range c.Networking.ClusterNetwork {
+		ones, bits := cn.CIDR.Mask.Size()
+		if bits != 32 || cn.HostPrefix < int32(ones) || cn.HostPrefix > int32(bits) {
+			continue
 		}
+		if c.NodeConfig != nil && c.NodeConfig.MaxPods != 0 {
+			// checked against nodeConfig.maxPods by validateNodeConfig
+			continue
+		}
+		if podAddresses := math.Pow(2, float64(bits)-float64(cn.HostPrefix)); podAddresses < defaultMaxPods {
+			warnings = append(warnings, fmt.Sprintf("%s: a host prefix of /%d leaves room for at most %d pods per node, which is less than the default maximum of %d",
+				fldPath.Index(i).Child("hostPrefix"), cn.HostPrefix, int(podAddresses), defaultMaxPods))
+		}
+	}
 
-		allErrs = append(allErrs, validateAPIAndIngressVIPs(virtualIPs, newVIPsFields, true, network, fldPath.Child(baremetal.Name))...)
-	case platform.Nutanix != nil:
-		allErrs = append(allErrs, ensureIPv4IsFirstInDualStackSlice(&platform.Nutanix.APIVIPs, fldPath.Child(nutanix.Name, newVIPsFields.APIVIPs))...)
-		allErrs = append(allErrs, ensureIPv4IsFirstInDualStackSlice(&platform.Nutanix.IngressVIPs, fldPath.Child(nutanix.Name, newVIPsFields.IngressVIPs))...)
-
-		virtualIPs = vips{
-			API:     platform.Nutanix.APIVIPs,
-			Ingress: platform.Nutanix.IngressVIPs,
+	hostSubnets, replicas := clusterNetworkHostSubnets(c)
+	for _, family := range []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol} {
+		if count, ok := hostSubnets[family]; ok && count >= float64(replicas) && count < float64(2*replicas) {
+			warnings = append(warnings, fmt.Sprintf("%s: the %s cluster networks provide only %d host subnets for %d machines, leaving little room to scale the cluster",
+				fldPath, family, int(count), replicas))
 		}
+	}
+	return warnings
+}
 
-		allErrs = append(allErrs, validateAPIAndIngressVIPs(virtualIPs, newVIPsFields, false, network, fldPath.Child(nutanix.Name))...)
-	case platform.OpenStack != nil:
-		allErrs = append(allErrs, ensureIPv4IsFirstInDualStackSlice(&platform.OpenStack.APIVIPs, fldPath.Child(openstack.Name, newVIPsFields.APIVIPs))...)
-		allErrs = append(allErrs, ensureIPv4IsFirstInDualStackSlice(&platform.OpenStack.IngressVIPs, fldPath.Child(openstack.Name, newVIPsFields.IngressVIPs))...)
+func clusterNetworksToStrings(networks []types.ClusterNetworkEntry) []string {
+	var diag []string
+	for _, cn := range networks {
+		diag = append(diag, fmt.Sprintf("%s (hostPrefix %d)", cn.CIDR.String(), cn.HostPrefix))
+	}
+	return diag
+}
 
-		virtualIPs = vips{
-			API:     platform.OpenStack.APIVIPs,
-			Ingress: platform.OpenStack.IngressVIPs,
-		}
+// maxControlPlaneReplicas is the largest control plane supported, beyond which
+// the etcd write latency degrades the cluster.
+const maxControlPlaneReplicas = 5
 
-		allErrs = append(allErrs, validateAPIAndIngressVIPs(virtualIPs, newVIPsFields, true, network, fldPath.Child(openstack.Name))...)
-	case platform.VSphere != nil:
-		allErrs = append(allErrs, ensureIPv4IsFirstInDualStackSlice(&platform.VSphere.APIVIPs, fldPath.Child(vsphere.Name, newVIPsFields.APIVIPs))...)
-		allErrs = append(allErrs, ensureIPv4IsFirstInDualStackSlice(&platform.VSphere.IngressVIPs, fldPath.Child(vsphere.Name, newVIPsFields.IngressVIPs))...)
+// largeControlPlanePlatforms are the platforms which support control planes of
+// more than three replicas.
+var largeControlPlanePlatforms = sets.NewString(aws.Name, azure.Name, baremetal.Name, gcp.Name, none.Name, nutanix.Name, vsphere.Name)
 
-		virtualIPs = vips{
-			API:     platform.VSphere.APIVIPs,
-			Ingress: platform.VSphere.IngressVIPs,
-		}
+// controlPlaneMachineSetPlatforms are the platforms on which a
+// ControlPlaneMachineSet is generated for the control plane.
+var controlPlaneMachineSetPlatforms = sets.NewString(aws.Name, azure.Name, gcp.Name)
 
-		allErrs = append(allErrs, validateAPIAndIngressVIPs(virtualIPs, newVIPsFields, false, network, fldPath.Child(vsphere.Name))...)
-	case platform.Ovirt != nil:
-		allErrs = append(allErrs, ensureIPv4IsFirstInDualStackSlice(&platform.Ovirt.APIVIPs, fldPath.Child(ovirt.Name, newVIPsFields.APIVIPs))...)
-		allErrs = append(allErrs, ensureIPv4IsFirstInDualStackSlice(&platform.Ovirt.IngressVIPs, fldPath.Child(ovirt.Name, newVIPsFields.IngressVIPs))...)
+// arbiterPlatforms are the platforms which support two-node clusters with an
+// arbiter. The installer does not provision the arbiter machine, so only
+// user-provisioned platforms are supported.
+var arbiterPlatforms = sets.NewString(none.Name)
 
-		newVIPsFields = vipFields{
-			APIVIPs:     "api_vips",
-			IngressVIPs: "ingress_vips",
+func validateControlPlane(platform *types.Platform, pool *types.MachinePool, arbiter *types.MachinePool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if pool.Name != types.MachinePoolControlPlaneRoleName {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("name"), pool.Name, []string{types.MachinePoolControlPlaneRoleName}))
+	}
+	if pool.Replicas != nil {
+		switch replicas := *pool.Replicas; {
+		case replicas == 0:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("replicas"), pool.Replicas, "number of control plane replicas must be positive"))
+		case replicas > maxControlPlaneReplicas:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("replicas"), pool.Replicas, fmt.Sprintf("number of control plane replicas must not exceed %d", maxControlPlaneReplicas)))
+		case replicas > 3 && !largeControlPlanePlatforms.Has(platform.Name()):
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("replicas"), pool.Replicas, fmt.Sprintf("control planes of more than 3 replicas are not supported on %s, supported platforms are %s", platform.Name(), strings.Join(largeControlPlanePlatforms.List(), ", "))))
+		case replicas == 2 && arbiter == nil:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("replicas"), pool.Replicas, "a control plane of 2 replicas requires an arbiter"))
 		}
-		virtualIPs = vips{
-			API:     platform.Ovirt.APIVIPs,
-			Ingress: platform.Ovirt.IngressVIPs,
+	}
+	if pool.MachineSetsInAllZones {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("machineSetsInAllZones"), "MachineSets are only generated for compute pools"))
+	}
+	if pool.Autoscaling != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("autoscaling"), "only compute pools can be autoscaled"))
+	}
+	if pool.Accelerators != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("accelerators"), "accelerators are only supported in compute pools"))
+	}
+	if pool.ContainerStorage != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("containerStorage"), "a dedicated container storage disk is only supported in compute pools"))
+	}
+	if pool.OSType != "" && pool.OSType != types.OSTypeLinux {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("osType"), pool.OSType, []string{string(types.OSTypeLinux)}))
+	}
+	switch pool.ControlPlaneMachineSetState {
+	case "":
+	case types.ControlPlaneMachineSetStateActive, types.ControlPlaneMachineSetStateInactive:
+		if !controlPlaneMachineSetPlatforms.Has(platform.Name()) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("controlPlaneMachineSetState"), pool.ControlPlaneMachineSetState, fmt.Sprintf("a ControlPlaneMachineSet is not generated on %s, supported platforms are %s", platform.Name(), strings.Join(controlPlaneMachineSetPlatforms.List(), ", "))))
 		}
-
-		allErrs = append(allErrs, validateAPIAndIngressVIPs(virtualIPs, newVIPsFields, true, network, fldPath.Child(ovirt.Name))...)
 	default:
-		//no vips to validate on this platform
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("controlPlaneMachineSetState"), pool.ControlPlaneMachineSetState, []string{string(types.ControlPlaneMachineSetStateActive), string(types.ControlPlaneMachineSetStateInactive)}))
 	}
-
+	allErrs = append(allErrs, ValidateMachinePool(platform, pool, fldPath)...)
 	return allErrs
 }
 
-func ensureIPv4IsFirstInDualStackSlice(vips *[]string, fldPath *field.Path) field.ErrorList {
-	errList := field.ErrorList{}
-	isDualStack, err := utilsnet.IsDualStackIPStrings(*vips)
-	if err != nil {
-		errList = append(errList, field.Invalid(fldPath, vips, err.Error()))
-		return errList
+// validateBootstrapInPlace checks that bootstrap-in-place is only used for
+// single-node clusters on the platforms which support it, and that the
+// installation disk is a block device.
+func validateBootstrapInPlace(c *types.InstallConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if platform := c.Platform.Name(); !sets.NewString(types.BootstrapInPlacePlatforms...).Has(platform) {
+		allErrs = append(allErrs, field.Invalid(fldPath, platform, fmt.Sprintf("bootstrap in place is only supported on platforms %s, single-node clusters on %s are installed with a bootstrap machine", strings.Join(types.BootstrapInPlacePlatforms, ", "), platform)))
 	}
-
-	if isDualStack {
-		if len(*vips) == 2 {
-			if utilsnet.IsIPv4String((*vips)[1]) && utilsnet.IsIPv6String((*vips)[0]) {
-				(*vips)[0], (*vips)[1] = (*vips)[1], (*vips)[0]
-			}
-		} else {
-			errList = append(errList, field.Invalid(fldPath, vips, "wrong number of VIPs given. Expecting 2 VIPs for dual stack"))
-			return errList
-		}
+	switch disk := c.BootstrapInPlace.InstallationDisk; {
+	case disk == "":
+		allErrs = append(allErrs, field.Required(fldPath.Child("installationDisk"), "installationDisk must be set the target disk drive for the installation"))
+	case !strings.HasPrefix(disk, "/dev/") || path.Clean(disk) != disk:
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("installationDisk"), disk, "installationDisk must be the path of a block device under /dev"))
 	}
-
-	return errList
+	if c.ControlPlane != nil && c.ControlPlane.Replicas != nil && *c.ControlPlane.Replicas != 1 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("controlPlane", "replicas"), c.ControlPlane.Replicas, "bootstrap in place requires a single ControlPlane replica"))
+	}
+	return allErrs
 }
 
-// validateAPIAndIngressVIPs validates the API and Ingress VIPs
-func validateAPIAndIngressVIPs(vips vips, fieldNames vipFields, vipIsRequired bool, n *types.Networking, fldPath *field.Path) field.ErrorList {
+func validateArbiter(platform *types.Platform, pool *types.MachinePool, control *types.MachinePool, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
+	if pool.Name != types.MachinePoolArbiterRoleName {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("name"), pool.Name, []string{types.MachinePoolArbiterRoleName}))
+	}
+	if !arbiterPlatforms.Has(platform.Name()) {
+		allErrs = append(allErrs, field.Invalid(fldPath, platform.Name(), fmt.Sprintf("an arbiter is not supported on %s, supported platforms are %s", platform.Name(), strings.Join(arbiterPlatforms.List(), ", "))))
+	}
+	if pool.Replicas != nil && *pool.Replicas != 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("replicas"), pool.Replicas, "number of arbiter replicas must be 1"))
+	}
+	if control != nil && control.Replicas != nil && *control.Replicas != 2 {
+		allErrs = append(allErrs, field.Invalid(fldPath, control.Replicas, "an arbiter requires a control plane of 2 replicas"))
+	}
+	if control != nil && control.Architecture != pool.Architecture {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("architecture"), pool.Architecture, "heteregeneous multi-arch is not supported; arbiter architecture must match control plane"))
+	}
+	allErrs = append(allErrs, ValidateMachinePool(platform, pool, fldPath)...)
+	return allErrs
+}
 
-	if len(vips.API) == 0 {
-		if vipIsRequired {
-			allErrs = append(allErrs, field.Required(fldPath.Child(fieldNames.APIVIPs), "must specify at least one VIP for the API"))
+func validateCompute(platform *types.Platform, control *types.MachinePool, pools []types.MachinePool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	poolNames := map[string]bool{}
+	for i, p := range pools {
+		poolFldPath := fldPath.Index(i)
+		switch p.Name {
+		case types.MachinePoolComputeRoleName:
+		case types.MachinePoolInfraRoleName:
+			allErrs = append(allErrs, validateInfraPool(platform, &p, pools, poolFldPath)...)
+		case types.MachinePoolWindowsRoleName:
+			allErrs = append(allErrs, validateWindowsPool(platform, &p, poolFldPath)...)
+		default:
+			allErrs = append(allErrs, field.NotSupported(poolFldPath.Child("name"), p.Name, []string{types.MachinePoolComputeRoleName, types.MachinePoolInfraRoleName, types.MachinePoolWindowsRoleName}))
 		}
-	} else if len(vips.API) <= 2 {
-		for _, vip := range vips.API {
-			if err := validate.IP(vip); err != nil {
-				allErrs = append(allErrs, field.Invalid(fldPath.Child(fieldNames.APIVIPs), vip, err.Error()))
-			}
-
-			for _, ingressVIP := range vips.Ingress {
-				apiVIPNet := net.ParseIP(vip)
-				ingressVIPNet := net.ParseIP(ingressVIP)
-
-				if apiVIPNet.Equal(ingressVIPNet) {
-					allErrs = append(allErrs, field.Invalid(fldPath.Child(fieldNames.APIVIPs), vip, "VIP for API must not be one of the Ingress VIPs"))
-				}
+		switch p.OSType {
+		case "", types.OSTypeLinux:
+			if p.Name == types.MachinePoolWindowsRoleName {
+				allErrs = append(allErrs, field.Invalid(poolFldPath.Child("osType"), p.OSType, "the windows pool must have the Windows osType"))
 			}
-
-			if err := ValidateIPinMachineCIDR(vip, n); err != nil {
-				allErrs = append(allErrs, field.Invalid(fldPath.Child(fieldNames.APIVIPs), vip, err.Error()))
-			}
-
-			if utilsnet.IsIPv6String(vip) && n.NetworkType == string(operv1.NetworkTypeOpenShiftSDN) {
-				allErrs = append(allErrs, field.Invalid(fldPath.Child(fieldNames.APIVIPs), vip, "IPv6 is not supported on OpenShiftSDN"))
+		case types.OSTypeWindows:
+			if p.Name != types.MachinePoolWindowsRoleName {
+				allErrs = append(allErrs, field.Invalid(poolFldPath.Child("osType"), p.OSType, "Windows machines are only supported in the windows pool"))
 			}
+		default:
+			allErrs = append(allErrs, field.NotSupported(poolFldPath.Child("osType"), p.OSType, []string{string(types.OSTypeLinux), string(types.OSTypeWindows)}))
 		}
-
-		if len(vips.Ingress) == 0 {
-			allErrs = append(allErrs, field.Required(fldPath.Child(fieldNames.IngressVIPs), "must specify VIP for ingress, when VIP for API is set"))
+		if poolNames[p.Name] {
+			allErrs = append(allErrs, field.Duplicate(poolFldPath.Child("name"), p.Name))
 		}
-
-		if len(vips.API) == 1 {
-			hasIPv4, hasIPv6, presence, _ := inferIPVersionFromInstallConfig(n)
-
-			apiVIPIPFamily := corev1.IPv4Protocol
-			if utilsnet.IsIPv6String(vips.API[0]) {
-				apiVIPIPFamily = corev1.IPv6Protocol
-			}
-
-			if hasIPv4 && hasIPv6 && apiVIPIPFamily != presence["machineNetwork"].Primary {
-				allErrs = append(allErrs, field.Invalid(fldPath.Child(fieldNames.APIVIPs), vips.API[0], "VIP for the API must be of the same IP family with machine network's primary IP Family for dual-stack IPv4/IPv6"))
-			}
-		} else if len(vips.API) == 2 {
-			if isDualStack, _ := utilsnet.IsDualStackIPStrings(vips.API); !isDualStack {
-				allErrs = append(allErrs, field.Invalid(fldPath.Child(fieldNames.APIVIPs), vips.API, "If two API VIPs are given, one must be an IPv4 address, the other an IPv6"))
-			}
+		poolNames[p.Name] = true
+		if p.Autoscaling != nil {
+			allErrs = append(allErrs, validateAutoscaling(platform, &p, poolFldPath.Child("autoscaling"))...)
 		}
-	} else {
-		allErrs = append(allErrs, field.TooMany(fldPath.Child(fieldNames.APIVIPs), len(vips.API), 2))
+		if p.Accelerators != nil {
+			allErrs = append(allErrs, validateAccelerators(platform, &p, poolFldPath.Child("accelerators"))...)
+		}
+		if p.ContainerStorage != nil && p.Name == types.MachinePoolComputeRoleName {
+			allErrs = append(allErrs, validateContainerStorage(platform.Name(), p.ContainerStorage, poolFldPath.Child("containerStorage"))...)
+		}
+		if p.ControlPlaneMachineSetState != "" {
+			allErrs = append(allErrs, field.Forbidden(poolFldPath.Child("controlPlaneMachineSetState"), "a ControlPlaneMachineSet is only generated for the control plane"))
+		}
+		if control != nil && control.Architecture != p.Architecture {
+			allErrs = append(allErrs, field.Invalid(poolFldPath.Child("architecture"), p.Architecture, "heteregeneous multi-arch is not supported; compute pool architecture must match control plane"))
+		}
+		allErrs = append(allErrs, ValidateMachinePool(platform, &p, poolFldPath)...)
 	}
+	return allErrs
+}
 
-	if len(vips.Ingress) == 0 {
-		if vipIsRequired {
-			allErrs = append(allErrs, field.Required(fldPath.Child(fieldNames.IngressVIPs), "must specify at least one VIP for the Ingress"))
+// validateInfraPool checks the infra compute pool. The infra machines are in
+// the worker MachineConfigPool, so they get the MachineConfigs of the worker
+// pool and cannot have MachineConfigs of their own.
+func validateInfraPool(platform *types.Platform, pool *types.MachinePool, pools []types.MachinePool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if platform.Name() == none.Name {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), pool.Name, "an infra pool requires MachineSets, which are not generated on none"))
+	}
+	var worker *types.MachinePool
+	for i := range pools {
+		if pools[i].Name == types.MachinePoolComputeRoleName {
+			worker = &pools[i]
+			break
 		}
-	} else if len(vips.Ingress) <= 2 {
-		for _, vip := range vips.Ingress {
-			if err := validate.IP(vip); err != nil {
-				allErrs = append(allErrs, field.Invalid(fldPath.Child(fieldNames.IngressVIPs), vip, err.Error()))
-			}
-
-			if err := ValidateIPinMachineCIDR(vip, n); err != nil {
-				allErrs = append(allErrs, field.Invalid(fldPath.Child(fieldNames.IngressVIPs), vip, err.Error()))
-			}
+	}
+	if worker == nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), pool.Name, "an infra pool requires a worker pool"))
+	} else if pool.Hyperthreading != worker.Hyperthreading {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("hyperthreading"), pool.Hyperthreading, "the infra pool must have the hyperthreading of the worker pool"))
+	}
+	if pool.DiskEncryption != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("diskEncryption"), "the infra machines use the disk encryption of the worker pool"))
+	}
+	if pool.DiskMirroring != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("diskMirroring"), "the infra machines use the disk mirroring of the worker pool"))
+	}
+	if pool.MachineConfig != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("machineConfig"), "the infra machines use the machine config of the worker pool"))
+	}
+	if pool.NTP != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("ntp"), "the infra machines use the ntp of the worker pool"))
+	}
+	if pool.ContainerStorage != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("containerStorage"), "the infra machines use the container storage of the worker pool"))
+	}
+	return allErrs
+}
 
-			if utilsnet.IsIPv6String(vip) && n.NetworkType == string(operv1.NetworkTypeOpenShiftSDN) {
-				allErrs = append(allErrs, field.Invalid(fldPath.Child(fieldNames.IngressVIPs), vip, "IPv6 is not supported on OpenShiftSDN"))
-			}
+// validateWindowsPool checks the windows compute pool, whose machines boot
+// the Windows image of the pool. The MachineConfigs do not apply to Windows
+// machines.
+func validateWindowsPool(platform *types.Platform, pool *types.MachinePool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch platform.Name() {
+	case aws.Name:
+		if pool.Platform.AWS == nil || pool.Platform.AWS.AMIID == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("platform", "aws", "amiID"), "the AMI of the Windows image is required"))
 		}
-
-		if len(vips.API) == 0 {
-			allErrs = append(allErrs, field.Required(fldPath.Child(fieldNames.APIVIPs), "must specify VIP for API, when VIP for ingress is set"))
+	case azure.Name:
+		if pool.Platform.Azure == nil || pool.Platform.Azure.OSImage.Publisher == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("platform", "azure", "osImage"), "the Windows image is required"))
 		}
+	default:
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), pool.Name, fmt.Sprintf("Windows machines are not supported on %s, supported platforms are aws, azure", platform.Name())))
+	}
+	if pool.Architecture != types.ArchitectureAMD64 {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("architecture"), pool.Architecture, []string{types.ArchitectureAMD64}))
+	}
+	if pool.DiskEncryption != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("diskEncryption"), "disk encryption is not supported on Windows machines"))
+	}
+	if pool.DiskMirroring != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("diskMirroring"), "disk mirroring is not supported on Windows machines"))
+	}
+	if pool.MachineConfig != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("machineConfig"), "machine configs do not apply to Windows machines"))
+	}
+	if pool.NTP != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("ntp"), "machine configs do not apply to Windows machines"))
+	}
+	if pool.ContainerStorage != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("containerStorage"), "machine configs do not apply to Windows machines"))
+	}
+	return allErrs
+}
 
-		if len(vips.Ingress) == 1 {
-			hasIPv4, hasIPv6, presence, _ := inferIPVersionFromInstallConfig(n)
-
-			ingressVIPIPFamily := corev1.IPv4Protocol
-			if utilsnet.IsIPv6String(vips.Ingress[0]) {
-				ingressVIPIPFamily = corev1.IPv6Protocol
-			}
-
-			if hasIPv4 && hasIPv6 && ingressVIPIPFamily != presence["machineNetwork"].Primary {
-				allErrs = append(allErrs, field.Invalid(fldPath.Child(fieldNames.IngressVIPs), vips.Ingress[0], "VIP for the Ingress must be of the same IP family with machine network's primary IP Family for dual-stack IPv4/IPv6"))
-			}
-		} else if len(vips.Ingress) == 2 {
-			if isDualStack, _ := utilsnet.IsDualStackIPStrings(vips.Ingress); !isDualStack {
-				allErrs = append(allErrs, field.Invalid(fldPath.Child(fieldNames.IngressVIPs), vips.Ingress, "If two Ingress VIPs are given, one must be an IPv4 address, the other an IPv6"))
-			}
+// validateWindowsNetworking checks that the network type of the cluster
+// supports the hybrid overlay required by the Windows machines.
+func validateWindowsNetworking(c *types.InstallConfig) field.ErrorList {
+	allErrs := field.ErrorList{}
+	windows := false
+	for _, p := range c.Compute {
+		if p.OSType == types.OSTypeWindows {
+			windows = true
 		}
-	} else {
-		allErrs = append(allErrs, field.TooMany(fldPath.Child(fieldNames.IngressVIPs), len(vips.Ingress), 2))
 	}
+	if !windows || c.Networking == nil {
+		return allErrs
+	}
+	fldPath := field.NewPath("networking")
+	if c.Networking.NetworkType != string(operv1.NetworkTypeOVNKubernetes) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("networkType"), c.Networking.NetworkType, fmt.Sprintf("Windows machines require the hybrid overlay of %s", operv1.NetworkTypeOVNKubernetes)))
+	} else if c.Networking.OVNKubernetesConfig == nil || c.Networking.OVNKubernetesConfig.HybridOverlay == nil {
+		allErrs = append(allErrs, field.Required(fldPath.Child("ovnKubernetesConfig", "hybridOverlay"), "the hybrid overlay is required by Windows machines"))
+	}
+	return allErrs
+}
 
+// validateAutoscaling checks the autoscaling range of the compute pool
+// against its replicas.
+func validateAutoscaling(platform *types.Platform, pool *types.MachinePool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if platform.Name() == none.Name {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "autoscaling requires MachineSets, which are not generated on none"))
+	}
+	a := pool.Autoscaling
+	if a.MinReplicas < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("minReplicas"), a.MinReplicas, "minReplicas must not be negative"))
+	}
+	if a.MaxReplicas < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxReplicas"), a.MaxReplicas, "maxReplicas must be positive"))
+	}
+	if a.MaxReplicas < a.MinReplicas {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxReplicas"), a.MaxReplicas, "maxReplicas must not be less than minReplicas"))
+	}
+	if pool.Replicas != nil && (*pool.Replicas < a.MinReplicas || *pool.Replicas > a.MaxReplicas) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Parent().Child("replicas"), *pool.Replicas, fmt.Sprintf("replicas must be within the autoscaling range of %d to %d", a.MinReplicas, a.MaxReplicas)))
+	}
 	return allErrs
 }
 
-// ValidateIPinMachineCIDR confirms if the specified VIP is in the machine CIDR.
-func ValidateIPinMachineCIDR(vip string, n *types.Networking) error {
-	var networks []string
+// acceleratorPlatforms are the platforms on which the GPUs of the instance
+// type of a pool are validated.
+var acceleratorPlatforms = sets.NewString(aws.Name, azure.Name)
 
-	for _, network := range n.MachineNetwork {
-		if network.CIDR.Contains(net.ParseIP(vip)) {
-			return nil
-		}
-		networks = append(networks, network.CIDR.String())
+func validateAccelerators(platform *types.Platform, pool *types.MachinePool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !acceleratorPlatforms.Has(platform.Name()) {
+		allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("accelerators are not supported on %s, supported platforms are %s", platform.Name(), strings.Join(acceleratorPlatforms.List(), ", "))))
 	}
-
-	return fmt.Errorf("IP expected to be in one of the machine networks: %s", strings.Join(networks, ","))
+	switch pool.Accelerators.Vendor {
+	case types.AcceleratorVendorNVIDIA, types.AcceleratorVendorAMD:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("vendor"), pool.Accelerators.Vendor, []string{string(types.AcceleratorVendorNVIDIA), string(types.AcceleratorVendorAMD)}))
+	}
+	if pool.OSType == types.OSTypeWindows {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "accelerators are not supported on Windows machines"))
+	}
+	return allErrs
 }
 
 func validatePlatform(platform *types.Platform, fldPath *field.Path, network *types.Networking, c *types.InstallConfig) field.ErrorList {
@@ -712,6 +1340,11 @@ func validatePlatform(platform *types.Platform, fldPath *field.Path, network *ty
 	if platform.Libvirt != nil {
 		validate(libvirt.Name, platform.Libvirt, func(f *field.Path) field.ErrorList { return libvirtvalidation.ValidatePlatform(platform.Libvirt, f) })
 	}
+	if platform.None != nil {
+		validate(none.Name, platform.None, func(f *field.Path) field.ErrorList {
+			return hostnetworkvalidation.ValidateHosts(platform.None.Hosts, f.Child("hosts"))
+		})
+	}
 	if platform.OpenStack != nil {
 		validate(openstack.Name, platform.OpenStack, func(f *field.Path) field.ErrorList {
 			return openstackvalidation.ValidatePlatform(platform.OpenStack, network, f, c)
@@ -799,6 +1432,23 @@ func validateImageContentSources(groups []types.ImageContentSource, fldPath *fie
 	return allErrs
 }
 
+func validateImageDigestSources(groups []types.ImageDigestSource, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for gidx, group := range groups {
+		groupf := fldPath.Index(gidx)
+		if err := validateNamedRepository(group.Source); err != nil {
+			allErrs = append(allErrs, field.Invalid(groupf.Child("source"), group.Source, err.Error()))
+		}
+
+		for midx, mirror := range group.Mirrors {
+			if err := validateNamedRepository(mirror); err != nil {
+				allErrs = append(allErrs, field.Invalid(groupf.Child("mirrors").Index(midx), mirror, err.Error()))
+			}
+		}
+	}
+	return allErrs
+}
+
 func validateNamedRepository(r string) error {
 	ref, err := dockerref.ParseNamed(r)
 	if err != nil {
@@ -926,24 +1576,59 @@ func validateIPProxy(proxy string, n *types.Networking, fldPath *field.Path) fie
 	return allErrs
 }
 
-// validateFIPSconfig checks if the current install-config is compatible with FIPS standards
-// and returns an error if it's not the case. As of this writing, only rsa or ecdsa algorithms are supported
-// for ssh keys on FIPS.
-func validateFIPSconfig(c *types.InstallConfig) field.ErrorList {
+// validateSSHKeys checks that each of the public keys listed in sshKey can be
+// parsed.
+func validateSSHKeys(c *types.InstallConfig) field.ErrorList {
 	allErrs := field.ErrorList{}
-	sshParsedKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(c.SSHKey))
-	if err != nil {
-		allErrs = append(allErrs, field.Invalid(field.NewPath("sshKey"), c.SSHKey, fmt.Sprintf("Fatal error trying to parse configured public key: %s", err)))
-	} else {
-		sshKeyType := sshParsedKey.Type()
-		re := regexp.MustCompile(`^ecdsa-sha2-nistp\d{3}$|^ssh-rsa$`)
-		if !re.MatchString(sshKeyType) {
-			allErrs = append(allErrs, field.Invalid(field.NewPath("sshKey"), c.SSHKey, fmt.Sprintf("SSH key type %s unavailable when FIPS is enabled. Please use rsa or ecdsa.", sshKeyType)))
+	fldPath := field.NewPath("sshKey")
+	keys := c.SSHKeys()
+	if len(keys) == 0 {
+		return append(allErrs, field.Invalid(fldPath, c.SSHKey, "no public keys found"))
+	}
+	for _, key := range keys {
+		if err := validate.SSHPublicKey(key); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath, key, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// fipsSSHKeyType matches the ssh key algorithms supported on FIPS, which as of
+// this writing are only rsa and ecdsa.
+var fipsSSHKeyType = regexp.MustCompile(`^ecdsa-sha2-nistp\d{3}$|^ssh-rsa$`)
+
+// validateFIPS checks that the settings of an install-config with fips
+// enabled are compatible with FIPS mode.
+func validateFIPS(c *types.InstallConfig) field.ErrorList {
+	allErrs := field.ErrorList{}
+	fipsPath := field.NewPath("fips")
+
+	if c.IsOKD() {
+		allErrs = append(allErrs, field.Invalid(fipsPath, c.FIPS, "FIPS mode is only supported with RHCOS, the OKD operating system images are not FIPS capable"))
+	}
+
+	for _, key := range c.SSHKeys() {
+		sshParsedKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key))
+		if err != nil {
+			// reported by validateSSHKeys
+			continue
+		}
+		if sshKeyType := sshParsedKey.Type(); !fipsSSHKeyType.MatchString(sshKeyType) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("sshKey"), key, fmt.Sprintf("SSH key type %s unavailable when FIPS is enabled. Please use rsa or ecdsa.", sshKeyType)))
 		}
 	}
 	return allErrs
 }
 
+// capabilityDependencies maps optional capabilities to the capabilities they
+// require in order to function. Required capabilities which are not optional
+// in this release are always enabled and therefore always satisfied.
+var capabilityDependencies = map[configv1.ClusterVersionCapability][]configv1.ClusterVersionCapability{
+	configv1.ClusterVersionCapabilityConsole:     {"Ingress"},
+	configv1.ClusterVersionCapabilityMarketplace: {"OperatorLifecycleManager"},
+	configv1.ClusterVersionCapabilityCSISnapshot: {configv1.ClusterVersionCapabilityStorage},
+}
+
 // validateCapabilities checks if additional, optional OpenShift components are specified in the
 // install-config to be included in the installation.
 func validateCapabilities(c *types.Capabilities, fldPath *field.Path) field.ErrorList {
@@ -958,6 +1643,9 @@ func validateCapabilities(c *types.Capabilities, fldPath *field.Path) field.Erro
 			allAvailableCapabilities.Insert(string(capability))
 		}
 	}
+	for _, preset := range types.CapabilityPresetNames() {
+		allCapabilitySets.Insert(string(preset))
+	}
 
 	if !allCapabilitySets.Has(string(c.BaselineCapabilitySet)) {
 		allErrs = append(allErrs, field.NotSupported(fldPath.Child("baselineCapabilitySet"), c.BaselineCapabilitySet, allCapabilitySets.List()))
@@ -969,6 +1657,48 @@ func validateCapabilities(c *types.Capabilities, fldPath *field.Path) field.Erro
 			allErrs = append(allErrs, field.NotSupported(fldPath.Child("additionalEnabledCapabilities").Index(i), capability, allAvailableCapabilities.List()))
 		}
 	}
+
+	allErrs = append(allErrs, validateCapabilityDependencies(c, allAvailableCapabilities, fldPath)...)
+	return allErrs
+}
+
+// validateCapabilityDependencies checks that every enabled capability has the
+// capabilities it depends on enabled as well. Dependencies which are not among
+// the optional capabilities are not checked.
+func validateCapabilityDependencies(c *types.Capabilities, optionalCapabilities sets.String, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	baseline, ok := configv1.ClusterVersionCapabilitySets[c.BaselineCapabilitySet]
+	if !ok {
+		baseline, _ = types.CapabilityPreset(c.BaselineCapabilitySet)
+	}
+	enabled := sets.NewString()
+	for _, capability := range baseline {
+		enabled.Insert(string(capability))
+	}
+	for _, capability := range c.AdditionalEnabledCapabilities {
+		enabled.Insert(string(capability))
+	}
+
+	checkDependencies := func(capability configv1.ClusterVersionCapability, fldPath *field.Path) {
+		for _, dependency := range capabilityDependencies[capability] {
+			if optionalCapabilities.Has(string(dependency)) && !enabled.Has(string(dependency)) {
+				allErrs = append(allErrs, field.Invalid(fldPath, capability,
+					fmt.Sprintf("the %s capability requires the %s capability to be enabled", capability, dependency)))
+			}
+		}
+	}
+	for _, capability := range baseline {
+		checkDependencies(capability, fldPath.Child("baselineCapabilitySet"))
+	}
+	additional := sets.NewString()
+	for i, capability := range c.AdditionalEnabledCapabilities {
+		if additional.Has(string(capability)) {
+			continue
+		}
+		additional.Insert(string(capability))
+		checkDependencies(capability, fldPath.Child("additionalEnabledCapabilities").Index(i))
+	}
 	return allErrs
 }
 
@@ -1000,6 +1730,14 @@ func validateFeatureSet(c *types.InstallConfig) field.ErrorList {
 	if c.FeatureSet != configv1.TechPreviewNoUpgrade {
 		errMsg := "the TechPreviewNoUpgrade feature set must be enabled to use this field"
 
+		if c.Arbiter != nil {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("arbiter"), errMsg))
+		}
+
+		if c.APIServer != nil && c.APIServer.Encryption != nil && c.APIServer.Encryption.Type == types.EncryptionTypeKMS {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("apiServer", "encryption", "type"), errMsg))
+		}
+
 		if c.VSphere != nil {
 			if len(c.VSphere.FailureDomains) > 0 {
 				allErrs = append(allErrs, field.Forbidden(field.NewPath("platform", "vsphere", "failureDomains"), errMsg))
@@ -1025,3 +1763,63 @@ func validateFeatureSet(c *types.InstallConfig) field.ErrorList {
 
 	return allErrs
 }
+
+// validSystemReservedResources are the resources which can be reserved for
+// the operating system daemons.
+var validSystemReservedResources = sets.NewString(
+	string(corev1.ResourceCPU),
+	string(corev1.ResourceMemory),
+	string(corev1.ResourceEphemeralStorage),
+)
+
+// validateNodeConfig checks the kubelet configuration and node tuning.
+func validateNodeConfig(c *types.InstallConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	n := c.NodeConfig
+
+	if n.MaxPods < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxPods"), n.MaxPods, "must be positive"))
+	} else if n.MaxPods > 0 && c.Networking != nil && pluginsUsingHostPrefix.Has(c.Networking.NetworkType) {
+		for i, cn := range c.Networking.ClusterNetwork {
+			ones, bits := cn.CIDR.Mask.Size()
+			if bits != 32 || cn.HostPrefix < int32(ones) || cn.HostPrefix > int32(bits) {
+				continue
+			}
+			if podAddresses := math.Pow(2, float64(bits)-float64(cn.HostPrefix)); podAddresses < float64(n.MaxPods) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("maxPods"), n.MaxPods,
+					fmt.Sprintf("networking.clusterNetwork[%d] leaves room for at most %d pods per node", i, int(podAddresses))))
+			}
+		}
+	}
+
+	names := make([]string, 0, len(n.SystemReserved))
+	for name := range n.SystemReserved {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := n.SystemReserved[name]
+		if !validSystemReservedResources.Has(name) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("systemReserved"), name, validSystemReservedResources.List()))
+			continue
+		}
+		if q, err := resource.ParseQuantity(value); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("systemReserved").Key(name), value, err.Error()))
+		} else if q.Sign() < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("systemReserved").Key(name), value, "must not be negative"))
+		}
+	}
+
+	switch n.CgroupMode {
+	case configv1.CgroupModeEmpty, configv1.CgroupModeV1, configv1.CgroupModeV2:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("cgroupMode"), n.CgroupMode, []string{string(configv1.CgroupModeV1), string(configv1.CgroupModeV2)}))
+	}
+
+	if n.CPUPartitioning != nil {
+		if err := validate.CPUSet(n.CPUPartitioning.ReservedCPUs); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("cpuPartitioning", "reservedCPUs"), n.CPUPartitioning.ReservedCPUs, err.Error()))
+		}
+	}
+	return allErrs
+}
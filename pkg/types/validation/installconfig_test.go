@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/pborman/uuid"
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/pointer"
 	utilsslice "k8s.io/utils/strings/slices"
@@ -23,6 +25,7 @@ import (
 	"github.com/openshift/installer/pkg/types/gcp"
 	"github.com/openshift/installer/pkg/types/ibmcloud"
 	"github.com/openshift/installer/pkg/types/libvirt"
+	"github.com/openshift/installer/pkg/types/loadbalancer"
 	"github.com/openshift/installer/pkg/types/none"
 	"github.com/openshift/installer/pkg/types/nutanix"
 	"github.com/openshift/installer/pkg/types/openstack"
@@ -33,6 +36,42 @@ import (
 
 const TechPreviewNoUpgrade = "TechPreviewNoUpgrade"
 
+const validRegistryCA = `-----BEGIN CERTIFICATE-----
+MIIF2zCCA8OgAwIBAgICEAAwDQYJKoZIhvcNAQELBQAwgYExCzAJBgNVBAYTAlVT
+MRcwFQYDVQQIDA5Ob3J0aCBDYXJvbGluYTEQMA4GA1UEBwwHUmFsZWlnaDEUMBIG
+A1UECgwLUmVkIEhhdCBJbmMxHzAdBgNVBAsMFk9wZW5TaGlmdCBJbnN0YWxsIFRl
+c3QxEDAOBgNVBAMMB1Jvb3QgQ0EwHhcNMTkwNzIyMjAwNzUxWhcNMjkwNzE5MjAw
+NzUxWjB3MQswCQYDVQQGEwJVUzEXMBUGA1UECAwOTm9ydGggQ2Fyb2xpbmExFDAS
+BgNVBAoMC1JlZCBIYXQgSW5jMR8wHQYDVQQLDBZPcGVuU2hpZnQgSW5zdGFsbCBU
+ZXN0MRgwFgYDVQQDDA9JbnRlcm1lZGlhdGUgQ0EwggIiMA0GCSqGSIb3DQEBAQUA
+A4ICDwAwggIKAoICAQDZhc69vEq9XyG+vcOW4rPx9aYJgn7NFXaE88xrKajFyu2v
+kD5Mz7geQV/RQKp1RMvj/1JCW5Npw8QwoPXNGQ8M+d+ajGgSkUZNVBQRXiR/hpfK
+ohox9gJRsOVCAvhyE15iZHkEVFFcchiWbsTM9QllLsiiI0qZ/QpkUmJmDyXUV4Hq
+hoAGXsojp0xaEQhrl+Hayiwao7qZkbKFCbNIDFU++ZDNT41qqDwcYmbkBJgYoGdS
+IAk4Mjf7+rLJPXWNYtYB3g1cuN4pH8FkFT9zocNr0xrsx2itY4gvXgIe/vzts8aw
+sHx1h2HcZK7iJEHs25QGrsZhiADeb0i5pN1kaPqpY0qgQUCIaqZAtMMeHXQ0k3PB
+xTz8vk0388oFLaJFuI0P9Q6CRf5+4rc9O201aUIuue3Y4IS6zAcd8yL5d5vxvCiN
+Dbl7YenBS4C9xSEEiVZwN7AtIdKFq5pGrlptmhVbGFW1CLQNsVWpetCY12Sh9FOq
+2IBaAup+XgRgO4kHs3t7euVaS2viH3MplPsOUim8NZPZBdZkTtS3W9SynBDriy1d
+KtrYgz0zrgEAa82mq4INaR+7Utct97zhKa1zM47KlHgkauiTPkUcqVhoNWxdM5tI
+nSWym/9pPHUmzt8v/F8COA/8Xv+db2QX14S3fStI+8mp084RWuevtbh5WcoypQID
+AQABo2YwZDAdBgNVHQ4EFgQUPUqJPYDZeUXbBlR0xXA/F+DYYagwHwYDVR0jBBgw
+FoAUjWflPh3KYZ5o3BP3Po4v2ZBshVkwEgYDVR0TAQH/BAgwBgEB/wIBADAOBgNV
+HQ8BAf8EBAMCAYYwDQYJKoZIhvcNAQELBQADggIBAH665ntrBhyf+MPFnkY+1VUr
+VrfRlP4SccoujdLB/sUKqydYsED+mDJ+V8uFOgoi7PHqwvsRS+yR/bB0bNNYSfKY
+slCMQA3sJ7SNDPBsec955ehYPNdquhem+oICzgFaQwL9ULDG87fKZjmaKO25dIYX
+ttLqn+0b0GjpfQRuZ3NpAnCTWevodc5A3aYQm6vYeCyeIHGPpmtLE6oPRFib7wtD
+n4DFVM57F34ClnnF4m8jq9HoTcM1Y3qOFyslK/4FRyx3HXbEVsm5L289l0AS866U
+WEVM9DCqpFNLTwRk0mn4mspNcRxTDUTiHAxMhKxHGgbPcFzCJXqZzkW56bDcAGA5
+sQr+MOfa1P/K7pVcFtOAhsBi5ff1G4t1G1+amqXEDalL+qKRGFugGVf+poyb2C3g
+sfxkPBp9jPPMgMzXULQglwU4IUm8GtBb9Lh6AFPvt78XAWvNvHLP1Rf8JNZ9prx5
+N9RzIKSWKm6CVEjSDvQ42j4OpW0eecHAoluZFMrykVl+KmapWUwQF6v0xz1RJdQ+
+q3vGJ6shhiFd6y0ygxPwMaEjhhpbRy4tK9iDBj5yRpo+HE5X+FQSN6NHOYWMeDoZ
+uzd86/huEH5qIAL4unM9YFTzJ4CFOC8EJMDW6ul0uKjOwGPP3R1Vss6sC7kR0gXI
+rLWYdt40z0pjcR3FDVzh
+-----END CERTIFICATE-----
+`
+
 func validInstallConfig() *types.InstallConfig {
 	return &types.InstallConfig{
 		TypeMeta: metav1.TypeMeta{
@@ -71,6 +110,15 @@ func validAWSPlatform() *aws.Platform {
 	}
 }
 
+func validAzurePlatform() *azure.Platform {
+	return &azure.Platform{
+		Region:                      "eastus",
+		BaseDomainResourceGroupName: "test-basedomain-rg",
+		CloudName:                   azure.PublicCloud,
+		OutboundType:                azure.LoadbalancerOutboundType,
+	}
+}
+
 func validAzureStackPlatform() *azure.Platform {
 	return &azure.Platform{
 		Region:                      "test-region",
@@ -299,6 +347,51 @@ func TestValidateInstallConfig(t *testing.T) {
 			}(),
 			expectedError: `^sshKey: Invalid value: "bad-ssh-key": ssh: no key found$`,
 		},
+		{
+			name: "multiple ssh keys",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.SSHKey = "# admins\necdsa-sha2-nistp256 AAAAE2VjZHNhLXNoYTItbmlzdHAyNTYAAAAIbmlzdHAyNTYAAABBBBX5s/TK+aj4+Ok3ZvnBIpQKXUMzRDrsUP/Fs2uMRbsYeeRTQacHmnArYY7rPaE0qYN3w2k+Ud4sJ7tTmc5i8QA=\n\nssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIC/kLwweZ4Ab1V85XGOgpro/g1jmxAe7somqfvPCCkUF user@example.com\n"
+				return c
+			}(),
+		},
+		{
+			name: "invalid ssh key among multiple",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.SSHKey = "ecdsa-sha2-nistp256 AAAAE2VjZHNhLXNoYTItbmlzdHAyNTYAAAAIbmlzdHAyNTYAAABBBBX5s/TK+aj4+Ok3ZvnBIpQKXUMzRDrsUP/Fs2uMRbsYeeRTQacHmnArYY7rPaE0qYN3w2k+Ud4sJ7tTmc5i8QA=\nbad-ssh-key"
+				return c
+			}(),
+			expectedError: `^sshKey: Invalid value: "bad-ssh-key": ssh: no key found$`,
+		},
+		{
+			name: "ssh key with only comments",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.SSHKey = "# no keys yet\n"
+				return c
+			}(),
+			expectedError: `^sshKey: Invalid value: "# no keys yet\\n": no public keys found$`,
+		},
+		{
+			name: "ecdsa ssh key with FIPS",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.FIPS = true
+				c.SSHKey = "ecdsa-sha2-nistp256 AAAAE2VjZHNhLXNoYTItbmlzdHAyNTYAAAAIbmlzdHAyNTYAAABBBBX5s/TK+aj4+Ok3ZvnBIpQKXUMzRDrsUP/Fs2uMRbsYeeRTQacHmnArYY7rPaE0qYN3w2k+Ud4sJ7tTmc5i8QA="
+				return c
+			}(),
+		},
+		{
+			name: "ed25519 ssh key with FIPS",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.FIPS = true
+				c.SSHKey = "ecdsa-sha2-nistp256 AAAAE2VjZHNhLXNoYTItbmlzdHAyNTYAAAAIbmlzdHAyNTYAAABBBBX5s/TK+aj4+Ok3ZvnBIpQKXUMzRDrsUP/Fs2uMRbsYeeRTQacHmnArYY7rPaE0qYN3w2k+Ud4sJ7tTmc5i8QA=\nssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIC/kLwweZ4Ab1V85XGOgpro/g1jmxAe7somqfvPCCkUF"
+				return c
+			}(),
+			expectedError: `^sshKey: Invalid value: "ssh-ed25519 [^"]+": SSH key type ssh-ed25519 unavailable when FIPS is enabled\. Please use rsa or ecdsa\.$`,
+		},
 		{
 			name: "invalid base domain",
 			installConfig: func() *types.InstallConfig {
@@ -506,6 +599,308 @@ func TestValidateInstallConfig(t *testing.T) {
 			}(),
 			expectedError: `^controlPlane.replicas: Required value: replicas is required$`,
 		},
+		{
+			name: "control plane with 5 replicas",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.ControlPlane.Replicas = pointer.Int64Ptr(5)
+				return c
+			}(),
+		},
+		{
+			name: "control plane with 6 replicas",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.ControlPlane.Replicas = pointer.Int64Ptr(6)
+				return c
+			}(),
+			expectedError: `^controlPlane.replicas: Invalid value: 6: number of control plane replicas must not exceed 5$`,
+		},
+		{
+			name: "control plane with 2 replicas without arbiter",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.ControlPlane.Replicas = pointer.Int64Ptr(2)
+				return c
+			}(),
+			expectedError: `^controlPlane.replicas: Invalid value: 2: a control plane of 2 replicas requires an arbiter$`,
+		},
+		{
+			name: "inactive control plane machine set",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.ControlPlane.ControlPlaneMachineSetState = types.ControlPlaneMachineSetStateInactive
+				return c
+			}(),
+		},
+		{
+			name: "invalid control plane machine set state",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.ControlPlane.ControlPlaneMachineSetState = "Paused"
+				return c
+			}(),
+			expectedError: `^controlPlane.controlPlaneMachineSetState: Unsupported value: "Paused": supported values: "Active", "Inactive"$`,
+		},
+		{
+			name: "control plane machine set state on unsupported platform",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform = types.Platform{None: &none.Platform{}}
+				c.ControlPlane.ControlPlaneMachineSetState = types.ControlPlaneMachineSetStateActive
+				return c
+			}(),
+			expectedError: `^controlPlane.controlPlaneMachineSetState: Invalid value: "Active": a ControlPlaneMachineSet is not generated on none, supported platforms are aws, azure, gcp$`,
+		},
+		{
+			name: "control plane machine set state on compute pool",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Compute[0].ControlPlaneMachineSetState = types.ControlPlaneMachineSetStateActive
+				return c
+			}(),
+			expectedError: `^compute\[0\].controlPlaneMachineSetState: Forbidden: a ControlPlaneMachineSet is only generated for the control plane$`,
+		},
+		{
+			name: "autoscaled compute pool",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Compute[0].Autoscaling = &types.MachinePoolAutoscaling{MinReplicas: 1, MaxReplicas: 6}
+				return c
+			}(),
+		},
+		{
+			name: "inverted autoscaling range",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Compute[0].Autoscaling = &types.MachinePoolAutoscaling{MinReplicas: 3, MaxReplicas: 2}
+				return c
+			}(),
+			expectedError: `^\[compute\[0\].autoscaling.maxReplicas: Invalid value: 2: maxReplicas must not be less than minReplicas, compute\[0\].replicas: Invalid value: 1: replicas must be within the autoscaling range of 3 to 2\]$`,
+		},
+		{
+			name: "replicas above the autoscaling range",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Compute[0].Replicas = pointer.Int64Ptr(4)
+				c.Compute[0].Autoscaling = &types.MachinePoolAutoscaling{MinReplicas: 0, MaxReplicas: 3}
+				return c
+			}(),
+			expectedError: `^compute\[0\].replicas: Invalid value: 4: replicas must be within the autoscaling range of 0 to 3$`,
+		},
+		{
+			name: "infra pool",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Compute = append(c.Compute, *validMachinePool("infra"))
+				return c
+			}(),
+		},
+		{
+			name: "infra pool without worker pool",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Compute = []types.MachinePool{*validMachinePool("infra")}
+				return c
+			}(),
+			expectedError: `^compute\[0\].name: Invalid value: "infra": an infra pool requires a worker pool$`,
+		},
+		{
+			name: "infra pool with machine config",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				infra := validMachinePool("infra")
+				infra.NTP = &types.NTP{Servers: []string{"ntp.example.com"}}
+				c.Compute = append(c.Compute, *infra)
+				return c
+			}(),
+			expectedError: `^compute\[1\].ntp: Forbidden: the infra machines use the ntp of the worker pool$`,
+		},
+		{
+			name: "invalid compute pool name",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Compute[0].Name = "storage"
+				return c
+			}(),
+			expectedError: `^compute\[0\].name: Unsupported value: "storage": supported values: "worker", "infra", "windows"$`,
+		},
+		{
+			name: "compute pool with accelerators",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Compute[0].Accelerators = &types.Accelerators{Vendor: types.AcceleratorVendorNVIDIA, NodeFeatureDiscovery: true}
+				return c
+			}(),
+		},
+		{
+			name: "invalid accelerator vendor",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Compute[0].Accelerators = &types.Accelerators{Vendor: "Intel"}
+				return c
+			}(),
+			expectedError: `^compute\[0\].accelerators.vendor: Unsupported value: "Intel": supported values: "NVIDIA", "AMD"$`,
+		},
+		{
+			name: "accelerators on unsupported platform",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform = types.Platform{None: &none.Platform{}}
+				c.Compute[0].Accelerators = &types.Accelerators{Vendor: types.AcceleratorVendorNVIDIA}
+				return c
+			}(),
+			expectedError: `^compute\[0\].accelerators: Forbidden: accelerators are not supported on none, supported platforms are aws, azure$`,
+		},
+		{
+			name: "compute pool with container storage",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Compute[0].ContainerStorage = &types.ContainerStorage{MountPath: "/var", SizeGB: 100, Type: "gp3"}
+				return c
+			}(),
+		},
+		{
+			name: "invalid container storage",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Compute[0].ContainerStorage = &types.ContainerStorage{MountPath: "/var/lib", SizeGB: 10, Type: "io1", Device: "/dev/sdb"}
+				return c
+			}(),
+			expectedError: `^\[compute\[0\].containerStorage.mountPath: Unsupported value: "/var/lib": supported values: "/var/lib/containers", "/var", compute\[0\].containerStorage.type: Unsupported value: "io1": supported values: "gp2", "gp3", compute\[0\].containerStorage.sizeGB: Invalid value: 10: must be at least 20 to hold the container images, compute\[0\].containerStorage.device: Forbidden: the disk is attached by the installer on aws\]$`,
+		},
+		{
+			name: "container storage device on none",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform = types.Platform{None: &none.Platform{}}
+				c.Compute[0].ContainerStorage = &types.ContainerStorage{MountPath: "/var/lib/containers", Device: "sdb"}
+				return c
+			}(),
+			expectedError: `^compute\[0\].containerStorage.device: Invalid value: "sdb": must be the path of a device in /dev$`,
+		},
+		{
+			name: "control plane with container storage",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.ControlPlane.ContainerStorage = &types.ContainerStorage{MountPath: "/var/lib/containers", SizeGB: 100, Type: "gp3"}
+				return c
+			}(),
+			expectedError: `^controlPlane.containerStorage: Forbidden: a dedicated container storage disk is only supported in compute pools$`,
+		},
+		{
+			name: "control plane with accelerators",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.ControlPlane.Accelerators = &types.Accelerators{Vendor: types.AcceleratorVendorNVIDIA}
+				return c
+			}(),
+			expectedError: `^controlPlane.accelerators: Forbidden: accelerators are only supported in compute pools$`,
+		},
+		{
+			name: "autoscaled control plane",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.ControlPlane.Autoscaling = &types.MachinePoolAutoscaling{MinReplicas: 1, MaxReplicas: 3}
+				return c
+			}(),
+			expectedError: `^controlPlane.autoscaling: Forbidden: only compute pools can be autoscaled$`,
+		},
+		{
+			name: "bootstrap in place on cloud platform",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.BootstrapInPlace = &types.BootstrapInPlace{InstallationDisk: "/dev/sda"}
+				return c
+			}(),
+			expectedError: `^bootstrapInPlace: Invalid value: "aws": bootstrap in place is only supported on platforms baremetal, none, vsphere, single-node clusters on aws are installed with a bootstrap machine$`,
+		},
+		{
+			name: "bootstrap in place with 3 control plane replicas",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform = types.Platform{None: &none.Platform{}}
+				c.ControlPlane.Replicas = pointer.Int64Ptr(3)
+				c.BootstrapInPlace = &types.BootstrapInPlace{InstallationDisk: "/dev/sda"}
+				return c
+			}(),
+			expectedError: `^controlPlane.replicas: Invalid value: 3: bootstrap in place requires a single ControlPlane replica$`,
+		},
+		{
+			name: "bootstrap in place without installation disk",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform = types.Platform{None: &none.Platform{}}
+				c.BootstrapInPlace = &types.BootstrapInPlace{}
+				return c
+			}(),
+			expectedError: `^bootstrapInPlace.installationDisk: Required value: installationDisk must be set the target disk drive for the installation$`,
+		},
+		{
+			name: "bootstrap in place with invalid installation disk",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform = types.Platform{None: &none.Platform{}}
+				c.BootstrapInPlace = &types.BootstrapInPlace{InstallationDisk: "/dev/../sda"}
+				return c
+			}(),
+			expectedError: `^bootstrapInPlace.installationDisk: Invalid value: "/dev/../sda": installationDisk must be the path of a block device under /dev$`,
+		},
+		{
+			name: "valid bootstrap in place on vsphere",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform = types.Platform{VSphere: validVSpherePlatform()}
+				c.BootstrapInPlace = &types.BootstrapInPlace{InstallationDisk: "/dev/disk/by-path/pci-0000:03:00.0-scsi-0:0:0:0"}
+				return c
+			}(),
+		},
+		{
+			name: "valid arbiter",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform = types.Platform{None: &none.Platform{}}
+				c.FeatureSet = configv1.TechPreviewNoUpgrade
+				c.ControlPlane.Replicas = pointer.Int64Ptr(2)
+				c.Arbiter = validMachinePool("arbiter")
+				return c
+			}(),
+		},
+		{
+			name: "arbiter without tech preview",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform = types.Platform{None: &none.Platform{}}
+				c.ControlPlane.Replicas = pointer.Int64Ptr(2)
+				c.Arbiter = validMachinePool("arbiter")
+				return c
+			}(),
+			expectedError: `^arbiter: Forbidden: the TechPreviewNoUpgrade feature set must be enabled to use this field$`,
+		},
+		{
+			name: "arbiter on unsupported platform",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.FeatureSet = configv1.TechPreviewNoUpgrade
+				c.ControlPlane.Replicas = pointer.Int64Ptr(2)
+				c.Arbiter = validMachinePool("arbiter")
+				return c
+			}(),
+			expectedError: `^arbiter: Invalid value: "aws": an arbiter is not supported on aws, supported platforms are none$`,
+		},
+		{
+			name: "arbiter with 3 control plane replicas",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform = types.Platform{None: &none.Platform{}}
+				c.FeatureSet = configv1.TechPreviewNoUpgrade
+				c.ControlPlane.Replicas = pointer.Int64Ptr(3)
+				c.Arbiter = validMachinePool("arbiter")
+				c.Arbiter.Replicas = pointer.Int64Ptr(2)
+				return c
+			}(),
+			expectedError: `^\[arbiter.replicas: Invalid value: 2: number of arbiter replicas must be 1, arbiter: Invalid value: 3: an arbiter requires a control plane of 2 replicas\]$`,
+		},
 		{
 			name: "missing compute",
 			installConfig: func() *types.InstallConfig {
@@ -1140,34 +1535,70 @@ func TestValidateInstallConfig(t *testing.T) {
 			}(),
 		},
 		{
-			name: "invalid publishing strategy",
+			name: "valid release image digest source",
 			installConfig: func() *types.InstallConfig {
 				c := validInstallConfig()
-				c.Publish = types.PublishingStrategy("ExternalInternalDoNotCare")
+				c.ImageDigestSources = []types.ImageDigestSource{{
+					Source:  "quay.io/ocp/release-x.y",
+					Mirrors: []string{"mirror.example.com/ocp/release-x.y"},
+				}}
 				return c
 			}(),
-			expectedError: `^publish: Unsupported value: \"ExternalInternalDoNotCare\": supported values: \"External\", \"Internal\"`,
 		},
-
 		{
-			name: "valid dual-stack configuration",
+			name: "invalid release image digest source",
 			installConfig: func() *types.InstallConfig {
 				c := validInstallConfig()
-				c.Platform = types.Platform{None: &none.Platform{}}
-				c.Networking = validDualStackNetworkingConfig()
+				c.ImageDigestSources = []types.ImageDigestSource{{
+					Source: "quay.io/ocp/release-x.y:latest",
+				}}
 				return c
 			}(),
+			expectedError: `^imageDigestSources\[0\]\.source: Invalid value: "quay\.io/ocp/release-x\.y:latest": must be repository--not reference$`,
 		},
 		{
-			name: "valid single-stack IPv6 configuration",
+			name: "both image content and digest sources",
 			installConfig: func() *types.InstallConfig {
 				c := validInstallConfig()
-				c.Platform = types.Platform{None: &none.Platform{}}
-				c.Networking = validIPv6NetworkingConfig()
-				return c
-			}(),
-		},
-		{
+				c.ImageContentSources = []types.ImageContentSource{{
+					Source: "quay.io/ocp/release-x.y",
+				}}
+				c.ImageDigestSources = []types.ImageDigestSource{{
+					Source: "quay.io/ocp/release-x.y",
+				}}
+				return c
+			}(),
+			expectedError: `^imageContentSources: Invalid value: "ImageContentSources": cannot set imageContentSources and imageDigestSources at the same time$`,
+		},
+		{
+			name: "invalid publishing strategy",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Publish = types.PublishingStrategy("ExternalInternalDoNotCare")
+				return c
+			}(),
+			expectedError: `^publish: Unsupported value: \"ExternalInternalDoNotCare\": supported values: \"External\", \"Internal\"`,
+		},
+
+		{
+			name: "valid dual-stack configuration",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform = types.Platform{None: &none.Platform{}}
+				c.Networking = validDualStackNetworkingConfig()
+				return c
+			}(),
+		},
+		{
+			name: "valid single-stack IPv6 configuration",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform = types.Platform{None: &none.Platform{}}
+				c.Networking = validIPv6NetworkingConfig()
+				return c
+			}(),
+		},
+		{
 			name: "invalid dual-stack configuration, bad platform",
 			installConfig: func() *types.InstallConfig {
 				c := validInstallConfig()
@@ -1175,7 +1606,7 @@ func TestValidateInstallConfig(t *testing.T) {
 				c.Networking = validDualStackNetworkingConfig()
 				return c
 			}(),
-			expectedError: `Invalid value: "DualStack": dual-stack IPv4/IPv6 is not supported for this platform, specify only one type of address`,
+			expectedError: `Invalid value: "DualStack": dual-stack IPv4/IPv6 is not supported on the "gcp" platform, specify only one type of address`,
 		},
 		{
 			name: "invalid single-stack IPv6 configuration, bad platform",
@@ -1185,7 +1616,7 @@ func TestValidateInstallConfig(t *testing.T) {
 				c.Networking = validIPv6NetworkingConfig()
 				return c
 			}(),
-			expectedError: `Invalid value: "IPv6": single-stack IPv6 is not supported for this platform`,
+			expectedError: `Invalid value: "IPv6": single-stack IPv6 is not supported on the "gcp" platform`,
 		},
 		{
 			name: "invalid dual-stack configuration, bad plugin",
@@ -1234,6 +1665,207 @@ func TestValidateInstallConfig(t *testing.T) {
 			}(),
 			expectedError: `Invalid value: "ffd1::/112, 172.30.0.0/16": IPv4 addresses must be listed before IPv6 addresses`,
 		},
+		{
+			name: "invalid dual-stack configuration, IPv6-primary service network with machineNetwork omitted",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform = types.Platform{None: &none.Platform{}}
+				c.Networking = validDualStackNetworkingConfig()
+				c.Networking.MachineNetwork = nil
+				c.Networking.ServiceNetwork[0], c.Networking.ServiceNetwork[1] = c.Networking.ServiceNetwork[1], c.Networking.ServiceNetwork[0]
+				return c
+			}(),
+			expectedError: `networking.serviceNetwork: Invalid value: "ffd1::/112, 172.30.0.0/16": IPv4 addresses must be listed before IPv6 addresses`,
+		},
+		{
+			name: "invalid dual-stack configuration, IPv6-primary in every list",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform = types.Platform{VSphere: validVSpherePlatform()}
+				c.Networking = validDualStackNetworkingConfig()
+				c.Networking.MachineNetwork[0], c.Networking.MachineNetwork[1] = c.Networking.MachineNetwork[1], c.Networking.MachineNetwork[0]
+				c.Networking.ServiceNetwork[0], c.Networking.ServiceNetwork[1] = c.Networking.ServiceNetwork[1], c.Networking.ServiceNetwork[0]
+				c.Networking.ClusterNetwork[0], c.Networking.ClusterNetwork[1] = c.Networking.ClusterNetwork[1], c.Networking.ClusterNetwork[0]
+				return c
+			}(),
+			expectedError: `networking.machineNetwork: Invalid value: "ffd0::/48, 10.0.0.0/16": IPv4 addresses must be listed before IPv6 addresses`,
+		},
+		{
+			name: "invalid dual-stack configuration, Azure without experimental support",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform = types.Platform{Azure: validAzurePlatform()}
+				c.Networking = validDualStackNetworkingConfig()
+				return c
+			}(),
+			expectedError: `Invalid value: "DualStack": dual-stack IPv4/IPv6 is not supported on the "azure" platform, specify only one type of address`,
+		},
+		{
+			name: "valid OVNKubernetes tunables",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Networking.OVNKubernetesConfig = &types.OVNKubernetesConfig{
+					V4InternalSubnet: ipnet.MustParseCIDR("100.99.0.0/16"),
+					GatewayMode:      types.OVNGatewayModeLocal,
+					IPsec:            true,
+				}
+				return c
+			}(),
+		},
+		{
+			name: "invalid OVNKubernetes tunables, wrong network type",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Networking.NetworkType = "OpenShiftSDN"
+				c.Networking.OVNKubernetesConfig = &types.OVNKubernetesConfig{IPsec: true}
+				return c
+			}(),
+			expectedError: `networking.ovnKubernetesConfig: Forbidden: may only be set when networkType is OVNKubernetes`,
+		},
+		{
+			name: "invalid OVNKubernetes gateway mode",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Networking.OVNKubernetesConfig = &types.OVNKubernetesConfig{GatewayMode: "Remote"}
+				return c
+			}(),
+			expectedError: `networking.ovnKubernetesConfig.gatewayMode: Unsupported value: "Remote": supported values: "Local", "Shared"`,
+		},
+		{
+			name: "invalid OVNKubernetes internal subnet overlapping machine network",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Networking.OVNKubernetesConfig = &types.OVNKubernetesConfig{
+					V4InternalSubnet: ipnet.MustParseCIDR("10.0.128.0/17"),
+				}
+				return c
+			}(),
+			expectedError: `networking.ovnKubernetesConfig.v4InternalSubnet: Invalid value: "10.0.128.0/17": must not overlap with machine network 0`,
+		},
+		{
+			name: "invalid OVNKubernetes internal subnet family",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Networking.OVNKubernetesConfig = &types.OVNKubernetesConfig{
+					V6InternalSubnet: ipnet.MustParseCIDR("100.99.0.0/16"),
+				}
+				return c
+			}(),
+			expectedError: `networking.ovnKubernetesConfig.v6InternalSubnet: Invalid value: "100.99.0.0/16": must be an IPv6 subnet`,
+		},
+		{
+			name: "invalid OVNKubernetes internal subnet too small",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Networking.OVNKubernetesConfig = &types.OVNKubernetesConfig{
+					V4InternalSubnet: ipnet.MustParseCIDR("100.99.0.0/28"),
+				}
+				return c
+			}(),
+			expectedError: `networking.ovnKubernetesConfig.v4InternalSubnet: Invalid value: "100.99.0.0/28": must be large enough to provide an address to every node`,
+		},
+		{
+			name: "invalid OVNKubernetes MTU too small",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Networking.OVNKubernetesConfig = &types.OVNKubernetesConfig{MTU: 500}
+				return c
+			}(),
+			expectedError: `networking.ovnKubernetesConfig.mtu: Invalid value: 500: must be at least 576`,
+		},
+		{
+			name: "invalid OVNKubernetes MTU larger than platform allows",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Networking.OVNKubernetesConfig = &types.OVNKubernetesConfig{MTU: 9001}
+				return c
+			}(),
+			expectedError: `networking.ovnKubernetesConfig.mtu: Invalid value: 9001: must not be larger than 8901, the aws machine MTU of 9001 less 100 bytes of overlay overhead`,
+		},
+		{
+			name: "valid OVNKubernetes jumbo MTU on AWS",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Networking.OVNKubernetesConfig = &types.OVNKubernetesConfig{MTU: 8901}
+				return c
+			}(),
+		},
+		{
+			name: "valid windows pool",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Networking.OVNKubernetesConfig = &types.OVNKubernetesConfig{
+					HybridOverlay: &types.HybridOverlayConfig{
+						HybridClusterNetwork: []types.ClusterNetworkEntry{{CIDR: *ipnet.MustParseCIDR("10.132.0.0/14"), HostPrefix: 23}},
+					},
+				}
+				windows := validMachinePool("windows")
+				windows.OSType = types.OSTypeWindows
+				windows.Platform.AWS = &aws.MachinePool{AMIID: "ami-0123456789abcdef0"}
+				c.Compute = append(c.Compute, *windows)
+				return c
+			}(),
+		},
+		{
+			name: "windows pool without image and hybrid overlay",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				windows := validMachinePool("windows")
+				windows.OSType = types.OSTypeWindows
+				c.Compute = append(c.Compute, *windows)
+				return c
+			}(),
+			expectedError: `^\[compute\[1\].platform.aws.amiID: Required value: the AMI of the Windows image is required, networking.ovnKubernetesConfig.hybridOverlay: Required value: the hybrid overlay is required by Windows machines\]$`,
+		},
+		{
+			name: "windows pool with OpenShiftSDN",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Networking.NetworkType = "OpenShiftSDN"
+				windows := validMachinePool("windows")
+				windows.OSType = types.OSTypeWindows
+				windows.Platform.AWS = &aws.MachinePool{AMIID: "ami-0123456789abcdef0"}
+				c.Compute = append(c.Compute, *windows)
+				return c
+			}(),
+			expectedError: `networking.networkType: Invalid value: "OpenShiftSDN": Windows machines require the hybrid overlay of OVNKubernetes`,
+		},
+		{
+			name: "windows os type on worker pool",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Compute[0].OSType = types.OSTypeWindows
+				c.Networking.OVNKubernetesConfig = &types.OVNKubernetesConfig{
+					HybridOverlay: &types.HybridOverlayConfig{
+						HybridClusterNetwork: []types.ClusterNetworkEntry{{CIDR: *ipnet.MustParseCIDR("10.132.0.0/14"), HostPrefix: 23}},
+					},
+				}
+				return c
+			}(),
+			expectedError: `^compute\[0\].osType: Invalid value: "Windows": Windows machines are only supported in the windows pool$`,
+		},
+		{
+			name: "hybrid cluster network overlapping the machine network",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Networking.OVNKubernetesConfig = &types.OVNKubernetesConfig{
+					HybridOverlay: &types.HybridOverlayConfig{
+						HybridClusterNetwork: []types.ClusterNetworkEntry{{CIDR: *ipnet.MustParseCIDR("10.0.0.0/14"), HostPrefix: 23}},
+					},
+				}
+				return c
+			}(),
+			expectedError: `^networking.ovnKubernetesConfig.hybridOverlay.hybridClusterNetwork\[0\].cidr: Invalid value: "10.0.0.0/14": must not overlap with machine network 0$`,
+		},
+		{
+			name: "cluster network too small for requested machines",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Compute[0].Replicas = pointer.Int64Ptr(20)
+				return c
+			}(),
+			expectedError: `networking.clusterNetwork: Invalid value: "192.168.1.0/24 \(hostPrefix 28\)": the IPv4 cluster networks provide only 16 host subnets, which is fewer than the 21 machines requested`,
+		},
 		{
 			name: "valid dual-stack configuration with mixed-order clusterNetworks",
 			installConfig: func() *types.InstallConfig {
@@ -1475,6 +2107,42 @@ func TestValidateInstallConfig(t *testing.T) {
 			}(),
 			expectedError: `capabilities.additionalEnabledCapabilities\[0\]: Unsupported value: "not-valid": supported values: .*`,
 		},
+		{
+			name: "valid capability preset",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Capabilities = &types.Capabilities{BaselineCapabilitySet: types.CapabilitySetVirtualization}
+				return c
+			}(),
+		},
+		{
+			name: "capability with dependency enabled",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Capabilities = &types.Capabilities{BaselineCapabilitySet: "None",
+					AdditionalEnabledCapabilities: []configv1.ClusterVersionCapability{"CSISnapshot", "Storage"}}
+				return c
+			}(),
+		},
+		{
+			name: "capability with missing dependency",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Capabilities = &types.Capabilities{BaselineCapabilitySet: "None",
+					AdditionalEnabledCapabilities: []configv1.ClusterVersionCapability{"NodeTuning", "CSISnapshot"}}
+				return c
+			}(),
+			expectedError: `capabilities.additionalEnabledCapabilities\[1\]: Invalid value: "CSISnapshot": the CSISnapshot capability requires the Storage capability to be enabled`,
+		},
+		{
+			name: "capability dependency not optional in this release",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Capabilities = &types.Capabilities{BaselineCapabilitySet: "None",
+					AdditionalEnabledCapabilities: []configv1.ClusterVersionCapability{"Console", "marketplace"}}
+				return c
+			}(),
+		},
 		//VIP tests
 		{
 			name: "apivip_v4_not_in_machinenetwork_cidr",
@@ -1787,6 +2455,19 @@ func TestValidateInstallConfig(t *testing.T) {
 			}(),
 			expectedError: "platform.baremetal.apiVIPs: Invalid value: \"2001:db8::5\": VIP for API must not be one of the Ingress VIPs",
 		},
+		{
+			name: "ingressvip_in_provisioning_dhcp_range",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform = types.Platform{
+					BareMetal: validBareMetalPlatform(),
+				}
+				c.Platform.BareMetal.ProvisioningDHCPRange = "10.0.0.2,10.0.0.4"
+
+				return c
+			}(),
+			expectedError: "platform.baremetal.ingressVIPs: Invalid value: \"10.0.0.4\": IP expected to be outside of the provisioning DHCP range 10.0.0.2-10.0.0.4",
+		},
 		{
 			name: "empty_api_vip_fields",
 			installConfig: func() *types.InstallConfig {
@@ -2002,10 +2683,598 @@ func TestValidateInstallConfig(t *testing.T) {
 			}(),
 			expectedError: "platform.vsphere.apiVIPs: Required value: must specify VIP for API, when VIP for ingress is set",
 		},
-	}
-	for _, tc := range cases {
-		t.Run(tc.name, func(t *testing.T) {
-			err := ValidateInstallConfig(tc.installConfig).ToAggregate()
+		{
+			name: "user-managed load balancer outside of the machine networks",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform = types.Platform{
+					VSphere: validVSpherePlatform(),
+				}
+				c.Platform.VSphere.LoadBalancer = &loadbalancer.LoadBalancer{Type: loadbalancer.UserManaged}
+				c.Platform.VSphere.APIVIPs = []string{"192.168.1.10"}
+				c.Platform.VSphere.IngressVIPs = []string{"192.168.1.10"}
+
+				return c
+			}(),
+		},
+		{
+			name: "unsupported load balancer type",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform = types.Platform{
+					VSphere: validVSpherePlatform(),
+				}
+				c.Platform.VSphere.LoadBalancer = &loadbalancer.LoadBalancer{Type: "External"}
+
+				return c
+			}(),
+			expectedError: `platform.vsphere.loadBalancer.type: Unsupported value: "External": supported values: "OpenShiftManagedDefault", "UserManaged"`,
+		},
+		{
+			name: "valid node config",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.NodeConfig = &types.NodeConfig{
+					MaxPods:         16,
+					SystemReserved:  map[string]string{"cpu": "500m", "memory": "1Gi"},
+					CgroupMode:      configv1.CgroupModeV2,
+					CPUPartitioning: &types.CPUPartitioning{ReservedCPUs: "0-1"},
+				}
+				return c
+			}(),
+		},
+		{
+			name: "max pods exceeding host prefix",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.NodeConfig = &types.NodeConfig{MaxPods: 32}
+				return c
+			}(),
+			expectedError: `^nodeConfig.maxPods: Invalid value: 32: networking.clusterNetwork\[0\] leaves room for at most 16 pods per node$`,
+		},
+		{
+			name: "negative max pods",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.NodeConfig = &types.NodeConfig{MaxPods: -1}
+				return c
+			}(),
+			expectedError: `^nodeConfig.maxPods: Invalid value: -1: must be positive$`,
+		},
+		{
+			name: "unsupported system reserved resource",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.NodeConfig = &types.NodeConfig{SystemReserved: map[string]string{"pods": "10"}}
+				return c
+			}(),
+			expectedError: `^nodeConfig.systemReserved: Unsupported value: "pods": supported values: "cpu", "ephemeral-storage", "memory"$`,
+		},
+		{
+			name: "invalid system reserved quantity",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.NodeConfig = &types.NodeConfig{SystemReserved: map[string]string{"memory": "lots"}}
+				return c
+			}(),
+			expectedError: `^nodeConfig.systemReserved\[memory\]: Invalid value: "lots": quantities must match the regular expression`,
+		},
+		{
+			name: "unsupported cgroup mode",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.NodeConfig = &types.NodeConfig{CgroupMode: "v3"}
+				return c
+			}(),
+			expectedError: `^nodeConfig.cgroupMode: Unsupported value: "v3": supported values: "v1", "v2"$`,
+		},
+		{
+			name: "invalid reserved cpus",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.NodeConfig = &types.NodeConfig{CPUPartitioning: &types.CPUPartitioning{ReservedCPUs: "1-0"}}
+				return c
+			}(),
+			expectedError: `^nodeConfig.cpuPartitioning.reservedCPUs: Invalid value: "1-0": invalid CPU range "1-0"$`,
+		},
+		{
+			name: "valid wait timeouts",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.WaitTimeouts = &types.WaitTimeouts{
+					BootstrapComplete:      &metav1.Duration{Duration: 90 * time.Minute},
+					ClusterOperatorsStable: &metav1.Duration{Duration: 2 * time.Hour},
+				}
+				return c
+			}(),
+		},
+		{
+			name: "invalid wait timeout",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.WaitTimeouts = &types.WaitTimeouts{ConsoleRoute: &metav1.Duration{Duration: -time.Minute}}
+				return c
+			}(),
+			expectedError: `^waitTimeouts.consoleRoute: Invalid value: "-1m0s": must be positive$`,
+		},
+		{
+			name: "valid hooks",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Hooks = &types.Hooks{
+					PreProvision: []string{"/usr/local/bin/open-firewall-ticket"},
+					PostInstall:  []string{"https://cmdb.example.com/register"},
+				}
+				return c
+			}(),
+		},
+		{
+			name: "invalid hooks",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Hooks = &types.Hooks{
+					PostBootstrap: []string{"hooks/notify"},
+					PostInstall:   []string{"https:///register"},
+				}
+				return c
+			}(),
+			expectedError: `^\[hooks.postBootstrap\[0\]: Invalid value: "hooks/notify": must be an absolute path or an http\(s\) URL, hooks.postInstall\[0\]: Invalid value: "https:///register": must have a host\]$`,
+		},
+		{
+			name: "valid ignition snippets",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.IgnitionSnippets = []types.IgnitionSnippet{{
+					Name:   "chrony",
+					Roles:  []string{"master", "worker"},
+					Butane: "variant: fcos\nversion: 1.3.0\nstorage:\n  files:\n  - path: /etc/chrony.conf\n    contents:\n      inline: server time.example.com\n",
+				}}
+				return c
+			}(),
+		},
+		{
+			name: "invalid ignition snippets",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.IgnitionSnippets = []types.IgnitionSnippet{{
+					Name:   "debug",
+					Roles:  []string{"bootstrap"},
+					Butane: "variant: fcos\nversion: 1.3.0\n",
+				}, {
+					Name:   "debug",
+					Roles:  []string{"infra"},
+					Butane: "variant: flatcar\n",
+				}}
+				return c
+			}(),
+			expectedError: `^\[ignitionSnippets\[1\]\.name: Duplicate value: "debug", ignitionSnippets\[1\]\.roles\[0\]: Unsupported value: "infra": supported values: "bootstrap", "master", "worker", ignitionSnippets\[1\]\.butane: Invalid value: "variant: flatcar\\n": unsupported variant "flatcar", must be fcos or openshift\]$`,
+		},
+		{
+			name: "valid additional trusted CA",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.AdditionalTrustedCA = map[string]string{
+					"registry.example.com":    validRegistryCA,
+					"mirror.example.com:5000": validRegistryCA,
+					"10.0.0.1:8443":           validRegistryCA,
+				}
+				return c
+			}(),
+		},
+		{
+			name: "invalid additional trusted CA",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.AdditionalTrustedCA = map[string]string{
+					"https://registry.example.com": validRegistryCA,
+					"mirror.example.com:99999":     validRegistryCA,
+					"registry.example.com":         "not a certificate",
+				}
+				return c
+			}(),
+			expectedError: `^\[additionalTrustedCA\[https://registry\.example\.com\]: Invalid value: "https://registry\.example\.com": must be a registry host with an optional port, additionalTrustedCA\[mirror\.example\.com:99999\]: Invalid value: "mirror\.example\.com:99999": the port must be between 1 and 65535, additionalTrustedCA\[registry\.example\.com\]: Invalid value: "not a certificate": invalid block\]$`,
+		},
+		{
+			name: "valid ntp",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.NTP = &types.NTP{Servers: []string{"10.0.0.1", "time.example.com"}, Pools: []string{"pool.example.com"}}
+				c.ControlPlane.NTP = &types.NTP{Servers: []string{"time.control.example.com"}}
+				return c
+			}(),
+		},
+		{
+			name: "invalid ntp",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.NTP = &types.NTP{Servers: []string{"time.example.com", "time server"}, Pools: []string{"time.example.com"}}
+				c.Compute[0].NTP = &types.NTP{}
+				return c
+			}(),
+			expectedError: `^\[compute\[0\]\.ntp: Required value: at least one of servers or pools must be specified, ntp\.servers\[1\]: Invalid value: "time server": must be a hostname or an IP address, ntp\.pools\[0\]: Duplicate value: "time\.example\.com"\]$`,
+		},
+		{
+			name: "kubeadmin disabled with break-glass users",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Kubeadmin = &types.Kubeadmin{
+					Disabled: true,
+					HTPasswd: "# break-glass\nalice:$2y$05$abcdefghijklmnopqrstuu\nbob:$2y$05$abcdefghijklmnopqrstuu\n",
+				}
+				return c
+			}(),
+		},
+		{
+			name: "invalid break-glass users",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Kubeadmin = &types.Kubeadmin{
+					HTPasswd: "alice:$2y$05$abcdefghijklmnopqrstuu\nalice:$2y$05$abcdefghijklmnopqrstuu\nbob:{SHA}abcdef\ncarol\n",
+				}
+				return c
+			}(),
+			expectedError: `^\[kubeadmin\.htpasswd: Duplicate value: "alice", kubeadmin\.htpasswd: Invalid value: "bob": the password hash must be bcrypt, e\.g\. from htpasswd -B, kubeadmin\.htpasswd: Invalid value: "line 4": must be of the form user:hash\]$`,
+		},
+		{
+			name: "valid identity providers",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.IdentityProviders = []types.IdentityProvider{{
+					Name: "oidc",
+					OpenID: &types.OpenIDIdentityProvider{
+						Issuer:       "https://oidc.example.com/realms/openshift",
+						ClientID:     "openshift",
+						ClientSecret: "secret",
+					},
+				}, {
+					Name:          "ldap",
+					MappingMethod: "lookup",
+					LDAP: &types.LDAPIdentityProvider{
+						URL:          "ldaps://ldap.example.com/ou=users,dc=example,dc=com?uid",
+						BindDN:       "cn=installer,dc=example,dc=com",
+						BindPassword: "secret",
+						CA:           validRegistryCA,
+					},
+				}, {
+					Name:     "local",
+					HTPasswd: &types.HTPasswdIdentityProvider{Contents: "alice:$2y$05$abcdefghijklmnopqrstuu\n"},
+				}}
+				return c
+			}(),
+		},
+		{
+			name: "invalid identity providers",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.IdentityProviders = []types.IdentityProvider{{
+					Name:   "Corp IdP",
+					OpenID: &types.OpenIDIdentityProvider{Issuer: "http://oidc.example.com"},
+				}, {
+					Name:          "ldap",
+					MappingMethod: "merge",
+					LDAP: &types.LDAPIdentityProvider{
+						URL:      "ldaps://ldap.example.com/ou=users,dc=example,dc=com?uid",
+						Insecure: true,
+						BindDN:   "cn=installer,dc=example,dc=com",
+					},
+				}, {
+					Name: "ldap",
+				}}
+				return c
+			}(),
+			expectedError: `^\[identityProviders\[0\]\.name: Invalid value: "Corp IdP": must be a lowercase RFC 1123 label, since it names the secrets of the identity provider, identityProviders\[0\]\.openID\.issuer: Invalid value: "http://oidc\.example\.com": must be an https URL without a query or fragment, identityProviders\[0\]\.openID\.clientID: Required value: the ID of the client registered with the provider is required, identityProviders\[0\]\.openID\.clientSecret: Required value: the secret of the client registered with the provider is required, identityProviders\[1\]\.mappingMethod: Unsupported value: "merge": supported values: "add", "claim", "generate", "lookup", identityProviders\[1\]\.ldap\.insecure: Invalid value: true: ldaps URLs always use TLS, identityProviders\[1\]\.ldap\.bindPassword: Required value: bindPassword is required with bindDN, identityProviders\[2\]\.name: Duplicate value: "ldap", identityProviders\[2\]: Required value: one of htpasswd, openID and ldap is required\]$`,
+		},
+		{
+			name: "valid etcd",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Etcd = &types.Etcd{
+					Disk:           &types.EtcdDisk{SizeGB: 32, Type: "gp3"},
+					LatencyProfile: types.EtcdLatencyProfileSlower,
+				}
+				return c
+			}(),
+		},
+		{
+			name: "invalid etcd",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Etcd = &types.Etcd{
+					Disk:           &types.EtcdDisk{SizeGB: 8, Type: "pd-ssd"},
+					LatencyProfile: "Fastest",
+				}
+				return c
+			}(),
+			expectedError: `^\[etcd\.disk\.type: Unsupported value: "pd-ssd": supported values: "gp2", "gp3", etcd\.disk\.sizeGB: Invalid value: 8: must be at least 10 to hold the etcd database, etcd\.latencyProfile: Unsupported value: "Fastest": supported values: "Standard", "Slower"\]$`,
+		},
+		{
+			name: "etcd disk on unsupported platform",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform = types.Platform{None: &none.Platform{}}
+				c.Etcd = &types.Etcd{Disk: &types.EtcdDisk{SizeGB: 32}}
+				return c
+			}(),
+			expectedError: `^etcd\.disk: Forbidden: a dedicated etcd disk is not supported on none$`,
+		},
+		{
+			name: "valid api and apps domains",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.APIDomain = "k8s.test-domain"
+				c.AppsDomain = "apps.test-domain"
+				return c
+			}(),
+		},
+		{
+			name: "api and apps domains outside the base domain",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.APIDomain = "k8s.example.com"
+				c.AppsDomain = "Apps_.test-domain"
+				return c
+			}(),
+			expectedError: `^\[apiDomain: Invalid value: "k8s\.example\.com": must be a subdomain of the base domain test-domain, in whose zone the installer creates the DNS records, appsDomain: Invalid value: "Apps_\.test-domain": .*\]$`,
+		},
+		{
+			name: "api and apps domains outside the base domain on platform none",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform = types.Platform{None: &none.Platform{}}
+				c.APIDomain = "k8s.example.com"
+				c.AppsDomain = "apps.example.org"
+				return c
+			}(),
+		},
+		{
+			name: "api domain in the apps domain",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.APIDomain = "api.apps.test-domain"
+				c.AppsDomain = "apps.test-domain"
+				return c
+			}(),
+			expectedError: `^apiDomain: Invalid value: "api\.apps\.test-domain": must not be in the apps domain apps\.test-domain, whose names resolve to the routers$`,
+		},
+		{
+			name: "api domain of the internal api",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.APIDomain = "api-int.test-cluster.test-domain"
+				return c
+			}(),
+			expectedError: `^apiDomain: Invalid value: "api-int\.test-cluster\.test-domain": api-int\.test-cluster\.test-domain is the domain name of the internal API$`,
+		},
+		{
+			name: "valid api server",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.APIServer = &types.APIServer{
+					AuditProfile: configv1.AllRequestBodiesAuditProfileType,
+					Encryption:   &types.APIServerEncryption{Type: types.EncryptionTypeAESCBC},
+				}
+				return c
+			}(),
+		},
+		{
+			name: "invalid api server",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.APIServer = &types.APIServer{
+					AuditProfile: "Verbose",
+					Encryption: &types.APIServerEncryption{
+						Type: types.EncryptionTypeAESGCM,
+						KMS:  &types.KMSConfig{AWS: &types.AWSKMSConfig{}},
+					},
+				}
+				return c
+			}(),
+			expectedError: `^\[apiServer\.auditProfile: Unsupported value: "Verbose": supported values: "AllRequestBodies", "Default", "None", "WriteRequestBodies", apiServer\.encryption\.kms: Forbidden: only allowed with the KMS encryption type\]$`,
+		},
+		{
+			name: "valid kms encryption",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.FeatureSet = configv1.TechPreviewNoUpgrade
+				c.APIServer = &types.APIServer{
+					Encryption: &types.APIServerEncryption{
+						Type: types.EncryptionTypeKMS,
+						KMS: &types.KMSConfig{AWS: &types.AWSKMSConfig{
+							KeyARN: "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+							Region: "us-east-1",
+						}},
+					},
+				}
+				return c
+			}(),
+		},
+		{
+			name: "invalid kms encryption",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.APIServer = &types.APIServer{
+					Encryption: &types.APIServerEncryption{
+						Type: types.EncryptionTypeKMS,
+						KMS: &types.KMSConfig{AWS: &types.AWSKMSConfig{
+							KeyARN: "arn:aws:kms:us-east-1:123456789012:alias/cluster",
+						}},
+					},
+				}
+				return c
+			}(),
+			expectedError: `^\[apiServer\.encryption\.type: Forbidden: the TechPreviewNoUpgrade feature set must be enabled to use this field, apiServer\.encryption\.kms\.aws\.keyARN: Invalid value: "arn:aws:kms:us-east-1:123456789012:alias/cluster": must be the ARN of a KMS key, apiServer\.encryption\.kms\.aws\.region: Required value: the region of the KMS key is required\]$`,
+		},
+		{
+			name: "valid gather bastion",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.GatherProxy = &types.GatherProxy{Bastion: "ec2-user@bastion.example.com:2222"}
+				return c
+			}(),
+		},
+		{
+			name: "invalid gather socks proxy",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.GatherProxy = &types.GatherProxy{SOCKS: "proxy.example.com"}
+				return c
+			}(),
+			expectedError: `^gatherProxy.socks: Invalid value: "proxy.example.com": must be host:port$`,
+		},
+		{
+			name: "gather bastion and socks proxy",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.GatherProxy = &types.GatherProxy{Bastion: "bastion.example.com", SOCKS: "proxy.example.com:1080"}
+				return c
+			}(),
+			expectedError: `^gatherProxy: Forbidden: only one of bastion and socks may be set$`,
+		},
+		{
+			name: "valid release image",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.ReleaseImage = &types.ReleaseImage{
+					PullSpec:         "quay.io/openshift-release-dev/ocp-release@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+					VerificationKeys: []string{"-----BEGIN PGP PUBLIC KEY BLOCK-----\n\nmQINBF...\n-----END PGP PUBLIC KEY BLOCK-----\n"},
+					SignatureStores:  []string{"https://mirror.example.com/signatures"},
+				}
+				return c
+			}(),
+		},
+		{
+			name: "release image without pull spec",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.ReleaseImage = &types.ReleaseImage{}
+				return c
+			}(),
+			expectedError: `^releaseImage.pullSpec: Required value: the pull spec of the release image is required$`,
+		},
+		{
+			name: "verified release image referenced by tag",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.ReleaseImage = &types.ReleaseImage{
+					PullSpec:         "quay.io/openshift-release-dev/ocp-release:4.12.0-x86_64",
+					VerificationKeys: []string{"ssh-rsa AAAA"},
+				}
+				return c
+			}(),
+			expectedError: `^\[releaseImage.pullSpec: Invalid value: "quay.io/openshift-release-dev/ocp-release:4.12.0-x86_64": must reference the image by digest when verificationKeys are given, releaseImage.verificationKeys\[0\]: Invalid value: "": must be an ASCII-armored GPG public key\]$`,
+		},
+		{
+			name: "signature stores without verification keys",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.ReleaseImage = &types.ReleaseImage{
+					PullSpec:        "quay.io/openshift-release-dev/ocp-release:4.12.0-x86_64",
+					SignatureStores: []string{"mirror.example.com/signatures"},
+				}
+				return c
+			}(),
+			expectedError: `^\[releaseImage.signatureStores\[0\]: Invalid value: "mirror.example.com/signatures": must be an http\(s\) URL, releaseImage.signatureStores: Forbidden: signatureStores may only be set with verificationKeys\]$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateInstallConfig(tc.installConfig).ToAggregate()
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestClusterNetworkWarnings(t *testing.T) {
+	cases := []struct {
+		name             string
+		installConfig    *types.InstallConfig
+		expectedWarnings []string
+	}{
+		{
+			name: "room for all pods and machines",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Networking.ClusterNetwork[0].HostPrefix = 24
+				c.Networking.ClusterNetwork[0].CIDR = *ipnet.MustParseCIDR("192.168.0.0/16")
+				return c
+			}(),
+		},
+		{
+			name:          "host prefix limits pods per node",
+			installConfig: validInstallConfig(),
+			expectedWarnings: []string{
+				"networking.clusterNetwork[0].hostPrefix: a host prefix of /28 leaves room for at most 16 pods per node, which is less than the default maximum of 250",
+			},
+		},
+		{
+			name: "host prefix checked against configured max pods",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.NodeConfig = &types.NodeConfig{MaxPods: 16}
+				return c
+			}(),
+		},
+		{
+			name: "little room to scale",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Networking.ClusterNetwork[0].HostPrefix = 24
+				c.Networking.ClusterNetwork[0].CIDR = *ipnet.MustParseCIDR("192.168.0.0/20")
+				c.Compute[0].Replicas = pointer.Int64Ptr(10)
+				return c
+			}(),
+			expectedWarnings: []string{
+				"networking.clusterNetwork: the IPv4 cluster networks provide only 16 host subnets for 11 machines, leaving little room to scale the cluster",
+			},
+		},
+		{
+			name: "host prefix not used by network type",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Networking.NetworkType = "Calico"
+				return c
+			}(),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedWarnings, ClusterNetworkWarnings(tc.installConfig))
+		})
+	}
+}
+
+func TestValidateCapabilityDependencies(t *testing.T) {
+	optional := sets.NewString("Console", "Ingress", "marketplace", "OperatorLifecycleManager", "Storage", "CSISnapshot")
+	cases := []struct {
+		name          string
+		capabilities  *types.Capabilities
+		expectedError string
+	}{
+		{
+			name: "dependencies enabled",
+			capabilities: &types.Capabilities{BaselineCapabilitySet: "None",
+				AdditionalEnabledCapabilities: []configv1.ClusterVersionCapability{"Console", "Ingress", "marketplace", "OperatorLifecycleManager"}},
+		},
+		{
+			name: "console without ingress",
+			capabilities: &types.Capabilities{BaselineCapabilitySet: "None",
+				AdditionalEnabledCapabilities: []configv1.ClusterVersionCapability{"Console"}},
+			expectedError: `^capabilities.additionalEnabledCapabilities\[0\]: Invalid value: "Console": the Console capability requires the Ingress capability to be enabled$`,
+		},
+		{
+			name: "marketplace without operator lifecycle manager",
+			capabilities: &types.Capabilities{BaselineCapabilitySet: "None",
+				AdditionalEnabledCapabilities: []configv1.ClusterVersionCapability{"Ingress", "marketplace"}},
+			expectedError: `^capabilities.additionalEnabledCapabilities\[1\]: Invalid value: "marketplace": the marketplace capability requires the OperatorLifecycleManager capability to be enabled$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateCapabilityDependencies(tc.capabilities, optional, field.NewPath("capabilities")).ToAggregate()
 			if tc.expectedError == "" {
 				assert.NoError(t, err)
 			} else {
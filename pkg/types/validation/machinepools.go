@@ -1,8 +1,14 @@
 package validation
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"path"
+	"strings"
 
+	igntypes "github.com/coreos/ignition/v2/config/v3_2/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/openshift/installer/pkg/types"
@@ -49,6 +55,39 @@ var (
 		types.ArchitectureARM64:   true,
 	}
 
+	// installerManagedFiles are the files written by the installer or the
+	// machine-config operator, which must not be overwritten by a machine
+	// pool's machineConfig.
+	installerManagedFiles = sets.NewString(
+		"/etc/containers/registries.conf",
+		"/etc/crio/crio.conf.d/01-workload-partitioning",
+		"/etc/kubernetes/kubelet-ca.crt",
+		"/etc/kubernetes/kubelet.conf",
+		"/etc/kubernetes/openshift-workload-pinning",
+	)
+
+	// installerManagedUnits are the systemd units managed by the
+	// machine-config operator.
+	installerManagedUnits = sets.NewString(
+		"crio.service",
+		"kubelet.service",
+		"machine-config-daemon-firstboot.service",
+		"machine-config-daemon-pull.service",
+	)
+
+	// validUnitSuffixes are the types of systemd units which may be
+	// installed by a machine pool's machineConfig.
+	validUnitSuffixes = []string{".service", ".socket", ".timer", ".path", ".mount", ".target"}
+
+	// supportedIgnitionVersions are the Ignition spec versions accepted in a
+	// machine pool's machineConfig, which are those the machine-config
+	// operator can merge.
+	supportedIgnitionVersions = sets.NewString("3.0.0", "3.1.0", "3.2.0")
+
+	// machineSetsInAllZonesPlatforms are the platforms on which compute
+	// pools can have MachineSets in all zones of the region.
+	machineSetsInAllZonesPlatforms = sets.NewString(aws.Name, azure.Name, gcp.Name)
+
 	validArchitectureValues = func() []string {
 		v := make([]string, 0, len(validArchitectures))
 		for m := range validArchitectures {
@@ -77,10 +116,139 @@ func ValidateMachinePool(platform *types.Platform, p *types.MachinePool, fldPath
 	if platform.AWS != nil {
 		allErrs = append(allErrs, awsvalidation.ValidateMachinePoolArchitecture(p, fldPath.Child("architecture"))...)
 	}
+	if p.DiskEncryption != nil {
+		allErrs = append(allErrs, validateDiskEncryption(p.DiskEncryption, fldPath.Child("diskEncryption"))...)
+	}
+	if p.DiskMirroring != nil {
+		allErrs = append(allErrs, validateDiskMirroring(p.DiskMirroring, fldPath.Child("diskMirroring"))...)
+	}
+	if p.MachineConfig != nil {
+		allErrs = append(allErrs, validateMachineConfigCustomization(p.MachineConfig, fldPath.Child("machineConfig"))...)
+	}
+	if p.NTP != nil {
+		allErrs = append(allErrs, validateNTP(p.NTP, fldPath.Child("ntp"))...)
+	}
+	if p.MachineSetsInAllZones && !machineSetsInAllZonesPlatforms.Has(platform.Name()) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("machineSetsInAllZones"), p.MachineSetsInAllZones, fmt.Sprintf("MachineSets in all zones are not supported on %s, supported platforms are %s", platform.Name(), strings.Join(machineSetsInAllZonesPlatforms.List(), ", "))))
+	}
 	allErrs = append(allErrs, validateMachinePoolPlatform(platform, &p.Platform, p, fldPath.Child("platform"))...)
 	return allErrs
 }
 
+func validateDiskEncryption(e *types.DiskEncryption, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	bindings := len(e.Tang)
+	if e.TPM2 {
+		bindings++
+	}
+	if bindings == 0 {
+		allErrs = append(allErrs, field.Required(fldPath, "at least one of tpm2 or tang must be specified"))
+	}
+	if e.Threshold < 1 || e.Threshold > bindings {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("threshold"), e.Threshold, fmt.Sprintf("threshold must be between 1 and the number of bindings (%d)", bindings)))
+	}
+	urls := map[string]bool{}
+	for i, tang := range e.Tang {
+		tangPath := fldPath.Child("tang").Index(i)
+		if u, err := url.Parse(tang.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(tangPath.Child("url"), tang.URL, "must be an http or https URL"))
+		} else if urls[tang.URL] {
+			allErrs = append(allErrs, field.Duplicate(tangPath.Child("url"), tang.URL))
+		}
+		urls[tang.URL] = true
+		if tang.Thumbprint == "" {
+			allErrs = append(allErrs, field.Required(tangPath.Child("thumbprint"), "the thumbprint of the trusted Tang server key is required"))
+		}
+	}
+	return allErrs
+}
+
+func validateDiskMirroring(m *types.DiskMirroring, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	devicesPath := fldPath.Child("devices")
+	if len(m.Devices) < 2 {
+		allErrs = append(allErrs, field.Invalid(devicesPath, m.Devices, "at least two devices are required for mirroring"))
+	}
+	devices := map[string]bool{}
+	for i, device := range m.Devices {
+		if !strings.HasPrefix(device, "/dev/") {
+			allErrs = append(allErrs, field.Invalid(devicesPath.Index(i), device, "must be a path under /dev/"))
+		} else if devices[device] {
+			allErrs = append(allErrs, field.Duplicate(devicesPath.Index(i), device))
+		}
+		devices[device] = true
+	}
+	return allErrs
+}
+
+func validateMachineConfigCustomization(c *types.MachineConfigCustomization, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	files := sets.NewString()
+	units := sets.NewString()
+
+	validateFile := func(filePath string, fldPath *field.Path) {
+		switch {
+		case !path.IsAbs(filePath) || path.Clean(filePath) != filePath:
+			allErrs = append(allErrs, field.Invalid(fldPath, filePath, "must be a clean absolute path"))
+		case installerManagedFiles.Has(filePath):
+			allErrs = append(allErrs, field.Invalid(fldPath, filePath, "the file is managed by the installer"))
+		case files.Has(filePath):
+			allErrs = append(allErrs, field.Duplicate(fldPath, filePath))
+		}
+		files.Insert(filePath)
+	}
+	validateUnit := func(name string, fldPath *field.Path) {
+		validSuffix := false
+		for _, suffix := range validUnitSuffixes {
+			if strings.HasSuffix(name, suffix) && len(name) > len(suffix) {
+				validSuffix = true
+			}
+		}
+		switch {
+		case !validSuffix || strings.Contains(name, "/"):
+			allErrs = append(allErrs, field.Invalid(fldPath, name, fmt.Sprintf("must be a unit name ending in one of %s", strings.Join(validUnitSuffixes, ", "))))
+		case installerManagedUnits.Has(name):
+			allErrs = append(allErrs, field.Invalid(fldPath, name, "the unit is managed by the installer"))
+		case units.Has(name):
+			allErrs = append(allErrs, field.Duplicate(fldPath, name))
+		}
+		units.Insert(name)
+	}
+
+	for i, f := range c.Files {
+		validateFile(f.Path, fldPath.Child("files").Index(i).Child("path"))
+		if f.Mode != nil && (*f.Mode < 0 || *f.Mode > 07777) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("files").Index(i).Child("mode"), *f.Mode, "must be a permission mode between 0 and 07777"))
+		}
+	}
+	for i, u := range c.Units {
+		validateUnit(u.Name, fldPath.Child("units").Index(i).Child("name"))
+	}
+	for i, arg := range c.KernelArguments {
+		if arg == "" || strings.ContainsAny(arg, " \t\n") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("kernelArguments").Index(i), arg, "must be a single non-empty kernel argument"))
+		}
+	}
+
+	if c.Ignition != "" {
+		ignitionPath := fldPath.Child("ignition")
+		var config igntypes.Config
+		if err := json.Unmarshal([]byte(c.Ignition), &config); err != nil {
+			return append(allErrs, field.Invalid(ignitionPath, c.Ignition, fmt.Sprintf("failed to parse the Ignition config: %v", err)))
+		}
+		if !supportedIgnitionVersions.Has(config.Ignition.Version) {
+			allErrs = append(allErrs, field.NotSupported(ignitionPath.Child("ignition", "version"), config.Ignition.Version, supportedIgnitionVersions.List()))
+		}
+		for i, f := range config.Storage.Files {
+			validateFile(f.Path, ignitionPath.Child("storage", "files").Index(i).Child("path"))
+		}
+		for i, u := range config.Systemd.Units {
+			validateUnit(u.Name, ignitionPath.Child("systemd", "units").Index(i).Child("name"))
+		}
+	}
+	return allErrs
+}
+
 func validateMachinePoolPlatform(platform *types.Platform, p *types.MachinePoolPlatform, pool *types.MachinePool, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	platformName := platform.Name()
@@ -57,6 +57,204 @@ func TestValidateMachinePool(t *testing.T) {
 			}(),
 			valid: false,
 		},
+		{
+			name:     "disk encryption with tpm2 and tang",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.DiskEncryption = &types.DiskEncryption{TPM2: true, Tang: []types.TangServer{{URL: "http://tang.example.com", Thumbprint: "abc"}}, Threshold: 2}
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name:     "disk encryption without bindings",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.DiskEncryption = &types.DiskEncryption{Threshold: 1}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "disk encryption threshold above bindings",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.DiskEncryption = &types.DiskEncryption{TPM2: true, Threshold: 2}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "disk encryption tang without thumbprint",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.DiskEncryption = &types.DiskEncryption{Tang: []types.TangServer{{URL: "http://tang.example.com"}}, Threshold: 1}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "disk encryption invalid tang url",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.DiskEncryption = &types.DiskEncryption{Tang: []types.TangServer{{URL: "tang.example.com", Thumbprint: "abc"}}, Threshold: 1}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "disk mirroring",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.DiskMirroring = &types.DiskMirroring{Devices: []string{"/dev/sda", "/dev/sdb"}}
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name:     "disk mirroring single device",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.DiskMirroring = &types.DiskMirroring{Devices: []string{"/dev/sda"}}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "disk mirroring duplicate device",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.DiskMirroring = &types.DiskMirroring{Devices: []string{"/dev/sda", "/dev/sda"}}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "disk mirroring relative device",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.DiskMirroring = &types.DiskMirroring{Devices: []string{"sda", "/dev/sdb"}}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "machine config",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.MachineConfig = &types.MachineConfigCustomization{
+					Files:           []types.MachineConfigFile{{Path: "/etc/example.conf", Contents: "example"}},
+					Units:           []types.MachineConfigUnit{{Name: "example.service", Contents: "[Unit]", Enabled: true}},
+					KernelArguments: []string{"nosmt"},
+					Ignition:        `{"ignition":{"version":"3.1.0"},"storage":{"files":[{"path":"/etc/other.conf"}]}}`,
+				}
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name:     "machine config relative path",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.MachineConfig = &types.MachineConfigCustomization{Files: []types.MachineConfigFile{{Path: "etc/example.conf"}}}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "machine config installer managed file",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.MachineConfig = &types.MachineConfigCustomization{Files: []types.MachineConfigFile{{Path: "/etc/kubernetes/kubelet.conf"}}}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "machine config duplicate file in ignition",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.MachineConfig = &types.MachineConfigCustomization{
+					Files:    []types.MachineConfigFile{{Path: "/etc/example.conf"}},
+					Ignition: `{"ignition":{"version":"3.2.0"},"storage":{"files":[{"path":"/etc/example.conf"}]}}`,
+				}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "machine config invalid mode",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.MachineConfig = &types.MachineConfigCustomization{Files: []types.MachineConfigFile{{Path: "/etc/example.conf", Mode: pointer.IntPtr(010000)}}}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "machine config installer managed unit",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.MachineConfig = &types.MachineConfigCustomization{Units: []types.MachineConfigUnit{{Name: "kubelet.service"}}}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "machine config invalid unit name",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.MachineConfig = &types.MachineConfigCustomization{Units: []types.MachineConfigUnit{{Name: "example"}}}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "machine config invalid kernel argument",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.MachineConfig = &types.MachineConfigCustomization{KernelArguments: []string{"a b"}}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "machine config unsupported ignition version",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.MachineConfig = &types.MachineConfigCustomization{Ignition: `{"ignition":{"version":"3.4.0"}}`}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name:     "machine config invalid ignition",
+			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.MachineConfig = &types.MachineConfigCustomization{Ignition: `variant: openshift`}
+				return p
+			}(),
+			valid: false,
+		},
 		{
 			name:     "valid aws",
 			platform: &types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
@@ -190,6 +388,26 @@ func TestValidateMachinePool(t *testing.T) {
 			}(),
 			valid: false,
 		},
+		{
+			name:     "machine sets in all zones",
+			platform: &types.Platform{Azure: &azure.Platform{Region: "eastus"}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.MachineSetsInAllZones = true
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name:     "machine sets in all zones on unsupported platform",
+			platform: &types.Platform{OpenStack: &openstack.Platform{}},
+			pool: func() *types.MachinePool {
+				p := validMachinePool("test-name")
+				p.MachineSetsInAllZones = true
+				return p
+			}(),
+			valid: false,
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -0,0 +1,86 @@
+package validation
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/validate"
+)
+
+// redactedKey stands for the private keys in the errors, which must not
+// include them.
+const redactedKey = "<redacted>"
+
+func validateServingCertificates(c *types.InstallConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if cert := c.ServingCertificates.API; cert != nil {
+		allErrs = append(allErrs, validateServingCertificate(cert, c.APIDomainName(), fldPath.Child("api"))...)
+	}
+	if cert := c.ServingCertificates.Ingress; cert != nil {
+		// the routes of the apps domain are served with a wildcard certificate
+		allErrs = append(allErrs, validateServingCertificate(cert, "*."+c.AppsDomainName(), fldPath.Child("ingress"))...)
+	}
+	return allErrs
+}
+
+// validateServingCertificate checks that the certificate is valid for the
+// host name and matches the key, and that it is issued by the CAs, if they
+// are set. The errors include the subject of the certificate rather than
+// the PEM of the certificate or of the key.
+func validateServingCertificate(cert *types.ServingCertificate, host string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	certPath, keyPath, caPath := fldPath.Child("certificate"), fldPath.Child("key"), fldPath.Child("ca")
+	if cert.Certificate == "" {
+		allErrs = append(allErrs, field.Required(certPath, "the PEM-encoded certificate is required"))
+	}
+	if cert.Key == "" {
+		allErrs = append(allErrs, field.Required(keyPath, "the PEM-encoded private key of the certificate is required"))
+	}
+	if cert.CA != "" {
+		if err := validate.CABundle(cert.CA); err != nil {
+			allErrs = append(allErrs, field.Invalid(caPath, cert.CA, err.Error()))
+		}
+	}
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+
+	pair, err := tls.X509KeyPair([]byte(cert.Certificate), []byte(cert.Key))
+	if err != nil {
+		return append(allErrs, field.Invalid(keyPath, redactedKey, fmt.Sprintf("must be the private key of the certificate: %v", err)))
+	}
+	chain := make([]*x509.Certificate, 0, len(pair.Certificate))
+	for _, der := range pair.Certificate {
+		c, err := x509.ParseCertificate(der)
+		if err != nil {
+			return append(allErrs, field.Invalid(certPath, cert.Certificate, err.Error()))
+		}
+		chain = append(chain, c)
+	}
+	leaf := chain[0]
+	subject := leaf.Subject.String()
+
+	if err := leaf.VerifyHostname(host); err != nil {
+		allErrs = append(allErrs, field.Invalid(certPath, subject, fmt.Sprintf("must be valid for %s: %v", host, err)))
+	}
+	if now := time.Now(); now.After(leaf.NotAfter) {
+		allErrs = append(allErrs, field.Invalid(certPath, subject, fmt.Sprintf("expired on %s", leaf.NotAfter.UTC().Format(time.RFC3339))))
+	}
+	if cert.CA != "" {
+		roots := x509.NewCertPool()
+		roots.AppendCertsFromPEM([]byte(cert.CA))
+		intermediates := x509.NewCertPool()
+		for _, c := range chain[1:] {
+			intermediates.AddCert(c)
+		}
+		if _, err := leaf.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates}); err != nil {
+			allErrs = append(allErrs, field.Invalid(certPath, subject, fmt.Sprintf("must be issued by the CAs of %s: %v", caPath, err)))
+		}
+	}
+	return allErrs
+}
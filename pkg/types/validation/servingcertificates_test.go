@@ -0,0 +1,132 @@
+package validation
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+type testCertificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  string
+}
+
+func (c *testCertificate) keyPEM(t *testing.T) string {
+	der, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+}
+
+// newTestCertificate returns a certificate for the names, signed by the
+// issuer, or self-signed when it is nil.
+func newTestCertificate(t *testing.T, issuer *testCertificate, notAfter time.Time, names ...string) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		DNSNames:     names,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	parent, signer := template, key
+	if issuer == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		parent, signer = issuer.cert, issuer.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCertificate{cert: cert, key: key, pem: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))}
+}
+
+func TestValidateServingCertificates(t *testing.T) {
+	valid := time.Now().Add(24 * time.Hour)
+	ca := newTestCertificate(t, nil, valid)
+	otherCA := newTestCertificate(t, nil, valid)
+	api := newTestCertificate(t, ca, valid, "api.test-cluster.example.com")
+	ingress := newTestCertificate(t, ca, valid, "*.apps.test-cluster.example.com")
+	expired := newTestCertificate(t, ca, time.Now().Add(-time.Minute), "api.test-cluster.example.com")
+
+	cases := []struct {
+		name     string
+		api      *types.ServingCertificate
+		ingress  *types.ServingCertificate
+		expected string
+	}{
+		{
+			name:    "valid",
+			api:     &types.ServingCertificate{Certificate: api.pem, Key: api.keyPEM(t), CA: ca.pem},
+			ingress: &types.ServingCertificate{Certificate: ingress.pem, Key: ingress.keyPEM(t)},
+		},
+		{
+			name:     "missing key",
+			api:      &types.ServingCertificate{Certificate: api.pem},
+			expected: `^servingCertificates\.api\.key: Required value: the PEM-encoded private key of the certificate is required$`,
+		},
+		{
+			name:     "mismatched key",
+			api:      &types.ServingCertificate{Certificate: api.pem, Key: ingress.keyPEM(t)},
+			expected: `^servingCertificates\.api\.key: Invalid value: "<redacted>": must be the private key of the certificate: .*$`,
+		},
+		{
+			name:     "wrong host",
+			ingress:  &types.ServingCertificate{Certificate: api.pem, Key: api.keyPEM(t)},
+			expected: `^servingCertificates\.ingress\.certificate: Invalid value: "CN=test": must be valid for \*\.apps\.test-cluster\.example\.com: .*$`,
+		},
+		{
+			name:     "expired",
+			api:      &types.ServingCertificate{Certificate: expired.pem, Key: expired.keyPEM(t)},
+			expected: `^servingCertificates\.api\.certificate: Invalid value: "CN=test": expired on .*$`,
+		},
+		{
+			name:     "other CA",
+			api:      &types.ServingCertificate{Certificate: api.pem, Key: api.keyPEM(t), CA: otherCA.pem},
+			expected: `^servingCertificates\.api\.certificate: Invalid value: "CN=test": must be issued by the CAs of servingCertificates\.api\.ca: .*$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ic := &types.InstallConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				BaseDomain: "example.com",
+				ServingCertificates: &types.ServingCertificates{
+					API:     tc.api,
+					Ingress: tc.ingress,
+				},
+			}
+			err := validateServingCertificates(ic, field.NewPath("servingCertificates")).ToAggregate()
+			if tc.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,342 @@
+package validation
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	utilsnet "k8s.io/utils/net"
+
+	operv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/baremetal"
+	"github.com/openshift/installer/pkg/types/loadbalancer"
+	loadbalancervalidation "github.com/openshift/installer/pkg/types/loadbalancer/validation"
+	"github.com/openshift/installer/pkg/types/nutanix"
+	"github.com/openshift/installer/pkg/types/openstack"
+	"github.com/openshift/installer/pkg/types/ovirt"
+	"github.com/openshift/installer/pkg/types/vsphere"
+	"github.com/openshift/installer/pkg/validate"
+)
+
+// vips defines the VIPs to validate
+type vips struct {
+	API     []string
+	Ingress []string
+}
+
+// vipFields defines the field names to which validation errors for each VIP
+// type should be assigned to
+type vipFields struct {
+	APIVIPs     string
+	IngressVIPs string
+}
+
+// IPRange is an inclusive range of IP addresses of the machine network, e.g.
+// a DHCP range or an allocation pool, which the VIPs must not be in.
+type IPRange struct {
+	// Name describes the range in the validation errors, e.g. "provisioning
+	// DHCP range".
+	Name  string
+	Start net.IP
+	End   net.IP
+}
+
+// Contains returns whether the IP is in the range.
+func (r IPRange) Contains(ip net.IP) bool {
+	if ip == nil || r.Start == nil || r.End == nil {
+		return false
+	}
+	ip, start, end := ip.To16(), r.Start.To16(), r.End.To16()
+	return bytes.Compare(start, ip) <= 0 && bytes.Compare(end, ip) >= 0
+}
+
+// platformVIPs are the VIPs of an on-prem platform and how they are
+// validated.
+type platformVIPs struct {
+	// name is the name of the platform, the field the VIPs are in.
+	name string
+	// api and ingress are the VIPs of the platform, reordered by the
+	// validation to have IPv4 first for dual-stack.
+	api     *[]string
+	ingress *[]string
+	fields  vipFields
+	// required is whether the VIPs must be set.
+	required bool
+	// reserved are the ranges the VIPs must not be in.
+	reserved []IPRange
+	// loadBalancer is the load balancer of the platform.
+	loadBalancer *loadbalancer.LoadBalancer
+}
+
+// onPremVIPs returns the VIPs of the platform, or nil if the platform has no
+// VIPs.
+func onPremVIPs(platform *types.Platform) *platformVIPs {
+	fields := vipFields{
+		APIVIPs:     "apiVIPs",
+		IngressVIPs: "ingressVIPs",
+	}
+	switch {
+	case platform.BareMetal != nil:
+		return &platformVIPs{
+			name:         baremetal.Name,
+			api:          &platform.BareMetal.APIVIPs,
+			ingress:      &platform.BareMetal.IngressVIPs,
+			fields:       fields,
+			required:     true,
+			reserved:     baremetalDHCPRanges(platform.BareMetal),
+			loadBalancer: platform.BareMetal.LoadBalancer,
+		}
+	case platform.Nutanix != nil:
+		return &platformVIPs{
+			name:         nutanix.Name,
+			api:          &platform.Nutanix.APIVIPs,
+			ingress:      &platform.Nutanix.IngressVIPs,
+			fields:       fields,
+			loadBalancer: platform.Nutanix.LoadBalancer,
+		}
+	case platform.OpenStack != nil:
+		return &platformVIPs{
+			name:         openstack.Name,
+			api:          &platform.OpenStack.APIVIPs,
+			ingress:      &platform.OpenStack.IngressVIPs,
+			fields:       fields,
+			required:     true,
+			loadBalancer: platform.OpenStack.LoadBalancer,
+		}
+	case platform.VSphere != nil:
+		return &platformVIPs{
+			name:         vsphere.Name,
+			api:          &platform.VSphere.APIVIPs,
+			ingress:      &platform.VSphere.IngressVIPs,
+			fields:       fields,
+			loadBalancer: platform.VSphere.LoadBalancer,
+		}
+	case platform.Ovirt != nil:
+		return &platformVIPs{
+			name:    ovirt.Name,
+			api:     &platform.Ovirt.APIVIPs,
+			ingress: &platform.Ovirt.IngressVIPs,
+			fields: vipFields{
+				APIVIPs:     "api_vips",
+				IngressVIPs: "ingress_vips",
+			},
+			required: true,
+		}
+	}
+	return nil
+}
+
+// baremetalDHCPRanges returns the DHCP range the installer serves on the
+// managed provisioning network, if any.
+func baremetalDHCPRanges(p *baremetal.Platform) []IPRange {
+	if p.ProvisioningNetwork != baremetal.ManagedProvisioningNetwork || p.ProvisioningDHCPRange == "" {
+		return nil
+	}
+	bounds := strings.Split(p.ProvisioningDHCPRange, ",")
+	if len(bounds) != 2 {
+		// The range itself is validated with the platform.
+		return nil
+	}
+	return []IPRange{{
+		Name:  "provisioning DHCP range",
+		Start: net.ParseIP(bounds[0]),
+		End:   net.ParseIP(bounds[1]),
+	}}
+}
+
+// validateVIPsForPlatform validates the VIPs (for API and Ingress) for the
+// given platform
+func validateVIPsForPlatform(network *types.Networking, platform *types.Platform, fldPath *field.Path) field.ErrorList {
+	p := onPremVIPs(platform)
+	if p == nil {
+		// no vips to validate on this platform
+		return field.ErrorList{}
+	}
+	fldPath = fldPath.Child(p.name)
+
+	allErrs := field.ErrorList{}
+	allErrs = append(allErrs, loadbalancervalidation.ValidateLoadBalancer(p.loadBalancer, fldPath.Child("loadBalancer"))...)
+	allErrs = append(allErrs, ensureIPv4IsFirstInDualStackSlice(p.api, fldPath.Child(p.fields.APIVIPs))...)
+	allErrs = append(allErrs, ensureIPv4IsFirstInDualStackSlice(p.ingress, fldPath.Child(p.fields.IngressVIPs))...)
+
+	virtualIPs := vips{
+		API:     *p.api,
+		Ingress: *p.ingress,
+	}
+	if loadbalancer.IsUserManaged(p.loadBalancer) {
+		// The VIPs, if any, are the addresses of the load balancer of the
+		// user, which need not be on the machine networks and may be shared
+		// by the API and the ingress.
+		allErrs = append(allErrs, validateVIPsAreIPs(virtualIPs.API, fldPath.Child(p.fields.APIVIPs))...)
+		allErrs = append(allErrs, validateVIPsAreIPs(virtualIPs.Ingress, fldPath.Child(p.fields.IngressVIPs))...)
+		return allErrs
+	}
+	allErrs = append(allErrs, validateAPIAndIngressVIPs(virtualIPs, p.fields, p.required, network, fldPath)...)
+	allErrs = append(allErrs, validateVIPsNotInRanges(virtualIPs, p.fields, p.reserved, fldPath)...)
+	return allErrs
+}
+
+func ensureIPv4IsFirstInDualStackSlice(vips *[]string, fldPath *field.Path) field.ErrorList {
+	errList := field.ErrorList{}
+	isDualStack, err := utilsnet.IsDualStackIPStrings(*vips)
+	if err != nil {
+		errList = append(errList, field.Invalid(fldPath, vips, err.Error()))
+		return errList
+	}
+
+	if isDualStack {
+		if len(*vips) == 2 {
+			if utilsnet.IsIPv4String((*vips)[1]) && utilsnet.IsIPv6String((*vips)[0]) {
+				(*vips)[0], (*vips)[1] = (*vips)[1], (*vips)[0]
+			}
+		} else {
+			errList = append(errList, field.Invalid(fldPath, vips, "wrong number of VIPs given. Expecting 2 VIPs for dual stack"))
+			return errList
+		}
+	}
+
+	return errList
+}
+
+// vipKind describes the API or the Ingress VIPs in the validation errors.
+type vipKind struct {
+	// name is "API" or "Ingress".
+	name string
+	// noun is the name in the middle of a sentence, "API" or "ingress".
+	noun string
+	// other is the noun of the other kind of VIPs.
+	other string
+	// otherField is the field of the other kind of VIPs.
+	otherField string
+}
+
+// validateAPIAndIngressVIPs validates the API and Ingress VIPs
+func validateAPIAndIngressVIPs(vips vips, fieldNames vipFields, vipIsRequired bool, n *types.Networking, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	api := vipKind{name: "API", noun: "API", other: "ingress", otherField: fieldNames.IngressVIPs}
+	allErrs = append(allErrs, validateVIPs(api, vips.API, vips.Ingress, vipIsRequired, n, fldPath.Child(fieldNames.APIVIPs), fldPath)...)
+	ingress := vipKind{name: "Ingress", noun: "ingress", other: "API", otherField: fieldNames.APIVIPs}
+	allErrs = append(allErrs, validateVIPs(ingress, vips.Ingress, vips.API, vipIsRequired, n, fldPath.Child(fieldNames.IngressVIPs), fldPath)...)
+
+	return allErrs
+}
+
+// validateVIPs validates the VIPs of one kind, API or Ingress, against the
+// machine networks and the VIPs of the other kind.
+func validateVIPs(kind vipKind, vips, otherVIPs []string, vipIsRequired bool, n *types.Networking, fldPath, platformPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	switch {
+	case len(vips) == 0:
+		if vipIsRequired {
+			allErrs = append(allErrs, field.Required(fldPath, fmt.Sprintf("must specify at least one VIP for the %s", kind.name)))
+		}
+		return allErrs
+	case len(vips) > 2:
+		return append(allErrs, field.TooMany(fldPath, len(vips), 2))
+	}
+
+	for _, vip := range vips {
+		if err := validate.IP(vip); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath, vip, err.Error()))
+		}
+
+		// The API VIPs report the VIPs shared with the Ingress.
+		if kind.name == "API" {
+			for _, otherVIP := range otherVIPs {
+				if net.ParseIP(vip).Equal(net.ParseIP(otherVIP)) {
+					allErrs = append(allErrs, field.Invalid(fldPath, vip, "VIP for API must not be one of the Ingress VIPs"))
+				}
+			}
+		}
+
+		if err := ValidateIPinMachineCIDR(vip, n); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath, vip, err.Error()))
+		}
+
+		if utilsnet.IsIPv6String(vip) && n.NetworkType == string(operv1.NetworkTypeOpenShiftSDN) {
+			allErrs = append(allErrs, field.Invalid(fldPath, vip, "IPv6 is not supported on OpenShiftSDN"))
+		}
+	}
+
+	if len(otherVIPs) == 0 {
+		allErrs = append(allErrs, field.Required(platformPath.Child(kind.otherField), fmt.Sprintf("must specify VIP for %s, when VIP for %s is set", kind.other, kind.noun)))
+	}
+
+	if len(vips) == 1 {
+		hasIPv4, hasIPv6, presence, _ := inferIPVersionFromInstallConfig(n)
+
+		vipIPFamily := corev1.IPv4Protocol
+		if utilsnet.IsIPv6String(vips[0]) {
+			vipIPFamily = corev1.IPv6Protocol
+		}
+
+		if hasIPv4 && hasIPv6 && vipIPFamily != presence["machineNetwork"].Primary {
+			allErrs = append(allErrs, field.Invalid(fldPath, vips[0], fmt.Sprintf("VIP for the %s must be of the same IP family with machine network's primary IP Family for dual-stack IPv4/IPv6", kind.name)))
+		}
+	} else if isDualStack, _ := utilsnet.IsDualStackIPStrings(vips); !isDualStack {
+		allErrs = append(allErrs, field.Invalid(fldPath, vips, fmt.Sprintf("If two %s VIPs are given, one must be an IPv4 address, the other an IPv6", kind.name)))
+	}
+
+	return allErrs
+}
+
+// validateVIPsAreIPs validates that the VIPs are at most an IP per IP family.
+func validateVIPsAreIPs(vips []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(vips) > 2 {
+		return append(allErrs, field.TooMany(fldPath, len(vips), 2))
+	}
+	for _, vip := range vips {
+		if err := validate.IP(vip); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath, vip, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// ValidateVIPsNotInRanges validates that none of the API and Ingress VIPs,
+// whose fields are under fldPath, is in one of the ranges.
+func ValidateVIPsNotInRanges(apiVIPs, ingressVIPs []string, ranges []IPRange, fldPath *field.Path) field.ErrorList {
+	fields := vipFields{
+		APIVIPs:     "apiVIPs",
+		IngressVIPs: "ingressVIPs",
+	}
+	return validateVIPsNotInRanges(vips{API: apiVIPs, Ingress: ingressVIPs}, fields, ranges, fldPath)
+}
+
+func validateVIPsNotInRanges(vips vips, fieldNames vipFields, ranges []IPRange, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, r := range ranges {
+		for _, vip := range vips.API {
+			if r.Contains(net.ParseIP(vip)) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child(fieldNames.APIVIPs), vip, fmt.Sprintf("IP expected to be outside of the %s %s-%s", r.Name, r.Start, r.End)))
+			}
+		}
+		for _, vip := range vips.Ingress {
+			if r.Contains(net.ParseIP(vip)) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child(fieldNames.IngressVIPs), vip, fmt.Sprintf("IP expected to be outside of the %s %s-%s", r.Name, r.Start, r.End)))
+			}
+		}
+	}
+	return allErrs
+}
+
+// ValidateIPinMachineCIDR confirms if the specified VIP is in the machine CIDR.
+func ValidateIPinMachineCIDR(vip string, n *types.Networking) error {
+	var networks []string
+
+	for _, network := range n.MachineNetwork {
+		if network.CIDR.Contains(net.ParseIP(vip)) {
+			return nil
+		}
+		networks = append(networks, network.CIDR.String())
+	}
+
+	return fmt.Errorf("IP expected to be in one of the machine networks: %s", strings.Join(networks, ","))
+}
@@ -1,5 +1,10 @@
 package vsphere
 
+import (
+	"github.com/openshift/installer/pkg/types/hostnetwork"
+	"github.com/openshift/installer/pkg/types/loadbalancer"
+)
+
 // DiskType is a disk provisioning type for vsphere.
 // +kubebuilder:validation:Enum="";thin;thick;eagerZeroedThick
 type DiskType string
@@ -78,6 +83,14 @@ type Platform struct {
 	// +optional
 	IngressVIPs []string `json:"ingressVIPs,omitempty"`
 
+	// LoadBalancer is the load balancer of the API and of the ingress. With a
+	// UserManaged load balancer, the API and ingress VIPs are optional: they
+	// are the addresses of the load balancer, which the cluster does not
+	// serve with keepalived and haproxy.
+	//
+	// +optional
+	LoadBalancer *loadbalancer.LoadBalancer `json:"loadBalancer,omitempty"`
+
 	// DefaultMachinePlatform is the default configuration used when
 	// installing on VSphere for machine pools which do not define their own
 	// platform configuration.
@@ -103,6 +116,10 @@ type Platform struct {
 	// FailureDomains is available in TechPreview.
 	// +kubebuilder:validation:Optional
 	FailureDomains []FailureDomain `json:"failureDomains,omitempty"`
+	// Hosts is the day-1 network configuration of the hosts, such as bonds,
+	// VLANs and static routes, applied to each host matched by its identity.
+	// +optional
+	Hosts []hostnetwork.Host `json:"hosts,omitempty"`
 }
 
 // FailureDomain holds the region and zone failure domain and
@@ -8,6 +8,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	hostnetworkvalidation "github.com/openshift/installer/pkg/types/hostnetwork/validation"
 	"github.com/openshift/installer/pkg/types/vsphere"
 	"github.com/openshift/installer/pkg/validate"
 )
@@ -59,6 +60,8 @@ func ValidatePlatform(p *vsphere.Platform, fldPath *field.Path) field.ErrorList
 		allErrs = append(allErrs, validateMultiZone(p, fldPath)...)
 	}
 
+	allErrs = append(allErrs, hostnetworkvalidation.ValidateHosts(p.Hosts, fldPath.Child("hosts"))...)
+
 	return allErrs
 }
 
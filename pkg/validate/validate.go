@@ -10,6 +10,7 @@ import (
 	"net"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 
 	uuid "github.com/google/uuid"
@@ -185,6 +186,13 @@ func DoCIDRsOverlap(acidr, bcidr *net.IPNet) bool {
 	return acidr.Contains(bcidr.IP) || bcidr.Contains(acidr.IP)
 }
 
+// CIDRContains returns true if the CIDR outer contains the whole CIDR inner.
+func CIDRContains(outer, inner *net.IPNet) bool {
+	outerOnes, outerBits := outer.Mask.Size()
+	innerOnes, innerBits := inner.Mask.Size()
+	return outerBits == innerBits && outerOnes <= innerOnes && outer.Contains(inner.IP)
+}
+
 // SSHPublicKey checks if the given string is a valid SSH public key
 // and returns an error if not.
 func SSHPublicKey(v string) error {
@@ -287,3 +295,29 @@ func OnPremClusterName(v string) error {
 	}
 	return ClusterName(v)
 }
+
+// CPUSet checks if the given string is a list of CPUs in the Linux CPU list
+// format, e.g. 0-3,8, and returns an error if not.
+func CPUSet(v string) error {
+	if v == "" {
+		return errors.New("must specify at least one CPU")
+	}
+	for _, r := range strings.Split(v, ",") {
+		first, last, isRange := strings.Cut(r, "-")
+		start, err := strconv.ParseUint(first, 10, 16)
+		if err != nil {
+			return fmt.Errorf("invalid CPU %q", first)
+		}
+		if !isRange {
+			continue
+		}
+		end, err := strconv.ParseUint(last, 10, 16)
+		if err != nil {
+			return fmt.Errorf("invalid CPU %q", last)
+		}
+		if end < start {
+			return fmt.Errorf("invalid CPU range %q", r)
+		}
+	}
+	return nil
+}
@@ -346,6 +346,53 @@ func TestDoCIDRsOverlap(t *testing.T) {
 	}
 }
 
+func TestCIDRContains(t *testing.T) {
+	cases := []struct {
+		outer    string
+		inner    string
+		contains bool
+	}{
+		{
+			outer:    "10.0.0.0/16",
+			inner:    "10.0.1.0/24",
+			contains: true,
+		},
+		{
+			outer:    "10.0.0.0/16",
+			inner:    "10.0.0.0/16",
+			contains: true,
+		},
+		{
+			outer:    "10.0.1.0/24",
+			inner:    "10.0.0.0/16",
+			contains: false,
+		},
+		{
+			outer:    "10.0.0.0/16",
+			inner:    "10.1.0.0/24",
+			contains: false,
+		},
+		{
+			outer:    "::/0",
+			inner:    "10.0.0.0/24",
+			contains: false,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%s %s", tc.outer, tc.inner), func(t *testing.T) {
+			_, outer, err := net.ParseCIDR(tc.outer)
+			if err != nil {
+				t.Fatalf("could not parse cidr %q: %v", tc.outer, err)
+			}
+			_, inner, err := net.ParseCIDR(tc.inner)
+			if err != nil {
+				t.Fatalf("could not parse cidr %q: %v", tc.inner, err)
+			}
+			assert.Equal(t, tc.contains, CIDRContains(outer, inner))
+		})
+	}
+}
+
 func TestImagePullSecret(t *testing.T) {
 	cases := []struct {
 		name   string
@@ -702,3 +749,30 @@ func TestMAC(t *testing.T) {
 		})
 	}
 }
+
+func TestCPUSet(t *testing.T) {
+	cases := []struct {
+		cpuset string
+		valid  bool
+	}{
+		{cpuset: "0", valid: true},
+		{cpuset: "0-1", valid: true},
+		{cpuset: "0-1,4,6-7", valid: true},
+		{cpuset: "", valid: false},
+		{cpuset: "a", valid: false},
+		{cpuset: "0-", valid: false},
+		{cpuset: "3-1", valid: false},
+		{cpuset: "0,,1", valid: false},
+		{cpuset: "-1", valid: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.cpuset, func(t *testing.T) {
+			err := CPUSet(tc.cpuset)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}